@@ -4,18 +4,17 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
-	"errors"
 	"fmt"
 
-	"github.com/flectolab/flecto-manager/jwt"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/model"
 	flectoService "github.com/flectolab/flecto-manager/service"
 	"github.com/flectolab/flecto-manager/types"
 )
 
 var (
-	ErrInvalidState = errors.New("invalid state parameter")
-	ErrUserInactive = errors.New("user account is inactive")
+	ErrInvalidState = flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid state parameter")
+	ErrUserInactive = flectoErrors.New(flectoErrors.CodeUserInactive, "user account is inactive")
 )
 
 type Service interface {
@@ -26,14 +25,14 @@ type Service interface {
 type service struct {
 	provider    Provider
 	userService flectoService.UserService
-	jwtService  *jwt.ServiceJWT
+	authService flectoService.AuthService
 }
 
-func NewService(provider Provider, userService flectoService.UserService, jwtService *jwt.ServiceJWT) Service {
+func NewService(provider Provider, userService flectoService.UserService, authService flectoService.AuthService) Service {
 	return &service{
 		provider:    provider,
 		userService: userService,
-		jwtService:  jwtService,
+		authService: authService,
 	}
 }
 
@@ -81,17 +80,11 @@ func (s *service) CompleteAuth(ctx context.Context, code, state, expectedState s
 		return nil, nil, ErrUserInactive
 	}
 
-	tokenPair, err := s.jwtService.GenerateTokenPair(user, types.AuthTypeOpenID, nil, userInfo.Roles)
+	tokenPair, err := s.authService.IssueTokens(ctx, user, types.AuthTypeOpenID, nil, userInfo.Roles)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
 
-	refreshTokenHash := jwt.HashToken(tokenPair.RefreshToken)
-	if err = s.userService.UpdateRefreshToken(ctx, user.ID, refreshTokenHash); err != nil {
-		return nil, nil, fmt.Errorf("failed to update user: %w", err)
-	}
-	user.RefreshTokenHash = refreshTokenHash
-
 	return user, tokenPair, nil
 }
 
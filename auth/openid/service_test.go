@@ -4,12 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
-	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/flectolab/flecto-manager/auth/openid"
-	"github.com/flectolab/flecto-manager/config"
-	"github.com/flectolab/flecto-manager/jwt"
 	mockOpenID "github.com/flectolab/flecto-manager/mocks/flecto-manager/auth/openid"
 	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
 	"github.com/flectolab/flecto-manager/model"
@@ -23,30 +20,25 @@ func setupServiceTest(t *testing.T) (
 	*gomock.Controller,
 	*mockOpenID.MockProvider,
 	*mockFlectoService.MockUserService,
-	*jwt.ServiceJWT,
+	*mockFlectoService.MockAuthService,
 	openid.Service,
 ) {
 	ctrl := gomock.NewController(t)
 	mockProvider := mockOpenID.NewMockProvider(ctrl)
 	mockUserService := mockFlectoService.NewMockUserService(ctrl)
-	jwtService := jwt.NewServiceJWT(&config.JWTConfig{
-		Secret:          "test-secret-key-32-bytes-long!!!",
-		Issuer:          "test-issuer",
-		AccessTokenTTL:  15 * time.Minute,
-		RefreshTokenTTL: 24 * time.Hour,
-	})
-	svc := openid.NewService(mockProvider, mockUserService, jwtService)
-	return ctrl, mockProvider, mockUserService, jwtService, svc
+	mockAuthService := mockFlectoService.NewMockAuthService(ctrl)
+	svc := openid.NewService(mockProvider, mockUserService, mockAuthService)
+	return ctrl, mockProvider, mockUserService, mockAuthService, svc
 }
 
 func TestNewService(t *testing.T) {
-	ctrl, mockProvider, mockUserService, jwtService, svc := setupServiceTest(t)
+	ctrl, mockProvider, mockUserService, mockAuthService, svc := setupServiceTest(t)
 	defer ctrl.Finish()
 
 	assert.NotNil(t, svc)
 	assert.NotNil(t, mockProvider)
 	assert.NotNil(t, mockUserService)
-	assert.NotNil(t, jwtService)
+	assert.NotNil(t, mockAuthService)
 }
 
 func TestService_BeginAuth(t *testing.T) {
@@ -293,7 +285,7 @@ func TestService_CompleteAuth(t *testing.T) {
 	})
 
 	t.Run("success with existing user", func(t *testing.T) {
-		ctrl, mockProvider, mockUserService, _, svc := setupServiceTest(t)
+		ctrl, mockProvider, mockUserService, mockAuthService, svc := setupServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -340,9 +332,9 @@ func TestService_CompleteAuth(t *testing.T) {
 			FindOrCreate(ctx, gomock.Any()).
 			Return(existingUser, nil)
 
-		mockUserService.EXPECT().
-			UpdateRefreshToken(ctx, int64(1), gomock.Any()).
-			Return(nil)
+		mockAuthService.EXPECT().
+			IssueTokens(ctx, gomock.Any(), types.AuthTypeOpenID, nil, gomock.Any()).
+			Return(&types.TokenPair{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
 
 		user, tokens, err := svc.CompleteAuth(ctx, "code", state, state)
 
@@ -355,7 +347,7 @@ func TestService_CompleteAuth(t *testing.T) {
 	})
 
 	t.Run("success with new user creation", func(t *testing.T) {
-		ctrl, mockProvider, mockUserService, _, svc := setupServiceTest(t)
+		ctrl, mockProvider, mockUserService, mockAuthService, svc := setupServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -407,9 +399,9 @@ func TestService_CompleteAuth(t *testing.T) {
 				return newUser, nil
 			})
 
-		mockUserService.EXPECT().
-			UpdateRefreshToken(ctx, int64(1), gomock.Any()).
-			Return(nil)
+		mockAuthService.EXPECT().
+			IssueTokens(ctx, gomock.Any(), types.AuthTypeOpenID, nil, gomock.Any()).
+			Return(&types.TokenPair{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
 
 		user, tokens, err := svc.CompleteAuth(ctx, "code", state, state)
 
@@ -420,7 +412,7 @@ func TestService_CompleteAuth(t *testing.T) {
 	})
 
 	t.Run("success with subject as username when no email", func(t *testing.T) {
-		ctrl, mockProvider, mockUserService, _, svc := setupServiceTest(t)
+		ctrl, mockProvider, mockUserService, mockAuthService, svc := setupServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -467,9 +459,9 @@ func TestService_CompleteAuth(t *testing.T) {
 				return existingUser, nil
 			})
 
-		mockUserService.EXPECT().
-			UpdateRefreshToken(ctx, int64(1), gomock.Any()).
-			Return(nil)
+		mockAuthService.EXPECT().
+			IssueTokens(ctx, gomock.Any(), types.AuthTypeOpenID, nil, gomock.Any()).
+			Return(&types.TokenPair{AccessToken: "access-token", RefreshToken: "refresh-token"}, nil)
 
 		user, tokens, err := svc.CompleteAuth(ctx, "code", state, state)
 
@@ -479,8 +471,8 @@ func TestService_CompleteAuth(t *testing.T) {
 		assert.NotNil(t, tokens)
 	})
 
-	t.Run("update refresh token error", func(t *testing.T) {
-		ctrl, mockProvider, mockUserService, _, svc := setupServiceTest(t)
+	t.Run("issue tokens error", func(t *testing.T) {
+		ctrl, mockProvider, mockUserService, mockAuthService, svc := setupServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -524,14 +516,14 @@ func TestService_CompleteAuth(t *testing.T) {
 			FindOrCreate(ctx, gomock.Any()).
 			Return(existingUser, nil)
 
-		mockUserService.EXPECT().
-			UpdateRefreshToken(ctx, int64(1), gomock.Any()).
-			Return(errors.New("database error"))
+		mockAuthService.EXPECT().
+			IssueTokens(ctx, gomock.Any(), types.AuthTypeOpenID, nil, gomock.Any()).
+			Return(nil, errors.New("database error"))
 
 		user, tokens, err := svc.CompleteAuth(ctx, "code", state, state)
 
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to update user")
+		assert.Contains(t, err.Error(), "failed to generate tokens")
 		assert.Nil(t, user)
 		assert.Nil(t, tokens)
 	})
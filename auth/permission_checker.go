@@ -2,6 +2,8 @@ package auth
 
 import (
 	"context"
+	"fmt"
+	"slices"
 
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/service"
@@ -61,6 +63,15 @@ func (c *PermissionChecker) CanAdminForRoleCode(ctx context.Context, roleCode st
 	return c.CanAdmin(permissions, section, action), nil
 }
 
+// CoversUsername checks if permissions grant every action the permissions of a user allow
+func (c *PermissionChecker) CoversUsername(ctx context.Context, permissions *model.SubjectPermissions, username string) (bool, error) {
+	userPermissions, err := c.roleService.GetPermissionsByUsername(ctx, username)
+	if err != nil {
+		return false, err
+	}
+	return c.Covers(permissions, userPermissions), nil
+}
+
 // --- Must methods (ignore errors, return false on error) ---
 
 // MustCanResourceForUsername checks if a user can perform an action on a resource, returns false on error
@@ -89,24 +100,184 @@ func (c *PermissionChecker) MustCanAdminForRoleCode(ctx context.Context, roleCod
 
 // --- Methods that work directly with SubjectPermissions (no DB call) ---
 
-// CanResource checks if permissions allow an action on a namespace/project/resource
+// CanResource checks if permissions allow an action on a namespace/project/resource.
+// Deny rules override allow rules: a single matching DENY refuses the action even if
+// a wildcard ALLOW would grant it.
 func (c *PermissionChecker) CanResource(permissions *model.SubjectPermissions, namespace, project string, resource model.ResourceType, action model.ActionType) bool {
+	allowed := false
 	for _, p := range permissions.Resources {
+		if p.IsDeny() {
+			if c.matchResourceDeny(p, namespace, project, resource, action) {
+				return false
+			}
+			continue
+		}
 		if c.matchResource(p, namespace, project, resource, action) {
-			return true
+			allowed = true
 		}
 	}
-	return false
+	return allowed
 }
 
-// CanAdmin checks if permissions allow an action on an admin section
+// CanAdmin checks if permissions allow an action on an admin section.
+// Only global admin permissions are considered, namespace-scoped ones are checked with CanAdminNamespace.
+// Deny rules override allow rules.
 func (c *PermissionChecker) CanAdmin(permissions *model.SubjectPermissions, section model.SectionType, action model.ActionType) bool {
+	return c.CanAdminNamespace(permissions, "*", section, action)
+}
+
+// CanAdminNamespace checks if permissions allow an action on an admin section within a namespace.
+// Both global admin permissions and permissions scoped to this namespace apply.
+// Deny rules override allow rules.
+func (c *PermissionChecker) CanAdminNamespace(permissions *model.SubjectPermissions, namespace string, section model.SectionType, action model.ActionType) bool {
+	allowed := false
+	for _, p := range permissions.Admin {
+		if !p.IsGlobal() && p.Namespace != namespace {
+			continue
+		}
+		if c.matchAdmin(p, section, action) {
+			if p.IsDeny() {
+				return false
+			}
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+// AdminNamespaces returns the namespaces where namespace-scoped admin permissions allow
+// an action on a section. Global permissions are not included, use CanAdmin for them.
+func (c *PermissionChecker) AdminNamespaces(permissions *model.SubjectPermissions, section model.SectionType, action model.ActionType) []string {
+	namespaces := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, p := range permissions.Admin {
+		if p.IsGlobal() || p.IsDeny() || seen[p.Namespace] {
+			continue
+		}
+		if c.matchAdmin(p, section, action) && c.CanAdminNamespace(permissions, p.Namespace, section, action) {
+			seen[p.Namespace] = true
+			namespaces = append(namespaces, p.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// deniedAdminNamespaces returns the namespaces where a namespace-scoped deny rule refuses an action on a section,
+// whatever the global admin permissions allow
+func (c *PermissionChecker) deniedAdminNamespaces(permissions *model.SubjectPermissions, section model.SectionType, action model.ActionType) []string {
+	var namespaces []string
 	for _, p := range permissions.Admin {
+		if p.IsGlobal() || !p.IsDeny() || slices.Contains(namespaces, p.Namespace) {
+			continue
+		}
 		if c.matchAdmin(p, section, action) {
-			return true
+			namespaces = append(namespaces, p.Namespace)
+		}
+	}
+	return namespaces
+}
+
+// Covers checks if permissions grant every action the allow rules of other grant.
+// The deny rules of other are ignored, and an allow rule of other is not covered when a deny rule of permissions
+// overlaps it, so that other never allows an action refused to permissions.
+func (c *PermissionChecker) Covers(permissions, other *model.SubjectPermissions) bool {
+	for _, p := range other.Resources {
+		if p.IsDeny() {
+			continue
+		}
+		if !c.CanResource(permissions, p.Namespace, p.Project, p.Resource, p.Action) {
+			return false
+		}
+		for _, deny := range permissions.Resources {
+			if deny.IsDeny() && overlaps(deny.Namespace, p.Namespace) && overlaps(deny.Project, p.Project) &&
+				overlaps(string(deny.Resource), string(p.Resource)) && overlaps(string(deny.Action), string(p.Action)) {
+				return false
+			}
+		}
+	}
+	for _, p := range other.Admin {
+		if p.IsDeny() {
+			continue
+		}
+		namespace := "*"
+		if !p.IsGlobal() {
+			namespace = p.Namespace
+		}
+		if !c.CanAdminNamespace(permissions, namespace, p.Section, p.Action) {
+			return false
+		}
+		for _, deny := range permissions.Admin {
+			if deny.IsDeny() && (deny.IsGlobal() || p.IsGlobal() || deny.Namespace == p.Namespace) &&
+				overlaps(string(deny.Section), string(p.Section)) && overlaps(string(deny.Action), string(p.Action)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// overlaps returns true if two values of a permission, either one possibly the * wildcard, match a common value
+func overlaps(a, b string) bool {
+	return a == "*" || b == "*" || a == b
+}
+
+// PermissionDecision is the outcome of a permission check
+type PermissionDecision string
+
+const (
+	PermissionDecisionGranted PermissionDecision = "GRANTED"
+	PermissionDecisionDenied  PermissionDecision = "DENIED"
+	PermissionDecisionNoMatch PermissionDecision = "NO_MATCH"
+)
+
+// ResourceExplanation details how CanResource reaches its decision
+type ResourceExplanation struct {
+	Decision PermissionDecision
+	Allows   []model.ResourcePermission
+	Denies   []model.ResourcePermission
+	Reason   string
+}
+
+// Allowed returns true if the explained action is permitted
+func (e *ResourceExplanation) Allowed() bool {
+	return e.Decision == PermissionDecisionGranted
+}
+
+// ExplainResource evaluates permissions like CanResource but returns every matching
+// allow and deny rule along with a human readable reason.
+func (c *PermissionChecker) ExplainResource(permissions *model.SubjectPermissions, namespace, project string, resource model.ResourceType, action model.ActionType) *ResourceExplanation {
+	explanation := &ResourceExplanation{
+		Allows: []model.ResourcePermission{},
+		Denies: []model.ResourcePermission{},
+	}
+	for _, p := range permissions.Resources {
+		if p.IsDeny() {
+			if c.matchResourceDeny(p, namespace, project, resource, action) {
+				explanation.Denies = append(explanation.Denies, p)
+			}
+			continue
+		}
+		if c.matchResource(p, namespace, project, resource, action) {
+			explanation.Allows = append(explanation.Allows, p)
 		}
 	}
-	return false
+
+	target := fmt.Sprintf("%s on %s in %s/%s", action, resource, namespace, project)
+	switch {
+	case len(explanation.Denies) > 0:
+		explanation.Decision = PermissionDecisionDenied
+		explanation.Reason = fmt.Sprintf("%s is denied by %d deny rule(s), deny rules override allow rules", target, len(explanation.Denies))
+	case len(explanation.Allows) > 0:
+		explanation.Decision = PermissionDecisionGranted
+		explanation.Reason = fmt.Sprintf("%s is granted by %d allow rule(s)", target, len(explanation.Allows))
+	case len(permissions.Resources) == 0:
+		explanation.Decision = PermissionDecisionNoMatch
+		explanation.Reason = "subject has no resource permissions"
+	default:
+		explanation.Decision = PermissionDecisionNoMatch
+		explanation.Reason = fmt.Sprintf("none of the %d resource permission(s) matches %s", len(permissions.Resources), target)
+	}
+	return explanation
 }
 
 // matchResource checks if a ResourcePermission matches the given criteria
@@ -119,6 +290,16 @@ func (c *PermissionChecker) matchResource(p model.ResourcePermission, namespace,
 	return namespaceMatch && projectMatch && resourceMatch && actionMatch
 }
 
+// matchResourceDeny checks if a deny ResourcePermission applies to the given criteria.
+// Unlike matchResource, a deny on a single resource type does not refuse a ResourceTypeAny
+// check, since other resource types of the project may still be accessible.
+func (c *PermissionChecker) matchResourceDeny(p model.ResourcePermission, namespace, project string, resource model.ResourceType, action model.ActionType) bool {
+	if resource == model.ResourceTypeAny && p.Resource != model.ResourceTypeAll {
+		return false
+	}
+	return c.matchResource(p, namespace, project, resource, action)
+}
+
 // matchAdmin checks if an AdminPermission matches the given criteria
 func (c *PermissionChecker) matchAdmin(p model.AdminPermission, section model.SectionType, action model.ActionType) bool {
 	sectionMatch := p.Section == model.AdminSectionAll || p.Section == section
@@ -140,6 +321,8 @@ func (c *PermissionChecker) FilterQueryByNamespace(query *gorm.DB, permissions [
 		return query.Where("1 = 0")
 	}
 
+	query = c.excludeDenied(query, permissions, action, false)
+
 	if len(allowedNamespaces) == 0 {
 		return query
 	}
@@ -158,6 +341,8 @@ func (c *PermissionChecker) FilterQueryByProject(query *gorm.DB, permissions []m
 		return query.Where("1 = 0")
 	}
 
+	query = c.excludeDenied(query, permissions, action, true)
+
 	// Collect allowed projects for this namespace
 	var allowedProjects []string
 	hasFullAccess := false
@@ -200,6 +385,8 @@ func (c *PermissionChecker) FilterQueryByNamespaceProject(query *gorm.DB, permis
 		return query.Where("1 = 0")
 	}
 
+	query = c.excludeDenied(query, permissions, action, true)
+
 	// Check for full access (namespace = *)
 	for _, p := range filtered {
 		if p.Namespace == "*" {
@@ -270,6 +457,32 @@ func (c *PermissionChecker) FilterQueryByNamespaceProject(query *gorm.DB, permis
 	return query.Where(combined, args...)
 }
 
+// FilterQueryByAdminNamespace adds WHERE conditions for admin listings of a section.
+// Global admins see everything but the namespaces a namespace-scoped deny rule refuses them. Otherwise rows are
+// limited to the namespaces the subject administers through namespace-scoped permissions, plus the namespaces its
+// resource permissions allow.
+func (c *PermissionChecker) FilterQueryByAdminNamespace(query *gorm.DB, permissions *model.SubjectPermissions, section model.SectionType, action model.ActionType) *gorm.DB {
+	if c.CanAdmin(permissions, section, action) {
+		if denied := c.deniedAdminNamespaces(permissions, section, action); len(denied) > 0 {
+			return query.Where(ColumnNamespaceCode+" NOT IN ?", denied)
+		}
+		return query
+	}
+
+	adminNamespaces := c.AdminNamespaces(permissions, section, action)
+	allowedNamespaces := c.extractAllowedNamespaces(permissions.Resources, action)
+	if len(adminNamespaces) == 0 || (allowedNamespaces != nil && len(allowedNamespaces) == 0) {
+		return c.FilterQueryByNamespace(query, permissions.Resources, action)
+	}
+
+	resourceScope := c.FilterQueryByNamespace(query.Session(&gorm.Session{NewDB: true}), permissions.Resources, action)
+	return query.Where(
+		query.Session(&gorm.Session{NewDB: true}).
+			Where(ColumnNamespaceCode+" IN ?", adminNamespaces).
+			Or(resourceScope),
+	)
+}
+
 // extractAllowedNamespaces returns the list of allowed namespaces for the given action.
 // Returns nil if no permissions match (should filter to nothing).
 // Returns empty slice if user has * namespace access (full access).
@@ -295,10 +508,41 @@ func (c *PermissionChecker) extractAllowedNamespaces(permissions []model.Resourc
 	return result
 }
 
-// filterPermissionsByAction returns only permissions that match the given action
+// excludeDenied adds WHERE conditions removing namespaces (and projects when withProjects is set)
+// covered by a deny rule on all resources for the given action.
+// Deny rules scoped to a single resource type are left to CanResource.
+func (c *PermissionChecker) excludeDenied(query *gorm.DB, permissions []model.ResourcePermission, action model.ActionType, withProjects bool) *gorm.DB {
+	for _, p := range permissions {
+		if !p.IsDeny() || p.Resource != model.ResourceTypeAll {
+			continue
+		}
+		if p.Action != model.ActionAll && p.Action != action {
+			continue
+		}
+
+		switch {
+		case p.Namespace == "*" && p.Project == "*":
+			return query.Where("1 = 0")
+		case p.Project == "*":
+			query = query.Where(ColumnNamespaceCode+" <> ?", p.Namespace)
+		case !withProjects:
+			continue
+		case p.Namespace == "*":
+			query = query.Where(ColumnProjectCode+" <> ?", p.Project)
+		default:
+			query = query.Where("NOT ("+ColumnNamespaceCode+" = ? AND "+ColumnProjectCode+" = ?)", p.Namespace, p.Project)
+		}
+	}
+	return query
+}
+
+// filterPermissionsByAction returns only allow permissions that match the given action
 func (c *PermissionChecker) filterPermissionsByAction(permissions []model.ResourcePermission, action model.ActionType) []model.ResourcePermission {
 	result := make([]model.ResourcePermission, 0, len(permissions))
 	for _, p := range permissions {
+		if p.IsDeny() {
+			continue
+		}
 		if p.Action == model.ActionAll || p.Action == action {
 			result = append(result, p)
 		}
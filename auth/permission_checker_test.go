@@ -222,6 +222,89 @@ func TestPermissionChecker_CanResource(t *testing.T) {
 			action:    model.ActionRead,
 			expected:  true,
 		},
+		{
+			name: "deny overrides wildcard allow",
+			permissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{
+					{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionAll},
+					{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionWrite, Effect: model.PermissionEffectDeny},
+				},
+			},
+			namespace: "ns1",
+			project:   "proj1",
+			resource:  model.ResourceTypeRedirect,
+			action:    model.ActionWrite,
+			expected:  false,
+		},
+		{
+			name: "deny does not affect other actions",
+			permissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{
+					{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionAll},
+					{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionWrite, Effect: model.PermissionEffectDeny},
+				},
+			},
+			namespace: "ns1",
+			project:   "proj1",
+			resource:  model.ResourceTypeRedirect,
+			action:    model.ActionRead,
+			expected:  true,
+		},
+		{
+			name: "deny does not affect other projects",
+			permissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{
+					{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionAll},
+					{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionAll, Effect: model.PermissionEffectDeny},
+				},
+			},
+			namespace: "ns1",
+			project:   "proj2",
+			resource:  model.ResourceTypeRedirect,
+			action:    model.ActionRead,
+			expected:  true,
+		},
+		{
+			name: "deny on one resource type does not refuse any resource check",
+			permissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{
+					{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+					{Namespace: "ns1", Project: "*", Resource: model.ResourceTypePage, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+				},
+			},
+			namespace: "ns1",
+			project:   "proj1",
+			resource:  model.ResourceTypeAny,
+			action:    model.ActionRead,
+			expected:  true,
+		},
+		{
+			name: "deny on all resources refuses any resource check",
+			permissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{
+					{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+					{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+				},
+			},
+			namespace: "ns1",
+			project:   "proj1",
+			resource:  model.ResourceTypeAny,
+			action:    model.ActionRead,
+			expected:  false,
+		},
+		{
+			name: "deny alone grants nothing",
+			permissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{
+					{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypePage, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+				},
+			},
+			namespace: "ns1",
+			project:   "proj1",
+			resource:  model.ResourceTypeRedirect,
+			action:    model.ActionRead,
+			expected:  false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -232,6 +315,65 @@ func TestPermissionChecker_CanResource(t *testing.T) {
 	}
 }
 
+func TestPermissionChecker_ExplainResource(t *testing.T) {
+	ctrl, _, checker := setupPermissionCheckerTest(t)
+	defer ctrl.Finish()
+
+	wildcard := model.ResourcePermission{RoleID: 1, Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionAll}
+	readNs1 := model.ResourcePermission{RoleID: 2, Namespace: "ns1", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead}
+	denyWrite := model.ResourcePermission{RoleID: 3, Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionWrite, Effect: model.PermissionEffectDeny}
+
+	t.Run("granted", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{Resources: []model.ResourcePermission{wildcard, readNs1, denyWrite}}
+
+		explanation := checker.ExplainResource(permissions, "ns1", "proj1", model.ResourceTypeRedirect, model.ActionRead)
+
+		assert.True(t, explanation.Allowed())
+		assert.Equal(t, PermissionDecisionGranted, explanation.Decision)
+		assert.Equal(t, []model.ResourcePermission{wildcard, readNs1}, explanation.Allows)
+		assert.Empty(t, explanation.Denies)
+		assert.Contains(t, explanation.Reason, "granted by 2 allow rule(s)")
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{Resources: []model.ResourcePermission{wildcard, readNs1, denyWrite}}
+
+		explanation := checker.ExplainResource(permissions, "ns1", "proj1", model.ResourceTypeRedirect, model.ActionWrite)
+
+		assert.False(t, explanation.Allowed())
+		assert.Equal(t, PermissionDecisionDenied, explanation.Decision)
+		assert.Equal(t, []model.ResourcePermission{wildcard}, explanation.Allows)
+		assert.Equal(t, []model.ResourcePermission{denyWrite}, explanation.Denies)
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{Resources: []model.ResourcePermission{readNs1}}
+
+		explanation := checker.ExplainResource(permissions, "ns2", "proj1", model.ResourceTypePage, model.ActionRead)
+
+		assert.False(t, explanation.Allowed())
+		assert.Equal(t, PermissionDecisionNoMatch, explanation.Decision)
+		assert.Equal(t, "none of the 1 resource permission(s) matches read on page in ns2/proj1", explanation.Reason)
+	})
+
+	t.Run("no permissions", func(t *testing.T) {
+		explanation := checker.ExplainResource(&model.SubjectPermissions{}, "ns1", "proj1", model.ResourceTypePage, model.ActionRead)
+
+		assert.Equal(t, PermissionDecisionNoMatch, explanation.Decision)
+		assert.Equal(t, "subject has no resource permissions", explanation.Reason)
+	})
+
+	t.Run("agrees with CanResource", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{Resources: []model.ResourcePermission{wildcard, readNs1, denyWrite}}
+		for _, action := range []model.ActionType{model.ActionRead, model.ActionWrite} {
+			for _, project := range []string{"proj1", "proj2"} {
+				explanation := checker.ExplainResource(permissions, "ns1", project, model.ResourceTypeAny, action)
+				assert.Equal(t, checker.CanResource(permissions, "ns1", project, model.ResourceTypeAny, action), explanation.Allowed())
+			}
+		}
+	})
+}
+
 func TestPermissionChecker_CanAdmin(t *testing.T) {
 	ctrl, _, checker := setupPermissionCheckerTest(t)
 	defer ctrl.Finish()
@@ -330,6 +472,30 @@ func TestPermissionChecker_CanAdmin(t *testing.T) {
 			action:   model.ActionWrite,
 			expected: true,
 		},
+		{
+			name: "deny overrides wildcard allow",
+			permissions: &model.SubjectPermissions{
+				Admin: []model.AdminPermission{
+					{Section: "*", Action: model.ActionAll},
+					{Section: model.AdminSectionUsers, Action: model.ActionWrite, Effect: model.PermissionEffectDeny},
+				},
+			},
+			section:  model.AdminSectionUsers,
+			action:   model.ActionWrite,
+			expected: false,
+		},
+		{
+			name: "deny does not affect other sections",
+			permissions: &model.SubjectPermissions{
+				Admin: []model.AdminPermission{
+					{Section: "*", Action: model.ActionAll},
+					{Section: model.AdminSectionUsers, Action: model.ActionAll, Effect: model.PermissionEffectDeny},
+				},
+			},
+			section:  model.AdminSectionRoles,
+			action:   model.ActionWrite,
+			expected: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -340,6 +506,121 @@ func TestPermissionChecker_CanAdmin(t *testing.T) {
 	}
 }
 
+func TestPermissionChecker_CanAdminNamespace(t *testing.T) {
+	ctrl, _, checker := setupPermissionCheckerTest(t)
+	defer ctrl.Finish()
+
+	scoped := &model.SubjectPermissions{
+		Admin: []model.AdminPermission{
+			{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionAll},
+		},
+	}
+
+	t.Run("scoped permission applies to its namespace", func(t *testing.T) {
+		assert.True(t, checker.CanAdminNamespace(scoped, "ns1", model.AdminSectionProjects, model.ActionWrite))
+	})
+
+	t.Run("scoped permission does not apply to other namespaces", func(t *testing.T) {
+		assert.False(t, checker.CanAdminNamespace(scoped, "ns2", model.AdminSectionProjects, model.ActionWrite))
+	})
+
+	t.Run("scoped permission does not grant global access", func(t *testing.T) {
+		assert.False(t, checker.CanAdmin(scoped, model.AdminSectionProjects, model.ActionRead))
+	})
+
+	t.Run("global permission applies to every namespace", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{
+			Admin: []model.AdminPermission{
+				{Section: model.AdminSectionProjects, Action: model.ActionRead},
+			},
+		}
+		assert.True(t, checker.CanAdminNamespace(permissions, "ns2", model.AdminSectionProjects, model.ActionRead))
+	})
+
+	t.Run("scoped deny overrides global allow in its namespace only", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{
+			Admin: []model.AdminPermission{
+				{Namespace: "*", Section: model.AdminSectionAll, Action: model.ActionAll},
+				{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionWrite, Effect: model.PermissionEffectDeny},
+			},
+		}
+		assert.False(t, checker.CanAdminNamespace(permissions, "ns1", model.AdminSectionProjects, model.ActionWrite))
+		assert.True(t, checker.CanAdminNamespace(permissions, "ns2", model.AdminSectionProjects, model.ActionWrite))
+		assert.True(t, checker.CanAdmin(permissions, model.AdminSectionProjects, model.ActionWrite))
+	})
+}
+
+func TestPermissionChecker_AdminNamespaces(t *testing.T) {
+	ctrl, _, checker := setupPermissionCheckerTest(t)
+	defer ctrl.Finish()
+
+	permissions := &model.SubjectPermissions{
+		Admin: []model.AdminPermission{
+			{Namespace: "*", Section: model.AdminSectionUsers, Action: model.ActionAll},
+			{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionAll},
+			{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionRead},
+			{Namespace: "ns2", Section: model.AdminSectionProjects, Action: model.ActionRead},
+			{Namespace: "ns3", Section: model.AdminSectionProjects, Action: model.ActionAll},
+			{Namespace: "ns3", Section: model.AdminSectionProjects, Action: model.ActionAll, Effect: model.PermissionEffectDeny},
+		},
+	}
+
+	assert.Equal(t, []string{"ns1", "ns2"}, checker.AdminNamespaces(permissions, model.AdminSectionProjects, model.ActionRead))
+	assert.Equal(t, []string{"ns1"}, checker.AdminNamespaces(permissions, model.AdminSectionProjects, model.ActionWrite))
+	assert.Empty(t, checker.AdminNamespaces(permissions, model.AdminSectionUsers, model.ActionRead))
+}
+
+func TestPermissionChecker_Covers(t *testing.T) {
+	ctrl, _, checker := setupPermissionCheckerTest(t)
+	defer ctrl.Finish()
+
+	impersonator := &model.SubjectPermissions{
+		Resources: []model.ResourcePermission{
+			{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionAll},
+			{Namespace: "ns2", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionWrite, Effect: model.PermissionEffectDeny},
+		},
+		Admin: []model.AdminPermission{
+			{Namespace: "*", Section: model.AdminSectionImpersonate, Action: model.ActionWrite},
+			{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionAll},
+		},
+	}
+
+	t.Run("user without permissions", func(t *testing.T) {
+		assert.True(t, checker.Covers(impersonator, &model.SubjectPermissions{}))
+	})
+
+	t.Run("user with fewer permissions", func(t *testing.T) {
+		assert.True(t, checker.Covers(impersonator, &model.SubjectPermissions{
+			Resources: []model.ResourcePermission{{Namespace: "ns1", Project: "p1", Resource: model.ResourceTypeRedirect, Action: model.ActionWrite}},
+			Admin:     []model.AdminPermission{{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionRead}},
+		}))
+	})
+
+	t.Run("user with a global admin permission", func(t *testing.T) {
+		assert.False(t, checker.Covers(impersonator, &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Namespace: "*", Section: model.AdminSectionAll, Action: model.ActionAll}},
+		}))
+	})
+
+	t.Run("user with an admin permission in another namespace", func(t *testing.T) {
+		assert.False(t, checker.Covers(impersonator, &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Namespace: "ns2", Section: model.AdminSectionProjects, Action: model.ActionRead}},
+		}))
+	})
+
+	t.Run("user allowed what the impersonator is denied", func(t *testing.T) {
+		assert.False(t, checker.Covers(impersonator, &model.SubjectPermissions{
+			Resources: []model.ResourcePermission{{Namespace: "*", Project: "*", Resource: model.ResourceTypePage, Action: model.ActionWrite}},
+		}))
+	})
+
+	t.Run("deny rules of the user are ignored", func(t *testing.T) {
+		assert.True(t, checker.Covers(impersonator, &model.SubjectPermissions{
+			Resources: []model.ResourcePermission{{Namespace: "ns3", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionAll, Effect: model.PermissionEffectDeny}},
+		}))
+	})
+}
+
 func TestPermissionChecker_CanResourceForUsername(t *testing.T) {
 	t.Run("success - permission granted", func(t *testing.T) {
 		ctrl, mockRoleService, checker := setupPermissionCheckerTest(t)
@@ -924,6 +1205,36 @@ func TestPermissionChecker_FilterQueryByNamespace(t *testing.T) {
 
 		assert.Contains(t, sql, "1 = 0")
 	})
+
+	t.Run("denied namespace - excluded", func(t *testing.T) {
+		permissions := []model.ResourcePermission{
+			{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+			{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+		}
+
+		sql := toSQL(checker.FilterQueryByNamespace(
+			mockDB(),
+			permissions,
+			model.ActionRead,
+		))
+
+		assert.Contains(t, sql, ColumnNamespaceCode+" <> \"ns1\"")
+	})
+
+	t.Run("denied project - namespace kept", func(t *testing.T) {
+		permissions := []model.ResourcePermission{
+			{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+			{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+		}
+
+		sql := toSQL(checker.FilterQueryByNamespace(
+			mockDB(),
+			permissions,
+			model.ActionRead,
+		))
+
+		assert.NotContains(t, sql, "WHERE")
+	})
 }
 
 func TestPermissionChecker_FilterQueryByProject(t *testing.T) {
@@ -1057,6 +1368,39 @@ func TestPermissionChecker_FilterQueryByProject(t *testing.T) {
 		assert.Contains(t, sql, ColumnNamespaceCode+" =")
 		assert.Contains(t, sql, ColumnProjectCode+" IN")
 	})
+
+	t.Run("denied project - excluded", func(t *testing.T) {
+		permissions := []model.ResourcePermission{
+			{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+			{Namespace: "*", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionAll, Effect: model.PermissionEffectDeny},
+		}
+
+		sql := toSQL(checker.FilterQueryByProject(
+			mockDB(),
+			permissions,
+			"ns1",
+			model.ActionRead,
+		))
+
+		assert.Contains(t, sql, ColumnProjectCode+" <> \"proj1\"")
+	})
+
+	t.Run("deny on single resource type - not excluded", func(t *testing.T) {
+		permissions := []model.ResourcePermission{
+			{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+			{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypePage, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+		}
+
+		sql := toSQL(checker.FilterQueryByProject(
+			mockDB(),
+			permissions,
+			"ns1",
+			model.ActionRead,
+		))
+
+		assert.NotContains(t, sql, "NOT")
+		assert.NotContains(t, sql, "<>")
+	})
 }
 
 func TestPermissionChecker_FilterQueryByNamespaceProject(t *testing.T) {
@@ -1151,6 +1495,99 @@ func TestPermissionChecker_FilterQueryByNamespaceProject(t *testing.T) {
 
 		assert.Contains(t, sql, "1 = 0")
 	})
+
+	t.Run("global deny - adds false condition", func(t *testing.T) {
+		permissions := []model.ResourcePermission{
+			{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionAll},
+			{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionWrite, Effect: model.PermissionEffectDeny},
+		}
+
+		sql := toSQL(checker.FilterQueryByNamespaceProject(
+			mockDB(),
+			permissions,
+			model.ActionWrite,
+		))
+
+		assert.Contains(t, sql, "1 = 0")
+	})
+
+	t.Run("denied project - excluded", func(t *testing.T) {
+		permissions := []model.ResourcePermission{
+			{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+			{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+		}
+
+		sql := toSQL(checker.FilterQueryByNamespaceProject(
+			mockDB(),
+			permissions,
+			model.ActionRead,
+		))
+
+		assert.Contains(t, sql, "NOT ("+ColumnNamespaceCode+" = \"ns1\" AND "+ColumnProjectCode+" = \"proj1\")")
+	})
+}
+
+func TestPermissionChecker_FilterQueryByAdminNamespace(t *testing.T) {
+	ctrl, _, checker := setupPermissionCheckerTest(t)
+	defer ctrl.Finish()
+
+	t.Run("global admin - no filter added", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Section: model.AdminSectionProjects, Action: model.ActionRead}},
+		}
+
+		sql := toSQL(checker.FilterQueryByAdminNamespace(mockDB(), permissions, model.AdminSectionProjects, model.ActionRead))
+
+		assert.NotContains(t, sql, "WHERE")
+	})
+
+	t.Run("global admin denied on a namespace - namespace excluded", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{
+			Admin: []model.AdminPermission{
+				{Section: model.AdminSectionProjects, Action: model.ActionRead},
+				{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+				{Namespace: "ns2", Section: model.AdminSectionUsers, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+			},
+		}
+
+		sql := toSQL(checker.FilterQueryByAdminNamespace(mockDB(), permissions, model.AdminSectionProjects, model.ActionRead))
+
+		assert.Contains(t, sql, ColumnNamespaceCode+" NOT IN (\"ns1\")")
+		assert.NotContains(t, sql, "ns2")
+		assert.False(t, checker.CanAdminNamespace(permissions, "ns1", model.AdminSectionProjects, model.ActionRead))
+		assert.True(t, checker.CanAdminNamespace(permissions, "ns2", model.AdminSectionProjects, model.ActionRead))
+	})
+
+	t.Run("no admin permission - filters by resource permissions", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{
+			Resources: []model.ResourcePermission{{Namespace: "ns2", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead}},
+		}
+
+		sql := toSQL(checker.FilterQueryByAdminNamespace(mockDB(), permissions, model.AdminSectionProjects, model.ActionRead))
+
+		assert.Contains(t, sql, ColumnNamespaceCode+" IN (\"ns2\")")
+	})
+
+	t.Run("scoped admin - adds its namespaces to resource permissions", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{
+			Admin:     []model.AdminPermission{{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionRead}},
+			Resources: []model.ResourcePermission{{Namespace: "ns2", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead}},
+		}
+
+		sql := toSQL(checker.FilterQueryByAdminNamespace(mockDB(), permissions, model.AdminSectionProjects, model.ActionRead).Where("project_code = ?", "proj1"))
+
+		assert.Contains(t, sql, "("+ColumnNamespaceCode+" IN (\"ns1\") OR "+ColumnNamespaceCode+" IN (\"ns2\")) AND project_code")
+	})
+
+	t.Run("scoped admin without resource permissions", func(t *testing.T) {
+		permissions := &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionRead}},
+		}
+
+		sql := toSQL(checker.FilterQueryByAdminNamespace(mockDB(), permissions, model.AdminSectionProjects, model.ActionRead).Where("project_code = ?", "proj1"))
+
+		assert.Contains(t, sql, "("+ColumnNamespaceCode+" IN (\"ns1\") OR 1 = 0) AND project_code")
+	})
 }
 
 // testDB is a test table for SQL generation tests
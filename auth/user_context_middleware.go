@@ -2,11 +2,14 @@ package auth
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/flectolab/flecto-manager/config"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/i18n"
 	flectoJwt "github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/service"
@@ -24,32 +27,63 @@ type UserContext struct {
 	Username           string
 	SubjectPermissions *model.SubjectPermissions
 	AuthType           types.AuthType
+	// ImpersonatorID and ImpersonatorUsername are set when an administrator acts as this user
+	ImpersonatorID       int64
+	ImpersonatorUsername string
+	// ExpiresAt is the expiration of the token authenticating the request, nil when it does not expire
+	ExpiresAt *time.Time
 }
 
 func (uc UserContext) GetUserIdStr() string {
 	return strconv.FormatInt(uc.UserID, 10)
 }
 
+// IsImpersonated returns true if the request is made by an administrator acting as the user
+func (uc UserContext) IsImpersonated() bool {
+	return uc.ImpersonatorID != 0
+}
+
+// Subject returns the subject recorded as the author of the changes, "admin (as user)" when an administrator acts as the user
+func (uc UserContext) Subject() string {
+	if uc.IsImpersonated() {
+		return fmt.Sprintf("%s (as %s)", uc.ImpersonatorUsername, uc.Username)
+	}
+	return uc.Username
+}
+
+// IsProjectAPIKey returns true if the request is authenticated by a project API key, which may not publish
+func (uc UserContext) IsProjectAPIKey() bool {
+	return uc.AuthType == types.AuthTypeProjectAPIKey
+}
+
 func GetUser(ctx context.Context) *UserContext {
 	user, _ := ctx.Value(userCtxKey).(*UserContext)
 	return user
 }
 
-// SetUserContext adds a UserContext to the given context. This is primarily used for testing.
+// SetUserContext adds a UserContext to the given context, with its subject recorded as the author of the changes
 func SetUserContext(ctx context.Context, userCtx *UserContext) context.Context {
+	if userCtx != nil {
+		ctx = types.WithSubject(ctx, userCtx.Subject())
+	}
 	return context.WithValue(ctx, userCtxKey, userCtx)
 }
 
-func UserCtxAuthMiddleware(jwtConfig *config.JWTConfig, userService service.UserService, roleService service.RoleService, tokenService service.TokenService) echo.MiddlewareFunc {
+func UserCtxAuthMiddleware(jwtConfig *config.JWTConfig, userService service.UserService, roleService service.RoleService, tokenService service.TokenService, projectAPIKeyService service.ProjectAPIKeyService) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			authHeader := c.Request().Header.Get(jwtConfig.HeaderName)
 			if len(authHeader) <= 7 || authHeader[:7] != "Bearer " {
-				return errors.New("missing or invalid Authorization header")
+				return flectoErrors.New(flectoErrors.CodeUnauthenticated, "missing or invalid Authorization header")
 			}
 
 			token := authHeader[7:]
 
+			// Project API key auth (prefixed by flectopk_)
+			if strings.HasPrefix(token, model.ProjectAPIKeyPrefix) {
+				return handleProjectAPIKeyAuth(c, next, projectAPIKeyService, token)
+			}
+
 			// API Token auth (prefixed by flecto_)
 			if strings.HasPrefix(token, model.TokenPrefix) {
 				return handleAPITokenAuth(c, next, tokenService, token)
@@ -64,14 +98,34 @@ func UserCtxAuthMiddleware(jwtConfig *config.JWTConfig, userService service.User
 func handleAPITokenAuth(c echo.Context, next echo.HandlerFunc, tokenService service.TokenService, plainToken string) error {
 	token, permissions, err := tokenService.ValidateToken(context.Background(), plainToken)
 	if err != nil {
-		return errors.New("invalid API token")
+		return flectoErrors.New(flectoErrors.CodeUnauthenticated, "invalid API token")
 	}
 
-	ctx := context.WithValue(c.Request().Context(), userCtxKey, &UserContext{
+	ctx := SetUserContext(c.Request().Context(), &UserContext{
 		UserID:             0,
 		Username:           token.Name,
 		AuthType:           types.AuthTypeToken,
 		SubjectPermissions: permissions,
+		ExpiresAt:          token.ExpiresAt,
+	})
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	return next(c)
+}
+
+// handleProjectAPIKeyAuth authenticates a project API key, its permissions being limited to the redirects and pages of its project
+func handleProjectAPIKeyAuth(c echo.Context, next echo.HandlerFunc, projectAPIKeyService service.ProjectAPIKeyService, plainKey string) error {
+	key, err := projectAPIKeyService.Validate(context.Background(), plainKey)
+	if err != nil {
+		return flectoErrors.New(flectoErrors.CodeUnauthenticated, "invalid project API key")
+	}
+
+	ctx := SetUserContext(c.Request().Context(), &UserContext{
+		UserID:             0,
+		Username:           key.GetSubject(),
+		AuthType:           types.AuthTypeProjectAPIKey,
+		SubjectPermissions: key.Permissions(),
+		ExpiresAt:          key.ExpiresAt,
 	})
 	c.SetRequest(c.Request().WithContext(ctx))
 
@@ -83,7 +137,7 @@ func handleJWTAuth(c echo.Context, next echo.HandlerFunc, jwtConfig *config.JWTC
 		return []byte(jwtConfig.Secret), nil
 	})
 	if err != nil || !token.Valid {
-		return errors.New("invalid Authorization token")
+		return flectoErrors.New(flectoErrors.CodeUnauthenticated, "invalid Authorization token")
 	}
 
 	if claims, ok := token.Claims.(*flectoJwt.Claims); ok && claims.TokenType == types.TokenTypeAccess {
@@ -94,7 +148,7 @@ func handleJWTAuth(c echo.Context, next echo.HandlerFunc, jwtConfig *config.JWTC
 
 		user, errGetUser := userService.GetByID(context.Background(), claims.UserID)
 		if errGetUser != nil || !*user.Active {
-			return service.ErrUserNotFound
+			return flectoErrors.Wrap(flectoErrors.CodeUnauthenticated, service.ErrUserNotFound)
 		}
 
 		userPermissions, errUserPerm := roleService.GetPermissionsByUsername(context.Background(), user.Username)
@@ -110,12 +164,23 @@ func handleJWTAuth(c echo.Context, next echo.HandlerFunc, jwtConfig *config.JWTC
 			}
 		}
 
-		ctx := context.WithValue(c.Request().Context(), userCtxKey, &UserContext{
-			UserID:             claims.UserID,
-			Username:           claims.Username,
-			AuthType:           claims.AuthType,
-			SubjectPermissions: subjectPermissions,
+		var expiresAt *time.Time
+		if claims.ExpiresAt != nil {
+			expiresAt = &claims.ExpiresAt.Time
+		}
+
+		ctx := SetUserContext(c.Request().Context(), &UserContext{
+			UserID:               claims.UserID,
+			Username:             claims.Username,
+			AuthType:             claims.AuthType,
+			SubjectPermissions:   subjectPermissions,
+			ImpersonatorID:       claims.ImpersonatorID,
+			ImpersonatorUsername: claims.ImpersonatorUsername,
+			ExpiresAt:            expiresAt,
 		})
+		if lang, ok := i18n.Parse(user.Language); ok {
+			ctx = i18n.WithLanguage(ctx, lang)
+		}
 		c.SetRequest(c.Request().WithContext(ctx))
 	}
 
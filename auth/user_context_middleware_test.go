@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/i18n"
 	"github.com/flectolab/flecto-manager/jwt"
 	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
 	"github.com/flectolab/flecto-manager/model"
@@ -20,19 +21,21 @@ import (
 )
 
 type middlewareMocks struct {
-	ctrl         *gomock.Controller
-	userService  *mockFlectoService.MockUserService
-	roleService  *mockFlectoService.MockRoleService
-	tokenService *mockFlectoService.MockTokenService
+	ctrl                 *gomock.Controller
+	userService          *mockFlectoService.MockUserService
+	roleService          *mockFlectoService.MockRoleService
+	tokenService         *mockFlectoService.MockTokenService
+	projectAPIKeyService *mockFlectoService.MockProjectAPIKeyService
 }
 
 func setupMiddlewareMocks(t *testing.T) (*middlewareMocks, *config.JWTConfig) {
 	ctrl := gomock.NewController(t)
 	mocks := &middlewareMocks{
-		ctrl:         ctrl,
-		userService:  mockFlectoService.NewMockUserService(ctrl),
-		roleService:  mockFlectoService.NewMockRoleService(ctrl),
-		tokenService: mockFlectoService.NewMockTokenService(ctrl),
+		ctrl:                 ctrl,
+		userService:          mockFlectoService.NewMockUserService(ctrl),
+		roleService:          mockFlectoService.NewMockRoleService(ctrl),
+		tokenService:         mockFlectoService.NewMockTokenService(ctrl),
+		projectAPIKeyService: mockFlectoService.NewMockProjectAPIKeyService(ctrl),
 	}
 	jwtConfig := &config.JWTConfig{
 		Secret:          "test-secret-key",
@@ -52,7 +55,7 @@ func TestUserCtxAuthMiddleware_MissingHeader(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -72,7 +75,7 @@ func TestUserCtxAuthMiddleware_InvalidBearerFormat(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -92,7 +95,7 @@ func TestUserCtxAuthMiddleware_ShortHeader(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -127,7 +130,7 @@ func TestUserCtxAuthMiddleware_APIToken_Valid(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 
 	var userCtx *UserContext
 	handler := middleware(func(c echo.Context) error {
@@ -141,6 +144,7 @@ func TestUserCtxAuthMiddleware_APIToken_Valid(t *testing.T) {
 	assert.Equal(t, int64(0), userCtx.UserID)
 	assert.Equal(t, "test-api-token", userCtx.Username)
 	assert.Equal(t, types.AuthTypeToken, userCtx.AuthType)
+	assert.Nil(t, userCtx.ExpiresAt)
 	assert.Len(t, userCtx.SubjectPermissions.Resources, 1)
 }
 
@@ -160,7 +164,7 @@ func TestUserCtxAuthMiddleware_APIToken_Invalid(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -170,6 +174,81 @@ func TestUserCtxAuthMiddleware_APIToken_Invalid(t *testing.T) {
 	assert.Contains(t, err.Error(), "invalid API token")
 }
 
+func TestUserCtxAuthMiddleware_ProjectAPIKey_Valid(t *testing.T) {
+	mocks, jwtConfig := setupMiddlewareMocks(t)
+	defer mocks.ctrl.Finish()
+
+	plainKey := "flectopk_testkey1234567890123456789012345"
+	key := &model.ProjectAPIKey{
+		ID:            1,
+		NamespaceCode: "ns1",
+		ProjectCode:   "proj1",
+		Name:          "ci",
+		Scopes:        []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeWrite},
+	}
+
+	mocks.projectAPIKeyService.EXPECT().
+		Validate(gomock.Any(), plainKey).
+		Return(key, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+plainKey)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
+
+	var userCtx *UserContext
+	handler := middleware(func(c echo.Context) error {
+		userCtx = GetUser(c.Request().Context())
+		return c.String(http.StatusOK, "ok")
+	})
+
+	err := handler(c)
+	assert.NoError(t, err)
+	assert.NotNil(t, userCtx)
+	assert.Equal(t, int64(0), userCtx.UserID)
+	assert.Equal(t, "apikey:ns1/proj1/ci", userCtx.Username)
+	assert.Equal(t, types.AuthTypeProjectAPIKey, userCtx.AuthType)
+	assert.True(t, userCtx.IsProjectAPIKey())
+
+	// The key only reaches the redirects and pages of its project
+	checker := NewPermissionChecker(mocks.roleService)
+	assert.True(t, checker.CanResource(userCtx.SubjectPermissions, "ns1", "proj1", model.ResourceTypeRedirect, model.ActionWrite))
+	assert.True(t, checker.CanResource(userCtx.SubjectPermissions, "ns1", "proj1", model.ResourceTypePage, model.ActionWrite))
+	assert.False(t, checker.CanResource(userCtx.SubjectPermissions, "ns1", "proj1", model.ResourceTypeRedirect, model.ActionRead))
+	assert.False(t, checker.CanResource(userCtx.SubjectPermissions, "ns1", "proj2", model.ResourceTypeRedirect, model.ActionWrite))
+	assert.False(t, checker.CanResource(userCtx.SubjectPermissions, "ns1", "proj1", model.ResourceTypeAgent, model.ActionWrite))
+	assert.False(t, checker.CanAdminNamespace(userCtx.SubjectPermissions, "ns1", model.AdminSectionProjects, model.ActionWrite))
+}
+
+func TestUserCtxAuthMiddleware_ProjectAPIKey_Invalid(t *testing.T) {
+	mocks, jwtConfig := setupMiddlewareMocks(t)
+	defer mocks.ctrl.Finish()
+
+	plainKey := "flectopk_invalidkey"
+
+	mocks.projectAPIKeyService.EXPECT().
+		Validate(gomock.Any(), plainKey).
+		Return(nil, service.ErrInvalidProjectAPIKey)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+plainKey)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
+	handler := middleware(func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	err := handler(c)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid project API key")
+}
+
 func TestUserCtxAuthMiddleware_JWT_Valid(t *testing.T) {
 	mocks, jwtConfig := setupMiddlewareMocks(t)
 	defer mocks.ctrl.Finish()
@@ -198,7 +277,7 @@ func TestUserCtxAuthMiddleware_JWT_Valid(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 
 	var userCtx *UserContext
 	handler := middleware(func(c echo.Context) error {
@@ -212,6 +291,83 @@ func TestUserCtxAuthMiddleware_JWT_Valid(t *testing.T) {
 	assert.Equal(t, int64(1), userCtx.UserID)
 	assert.Equal(t, "testuser", userCtx.Username)
 	assert.Equal(t, types.AuthTypeBasic, userCtx.AuthType)
+	assert.NotNil(t, userCtx.ExpiresAt)
+}
+
+func TestUserCtxAuthMiddleware_JWT_Language(t *testing.T) {
+	mocks, jwtConfig := setupMiddlewareMocks(t)
+	defer mocks.ctrl.Finish()
+
+	jwtService := jwt.NewServiceJWT(jwtConfig)
+	user := &model.User{ID: 1, Username: "testuser"}
+	tokenPair, err := jwtService.GenerateTokenPair(user, types.AuthTypeBasic, nil, nil)
+	assert.NoError(t, err)
+
+	mocks.userService.EXPECT().
+		GetByID(gomock.Any(), int64(1)).
+		Return(&model.User{ID: 1, Username: "testuser", Active: types.Ptr(true), Language: "de"}, nil)
+
+	mocks.roleService.EXPECT().
+		GetPermissionsByUsername(gomock.Any(), "testuser").
+		Return(&model.SubjectPermissions{}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenPair.AccessToken)
+	c := e.NewContext(req.WithContext(i18n.WithLanguage(req.Context(), i18n.French)), httptest.NewRecorder())
+
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
+
+	var lang i18n.Language
+	handler := middleware(func(c echo.Context) error {
+		lang = i18n.FromContext(c.Request().Context())
+		return c.String(http.StatusOK, "ok")
+	})
+
+	assert.NoError(t, handler(c))
+	assert.Equal(t, i18n.German, lang, "the preference of the user replaces the negotiated language")
+}
+
+func TestUserCtxAuthMiddleware_JWT_Impersonation(t *testing.T) {
+	mocks, jwtConfig := setupMiddlewareMocks(t)
+	defer mocks.ctrl.Finish()
+
+	jwtService := jwt.NewServiceJWT(jwtConfig)
+	user := &model.User{ID: 2, Username: "target"}
+	admin := &model.User{ID: 1, Username: "admin"}
+	accessToken, _, err := jwtService.GenerateImpersonationToken(user, admin)
+	assert.NoError(t, err)
+
+	mocks.userService.EXPECT().
+		GetByID(gomock.Any(), int64(2)).
+		Return(&model.User{ID: 2, Username: "target", Active: types.Ptr(true)}, nil)
+
+	mocks.roleService.EXPECT().
+		GetPermissionsByUsername(gomock.Any(), "target").
+		Return(&model.SubjectPermissions{}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
+
+	var userCtx *UserContext
+	handler := middleware(func(c echo.Context) error {
+		userCtx = GetUser(c.Request().Context())
+		return c.String(http.StatusOK, "ok")
+	})
+
+	err = handler(c)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), userCtx.UserID)
+	assert.Equal(t, "target", userCtx.Username)
+	assert.Equal(t, types.AuthTypeImpersonation, userCtx.AuthType)
+	assert.True(t, userCtx.IsImpersonated())
+	assert.Equal(t, int64(1), userCtx.ImpersonatorID)
+	assert.Equal(t, "admin", userCtx.ImpersonatorUsername)
 }
 
 func TestUserCtxAuthMiddleware_JWT_Invalid(t *testing.T) {
@@ -224,7 +380,7 @@ func TestUserCtxAuthMiddleware_JWT_Invalid(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -274,7 +430,7 @@ func TestUserCtxAuthMiddleware_JWT_WithExtraRoles(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 
 	var userCtx *UserContext
 	handler := middleware(func(c echo.Context) error {
@@ -309,14 +465,14 @@ func TestUserCtxAuthMiddleware_JWT_UserNotFound(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
 
 	err = handler(c)
 	assert.Error(t, err)
-	assert.Equal(t, service.ErrUserNotFound, err)
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
 }
 
 func TestUserCtxAuthMiddleware_JWT_InactiveUser(t *testing.T) {
@@ -342,14 +498,14 @@ func TestUserCtxAuthMiddleware_JWT_InactiveUser(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
 
 	err = handler(c)
 	assert.Error(t, err)
-	assert.Equal(t, service.ErrUserNotFound, err)
+	assert.ErrorIs(t, err, service.ErrUserNotFound)
 }
 
 func TestUserCtxAuthMiddleware_JWT_PermissionsError(t *testing.T) {
@@ -381,7 +537,7 @@ func TestUserCtxAuthMiddleware_JWT_PermissionsError(t *testing.T) {
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
 
-	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService)
+	middleware := UserCtxAuthMiddleware(jwtConfig, mocks.userService, mocks.roleService, mocks.tokenService, mocks.projectAPIKeyService)
 	handler := middleware(func(c echo.Context) error {
 		return c.String(http.StatusOK, "ok")
 	})
@@ -456,4 +612,18 @@ func TestSetUserContext(t *testing.T) {
 	assert.Equal(t, int64(1), result.UserID)
 	assert.Equal(t, "testuser", result.Username)
 	assert.Equal(t, types.AuthTypeBasic, result.AuthType)
+	assert.Equal(t, "testuser", types.SubjectFromContext(ctx))
+}
+
+func TestSetUserContext_Impersonated(t *testing.T) {
+	ctx := SetUserContext(context.Background(), &UserContext{
+		UserID:               2,
+		Username:             "target",
+		AuthType:             types.AuthTypeImpersonation,
+		ImpersonatorID:       1,
+		ImpersonatorUsername: "admin",
+	})
+
+	assert.Equal(t, "target", GetUser(ctx).Username)
+	assert.Equal(t, "admin (as target)", types.SubjectFromContext(ctx))
 }
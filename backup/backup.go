@@ -0,0 +1,225 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/version"
+	"gorm.io/gorm"
+)
+
+const (
+	// FormatVersion is the version of the backup format, increased on incompatible changes
+	FormatVersion = 1
+	ManifestFile  = "manifest.json"
+	// TableFileExt is the extension of the table files, holding one JSON object per line
+	TableFileExt = ".jsonl"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported backup format")
+	ErrDatabaseNotEmpty  = errors.New("database not empty")
+)
+
+// Manifest describes the backup, it is the first file of the archive
+type Manifest struct {
+	FormatVersion int       `json:"formatVersion"`
+	Version       string    `json:"version"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Tables        []string  `json:"tables"`
+}
+
+// Counts are the number of rows per table written or restored
+type Counts map[string]int
+
+// Write writes the backup of the namespaces, their projects, redirects, pages, drafts and templates, and
+// of the roles with their permissions to w, as a tar.gz archive of a JSON lines file per table.
+// Users and tokens are not part of the backup, nor the roles of users and tokens.
+func Write(ctx context.Context, db *gorm.DB, w io.Writer) (Counts, error) {
+	ctx = database.WithPrimary(ctx)
+	gzipWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	manifest := Manifest{
+		FormatVersion: FormatVersion,
+		Version:       version.GetFormattedVersion(),
+		CreatedAt:     time.Now().UTC(),
+		Tables:        make([]string, 0, len(tables)),
+	}
+	for _, t := range tables {
+		manifest.Tables = append(manifest.Tables, t.name())
+	}
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err = writeFile(tarWriter, ManifestFile, int64(len(content)), bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
+
+	counts := make(Counts, len(tables))
+	dumpTables := func(tx *gorm.DB) error {
+		for _, t := range tables {
+			count, errTable := writeTable(ctx, tx, tarWriter, t)
+			if errTable != nil {
+				return fmt.Errorf("table %s: %w", t.name(), errTable)
+			}
+			counts[t.name()] = count
+		}
+		return nil
+	}
+	// Without shards the tables are read in a transaction so that the backup is consistent, the
+	// transaction would only reach the primary database otherwise
+	if _, sharded := db.ConnPool.(*database.ShardRouter); sharded {
+		err = dumpTables(db)
+	} else {
+		err = db.WithContext(ctx).Transaction(dumpTables)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tarWriter.Close(); err != nil {
+		return nil, err
+	}
+	return counts, gzipWriter.Close()
+}
+
+// writeTable writes the rows of the table to a temporary file, the size of the archive files
+// being needed before their content
+func writeTable(ctx context.Context, db *gorm.DB, tarWriter *tar.Writer, t table) (int, error) {
+	file, err := os.CreateTemp("", "flecto-backup-*"+TableFileExt)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	buffer := bufio.NewWriter(file)
+	count, err := t.dump(ctx, db, buffer)
+	if err != nil {
+		return 0, err
+	}
+	if err = buffer.Flush(); err != nil {
+		return 0, err
+	}
+	size, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return count, writeFile(tarWriter, t.name()+TableFileExt, size, file)
+}
+
+func writeFile(tarWriter *tar.Writer, name string, size int64, r io.Reader) error {
+	header := &tar.Header{Name: name, Mode: 0o600, Size: size, ModTime: time.Now()}
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(tarWriter, r)
+	return err
+}
+
+// Restore loads the backup read from r into a migrated database holding no namespace. The roles
+// already in the database are kept with their permissions, the other roles of the backup get a new id.
+// The rows are written by batch, a failed restore leaves the rows already written.
+func Restore(ctx context.Context, db *gorm.DB, r io.Reader) (*Manifest, Counts, error) {
+	ctx = database.WithPrimary(ctx)
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	defer func() { _ = gzipReader.Close() }()
+	tarReader := tar.NewReader(gzipReader)
+
+	manifest, err := readManifest(tarReader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, t := range tables {
+		if t.merged() {
+			continue
+		}
+		count, errCount := t.count(ctx, db)
+		if errCount != nil {
+			return nil, nil, errCount
+		}
+		if count > 0 {
+			return nil, nil, fmt.Errorf("%w: table %s has %d rows", ErrDatabaseNotEmpty, t.name(), count)
+		}
+	}
+
+	state := newRestoreState()
+	counts := make(Counts, len(tables))
+	next := 0
+	for {
+		header, errNext := tarReader.Next()
+		if errors.Is(errNext, io.EOF) {
+			break
+		}
+		if errNext != nil {
+			return nil, nil, errNext
+		}
+
+		// The tables are restored in the order of the backup, the referenced rows first
+		name := strings.TrimSuffix(header.Name, TableFileExt)
+		index := tableIndex(name)
+		if index < 0 {
+			return nil, nil, fmt.Errorf("%w: unknown file '%s'", ErrUnsupportedFormat, header.Name)
+		}
+		if index < next {
+			return nil, nil, fmt.Errorf("%w: table %s is out of order", ErrUnsupportedFormat, name)
+		}
+		next = index + 1
+
+		count, errTable := tables[index].restore(ctx, db, tarReader, state)
+		if errTable != nil {
+			return nil, nil, fmt.Errorf("table %s: %w", name, errTable)
+		}
+		counts[name] = count
+	}
+	return manifest, counts, nil
+}
+
+func readManifest(tarReader *tar.Reader) (*Manifest, error) {
+	header, err := tarReader.Next()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if header.Name != ManifestFile {
+		return nil, fmt.Errorf("%w: the archive does not start with %s", ErrUnsupportedFormat, ManifestFile)
+	}
+	manifest := &Manifest{}
+	if err = json.NewDecoder(tarReader).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("%w: format version %d, expected %d", ErrUnsupportedFormat, manifest.FormatVersion, FormatVersion)
+	}
+	return manifest, nil
+}
+
+func tableIndex(name string) int {
+	for i, t := range tables {
+		if t.name() == name {
+			return i
+		}
+	}
+	return -1
+}
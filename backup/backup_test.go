@@ -0,0 +1,268 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupBackupTestDB(t *testing.T, compression config.PageCompressionAlgorithm) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(database.Models...))
+	pageCompression, err := database.NewPageCompression(config.PageCompressionConfig{Algorithm: compression})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(pageCompression))
+	return db
+}
+
+// seedBackupTestDB fills the database with a row of every table of the backup
+func seedBackupTestDB(t *testing.T, db *gorm.DB) {
+	published := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "shop", Name: "Shop"}).Error)
+	require.NoError(t, db.Create(&model.Project{NamespaceCode: "shop", ProjectCode: "web", Name: "Web", Version: 3, PublishedAt: published}).Error)
+	require.NoError(t, db.Create(&model.ProjectEnvironment{
+		NamespaceCode: "shop", ProjectCode: "web", Environment: commonTypes.EnvironmentProduction, Version: 2,
+		Redirects: []commonTypes.Redirect{{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/b"}},
+	}).Error)
+
+	tag := &model.Tag{NamespaceCode: "shop", ProjectCode: "web", Name: "summer"}
+	require.NoError(t, db.Create(tag).Error)
+	redirect := &model.Redirect{
+		ID: 42, NamespaceCode: "shop", ProjectCode: "web", IsPublished: types.Ptr(true), PublishedAt: published,
+		Redirect: &commonTypes.Redirect{
+			Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent,
+			Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeQuery, Name: "lang", Value: "fr"}},
+		},
+	}
+	require.NoError(t, db.Omit("Tags").Create(redirect).Error)
+	require.NoError(t, db.Create(&model.RedirectTag{RedirectID: redirect.ID, TagID: tag.ID}).Error)
+	changeset := &model.Changeset{ID: 7, NamespaceCode: "shop", ProjectCode: "web", Name: "summer sale"}
+	require.NoError(t, db.Create(changeset).Error)
+	deleteDraft := &model.RedirectDraft{NamespaceCode: "shop", ProjectCode: "web", ChangeType: model.DraftChangeTypeDelete, OldRedirectID: &redirect.ID, ChangesetID: &changeset.ID}
+	require.NoError(t, db.Create(deleteDraft).Error)
+	require.NoError(t, db.Create(&model.RedirectDraftTag{RedirectDraftID: deleteDraft.ID, TagID: tag.ID}).Error)
+
+	require.NoError(t, db.Create(&model.Page{
+		NamespaceCode: "shop", ProjectCode: "web", IsPublished: types.Ptr(true),
+		Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "User-agent: *", ContentType: commonTypes.PageContentTypeTextPlain},
+	}).Error)
+	require.NoError(t, db.Create(&model.PageDraft{
+		NamespaceCode: "shop", ProjectCode: "web", ChangeType: model.DraftChangeTypeCreate, ChangesetID: types.Ptr(int64(99)),
+		NewPage: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/ads.txt", Content: "ads", ContentType: commonTypes.PageContentTypeTextPlain},
+	}).Error)
+	require.NoError(t, db.Create(&model.ImportProfile{
+		NamespaceCode: "shop", ProjectCode: "web", Name: "agency", Columns: []string{"source", "target"}, Delimiter: model.ImportDelimiterSemicolon,
+	}).Error)
+	require.NoError(t, db.Create(&model.NamespacePolicy{
+		NamespaceCode: "shop", Rules: []model.NamespacePolicyRule{{Type: model.NamespacePolicyRuleSourcePattern, Pattern: "^/", Message: "Sources start with /"}},
+	}).Error)
+	require.NoError(t, db.Create(&model.PageTemplate{NamespaceCode: "shop", Code: "robots", Name: "Robots", ContentType: commonTypes.PageContentTypeTextPlain, Content: "{{host}}"}).Error)
+
+	require.NoError(t, db.Create(&model.Role{Code: "admin", Type: model.RoleTypeRole, Admin: []model.AdminPermission{{Section: model.AdminSectionAll, Action: model.ActionAll}}}).Error)
+	editor := &model.Role{Code: "editor", Type: model.RoleTypeRole, Resources: []model.ResourcePermission{{Namespace: "shop", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionAll}}}
+	require.NoError(t, db.Create(editor).Error)
+	reviewer := &model.Role{Code: "reviewer", Type: model.RoleTypeRole}
+	require.NoError(t, db.Create(reviewer).Error)
+	require.NoError(t, db.Create(&model.RoleParent{RoleID: reviewer.ID, ParentID: editor.ID}).Error)
+	require.NoError(t, db.Create(&model.Role{Code: "jdoe", Type: model.RoleTypeUser, Resources: []model.ResourcePermission{{Namespace: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead}}}).Error)
+}
+
+func TestWriteRestore(t *testing.T) {
+	ctx := context.Background()
+	source := setupBackupTestDB(t, config.PageCompressionGzip)
+	seedBackupTestDB(t, source)
+
+	var archive bytes.Buffer
+	counts, err := Write(ctx, source, &archive)
+	require.NoError(t, err)
+	assert.Equal(t, 1, counts["redirects"])
+	assert.Equal(t, 3, counts["roles"])
+	assert.Equal(t, 1, counts["resource_permissions"])
+
+	target := setupBackupTestDB(t, config.PageCompressionNone)
+	// An existing role is kept, the roles of the backup being created with a new id
+	existingAdmin := &model.Role{Code: "admin", Type: model.RoleTypeRole}
+	require.NoError(t, target.Create(&model.Role{Code: "other", Type: model.RoleTypeRole}).Error)
+	require.NoError(t, target.Create(existingAdmin).Error)
+
+	manifest, counts, err := Restore(ctx, target, &archive)
+	require.NoError(t, err)
+	assert.Equal(t, FormatVersion, manifest.FormatVersion)
+	assert.Equal(t, 1, counts["redirect_drafts"])
+	assert.Equal(t, 2, counts["roles"])
+	assert.Equal(t, 1, counts["role_parents"])
+	assert.Equal(t, 0, counts["admin_permissions"])
+
+	t.Run("namespace data keeps its ids", func(t *testing.T) {
+		var redirect model.Redirect
+		require.NoError(t, target.Preload("Tags").First(&redirect, 42).Error)
+		assert.Equal(t, "/old", redirect.Source)
+		assert.True(t, *redirect.IsPublished)
+		assert.Equal(t, "2026-01-02T03:04:05Z", redirect.PublishedAt.UTC().Format(time.RFC3339))
+		assert.Equal(t, []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeQuery, Name: "lang", Value: "fr"}}, redirect.Conditions)
+		require.Len(t, redirect.Tags, 1)
+		assert.Equal(t, "summer", redirect.Tags[0].Name)
+
+		var draft model.RedirectDraft
+		require.NoError(t, target.Preload("Tags").First(&draft).Error)
+		assert.Equal(t, model.DraftChangeTypeDelete, draft.ChangeType)
+		assert.Equal(t, int64(42), *draft.OldRedirectID)
+		assert.Len(t, draft.Tags, 1)
+
+		var policy model.NamespacePolicy
+		require.NoError(t, target.Where("namespace_code = ?", "shop").First(&policy).Error)
+		assert.Equal(t, []model.NamespacePolicyRule{{Type: model.NamespacePolicyRuleSourcePattern, Pattern: "^/", Message: "Sources start with /"}}, policy.Rules)
+
+		var profile model.ImportProfile
+		require.NoError(t, target.Where("name = ?", "agency").First(&profile).Error)
+		assert.Equal(t, []string{"source", "target"}, profile.Columns)
+		assert.Equal(t, model.ImportDelimiterSemicolon, profile.Delimiter)
+
+		var environment model.ProjectEnvironment
+		require.NoError(t, target.First(&environment).Error)
+		require.Len(t, environment.Redirects, 1)
+		assert.Equal(t, "/a", environment.Redirects[0].Source)
+	})
+
+	t.Run("drafts keep their changeset", func(t *testing.T) {
+		var draft model.RedirectDraft
+		require.NoError(t, target.First(&draft).Error)
+		require.NotNil(t, draft.ChangesetID)
+		var changeset model.Changeset
+		require.NoError(t, target.First(&changeset, *draft.ChangesetID).Error)
+		assert.Equal(t, "summer sale", changeset.Name)
+
+		// A draft of a changeset missing from the backup belongs to none
+		var pageDraft model.PageDraft
+		require.NoError(t, target.First(&pageDraft).Error)
+		assert.Nil(t, pageDraft.ChangesetID)
+
+		created := &model.Changeset{NamespaceCode: "shop", ProjectCode: "web", Name: "winter sale"}
+		require.NoError(t, target.Create(created).Error)
+		assert.NotEqual(t, changeset.ID, created.ID)
+	})
+
+	t.Run("page contents are restored decompressed", func(t *testing.T) {
+		var page model.Page
+		require.NoError(t, target.First(&page).Error)
+		assert.Equal(t, "User-agent: *", page.Content)
+		assert.Equal(t, model.PageContentEncodingIdentity, page.ContentEncoding)
+
+		var draft model.PageDraft
+		require.NoError(t, target.First(&draft).Error)
+		assert.Equal(t, "ads", draft.NewPage.Content)
+
+		var template model.PageTemplate
+		require.NoError(t, target.First(&template).Error)
+		assert.Equal(t, "{{host}}", template.Content)
+	})
+
+	t.Run("roles are merged", func(t *testing.T) {
+		var roles []model.Role
+		require.NoError(t, target.Preload("Resources").Preload("Admin").Order("code").Find(&roles).Error)
+		require.Len(t, roles, 4)
+		assert.Equal(t, "admin", roles[0].Code)
+		assert.Equal(t, existingAdmin.ID, roles[0].ID)
+		assert.Empty(t, roles[0].Admin)
+		assert.Equal(t, "editor", roles[1].Code)
+		require.Len(t, roles[1].Resources, 1)
+		assert.Equal(t, "shop", roles[1].Resources[0].Namespace)
+		assert.Equal(t, "other", roles[2].Code)
+		assert.Equal(t, "reviewer", roles[3].Code)
+
+		var parent model.RoleParent
+		require.NoError(t, target.First(&parent).Error)
+		assert.Equal(t, roles[3].ID, parent.RoleID)
+		assert.Equal(t, roles[1].ID, parent.ParentID)
+	})
+}
+
+func TestRestore_NotEmpty(t *testing.T) {
+	ctx := context.Background()
+	source := setupBackupTestDB(t, config.PageCompressionNone)
+	seedBackupTestDB(t, source)
+
+	var archive bytes.Buffer
+	_, err := Write(ctx, source, &archive)
+	require.NoError(t, err)
+
+	_, _, err = Restore(ctx, source, &archive)
+	assert.ErrorIs(t, err, ErrDatabaseNotEmpty)
+	assert.ErrorContains(t, err, "table namespaces has 1 rows")
+}
+
+func TestRestore_UnsupportedFormat(t *testing.T) {
+	ctx := context.Background()
+	db := setupBackupTestDB(t, config.PageCompressionNone)
+
+	archive := func(files map[string]string, order ...string) *bytes.Buffer {
+		var buffer bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buffer)
+		tarWriter := tar.NewWriter(gzipWriter)
+		for _, name := range order {
+			require.NoError(t, writeFile(tarWriter, name, int64(len(files[name])), bytes.NewReader([]byte(files[name]))))
+		}
+		require.NoError(t, tarWriter.Close())
+		require.NoError(t, gzipWriter.Close())
+		return &buffer
+	}
+
+	tests := []struct {
+		name    string
+		archive *bytes.Buffer
+		wantErr string
+	}{
+		{
+			name:    "not an archive",
+			archive: bytes.NewBufferString("not an archive"),
+			wantErr: "unsupported backup format",
+		},
+		{
+			name:    "missing manifest",
+			archive: archive(map[string]string{"namespaces.jsonl": ""}, "namespaces.jsonl"),
+			wantErr: "does not start with manifest.json",
+		},
+		{
+			name:    "newer format",
+			archive: archive(map[string]string{ManifestFile: `{"formatVersion": 2}`}, ManifestFile),
+			wantErr: "format version 2, expected 1",
+		},
+		{
+			name:    "unknown table",
+			archive: archive(map[string]string{ManifestFile: `{"formatVersion": 1}`, "users.jsonl": ""}, ManifestFile, "users.jsonl"),
+			wantErr: "unknown file 'users.jsonl'",
+		},
+		{
+			name:    "unknown column",
+			archive: archive(map[string]string{ManifestFile: `{"formatVersion": 1}`, "namespaces.jsonl": `{"namespace_code": "shop", "color": "red"}`}, ManifestFile, "namespaces.jsonl"),
+			wantErr: "table namespaces: row 1: unsupported backup format: unknown column 'color'",
+		},
+		{
+			name: "tables out of order",
+			archive: archive(map[string]string{ManifestFile: `{"formatVersion": 1}`, "projects.jsonl": "", "namespaces.jsonl": ""},
+				ManifestFile, "projects.jsonl", "namespaces.jsonl"),
+			wantErr: "table namespaces is out of order",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Restore(ctx, db, tt.archive)
+			assert.ErrorIs(t, err, ErrUnsupportedFormat)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,433 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+const batchSize = 500
+
+// derivedColumns are not backed up, they are computed again when the rows are restored.
+// Page contents are backed up decompressed and loaded from their store, and compressed and stored on restore
+// as configured.
+var derivedColumns = map[string]bool{
+	"content_encoding":    true,
+	"stored_content_size": true,
+	"content_store":       true,
+}
+
+// restoreState keeps what the restored rows tell about the rows of the following tables
+type restoreState struct {
+	// roleIDs maps the role ids of the backup to their id in the database
+	roleIDs map[int64]int64
+	// keptRoles are the role ids of the backup already in the database, kept as they are
+	keptRoles map[int64]bool
+	// tagNamespaces maps the tag ids to their namespace, for the tag links to reach its shard
+	tagNamespaces map[int64]string
+	// changesets are the ids of the changesets restored
+	changesets map[int64]bool
+}
+
+func newRestoreState() *restoreState {
+	return &restoreState{
+		roleIDs:       make(map[int64]int64),
+		keptRoles:     make(map[int64]bool),
+		tagNamespaces: make(map[int64]string),
+		changesets:    make(map[int64]bool),
+	}
+}
+
+// table is a table of the backup, stored as one JSON object per row keyed by column names
+type table interface {
+	name() string
+	// merged tells whether the table may already hold rows when restoring
+	merged() bool
+	count(ctx context.Context, db *gorm.DB) (int64, error)
+	dump(ctx context.Context, db *gorm.DB, w io.Writer) (int, error)
+	restore(ctx context.Context, db *gorm.DB, r io.Reader, state *restoreState) (int, error)
+}
+
+type modelTable[T any] struct {
+	table string
+	// scope restricts the rows backed up
+	scope func(db *gorm.DB) *gorm.DB
+	// prepare updates a row before it is restored, it returns false to skip the row
+	prepare func(ctx context.Context, db *gorm.DB, row *T, state *restoreState) (bool, error)
+	// create writes a row alone instead of by batch, for the rows whose new id must be known
+	create func(ctx context.Context, db *gorm.DB, row *T, state *restoreState) error
+	// namespace returns the namespace of a row of a table without namespace_code column
+	namespace func(row *T, state *restoreState) string
+	// mergeRows is set on the tables restored into a database that may already hold rows
+	mergeRows bool
+}
+
+func (t *modelTable[T]) name() string {
+	return t.table
+}
+
+func (t *modelTable[T]) merged() bool {
+	return t.mergeRows
+}
+
+// contexts returns the contexts reaching all the databases holding rows of the table
+func (t *modelTable[T]) contexts(ctx context.Context, db *gorm.DB) []context.Context {
+	if database.GlobalTables[t.table] {
+		return []context.Context{ctx}
+	}
+	return database.ShardContexts(db, ctx)
+}
+
+func (t *modelTable[T]) query(ctx context.Context, db *gorm.DB) *gorm.DB {
+	query := db.WithContext(ctx).Model(new(T))
+	if t.scope != nil {
+		query = t.scope(query)
+	}
+	return query
+}
+
+func (t *modelTable[T]) count(ctx context.Context, db *gorm.DB) (int64, error) {
+	var total int64
+	for _, dbCtx := range t.contexts(ctx, db) {
+		var count int64
+		if err := t.query(dbCtx, db).Count(&count).Error; err != nil {
+			return 0, err
+		}
+		total += count
+	}
+	return total, nil
+}
+
+func (t *modelTable[T]) dump(ctx context.Context, db *gorm.DB, w io.Writer) (int, error) {
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return 0, err
+	}
+	encoder := json.NewEncoder(w)
+	total := 0
+	for _, dbCtx := range t.contexts(ctx, db) {
+		for offset := 0; ; offset += batchSize {
+			query := t.query(dbCtx, db)
+			for _, primaryKey := range sch.PrimaryFieldDBNames {
+				query = query.Order(clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: primaryKey}})
+			}
+			var rows []T
+			if err = query.Limit(batchSize).Offset(offset).Find(&rows).Error; err != nil {
+				return total, err
+			}
+			for i := range rows {
+				if err = encoder.Encode(encodeRow(sch, reflect.ValueOf(&rows[i]).Elem())); err != nil {
+					return total, err
+				}
+			}
+			total += len(rows)
+			if len(rows) < batchSize {
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+func (t *modelTable[T]) restore(ctx context.Context, db *gorm.DB, r io.Reader, state *restoreState) (int, error) {
+	sch, err := parseSchema(db, new(T))
+	if err != nil {
+		return 0, err
+	}
+	namespaceField := sch.LookUpField("namespace_code")
+
+	total := 0
+	batch := make([]T, 0, batchSize)
+	batchNamespace := ""
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		batchCtx := ctx
+		if !database.GlobalTables[t.table] {
+			batchCtx = database.WithNamespace(ctx, batchNamespace)
+		}
+		if errCreate := db.WithContext(batchCtx).Omit(clause.Associations).Create(&batch).Error; errCreate != nil {
+			return errCreate
+		}
+		total += len(batch)
+		batch = make([]T, 0, batchSize)
+		return nil
+	}
+
+	decoder := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var columns map[string]json.RawMessage
+		if err = decoder.Decode(&columns); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return total, fmt.Errorf("row %d: %w", line, err)
+		}
+
+		var row T
+		rowValue := reflect.ValueOf(&row).Elem()
+		if err = decodeRow(ctx, sch, rowValue, columns); err != nil {
+			return total, fmt.Errorf("row %d: %w", line, err)
+		}
+		if t.prepare != nil {
+			keep, errPrepare := t.prepare(ctx, db, &row, state)
+			if errPrepare != nil {
+				return total, fmt.Errorf("row %d: %w", line, errPrepare)
+			}
+			if !keep {
+				continue
+			}
+		}
+
+		if t.create != nil {
+			if err = t.create(ctx, db, &row, state); err != nil {
+				return total, fmt.Errorf("row %d: %w", line, err)
+			}
+			total++
+			continue
+		}
+
+		namespace := ""
+		switch {
+		case t.namespace != nil:
+			namespace = t.namespace(&row, state)
+		case namespaceField != nil:
+			value, _ := namespaceField.ValueOf(ctx, rowValue)
+			namespace, _ = value.(string)
+		}
+		if len(batch) == batchSize || (len(batch) > 0 && namespace != batchNamespace) {
+			if err = flush(); err != nil {
+				return total, err
+			}
+		}
+		batch = append(batch, row)
+		batchNamespace = namespace
+	}
+	return total, flush()
+}
+
+func parseSchema(db *gorm.DB, value interface{}) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(value); err != nil {
+		return nil, err
+	}
+	return stmt.Schema, nil
+}
+
+// encodeRow returns the values of the columns of the row
+func encodeRow(sch *schema.Schema, row reflect.Value) map[string]interface{} {
+	columns := make(map[string]interface{}, len(sch.DBNames))
+	for _, dbName := range sch.DBNames {
+		if derivedColumns[dbName] {
+			continue
+		}
+		columns[dbName] = columnValue(sch.FieldsByDBName[dbName], row)
+	}
+	return columns
+}
+
+// columnValue returns the value of the field in the row, nil when it belongs to a nil embedded struct.
+// The gorm ValueOf is not used as it returns the serialized form of the fields having a serializer.
+func columnValue(field *schema.Field, row reflect.Value) interface{} {
+	value := row
+	for _, index := range field.StructField.Index {
+		if index >= 0 {
+			value = value.Field(index)
+			continue
+		}
+		// Negative indexes are the embedded struct pointers
+		value = value.Field(-index - 1)
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	return value.Interface()
+}
+
+// decodeRow sets the fields of the row from the values of its columns, the null values being left unset
+func decodeRow(ctx context.Context, sch *schema.Schema, row reflect.Value, columns map[string]json.RawMessage) error {
+	for dbName, raw := range columns {
+		field, ok := sch.FieldsByDBName[dbName]
+		if !ok || derivedColumns[dbName] {
+			return fmt.Errorf("%w: unknown column '%s'", ErrUnsupportedFormat, dbName)
+		}
+		if string(raw) == "null" {
+			continue
+		}
+		value := reflect.New(field.FieldType)
+		if err := json.Unmarshal(raw, value.Interface()); err != nil {
+			return fmt.Errorf("column '%s': %w", dbName, err)
+		}
+		if err := field.Set(ctx, row, value.Elem().Interface()); err != nil {
+			return fmt.Errorf("column '%s': %w", dbName, err)
+		}
+	}
+	return nil
+}
+
+// mapChangesetID clears the changeset of a draft when the changeset is not in the backup, so that the draft does not
+// join another changeset created later with the same id
+func mapChangesetID(changesetID **int64, state *restoreState) {
+	if *changesetID != nil && !state.changesets[**changesetID] {
+		*changesetID = nil
+	}
+}
+
+// roleScope restricts the backup to the roles, the user and token roles belonging to users and tokens
+func roleScope(db *gorm.DB) *gorm.DB {
+	return db.Where("type = ?", model.RoleTypeRole)
+}
+
+// roleChildScope restricts the backup to the rows whose role columns reference roles backed up
+func roleChildScope(columns ...string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		roleIDs := db.Session(&gorm.Session{NewDB: true}).Model(&model.Role{}).Select("id").Where("type = ?", model.RoleTypeRole)
+		for _, column := range columns {
+			db = db.Where(column+" IN (?)", roleIDs)
+		}
+		return db
+	}
+}
+
+// prepareRole skips the roles already in the database, their permissions and parents being kept
+func prepareRole(ctx context.Context, db *gorm.DB, role *model.Role, state *restoreState) (bool, error) {
+	var existing []model.Role
+	if err := db.WithContext(ctx).Where("code = ? AND type = ?", role.Code, role.Type).Limit(1).Find(&existing).Error; err != nil {
+		return false, err
+	}
+	if len(existing) > 0 {
+		state.roleIDs[role.ID] = existing[0].ID
+		state.keptRoles[role.ID] = true
+		return false, nil
+	}
+	return true, nil
+}
+
+// createRole creates the role with a new id, known by its permissions and children
+func createRole(ctx context.Context, db *gorm.DB, role *model.Role, state *restoreState) error {
+	backupID := role.ID
+	role.ID = 0
+	if err := db.WithContext(ctx).Omit(clause.Associations).Create(role).Error; err != nil {
+		return err
+	}
+	state.roleIDs[backupID] = role.ID
+	return nil
+}
+
+// mapRoleID replaces the role id of the backup by the id of the restored role, it returns false
+// when the role was already in the database, its permissions and parents being kept
+func mapRoleID(roleID *int64, state *restoreState) (bool, error) {
+	if state.keptRoles[*roleID] {
+		return false, nil
+	}
+	newID, ok := state.roleIDs[*roleID]
+	if !ok {
+		return false, fmt.Errorf("unknown role %d", *roleID)
+	}
+	*roleID = newID
+	return true, nil
+}
+
+// mapParentRoleID replaces the parent role id of the backup by the id of the role in the database
+func mapParentRoleID(roleID *int64, state *restoreState) (bool, error) {
+	newID, ok := state.roleIDs[*roleID]
+	if !ok {
+		return false, fmt.Errorf("unknown role %d", *roleID)
+	}
+	*roleID = newID
+	return true, nil
+}
+
+var tables = []table{
+	&modelTable[model.Namespace]{table: "namespaces"},
+	&modelTable[model.Project]{table: "projects"},
+	&modelTable[model.ProjectEnvironment]{table: "project_environments"},
+	&modelTable[model.Tag]{
+		table: "tags",
+		prepare: func(_ context.Context, _ *gorm.DB, tag *model.Tag, state *restoreState) (bool, error) {
+			state.tagNamespaces[tag.ID] = tag.NamespaceCode
+			return true, nil
+		},
+	},
+	&modelTable[model.Redirect]{table: "redirects"},
+	&modelTable[model.RedirectTag]{
+		table: "redirect_tags",
+		namespace: func(link *model.RedirectTag, state *restoreState) string {
+			return state.tagNamespaces[link.TagID]
+		},
+	},
+	&modelTable[model.Changeset]{
+		table: "changesets",
+		prepare: func(_ context.Context, _ *gorm.DB, changeset *model.Changeset, state *restoreState) (bool, error) {
+			state.changesets[changeset.ID] = true
+			return true, nil
+		},
+	},
+	&modelTable[model.RedirectDraft]{
+		table: "redirect_drafts",
+		prepare: func(_ context.Context, _ *gorm.DB, draft *model.RedirectDraft, state *restoreState) (bool, error) {
+			mapChangesetID(&draft.ChangesetID, state)
+			return true, nil
+		},
+	},
+	&modelTable[model.RedirectDraftTag]{
+		table: "redirect_draft_tags",
+		namespace: func(link *model.RedirectDraftTag, state *restoreState) string {
+			return state.tagNamespaces[link.TagID]
+		},
+	},
+	&modelTable[model.Page]{table: "pages"},
+	&modelTable[model.PageDraft]{
+		table: "page_drafts",
+		prepare: func(_ context.Context, _ *gorm.DB, draft *model.PageDraft, state *restoreState) (bool, error) {
+			mapChangesetID(&draft.ChangesetID, state)
+			return true, nil
+		},
+	},
+	&modelTable[model.PageTemplate]{table: "page_templates"},
+	&modelTable[model.NamespacePolicy]{table: "namespace_policies"},
+	&modelTable[model.ProjectGitSync]{table: "project_git_syncs"},
+	&modelTable[model.ImportProfile]{table: "import_profiles"},
+	&modelTable[model.NotificationSubscription]{table: "notification_subscriptions"},
+	&modelTable[model.Role]{table: "roles", scope: roleScope, prepare: prepareRole, create: createRole, mergeRows: true},
+	&modelTable[model.RoleParent]{
+		table:     "role_parents",
+		scope:     roleChildScope("role_id", "parent_id"),
+		mergeRows: true,
+		prepare: func(_ context.Context, _ *gorm.DB, parent *model.RoleParent, state *restoreState) (bool, error) {
+			keep, err := mapRoleID(&parent.RoleID, state)
+			if !keep || err != nil {
+				return false, err
+			}
+			return mapParentRoleID(&parent.ParentID, state)
+		},
+	},
+	&modelTable[model.ResourcePermission]{
+		table:     "resource_permissions",
+		scope:     roleChildScope("role_id"),
+		mergeRows: true,
+		prepare: func(_ context.Context, _ *gorm.DB, permission *model.ResourcePermission, state *restoreState) (bool, error) {
+			permission.ID = 0
+			return mapRoleID(&permission.RoleID, state)
+		},
+	},
+	&modelTable[model.AdminPermission]{
+		table:     "admin_permissions",
+		scope:     roleChildScope("role_id"),
+		mergeRows: true,
+		prepare: func(_ context.Context, _ *gorm.DB, permission *model.AdminPermission, state *restoreState) (bool, error) {
+			permission.ID = 0
+			return mapRoleID(&permission.RoleID, state)
+		},
+	},
+}
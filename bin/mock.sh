@@ -4,9 +4,9 @@ go install go.uber.org/mock/mockgen@latest
 
 rm -rf mocks
 
-mockgen -destination=mocks/flecto-manager/repository/mock.go -package=mockFlectoRepository github.com/flectolab/flecto-manager/repository NamespaceRepository,ProjectRepository,UserRepository,RoleRepository,ResourcePermissionRepository,AdminPermissionRepository,RedirectRepository,RedirectDraftRepository,PageRepository,PageDraftRepository,AgentRepository,TokenRepository
+mockgen -destination=mocks/flecto-manager/repository/mock.go -package=mockFlectoRepository github.com/flectolab/flecto-manager/repository NamespaceRepository,ProjectRepository,UserRepository,RoleRepository,ResourcePermissionRepository,AdminPermissionRepository,RedirectRepository,RedirectDraftRepository,PageRepository,PageDraftRepository,PageTemplateRepository,AgentRepository,TokenRepository,ImportJobRepository,RefreshTokenRepository,HitRepository
 
-mockgen -destination=mocks/flecto-manager/service/mock.go -package=mockFlectoService github.com/flectolab/flecto-manager/service RoleService,AuthService,TokenService,UserService,ProjectService,RedirectService,RedirectDraftService,PageService,PageDraftService,AgentService
+mockgen -destination=mocks/flecto-manager/service/mock.go -package=mockFlectoService github.com/flectolab/flecto-manager/service RoleService,AuthService,TokenService,UserService,ProjectService,RedirectService,RedirectDraftService,RedirectExportService,PageService,PageDraftService,AgentService,HitService,ProbeService,GitSyncService,ProjectAPIKeyService,NamespaceService,AgentInstanceService
 
 mockgen -destination=mocks/flecto-manager/cli/db/mock.go -package=mockMigratorDB github.com/flectolab/flecto-manager/cli/db Migrator
 
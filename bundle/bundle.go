@@ -0,0 +1,234 @@
+package bundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// Format is the archive format of a bundle
+type Format string
+
+const (
+	FormatTarGz Format = "tar.gz"
+	FormatZip   Format = "zip"
+)
+
+// RedirectFormat is the format of the redirects manifest of a bundle
+type RedirectFormat string
+
+const (
+	// RedirectFormatNginx writes nginx map blocks, included in the http context of the server
+	RedirectFormatNginx RedirectFormat = "nginx"
+	// RedirectFormatApache writes mod_rewrite rules, included in the virtual host of the server
+	RedirectFormatApache RedirectFormat = "apache"
+	// RedirectFormatCaddy writes a Caddyfile snippet, imported in the site block of the server
+	RedirectFormatCaddy RedirectFormat = "caddy"
+	// RedirectFormatJSON writes the redirects as served to the agents
+	RedirectFormatJSON RedirectFormat = "json"
+)
+
+const (
+	// PagesDir holds the BASIC pages, at their path
+	PagesDir = "pages"
+	// HostsDir holds the BASIC_HOST pages, in a directory per host
+	HostsDir = "hosts"
+	// PagesFile lists the pages of the bundle with the file holding them and their content type
+	PagesFile = "pages.json"
+	// indexFile names the file of the pages whose path ends with a slash
+	indexFile = "index"
+)
+
+var (
+	ErrUnsupportedFormat         = errors.New("unsupported bundle format")
+	ErrUnsupportedRedirectFormat = errors.New("unsupported redirect format")
+)
+
+// IsValid returns true for the archive formats a bundle can be written in
+func (f Format) IsValid() bool {
+	return f == FormatTarGz || f == FormatZip
+}
+
+// IsValid returns true for the formats the redirects manifest can be written in
+func (f RedirectFormat) IsValid() bool {
+	switch f {
+	case RedirectFormatNginx, RedirectFormatApache, RedirectFormatCaddy, RedirectFormatJSON:
+		return true
+	}
+	return false
+}
+
+// FileName returns the name of the redirects manifest file
+func (f RedirectFormat) FileName() string {
+	switch f {
+	case RedirectFormatNginx:
+		return "redirects.conf"
+	case RedirectFormatApache:
+		return "redirects.apache.conf"
+	case RedirectFormatCaddy:
+		return "redirects.caddy"
+	default:
+		return "redirects.json"
+	}
+}
+
+// Options select the formats of a bundle
+type Options struct {
+	Format         Format
+	RedirectFormat RedirectFormat
+}
+
+// Bundle is the published content of a project at a version
+type Bundle struct {
+	NamespaceCode   string
+	ProjectCode     string
+	Version         int
+	RedirectOptions commonTypes.RedirectOptions
+	// Redirects are in their evaluation order
+	Redirects []commonTypes.Redirect
+	Pages     []commonTypes.Page
+	// CreatedAt is the time of the export, the redirects outside of their validity period at this time
+	// are left out of the server configurations
+	CreatedAt time.Time
+}
+
+// PageEntry describes a page of the bundle in the pages file
+type PageEntry struct {
+	Type        commonTypes.PageType `json:"type"`
+	Path        string               `json:"path"`
+	File        string               `json:"file"`
+	ContentType string               `json:"contentType"`
+	// Headers are the headers to add to the response of the page
+	Headers commonTypes.ResponseHeaders `json:"headers,omitempty"`
+}
+
+// FileName returns the name of the archive of the bundle
+func (b *Bundle) FileName(format Format) string {
+	return fmt.Sprintf("%s-%s-v%d.%s", b.NamespaceCode, b.ProjectCode, b.Version, format)
+}
+
+// Write writes the bundle to w as an archive holding the body of each page, the pages file and the
+// redirects manifest
+func (b *Bundle) Write(w io.Writer, opts Options) error {
+	if !opts.Format.IsValid() {
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, opts.Format)
+	}
+	redirects, err := b.RedirectsManifest(opts.RedirectFormat)
+	if err != nil {
+		return err
+	}
+
+	archive := newArchiveWriter(w, opts.Format, b.CreatedAt)
+	entries := make([]PageEntry, 0, len(b.Pages))
+	for _, page := range b.Pages {
+		body, errBody := page.Body()
+		if errBody != nil {
+			return fmt.Errorf("page %s: %w", page.Path, errBody)
+		}
+		file := PageFile(page)
+		if err = archive.writeFile(file, body); err != nil {
+			return err
+		}
+		entries = append(entries, PageEntry{Type: page.Type, Path: page.Path, File: file, ContentType: page.HTTPContentType(), Headers: page.Headers})
+	}
+	pages, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = archive.writeFile(PagesFile, pages); err != nil {
+		return err
+	}
+	if err = archive.writeFile(opts.RedirectFormat.FileName(), redirects); err != nil {
+		return err
+	}
+	return archive.close()
+}
+
+// PageFile returns the name of the file holding the body of the page in the archive. The path is
+// cleaned so that no file is written outside of the directory of the page.
+func PageFile(page commonTypes.Page) string {
+	dir := PagesDir
+	pagePath := page.Path
+	if page.Type == commonTypes.PageTypeBasicHost {
+		host, hostPath, _ := strings.Cut(strings.TrimPrefix(pagePath, "//"), "/")
+		dir = path.Join(HostsDir, path.Clean("/" + host)[1:])
+		pagePath = "/" + hostPath
+	}
+	name := path.Clean("/" + pagePath)
+	if strings.HasSuffix(pagePath, "/") {
+		name = path.Join(name, indexFile)
+	}
+	return dir + name
+}
+
+// RedirectsManifest returns the redirects of the bundle in the configuration format of a server
+func (b *Bundle) RedirectsManifest(format RedirectFormat) ([]byte, error) {
+	switch format {
+	case RedirectFormatNginx:
+		return b.nginxManifest(), nil
+	case RedirectFormatApache:
+		return b.apacheManifest(), nil
+	case RedirectFormatCaddy:
+		return b.caddyManifest(), nil
+	case RedirectFormatJSON:
+		return b.jsonManifest()
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedRedirectFormat, format)
+	}
+}
+
+// archiveWriter writes the files of a bundle in a tar.gz or zip archive
+type archiveWriter struct {
+	modTime    time.Time
+	gzipWriter *gzip.Writer
+	tarWriter  *tar.Writer
+	zipWriter  *zip.Writer
+}
+
+func newArchiveWriter(w io.Writer, format Format, modTime time.Time) *archiveWriter {
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+	if format == FormatZip {
+		return &archiveWriter{modTime: modTime, zipWriter: zip.NewWriter(w)}
+	}
+	gzipWriter := gzip.NewWriter(w)
+	return &archiveWriter{modTime: modTime, gzipWriter: gzipWriter, tarWriter: tar.NewWriter(gzipWriter)}
+}
+
+func (a *archiveWriter) writeFile(name string, content []byte) error {
+	if a.zipWriter != nil {
+		fileWriter, err := a.zipWriter.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: a.modTime})
+		if err != nil {
+			return err
+		}
+		_, err = fileWriter.Write(content)
+		return err
+	}
+	header := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: a.modTime}
+	if err := a.tarWriter.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := io.Copy(a.tarWriter, bytes.NewReader(content))
+	return err
+}
+
+func (a *archiveWriter) close() error {
+	if a.zipWriter != nil {
+		return a.zipWriter.Close()
+	}
+	if err := a.tarWriter.Close(); err != nil {
+		return err
+	}
+	return a.gzipWriter.Close()
+}
@@ -0,0 +1,134 @@
+package bundle
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBundle() *Bundle {
+	return &Bundle{
+		NamespaceCode: "ns1",
+		ProjectCode:   "proj1",
+		Version:       3,
+		Redirects: []commonTypes.Redirect{
+			{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent},
+		},
+		Pages: []commonTypes.Page{
+			{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "User-agent: *", ContentType: commonTypes.PageContentTypeTextPlain},
+			{Type: commonTypes.PageTypeBasicHost, Path: "example.com/sitemap.xml", Content: "<urlset/>", ContentType: commonTypes.PageContentTypeXML},
+			{Type: commonTypes.PageTypeBasic, Path: "/favicon.ico", Content: base64.StdEncoding.EncodeToString([]byte{0, 1, 2}), ContentType: commonTypes.PageContentTypeBinary, MimeType: "image/x-icon",
+				Headers: commonTypes.ResponseHeaders{"Cache-Control": "max-age=86400"}},
+		},
+		CreatedAt: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestBundle_Write(t *testing.T) {
+	expectedFiles := map[string]string{
+		"pages/robots.txt":              "User-agent: *",
+		"hosts/example.com/sitemap.xml": "<urlset/>",
+		"pages/favicon.ico":             string([]byte{0, 1, 2}),
+	}
+
+	t.Run("tar.gz", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, testBundle().Write(&buf, Options{Format: FormatTarGz, RedirectFormat: RedirectFormatNginx}))
+
+		gzipReader, err := gzip.NewReader(&buf)
+		require.NoError(t, err)
+		tarReader := tar.NewReader(gzipReader)
+		files := map[string]string{}
+		for {
+			header, errNext := tarReader.Next()
+			if errNext == io.EOF {
+				break
+			}
+			require.NoError(t, errNext)
+			content, errRead := io.ReadAll(tarReader)
+			require.NoError(t, errRead)
+			files[header.Name] = string(content)
+		}
+
+		for name, content := range expectedFiles {
+			assert.Equal(t, content, files[name], name)
+		}
+		assert.Contains(t, files["redirects.conf"], `"~^[^/]*+/old$" "/new";`)
+		var entries []PageEntry
+		require.NoError(t, json.Unmarshal([]byte(files[PagesFile]), &entries))
+		assert.Equal(t, PageEntry{Type: commonTypes.PageTypeBasic, Path: "/favicon.ico", File: "pages/favicon.ico", ContentType: "image/x-icon",
+			Headers: commonTypes.ResponseHeaders{"Cache-Control": "max-age=86400"}}, entries[2])
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, testBundle().Write(&buf, Options{Format: FormatZip, RedirectFormat: RedirectFormatJSON}))
+
+		zipReader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		files := map[string]string{}
+		for _, file := range zipReader.File {
+			r, errOpen := file.Open()
+			require.NoError(t, errOpen)
+			content, errRead := io.ReadAll(r)
+			require.NoError(t, errRead)
+			files[file.Name] = string(content)
+		}
+
+		for name, content := range expectedFiles {
+			assert.Equal(t, content, files[name], name)
+		}
+		assert.Contains(t, files, PagesFile)
+		assert.Contains(t, files["redirects.json"], `"source": "/old"`)
+	})
+
+	t.Run("unsupported formats", func(t *testing.T) {
+		err := testBundle().Write(io.Discard, Options{Format: "rar", RedirectFormat: RedirectFormatJSON})
+		assert.ErrorIs(t, err, ErrUnsupportedFormat)
+
+		err = testBundle().Write(io.Discard, Options{Format: FormatZip, RedirectFormat: "htaccess"})
+		assert.ErrorIs(t, err, ErrUnsupportedRedirectFormat)
+	})
+
+	t.Run("invalid binary page", func(t *testing.T) {
+		b := testBundle()
+		b.Pages = append(b.Pages, commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/broken", Content: "!", ContentType: commonTypes.PageContentTypeBinary})
+
+		err := b.Write(io.Discard, Options{Format: FormatTarGz, RedirectFormat: RedirectFormatJSON})
+		assert.ErrorContains(t, err, "page /broken")
+	})
+}
+
+func TestPageFile(t *testing.T) {
+	tests := []struct {
+		page     commonTypes.Page
+		expected string
+	}{
+		{commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt"}, "pages/robots.txt"},
+		{commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/.well-known/security.txt"}, "pages/.well-known/security.txt"},
+		{commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/"}, "pages/index"},
+		{commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/docs/"}, "pages/docs/index"},
+		{commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/../../etc/passwd"}, "pages/etc/passwd"},
+		{commonTypes.Page{Type: commonTypes.PageTypeBasicHost, Path: "example.com/robots.txt"}, "hosts/example.com/robots.txt"},
+		{commonTypes.Page{Type: commonTypes.PageTypeBasicHost, Path: "//example.com/"}, "hosts/example.com/index"},
+		{commonTypes.Page{Type: commonTypes.PageTypeBasicHost, Path: "../x/robots.txt"}, "hosts/x/robots.txt"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, PageFile(tt.page), tt.page.Path)
+	}
+}
+
+func TestBundle_FileName(t *testing.T) {
+	assert.Equal(t, "ns1-proj1-v3.tar.gz", testBundle().FileName(FormatTarGz))
+	assert.Equal(t, "ns1-proj1-v3.zip", testBundle().FileName(FormatZip))
+}
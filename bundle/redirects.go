@@ -0,0 +1,250 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// redirectsManifest is the content of the JSON redirects manifest
+type redirectsManifest struct {
+	NamespaceCode string                      `json:"namespaceCode"`
+	ProjectCode   string                      `json:"projectCode"`
+	Version       int                         `json:"version"`
+	Options       commonTypes.RedirectOptions `json:"options"`
+	Redirects     []commonTypes.Redirect      `json:"redirects"`
+}
+
+func (b *Bundle) jsonManifest() ([]byte, error) {
+	redirects := b.Redirects
+	if redirects == nil {
+		redirects = []commonTypes.Redirect{}
+	}
+	return json.MarshalIndent(redirectsManifest{
+		NamespaceCode: b.NamespaceCode,
+		ProjectCode:   b.ProjectCode,
+		Version:       b.Version,
+		Options:       b.RedirectOptions,
+		Redirects:     redirects,
+	}, "", "  ")
+}
+
+// staticRedirects returns the redirects in the order the agents evaluate them, the basic redirects
+// by host first then the regex ones and the catch-all last. The redirects not expressible in a
+// server configuration, those with conditions, weighted targets or outside of their validity period, are
+// returned apart with the reason they are left out.
+func (b *Bundle) staticRedirects() ([]commonTypes.Redirect, *commonTypes.Redirect, []string) {
+	groups := map[commonTypes.RedirectType][]commonTypes.Redirect{}
+	var catchAll *commonTypes.Redirect
+	var skipped []string
+	for _, redirect := range b.Redirects {
+		switch {
+		case len(redirect.Conditions) > 0:
+			skipped = append(skipped, fmt.Sprintf("%s %s: has conditions", redirect.Type, redirect.Source))
+		case len(redirect.Targets) > 0:
+			skipped = append(skipped, fmt.Sprintf("%s %s: has weighted targets", redirect.Type, redirect.Source))
+		case !redirect.IsActiveAt(b.CreatedAt):
+			skipped = append(skipped, fmt.Sprintf("%s %s: outside of its validity period", redirect.Type, redirect.Source))
+		case redirect.Type == commonTypes.RedirectTypeCatchAll:
+			catchAll = &redirect
+		default:
+			groups[redirect.Type] = append(groups[redirect.Type], redirect)
+		}
+	}
+	var redirects []commonTypes.Redirect
+	for _, redirectType := range []commonTypes.RedirectType{
+		commonTypes.RedirectTypeBasicHost, commonTypes.RedirectTypeBasic, commonTypes.RedirectTypeRegexHost, commonTypes.RedirectTypeRegex,
+	} {
+		redirects = append(redirects, groups[redirectType]...)
+	}
+	return redirects, catchAll, skipped
+}
+
+// basicSourceRegex returns the regex matching the requests of a BASIC or BASIC_HOST source with the
+// matching options of the project
+func basicSourceRegex(source string, options commonTypes.RedirectOptions) string {
+	path, query, found := strings.Cut(source, "?")
+	pattern := regexp.QuoteMeta(path)
+	if options.IgnoreTrailingSlash && len(path) > 1 {
+		pattern = regexp.QuoteMeta(strings.TrimSuffix(path, "/")) + "/?"
+	}
+	if found {
+		pattern += `\?` + regexp.QuoteMeta(query)
+	}
+	if options.CaseInsensitive {
+		pattern = "(?i)" + pattern
+	}
+	return pattern + "$"
+}
+
+func (b *Bundle) manifestHeader(buf *bytes.Buffer) {
+	_, _ = fmt.Fprintf(buf, "# Redirects of the project %s/%s at version %d, exported by flecto-manager.\n", b.NamespaceCode, b.ProjectCode, b.Version)
+}
+
+func writeSkipped(buf *bytes.Buffer, skipped []string) {
+	for _, reason := range skipped {
+		_, _ = fmt.Fprintf(buf, "# Skipped %s\n", reason)
+	}
+}
+
+// nginxManifest writes two maps of the host and URI of the request, to the target and the status of
+// the redirect matching it. The keys are regexes checked in the evaluation order of the agents, the
+// catch-all being the default value.
+func (b *Bundle) nginxManifest() []byte {
+	redirects, catchAll, skipped := b.staticRedirects()
+
+	var buf bytes.Buffer
+	b.manifestHeader(&buf)
+	buf.WriteString("# Include it in the http context and return the redirects in the server block:\n#\n")
+	for _, status := range []commonTypes.RedirectStatus{
+		commonTypes.RedirectStatusMovedPermanent, commonTypes.RedirectStatusFound, commonTypes.RedirectStatusTemporary, commonTypes.RedirectStatusPermanent,
+	} {
+		code := commonTypes.Redirect{Status: status}.HTTPCode()
+		_, _ = fmt.Fprintf(&buf, "#   if ($flecto_redirect_status = %d) { return %d $flecto_redirect_target; }\n", code, code)
+	}
+	writeSkipped(&buf, skipped)
+
+	for _, variable := range []string{"target", "status"} {
+		_, _ = fmt.Fprintf(&buf, "\nmap $host$request_uri $flecto_redirect_%s {\n", variable)
+		value := func(redirect commonTypes.Redirect) string {
+			if variable == "status" {
+				return strconv.Itoa(redirect.HTTPCode())
+			}
+			return redirect.Target
+		}
+		if catchAll != nil {
+			_, _ = fmt.Fprintf(&buf, "    default %s;\n", nginxQuote(value(*catchAll)))
+		} else {
+			buf.WriteString("    default \"\";\n")
+		}
+		for _, redirect := range redirects {
+			_, _ = fmt.Fprintf(&buf, "    %s %s;\n", nginxQuote("~"+hostURIRegex(redirect, b.RedirectOptions)), nginxQuote(value(redirect)))
+		}
+		buf.WriteString("}\n")
+	}
+	return buf.Bytes()
+}
+
+// hostURIRegex returns the PCRE regex matching the host followed by the URI of the requests of a
+// redirect. The host of the sources without one is skipped by a possessive match up to the first
+// slash, which keeps the numbering of the groups of regex sources.
+func hostURIRegex(redirect commonTypes.Redirect, options commonTypes.RedirectOptions) string {
+	switch redirect.Type {
+	case commonTypes.RedirectTypeBasicHost:
+		return "^" + basicSourceRegex(redirect.Source, options)
+	case commonTypes.RedirectTypeBasic:
+		return "^[^/]*+" + basicSourceRegex(redirect.Source, options)
+	case commonTypes.RedirectTypeRegex:
+		if source, anchored := strings.CutPrefix(redirect.Source, "^"); anchored {
+			return "^[^/]*+" + source
+		}
+		return "^[^/]*+.*?(?:" + redirect.Source + ")"
+	default:
+		return redirect.Source
+	}
+}
+
+// nginxQuote quotes a string of the configuration, the parser unescaping the backslashes and quotes
+func nginxQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// apacheManifest writes mod_rewrite rules matching the host followed by the URI of the requests, set
+// in an environment variable by the first rules. The rules are evaluated in order, the catch-all last.
+func (b *Bundle) apacheManifest() []byte {
+	redirects, catchAll, skipped := b.staticRedirects()
+
+	var buf bytes.Buffer
+	b.manifestHeader(&buf)
+	buf.WriteString("# Include it in the virtual host of the server, mod_rewrite being enabled.\n")
+	writeSkipped(&buf, skipped)
+
+	buf.WriteString("\nRewriteEngine On\n")
+	buf.WriteString("RewriteCond %{QUERY_STRING} ^$\n")
+	buf.WriteString("RewriteRule ^ - [E=FLECTO_URI:%{HTTP_HOST}%{REQUEST_URI}]\n")
+	buf.WriteString("RewriteCond %{QUERY_STRING} .\n")
+	buf.WriteString("RewriteRule ^ - [E=FLECTO_URI:%{HTTP_HOST}%{REQUEST_URI}?%{QUERY_STRING}]\n")
+	for _, redirect := range redirects {
+		_, _ = fmt.Fprintf(&buf, "\nRewriteCond %%{ENV:FLECTO_URI} %s\n", apacheQuote(hostURIRegex(redirect, b.RedirectOptions)))
+		_, _ = fmt.Fprintf(&buf, "RewriteRule ^ %s [R=%d,L,QSD,NE]\n", apacheQuote(apacheTarget(redirect.Target)), redirect.HTTPCode())
+	}
+	if catchAll != nil {
+		_, _ = fmt.Fprintf(&buf, "\nRewriteRule ^ %s [R=%d,L,QSD,NE]\n", apacheQuote(apacheTarget(catchAll.Target)), catchAll.HTTPCode())
+	}
+	return buf.Bytes()
+}
+
+// apacheTarget escapes the $ and % of a target, then replaces its $1 to $9 groups by the back-references
+// of the condition matching the request
+func apacheTarget(target string) string {
+	target = strings.NewReplacer(`$`, `\$`, `%`, `\%`).Replace(target)
+	for i := 1; i <= 9; i++ {
+		target = strings.ReplaceAll(target, `\$`+strconv.Itoa(i), "%"+strconv.Itoa(i))
+	}
+	return target
+}
+
+// apacheQuote quotes an argument of a directive, the parser unescaping the quotes
+func apacheQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// caddyManifest writes a route block redirecting the requests, its directives being evaluated in
+// order. The REGEX_HOST redirects are skipped, Caddy matching the host and the URI apart.
+func (b *Bundle) caddyManifest() []byte {
+	redirects, catchAll, skipped := b.staticRedirects()
+
+	var buf bytes.Buffer
+	b.manifestHeader(&buf)
+	buf.WriteString("# Import it in the site block of the server.\n")
+	var routes bytes.Buffer
+	for i, redirect := range redirects {
+		matcher := fmt.Sprintf("flecto_%d", i+1)
+		target := redirect.Target
+		switch redirect.Type {
+		case commonTypes.RedirectTypeBasicHost:
+			host, path, _ := strings.Cut(redirect.Source, "/")
+			_, _ = fmt.Fprintf(&routes, "\t@%s {\n\t\thost %s\n\t\tvars_regexp {http.request.uri} %s\n\t}\n",
+				matcher, host, caddyQuote("^"+basicSourceRegex("/"+path, b.RedirectOptions)))
+		case commonTypes.RedirectTypeBasic:
+			_, _ = fmt.Fprintf(&routes, "\t@%s vars_regexp {http.request.uri} %s\n", matcher, caddyQuote("^"+basicSourceRegex(redirect.Source, b.RedirectOptions)))
+		case commonTypes.RedirectTypeRegex:
+			_, _ = fmt.Fprintf(&routes, "\t@%s vars_regexp %s {http.request.uri} %s\n", matcher, matcher, caddyQuote(redirect.Source))
+			target = caddyTarget(target, matcher)
+		default:
+			skipped = append(skipped, fmt.Sprintf("%s %s: not supported by Caddy", redirect.Type, redirect.Source))
+			continue
+		}
+		_, _ = fmt.Fprintf(&routes, "\tredir @%s %s %d\n", matcher, caddyQuote(target), redirect.HTTPCode())
+	}
+	if catchAll != nil {
+		_, _ = fmt.Fprintf(&routes, "\tredir %s %d\n", caddyQuote(catchAll.Target), catchAll.HTTPCode())
+	}
+	writeSkipped(&buf, skipped)
+
+	buf.WriteString("\nroute {\n")
+	buf.Write(routes.Bytes())
+	buf.WriteString("}\n")
+	return buf.Bytes()
+}
+
+// caddyTarget replaces the $1 to $9 groups of a regex target by the placeholders of the matcher
+func caddyTarget(target, matcher string) string {
+	for i := 9; i >= 1; i-- {
+		target = strings.ReplaceAll(target, "$"+strconv.Itoa(i), fmt.Sprintf("{re.%s.%d}", matcher, i))
+	}
+	return target
+}
+
+// caddyQuote quotes a token of the Caddyfile, backticks keeping the backslashes of the regexes
+func caddyQuote(s string) string {
+	if !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
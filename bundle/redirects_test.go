@@ -0,0 +1,177 @@
+package bundle
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRedirectsBundle() *Bundle {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Hour)
+	return &Bundle{
+		NamespaceCode: "ns1",
+		ProjectCode:   "proj1",
+		Version:       3,
+		Redirects: []commonTypes.Redirect{
+			{Type: commonTypes.RedirectTypeRegex, Source: `^/blog/(\d+)$`, Target: "/posts/$1", Status: commonTypes.RedirectStatusMovedPermanent},
+			{Type: commonTypes.RedirectTypeBasic, Source: "/old?lang=fr", Target: "/new", Status: commonTypes.RedirectStatusFound},
+			{Type: commonTypes.RedirectTypeBasicHost, Source: "example.com/docs/", Target: `https://docs.example.com/"v2"`, Status: commonTypes.RedirectStatusPermanent},
+			{Type: commonTypes.RedirectTypeRegexHost, Source: `^shop\.example\.com/(.*)`, Target: "https://example.com/shop/$1", Status: commonTypes.RedirectStatusTemporary},
+			{Type: commonTypes.RedirectTypeBasic, Source: "/conditional", Target: "/new", Status: commonTypes.RedirectStatusFound,
+				Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeHeader, Name: "Accept-Language", Value: "fr"}}},
+			{Type: commonTypes.RedirectTypeBasic, Source: "/expired", Target: "/new", Status: commonTypes.RedirectStatusFound, ValidUntil: &expired},
+			{Type: commonTypes.RedirectTypeBasic, Source: "/split", Target: "/new", Status: commonTypes.RedirectStatusFound,
+				Targets: []commonTypes.RedirectTarget{{Target: "/new", Weight: 90}, {Target: "/beta", Weight: 10}}},
+			{Type: commonTypes.RedirectTypeCatchAll, Target: "https://example.com/", Status: commonTypes.RedirectStatusFound},
+		},
+		CreatedAt: now,
+	}
+}
+
+func TestBundle_NginxManifest(t *testing.T) {
+	b := testRedirectsBundle()
+	b.RedirectOptions = commonTypes.RedirectOptions{IgnoreTrailingSlash: true}
+
+	expected := `# Redirects of the project ns1/proj1 at version 3, exported by flecto-manager.
+# Include it in the http context and return the redirects in the server block:
+#
+#   if ($flecto_redirect_status = 301) { return 301 $flecto_redirect_target; }
+#   if ($flecto_redirect_status = 302) { return 302 $flecto_redirect_target; }
+#   if ($flecto_redirect_status = 307) { return 307 $flecto_redirect_target; }
+#   if ($flecto_redirect_status = 308) { return 308 $flecto_redirect_target; }
+# Skipped BASIC /conditional: has conditions
+# Skipped BASIC /expired: outside of its validity period
+# Skipped BASIC /split: has weighted targets
+
+map $host$request_uri $flecto_redirect_target {
+    default "https://example.com/";
+    "~^example\\.com/docs/?$" "https://docs.example.com/\"v2\"";
+    "~^[^/]*+/old/?\\?lang=fr$" "/new";
+    "~^shop\\.example\\.com/(.*)" "https://example.com/shop/$1";
+    "~^[^/]*+/blog/(\\d+)$" "/posts/$1";
+}
+
+map $host$request_uri $flecto_redirect_status {
+    default "302";
+    "~^example\\.com/docs/?$" "308";
+    "~^[^/]*+/old/?\\?lang=fr$" "302";
+    "~^shop\\.example\\.com/(.*)" "307";
+    "~^[^/]*+/blog/(\\d+)$" "301";
+}
+`
+	assert.Equal(t, expected, string(b.nginxManifest()))
+}
+
+func TestHostURIRegex(t *testing.T) {
+	tests := []struct {
+		redirect commonTypes.Redirect
+		options  commonTypes.RedirectOptions
+		expected string
+	}{
+		{commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a.html"}, commonTypes.RedirectOptions{}, `^[^/]*+/a\.html$`},
+		{commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a/"}, commonTypes.RedirectOptions{CaseInsensitive: true, IgnoreTrailingSlash: true}, `^[^/]*+(?i)/a/?$`},
+		{commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/"}, commonTypes.RedirectOptions{IgnoreTrailingSlash: true}, `^[^/]*+/$`},
+		{commonTypes.Redirect{Type: commonTypes.RedirectTypeBasicHost, Source: "example.com/a"}, commonTypes.RedirectOptions{}, `^example\.com/a$`},
+		{commonTypes.Redirect{Type: commonTypes.RedirectTypeRegex, Source: "/a/(.*)"}, commonTypes.RedirectOptions{}, `^[^/]*+.*?(?:/a/(.*))`},
+		{commonTypes.Redirect{Type: commonTypes.RedirectTypeRegexHost, Source: `example\.com/(.*)`}, commonTypes.RedirectOptions{}, `example\.com/(.*)`},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, hostURIRegex(tt.redirect, tt.options), tt.redirect.Source)
+	}
+}
+
+func TestBundle_ApacheManifest(t *testing.T) {
+	b := testRedirectsBundle()
+
+	expected := `# Redirects of the project ns1/proj1 at version 3, exported by flecto-manager.
+# Include it in the virtual host of the server, mod_rewrite being enabled.
+# Skipped BASIC /conditional: has conditions
+# Skipped BASIC /expired: outside of its validity period
+# Skipped BASIC /split: has weighted targets
+
+RewriteEngine On
+RewriteCond %{QUERY_STRING} ^$
+RewriteRule ^ - [E=FLECTO_URI:%{HTTP_HOST}%{REQUEST_URI}]
+RewriteCond %{QUERY_STRING} .
+RewriteRule ^ - [E=FLECTO_URI:%{HTTP_HOST}%{REQUEST_URI}?%{QUERY_STRING}]
+
+RewriteCond %{ENV:FLECTO_URI} "^example\.com/docs/$"
+RewriteRule ^ "https://docs.example.com/\"v2\"" [R=308,L,QSD,NE]
+
+RewriteCond %{ENV:FLECTO_URI} "^[^/]*+/old\?lang=fr$"
+RewriteRule ^ "/new" [R=302,L,QSD,NE]
+
+RewriteCond %{ENV:FLECTO_URI} "^shop\.example\.com/(.*)"
+RewriteRule ^ "https://example.com/shop/%1" [R=307,L,QSD,NE]
+
+RewriteCond %{ENV:FLECTO_URI} "^[^/]*+/blog/(\d+)$"
+RewriteRule ^ "/posts/%1" [R=301,L,QSD,NE]
+
+RewriteRule ^ "https://example.com/" [R=302,L,QSD,NE]
+`
+	assert.Equal(t, expected, string(b.apacheManifest()))
+}
+
+func TestApacheTarget(t *testing.T) {
+	assert.Equal(t, "/a/%1/%2", apacheTarget("/a/$1/$2"))
+	assert.Equal(t, `/a\%20b/\$x`, apacheTarget("/a%20b/$x"))
+}
+
+func TestBundle_CaddyManifest(t *testing.T) {
+	b := testRedirectsBundle()
+	b.RedirectOptions = commonTypes.RedirectOptions{CaseInsensitive: true}
+
+	expected := "# Redirects of the project ns1/proj1 at version 3, exported by flecto-manager.\n" +
+		"# Import it in the site block of the server.\n" +
+		"# Skipped BASIC /conditional: has conditions\n" +
+		"# Skipped BASIC /expired: outside of its validity period\n" +
+		"# Skipped BASIC /split: has weighted targets\n" +
+		"# Skipped REGEX_HOST ^shop\\.example\\.com/(.*): not supported by Caddy\n" +
+		"\n" +
+		"route {\n" +
+		"\t@flecto_1 {\n" +
+		"\t\thost example.com\n" +
+		"\t\tvars_regexp {http.request.uri} `^(?i)/docs/$`\n" +
+		"\t}\n" +
+		"\tredir @flecto_1 `https://docs.example.com/\"v2\"` 308\n" +
+		"\t@flecto_2 vars_regexp {http.request.uri} `^(?i)/old\\?lang=fr$`\n" +
+		"\tredir @flecto_2 `/new` 302\n" +
+		"\t@flecto_4 vars_regexp flecto_4 {http.request.uri} `^/blog/(\\d+)$`\n" +
+		"\tredir @flecto_4 `/posts/{re.flecto_4.1}` 301\n" +
+		"\tredir `https://example.com/` 302\n" +
+		"}\n"
+	assert.Equal(t, expected, string(b.caddyManifest()))
+}
+
+func TestCaddyQuote(t *testing.T) {
+	assert.Equal(t, "`/a b`", caddyQuote("/a b"))
+	assert.Equal(t, "\"/a`b\\\"c\"", caddyQuote("/a`b\"c"))
+}
+
+func TestBundle_JSONManifest(t *testing.T) {
+	b := testRedirectsBundle()
+	b.RedirectOptions = commonTypes.RedirectOptions{PreserveQueryString: true}
+
+	content, err := b.jsonManifest()
+	require.NoError(t, err)
+
+	var manifest redirectsManifest
+	require.NoError(t, json.Unmarshal(content, &manifest))
+	assert.Equal(t, "ns1", manifest.NamespaceCode)
+	assert.Equal(t, 3, manifest.Version)
+	assert.True(t, manifest.Options.PreserveQueryString)
+	// The JSON manifest keeps all the redirects, with their conditions, validity period and weighted targets
+	assert.Len(t, manifest.Redirects, 8)
+	assert.Len(t, manifest.Redirects[4].Conditions, 1)
+	assert.Len(t, manifest.Redirects[6].Targets, 2)
+
+	b.Redirects = nil
+	content, err = b.jsonManifest()
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"redirects": []`)
+}
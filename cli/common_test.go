@@ -34,6 +34,10 @@ func Test_validateConfig(t *testing.T) {
 						HeaderName:      "Authorization",
 					},
 					OpenID: config.OpenIDConfig{Enabled: false},
+					Password: config.PasswordConfig{
+						MinLength:  8,
+						MinClasses: 1,
+					},
 				},
 				Page: config.PageConfig{
 					SizeLimit:      1024,
@@ -41,6 +45,41 @@ func Test_validateConfig(t *testing.T) {
 				},
 				Agent: config.AgentConfig{
 					OfflineThreshold: 1 * time.Hour,
+					StaleThreshold:   5 * time.Minute,
+					RolloutTimeout:   10 * time.Minute,
+				},
+				Import: config.ImportConfig{
+					MaxFileSize: 1024,
+					BatchSize:   10,
+					Workers:     1,
+					QueueSize:   10,
+				},
+				Expiry: config.ExpiryConfig{
+					Interval: time.Minute,
+				},
+				Health: config.HealthConfig{
+					Interval:    time.Hour,
+					Timeout:     time.Second,
+					Concurrency: 1,
+				},
+				DraftLock: config.DraftLockConfig{
+					TTL:    time.Minute,
+					MaxTTL: time.Hour,
+				},
+				Notification: config.NotificationConfig{
+					Timeout: time.Second,
+				},
+				Outbox: config.OutboxConfig{
+					PollInterval:  time.Second,
+					BatchSize:     1,
+					MaxAttempts:   1,
+					RetryDelay:    time.Second,
+					MaxRetryDelay: time.Second,
+					Lease:         time.Second,
+				},
+				Retention: config.RetentionConfig{
+					Interval:  time.Hour,
+					BatchSize: 1,
 				},
 			},
 			wantErr: assert.NoError,
@@ -61,3 +100,37 @@ func Test_validateConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateConfig_Scheduler(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   map[string]config.ScheduledTaskConfig
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "success",
+			tasks:   map[string]config.ScheduledTaskConfig{"retention": {Schedule: "0 3 * * *"}, "git_sync": {Disabled: true}},
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "failedWithInvalidSchedule",
+			tasks:   map[string]config.ScheduledTaskConfig{"retention": {Schedule: "every night"}},
+			wantErr: assert.Error,
+		},
+		{
+			name:    "failedWithUnknownTask",
+			tasks:   map[string]config.ScheduledTaskConfig{"backup": {Schedule: "@daily"}},
+			wantErr: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.TestContext(nil)
+			ctx.Config = config.DefaultConfig()
+			ctx.Config.Auth.JWT.Secret = "test-secret-key-for-jwt-min-32-chars!"
+			ctx.Config.DB = config.DbConfig{Type: "sqlite", Config: map[string]interface{}{"dsn": ":memory:"}}
+			ctx.Config.Scheduler.Tasks = tt.tasks
+			tt.wantErr(t, validateConfig(ctx))
+		})
+	}
+}
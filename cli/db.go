@@ -14,6 +14,9 @@ func GetDBCmd(ctx *context.Context) *cobra.Command {
 	cmd.AddCommand(db.GetInitCmd(ctx))
 	cmd.AddCommand(db.GetDemoCmd(ctx))
 	cmd.AddCommand(db.GetMigrateCmd(ctx))
+	cmd.AddCommand(db.GetSeedCmd(ctx))
+	cmd.AddCommand(db.GetBackupCmd(ctx))
+	cmd.AddCommand(db.GetRestoreCmd(ctx))
 
 	return cmd
 }
@@ -0,0 +1,105 @@
+package db
+
+import (
+	stdContext "context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/flectolab/flecto-manager/backup"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/spf13/cobra"
+)
+
+const (
+	backupOutFlag  = "out"
+	restoreInFlag  = "in"
+	backupFileMode = 0o600
+)
+
+func GetBackupCmd(ctx *appContext.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "back up the namespaces and roles to a file",
+		Long: "Write the namespaces with their projects, redirects, pages, drafts and templates, and the roles with " +
+			"their permissions to a tar.gz archive of JSON files, independent of the database type. " +
+			"Users and tokens are not backed up.",
+		RunE: GetBackupRunFn(ctx),
+	}
+	cmd.Flags().StringP(backupOutFlag, "o", "", "Path of the backup file to write")
+	_ = cmd.MarkFlagRequired(backupOutFlag)
+	return cmd
+}
+
+func GetBackupRunFn(ctx *appContext.Context) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		out, _ := cmd.Flags().GetString(backupOutFlag)
+		db, errDb := NewInitDB(ctx)
+		if errDb != nil {
+			return errDb
+		}
+
+		file, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, backupFileMode)
+		if err != nil {
+			return err
+		}
+		counts, err := backup.Write(stdContext.Background(), db, file)
+		if errClose := file.Close(); err == nil {
+			err = errClose
+		}
+		if err != nil {
+			_ = os.Remove(out)
+			return fmt.Errorf("failed to write backup: %w", err)
+		}
+		logCounts(ctx, "backup: table written", counts)
+		fmt.Printf("Backup written to %s\n", out)
+		return nil
+	}
+}
+
+func GetRestoreCmd(ctx *appContext.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "restore a backup into an empty database",
+		Long: "Load a file written by the backup command into a migrated database holding no namespace. " +
+			"The roles already in the database are kept as they are.",
+		RunE: GetRestoreRunFn(ctx),
+	}
+	cmd.Flags().StringP(restoreInFlag, "i", "", "Path of the backup file to restore")
+	_ = cmd.MarkFlagRequired(restoreInFlag)
+	return cmd
+}
+
+func GetRestoreRunFn(ctx *appContext.Context) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		in, _ := cmd.Flags().GetString(restoreInFlag)
+		file, err := os.Open(in)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+
+		db, errDb := NewInitDB(ctx)
+		if errDb != nil {
+			return errDb
+		}
+		manifest, counts, err := backup.Restore(stdContext.Background(), db, file)
+		if err != nil {
+			return fmt.Errorf("failed to restore backup: %w", err)
+		}
+		logCounts(ctx, "restore: table restored", counts)
+		fmt.Printf("Backup of %s made by version %s restored\n", manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"), manifest.Version)
+		return nil
+	}
+}
+
+func logCounts(ctx *appContext.Context, msg string, counts backup.Counts) {
+	tables := make([]string, 0, len(counts))
+	for table := range counts {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		ctx.Logger.Info(msg, "table", table, "rows", counts[table])
+	}
+}
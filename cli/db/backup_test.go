@@ -0,0 +1,90 @@
+package db
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestGetBackupCmd(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+
+	assert.Equal(t, "backup", GetBackupCmd(ctx).Use)
+	assert.Equal(t, "restore", GetRestoreCmd(ctx).Use)
+}
+
+func TestGetBackupRestoreRunFn(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+	source := setupInitTestDB(t)
+	target := setupInitTestDB(t)
+	require.NoError(t, Seed(ctx, source))
+
+	oldNewInitDB := NewInitDB
+	defer func() { NewInitDB = oldNewInitDB }()
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	NewInitDB = func(c *appContext.Context) (*gorm.DB, error) {
+		return source, nil
+	}
+	backupCmd := GetBackupCmd(ctx)
+	backupCmd.SetArgs([]string{"--out", path})
+	require.NoError(t, backupCmd.Execute())
+	assert.FileExists(t, path)
+
+	NewInitDB = func(c *appContext.Context) (*gorm.DB, error) {
+		return target, nil
+	}
+	restoreCmd := GetRestoreCmd(ctx)
+	restoreCmd.SetArgs([]string{"-i", path})
+	require.NoError(t, restoreCmd.Execute())
+
+	var projects []model.Project
+	require.NoError(t, target.Find(&projects).Error)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "website", projects[0].ProjectCode)
+	var roleCount, userCount int64
+	target.Model(&model.Role{}).Count(&roleCount)
+	target.Model(&model.User{}).Count(&userCount)
+	assert.Equal(t, int64(3), roleCount)
+	assert.Equal(t, int64(0), userCount)
+
+	// The target now holds a namespace
+	restoreCmd = GetRestoreCmd(ctx)
+	restoreCmd.SetArgs([]string{"-i", path})
+	assert.ErrorContains(t, restoreCmd.Execute(), "database not empty")
+}
+
+func TestGetBackupRunFn_Errors(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+	oldNewInitDB := NewInitDB
+	defer func() { NewInitDB = oldNewInitDB }()
+	NewInitDB = func(c *appContext.Context) (*gorm.DB, error) {
+		return nil, errors.New("connection failed")
+	}
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+
+	backupCmd := GetBackupCmd(ctx)
+	backupCmd.SetArgs([]string{"--out", path})
+	assert.ErrorContains(t, backupCmd.Execute(), "connection failed")
+	assert.NoFileExists(t, path)
+
+	restoreCmd := GetRestoreCmd(ctx)
+	restoreCmd.SetArgs([]string{"--in", path})
+	assert.ErrorIs(t, restoreCmd.Execute(), os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(path, []byte("not a backup"), 0o600))
+	restoreCmd = GetRestoreCmd(ctx)
+	restoreCmd.SetArgs([]string{"--in", path})
+	assert.ErrorContains(t, restoreCmd.Execute(), "connection failed")
+
+	backupCmd = GetBackupCmd(ctx)
+	backupCmd.SetArgs([]string{})
+	assert.ErrorContains(t, backupCmd.Execute(), "required flag(s) \"out\" not set")
+}
@@ -8,6 +8,7 @@ import (
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
 	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/repository"
@@ -48,7 +49,7 @@ func demoData(appCtx *appContext.Context, db *gorm.DB) error {
 
 	jwtService := jwt.NewServiceJWT(&appCtx.Config.Auth.JWT)
 	repos := repository.NewRepositories(db)
-	services := service.NewServices(appCtx, repos, jwtService)
+	services := service.NewServices(appCtx, repos, jwtService, invalidation.NewMemoryBus())
 
 	namespace1 := &model.Namespace{NamespaceCode: "ns1", Name: "Namespace 1"}
 	namespace2 := &model.Namespace{NamespaceCode: "ns2", Name: "Namespace 2"}
@@ -99,7 +100,7 @@ func demoData(appCtx *appContext.Context, db *gorm.DB) error {
 	}
 
 	for _, redirect := range redirects {
-		_, err := services.RedirectDraft.Create(ctx, redirect.NamespaceCode, redirect.ProjectCode, nil, redirect.Redirect)
+		_, err := services.RedirectDraft.Create(ctx, redirect.NamespaceCode, redirect.ProjectCode, nil, redirect.Redirect, nil)
 		if err != nil {
 			return err
 		}
@@ -124,7 +125,7 @@ func demoData(appCtx *appContext.Context, db *gorm.DB) error {
 		return err
 	}
 
-	_, err = services.Project.Publish(ctx, projects[0].NamespaceCode, projects[0].ProjectCode)
+	_, err = services.Project.Publish(ctx, projects[0].NamespaceCode, projects[0].ProjectCode, types.PublishOptions{})
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,7 @@ import (
 	appContext "github.com/flectolab/flecto-manager/context"
 	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/hash"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/repository"
@@ -46,16 +47,23 @@ func initData(appCtx *appContext.Context, db *gorm.DB) error {
 
 	jwtService := jwt.NewServiceJWT(&appCtx.Config.Auth.JWT)
 	repos := repository.NewRepositories(db)
-	services := service.NewServices(appCtx, repos, jwtService)
+	services := service.NewServices(appCtx, repos, jwtService, invalidation.NewMemoryBus())
 
 	adminUser := &model.User{Username: "admin", Lastname: "Admin", Firstname: "Admin", Active: types.Ptr(true)}
-	hashedPassword, _ := hash.Password(adminUser.Username)
-	adminUser.Password = string(hashedPassword)
 	adminUser, err := services.User.Create(ctx, adminUser)
 	if err != nil {
 		return err
 	}
 
+	// The default password does not match the password policy, it must be changed at first login
+	hashedPassword, err := hash.Password(adminUser.Username)
+	if err != nil {
+		return err
+	}
+	if err = repos.User.UpdatePassword(ctx, adminUser.ID, string(hashedPassword), true); err != nil {
+		return err
+	}
+
 	adminRole := &model.Role{
 		Code: "admin",
 		Type: model.RoleTypeRole,
@@ -113,6 +113,8 @@ func TestInitData_CreatesAdminUserWithCorrectPassword(t *testing.T) {
 	// password should be bcrypt hashed
 	assert.NotEmpty(t, user.Password)
 	assert.NotEqual(t, "admin", user.Password)
+	// the default password must be changed at first login
+	assert.True(t, user.MustChangePassword)
 }
 
 func TestInitData_CreatesAdminRoleWithCorrectPermissions(t *testing.T) {
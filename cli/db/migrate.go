@@ -34,6 +34,7 @@ func GetMigrateCmd(ctx *appContext.Context) *cobra.Command {
 		Use:   "migrate",
 		Short: "Database migration commands",
 	}
+	cmd.PersistentFlags().String(shardFlag, "", "Name of the database shard to migrate instead of the primary database")
 	cmd.AddCommand(
 		getMigrateApplyCmd(ctx),
 		getMigrateDownCmd(ctx),
@@ -42,6 +43,35 @@ func GetMigrateCmd(ctx *appContext.Context) *cobra.Command {
 	return cmd
 }
 
+const shardFlag = "shard"
+
+// newCmdMigrator creates the migrator of the database selected by the shard flag of the command
+func newCmdMigrator(ctx *appContext.Context, cmd *cobra.Command) (Migrator, error) {
+	flag := cmd.Flag(shardFlag)
+	if flag == nil || flag.Value.String() == "" {
+		return NewMigrator(ctx)
+	}
+	shardCtx, err := shardContext(ctx, flag.Value.String())
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(shardCtx)
+}
+
+// shardContext returns a copy of ctx whose database is the given shard
+func shardContext(ctx *appContext.Context, name string) (*appContext.Context, error) {
+	for _, shardConfig := range ctx.Config.DB.Shards {
+		if shardConfig.Name == name {
+			cfg := *ctx.Config
+			cfg.DB = shardConfig.DbConfig()
+			shardCtx := *ctx
+			shardCtx.Config = &cfg
+			return &shardCtx, nil
+		}
+	}
+	return nil, fmt.Errorf("config db shard '%s' does not exist", name)
+}
+
 func createMigrator(ctx *appContext.Context) (Migrator, error) {
 	// Enable multiStatements for migrations (needed to execute multiple SQL statements)
 	if dsn, ok := ctx.Config.DB.Config["dsn"].(string); ok {
@@ -92,10 +122,11 @@ func getMigrateApplyCmd(ctx *appContext.Context) *cobra.Command {
 	var steps int
 
 	cmd := &cobra.Command{
-		Use:   "apply",
-		Short: "Apply pending migrations",
+		Use:     "apply",
+		Aliases: []string{"up"},
+		Short:   "Apply pending migrations",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			m, err := NewMigrator(ctx)
+			m, err := newCmdMigrator(ctx, cmd)
 			if err != nil {
 				return err
 			}
@@ -130,7 +161,7 @@ func getMigrateDownCmd(ctx *appContext.Context) *cobra.Command {
 		Use:   "down",
 		Short: "Rollback migrations",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			m, err := NewMigrator(ctx)
+			m, err := newCmdMigrator(ctx, cmd)
 			if err != nil {
 				return err
 			}
@@ -163,7 +194,7 @@ func getMigrateStatusCmd(ctx *appContext.Context) *cobra.Command {
 		Use:   "status",
 		Short: "Show migration status",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			m, err := NewMigrator(ctx)
+			m, err := newCmdMigrator(ctx, cmd)
 			if err != nil {
 				return err
 			}
@@ -178,12 +209,21 @@ func getMigrateStatusCmd(ctx *appContext.Context) *cobra.Command {
 				return fmt.Errorf("failed to get version: %w", err)
 			}
 
+			latest, err := migrations.LatestVersion()
+			if err != nil {
+				return fmt.Errorf("failed to get latest version: %w", err)
+			}
+
 			fmt.Println("Migration Status")
 			fmt.Println("================")
 			fmt.Printf("Current version: %d\n", version)
-			if dirty {
+			fmt.Printf("Latest version:  %d\n", latest)
+			switch {
+			case dirty:
 				fmt.Println("Status: DIRTY (migration failed, manual fix required)")
-			} else {
+			case version < latest:
+				fmt.Println("Status: PENDING (run 'migrate apply')")
+			default:
 				fmt.Println("Status: OK")
 			}
 
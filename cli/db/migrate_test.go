@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	"github.com/flectolab/flecto-manager/config"
 	appContext "github.com/flectolab/flecto-manager/context"
 	mockMigratorDB "github.com/flectolab/flecto-manager/mocks/flecto-manager/cli/db"
 )
@@ -328,4 +329,40 @@ func TestMigrateDown_MigratorCreationError(t *testing.T) {
 	err := cmd.Execute()
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "connection failed")
-}
\ No newline at end of file
+}
+func TestMigrate_Shard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockMigrator := mockMigratorDB.NewMockMigrator(ctrl)
+	mockMigrator.EXPECT().Up().Return(nil)
+
+	ctx := appContext.TestContext(nil)
+	ctx.Config.DB = config.DbConfig{
+		Type: "mysql",
+		Shards: []config.DbShardConfig{
+			{Name: "eu", Type: "sqlite", Config: map[string]interface{}{"dsn": "eu.db"}, Namespaces: []string{"ns1"}},
+		},
+	}
+
+	var migratedDB config.DbConfig
+	oldNewMigrator := NewMigrator
+	NewMigrator = func(ctx *appContext.Context) (Migrator, error) {
+		migratedDB = ctx.Config.DB
+		return mockMigrator, nil
+	}
+	defer func() { NewMigrator = oldNewMigrator }()
+
+	cmd := GetMigrateCmd(ctx)
+	cmd.SetArgs([]string{"apply", "--shard", "eu"})
+	assert.NoError(t, cmd.Execute())
+	assert.Equal(t, "sqlite", migratedDB.Type)
+	assert.Equal(t, "eu.db", migratedDB.Config["dsn"])
+	assert.Equal(t, "mysql", ctx.Config.DB.Type)
+
+	cmd = GetMigrateCmd(ctx)
+	cmd.SetArgs([]string{"status", "--shard", "us"})
+	err := cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "config db shard 'us' does not exist")
+}
@@ -0,0 +1,145 @@
+package db
+
+import (
+	stdContext "context"
+	"errors"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/hash"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/jwt"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+const (
+	SeedRoleAdmin  = "admin"
+	SeedRoleEditor = "editor"
+	SeedRoleViewer = "viewer"
+)
+
+// seedRoles returns the built-in roles created by the seed
+func seedRoles() []model.Role {
+	return []model.Role{
+		{
+			Code: SeedRoleAdmin,
+			Type: model.RoleTypeRole,
+			Resources: []model.ResourcePermission{
+				{Namespace: "*", Project: "*", Action: model.ActionAll, Resource: model.ResourceTypeAll},
+			},
+			Admin: []model.AdminPermission{
+				{Section: model.AdminSectionAll, Action: model.ActionAll},
+			},
+		},
+		{
+			Code: SeedRoleEditor,
+			Type: model.RoleTypeRole,
+			Resources: []model.ResourcePermission{
+				{Namespace: "*", Project: "*", Action: model.ActionAll, Resource: model.ResourceTypeAll},
+			},
+		},
+		{
+			Code: SeedRoleViewer,
+			Type: model.RoleTypeRole,
+			Resources: []model.ResourcePermission{
+				{Namespace: "*", Project: "*", Action: model.ActionRead, Resource: model.ResourceTypeAll},
+			},
+		},
+	}
+}
+
+func GetSeedCmd(ctx *appContext.Context) *cobra.Command {
+	return &cobra.Command{
+		Use:   "seed",
+		Short: "create the missing bootstrap data",
+		Long: "Create the built-in admin, editor and viewer roles when missing, an admin user when there is no user " +
+			"and an example namespace and project when there is no namespace. Running it again changes nothing.",
+		RunE: GetSeedRunFn(ctx),
+	}
+}
+
+func GetSeedRunFn(ctx *appContext.Context) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		db, errDb := NewInitDB(ctx)
+		if errDb != nil {
+			return errDb
+		}
+		return Seed(ctx, db)
+	}
+}
+
+// Seed creates the bootstrap data missing from the database, so that a fresh install is usable.
+// It only creates the admin user when there is no user and the example namespace when there is no
+// namespace, so that the ones deleted on purpose are not created again.
+func Seed(appCtx *appContext.Context, db *gorm.DB) error {
+	ctx := stdContext.Background()
+
+	jwtService := jwt.NewServiceJWT(&appCtx.Config.Auth.JWT)
+	repos := repository.NewRepositories(db)
+	services := service.NewServices(appCtx, repos, jwtService, invalidation.NewMemoryBus())
+
+	roles := make(map[string]*model.Role)
+	for _, seedRole := range seedRoles() {
+		role, err := services.Role.GetByCode(ctx, seedRole.Code, seedRole.Type)
+		if errors.Is(err, service.ErrRoleNotFound) {
+			role, err = services.Role.Create(ctx, &seedRole)
+			if err == nil {
+				appCtx.Logger.Info("seed: role created", "role", role.Code)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		roles[role.Code] = role
+	}
+
+	var userCount int64
+	if err := repos.User.GetTx(ctx).Model(&model.User{}).Count(&userCount).Error; err != nil {
+		return err
+	}
+	if userCount == 0 {
+		if err := seedAdminUser(ctx, services, repos, roles[SeedRoleAdmin]); err != nil {
+			return err
+		}
+		appCtx.Logger.Info("seed: admin user created, its password must be changed at first login", "username", "admin")
+	}
+
+	namespaces, err := services.Namespace.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	if len(namespaces) == 0 {
+		namespace, errNamespace := services.Namespace.Create(ctx, &model.Namespace{NamespaceCode: "example", Name: "Example"})
+		if errNamespace != nil {
+			return errNamespace
+		}
+		_, errProject := services.Project.Create(ctx, &model.Project{ProjectCode: "website", Name: "Website", NamespaceCode: namespace.NamespaceCode})
+		if errProject != nil {
+			return errProject
+		}
+		appCtx.Logger.Info("seed: example namespace and project created", "namespace", "example", "project", "website")
+	}
+	return nil
+}
+
+// seedAdminUser creates the admin user in the admin role, with its username as password
+func seedAdminUser(ctx stdContext.Context, services *service.Services, repos *repository.Repositories, adminRole *model.Role) error {
+	adminUser, err := services.User.Create(ctx, &model.User{Username: "admin", Lastname: "Admin", Firstname: "Admin", Active: types.Ptr(true)})
+	if err != nil {
+		return err
+	}
+
+	// The default password does not match the password policy, it must be changed at first login
+	hashedPassword, err := hash.Password(adminUser.Username)
+	if err != nil {
+		return err
+	}
+	if err = repos.User.UpdatePassword(ctx, adminUser.ID, string(hashedPassword), true); err != nil {
+		return err
+	}
+	return services.Role.AddUserToRole(ctx, adminUser.ID, adminRole.ID)
+}
@@ -0,0 +1,89 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestGetSeedCmd(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+	cmd := GetSeedCmd(ctx)
+
+	assert.Equal(t, "seed", cmd.Use)
+}
+
+func TestGetSeedRunFn(t *testing.T) {
+	db := setupInitTestDB(t)
+	ctx := appContext.TestContext(nil)
+
+	oldNewInitDB := NewInitDB
+	NewInitDB = func(c *appContext.Context) (*gorm.DB, error) {
+		return db, nil
+	}
+	defer func() { NewInitDB = oldNewInitDB }()
+
+	require.NoError(t, GetSeedCmd(ctx).Execute())
+	// seeding again changes nothing
+	require.NoError(t, GetSeedCmd(ctx).Execute())
+
+	var roles []model.Role
+	require.NoError(t, db.Preload("Resources").Preload("Admin").Where("type = ?", model.RoleTypeRole).Order("code").Find(&roles).Error)
+	require.Len(t, roles, 3)
+	assert.Equal(t, SeedRoleAdmin, roles[0].Code)
+	assert.Len(t, roles[0].Admin, 1)
+	assert.Equal(t, SeedRoleEditor, roles[1].Code)
+	assert.Empty(t, roles[1].Admin)
+	assert.Equal(t, model.ActionAll, roles[1].Resources[0].Action)
+	assert.Equal(t, SeedRoleViewer, roles[2].Code)
+	assert.Equal(t, model.ActionRead, roles[2].Resources[0].Action)
+
+	var users []model.User
+	require.NoError(t, db.Find(&users).Error)
+	require.Len(t, users, 1)
+	assert.Equal(t, "admin", users[0].Username)
+	assert.True(t, users[0].MustChangePassword)
+	var userRole model.UserRole
+	assert.NoError(t, db.Where("user_id = ? AND role_id = ?", users[0].ID, roles[0].ID).First(&userRole).Error)
+
+	var projects []model.Project
+	require.NoError(t, db.Find(&projects).Error)
+	require.Len(t, projects, 1)
+	assert.Equal(t, "example", projects[0].NamespaceCode)
+	assert.Equal(t, "website", projects[0].ProjectCode)
+}
+
+func TestSeed_KeepsExistingData(t *testing.T) {
+	db := setupInitTestDB(t)
+	ctx := appContext.TestContext(nil)
+	require.NoError(t, db.Create(&model.User{Username: "jdoe", Firstname: "John", Lastname: "Doe"}).Error)
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "shop", Name: "Shop"}).Error)
+	require.NoError(t, db.Create(&model.Role{Code: SeedRoleViewer, Type: model.RoleTypeRole}).Error)
+
+	require.NoError(t, Seed(ctx, db))
+
+	var userCount, namespaceCount, viewerCount int64
+	db.Model(&model.User{}).Count(&userCount)
+	db.Model(&model.Namespace{}).Count(&namespaceCount)
+	db.Model(&model.Role{}).Where("code = ?", SeedRoleViewer).Count(&viewerCount)
+	assert.Equal(t, int64(1), userCount)
+	assert.Equal(t, int64(1), namespaceCount)
+	assert.Equal(t, int64(1), viewerCount)
+}
+
+func TestGetSeedRunFn_DBError(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+
+	oldNewInitDB := NewInitDB
+	NewInitDB = func(c *appContext.Context) (*gorm.DB, error) {
+		return nil, errors.New("connection failed")
+	}
+	defer func() { NewInitDB = oldNewInitDB }()
+
+	assert.ErrorContains(t, GetSeedCmd(ctx).Execute(), "connection failed")
+}
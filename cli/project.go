@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"github.com/flectolab/flecto-manager/cli/project"
+	"github.com/flectolab/flecto-manager/context"
+	"github.com/spf13/cobra"
+)
+
+func GetProjectCmd(ctx *context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project",
+		Short: "project sub commands",
+	}
+	cmd.AddCommand(project.GetApplyCmd(ctx))
+
+	return cmd
+}
@@ -0,0 +1,109 @@
+package project
+
+import (
+	stdContext "context"
+	"fmt"
+	"os"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/jwt"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+const (
+	namespaceFlag = "namespace"
+	projectFlag   = "project"
+	fileFlag      = "file"
+	publishFlag   = "publish"
+	dryRunFlag    = "dry-run"
+	forceFlag     = "force"
+)
+
+type CreateApplyDBFn func(ctx *appContext.Context) (*gorm.DB, error)
+
+var NewApplyDB CreateApplyDBFn = func(ctx *appContext.Context) (*gorm.DB, error) {
+	return database.CreateDB(ctx)
+}
+
+func GetApplyCmd(ctx *appContext.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "apply a manifest to a project",
+		Long: "Read a YAML or JSON manifest holding the full set of redirects and pages of a project, compare it " +
+			"with the published project and rewrite the drafts of the project so that publishing them gives the manifest.",
+		RunE: GetApplyRunFn(ctx),
+	}
+	cmd.Flags().StringP(namespaceFlag, "n", "", "Namespace code of the project")
+	cmd.Flags().StringP(projectFlag, "p", "", "Project code")
+	cmd.Flags().StringP(fileFlag, "f", "", "Path of the manifest file")
+	cmd.Flags().Bool(publishFlag, false, "Publish the project once its drafts match the manifest")
+	cmd.Flags().Bool(dryRunFlag, false, "Report the changes without writing any draft")
+	cmd.Flags().Bool(forceFlag, false, "Apply and publish the manifest even when it deletes most of the project")
+	_ = cmd.MarkFlagRequired(namespaceFlag)
+	_ = cmd.MarkFlagRequired(projectFlag)
+	_ = cmd.MarkFlagRequired(fileFlag)
+	return cmd
+}
+
+func GetApplyRunFn(appCtx *appContext.Context) func(*cobra.Command, []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		namespaceCode, _ := cmd.Flags().GetString(namespaceFlag)
+		projectCode, _ := cmd.Flags().GetString(projectFlag)
+		path, _ := cmd.Flags().GetString(fileFlag)
+		opts := types.ApplyProjectOptions{}
+		opts.Publish, _ = cmd.Flags().GetBool(publishFlag)
+		opts.DryRun, _ = cmd.Flags().GetBool(dryRunFlag)
+		opts.Force, _ = cmd.Flags().GetBool(forceFlag)
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = file.Close() }()
+		manifest, err := service.ParseProjectManifest(file)
+		if err != nil {
+			return err
+		}
+
+		db, errDb := NewApplyDB(appCtx)
+		if errDb != nil {
+			return errDb
+		}
+		services := service.NewServices(appCtx, repository.NewRepositories(db), jwt.NewServiceJWT(&appCtx.Config.Auth.JWT), invalidation.NewMemoryBus())
+		result, err := services.ProjectApply.Apply(stdContext.Background(), namespaceCode, projectCode, manifest, opts)
+		if err != nil {
+			return err
+		}
+
+		printChanges(cmd, "redirect", result.Redirects)
+		printChanges(cmd, "page", result.Pages)
+		switch {
+		case result.Published:
+			fmt.Fprintf(cmd.OutOrStdout(), "Project %s/%s published, version %d\n", namespaceCode, projectCode, result.Version)
+		case !result.HasChanges():
+			fmt.Fprintf(cmd.OutOrStdout(), "Project %s/%s matches the manifest\n", namespaceCode, projectCode)
+		case opts.DryRun:
+			fmt.Fprintf(cmd.OutOrStdout(), "Dry run, %s\n", summary(result))
+		default:
+			fmt.Fprintf(cmd.OutOrStdout(), "Drafts written, %s\n", summary(result))
+		}
+		return nil
+	}
+}
+
+func printChanges(cmd *cobra.Command, kind string, changes []model.ApplyChange) {
+	for _, change := range changes {
+		fmt.Fprintf(cmd.OutOrStdout(), "%-6s %s %s\n", change.Action, kind, change.Key)
+	}
+}
+
+func summary(result *model.ApplyResult) string {
+	return fmt.Sprintf("%d redirect changes, %d page changes, %d unchanged", len(result.Redirects), len(result.Pages), result.Unchanged)
+}
@@ -0,0 +1,126 @@
+package project
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+const testManifest = `
+redirects:
+  - type: BASIC
+    source: /old
+    target: /new
+    status: MOVED_PERMANENT
+pages:
+  - type: BASIC
+    path: /robots.txt
+    content: "User-agent: *"
+    contentType: TEXT_PLAIN
+`
+
+func setupApplyTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(database.Models...))
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "shop", Name: "Shop"}).Error)
+	require.NoError(t, db.Create(&model.Project{NamespaceCode: "shop", ProjectCode: "web", Name: "Web"}).Error)
+	require.NoError(t, db.Create(&model.Redirect{
+		NamespaceCode: "shop", ProjectCode: "web", IsPublished: types.Ptr(true),
+		Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/legacy", Target: "/new", Status: commonTypes.RedirectStatusFound},
+	}).Error)
+	return db
+}
+
+func writeManifest(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestGetApplyCmd(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+	cmd := GetApplyCmd(ctx)
+
+	assert.Equal(t, "apply", cmd.Use)
+	for _, flag := range []string{"namespace", "project", "file", "publish", "dry-run", "force"} {
+		assert.NotNil(t, cmd.Flags().Lookup(flag), flag)
+	}
+}
+
+func TestGetApplyRunFn(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+	db := setupApplyTestDB(t)
+	oldNewApplyDB := NewApplyDB
+	defer func() { NewApplyDB = oldNewApplyDB }()
+	NewApplyDB = func(c *appContext.Context) (*gorm.DB, error) {
+		return db, nil
+	}
+	path := writeManifest(t, testManifest)
+
+	var out bytes.Buffer
+	cmd := GetApplyCmd(ctx)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"-n", "shop", "-p", "web", "-f", path, "--dry-run"})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "DELETE redirect /legacy\nCREATE redirect /old\nCREATE page /robots.txt\n"+
+		"Dry run, 2 redirect changes, 1 page changes, 0 unchanged\n", out.String())
+
+	out.Reset()
+	cmd = GetApplyCmd(ctx)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"-n", "shop", "-p", "web", "-f", path, "--publish"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), "Project shop/web published, version 2\n")
+
+	var redirects []model.Redirect
+	require.NoError(t, db.Find(&redirects).Error)
+	require.Len(t, redirects, 1)
+	assert.Equal(t, "/old", redirects[0].Source)
+
+	out.Reset()
+	cmd = GetApplyCmd(ctx)
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"-n", "shop", "-p", "web", "-f", path})
+	require.NoError(t, cmd.Execute())
+	assert.Equal(t, "Project shop/web matches the manifest\n", out.String())
+}
+
+func TestGetApplyRunFn_Errors(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+	oldNewApplyDB := NewApplyDB
+	defer func() { NewApplyDB = oldNewApplyDB }()
+	NewApplyDB = func(c *appContext.Context) (*gorm.DB, error) {
+		return nil, errors.New("connection failed")
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "missing flags", args: []string{"-n", "shop"}, wantErr: "required flag(s)"},
+		{name: "missing file", args: []string{"-n", "shop", "-p", "web", "-f", filepath.Join(t.TempDir(), "missing.yaml")}, wantErr: "no such file"},
+		{name: "invalid manifest", args: []string{"-n", "shop", "-p", "web", "-f", writeManifest(t, "pages: {")}, wantErr: "invalid manifest"},
+		{name: "database", args: []string{"-n", "shop", "-p", "web", "-f", writeManifest(t, testManifest)}, wantErr: "connection failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := GetApplyCmd(ctx)
+			cmd.SetArgs(tt.args)
+			assert.ErrorContains(t, cmd.Execute(), tt.wantErr)
+		})
+	}
+}
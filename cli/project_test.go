@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/flectolab/flecto-manager/context"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProjectCmd(t *testing.T) {
+	ctx := context.TestContext(nil)
+	cmd := GetProjectCmd(ctx)
+
+	assert.Equal(t, "project", cmd.Use)
+	applyCmd, _, err := cmd.Find([]string{"apply"})
+	assert.NoError(t, err)
+	assert.Equal(t, "apply", applyCmd.Use)
+}
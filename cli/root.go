@@ -1,12 +1,16 @@
 package cli
 
 import (
+	stdContext "context"
 	"fmt"
 	"log/slog"
 	"path"
 	"path/filepath"
 
+	"github.com/flectolab/flecto-manager/cli/db"
+	"github.com/flectolab/flecto-manager/cli/project"
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
 	"github.com/flectolab/flecto-manager/context"
 
 	"github.com/spf13/cobra"
@@ -17,6 +21,10 @@ const (
 	ConfigName = "manager"
 	LogLevel   = "level"
 	Name       = "flecto-manager"
+
+	// ReadOnlyEnv and ReadOnlyMessageEnv switch the read-only mode without editing the configuration file
+	ReadOnlyEnv        = "FLECTO_MANAGER_READ_ONLY"
+	ReadOnlyMessageEnv = "FLECTO_MANAGER_READ_ONLY_MESSAGE"
 )
 
 func GetDefaultConfigPath() string {
@@ -38,6 +46,12 @@ func GetRootCmd(ctx *context.Context) *cobra.Command {
 	cmd.AddCommand(
 		GetStartCmd(ctx),
 		GetDBCmd(ctx),
+		db.GetMigrateCmd(ctx),
+		db.GetSeedCmd(ctx),
+		db.GetBackupCmd(ctx),
+		db.GetRestoreCmd(ctx),
+		GetProjectCmd(ctx),
+		project.GetApplyCmd(ctx),
 		GetUserCmd(ctx),
 		GetVersionCmd(),
 		GetValidateCmd(ctx),
@@ -50,12 +64,19 @@ func GetRootPreRunEFn(ctx *context.Context, validateCfg bool) func(*cobra.Comman
 	return func(cmd *cobra.Command, args []string) error {
 		var err error
 		initConfig(ctx, cmd)
+		if err = config.ResolveSecrets(commandContext(cmd), ctx.Config, config.DefaultSecretResolvers()); err != nil {
+			return err
+		}
 
 		if errValidate := validateConfig(ctx); validateCfg && errValidate != nil {
 			return errValidate
 		}
 
+		// The level flag takes precedence over the level key of the configuration file
 		logLevelFlagStr, _ := cmd.Flags().GetString(LogLevel)
+		if !cmd.Flags().Changed(LogLevel) && ctx.Config.LogLevel != "" {
+			logLevelFlagStr = ctx.Config.LogLevel
+		}
 		if logLevelFlagStr != "" {
 			level := slog.LevelInfo
 			err = level.UnmarshalText([]byte(logLevelFlagStr))
@@ -80,6 +101,8 @@ func initConfig(ctx *context.Context, cmd *cobra.Command) {
 	if err := viper.BindPFlags(cmd.Flags()); err != nil {
 		panic(err)
 	}
+	_ = viper.BindEnv("read_only.enabled", ReadOnlyEnv)
+	_ = viper.BindEnv("read_only.message", ReadOnlyMessageEnv)
 
 	configPath := viper.GetString(ConfigName)
 
@@ -102,4 +125,29 @@ func initConfig(ctx *context.Context, cmd *cobra.Command) {
 		panic(fmt.Errorf("unable to decode into config struct, %v", err))
 	}
 
+	ctx.ConfigLoader = loadConfig
+}
+
+// loadConfig reads the configuration file again, the flags and the environment variables still applying,
+// and resolves its secrets again so that the rotated ones are taken
+func loadConfig() (*config.Config, error) {
+	if err := viper.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	cfg := config.DefaultConfig()
+	if err := viper.Unmarshal(cfg); err != nil {
+		return nil, err
+	}
+	if err := config.ResolveSecrets(stdContext.Background(), cfg, config.DefaultSecretResolvers()); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// commandContext returns the context of the command, which is nil when it is not executed
+func commandContext(cmd *cobra.Command) stdContext.Context {
+	if cmdCtx := cmd.Context(); cmdCtx != nil {
+		return cmdCtx
+	}
+	return stdContext.Background()
 }
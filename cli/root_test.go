@@ -45,6 +45,20 @@ func Test_initConfig_SuccessWithConfigFlag(t *testing.T) {
 	assert.Equal(t, want, ctx.Config)
 }
 
+func Test_initConfig_ReadOnlyEnv(t *testing.T) {
+	ctx := context.TestContext(nil)
+	cmd := GetRootCmd(ctx)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	viper.Reset()
+	viper.SetFs(afero.NewMemMapFs())
+	t.Setenv(ReadOnlyEnv, "true")
+	t.Setenv(ReadOnlyMessageEnv, "database migration")
+
+	initConfig(ctx, cmd)
+	assert.Equal(t, config.ReadOnlyConfig{Enabled: true, Message: "database migration"}, ctx.Config.ReadOnly)
+}
+
 func Test_initConfig_FailReadConfig(t *testing.T) {
 	ctx := context.TestContext(nil)
 	cmd := GetRootCmd(ctx)
@@ -184,3 +198,48 @@ func TestGetRootPreRunEFn_FailValidator(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "configuration file is not valid")
 }
+
+func TestGetRootPreRunEFn_ResolveSecrets(t *testing.T) {
+	configStr := `
+db:
+  type: mysql
+  config:
+    dsn: env:FLECTO_TEST_DSN
+auth:
+  jwt:
+    secret: env:FLECTO_TEST_JWT_SECRET
+    access_token_ttl: 15m
+    refresh_token_ttl: 168h
+    issuer: "flecto-manager-test"`
+	setup := func() *context.Context {
+		ctx := context.TestContext(nil)
+		path := GetDefaultConfigPath()
+		fs := afero.NewMemMapFs()
+		_ = fs.Mkdir(path, 0775)
+		_ = afero.WriteFile(fs, fmt.Sprintf("%s/%s.yml", path, ConfigName), []byte(configStr+"\n"), 0644)
+		viper.Reset()
+		viper.SetFs(fs)
+		return ctx
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Setenv("FLECTO_TEST_DSN", "flecto:secret@tcp(127.0.0.1:3306)/flecto")
+		t.Setenv("FLECTO_TEST_JWT_SECRET", "test-secret-key-for-jwt-min-32-chars!")
+		ctx := setup()
+		cmd := GetRootCmd(ctx)
+
+		assert.NoError(t, GetRootPreRunEFn(ctx, true)(cmd, []string{}))
+		assert.Equal(t, "flecto:secret@tcp(127.0.0.1:3306)/flecto", ctx.Config.DB.Config["dsn"])
+		assert.Equal(t, "test-secret-key-for-jwt-min-32-chars!", ctx.Config.Auth.JWT.Secret)
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		t.Setenv("FLECTO_TEST_DSN", "flecto:secret@tcp(127.0.0.1:3306)/flecto")
+		ctx := setup()
+		cmd := GetRootCmd(ctx)
+
+		err := GetRootPreRunEFn(ctx, true)(cmd, []string{})
+		assert.ErrorIs(t, err, config.ErrSecretNotFound)
+		assert.ErrorContains(t, err, "auth.jwt.secret")
+	})
+}
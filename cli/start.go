@@ -5,22 +5,43 @@ import (
 	"fmt"
 	buildinHttp "net/http"
 
+	"github.com/flectolab/flecto-manager/cli/db"
 	"github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/http"
 	"github.com/flectolab/flecto-manager/metrics"
 	"github.com/spf13/cobra"
 )
 
+const skipSchemaCheckFlag = "skip-schema-check"
+
 func GetStartCmd(ctx *context.Context) *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "start",
 		Short: "start server",
 		RunE:  GetStartRunFn(ctx),
 	}
+	cmd.Flags().Bool(skipSchemaCheckFlag, false, "Start even when database migrations are pending")
+	return cmd
 }
 
 func GetStartRunFn(ctx *context.Context) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) error {
+		if skip, _ := cmd.Flags().GetBool(skipSchemaCheckFlag); !skip {
+			if err := checkSchema(ctx); err != nil {
+				return err
+			}
+		}
+		if ctx.Config.DB.Seed {
+			gormDB, err := database.CreateDB(ctx)
+			if err != nil {
+				return err
+			}
+			if err = db.Seed(ctx, gormDB); err != nil {
+				return fmt.Errorf("failed to seed database: %w", err)
+			}
+		}
+
 		e, err := http.CreateServerHTTP(ctx)
 		if err != nil {
 			return err
@@ -44,6 +65,11 @@ func GetStartRunFn(ctx *context.Context) func(*cobra.Command, []string) error {
 					}
 					_ = e.Shutdown(stdContext.Background())
 					ctx.Logger.Info("graceful shutdown completed")
+				case <-ctx.ReloadSignal():
+					ctx.Logger.Info("SIGHUP received, reloading configuration...")
+					if errReload := ctx.ReloadConfig(); errReload != nil {
+						ctx.Logger.Error("failed to reload configuration", "error", errReload)
+					}
 				}
 			}
 		}()
@@ -57,3 +83,16 @@ func GetStartRunFn(ctx *context.Context) func(*cobra.Command, []string) error {
 		return nil
 	}
 }
+
+// checkSchema refuses to start on databases whose migrations are not all applied,
+// the queries of the server expecting the latest schema
+func checkSchema(ctx *context.Context) error {
+	db, err := database.CreateDB(ctx)
+	if err != nil {
+		return err
+	}
+	if err = database.CheckSchema(stdContext.Background(), db); err != nil {
+		return fmt.Errorf("refusing to start: %w, apply the migrations with 'flecto-manager migrate up' or start with --%s", err, skipSchemaCheckFlag)
+	}
+	return nil
+}
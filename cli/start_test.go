@@ -40,6 +40,7 @@ func TestGetStartRunFn_SuccessOnlyListenHTTP(t *testing.T) {
 	defer ctrl.Finish()
 
 	cmd := GetStartCmd(ctx)
+	_ = cmd.Flags().Set(skipSchemaCheckFlag, "true")
 	go func() {
 		err := GetStartRunFn(ctx)(cmd, []string{})
 		assert.NoError(t, err)
@@ -76,6 +77,7 @@ func TestGetStartRunFn_FailPortAlreadyBind(t *testing.T) {
 
 	ctx.Config.HTTP.Listen = e.Listener.Addr().String()
 	cmd := GetStartCmd(ctx)
+	_ = cmd.Flags().Set(skipSchemaCheckFlag, "true")
 
 	assert.Panics(t, func() {
 		_ = GetStartRunFn(ctx)(cmd, []string{})
@@ -113,6 +115,7 @@ func TestGetStartRunFn_WithMetricsEnabled(t *testing.T) {
 	defer ctrl.Finish()
 
 	cmd := GetStartCmd(ctx)
+	_ = cmd.Flags().Set(skipSchemaCheckFlag, "true")
 	go func() {
 		err := GetStartRunFn(ctx)(cmd, []string{})
 		assert.NoError(t, err)
@@ -151,6 +154,7 @@ func TestGetStartRunFn_WithSeparateMetricsServer(t *testing.T) {
 	defer ctrl.Finish()
 
 	cmd := GetStartCmd(ctx)
+	_ = cmd.Flags().Set(skipSchemaCheckFlag, "true")
 	go func() {
 		err := GetStartRunFn(ctx)(cmd, []string{})
 		assert.NoError(t, err)
@@ -158,3 +162,18 @@ func TestGetStartRunFn_WithSeparateMetricsServer(t *testing.T) {
 	time.Sleep(time.Millisecond * 500)
 	ctx.Signal() <- syscall.SIGINT
 }
+
+func TestGetStartRunFn_FailSchemaOutdated(t *testing.T) {
+	database.FactoryDialector[database.DbTypeSqlite] = database.CreateDialectorSqlite
+	ctx := context.TestContext(nil)
+	ctx.Config.DB = config.DbConfig{
+		Type:   database.DbTypeSqlite,
+		Config: map[string]interface{}{"dsn": ":memory:"},
+	}
+	ctx.Config.HTTP.Listen = "127.0.0.1:0"
+
+	cmd := GetStartCmd(ctx)
+	err := GetStartRunFn(ctx)(cmd, []string{})
+	assert.ErrorContains(t, err, "refusing to start")
+	assert.ErrorContains(t, err, "--"+skipSchemaCheckFlag)
+}
@@ -6,6 +6,7 @@ import (
 
 	appContext "github.com/flectolab/flecto-manager/context"
 	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/service"
@@ -40,7 +41,7 @@ func GetChangePasswordRunFn(appCtx *appContext.Context) func(*cobra.Command, []s
 
 		jwtService := jwt.NewServiceJWT(&appCtx.Config.Auth.JWT)
 		repos := repository.NewRepositories(db)
-		services := service.NewServices(appCtx, repos, jwtService)
+		services := service.NewServices(appCtx, repos, jwtService, invalidation.NewMemoryBus())
 
 		username, err := cmd.Flags().GetString("username")
 		if err != nil {
@@ -61,6 +62,6 @@ func GetChangePasswordRunFn(appCtx *appContext.Context) func(*cobra.Command, []s
 			return err
 		}
 
-		return services.User.UpdatePassword(ctx, user.ID, password)
+		return services.User.UpdatePassword(ctx, user.ID, password, false)
 	}
 }
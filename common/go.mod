@@ -1,14 +1,16 @@
 module github.com/flectolab/flecto-manager/common
 
-go 1.24
+go 1.24.0
 
 require (
 	github.com/armon/go-radix v1.0.0
 	github.com/stretchr/testify v1.11.1
+	golang.org/x/net v0.47.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/text v0.31.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -39,6 +39,24 @@ func (s AgentStatus) IsValid() bool {
 	}
 }
 
+// Environment is the publication stage a project is served from: publishing feeds staging,
+// promoting copies staging to production
+type Environment string
+
+const (
+	EnvironmentStaging    Environment = "staging"
+	EnvironmentProduction Environment = "production"
+)
+
+func (e Environment) IsValid() bool {
+	switch e {
+	case EnvironmentStaging, EnvironmentProduction:
+		return true
+	default:
+		return false
+	}
+}
+
 type Agent struct {
 	Name         string      `json:"name" gorm:"size:100"`
 	Status       AgentStatus `json:"status" gorm:"size:50"`
@@ -46,6 +64,8 @@ type Agent struct {
 	Version      int         `json:"version"`
 	LoadDuration Duration    `json:"load_duration"`
 	Error        string      `json:"error" gorm:"size:500"`
+	// Environment the agent is subscribed to, staging when empty
+	Environment Environment `json:"environment,omitempty" gorm:"size:20"`
 }
 
 func ValidateAgent(agent Agent) error {
@@ -61,9 +81,89 @@ func ValidateAgent(agent Agent) error {
 		return fmt.Errorf("invalid agent status: %s", agent.Status)
 	}
 
+	if agent.Environment != "" && !agent.Environment.IsValid() {
+		return fmt.Errorf("invalid agent environment: %s", agent.Environment)
+	}
+
 	if agent.Version == 0 {
 		return fmt.Errorf("agent version is required")
 	}
 
 	return nil
 }
+
+// AgentRegistration is sent by an agent to register with the manager, once for all the projects it serves
+type AgentRegistration struct {
+	Name         string    `json:"name"`
+	Type         AgentType `json:"type"`
+	Hostname     string    `json:"hostname,omitempty"`
+	AgentVersion string    `json:"agentVersion,omitempty"`
+	// Projects served by the agent, on which the registering token needs the agent write permission
+	Projects []AgentProject `json:"projects"`
+}
+
+// AgentProject is a project served by an agent
+type AgentProject struct {
+	NamespaceCode string `json:"namespaceCode"`
+	ProjectCode   string `json:"projectCode"`
+}
+
+// AgentAppliedVersion is the version of a project currently applied by an agent
+type AgentAppliedVersion struct {
+	NamespaceCode string `json:"namespaceCode"`
+	ProjectCode   string `json:"projectCode"`
+	// Environment the agent is subscribed to, staging when empty
+	Environment Environment `json:"environment,omitempty"`
+	Version     int         `json:"version"`
+}
+
+// AgentHeartbeat is sent periodically by a registered agent with the versions it currently applies
+type AgentHeartbeat struct {
+	Projects []AgentAppliedVersion `json:"projects"`
+}
+
+func ValidateAgentRegistration(registration AgentRegistration) error {
+	if !validAgentNameRegex.MatchString(registration.Name) {
+		return fmt.Errorf("invalid agent name: only alphanumeric characters, underscores and hyphens are allowed")
+	}
+
+	if !registration.Type.IsValid() {
+		return fmt.Errorf("invalid agent type: %s", registration.Type)
+	}
+
+	if len(registration.Projects) == 0 {
+		return fmt.Errorf("at least one project is required")
+	}
+	for _, project := range registration.Projects {
+		if project.NamespaceCode == "" || project.ProjectCode == "" {
+			return fmt.Errorf("namespaceCode and projectCode are required")
+		}
+	}
+
+	return nil
+}
+
+func ValidateAgentHeartbeat(heartbeat AgentHeartbeat) error {
+	seen := make(map[AgentAppliedVersion]bool, len(heartbeat.Projects))
+	for _, project := range heartbeat.Projects {
+		if project.NamespaceCode == "" || project.ProjectCode == "" {
+			return fmt.Errorf("namespaceCode and projectCode are required")
+		}
+		if project.Environment != "" && !project.Environment.IsValid() {
+			return fmt.Errorf("invalid agent environment: %s", project.Environment)
+		}
+		if project.Version < 0 {
+			return fmt.Errorf("invalid version %d of project %s/%s", project.Version, project.NamespaceCode, project.ProjectCode)
+		}
+		key := AgentAppliedVersion{NamespaceCode: project.NamespaceCode, ProjectCode: project.ProjectCode, Environment: project.Environment}
+		if key.Environment == "" {
+			key.Environment = EnvironmentStaging
+		}
+		if seen[key] {
+			return fmt.Errorf("project %s/%s is reported twice for the %s environment", project.NamespaceCode, project.ProjectCode, key.Environment)
+		}
+		seen[key] = true
+	}
+
+	return nil
+}
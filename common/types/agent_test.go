@@ -79,6 +79,42 @@ func TestAgentStatus_IsValid(t *testing.T) {
 	}
 }
 
+func TestEnvironment_IsValid(t *testing.T) {
+	tests := []struct {
+		name string
+		e    Environment
+		want bool
+	}{
+		{
+			name: "valid staging environment",
+			e:    EnvironmentStaging,
+			want: true,
+		},
+		{
+			name: "valid production environment",
+			e:    EnvironmentProduction,
+			want: true,
+		},
+		{
+			name: "invalid environment",
+			e:    Environment("qa"),
+			want: false,
+		},
+		{
+			name: "empty environment",
+			e:    Environment(""),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.e.IsValid()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestValidateAgent(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -156,6 +192,26 @@ func TestValidateAgent(t *testing.T) {
 			},
 			wantErr: "invalid agent status: pending",
 		},
+		{
+			name: "valid agent with environment",
+			agent: Agent{
+				Name:        "my-agent",
+				Type:        AgentTypeTraefik,
+				Version:     1,
+				Environment: EnvironmentProduction,
+			},
+			wantErr: "",
+		},
+		{
+			name: "invalid environment",
+			agent: Agent{
+				Name:        "my-agent",
+				Type:        AgentTypeTraefik,
+				Version:     1,
+				Environment: Environment("qa"),
+			},
+			wantErr: "invalid agent environment: qa",
+		},
 		{
 			name: "missing version",
 			agent: Agent{
@@ -177,3 +233,68 @@ func TestValidateAgent(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAgentRegistration(t *testing.T) {
+	projects := []AgentProject{{NamespaceCode: "ns", ProjectCode: "proj"}}
+	assert.NoError(t, ValidateAgentRegistration(AgentRegistration{Name: "edge-eu-1", Type: AgentTypeTraefik, Hostname: "edge1", Projects: projects}))
+	assert.EqualError(t, ValidateAgentRegistration(AgentRegistration{Name: "edge eu", Type: AgentTypeTraefik, Projects: projects}),
+		"invalid agent name: only alphanumeric characters, underscores and hyphens are allowed")
+	assert.EqualError(t, ValidateAgentRegistration(AgentRegistration{Name: "edge", Type: "nginx", Projects: projects}), "invalid agent type: nginx")
+	assert.EqualError(t, ValidateAgentRegistration(AgentRegistration{Name: "edge", Type: AgentTypeTraefik}), "at least one project is required")
+	assert.EqualError(t, ValidateAgentRegistration(AgentRegistration{Name: "edge", Type: AgentTypeTraefik, Projects: []AgentProject{{NamespaceCode: "ns"}}}),
+		"namespaceCode and projectCode are required")
+}
+
+func TestValidateAgentHeartbeat(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects []AgentAppliedVersion
+		wantErr  string
+	}{
+		{
+			name:     "no project",
+			projects: nil,
+		},
+		{
+			name: "both environments of a project",
+			projects: []AgentAppliedVersion{
+				{NamespaceCode: "ns", ProjectCode: "proj", Version: 3},
+				{NamespaceCode: "ns", ProjectCode: "proj", Environment: EnvironmentProduction, Version: 2},
+			},
+		},
+		{
+			name:     "missing project code",
+			projects: []AgentAppliedVersion{{NamespaceCode: "ns", Version: 1}},
+			wantErr:  "namespaceCode and projectCode are required",
+		},
+		{
+			name:     "invalid environment",
+			projects: []AgentAppliedVersion{{NamespaceCode: "ns", ProjectCode: "proj", Environment: "qa"}},
+			wantErr:  "invalid agent environment: qa",
+		},
+		{
+			name:     "negative version",
+			projects: []AgentAppliedVersion{{NamespaceCode: "ns", ProjectCode: "proj", Version: -1}},
+			wantErr:  "invalid version -1 of project ns/proj",
+		},
+		{
+			name: "project reported twice, staging by default",
+			projects: []AgentAppliedVersion{
+				{NamespaceCode: "ns", ProjectCode: "proj", Version: 3},
+				{NamespaceCode: "ns", ProjectCode: "proj", Environment: EnvironmentStaging, Version: 2},
+			},
+			wantErr: "project ns/proj is reported twice for the staging environment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAgentHeartbeat(AgentHeartbeat{Projects: tt.projects})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package types
+
+// CacheTTLMax is the maximum cache duration, in seconds, hinted to the agents
+const CacheTTLMax = 7 * 24 * 3600
+
+// CacheTTL are the cache durations, in seconds, hinted to the agents by a project and sent with its redirects
+// and pages. A zero duration leaves the agents to their own default.
+type CacheTTL struct {
+	// Pages is the default duration the responses of the pages can be cached for, set in the Cache-Control header
+	// of the pages not setting it themselves
+	Pages int `json:"pages" gorm:"default:0;not null"`
+	// Redirects is the duration the agents keep the redirect table before checking for a new version
+	Redirects int `json:"redirects" gorm:"default:0;not null"`
+}
+
+// IsValid returns true when both durations are between 0 and CacheTTLMax
+func (c CacheTTL) IsValid() bool {
+	return c.Pages >= 0 && c.Pages <= CacheTTLMax && c.Redirects >= 0 && c.Redirects <= CacheTTLMax
+}
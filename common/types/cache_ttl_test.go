@@ -0,0 +1,14 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheTTL_IsValid(t *testing.T) {
+	assert.True(t, CacheTTL{}.IsValid())
+	assert.True(t, CacheTTL{Pages: 3600, Redirects: CacheTTLMax}.IsValid())
+	assert.False(t, CacheTTL{Pages: -1}.IsValid())
+	assert.False(t, CacheTTL{Redirects: CacheTTLMax + 1}.IsValid())
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const cursorPrefix = "id:"
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorInput requests the items following the After cursor, ordered by id.
+// An empty cursor starts from the first item.
+type CursorInput struct {
+	After *string
+	Limit *int
+}
+
+func (c *CursorInput) GetLimit() int {
+	if c == nil || c.Limit == nil || *c.Limit <= 0 {
+		return DefaultLimit
+	}
+	return *c.Limit
+}
+
+// GetAfterID returns the id encoded in the After cursor, 0 without cursor
+func (c *CursorInput) GetAfterID() (int64, error) {
+	if c == nil || c.After == nil || *c.After == "" {
+		return 0, nil
+	}
+	return DecodeCursor(*c.After)
+}
+
+// CursorResult is a page of items, NextCursor is nil on the last page
+type CursorResult[T any] struct {
+	Items      []T
+	NextCursor *string
+}
+
+// EncodeCursor returns the opaque cursor of the item having the id
+func EncodeCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.FormatInt(id, 10)))
+}
+
+// DecodeCursor returns the id of the item of a cursor created by EncodeCursor
+func DecodeCursor(cursor string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	value, found := strings.CutPrefix(string(raw), cursorPrefix)
+	if !found {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return id, nil
+}
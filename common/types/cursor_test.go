@@ -0,0 +1,53 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestCursorInput_GetLimit(t *testing.T) {
+	var nilInput *CursorInput
+	assert.Equal(t, DefaultLimit, nilInput.GetLimit())
+	assert.Equal(t, DefaultLimit, (&CursorInput{}).GetLimit())
+	assert.Equal(t, DefaultLimit, (&CursorInput{Limit: intPtr(0)}).GetLimit())
+	assert.Equal(t, 50, (&CursorInput{Limit: intPtr(50)}).GetLimit())
+}
+
+func TestCursorInput_GetAfterID(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   *CursorInput
+		want    int64
+		wantErr error
+	}{
+		{name: "nil receiver", input: nil, want: 0},
+		{name: "nil cursor", input: &CursorInput{}, want: 0},
+		{name: "empty cursor", input: &CursorInput{After: strPtr("")}, want: 0},
+		{name: "valid cursor", input: &CursorInput{After: strPtr(EncodeCursor(42))}, want: 42},
+		{name: "not base64", input: &CursorInput{After: strPtr("%%%")}, wantErr: ErrInvalidCursor},
+		{name: "missing prefix", input: &CursorInput{After: strPtr("NDI")}, wantErr: ErrInvalidCursor},
+		{name: "not a number", input: &CursorInput{After: strPtr("aWQ6YWJj")}, wantErr: ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.input.GetAfterID()
+
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEncodeCursor(t *testing.T) {
+	cursor := EncodeCursor(123456)
+
+	assert.NotContains(t, cursor, "123456")
+	id, err := DecodeCursor(cursor)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123456), id)
+}
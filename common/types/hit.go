@@ -0,0 +1,64 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// HitDateLayout is the layout of the day of a hit
+const HitDateLayout = "2006-01-02"
+
+type HitResourceType string
+
+const (
+	HitResourceTypeRedirect HitResourceType = "redirect"
+	HitResourceTypePage     HitResourceType = "page"
+)
+
+func (t HitResourceType) IsValid() bool {
+	switch t {
+	case HitResourceTypeRedirect, HitResourceTypePage:
+		return true
+	default:
+		return false
+	}
+}
+
+// Hit is the number of requests served by a redirect or a page, identified by its id
+type Hit struct {
+	Type  HitResourceType `json:"type"`
+	ID    int64           `json:"id"`
+	Count int64           `json:"count"`
+	// Date is the day of the requests formatted as 2006-01-02, the day the hits are received when empty
+	Date string `json:"date,omitempty"`
+}
+
+// HitBatch is a batch of hit counts sent by an agent
+type HitBatch struct {
+	Hits []Hit `json:"hits"`
+}
+
+// Day returns the day of the hit, the UTC day of now when it has no date
+func (h Hit) Day(now time.Time) (time.Time, error) {
+	if h.Date == "" {
+		now = now.UTC()
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Parse(HitDateLayout, h.Date)
+}
+
+func ValidateHit(hit Hit) error {
+	if !hit.Type.IsValid() {
+		return fmt.Errorf("invalid hit type: %s", hit.Type)
+	}
+	if hit.ID <= 0 {
+		return fmt.Errorf("invalid hit id: %d", hit.ID)
+	}
+	if hit.Count < 0 {
+		return fmt.Errorf("invalid hit count: %d", hit.Count)
+	}
+	if _, err := hit.Day(time.Now()); err != nil {
+		return fmt.Errorf("invalid hit date: %s", hit.Date)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHitResourceType_IsValid(t *testing.T) {
+	assert.True(t, HitResourceTypeRedirect.IsValid())
+	assert.True(t, HitResourceTypePage.IsValid())
+	assert.False(t, HitResourceType("agent").IsValid())
+	assert.False(t, HitResourceType("").IsValid())
+}
+
+func TestHit_Day(t *testing.T) {
+	now := time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC)
+
+	t.Run("defaults to now", func(t *testing.T) {
+		day, err := Hit{}.Day(now)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), day)
+	})
+
+	t.Run("parses date", func(t *testing.T) {
+		day, err := Hit{Date: "2026-02-28"}.Day(now)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), day)
+	})
+
+	t.Run("invalid date", func(t *testing.T) {
+		_, err := Hit{Date: "28/02/2026"}.Day(now)
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateHit(t *testing.T) {
+	tests := []struct {
+		name    string
+		hit     Hit
+		wantErr string
+	}{
+		{name: "valid redirect hit", hit: Hit{Type: HitResourceTypeRedirect, ID: 1, Count: 10}},
+		{name: "valid page hit with date", hit: Hit{Type: HitResourceTypePage, ID: 2, Count: 0, Date: "2026-01-01"}},
+		{name: "invalid type", hit: Hit{Type: "agent", ID: 1, Count: 1}, wantErr: "invalid hit type"},
+		{name: "invalid id", hit: Hit{Type: HitResourceTypeRedirect, Count: 1}, wantErr: "invalid hit id"},
+		{name: "negative count", hit: Hit{Type: HitResourceTypeRedirect, ID: 1, Count: -1}, wantErr: "invalid hit count"},
+		{name: "invalid date", hit: Hit{Type: HitResourceTypeRedirect, ID: 1, Count: 1, Date: "yesterday"}, wantErr: "invalid hit date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHit(tt.hit)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
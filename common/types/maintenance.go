@@ -0,0 +1,13 @@
+package types
+
+// HeaderMaintenance is the header of the version response telling the agents whether the project is in maintenance,
+// the maintenance mode changing without a new version
+const HeaderMaintenance = "X-Flecto-Maintenance"
+
+// Maintenance is the maintenance mode of a project, sent to the agents with the redirects. While enabled,
+// the agents serve the page at PagePath for all the requests instead of the redirects and pages
+type Maintenance struct {
+	Enabled bool `json:"enabled" gorm:"default:false;not null"`
+	// PagePath is the path of the published page of the project served during the maintenance
+	PagePath string `json:"pagePath" gorm:"size:600;default:'';not null"`
+}
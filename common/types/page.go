@@ -1,5 +1,7 @@
 package types
 
+import "encoding/base64"
+
 type PageType string
 
 const (
@@ -12,13 +14,24 @@ type PageContentType string
 const (
 	PageContentTypeTextPlain PageContentType = "TEXT_PLAIN"
 	PageContentTypeXML       PageContentType = "XML"
+	// PageContentTypeBinary pages hold base64 encoded content served with their MimeType
+	PageContentTypeBinary PageContentType = "BINARY"
+	// PageContentTypeMarkdown pages are authored in markdown, their content is the HTML rendered from their Source
+	PageContentTypeMarkdown PageContentType = "MARKDOWN"
 )
 
 type Page struct {
+	// ID is the id of the page in the manager, agents use it to report hits
+	ID          int64           `json:"id,omitempty" gorm:"-"`
 	Type        PageType        `json:"type" gorm:"size:50"`
 	Path        string          `json:"path" gorm:"size:600"`
 	Content     string          `json:"content"`
 	ContentType PageContentType `json:"contentType" gorm:"size:50"`
+	MimeType    string          `json:"mimeType,omitempty" gorm:"size:100"`
+	// Source is the markdown of the MARKDOWN pages, the agents only receive the rendered Content
+	Source string `json:"source,omitempty" gorm:"type:longtext"`
+	// Headers are added to the page response
+	Headers ResponseHeaders `json:"headers,omitempty" gorm:"type:text;serializer:json"`
 }
 
 func (p Page) HTTPContentType() string {
@@ -27,16 +40,55 @@ func (p Page) HTTPContentType() string {
 		return "text/plain"
 	case PageContentTypeXML:
 		return "application/xml"
+	case PageContentTypeMarkdown:
+		return "text/html"
+	case PageContentTypeBinary:
+		if p.MimeType != "" {
+			return p.MimeType
+		}
+		return "application/octet-stream"
 	default:
 		return "text/plain"
 	}
 }
 
+// IsBinary returns true when the content of the page is base64 encoded
+func (p Page) IsBinary() bool {
+	return p.ContentType == PageContentTypeBinary
+}
+
+// Body returns the bytes served for the page, decoding the content of binary pages
+func (p Page) Body() ([]byte, error) {
+	if p.IsBinary() {
+		return base64.StdEncoding.DecodeString(p.Content)
+	}
+	return []byte(p.Content), nil
+}
+
+// BodySize returns the size in bytes of the body served for the page
+func (p Page) BodySize() int64 {
+	if p.IsBinary() {
+		return int64(base64.StdEncoding.DecodedLen(len(p.Content)) - base64Padding(p.Content))
+	}
+	return int64(len(p.Content))
+}
+
+// base64Padding returns the number of padding characters ending a base64 content
+func base64Padding(content string) int {
+	n := 0
+	for i := len(content) - 1; i >= 0 && n < 2 && content[i] == '='; i-- {
+		n++
+	}
+	return n
+}
+
 type PageList struct {
 	Items  []Page
 	Total  int
 	Limit  int
 	Offset int
+	// CacheTTL are the cache durations hinted by the project of the pages
+	CacheTTL CacheTTL
 }
 
 func (pl PageList) HasMore() bool {
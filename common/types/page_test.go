@@ -22,6 +22,16 @@ func TestPage_HTTPContentType(t *testing.T) {
 			contentType: PageContentTypeXML,
 			want:        "application/xml",
 		},
+		{
+			name:        "markdown returns text/html",
+			contentType: PageContentTypeMarkdown,
+			want:        "text/html",
+		},
+		{
+			name:        "binary without mime type returns application/octet-stream",
+			contentType: PageContentTypeBinary,
+			want:        "application/octet-stream",
+		},
 		{
 			name:        "unknown content type returns text/plain by default",
 			contentType: PageContentType("UNKNOWN"),
@@ -38,6 +48,46 @@ func TestPage_HTTPContentType(t *testing.T) {
 	}
 }
 
+func TestPage_HTTPContentType_BinaryMimeType(t *testing.T) {
+	p := Page{ContentType: PageContentTypeBinary, MimeType: "image/png"}
+	assert.Equal(t, "image/png", p.HTTPContentType())
+
+	p = Page{ContentType: PageContentTypeTextPlain, MimeType: "image/png"}
+	assert.Equal(t, "text/plain", p.HTTPContentType())
+}
+
+func TestPage_Body(t *testing.T) {
+	body, err := Page{ContentType: PageContentTypeTextPlain, Content: "User-agent: *"}.Body()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("User-agent: *"), body)
+
+	body, err = Page{ContentType: PageContentTypeBinary, Content: "AAEC/w=="}.Body()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x00, 0x01, 0x02, 0xff}, body)
+
+	_, err = Page{ContentType: PageContentTypeBinary, Content: "not base64!"}.Body()
+	assert.Error(t, err)
+}
+
+func TestPage_BodySize(t *testing.T) {
+	tests := []struct {
+		name string
+		page Page
+		want int64
+	}{
+		{name: "text", page: Page{ContentType: PageContentTypeTextPlain, Content: "héllo"}, want: 6},
+		{name: "binary without padding", page: Page{ContentType: PageContentTypeBinary, Content: "AAEC"}, want: 3},
+		{name: "binary with one padding", page: Page{ContentType: PageContentTypeBinary, Content: "AAECAwQ="}, want: 5},
+		{name: "binary with two padding", page: Page{ContentType: PageContentTypeBinary, Content: "AAEC/w=="}, want: 4},
+		{name: "empty binary", page: Page{ContentType: PageContentTypeBinary}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.page.BodySize())
+		})
+	}
+}
+
 func TestPageList_HasMore(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -6,8 +6,9 @@ const (
 )
 
 type PaginationInput struct {
-	Limit  *int `query:"limit"`
-	Offset *int `query:"offset"`
+	Limit   *int `query:"limit"`
+	Offset  *int `query:"offset"`
+	OrderBy []SortInput
 }
 
 func (p *PaginationInput) GetLimit() int {
@@ -24,6 +25,13 @@ func (p *PaginationInput) GetOffset() int {
 	return *p.Offset
 }
 
+func (p *PaginationInput) GetOrderBy() []SortInput {
+	if p == nil {
+		return nil
+	}
+	return p.OrderBy
+}
+
 type PaginatedResult[T any] struct {
 	Items  []T
 	Total  int
@@ -151,3 +151,12 @@ func TestPaginatedResult_HasMore(t *testing.T) {
 		})
 	}
 }
+
+func TestPaginationInput_GetOrderBy(t *testing.T) {
+	var nilInput *PaginationInput
+	assert.Nil(t, nilInput.GetOrderBy())
+	assert.Nil(t, (&PaginationInput{}).GetOrderBy())
+
+	orderBy := []SortInput{{Column: "updatedAt", Direction: SortDESC}, {Column: "source", Direction: SortASC}}
+	assert.Equal(t, orderBy, (&PaginationInput{OrderBy: orderBy}).GetOrderBy())
+}
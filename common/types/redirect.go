@@ -1,5 +1,7 @@
 package types
 
+import "time"
+
 type RedirectType string
 
 const (
@@ -7,6 +9,9 @@ const (
 	RedirectTypeBasicHost RedirectType = "BASIC_HOST"
 	RedirectTypeRegex     RedirectType = "REGEX"
 	RedirectTypeRegexHost RedirectType = "REGEX_HOST"
+	// RedirectTypeCatchAll applies to the requests matched by no other redirect, a project having at most one.
+	// It has no source and no conditions.
+	RedirectTypeCatchAll RedirectType = "CATCH_ALL"
 )
 
 type RedirectStatus string
@@ -19,10 +24,34 @@ const (
 )
 
 type Redirect struct {
+	// ID is the id of the redirect in the manager, agents use it to report hits
+	ID     int64          `json:"id,omitempty" gorm:"-"`
 	Type   RedirectType   `json:"type" gorm:"size:50"`
 	Source string         `json:"source" gorm:"size:600"`
 	Target string         `json:"target" gorm:"size:2048"`
 	Status RedirectStatus `json:"status" gorm:"size:50"`
+	// Priority orders the evaluation of the redirects, the highest first
+	Priority   int        `json:"priority,omitempty" gorm:"default:0;not null"`
+	ValidFrom  *time.Time `json:"validFrom,omitempty" gorm:"type:timestamp"`
+	ValidUntil *time.Time `json:"validUntil,omitempty" gorm:"type:timestamp"`
+	// Conditions restrict the redirect to the requests fulfilling all of them
+	Conditions []RedirectCondition `json:"conditions,omitempty" gorm:"type:text;serializer:json"`
+	// Targets split the requests between weighted targets, Target being one of them and served by the agents
+	// not splitting the requests
+	Targets []RedirectTarget `json:"targets,omitempty" gorm:"type:text;serializer:json"`
+	// Headers are added to the redirect response
+	Headers ResponseHeaders `json:"headers,omitempty" gorm:"type:text;serializer:json"`
+}
+
+// IsActiveAt returns true when t is within the validity period of the redirect, bounds being optional
+func (r Redirect) IsActiveAt(t time.Time) bool {
+	if r.ValidFrom != nil && t.Before(*r.ValidFrom) {
+		return false
+	}
+	if r.ValidUntil != nil && !t.Before(*r.ValidUntil) {
+		return false
+	}
+	return true
 }
 
 func (r Redirect) HTTPCode() int {
@@ -45,6 +74,12 @@ type RedirectList struct {
 	Total  int
 	Limit  int
 	Offset int
+	// Options are the matching options of the project of the redirects
+	Options RedirectOptions
+	// Maintenance is the maintenance mode of the project of the redirects
+	Maintenance Maintenance
+	// CacheTTL are the cache durations hinted by the project of the redirects
+	CacheTTL CacheTTL
 }
 
 func (rl RedirectList) HasMore() bool {
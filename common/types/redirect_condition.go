@@ -0,0 +1,183 @@
+package types
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type RedirectConditionType string
+
+const (
+	RedirectConditionTypeQuery  RedirectConditionType = "QUERY"
+	RedirectConditionTypeHeader RedirectConditionType = "HEADER"
+	RedirectConditionTypeHost   RedirectConditionType = "HOST"
+	// RedirectConditionTypeCountry is fulfilled by the requests from one of the countries of the values,
+	// ISO 3166-1 alpha-2 codes
+	RedirectConditionTypeCountry RedirectConditionType = "COUNTRY"
+	// RedirectConditionTypeLanguage is fulfilled by the requests whose preferred language in the
+	// Accept-Language header starts with one of the language tags of the values
+	RedirectConditionTypeLanguage RedirectConditionType = "LANGUAGE"
+)
+
+// RedirectCondition restricts a redirect to the requests having a query parameter, a header or a host,
+// or coming from a country or preferring a language.
+// An empty value only requires the query parameter or the header to be present.
+type RedirectCondition struct {
+	Type  RedirectConditionType `json:"type"`
+	Name  string                `json:"name,omitempty"`
+	Value string                `json:"value,omitempty"`
+	// Values are the countries or the languages of the COUNTRY and LANGUAGE conditions
+	Values []string `json:"values,omitempty"`
+}
+
+// RedirectRequest holds the parts of a request used to match a redirect
+type RedirectRequest struct {
+	Host   string
+	URI    string
+	Header http.Header
+	// Country is the ISO 3166-1 alpha-2 code of the country of the client, resolved by the agent,
+	// empty when unknown
+	Country string
+}
+
+// Path returns the request URI without its query string
+func (r RedirectRequest) Path() string {
+	path, _, _ := strings.Cut(r.URI, "?")
+	return path
+}
+
+// Query returns the query parameters of the request URI
+func (r RedirectRequest) Query() url.Values {
+	_, rawQuery, found := strings.Cut(r.URI, "?")
+	if !found {
+		return url.Values{}
+	}
+	query, _ := url.ParseQuery(rawQuery)
+	return query
+}
+
+// Matches returns true when the condition is fulfilled by the request, the country conditions never being
+// fulfilled by the requests from an unknown country
+func (c RedirectCondition) Matches(host, country string, query url.Values, header http.Header) bool {
+	switch c.Type {
+	case RedirectConditionTypeQuery:
+		values, found := query[c.Name]
+		return found && (c.Value == "" || containsString(values, c.Value))
+	case RedirectConditionTypeHeader:
+		values := header.Values(c.Name)
+		return len(values) > 0 && (c.Value == "" || containsString(values, c.Value))
+	case RedirectConditionTypeHost:
+		return strings.EqualFold(host, c.Value)
+	case RedirectConditionTypeCountry:
+		return country != "" && containsString(c.Values, strings.ToUpper(country))
+	case RedirectConditionTypeLanguage:
+		language := PreferredLanguage(header.Get("Accept-Language"))
+		for _, tag := range c.Values {
+			if language == tag || strings.HasPrefix(language, tag+"-") {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+// PreferredLanguage returns the lower case language tag of the highest quality in an Accept-Language header,
+// the first one between tags of the same quality, or an empty string when the header has none
+func PreferredLanguage(acceptLanguage string) string {
+	preferred := ""
+	best := 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > best {
+			preferred, best = tag, quality
+		}
+	}
+	return preferred
+}
+
+// NormalizeRedirectConditions sorts conditions and canonicalizes header names,
+// so that two equivalent lists have the same key. It returns nil for an empty list.
+func NormalizeRedirectConditions(conditions []RedirectCondition) []RedirectCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+	normalized := make([]RedirectCondition, 0, len(conditions))
+	for _, condition := range conditions {
+		switch condition.Type {
+		case RedirectConditionTypeHeader:
+			condition.Name = http.CanonicalHeaderKey(condition.Name)
+		case RedirectConditionTypeHost:
+			condition.Name = ""
+			condition.Value = strings.ToLower(condition.Value)
+		case RedirectConditionTypeCountry:
+			condition.Name, condition.Value = "", ""
+			condition.Values = normalizeConditionValues(condition.Values, strings.ToUpper)
+		case RedirectConditionTypeLanguage:
+			condition.Name, condition.Value = "", ""
+			condition.Values = normalizeConditionValues(condition.Values, strings.ToLower)
+		}
+		normalized = append(normalized, condition)
+	}
+	sort.Slice(normalized, func(i, j int) bool {
+		a, b := normalized[i], normalized[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Value != b.Value {
+			return a.Value < b.Value
+		}
+		return strings.Join(a.Values, ",") < strings.Join(b.Values, ",")
+	})
+	return normalized
+}
+
+// normalizeConditionValues returns the trimmed values in the case of the condition, sorted and without duplicates
+func normalizeConditionValues(values []string, toCase func(string) string) []string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		normalized = append(normalized, toCase(strings.TrimSpace(value)))
+	}
+	sort.Strings(normalized)
+	return slices.Compact(normalized)
+}
+
+// RedirectConditionsKey returns the key identifying a normalized list of conditions, empty when there is none.
+// It is the value stored in database for the conditions.
+func RedirectConditionsKey(conditions []RedirectCondition) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	key, _ := json.Marshal(conditions)
+	return string(key)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectRequest_PathAndQuery(t *testing.T) {
+	req := RedirectRequest{URI: "/page?lang=fr&tag=a&tag=b"}
+	assert.Equal(t, "/page", req.Path())
+	assert.Equal(t, url.Values{"lang": {"fr"}, "tag": {"a", "b"}}, req.Query())
+
+	req = RedirectRequest{URI: "/page"}
+	assert.Equal(t, "/page", req.Path())
+	assert.Equal(t, url.Values{}, req.Query())
+}
+
+func TestRedirectCondition_Matches(t *testing.T) {
+	query := url.Values{"lang": {"fr"}, "tag": {"a", "b"}}
+	header := http.Header{"X-Platform": {"ios"}, "Accept-Language": {"fr-CA,en;q=0.8"}}
+	tests := []struct {
+		name      string
+		condition RedirectCondition
+		want      bool
+	}{
+		{name: "query value", condition: RedirectCondition{Type: RedirectConditionTypeQuery, Name: "lang", Value: "fr"}, want: true},
+		{name: "query one of values", condition: RedirectCondition{Type: RedirectConditionTypeQuery, Name: "tag", Value: "b"}, want: true},
+		{name: "query other value", condition: RedirectCondition{Type: RedirectConditionTypeQuery, Name: "lang", Value: "en"}, want: false},
+		{name: "query presence", condition: RedirectCondition{Type: RedirectConditionTypeQuery, Name: "tag"}, want: true},
+		{name: "query missing", condition: RedirectCondition{Type: RedirectConditionTypeQuery, Name: "page"}, want: false},
+		{name: "header value case insensitive name", condition: RedirectCondition{Type: RedirectConditionTypeHeader, Name: "x-platform", Value: "ios"}, want: true},
+		{name: "header presence", condition: RedirectCondition{Type: RedirectConditionTypeHeader, Name: "X-Platform"}, want: true},
+		{name: "header missing", condition: RedirectCondition{Type: RedirectConditionTypeHeader, Name: "Referer"}, want: false},
+		{name: "host", condition: RedirectCondition{Type: RedirectConditionTypeHost, Value: "EXAMPLE.com"}, want: true},
+		{name: "other host", condition: RedirectCondition{Type: RedirectConditionTypeHost, Value: "other.com"}, want: false},
+		{name: "country", condition: RedirectCondition{Type: RedirectConditionTypeCountry, Values: []string{"BE", "CA"}}, want: true},
+		{name: "other country", condition: RedirectCondition{Type: RedirectConditionTypeCountry, Values: []string{"FR"}}, want: false},
+		{name: "language prefix", condition: RedirectCondition{Type: RedirectConditionTypeLanguage, Values: []string{"de", "fr"}}, want: true},
+		{name: "language tag", condition: RedirectCondition{Type: RedirectConditionTypeLanguage, Values: []string{"fr-ca"}}, want: true},
+		{name: "other language tag", condition: RedirectCondition{Type: RedirectConditionTypeLanguage, Values: []string{"fr-fr"}}, want: false},
+		{name: "not the preferred language", condition: RedirectCondition{Type: RedirectConditionTypeLanguage, Values: []string{"en"}}, want: false},
+		{name: "unknown type", condition: RedirectCondition{Type: "COOKIE", Name: "lang"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.condition.Matches("example.com", "ca", query, header))
+		})
+	}
+
+	t.Run("nil header", func(t *testing.T) {
+		condition := RedirectCondition{Type: RedirectConditionTypeHeader, Name: "X-Platform"}
+		assert.False(t, condition.Matches("example.com", "", query, nil))
+	})
+
+	t.Run("unknown country", func(t *testing.T) {
+		condition := RedirectCondition{Type: RedirectConditionTypeCountry, Values: []string{"FR"}}
+		assert.False(t, condition.Matches("example.com", "", query, header))
+	})
+}
+
+func TestPreferredLanguage(t *testing.T) {
+	assert.Equal(t, "fr-ca", PreferredLanguage("fr-CA,fr;q=0.9,en;q=0.8"))
+	assert.Equal(t, "en", PreferredLanguage("de;q=0.5, en;q=0.9, fr;q=0.9"))
+	assert.Equal(t, "de", PreferredLanguage("*, de;q=0.5"))
+	assert.Equal(t, "", PreferredLanguage("fr;q=0, *"))
+	assert.Equal(t, "", PreferredLanguage(""))
+}
+
+func TestNormalizeRedirectConditions(t *testing.T) {
+	assert.Nil(t, NormalizeRedirectConditions(nil))
+	assert.Nil(t, NormalizeRedirectConditions([]RedirectCondition{}))
+
+	got := NormalizeRedirectConditions([]RedirectCondition{
+		{Type: RedirectConditionTypeQuery, Name: "lang", Value: "fr"},
+		{Type: RedirectConditionTypeHost, Name: "ignored", Value: "Example.com"},
+		{Type: RedirectConditionTypeHeader, Name: "x-platform", Value: "ios"},
+		{Type: RedirectConditionTypeLanguage, Name: "ignored", Values: []string{"FR", "en-GB", "fr"}},
+		{Type: RedirectConditionTypeCountry, Values: []string{" ca", "BE"}},
+	})
+	assert.Equal(t, []RedirectCondition{
+		{Type: RedirectConditionTypeCountry, Values: []string{"BE", "CA"}},
+		{Type: RedirectConditionTypeHeader, Name: "X-Platform", Value: "ios"},
+		{Type: RedirectConditionTypeHost, Value: "example.com"},
+		{Type: RedirectConditionTypeLanguage, Values: []string{"en-gb", "fr"}},
+		{Type: RedirectConditionTypeQuery, Name: "lang", Value: "fr"},
+	}, got)
+}
+
+func TestRedirectConditionsKey(t *testing.T) {
+	assert.Equal(t, "", RedirectConditionsKey(nil))
+	assert.Equal(t, `[{"type":"QUERY","name":"lang","value":"fr"}]`, RedirectConditionsKey([]RedirectCondition{{Type: RedirectConditionTypeQuery, Name: "lang", Value: "fr"}}))
+}
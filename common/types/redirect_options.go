@@ -0,0 +1,159 @@
+package types
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// RedirectOptions are the matching options of the redirects of a project, sent to the agents with the redirects
+type RedirectOptions struct {
+	// CaseInsensitive matches the sources of the BASIC and BASIC_HOST redirects regardless of their case
+	CaseInsensitive bool `json:"caseInsensitive" gorm:"default:false;not null"`
+	// IgnoreTrailingSlash matches the sources of the BASIC and BASIC_HOST redirects with or without a trailing slash
+	IgnoreTrailingSlash bool `json:"ignoreTrailingSlash" gorm:"default:false;not null"`
+	// PreserveQueryString appends the query string of the request to the target of the redirects
+	PreserveQueryString bool `json:"preserveQueryString" gorm:"default:false;not null"`
+	// NormalizeURL matches the sources of the BASIC and BASIC_HOST redirects regardless of their encoding:
+	// percent-encoding, international domain names and duplicate slashes, see NormalizeURL
+	NormalizeURL bool `json:"normalizeUrl" gorm:"default:false;not null"`
+}
+
+// NormalizesSources returns true when the options change the matching of the BASIC and BASIC_HOST sources
+func (o RedirectOptions) NormalizesSources() bool {
+	return o.CaseInsensitive || o.IgnoreTrailingSlash || o.NormalizeURL
+}
+
+// NormalizeSource returns the key under which a BASIC or BASIC_HOST source, or a request, is matched.
+// Two sources having the same key match the same requests.
+func (o RedirectOptions) NormalizeSource(source string) string {
+	if o.NormalizeURL {
+		source = NormalizeURL(source)
+	}
+	if o.CaseInsensitive {
+		source = strings.ToLower(source)
+	}
+	if o.IgnoreTrailingSlash {
+		path, query, found := strings.Cut(source, "?")
+		if len(path) > 1 {
+			path = strings.TrimSuffix(path, "/")
+		}
+		if found {
+			return path + "?" + query
+		}
+		return path
+	}
+	return source
+}
+
+// NormalizeURL returns the canonical form of a BASIC or BASIC_HOST source, or of a request, so that the URLs differing
+// only in their encoding have the same form:
+//   - the host of a BASIC_HOST source is converted to lowercase punycode
+//   - the percent-encoded unreserved characters are decoded, the other escapes are written in uppercase
+//   - the non-ASCII characters, the stray percent signs and the other characters not allowed in an URL are percent-encoded
+//   - the duplicate slashes of the path are collapsed
+func NormalizeURL(source string) string {
+	host, path := "", source
+	if !strings.HasPrefix(source, "/") {
+		if i := strings.IndexByte(source, '/'); i >= 0 {
+			host, path = source[:i], source[i:]
+		} else {
+			host, path = source, ""
+		}
+		if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+			host = ascii
+		} else {
+			host = strings.ToLower(host)
+		}
+	}
+
+	path, query, found := strings.Cut(path, "?")
+	path = normalizeEscapes(path)
+	for strings.Contains(path, "//") {
+		path = strings.ReplaceAll(path, "//", "/")
+	}
+	if found {
+		return host + path + "?" + normalizeEscapes(query)
+	}
+	return host + path
+}
+
+// normalizeEscapes decodes the percent-encoded unreserved characters of an URL part and percent-encodes in
+// uppercase the other escapes and the characters not allowed in an URL
+func normalizeEscapes(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			c = unhex(s[i+1])<<4 | unhex(s[i+2])
+			i += 2
+			if isUnreserved(c) {
+				b.WriteByte(c)
+				continue
+			}
+		} else if c != '%' && !escapedInURL(c) {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
+
+// isUnreserved returns true for the characters an URL never needs to percent-encode
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// escapedInURL returns true for the characters an URL must percent-encode
+func escapedInURL(c byte) bool {
+	return c <= ' ' || c >= 0x7f || strings.IndexByte("\"<>\\^`{|}", c) >= 0
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	}
+	return c - 'A' + 10
+}
+
+// ResolveTarget returns the target of a redirect matching a request of the given URI.
+// With PreserveQueryString, the query string of the request is appended to the target,
+// unless the source of the redirect includes a query string, matched with the rest of the URI.
+func (o RedirectOptions) ResolveTarget(r *Redirect, target, uri string) string {
+	if !o.PreserveQueryString || r.matchesQueryString() {
+		return target
+	}
+	_, rawQuery, found := strings.Cut(uri, "?")
+	if !found || rawQuery == "" {
+		return target
+	}
+	if strings.Contains(target, "?") {
+		return target + "&" + rawQuery
+	}
+	return target + "?" + rawQuery
+}
+
+// IsBasic returns true for the redirect types matching their source exactly, whose sources are normalized by the options
+func (t RedirectType) IsBasic() bool {
+	return t == RedirectTypeBasic || t == RedirectTypeBasicHost
+}
+
+// matchesQueryString returns true when the source of the redirect includes a query string
+func (r Redirect) matchesQueryString() bool {
+	if r.Type.IsBasic() {
+		return strings.Contains(r.Source, "?")
+	}
+	return strings.Contains(r.Source, `\?`)
+}
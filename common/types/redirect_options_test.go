@@ -0,0 +1,81 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectOptions_NormalizeSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		options RedirectOptions
+		source  string
+		want    string
+	}{
+		{name: "no options", options: RedirectOptions{}, source: "/About/", want: "/About/"},
+		{name: "case insensitive", options: RedirectOptions{CaseInsensitive: true}, source: "/About/", want: "/about/"},
+		{name: "trailing slash", options: RedirectOptions{IgnoreTrailingSlash: true}, source: "/About/", want: "/About"},
+		{name: "trailing slash before query", options: RedirectOptions{IgnoreTrailingSlash: true}, source: "/about/?a=b/", want: "/about?a=b/"},
+		{name: "root kept", options: RedirectOptions{IgnoreTrailingSlash: true}, source: "/", want: "/"},
+		{name: "host", options: RedirectOptions{CaseInsensitive: true, IgnoreTrailingSlash: true}, source: "Example.com/Shop/", want: "example.com/shop"},
+		{name: "url", options: RedirectOptions{NormalizeURL: true, IgnoreTrailingSlash: true}, source: "Bücher.example/a//caf%c3%a9/", want: "xn--bcher-kva.example/a/caf%C3%A9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.options.NormalizeSource(tt.source))
+		})
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "/about", want: "/about"},
+		{source: "/caf%c3%a9", want: "/caf%C3%A9"},
+		{source: "/café", want: "/caf%C3%A9"},
+		{source: "/%7Euser/%41bc", want: "/~user/Abc"},
+		{source: "/a%2Fb", want: "/a%2Fb"},
+		{source: "/a b/<c>", want: "/a%20b/%3Cc%3E"},
+		{source: "//a///b/", want: "/a/b/"},
+		{source: "/search?q=caf%c3%a9&path=//x", want: "/search?q=caf%C3%A9&path=//x"},
+		{source: "/100%", want: "/100%25"},
+		{source: "/%zz", want: "/%25zz"},
+		{source: "Bücher.Example/Shop", want: "xn--bcher-kva.example/Shop"},
+		{source: "xn--bcher-kva.example/shop", want: "xn--bcher-kva.example/shop"},
+		{source: "example.com", want: "example.com"},
+		{source: "bad_host!.com/a", want: "bad_host!.com/a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeURL(tt.source))
+		})
+	}
+}
+
+func TestRedirectOptions_ResolveTarget(t *testing.T) {
+	basic := &Redirect{Type: RedirectTypeBasic, Source: "/old"}
+	tests := []struct {
+		name     string
+		options  RedirectOptions
+		redirect *Redirect
+		target   string
+		uri      string
+		want     string
+	}{
+		{name: "not preserved", options: RedirectOptions{}, redirect: basic, target: "/new", uri: "/old?a=1", want: "/new"},
+		{name: "preserved", options: RedirectOptions{PreserveQueryString: true}, redirect: basic, target: "/new", uri: "/old?a=1", want: "/new?a=1"},
+		{name: "merged", options: RedirectOptions{PreserveQueryString: true}, redirect: basic, target: "/new?b=2", uri: "/old?a=1", want: "/new?b=2&a=1"},
+		{name: "no query", options: RedirectOptions{PreserveQueryString: true}, redirect: basic, target: "/new", uri: "/old?", want: "/new"},
+		{name: "query in basic source", options: RedirectOptions{PreserveQueryString: true}, redirect: &Redirect{Type: RedirectTypeBasic, Source: "/old?a=1"}, target: "/new", uri: "/old?a=1", want: "/new"},
+		{name: "query in regex source", options: RedirectOptions{PreserveQueryString: true}, redirect: &Redirect{Type: RedirectTypeRegex, Source: `^/old\?a=(.*)$`}, target: "/new", uri: "/old?a=1", want: "/new"},
+		{name: "optional regex", options: RedirectOptions{PreserveQueryString: true}, redirect: &Redirect{Type: RedirectTypeRegex, Source: "^/old/?"}, target: "/new", uri: "/old?a=1", want: "/new?a=1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.options.ResolveTarget(tt.redirect, tt.target, tt.uri))
+		})
+	}
+}
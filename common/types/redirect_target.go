@@ -0,0 +1,23 @@
+package types
+
+// RedirectTargetsWeight is the sum of the weights of the targets of a redirect
+const RedirectTargetsWeight = 100
+
+// RedirectTarget is one of the weighted targets of a redirect splitting its requests
+type RedirectTarget struct {
+	Target string `json:"target"`
+	// Weight is the percentage of the requests sent to the target
+	Weight int `json:"weight"`
+}
+
+// SplitTarget returns the target of a request drawn at roll, in [0, RedirectTargetsWeight), among the weighted
+// targets of the redirect. It returns Target when the redirect has a single target.
+func (r Redirect) SplitTarget(roll int) string {
+	for _, target := range r.Targets {
+		if roll < target.Weight {
+			return target.Target
+		}
+		roll -= target.Weight
+	}
+	return r.Target
+}
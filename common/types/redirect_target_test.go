@@ -0,0 +1,27 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirect_SplitTarget(t *testing.T) {
+	redirect := Redirect{
+		Target:  "/new",
+		Targets: []RedirectTarget{{Target: "/new", Weight: 90}, {Target: "/beta", Weight: 10}},
+	}
+
+	assert.Equal(t, "/new", redirect.SplitTarget(0))
+	assert.Equal(t, "/new", redirect.SplitTarget(89))
+	assert.Equal(t, "/beta", redirect.SplitTarget(90))
+	assert.Equal(t, "/beta", redirect.SplitTarget(99))
+
+	counts := map[string]int{}
+	for roll := 0; roll < RedirectTargetsWeight; roll++ {
+		counts[redirect.SplitTarget(roll)]++
+	}
+	assert.Equal(t, map[string]int{"/new": 90, "/beta": 10}, counts)
+
+	assert.Equal(t, "/single", Redirect{Target: "/single"}.SplitTarget(42))
+}
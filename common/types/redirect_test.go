@@ -2,6 +2,7 @@ package types
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -116,4 +117,29 @@ func TestRedirectList_HasMore(t *testing.T) {
 			assert.Equal(t, tt.want, got)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestRedirect_IsActiveAt(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		redirect Redirect
+		want     bool
+	}{
+		{name: "no validity period", redirect: Redirect{}, want: true},
+		{name: "started", redirect: Redirect{ValidFrom: &before}, want: true},
+		{name: "not started", redirect: Redirect{ValidFrom: &after}, want: false},
+		{name: "not expired", redirect: Redirect{ValidUntil: &after}, want: true},
+		{name: "expired", redirect: Redirect{ValidUntil: &before}, want: false},
+		{name: "expires now", redirect: Redirect{ValidUntil: &now}, want: false},
+		{name: "within period", redirect: Redirect{ValidFrom: &before, ValidUntil: &after}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.redirect.IsActiveAt(now))
+		})
+	}
+}
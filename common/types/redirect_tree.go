@@ -1,10 +1,13 @@
 package types
 
 import (
+	"net/http"
+	"net/url"
 	"regexp"
 	"regexp/syntax"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/armon/go-radix"
 )
@@ -18,12 +21,48 @@ type regexBucket struct {
 	redirects []*compiledRedirect
 }
 
+// basicBucket holds the redirects sharing a source, the ones with the highest priority then the most conditions first
+type basicBucket struct {
+	redirects []*compiledRedirect
+}
+
+// matchContext holds the request data checked against the validity period and the conditions of the redirects
+type matchContext struct {
+	host    string
+	country string
+	query   url.Values
+	header  http.Header
+	now     time.Time
+	// preserveQuery matches the redirects without query string in their source on the path of the request
+	preserveQuery bool
+}
+
+// matchesPath returns true when the redirect is matched on the request URI without its query string
+func (cr *compiledRedirect) matchesPath(mc *matchContext) bool {
+	return len(cr.Conditions) > 0 || (mc.preserveQuery && !cr.matchesQueryString())
+}
+
+func (cr *compiledRedirect) accepts(mc *matchContext) bool {
+	if !cr.IsActiveAt(mc.now) {
+		return false
+	}
+	for _, condition := range cr.Conditions {
+		if !condition.Matches(mc.host, mc.country, mc.query, mc.header) {
+			return false
+		}
+	}
+	return true
+}
+
 type RedirectTreeMatcher interface {
 	Insert(r *Redirect) error
 	Match(host, uri string) (*Redirect, string)
+	MatchRequest(req RedirectRequest) (*Redirect, string)
 }
 
 type RedirectTree struct {
+	options RedirectOptions
+
 	basicHost *radix.Tree
 	basic     *radix.Tree
 
@@ -31,10 +70,18 @@ type RedirectTree struct {
 	regex         *radix.Tree
 	regexHostRoot []*compiledRedirect
 	regexRoot     []*compiledRedirect
+	// catchAll is evaluated when no other redirect matches, inserting another catch-all replaces it
+	catchAll *compiledRedirect
 }
 
 func NewRedirectTreeMatcher() RedirectTreeMatcher {
+	return NewRedirectTreeMatcherWithOptions(RedirectOptions{})
+}
+
+// NewRedirectTreeMatcherWithOptions returns a matcher applying the matching options of a project
+func NewRedirectTreeMatcherWithOptions(options RedirectOptions) RedirectTreeMatcher {
 	return &RedirectTree{
+		options:       options,
 		basicHost:     radix.New(),
 		basic:         radix.New(),
 		regexHost:     radix.New(),
@@ -47,10 +94,13 @@ func NewRedirectTreeMatcher() RedirectTreeMatcher {
 func (rt *RedirectTree) Insert(r *Redirect) error {
 	switch r.Type {
 	case RedirectTypeBasicHost:
-		rt.basicHost.Insert(r.Source, &compiledRedirect{Redirect: r})
+		insertBasic(rt.basicHost, rt.options.NormalizeSource(r.Source), &compiledRedirect{Redirect: r})
 
 	case RedirectTypeBasic:
-		rt.basic.Insert(r.Source, &compiledRedirect{Redirect: r})
+		insertBasic(rt.basic, rt.options.NormalizeSource(r.Source), &compiledRedirect{Redirect: r})
+
+	case RedirectTypeCatchAll:
+		rt.catchAll = &compiledRedirect{Redirect: r}
 
 	case RedirectTypeRegexHost, RedirectTypeRegex:
 		re, err := regexp.Compile(r.Source)
@@ -82,31 +132,109 @@ func (rt *RedirectTree) Insert(r *Redirect) error {
 	return nil
 }
 
+// insertBasic adds a redirect to the bucket of its normalized source.
+// A redirect with the same conditions as an existing one replaces it.
+func insertBasic(tree *radix.Tree, source string, cr *compiledRedirect) {
+	val, found := tree.Get(source)
+	if !found {
+		tree.Insert(source, &basicBucket{redirects: []*compiledRedirect{cr}})
+		return
+	}
+	bucket := val.(*basicBucket)
+	key := RedirectConditionsKey(NormalizeRedirectConditions(cr.Conditions))
+	for i, existing := range bucket.redirects {
+		if RedirectConditionsKey(NormalizeRedirectConditions(existing.Conditions)) == key {
+			bucket.redirects[i] = cr
+			return
+		}
+	}
+	bucket.redirects = append(bucket.redirects, cr)
+	sort.SliceStable(bucket.redirects, func(i, j int) bool {
+		if bucket.redirects[i].Priority != bucket.redirects[j].Priority {
+			return bucket.redirects[i].Priority > bucket.redirects[j].Priority
+		}
+		return len(bucket.redirects[i].Conditions) > len(bucket.redirects[j].Conditions)
+	})
+}
+
+// Match returns the redirect matching the host and uri and its resolved target.
+// Header, language and country conditions are never fulfilled, use MatchRequest to check them.
 func (rt *RedirectTree) Match(host, uri string) (*Redirect, string) {
-	hostURI := host + uri
+	return rt.MatchRequest(RedirectRequest{Host: host, URI: uri})
+}
 
-	if val, found := rt.basicHost.Get(hostURI); found {
-		cr := val.(*compiledRedirect)
+// MatchRequest returns the redirect matching the request and its resolved target.
+// Redirects outside of their validity period or whose conditions are not fulfilled are ignored.
+// Redirects without conditions match the full request URI, redirects with conditions match
+// the URI without its query string, the query parameters being checked by the conditions.
+// With the PreserveQueryString option, redirects without query string in their source also match
+// the URI without its query string, which is then appended to the target.
+func (rt *RedirectTree) MatchRequest(req RedirectRequest) (*Redirect, string) {
+	r, target := rt.matchRequest(req)
+	if r == nil {
+		return nil, ""
+	}
+	return r, rt.options.ResolveTarget(r, target, req.URI)
+}
+
+func (rt *RedirectTree) matchRequest(req RedirectRequest) (*Redirect, string) {
+	mc := &matchContext{
+		host:          req.Host,
+		country:       req.Country,
+		query:         req.Query(),
+		header:        req.Header,
+		now:           time.Now(),
+		preserveQuery: rt.options.PreserveQueryString,
+	}
+	uri := req.URI
+	path := req.Path()
+
+	normalize := rt.options.NormalizeSource
+	if cr := matchBasic(rt.basicHost, normalize(req.Host+uri), normalize(req.Host+path), mc); cr != nil {
 		return cr.Redirect, cr.Target
 	}
 
-	if val, found := rt.basic.Get(uri); found {
-		cr := val.(*compiledRedirect)
+	if cr := matchBasic(rt.basic, normalize(uri), normalize(path), mc); cr != nil {
 		return cr.Redirect, cr.Target
 	}
 
-	if r, target := rt.matchRegex(rt.regexHost, rt.regexHostRoot, hostURI); r != nil {
+	if r, target := rt.matchRegex(rt.regexHost, rt.regexHostRoot, req.Host+uri, req.Host+path, mc); r != nil {
 		return r, target
 	}
 
-	if r, target := rt.matchRegex(rt.regex, rt.regexRoot, uri); r != nil {
+	if r, target := rt.matchRegex(rt.regex, rt.regexRoot, uri, path, mc); r != nil {
 		return r, target
 	}
 
+	if rt.catchAll != nil && rt.catchAll.accepts(mc) {
+		return rt.catchAll.Redirect, rt.catchAll.Target
+	}
+
 	return nil, ""
 }
 
-func (rt *RedirectTree) matchRegex(tree *radix.Tree, rootBucket []*compiledRedirect, input string) (*Redirect, string) {
+func matchBasic(tree *radix.Tree, input, pathInput string, mc *matchContext) *compiledRedirect {
+	if val, found := tree.Get(input); found {
+		for _, cr := range val.(*basicBucket).redirects {
+			if cr.accepts(mc) {
+				return cr
+			}
+		}
+	}
+	if pathInput == input {
+		return nil
+	}
+	if val, found := tree.Get(pathInput); found {
+		for _, cr := range val.(*basicBucket).redirects {
+			if cr.matchesPath(mc) && cr.accepts(mc) {
+				return cr
+			}
+		}
+	}
+	return nil
+}
+
+func (rt *RedirectTree) matchRegex(tree *radix.Tree, rootBucket []*compiledRedirect, input, pathInput string, mc *matchContext) (*Redirect, string) {
 	var candidates []*compiledRedirect
 
 	tree.WalkPrefix(input[:minInt(len(input), 1)], func(prefix string, val interface{}) bool {
@@ -119,18 +247,27 @@ func (rt *RedirectTree) matchRegex(tree *radix.Tree, rootBucket []*compiledRedir
 
 	candidates = append(candidates, rootBucket...)
 
-	sortBySourceLength(candidates)
+	sortRegexCandidates(candidates)
 
 	for _, cr := range candidates {
-		if matches := cr.regex.FindStringSubmatch(input); matches != nil {
-			target := resolveTarget(cr.Target, matches)
+		if !cr.accepts(mc) {
+			continue
+		}
+		candidateInput := input
+		if cr.matchesPath(mc) {
+			candidateInput = pathInput
+		}
+		if matches := cr.regex.FindStringSubmatch(candidateInput); matches != nil {
+			target := ResolveTarget(cr.Target, matches)
 			return cr.Redirect, target
 		}
 	}
 
 	return nil, ""
 }
-func resolveTarget(target string, matches []string) string {
+
+// ResolveTarget replaces the $1 to $9 placeholders of the target of a regex redirect with the groups matched by its source
+func ResolveTarget(target string, matches []string) string {
 	result := target
 	for i := len(matches) - 1; i >= 1; i-- {
 		placeholder := "$" + string(rune('0'+i))
@@ -150,15 +287,20 @@ func extractRegexPrefix(pattern string) string {
 	return extractLiteralPrefix(re)
 }
 
+// extractLiteralPrefix returns the literal the regex starts with, case-insensitive literals
+// being excluded since the prefix tree is case-sensitive
 func extractLiteralPrefix(re *syntax.Regexp) string {
 	switch re.Op {
 	case syntax.OpLiteral:
+		if re.Flags&syntax.FoldCase != 0 {
+			return ""
+		}
 		return string(re.Rune)
 
 	case syntax.OpConcat:
 		var prefix strings.Builder
 		for _, sub := range re.Sub {
-			if sub.Op == syntax.OpLiteral {
+			if sub.Op == syntax.OpLiteral && sub.Flags&syntax.FoldCase == 0 {
 				prefix.WriteString(string(sub.Rune))
 			} else if sub.Op == syntax.OpCapture && len(sub.Sub) > 0 {
 				inner := extractLiteralPrefix(sub.Sub[0])
@@ -188,8 +330,19 @@ func minInt(a, b int) int {
 	return b
 }
 
-func sortBySourceLength(candidates []*compiledRedirect) {
-	sort.Slice(candidates, func(i, j int) bool {
-		return len(candidates[i].Source) > len(candidates[j].Source)
+// sortRegexCandidates orders the regex redirects to evaluate: the highest priority first, then the longest
+// source, the most conditions and the lowest id, so that the result does not depend on the insertion order
+func sortRegexCandidates(candidates []*compiledRedirect) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Priority != candidates[j].Priority {
+			return candidates[i].Priority > candidates[j].Priority
+		}
+		if len(candidates[i].Source) != len(candidates[j].Source) {
+			return len(candidates[i].Source) > len(candidates[j].Source)
+		}
+		if len(candidates[i].Conditions) != len(candidates[j].Conditions) {
+			return len(candidates[i].Conditions) > len(candidates[j].Conditions)
+		}
+		return candidates[i].ID < candidates[j].ID
 	})
 }
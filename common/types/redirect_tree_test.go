@@ -1,8 +1,10 @@
 package types
 
 import (
+	"net/http"
 	"regexp/syntax"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -115,6 +117,8 @@ func TestRedirectTree_Insert(t *testing.T) {
 }
 
 func TestRedirectTree_Match(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
 	tests := []struct {
 		name         string
 		redirects    []*Redirect
@@ -133,6 +137,46 @@ func TestRedirectTree_Match(t *testing.T) {
 			wantRedirect: true,
 			wantTarget:   "/new-page",
 		},
+		{
+			name: "no match for expired basic redirect",
+			redirects: []*Redirect{
+				{Type: RedirectTypeBasic, Source: "/old-page", Target: "/new-page", Status: RedirectStatusMovedPermanent, ValidUntil: &past},
+			},
+			host:         "example.com",
+			uri:          "/old-page",
+			wantRedirect: false,
+		},
+		{
+			name: "no match for basic host redirect not yet valid",
+			redirects: []*Redirect{
+				{Type: RedirectTypeBasicHost, Source: "example.com/old-page", Target: "/new-page", Status: RedirectStatusMovedPermanent, ValidFrom: &future},
+			},
+			host:         "example.com",
+			uri:          "/old-page",
+			wantRedirect: false,
+		},
+		{
+			name: "expired basic redirect falls back to regex",
+			redirects: []*Redirect{
+				{Type: RedirectTypeBasic, Source: "/old-page", Target: "/new-page", Status: RedirectStatusMovedPermanent, ValidUntil: &past},
+				{Type: RedirectTypeRegex, Source: "^/old-(.*)$", Target: "/fallback-$1", Status: RedirectStatusMovedPermanent},
+			},
+			host:         "example.com",
+			uri:          "/old-page",
+			wantRedirect: true,
+			wantTarget:   "/fallback-page",
+		},
+		{
+			name: "inactive regex redirect is skipped",
+			redirects: []*Redirect{
+				{Type: RedirectTypeRegex, Source: "^/product/([0-9]+)$", Target: "/item/$1", Status: RedirectStatusMovedPermanent, ValidFrom: &past, ValidUntil: &past},
+				{Type: RedirectTypeRegex, Source: "^/product/(.*)", Target: "/other/$1", Status: RedirectStatusMovedPermanent, ValidFrom: &past, ValidUntil: &future},
+			},
+			host:         "example.com",
+			uri:          "/product/123",
+			wantRedirect: true,
+			wantTarget:   "/other/123",
+		},
 		{
 			name: "match basic host redirect",
 			redirects: []*Redirect{
@@ -247,6 +291,59 @@ func TestRedirectTree_Match(t *testing.T) {
 			wantRedirect: true,
 			wantTarget:   "/home",
 		},
+		{
+			name: "match basic redirect with query condition",
+			redirects: []*Redirect{
+				{Type: RedirectTypeBasic, Source: "/page", Target: "/default", Status: RedirectStatusMovedPermanent},
+				{Type: RedirectTypeBasic, Source: "/page", Target: "/fr", Status: RedirectStatusMovedPermanent, Conditions: []RedirectCondition{{Type: RedirectConditionTypeQuery, Name: "lang", Value: "fr"}}},
+			},
+			host:         "example.com",
+			uri:          "/page?lang=fr&utm=x",
+			wantRedirect: true,
+			wantTarget:   "/fr",
+		},
+		{
+			name: "fallback to redirect without condition on same path",
+			redirects: []*Redirect{
+				{Type: RedirectTypeBasic, Source: "/page", Target: "/default", Status: RedirectStatusMovedPermanent},
+				{Type: RedirectTypeBasic, Source: "/page", Target: "/fr", Status: RedirectStatusMovedPermanent, Conditions: []RedirectCondition{{Type: RedirectConditionTypeQuery, Name: "lang", Value: "fr"}}},
+			},
+			host:         "example.com",
+			uri:          "/page",
+			wantRedirect: true,
+			wantTarget:   "/default",
+		},
+		{
+			name: "no match when query condition not fulfilled",
+			redirects: []*Redirect{
+				{Type: RedirectTypeBasic, Source: "/page", Target: "/fr", Status: RedirectStatusMovedPermanent, Conditions: []RedirectCondition{{Type: RedirectConditionTypeQuery, Name: "lang", Value: "fr"}}},
+			},
+			host:         "example.com",
+			uri:          "/page?lang=en",
+			wantRedirect: false,
+			wantTarget:   "",
+		},
+		{
+			name: "redirect without condition does not ignore query string",
+			redirects: []*Redirect{
+				{Type: RedirectTypeBasic, Source: "/page", Target: "/default", Status: RedirectStatusMovedPermanent},
+			},
+			host:         "example.com",
+			uri:          "/page?lang=fr",
+			wantRedirect: false,
+			wantTarget:   "",
+		},
+		{
+			name: "match regex redirect with query condition",
+			redirects: []*Redirect{
+				{Type: RedirectTypeRegex, Source: "^/blog/(.*)$", Target: "/articles/$1", Status: RedirectStatusMovedPermanent},
+				{Type: RedirectTypeRegex, Source: "^/blog/(.*)$", Target: "/preview/$1", Status: RedirectStatusFound, Conditions: []RedirectCondition{{Type: RedirectConditionTypeQuery, Name: "preview"}}},
+			},
+			host:         "example.com",
+			uri:          "/blog/post?preview=1",
+			wantRedirect: true,
+			wantTarget:   "/preview/post",
+		},
 	}
 
 	for _, tt := range tests {
@@ -269,7 +366,141 @@ func TestRedirectTree_Match(t *testing.T) {
 	}
 }
 
-func Test_resolveTarget(t *testing.T) {
+func TestRedirectTree_MatchRequest(t *testing.T) {
+	tree := NewRedirectTreeMatcher()
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/app", Target: "/download", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/app", Target: "/ios", Status: RedirectStatusFound, Conditions: []RedirectCondition{{Type: RedirectConditionTypeHeader, Name: "X-Platform", Value: "ios"}}}))
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/app", Target: "/ios-shop", Status: RedirectStatusFound, Conditions: []RedirectCondition{
+		{Type: RedirectConditionTypeHeader, Name: "X-Platform", Value: "ios"},
+		{Type: RedirectConditionTypeHost, Value: "shop.example.com"},
+	}}))
+
+	_, target := tree.MatchRequest(RedirectRequest{Host: "example.com", URI: "/app", Header: http.Header{"X-Platform": {"ios"}}})
+	assert.Equal(t, "/ios", target)
+
+	_, target = tree.MatchRequest(RedirectRequest{Host: "Shop.Example.com", URI: "/app", Header: http.Header{"X-Platform": {"ios"}}})
+	assert.Equal(t, "/ios-shop", target)
+
+	_, target = tree.MatchRequest(RedirectRequest{Host: "example.com", URI: "/app", Header: http.Header{"X-Platform": {"android"}}})
+	assert.Equal(t, "/download", target)
+
+	_, target = tree.Match("example.com", "/app")
+	assert.Equal(t, "/download", target)
+
+	t.Run("same conditions replace the redirect", func(t *testing.T) {
+		assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/app", Target: "/ios-new", Status: RedirectStatusFound, Conditions: []RedirectCondition{{Type: RedirectConditionTypeHeader, Name: "x-platform", Value: "ios"}}}))
+
+		_, target := tree.MatchRequest(RedirectRequest{Host: "example.com", URI: "/app", Header: http.Header{"X-Platform": {"ios"}}})
+		assert.Equal(t, "/ios-new", target)
+	})
+}
+
+func TestRedirectTree_MatchRequest_CountryAndLanguage(t *testing.T) {
+	tree := NewRedirectTreeMatcher()
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/", Target: "/en/", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/", Target: "/fr/", Status: RedirectStatusFound, Conditions: []RedirectCondition{{Type: RedirectConditionTypeLanguage, Values: []string{"fr"}}}}))
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/", Target: "/be-fr/", Status: RedirectStatusFound, Conditions: []RedirectCondition{
+		{Type: RedirectConditionTypeCountry, Values: []string{"BE"}},
+		{Type: RedirectConditionTypeLanguage, Values: []string{"fr"}},
+	}}))
+
+	_, target := tree.MatchRequest(RedirectRequest{Host: "example.com", URI: "/", Header: http.Header{"Accept-Language": {"fr-FR,fr;q=0.9,en;q=0.8"}}})
+	assert.Equal(t, "/fr/", target)
+
+	_, target = tree.MatchRequest(RedirectRequest{Host: "example.com", URI: "/", Country: "be", Header: http.Header{"Accept-Language": {"fr-BE"}}})
+	assert.Equal(t, "/be-fr/", target)
+
+	_, target = tree.MatchRequest(RedirectRequest{Host: "example.com", URI: "/", Country: "BE", Header: http.Header{"Accept-Language": {"nl-BE"}}})
+	assert.Equal(t, "/en/", target)
+}
+
+func TestRedirectTree_Priority(t *testing.T) {
+	tree := NewRedirectTreeMatcher()
+	assert.NoError(t, tree.Insert(&Redirect{ID: 1, Type: RedirectTypeRegex, Source: "^/blog/(.*)$", Target: "/articles/$1", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{ID: 2, Type: RedirectTypeRegex, Source: "^/blog/2020/(.*)$", Target: "/archive/$1", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{ID: 3, Type: RedirectTypeBasic, Source: "/app", Target: "/ios", Status: RedirectStatusFound, Conditions: []RedirectCondition{{Type: RedirectConditionTypeHeader, Name: "X-Platform", Value: "ios"}}}))
+
+	// The longest source is evaluated first by default
+	_, target := tree.Match("example.com", "/blog/2020/post")
+	assert.Equal(t, "/archive/post", target)
+
+	assert.NoError(t, tree.Insert(&Redirect{ID: 4, Type: RedirectTypeRegex, Source: "^/blog/(.*)$", Target: "/new/$1", Status: RedirectStatusFound, Priority: 1}))
+	_, target = tree.Match("example.com", "/blog/2020/post")
+	assert.Equal(t, "/new/2020/post", target)
+
+	// Among the redirects of a source, the priority comes before the number of conditions
+	assert.NoError(t, tree.Insert(&Redirect{ID: 5, Type: RedirectTypeBasic, Source: "/app", Target: "/maintenance", Status: RedirectStatusFound, Priority: 1}))
+	_, target = tree.MatchRequest(RedirectRequest{Host: "example.com", URI: "/app", Header: http.Header{"X-Platform": {"ios"}}})
+	assert.Equal(t, "/maintenance", target)
+}
+
+func TestRedirectTree_CatchAll(t *testing.T) {
+	tree := NewRedirectTreeMatcherWithOptions(RedirectOptions{PreserveQueryString: true})
+	assert.NoError(t, tree.Insert(&Redirect{ID: 1, Type: RedirectTypeCatchAll, Target: "https://example.com", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{ID: 2, Type: RedirectTypeBasic, Source: "/old", Target: "/new", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{ID: 3, Type: RedirectTypeRegex, Source: "^/blog/(.*)$", Target: "/articles/$1", Status: RedirectStatusFound, Priority: -1}))
+
+	r, target := tree.Match("example.com", "/old")
+	assert.Equal(t, int64(2), r.ID)
+	assert.Equal(t, "/new", target)
+	r, _ = tree.Match("example.com", "/blog/post")
+	assert.Equal(t, int64(3), r.ID)
+
+	r, target = tree.Match("example.com", "/unknown?a=1")
+	assert.Equal(t, int64(1), r.ID)
+	assert.Equal(t, "https://example.com?a=1", target)
+
+	// A single catch-all is kept, and it follows its validity period
+	expired := time.Now().Add(-time.Hour)
+	assert.NoError(t, tree.Insert(&Redirect{ID: 4, Type: RedirectTypeCatchAll, Target: "/404", Status: RedirectStatusFound, ValidUntil: &expired}))
+	r, _ = tree.Match("example.com", "/unknown")
+	assert.Nil(t, r)
+}
+
+func TestRedirectTree_Options(t *testing.T) {
+	tree := NewRedirectTreeMatcherWithOptions(RedirectOptions{CaseInsensitive: true, IgnoreTrailingSlash: true, PreserveQueryString: true})
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/About/", Target: "/about-us", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasicHost, Source: "Example.com/shop", Target: "https://shop.example.com?ref=old", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/search?q=old", Target: "/search", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeRegex, Source: "^/Blog/(.*)$", Target: "/articles/$1", Status: RedirectStatusFound}))
+
+	_, target := tree.Match("example.com", "/about")
+	assert.Equal(t, "/about-us", target)
+	_, target = tree.Match("example.com", "/ABOUT/?utm_source=mail")
+	assert.Equal(t, "/about-us?utm_source=mail", target)
+	_, target = tree.Match("EXAMPLE.com", "/shop/?a=1")
+	assert.Equal(t, "https://shop.example.com?ref=old&a=1", target)
+
+	// The query string matched by the source is not appended
+	_, target = tree.Match("example.com", "/search?q=old")
+	assert.Equal(t, "/search", target)
+
+	// Regex sources are not normalized
+	r, _ := tree.Match("example.com", "/blog/post")
+	assert.Nil(t, r)
+	_, target = tree.Match("example.com", "/Blog/post?page=2")
+	assert.Equal(t, "/articles/post?page=2", target)
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeRegex, Source: "^(?i)/news/(.*)$", Target: "/articles/$1", Status: RedirectStatusFound}))
+	_, target = tree.Match("example.com", "/NEWS/post")
+	assert.Equal(t, "/articles/post", target)
+}
+
+func TestRedirectTree_NormalizeURL(t *testing.T) {
+	tree := NewRedirectTreeMatcherWithOptions(RedirectOptions{NormalizeURL: true})
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasic, Source: "/café/menu", Target: "/cafe", Status: RedirectStatusFound}))
+	assert.NoError(t, tree.Insert(&Redirect{Type: RedirectTypeBasicHost, Source: "bücher.example/shop", Target: "/books", Status: RedirectStatusFound}))
+
+	_, target := tree.Match("example.com", "/caf%c3%a9//menu")
+	assert.Equal(t, "/cafe", target)
+	_, target = tree.Match("example.com", "/caf%C3%A9/m%65nu")
+	assert.Equal(t, "/cafe", target)
+	_, target = tree.Match("xn--bcher-kva.example", "/shop")
+	assert.Equal(t, "/books", target)
+	r, _ := tree.Match("example.com", "/Café/menu")
+	assert.Nil(t, r, "the case still matters")
+}
+
+func TestResolveTarget(t *testing.T) {
 	tests := []struct {
 		name    string
 		target  string
@@ -322,7 +553,7 @@ func Test_resolveTarget(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := resolveTarget(tt.target, tt.matches)
+			got := ResolveTarget(tt.target, tt.matches)
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -354,6 +585,16 @@ func Test_extractRegexPrefix(t *testing.T) {
 			pattern: "[a-z]+/item",
 			want:    "",
 		},
+		{
+			name:    "case-insensitive pattern",
+			pattern: "^(?i)/product/item",
+			want:    "",
+		},
+		{
+			name:    "case-insensitive group",
+			pattern: "^/product/(?i:item)",
+			want:    "/product/",
+		},
 		{
 			name:    "pattern with capture group",
 			pattern: "/user/([0-9]+)/profile",
@@ -500,7 +741,7 @@ func Test_minInt(t *testing.T) {
 	}
 }
 
-func Test_sortBySourceLength(t *testing.T) {
+func Test_sortRegexCandidates(t *testing.T) {
 	tests := []struct {
 		name       string
 		candidates []*compiledRedirect
@@ -555,11 +796,30 @@ func Test_sortBySourceLength(t *testing.T) {
 			},
 			wantOrder: []string{"/aaa", "/bbb", "/ccc"},
 		},
+		{
+			name: "same length elements by id",
+			candidates: []*compiledRedirect{
+				{Redirect: &Redirect{ID: 3, Source: "/aaa"}},
+				{Redirect: &Redirect{ID: 1, Source: "/bbb"}},
+				{Redirect: &Redirect{ID: 2, Source: "/ccc"}},
+			},
+			wantOrder: []string{"/bbb", "/ccc", "/aaa"},
+		},
+		{
+			name: "priority before length",
+			candidates: []*compiledRedirect{
+				{Redirect: &Redirect{Source: "/very/long/path"}},
+				{Redirect: &Redirect{Source: "/a", Priority: 10}},
+				{Redirect: &Redirect{Source: "/low", Priority: -1}},
+				{Redirect: &Redirect{Source: "/medium", Priority: 10}},
+			},
+			wantOrder: []string{"/medium", "/a", "/very/long/path", "/low"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sortBySourceLength(tt.candidates)
+			sortRegexCandidates(tt.candidates)
 
 			assert.Len(t, tt.candidates, len(tt.wantOrder))
 
@@ -632,7 +892,7 @@ func TestRedirectTree_matchRegex(t *testing.T) {
 				assert.NoError(t, tree.Insert(r))
 			}
 
-			gotRedirect, gotTarget := tree.matchRegex(tree.regex, tree.regexRoot, tt.input)
+			gotRedirect, gotTarget := tree.matchRegex(tree.regex, tree.regexRoot, tt.input, tt.input, &matchContext{now: time.Now()})
 
 			if tt.wantRedirect {
 				assert.NotNil(t, gotRedirect)
@@ -0,0 +1,28 @@
+package types
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ResponseHeaders are the HTTP headers added by the agents to the response of a page or a redirect, by name
+type ResponseHeaders map[string]string
+
+// NormalizeResponseHeaders returns the headers with canonical names and trimmed values, nil when there is none
+func NormalizeResponseHeaders(headers ResponseHeaders) ResponseHeaders {
+	if len(headers) == 0 {
+		return nil
+	}
+	normalized := make(ResponseHeaders, len(headers))
+	for name, value := range headers {
+		normalized[http.CanonicalHeaderKey(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	return normalized
+}
+
+// Apply sets the headers on the header of a response
+func (h ResponseHeaders) Apply(header http.Header) {
+	for name, value := range h {
+		header.Set(name, value)
+	}
+}
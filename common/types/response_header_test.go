@@ -0,0 +1,21 @@
+package types
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeResponseHeaders(t *testing.T) {
+	assert.Nil(t, NormalizeResponseHeaders(nil))
+	assert.Nil(t, NormalizeResponseHeaders(ResponseHeaders{}))
+	assert.Equal(t, ResponseHeaders{"Cache-Control": "max-age=60", "X-Robots-Tag": "noindex"},
+		NormalizeResponseHeaders(ResponseHeaders{" cache-control": "max-age=60 ", "x-robots-tag": "noindex"}))
+}
+
+func TestResponseHeaders_Apply(t *testing.T) {
+	header := http.Header{"Cache-Control": {"no-cache"}}
+	ResponseHeaders{"Cache-Control": "max-age=60", "X-Robots-Tag": "noindex"}.Apply(header)
+	assert.Equal(t, http.Header{"Cache-Control": {"max-age=60"}, "X-Robots-Tag": {"noindex"}}, header)
+}
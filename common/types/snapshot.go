@@ -0,0 +1,62 @@
+package types
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// MIMEApplicationNDJSON is the content type of the streamed snapshots, asked by the agents with the Accept header
+	// of the redirects and pages requests
+	MIMEApplicationNDJSON = "application/x-ndjson"
+	// HeaderVersion is the header of the snapshot responses giving the version of the project they were read at
+	HeaderVersion = "X-Flecto-Version"
+)
+
+// ErrSnapshotIncomplete is returned when a streamed snapshot ends before all its items, the manager interrupting the
+// stream when the project is published meanwhile. The snapshot is downloaded again at the new version.
+var ErrSnapshotIncomplete = errors.New("snapshot stream ended before all its items")
+
+// SnapshotHeader is the first line of a streamed snapshot, followed by a line for each of its Total redirects or pages
+type SnapshotHeader struct {
+	Version int `json:"version"`
+	Total   int `json:"total"`
+	// Options, Maintenance and CacheTTL are the settings of the project sent with the redirects, only CacheTTL is
+	// sent with the pages
+	Options     *RedirectOptions `json:"options,omitempty"`
+	Maintenance *Maintenance     `json:"maintenance,omitempty"`
+	CacheTTL    CacheTTL         `json:"cacheTTL"`
+}
+
+// ReadSnapshot reads a streamed snapshot, calling fn with each of its items, and returns its header. It fails with
+// ErrSnapshotIncomplete when the stream holds fewer items than announced by the header.
+func ReadSnapshot[T any](r io.Reader, fn func(item T) error) (*SnapshotHeader, error) {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	header := &SnapshotHeader{}
+	if err := decoder.Decode(header); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot header: %w", err)
+	}
+
+	count := 0
+	for {
+		var item T
+		err := decoder.Decode(&item)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot item %d: %w", count+1, err)
+		}
+		if err = fn(item); err != nil {
+			return nil, err
+		}
+		count++
+	}
+	if count != header.Total {
+		return nil, fmt.Errorf("%w: %d of %d read", ErrSnapshotIncomplete, count, header.Total)
+	}
+	return header, nil
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSnapshot(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		stream := `{"version":3,"total":2,"options":{"caseInsensitive":true},"cacheTTL":{"pages":0,"redirects":60}}
+{"type":"BASIC","source":"/a","target":"/b","status":"MOVED_PERMANENT"}
+{"type":"BASIC","source":"/c","target":"/d","status":"FOUND"}
+`
+		var redirects []Redirect
+		header, err := ReadSnapshot(strings.NewReader(stream), func(redirect Redirect) error {
+			redirects = append(redirects, redirect)
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, header.Version)
+		assert.True(t, header.Options.CaseInsensitive)
+		assert.Nil(t, header.Maintenance)
+		assert.Equal(t, 60, header.CacheTTL.Redirects)
+		require.Len(t, redirects, 2)
+		assert.Equal(t, "/a", redirects[0].Source)
+		assert.Equal(t, "/d", redirects[1].Target)
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		stream := `{"version":3,"total":2}
+{"path":"/a","content":"a"}
+`
+		_, err := ReadSnapshot(strings.NewReader(stream), func(page Page) error { return nil })
+
+		assert.ErrorIs(t, err, ErrSnapshotIncomplete)
+	})
+
+	t.Run("missing header", func(t *testing.T) {
+		_, err := ReadSnapshot(strings.NewReader(""), func(page Page) error { return nil })
+
+		assert.ErrorContains(t, err, "failed to read snapshot header")
+	})
+
+	t.Run("invalid item", func(t *testing.T) {
+		stream := `{"version":3,"total":1}
+{"path":`
+		_, err := ReadSnapshot(strings.NewReader(stream), func(page Page) error { return nil })
+
+		assert.ErrorContains(t, err, "failed to read snapshot item 1")
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		stream := `{"version":3,"total":1}
+{"path":"/a"}
+`
+		_, err := ReadSnapshot(strings.NewReader(stream), func(page Page) error { return errors.New("full") })
+
+		assert.EqualError(t, err, "full")
+	})
+}
@@ -0,0 +1,13 @@
+package types
+
+type SortDirection string
+
+const (
+	SortASC  SortDirection = "ASC"
+	SortDESC SortDirection = "DESC"
+)
+
+type SortInput struct {
+	Column    string
+	Direction SortDirection
+}
@@ -12,7 +12,52 @@ type Config struct {
 	Auth    AuthConfig    `mapstructure:"auth" validate:"required"`
 	Page    PageConfig    `mapstructure:"page" validate:"required"`
 	Agent   AgentConfig   `mapstructure:"agent" validate:"required"`
+	Import  ImportConfig  `mapstructure:"import" validate:"required"`
+	Expiry  ExpiryConfig  `mapstructure:"expiry" validate:"required"`
+	Health  HealthConfig  `mapstructure:"health" validate:"required"`
 	Metrics MetricsConfig `mapstructure:"metrics"`
+	// Invalidation broadcasts the cache invalidation events to all the replicas of the manager
+	Invalidation InvalidationConfig `mapstructure:"invalidation"`
+	Publish      PublishConfig      `mapstructure:"publish"`
+	GitSync      GitSyncConfig      `mapstructure:"git_sync"`
+	DraftLock    DraftLockConfig    `mapstructure:"draft_lock" validate:"required"`
+	Notification NotificationConfig `mapstructure:"notification" validate:"required"`
+	// Outbox delivers the events written with the changes they announce, like the notifications of the publishes
+	Outbox    OutboxConfig    `mapstructure:"outbox" validate:"required"`
+	Retention RetentionConfig `mapstructure:"retention" validate:"required"`
+	// LinkCheck scans the published pages for broken internal links
+	LinkCheck LinkCheckConfig `mapstructure:"link_check"`
+	// ReadOnly rejects the changes during migrations or incidents, the reads and the agent sync staying available
+	ReadOnly ReadOnlyConfig `mapstructure:"read_only"`
+	// Scheduler sets when the recurring tasks, like the expiry, the health checks and the retention purge, run
+	Scheduler SchedulerConfig `mapstructure:"scheduler"`
+	// Extensions enables or disables the hooks registered by the extensions compiled in the manager
+	Extensions ExtensionsConfig `mapstructure:"extensions"`
+	// LogLevel is the level of the messages logged, given by the level flag or key
+	LogLevel string `mapstructure:"level"`
+}
+
+// WithRuntimeSettings returns a copy of the configuration with the settings which can change without restarting
+// the manager taken from cfg: the page size limits, markdown rendering and secrets scanning, the publish retries, the
+// draft lock durations, the notification timeout, quota warning ratio and channels, the read-only mode, the schedules
+// of the recurring tasks, the disabled extension hooks and the log level. The other settings are kept.
+func (c *Config) WithRuntimeSettings(cfg *Config) *Config {
+	next := *c
+	next.Page.SizeLimit = cfg.Page.SizeLimit
+	next.Page.TotalSizeLimit = cfg.Page.TotalSizeLimit
+	next.Page.Markdown = cfg.Page.Markdown
+	next.Page.Secrets = cfg.Page.Secrets
+	next.Publish = cfg.Publish
+	next.DraftLock = cfg.DraftLock
+	next.Notification.Timeout = cfg.Notification.Timeout
+	next.Notification.QuotaWarningRatio = cfg.Notification.QuotaWarningRatio
+	next.Notification.SMTP = cfg.Notification.SMTP
+	next.Notification.Slack = cfg.Notification.Slack
+	next.ReadOnly = cfg.ReadOnly
+	next.Scheduler = cfg.Scheduler
+	next.Extensions = cfg.Extensions
+	next.LogLevel = cfg.LogLevel
+	return &next
 }
 
 type MetricsConfig struct {
@@ -22,15 +67,204 @@ type MetricsConfig struct {
 
 type HTTPConfig struct {
 	Listen string `mapstructure:"listen" validate:"required"`
+	// AccessLog logs each request with its ID, subject, route, status, latency and number of database statements
+	AccessLog bool `mapstructure:"access_log"`
+	// BodyLimit is the largest body of a request in bytes, the import uploads excepted, 0 disables the limit
+	BodyLimit int64 `mapstructure:"body_limit" validate:"min=0"`
+	// TrustedProxies are the IPs and CIDR ranges of the proxies whose X-Forwarded-For header gives the client IP, the
+	// client IP being the address of the connection without them
+	TrustedProxies []string `mapstructure:"trusted_proxies" validate:"dive,cidr|ip"`
+	// Status is the public status endpoint polled by the uptime monitors
+	Status StatusConfig `mapstructure:"status"`
+	// GraphQL hardens the GraphQL API against the expensive or unexpected queries
+	GraphQL GraphQLConfig `mapstructure:"graphql"`
+}
+
+// GraphQLConfig restricts the operations the GraphQL API runs
+type GraphQLConfig struct {
+	// Introspection answers the queries of the schema, used by the GraphQL IDEs and code generators
+	Introspection bool `mapstructure:"introspection"`
+	// DepthLimit is the highest number of nested fields of an operation which is not a persisted query, 0 disables
+	// the limit
+	DepthLimit int `mapstructure:"depth_limit" validate:"min=0"`
+	// ComplexityLimit is the highest complexity of an operation which is not a persisted query, 0 disables the limit
+	ComplexityLimit  int                    `mapstructure:"complexity_limit" validate:"min=0"`
+	PersistedQueries PersistedQueriesConfig `mapstructure:"persisted_queries"`
+}
+
+// PersistedQueriesConfig is the allowlist of the queries of the GraphQL API, reviewed and deployed with their clients
+type PersistedQueriesConfig struct {
+	// File is a JSON object of the queries keyed by the hex SHA-256 hash of their text, read at startup
+	File string `mapstructure:"file" validate:"required_if=Enforce true"`
+	// Enforce rejects the operations which are not in the file, the automatic persisted queries being disabled
+	Enforce bool `mapstructure:"enforce"`
+}
+
+// StatusDetail is how much the public status endpoint reveals
+type StatusDetail string
+
+const (
+	// StatusDetailMinimal only reports the overall status
+	StatusDetailMinimal StatusDetail = "minimal"
+	// StatusDetailStandard adds the uptime and the status of each subsystem
+	StatusDetailStandard StatusDetail = "standard"
+	// StatusDetailFull adds the version, the errors of the subsystems and the length of the queues
+	StatusDetailFull StatusDetail = "full"
+)
+
+// StatusConfig is the /status endpoint, served without authentication and rate limited per client IP
+type StatusConfig struct {
+	Enabled bool         `mapstructure:"enabled"`
+	Detail  StatusDetail `mapstructure:"detail" validate:"omitempty,oneof=minimal standard full"`
+	// CacheTTL is how long a report is served again, and cached by the clients and proxies, 0 runs the checks on each request
+	CacheTTL time.Duration `mapstructure:"cache_ttl" validate:"min=0"`
+	// RateLimit is the number of requests per second allowed to a client IP, 0 disables the limit
+	RateLimit float64 `mapstructure:"rate_limit" validate:"min=0"`
+	// Burst is the number of requests a client IP can send at once
+	Burst int `mapstructure:"burst" validate:"min=0"`
 }
 type PageConfig struct {
-	SizeLimit      int `mapstructure:"size_limit" validate:"required,min=1"`
-	TotalSizeLimit int `mapstructure:"total_size_limit" validate:"required,min=2,gtfield=SizeLimit"`
+	SizeLimit      int                   `mapstructure:"size_limit" validate:"required,min=1"`
+	TotalSizeLimit int                   `mapstructure:"total_size_limit" validate:"required,min=2,gtfield=SizeLimit"`
+	Compression    PageCompressionConfig `mapstructure:"compression"`
+	Storage        PageStorageConfig     `mapstructure:"storage"`
+	Markdown       PageMarkdownConfig    `mapstructure:"markdown"`
+	Secrets        PageSecretsConfig     `mapstructure:"secrets"`
+}
+
+// PageSecretsMode is what saving a page draft whose content holds a likely secret leads to
+type PageSecretsMode string
+
+const (
+	PageSecretsOff PageSecretsMode = "off"
+	// PageSecretsWarn saves the draft with a warning for each secret found
+	PageSecretsWarn PageSecretsMode = "warn"
+	// PageSecretsBlock refuses to save the draft
+	PageSecretsBlock PageSecretsMode = "block"
+)
+
+// PageSecretsConfig scans the content of the page drafts for likely credentials and API keys before saving them,
+// so that they are not published by mistake
+type PageSecretsConfig struct {
+	Mode PageSecretsMode `mapstructure:"mode" validate:"omitempty,oneof=off warn block"`
+	// DefaultRules enables the built-in rules, detecting the common cloud, payment and chat API keys, private keys
+	// and tokens
+	DefaultRules bool `mapstructure:"default_rules"`
+	// Rules are checked in addition to the built-in rules
+	Rules []PageSecretRule `mapstructure:"rules" validate:"dive"`
+}
+
+// PageSecretRule detects the secrets matching the regular expression Pattern
+type PageSecretRule struct {
+	Name    string `mapstructure:"name" validate:"required"`
+	Pattern string `mapstructure:"pattern" validate:"required,pattern"`
+}
+
+// PageMarkdownConfig is the rendering of the MARKDOWN pages into HTML, always sanitized afterward
+type PageMarkdownConfig struct {
+	// GFM enables the GitHub Flavored Markdown extensions: tables, strikethrough, autolinks and task lists
+	GFM bool `mapstructure:"gfm"`
+	// HardWraps renders the newlines of the paragraphs as line breaks
+	HardWraps bool `mapstructure:"hard_wraps"`
+	// HeadingIDs gives the headings an id generated from their text, to link to them
+	HeadingIDs bool `mapstructure:"heading_ids"`
+	// Typographer replaces the quotes, dashes and ellipses by their typographic form
+	Typographer bool `mapstructure:"typographer"`
+	// RawHTML keeps the HTML written in the markdown, which is left out otherwise
+	RawHTML bool `mapstructure:"raw_html"`
+}
+
+// PageCompressionAlgorithm is the algorithm compressing the content of the pages stored in the database
+type PageCompressionAlgorithm string
+
+const (
+	PageCompressionNone PageCompressionAlgorithm = "none"
+	PageCompressionGzip PageCompressionAlgorithm = "gzip"
+	PageCompressionZstd PageCompressionAlgorithm = "zstd"
+)
+
+// PageCompressionConfig enables the compression of the page contents larger than MinSize
+type PageCompressionConfig struct {
+	Algorithm PageCompressionAlgorithm `mapstructure:"algorithm" validate:"omitempty,oneof=none gzip zstd"`
+	MinSize   int                      `mapstructure:"min_size" validate:"min=0"`
+}
+
+// PageStorageBackend is where the content of the pages is stored
+type PageStorageBackend string
+
+const (
+	PageStorageDatabase   PageStorageBackend = "db"
+	PageStorageS3         PageStorageBackend = "s3"
+	PageStorageFilesystem PageStorageBackend = "fs"
+)
+
+// PageStorageConfig stores the page contents of at least MinSize bytes, once compressed, outside the database.
+// The contents already stored keep being read from their backend, which must stay configured.
+type PageStorageConfig struct {
+	Backend    PageStorageBackend      `mapstructure:"backend" validate:"omitempty,oneof=db s3 fs"`
+	MinSize    int                     `mapstructure:"min_size" validate:"min=0"`
+	S3         S3StorageConfig         `mapstructure:"s3"`
+	Filesystem FilesystemStorageConfig `mapstructure:"fs"`
+}
+
+// S3StorageConfig is an AWS S3 bucket, or a bucket of an S3 compatible storage like MinIO
+type S3StorageConfig struct {
+	// Endpoint is the URL of the storage, the regional endpoint of AWS S3 when empty
+	Endpoint string `mapstructure:"endpoint"`
+	Region   string `mapstructure:"region"`
+	Bucket   string `mapstructure:"bucket"`
+	// Prefix is prepended to the keys of the contents
+	Prefix          string `mapstructure:"prefix"`
+	AccessKeyID     string `mapstructure:"access_key_id"`
+	SecretAccessKey string `mapstructure:"secret_access_key"`
+	SessionToken    string `mapstructure:"session_token"`
+	// PathStyle addresses the bucket in the path of the URLs instead of their host, as most S3 compatible storages expect
+	PathStyle bool `mapstructure:"path_style"`
+}
+
+// FilesystemStorageConfig is a directory, shared by all the replicas of the manager
+type FilesystemStorageConfig struct {
+	Path string `mapstructure:"path"`
+}
+
+// InvalidationDriver is the transport of the cache invalidation events between the replicas
+type InvalidationDriver string
+
+const (
+	// InvalidationDriverMemory only delivers the events to the replica publishing them
+	InvalidationDriverMemory InvalidationDriver = "memory"
+	// InvalidationDriverPostgres delivers the events to every replica with Postgres LISTEN/NOTIFY
+	InvalidationDriverPostgres InvalidationDriver = "postgres"
+)
+
+type InvalidationConfig struct {
+	Driver   InvalidationDriver         `mapstructure:"driver" validate:"omitempty,oneof=memory postgres"`
+	Postgres PostgresInvalidationConfig `mapstructure:"postgres"`
+}
+
+type PostgresInvalidationConfig struct {
+	DSN        string        `mapstructure:"dsn"`
+	Channel    string        `mapstructure:"channel" validate:"omitempty,max=63"`
+	RetryDelay time.Duration `mapstructure:"retry_delay" validate:"omitempty,min=100ms"`
 }
 
 type AuthConfig struct {
-	JWT    JWTConfig    `mapstructure:"jwt" validate:"required"`
-	OpenID OpenIDConfig `mapstructure:"openid"`
+	JWT      JWTConfig      `mapstructure:"jwt" validate:"required"`
+	OpenID   OpenIDConfig   `mapstructure:"openid"`
+	Password PasswordConfig `mapstructure:"password" validate:"required"`
+	// PermissionCache keeps the permissions of the users, roles and tokens in memory
+	PermissionCache PermissionCacheConfig `mapstructure:"permission_cache"`
+	SCIM            SCIMConfig            `mapstructure:"scim"`
+}
+
+// SCIMConfig enables the SCIM 2.0 endpoints provisioning the users and the groups from an identity provider
+type SCIMConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+type PermissionCacheConfig struct {
+	// TTL is how long permissions are cached, 0 disables the cache
+	TTL time.Duration `mapstructure:"ttl" validate:"min=0"`
 }
 
 type JWTConfig struct {
@@ -53,6 +287,14 @@ type OpenIDConfig struct {
 	RolesClaim   string   `mapstructure:"roles_claim"`
 }
 
+// PasswordConfig is the policy enforced when a user password is set
+type PasswordConfig struct {
+	MinLength  int           `mapstructure:"min_length" validate:"min=1,max=72"`
+	MinClasses int           `mapstructure:"min_classes" validate:"min=1,max=4"`
+	History    int           `mapstructure:"history" validate:"min=0"`
+	MaxAge     time.Duration `mapstructure:"max_age" validate:"min=0"`
+}
+
 // DbLogLevel represents the database logging level
 type DbLogLevel string
 
@@ -67,18 +309,322 @@ type DbConfig struct {
 	Type     string                 `mapstructure:"type" validate:"required,excludesall=!@#$ "`
 	LogLevel DbLogLevel             `mapstructure:"log_level"`
 	Config   map[string]interface{} `mapstructure:"config"`
+	// Shards store the data of some namespaces in other databases, the other namespaces
+	// and the users, roles and tokens staying in this database
+	Shards []DbShardConfig `mapstructure:"shards" validate:"dive"`
+	// Replicas are read-only copies of this database, the reads done outside transactions
+	// are sent to them so that they do not contend with the writes
+	Replicas []DbReplicaConfig `mapstructure:"replicas" validate:"dive"`
+	// Seed creates at startup the bootstrap data missing from the database, see the seed command
+	Seed bool `mapstructure:"seed"`
+	// Pool sizes the connections kept to the database, its replicas using the same settings
+	Pool DbPoolConfig `mapstructure:"pool"`
+	// SlowQueryThreshold logs as warnings the statements running longer, with the function calling them, 0 to not
+	// log them
+	SlowQueryThreshold time.Duration `mapstructure:"slow_query_threshold" validate:"min=0"`
+}
+
+// DbPoolConfig is the connection pool of a database, the zero values keeping the defaults of the driver
+type DbPoolConfig struct {
+	// MaxOpenConns limits the connections open to the database, 0 for no limit
+	MaxOpenConns int `mapstructure:"max_open_conns" validate:"min=0"`
+	// MaxIdleConns is the number of idle connections kept for the next statements, 2 when 0
+	MaxIdleConns int `mapstructure:"max_idle_conns" validate:"min=0"`
+	// ConnMaxLifetime closes the connections open for longer, before the database or a proxy does, 0 to keep them
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime" validate:"min=0"`
+}
+
+// DbReplicaConfig is a read-only copy of the main database, of the same type
+type DbReplicaConfig struct {
+	Config map[string]interface{} `mapstructure:"config" validate:"required"`
+}
+
+// DbShardConfig is a database storing the data of a list of namespaces
+type DbShardConfig struct {
+	Name       string                 `mapstructure:"name" validate:"required"`
+	Type       string                 `mapstructure:"type" validate:"required,excludesall=!@#$ "`
+	LogLevel   DbLogLevel             `mapstructure:"log_level"`
+	Config     map[string]interface{} `mapstructure:"config"`
+	Namespaces []string               `mapstructure:"namespaces" validate:"required,min=1"`
+	Pool       DbPoolConfig           `mapstructure:"pool"`
+}
+
+// DbConfig returns the connection configuration of the shard
+func (c DbShardConfig) DbConfig() DbConfig {
+	return DbConfig{Type: c.Type, LogLevel: c.LogLevel, Config: c.Config, Pool: c.Pool}
 }
 
 type AgentConfig struct {
 	OfflineThreshold time.Duration `mapstructure:"offline_threshold" validate:"required,min=1s"`
+	// StaleThreshold is the duration without heartbeat after which a registered agent is stale
+	StaleThreshold time.Duration `mapstructure:"stale_threshold" validate:"required,min=1s"`
+	// RolloutTimeout is the duration after a publish past which the agents not serving the new version are reported lagging
+	RolloutTimeout time.Duration `mapstructure:"rollout_timeout" validate:"required,min=1s"`
+	// SnapshotCache keeps the redirects and pages served to the agents in memory
+	SnapshotCache SnapshotCacheConfig `mapstructure:"snapshot_cache"`
+}
+
+// SnapshotCacheConfig sizes the cache of the pages of redirects and pages served to the agents, per project version
+type SnapshotCacheConfig struct {
+	// MaxSize is the number of bytes of serialized redirects and pages kept, the least recently used pages being
+	// dropped first, 0 disables the cache
+	MaxSize int64 `mapstructure:"max_size" validate:"min=0"`
+}
+
+type ImportConfig struct {
+	MaxFileSize int64 `mapstructure:"max_file_size" validate:"required,min=1"`
+	BatchSize   int   `mapstructure:"batch_size" validate:"required,min=1"`
+	Workers     int   `mapstructure:"workers" validate:"required,min=1"`
+	QueueSize   int   `mapstructure:"queue_size" validate:"required,min=1"`
+}
+
+type ExpiryConfig struct {
+	Interval    time.Duration `mapstructure:"interval" validate:"required,min=1s"`
+	AutoPublish bool          `mapstructure:"auto_publish"`
+}
+
+type PublishConfig struct {
+	Retry PublishRetryConfig `mapstructure:"retry"`
+	// ValidationHooks are the external policy services asked to approve the plan of each publish before it is applied
+	ValidationHooks []PublishHookConfig `mapstructure:"validation_hooks" validate:"dive"`
+	// Chains resolves the redirect chains of the published redirects, a redirect whose target is the source of another
+	Chains PublishChainsConfig `mapstructure:"chains"`
+	// MassDeletion refuses the operations deleting a large share of the published redirects or pages of a project
+	MassDeletion MassDeletionConfig `mapstructure:"mass_deletion"`
+}
+
+// MassDeletionConfig is the safeguard of the publishes, manifest applies and deletions by tag removing more than
+// MaxPercent of the published redirects or of the published pages of a project. Such an operation is refused unless
+// it is forced, and the users subscribed to the project are alerted either way.
+type MassDeletionConfig struct {
+	// MaxPercent is the share of the published redirects or pages an operation can delete without being forced, 0 disables the safeguard
+	MaxPercent int `mapstructure:"max_percent" validate:"min=0,max=100"`
+	// MinCount is the number of redirects or pages below which an operation is never refused, so that small projects can be emptied
+	MinCount int `mapstructure:"min_count" validate:"min=0"`
+}
+
+// PublishChainsConfig resolves the chains of the redirects of a project when it is published. Only the redirects
+// without conditions, weighted targets nor validity period are followed.
+type PublishChainsConfig struct {
+	// Flatten points the redirects starting a chain directly at the final target of the chain
+	Flatten bool `mapstructure:"flatten"`
+	// MaxDepth fails the publishes leaving a chain of more than MaxDepth redirects, no limit when 0
+	MaxDepth int `mapstructure:"max_depth" validate:"min=0"`
+}
+
+// PublishHookConfig is an external policy service receiving the plan of the publishes as a POST request, which
+// answers whether the publish is allowed
+type PublishHookConfig struct {
+	Name string `mapstructure:"name" validate:"required"`
+	URL  string `mapstructure:"url" validate:"required,url"`
+	// Timeout bounds the call to the hook, 5 seconds when 0
+	Timeout time.Duration `mapstructure:"timeout" validate:"min=0"`
+	// FailOpen lets the publishes through when the hook cannot be reached or answers an error, they are blocked otherwise
+	FailOpen bool `mapstructure:"fail_open"`
+	// Secret signs the body of the requests with HMAC SHA-256 in the X-Flecto-Signature header, unsigned when empty
+	Secret string `mapstructure:"secret"`
+	// Namespaces restricts the hook to the publishes of these namespaces, all namespaces when empty
+	Namespaces []string `mapstructure:"namespaces"`
+	// IncludePageContent sends the content of the published pages along with the plan, left out by default
+	IncludePageContent bool `mapstructure:"include_page_content"`
+}
+
+// PublishRetryConfig retries the publishes failing because another one holds the lock of the project.
+// The delay between attempts doubles from InitialDelay up to MaxDelay, with a random jitter.
+type PublishRetryConfig struct {
+	// MaxAttempts is the number of attempts of a publish, 0 or 1 disables the retries
+	MaxAttempts  int           `mapstructure:"max_attempts" validate:"min=0"`
+	InitialDelay time.Duration `mapstructure:"initial_delay" validate:"min=0"`
+	MaxDelay     time.Duration `mapstructure:"max_delay" validate:"min=0"`
+}
+
+// GitSyncConfig configures the sync of the projects from the manifests stored in Git repositories
+type GitSyncConfig struct {
+	// PollInterval is the interval at which the projects polling their repository are synced, 0 disables the polling
+	PollInterval time.Duration `mapstructure:"poll_interval" validate:"min=0"`
+	// Timeout bounds the repository fetch of a sync, 0 leaves it unbounded
+	Timeout time.Duration `mapstructure:"timeout" validate:"min=0"`
+	// Credentials authenticate the fetches of the repositories whose URL starts with their prefix
+	Credentials []GitCredentialConfig `mapstructure:"credentials" validate:"dive"`
+}
+
+// GitCredentialConfig is the HTTP basic authentication of the Git repositories under a URL prefix,
+// Password usually being an access token
+type GitCredentialConfig struct {
+	URLPrefix string `mapstructure:"url_prefix" validate:"required"`
+	Username  string `mapstructure:"username"`
+	Password  string `mapstructure:"password" validate:"required"`
+}
+
+// DraftLockConfig bounds the duration of the locks taken by the users on drafts
+type DraftLockConfig struct {
+	// TTL is the duration of a lock when none is requested
+	TTL time.Duration `mapstructure:"ttl" validate:"required,min=1m"`
+	// MaxTTL is the longest duration a lock can be requested for
+	MaxTTL time.Duration `mapstructure:"max_ttl" validate:"required,gtefield=TTL"`
+}
+
+// NotificationConfig configures the channels sending the notifications the users subscribed to
+type NotificationConfig struct {
+	// Timeout bounds the sending of a notification to a subscription
+	Timeout time.Duration `mapstructure:"timeout" validate:"required,min=100ms"`
+	// QuotaWarningRatio is the share of page.total_size_limit from which the publishes send a quota warning, 0 disables them
+	QuotaWarningRatio float64     `mapstructure:"quota_warning_ratio" validate:"min=0,max=1"`
+	SMTP              SMTPConfig  `mapstructure:"smtp"`
+	Slack             SlackConfig `mapstructure:"slack"`
+}
+
+// SMTPConfig is the server sending the email notifications, they are disabled when Host is empty
+type SMTPConfig struct {
+	Host string `mapstructure:"host"`
+	Port int    `mapstructure:"port" validate:"min=0,max=65535"`
+	// Username and Password authenticate to the server when Username is set, after STARTTLS when the server offers it
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from" validate:"required_with=Host,omitempty,email"`
+}
+
+// SlackConfig restricts the Slack incoming webhooks the notifications are posted to
+type SlackConfig struct {
+	// AllowedHosts are the hosts the webhook URLs of the subscriptions may point to, Slack notifications are disabled when empty
+	AllowedHosts []string `mapstructure:"allowed_hosts"`
+}
+
+// OutboxConfig configures the relay delivering the events of the outbox. A failed delivery is attempted again after
+// a delay doubled at each attempt, until MaxAttempts.
+type OutboxConfig struct {
+	// PollInterval is the interval between two scans of the pending events, the events of the changes made by the
+	// replica being delivered right away and the notifications at the next scan
+	PollInterval time.Duration `mapstructure:"poll_interval" validate:"required,min=100ms"`
+	// BatchSize is the number of events delivered per scan and shard
+	BatchSize   int `mapstructure:"batch_size" validate:"required,min=1"`
+	MaxAttempts int `mapstructure:"max_attempts" validate:"required,min=1"`
+	// RetryDelay is the delay before the second attempt of an event
+	RetryDelay    time.Duration `mapstructure:"retry_delay" validate:"required,min=1s"`
+	MaxRetryDelay time.Duration `mapstructure:"max_retry_delay" validate:"required,gtefield=RetryDelay"`
+	// Lease is the time a replica has to deliver an event before another one can take it, longer than a delivery
+	Lease time.Duration `mapstructure:"lease" validate:"required,min=1s"`
+}
+
+// RetentionConfig configures the cleanup worker purging the records older than the days to keep of
+// their category, the records of a category being kept forever when its days to keep is 0
+type RetentionConfig struct {
+	Interval time.Duration `mapstructure:"interval" validate:"required,min=1m"`
+	// BatchSize is the number of records deleted per statement, to not hold long locks
+	BatchSize int `mapstructure:"batch_size" validate:"required,min=1"`
+	// ImportJobs is the number of days to keep the finished import jobs
+	ImportJobs int `mapstructure:"import_jobs" validate:"min=0"`
+	// Tombstones is the number of days to keep the copies of the redirects and pages deleted by the publishes
+	Tombstones int `mapstructure:"tombstones" validate:"min=0"`
+	// OutboxEvents is the number of days to keep the events of the outbox delivered or given up
+	OutboxEvents int `mapstructure:"outbox_events" validate:"min=0"`
+}
+
+type HealthConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Interval    time.Duration `mapstructure:"interval" validate:"required,min=1m"`
+	Timeout     time.Duration `mapstructure:"timeout" validate:"required,min=100ms"`
+	Concurrency int           `mapstructure:"concurrency" validate:"required,min=1"`
+}
+
+// LinkCheckConfig enables the periodic scan of the HTML pages for internal links matching no page or redirect.
+// With SuggestDrafts, a redirect draft is created for the broken links with a suggested target.
+type LinkCheckConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Interval      time.Duration `mapstructure:"interval" validate:"required_if=Enabled true,omitempty,min=1m"`
+	SuggestDrafts bool          `mapstructure:"suggest_drafts"`
+}
+
+// ReadOnlyConfig is the maintenance mode of the manager, rejecting the mutations of the GraphQL API and the changes of
+// the REST API while the manager keeps serving the reads and the agents. The periodic workers changing data, like the
+// expiry, the git sync and the retention, skip their runs meanwhile.
+type ReadOnlyConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Message is added to the errors of the rejected changes, to tell the users why and for how long
+	Message string `mapstructure:"message"`
+}
+
+// SchedulerConfig changes the schedules of the recurring tasks, keyed by their name. A task missing runs at the
+// interval of its own configuration, like expiry.interval, and only when its feature is enabled.
+type SchedulerConfig struct {
+	Tasks map[string]ScheduledTaskConfig `mapstructure:"tasks" validate:"dive,keys,oneof=redirect_expiry redirect_health page_link_check git_sync retention draft_locks,endkeys"`
+}
+
+// ScheduledTaskConfig is the schedule of a recurring task
+type ScheduledTaskConfig struct {
+	// Schedule is a cron expression, or "@every <duration>", replacing the interval of the task when set
+	Schedule string `mapstructure:"schedule" validate:"omitempty,schedule"`
+	// Disabled stops running the task, even when its feature is enabled
+	Disabled bool `mapstructure:"disabled"`
+}
+
+// ExtensionsConfig disables some hooks of the extensions without building the manager again
+type ExtensionsConfig struct {
+	// Disabled are the names of the hooks not run, at any point they are registered
+	Disabled []string `mapstructure:"disabled"`
 }
 
 func DefaultConfig() *Config {
 	return &Config{
-		HTTP: HTTPConfig{Listen: "127.0.0.1:8080"},
-		Page: PageConfig{SizeLimit: 1024 * 1024, TotalSizeLimit: 1024 * 1024 * 100},
+		HTTP: HTTPConfig{
+			Listen:    "127.0.0.1:8080",
+			AccessLog: true,
+			BodyLimit: 10 * 1024 * 1024,
+			Status: StatusConfig{
+				Enabled:   true,
+				Detail:    StatusDetailMinimal,
+				CacheTTL:  10 * time.Second,
+				RateLimit: 1,
+				Burst:     10,
+			},
+			GraphQL: GraphQLConfig{
+				Introspection: true,
+			},
+		},
+		Page: PageConfig{
+			SizeLimit:      1024 * 1024,
+			TotalSizeLimit: 1024 * 1024 * 100,
+			Compression: PageCompressionConfig{
+				Algorithm: PageCompressionNone,
+				MinSize:   4 * 1024,
+			},
+			Storage: PageStorageConfig{
+				Backend: PageStorageDatabase,
+				MinSize: 64 * 1024,
+			},
+			Markdown: PageMarkdownConfig{
+				GFM: true,
+			},
+			Secrets: PageSecretsConfig{
+				Mode:         PageSecretsWarn,
+				DefaultRules: true,
+			},
+		},
 		Agent: AgentConfig{
 			OfflineThreshold: 6 * time.Hour,
+			StaleThreshold:   5 * time.Minute,
+			RolloutTimeout:   10 * time.Minute,
+			SnapshotCache:    SnapshotCacheConfig{MaxSize: 64 * 1024 * 1024},
+		},
+		Import: ImportConfig{
+			MaxFileSize: 2 * 1024 * 1024,
+			BatchSize:   500,
+			Workers:     2,
+			QueueSize:   100,
+		},
+		Expiry: ExpiryConfig{
+			Interval:    time.Minute,
+			AutoPublish: false,
+		},
+		Health: HealthConfig{
+			Enabled:     false,
+			Interval:    time.Hour,
+			Timeout:     5 * time.Second,
+			Concurrency: 4,
+		},
+		LinkCheck: LinkCheckConfig{
+			Enabled:  false,
+			Interval: time.Hour,
 		},
 		Auth: AuthConfig{
 			JWT: JWTConfig{
@@ -91,9 +637,69 @@ func DefaultConfig() *Config {
 			OpenID: OpenIDConfig{
 				Enabled: false,
 			},
+			Password: PasswordConfig{
+				MinLength:  8,
+				MinClasses: 1,
+				History:    0,
+				MaxAge:     0,
+			},
+			PermissionCache: PermissionCacheConfig{
+				TTL: 30 * time.Second,
+			},
 		},
 		Metrics: MetricsConfig{
 			Enabled: false,
 		},
+		Invalidation: InvalidationConfig{
+			Driver: InvalidationDriverMemory,
+			Postgres: PostgresInvalidationConfig{
+				Channel:    "flecto_invalidation",
+				RetryDelay: 5 * time.Second,
+			},
+		},
+		Publish: PublishConfig{
+			Retry: PublishRetryConfig{
+				MaxAttempts:  3,
+				InitialDelay: 200 * time.Millisecond,
+				MaxDelay:     2 * time.Second,
+			},
+			MassDeletion: MassDeletionConfig{
+				MaxPercent: 50,
+				MinCount:   10,
+			},
+		},
+		GitSync: GitSyncConfig{
+			PollInterval: 5 * time.Minute,
+			Timeout:      time.Minute,
+		},
+		DraftLock: DraftLockConfig{
+			TTL:    30 * time.Minute,
+			MaxTTL: 8 * time.Hour,
+		},
+		Notification: NotificationConfig{
+			Timeout:           10 * time.Second,
+			QuotaWarningRatio: 0.8,
+			SMTP: SMTPConfig{
+				Port: 587,
+			},
+			Slack: SlackConfig{
+				AllowedHosts: []string{"hooks.slack.com"},
+			},
+		},
+		Outbox: OutboxConfig{
+			PollInterval:  time.Second,
+			BatchSize:     100,
+			MaxAttempts:   10,
+			RetryDelay:    10 * time.Second,
+			MaxRetryDelay: time.Hour,
+			Lease:         time.Minute,
+		},
+		Retention: RetentionConfig{
+			Interval:     time.Hour,
+			BatchSize:    1000,
+			ImportJobs:   30,
+			Tombstones:   0,
+			OutboxEvents: 7,
+		},
 	}
 }
@@ -12,11 +12,115 @@ func TestDefaultConfig(t *testing.T) {
 	assert.Equal(t,
 		&Config{
 			HTTP: HTTPConfig{
-				Listen: "127.0.0.1:8080",
+				Listen:    "127.0.0.1:8080",
+				AccessLog: true,
+				BodyLimit: 10 * 1024 * 1024,
+				Status: StatusConfig{
+					Enabled:   true,
+					Detail:    StatusDetailMinimal,
+					CacheTTL:  10 * time.Second,
+					RateLimit: 1,
+					Burst:     10,
+				},
+				GraphQL: GraphQLConfig{
+					Introspection: true,
+				},
+			},
+			Page: PageConfig{
+				SizeLimit:      1024 * 1024,
+				TotalSizeLimit: 1024 * 1024 * 100,
+				Compression: PageCompressionConfig{
+					Algorithm: PageCompressionNone,
+					MinSize:   4 * 1024,
+				},
+				Storage: PageStorageConfig{
+					Backend: PageStorageDatabase,
+					MinSize: 64 * 1024,
+				},
+				Markdown: PageMarkdownConfig{
+					GFM: true,
+				},
+				Secrets: PageSecretsConfig{
+					Mode:         PageSecretsWarn,
+					DefaultRules: true,
+				},
 			},
-			Page: PageConfig{SizeLimit: 1024 * 1024, TotalSizeLimit: 1024 * 1024 * 100},
 			Agent: AgentConfig{
 				OfflineThreshold: 6 * time.Hour,
+				StaleThreshold:   5 * time.Minute,
+				RolloutTimeout:   10 * time.Minute,
+				SnapshotCache:    SnapshotCacheConfig{MaxSize: 64 * 1024 * 1024},
+			},
+			Import: ImportConfig{
+				MaxFileSize: 2 * 1024 * 1024,
+				BatchSize:   500,
+				Workers:     2,
+				QueueSize:   100,
+			},
+			Expiry: ExpiryConfig{
+				Interval:    time.Minute,
+				AutoPublish: false,
+			},
+			Health: HealthConfig{
+				Enabled:     false,
+				Interval:    time.Hour,
+				Timeout:     5 * time.Second,
+				Concurrency: 4,
+			},
+			LinkCheck: LinkCheckConfig{
+				Enabled:  false,
+				Interval: time.Hour,
+			},
+			Invalidation: InvalidationConfig{
+				Driver: InvalidationDriverMemory,
+				Postgres: PostgresInvalidationConfig{
+					Channel:    "flecto_invalidation",
+					RetryDelay: 5 * time.Second,
+				},
+			},
+			Publish: PublishConfig{
+				Retry: PublishRetryConfig{
+					MaxAttempts:  3,
+					InitialDelay: 200 * time.Millisecond,
+					MaxDelay:     2 * time.Second,
+				},
+				MassDeletion: MassDeletionConfig{
+					MaxPercent: 50,
+					MinCount:   10,
+				},
+			},
+			GitSync: GitSyncConfig{
+				PollInterval: 5 * time.Minute,
+				Timeout:      time.Minute,
+			},
+			DraftLock: DraftLockConfig{
+				TTL:    30 * time.Minute,
+				MaxTTL: 8 * time.Hour,
+			},
+			Notification: NotificationConfig{
+				Timeout:           10 * time.Second,
+				QuotaWarningRatio: 0.8,
+				SMTP: SMTPConfig{
+					Port: 587,
+				},
+				Slack: SlackConfig{
+					AllowedHosts: []string{"hooks.slack.com"},
+				},
+			},
+			Outbox: OutboxConfig{
+				PollInterval:  time.Second,
+				BatchSize:     100,
+				MaxAttempts:   10,
+				RetryDelay:    10 * time.Second,
+				MaxRetryDelay: time.Hour,
+				Lease:         time.Minute,
+			},
+			Retention: RetentionConfig{
+				Interval:     time.Hour,
+				BatchSize:    1000,
+				ImportJobs:   30,
+				Tombstones:   0,
+				OutboxEvents: 7,
 			},
 			Auth: AuthConfig{
 				JWT: JWTConfig{
@@ -29,8 +133,51 @@ func TestDefaultConfig(t *testing.T) {
 				OpenID: OpenIDConfig{
 					Enabled: false,
 				},
+				Password: PasswordConfig{
+					MinLength:  8,
+					MinClasses: 1,
+					History:    0,
+					MaxAge:     0,
+				},
+				PermissionCache: PermissionCacheConfig{
+					TTL: 30 * time.Second,
+				},
 			},
 		},
 		got,
 	)
 }
+
+func TestConfig_WithRuntimeSettings(t *testing.T) {
+	current := DefaultConfig()
+	reloaded := DefaultConfig()
+	reloaded.HTTP.Listen = ":9999"
+	reloaded.DB.Type = "mysql"
+	reloaded.Page.SizeLimit = 42
+	reloaded.Page.Compression.MinSize = 1
+	reloaded.Page.Markdown.HardWraps = true
+	reloaded.Page.Secrets.Mode = PageSecretsBlock
+	reloaded.Notification.Timeout = time.Minute
+	reloaded.Notification.QuotaWarningRatio = 0.5
+	reloaded.ReadOnly = ReadOnlyConfig{Enabled: true, Message: "database migration"}
+	reloaded.Scheduler.Tasks = map[string]ScheduledTaskConfig{"retention": {Schedule: "0 3 * * *"}}
+	reloaded.Extensions.Disabled = []string{"audit"}
+	reloaded.LogLevel = "debug"
+
+	got := current.WithRuntimeSettings(reloaded)
+
+	assert.NotSame(t, current, got)
+	assert.Equal(t, DefaultConfig(), current)
+	assert.Equal(t, current.HTTP, got.HTTP)
+	assert.Equal(t, current.DB, got.DB)
+	assert.Equal(t, current.Page.Compression, got.Page.Compression)
+	assert.Equal(t, 42, got.Page.SizeLimit)
+	assert.True(t, got.Page.Markdown.HardWraps)
+	assert.Equal(t, PageSecretsBlock, got.Page.Secrets.Mode)
+	assert.Equal(t, time.Minute, got.Notification.Timeout)
+	assert.Equal(t, 0.5, got.Notification.QuotaWarningRatio)
+	assert.Equal(t, ReadOnlyConfig{Enabled: true, Message: "database migration"}, got.ReadOnly)
+	assert.Equal(t, "0 3 * * *", got.Scheduler.Tasks["retention"].Schedule)
+	assert.Equal(t, []string{"audit"}, got.Extensions.Disabled)
+	assert.Equal(t, "debug", got.LogLevel)
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	SecretSchemeEnv   = "env"
+	SecretSchemeVault = "vault"
+	SecretSchemeAWS   = "aws-sm"
+	SecretSchemeGCP   = "gcp-sm"
+
+	secretRequestTimeout = 10 * time.Second
+)
+
+// ErrSecretNotFound is returned when a referenced secret, or its key, does not exist
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretResolver returns the secret referenced by a configuration value, ref being the value without its scheme
+type SecretResolver interface {
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+// SecretResolverFunc is a function used as SecretResolver
+type SecretResolverFunc func(ctx context.Context, ref string) (string, error)
+
+func (f SecretResolverFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+// DefaultSecretResolvers returns the resolvers of the secret schemes, configured by the usual environment
+// variables of each provider: VAULT_ADDR, VAULT_TOKEN and VAULT_NAMESPACE for Vault, AWS_REGION and the
+// AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN credentials for AWS Secrets Manager, the
+// application default credentials for GCP Secret Manager
+func DefaultSecretResolvers() map[string]SecretResolver {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	return map[string]SecretResolver{
+		SecretSchemeEnv: SecretResolverFunc(resolveEnvSecret),
+		SecretSchemeVault: &VaultSecretResolver{
+			Address:   os.Getenv("VAULT_ADDR"),
+			Token:     os.Getenv("VAULT_TOKEN"),
+			Namespace: os.Getenv("VAULT_NAMESPACE"),
+		},
+		SecretSchemeAWS: &AWSSecretResolver{
+			Region:          region,
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		},
+		SecretSchemeGCP: &GCPSecretResolver{},
+	}
+}
+
+// ResolveSecrets replaces the string values of the configuration written scheme:ref, like
+// vault:secret/data/flecto#jwt_secret, by the secret they reference. The values without a known
+// scheme are kept. The errors name the key of the value, never the secret.
+func ResolveSecrets(ctx context.Context, cfg *Config, resolvers map[string]SecretResolver) error {
+	w := &secretWalker{ctx: ctx, resolvers: resolvers, resolved: map[string]string{}}
+	return w.walk(reflect.ValueOf(cfg).Elem(), "")
+}
+
+type secretWalker struct {
+	ctx       context.Context
+	resolvers map[string]SecretResolver
+	// resolved caches the secrets by reference, a value used by several keys being fetched once
+	resolved map[string]string
+}
+
+func (w *secretWalker) walk(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.String:
+		secret, ok, err := w.resolve(v.String())
+		if err != nil {
+			return fmt.Errorf("config %s: %w", path, err)
+		}
+		if ok {
+			v.SetString(secret)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			if err := w.walk(v.Field(i), joinSecretPath(path, name)); err != nil {
+				return err
+			}
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			return w.walk(v.Elem(), path)
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			if err := w.walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Interface:
+		// The value held by an interface cannot be set, it is resolved in a copy stored back
+		if v.IsNil() {
+			return nil
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		if err := w.walk(elem, path); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			if err := w.walk(elem, joinSecretPath(path, fmt.Sprint(iter.Key()))); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return nil
+}
+
+func (w *secretWalker) resolve(value string) (string, bool, error) {
+	scheme, ref, found := strings.Cut(value, ":")
+	resolver, ok := w.resolvers[scheme]
+	if !found || !ok {
+		return "", false, nil
+	}
+	if secret, cached := w.resolved[value]; cached {
+		return secret, true, nil
+	}
+	secret, err := resolver.Resolve(w.ctx, ref)
+	if err != nil {
+		return "", false, fmt.Errorf("resolve %s secret: %w", scheme, err)
+	}
+	w.resolved[value] = secret
+	return secret, true, nil
+}
+
+func joinSecretPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func resolveEnvSecret(_ context.Context, name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrSecretNotFound, name)
+	}
+	return value, nil
+}
+
+// splitSecretRef splits a reference into the secret and the key of the value within it, after the last #
+func splitSecretRef(ref string) (string, string) {
+	if i := strings.LastIndex(ref, "#"); i >= 0 {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, ""
+}
+
+// secretField returns the key of a secret holding a JSON object
+func secretField(fields map[string]any, key string) (string, error) {
+	value, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("%w: no key %s", ErrSecretNotFound, key)
+	}
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number, float64, bool:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("key %s is not a scalar", key)
+	}
+}
+
+func secretHTTPClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Timeout: secretRequestTimeout}
+}
+
+// readSecretResponse decodes the JSON body of a successful response of a secret provider
+func readSecretResponse(resp *http.Response, provider string, out any) error {
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrSecretNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%s returned status %d", provider, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flectolab/flecto-manager/sigv4"
+)
+
+const awsSecretsManagerService = "secretsmanager"
+
+// AWSSecretResolver reads the secrets of AWS Secrets Manager, referenced as aws-sm:secret-id or
+// aws-sm:secret-id#key for a key of a secret holding a JSON object. The secret ID is its name or ARN.
+type AWSSecretResolver struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Endpoint replaces the regional endpoint of the service
+	Endpoint string
+	Client   *http.Client
+}
+
+func (r *AWSSecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	secretID, key := splitSecretRef(ref)
+	if r.Region == "" {
+		return "", errors.New("AWS_REGION is not set")
+	}
+	if r.AccessKeyID == "" || r.SecretAccessKey == "" {
+		return "", errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY are not set")
+	}
+
+	endpoint := r.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com/", awsSecretsManagerService, r.Region)
+	}
+	payload, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	sigv4.Sign(req, payload, sigv4.Credentials{AccessKeyID: r.AccessKeyID, SecretAccessKey: r.SecretAccessKey, SessionToken: r.SessionToken},
+		r.Region, awsSecretsManagerService, time.Now())
+
+	resp, err := secretHTTPClient(r.Client).Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Type string `json:"__type"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if strings.HasSuffix(apiErr.Type, "ResourceNotFoundException") {
+			return "", ErrSecretNotFound
+		}
+		return "", fmt.Errorf("aws secrets manager returned status %d %s", resp.StatusCode, apiErr.Type)
+	}
+
+	var body struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.SecretString == nil {
+		return "", fmt.Errorf("secret %s is binary", secretID)
+	}
+	if key == "" {
+		return *body.SecretString, nil
+	}
+	fields := map[string]any{}
+	if err = json.Unmarshal([]byte(*body.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object: %w", secretID, err)
+	}
+	return secretField(fields, key)
+}
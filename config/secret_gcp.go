@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	gcpSecretManagerEndpoint = "https://secretmanager.googleapis.com"
+	gcpCloudPlatformScope    = "https://www.googleapis.com/auth/cloud-platform"
+)
+
+// GCPSecretResolver reads the secrets of GCP Secret Manager, referenced as
+// gcp-sm:projects/my-project/secrets/my-secret or gcp-sm:projects/my-project/secrets/my-secret#key for a key
+// of a secret holding a JSON object. The latest version is read unless the name ends with /versions/<version>.
+type GCPSecretResolver struct {
+	// TokenSource authenticates the requests, the application default credentials when nil
+	TokenSource oauth2.TokenSource
+	// Endpoint replaces the endpoint of the service
+	Endpoint string
+	Client   *http.Client
+}
+
+func (r *GCPSecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	name, key := splitSecretRef(ref)
+	if !strings.Contains(name, "/versions/") {
+		name += "/versions/latest"
+	}
+
+	tokenSource := r.TokenSource
+	if tokenSource == nil {
+		var err error
+		if tokenSource, err = google.DefaultTokenSource(ctx, gcpCloudPlatformScope); err != nil {
+			return "", err
+		}
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := r.Endpoint
+	if endpoint == "" {
+		endpoint = gcpSecretManagerEndpoint
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(endpoint, "/")+"/v1/"+strings.TrimLeft(name, "/")+":access", nil)
+	if err != nil {
+		return "", err
+	}
+	token.SetAuthHeader(req)
+	resp, err := secretHTTPClient(r.Client).Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Payload struct {
+			Data string `json:"data"`
+		} `json:"payload"`
+	}
+	if err = readSecretResponse(resp, "gcp secret manager", &body); err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(body.Payload.Data)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return string(data), nil
+	}
+	fields := map[string]any{}
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not a JSON object: %w", name, err)
+	}
+	return secretField(fields, key)
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	var calls int
+	resolvers := map[string]SecretResolver{
+		"test": SecretResolverFunc(func(_ context.Context, ref string) (string, error) {
+			calls++
+			if ref == "missing" {
+				return "", ErrSecretNotFound
+			}
+			return "resolved-" + ref, nil
+		}),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		calls = 0
+		cfg := DefaultConfig()
+		cfg.Auth.JWT.Secret = "test:jwt"
+		cfg.DB.Config = map[string]interface{}{"dsn": "test:dsn", "port": 3306, "nested": map[string]interface{}{"password": "test:dsn"}}
+		cfg.DB.Shards = []DbShardConfig{{Name: "shard1", Config: map[string]interface{}{"dsn": "user:pass@tcp(db:3306)/flecto"}}}
+		cfg.Notification.SMTP.Password = "test:smtp"
+
+		require.NoError(t, ResolveSecrets(context.Background(), cfg, resolvers))
+		assert.Equal(t, "resolved-jwt", cfg.Auth.JWT.Secret)
+		assert.Equal(t, map[string]interface{}{"dsn": "resolved-dsn", "port": 3306, "nested": map[string]interface{}{"password": "resolved-dsn"}}, cfg.DB.Config)
+		// A value whose prefix is not a known scheme is kept
+		assert.Equal(t, "user:pass@tcp(db:3306)/flecto", cfg.DB.Shards[0].Config["dsn"])
+		assert.Equal(t, "resolved-smtp", cfg.Notification.SMTP.Password)
+		assert.Equal(t, "127.0.0.1:8080", cfg.HTTP.Listen)
+		// The same reference is resolved once
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("error names the key", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Notification.SMTP.Password = "test:missing"
+
+		err := ResolveSecrets(context.Background(), cfg, resolvers)
+		assert.ErrorIs(t, err, ErrSecretNotFound)
+		assert.ErrorContains(t, err, "notification.smtp.password")
+	})
+}
+
+func TestResolveEnvSecret(t *testing.T) {
+	t.Setenv("FLECTO_TEST_SECRET", "s3cret")
+
+	secret, err := resolveEnvSecret(context.Background(), "FLECTO_TEST_SECRET")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", secret)
+
+	_, err = resolveEnvSecret(context.Background(), "FLECTO_TEST_SECRET_MISSING")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+}
+
+func TestVaultSecretResolver_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/flecto":
+			_, _ = io.WriteString(w, `{"data":{"data":{"jwt_secret":"kv2-secret"},"metadata":{"version":3}}}`)
+		case "/v1/kv/flecto":
+			_, _ = io.WriteString(w, `{"data":{"jwt_secret":"kv1-secret"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	resolver := &VaultSecretResolver{Address: server.URL + "/", Token: "token", Namespace: "team"}
+
+	secret, err := resolver.Resolve(context.Background(), "secret/data/flecto#jwt_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "kv2-secret", secret)
+
+	secret, err = resolver.Resolve(context.Background(), "/kv/flecto#jwt_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "kv1-secret", secret)
+
+	_, err = resolver.Resolve(context.Background(), "secret/data/flecto#missing")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+
+	_, err = resolver.Resolve(context.Background(), "secret/data/other#jwt_secret")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+
+	_, err = resolver.Resolve(context.Background(), "secret/data/flecto")
+	assert.ErrorContains(t, err, "has no #key")
+
+	_, err = (&VaultSecretResolver{Address: server.URL}).Resolve(context.Background(), "secret/data/flecto#jwt_secret")
+	assert.ErrorContains(t, err, "status 403")
+}
+
+func TestAWSSecretResolver_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Equal(t, "session", r.Header.Get("X-Amz-Security-Token"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/aws4_request, SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target, Signature=")
+
+		body, _ := io.ReadAll(r.Body)
+		switch string(body) {
+		case `{"SecretId":"flecto/db"}`:
+			_, _ = io.WriteString(w, `{"Name":"flecto/db","SecretString":"{\"password\":\"db-secret\",\"port\":3306}"}`)
+		case `{"SecretId":"flecto/jwt"}`:
+			_, _ = io.WriteString(w, `{"Name":"flecto/jwt","SecretString":"jwt-secret"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"ResourceNotFoundException","message":"Secrets Manager can't find the specified secret."}`)
+		}
+	}))
+	defer server.Close()
+	resolver := &AWSSecretResolver{Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session", Endpoint: server.URL}
+
+	secret, err := resolver.Resolve(context.Background(), "flecto/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "db-secret", secret)
+
+	secret, err = resolver.Resolve(context.Background(), "flecto/db#port")
+	require.NoError(t, err)
+	assert.Equal(t, "3306", secret)
+
+	secret, err = resolver.Resolve(context.Background(), "flecto/jwt")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-secret", secret)
+
+	_, err = resolver.Resolve(context.Background(), "flecto/jwt#key")
+	assert.ErrorContains(t, err, "is not a JSON object")
+
+	_, err = resolver.Resolve(context.Background(), "flecto/missing")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+
+	_, err = (&AWSSecretResolver{Region: "eu-west-1"}).Resolve(context.Background(), "flecto/jwt")
+	assert.ErrorContains(t, err, "AWS_ACCESS_KEY_ID")
+}
+
+func TestGCPSecretResolver_Resolve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/projects/my-project/secrets/db/versions/latest:access":
+			_, _ = io.WriteString(w, `{"payload":{"data":"`+base64.StdEncoding.EncodeToString([]byte(`{"password":"db-secret"}`))+`"}}`)
+		case "/v1/projects/my-project/secrets/jwt/versions/2:access":
+			_, _ = io.WriteString(w, `{"payload":{"data":"`+base64.StdEncoding.EncodeToString([]byte("jwt-secret"))+`"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	resolver := &GCPSecretResolver{
+		TokenSource: oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "token", TokenType: "Bearer"}),
+		Endpoint:    server.URL,
+	}
+
+	secret, err := resolver.Resolve(context.Background(), "projects/my-project/secrets/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "db-secret", secret)
+
+	secret, err = resolver.Resolve(context.Background(), "projects/my-project/secrets/jwt/versions/2")
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-secret", secret)
+
+	_, err = resolver.Resolve(context.Background(), "projects/my-project/secrets/missing")
+	assert.ErrorIs(t, err, ErrSecretNotFound)
+
+	errToken := errors.New("no credentials")
+	resolver.TokenSource = oauth2.ReuseTokenSource(nil, tokenSourceFunc(func() (*oauth2.Token, error) { return nil, errToken }))
+	_, err = resolver.Resolve(context.Background(), "projects/my-project/secrets/db")
+	assert.ErrorIs(t, err, errToken)
+}
+
+type tokenSourceFunc func() (*oauth2.Token, error)
+
+func (f tokenSourceFunc) Token() (*oauth2.Token, error) {
+	return f()
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// VaultSecretResolver reads the keys of the secrets of HashiCorp Vault, referenced as vault:path#key.
+// The path is the API path of the secret, with the data segment for a KV version 2 engine like
+// secret/data/flecto.
+type VaultSecretResolver struct {
+	Address   string
+	Token     string
+	Namespace string
+	Client    *http.Client
+}
+
+func (r *VaultSecretResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	path, key := splitSecretRef(ref)
+	if key == "" {
+		return "", fmt.Errorf("vault reference %s has no #key", path)
+	}
+	if r.Address == "" {
+		return "", errors.New("VAULT_ADDR is not set")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(r.Address, "/")+"/v1/"+strings.TrimLeft(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", r.Token)
+	if r.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", r.Namespace)
+	}
+	resp, err := secretHTTPClient(r.Client).Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	if err = readSecretResponse(resp, "vault", &body); err != nil {
+		return "", err
+	}
+	fields := body.Data
+	// A KV version 2 engine nests the keys in data, next to the metadata of the version
+	if nested, ok := fields["data"].(map[string]any); ok {
+		if _, versioned := fields["metadata"]; versioned {
+			fields = nested
+		}
+	}
+	return secretField(fields, key)
+}
@@ -1,26 +1,56 @@
 package context
 
 import (
+	"errors"
 	"io"
 	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/flectolab/flecto-manager/config"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/extension"
+	"github.com/flectolab/flecto-manager/probe"
 	flectoValidator "github.com/flectolab/flecto-manager/validator"
 	"github.com/go-playground/validator/v10"
 )
 
+// ErrConfigReloadUnsupported is returned by ReloadConfig when no ConfigLoader is set
+var ErrConfigReloadUnsupported = errors.New("configuration reload is not supported")
+
 type Context struct {
 	Logger   *slog.Logger
 	LogLevel *slog.LevelVar
 
-	sigs chan os.Signal
-	done chan bool
+	sigs       chan os.Signal
+	reloadSigs chan os.Signal
+	done       chan bool
 
+	// Config is the configuration loaded at startup, the settings which can be reloaded being read from CurrentConfig
 	Config    *config.Config
 	Validator *validator.Validate
+	// Workers holds the heartbeats of the background workers
+	Workers *probe.Registry
+	// Extensions holds the hooks of the extensions compiled in the manager
+	Extensions *extension.Registry
+	// ConfigLoader reads the configuration again for ReloadConfig
+	ConfigLoader func() (*config.Config, error)
+	// StartedAt is when the context was built, the start of the uptime reported by the status endpoint
+	StartedAt time.Time
+
+	// reload is shared by the copies of the context, nil for a context built without a constructor
+	reload *configReload
+}
+
+// configReload holds the configuration of the last reload and the functions called after each one
+type configReload struct {
+	mu       sync.Mutex
+	config   atomic.Pointer[config.Config]
+	onReload []func(cfg *config.Config)
 }
 
 func (c *Context) GetLogger() *slog.Logger {
@@ -43,19 +73,110 @@ func (c *Context) Signal() chan os.Signal {
 	return c.sigs
 }
 
+// ReloadSignal receives the SIGHUP signals asking to reload the configuration
+func (c *Context) ReloadSignal() chan os.Signal {
+	return c.reloadSigs
+}
+
+// CurrentConfig returns the configuration with the runtime settings of the last reload, Config until then
+func (c *Context) CurrentConfig() *config.Config {
+	if c.reload != nil {
+		if cfg := c.reload.config.Load(); cfg != nil {
+			return cfg
+		}
+	}
+	return c.Config
+}
+
+// OnConfigReload registers a function called with the current configuration after each reload,
+// for the components built from runtime settings
+func (c *Context) OnConfigReload(fn func(cfg *config.Config)) {
+	if c.reload == nil {
+		return
+	}
+	c.reload.mu.Lock()
+	defer c.reload.mu.Unlock()
+	c.reload.onReload = append(c.reload.onReload, fn)
+}
+
+// ReloadConfig reads the configuration with ConfigLoader and swaps its runtime settings and log level in,
+// see config.Config.WithRuntimeSettings. An invalid configuration is refused and leaves the current one in place.
+func (c *Context) ReloadConfig() error {
+	if c.ConfigLoader == nil || c.reload == nil {
+		return ErrConfigReloadUnsupported
+	}
+	cfg, err := c.ConfigLoader()
+	if err != nil {
+		return err
+	}
+	if err = c.Validator.Struct(cfg); err != nil {
+		return err
+	}
+	level := c.LogLevel.Level()
+	if cfg.LogLevel != "" {
+		if err = level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+			return err
+		}
+	}
+
+	c.reload.mu.Lock()
+	defer c.reload.mu.Unlock()
+	current := c.CurrentConfig().WithRuntimeSettings(cfg)
+	c.reload.config.Store(current)
+	c.LogLevel.Set(level)
+	for _, fn := range c.reload.onReload {
+		fn(current)
+	}
+	c.Logger.Info("configuration reloaded", "level", level.String())
+	return nil
+}
+
+// SetReadOnly switches the read-only mode of the current configuration, until the next reload which takes the mode
+// of the configuration again. The functions registered with OnConfigReload are not called.
+func (c *Context) SetReadOnly(readOnly config.ReadOnlyConfig) {
+	if c.reload == nil {
+		c.Config.ReadOnly = readOnly
+		return
+	}
+	c.reload.mu.Lock()
+	defer c.reload.mu.Unlock()
+	current := *c.CurrentConfig()
+	current.ReadOnly = readOnly
+	c.reload.config.Store(&current)
+}
+
+// ReadOnlyError returns the error of the changes refused while the manager is in read-only mode, nil otherwise
+func (c *Context) ReadOnlyError() error {
+	readOnly := c.CurrentConfig().ReadOnly
+	if !readOnly.Enabled {
+		return nil
+	}
+	if readOnly.Message == "" {
+		return flectoErrors.New(flectoErrors.CodeReadOnly, "the manager is in read-only mode")
+	}
+	return flectoErrors.Newf(flectoErrors.CodeReadOnly, "the manager is in read-only mode: %s", readOnly.Message)
+}
+
 func DefaultContext() *Context {
 	level := &slog.LevelVar{}
 	level.Set(slog.LevelInfo)
 	opts := &slog.HandlerOptions{AddSource: false, Level: level}
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	reloadSigs := make(chan os.Signal, 1)
+	signal.Notify(reloadSigs, syscall.SIGHUP)
 	return &Context{
-		Logger:    slog.New(slog.NewTextHandler(os.Stdout, opts)),
-		LogLevel:  level,
-		done:      make(chan bool),
-		sigs:      sigs,
-		Config:    config.DefaultConfig(),
-		Validator: flectoValidator.New(),
+		Logger:     slog.New(newRequestIDHandler(slog.NewTextHandler(os.Stdout, opts))),
+		LogLevel:   level,
+		done:       make(chan bool),
+		sigs:       sigs,
+		reloadSigs: reloadSigs,
+		Config:     config.DefaultConfig(),
+		Validator:  flectoValidator.New(),
+		Workers:    probe.NewRegistry(),
+		Extensions: extension.Default,
+		StartedAt:  time.Now(),
+		reload:     &configReload{},
 	}
 }
 
@@ -68,13 +189,20 @@ func TestContext(logBuffer io.Writer) *Context {
 	opts := &slog.HandlerOptions{AddSource: false, Level: level}
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	reloadSigs := make(chan os.Signal, 1)
+	signal.Notify(reloadSigs, syscall.SIGHUP)
 
 	return &Context{
-		Logger:    slog.New(slog.NewTextHandler(logBuffer, opts)),
-		LogLevel:  level,
-		done:      make(chan bool),
-		sigs:      sigs,
-		Config:    config.DefaultConfig(),
-		Validator: flectoValidator.New(),
+		Logger:     slog.New(newRequestIDHandler(slog.NewTextHandler(logBuffer, opts))),
+		LogLevel:   level,
+		done:       make(chan bool),
+		sigs:       sigs,
+		reloadSigs: reloadSigs,
+		Config:     config.DefaultConfig(),
+		Validator:  flectoValidator.New(),
+		Workers:    probe.NewRegistry(),
+		Extensions: extension.NewRegistry(),
+		StartedAt:  time.Now(),
+		reload:     &configReload{},
 	}
 }
@@ -1,6 +1,8 @@
 package context
 
 import (
+	"bytes"
+	"errors"
 	"io"
 	"log/slog"
 	"os"
@@ -10,7 +12,10 @@ import (
 	"time"
 
 	"github.com/flectolab/flecto-manager/config"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/extension"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDefaultContext_Success(t *testing.T) {
@@ -18,18 +23,26 @@ func TestDefaultContext_Success(t *testing.T) {
 	level := &slog.LevelVar{}
 	level.Set(slog.LevelInfo)
 	opts := &slog.HandlerOptions{AddSource: false, Level: level}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, opts))
+	logger := slog.New(newRequestIDHandler(slog.NewTextHandler(os.Stdout, opts)))
 	want := &Context{
-		Logger:   logger,
-		LogLevel: level,
-		Config:   config.DefaultConfig(),
+		Logger:     logger,
+		LogLevel:   level,
+		Config:     config.DefaultConfig(),
+		Extensions: extension.Default,
 	}
 	got := DefaultContext()
 
 	got.done = nil
 	got.sigs = nil
+	got.reloadSigs = nil
+	assert.NotNil(t, got.reload)
+	got.reload = nil
 	assert.NotNil(t, got.Validator)
 	got.Validator = nil
+	assert.NotNil(t, got.Workers)
+	got.Workers = nil
+	assert.False(t, got.StartedAt.IsZero())
+	got.StartedAt = time.Time{}
 	assert.Equal(t, want, got)
 }
 
@@ -38,18 +51,27 @@ func TestTestContext(t *testing.T) {
 	level := &slog.LevelVar{}
 	level.Set(slog.LevelInfo)
 	opts := &slog.HandlerOptions{AddSource: false, Level: level}
-	logger := slog.New(slog.NewTextHandler(io.Discard, opts))
+	logger := slog.New(newRequestIDHandler(slog.NewTextHandler(io.Discard, opts)))
 	want := &Context{
-		Logger:   logger,
-		LogLevel: level,
-		Config:   config.DefaultConfig(),
+		Logger:     logger,
+		LogLevel:   level,
+		Config:     config.DefaultConfig(),
+		Extensions: extension.NewRegistry(),
 	}
 	got := TestContext(nil)
+	assert.NotSame(t, extension.Default, got.Extensions, "the hooks of the extensions are not run in the tests")
 
 	got.done = nil
 	got.sigs = nil
+	got.reloadSigs = nil
+	assert.NotNil(t, got.reload)
+	got.reload = nil
 	assert.NotNil(t, got.Validator)
 	got.Validator = nil
+	assert.NotNil(t, got.Workers)
+	got.Workers = nil
+	assert.False(t, got.StartedAt.IsZero())
+	got.StartedAt = time.Time{}
 	assert.Equal(t, want, got)
 }
 
@@ -58,17 +80,25 @@ func TestTestContext_WithLogBuffer(t *testing.T) {
 	level := &slog.LevelVar{}
 	level.Set(slog.LevelInfo)
 	opts := &slog.HandlerOptions{AddSource: false, Level: level}
-	logger := slog.New(slog.NewTextHandler(io.Discard, opts))
+	logger := slog.New(newRequestIDHandler(slog.NewTextHandler(io.Discard, opts)))
 	want := &Context{
-		Logger:   logger,
-		LogLevel: level,
-		Config:   config.DefaultConfig(),
+		Logger:     logger,
+		LogLevel:   level,
+		Config:     config.DefaultConfig(),
+		Extensions: extension.NewRegistry(),
 	}
 	got := TestContext(io.Discard)
 	got.done = nil
 	got.sigs = nil
+	got.reloadSigs = nil
+	assert.NotNil(t, got.reload)
+	got.reload = nil
 	assert.NotNil(t, got.Validator)
 	got.Validator = nil
+	assert.NotNil(t, got.Workers)
+	got.Workers = nil
+	assert.False(t, got.StartedAt.IsZero())
+	got.StartedAt = time.Time{}
 	assert.Equal(t, want, got)
 }
 
@@ -136,3 +166,110 @@ func TestContext_GetLogLevel(t *testing.T) {
 	}
 	assert.Equalf(t, logLevel, c.GetLogLevel(), "GetLogLevel()")
 }
+
+func validConfig() *config.Config {
+	cfg := config.DefaultConfig()
+	cfg.DB.Type = "sqlite"
+	cfg.Auth.JWT.Secret = "test-secret-key-32-bytes-long!!!"
+	return cfg
+}
+
+func TestContext_ReloadConfig(t *testing.T) {
+	t.Run("without loader", func(t *testing.T) {
+		c := TestContext(nil)
+		assert.ErrorIs(t, c.ReloadConfig(), ErrConfigReloadUnsupported)
+		assert.Same(t, c.Config, c.CurrentConfig())
+	})
+
+	t.Run("loader error", func(t *testing.T) {
+		errLoad := errors.New("load failed")
+		c := TestContext(nil)
+		c.ConfigLoader = func() (*config.Config, error) {
+			return nil, errLoad
+		}
+		assert.ErrorIs(t, c.ReloadConfig(), errLoad)
+		assert.Same(t, c.Config, c.CurrentConfig())
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		c := TestContext(nil)
+		c.ConfigLoader = func() (*config.Config, error) {
+			cfg := validConfig()
+			cfg.HTTP.Listen = ""
+			return cfg, nil
+		}
+		assert.Error(t, c.ReloadConfig())
+		assert.Same(t, c.Config, c.CurrentConfig())
+	})
+
+	t.Run("invalid log level", func(t *testing.T) {
+		c := TestContext(nil)
+		c.ConfigLoader = func() (*config.Config, error) {
+			cfg := validConfig()
+			cfg.LogLevel = "verbose"
+			return cfg, nil
+		}
+		assert.Error(t, c.ReloadConfig())
+		assert.Same(t, c.Config, c.CurrentConfig())
+		assert.Equal(t, slog.LevelInfo, c.LogLevel.Level())
+	})
+
+	t.Run("swaps the runtime settings", func(t *testing.T) {
+		logBuffer := &bytes.Buffer{}
+		c := TestContext(logBuffer)
+		c.ConfigLoader = func() (*config.Config, error) {
+			cfg := validConfig()
+			cfg.HTTP.Listen = ":9999"
+			cfg.Page.SizeLimit = 42
+			cfg.LogLevel = "debug"
+			return cfg, nil
+		}
+		var hooked *config.Config
+		c.OnConfigReload(func(cfg *config.Config) {
+			hooked = cfg
+		})
+
+		require.NoError(t, c.ReloadConfig())
+		current := c.CurrentConfig()
+		assert.NotSame(t, c.Config, current)
+		assert.Same(t, current, hooked)
+		assert.Equal(t, 42, current.Page.SizeLimit)
+		assert.Equal(t, c.Config.HTTP.Listen, current.HTTP.Listen)
+		assert.Equal(t, config.DefaultConfig().Page.SizeLimit, c.Config.Page.SizeLimit)
+		assert.Equal(t, slog.LevelDebug, c.LogLevel.Level())
+		assert.Contains(t, logBuffer.String(), "configuration reloaded")
+	})
+}
+
+func TestContext_ReadOnly(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		c := TestContext(nil)
+		assert.NoError(t, c.ReadOnlyError())
+	})
+
+	t.Run("enabled until the next reload", func(t *testing.T) {
+		c := TestContext(nil)
+		c.ConfigLoader = func() (*config.Config, error) {
+			return validConfig(), nil
+		}
+
+		c.SetReadOnly(config.ReadOnlyConfig{Enabled: true})
+		err := flectoErrors.As(c.ReadOnlyError())
+		require.NotNil(t, err)
+		assert.Equal(t, flectoErrors.CodeReadOnly, err.Code)
+		assert.False(t, c.Config.ReadOnly.Enabled)
+
+		c.SetReadOnly(config.ReadOnlyConfig{Enabled: true, Message: "database migration"})
+		assert.EqualError(t, c.ReadOnlyError(), "the manager is in read-only mode: database migration")
+
+		require.NoError(t, c.ReloadConfig())
+		assert.NoError(t, c.ReadOnlyError())
+	})
+
+	t.Run("context without constructor", func(t *testing.T) {
+		c := &Context{Config: config.DefaultConfig()}
+		c.SetReadOnly(config.ReadOnlyConfig{Enabled: true})
+		assert.True(t, c.Config.ReadOnly.Enabled)
+		assert.Error(t, c.ReadOnlyError())
+	})
+}
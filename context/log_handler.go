@@ -0,0 +1,32 @@
+package context
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/flectolab/flecto-manager/types"
+)
+
+// requestIDHandler adds the request ID of the context to the messages logged with it
+type requestIDHandler struct {
+	slog.Handler
+}
+
+func newRequestIDHandler(handler slog.Handler) slog.Handler {
+	return &requestIDHandler{Handler: handler}
+}
+
+func (h *requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := types.RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return newRequestIDHandler(h.Handler.WithAttrs(attrs))
+}
+
+func (h *requestIDHandler) WithGroup(name string) slog.Handler {
+	return newRequestIDHandler(h.Handler.WithGroup(name))
+}
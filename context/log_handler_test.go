@@ -0,0 +1,22 @@
+package context
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDHandler(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := TestContext(buffer).Logger.With("service", "test")
+
+	logger.InfoContext(types.WithRequestID(context.Background(), "req-1"), "with request")
+	assert.Contains(t, buffer.String(), `msg="with request" service=test request_id=req-1`)
+
+	buffer.Reset()
+	logger.InfoContext(context.Background(), "without request")
+	assert.NotContains(t, buffer.String(), "request_id")
+}
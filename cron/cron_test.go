@@ -0,0 +1,167 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	// Friday
+	from := time.Date(2026, 10, 16, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		spec     string
+		expected []time.Time
+	}{
+		{
+			spec: "* * * * *",
+			expected: []time.Time{
+				time.Date(2026, 10, 16, 10, 31, 0, 0, time.UTC),
+				time.Date(2026, 10, 16, 10, 32, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "*/15 * * * *",
+			expected: []time.Time{
+				time.Date(2026, 10, 16, 10, 45, 0, 0, time.UTC),
+				time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 3 * * *",
+			expected: []time.Time{
+				time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "30 2 * * mon-fri",
+			expected: []time.Time{
+				time.Date(2026, 10, 19, 2, 30, 0, 0, time.UTC),
+				time.Date(2026, 10, 20, 2, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 * * 7",
+			expected: []time.Time{
+				time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 12 1,15 JAN,jul *",
+			expected: []time.Time{
+				time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC),
+				time.Date(2027, 1, 15, 12, 0, 0, 0, time.UTC),
+				time.Date(2027, 7, 1, 12, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "10-20/5 8 * * *",
+			expected: []time.Time{
+				time.Date(2026, 10, 17, 8, 10, 0, 0, time.UTC),
+				time.Date(2026, 10, 17, 8, 15, 0, 0, time.UTC),
+				time.Date(2026, 10, 17, 8, 20, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 1 * sun",
+			expected: []time.Time{
+				time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 10, 25, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				time.Date(2026, 11, 8, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "0 0 29 2 *",
+			expected: []time.Time{
+				time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "@weekly",
+			expected: []time.Time{
+				time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "@hourly",
+			expected: []time.Time{
+				time.Date(2026, 10, 16, 11, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			spec: "@every 90s",
+			expected: []time.Time{
+				time.Date(2026, 10, 16, 10, 31, 30, 0, time.UTC),
+				time.Date(2026, 10, 16, 10, 33, 0, 0, time.UTC),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			require.NoError(t, err)
+
+			next := from
+			var got []time.Time
+			for range tt.expected {
+				next = schedule.Next(next)
+				got = append(got, next)
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"* * * * fun",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"0 0 30 2 *",
+		"@every",
+		"@every 1ms",
+		"@every soon",
+	} {
+		t.Run(spec, func(t *testing.T) {
+			_, err := Parse(spec)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestExpression_Next(t *testing.T) {
+	t.Run("seconds of the current minute skipped", func(t *testing.T) {
+		schedule, err := Parse("* * * * *")
+		require.NoError(t, err)
+
+		assert.Equal(t, time.Date(2026, 10, 16, 10, 31, 0, 0, time.UTC), schedule.Next(time.Date(2026, 10, 16, 10, 30, 59, 0, time.UTC)))
+	})
+
+	t.Run("location of the time", func(t *testing.T) {
+		paris, err := time.LoadLocation("Europe/Paris")
+		if err != nil {
+			t.Skip("time zone database not available")
+		}
+		schedule, err := Parse("0 3 * * *")
+		require.NoError(t, err)
+
+		// The clocks go back from 3:00 to 2:00 on October 25, 2026 in Paris
+		next := schedule.Next(time.Date(2026, 10, 24, 12, 0, 0, 0, paris))
+		assert.Equal(t, time.Date(2026, 10, 25, 3, 0, 0, 0, paris), next)
+		assert.Equal(t, time.Date(2026, 10, 26, 3, 0, 0, 0, paris), schedule.Next(next))
+	})
+}
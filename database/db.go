@@ -1,12 +1,14 @@
 package database
 
 import (
+	"database/sql"
 	"fmt"
 	"sync"
 
 	"github.com/flectolab/flecto-manager/config"
 	"github.com/flectolab/flecto-manager/context"
 	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/storage"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
@@ -17,17 +19,46 @@ var (
 	Models     = []interface{}{
 		model.Namespace{},
 		model.Project{},
+		model.ProjectEnvironment{},
 		model.User{},
 		model.Redirect{},
 		model.RedirectDraft{},
 		model.Page{},
 		model.PageDraft{},
+		model.PageTemplate{},
 		model.ResourcePermission{},
 		model.AdminPermission{},
 		model.Role{},
 		model.UserRole{},
+		model.RoleParent{},
+		model.UserPasswordHistory{},
+		model.RefreshToken{},
 		model.Agent{},
+		model.AgentInstance{},
+		model.AgentInstanceProject{},
 		model.Token{},
+		model.ImportJob{},
+		model.Tag{},
+		model.RedirectTag{},
+		model.RedirectDraftTag{},
+		model.RedirectHealth{},
+		model.RedirectHit{},
+		model.PageHit{},
+		model.ProjectGitSync{},
+		model.DraftLock{},
+		model.NotificationSubscription{},
+		model.RedirectTombstone{},
+		model.PageTombstone{},
+		model.ProjectAPIKey{},
+		model.PageBrokenLink{},
+		model.NamespacePolicy{},
+		model.ProjectMember{},
+		model.Group{},
+		model.UserGroup{},
+		model.GroupRole{},
+		model.Changeset{},
+		model.ImportProfile{},
+		model.OutboxEvent{},
 	}
 )
 
@@ -39,26 +70,42 @@ func CreateDB(ctx *context.Context) (*gorm.DB, error) {
 	if dbInstance == nil {
 		mutex.Lock()
 		defer mutex.Unlock()
-		dbConfig := ctx.Config.DB
-		dbCfg := &gorm.Config{
-			Logger: logger.NewSlogLogger(ctx.Logger, logger.Config{LogLevel: getGormLogLevel(dbConfig.LogLevel), Colorful: true}),
+		db, err := openDB(ctx, ctx.Config.DB)
+		if err != nil {
+			return nil, err
 		}
-		var err error
-		var dialector gorm.Dialector
-		if fn, ok := FactoryDialector[dbConfig.Type]; ok {
-			dialector, err = fn(ctx, dbConfig)
-			if err != nil {
-				return nil, err
+
+		if err = registerQueryCounter(db); err != nil {
+			return nil, fmt.Errorf("DB: failed to register query counter: %v", err)
+		}
+		if threshold := ctx.Config.DB.SlowQueryThreshold; threshold > 0 {
+			if err = registerSlowQueryLog(db, ctx.Logger, threshold); err != nil {
+				return nil, fmt.Errorf("DB: failed to register slow query log: %v", err)
 			}
 		}
 
-		if dialector == nil {
-			return nil, fmt.Errorf("config db type '%s' does not exist", dbConfig.Type)
+		if len(ctx.Config.DB.Shards) > 0 {
+			shardRouter, errShards := createShardRouter(ctx)
+			if errShards != nil {
+				return nil, errShards
+			}
+			if errShards = db.Use(shardRouter); errShards != nil {
+				return nil, fmt.Errorf("DB: failed to register shard router: %v", errShards)
+			}
 		}
 
-		db, errDbOpen := gorm.Open(dialector, dbCfg)
-		if errDbOpen != nil {
-			return nil, fmt.Errorf("DB: failed to create database connexion: %v", errDbOpen)
+		// The replica resolver is registered after the shard router so that the writes go through it
+		if len(ctx.Config.DB.Replicas) > 0 {
+			replicaResolver, errReplicas := createReplicaResolver(ctx, ctx.Config.DB)
+			if errReplicas != nil {
+				return nil, errReplicas
+			}
+			if errReplicas = db.Use(replicaResolver); errReplicas != nil {
+				return nil, fmt.Errorf("DB: failed to register replicas: %v", errReplicas)
+			}
+			if errReplicas = registerPrimaryContext(db); errReplicas != nil {
+				return nil, fmt.Errorf("DB: failed to register replicas: %v", errReplicas)
+			}
 		}
 
 		dbInstance = db
@@ -66,6 +113,95 @@ func CreateDB(ctx *context.Context) (*gorm.DB, error) {
 	return dbInstance, nil
 }
 
+func createShardRouter(ctx *context.Context) (*ShardRouter, error) {
+	shards := make(map[string]*gorm.DB, len(ctx.Config.DB.Shards))
+	namespaces := make(map[string]string)
+	for _, shardConfig := range ctx.Config.DB.Shards {
+		if _, ok := shards[shardConfig.Name]; ok {
+			return nil, fmt.Errorf("config db shard '%s' is defined twice", shardConfig.Name)
+		}
+		for _, namespaceCode := range shardConfig.Namespaces {
+			if name, ok := namespaces[namespaceCode]; ok {
+				return nil, fmt.Errorf("config db namespace '%s' belongs to shards '%s' and '%s'", namespaceCode, name, shardConfig.Name)
+			}
+			namespaces[namespaceCode] = shardConfig.Name
+		}
+		shard, err := openDB(ctx, shardConfig.DbConfig())
+		if err != nil {
+			return nil, fmt.Errorf("DB: shard '%s': %v", shardConfig.Name, err)
+		}
+		shards[shardConfig.Name] = shard
+	}
+	return NewShardRouter(shards, namespaces)
+}
+
+func openDB(ctx *context.Context, dbConfig config.DbConfig) (*gorm.DB, error) {
+	dbCfg := &gorm.Config{
+		Logger: logger.NewSlogLogger(ctx.Logger, logger.Config{LogLevel: getGormLogLevel(dbConfig.LogLevel), Colorful: true}),
+	}
+	dialector, err := createDialector(ctx, dbConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	db, errDbOpen := gorm.Open(dialector, dbCfg)
+	if errDbOpen != nil {
+		return nil, fmt.Errorf("DB: failed to create database connexion: %v", errDbOpen)
+	}
+	sqlDB, errPool := db.DB()
+	if errPool != nil {
+		return nil, fmt.Errorf("DB: failed to configure connection pool: %v", errPool)
+	}
+	configurePool(sqlDB, dbConfig.Pool)
+
+	pageCompression, errCompression := NewPageCompression(ctx.Config.Page.Compression)
+	if errCompression != nil {
+		return nil, fmt.Errorf("DB: failed to create page compression: %v", errCompression)
+	}
+	contentStore, errStorage := storage.New(ctx.Config.Page.Storage)
+	if errStorage != nil {
+		return nil, fmt.Errorf("DB: failed to create page storage: %v", errStorage)
+	}
+	contentReaders, errStorage := storage.Configured(ctx.Config.Page.Storage)
+	if errStorage != nil {
+		return nil, fmt.Errorf("DB: failed to create page storage: %v", errStorage)
+	}
+	pageCompression.WithStorage(NewPageStorage(contentStore, ctx.Config.Page.Storage.MinSize, contentReaders...))
+	if errCompression = db.Use(pageCompression); errCompression != nil {
+		return nil, fmt.Errorf("DB: failed to register page compression: %v", errCompression)
+	}
+	return db, nil
+}
+
+func createDialector(ctx *context.Context, dbConfig config.DbConfig) (gorm.Dialector, error) {
+	var err error
+	var dialector gorm.Dialector
+	if fn, ok := FactoryDialector[dbConfig.Type]; ok {
+		dialector, err = fn(ctx, dbConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if dialector == nil {
+		return nil, fmt.Errorf("config db type '%s' does not exist", dbConfig.Type)
+	}
+	return dialector, nil
+}
+
+// configurePool applies the settings of the pool which are set, the others keeping the defaults of database/sql
+func configurePool(pool *sql.DB, poolConfig config.DbPoolConfig) {
+	if poolConfig.MaxOpenConns > 0 {
+		pool.SetMaxOpenConns(poolConfig.MaxOpenConns)
+	}
+	if poolConfig.MaxIdleConns > 0 {
+		pool.SetMaxIdleConns(poolConfig.MaxIdleConns)
+	}
+	if poolConfig.ConnMaxLifetime > 0 {
+		pool.SetConnMaxLifetime(poolConfig.ConnMaxLifetime)
+	}
+}
+
 // getGormLogLevel converts DbLogLevel to gorm logger.LogLevel
 func getGormLogLevel(level config.DbLogLevel) logger.LogLevel {
 	switch level {
@@ -3,6 +3,7 @@ package database
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/flectolab/flecto-manager/config"
 	"github.com/flectolab/flecto-manager/context"
@@ -18,17 +19,46 @@ func TestModels(t *testing.T) {
 		expectedModels := []interface{}{
 			model.Namespace{},
 			model.Project{},
+			model.ProjectEnvironment{},
 			model.User{},
 			model.Redirect{},
 			model.RedirectDraft{},
 			model.Page{},
 			model.PageDraft{},
+			model.PageTemplate{},
 			model.ResourcePermission{},
 			model.AdminPermission{},
 			model.Role{},
 			model.UserRole{},
+			model.RoleParent{},
+			model.UserPasswordHistory{},
+			model.RefreshToken{},
 			model.Agent{},
+			model.AgentInstance{},
+			model.AgentInstanceProject{},
 			model.Token{},
+			model.ImportJob{},
+			model.Tag{},
+			model.RedirectTag{},
+			model.RedirectDraftTag{},
+			model.RedirectHealth{},
+			model.RedirectHit{},
+			model.PageHit{},
+			model.ProjectGitSync{},
+			model.DraftLock{},
+			model.NotificationSubscription{},
+			model.RedirectTombstone{},
+			model.PageTombstone{},
+			model.ProjectAPIKey{},
+			model.PageBrokenLink{},
+			model.NamespacePolicy{},
+			model.ProjectMember{},
+			model.Group{},
+			model.UserGroup{},
+			model.GroupRole{},
+			model.Changeset{},
+			model.ImportProfile{},
+			model.OutboxEvent{},
 		}
 
 		assert.Equal(t, len(expectedModels), len(Models))
@@ -37,8 +67,8 @@ func TestModels(t *testing.T) {
 		}
 	})
 
-	t.Run("models count is 13", func(t *testing.T) {
-		assert.Len(t, Models, 13)
+	t.Run("models count is 42", func(t *testing.T) {
+		assert.Len(t, Models, 42)
 	})
 }
 
@@ -177,6 +207,78 @@ func TestCreateDB(t *testing.T) {
 
 		assert.Same(t, db1, db2)
 	})
+
+	t.Run("success with shards", func(t *testing.T) {
+		dbInstance = nil
+		FactoryDialector = map[string]CreateDialectorFn{
+			DbTypeSqlite: CreateDialectorSqlite,
+		}
+
+		ctx := context.TestContext(nil)
+		ctx.Config.DB = config.DbConfig{
+			Type:   DbTypeSqlite,
+			Config: map[string]interface{}{"dsn": ":memory:"},
+			Shards: []config.DbShardConfig{
+				{Name: "eu", Type: DbTypeSqlite, Config: map[string]interface{}{"dsn": ":memory:"}, Namespaces: []string{"ns1", "ns2"}},
+			},
+		}
+
+		db, err := CreateDB(ctx)
+
+		require.NoError(t, err)
+		assert.IsType(t, &ShardRouter{}, db.ConnPool)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		assert.NotNil(t, sqlDB)
+		dbInstance = nil
+	})
+
+	t.Run("error when a namespace belongs to two shards", func(t *testing.T) {
+		dbInstance = nil
+		FactoryDialector = map[string]CreateDialectorFn{
+			DbTypeSqlite: CreateDialectorSqlite,
+		}
+
+		ctx := context.TestContext(nil)
+		ctx.Config.DB = config.DbConfig{
+			Type:   DbTypeSqlite,
+			Config: map[string]interface{}{"dsn": ":memory:"},
+			Shards: []config.DbShardConfig{
+				{Name: "eu", Type: DbTypeSqlite, Config: map[string]interface{}{"dsn": ":memory:"}, Namespaces: []string{"ns1"}},
+				{Name: "us", Type: DbTypeSqlite, Config: map[string]interface{}{"dsn": ":memory:"}, Namespaces: []string{"ns1"}},
+			},
+		}
+
+		_, err := CreateDB(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "belongs to shards")
+		dbInstance = nil
+	})
+
+	t.Run("success with pool settings", func(t *testing.T) {
+		dbInstance = nil
+		FactoryDialector = map[string]CreateDialectorFn{
+			DbTypeSqlite: CreateDialectorSqlite,
+		}
+
+		ctx := context.TestContext(nil)
+		ctx.Config.DB = config.DbConfig{
+			Type:               DbTypeSqlite,
+			Config:             map[string]interface{}{"dsn": ":memory:"},
+			Pool:               config.DbPoolConfig{MaxOpenConns: 3, MaxIdleConns: 2, ConnMaxLifetime: time.Minute},
+			SlowQueryThreshold: time.Second,
+		}
+
+		db, err := CreateDB(ctx)
+
+		require.NoError(t, err)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		assert.Equal(t, 3, sqlDB.Stats().MaxOpenConnections)
+		assert.NotNil(t, db.Callback().Query().Get(slowQueryLogCallback))
+		dbInstance = nil
+	})
 }
 
 func TestGetGormLogLevel(t *testing.T) {
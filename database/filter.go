@@ -0,0 +1,180 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxFilterDepth is the maximum nesting of and/or groups in a filter
+	MaxFilterDepth = 5
+	// MaxFilterConditions is the maximum number of conditions in a filter
+	MaxFilterConditions = 50
+)
+
+type FilterOperator string
+
+const (
+	FilterEQ         FilterOperator = "EQ"
+	FilterNEQ        FilterOperator = "NEQ"
+	FilterContains   FilterOperator = "CONTAINS"
+	FilterStartsWith FilterOperator = "STARTS_WITH"
+	FilterEndsWith   FilterOperator = "ENDS_WITH"
+	FilterGT         FilterOperator = "GT"
+	FilterGTE        FilterOperator = "GTE"
+	FilterLT         FilterOperator = "LT"
+	FilterLTE        FilterOperator = "LTE"
+	FilterIn         FilterOperator = "IN"
+	FilterIsNull     FilterOperator = "IS_NULL"
+	FilterIsNotNull  FilterOperator = "IS_NOT_NULL"
+)
+
+// FilterInput is a condition on a column, a group of filters, or both.
+// A condition has a Field and an Operator, IN uses Values and the null checks have no value.
+// All the parts of a filter must match: the condition, every filter of And and at least one filter of Or.
+type FilterInput struct {
+	And      []FilterInput
+	Or       []FilterInput
+	Field    *string
+	Operator *FilterOperator
+	Value    *string
+	Values   []string
+}
+
+// ApplyFilter adds the conditions of a filter to a GORM query
+// allowedColumns: map[jsonName]dbColumnName
+// tablePrefix: table prefix for joins (optional, "" without join)
+func ApplyFilter(query *gorm.DB, allowedColumns map[string]string, filter *FilterInput, tablePrefix string) (*gorm.DB, error) {
+	if filter == nil {
+		return query, nil
+	}
+
+	conditions := 0
+	sql, args, err := buildFilter(*filter, allowedColumns, tablePrefix, 1, &conditions)
+	if err != nil {
+		return nil, err
+	}
+	if sql == "" {
+		return query, nil
+	}
+	return query.Where(sql, args...), nil
+}
+
+func buildFilter(filter FilterInput, allowedColumns map[string]string, tablePrefix string, depth int, conditions *int) (string, []interface{}, error) {
+	if depth > MaxFilterDepth {
+		return "", nil, fmt.Errorf("filter cannot be nested more than %d levels", MaxFilterDepth)
+	}
+
+	var parts []string
+	var args []interface{}
+
+	if filter.Field != nil || filter.Operator != nil {
+		*conditions++
+		if *conditions > MaxFilterConditions {
+			return "", nil, fmt.Errorf("filter cannot have more than %d conditions", MaxFilterConditions)
+		}
+		sql, conditionArgs, err := buildFilterCondition(filter, allowedColumns, tablePrefix)
+		if err != nil {
+			return "", nil, err
+		}
+		parts = append(parts, sql)
+		args = append(args, conditionArgs...)
+	}
+
+	for _, group := range []struct {
+		filters   []FilterInput
+		separator string
+	}{{filter.And, " AND "}, {filter.Or, " OR "}} {
+		var groupParts []string
+		for _, sub := range group.filters {
+			sql, subArgs, err := buildFilter(sub, allowedColumns, tablePrefix, depth+1, conditions)
+			if err != nil {
+				return "", nil, err
+			}
+			if sql == "" {
+				continue
+			}
+			groupParts = append(groupParts, sql)
+			args = append(args, subArgs...)
+		}
+		if len(groupParts) > 0 {
+			parts = append(parts, "("+strings.Join(groupParts, group.separator)+")")
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", nil, nil
+	}
+	return "(" + strings.Join(parts, " AND ") + ")", args, nil
+}
+
+func buildFilterCondition(filter FilterInput, allowedColumns map[string]string, tablePrefix string) (string, []interface{}, error) {
+	if filter.Field == nil || filter.Operator == nil {
+		return "", nil, fmt.Errorf("filter condition requires a field and an operator")
+	}
+	col, ok := allowedColumns[*filter.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("field %s cannot be filtered", *filter.Field)
+	}
+	if tablePrefix != "" {
+		col = tablePrefix + "." + col
+	}
+
+	operator := *filter.Operator
+	switch operator {
+	case FilterIsNull:
+		return col + " IS NULL", nil, nil
+	case FilterIsNotNull:
+		return col + " IS NOT NULL", nil, nil
+	case FilterIn:
+		if len(filter.Values) == 0 {
+			return "", nil, fmt.Errorf("operator %s on field %s requires values", operator, *filter.Field)
+		}
+		return col + " IN ?", []interface{}{filter.Values}, nil
+	}
+
+	if filter.Value == nil {
+		return "", nil, fmt.Errorf("operator %s on field %s requires a value", operator, *filter.Field)
+	}
+	value := *filter.Value
+
+	switch operator {
+	case FilterEQ:
+		return col + " = ?", []interface{}{filterValue(value)}, nil
+	case FilterNEQ:
+		return col + " <> ?", []interface{}{filterValue(value)}, nil
+	case FilterGT:
+		return col + " > ?", []interface{}{filterValue(value)}, nil
+	case FilterGTE:
+		return col + " >= ?", []interface{}{filterValue(value)}, nil
+	case FilterLT:
+		return col + " < ?", []interface{}{filterValue(value)}, nil
+	case FilterLTE:
+		return col + " <= ?", []interface{}{filterValue(value)}, nil
+	case FilterContains:
+		return col + " LIKE ? ESCAPE '!'", []interface{}{"%" + EscapeLike(value) + "%"}, nil
+	case FilterStartsWith:
+		return col + " LIKE ? ESCAPE '!'", []interface{}{EscapeLike(value) + "%"}, nil
+	case FilterEndsWith:
+		return col + " LIKE ? ESCAPE '!'", []interface{}{"%" + EscapeLike(value)}, nil
+	default:
+		return "", nil, fmt.Errorf("unknown filter operator %s", operator)
+	}
+}
+
+// filterValue converts RFC 3339 dates, so that they are compared as dates by the database
+func filterValue(value string) interface{} {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t
+	}
+	return value
+}
+
+// EscapeLike escapes the wildcards of a LIKE pattern, '!' being used as escape character
+// as backslash is not an escape character in SQLite
+func EscapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
@@ -0,0 +1,161 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type filterTestRow struct {
+	ID        int64
+	Source    string
+	Status    string
+	Note      *string
+	UpdatedAt time.Time
+}
+
+func setupFilterTestDB(t *testing.T) *gorm.DB {
+	db := setupSortTestDB(t)
+	assert.NoError(t, db.AutoMigrate(&filterTestRow{}))
+
+	note := "note"
+	rows := []filterTestRow{
+		{ID: 1, Source: "/blog/first", Status: "MOVED_PERMANENT", UpdatedAt: time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, Source: "/blog/second", Status: "FOUND", Note: &note, UpdatedAt: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{ID: 3, Source: "/shop/100%_off", Status: "MOVED_PERMANENT", UpdatedAt: time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)},
+		{ID: 4, Source: "/about", Status: "FOUND", UpdatedAt: time.Date(2026, 7, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	assert.NoError(t, db.Create(&rows).Error)
+	return db
+}
+
+func filterCondition(field string, operator FilterOperator, value string) FilterInput {
+	return FilterInput{Field: &field, Operator: &operator, Value: &value}
+}
+
+func TestApplyFilter(t *testing.T) {
+	allowedColumns := map[string]string{
+		"source":    "source",
+		"status":    "status",
+		"note":      "note",
+		"updatedAt": "updated_at",
+	}
+	db := setupFilterTestDB(t)
+
+	find := func(t *testing.T, filter *FilterInput) []int64 {
+		query, err := ApplyFilter(db.Model(&filterTestRow{}), allowedColumns, filter, "")
+		assert.NoError(t, err)
+		var ids []int64
+		assert.NoError(t, query.Order("id").Pluck("id", &ids).Error)
+		return ids
+	}
+
+	t.Run("nil and empty filters return query unchanged", func(t *testing.T) {
+		assert.Equal(t, []int64{1, 2, 3, 4}, find(t, nil))
+		assert.Equal(t, []int64{1, 2, 3, 4}, find(t, &FilterInput{}))
+		assert.Equal(t, []int64{1, 2, 3, 4}, find(t, &FilterInput{And: []FilterInput{{}}}))
+	})
+
+	t.Run("operators", func(t *testing.T) {
+		isNull := FilterIsNull
+		isNotNull := FilterIsNotNull
+		in := FilterIn
+		source := "source"
+		note := "note"
+		tests := []struct {
+			name     string
+			filter   FilterInput
+			expected []int64
+		}{
+			{"EQ", filterCondition("status", FilterEQ, "FOUND"), []int64{2, 4}},
+			{"NEQ", filterCondition("status", FilterNEQ, "FOUND"), []int64{1, 3}},
+			{"CONTAINS", filterCondition("source", FilterContains, "blog"), []int64{1, 2}},
+			{"CONTAINS escapes wildcards", filterCondition("source", FilterContains, "%_"), []int64{3}},
+			{"STARTS_WITH", filterCondition("source", FilterStartsWith, "/shop"), []int64{3}},
+			{"ENDS_WITH", filterCondition("source", FilterEndsWith, "second"), []int64{2}},
+			{"GT date", filterCondition("updatedAt", FilterGT, "2026-03-10T00:00:00Z"), []int64{3, 4}},
+			{"GTE date", filterCondition("updatedAt", FilterGTE, "2026-03-10T00:00:00Z"), []int64{2, 3, 4}},
+			{"LT date", filterCondition("updatedAt", FilterLT, "2026-03-10T00:00:00Z"), []int64{1}},
+			{"LTE date", filterCondition("updatedAt", FilterLTE, "2026-03-10T00:00:00Z"), []int64{1, 2}},
+			{"IN", FilterInput{Field: &source, Operator: &in, Values: []string{"/about", "/blog/first"}}, []int64{1, 4}},
+			{"IS_NULL", FilterInput{Field: &note, Operator: &isNull}, []int64{1, 3, 4}},
+			{"IS_NOT_NULL", FilterInput{Field: &note, Operator: &isNotNull}, []int64{2}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				assert.Equal(t, tt.expected, find(t, &tt.filter))
+			})
+		}
+	})
+
+	t.Run("and group", func(t *testing.T) {
+		filter := &FilterInput{And: []FilterInput{
+			filterCondition("source", FilterContains, "/blog"),
+			filterCondition("status", FilterEQ, "MOVED_PERMANENT"),
+			filterCondition("updatedAt", FilterGT, "2026-01-01T00:00:00Z"),
+		}}
+
+		assert.Equal(t, []int64{1}, find(t, filter))
+	})
+
+	t.Run("or group combined with a condition", func(t *testing.T) {
+		filter := filterCondition("status", FilterEQ, "FOUND")
+		filter.Or = []FilterInput{
+			filterCondition("source", FilterStartsWith, "/blog"),
+			{And: []FilterInput{filterCondition("source", FilterEQ, "/about")}},
+		}
+
+		assert.Equal(t, []int64{2, 4}, find(t, &filter))
+	})
+
+	t.Run("table prefix", func(t *testing.T) {
+		filter := filterCondition("status", FilterEQ, "FOUND")
+		query, err := ApplyFilter(db.Model(&filterTestRow{}), allowedColumns, &filter, "filter_test_rows")
+		assert.NoError(t, err)
+
+		var ids []int64
+		assert.NoError(t, query.Order("id").Pluck("id", &ids).Error)
+		assert.Equal(t, []int64{2, 4}, ids)
+	})
+}
+
+func TestApplyFilter_Errors(t *testing.T) {
+	allowedColumns := map[string]string{"source": "source"}
+	db := setupSortTestDB(t)
+	source := "source"
+	in := FilterIn
+	unknown := FilterOperator("LIKE")
+
+	deep := filterCondition("source", FilterEQ, "/a")
+	for i := 0; i < MaxFilterDepth; i++ {
+		deep = FilterInput{And: []FilterInput{deep}}
+	}
+	many := FilterInput{}
+	for i := 0; i <= MaxFilterConditions; i++ {
+		many.Or = append(many.Or, filterCondition("source", FilterEQ, "/a"))
+	}
+
+	tests := []struct {
+		name   string
+		filter FilterInput
+		err    string
+	}{
+		{"unknown field", filterCondition("password", FilterEQ, "x"), "field password cannot be filtered"},
+		{"missing operator", FilterInput{Field: &source}, "filter condition requires a field and an operator"},
+		{"missing value", FilterInput{Field: &source, Operator: &unknown}, "operator LIKE on field source requires a value"},
+		{"missing values", FilterInput{Field: &source, Operator: &in}, "operator IN on field source requires values"},
+		{"unknown operator", filterCondition("source", unknown, "x"), "unknown filter operator LIKE"},
+		{"too deep", deep, "filter cannot be nested more than 5 levels"},
+		{"too many conditions", many, "filter cannot have more than 50 conditions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ApplyFilter(db, allowedColumns, &tt.filter, "")
+
+			assert.Nil(t, query)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,266 @@
+package database
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"reflect"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/klauspost/compress/zstd"
+	"gorm.io/gorm"
+)
+
+const pageCompressionInstanceKey = "flecto:page_compression"
+
+// PageCompression is a gorm plugin compressing the content of pages and page drafts on write and
+// decompressing it on read, so the rest of the application always sees the raw content.
+// Compressed contents are stored base64 encoded as the content columns are text columns.
+// With a PageStorage, the large contents are then moved to its content store.
+type PageCompression struct {
+	cfg     config.PageCompressionConfig
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+	storage *PageStorage
+}
+
+func NewPageCompression(cfg config.PageCompressionConfig) (*PageCompression, error) {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &PageCompression{cfg: cfg, encoder: encoder, decoder: decoder}, nil
+}
+
+// WithStorage stores the compressed contents with storage, and loads them from it before decompressing them
+func (p *PageCompression) WithStorage(storage *PageStorage) *PageCompression {
+	p.storage = storage
+	return p
+}
+
+func (p *PageCompression) Name() string {
+	return "flecto:page_compression"
+}
+
+func (p *PageCompression) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Create().Before("gorm:create").Register("flecto:page_compress_create", p.compress); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:create").Register("flecto:page_restore_create", p.restore); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:update").Register("flecto:page_compress_update", p.compress); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("flecto:page_restore_update", p.restore); err != nil {
+		return err
+	}
+	return db.Callback().Query().After("gorm:query").Register("flecto:page_decompress", p.decompress)
+}
+
+// pageContent is a stored page content with its encoding and store flags
+type pageContent struct {
+	page       *commonTypes.Page
+	encoding   *model.PageContentEncoding
+	storedSize *int64
+	store      *model.PageContentStore
+}
+
+// compress replaces the contents of the written pages by their stored form, the raw
+// contents being kept to be restored once the statement is executed
+func (p *PageCompression) compress(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	contents := pageContents(db)
+	if len(contents) == 0 {
+		return
+	}
+	raw := make(map[*commonTypes.Page]string, len(contents))
+	for _, content := range contents {
+		if _, ok := raw[content.page]; ok {
+			continue
+		}
+		stored, encoding, err := p.encode(content.page.Content)
+		if err != nil {
+			_ = db.AddError(fmt.Errorf("failed to compress page content: %w", err))
+			return
+		}
+		ref, store, err := p.storage.put(db.Statement.Context, stored)
+		if err != nil {
+			_ = db.AddError(fmt.Errorf("failed to store page content: %w", err))
+			return
+		}
+		raw[content.page] = content.page.Content
+		content.page.Content = ref
+		*content.encoding = encoding
+		*content.storedSize = int64(len(stored))
+		*content.store = store
+	}
+	db.InstanceSet(pageCompressionInstanceKey, raw)
+}
+
+// restore puts back the raw contents of the written pages
+func (p *PageCompression) restore(db *gorm.DB) {
+	value, ok := db.InstanceGet(pageCompressionInstanceKey)
+	if !ok {
+		return
+	}
+	for page, content := range value.(map[*commonTypes.Page]string) {
+		page.Content = content
+	}
+}
+
+// decompress loads and decodes the contents of the loaded pages, their encoding and store flags still telling
+// how they are stored
+func (p *PageCompression) decompress(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	for _, content := range pageContents(db) {
+		if content.page.Content == "" {
+			continue
+		}
+		if *content.store != model.PageContentStoreDatabase {
+			stored, err := p.storage.get(db.Statement.Context, content.page.Content, *content.store)
+			if err != nil {
+				_ = db.AddError(fmt.Errorf("failed to load page content: %w", err))
+				return
+			}
+			content.page.Content = stored
+		}
+		if *content.encoding == model.PageContentEncodingIdentity {
+			continue
+		}
+		decoded, err := p.decode(content.page.Content, *content.encoding)
+		if err != nil {
+			_ = db.AddError(fmt.Errorf("failed to decompress page content: %w", err))
+			return
+		}
+		content.page.Content = decoded
+	}
+}
+
+// encode returns the stored form of a content, it is left as is when compression is disabled,
+// the content is smaller than the minimum size or compressing does not reduce its size
+func (p *PageCompression) encode(content string) (string, model.PageContentEncoding, error) {
+	if p.cfg.Algorithm == "" || p.cfg.Algorithm == config.PageCompressionNone || len(content) < p.cfg.MinSize {
+		return content, model.PageContentEncodingIdentity, nil
+	}
+
+	var compressed []byte
+	var encoding model.PageContentEncoding
+	switch p.cfg.Algorithm {
+	case config.PageCompressionGzip:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write([]byte(content)); err != nil {
+			return "", "", err
+		}
+		if err := writer.Close(); err != nil {
+			return "", "", err
+		}
+		compressed = buf.Bytes()
+		encoding = model.PageContentEncodingGzip
+	case config.PageCompressionZstd:
+		compressed = p.encoder.EncodeAll([]byte(content), nil)
+		encoding = model.PageContentEncodingZstd
+	default:
+		return "", "", fmt.Errorf("unknown compression algorithm '%s'", p.cfg.Algorithm)
+	}
+
+	stored := base64.StdEncoding.EncodeToString(compressed)
+	if len(stored) >= len(content) {
+		return content, model.PageContentEncodingIdentity, nil
+	}
+	return stored, encoding, nil
+}
+
+// decode returns the raw content of a stored content
+func (p *PageCompression) decode(stored string, encoding model.PageContentEncoding) (string, error) {
+	compressed, err := base64.StdEncoding.DecodeString(stored)
+	if err != nil {
+		return "", err
+	}
+
+	switch encoding {
+	case model.PageContentEncodingGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return "", err
+		}
+		defer reader.Close()
+		content, err := io.ReadAll(reader)
+		if err != nil {
+			return "", err
+		}
+		return string(content), nil
+	case model.PageContentEncodingZstd:
+		content, err := p.decoder.DecodeAll(compressed, nil)
+		if err != nil {
+			return "", err
+		}
+		return string(content), nil
+	default:
+		return "", fmt.Errorf("unknown content encoding '%s'", encoding)
+	}
+}
+
+// pageContents returns the page contents of the statement destination, it can be a page,
+// a page draft, a page tombstone or a slice of them
+func pageContents(db *gorm.DB) []pageContent {
+	if db.Statement.Schema == nil {
+		return nil
+	}
+	switch db.Statement.Schema.ModelType {
+	case reflect.TypeOf(model.Page{}), reflect.TypeOf(model.PageDraft{}), reflect.TypeOf(model.PageTombstone{}):
+	default:
+		return nil
+	}
+
+	contents := make([]pageContent, 0)
+	collect := func(value reflect.Value) {
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				return
+			}
+			value = value.Elem()
+		}
+		if !value.CanAddr() {
+			return
+		}
+		switch item := value.Addr().Interface().(type) {
+		case *model.Page:
+			if item.Page != nil {
+				contents = append(contents, pageContent{page: item.Page, encoding: &item.ContentEncoding, storedSize: &item.StoredContentSize, store: &item.ContentStore})
+			}
+		case *model.PageDraft:
+			if item.NewPage != nil {
+				contents = append(contents, pageContent{page: item.NewPage, encoding: &item.ContentEncoding, storedSize: &item.StoredContentSize, store: &item.ContentStore})
+			}
+		case *model.PageTombstone:
+			if item.Page != nil {
+				contents = append(contents, pageContent{page: item.Page, encoding: &item.ContentEncoding, storedSize: &item.StoredContentSize, store: &item.ContentStore})
+			}
+		}
+	}
+
+	value := db.Statement.ReflectValue
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			collect(value.Index(i))
+		}
+	default:
+		collect(value)
+	}
+	return contents
+}
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupPageCompressionTestDB(t *testing.T, cfg config.PageCompressionConfig) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	pageCompression, err := NewPageCompression(cfg)
+	require.NoError(t, err)
+	require.NoError(t, db.Use(pageCompression))
+
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Page{}, &model.PageDraft{}, &model.PageTombstone{}))
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "ns", Name: "Namespace"}).Error)
+	require.NoError(t, db.Create(&model.Project{NamespaceCode: "ns", ProjectCode: "proj", Name: "Project"}).Error)
+	return db
+}
+
+func newCompressionTestPage(path, content string) *model.Page {
+	return &model.Page{
+		NamespaceCode: "ns",
+		ProjectCode:   "proj",
+		ContentSize:   int64(len(content)),
+		Page: &commonTypes.Page{
+			Type:        commonTypes.PageTypeBasic,
+			Path:        path,
+			Content:     content,
+			ContentType: commonTypes.PageContentTypeTextPlain,
+		},
+	}
+}
+
+func storedPageContent(t *testing.T, db *gorm.DB, table, column string, id int64) string {
+	var content string
+	require.NoError(t, db.Raw("SELECT "+column+" FROM "+table+" WHERE id = ?", id).Scan(&content).Error)
+	return content
+}
+
+func TestPageCompression(t *testing.T) {
+	largeContent := strings.Repeat("<p>flecto compressed page</p>\n", 200)
+
+	for _, algorithm := range []config.PageCompressionAlgorithm{config.PageCompressionGzip, config.PageCompressionZstd} {
+		t.Run(string(algorithm)+" compresses large content", func(t *testing.T) {
+			db := setupPageCompressionTestDB(t, config.PageCompressionConfig{Algorithm: algorithm, MinSize: 1024})
+
+			page := newCompressionTestPage("/large", largeContent)
+			require.NoError(t, db.Create(page).Error)
+
+			assert.Equal(t, largeContent, page.Content)
+			assert.Equal(t, model.PageContentEncoding(algorithm), page.ContentEncoding)
+			assert.Less(t, page.StoredContentSize, page.ContentSize)
+
+			stored := storedPageContent(t, db, "pages", "content", page.ID)
+			assert.NotEqual(t, largeContent, stored)
+			assert.Equal(t, page.StoredContentSize, int64(len(stored)))
+
+			var found model.Page
+			require.NoError(t, db.First(&found, page.ID).Error)
+			assert.Equal(t, largeContent, found.Content)
+			assert.Equal(t, model.PageContentEncoding(algorithm), found.ContentEncoding)
+		})
+	}
+
+	t.Run("keeps content smaller than min size", func(t *testing.T) {
+		db := setupPageCompressionTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionGzip, MinSize: 1024})
+
+		page := newCompressionTestPage("/small", "small content")
+		require.NoError(t, db.Create(page).Error)
+
+		assert.Equal(t, model.PageContentEncodingIdentity, page.ContentEncoding)
+		assert.Equal(t, int64(len("small content")), page.StoredContentSize)
+		assert.Equal(t, "small content", storedPageContent(t, db, "pages", "content", page.ID))
+	})
+
+	t.Run("keeps content when compression does not reduce its size", func(t *testing.T) {
+		db := setupPageCompressionTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionZstd})
+
+		page := newCompressionTestPage("/random", "a1b2c3")
+		require.NoError(t, db.Create(page).Error)
+
+		assert.Equal(t, model.PageContentEncodingIdentity, page.ContentEncoding)
+		assert.Equal(t, "a1b2c3", storedPageContent(t, db, "pages", "content", page.ID))
+	})
+
+	t.Run("disabled compression still reads compressed content", func(t *testing.T) {
+		db := setupPageCompressionTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionZstd})
+		page := newCompressionTestPage("/large", largeContent)
+		require.NoError(t, db.Create(page).Error)
+
+		pageCompression, err := NewPageCompression(config.PageCompressionConfig{Algorithm: config.PageCompressionNone})
+		require.NoError(t, err)
+		uncompressedDB, err := gorm.Open(sqlite.New(sqlite.Config{Conn: db.ConnPool}), &gorm.Config{})
+		require.NoError(t, err)
+		require.NoError(t, uncompressedDB.Use(pageCompression))
+
+		var found model.Page
+		require.NoError(t, uncompressedDB.First(&found, page.ID).Error)
+		assert.Equal(t, largeContent, found.Content)
+
+		require.NoError(t, uncompressedDB.Save(&found).Error)
+		assert.Equal(t, model.PageContentEncodingIdentity, found.ContentEncoding)
+		assert.Equal(t, largeContent, storedPageContent(t, db, "pages", "content", page.ID))
+	})
+
+	t.Run("compresses batches and updates", func(t *testing.T) {
+		db := setupPageCompressionTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionGzip})
+
+		pages := []*model.Page{newCompressionTestPage("/a", largeContent), newCompressionTestPage("/b", largeContent+"b")}
+		require.NoError(t, db.CreateInBatches(pages, 500).Error)
+		assert.Equal(t, largeContent, pages[0].Content)
+		assert.Equal(t, model.PageContentEncodingGzip, pages[1].ContentEncoding)
+
+		pages[0].Content = "updated"
+		require.NoError(t, db.Save(pages[0]).Error)
+		assert.Equal(t, model.PageContentEncodingIdentity, pages[0].ContentEncoding)
+
+		var found []model.Page
+		require.NoError(t, db.Order("id").Find(&found).Error)
+		require.Len(t, found, 2)
+		assert.Equal(t, "updated", found[0].Content)
+		assert.Equal(t, largeContent+"b", found[1].Content)
+	})
+
+	t.Run("compresses page drafts", func(t *testing.T) {
+		db := setupPageCompressionTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionZstd})
+
+		page := newCompressionTestPage("/draft", largeContent)
+		require.NoError(t, db.Create(page).Error)
+		draft := &model.PageDraft{
+			NamespaceCode: "ns",
+			ProjectCode:   "proj",
+			ChangeType:    model.DraftChangeTypeUpdate,
+			OldPageID:     &page.ID,
+			ContentSize:   page.ContentSize,
+			NewPage:       page.Page,
+		}
+		require.NoError(t, db.Create(draft).Error)
+		assert.Equal(t, model.PageContentEncodingZstd, draft.ContentEncoding)
+		assert.NotEqual(t, largeContent, storedPageContent(t, db, "page_drafts", "new_content", draft.ID))
+
+		var found model.PageDraft
+		require.NoError(t, db.Preload("OldPage").First(&found, draft.ID).Error)
+		assert.Equal(t, largeContent, found.NewPage.Content)
+		require.NotNil(t, found.OldPage)
+		assert.Equal(t, largeContent, found.OldPage.Content)
+	})
+
+	t.Run("compresses page tombstones", func(t *testing.T) {
+		db := setupPageCompressionTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionGzip})
+
+		page := newCompressionTestPage("/deleted", largeContent)
+		require.NoError(t, db.Create(page).Error)
+		tombstone := model.NewPageTombstone(*page, "alice", page.CreatedAt)
+		require.NoError(t, db.Create(&tombstone).Error)
+		assert.Equal(t, model.PageContentEncodingGzip, tombstone.ContentEncoding)
+		assert.NotEqual(t, largeContent, storedPageContent(t, db, "page_tombstones", "content", tombstone.ID))
+
+		var found model.PageTombstone
+		require.NoError(t, db.First(&found, tombstone.ID).Error)
+		assert.Equal(t, largeContent, found.Content)
+	})
+
+	t.Run("error on corrupted content", func(t *testing.T) {
+		db := setupPageCompressionTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionGzip})
+		page := newCompressionTestPage("/large", largeContent)
+		require.NoError(t, db.Create(page).Error)
+		require.NoError(t, db.Exec("UPDATE pages SET content = ? WHERE id = ?", "not compressed", page.ID).Error)
+
+		var found model.Page
+		err := db.First(&found, page.ID).Error
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to decompress page content")
+	})
+}
+
+func TestPageCompressionName(t *testing.T) {
+	pageCompression, err := NewPageCompression(config.PageCompressionConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, "flecto:page_compression", pageCompression.Name())
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/storage"
+)
+
+// PageStorage moves the page contents of at least minSize bytes, once compressed, to a content store,
+// their content column holding their key instead. It is run by PageCompression, see WithStorage.
+type PageStorage struct {
+	store   storage.ContentStore
+	minSize int
+	// stores are the stores the contents are read from, the contents stored in another one cannot be read
+	stores map[model.PageContentStore]storage.ContentStore
+}
+
+// NewPageStorage returns a storage writing the contents to store, and reading them from it or from readers
+func NewPageStorage(store storage.ContentStore, minSize int, readers ...storage.ContentStore) *PageStorage {
+	stores := map[model.PageContentStore]storage.ContentStore{model.PageContentStoreDatabase: storage.Database{}}
+	for _, reader := range readers {
+		stores[reader.Name()] = reader
+	}
+	stores[store.Name()] = store
+	return &PageStorage{store: store, minSize: minSize, stores: stores}
+}
+
+// put returns the value of the content column of a stored content and the store holding it
+func (s *PageStorage) put(ctx context.Context, stored string) (string, model.PageContentStore, error) {
+	if s == nil || len(stored) < s.minSize {
+		return stored, model.PageContentStoreDatabase, nil
+	}
+	ref, err := s.store.Put(ctx, stored)
+	if err != nil {
+		return "", "", err
+	}
+	return ref, s.store.Name(), nil
+}
+
+// get returns the stored content of a content column value
+func (s *PageStorage) get(ctx context.Context, ref string, name model.PageContentStore) (string, error) {
+	if name == model.PageContentStoreDatabase {
+		return ref, nil
+	}
+	var store storage.ContentStore
+	if s != nil {
+		store = s.stores[name]
+	}
+	if store == nil {
+		return "", fmt.Errorf("page content store '%s' is not configured", name)
+	}
+	return store.Get(ctx, ref)
+}
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupPageStorageTestDB(t *testing.T, compression config.PageCompressionConfig, minSize int) (*gorm.DB, storage.ContentStore) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	store, err := storage.NewFilesystemStore(config.FilesystemStorageConfig{Path: t.TempDir()})
+	require.NoError(t, err)
+	pageCompression, err := NewPageCompression(compression)
+	require.NoError(t, err)
+	require.NoError(t, db.Use(pageCompression.WithStorage(NewPageStorage(store, minSize))))
+
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Page{}, &model.PageDraft{}, &model.PageTombstone{}))
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "ns", Name: "Namespace"}).Error)
+	require.NoError(t, db.Create(&model.Project{NamespaceCode: "ns", ProjectCode: "proj", Name: "Project"}).Error)
+	return db, store
+}
+
+func TestPageStorage(t *testing.T) {
+	largeContent := strings.Repeat("<p>flecto stored page</p>\n", 200)
+
+	t.Run("stores large content", func(t *testing.T) {
+		db, store := setupPageStorageTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionNone}, 1024)
+
+		page := newCompressionTestPage("/large", largeContent)
+		require.NoError(t, db.Create(page).Error)
+		assert.Equal(t, largeContent, page.Content)
+		assert.Equal(t, model.PageContentStoreFilesystem, page.ContentStore)
+		assert.Equal(t, int64(len(largeContent)), page.StoredContentSize)
+
+		key := storedPageContent(t, db, "pages", "content", page.ID)
+		assert.Len(t, key, 64)
+		content, err := store.Get(t.Context(), key)
+		require.NoError(t, err)
+		assert.Equal(t, largeContent, content)
+
+		var found model.Page
+		require.NoError(t, db.First(&found, page.ID).Error)
+		assert.Equal(t, largeContent, found.Content)
+		assert.Equal(t, model.PageContentStoreFilesystem, found.ContentStore)
+	})
+
+	t.Run("stores the compressed content", func(t *testing.T) {
+		db, store := setupPageStorageTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionGzip}, 16)
+
+		page := newCompressionTestPage("/large", largeContent)
+		require.NoError(t, db.Create(page).Error)
+		assert.Equal(t, model.PageContentEncodingGzip, page.ContentEncoding)
+		assert.Equal(t, model.PageContentStoreFilesystem, page.ContentStore)
+
+		content, err := store.Get(t.Context(), storedPageContent(t, db, "pages", "content", page.ID))
+		require.NoError(t, err)
+		assert.Equal(t, page.StoredContentSize, int64(len(content)))
+
+		var found model.Page
+		require.NoError(t, db.First(&found, page.ID).Error)
+		assert.Equal(t, largeContent, found.Content)
+	})
+
+	t.Run("keeps content smaller than min size", func(t *testing.T) {
+		db, _ := setupPageStorageTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionNone}, 1024)
+
+		page := newCompressionTestPage("/large", largeContent)
+		require.NoError(t, db.Create(page).Error)
+		page.Content = "small content"
+		require.NoError(t, db.Save(page).Error)
+		assert.Equal(t, model.PageContentStoreDatabase, page.ContentStore)
+		assert.Equal(t, "small content", storedPageContent(t, db, "pages", "content", page.ID))
+	})
+
+	t.Run("stores page drafts and tombstones", func(t *testing.T) {
+		db, _ := setupPageStorageTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionNone}, 1024)
+
+		page := newCompressionTestPage("/draft", largeContent)
+		require.NoError(t, db.Create(page).Error)
+		draft := &model.PageDraft{
+			NamespaceCode: "ns",
+			ProjectCode:   "proj",
+			ChangeType:    model.DraftChangeTypeUpdate,
+			OldPageID:     &page.ID,
+			ContentSize:   page.ContentSize,
+			NewPage:       page.Page,
+		}
+		require.NoError(t, db.Create(draft).Error)
+		tombstone := model.NewPageTombstone(*page, "alice", page.CreatedAt)
+		require.NoError(t, db.Create(&tombstone).Error)
+		// The contents share the object of their hash
+		assert.Equal(t, storedPageContent(t, db, "pages", "content", page.ID), storedPageContent(t, db, "page_drafts", "new_content", draft.ID))
+
+		var foundDraft model.PageDraft
+		require.NoError(t, db.Preload("OldPage").First(&foundDraft, draft.ID).Error)
+		assert.Equal(t, largeContent, foundDraft.NewPage.Content)
+		assert.Equal(t, largeContent, foundDraft.OldPage.Content)
+
+		var foundTombstone model.PageTombstone
+		require.NoError(t, db.First(&foundTombstone, tombstone.ID).Error)
+		assert.Equal(t, model.PageContentStoreFilesystem, foundTombstone.ContentStore)
+		assert.Equal(t, largeContent, foundTombstone.Content)
+	})
+
+	t.Run("error on a store not configured", func(t *testing.T) {
+		db, _ := setupPageStorageTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionNone}, 1024)
+		page := newCompressionTestPage("/large", largeContent)
+		require.NoError(t, db.Create(page).Error)
+		require.NoError(t, db.Exec("UPDATE pages SET content_store = ? WHERE id = ?", model.PageContentStoreS3, page.ID).Error)
+
+		var found model.Page
+		err := db.First(&found, page.ID).Error
+		assert.ErrorContains(t, err, "page content store 's3' is not configured")
+	})
+
+	t.Run("reads the contents of a previous store", func(t *testing.T) {
+		db, previous := setupPageStorageTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionNone}, 1024)
+		page := newCompressionTestPage("/large", largeContent)
+		require.NoError(t, db.Create(page).Error)
+
+		pageCompression, err := NewPageCompression(config.PageCompressionConfig{})
+		require.NoError(t, err)
+		dbStorage, err := gorm.Open(sqlite.New(sqlite.Config{Conn: db.ConnPool}), &gorm.Config{})
+		require.NoError(t, err)
+		require.NoError(t, dbStorage.Use(pageCompression.WithStorage(NewPageStorage(storage.Database{}, 0, previous))))
+
+		var found model.Page
+		require.NoError(t, dbStorage.First(&found, page.ID).Error)
+		assert.Equal(t, largeContent, found.Content)
+
+		// Saved again, the content goes to the new store
+		require.NoError(t, dbStorage.Save(&found).Error)
+		assert.Equal(t, model.PageContentStoreDatabase, found.ContentStore)
+		assert.Equal(t, largeContent, storedPageContent(t, db, "pages", "content", page.ID))
+	})
+
+	t.Run("error on a missing content", func(t *testing.T) {
+		db, _ := setupPageStorageTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionNone}, 1024)
+		page := newCompressionTestPage("/large", largeContent)
+		require.NoError(t, db.Create(page).Error)
+		require.NoError(t, db.Exec("UPDATE pages SET content = ? WHERE id = ?", strings.Repeat("0", 64), page.ID).Error)
+
+		var found model.Page
+		err := db.First(&found, page.ID).Error
+		assert.ErrorIs(t, err, storage.ErrContentNotFound)
+	})
+}
+
+func TestPageStorage_WithoutStorage(t *testing.T) {
+	db := setupPageCompressionTestDB(t, config.PageCompressionConfig{Algorithm: config.PageCompressionNone})
+	page := newCompressionTestPage("/large", "content")
+	require.NoError(t, db.Create(page).Error)
+	require.NoError(t, db.Exec("UPDATE pages SET content_store = ? WHERE id = ?", model.PageContentStoreFilesystem, page.ID).Error)
+
+	var found model.Page
+	err := db.First(&found, page.ID).Error
+	assert.ErrorContains(t, err, "page content store 'fs' is not configured")
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	stdContext "context"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// queryCounterCallback is the name of the callbacks counting the statements
+const queryCounterCallback = "flecto:query_counter"
+
+type queryCounterKey struct{}
+
+// WithQueryCounter returns a context counting the statements run with it, read back by QueryCount
+func WithQueryCounter(ctx stdContext.Context) stdContext.Context {
+	return stdContext.WithValue(ctx, queryCounterKey{}, new(atomic.Int64))
+}
+
+// QueryCount returns the number of statements run with a context given by WithQueryCounter
+func QueryCount(ctx stdContext.Context) int64 {
+	if counter, ok := ctx.Value(queryCounterKey{}).(*atomic.Int64); ok {
+		return counter.Load()
+	}
+	return 0
+}
+
+// registerQueryCounter counts the statements of the contexts given by WithQueryCounter
+func registerQueryCounter(db *gorm.DB) error {
+	count := func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+		if counter, ok := db.Statement.Context.Value(queryCounterKey{}).(*atomic.Int64); ok {
+			counter.Add(1)
+		}
+	}
+	if err := db.Callback().Create().After("*").Register(queryCounterCallback, count); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().After("*").Register(queryCounterCallback, count); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("*").Register(queryCounterCallback, count); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().After("*").Register(queryCounterCallback, count); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().After("*").Register(queryCounterCallback, count); err != nil {
+		return err
+	}
+	return db.Callback().Raw().After("*").Register(queryCounterCallback, count)
+}
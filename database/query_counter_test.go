@@ -0,0 +1,33 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestQueryCounter(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}))
+	require.NoError(t, registerQueryCounter(db))
+
+	ctx := WithQueryCounter(context.Background())
+	require.NoError(t, db.WithContext(ctx).Create(&model.Namespace{NamespaceCode: "ns", Name: "Namespace"}).Error)
+	var namespaces []model.Namespace
+	require.NoError(t, db.WithContext(ctx).Find(&namespaces).Error)
+	require.NoError(t, db.WithContext(ctx).Model(&model.Namespace{}).Where("namespace_code = ?", "ns").Update("name", "Renamed").Error)
+	var count int64
+	require.NoError(t, db.WithContext(ctx).Raw("SELECT COUNT(*) FROM namespaces").Scan(&count).Error)
+	assert.Equal(t, int64(4), QueryCount(ctx))
+
+	// The statements of the other contexts are not counted
+	require.NoError(t, db.Find(&namespaces).Error)
+	assert.Equal(t, int64(4), QueryCount(ctx))
+	assert.Equal(t, int64(0), QueryCount(context.Background()))
+}
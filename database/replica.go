@@ -0,0 +1,81 @@
+package database
+
+import (
+	stdContext "context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/context"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// replicaCallback is the name of the callbacks of the replica resolver
+const replicaCallback = "gorm:db_resolver"
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose reads are run against the primary database instead of its
+// replicas, for the reads which must see the writes just done
+func WithPrimary(ctx stdContext.Context) stdContext.Context {
+	return stdContext.WithValue(ctx, primaryKey{}, true)
+}
+
+// PrimaryFromContext tells whether the reads of the context are run against the primary database
+func PrimaryFromContext(ctx stdContext.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// createReplicaResolver returns a gorm plugin sending the queries done outside transactions to the
+// replicas of the database, the writes, the transactions and the locking reads staying on the
+// database itself. A read can be forced on the database with Clauses(dbresolver.Write).
+func createReplicaResolver(ctx *context.Context, dbConfig config.DbConfig) (*dbresolver.DBResolver, error) {
+	replicas := make([]gorm.Dialector, 0, len(dbConfig.Replicas))
+	for i, replicaConfig := range dbConfig.Replicas {
+		dialector, err := createDialector(ctx, config.DbConfig{Type: dbConfig.Type, LogLevel: dbConfig.LogLevel, Config: replicaConfig.Config})
+		if err != nil {
+			return nil, fmt.Errorf("DB: replica %d: %v", i, err)
+		}
+		replicas = append(replicas, dialector)
+	}
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	})
+	// The replicas are sized like the database, see configurePool
+	if dbConfig.Pool.MaxOpenConns > 0 {
+		resolver.SetMaxOpenConns(dbConfig.Pool.MaxOpenConns)
+	}
+	if dbConfig.Pool.MaxIdleConns > 0 {
+		resolver.SetMaxIdleConns(dbConfig.Pool.MaxIdleConns)
+	}
+	if dbConfig.Pool.ConnMaxLifetime > 0 {
+		resolver.SetConnMaxLifetime(dbConfig.Pool.ConnMaxLifetime)
+	}
+	return resolver, nil
+}
+
+// registerPrimaryContext keeps the reads of the contexts given by WithPrimary on the connection pool
+// of the database instead of sending them to the replicas. It wraps the callbacks of the replica resolver,
+// which must run before all the others as the wrapped ones do.
+func registerPrimaryContext(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("*").Replace(replicaCallback, skipReplicas(db.Callback().Query().Get(replicaCallback))); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("*").Replace(replicaCallback, skipReplicas(db.Callback().Row().Get(replicaCallback))); err != nil {
+		return err
+	}
+	return db.Callback().Raw().Before("*").Replace(replicaCallback, skipReplicas(db.Callback().Raw().Get(replicaCallback)))
+}
+
+func skipReplicas(resolve func(*gorm.DB)) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if !PrimaryFromContext(db.Statement.Context) {
+			resolve(db)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// migrateReplicaTestDB creates a migrated sqlite database file and returns its path
+func migrateReplicaTestDB(t *testing.T, dir, name string) string {
+	path := filepath.Join(dir, name+".db")
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.User{}))
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+	return path
+}
+
+// setupReplicaTest returns a database whose replica is not replicated, so that the rows
+// read tell which database was queried. The namespace ns-eu is stored in a shard.
+func setupReplicaTest(t *testing.T) (db, primary, replica, shard *gorm.DB) {
+	originalInstance := dbInstance
+	originalFactory := FactoryDialector
+	t.Cleanup(func() {
+		dbInstance = originalInstance
+		FactoryDialector = originalFactory
+	})
+
+	dir := t.TempDir()
+	primaryPath := migrateReplicaTestDB(t, dir, "primary")
+	replicaPath := migrateReplicaTestDB(t, dir, "replica")
+	shardPath := migrateReplicaTestDB(t, dir, "shard")
+
+	dbInstance = nil
+	FactoryDialector = map[string]CreateDialectorFn{DbTypeSqlite: CreateDialectorSqlite}
+	ctx := appContext.TestContext(nil)
+	ctx.Config.DB = config.DbConfig{
+		Type:     DbTypeSqlite,
+		Config:   map[string]interface{}{"dsn": primaryPath},
+		Replicas: []config.DbReplicaConfig{{Config: map[string]interface{}{"dsn": replicaPath}}},
+		Shards: []config.DbShardConfig{
+			{Name: "eu", Type: DbTypeSqlite, Config: map[string]interface{}{"dsn": shardPath}, Namespaces: []string{"ns-eu"}},
+		},
+	}
+	db, err := CreateDB(ctx)
+	require.NoError(t, err)
+
+	open := func(path string) *gorm.DB {
+		conn, errOpen := gorm.Open(sqlite.Open(path), &gorm.Config{})
+		require.NoError(t, errOpen)
+		return conn
+	}
+	return db, open(primaryPath), open(replicaPath), open(shardPath)
+}
+
+func TestReplicaResolver(t *testing.T) {
+	db, primary, replica, shard := setupReplicaTest(t)
+	ctx := context.Background()
+	euCtx := WithNamespace(ctx, "ns-eu")
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "ns-main", Name: "Main"}).Error)
+	require.NoError(t, db.WithContext(euCtx).Create(&model.Namespace{NamespaceCode: "ns-eu", Name: "EU"}).Error)
+	require.NoError(t, db.WithContext(euCtx).Create(&model.Project{NamespaceCode: "ns-eu", ProjectCode: "proj", Name: "Project"}).Error)
+	require.NoError(t, replica.Create(&model.Project{NamespaceCode: "ns-main", ProjectCode: "replicated", Name: "Replicated"}).Error)
+
+	t.Run("writes go to the primary database", func(t *testing.T) {
+		assert.Equal(t, int64(2), countRows(t, primary, &model.Namespace{}))
+		assert.Equal(t, int64(0), countRows(t, replica, &model.Namespace{}))
+		assert.Equal(t, int64(1), countRows(t, shard, &model.Project{}))
+	})
+
+	t.Run("reads go to the replicas", func(t *testing.T) {
+		var projects []model.Project
+		require.NoError(t, db.Where("namespace_code = ?", "ns-main").Find(&projects).Error)
+		require.Len(t, projects, 1)
+		assert.Equal(t, "replicated", projects[0].ProjectCode)
+		assert.Equal(t, int64(0), countRows(t, db, &model.Namespace{}))
+	})
+
+	t.Run("forced and transaction reads go to the primary database", func(t *testing.T) {
+		assert.Equal(t, int64(2), countRows(t, db.Clauses(dbresolver.Write), &model.Namespace{}))
+		require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+			assert.Equal(t, int64(2), countRows(t, tx, &model.Namespace{}))
+			return nil
+		}))
+	})
+
+	t.Run("reads of primary contexts go to the primary database", func(t *testing.T) {
+		primaryCtx := WithPrimary(ctx)
+		assert.True(t, PrimaryFromContext(primaryCtx))
+		assert.False(t, PrimaryFromContext(ctx))
+		assert.Equal(t, int64(2), countRows(t, db.WithContext(primaryCtx), &model.Namespace{}))
+
+		var count int64
+		require.NoError(t, db.WithContext(primaryCtx).Raw("SELECT count(*) FROM namespaces").Scan(&count).Error)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("reads of sharded namespaces go to their shard", func(t *testing.T) {
+		var projects []model.Project
+		require.NoError(t, db.WithContext(euCtx).Find(&projects).Error)
+		require.Len(t, projects, 1)
+		assert.Equal(t, "ns-eu", projects[0].NamespaceCode)
+	})
+}
+
+func TestCreateReplicaResolver(t *testing.T) {
+	originalFactory := FactoryDialector
+	t.Cleanup(func() { FactoryDialector = originalFactory })
+	FactoryDialector = map[string]CreateDialectorFn{DbTypeSqlite: CreateDialectorSqlite}
+	ctx := appContext.TestContext(nil)
+
+	_, err := createReplicaResolver(ctx, config.DbConfig{Type: DbTypeSqlite, Replicas: []config.DbReplicaConfig{{Config: map[string]interface{}{}}}})
+	assert.ErrorContains(t, err, "replica 0")
+
+	_, err = createReplicaResolver(ctx, config.DbConfig{Type: "unknown", Replicas: []config.DbReplicaConfig{{Config: map[string]interface{}{"dsn": ":memory:"}}}})
+	assert.ErrorContains(t, err, "does not exist")
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/migrations"
+	"gorm.io/gorm"
+)
+
+// SchemaMigrationsTable is the table where the migrations store the version of the schema
+const SchemaMigrationsTable = "schema_migrations"
+
+// ErrSchemaOutdated is returned when the migrations of a database are not all applied
+var ErrSchemaOutdated = errors.New("database schema is not up to date")
+
+// Name returns the name of the database of a context returned by ShardContexts, for the messages
+func Name(ctx context.Context) string {
+	if namespaceCode := NamespaceFromContext(ctx); namespaceCode != "" {
+		return fmt.Sprintf("shard of namespace %s", namespaceCode)
+	}
+	return "primary database"
+}
+
+// CheckSchema checks that all the migrations are applied to the primary database and to every shard
+func CheckSchema(ctx context.Context, db *gorm.DB) error {
+	latest, err := migrations.LatestVersion()
+	if err != nil {
+		return err
+	}
+	for _, dbCtx := range ShardContexts(db, WithPrimary(ctx)) {
+		var version struct {
+			Version uint
+			Dirty   bool
+		}
+		err = db.WithContext(dbCtx).Raw("SELECT version, dirty FROM " + SchemaMigrationsTable).Scan(&version).Error
+		switch {
+		case err != nil:
+			return fmt.Errorf("%s: %w", Name(dbCtx), err)
+		case version.Dirty:
+			return fmt.Errorf("%w: %s: migration %d failed, manual fix required", ErrSchemaOutdated, Name(dbCtx), version.Version)
+		case version.Version < latest:
+			return fmt.Errorf("%w: %s: pending migrations, at version %d instead of %d", ErrSchemaOutdated, Name(dbCtx), version.Version, latest)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestCheckSchema(t *testing.T) {
+	latest, err := migrations.LatestVersion()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		version uint
+		dirty   bool
+		wantErr string
+	}{
+		{name: "up to date", version: latest},
+		{name: "pending migrations", version: latest - 1, wantErr: "pending migrations"},
+		{name: "dirty", version: latest, dirty: true, wantErr: "failed, manual fix required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+			require.NoError(t, err)
+			require.NoError(t, db.Exec("CREATE TABLE schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)").Error)
+			require.NoError(t, db.Exec("INSERT INTO schema_migrations VALUES (?, ?)", tt.version, tt.dirty).Error)
+
+			err = CheckSchema(context.Background(), db)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, ErrSchemaOutdated)
+			assert.ErrorContains(t, err, "primary database: ")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("not migrated", func(t *testing.T) {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		require.NoError(t, err)
+		assert.ErrorContains(t, CheckSchema(context.Background(), db), "schema_migrations")
+	})
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "primary database", Name(context.Background()))
+	assert.Equal(t, "shard of namespace ns-eu", Name(WithNamespace(context.Background(), "ns-eu")))
+}
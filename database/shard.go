@@ -0,0 +1,243 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+type shardNamespaceKey struct{}
+
+// GlobalTables are the tables not belonging to a namespace, they are always read from and written
+// to the primary database. The namespaces table is also copied to the shards of the namespaces,
+// the namespace data of the shards referencing it.
+var GlobalTables = map[string]bool{
+	"namespaces":              true,
+	"users":                   true,
+	"user_password_history":   true,
+	"refresh_tokens":          true,
+	"roles":                   true,
+	"user_roles":              true,
+	"role_parents":            true,
+	"resource_permissions":    true,
+	"admin_permissions":       true,
+	"tokens":                  true,
+	"agent_instances":         true,
+	"agent_instance_projects": true,
+	"groups":                  true,
+	"user_groups":             true,
+	"group_roles":             true,
+	"project_members":         true,
+	"project_api_keys":        true,
+}
+
+// WithNamespace returns a context whose statements are run against the database of the namespace
+func WithNamespace(ctx context.Context, namespaceCode string) context.Context {
+	return context.WithValue(ctx, shardNamespaceKey{}, namespaceCode)
+}
+
+// NamespaceFromContext returns the namespace set by WithNamespace
+func NamespaceFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	namespaceCode, _ := ctx.Value(shardNamespaceKey{}).(string)
+	return namespaceCode
+}
+
+// ShardContexts returns a context per database, so tasks going through the data of every
+// namespace can run once against each of them. It returns ctx alone when sharding is disabled.
+func ShardContexts(db *gorm.DB, ctx context.Context) []context.Context {
+	router, ok := db.ConnPool.(*ShardRouter)
+	if !ok {
+		return []context.Context{ctx}
+	}
+	contexts := []context.Context{WithNamespace(ctx, "")}
+	for _, namespaceCode := range router.shardNamespaces {
+		contexts = append(contexts, WithNamespace(ctx, namespaceCode))
+	}
+	return contexts
+}
+
+// SameDatabase returns true when the data of both namespaces is stored in the same database, always the case when
+// sharding is disabled
+func SameDatabase(db *gorm.DB, namespaceCode, otherNamespaceCode string) bool {
+	router, ok := db.ConnPool.(*ShardRouter)
+	if !ok {
+		return true
+	}
+	return router.SameDatabase(namespaceCode, otherNamespaceCode)
+}
+
+// ShardRouter is a gorm plugin replacing the connection pool of the primary database by a pool
+// sending each statement to the database of the namespace of its context, see WithNamespace.
+// Statements without namespace, on a namespace without shard or on a global table go to the
+// primary database. Transactions stay on the database they were started on.
+type ShardRouter struct {
+	db      *gorm.DB
+	primary gorm.ConnPool
+	// shards are the shard databases by namespace code
+	shards map[string]*gorm.DB
+	// shardNamespaces holds a namespace code per shard
+	shardNamespaces []string
+}
+
+// NewShardRouter returns a router for shard databases given by name, namespaces mapping
+// the namespace codes to the shard names
+func NewShardRouter(shards map[string]*gorm.DB, namespaces map[string]string) (*ShardRouter, error) {
+	router := &ShardRouter{shards: make(map[string]*gorm.DB, len(namespaces))}
+	seen := make(map[string]bool, len(shards))
+	for namespaceCode, name := range namespaces {
+		shard, ok := shards[name]
+		if !ok {
+			return nil, fmt.Errorf("shard '%s' of namespace '%s' does not exist", name, namespaceCode)
+		}
+		router.shards[namespaceCode] = shard
+		if !seen[name] {
+			seen[name] = true
+			router.shardNamespaces = append(router.shardNamespaces, namespaceCode)
+		}
+	}
+	return router, nil
+}
+
+func (r *ShardRouter) Name() string {
+	return "flecto:shard_router"
+}
+
+func (r *ShardRouter) Initialize(db *gorm.DB) error {
+	// gorm initializes the plugins again on the databases opened with the config of db,
+	// as done by the replica resolver, the router only belongs to the first one
+	if r.db != nil {
+		return nil
+	}
+	r.db = db
+	r.primary = db.ConnPool
+	db.ConnPool = r
+	db.Statement.ConnPool = r
+
+	if err := db.Callback().Create().Before("gorm:begin_transaction").Register("flecto:shard_route_create", r.route); err != nil {
+		return err
+	}
+	if err := db.Callback().Query().Before("gorm:query").Register("flecto:shard_route_query", r.route); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().Before("gorm:begin_transaction").Register("flecto:shard_route_update", r.route); err != nil {
+		return err
+	}
+	if err := db.Callback().Delete().Before("gorm:begin_transaction").Register("flecto:shard_route_delete", r.route); err != nil {
+		return err
+	}
+	if err := db.Callback().Row().Before("gorm:row").Register("flecto:shard_route_row", r.route); err != nil {
+		return err
+	}
+	if err := db.Callback().Create().After("gorm:commit_or_rollback_transaction").Register("flecto:shard_sync_create", r.syncNamespaces); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:commit_or_rollback_transaction").Register("flecto:shard_sync_update", r.syncNamespaces); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:commit_or_rollback_transaction").Register("flecto:shard_sync_delete", r.syncNamespaces)
+}
+
+// SameDatabase returns true when both namespaces are routed to the same database, the namespaces without shard being
+// in the primary database
+func (r *ShardRouter) SameDatabase(namespaceCode, otherNamespaceCode string) bool {
+	return r.shards[namespaceCode] == r.shards[otherNamespaceCode]
+}
+
+// pool returns the connection pool of the namespace of the context
+func (r *ShardRouter) pool(ctx context.Context) gorm.ConnPool {
+	if shard, ok := r.shards[NamespaceFromContext(ctx)]; ok {
+		return shard.ConnPool
+	}
+	return r.primary
+}
+
+func (r *ShardRouter) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.pool(ctx).PrepareContext(ctx, query)
+}
+
+func (r *ShardRouter) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.pool(ctx).ExecContext(ctx, query, args...)
+}
+
+func (r *ShardRouter) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.pool(ctx).QueryContext(ctx, query, args...)
+}
+
+func (r *ShardRouter) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.pool(ctx).QueryRowContext(ctx, query, args...)
+}
+
+func (r *ShardRouter) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	beginner, ok := r.pool(ctx).(gorm.TxBeginner)
+	if !ok {
+		return nil, gorm.ErrInvalidTransaction
+	}
+	return beginner.BeginTx(ctx, opts)
+}
+
+// GetDBConn returns the primary database, used by gorm.DB.DB
+func (r *ShardRouter) GetDBConn() (*sql.DB, error) {
+	if connector, ok := r.primary.(gorm.GetDBConnector); ok {
+		return connector.GetDBConn()
+	}
+	if sqlDB, ok := r.primary.(*sql.DB); ok {
+		return sqlDB, nil
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+// route sends the statements on global tables to the primary database, it runs before the
+// default transaction of writes is started so it is started on the primary database too.
+// The reads sent to the replicas of the primary database are brought back to the router when
+// their namespace has a shard, the replicas only holding the data of the primary database.
+func (r *ShardRouter) route(db *gorm.DB) {
+	pool := db.Statement.ConnPool
+	global := GlobalTables[db.Statement.Table]
+	switch {
+	case pool == gorm.ConnPool(r):
+		if global {
+			db.Statement.ConnPool = r.primary
+		}
+	case !global && !isTransaction(pool):
+		if _, ok := r.shards[NamespaceFromContext(db.Statement.Context)]; ok {
+			db.Statement.ConnPool = r
+		}
+	}
+}
+
+func isTransaction(pool gorm.ConnPool) bool {
+	_, ok := pool.(gorm.TxCommitter)
+	return ok
+}
+
+// syncNamespaces copies the sharded namespaces of the primary database to their shard
+// after a write on the namespaces table, removing the deleted ones
+func (r *ShardRouter) syncNamespaces(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Table != "namespaces" || len(r.shards) == 0 {
+		return
+	}
+	ctx := db.Statement.Context
+	for namespaceCode, shard := range r.shards {
+		var namespace model.Namespace
+		err := r.db.WithContext(ctx).Clauses(dbresolver.Write).Where("namespace_code = ?", namespaceCode).Take(&namespace).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			err = shard.WithContext(ctx).Where("namespace_code = ?", namespaceCode).Delete(&model.Namespace{}).Error
+		case err == nil:
+			err = shard.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&namespace).Error
+		}
+		if err != nil {
+			_ = db.AddError(fmt.Errorf("failed to copy namespace '%s' to its shard: %w", namespaceCode, err))
+			return
+		}
+	}
+}
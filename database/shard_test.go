@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func openShardTestDB(t *testing.T, name string) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), name+".db")), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.User{}))
+	return db
+}
+
+func setupShardTest(t *testing.T) (db *gorm.DB, shard *gorm.DB) {
+	db = openShardTestDB(t, "primary")
+	shard = openShardTestDB(t, "shard")
+	router, err := NewShardRouter(map[string]*gorm.DB{"eu": shard}, map[string]string{"ns-eu": "eu"})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(router))
+	return db, shard
+}
+
+func countRows(t *testing.T, db *gorm.DB, value interface{}) int64 {
+	var count int64
+	require.NoError(t, db.Model(value).Count(&count).Error)
+	return count
+}
+
+func TestNewShardRouter(t *testing.T) {
+	_, err := NewShardRouter(map[string]*gorm.DB{}, map[string]string{"ns": "missing"})
+	assert.Error(t, err)
+
+	router, err := NewShardRouter(map[string]*gorm.DB{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "flecto:shard_router", router.Name())
+}
+
+func TestShardRouter(t *testing.T) {
+	db, shard := setupShardTest(t)
+	euCtx := WithNamespace(context.Background(), "ns-eu")
+
+	t.Run("copies sharded namespaces to their shard", func(t *testing.T) {
+		require.NoError(t, db.WithContext(euCtx).Create(&model.Namespace{NamespaceCode: "ns-eu", Name: "EU"}).Error)
+		require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "ns-main", Name: "Main"}).Error)
+
+		assert.Equal(t, int64(2), countRows(t, db, &model.Namespace{}))
+		var namespaces []model.Namespace
+		require.NoError(t, shard.Find(&namespaces).Error)
+		require.Len(t, namespaces, 1)
+		assert.Equal(t, "ns-eu", namespaces[0].NamespaceCode)
+
+		require.NoError(t, db.Model(&model.Namespace{}).Where("namespace_code = ?", "ns-eu").Update("name", "Europe").Error)
+		require.NoError(t, shard.First(&namespaces[0]).Error)
+		assert.Equal(t, "Europe", namespaces[0].Name)
+	})
+
+	t.Run("routes namespace data by context", func(t *testing.T) {
+		require.NoError(t, db.WithContext(euCtx).Create(&model.Project{NamespaceCode: "ns-eu", ProjectCode: "proj", Name: "Project"}).Error)
+		require.NoError(t, db.Create(&model.Project{NamespaceCode: "ns-main", ProjectCode: "proj", Name: "Project"}).Error)
+		require.NoError(t, db.WithContext(euCtx).Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&model.Redirect{
+				NamespaceCode: "ns-eu",
+				ProjectCode:   "proj",
+				IsPublished:   types.Ptr(true),
+				Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/b"},
+			}).Error
+		}))
+
+		assert.Equal(t, int64(1), countRows(t, shard, &model.Project{}))
+		assert.Equal(t, int64(1), countRows(t, shard, &model.Redirect{}))
+		assert.Equal(t, int64(1), countRows(t, db, &model.Project{}))
+		assert.Equal(t, int64(0), countRows(t, db, &model.Redirect{}))
+		assert.Equal(t, int64(1), countRows(t, db.WithContext(euCtx), &model.Redirect{}))
+
+		var project model.Project
+		require.NoError(t, db.WithContext(euCtx).Where("project_code = ?", "proj").First(&project).Error)
+		assert.Equal(t, "ns-eu", project.NamespaceCode)
+	})
+
+	t.Run("keeps global tables in the primary database", func(t *testing.T) {
+		require.NoError(t, db.WithContext(euCtx).Create(&model.User{Username: "user", Firstname: "First", Lastname: "Last"}).Error)
+
+		assert.Equal(t, int64(1), countRows(t, db, &model.User{}))
+		assert.Equal(t, int64(0), countRows(t, shard, &model.User{}))
+		assert.Equal(t, int64(1), countRows(t, db.WithContext(euCtx), &model.User{}))
+	})
+
+	t.Run("removes deleted namespaces from their shard", func(t *testing.T) {
+		require.NoError(t, db.WithContext(euCtx).Where("namespace_code = ?", "ns-eu").Delete(&model.Namespace{}).Error)
+
+		assert.Equal(t, int64(0), countRows(t, shard, &model.Namespace{}))
+	})
+
+	t.Run("shard contexts", func(t *testing.T) {
+		contexts := ShardContexts(db, context.Background())
+		require.Len(t, contexts, 2)
+		assert.Equal(t, "", NamespaceFromContext(contexts[0]))
+		assert.Equal(t, "ns-eu", NamespaceFromContext(contexts[1]))
+
+		assert.Len(t, ShardContexts(shard, context.Background()), 1)
+	})
+
+	t.Run("same database", func(t *testing.T) {
+		assert.True(t, SameDatabase(db, "ns-eu", "ns-eu"))
+		assert.True(t, SameDatabase(db, "ns-main", "ns-other"))
+		assert.False(t, SameDatabase(db, "ns-eu", "ns-main"))
+		assert.False(t, SameDatabase(db, "ns-main", "ns-eu"))
+
+		assert.True(t, SameDatabase(shard, "ns-eu", "ns-main"))
+	})
+
+	t.Run("primary sql database", func(t *testing.T) {
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		assert.NoError(t, sqlDB.Ping())
+	})
+}
+
+func TestGlobalTables(t *testing.T) {
+	db := openShardTestDB(t, "global")
+	globalModels := []interface{}{
+		&model.Namespace{}, &model.User{}, &model.UserPasswordHistory{}, &model.RefreshToken{},
+		&model.Role{}, &model.UserRole{}, &model.RoleParent{}, &model.ResourcePermission{},
+		&model.AdminPermission{}, &model.Token{}, &model.AgentInstance{}, &model.AgentInstanceProject{},
+		&model.Group{}, &model.UserGroup{}, &model.GroupRole{}, &model.ProjectMember{}, &model.ProjectAPIKey{},
+	}
+	tables := map[string]bool{}
+	for _, value := range globalModels {
+		stmt := &gorm.Statement{DB: db}
+		require.NoError(t, stmt.Parse(value))
+		tables[stmt.Schema.Table] = true
+	}
+	assert.Equal(t, tables, GlobalTables)
+}
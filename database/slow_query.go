@@ -0,0 +1,91 @@
+package database
+
+import (
+	"log/slog"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+const (
+	// slowQueryStartCallback and slowQueryLogCallback are the names of the callbacks timing the statements
+	slowQueryStartCallback = "flecto:slow_query_start"
+	slowQueryLogCallback   = "flecto:slow_query_log"
+
+	slowQueryStartKey = "flecto:slow_query_start"
+)
+
+// registerSlowQueryLog logs as warnings the statements running longer than threshold, with the function of the
+// services calling them, the subject and the namespace of their context. The values of the statements are not logged.
+func registerSlowQueryLog(db *gorm.DB, logger *slog.Logger, threshold time.Duration) error {
+	start := func(db *gorm.DB) {
+		db.InstanceSet(slowQueryStartKey, time.Now())
+	}
+	log := func(db *gorm.DB) {
+		value, ok := db.InstanceGet(slowQueryStartKey)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(value.(time.Time))
+		if elapsed < threshold {
+			return
+		}
+		ctx := db.Statement.Context
+		logger.WarnContext(ctx, "slow query",
+			"duration", elapsed,
+			"sql", db.Statement.SQL.String(),
+			"table", db.Statement.Table,
+			"rows", db.RowsAffected,
+			"caller", queryCaller(),
+			"subject", types.SubjectFromContext(ctx),
+			"namespace", NamespaceFromContext(ctx),
+		)
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register(slowQueryStartCallback, start),
+		callbacks.Create().After("*").Register(slowQueryLogCallback, log),
+		callbacks.Query().Before("*").Register(slowQueryStartCallback, start),
+		callbacks.Query().After("*").Register(slowQueryLogCallback, log),
+		callbacks.Update().Before("*").Register(slowQueryStartCallback, start),
+		callbacks.Update().After("*").Register(slowQueryLogCallback, log),
+		callbacks.Delete().Before("*").Register(slowQueryStartCallback, start),
+		callbacks.Delete().After("*").Register(slowQueryLogCallback, log),
+		callbacks.Row().Before("*").Register(slowQueryStartCallback, start),
+		callbacks.Row().After("*").Register(slowQueryLogCallback, log),
+		callbacks.Raw().Before("*").Register(slowQueryStartCallback, start),
+		callbacks.Raw().After("*").Register(slowQueryLogCallback, log),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryCaller returns the function of the services running the statement, or the first function outside gorm and
+// the database package when no service is calling it
+func queryCaller() string {
+	pcs := make([]uintptr, 64)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	caller := ""
+	for {
+		frame, more := frames.Next()
+		name := path.Base(frame.Function)
+		switch {
+		case strings.HasPrefix(name, "service."):
+			return name
+		case caller == "" && !strings.HasPrefix(frame.Function, "gorm.io/") && !strings.HasPrefix(name, "database.") &&
+			!strings.HasPrefix(frame.Function, "runtime.") && !strings.HasPrefix(frame.Function, "database/sql."):
+			caller = name
+		}
+		if !more {
+			return caller
+		}
+	}
+}
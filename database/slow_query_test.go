@@ -0,0 +1,52 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestSlowQueryLog(t *testing.T) {
+	openDB := func(t *testing.T, threshold time.Duration) (*gorm.DB, *bytes.Buffer) {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		require.NoError(t, err)
+		require.NoError(t, db.AutoMigrate(&model.Namespace{}))
+		logBuffer := &bytes.Buffer{}
+		require.NoError(t, registerSlowQueryLog(db, slog.New(slog.NewTextHandler(logBuffer, nil)), threshold))
+		return db, logBuffer
+	}
+
+	t.Run("logs the statements longer than the threshold", func(t *testing.T) {
+		db, logBuffer := openDB(t, time.Nanosecond)
+		ctx := WithNamespace(types.WithSubject(context.Background(), "alice"), "shop")
+
+		var namespaces []model.Namespace
+		require.NoError(t, db.WithContext(ctx).Where("namespace_code = ?", "secret-value").Find(&namespaces).Error)
+
+		log := logBuffer.String()
+		assert.Contains(t, log, `msg="slow query"`)
+		assert.Contains(t, log, "table=namespaces")
+		assert.Contains(t, log, "subject=alice")
+		assert.Contains(t, log, "namespace=shop")
+		// The functions of gorm and of the database package are skipped
+		assert.Regexp(t, `caller=\S+`, log)
+		assert.NotRegexp(t, `caller=(gorm|database)\.`, log)
+		assert.NotContains(t, log, "secret-value")
+	})
+
+	t.Run("ignores the fast statements", func(t *testing.T) {
+		db, logBuffer := openDB(t, time.Hour)
+
+		require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "ns", Name: "Namespace"}).Error)
+		assert.Empty(t, logBuffer.String())
+	})
+}
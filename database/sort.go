@@ -1,20 +1,20 @@
 package database
 
 import (
+	"fmt"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"gorm.io/gorm"
 )
 
-type SortDirection string
+type SortDirection = commonTypes.SortDirection
 
 const (
-	SortASC  SortDirection = "ASC"
-	SortDESC SortDirection = "DESC"
+	SortASC  = commonTypes.SortASC
+	SortDESC = commonTypes.SortDESC
 )
 
-type SortInput struct {
-	Column    string
-	Direction SortDirection
-}
+type SortInput = commonTypes.SortInput
 
 // ApplySort applique les tris à une requête GORM
 // allowedColumns: map[jsonName]dbColumnName
@@ -38,4 +38,18 @@ func ApplySort(query *gorm.DB, allowedColumns map[string]string, sorts []SortInp
 		query = query.Order(col + " " + string(dir))
 	}
 	return query
-}
\ No newline at end of file
+}
+
+// ApplyOrderBy applies the orderBy of a pagination input, like ApplySort,
+// but rejects the columns missing from allowedColumns instead of ignoring them.
+func ApplyOrderBy(query *gorm.DB, allowedColumns map[string]string, orderBy []SortInput, tablePrefix string) (*gorm.DB, error) {
+	for _, sort := range orderBy {
+		if _, ok := allowedColumns[sort.Column]; !ok {
+			return nil, fmt.Errorf("column %s cannot be sorted", sort.Column)
+		}
+		if sort.Direction != SortASC && sort.Direction != SortDESC {
+			return nil, fmt.Errorf("unknown sort direction %s", sort.Direction)
+		}
+	}
+	return ApplySort(query, allowedColumns, orderBy, tablePrefix), nil
+}
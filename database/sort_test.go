@@ -183,3 +183,55 @@ func TestApplySort(t *testing.T) {
 		assert.NotContains(t, stmt.SQL.String(), "name")
 	})
 }
+
+func TestApplyOrderBy(t *testing.T) {
+	allowedColumns := map[string]string{
+		"name":      "name",
+		"updatedAt": "updated_at",
+	}
+
+	t.Run("applies allowed columns", func(t *testing.T) {
+		db := setupSortTestDB(t)
+		query := db.Model(&struct{}{})
+
+		orderBy := []SortInput{
+			{Column: "updatedAt", Direction: SortDESC},
+			{Column: "name", Direction: SortASC},
+		}
+
+		result, err := ApplyOrderBy(query, allowedColumns, orderBy, "items")
+
+		assert.NoError(t, err)
+		stmt := result.Statement
+		result.Statement.Build("ORDER BY")
+		assert.Contains(t, stmt.SQL.String(), "items.updated_at DESC,items.name ASC")
+	})
+
+	t.Run("empty orderBy returns query unchanged", func(t *testing.T) {
+		db := setupSortTestDB(t)
+		query := db.Model(&struct{}{})
+
+		result, err := ApplyOrderBy(query, allowedColumns, nil, "")
+
+		assert.NoError(t, err)
+		assert.Equal(t, query, result)
+	})
+
+	t.Run("rejects unknown column", func(t *testing.T) {
+		db := setupSortTestDB(t)
+
+		result, err := ApplyOrderBy(db, allowedColumns, []SortInput{{Column: "password", Direction: SortASC}}, "")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "column password cannot be sorted")
+	})
+
+	t.Run("rejects invalid direction", func(t *testing.T) {
+		db := setupSortTestDB(t)
+
+		result, err := ApplyOrderBy(db, allowedColumns, []SortInput{{Column: "name", Direction: SortDirection("UP")}}, "")
+
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "unknown sort direction UP")
+	})
+}
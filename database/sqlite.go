@@ -1,6 +1,10 @@
 package database
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/flectolab/flecto-manager/config"
 	"github.com/flectolab/flecto-manager/context"
 	"github.com/go-viper/mapstructure/v2"
@@ -10,14 +14,33 @@ import (
 
 const (
 	DbTypeSqlite = "sqlite"
+
+	// DefaultSqliteJournalMode lets the reads go on while a transaction writes
+	DefaultSqliteJournalMode = "WAL"
+	// DefaultSqliteBusyTimeout is the number of milliseconds a statement waits for the lock of another connection
+	DefaultSqliteBusyTimeout = 5000
+	// DefaultSqliteTxLock takes the write lock when the transactions begin, see SqliteConfig.TxLock
+	DefaultSqliteTxLock = "immediate"
 )
 
 type SqliteConfig struct {
 	DSN string `mapstructure:"dsn" validate:"required"`
+	// JournalMode is the journal mode of the database file, empty to keep the one of the file
+	JournalMode string `mapstructure:"journal_mode" validate:"omitempty,oneof=WAL DELETE TRUNCATE PERSIST MEMORY OFF"`
+	// BusyTimeout is the number of milliseconds a statement waits for the lock held by another connection before
+	// failing with "database is locked"
+	BusyTimeout int `mapstructure:"busy_timeout" validate:"min=0"`
+	// TxLock is the lock taken when a transaction begins. With immediate, the transactions wait their turn to write
+	// within the busy timeout, instead of failing when they upgrade their read lock while another one writes.
+	TxLock string `mapstructure:"tx_lock" validate:"omitempty,oneof=deferred immediate exclusive"`
 }
 
 func CreateDialectorSqlite(ctx *context.Context, cfg config.DbConfig) (gorm.Dialector, error) {
-	dialectorCfg := SqliteConfig{}
+	dialectorCfg := SqliteConfig{
+		JournalMode: DefaultSqliteJournalMode,
+		BusyTimeout: DefaultSqliteBusyTimeout,
+		TxLock:      DefaultSqliteTxLock,
+	}
 	err := mapstructure.Decode(cfg.Config, &dialectorCfg)
 	if err != nil {
 		return nil, err
@@ -28,7 +51,26 @@ func CreateDialectorSqlite(ctx *context.Context, cfg config.DbConfig) (gorm.Dial
 		return nil, err
 	}
 
-	dialector := sqlite.Open(dialectorCfg.DSN)
+	dialector := sqlite.Open(dialectorCfg.sqliteDSN())
 
 	return dialector, nil
 }
+
+// sqliteDSN returns the DSN with the connection settings added to its parameters. The driver takes the first value of
+// a parameter, the ones already set by the DSN are kept.
+func (c SqliteConfig) sqliteDSN() string {
+	params := url.Values{}
+	if c.JournalMode != "" {
+		params.Set("_journal_mode", c.JournalMode)
+	}
+	params.Set("_busy_timeout", fmt.Sprint(c.BusyTimeout))
+	if c.TxLock != "" {
+		params.Set("_txlock", c.TxLock)
+	}
+
+	separator := "?"
+	if strings.Contains(c.DSN, "?") {
+		separator = "&"
+	}
+	return c.DSN + separator + params.Encode()
+}
@@ -1,12 +1,14 @@
 package database
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/flectolab/flecto-manager/config"
 	"github.com/flectolab/flecto-manager/context"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func TestDbTypeSqliteConstant(t *testing.T) {
@@ -87,3 +89,54 @@ func TestCreateDialectorSqlite(t *testing.T) {
 		assert.Nil(t, dialector)
 	})
 }
+
+func TestCreateDialectorSqlite_ConnectionSettings(t *testing.T) {
+	openSqlite := func(t *testing.T, settings map[string]interface{}) *gorm.DB {
+		settings["dsn"] = filepath.Join(t.TempDir(), "flecto.db")
+		dialector, err := CreateDialectorSqlite(context.TestContext(nil), config.DbConfig{Type: DbTypeSqlite, Config: settings})
+		require.NoError(t, err)
+		db, err := gorm.Open(dialector, &gorm.Config{})
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			sqlDB, _ := db.DB()
+			_ = sqlDB.Close()
+		})
+		return db
+	}
+	pragma := func(t *testing.T, db *gorm.DB, name string) string {
+		var value string
+		require.NoError(t, db.Raw("PRAGMA "+name).Scan(&value).Error)
+		return value
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		db := openSqlite(t, map[string]interface{}{})
+		assert.Equal(t, "wal", pragma(t, db, "journal_mode"))
+		assert.Equal(t, "5000", pragma(t, db, "busy_timeout"))
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		db := openSqlite(t, map[string]interface{}{
+			"journal_mode": "DELETE",
+			"busy_timeout": 250,
+		})
+		assert.Equal(t, "delete", pragma(t, db, "journal_mode"))
+		assert.Equal(t, "250", pragma(t, db, "busy_timeout"))
+	})
+
+	t.Run("invalid journal mode", func(t *testing.T) {
+		_, err := CreateDialectorSqlite(context.TestContext(nil), config.DbConfig{
+			Type:   DbTypeSqlite,
+			Config: map[string]interface{}{"dsn": ":memory:", "journal_mode": "FAST"},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestSqliteConfig_sqliteDSN(t *testing.T) {
+	cfg := SqliteConfig{DSN: "/data/flecto.db", JournalMode: "WAL", BusyTimeout: 5000, TxLock: "immediate"}
+	assert.Equal(t, "/data/flecto.db?_busy_timeout=5000&_journal_mode=WAL&_txlock=immediate", cfg.sqliteDSN())
+
+	cfg = SqliteConfig{DSN: "file:/data/flecto.db?cache=shared"}
+	assert.Equal(t, "file:/data/flecto.db?cache=shared&_busy_timeout=0", cfg.sqliteDSN())
+}
@@ -23,12 +23,13 @@ const sidebars: SidebarsConfig = {
         'features/redirects',
         'features/pages',
         'features/agents',
+        'features/search',
       ],
     },
     {
       type: 'category',
       label: 'API',
-      items: ['api/authentication', 'api/rest'],
+      items: ['api/authentication', 'api/rest', 'api/filters'],
     },
   ],
 };
@@ -0,0 +1,242 @@
+// Package errors is the error model of the API. Each error returned to the clients has a stable code they can branch
+// on, a message for the users and, when it is about an input, the field of the input and details.
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm"
+)
+
+// Code identifies the cause of an error, it never changes once released
+type Code string
+
+const (
+	// CodeInternal is an unexpected failure of the manager
+	CodeInternal Code = "INTERNAL"
+	// CodeInvalidRequest is a request whose body, parameters or arguments cannot be processed
+	CodeInvalidRequest Code = "INVALID_REQUEST"
+	// CodeValidationFailed is an input breaking a validation rule, the details listing each field and rule broken
+	CodeValidationFailed Code = "VALIDATION_FAILED"
+	// CodeUnauthenticated is a request without valid credentials
+	CodeUnauthenticated Code = "UNAUTHENTICATED"
+	// CodeInvalidCredentials is a login or a password change with a wrong password
+	CodeInvalidCredentials Code = "INVALID_CREDENTIALS"
+	// CodeTokenExpired is a request authenticated by an expired API token or project API key
+	CodeTokenExpired Code = "TOKEN_EXPIRED"
+	// CodeUserInactive is a login or an impersonation of a deactivated user
+	CodeUserInactive Code = "USER_INACTIVE"
+	// CodePasswordPolicy is a password refused by the password policy
+	CodePasswordPolicy Code = "PASSWORD_POLICY"
+	// CodeForbidden is a request the subject has no permission for
+	CodeForbidden Code = "FORBIDDEN"
+	// CodeNotFound is a request about a resource which does not exist
+	CodeNotFound Code = "NOT_FOUND"
+	// CodeAlreadyExists is the creation of a resource whose code, name, source or path is already used
+	CodeAlreadyExists Code = "ALREADY_EXISTS"
+	// CodeConflict is a change refused because of the current state of the resource
+	CodeConflict Code = "CONFLICT"
+	// CodeRateLimited is a request refused because too many were sent, to try again later
+	CodeRateLimited Code = "RATE_LIMITED"
+	// CodeUnavailable is a request the manager cannot process for now, to try again later
+	CodeUnavailable Code = "UNAVAILABLE"
+	// CodeUnsupported is a request for a feature the manager does not support as it is run
+	CodeUnsupported Code = "UNSUPPORTED"
+	// CodeNothingToPublish is the publish of a project without drafts
+	CodeNothingToPublish Code = "NOTHING_TO_PUBLISH"
+	// CodeNothingToPromote is the promotion of an environment already at the version of the project
+	CodeNothingToPromote Code = "NOTHING_TO_PROMOTE"
+	// CodePublishInProgress is the publish of a project another publish holds the lock of
+	CodePublishInProgress Code = "PUBLISH_IN_PROGRESS"
+	// CodePublishVetoed is a publish refused by a validation hook
+	CodePublishVetoed Code = "PUBLISH_VETOED"
+	// CodeMassDeletion is an operation refused by the mass deletion safeguard, to repeat with force
+	CodeMassDeletion Code = "MASS_DELETION"
+	// CodeRedirectChainTooLong is a publish leaving a redirect chain longer than allowed
+	CodeRedirectChainTooLong Code = "REDIRECT_CHAIN_TOO_LONG"
+	// CodeDraftLocked is the change of a draft locked by another user
+	CodeDraftLocked Code = "DRAFT_LOCKED"
+	// CodeSizeLimitExceeded is a page or a file larger than allowed, or pages exceeding the size allowed to a project
+	CodeSizeLimitExceeded Code = "SIZE_LIMIT_EXCEEDED"
+	// CodeSecretDetected is a page draft whose content holds a likely secret
+	CodeSecretDetected Code = "SECRET_DETECTED"
+	// CodePolicyViolation is a redirect draft breaking a rule of the policy of its namespace
+	CodePolicyViolation Code = "POLICY_VIOLATION"
+	// CodeReadOnly is a change refused while the manager is in read-only maintenance mode
+	CodeReadOnly Code = "READ_ONLY"
+	// CodeDepthLimitExceeded is a GraphQL operation nesting its fields deeper than allowed
+	CodeDepthLimitExceeded Code = "DEPTH_LIMIT_EXCEEDED"
+	// CodeComplexityLimitExceeded is a GraphQL operation more complex than allowed
+	CodeComplexityLimitExceeded Code = "COMPLEXITY_LIMIT_EXCEEDED"
+)
+
+// httpStatuses are the HTTP statuses of the codes, the codes missing being answered with 400 Bad Request
+var httpStatuses = map[Code]int{
+	CodeInternal:                http.StatusInternalServerError,
+	CodeUnauthenticated:         http.StatusUnauthorized,
+	CodeInvalidCredentials:      http.StatusUnauthorized,
+	CodeTokenExpired:            http.StatusUnauthorized,
+	CodeUserInactive:            http.StatusForbidden,
+	CodeForbidden:               http.StatusForbidden,
+	CodeNotFound:                http.StatusNotFound,
+	CodeAlreadyExists:           http.StatusConflict,
+	CodeConflict:                http.StatusConflict,
+	CodeRateLimited:             http.StatusTooManyRequests,
+	CodeUnavailable:             http.StatusServiceUnavailable,
+	CodeUnsupported:             http.StatusNotImplemented,
+	CodeNothingToPublish:        http.StatusConflict,
+	CodeNothingToPromote:        http.StatusConflict,
+	CodePublishInProgress:       http.StatusConflict,
+	CodePublishVetoed:           http.StatusUnprocessableEntity,
+	CodeMassDeletion:            http.StatusConflict,
+	CodeRedirectChainTooLong:    http.StatusUnprocessableEntity,
+	CodeDraftLocked:             http.StatusLocked,
+	CodeSizeLimitExceeded:       http.StatusRequestEntityTooLarge,
+	CodeSecretDetected:          http.StatusUnprocessableEntity,
+	CodePolicyViolation:         http.StatusUnprocessableEntity,
+	CodeReadOnly:                http.StatusServiceUnavailable,
+	CodeDepthLimitExceeded:      http.StatusUnprocessableEntity,
+	CodeComplexityLimitExceeded: http.StatusUnprocessableEntity,
+}
+
+// HTTPStatus returns the status of the REST responses failing with the code
+func (c Code) HTTPStatus() int {
+	if status, ok := httpStatuses[c]; ok {
+		return status
+	}
+	return http.StatusBadRequest
+}
+
+// StatusCode returns the code of the errors answered with an HTTP status without a code of their own
+func StatusCode(status int) Code {
+	switch status {
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodeSizeLimitExceeded
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternal
+	}
+	return CodeInvalidRequest
+}
+
+// Error is an error returned to the API clients. The errors are matched with errors.Is and errors.As through the
+// *Error wrapping them.
+type Error struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+	// Field is the field of the input the error is about, empty when it is about the whole request
+	Field   string         `json:"field,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+
+	cause error
+}
+
+// New returns an error with the code and the message, the services declaring their errors with it
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Newf formats the message like fmt.Errorf, the error wrapped with %w being the cause of the error
+func Newf(code Code, format string, args ...any) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Code: code, Message: err.Error(), cause: errors.Unwrap(err)}
+}
+
+// Wrap returns an error with the code and the message of err, err being its cause
+func Wrap(code Code, err error) *Error {
+	return &Error{Code: code, Message: err.Error(), cause: err}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// WithField returns a copy of the error about the field, still matching the error with errors.Is
+func (e *Error) WithField(field string) *Error {
+	return &Error{Code: e.Code, Message: e.Message, Field: field, Details: e.Details, cause: e}
+}
+
+// WithMessage returns a copy of the error with the message, still matching the error with errors.Is
+func (e *Error) WithMessage(message string) *Error {
+	return &Error{Code: e.Code, Message: message, Field: e.Field, Details: e.Details, cause: e}
+}
+
+// WithDetails returns a copy of the error with the details, still matching the error with errors.Is
+func (e *Error) WithDetails(details map[string]any) *Error {
+	return &Error{Code: e.Code, Message: e.Message, Field: e.Field, Details: details, cause: e}
+}
+
+// As returns the error of the first *Error wrapped by err, with the message of err, or nil when err wraps none
+func As(err error) *Error {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		return nil
+	}
+	if apiErr == err {
+		return apiErr
+	}
+	return &Error{Code: apiErr.Code, Message: err.Error(), Field: apiErr.Field, Details: apiErr.Details, cause: err}
+}
+
+// From returns the error of err returned to the clients: the *Error it wraps, a VALIDATION_FAILED error for the
+// failed validations, a NOT_FOUND error for the records not found, and an INTERNAL error with the message of err
+// otherwise
+func From(err error) *Error {
+	if apiErr := As(err); apiErr != nil {
+		return apiErr
+	}
+
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) && len(validationErrs) > 0 {
+		violations := make([]map[string]string, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			violations = append(violations, map[string]string{"field": fieldName(fieldErr), "rule": fieldErr.Tag()})
+		}
+		return &Error{
+			Code:    CodeValidationFailed,
+			Message: err.Error(),
+			Field:   fieldName(validationErrs[0]),
+			Details: map[string]any{"violations": violations},
+			cause:   err,
+		}
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return Wrap(CodeNotFound, err)
+	}
+	return Wrap(CodeInternal, err)
+}
+
+// fieldName returns the path of the field of the input in camel case, without the name of the validated struct
+func fieldName(fieldErr validator.FieldError) string {
+	path := strings.Split(fieldErr.Namespace(), ".")
+	if len(path) > 1 {
+		path = path[1:]
+	}
+	for i, name := range path {
+		r, size := utf8.DecodeRuneInString(name)
+		path[i] = string(unicode.ToLower(r)) + name[size:]
+	}
+	return strings.Join(path, ".")
+}
@@ -0,0 +1,82 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var errTest = New(CodeNothingToPublish, "nothing to publish")
+
+func TestError(t *testing.T) {
+	t.Run("wrapped", func(t *testing.T) {
+		err := fmt.Errorf("%w: project ns/proj", errTest)
+		assert.ErrorIs(t, err, errTest)
+
+		apiErr := From(err)
+		assert.Equal(t, CodeNothingToPublish, apiErr.Code)
+		assert.Equal(t, "nothing to publish: project ns/proj", apiErr.Message)
+		assert.ErrorIs(t, apiErr, errTest)
+	})
+
+	t.Run("with field", func(t *testing.T) {
+		err := errTest.WithField("source").WithDetails(map[string]any{"project": "proj"})
+		assert.ErrorIs(t, err, errTest)
+		assert.Equal(t, "source", err.Field)
+		assert.Equal(t, map[string]any{"project": "proj"}, err.Details)
+		assert.Empty(t, errTest.Field, "the sentinel is not changed")
+	})
+
+	t.Run("newf", func(t *testing.T) {
+		err := Newf(CodeForbidden, "user %s: %w", "jdoe", gorm.ErrRecordNotFound)
+		assert.Equal(t, "user jdoe: record not found", err.Error())
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestFrom(t *testing.T) {
+	t.Run("validation", func(t *testing.T) {
+		type input struct {
+			Name      string `validate:"required"`
+			MaxLength int    `validate:"min=1"`
+		}
+		err := validator.New().Struct(input{})
+		require.Error(t, err)
+
+		apiErr := From(err)
+		assert.Equal(t, CodeValidationFailed, apiErr.Code)
+		assert.Equal(t, "name", apiErr.Field)
+		assert.Equal(t, map[string]any{"violations": []map[string]string{
+			{"field": "name", "rule": "required"},
+			{"field": "maxLength", "rule": "min"},
+		}}, apiErr.Details)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		assert.Equal(t, CodeNotFound, From(fmt.Errorf("load: %w", gorm.ErrRecordNotFound)).Code)
+	})
+
+	t.Run("internal", func(t *testing.T) {
+		apiErr := From(errors.New("boom"))
+		assert.Equal(t, CodeInternal, apiErr.Code)
+		assert.Equal(t, "boom", apiErr.Message)
+	})
+}
+
+func TestCode_HTTPStatus(t *testing.T) {
+	assert.Equal(t, http.StatusConflict, CodeNothingToPublish.HTTPStatus())
+	assert.Equal(t, http.StatusLocked, CodeDraftLocked.HTTPStatus())
+	assert.Equal(t, http.StatusBadRequest, CodeValidationFailed.HTTPStatus())
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, CodeNotFound, StatusCode(http.StatusNotFound))
+	assert.Equal(t, CodeInvalidRequest, StatusCode(http.StatusMethodNotAllowed))
+	assert.Equal(t, CodeInternal, StatusCode(http.StatusBadGateway))
+}
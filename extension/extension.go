@@ -0,0 +1,220 @@
+// Package extension lets the code built with the manager, like a fork or a plugin compiled in with a build tag, run
+// its own logic at some points of the publishes and of the drafts without changing the services. A plugin registers
+// its hooks from the init function of its package, imported by a file of the main package:
+//
+//	//go:build acme
+//
+//	package main
+//
+//	import _ "example.com/acme/flecto-plugin"
+//
+// The hooks run in the order they were registered, and can be disabled by name with extensions.disabled.
+package extension
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/policy"
+)
+
+// Point is a place of the manager where hooks run
+type Point string
+
+const (
+	// PointPrePublish runs before a publish is applied, a hook returning an error refusing the publish
+	PointPrePublish Point = "pre_publish"
+	// PointPostPublish runs once a publish is committed, the errors being only logged
+	PointPostPublish Point = "post_publish"
+	// PointPreDraftSave runs before a redirect or page draft is created or updated, a hook returning an error
+	// refusing the draft
+	PointPreDraftSave Point = "pre_draft_save"
+)
+
+// ErrHookRefused wraps the error of a hook refusing a publish or a draft
+var ErrHookRefused = errors.New("refused by extension hook")
+
+// PublishResult is a committed publish
+type PublishResult struct {
+	NamespaceCode string
+	ProjectCode   string
+	Version       int
+	PublishedBy   string
+	PublishedAt   time.Time
+	// Redirects and Pages are the numbers of redirect and page drafts published
+	Redirects int
+	Pages     int
+}
+
+// DraftSave is a redirect or page draft about to be saved. The hooks can change its new redirect or page, the
+// draft being validated afterwards.
+type DraftSave struct {
+	NamespaceCode string
+	ProjectCode   string
+	// ChangeType is CREATE, UPDATE or DELETE
+	ChangeType string
+	SavedBy    string
+	// Redirect is the new redirect of a redirect draft, nil for a page draft or a deletion
+	Redirect *commonTypes.Redirect
+	// Page is the new page of a page draft, nil for a redirect draft or a deletion
+	Page *commonTypes.Page
+}
+
+type PrePublishHook func(ctx context.Context, plan policy.Plan) error
+
+type PostPublishHook func(ctx context.Context, result PublishResult) error
+
+type PreDraftSaveHook func(ctx context.Context, draft *DraftSave) error
+
+type registered[H any] struct {
+	name string
+	hook H
+}
+
+// Registry holds the hooks of the extensions by point
+type Registry struct {
+	mu           sync.RWMutex
+	prePublish   []registered[PrePublishHook]
+	postPublish  []registered[PostPublishHook]
+	preDraftSave []registered[PreDraftSaveHook]
+}
+
+// Default is the registry of the manager, filled by the init functions of the extensions
+var Default = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// RegisterPrePublish adds a pre-publish hook to the default registry
+func RegisterPrePublish(name string, hook PrePublishHook) {
+	Default.RegisterPrePublish(name, hook)
+}
+
+// RegisterPostPublish adds a post-publish hook to the default registry
+func RegisterPostPublish(name string, hook PostPublishHook) {
+	Default.RegisterPostPublish(name, hook)
+}
+
+// RegisterPreDraftSave adds a pre-draft-save hook to the default registry
+func RegisterPreDraftSave(name string, hook PreDraftSaveHook) {
+	Default.RegisterPreDraftSave(name, hook)
+}
+
+// RegisterPrePublish adds a pre-publish hook, it panics when the name is already used at this point
+func (r *Registry) RegisterPrePublish(name string, hook PrePublishHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prePublish = register(r.prePublish, PointPrePublish, name, hook)
+}
+
+// RegisterPostPublish adds a post-publish hook, it panics when the name is already used at this point
+func (r *Registry) RegisterPostPublish(name string, hook PostPublishHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.postPublish = register(r.postPublish, PointPostPublish, name, hook)
+}
+
+// RegisterPreDraftSave adds a pre-draft-save hook, it panics when the name is already used at this point
+func (r *Registry) RegisterPreDraftSave(name string, hook PreDraftSaveHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.preDraftSave = register(r.preDraftSave, PointPreDraftSave, name, hook)
+}
+
+func register[H any](hooks []registered[H], point Point, name string, hook H) []registered[H] {
+	if name == "" {
+		panic(fmt.Sprintf("extension: %s hook without name", point))
+	}
+	for _, h := range hooks {
+		if h.name == name {
+			panic(fmt.Sprintf("extension: %s hook %q registered twice", point, name))
+		}
+	}
+	return append(hooks, registered[H]{name: name, hook: hook})
+}
+
+// Hooks returns the names of the registered hooks by point, in their order, the points without hooks being left out
+func (r *Registry) Hooks() map[Point][]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	hooks := make(map[Point][]string)
+	addNames(hooks, PointPrePublish, r.prePublish)
+	addNames(hooks, PointPostPublish, r.postPublish)
+	addNames(hooks, PointPreDraftSave, r.preDraftSave)
+	return hooks
+}
+
+func addNames[H any](hooks map[Point][]string, point Point, registeredHooks []registered[H]) {
+	for _, h := range registeredHooks {
+		hooks[point] = append(hooks[point], h.name)
+	}
+}
+
+// enabled returns the hooks not disabled by the configuration
+func enabled[H any](hooks []registered[H], cfg config.ExtensionsConfig) []registered[H] {
+	result := make([]registered[H], 0, len(hooks))
+	for _, h := range hooks {
+		if !slices.Contains(cfg.Disabled, h.name) {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// PrePublish runs the pre-publish hooks and returns the error of the first one refusing the publish, wrapping
+// ErrHookRefused. A nil registry has no hooks.
+func (r *Registry) PrePublish(ctx context.Context, cfg config.ExtensionsConfig, plan policy.Plan) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	hooks := enabled(r.prePublish, cfg)
+	r.mu.RUnlock()
+	for _, h := range hooks {
+		if err := h.hook(ctx, plan); err != nil {
+			return fmt.Errorf("%w %s: %w", ErrHookRefused, h.name, err)
+		}
+	}
+	return nil
+}
+
+// PostPublish runs all the post-publish hooks and returns their errors, joined
+func (r *Registry) PostPublish(ctx context.Context, cfg config.ExtensionsConfig, result PublishResult) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	hooks := enabled(r.postPublish, cfg)
+	r.mu.RUnlock()
+	var errs []error
+	for _, h := range hooks {
+		if err := h.hook(ctx, result); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PreDraftSave runs the pre-draft-save hooks, each one getting the draft changed by the previous ones, and returns
+// the error of the first one refusing the draft, wrapping ErrHookRefused
+func (r *Registry) PreDraftSave(ctx context.Context, cfg config.ExtensionsConfig, draft *DraftSave) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	hooks := enabled(r.preDraftSave, cfg)
+	r.mu.RUnlock()
+	for _, h := range hooks {
+		if err := h.hook(ctx, draft); err != nil {
+			return fmt.Errorf("%w %s: %w", ErrHookRefused, h.name, err)
+		}
+	}
+	return nil
+}
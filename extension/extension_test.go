@@ -0,0 +1,105 @@
+package extension
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	assert.Empty(t, r.Hooks())
+
+	r.RegisterPrePublish("audit", func(ctx context.Context, plan policy.Plan) error { return nil })
+	r.RegisterPrePublish("freeze", func(ctx context.Context, plan policy.Plan) error { return nil })
+	r.RegisterPostPublish("audit", func(ctx context.Context, result PublishResult) error { return nil })
+
+	assert.Equal(t, map[Point][]string{
+		PointPrePublish:  {"audit", "freeze"},
+		PointPostPublish: {"audit"},
+	}, r.Hooks())
+	assert.PanicsWithValue(t, `extension: pre_publish hook "audit" registered twice`, func() {
+		r.RegisterPrePublish("audit", func(ctx context.Context, plan policy.Plan) error { return nil })
+	})
+	assert.PanicsWithValue(t, "extension: pre_draft_save hook without name", func() {
+		r.RegisterPreDraftSave("", func(ctx context.Context, draft *DraftSave) error { return nil })
+	})
+}
+
+func TestRegistry_PrePublish(t *testing.T) {
+	r := NewRegistry()
+	var calls []string
+	r.RegisterPrePublish("first", func(ctx context.Context, plan policy.Plan) error {
+		calls = append(calls, "first")
+		return nil
+	})
+	r.RegisterPrePublish("freeze", func(ctx context.Context, plan policy.Plan) error {
+		calls = append(calls, "freeze")
+		return errors.New("project " + plan.ProjectCode + " is frozen")
+	})
+	r.RegisterPrePublish("last", func(ctx context.Context, plan policy.Plan) error {
+		calls = append(calls, "last")
+		return nil
+	})
+	plan := policy.Plan{NamespaceCode: "ns", ProjectCode: "proj"}
+
+	err := r.PrePublish(context.Background(), config.ExtensionsConfig{}, plan)
+	assert.ErrorIs(t, err, ErrHookRefused)
+	assert.EqualError(t, err, "refused by extension hook freeze: project proj is frozen")
+	assert.Equal(t, []string{"first", "freeze"}, calls)
+
+	calls = nil
+	require.NoError(t, r.PrePublish(context.Background(), config.ExtensionsConfig{Disabled: []string{"freeze"}}, plan))
+	assert.Equal(t, []string{"first", "last"}, calls)
+}
+
+func TestRegistry_PostPublish(t *testing.T) {
+	r := NewRegistry()
+	var got []PublishResult
+	r.RegisterPostPublish("failing", func(ctx context.Context, result PublishResult) error { return errors.New("unavailable") })
+	r.RegisterPostPublish("recorder", func(ctx context.Context, result PublishResult) error {
+		got = append(got, result)
+		return nil
+	})
+	result := PublishResult{NamespaceCode: "ns", ProjectCode: "proj", Version: 3}
+
+	err := r.PostPublish(context.Background(), config.ExtensionsConfig{}, result)
+	assert.EqualError(t, err, "failing: unavailable")
+	assert.Equal(t, []PublishResult{result}, got, "the hooks after a failing one still run")
+}
+
+func TestRegistry_PreDraftSave(t *testing.T) {
+	r := NewRegistry()
+	r.RegisterPreDraftSave("lowercase", func(ctx context.Context, draft *DraftSave) error {
+		if draft.Redirect != nil {
+			draft.Redirect.Target = "/lowercase"
+		}
+		return nil
+	})
+	r.RegisterPreDraftSave("no-delete", func(ctx context.Context, draft *DraftSave) error {
+		if draft.ChangeType == "DELETE" {
+			return errors.New("deletions are refused")
+		}
+		return nil
+	})
+
+	redirect := &commonTypes.Redirect{Source: "/a", Target: "/B"}
+	require.NoError(t, r.PreDraftSave(context.Background(), config.ExtensionsConfig{}, &DraftSave{ChangeType: "CREATE", Redirect: redirect}))
+	assert.Equal(t, "/lowercase", redirect.Target)
+
+	err := r.PreDraftSave(context.Background(), config.ExtensionsConfig{}, &DraftSave{ChangeType: "DELETE"})
+	assert.ErrorIs(t, err, ErrHookRefused)
+}
+
+func TestRegistry_Nil(t *testing.T) {
+	var r *Registry
+	assert.NoError(t, r.PrePublish(context.Background(), config.ExtensionsConfig{}, policy.Plan{}))
+	assert.NoError(t, r.PostPublish(context.Background(), config.ExtensionsConfig{}, PublishResult{}))
+	assert.NoError(t, r.PreDraftSave(context.Background(), config.ExtensionsConfig{}, &DraftSave{}))
+}
@@ -0,0 +1,221 @@
+package gitrepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/go-git/go-git/v5"
+	gitConfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+var (
+	ErrBranchNotFound = errors.New("branch not found")
+	ErrPathNotFound   = errors.New("path not found")
+	ErrFileTooLarge   = errors.New("file too large")
+	ErrUnsupportedURL = errors.New("unsupported repository URL")
+)
+
+// manifestExtensions are the extensions of the files read from a directory
+var manifestExtensions = map[string]bool{
+	".yaml": true,
+	".yml":  true,
+	".json": true,
+}
+
+// Source is a file or a directory of a branch of a Git repository
+type Source struct {
+	URL    string
+	Branch string
+	// Path is relative to the repository root, empty for the root directory
+	Path string
+}
+
+// File is a file of a source
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// Snapshot is the content of a source at a commit
+type Snapshot struct {
+	Commit string
+	// Files is the file of the source, or the YAML and JSON files of its directory sorted by name
+	Files []File
+}
+
+// Fetcher reads the sources from their repository
+type Fetcher interface {
+	// Head returns the SHA of the last commit of the branch of the source
+	Head(ctx context.Context, source Source) (string, error)
+	// Fetch returns the files of the source at the last commit of its branch
+	Fetch(ctx context.Context, source Source) (*Snapshot, error)
+}
+
+type fetcher struct {
+	credentials []config.GitCredentialConfig
+	maxFileSize int64
+}
+
+// NewFetcher returns a fetcher cloning the repositories in memory, the files larger than maxFileSize being refused
+func NewFetcher(credentials []config.GitCredentialConfig, maxFileSize int64) Fetcher {
+	return &fetcher{credentials: credentials, maxFileSize: maxFileSize}
+}
+
+// CheckURL returns ErrUnsupportedURL unless the URL is an https URL with a host and without credentials, the
+// repositories being fetched by the manager with the configured credentials only
+func CheckURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return fmt.Errorf("%w: %s, an https URL is expected", ErrUnsupportedURL, rawURL)
+	}
+	return nil
+}
+
+// matchesPrefix returns true when the URL has the scheme and the host of the prefix, and its path starts with the
+// path segments of the prefix
+func matchesPrefix(u *url.URL, prefix string) bool {
+	p, err := url.Parse(prefix)
+	if err != nil || !strings.EqualFold(u.Scheme, p.Scheme) || !strings.EqualFold(u.Host, p.Host) {
+		return false
+	}
+	prefixPath := strings.TrimSuffix(p.Path, "/")
+	return prefixPath == "" || u.Path == prefixPath || strings.HasPrefix(u.Path, prefixPath+"/")
+}
+
+// auth returns the credentials with the longest prefix of the URL, nil when none matches
+func (f *fetcher) auth(rawURL string) transport.AuthMethod {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	var match *config.GitCredentialConfig
+	for i, credential := range f.credentials {
+		if matchesPrefix(u, credential.URLPrefix) && (match == nil || len(credential.URLPrefix) > len(match.URLPrefix)) {
+			match = &f.credentials[i]
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	username := match.Username
+	if username == "" {
+		// Access tokens are accepted with any non-empty username
+		username = "git"
+	}
+	return &http.BasicAuth{Username: username, Password: match.Password}
+}
+
+func (f *fetcher) Head(ctx context.Context, source Source) (string, error) {
+	remote := git.NewRemote(memory.NewStorage(), &gitConfig.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{source.URL},
+	})
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: f.auth(source.URL)})
+	if err != nil {
+		return "", err
+	}
+	branch := plumbing.NewBranchReferenceName(source.Branch)
+	for _, ref := range refs {
+		if ref.Name() == branch {
+			return ref.Hash().String(), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrBranchNotFound, source.Branch)
+}
+
+func (f *fetcher) Fetch(ctx context.Context, source Source) (*Snapshot, error) {
+	repository, err := git.CloneContext(ctx, memory.NewStorage(), nil, &git.CloneOptions{
+		URL:           source.URL,
+		Auth:          f.auth(source.URL),
+		ReferenceName: plumbing.NewBranchReferenceName(source.Branch),
+		SingleBranch:  true,
+		Depth:         1,
+		Tags:          git.NoTags,
+	})
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) || errors.Is(err, git.NoMatchingRefSpecError{}) {
+			return nil, fmt.Errorf("%w: %s", ErrBranchNotFound, source.Branch)
+		}
+		return nil, err
+	}
+	head, err := repository.Head()
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repository.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{Commit: commit.Hash.String()}
+	sourcePath := strings.Trim(path.Clean("/"+source.Path), "/")
+	if sourcePath != "" {
+		entry, errEntry := tree.FindEntry(sourcePath)
+		if errEntry != nil {
+			return nil, fmt.Errorf("%w: %s", ErrPathNotFound, sourcePath)
+		}
+		if entry.Mode.IsFile() {
+			file, errFile := tree.TreeEntryFile(entry)
+			if errFile != nil {
+				return nil, errFile
+			}
+			content, errRead := f.read(sourcePath, file)
+			if errRead != nil {
+				return nil, errRead
+			}
+			snapshot.Files = []File{{Path: sourcePath, Content: content}}
+			return snapshot, nil
+		}
+		if tree, err = tree.Tree(sourcePath); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, entry := range tree.Entries {
+		if !entry.Mode.IsFile() || !manifestExtensions[strings.ToLower(path.Ext(entry.Name))] {
+			continue
+		}
+		file, errFile := tree.TreeEntryFile(&entry)
+		if errFile != nil {
+			return nil, errFile
+		}
+		filePath := path.Join(sourcePath, entry.Name)
+		content, errRead := f.read(filePath, file)
+		if errRead != nil {
+			return nil, errRead
+		}
+		snapshot.Files = append(snapshot.Files, File{Path: filePath, Content: content})
+	}
+	sort.Slice(snapshot.Files, func(i, j int) bool {
+		return snapshot.Files[i].Path < snapshot.Files[j].Path
+	})
+	return snapshot, nil
+}
+
+func (f *fetcher) read(filePath string, file *object.File) ([]byte, error) {
+	if f.maxFileSize > 0 && file.Size > f.maxFileSize {
+		return nil, fmt.Errorf("%w: %s is %d bytes, the limit is %d", ErrFileTooLarge, filePath, file.Size, f.maxFileSize)
+	}
+	reader, err := file.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(reader)
+}
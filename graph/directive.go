@@ -2,13 +2,13 @@ package graph
 
 import (
 	"context"
-	"errors"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 )
 
-var ErrUnauthorized = errors.New("unauthorized")
+var ErrUnauthorized = flectoErrors.New(flectoErrors.CodeUnauthenticated, "unauthorized")
 
 func PublicDirective(ctx context.Context, obj any, next graphql.Resolver) (any, error) {
 	ctx = context.WithValue(ctx, "public", true)
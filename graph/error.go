@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/i18n"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrorPresenter adds the code of the errors, and their field and details, to the extensions of the GraphQL errors.
+// The errors of gqlgen having a code, like the parsing and validation errors of the operations, keep it, and the
+// errors of the arguments are INVALID_REQUEST errors. The errors are localized in the language of the request.
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+	if _, ok := gqlErr.Extensions["code"]; ok {
+		return gqlErr
+	}
+
+	var apiErr *flectoErrors.Error
+	if gqlErr.Err != nil {
+		apiErr = i18n.Localize(ctx, gqlErr.Err)
+	} else {
+		apiErr = i18n.Localize(ctx, flectoErrors.New(flectoErrors.CodeInvalidRequest, gqlErr.Message))
+	}
+	gqlErr.Message = apiErr.Message
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = make(map[string]any)
+	}
+	gqlErr.Extensions["code"] = apiErr.Code
+	if apiErr.Field != "" {
+		gqlErr.Extensions["field"] = apiErr.Field
+	}
+	if len(apiErr.Details) > 0 {
+		gqlErr.Extensions["details"] = apiErr.Details
+	}
+	return gqlErr
+}
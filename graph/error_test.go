@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestErrorPresenter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("coded error", func(t *testing.T) {
+		sentinel := flectoErrors.New(flectoErrors.CodeAlreadyExists, "source is already used")
+		gqlErr := ErrorPresenter(ctx, fmt.Errorf("%w: /old", sentinel.WithField("source")))
+		assert.Equal(t, "source is already used: /old", gqlErr.Message)
+		assert.Equal(t, map[string]any{"code": flectoErrors.CodeAlreadyExists, "field": "source"}, gqlErr.Extensions)
+	})
+
+	t.Run("localized error", func(t *testing.T) {
+		sentinel := flectoErrors.New(flectoErrors.CodeAlreadyExists, "source is already used")
+		gqlErr := ErrorPresenter(i18n.WithLanguage(ctx, i18n.Spanish), fmt.Errorf("%w: /old", sentinel))
+		assert.Equal(t, "El recurso ya existe", gqlErr.Message)
+		assert.Equal(t, flectoErrors.CodeAlreadyExists, gqlErr.Extensions["code"])
+	})
+
+	t.Run("uncoded error", func(t *testing.T) {
+		gqlErr := ErrorPresenter(ctx, fmt.Errorf("boom"))
+		assert.Equal(t, map[string]any{"code": flectoErrors.CodeInternal}, gqlErr.Extensions)
+	})
+
+	t.Run("gqlgen error", func(t *testing.T) {
+		err := gqlerror.Errorf("Cannot query field")
+		err.Extensions = map[string]any{"code": "GRAPHQL_VALIDATION_FAILED"}
+		gqlErr := ErrorPresenter(ctx, err)
+		assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", gqlErr.Extensions["code"])
+	})
+
+	t.Run("argument error", func(t *testing.T) {
+		gqlErr := ErrorPresenter(ctx, gqlerror.Errorf("must be defined"))
+		assert.Equal(t, flectoErrors.CodeInvalidRequest, gqlErr.Extensions["code"])
+	})
+}
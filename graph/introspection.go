@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ErrIntrospectionDisabled is returned for the introspection queries when graphql.introspection is disabled
+var ErrIntrospectionDisabled = flectoErrors.New(flectoErrors.CodeForbidden, "introspection is disabled")
+
+// NoIntrospectionMiddleware rejects the operations selecting __schema or __type, for the servers not answering the
+// introspection queries, __typename being still allowed
+func NoIntrospectionMiddleware(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	if op := graphql.GetOperationContext(ctx).Operation; op != nil && selectsIntrospection(op.SelectionSet, make(map[string]bool)) {
+		return graphql.OneShot(&graphql.Response{Errors: gqlerror.List{ErrorPresenter(ctx, ErrIntrospectionDisabled)}})
+	}
+	return next(ctx)
+}
+
+// selectsIntrospection tells whether the selections, or those of their fragments, select __schema or __type, visited
+// holding the fragments already walked
+func selectsIntrospection(selections ast.SelectionSet, visited map[string]bool) bool {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if s.Name == "__schema" || s.Name == "__type" || selectsIntrospection(s.SelectionSet, visited) {
+				return true
+			}
+		case *ast.InlineFragment:
+			if selectsIntrospection(s.SelectionSet, visited) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			if visited[s.Name] || s.Definition == nil {
+				continue
+			}
+			visited[s.Name] = true
+			if selectsIntrospection(s.Definition.SelectionSet, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
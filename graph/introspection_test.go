@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+func TestNoIntrospectionMiddleware(t *testing.T) {
+	run := func(t *testing.T, query string) (*graphql.Response, bool) {
+		doc, err := parser.ParseQuery(&ast.Source{Input: query})
+		require.NoError(t, err)
+		for _, fragment := range doc.Fragments {
+			linkFragments(doc, fragment.SelectionSet)
+		}
+		linkFragments(doc, doc.Operations[0].SelectionSet)
+		ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{Operation: doc.Operations[0]})
+		called := false
+		next := func(ctx context.Context) graphql.ResponseHandler {
+			called = true
+			return graphql.OneShot(&graphql.Response{})
+		}
+		return NoIntrospectionMiddleware(ctx, next)(ctx), called
+	}
+
+	for name, query := range map[string]string{
+		"schema":             `{ __schema { queryType { name } } }`,
+		"type":               `{ __type(name: "Query") { name } }`,
+		"fragment":           `query { ...schema } fragment schema on Query { __schema { types { name } } }`,
+		"recursive fragment": `query { ...a } fragment a on Query { ...b } fragment b on Query { ...a __type(name: "Query") { name } }`,
+	} {
+		t.Run(name+" rejected", func(t *testing.T) {
+			resp, called := run(t, query)
+			assert.False(t, called)
+			require.Len(t, resp.Errors, 1)
+			assert.Equal(t, flectoErrors.CodeForbidden, resp.Errors[0].Extensions["code"])
+			assert.Equal(t, "introspection is disabled", resp.Errors[0].Message)
+		})
+	}
+
+	t.Run("typename run", func(t *testing.T) {
+		resp, called := run(t, `{ agentInstances { __typename name } }`)
+		assert.True(t, called)
+		assert.Empty(t, resp.Errors)
+	})
+}
+
+// linkFragments sets the definitions of the fragment spreads, as the validation of gqlgen does
+func linkFragments(doc *ast.QueryDocument, selections ast.SelectionSet) {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			linkFragments(doc, s.SelectionSet)
+		case *ast.InlineFragment:
+			linkFragments(doc, s.SelectionSet)
+		case *ast.FragmentSpread:
+			s.Definition = doc.Fragments.ForName(s.Name)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// OperationLimits rejects the operations nesting their fields deeper than Depth, or more complex than Complexity, a
+// limit of 0 being disabled. The persisted queries, reviewed before being allowed, have no limits.
+type OperationLimits struct {
+	Depth      int
+	Complexity int
+
+	es graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = &OperationLimits{}
+
+func (l *OperationLimits) ExtensionName() string {
+	return "OperationLimits"
+}
+
+func (l *OperationLimits) Validate(schema graphql.ExecutableSchema) error {
+	l.es = schema
+	return nil
+}
+
+func (l *OperationLimits) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if opCtx.Operation == nil || IsPersistedQuery(opCtx) {
+		return nil
+	}
+	if l.Depth > 0 {
+		if depth := selectionDepth(opCtx.Operation.SelectionSet); depth > l.Depth {
+			return ErrorPresenter(ctx, flectoErrors.Newf(flectoErrors.CodeDepthLimitExceeded,
+				"operation has a depth of %d, more than the limit of %d", depth, l.Depth).
+				WithDetails(map[string]any{"depth": depth, "limit": l.Depth}))
+		}
+	}
+	if l.Complexity > 0 {
+		if value := complexity.Calculate(ctx, l.es, opCtx.Operation, opCtx.Variables); value > l.Complexity {
+			return ErrorPresenter(ctx, flectoErrors.Newf(flectoErrors.CodeComplexityLimitExceeded,
+				"operation has a complexity of %d, more than the limit of %d", value, l.Complexity).
+				WithDetails(map[string]any{"complexity": value, "limit": l.Complexity}))
+		}
+	}
+	return nil
+}
+
+// selectionDepth returns the number of nested fields of the deepest branch of the selections, through their
+// fragments. The introspection fields are not counted, the fragment cycles being rejected by the validation.
+func selectionDepth(selections ast.SelectionSet) int {
+	depth := 0
+	for _, selection := range selections {
+		var d int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(s.SelectionSet)
+		case *ast.InlineFragment:
+			d = selectionDepth(s.SelectionSet)
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				d = selectionDepth(s.Definition.SelectionSet)
+			}
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
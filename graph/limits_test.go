@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+func TestOperationLimits_MutateOperationContext(t *testing.T) {
+	es := NewExecutableSchema(Config{})
+	run := func(t *testing.T, limits *OperationLimits, query string, persisted bool) *gqlerror.Error {
+		doc, errs := gqlparser.LoadQuery(es.Schema(), query)
+		require.Empty(t, errs)
+		require.NoError(t, limits.Validate(es))
+		opCtx := &graphql.OperationContext{Operation: doc.Operations[0]}
+		if persisted {
+			opCtx.Stats.SetExtension(persistedQueryExtension, true)
+		}
+		return limits.MutateOperationContext(graphql.WithOperationContext(context.Background(), opCtx), opCtx)
+	}
+
+	t.Run("depth", func(t *testing.T) {
+		err := run(t, &OperationLimits{Depth: 1}, `{ agentInstances { name } }`, false)
+		require.NotNil(t, err)
+		assert.Equal(t, flectoErrors.CodeDepthLimitExceeded, err.Extensions["code"])
+		assert.Equal(t, "operation has a depth of 2, more than the limit of 1", err.Message)
+		assert.Equal(t, map[string]any{"depth": 2, "limit": 1}, err.Extensions["details"])
+
+		assert.Nil(t, run(t, &OperationLimits{Depth: 2}, `{ agentInstances { name } }`, false))
+	})
+
+	t.Run("depth through the fragments", func(t *testing.T) {
+		query := `query { ...instances } fragment instances on Query { agentInstances { ... on AgentInstance { name } } }`
+		err := run(t, &OperationLimits{Depth: 1}, query, false)
+		require.NotNil(t, err)
+		assert.Equal(t, flectoErrors.CodeDepthLimitExceeded, err.Extensions["code"])
+	})
+
+	t.Run("introspection fields not counted", func(t *testing.T) {
+		assert.Nil(t, run(t, &OperationLimits{Depth: 1}, `{ __schema { types { fields { type { name } } } } }`, false))
+	})
+
+	t.Run("complexity", func(t *testing.T) {
+		err := run(t, &OperationLimits{Complexity: 1}, `{ agentInstances { name state } }`, false)
+		require.NotNil(t, err)
+		assert.Equal(t, flectoErrors.CodeComplexityLimitExceeded, err.Extensions["code"])
+		assert.Equal(t, map[string]any{"complexity": 3, "limit": 1}, err.Extensions["details"])
+
+		assert.Nil(t, run(t, &OperationLimits{Complexity: 3}, `{ agentInstances { name state } }`, false))
+	})
+
+	t.Run("persisted query not limited", func(t *testing.T) {
+		assert.Nil(t, run(t, &OperationLimits{Depth: 1, Complexity: 1}, `{ agentInstances { name state } }`, true))
+	})
+}
@@ -0,0 +1,94 @@
+package loader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FetchFunc fetches the values of a batch of keys, the keys missing from the result getting the zero value
+type FetchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader batches the keys loaded by the resolvers running concurrently within a short wait, and fetches
+// them with a single call. The values are cached for the lifetime of the loader, which is a single request.
+type Loader[K comparable, V any] struct {
+	fetch    FetchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending map[K]*result[V]
+	timer   *time.Timer
+}
+
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// NewLoader returns a loader fetching the keys loaded within wait, or as soon as maxBatch keys are pending
+func NewLoader[K comparable, V any](fetch FetchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    map[K]*result[V]{},
+		pending:  map[K]*result[V]{},
+	}
+}
+
+// Load returns the value of a key, waiting for the batch it belongs to
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.cache[key] = res
+		l.pending[key] = res
+		if len(l.pending) >= l.maxBatch {
+			l.dispatchLocked(ctx)
+		} else if l.timer == nil {
+			l.timer = time.AfterFunc(l.wait, func() {
+				l.mu.Lock()
+				defer l.mu.Unlock()
+				l.dispatchLocked(ctx)
+			})
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// dispatchLocked fetches the pending keys in the background, l.mu being held by the caller
+func (l *Loader[K, V]) dispatchLocked(ctx context.Context) {
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	if len(l.pending) == 0 {
+		return
+	}
+	batch := l.pending
+	l.pending = map[K]*result[V]{}
+
+	go func() {
+		keys := make([]K, 0, len(batch))
+		for key := range batch {
+			keys = append(keys, key)
+		}
+		values, err := l.fetch(ctx, keys)
+		for key, res := range batch {
+			res.value, res.err = values[key], err
+			close(res.done)
+		}
+	}()
+}
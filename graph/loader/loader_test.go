@@ -0,0 +1,132 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/database"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func loadConcurrently[K comparable, V any](l *Loader[K, V], keys []K) ([]V, []error) {
+	values := make([]V, len(keys))
+	errs := make([]error, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values[i], errs[i] = l.Load(context.Background(), key)
+		}()
+	}
+	wg.Wait()
+	return values, errs
+}
+
+func TestLoader_Load(t *testing.T) {
+	t.Run("batches concurrent loads", func(t *testing.T) {
+		var calls atomic.Int32
+		l := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+			calls.Add(1)
+			values := map[int]int{}
+			for _, key := range keys {
+				if key != 3 {
+					values[key] = key * 10
+				}
+			}
+			return values, nil
+		}, 10*time.Millisecond, 100)
+
+		values, errs := loadConcurrently(l, []int{1, 2, 3, 2})
+		assert.Equal(t, []int{10, 20, 0, 20}, values)
+		assert.Equal(t, []error{nil, nil, nil, nil}, errs)
+		assert.Equal(t, int32(1), calls.Load())
+
+		// The values are cached
+		value, err := l.Load(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, 10, value)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("splits the batches at the max size", func(t *testing.T) {
+		var calls atomic.Int32
+		l := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+			calls.Add(1)
+			assert.LessOrEqual(t, len(keys), 2)
+			return map[int]int{}, nil
+		}, 10*time.Millisecond, 2)
+
+		_, errs := loadConcurrently(l, []int{1, 2, 3, 4, 5})
+		assert.Equal(t, []error{nil, nil, nil, nil, nil}, errs)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("returns the error of the batch", func(t *testing.T) {
+		errFetch := errors.New("fetch failed")
+		l := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+			return nil, errFetch
+		}, time.Millisecond, 100)
+
+		_, errs := loadConcurrently(l, []int{1, 2})
+		assert.ErrorIs(t, errs[0], errFetch)
+		assert.ErrorIs(t, errs[1], errFetch)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		l := NewLoader(func(ctx context.Context, keys []int) (map[int]int, error) {
+			return map[int]int{}, nil
+		}, time.Hour, 100)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := l.Load(ctx, 1)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestFetchProjectCounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	projectService := mockFlectoService.NewMockProjectService(ctrl)
+
+	projectService.EXPECT().CountByProjects(gomock.Any(), "ns1", gomock.InAnyOrder([]string{"proj1", "proj2"})).
+		DoAndReturn(func(ctx context.Context, namespaceCode string, projectCodes []string) (map[string]model.ProjectCounts, error) {
+			assert.Equal(t, "ns1", database.NamespaceFromContext(ctx))
+			return map[string]model.ProjectCounts{"proj1": {Redirects: 1}, "proj2": {PageDrafts: 2}}, nil
+		})
+	projectService.EXPECT().CountByProjects(gomock.Any(), "ns2", []string{"proj1"}).
+		Return(map[string]model.ProjectCounts{"proj1": {RedirectDrafts: 3}}, nil)
+
+	counts, err := fetchProjectCounts(projectService)(context.Background(), []ProjectKey{
+		{NamespaceCode: "ns1", ProjectCode: "proj1"},
+		{NamespaceCode: "ns2", ProjectCode: "proj1"},
+		{NamespaceCode: "ns1", ProjectCode: "proj2"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[ProjectKey]model.ProjectCounts{
+		{NamespaceCode: "ns1", ProjectCode: "proj1"}: {Redirects: 1},
+		{NamespaceCode: "ns1", ProjectCode: "proj2"}: {PageDrafts: 2},
+		{NamespaceCode: "ns2", ProjectCode: "proj1"}: {RedirectDrafts: 3},
+	}, counts)
+}
+
+func TestFetchNamespaces(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	namespaceService := mockFlectoService.NewMockNamespaceService(ctrl)
+
+	namespaceService.EXPECT().GetByCodes(gomock.Any(), []string{"ns1", "ns2"}).
+		Return([]model.Namespace{{NamespaceCode: "ns1", Name: "Namespace 1"}}, nil)
+
+	namespaces, err := fetchNamespaces(namespaceService)(context.Background(), []string{"ns1", "ns2"})
+	require.NoError(t, err)
+	require.Len(t, namespaces, 1)
+	assert.Equal(t, "Namespace 1", namespaces["ns1"].Name)
+}
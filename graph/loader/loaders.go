@@ -0,0 +1,91 @@
+package loader
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	batchWait     = 2 * time.Millisecond
+	batchMaxItems = 100
+)
+
+type loadersKey struct{}
+
+// ProjectKey identifies a project across the namespaces
+type ProjectKey struct {
+	NamespaceCode string
+	ProjectCode   string
+}
+
+// Loaders are the loaders of a GraphQL request, batching the queries run by the field resolvers of a list
+type Loaders struct {
+	Namespace     *Loader[string, *model.Namespace]
+	ProjectCounts *Loader[ProjectKey, model.ProjectCounts]
+}
+
+func NewLoaders(namespaceService service.NamespaceService, projectService service.ProjectService) *Loaders {
+	return &Loaders{
+		Namespace:     NewLoader(fetchNamespaces(namespaceService), batchWait, batchMaxItems),
+		ProjectCounts: NewLoader(fetchProjectCounts(projectService), batchWait, batchMaxItems),
+	}
+}
+
+// For returns the loaders of the request, nil outside a query
+func For(ctx context.Context) *Loaders {
+	loaders, _ := ctx.Value(loadersKey{}).(*Loaders)
+	return loaders
+}
+
+// Middleware creates the loaders of each query. The mutations run without them, so that a field
+// resolved after a mutation is never answered from the values cached before it.
+func Middleware(namespaceService service.NamespaceService, projectService service.ProjectService) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		if op := graphql.GetOperationContext(ctx).Operation; op != nil && op.Operation == ast.Query {
+			ctx = context.WithValue(ctx, loadersKey{}, NewLoaders(namespaceService, projectService))
+		}
+		return next(ctx)
+	}
+}
+
+func fetchNamespaces(namespaceService service.NamespaceService) FetchFunc[string, *model.Namespace] {
+	return func(ctx context.Context, namespaceCodes []string) (map[string]*model.Namespace, error) {
+		namespaces, err := namespaceService.GetByCodes(ctx, namespaceCodes)
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]*model.Namespace, len(namespaces))
+		for i := range namespaces {
+			values[namespaces[i].NamespaceCode] = &namespaces[i]
+		}
+		return values, nil
+	}
+}
+
+// fetchProjectCounts counts the projects of each namespace against the database of the namespace
+func fetchProjectCounts(projectService service.ProjectService) FetchFunc[ProjectKey, model.ProjectCounts] {
+	return func(ctx context.Context, keys []ProjectKey) (map[ProjectKey]model.ProjectCounts, error) {
+		projectCodes := map[string][]string{}
+		for _, key := range keys {
+			projectCodes[key.NamespaceCode] = append(projectCodes[key.NamespaceCode], key.ProjectCode)
+		}
+
+		values := make(map[ProjectKey]model.ProjectCounts, len(keys))
+		for namespaceCode, codes := range projectCodes {
+			counts, err := projectService.CountByProjects(database.WithNamespace(ctx, namespaceCode), namespaceCode, codes)
+			if err != nil {
+				return nil, err
+			}
+			for projectCode, projectCounts := range counts {
+				values[ProjectKey{NamespaceCode: namespaceCode, ProjectCode: projectCode}] = projectCounts
+			}
+		}
+		return values, nil
+	}
+}
@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/flectolab/flecto-manager/database"
+)
+
+// NamespaceMiddleware runs the database statements of a field against the database of its namespace,
+// given by a namespaceCode argument of the field or of a parent field, or by the namespace of a parent object
+func NamespaceMiddleware(ctx context.Context, next graphql.Resolver) (any, error) {
+	if namespaceCode := fieldNamespace(graphql.GetFieldContext(ctx)); namespaceCode != "" {
+		ctx = database.WithNamespace(ctx, namespaceCode)
+	}
+	return next(ctx)
+}
+
+func fieldNamespace(fc *graphql.FieldContext) string {
+	for ; fc != nil; fc = fc.Parent {
+		if namespaceCode, ok := fc.Args["namespaceCode"].(string); ok && namespaceCode != "" {
+			return namespaceCode
+		}
+		for _, arg := range fc.Args {
+			if namespaceCode := objectNamespace(arg); namespaceCode != "" {
+				return namespaceCode
+			}
+		}
+		if namespaceCode := objectNamespace(fc.Result); namespaceCode != "" {
+			return namespaceCode
+		}
+	}
+	return ""
+}
+
+// objectNamespace returns the NamespaceCode field of a struct or of a pointer to a struct
+func objectNamespace(value any) string {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	field := v.FieldByName("NamespaceCode")
+	if !field.IsValid() {
+		return ""
+	}
+	switch field.Kind() {
+	case reflect.String:
+		return field.String()
+	case reflect.Ptr:
+		if !field.IsNil() && field.Elem().Kind() == reflect.String {
+			return field.Elem().String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+var (
+	// ErrPersistedQueryNotFound is returned for a hash matching no persisted query
+	ErrPersistedQueryNotFound = flectoErrors.New(flectoErrors.CodeNotFound, "persisted query not found")
+	// ErrQueryNotPersisted is returned for a query which is not a persisted query when they are enforced
+	ErrQueryNotPersisted = flectoErrors.New(flectoErrors.CodeForbidden, "only the persisted queries are allowed")
+)
+
+// persistedQueryExtension is the name of the statistics of the operations taken from the persisted queries
+const persistedQueryExtension = "PersistedQuery"
+
+// PersistedQueries is the allowlist of the queries of the GraphQL API, keyed by the hex SHA-256 hash of their text.
+// A client sends the hash in the persistedQuery extension of the request, as with the automatic persisted queries,
+// or the query itself. With enforce, the queries which are not in the allowlist are rejected.
+type PersistedQueries struct {
+	queries map[string]string
+	enforce bool
+}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = &PersistedQueries{}
+
+// NewPersistedQueries returns the allowlist of the queries, keyed by their hash
+func NewPersistedQueries(queries map[string]string, enforce bool) *PersistedQueries {
+	return &PersistedQueries{queries: queries, enforce: enforce}
+}
+
+// LoadPersistedQueries reads the allowlist of the queries from a JSON object of the queries keyed by their hash, a
+// hash which does not match its query being refused
+func LoadPersistedQueries(file string, enforce bool) (*PersistedQueries, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var queries map[string]string
+	if err = json.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("invalid persisted queries file %s: %w", file, err)
+	}
+	normalized := make(map[string]string, len(queries))
+	for hash, query := range queries {
+		hash = strings.ToLower(hash)
+		if queryHash(query) != hash {
+			return nil, fmt.Errorf("invalid persisted queries file %s: %s is not the SHA-256 hash of its query", file, hash)
+		}
+		normalized[hash] = query
+	}
+	return NewPersistedQueries(normalized, enforce), nil
+}
+
+func (p *PersistedQueries) ExtensionName() string {
+	return "PersistedQueries"
+}
+
+func (p *PersistedQueries) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+// MutateOperationParameters sets the query of the hash sent by the client, and rejects the queries which are not in
+// the allowlist when it is enforced. Otherwise, the hashes and queries missing from the allowlist are left to the
+// next extensions, like the automatic persisted queries.
+func (p *PersistedQueries) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	if rawParams.Query == "" {
+		persistedQuery, _ := rawParams.Extensions["persistedQuery"].(map[string]any)
+		hash, _ := persistedQuery["sha256Hash"].(string)
+		query, ok := p.queries[strings.ToLower(hash)]
+		if !ok {
+			if p.enforce {
+				return ErrorPresenter(ctx, ErrPersistedQueryNotFound)
+			}
+			return nil
+		}
+		rawParams.Query = query
+	} else if _, ok := p.queries[queryHash(rawParams.Query)]; !ok {
+		if p.enforce {
+			return ErrorPresenter(ctx, ErrQueryNotPersisted)
+		}
+		return nil
+	}
+	graphql.GetOperationContext(ctx).Stats.SetExtension(persistedQueryExtension, true)
+	return nil
+}
+
+// IsPersistedQuery tells whether the operation was taken from the persisted queries
+func IsPersistedQuery(opCtx *graphql.OperationContext) bool {
+	persisted, _ := opCtx.Stats.GetExtension(persistedQueryExtension).(bool)
+	return persisted
+}
+
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const persistedTestQuery = `{ agentInstances { name } }`
+
+func TestLoadPersistedQueries(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		file := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+		return file
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		file := write("valid.json", `{"`+queryHash(persistedTestQuery)+`": "{ agentInstances { name } }"}`)
+		queries, err := LoadPersistedQueries(file, true)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{queryHash(persistedTestQuery): persistedTestQuery}, queries.queries)
+		assert.True(t, queries.enforce)
+	})
+
+	t.Run("hash not matching its query", func(t *testing.T) {
+		file := write("mismatch.json", `{"abc": "{ agentInstances { name } }"}`)
+		_, err := LoadPersistedQueries(file, false)
+		assert.ErrorContains(t, err, "abc is not the SHA-256 hash of its query")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		_, err := LoadPersistedQueries(write("invalid.json", `[]`), false)
+		assert.ErrorContains(t, err, "invalid persisted queries file")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := LoadPersistedQueries(filepath.Join(dir, "missing.json"), false)
+		assert.Error(t, err)
+	})
+}
+
+func TestPersistedQueries_MutateOperationParameters(t *testing.T) {
+	hash := queryHash(persistedTestQuery)
+	run := func(enforce bool, params *graphql.RawParams) (*graphql.OperationContext, *gqlerror.Error) {
+		opCtx := &graphql.OperationContext{}
+		ctx := graphql.WithOperationContext(context.Background(), opCtx)
+		queries := NewPersistedQueries(map[string]string{hash: persistedTestQuery}, enforce)
+		return opCtx, queries.MutateOperationParameters(ctx, params)
+	}
+	withHash := func(hash string) *graphql.RawParams {
+		return &graphql.RawParams{Extensions: map[string]any{"persistedQuery": map[string]any{"version": float64(1), "sha256Hash": hash}}}
+	}
+
+	t.Run("hash of a persisted query", func(t *testing.T) {
+		params := withHash(hash)
+		opCtx, err := run(true, params)
+		require.Nil(t, err)
+		assert.Equal(t, persistedTestQuery, params.Query)
+		assert.True(t, IsPersistedQuery(opCtx))
+	})
+
+	t.Run("text of a persisted query", func(t *testing.T) {
+		opCtx, err := run(true, &graphql.RawParams{Query: persistedTestQuery})
+		require.Nil(t, err)
+		assert.True(t, IsPersistedQuery(opCtx))
+	})
+
+	t.Run("unknown hash", func(t *testing.T) {
+		_, err := run(true, withHash("abc"))
+		require.NotNil(t, err)
+		assert.Equal(t, flectoErrors.CodeNotFound, err.Extensions["code"])
+
+		params := withHash("abc")
+		opCtx, err := run(false, params)
+		assert.Nil(t, err, "left to the automatic persisted queries")
+		assert.Empty(t, params.Query)
+		assert.False(t, IsPersistedQuery(opCtx))
+	})
+
+	t.Run("ad-hoc query", func(t *testing.T) {
+		_, err := run(true, &graphql.RawParams{Query: `{ agentInstances { name state } }`})
+		require.NotNil(t, err)
+		assert.Equal(t, flectoErrors.CodeForbidden, err.Extensions["code"])
+		assert.Equal(t, "only the persisted queries are allowed", err.Message)
+
+		opCtx, err := run(false, &graphql.RawParams{Query: `{ agentInstances { name state } }`})
+		assert.Nil(t, err)
+		assert.False(t, IsPersistedQuery(opCtx))
+	})
+}
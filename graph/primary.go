@@ -0,0 +1,18 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// PrimaryMiddleware runs the reads of the mutations against the primary database, so that the
+// returned objects include their own changes instead of being read from a lagging replica
+func PrimaryMiddleware(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	if op := graphql.GetOperationContext(ctx).Operation; op != nil && op.Operation == ast.Mutation {
+		ctx = database.WithPrimary(ctx)
+	}
+	return next(ctx)
+}
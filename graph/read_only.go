@@ -0,0 +1,22 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ReadOnlyMiddleware rejects the mutations with the error returned by readOnlyError while the manager is in
+// read-only mode, the queries being still run
+func ReadOnlyMiddleware(readOnlyError func() error) graphql.OperationMiddleware {
+	return func(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+		if op := graphql.GetOperationContext(ctx).Operation; op != nil && op.Operation == ast.Mutation {
+			if err := readOnlyError(); err != nil {
+				return graphql.OneShot(&graphql.Response{Errors: gqlerror.List{ErrorPresenter(ctx, err)}})
+			}
+		}
+		return next(ctx)
+	}
+}
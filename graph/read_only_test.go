@@ -0,0 +1,48 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	readOnly := func() error {
+		return flectoErrors.New(flectoErrors.CodeReadOnly, "the manager is in read-only mode")
+	}
+	run := func(readOnlyError func() error, operation ast.Operation) (*graphql.Response, bool) {
+		ctx := graphql.WithOperationContext(context.Background(), &graphql.OperationContext{
+			Operation: &ast.OperationDefinition{Operation: operation},
+		})
+		called := false
+		next := func(ctx context.Context) graphql.ResponseHandler {
+			called = true
+			return graphql.OneShot(&graphql.Response{})
+		}
+		return ReadOnlyMiddleware(readOnlyError)(ctx, next)(ctx), called
+	}
+
+	t.Run("mutation rejected", func(t *testing.T) {
+		resp, called := run(readOnly, ast.Mutation)
+		assert.False(t, called)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "the manager is in read-only mode", resp.Errors[0].Message)
+		assert.Equal(t, flectoErrors.CodeReadOnly, resp.Errors[0].Extensions["code"])
+	})
+
+	t.Run("query run", func(t *testing.T) {
+		resp, called := run(readOnly, ast.Query)
+		assert.True(t, called)
+		assert.Empty(t, resp.Errors)
+	})
+
+	t.Run("mutation run when writable", func(t *testing.T) {
+		_, called := run(func() error { return nil }, ast.Mutation)
+		assert.True(t, called)
+	})
+}
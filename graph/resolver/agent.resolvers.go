@@ -13,8 +13,10 @@ import (
 	"github.com/flectolab/flecto-manager/auth"
 	"github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
 	"github.com/flectolab/flecto-manager/model"
+	flectoTypes "github.com/flectolab/flecto-manager/types"
 )
 
 // LoadDuration is the resolver for the load_duration field.
@@ -22,11 +24,34 @@ func (r *agentResolver) LoadDuration(ctx context.Context, obj *model.Agent) (int
 	return obj.Agent.LoadDuration.Nanoseconds(), nil
 }
 
+// Outdated is the resolver for the outdated field.
+func (r *agentInstanceResolver) Outdated(ctx context.Context, obj *model.AgentInstance) (bool, error) {
+	return obj.IsOutdated(), nil
+}
+
+// Outdated is the resolver for the outdated field.
+func (r *agentInstanceProjectResolver) Outdated(ctx context.Context, obj *model.AgentInstanceProject) (bool, error) {
+	return obj.IsOutdated(), nil
+}
+
+// DeleteAgentInstance is the resolver for the deleteAgentInstance field.
+func (r *mutationResolver) DeleteAgentInstance(ctx context.Context, name string) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionAgents, model.ActionWrite) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to write %s", userCtx.Username, model.AdminSectionAgents)
+	}
+
+	if err := r.AgentInstanceService.Delete(ctx, name); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // SearchAgents is the resolver for the searchAgents field.
-func (r *queryResolver) SearchAgents(ctx context.Context, namespaceCode string, projectCode string, pagination *types.PaginationInput, filter graph.AgentFilter, sort []database.SortInput) (*types.PaginatedResult[model.Agent], error) {
+func (r *queryResolver) SearchAgents(ctx context.Context, namespaceCode string, projectCode string, pagination *types.PaginationInput, filter graph.AgentFilter, sort []database.SortInput, where *database.FilterInput) (*types.PaginatedResult[model.Agent], error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAgent, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
 	query := r.AgentService.GetQuery(ctx).
@@ -53,10 +78,63 @@ func (r *queryResolver) SearchAgents(ctx context.Context, namespaceCode string,
 		query = database.ApplySort(query, model.AgentSortableColumns, sort, "")
 	}
 
+	query, err := database.ApplyFilter(query, model.AgentSortableColumns, where, "")
+	if err != nil {
+		return nil, err
+	}
+
 	return r.AgentService.SearchPaginate(ctx, pagination, query)
 }
 
+// AgentInstances is the resolver for the agentInstances field.
+func (r *queryResolver) AgentInstances(ctx context.Context, filter *graph.AgentInstanceFilter) ([]model.AgentInstance, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionAgents, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionAgents)
+	}
+
+	agentFilter := flectoTypes.AgentInstanceFilter{}
+	if filter != nil {
+		if filter.NamespaceCode != nil {
+			agentFilter.NamespaceCode = *filter.NamespaceCode
+		}
+		if filter.ProjectCode != nil {
+			agentFilter.ProjectCode = *filter.ProjectCode
+		}
+		if filter.State != nil {
+			agentFilter.State = string(*filter.State)
+		}
+		agentFilter.Outdated = filter.Outdated
+	}
+	return r.AgentInstanceService.List(ctx, agentFilter)
+}
+
+// Rollout is the resolver for the rollout field.
+func (r *projectResolver) Rollout(ctx context.Context, obj *model.Project, environment *types.Environment) (*model.RolloutStatus, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, obj.NamespaceCode, obj.ProjectCode, model.ResourceTypeAgent, model.ActionRead) {
+		return nil, nil
+	}
+
+	rolloutEnvironment := types.EnvironmentStaging
+	if environment != nil {
+		rolloutEnvironment = *environment
+	}
+	return r.AgentInstanceService.GetRollout(ctx, obj.NamespaceCode, obj.ProjectCode, rolloutEnvironment)
+}
+
 // Agent returns graph.AgentResolver implementation.
 func (r *Resolver) Agent() graph.AgentResolver { return &agentResolver{r} }
 
 type agentResolver struct{ *Resolver }
+
+// AgentInstance returns graph.AgentInstanceResolver implementation.
+func (r *Resolver) AgentInstance() graph.AgentInstanceResolver { return &agentInstanceResolver{r} }
+
+// AgentInstanceProject returns graph.AgentInstanceProjectResolver implementation.
+func (r *Resolver) AgentInstanceProject() graph.AgentInstanceProjectResolver {
+	return &agentInstanceProjectResolver{r}
+}
+
+type agentInstanceResolver struct{ *Resolver }
+type agentInstanceProjectResolver struct{ *Resolver }
@@ -0,0 +1,92 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// CreateChangeset is the resolver for the createChangeset field.
+func (r *mutationResolver) CreateChangeset(ctx context.Context, namespaceCode string, projectCode string, input graph.ChangesetInput) (*model.Changeset, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to write project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ChangesetService.Create(ctx, namespaceCode, projectCode, input.Name, changesetDescription(input))
+}
+
+// UpdateChangeset is the resolver for the updateChangeset field.
+func (r *mutationResolver) UpdateChangeset(ctx context.Context, namespaceCode string, projectCode string, changesetID int64, input graph.ChangesetInput) (*model.Changeset, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to write project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ChangesetService.Update(ctx, namespaceCode, projectCode, changesetID, input.Name, changesetDescription(input))
+}
+
+// DeleteChangeset is the resolver for the deleteChangeset field.
+func (r *mutationResolver) DeleteChangeset(ctx context.Context, namespaceCode string, projectCode string, changesetID int64, discardDrafts *bool) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionWrite) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to write project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	discard := discardDrafts != nil && *discardDrafts
+	if discard {
+		if err := r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+			return false, err
+		}
+	}
+
+	return r.ChangesetService.Delete(ctx, namespaceCode, projectCode, changesetID, discard)
+}
+
+// AssignDraftsToChangeset is the resolver for the assignDraftsToChangeset field.
+func (r *mutationResolver) AssignDraftsToChangeset(ctx context.Context, namespaceCode string, projectCode string, changesetID *int64, redirectDraftIDs []int64, pageDraftIDs []int64) (int, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionWrite) {
+		return 0, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to write project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ChangesetService.AssignDrafts(ctx, namespaceCode, projectCode, changesetID, redirectDraftIDs, pageDraftIDs)
+}
+
+// PublishChangeset is the resolver for the publishChangeset field.
+func (r *mutationResolver) PublishChangeset(ctx context.Context, namespaceCode string, projectCode string, changesetID int64, force *bool) (*model.Project, error) {
+	userCtx := auth.GetUser(ctx)
+	if err := r.checkPublish(userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+
+	return r.ChangesetService.Publish(ctx, namespaceCode, projectCode, changesetID, force != nil && *force)
+}
+
+// ProjectChangesets is the resolver for the projectChangesets field.
+func (r *queryResolver) ProjectChangesets(ctx context.Context, namespaceCode string, projectCode string) ([]model.Changeset, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ChangesetService.GetByProject(ctx, namespaceCode, projectCode)
+}
+
+// ProjectChangeset is the resolver for the projectChangeset field.
+func (r *queryResolver) ProjectChangeset(ctx context.Context, namespaceCode string, projectCode string, changesetID int64) (*model.Changeset, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ChangesetService.GetByID(ctx, namespaceCode, projectCode, changesetID)
+}
@@ -0,0 +1,48 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// Code is the resolver for the code field.
+func (r *draftOperationErrorResolver) Code(ctx context.Context, obj *flectoErrors.Error) (string, error) {
+	return string(obj.Code), nil
+}
+
+// ApplyDraftOperations is the resolver for the applyDraftOperations field.
+func (r *mutationResolver) ApplyDraftOperations(ctx context.Context, namespaceCode string, projectCode string, operations []model.DraftOperation) (*model.DraftBatchResult, error) {
+	userCtx := auth.GetUser(ctx)
+	for i, operation := range operations {
+		if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, operation.Target.ResourceType(), model.ActionWrite) {
+			return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode).
+				WithDetails(map[string]any{"index": i})
+		}
+
+		var draftID int64
+		if operation.Action != model.DraftOperationActionCreate && operation.DraftID != nil {
+			draftID = *operation.DraftID
+		}
+		if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, operation.Target.LockTarget(), draftID); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.DraftBatchService.Apply(ctx, namespaceCode, projectCode, operations)
+}
+
+// DraftOperationError returns graph.DraftOperationErrorResolver implementation.
+func (r *Resolver) DraftOperationError() graph.DraftOperationErrorResolver {
+	return &draftOperationErrorResolver{r}
+}
+
+type draftOperationErrorResolver struct{ *Resolver }
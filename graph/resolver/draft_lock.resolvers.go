@@ -0,0 +1,50 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+	"time"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// LockDrafts is the resolver for the lockDrafts field.
+func (r *mutationResolver) LockDrafts(ctx context.Context, namespaceCode string, projectCode string, input graph.DraftLockInput, ttlSeconds *int) (*model.DraftLock, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, draftLockResourceType(input.Target), model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	var ttl time.Duration
+	if ttlSeconds != nil {
+		ttl = time.Duration(*ttlSeconds) * time.Second
+	}
+	return r.DraftLockService.Lock(ctx, namespaceCode, projectCode, input.Target, draftLockID(input), userCtx.Username, ttl)
+}
+
+// UnlockDrafts is the resolver for the unlockDrafts field.
+func (r *mutationResolver) UnlockDrafts(ctx context.Context, namespaceCode string, projectCode string, input graph.DraftLockInput) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, draftLockResourceType(input.Target), model.ActionWrite) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	override := r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionDraftLocks, model.ActionWrite)
+	return r.DraftLockService.Unlock(ctx, namespaceCode, projectCode, input.Target, draftLockID(input), userCtx.Username, override)
+}
+
+// ProjectDraftLocks is the resolver for the projectDraftLocks field.
+func (r *queryResolver) ProjectDraftLocks(ctx context.Context, namespaceCode string, projectCode string) ([]model.DraftLock, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	return r.DraftLockService.GetByProject(ctx, namespaceCode, projectCode)
+}
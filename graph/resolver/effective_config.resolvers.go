@@ -0,0 +1,25 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// ProjectEffectiveConfig is the resolver for the projectEffectiveConfig field.
+func (r *queryResolver) ProjectEffectiveConfig(ctx context.Context, namespaceCode string, projectCode string, includeDrafts bool) (*model.EffectiveConfig, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) ||
+		!r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ProjectService.GetEffectiveConfig(ctx, namespaceCode, projectCode, includeDrafts)
+}
@@ -0,0 +1,91 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+	"errors"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+// WebhookSecret is the resolver for the webhookSecret field.
+func (r *gitSyncResolver) WebhookSecret(ctx context.Context, obj *model.ProjectGitSync) (*string, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, obj.NamespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return nil, nil
+	}
+	return &obj.WebhookSecret, nil
+}
+
+// ConfigureGitSync is the resolver for the configureGitSync field.
+func (r *mutationResolver) ConfigureGitSync(ctx context.Context, namespaceCode string, projectCode string, input graph.GitSyncInput) (*model.ProjectGitSync, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	}
+
+	gitSync := model.ProjectGitSync{
+		RepoURL: input.RepoURL,
+		Branch:  input.Branch,
+	}
+	if input.Path != nil {
+		gitSync.Path = *input.Path
+	}
+	if input.AutoPublish != nil {
+		gitSync.AutoPublish = *input.AutoPublish
+	}
+	if input.Poll != nil {
+		gitSync.Poll = *input.Poll
+	}
+	return r.GitSyncService.Configure(ctx, namespaceCode, projectCode, gitSync)
+}
+
+// DeleteGitSync is the resolver for the deleteGitSync field.
+func (r *mutationResolver) DeleteGitSync(ctx context.Context, namespaceCode string, projectCode string) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	}
+	return r.GitSyncService.Delete(ctx, namespaceCode, projectCode)
+}
+
+// SyncProject is the resolver for the syncProject field.
+func (r *mutationResolver) SyncProject(ctx context.Context, namespaceCode string, projectCode string, force *bool) (*model.GitSyncResult, error) {
+	userCtx := auth.GetUser(ctx)
+	// The sync may publish the project, it is refused to the project API keys like a publish
+	if err := r.checkPublish(userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+	if err := r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+	return r.GitSyncService.Sync(ctx, namespaceCode, projectCode, force != nil && *force)
+}
+
+// ProjectGitSync is the resolver for the projectGitSync field.
+func (r *queryResolver) ProjectGitSync(ctx context.Context, namespaceCode string, projectCode string) (*model.ProjectGitSync, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionRead) &&
+		!r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	gitSync, err := r.GitSyncService.GetByProject(ctx, namespaceCode, projectCode)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return gitSync, err
+}
+
+// GitSync returns graph.GitSyncResolver implementation.
+func (r *Resolver) GitSync() graph.GitSyncResolver { return &gitSyncResolver{r} }
+
+type gitSyncResolver struct{ *Resolver }
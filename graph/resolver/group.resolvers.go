@@ -0,0 +1,146 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// Users is the resolver for the users field.
+func (r *groupResolver) Users(ctx context.Context, obj *model.Group) ([]string, error) {
+	users, err := r.GroupService.GetGroupUsers(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	usernames := make([]string, len(users))
+	for i, user := range users {
+		usernames[i] = user.Username
+	}
+	return usernames, nil
+}
+
+// Roles is the resolver for the roles field.
+func (r *groupResolver) Roles(ctx context.Context, obj *model.Group) ([]string, error) {
+	roles, err := r.GroupService.GetGroupRoles(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, len(roles))
+	for i, role := range roles {
+		codes[i] = role.Code
+	}
+	return codes, nil
+}
+
+// CreateGroup is the resolver for the createGroup field.
+func (r *mutationResolver) CreateGroup(ctx context.Context, input graph.CreateGroupInput) (*model.Group, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+	}
+
+	// Validate group code: only alphanumeric, underscore and hyphen allowed
+	if !model.ValidRoleNameRegex.MatchString(input.Code) {
+		return nil, flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid group code: only alphanumeric characters, underscores and hyphens are allowed").WithField("code")
+	}
+
+	group := &model.Group{Code: input.Code, Name: input.Name}
+	if input.Description != nil {
+		group.Description = *input.Description
+	}
+	group, err := r.GroupService.Create(ctx, group)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(input.Users) > 0 {
+		if err = r.GroupService.UpdateGroupUsers(ctx, group.ID, input.Users); err != nil {
+			return nil, err
+		}
+	}
+	if len(input.Roles) > 0 {
+		if err = r.GroupService.UpdateGroupRoles(ctx, group.ID, input.Roles); err != nil {
+			return nil, err
+		}
+	}
+
+	return group, nil
+}
+
+// UpdateGroup is the resolver for the updateGroup field.
+func (r *mutationResolver) UpdateGroup(ctx context.Context, code string, input graph.UpdateGroupInput) (*model.Group, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+	}
+
+	group, err := r.GroupService.GetByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	update := model.Group{Name: input.Name}
+	if input.Description != nil {
+		update.Description = *input.Description
+	}
+	group, err = r.GroupService.Update(ctx, group.ID, update)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = r.GroupService.UpdateGroupUsers(ctx, group.ID, input.Users); err != nil {
+		return nil, err
+	}
+	if err = r.GroupService.UpdateGroupRoles(ctx, group.ID, input.Roles); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// DeleteGroup is the resolver for the deleteGroup field.
+func (r *mutationResolver) DeleteGroup(ctx context.Context, code string) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+	}
+
+	group, err := r.GroupService.GetByCode(ctx, code)
+	if err != nil {
+		return false, err
+	}
+	return r.GroupService.Delete(ctx, group.ID)
+}
+
+// Groups is the resolver for the groups field.
+func (r *queryResolver) Groups(ctx context.Context) ([]model.Group, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+	}
+	return r.GroupService.GetAll(ctx)
+}
+
+// Group is the resolver for the group field.
+func (r *queryResolver) Group(ctx context.Context, code string) (*model.Group, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+	}
+	return r.GroupService.GetByCode(ctx, code)
+}
+
+// Group returns graph.GroupResolver implementation.
+func (r *Resolver) Group() graph.GroupResolver { return &groupResolver{r} }
+
+type groupResolver struct{ *Resolver }
@@ -0,0 +1,55 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/common/types"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// ProjectTopRedirectHits is the resolver for the projectTopRedirectHits field.
+func (r *queryResolver) ProjectTopRedirectHits(ctx context.Context, namespaceCode string, projectCode string, days int, limit int) ([]model.RedirectHitCount, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.HitService.TopRedirects(ctx, namespaceCode, projectCode, days, limit)
+}
+
+// ProjectUnusedRedirects is the resolver for the projectUnusedRedirects field.
+func (r *queryResolver) ProjectUnusedRedirects(ctx context.Context, namespaceCode string, projectCode string, days int, pagination *types.PaginationInput) (*types.PaginatedResult[model.Redirect], error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.HitService.UnusedRedirects(ctx, namespaceCode, projectCode, days, pagination)
+}
+
+// ProjectTopPageHits is the resolver for the projectTopPageHits field.
+func (r *queryResolver) ProjectTopPageHits(ctx context.Context, namespaceCode string, projectCode string, days int, limit int) ([]model.PageHitCount, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.HitService.TopPages(ctx, namespaceCode, projectCode, days, limit)
+}
+
+// ProjectUnusedPages is the resolver for the projectUnusedPages field.
+func (r *queryResolver) ProjectUnusedPages(ctx context.Context, namespaceCode string, projectCode string, days int, pagination *types.PaginationInput) (*types.PaginatedResult[model.Page], error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.HitService.UnusedPages(ctx, namespaceCode, projectCode, days, pagination)
+}
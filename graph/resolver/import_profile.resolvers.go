@@ -0,0 +1,65 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// CreateImportProfile is the resolver for the createImportProfile field.
+func (r *mutationResolver) CreateImportProfile(ctx context.Context, namespaceCode string, projectCode string, input graph.ImportProfileInput) (*model.ImportProfile, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ImportProfileService.Create(ctx, namespaceCode, projectCode, toImportProfile(input))
+}
+
+// UpdateImportProfile is the resolver for the updateImportProfile field.
+func (r *mutationResolver) UpdateImportProfile(ctx context.Context, namespaceCode string, projectCode string, importProfileID int64, input graph.ImportProfileInput) (*model.ImportProfile, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ImportProfileService.Update(ctx, namespaceCode, projectCode, importProfileID, *toImportProfile(input))
+}
+
+// DeleteImportProfile is the resolver for the deleteImportProfile field.
+func (r *mutationResolver) DeleteImportProfile(ctx context.Context, namespaceCode string, projectCode string, importProfileID int64) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ImportProfileService.Delete(ctx, namespaceCode, projectCode, importProfileID)
+}
+
+// ProjectImportProfiles is the resolver for the projectImportProfiles field.
+func (r *queryResolver) ProjectImportProfiles(ctx context.Context, namespaceCode string, projectCode string) ([]model.ImportProfile, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ImportProfileService.GetByProject(ctx, namespaceCode, projectCode)
+}
+
+// ProjectImportProfile is the resolver for the projectImportProfile field.
+func (r *queryResolver) ProjectImportProfile(ctx context.Context, namespaceCode string, projectCode string, importProfileID int64) (*model.ImportProfile, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.ImportProfileService.GetByID(ctx, namespaceCode, projectCode, importProfileID)
+}
@@ -0,0 +1,162 @@
+package resolver
+
+// This file will not be regenerated automatically.
+//
+// It holds the queries shared by the offset and cursor variants of the list resolvers.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+// projectRedirectsQuery selects the redirects of a project matching the filter, joined with their draft
+func (r *queryResolver) projectRedirectsQuery(ctx context.Context, namespaceCode string, projectCode string, filter *graph.RedirectFilter) *gorm.DB {
+	query := r.RedirectService.GetQuery(ctx).
+		Joins("LEFT JOIN redirect_drafts ON redirect_drafts.old_redirect_id = redirects.id").
+		Where(fmt.Sprintf("redirects.%s = ? AND redirects.%s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode)
+
+	if filter != nil {
+		if filter.Search != nil && *filter.Search != "" {
+			search := "%" + *filter.Search + "%"
+			query = query.Where(
+				"redirects.source LIKE ? OR redirects.target LIKE ? OR redirect_drafts.new_source LIKE ? OR redirect_drafts.new_target LIKE ?",
+				search, search, search, search,
+			)
+		}
+		if len(filter.Types) > 0 {
+			query = query.Where("redirects.type IN ?", filter.Types)
+		}
+		if len(filter.Status) > 0 {
+			query = query.Where("redirects.status IN ?", filter.Status)
+		}
+		if len(filter.Tags) > 0 {
+			query = query.Where(
+				"redirects.id IN (SELECT redirect_tags.redirect_id FROM redirect_tags JOIN tags ON tags.id = redirect_tags.tag_id WHERE tags.name IN ?)"+
+					" OR redirect_drafts.id IN (SELECT redirect_draft_tags.redirect_draft_id FROM redirect_draft_tags JOIN tags ON tags.id = redirect_draft_tags.tag_id WHERE tags.name IN ?)",
+				filter.Tags, filter.Tags,
+			)
+		}
+		if filter.BrokenTarget != nil {
+			brokenQuery := "redirects.id IN (SELECT redirect_health.redirect_id FROM redirect_health WHERE " + model.RedirectHealthBrokenCondition + ")"
+			if *filter.BrokenTarget {
+				query = query.Where(brokenQuery)
+			} else {
+				query = query.Not(brokenQuery)
+			}
+		}
+		if len(filter.DraftStatus) > 0 {
+			// Build conditions for draft status filtering
+			// DraftStatus can include CREATE, UPDATE, DELETE (from draft) or PUBLISHED (no draft)
+			var hasDraftTypes []model.DraftChangeType
+			includePublished := false
+
+			for _, status := range filter.DraftStatus {
+				if status == model.DraftChangeTypePublished {
+					includePublished = true
+				} else {
+					hasDraftTypes = append(hasDraftTypes, status)
+				}
+			}
+
+			if len(hasDraftTypes) > 0 && includePublished {
+				query = query.Where("redirect_drafts.change_type IN ? OR redirect_drafts.change_type IS NULL", hasDraftTypes)
+			} else if len(hasDraftTypes) > 0 {
+				query = query.Where("redirect_drafts.change_type IN ?", hasDraftTypes)
+			} else if includePublished {
+				query = query.Where("redirect_drafts.change_type IS NULL")
+			}
+		}
+	}
+
+	return query
+}
+
+// projectPagesQuery selects the pages of a project matching the filter, joined with their draft
+func (r *queryResolver) projectPagesQuery(ctx context.Context, namespaceCode string, projectCode string, filter *graph.PageFilter) *gorm.DB {
+	query := r.PageService.GetQuery(ctx).
+		Joins("LEFT JOIN page_drafts ON page_drafts.old_page_id = pages.id").
+		Where(fmt.Sprintf("pages.%s = ? AND pages.%s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode)
+
+	if filter != nil {
+		if filter.Search != nil && *filter.Search != "" {
+			search := "%" + *filter.Search + "%"
+			query = query.Where(
+				"pages.path LIKE ? OR pages.content LIKE ? OR page_drafts.new_path LIKE ? OR page_drafts.new_content LIKE ?",
+				search, search, search, search,
+			)
+		}
+		if len(filter.Types) > 0 {
+			query = query.Where("pages.type IN ?", filter.Types)
+		}
+		if len(filter.ContentTypes) > 0 {
+			query = query.Where("pages.content_type IN ?", filter.ContentTypes)
+		}
+		if len(filter.DraftStatus) > 0 {
+			var hasDraftTypes []model.DraftChangeType
+			includePublished := false
+
+			for _, status := range filter.DraftStatus {
+				if status == model.DraftChangeTypePublished {
+					includePublished = true
+				} else {
+					hasDraftTypes = append(hasDraftTypes, status)
+				}
+			}
+
+			if len(hasDraftTypes) > 0 && includePublished {
+				query = query.Where("page_drafts.change_type IN ? OR page_drafts.change_type IS NULL", hasDraftTypes)
+			} else if len(hasDraftTypes) > 0 {
+				query = query.Where("page_drafts.change_type IN ?", hasDraftTypes)
+			} else if includePublished {
+				query = query.Where("page_drafts.change_type IS NULL")
+			}
+		}
+	}
+
+	return query
+}
+
+// projectRedirectDraftsQuery selects the redirect drafts of a project matching the filter
+func (r *queryResolver) projectRedirectDraftsQuery(ctx context.Context, namespaceCode string, projectCode string, filter *graph.RedirectDraftFilter) *gorm.DB {
+	query := r.RedirectDraftService.GetQuery(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode)
+
+	if filter != nil {
+		query = filterDraftAuthors(query, filter.CreatedBy, filter.UpdatedBy)
+		if filter.ChangesetID != nil {
+			query = query.Where("changeset_id = ?", *filter.ChangesetID)
+		}
+	}
+
+	return query
+}
+
+// projectPageDraftsQuery selects the page drafts of a project matching the filter
+func (r *queryResolver) projectPageDraftsQuery(ctx context.Context, namespaceCode string, projectCode string, filter *graph.PageDraftFilter) *gorm.DB {
+	query := r.PageDraftService.GetQuery(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode)
+
+	if filter != nil {
+		query = filterDraftAuthors(query, filter.CreatedBy, filter.UpdatedBy)
+		if filter.ChangesetID != nil {
+			query = query.Where("changeset_id = ?", *filter.ChangesetID)
+		}
+	}
+
+	return query
+}
+
+// filterDraftAuthors restricts the drafts to those staged and last edited by the given subjects, when set
+func filterDraftAuthors(query *gorm.DB, createdBy, updatedBy *string) *gorm.DB {
+	if createdBy != nil && *createdBy != "" {
+		query = query.Where("created_by = ?", *createdBy)
+	}
+	if updatedBy != nil && *updatedBy != "" {
+		query = query.Where("updated_by = ?", *updatedBy)
+	}
+	return query
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/flectolab/flecto-manager/auth"
 	"github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
 	"github.com/flectolab/flecto-manager/model"
 )
@@ -20,7 +21,7 @@ import (
 func (r *mutationResolver) CreateNamespace(ctx context.Context, input graph.CreateNamespaceInput) (*model.Namespace, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionNamespaces, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
 	}
 
 	newNamespace := &model.Namespace{
@@ -34,8 +35,8 @@ func (r *mutationResolver) CreateNamespace(ctx context.Context, input graph.Crea
 // UpdateNamespace is the resolver for the updateNamespace field.
 func (r *mutationResolver) UpdateNamespace(ctx context.Context, namespaceCode string, input graph.UpdateNamespaceInput) (*model.Namespace, error) {
 	userCtx := auth.GetUser(ctx)
-	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionNamespaces, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionNamespaces, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
 	}
 
 	return r.NamespaceService.Update(ctx, namespaceCode, model.Namespace{Name: input.Name})
@@ -45,12 +46,38 @@ func (r *mutationResolver) UpdateNamespace(ctx context.Context, namespaceCode st
 func (r *mutationResolver) DeleteNamespace(ctx context.Context, namespaceCode string) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionNamespaces, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
 	}
 
 	return r.NamespaceService.Delete(ctx, namespaceCode)
 }
 
+// UpdateNamespacePolicy is the resolver for the updateNamespacePolicy field.
+func (r *mutationResolver) UpdateNamespacePolicy(ctx context.Context, namespaceCode string, rules []graph.NamespacePolicyRuleInput) (*model.NamespacePolicy, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionNamespaces, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
+	}
+
+	policyRules := make([]model.NamespacePolicyRule, 0, len(rules))
+	for _, rule := range rules {
+		policyRule := model.NamespacePolicyRule{Type: rule.Type}
+		if rule.Pattern != nil {
+			policyRule.Pattern = *rule.Pattern
+		}
+		if rule.Message != nil {
+			policyRule.Message = *rule.Message
+		}
+		policyRules = append(policyRules, policyRule)
+	}
+	return r.NamespacePolicyService.Update(ctx, namespaceCode, policyRules)
+}
+
+// Policy is the resolver for the policy field.
+func (r *namespaceResolver) Policy(ctx context.Context, obj *model.Namespace) (*model.NamespacePolicy, error) {
+	return r.NamespacePolicyService.Get(ctx, obj.NamespaceCode)
+}
+
 // Projects is the resolver for the projects field.
 func (r *namespaceResolver) Projects(ctx context.Context, obj *model.Namespace) ([]model.Project, error) {
 	userCtx := auth.GetUser(ctx)
@@ -76,20 +103,18 @@ func (r *queryResolver) Namespaces(ctx context.Context) ([]model.Namespace, erro
 // Namespace is the resolver for the namespace field.
 func (r *queryResolver) Namespace(ctx context.Context, namespaceCode string) (*model.Namespace, error) {
 	userCtx := auth.GetUser(ctx)
-	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionNamespaces, model.ActionRead) &&
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionNamespaces, model.ActionRead) &&
 		!r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, "*", model.ResourceTypeAny, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access namespace %s", userCtx.Username, namespaceCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access namespace %s", userCtx.Username, namespaceCode)
 	}
 	return r.NamespaceService.GetByCode(ctx, namespaceCode)
 }
 
 // SearchNamespaces is the resolver for the searchNamespaces field.
-func (r *queryResolver) SearchNamespaces(ctx context.Context, pagination *types.PaginationInput, filter graph.NamespaceFilter, sort []database.SortInput) (*types.PaginatedResult[model.Namespace], error) {
+func (r *queryResolver) SearchNamespaces(ctx context.Context, pagination *types.PaginationInput, filter graph.NamespaceFilter, sort []database.SortInput, where *database.FilterInput) (*types.PaginatedResult[model.Namespace], error) {
 	userCtx := auth.GetUser(ctx)
 	query := r.NamespaceService.GetQuery(ctx)
-	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionNamespaces, model.ActionRead) {
-		query = r.PermissionChecker.FilterQueryByNamespace(query, userCtx.SubjectPermissions.Resources, model.ActionRead)
-	}
+	query = r.PermissionChecker.FilterQueryByAdminNamespace(query, userCtx.SubjectPermissions, model.AdminSectionNamespaces, model.ActionRead)
 
 	if filter.Search != nil && *filter.Search != "" {
 		search := fmt.Sprintf("%%%s%%", *filter.Search)
@@ -100,6 +125,11 @@ func (r *queryResolver) SearchNamespaces(ctx context.Context, pagination *types.
 		query = database.ApplySort(query, model.NamespaceSortableColumns, sort, "")
 	}
 
+	query, err := database.ApplyFilter(query, model.NamespaceSortableColumns, where, "")
+	if err != nil {
+		return nil, err
+	}
+
 	return r.NamespaceService.SearchPaginate(ctx, pagination, query)
 }
 
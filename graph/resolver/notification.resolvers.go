@@ -0,0 +1,51 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// SubscribeNotifications is the resolver for the subscribeNotifications field.
+func (r *mutationResolver) SubscribeNotifications(ctx context.Context, namespaceCode string, projectCode string, input graph.NotificationSubscriptionInput) (*model.NotificationSubscription, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	return r.NotificationService.Subscribe(ctx, namespaceCode, projectCode, userCtx.Username, model.NotificationSubscription{
+		Channel: input.Channel,
+		Target:  input.Target,
+		Events:  input.Events,
+	})
+}
+
+// UnsubscribeNotifications is the resolver for the unsubscribeNotifications field.
+func (r *mutationResolver) UnsubscribeNotifications(ctx context.Context, namespaceCode string, projectCode string, channel model.NotificationChannel) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	return r.NotificationService.Unsubscribe(ctx, namespaceCode, projectCode, userCtx.Username, channel)
+}
+
+// ProjectNotificationSubscriptions is the resolver for the projectNotificationSubscriptions field.
+func (r *queryResolver) ProjectNotificationSubscriptions(ctx context.Context, namespaceCode string, projectCode string) ([]model.NotificationSubscription, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	return r.NotificationService.GetByUser(ctx, namespaceCode, projectCode, userCtx.Username)
+}
+
+// NotificationChannels is the resolver for the notificationChannels field.
+func (r *queryResolver) NotificationChannels(ctx context.Context) ([]model.NotificationChannel, error) {
+	return r.NotificationService.Channels(), nil
+}
@@ -7,10 +7,10 @@ package resolver
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/flectolab/flecto-manager/auth"
 	"github.com/flectolab/flecto-manager/common/types"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
 	"github.com/flectolab/flecto-manager/model"
 )
@@ -19,9 +19,12 @@ import (
 func (r *mutationResolver) CreatePageDraft(ctx context.Context, namespaceCode string, projectCode string, input graph.CreatePageDraft) (*model.PageDraft, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
+	if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, model.DraftLockTargetPageDraft, 0); err != nil {
+		return nil, err
+	}
 	return r.PageDraftService.Create(ctx, namespaceCode, projectCode, input.OldPageID, input.NewPage)
 }
 
@@ -29,7 +32,11 @@ func (r *mutationResolver) CreatePageDraft(ctx context.Context, namespaceCode st
 func (r *mutationResolver) UpdatePageDraft(ctx context.Context, namespaceCode string, projectCode string, pageDraftID int64, input graph.UpdatePageDraft) (*model.PageDraft, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, model.DraftLockTargetPageDraft, pageDraftID); err != nil {
+		return nil, err
 	}
 	return r.PageDraftService.Update(ctx, pageDraftID, input.NewPage)
 }
@@ -38,9 +45,12 @@ func (r *mutationResolver) UpdatePageDraft(ctx context.Context, namespaceCode st
 func (r *mutationResolver) DeletePageDraft(ctx context.Context, namespaceCode string, projectCode string, pageDraftID int64) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
+	if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, model.DraftLockTargetPageDraft, pageDraftID); err != nil {
+		return false, err
+	}
 	return r.PageDraftService.Delete(ctx, pageDraftID)
 }
 
@@ -48,30 +58,66 @@ func (r *mutationResolver) DeletePageDraft(ctx context.Context, namespaceCode st
 func (r *mutationResolver) RollbackPageDraft(ctx context.Context, namespaceCode string, projectCode string) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
+	if err := r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return false, err
+	}
 	return r.PageDraftService.Rollback(ctx, namespaceCode, projectCode)
 }
 
+// RestoreDeletedPage is the resolver for the restoreDeletedPage field.
+func (r *mutationResolver) RestoreDeletedPage(ctx context.Context, namespaceCode string, projectCode string, pageID int64) (*model.PageDraft, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, model.DraftLockTargetPageDraft, 0); err != nil {
+		return nil, err
+	}
+	return r.PageDraftService.RestoreDeleted(ctx, namespaceCode, projectCode, pageID)
+}
+
 // ProjectsPageDrafts is the resolver for the projectsPageDrafts field.
 func (r *queryResolver) ProjectsPageDrafts(ctx context.Context, namespaceCode string, projectCode string, pagination *types.PaginationInput, filter *graph.PageDraftFilter) (*types.PaginatedResult[model.PageDraft], error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
-	query := r.PageDraftService.GetQuery(ctx).Preload("OldPage").
-		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode)
+	query := r.projectPageDraftsQuery(ctx, namespaceCode, projectCode, filter).Preload("OldPage")
 
 	return r.PageDraftService.SearchPaginate(ctx, pagination, query)
 }
 
+// ProjectsPageDraftsCursor is the resolver for the projectsPageDraftsCursor field.
+func (r *queryResolver) ProjectsPageDraftsCursor(ctx context.Context, namespaceCode string, projectCode string, cursor *types.CursorInput, filter *graph.PageDraftFilter) (*types.CursorResult[model.PageDraft], error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	query := r.projectPageDraftsQuery(ctx, namespaceCode, projectCode, filter)
+
+	return r.PageDraftService.SearchCursor(ctx, cursor, query)
+}
+
 // ProjectPageDraft is the resolver for the projectPageDraft field.
 func (r *queryResolver) ProjectPageDraft(ctx context.Context, namespaceCode string, projectCode string, pageDraftID int64) (*model.PageDraft, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
 	return r.PageDraftService.GetByID(ctx, pageDraftID)
 }
+
+// ProjectDeletedPages is the resolver for the projectDeletedPages field.
+func (r *queryResolver) ProjectDeletedPages(ctx context.Context, namespaceCode string, projectCode string, pagination *types.PaginationInput) (*types.PaginatedResult[model.PageTombstone], error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.PageDraftService.GetDeleted(ctx, namespaceCode, projectCode, pagination)
+}
@@ -0,0 +1,97 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// CreatePageTemplate is the resolver for the createPageTemplate field.
+func (r *mutationResolver) CreatePageTemplate(ctx context.Context, namespaceCode string, input graph.CreatePageTemplateInput) (*model.PageTemplate, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionNamespaces, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
+	}
+
+	return r.PageTemplateService.Create(ctx, namespaceCode, &model.PageTemplate{
+		Code:        input.Code,
+		Name:        input.Name,
+		ContentType: input.ContentType,
+		Content:     input.Content,
+	})
+}
+
+// UpdatePageTemplate is the resolver for the updatePageTemplate field.
+func (r *mutationResolver) UpdatePageTemplate(ctx context.Context, namespaceCode string, code string, input graph.UpdatePageTemplateInput) (*model.PageTemplate, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionNamespaces, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
+	}
+
+	return r.PageTemplateService.Update(ctx, namespaceCode, code, model.PageTemplate{
+		Name:        input.Name,
+		ContentType: input.ContentType,
+		Content:     input.Content,
+	})
+}
+
+// DeletePageTemplate is the resolver for the deletePageTemplate field.
+func (r *mutationResolver) DeletePageTemplate(ctx context.Context, namespaceCode string, code string) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionNamespaces, model.ActionWrite) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
+	}
+
+	return r.PageTemplateService.Delete(ctx, namespaceCode, code)
+}
+
+// CreatePageDraftFromTemplate is the resolver for the createPageDraftFromTemplate field.
+func (r *mutationResolver) CreatePageDraftFromTemplate(ctx context.Context, namespaceCode string, projectCode string, input graph.CreatePageDraftFromTemplate) (*model.PageDraft, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, model.DraftLockTargetPageDraft, 0); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(input.Values))
+	for _, value := range input.Values {
+		values[value.Name] = value.Value
+	}
+	return r.PageTemplateService.CreatePageFromTemplate(ctx, namespaceCode, projectCode, input.TemplateCode, input.Type, input.Path, values)
+}
+
+// PageTemplates is the resolver for the pageTemplates field.
+func (r *projectResolver) PageTemplates(ctx context.Context, obj *model.Project) ([]model.PageTemplate, error) {
+	return r.PageTemplateService.GetByNamespace(ctx, obj.NamespaceCode)
+}
+
+// PageTemplates is the resolver for the pageTemplates field.
+func (r *queryResolver) PageTemplates(ctx context.Context, namespaceCode string) ([]model.PageTemplate, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionNamespaces, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
+	}
+
+	return r.PageTemplateService.GetByNamespace(ctx, namespaceCode)
+}
+
+// PageTemplate is the resolver for the pageTemplate field.
+func (r *queryResolver) PageTemplate(ctx context.Context, namespaceCode string, code string) (*model.PageTemplate, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionNamespaces, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionNamespaces)
+	}
+
+	return r.PageTemplateService.GetByCode(ctx, namespaceCode, code)
+}
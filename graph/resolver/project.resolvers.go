@@ -8,20 +8,25 @@ package resolver
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/flectolab/flecto-manager/auth"
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/graph/loader"
 	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
 )
 
 // CreateProject is the resolver for the createProject field.
 func (r *mutationResolver) CreateProject(ctx context.Context, namespaceCode string, input *graph.CreateProjectInput) (*model.Project, error) {
 	userCtx := auth.GetUser(ctx)
-	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionProjects, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
 	}
 
 	newProject := &model.Project{
@@ -36,8 +41,18 @@ func (r *mutationResolver) CreateProject(ctx context.Context, namespaceCode stri
 // UpdateProject is the resolver for the updateProject field.
 func (r *mutationResolver) UpdateProject(ctx context.Context, namespaceCode string, projectCode string, input *graph.UpdateProjectInput) (*model.Project, error) {
 	userCtx := auth.GetUser(ctx)
-	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionProjects, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	}
+	if input.RedirectOptions != nil {
+		if _, err := r.ProjectService.UpdateRedirectOptions(ctx, namespaceCode, projectCode, *input.RedirectOptions); err != nil {
+			return nil, err
+		}
+	}
+	if input.CacheTTL != nil {
+		if _, err := r.ProjectService.UpdateCacheTTL(ctx, namespaceCode, projectCode, *input.CacheTTL); err != nil {
+			return nil, err
+		}
 	}
 	return r.ProjectService.Update(ctx, namespaceCode, projectCode, model.Project{Name: input.Name})
 }
@@ -45,41 +60,174 @@ func (r *mutationResolver) UpdateProject(ctx context.Context, namespaceCode stri
 // DeleteProject is the resolver for the deleteProject field.
 func (r *mutationResolver) DeleteProject(ctx context.Context, namespaceCode string, projectCode string) (bool, error) {
 	userCtx := auth.GetUser(ctx)
-	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionProjects, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
 	}
 
 	return r.ProjectService.Delete(ctx, namespaceCode, projectCode)
 }
 
 // PublishProject is the resolver for the publish field.
-func (r *mutationResolver) PublishProject(ctx context.Context, namespaceCode string, projectCode string) (*model.Project, error) {
+func (r *mutationResolver) PublishProject(ctx context.Context, namespaceCode string, projectCode string, force *bool) (*model.Project, error) {
+	userCtx := auth.GetUser(ctx)
+	if err := r.checkPublish(userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+
+	return r.ProjectService.Publish(ctx, namespaceCode, projectCode, types.PublishOptions{Force: force != nil && *force})
+}
+
+// SetProjectMaintenance is the resolver for the setProjectMaintenance field.
+func (r *mutationResolver) SetProjectMaintenance(ctx context.Context, namespaceCode string, projectCode string, input commonTypes.Maintenance) (*model.Project, error) {
+	userCtx := auth.GetUser(ctx)
+	if err := r.checkPublish(userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+
+	return r.ProjectService.SetMaintenance(ctx, namespaceCode, projectCode, input, userCtx.Username)
+}
+
+// PublishNamespace is the resolver for the publishNamespace field.
+func (r *mutationResolver) PublishNamespace(ctx context.Context, namespaceCode string, input *graph.PublishNamespaceInput) ([]model.ProjectPublishResult, error) {
+	userCtx := auth.GetUser(ctx)
+	projects, err := r.ProjectService.GetByNamespace(ctx, namespaceCode)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		if err = r.checkPublish(userCtx, namespaceCode, project.ProjectCode); err != nil {
+			return nil, err
+		}
+	}
+
+	opts := types.PublishNamespaceOptions{
+		Username:         userCtx.Username,
+		IgnoreDraftLocks: r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionDraftLocks, model.ActionWrite),
+	}
+	if input != nil {
+		if input.Concurrency != nil {
+			opts.Concurrency = *input.Concurrency
+		}
+		if input.StopOnFailure != nil {
+			opts.StopOnFailure = *input.StopOnFailure
+		}
+		if input.Force != nil {
+			opts.Force = *input.Force
+		}
+	}
+	return r.ProjectService.PublishNamespace(ctx, namespaceCode, opts)
+}
+
+// PromoteEnvironment is the resolver for the promoteEnvironment field.
+func (r *mutationResolver) PromoteEnvironment(ctx context.Context, namespaceCode string, projectCode string) (*model.ProjectEnvironment, error) {
+	userCtx := auth.GetUser(ctx)
+	if err := r.checkPublish(userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+
+	return r.ProjectService.PromoteEnvironment(ctx, namespaceCode, projectCode, userCtx.Username)
+}
+
+// MoveProject is the resolver for the moveProject field.
+func (r *mutationResolver) MoveProject(ctx context.Context, namespaceCode string, projectCode string, targetNamespaceCode string) (*model.Project, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionWrite) ||
+		!r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, targetNamespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	}
+
+	return r.ProjectService.MoveProject(ctx, namespaceCode, projectCode, targetNamespaceCode, userCtx.Username)
+}
+
+// CloneProject is the resolver for the cloneProject field.
+func (r *mutationResolver) CloneProject(ctx context.Context, namespaceCode string, projectCode string, input graph.CloneProjectInput) (*model.Project, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, input.TargetNamespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	}
+
+	opts := types.CloneProjectOptions{}
+	if input.Name != nil {
+		opts.Name = *input.Name
+	}
+	if input.IncludeDrafts != nil {
+		opts.IncludeDrafts = *input.IncludeDrafts
+	}
+	return r.ProjectService.CloneProject(ctx, namespaceCode, projectCode, input.TargetNamespaceCode, input.TargetProjectCode, opts)
+}
+
+// ApplyProjectManifest is the resolver for the applyProjectManifest field.
+func (r *mutationResolver) ApplyProjectManifest(ctx context.Context, namespaceCode string, projectCode string, manifest string, input *graph.ApplyProjectInput) (*model.ApplyResult, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
-	return r.ProjectService.Publish(ctx, namespaceCode, projectCode)
+	projectManifest, err := service.ParseProjectManifest(strings.NewReader(manifest))
+	if err != nil {
+		return nil, err
+	}
+	opts := types.ApplyProjectOptions{}
+	if input != nil {
+		if input.Publish != nil {
+			opts.Publish = *input.Publish
+		}
+		if input.DryRun != nil {
+			opts.DryRun = *input.DryRun
+		}
+		if input.Force != nil {
+			opts.Force = *input.Force
+		}
+	}
+	if opts.Publish {
+		if err = r.checkPublish(userCtx, namespaceCode, projectCode); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.DryRun {
+		if err = r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+			return nil, err
+		}
+	}
+	return r.ProjectApplyService.Apply(ctx, namespaceCode, projectCode, projectManifest, opts)
+}
+
+// Namespace is the resolver for the namespace field.
+func (r *projectResolver) Namespace(ctx context.Context, obj *model.Project) (*model.Namespace, error) {
+	if obj.Namespace != nil {
+		return obj.Namespace, nil
+	}
+	if loaders := loader.For(ctx); loaders != nil {
+		namespace, err := loaders.Namespace.Load(ctx, obj.NamespaceCode)
+		if err == nil && namespace == nil {
+			err = flectoErrors.Newf(flectoErrors.CodeNotFound, "namespace %s not found", obj.NamespaceCode)
+		}
+		return namespace, err
+	}
+	return r.NamespaceService.GetByCode(ctx, obj.NamespaceCode)
 }
 
 // CountRedirects is the resolver for the countRedirects field.
 func (r *projectResolver) CountRedirects(ctx context.Context, obj *model.Project) (int64, error) {
-	return r.ProjectService.CountRedirects(ctx, obj.NamespaceCode, obj.ProjectCode)
+	return r.projectCount(ctx, obj, func(counts model.ProjectCounts) int64 { return counts.Redirects }, r.ProjectService.CountRedirects)
 }
 
 // CountRedirectDrafts is the resolver for the countRedirectDrafts field.
 func (r *projectResolver) CountRedirectDrafts(ctx context.Context, obj *model.Project) (int64, error) {
-	return r.ProjectService.CountRedirectDrafts(ctx, obj.NamespaceCode, obj.ProjectCode)
+	return r.projectCount(ctx, obj, func(counts model.ProjectCounts) int64 { return counts.RedirectDrafts }, r.ProjectService.CountRedirectDrafts)
 }
 
 // CountPages is the resolver for the countPages field.
 func (r *projectResolver) CountPages(ctx context.Context, obj *model.Project) (int64, error) {
-	return r.ProjectService.CountPages(ctx, obj.NamespaceCode, obj.ProjectCode)
+	return r.projectCount(ctx, obj, func(counts model.ProjectCounts) int64 { return counts.Pages }, r.ProjectService.CountPages)
 }
 
 // CountPageDrafts is the resolver for the countPageDrafts field.
 func (r *projectResolver) CountPageDrafts(ctx context.Context, obj *model.Project) (int64, error) {
-	return r.ProjectService.CountPageDrafts(ctx, obj.NamespaceCode, obj.ProjectCode)
+	return r.projectCount(ctx, obj, func(counts model.ProjectCounts) int64 { return counts.PageDrafts }, r.ProjectService.CountPageDrafts)
 }
 
 // TotalPageContentSize is the resolver for the totalPageContentSize field.
@@ -87,6 +235,11 @@ func (r *projectResolver) TotalPageContentSize(ctx context.Context, obj *model.P
 	return r.ProjectService.TotalPageContentSize(ctx, obj.NamespaceCode, obj.ProjectCode)
 }
 
+// TotalPageStoredContentSize is the resolver for the totalPageStoredContentSize field.
+func (r *projectResolver) TotalPageStoredContentSize(ctx context.Context, obj *model.Project) (int64, error) {
+	return r.ProjectService.TotalPageStoredContentSize(ctx, obj.NamespaceCode, obj.ProjectCode)
+}
+
 // TotalPageContentSizeLimit is the resolver for the totalPageContentSizeLimit field.
 func (r *projectResolver) TotalPageContentSizeLimit(ctx context.Context, obj *model.Project) (int64, error) {
 	return r.ProjectService.TotalPageContentSizeLimit(), nil
@@ -98,13 +251,16 @@ func (r *projectResolver) CountAgentError(ctx context.Context, obj *model.Projec
 	return r.AgentService.CountByProjectAndStatus(ctx, obj.NamespaceCode, obj.ProjectCode, commonTypes.AgentStatusError, updatedAfter)
 }
 
+// Environments is the resolver for the environments field.
+func (r *projectResolver) Environments(ctx context.Context, obj *model.Project) ([]model.ProjectEnvironment, error) {
+	return r.ProjectService.GetEnvironments(ctx, obj.NamespaceCode, obj.ProjectCode)
+}
+
 // SearchProjects is the resolver for the searchProjects field.
-func (r *queryResolver) SearchProjects(ctx context.Context, pagination *commonTypes.PaginationInput, filter graph.ProjectFilter, sort []database.SortInput) (*commonTypes.PaginatedResult[model.Project], error) {
+func (r *queryResolver) SearchProjects(ctx context.Context, pagination *commonTypes.PaginationInput, filter graph.ProjectFilter, sort []database.SortInput, where *database.FilterInput) (*commonTypes.PaginatedResult[model.Project], error) {
 	userCtx := auth.GetUser(ctx)
 	query := r.ProjectService.GetQuery(ctx)
-	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionProjects, model.ActionRead) {
-		query = r.PermissionChecker.FilterQueryByNamespace(query, userCtx.SubjectPermissions.Resources, model.ActionRead)
-	}
+	query = r.PermissionChecker.FilterQueryByAdminNamespace(query, userCtx.SubjectPermissions, model.AdminSectionProjects, model.ActionRead)
 
 	if filter.Search != nil && *filter.Search != "" {
 		search := fmt.Sprintf("%%%s%%", *filter.Search)
@@ -115,23 +271,45 @@ func (r *queryResolver) SearchProjects(ctx context.Context, pagination *commonTy
 		query = query.Where(fmt.Sprintf("%s = ?", model.ColumnNamespaceCode), filter.NamespaceCode)
 	}
 
-	if len(sort) > 0 {
-		query = database.ApplySort(query, model.ProjectSortableColumns, sort, "")
+	query, err := database.ApplyFilter(query, model.ProjectSortableColumns, where, "")
+	if err != nil {
+		return nil, err
 	}
 
-	return r.ProjectService.SearchPaginate(ctx, pagination, query)
+	// The sorts go along with the order of the pagination, the projects of the sharded namespaces being merged by them
+	return r.ProjectService.SearchPaginate(ctx, projectPagination(pagination, sort), query)
 }
 
 // Project is the resolver for the project field.
 func (r *queryResolver) Project(ctx context.Context, namespaceCode string, projectCode string) (*model.Project, error) {
 	userCtx := auth.GetUser(ctx)
-	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionProjects, model.ActionRead) &&
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionRead) &&
 		!r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 	return r.ProjectService.GetByCodeWithNamespace(ctx, namespaceCode, projectCode)
 }
 
+// ProjectCounts is the resolver for the projectCounts field.
+func (r *queryResolver) ProjectCounts(ctx context.Context, namespaceCode string) ([]model.ProjectCounts, error) {
+	userCtx := auth.GetUser(ctx)
+	counts, err := r.ProjectService.CountsByNamespace(ctx, namespaceCode)
+	if err != nil {
+		return nil, err
+	}
+	if r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionRead) {
+		return counts, nil
+	}
+
+	readable := []model.ProjectCounts{}
+	for _, projectCounts := range counts {
+		if r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCounts.ProjectCode, model.ResourceTypeAny, model.ActionRead) {
+			readable = append(readable, projectCounts)
+		}
+	}
+	return readable, nil
+}
+
 // Project returns graph.ProjectResolver implementation.
 func (r *Resolver) Project() graph.ProjectResolver { return &projectResolver{r} }
 
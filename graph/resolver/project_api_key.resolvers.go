@@ -0,0 +1,55 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// CreateProjectAPIKey is the resolver for the createProjectAPIKey field.
+func (r *mutationResolver) CreateProjectAPIKey(ctx context.Context, namespaceCode string, projectCode string, input graph.CreateProjectAPIKeyInput) (*graph.ProjectAPIKeyCreateResponse, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	}
+
+	if !model.ValidRoleNameRegex.MatchString(input.Name) {
+		return nil, flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid project API key name: only alphanumeric characters, underscores and hyphens are allowed").WithField("name")
+	}
+
+	key, plainKey, err := r.ProjectAPIKeyService.Create(ctx, namespaceCode, projectCode, model.ProjectAPIKey{
+		Name:      input.Name,
+		Scopes:    input.Scopes,
+		ExpiresAt: input.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &graph.ProjectAPIKeyCreateResponse{Key: key, PlainKey: plainKey}, nil
+}
+
+// DeleteProjectAPIKey is the resolver for the deleteProjectAPIKey field.
+func (r *mutationResolver) DeleteProjectAPIKey(ctx context.Context, namespaceCode string, projectCode string, id int64) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	}
+	return r.ProjectAPIKeyService.Delete(ctx, namespaceCode, projectCode, id)
+}
+
+// ProjectAPIKeys is the resolver for the projectAPIKeys field.
+func (r *queryResolver) ProjectAPIKeys(ctx context.Context, namespaceCode string, projectCode string) ([]model.ProjectAPIKey, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionProjects)
+	}
+	return r.ProjectAPIKeyService.GetByProject(ctx, namespaceCode, projectCode)
+}
@@ -22,11 +22,15 @@ func (r *queryResolver) ProjectDashboard(ctx context.Context, namespaceCode stri
 		Version:     stats.Version,
 		PublishedAt: stats.PublishedAt,
 		RedirectStats: &graph.RedirectStats{
-			Total:          stats.RedirectTotal,
-			CountBasic:     stats.RedirectCountBasic,
-			CountBasicHost: stats.RedirectCountBasicHost,
-			CountRegex:     stats.RedirectCountRegex,
-			CountRegexHost: stats.RedirectCountRegexHost,
+			Total:                  stats.RedirectTotal,
+			CountBasic:             stats.RedirectCountBasic,
+			CountBasicHost:         stats.RedirectCountBasicHost,
+			CountRegex:             stats.RedirectCountRegex,
+			CountRegexHost:         stats.RedirectCountRegexHost,
+			CountMovedPermanent:    stats.RedirectCountMovedPermanent,
+			CountFound:             stats.RedirectCountFound,
+			CountTemporaryRedirect: stats.RedirectCountTemporary,
+			CountPermanentRedirect: stats.RedirectCountPermanent,
 		},
 		RedirectDraftStats: &graph.RedirectDraftStats{
 			Total:       stats.RedirectDraftTotal,
@@ -35,9 +39,11 @@ func (r *queryResolver) ProjectDashboard(ctx context.Context, namespaceCode stri
 			CountDelete: stats.RedirectDraftCountDelete,
 		},
 		PageStats: &graph.PageStats{
-			Total:          stats.PageTotal,
-			CountBasic:     stats.PageCountBasic,
-			CountBasicHost: stats.PageCountBasicHost,
+			Total:                 stats.PageTotal,
+			CountBasic:            stats.PageCountBasic,
+			CountBasicHost:        stats.PageCountBasicHost,
+			TotalContentSize:      stats.PageContentSize,
+			TotalContentSizeLimit: stats.PageContentSizeLimit,
 		},
 		PageDraftStats: &graph.PageDraftStats{
 			Total:       stats.PageDraftTotal,
@@ -0,0 +1,60 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// AddProjectMember is the resolver for the addProjectMember field.
+func (r *mutationResolver) AddProjectMember(ctx context.Context, namespaceCode string, projectCode string, username string, role model.ProjectMemberRole) (*model.ProjectMember, error) {
+	userCtx := auth.GetUser(ctx)
+	if err := r.checkProjectMembersAdmin(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+	return r.ProjectMemberService.Add(ctx, namespaceCode, projectCode, username, role)
+}
+
+// UpdateProjectMemberRole is the resolver for the updateProjectMemberRole field.
+func (r *mutationResolver) UpdateProjectMemberRole(ctx context.Context, namespaceCode string, projectCode string, username string, role model.ProjectMemberRole) (*model.ProjectMember, error) {
+	userCtx := auth.GetUser(ctx)
+	if err := r.checkProjectMembersAdmin(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+	return r.ProjectMemberService.UpdateRole(ctx, namespaceCode, projectCode, username, role)
+}
+
+// RemoveProjectMember is the resolver for the removeProjectMember field.
+func (r *mutationResolver) RemoveProjectMember(ctx context.Context, namespaceCode string, projectCode string, username string) (bool, error) {
+	userCtx := auth.GetUser(ctx)
+	if err := r.checkProjectMembersAdmin(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return false, err
+	}
+	return r.ProjectMemberService.Remove(ctx, namespaceCode, projectCode, username)
+}
+
+// TransferProjectOwnership is the resolver for the transferProjectOwnership field.
+func (r *mutationResolver) TransferProjectOwnership(ctx context.Context, namespaceCode string, projectCode string, username string) (*model.ProjectMember, error) {
+	userCtx := auth.GetUser(ctx)
+	if err := r.checkProjectMembersAdmin(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+	return r.ProjectMemberService.TransferOwnership(ctx, namespaceCode, projectCode, username)
+}
+
+// ProjectMembers is the resolver for the projectMembers field.
+func (r *queryResolver) ProjectMembers(ctx context.Context, namespaceCode string, projectCode string) ([]model.ProjectMember, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionRead) &&
+		!r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	return r.ProjectMemberService.GetByProject(ctx, namespaceCode, projectCode)
+}
@@ -7,77 +7,90 @@ package resolver
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/flectolab/flecto-manager/auth"
 	"github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
 	"github.com/flectolab/flecto-manager/model"
 )
 
+// Tags is the resolver for the tags field.
+func (r *redirectResolver) Tags(ctx context.Context, obj *model.Redirect) ([]string, error) {
+	return model.TagNames(obj.Tags), nil
+}
+
 // ProjectsRedirects is the resolver for the projectsRedirects field.
-func (r *queryResolver) ProjectsRedirects(ctx context.Context, namespaceCode string, projectCode string, pagination *types.PaginationInput, filter *graph.RedirectFilter, sort []database.SortInput) (*types.PaginatedResult[model.Redirect], error) {
+func (r *queryResolver) ProjectsRedirects(ctx context.Context, namespaceCode string, projectCode string, pagination *types.PaginationInput, filter *graph.RedirectFilter, sort []database.SortInput, where *database.FilterInput) (*types.PaginatedResult[model.Redirect], error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
-	}
-	query := r.RedirectService.GetQuery(ctx).
-		Joins("LEFT JOIN redirect_drafts ON redirect_drafts.old_redirect_id = redirects.id").
-		Where(fmt.Sprintf("redirects.%s = ? AND redirects.%s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode)
-
-	if filter != nil {
-		if filter.Search != nil && *filter.Search != "" {
-			search := "%" + *filter.Search + "%"
-			query = query.Where(
-				"redirects.source LIKE ? OR redirects.target LIKE ? OR redirect_drafts.new_source LIKE ? OR redirect_drafts.new_target LIKE ?",
-				search, search, search, search,
-			)
-		}
-		if len(filter.Types) > 0 {
-			query = query.Where("redirects.type IN ?", filter.Types)
-		}
-		if len(filter.Status) > 0 {
-			query = query.Where("redirects.status IN ?", filter.Status)
-		}
-		if len(filter.DraftStatus) > 0 {
-			// Build conditions for draft status filtering
-			// DraftStatus can include CREATE, UPDATE, DELETE (from draft) or PUBLISHED (no draft)
-			var hasDraftTypes []model.DraftChangeType
-			includePublished := false
-
-			for _, status := range filter.DraftStatus {
-				if status == model.DraftChangeTypePublished {
-					includePublished = true
-				} else {
-					hasDraftTypes = append(hasDraftTypes, status)
-				}
-			}
-
-			if len(hasDraftTypes) > 0 && includePublished {
-				query = query.Where("redirect_drafts.change_type IN ? OR redirect_drafts.change_type IS NULL", hasDraftTypes)
-			} else if len(hasDraftTypes) > 0 {
-				query = query.Where("redirect_drafts.change_type IN ?", hasDraftTypes)
-			} else if includePublished {
-				query = query.Where("redirect_drafts.change_type IS NULL")
-			}
-		}
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
+	query := r.projectRedirectsQuery(ctx, namespaceCode, projectCode, filter)
 
 	// Apply sorting
 	if len(sort) > 0 {
 		query = database.ApplySort(query, model.RedirectSortableColumns, sort, "redirects")
 	}
 
+	query, err := database.ApplyFilter(query, model.RedirectSortableColumns, where, "redirects")
+	if err != nil {
+		return nil, err
+	}
+
 	return r.RedirectService.SearchPaginate(ctx, pagination, query)
 }
 
+// ProjectsRedirectsCursor is the resolver for the projectsRedirectsCursor field.
+func (r *queryResolver) ProjectsRedirectsCursor(ctx context.Context, namespaceCode string, projectCode string, cursor *types.CursorInput, filter *graph.RedirectFilter, where *database.FilterInput) (*types.CursorResult[model.Redirect], error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	query := r.projectRedirectsQuery(ctx, namespaceCode, projectCode, filter)
+
+	query, err := database.ApplyFilter(query, model.RedirectSortableColumns, where, "redirects")
+	if err != nil {
+		return nil, err
+	}
+
+	return r.RedirectService.SearchCursor(ctx, cursor, query)
+}
+
 // ProjectRedirect is the resolver for the projectRedirect field.
 func (r *queryResolver) ProjectRedirect(ctx context.Context, namespaceCode string, projectCode string, redirectID int64) (*model.Redirect, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
 	return r.RedirectService.GetByID(ctx, namespaceCode, projectCode, redirectID)
 }
+
+// NamespaceRedirectOverlaps is the resolver for the namespaceRedirectOverlaps field.
+func (r *queryResolver) NamespaceRedirectOverlaps(ctx context.Context, namespaceCode string, limit *int) ([]model.RedirectOverlap, error) {
+	userCtx := auth.GetUser(ctx)
+	projects, err := r.ProjectService.GetByNamespace(ctx, namespaceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectCodes []string
+	for _, project := range projects {
+		if r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, project.ProjectCode, model.ResourceTypeRedirect, model.ActionRead) {
+			projectCodes = append(projectCodes, project.ProjectCode)
+		}
+	}
+
+	overlapLimit := 0
+	if limit != nil {
+		overlapLimit = *limit
+	}
+	return r.RedirectService.FindSourceOverlaps(ctx, namespaceCode, projectCodes, overlapLimit)
+}
+
+// Redirect returns graph.RedirectResolver implementation.
+func (r *Resolver) Redirect() graph.RedirectResolver { return &redirectResolver{r} }
+
+type redirectResolver struct{ *Resolver }
@@ -7,43 +7,75 @@ package resolver
 
 import (
 	"context"
-	"fmt"
+	"net/http"
 	"net/url"
 
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/flectolab/flecto-manager/auth"
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
 )
 
+// Errors is the resolver for the errors field.
+func (r *importJobResolver) Errors(ctx context.Context, obj *model.ImportJob) ([]graph.ImportRedirectError, error) {
+	graphErrors := make([]graph.ImportRedirectError, 0, len(obj.Errors))
+	for _, e := range obj.Errors {
+		graphErrors = append(graphErrors, graph.ImportRedirectError{
+			Line:    e.Line,
+			Source:  strPtrOrNil(e.Source),
+			Target:  strPtrOrNil(e.Target),
+			Reason:  convertErrorReason(service.ImportErrorReason(e.Reason)),
+			Message: e.Message,
+		})
+	}
+	return graphErrors, nil
+}
+
+// Tags is the resolver for the tags field.
+func (r *redirectDraftResolver) Tags(ctx context.Context, obj *model.RedirectDraft) ([]string, error) {
+	return model.TagNames(obj.Tags), nil
+}
+
 // CreateRedirectDraft is the resolver for the createRedirectDraft field.
 func (r *mutationResolver) CreateRedirectDraft(ctx context.Context, namespaceCode string, projectCode string, input graph.CreateRedirectDraft) (*model.RedirectDraft, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
-	return r.RedirectDraftService.Create(ctx, namespaceCode, projectCode, input.OldRedirectID, input.NewRedirect)
+	if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, model.DraftLockTargetRedirectDraft, 0); err != nil {
+		return nil, err
+	}
+	return r.RedirectDraftService.Create(ctx, namespaceCode, projectCode, input.OldRedirectID, input.NewRedirect, input.Tags)
 }
 
 // UpdateRedirectDraft is the resolver for the updateRedirectDraft field.
 func (r *mutationResolver) UpdateRedirectDraft(ctx context.Context, namespaceCode string, projectCode string, redirectDraftID int64, input graph.UpdateRedirectDraft) (*model.RedirectDraft, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, model.DraftLockTargetRedirectDraft, redirectDraftID); err != nil {
+		return nil, err
 	}
-	return r.RedirectDraftService.Update(ctx, redirectDraftID, input.NewRedirect)
+	return r.RedirectDraftService.Update(ctx, redirectDraftID, input.NewRedirect, input.Tags)
 }
 
 // DeleteRedirectDraft is the resolver for the deleteRedirectDraft field.
 func (r *mutationResolver) DeleteRedirectDraft(ctx context.Context, namespaceCode string, projectCode string, redirectDraftID int64) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
+	if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, model.DraftLockTargetRedirectDraft, redirectDraftID); err != nil {
+		return false, err
+	}
 	return r.RedirectDraftService.Delete(ctx, redirectDraftID)
 }
 
@@ -51,111 +83,229 @@ func (r *mutationResolver) DeleteRedirectDraft(ctx context.Context, namespaceCod
 func (r *mutationResolver) RollbackRedirectDraft(ctx context.Context, namespaceCode string, projectCode string) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
+	if err := r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return false, err
+	}
 	return r.RedirectDraftService.Rollback(ctx, namespaceCode, projectCode)
 }
 
+// DeleteRedirectDraftsByTag is the resolver for the deleteRedirectDraftsByTag field.
+func (r *mutationResolver) DeleteRedirectDraftsByTag(ctx context.Context, namespaceCode string, projectCode string, tag string, force *bool) (int, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
+		return 0, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	if err := r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return 0, err
+	}
+	return r.RedirectDraftService.DeleteByTag(ctx, namespaceCode, projectCode, tag, force != nil && *force)
+}
+
 // ImportRedirectDraft is the resolver for the importRedirectDraft field.
 func (r *mutationResolver) ImportRedirectDraft(ctx context.Context, namespaceCode string, projectCode string, file graphql.Upload, input *graph.ImportRedirectInput) (*graph.ImportRedirectResult, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
-	// Validate file
-	if err := r.RedirectImportService.ValidateFile(file.Filename, file.ContentType, file.Size); err != nil {
+	if err := r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+	opts, err := r.buildImportRedirectOptions(ctx, namespaceCode, projectCode, input)
+	if err != nil {
+		return nil, err
+	}
+	format, err := r.RedirectImportService.ValidateFile(file.Filename, file.ContentType, file.Size)
+	if err != nil {
 		return nil, err
 	}
 
-	// Parse file
-	parsedRows, parseErrors, err := r.RedirectImportService.ParseFile(file.File)
+	// Parse and import the file batch by batch
+	importResult, err := r.RedirectImportService.ImportFile(ctx, namespaceCode, projectCode, file.File, format, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build import options
-	opts := service.ImportRedirectOptions{
-		Overwrite: true, // Default to true
+	return toGraphImportRedirectResult(importResult), nil
+}
+
+// PreviewImportRedirectDraft is the resolver for the previewImportRedirectDraft field.
+func (r *mutationResolver) PreviewImportRedirectDraft(ctx context.Context, namespaceCode string, projectCode string, file graphql.Upload, input *graph.ImportRedirectInput) (*graph.ImportRedirectResult, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	opts, err := r.buildImportRedirectOptions(ctx, namespaceCode, projectCode, input)
+	if err != nil {
+		return nil, err
 	}
-	if input != nil {
-		opts.Overwrite = input.Overwrite
+	format, err := r.RedirectImportService.ValidateFile(file.Filename, file.ContentType, file.Size)
+	if err != nil {
+		return nil, err
 	}
 
-	// Import rows
-	importResult, err := r.RedirectImportService.Import(ctx, namespaceCode, projectCode, parsedRows, opts)
+	// Simulate the import batch by batch without writing anything
+	previewResult, err := r.RedirectImportService.PreviewFile(ctx, namespaceCode, projectCode, file.File, format, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert service errors to GraphQL errors
-	graphErrors := make([]graph.ImportRedirectError, 0, len(parseErrors)+len(importResult.Errors))
+	return toGraphImportRedirectResult(previewResult), nil
+}
 
-	// Add parse errors
-	for _, e := range parseErrors {
-		graphErrors = append(graphErrors, graph.ImportRedirectError{
-			Line:    e.Line,
-			Source:  strPtrOrNil(e.Source),
-			Target:  strPtrOrNil(e.Target),
-			Reason:  convertErrorReason(e.Reason),
-			Message: e.Message,
-		})
+// RewriteRedirectDrafts is the resolver for the rewriteRedirectDrafts field.
+func (r *mutationResolver) RewriteRedirectDrafts(ctx context.Context, namespaceCode string, projectCode string, input graph.RedirectRewriteInput) (*types.RedirectRewriteResult, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
-	// Add import errors
-	for _, e := range importResult.Errors {
-		graphErrors = append(graphErrors, graph.ImportRedirectError{
-			Line:    e.Line,
-			Source:  strPtrOrNil(e.Source),
-			Target:  strPtrOrNil(e.Target),
-			Reason:  convertErrorReason(e.Reason),
-			Message: e.Message,
-		})
+	if !input.DryRun {
+		if err := r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+			return nil, err
+		}
+	}
+	return r.RedirectDraftService.Rewrite(ctx, namespaceCode, projectCode, types.RedirectRewriteInput{
+		Find:    input.Find,
+		Replace: input.Replace,
+		Regex:   input.Regex,
+		Source:  input.Source,
+		Target:  input.Target,
+		DryRun:  input.DryRun,
+	})
+}
+
+// ReorderRedirects is the resolver for the reorderRedirects field.
+func (r *mutationResolver) ReorderRedirects(ctx context.Context, namespaceCode string, projectCode string, redirectIDs []int64) (int, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
+		return 0, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	if err := r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return 0, err
+	}
+	return r.RedirectDraftService.Reorder(ctx, namespaceCode, projectCode, redirectIDs)
+}
+
+// RestoreDeletedRedirect is the resolver for the restoreDeletedRedirect field.
+func (r *mutationResolver) RestoreDeletedRedirect(ctx context.Context, namespaceCode string, projectCode string, redirectID int64) (*model.RedirectDraft, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	if err := r.checkDraftLock(ctx, userCtx, namespaceCode, projectCode, model.DraftLockTargetRedirectDraft, 0); err != nil {
+		return nil, err
+	}
+	return r.RedirectDraftService.RestoreDeleted(ctx, namespaceCode, projectCode, redirectID)
+}
+
+// StartImportRedirectDraftJob is the resolver for the startImportRedirectDraftJob field.
+func (r *mutationResolver) StartImportRedirectDraftJob(ctx context.Context, namespaceCode string, projectCode string, file graphql.Upload, input *graph.ImportRedirectInput) (*model.ImportJob, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionWrite) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	if err := r.checkProjectDraftLocks(ctx, userCtx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+	opts, err := r.buildImportRedirectOptions(ctx, namespaceCode, projectCode, input)
+	if err != nil {
+		return nil, err
 	}
+	format, err := r.RedirectImportService.ValidateFile(file.Filename, file.ContentType, file.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.RedirectImportService.StartImportJob(ctx, namespaceCode, projectCode, file.File, format, opts)
+}
 
-	totalLines := len(parsedRows) + len(parseErrors)
+// MoveRedirects is the resolver for the moveRedirects field.
+func (r *mutationResolver) MoveRedirects(ctx context.Context, namespaceCode string, projectCode string, redirectIDs []int64, targetNamespaceCode string, targetProjectCode string) ([]model.RedirectDraft, error) {
+	userCtx := auth.GetUser(ctx)
+	for _, project := range [][2]string{{namespaceCode, projectCode}, {targetNamespaceCode, targetProjectCode}} {
+		if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, project[0], project[1], model.ResourceTypeRedirect, model.ActionWrite) {
+			return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, project[0], project[1])
+		}
+		if err := r.checkProjectDraftLocks(ctx, userCtx, project[0], project[1]); err != nil {
+			return nil, err
+		}
+	}
 
-	return &graph.ImportRedirectResult{
-		Success:       importResult.Success && len(parseErrors) == 0,
-		TotalLines:    totalLines,
-		ImportedCount: importResult.ImportedCount,
-		SkippedCount:  importResult.SkippedCount,
-		ErrorCount:    len(parseErrors) + importResult.ErrorCount,
-		Errors:        graphErrors,
-	}, nil
+	return r.RedirectDraftService.MoveRedirects(ctx, namespaceCode, projectCode, targetNamespaceCode, targetProjectCode, redirectIDs)
 }
 
 // ProjectsRedirectDrafts is the resolver for the projectsRedirectDrafts field.
 func (r *queryResolver) ProjectsRedirectDrafts(ctx context.Context, namespaceCode string, projectCode string, pagination *commonTypes.PaginationInput, filter *graph.RedirectDraftFilter) (*commonTypes.PaginatedResult[model.RedirectDraft], error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
-	query := r.RedirectDraftService.GetQuery(ctx).Preload("OldRedirect").
-		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode)
+	query := r.projectRedirectDraftsQuery(ctx, namespaceCode, projectCode, filter).Preload("OldRedirect")
 
 	return r.RedirectDraftService.SearchPaginate(ctx, pagination, query)
 }
 
+// ProjectsRedirectDraftsCursor is the resolver for the projectsRedirectDraftsCursor field.
+func (r *queryResolver) ProjectsRedirectDraftsCursor(ctx context.Context, namespaceCode string, projectCode string, cursor *commonTypes.CursorInput, filter *graph.RedirectDraftFilter) (*commonTypes.CursorResult[model.RedirectDraft], error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	query := r.projectRedirectDraftsQuery(ctx, namespaceCode, projectCode, filter)
+
+	return r.RedirectDraftService.SearchCursor(ctx, cursor, query)
+}
+
 // ProjectRedirectDraft is the resolver for the projectRedirectDraft field.
 func (r *queryResolver) ProjectRedirectDraft(ctx context.Context, namespaceCode string, projectCode string, redirectDraftID int64) (*model.RedirectDraft, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
 
 	return r.RedirectDraftService.GetByID(ctx, redirectDraftID)
 }
 
+// ProjectDeletedRedirects is the resolver for the projectDeletedRedirects field.
+func (r *queryResolver) ProjectDeletedRedirects(ctx context.Context, namespaceCode string, projectCode string, pagination *commonTypes.PaginationInput) (*commonTypes.PaginatedResult[model.RedirectTombstone], error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.RedirectDraftService.GetDeleted(ctx, namespaceCode, projectCode, pagination)
+}
+
+// ProjectImportJob is the resolver for the projectImportJob field.
+func (r *queryResolver) ProjectImportJob(ctx context.Context, namespaceCode string, projectCode string, importJobID int64) (*model.ImportJob, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.RedirectImportService.GetImportJob(ctx, namespaceCode, projectCode, importJobID)
+}
+
 // ProjectRedirectDraftCheck is the resolver for the projectRedirectDraftCheck field.
 func (r *queryResolver) ProjectRedirectDraftCheck(ctx context.Context, namespaceCode string, projectCode string, redirectCheck graph.RedirectCheck, scope *graph.RedirectScope) ([]graph.RedirectCheckResult, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
 	}
-	var err error
-	treeMatcher := commonTypes.NewRedirectTreeMatcher()
+	project, err := r.ProjectService.GetByCode(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+	treeMatcher := commonTypes.NewRedirectTreeMatcherWithOptions(project.RedirectOptions)
 	if *scope != graph.RedirectScopeSingle {
 		redirects, errGetRedirects := r.RedirectService.FindByProject(ctx, namespaceCode, projectCode)
 		if errGetRedirects != nil {
@@ -182,13 +332,23 @@ func (r *queryResolver) ProjectRedirectDraftCheck(ctx context.Context, namespace
 		}
 	}
 
+	header := http.Header{}
+	for _, h := range redirectCheck.Headers {
+		header.Add(h.Name, h.Value)
+	}
+
+	country := ""
+	if redirectCheck.Country != nil {
+		country = *redirectCheck.Country
+	}
+
 	redirectCheckResults := make([]graph.RedirectCheckResult, 0)
 	for _, urlTest := range redirectCheck.Urls {
 		u, errParse := url.Parse(urlTest)
 		if errParse != nil {
 			return nil, errParse
 		}
-		redirect, target := treeMatcher.Match(u.Host, u.RequestURI())
+		redirect, target := treeMatcher.MatchRequest(commonTypes.RedirectRequest{Host: u.Host, URI: u.RequestURI(), Header: header, Country: country})
 		redirectCheckResults = append(redirectCheckResults, graph.RedirectCheckResult{
 			URL:             urlTest,
 			RedirectMatched: redirect,
@@ -199,3 +359,22 @@ func (r *queryResolver) ProjectRedirectDraftCheck(ctx context.Context, namespace
 
 	return redirectCheckResults, nil
 }
+
+// ProjectRedirectRegexTest is the resolver for the projectRedirectRegexTest field.
+func (r *queryResolver) ProjectRedirectRegexTest(ctx context.Context, namespaceCode string, projectCode string, input types.RedirectRegexTestInput) (*types.RedirectRegexTestResult, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+
+	return r.RedirectDraftService.TestRegex(input)
+}
+
+// ImportJob returns graph.ImportJobResolver implementation.
+func (r *Resolver) ImportJob() graph.ImportJobResolver { return &importJobResolver{r} }
+
+// RedirectDraft returns graph.RedirectDraftResolver implementation.
+func (r *Resolver) RedirectDraft() graph.RedirectDraftResolver { return &redirectDraftResolver{r} }
+
+type importJobResolver struct{ *Resolver }
+type redirectDraftResolver struct{ *Resolver }
@@ -1,9 +1,15 @@
 package resolver
 
 import (
+	"context"
+
 	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/config"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/graph/loader"
+	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/service"
 )
 
@@ -15,6 +21,7 @@ import (
 type Resolver struct {
 	PermissionChecker       *auth.PermissionChecker
 	NamespaceService        service.NamespaceService
+	NamespacePolicyService  service.NamespacePolicyService
 	ProjectService          service.ProjectService
 	UserService             service.UserService
 	RoleService             service.RoleService
@@ -22,11 +29,28 @@ type Resolver struct {
 	RedirectService         service.RedirectService
 	RedirectDraftService    service.RedirectDraftService
 	RedirectImportService   service.RedirectImportService
+	RedirectHealthService   service.RedirectHealthService
+	PageLinkService         service.PageLinkService
+	HitService              service.HitService
 	PageService             service.PageService
 	PageDraftService        service.PageDraftService
+	PageTemplateService     service.PageTemplateService
 	AgentService            service.AgentService
+	AgentInstanceService    service.AgentInstanceService
 	ProjectDashboardService service.ProjectDashboardService
+	ProjectApplyService     service.ProjectApplyService
+	GitSyncService          service.GitSyncService
+	DraftLockService        service.DraftLockService
+	DraftBatchService       service.DraftBatchService
+	ChangesetService        service.ChangesetService
+	ImportProfileService    service.ImportProfileService
+	NotificationService     service.NotificationService
+	ProjectAPIKeyService    service.ProjectAPIKeyService
+	ProjectMemberService    service.ProjectMemberService
+	GroupService            service.GroupService
+	SearchService           service.SearchService
 	AgentConfig             config.AgentConfig
+	PasswordConfig          config.PasswordConfig
 }
 
 func strPtrOrNil(s string) *string {
@@ -36,6 +60,179 @@ func strPtrOrNil(s string) *string {
 	return &s
 }
 
+// checkDraftLock refuses the change of a draft locked by another user, a draftID of 0 checking the creation
+// of a draft. Users with the draft_locks admin permission on the namespace are not bound by the locks.
+func (r *Resolver) checkDraftLock(ctx context.Context, userCtx *auth.UserContext, namespaceCode, projectCode string, target model.DraftLockTarget, draftID int64) error {
+	if r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionDraftLocks, model.ActionWrite) {
+		return nil
+	}
+	return r.DraftLockService.CheckDraft(ctx, namespaceCode, projectCode, target, draftID, userCtx.Username)
+}
+
+// checkProjectDraftLocks refuses a change to all the drafts of a project while another user holds a lock in it,
+// see checkDraftLock
+func (r *Resolver) checkProjectDraftLocks(ctx context.Context, userCtx *auth.UserContext, namespaceCode, projectCode string) error {
+	if r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionDraftLocks, model.ActionWrite) {
+		return nil
+	}
+	return r.DraftLockService.CheckProject(ctx, namespaceCode, projectCode, userCtx.Username)
+}
+
+// checkPublish returns an error if the user may not publish the project: publishing requires the write permission
+// on the project and is refused to the project API keys, which only reach the drafts
+func (r *Resolver) checkPublish(userCtx *auth.UserContext, namespaceCode, projectCode string) error {
+	if !r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAny, model.ActionWrite) || userCtx.IsProjectAPIKey() {
+		return flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to publish project %s/%s", userCtx.Username, namespaceCode, projectCode)
+	}
+	return nil
+}
+
+// checkProjectMembersAdmin returns an error if the user may not manage the members of the project: it requires the
+// projects admin permission on the namespace, or to be the owner of the project
+func (r *Resolver) checkProjectMembersAdmin(ctx context.Context, userCtx *auth.UserContext, namespaceCode, projectCode string) error {
+	if r.PermissionChecker.CanAdminNamespace(userCtx.SubjectPermissions, namespaceCode, model.AdminSectionProjects, model.ActionWrite) {
+		return nil
+	}
+	if userCtx.UserID != 0 && !userCtx.IsProjectAPIKey() {
+		isOwner, err := r.ProjectMemberService.IsOwner(ctx, namespaceCode, projectCode, userCtx.UserID)
+		if err != nil {
+			return err
+		}
+		if isOwner {
+			return nil
+		}
+	}
+	return flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to manage the members of project %s/%s", userCtx.Username, namespaceCode, projectCode)
+}
+
+// projectCount returns a count of a project, batched with the other projects of the request by the
+// loaders of the queries, and counted alone by countFunc within the mutations
+func (r *Resolver) projectCount(ctx context.Context, project *model.Project, field func(model.ProjectCounts) int64, countFunc func(ctx context.Context, namespaceCode, projectCode string) (int64, error)) (int64, error) {
+	loaders := loader.For(ctx)
+	if loaders == nil {
+		return countFunc(ctx, project.NamespaceCode, project.ProjectCode)
+	}
+	counts, err := loaders.ProjectCounts.Load(ctx, loader.ProjectKey{NamespaceCode: project.NamespaceCode, ProjectCode: project.ProjectCode})
+	return field(counts), err
+}
+
+// changesetDescription returns the description of the input, empty when unset
+// projectPagination returns the pagination ordering the projects by the sorts first, the sorts on unknown columns being
+// ignored, then by the order of the pagination
+func projectPagination(pagination *commonTypes.PaginationInput, sorts []commonTypes.SortInput) *commonTypes.PaginationInput {
+	orderBy := make([]commonTypes.SortInput, 0, len(sorts)+len(pagination.GetOrderBy()))
+	for _, sort := range sorts {
+		if _, ok := model.ProjectSortableColumns[sort.Column]; !ok {
+			continue
+		}
+		if sort.Direction != commonTypes.SortDESC {
+			sort.Direction = commonTypes.SortASC
+		}
+		orderBy = append(orderBy, sort)
+	}
+	if len(orderBy) == 0 {
+		return pagination
+	}
+
+	withSorts := commonTypes.PaginationInput{}
+	if pagination != nil {
+		withSorts = *pagination
+	}
+	withSorts.OrderBy = append(orderBy, pagination.GetOrderBy()...)
+	return &withSorts
+}
+
+func changesetDescription(input graph.ChangesetInput) string {
+	if input.Description == nil {
+		return ""
+	}
+	return *input.Description
+}
+
+// toImportProfile returns the import profile of the input
+func toImportProfile(input graph.ImportProfileInput) *model.ImportProfile {
+	profile := &model.ImportProfile{
+		Name:          input.Name,
+		Columns:       input.Columns,
+		SkipHeader:    input.SkipHeader != nil && *input.SkipHeader,
+		DefaultType:   input.DefaultType,
+		DefaultStatus: input.DefaultStatus,
+		Overwrite:     input.Overwrite,
+	}
+	if input.Delimiter != nil {
+		profile.Delimiter = *input.Delimiter
+	}
+	return profile
+}
+
+// draftLockResourceType returns the resource whose write permission is required to lock the target
+func draftLockResourceType(target model.DraftLockTarget) model.ResourceType {
+	switch target {
+	case model.DraftLockTargetRedirectDraft:
+		return model.ResourceTypeRedirect
+	case model.DraftLockTargetPageDraft:
+		return model.ResourceTypePage
+	default:
+		return model.ResourceTypeAny
+	}
+}
+
+// draftLockID returns the draft id of a lock input, 0 for a project lock
+func draftLockID(input graph.DraftLockInput) int64 {
+	if input.DraftID == nil {
+		return 0
+	}
+	return *input.DraftID
+}
+
+// buildImportRedirectOptions returns the options of an import, the overwrite of the input taking precedence over
+// the one of its profile
+func (r *Resolver) buildImportRedirectOptions(ctx context.Context, namespaceCode, projectCode string, input *graph.ImportRedirectInput) (service.ImportRedirectOptions, error) {
+	opts := service.ImportRedirectOptions{
+		Overwrite: true, // Default to true
+	}
+	if input == nil {
+		return opts, nil
+	}
+	if input.ProfileID != nil {
+		profile, err := r.ImportProfileService.GetByID(ctx, namespaceCode, projectCode, *input.ProfileID)
+		if err != nil {
+			return opts, err
+		}
+		opts.Profile = profile
+		opts.Overwrite = profile.Overwrite
+	}
+	if input.Overwrite != nil {
+		opts.Overwrite = *input.Overwrite
+	}
+	opts.SourcePrefix = input.SourcePrefix
+	opts.TargetPrefix = input.TargetPrefix
+	return opts, nil
+}
+
+func toGraphImportRedirectResult(result *service.ImportRedirectResult) *graph.ImportRedirectResult {
+	graphErrors := make([]graph.ImportRedirectError, 0, len(result.Errors))
+	for _, e := range result.Errors {
+		graphErrors = append(graphErrors, graph.ImportRedirectError{
+			Line:    e.Line,
+			Source:  strPtrOrNil(e.Source),
+			Target:  strPtrOrNil(e.Target),
+			Reason:  convertErrorReason(e.Reason),
+			Message: e.Message,
+		})
+	}
+
+	return &graph.ImportRedirectResult{
+		Success:        result.Success,
+		TotalLines:     result.TotalLines,
+		ImportedCount:  result.ImportedCount,
+		SkippedCount:   result.SkippedCount,
+		ErrorCount:     result.ErrorCount,
+		RewrittenCount: result.RewrittenCount,
+		Errors:         graphErrors,
+	}
+}
+
 func convertErrorReason(reason service.ImportErrorReason) graph.ImportErrorReason {
 	switch reason {
 	case service.ImportErrorInvalidFormat:
@@ -54,6 +251,10 @@ func convertErrorReason(reason service.ImportErrorReason) graph.ImportErrorReaso
 		return graph.ImportErrorReasonSourceAlreadyExists
 	case service.ImportErrorDatabaseError:
 		return graph.ImportErrorReasonDatabaseError
+	case service.ImportErrorPolicyViolation:
+		return graph.ImportErrorReasonPolicyViolation
+	case service.ImportErrorDraftRefused:
+		return graph.ImportErrorReasonDraftRefused
 	default:
 		return graph.ImportErrorReasonInvalidFormat
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/flectolab/flecto-manager/auth"
 	"github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
 	"github.com/flectolab/flecto-manager/model"
 )
@@ -30,18 +31,18 @@ func (r *adminPermissionResolver) Action(ctx context.Context, obj *model.AdminPe
 func (r *mutationResolver) CreateRole(ctx context.Context, input graph.CreateRoleInput) (*model.Role, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
 	}
 
 	// Validate role code: only alphanumeric, underscore and hyphen allowed
 	if !model.ValidRoleNameRegex.MatchString(input.Code) {
-		return nil, fmt.Errorf("invalid role code: only alphanumeric characters, underscores and hyphens are allowed")
+		return nil, flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid role code: only alphanumeric characters, underscores and hyphens are allowed").WithField("code")
 	}
 
 	// Check if role already exists
 	existingRole, _ := r.RoleService.GetByCode(ctx, input.Code, model.RoleTypeRole)
 	if existingRole != nil {
-		return nil, fmt.Errorf("role %s already exists", input.Code)
+		return nil, flectoErrors.Newf(flectoErrors.CodeAlreadyExists, "role %s already exists", input.Code)
 	}
 
 	// Create the role
@@ -58,10 +59,11 @@ func (r *mutationResolver) CreateRole(ctx context.Context, input graph.CreateRol
 			Project:   permission.Project,
 			Resource:  model.ResourceType(permission.Resource),
 			Action:    model.ActionType(permission.Action),
+			Effect:    model.EffectOrDefault(permission.Effect),
 		})
 	}
 	for _, permission := range input.AdminPermissions {
-		subjectPermissions.Admin = append(subjectPermissions.Admin, model.AdminPermission{Section: model.SectionType(permission.Section), Action: model.ActionType(permission.Action)})
+		subjectPermissions.Admin = append(subjectPermissions.Admin, model.AdminPermission{Namespace: model.NamespaceOrAll(permission.Namespace), Section: model.SectionType(permission.Section), Action: model.ActionType(permission.Action), Effect: model.EffectOrDefault(permission.Effect)})
 	}
 
 	if len(subjectPermissions.Resources) > 0 || len(subjectPermissions.Admin) > 0 {
@@ -71,6 +73,13 @@ func (r *mutationResolver) CreateRole(ctx context.Context, input graph.CreateRol
 		}
 	}
 
+	if len(input.Parents) > 0 {
+		err = r.RoleService.UpdateRoleParents(ctx, role.ID, input.Parents)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	role.Resources = subjectPermissions.Resources
 	role.Admin = subjectPermissions.Admin
 	return role, nil
@@ -80,13 +89,13 @@ func (r *mutationResolver) CreateRole(ctx context.Context, input graph.CreateRol
 func (r *mutationResolver) UpdateRole(ctx context.Context, code string, input graph.UpdateRoleInput) (*model.Role, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
 	}
 
 	// Get existing role
 	role, err := r.RoleService.GetByCode(ctx, code, model.RoleTypeRole)
 	if err != nil {
-		return nil, fmt.Errorf("role %s not found", code)
+		return nil, flectoErrors.Newf(flectoErrors.CodeNotFound, "role %s not found", code)
 	}
 
 	// Update permissions
@@ -99,6 +108,7 @@ func (r *mutationResolver) UpdateRole(ctx context.Context, code string, input gr
 				Project:   permission.Project,
 				Resource:  model.ResourceType(permission.Resource),
 				Action:    model.ActionType(permission.Action),
+				Effect:    model.EffectOrDefault(permission.Effect),
 			},
 		)
 	}
@@ -106,8 +116,10 @@ func (r *mutationResolver) UpdateRole(ctx context.Context, code string, input gr
 		subjectPermissions.Admin = append(
 			subjectPermissions.Admin,
 			model.AdminPermission{
-				Section: model.SectionType(permission.Section),
-				Action:  model.ActionType(permission.Action),
+				Namespace: model.NamespaceOrAll(permission.Namespace),
+				Section:   model.SectionType(permission.Section),
+				Action:    model.ActionType(permission.Action),
+				Effect:    model.EffectOrDefault(permission.Effect),
 			},
 		)
 	}
@@ -117,6 +129,14 @@ func (r *mutationResolver) UpdateRole(ctx context.Context, code string, input gr
 		return nil, err
 	}
 
+	// Parents are left unchanged when not provided
+	if input.Parents != nil {
+		err = r.RoleService.UpdateRoleParents(ctx, role.ID, input.Parents)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Fetch updated role to get new timestamps and permissions
 	role, _ = r.RoleService.GetByCode(ctx, code, model.RoleTypeRole)
 	return role, nil
@@ -126,7 +146,7 @@ func (r *mutationResolver) UpdateRole(ctx context.Context, code string, input gr
 func (r *mutationResolver) DeleteRole(ctx context.Context, code string) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to delete %s", userCtx.Username, model.AdminSectionRoles)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to delete %s", userCtx.Username, model.AdminSectionRoles)
 	}
 
 	role, err := r.RoleService.GetByCode(ctx, code, model.RoleTypeRole)
@@ -141,7 +161,7 @@ func (r *mutationResolver) DeleteRole(ctx context.Context, code string) (bool, e
 func (r *mutationResolver) AddUserToRole(ctx context.Context, roleCode string, userID int64) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to modify %s", userCtx.Username, model.AdminSectionRoles)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to modify %s", userCtx.Username, model.AdminSectionRoles)
 	}
 
 	role, err := r.RoleService.GetByCode(ctx, roleCode, model.RoleTypeRole)
@@ -160,7 +180,7 @@ func (r *mutationResolver) AddUserToRole(ctx context.Context, roleCode string, u
 func (r *mutationResolver) RemoveUserFromRole(ctx context.Context, roleCode string, userID int64) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to modify %s", userCtx.Username, model.AdminSectionRoles)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to modify %s", userCtx.Username, model.AdminSectionRoles)
 	}
 
 	role, err := r.RoleService.GetByCode(ctx, roleCode, model.RoleTypeRole)
@@ -179,7 +199,7 @@ func (r *mutationResolver) RemoveUserFromRole(ctx context.Context, roleCode stri
 func (r *queryResolver) Roles(ctx context.Context) ([]model.Role, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
 	}
 	return r.RoleService.GetAllByType(ctx, model.RoleTypeRole)
 }
@@ -188,16 +208,16 @@ func (r *queryResolver) Roles(ctx context.Context) ([]model.Role, error) {
 func (r *queryResolver) Role(ctx context.Context, code string) (*model.Role, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
 	}
 	return r.RoleService.GetByCode(ctx, code, model.RoleTypeRole)
 }
 
 // SearchRoles is the resolver for the searchRoles field.
-func (r *queryResolver) SearchRoles(ctx context.Context, pagination *types.PaginationInput, filter graph.RoleFilter, sort []database.SortInput) (*types.PaginatedResult[model.Role], error) {
+func (r *queryResolver) SearchRoles(ctx context.Context, pagination *types.PaginationInput, filter graph.RoleFilter, sort []database.SortInput, where *database.FilterInput) (*types.PaginatedResult[model.Role], error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
 	}
 	query := r.RoleService.GetQuery(ctx).Where("type = ?", model.RoleTypeRole)
 
@@ -210,6 +230,11 @@ func (r *queryResolver) SearchRoles(ctx context.Context, pagination *types.Pagin
 		query = database.ApplySort(query, model.RoleSortableColumns, sort, "")
 	}
 
+	query, err := database.ApplyFilter(query, model.RoleSortableColumns, where, "")
+	if err != nil {
+		return nil, err
+	}
+
 	return r.RoleService.SearchPaginate(ctx, pagination, query)
 }
 
@@ -217,7 +242,7 @@ func (r *queryResolver) SearchRoles(ctx context.Context, pagination *types.Pagin
 func (r *queryResolver) RoleUsers(ctx context.Context, code string, pagination *types.PaginationInput, filter *graph.RoleUsersFilter, sort []database.SortInput) (*types.PaginatedResult[model.User], error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
 	}
 
 	search := ""
@@ -232,7 +257,7 @@ func (r *queryResolver) RoleUsers(ctx context.Context, code string, pagination *
 func (r *queryResolver) UsersNotInRole(ctx context.Context, code string, search string, limit *int) ([]model.User, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
 	}
 
 	l := 10
@@ -243,6 +268,69 @@ func (r *queryResolver) UsersNotInRole(ctx context.Context, code string, search
 	return r.RoleService.GetUsersNotInRole(ctx, code, search, l)
 }
 
+// ExplainPermission is the resolver for the explainPermission field.
+func (r *queryResolver) ExplainPermission(ctx context.Context, input graph.ExplainPermissionInput) (*graph.PermissionExplanation, error) {
+	userCtx := auth.GetUser(ctx)
+	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionRoles)
+	}
+
+	var permissions *model.SubjectPermissions
+	var err error
+	switch input.SubjectType {
+	case graph.PermissionSubjectTypeUser:
+		permissions, err = r.RoleService.GetPermissionsByUsername(ctx, input.Subject)
+	case graph.PermissionSubjectTypeRole:
+		permissions, err = r.RoleService.GetPermissionsByRoleCode(ctx, input.Subject)
+	case graph.PermissionSubjectTypeToken:
+		permissions, err = r.RoleService.GetPermissionsByTokenName(ctx, input.Subject)
+	default:
+		return nil, flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "invalid subject type: %s", input.SubjectType).WithField("subjectType")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := r.PermissionChecker.ExplainResource(
+		permissions,
+		input.Namespace,
+		input.Project,
+		model.ResourceType(input.Resource),
+		model.ActionType(input.Action),
+	)
+
+	// Resolve the code of the role each matching permission comes from, the permissions of the project
+	// memberships having no role
+	roleCodes := map[int64]string{0: "project-member"}
+	matches := make([]graph.PermissionMatch, 0, len(explanation.Denies)+len(explanation.Allows))
+	for _, p := range append(explanation.Denies, explanation.Allows...) {
+		code, ok := roleCodes[p.RoleID]
+		if !ok {
+			role, err := r.RoleService.GetByID(ctx, p.RoleID)
+			if err != nil {
+				return nil, err
+			}
+			code = role.Code
+			roleCodes[p.RoleID] = code
+		}
+		matches = append(matches, graph.PermissionMatch{
+			Role:      code,
+			Namespace: p.Namespace,
+			Project:   p.Project,
+			Resource:  string(p.Resource),
+			Action:    string(p.Action),
+			Effect:    model.EffectOrDefault(&p.Effect),
+		})
+	}
+
+	return &graph.PermissionExplanation{
+		Allowed:  explanation.Allowed(),
+		Decision: graph.PermissionDecision(explanation.Decision),
+		Reason:   explanation.Reason,
+		Matches:  matches,
+	}, nil
+}
+
 // Resource is the resolver for the resource field.
 func (r *resourcePermissionResolver) Resource(ctx context.Context, obj *model.ResourcePermission) (string, error) {
 	return string(obj.Resource), nil
@@ -258,6 +346,20 @@ func (r *roleResolver) Type(ctx context.Context, obj *model.Role) (string, error
 	return string(obj.Type), nil
 }
 
+// Parents is the resolver for the parents field.
+func (r *roleResolver) Parents(ctx context.Context, obj *model.Role) ([]string, error) {
+	parents, err := r.RoleService.GetParentRoles(ctx, obj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, len(parents))
+	for i, parent := range parents {
+		codes[i] = parent.Code
+	}
+	return codes, nil
+}
+
 // AdminPermission returns graph.AdminPermissionResolver implementation.
 func (r *Resolver) AdminPermission() graph.AdminPermissionResolver {
 	return &adminPermissionResolver{r}
@@ -0,0 +1,37 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.84
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// Search is the resolver for the search field.
+func (r *queryResolver) Search(ctx context.Context, namespaceCode string, query string, limit *int) ([]model.SearchResult, error) {
+	userCtx := auth.GetUser(ctx)
+	projects, err := r.ProjectService.GetByNamespace(ctx, namespaceCode)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := model.SearchScope{}
+	if limit != nil {
+		scope.Limit = *limit
+	}
+	for _, project := range projects {
+		if r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, project.ProjectCode, model.ResourceTypeRedirect, model.ActionRead) {
+			scope.RedirectProjectCodes = append(scope.RedirectProjectCodes, project.ProjectCode)
+		}
+		if r.PermissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, project.ProjectCode, model.ResourceTypePage, model.ActionRead) {
+			scope.PageProjectCodes = append(scope.PageProjectCodes, project.ProjectCode)
+		}
+	}
+
+	return r.SearchService.SearchAll(ctx, namespaceCode, query, scope)
+}
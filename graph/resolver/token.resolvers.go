@@ -12,6 +12,7 @@ import (
 	"github.com/flectolab/flecto-manager/auth"
 	"github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
 	"github.com/flectolab/flecto-manager/model"
 )
@@ -20,12 +21,12 @@ import (
 func (r *mutationResolver) CreateToken(ctx context.Context, input graph.CreateTokenInput) (*graph.TokenCreateResponse, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionTokens, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to manage %s", userCtx.Username, model.AdminSectionTokens)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to manage %s", userCtx.Username, model.AdminSectionTokens)
 	}
 
 	// Validate token name
 	if !model.ValidRoleNameRegex.MatchString(input.Name) {
-		return nil, fmt.Errorf("invalid token name: only alphanumeric characters, underscores and hyphens are allowed")
+		return nil, flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid token name: only alphanumeric characters, underscores and hyphens are allowed").WithField("name")
 	}
 
 	var expiresAt *string
@@ -44,12 +45,15 @@ func (r *mutationResolver) CreateToken(ctx context.Context, input graph.CreateTo
 				Project:   perm.Project,
 				Resource:  model.ResourceType(perm.Resource),
 				Action:    model.ActionType(perm.Action),
+				Effect:    model.EffectOrDefault(perm.Effect),
 			})
 		}
 		for _, perm := range input.AdminPermissions {
 			permissions.Admin = append(permissions.Admin, model.AdminPermission{
-				Section: model.SectionType(perm.Section),
-				Action:  model.ActionType(perm.Action),
+				Namespace: model.NamespaceOrAll(perm.Namespace),
+				Section:   model.SectionType(perm.Section),
+				Action:    model.ActionType(perm.Action),
+				Effect:    model.EffectOrDefault(perm.Effect),
 			})
 		}
 	}
@@ -76,13 +80,13 @@ func (r *mutationResolver) CreateToken(ctx context.Context, input graph.CreateTo
 func (r *mutationResolver) UpdateTokenPermissions(ctx context.Context, id int64, input graph.UpdateTokenPermissionsInput) (*graph.Token, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionTokens, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to manage %s", userCtx.Username, model.AdminSectionTokens)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to manage %s", userCtx.Username, model.AdminSectionTokens)
 	}
 
 	// Get the token to find its role
 	token, err := r.TokenService.GetByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("token not found")
+		return nil, flectoErrors.New(flectoErrors.CodeNotFound, "token not found")
 	}
 
 	// Get the role for this token
@@ -101,6 +105,7 @@ func (r *mutationResolver) UpdateTokenPermissions(ctx context.Context, id int64,
 				Project:   permission.Project,
 				Resource:  model.ResourceType(permission.Resource),
 				Action:    model.ActionType(permission.Action),
+				Effect:    model.EffectOrDefault(permission.Effect),
 			},
 		)
 	}
@@ -108,8 +113,10 @@ func (r *mutationResolver) UpdateTokenPermissions(ctx context.Context, id int64,
 		subjectPermissions.Admin = append(
 			subjectPermissions.Admin,
 			model.AdminPermission{
-				Section: model.SectionType(permission.Section),
-				Action:  model.ActionType(permission.Action),
+				Namespace: model.NamespaceOrAll(permission.Namespace),
+				Section:   model.SectionType(permission.Section),
+				Action:    model.ActionType(permission.Action),
+				Effect:    model.EffectOrDefault(permission.Effect),
 			},
 		)
 	}
@@ -137,7 +144,7 @@ func (r *mutationResolver) UpdateTokenPermissions(ctx context.Context, id int64,
 func (r *mutationResolver) DeleteToken(ctx context.Context, id int64) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionTokens, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to delete %s", userCtx.Username, model.AdminSectionTokens)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to delete %s", userCtx.Username, model.AdminSectionTokens)
 	}
 
 	return r.TokenService.Delete(ctx, id)
@@ -147,7 +154,7 @@ func (r *mutationResolver) DeleteToken(ctx context.Context, id int64) (bool, err
 func (r *queryResolver) Tokens(ctx context.Context) ([]graph.Token, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionTokens, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionTokens)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionTokens)
 	}
 
 	tokens, err := r.TokenService.GetAll(ctx)
@@ -173,7 +180,7 @@ func (r *queryResolver) Tokens(ctx context.Context) ([]graph.Token, error) {
 func (r *queryResolver) Token(ctx context.Context, id int64) (*graph.Token, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionTokens, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionTokens)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionTokens)
 	}
 
 	token, err := r.TokenService.GetByID(ctx, id)
@@ -195,10 +202,10 @@ func (r *queryResolver) Token(ctx context.Context, id int64) (*graph.Token, erro
 }
 
 // SearchTokens is the resolver for the searchTokens field.
-func (r *queryResolver) SearchTokens(ctx context.Context, pagination *types.PaginationInput, filter graph.TokenFilter, sort []database.SortInput) (*graph.TokenList, error) {
+func (r *queryResolver) SearchTokens(ctx context.Context, pagination *types.PaginationInput, filter graph.TokenFilter, sort []database.SortInput, where *database.FilterInput) (*graph.TokenList, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionTokens, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionTokens)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionTokens)
 	}
 
 	query := r.TokenService.GetQuery(ctx)
@@ -212,6 +219,11 @@ func (r *queryResolver) SearchTokens(ctx context.Context, pagination *types.Pagi
 		query = database.ApplySort(query, model.TokenSortableColumns, sort, "")
 	}
 
+	query, err := database.ApplyFilter(query, model.TokenSortableColumns, where, "")
+	if err != nil {
+		return nil, err
+	}
+
 	result, err := r.TokenService.SearchPaginate(ctx, pagination, query)
 	if err != nil {
 		return nil, err
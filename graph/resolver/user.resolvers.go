@@ -12,8 +12,10 @@ import (
 	"github.com/flectolab/flecto-manager/auth"
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/graph"
 	"github.com/flectolab/flecto-manager/hash"
+	"github.com/flectolab/flecto-manager/i18n"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/types"
 )
@@ -23,6 +25,17 @@ func (r *meResolver) Active(ctx context.Context, obj *model.User) (bool, error)
 	return obj.IsActive(), nil
 }
 
+// MustChangePassword is the resolver for the mustChangePassword field.
+func (r *meResolver) MustChangePassword(ctx context.Context, obj *model.User) (bool, error) {
+	return obj.NeedsPasswordChange(r.PasswordConfig.MaxAge), nil
+}
+
+// ImpersonatedBy is the resolver for the impersonatedBy field.
+func (r *meResolver) ImpersonatedBy(ctx context.Context, obj *model.User) (*string, error) {
+	userCtx := auth.GetUser(ctx)
+	return strPtrOrNil(userCtx.ImpersonatorUsername), nil
+}
+
 // Permissions is the resolver for the permissions field.
 func (r *meResolver) Permissions(ctx context.Context, obj *model.User) (*model.SubjectPermissions, error) {
 	userCtx := auth.GetUser(ctx)
@@ -38,19 +51,15 @@ func (r *meResolver) Permissions(ctx context.Context, obj *model.User) (*model.S
 func (r *mutationResolver) CreateUser(ctx context.Context, input graph.CreateUserInput) (*model.User, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
 	}
-	hashedPassword, err := hash.Password(input.Password)
-	if err != nil {
-		return nil, err
-	}
-
 	newUser := &model.User{
-		Username:  input.Username,
-		Password:  string(hashedPassword),
-		Firstname: input.Firstname,
-		Lastname:  input.Lastname,
-		Active:    types.Ptr(true),
+		Username:           input.Username,
+		Password:           input.Password,
+		Firstname:          input.Firstname,
+		Lastname:           input.Lastname,
+		Active:             types.Ptr(true),
+		MustChangePassword: input.MustChangePassword != nil && *input.MustChangePassword,
 	}
 
 	return r.UserService.Create(ctx, newUser)
@@ -60,7 +69,7 @@ func (r *mutationResolver) CreateUser(ctx context.Context, input graph.CreateUse
 func (r *mutationResolver) UpdateUser(ctx context.Context, id int64, input graph.UpdateUserInput) (*model.User, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
 	}
 
 	return r.UserService.Update(ctx, id, model.User{Firstname: input.Firstname, Lastname: input.Lastname})
@@ -70,7 +79,7 @@ func (r *mutationResolver) UpdateUser(ctx context.Context, id int64, input graph
 func (r *mutationResolver) UpdateUserPermissions(ctx context.Context, id int64, input graph.SubjectPermissionsInput) (*model.User, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
 	}
 
 	// Fetch user to return
@@ -90,13 +99,16 @@ func (r *mutationResolver) UpdateUserPermissions(ctx context.Context, id int64,
 			Project:   resource.Project,
 			Resource:  model.ResourceType(resource.Resource),
 			Action:    model.ActionType(resource.Action),
+			Effect:    model.EffectOrDefault(resource.Effect),
 		})
 	}
 
 	for _, a := range input.Admin {
 		subjectPermissions.Admin = append(subjectPermissions.Admin, model.AdminPermission{
-			Section: model.SectionType(a.Section),
-			Action:  model.ActionType(a.Action),
+			Namespace: model.NamespaceOrAll(a.Namespace),
+			Section:   model.SectionType(a.Section),
+			Action:    model.ActionType(a.Action),
+			Effect:    model.EffectOrDefault(a.Effect),
 		})
 	}
 
@@ -117,7 +129,7 @@ func (r *mutationResolver) UpdateUserPermissions(ctx context.Context, id int64,
 func (r *mutationResolver) UpdateUserStatus(ctx context.Context, id int64, input graph.UpdateUserStatusInput) (*model.User, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
 	}
 
 	return r.UserService.UpdateStatus(ctx, id, *input.Active)
@@ -127,10 +139,10 @@ func (r *mutationResolver) UpdateUserStatus(ctx context.Context, id int64, input
 func (r *mutationResolver) UpdateUserPassword(ctx context.Context, id int64, input graph.UpdateUserPasswordInput) (*model.User, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
 	}
 
-	err := r.UserService.UpdatePassword(ctx, id, input.NewPassword)
+	err := r.UserService.UpdatePassword(ctx, id, input.NewPassword, input.MustChangePassword != nil && *input.MustChangePassword)
 	if err != nil {
 		return nil, err
 	}
@@ -142,7 +154,7 @@ func (r *mutationResolver) UpdateUserPassword(ctx context.Context, id int64, inp
 func (r *mutationResolver) DeleteUser(ctx context.Context, id int64) (bool, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
-		return false, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
+		return false, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
 	}
 
 	return r.UserService.Delete(ctx, id)
@@ -152,11 +164,7 @@ func (r *mutationResolver) DeleteUser(ctx context.Context, id int64) (bool, erro
 func (r *mutationResolver) MeUpdatePassword(ctx context.Context, input graph.MeUpdatePasswordInput) (*model.User, error) {
 	userCtx := auth.GetUser(ctx)
 	if userCtx.AuthType != types.AuthTypeBasic {
-		return nil, fmt.Errorf("user must authenticated with basic auth")
-	}
-
-	if len(input.NewPassword) < 8 {
-		return nil, fmt.Errorf("new password must be at least 8 characters")
+		return nil, flectoErrors.New(flectoErrors.CodeForbidden, "user must authenticated with basic auth")
 	}
 
 	// Fetch the user to verify old password
@@ -167,15 +175,25 @@ func (r *mutationResolver) MeUpdatePassword(ctx context.Context, input graph.MeU
 
 	// Verify old password
 	if err = hash.CheckPassword(user.Password, input.OldPassword); err != nil {
-		return nil, fmt.Errorf("current password is incorrect")
+		return nil, flectoErrors.New(flectoErrors.CodeInvalidCredentials, "current password is incorrect").WithField("oldPassword")
 	}
 
-	err = r.UserService.UpdatePassword(ctx, userCtx.UserID, input.NewPassword)
+	err = r.UserService.UpdatePassword(ctx, userCtx.UserID, input.NewPassword, false)
 	if err != nil {
 		return nil, err
 	}
 
-	return user, nil
+	return r.UserService.GetByID(ctx, userCtx.UserID)
+}
+
+// MeUpdateLanguage is the resolver for the meUpdateLanguage field.
+func (r *mutationResolver) MeUpdateLanguage(ctx context.Context, input graph.MeUpdateLanguageInput) (*model.User, error) {
+	userCtx := auth.GetUser(ctx)
+	if userCtx.UserID == 0 {
+		return nil, flectoErrors.New(flectoErrors.CodeForbidden, "user must be authenticated as a user")
+	}
+
+	return r.UserService.UpdateLanguage(ctx, userCtx.UserID, input.Language)
 }
 
 // Me is the resolver for the me field.
@@ -188,17 +206,17 @@ func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
 func (r *queryResolver) Users(ctx context.Context, pagination *commonTypes.PaginationInput) (*commonTypes.PaginatedResult[model.User], error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
 	}
 
 	return r.UserService.SearchPaginate(ctx, pagination, nil)
 }
 
 // SearchUsers is the resolver for the searchUsers field.
-func (r *queryResolver) SearchUsers(ctx context.Context, pagination *commonTypes.PaginationInput, filter graph.UserFilter, sort []database.SortInput) (*commonTypes.PaginatedResult[model.User], error) {
+func (r *queryResolver) SearchUsers(ctx context.Context, pagination *commonTypes.PaginationInput, filter graph.UserFilter, sort []database.SortInput, where *database.FilterInput) (*commonTypes.PaginatedResult[model.User], error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
 	}
 
 	query := r.UserService.GetQuery(ctx)
@@ -216,6 +234,11 @@ func (r *queryResolver) SearchUsers(ctx context.Context, pagination *commonTypes
 		query = database.ApplySort(query, model.UserSortableColumns, sort, "")
 	}
 
+	query, err := database.ApplyFilter(query, model.UserSortableColumns, where, "")
+	if err != nil {
+		return nil, err
+	}
+
 	return r.UserService.SearchPaginate(ctx, pagination, query)
 }
 
@@ -223,11 +246,20 @@ func (r *queryResolver) SearchUsers(ctx context.Context, pagination *commonTypes
 func (r *queryResolver) User(ctx context.Context, username string) (*model.User, error) {
 	userCtx := auth.GetUser(ctx)
 	if !r.PermissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionRead) {
-		return nil, fmt.Errorf("user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
+		return nil, flectoErrors.Newf(flectoErrors.CodeForbidden, "user %s has no permission to access %s", userCtx.Username, model.AdminSectionUsers)
 	}
 	return r.UserService.GetByUsername(ctx, username)
 }
 
+// Languages is the resolver for the languages field.
+func (r *queryResolver) Languages(ctx context.Context) ([]string, error) {
+	languages := make([]string, 0, len(i18n.Languages))
+	for _, lang := range i18n.Languages {
+		languages = append(languages, string(lang))
+	}
+	return languages, nil
+}
+
 // Active is the resolver for the active field.
 func (r *userResolver) Active(ctx context.Context, obj *model.User) (bool, error) {
 	return obj.IsActive(), nil
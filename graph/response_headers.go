@@ -0,0 +1,34 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/99designs/gqlgen/graphql"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// MarshalResponseHeaders writes the headers of a page or a redirect as a JSON object of the values by name
+func MarshalResponseHeaders(headers commonTypes.ResponseHeaders) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_ = json.NewEncoder(w).Encode(headers)
+	})
+}
+
+// UnmarshalResponseHeaders reads the headers of a page or a redirect from an object of string values by name
+func UnmarshalResponseHeaders(v any) (commonTypes.ResponseHeaders, error) {
+	object, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("headers must be an object")
+	}
+	headers := make(commonTypes.ResponseHeaders, len(object))
+	for name, value := range object {
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("value of the header %s must be a string", name)
+		}
+		headers[name] = s
+	}
+	return headers, nil
+}
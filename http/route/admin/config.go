@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/flectolab/flecto-manager/auth"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/labstack/echo/v4"
+)
+
+// PostConfigReload reloads the runtime settings of the configuration like a SIGHUP signal, an invalid
+// configuration being refused with the reason and leaving the current one in place
+func PostConfigReload(ctx *appContext.Context, permissionChecker *auth.PermissionChecker) func(echo.Context) error {
+	return func(c echo.Context) error {
+		userCtx := auth.GetUser(c.Request().Context())
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionConfig, model.ActionWrite) {
+			return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeForbidden, "Reloading the configuration is not allowed"))
+		}
+
+		if err := ctx.ReloadConfig(); err != nil {
+			if errors.Is(err, appContext.ErrConfigReloadUnsupported) {
+				return route.ErrorJSON(c, http.StatusNotImplemented, flectoErrors.Wrap(flectoErrors.CodeUnsupported, err))
+			}
+			ctx.Logger.ErrorContext(c.Request().Context(), "failed to reload configuration", "username", userCtx.Username, "error", err)
+			return route.ErrorJSON(c, http.StatusBadRequest, flectoErrors.Wrap(flectoErrors.CodeInvalidRequest, err))
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
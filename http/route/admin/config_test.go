@@ -0,0 +1,77 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func servePostConfigReload(t *testing.T, ctx *appContext.Context, permissions *model.SubjectPermissions) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/admin/config/reload", nil)
+	req = req.WithContext(auth.SetUserContext(req.Context(), &auth.UserContext{Username: "admin", SubjectPermissions: permissions}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NoError(t, PostConfigReload(ctx, auth.NewPermissionChecker(nil))(c))
+	return rec
+}
+
+func TestPostConfigReload(t *testing.T) {
+	canReload := &model.SubjectPermissions{
+		Admin: []model.AdminPermission{{Section: model.AdminSectionConfig, Action: model.ActionWrite}},
+	}
+
+	t.Run("forbidden", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		ctx.ConfigLoader = func() (*config.Config, error) {
+			t.Fatal("the configuration must not be loaded")
+			return nil, nil
+		}
+
+		rec := servePostConfigReload(t, ctx, &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Section: model.AdminSectionConfig, Action: model.ActionRead}},
+		})
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		rec := servePostConfigReload(t, appContext.TestContext(nil), canReload)
+		assert.Equal(t, http.StatusNotImplemented, rec.Code)
+	})
+
+	t.Run("invalid configuration", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		ctx.ConfigLoader = func() (*config.Config, error) {
+			return nil, errors.New("unable to read the configuration file")
+		}
+
+		rec := servePostConfigReload(t, ctx, canReload)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unable to read the configuration file")
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		ctx.ConfigLoader = func() (*config.Config, error) {
+			cfg := config.DefaultConfig()
+			cfg.DB.Type = "sqlite"
+			cfg.Auth.JWT.Secret = "test-secret-key-32-bytes-long!!!"
+			cfg.Page.SizeLimit = 42
+			return cfg, nil
+		}
+
+		rec := servePostConfigReload(t, ctx, canReload)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, 42, ctx.CurrentConfig().Page.SizeLimit)
+	})
+}
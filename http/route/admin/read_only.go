@@ -0,0 +1,50 @@
+package admin
+
+import (
+	"net/http"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+// GetReadOnly returns the read-only mode of the manager
+func GetReadOnly(ctx *appContext.Context, permissionChecker *auth.PermissionChecker) func(echo.Context) error {
+	return func(c echo.Context) error {
+		userCtx := auth.GetUser(c.Request().Context())
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionConfig, model.ActionRead) {
+			return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeForbidden, "Reading the read-only mode is not allowed"))
+		}
+
+		readOnly := ctx.CurrentConfig().ReadOnly
+		return c.JSON(http.StatusOK, types.ReadOnlyMode{Enabled: readOnly.Enabled, Message: readOnly.Message})
+	}
+}
+
+// PutReadOnly switches the read-only mode of the manager until the next configuration reload, which takes the mode
+// of the configuration again. Only the replica serving the request is switched.
+func PutReadOnly(ctx *appContext.Context, permissionChecker *auth.PermissionChecker) func(echo.Context) error {
+	return func(c echo.Context) error {
+		userCtx := auth.GetUser(c.Request().Context())
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionConfig, model.ActionWrite) {
+			return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeForbidden, "Switching the read-only mode is not allowed"))
+		}
+
+		var req types.ReadOnlyMode
+		if err := c.Bind(&req); err != nil {
+			return route.ErrorJSON(c, http.StatusBadRequest, flectoErrors.New(flectoErrors.CodeInvalidRequest, "Invalid request body"))
+		}
+		if err := ctx.Validator.Struct(&req); err != nil {
+			return route.ErrorJSON(c, http.StatusBadRequest, err)
+		}
+
+		ctx.SetReadOnly(config.ReadOnlyConfig{Enabled: req.Enabled, Message: req.Message})
+		ctx.Logger.InfoContext(c.Request().Context(), "read-only mode switched", "username", userCtx.Username, "enabled", req.Enabled, "message", req.Message)
+		return c.JSON(http.StatusOK, req)
+	}
+}
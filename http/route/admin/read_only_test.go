@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveReadOnly(t *testing.T, ctx *appContext.Context, permissions *model.SubjectPermissions, method, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(method, "/admin/read-only", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(auth.SetUserContext(req.Context(), &auth.UserContext{Username: "admin", SubjectPermissions: permissions}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := GetReadOnly(ctx, auth.NewPermissionChecker(nil))
+	if method == http.MethodPut {
+		handler = PutReadOnly(ctx, auth.NewPermissionChecker(nil))
+	}
+	require.NoError(t, handler(c))
+	return rec
+}
+
+func TestGetReadOnly(t *testing.T) {
+	t.Run("forbidden", func(t *testing.T) {
+		rec := serveReadOnly(t, appContext.TestContext(nil), &model.SubjectPermissions{}, http.MethodGet, "")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		ctx.SetReadOnly(config.ReadOnlyConfig{Enabled: true, Message: "database migration"})
+
+		rec := serveReadOnly(t, ctx, &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Section: model.AdminSectionConfig, Action: model.ActionRead}},
+		}, http.MethodGet, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"enabled":true,"message":"database migration"}`, rec.Body.String())
+	})
+}
+
+func TestPutReadOnly(t *testing.T) {
+	canSwitch := &model.SubjectPermissions{
+		Admin: []model.AdminPermission{{Section: model.AdminSectionConfig, Action: model.ActionWrite}},
+	}
+
+	t.Run("forbidden", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		rec := serveReadOnly(t, ctx, &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Section: model.AdminSectionConfig, Action: model.ActionRead}},
+		}, http.MethodPut, `{"enabled":true}`)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.False(t, ctx.CurrentConfig().ReadOnly.Enabled)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		rec := serveReadOnly(t, appContext.TestContext(nil), canSwitch, http.MethodPut, `{"enabled":"yes"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("enable and disable", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+
+		rec := serveReadOnly(t, ctx, canSwitch, http.MethodPut, `{"enabled":true,"message":"database migration"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, config.ReadOnlyConfig{Enabled: true, Message: "database migration"}, ctx.CurrentConfig().ReadOnly)
+		assert.Error(t, ctx.ReadOnlyError())
+
+		rec = serveReadOnly(t, ctx, canSwitch, http.MethodPut, `{"enabled":false}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NoError(t, ctx.ReadOnlyError())
+	})
+}
@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/flectolab/flecto-manager/auth"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+// TaskNameKey is the path parameter of the name of a scheduled task
+const TaskNameKey = "name"
+
+// GetScheduledTasks returns the recurring tasks run by the replica serving the request, with their schedule and the
+// status of their runs
+func GetScheduledTasks(permissionChecker *auth.PermissionChecker, schedulerService service.SchedulerService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		userCtx := auth.GetUser(c.Request().Context())
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionConfig, model.ActionRead) {
+			return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeForbidden, "Reading the scheduled tasks is not allowed"))
+		}
+
+		return c.JSON(http.StatusOK, schedulerService.Tasks())
+	}
+}
+
+// PutScheduledTask changes the schedule or switches a recurring task until the next configuration reload, which takes
+// the scheduler configuration again. Only the replica serving the request is changed.
+func PutScheduledTask(ctx *appContext.Context, permissionChecker *auth.PermissionChecker, schedulerService service.SchedulerService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		userCtx := auth.GetUser(c.Request().Context())
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionConfig, model.ActionWrite) {
+			return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeForbidden, "Changing the scheduled tasks is not allowed"))
+		}
+
+		var req types.ScheduledTaskUpdate
+		if err := c.Bind(&req); err != nil {
+			return route.ErrorJSON(c, http.StatusBadRequest, flectoErrors.New(flectoErrors.CodeInvalidRequest, "Invalid request body"))
+		}
+		if err := ctx.Validator.Struct(&req); err != nil {
+			return route.ErrorJSON(c, http.StatusBadRequest, err)
+		}
+
+		task, err := schedulerService.UpdateTask(c.Param(TaskNameKey), req)
+		switch {
+		case errors.Is(err, service.ErrScheduledTaskNotFound):
+			return route.ErrorJSON(c, http.StatusNotFound, flectoErrors.New(flectoErrors.CodeNotFound, err.Error()))
+		case errors.Is(err, service.ErrScheduledTaskNoSchedule):
+			return route.ErrorJSON(c, http.StatusBadRequest, flectoErrors.New(flectoErrors.CodeInvalidRequest, err.Error()))
+		case err != nil:
+			return route.ErrorJSON(c, http.StatusInternalServerError, err)
+		}
+		ctx.Logger.InfoContext(c.Request().Context(), "scheduled task changed", "username", userCtx.Username, "task", task.Name, "schedule", task.Schedule, "enabled", task.Enabled)
+		return c.JSON(http.StatusOK, task)
+	}
+}
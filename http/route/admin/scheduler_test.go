@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/auth"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupScheduler(ctx *appContext.Context) service.SchedulerService {
+	return service.NewSchedulerService(ctx,
+		types.ScheduledTask{Name: "retention", Interval: time.Hour, Enabled: true, Run: func(ctx context.Context, now time.Time) error { return nil }},
+		types.ScheduledTask{Name: "git_sync", Run: func(ctx context.Context, now time.Time) error { return nil }},
+	)
+}
+
+func serveScheduledTasks(t *testing.T, ctx *appContext.Context, scheduler service.SchedulerService, permissions *model.SubjectPermissions, method, name, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(method, "/admin/scheduler/tasks", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(auth.SetUserContext(req.Context(), &auth.UserContext{Username: "admin", SubjectPermissions: permissions}))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := GetScheduledTasks(auth.NewPermissionChecker(nil), scheduler)
+	if method == http.MethodPut {
+		c.SetParamNames(TaskNameKey)
+		c.SetParamValues(name)
+		handler = PutScheduledTask(ctx, auth.NewPermissionChecker(nil), scheduler)
+	}
+	require.NoError(t, handler(c))
+	return rec
+}
+
+func TestGetScheduledTasks(t *testing.T) {
+	t.Run("forbidden", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		rec := serveScheduledTasks(t, ctx, setupScheduler(ctx), &model.SubjectPermissions{}, http.MethodGet, "", "")
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		rec := serveScheduledTasks(t, ctx, setupScheduler(ctx), &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Section: model.AdminSectionConfig, Action: model.ActionRead}},
+		}, http.MethodGet, "", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var tasks []types.ScheduledTaskStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &tasks))
+		require.Len(t, tasks, 2)
+		assert.Equal(t, "git_sync", tasks[0].Name)
+		assert.False(t, tasks[0].Enabled)
+		assert.Equal(t, "retention", tasks[1].Name)
+		assert.Equal(t, "@every 1h0m0s", tasks[1].Schedule)
+		assert.NotNil(t, tasks[1].NextRunAt)
+	})
+}
+
+func TestPutScheduledTask(t *testing.T) {
+	canChange := &model.SubjectPermissions{
+		Admin: []model.AdminPermission{{Section: model.AdminSectionConfig, Action: model.ActionWrite}},
+	}
+
+	t.Run("forbidden", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		rec := serveScheduledTasks(t, ctx, setupScheduler(ctx), &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Section: model.AdminSectionConfig, Action: model.ActionRead}},
+		}, http.MethodPut, "retention", `{"enabled":false}`)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		rec := serveScheduledTasks(t, ctx, setupScheduler(ctx), canChange, http.MethodPut, "retention", `{"schedule":"every day"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		rec := serveScheduledTasks(t, ctx, setupScheduler(ctx), canChange, http.MethodPut, "unknown", `{"enabled":false}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("no schedule", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		rec := serveScheduledTasks(t, ctx, setupScheduler(ctx), canChange, http.MethodPut, "git_sync", `{"enabled":true}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		ctx := appContext.TestContext(nil)
+		scheduler := setupScheduler(ctx)
+		rec := serveScheduledTasks(t, ctx, scheduler, canChange, http.MethodPut, "retention", `{"schedule":"30 2 * * *"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var task types.ScheduledTaskStatus
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &task))
+		assert.Equal(t, "30 2 * * *", task.Schedule)
+		assert.True(t, task.Overridden)
+		assert.Equal(t, "30 2 * * *", scheduler.Tasks()[1].Schedule)
+	})
+}
@@ -0,0 +1,148 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+// PostRegister registers the agent calling it, authenticated by an API token needing the agent write permission on
+// each project the agent serves. The token is the only one allowed to send the heartbeats of the agent afterwards.
+func PostRegister(permissionChecker *auth.PermissionChecker, agentInstanceService service.AgentInstanceService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if userCtx.AuthType != types.AuthTypeToken {
+			return echo.NewHTTPError(http.StatusForbidden, fmt.Errorf("agents register with an API token"))
+		}
+		registration := commonTypes.AgentRegistration{}
+		if err := c.Bind(&registration); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+		for _, project := range registration.Projects {
+			if !permissionChecker.CanResource(userCtx.SubjectPermissions, project.NamespaceCode, project.ProjectCode, model.ResourceTypeAgent, model.ActionWrite) {
+				return c.NoContent(http.StatusForbidden)
+			}
+		}
+
+		agent, err := agentInstanceService.Register(ctx, registration, userCtx.Username)
+		if err != nil {
+			return agentError(err)
+		}
+
+		return c.JSON(http.StatusOK, agent)
+	}
+}
+
+// PostHeartbeat records the heartbeat of a registered agent with the versions of the projects it applies,
+// the token needing the agent write permission on each of them
+func PostHeartbeat(permissionChecker *auth.PermissionChecker, agentInstanceService service.AgentInstanceService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		name := c.Param(route.NameKey)
+		if name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("name is required"))
+		}
+		heartbeat := commonTypes.AgentHeartbeat{}
+		if err := c.Bind(&heartbeat); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+		userCtx := auth.GetUser(ctx)
+		for _, project := range heartbeat.Projects {
+			if !permissionChecker.CanResource(userCtx.SubjectPermissions, project.NamespaceCode, project.ProjectCode, model.ResourceTypeAgent, model.ActionWrite) {
+				return c.NoContent(http.StatusForbidden)
+			}
+		}
+
+		agent, err := agentInstanceService.Heartbeat(ctx, name, heartbeat, userCtx.Username)
+		if err != nil {
+			return agentError(err)
+		}
+
+		return c.JSON(http.StatusOK, agent)
+	}
+}
+
+// PostAck records that a registered agent applied a version of a project, without waiting for its next heartbeat,
+// the token needing the agent write permission on the project
+func PostAck(permissionChecker *auth.PermissionChecker, agentInstanceService service.AgentInstanceService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		name := c.Param(route.NameKey)
+		if name == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("name is required"))
+		}
+		applied := commonTypes.AgentAppliedVersion{}
+		if err := c.Bind(&applied); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanResource(userCtx.SubjectPermissions, applied.NamespaceCode, applied.ProjectCode, model.ResourceTypeAgent, model.ActionWrite) {
+			return c.NoContent(http.StatusForbidden)
+		}
+
+		agent, err := agentInstanceService.Acknowledge(ctx, name, applied, userCtx.Username)
+		if err != nil {
+			return agentError(err)
+		}
+
+		return c.JSON(http.StatusOK, agent)
+	}
+}
+
+// GetAgents lists the registered agents with their state and the versions they serve, filtered by the
+// namespace, project, state and outdated query parameters
+func GetAgents(permissionChecker *auth.PermissionChecker, agentInstanceService service.AgentInstanceService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionAgents, model.ActionRead) {
+			return c.NoContent(http.StatusForbidden)
+		}
+
+		filter := types.AgentInstanceFilter{
+			NamespaceCode: c.QueryParam("namespace"),
+			ProjectCode:   c.QueryParam("project"),
+			State:         c.QueryParam("state"),
+		}
+		if value := c.QueryParam("outdated"); value != "" {
+			outdated, err := strconv.ParseBool(value)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid outdated: %s", value))
+			}
+			filter.Outdated = &outdated
+		}
+
+		agents, err := agentInstanceService.List(ctx, filter)
+		if err != nil {
+			return agentError(err)
+		}
+
+		return c.JSON(http.StatusOK, agents)
+	}
+}
+
+// agentError returns the response of a failed registry call
+func agentError(err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidAgentInstance), errors.Is(err, service.ErrInvalidAgentHeartbeat), errors.Is(err, service.ErrInvalidAgentInstanceFilter):
+		return echo.NewHTTPError(http.StatusBadRequest, err)
+	case errors.Is(err, service.ErrAgentInstanceNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	case errors.Is(err, service.ErrAgentInstanceNotOwned):
+		return echo.NewHTTPError(http.StatusForbidden, err)
+	case errors.Is(err, service.ErrAgentInstanceNameTaken):
+		return echo.NewHTTPError(http.StatusConflict, err)
+	default:
+		return echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+}
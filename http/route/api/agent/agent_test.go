@@ -0,0 +1,233 @@
+package agent
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/http/route"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newAgentContext(method, target, body string, userCtx *auth.UserContext) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+	return c, rec
+}
+
+func tokenUser(resources ...model.ResourcePermission) *auth.UserContext {
+	return &auth.UserContext{
+		Username:           "edge-token",
+		AuthType:           types.AuthTypeToken,
+		SubjectPermissions: &model.SubjectPermissions{Resources: resources},
+	}
+}
+
+func TestPostRegister(t *testing.T) {
+	permissionChecker := auth.NewPermissionChecker(nil)
+	agentWrite := model.ResourcePermission{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeAgent, Action: model.ActionWrite}
+	body := `{"name":"edge-1","type":"traefik","hostname":"host1","projects":[{"namespaceCode":"ns1","projectCode":"proj1"}]}`
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+		mockAgentInstanceService.EXPECT().
+			Register(gomock.Any(), commonTypes.AgentRegistration{Name: "edge-1", Type: commonTypes.AgentTypeTraefik, Hostname: "host1",
+				Projects: []commonTypes.AgentProject{{NamespaceCode: "ns1", ProjectCode: "proj1"}}}, "edge-token").
+			Return(&model.AgentInstance{Name: "edge-1", RegisteredBy: "edge-token", State: model.AgentInstanceStateOnline}, nil)
+
+		c, rec := newAgentContext(http.MethodPost, "/api/agents/register", body, tokenUser(agentWrite))
+		require.NoError(t, PostRegister(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"state":"ONLINE"`)
+	})
+
+	t.Run("forbidden without an API token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+		userCtx := tokenUser(agentWrite)
+		userCtx.AuthType = types.AuthTypeProjectAPIKey
+
+		c, _ := newAgentContext(http.MethodPost, "/api/agents/register", body, userCtx)
+		err := PostRegister(permissionChecker, mockAgentInstanceService)(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusForbidden, httpErr.Code)
+	})
+
+	t.Run("forbidden without the agent write permission on a project", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+
+		c, rec := newAgentContext(http.MethodPost, "/api/agents/register",
+			`{"name":"edge-1","type":"traefik","projects":[{"namespaceCode":"ns1","projectCode":"proj1"},{"namespaceCode":"ns2","projectCode":"proj1"}]}`,
+			tokenUser(agentWrite, model.ResourcePermission{Namespace: "ns2", Project: "*", Resource: model.ResourceTypeAgent, Action: model.ActionRead}))
+		require.NoError(t, PostRegister(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("name registered by another token", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+		mockAgentInstanceService.EXPECT().
+			Register(gomock.Any(), gomock.Any(), "edge-token").
+			Return(nil, fmt.Errorf("%w: edge-1", service.ErrAgentInstanceNameTaken))
+
+		c, _ := newAgentContext(http.MethodPost, "/api/agents/register", body, tokenUser(agentWrite))
+		err := PostRegister(permissionChecker, mockAgentInstanceService)(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusConflict, httpErr.Code)
+	})
+}
+
+func TestPostHeartbeat(t *testing.T) {
+	permissionChecker := auth.NewPermissionChecker(nil)
+	body := `{"projects":[{"namespaceCode":"ns1","projectCode":"proj1","version":3}]}`
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+		mockAgentInstanceService.EXPECT().
+			Heartbeat(gomock.Any(), "edge-1", commonTypes.AgentHeartbeat{Projects: []commonTypes.AgentAppliedVersion{{NamespaceCode: "ns1", ProjectCode: "proj1", Version: 3}}}, "edge-token").
+			Return(&model.AgentInstance{Name: "edge-1"}, nil)
+
+		c, rec := newAgentContext(http.MethodPost, "/api/agents/edge-1/heartbeat", body,
+			tokenUser(model.ResourcePermission{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeAgent, Action: model.ActionWrite}))
+		c.SetParamNames(route.NameKey)
+		c.SetParamValues("edge-1")
+		require.NoError(t, PostHeartbeat(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("forbidden without the agent permission on a project", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+
+		c, rec := newAgentContext(http.MethodPost, "/api/agents/edge-1/heartbeat", body,
+			tokenUser(model.ResourcePermission{Namespace: "ns2", Project: "*", Resource: model.ResourceTypeAgent, Action: model.ActionWrite}))
+		c.SetParamNames(route.NameKey)
+		c.SetParamValues("edge-1")
+		require.NoError(t, PostHeartbeat(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("agent not registered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+		mockAgentInstanceService.EXPECT().
+			Heartbeat(gomock.Any(), "edge-1", gomock.Any(), "edge-token").
+			Return(nil, fmt.Errorf("%w: edge-1", service.ErrAgentInstanceNotFound))
+
+		c, _ := newAgentContext(http.MethodPost, "/api/agents/edge-1/heartbeat", `{"projects":[]}`, tokenUser())
+		c.SetParamNames(route.NameKey)
+		c.SetParamValues("edge-1")
+		err := PostHeartbeat(permissionChecker, mockAgentInstanceService)(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+}
+
+func TestPostAck(t *testing.T) {
+	permissionChecker := auth.NewPermissionChecker(nil)
+	body := `{"namespaceCode":"ns1","projectCode":"proj1","environment":"production","version":4}`
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+		mockAgentInstanceService.EXPECT().
+			Acknowledge(gomock.Any(), "edge-1", commonTypes.AgentAppliedVersion{NamespaceCode: "ns1", ProjectCode: "proj1", Environment: commonTypes.EnvironmentProduction, Version: 4}, "edge-token").
+			Return(&model.AgentInstance{Name: "edge-1"}, nil)
+
+		c, rec := newAgentContext(http.MethodPost, "/api/agents/edge-1/ack", body,
+			tokenUser(model.ResourcePermission{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAgent, Action: model.ActionWrite}))
+		c.SetParamNames(route.NameKey)
+		c.SetParamValues("edge-1")
+		require.NoError(t, PostAck(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("forbidden without the agent permission on the project", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+
+		c, rec := newAgentContext(http.MethodPost, "/api/agents/edge-1/ack", body,
+			tokenUser(model.ResourcePermission{Namespace: "ns1", Project: "proj2", Resource: model.ResourceTypeAgent, Action: model.ActionWrite}))
+		c.SetParamNames(route.NameKey)
+		c.SetParamValues("edge-1")
+		require.NoError(t, PostAck(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestGetAgents(t *testing.T) {
+	permissionChecker := auth.NewPermissionChecker(nil)
+	adminUser := &auth.UserContext{
+		Username: "admin",
+		SubjectPermissions: &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Section: model.AdminSectionAgents, Action: model.ActionRead}},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+		outdated := true
+		mockAgentInstanceService.EXPECT().
+			List(gomock.Any(), types.AgentInstanceFilter{NamespaceCode: "ns1", State: "ONLINE", Outdated: &outdated}).
+			Return([]model.AgentInstance{{Name: "edge-1"}}, nil)
+
+		c, rec := newAgentContext(http.MethodGet, "/api/agents?namespace=ns1&state=ONLINE&outdated=true", "", adminUser)
+		require.NoError(t, GetAgents(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"name":"edge-1"`)
+	})
+
+	t.Run("invalid outdated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+
+		c, _ := newAgentContext(http.MethodGet, "/api/agents?outdated=maybe", "", adminUser)
+		err := GetAgents(permissionChecker, mockAgentInstanceService)(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("forbidden without the agents admin permission", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+
+		c, rec := newAgentContext(http.MethodGet, "/api/agents", "", tokenUser())
+		require.NoError(t, GetAgents(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
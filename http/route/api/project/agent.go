@@ -33,6 +33,9 @@ func PostAgent(permissionChecker *auth.PermissionChecker, agentService service.A
 		if errValidate := commonTypes.ValidateAgent(agentBase); errValidate != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, errValidate)
 		}
+		if agentBase.Environment == "" {
+			agentBase.Environment = commonTypes.EnvironmentStaging
+		}
 		err = agentService.Upsert(ctx, &model.Agent{NamespaceCode: namespaceCode, ProjectCode: projectCode, Agent: agentBase})
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, err)
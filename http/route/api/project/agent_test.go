@@ -1,6 +1,7 @@
 package project
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/http/route"
 	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
 	"github.com/flectolab/flecto-manager/model"
@@ -446,3 +448,53 @@ func TestPatchAgentHit(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
 	})
 }
+
+func TestPostAgent_Environment(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want commonTypes.Environment
+	}{
+		{name: "defaults to staging", body: `{"name":"test-agent","status":"success","type":"default","version":1}`, want: commonTypes.EnvironmentStaging},
+		{name: "production", body: `{"name":"test-agent","status":"success","type":"default","version":1,"environment":"production"}`, want: commonTypes.EnvironmentProduction},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAgentService := mockFlectoService.NewMockAgentService(ctrl)
+			permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+			mockAgentService.EXPECT().
+				Upsert(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, agent *model.Agent) error {
+					assert.Equal(t, tt.want, agent.Environment)
+					return nil
+				})
+
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodPost, "/api/projects/ns1/proj1/agents", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+			c.SetParamValues("ns1", "proj1")
+			userCtx := &auth.UserContext{
+				UserID:   1,
+				Username: "testuser",
+				SubjectPermissions: &model.SubjectPermissions{
+					Resources: []model.ResourcePermission{
+						{Namespace: "*", Project: "*", Resource: model.ResourceTypeAgent, Action: model.ActionWrite},
+					},
+				},
+			}
+			c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+
+			err := PostAgent(permissionChecker, mockAgentService)(c)
+
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/bundle"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetBundle exports the published pages and redirects of a project as an archive deployable to a
+// static host, at the version given by the version query parameter, the published version by default
+func GetBundle(permissionChecker *auth.PermissionChecker, projectService service.ProjectService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		namespaceCode := c.Param(route.NamespaceCodeKey)
+		projectCode := c.Param(route.ProjectCodeKey)
+		if namespaceCode == "" || projectCode == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("namespaceCode and projectCode are required"))
+		}
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) ||
+			!permissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypePage, model.ActionRead) {
+			return c.NoContent(http.StatusForbidden)
+		}
+
+		opts := bundle.Options{Format: bundle.FormatTarGz, RedirectFormat: bundle.RedirectFormatJSON}
+		if format := c.QueryParam("format"); format != "" {
+			opts.Format = bundle.Format(format)
+		}
+		if redirectFormat := c.QueryParam("redirects"); redirectFormat != "" {
+			opts.RedirectFormat = bundle.RedirectFormat(redirectFormat)
+		}
+		if !opts.Format.IsValid() {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid format: %s", opts.Format))
+		}
+		if !opts.RedirectFormat.IsValid() {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid redirects format: %s", opts.RedirectFormat))
+		}
+		version, err := getExportVersion(c)
+		if err != nil {
+			return err
+		}
+
+		result, err := projectService.ExportBundle(ctx, namespaceCode, projectCode, version)
+		if err != nil {
+			return exportError(err)
+		}
+
+		contentType := "application/gzip"
+		if opts.Format == bundle.FormatZip {
+			contentType = "application/zip"
+		}
+		c.Response().Header().Set(echo.HeaderContentType, contentType)
+		c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": result.FileName(opts.Format)}))
+		c.Response().WriteHeader(http.StatusOK)
+		return result.Write(c.Response(), opts)
+	}
+}
+
+// getExportVersion returns the version requested by the version query parameter, 0 for the published version
+func getExportVersion(c echo.Context) (int, error) {
+	value := c.QueryParam("version")
+	if value == "" {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid version: %s", value))
+	}
+	return version, nil
+}
+
+// exportError returns the response of a failed export, unknown projects and versions being not found
+func exportError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, service.ErrBundleVersionNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, err)
+	}
+	return echo.NewHTTPError(http.StatusInternalServerError, err)
+}
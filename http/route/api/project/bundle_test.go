@@ -0,0 +1,129 @@
+package project
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/bundle"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/http/route"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newBundleContext(target string, resource model.ResourceType) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+	c.SetParamValues("ns1", "proj1")
+	userCtx := &auth.UserContext{
+		UserID:   1,
+		Username: "testuser",
+		SubjectPermissions: &model.SubjectPermissions{
+			Resources: []model.ResourcePermission{
+				{Namespace: "*", Project: "*", Resource: resource, Action: model.ActionRead},
+			},
+		},
+	}
+	c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+	return c, rec
+}
+
+func TestGetBundle(t *testing.T) {
+	permissionChecker := auth.NewPermissionChecker(nil)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			ExportBundle(gomock.Any(), "ns1", "proj1", 2).
+			Return(&bundle.Bundle{
+				NamespaceCode: "ns1",
+				ProjectCode:   "proj1",
+				Version:       2,
+				Pages: []commonTypes.Page{
+					{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "User-agent: *", ContentType: commonTypes.PageContentTypeTextPlain},
+				},
+			}, nil)
+
+		c, rec := newBundleContext("/api/namespace/ns1/project/proj1/bundle?format=zip&redirects=caddy&version=2", model.ResourceTypeAll)
+		require.NoError(t, GetBundle(permissionChecker, mockProjectService)(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/zip", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "attachment; filename=ns1-proj1-v2.zip", rec.Header().Get(echo.HeaderContentDisposition))
+		zipReader, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+		require.NoError(t, err)
+		var names []string
+		for _, file := range zipReader.File {
+			names = append(names, file.Name)
+		}
+		assert.Equal(t, []string{"pages/robots.txt", bundle.PagesFile, "redirects.caddy"}, names)
+	})
+
+	t.Run("default formats", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			ExportBundle(gomock.Any(), "ns1", "proj1", 0).
+			Return(&bundle.Bundle{NamespaceCode: "ns1", ProjectCode: "proj1", Version: 5}, nil)
+
+		c, rec := newBundleContext("/api/namespace/ns1/project/proj1/bundle", model.ResourceTypeAll)
+		require.NoError(t, GetBundle(permissionChecker, mockProjectService)(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/gzip", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "attachment; filename=ns1-proj1-v5.tar.gz", rec.Header().Get(echo.HeaderContentDisposition))
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		for _, query := range []string{"format=rar", "redirects=iis", "version=abc", "version=-1"} {
+			ctrl := gomock.NewController(t)
+			mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+
+			c, _ := newBundleContext("/api/namespace/ns1/project/proj1/bundle?"+query, model.ResourceTypeAll)
+			err := GetBundle(permissionChecker, mockProjectService)(c)
+
+			var httpErr *echo.HTTPError
+			require.ErrorAs(t, err, &httpErr, query)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code, query)
+		}
+	})
+
+	t.Run("version not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			ExportBundle(gomock.Any(), "ns1", "proj1", 7).
+			Return(nil, fmt.Errorf("%w: version 7 of project ns1/proj1", service.ErrBundleVersionNotFound))
+
+		c, _ := newBundleContext("/api/namespace/ns1/project/proj1/bundle?version=7", model.ResourceTypeAll)
+		err := GetBundle(permissionChecker, mockProjectService)(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+
+	t.Run("forbidden without the page permission", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+
+		c, rec := newBundleContext("/api/namespace/ns1/project/proj1/bundle", model.ResourceTypeRedirect)
+		require.NoError(t, GetBundle(permissionChecker, mockProjectService)(c))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
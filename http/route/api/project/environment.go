@@ -0,0 +1,72 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// getEnvironment returns the environment requested by the agent, staging when it is not given
+func getEnvironment(c echo.Context) (commonTypes.Environment, error) {
+	environment := commonTypes.Environment(c.QueryParam(route.EnvironmentKey))
+	if environment == "" {
+		return commonTypes.EnvironmentStaging, nil
+	}
+	if !environment.IsValid() {
+		return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid environment: %s", environment))
+	}
+	return environment, nil
+}
+
+// getPromotedEnvironment returns the snapshot of an environment fed by promotion
+func getPromotedEnvironment(c echo.Context, projectService service.ProjectService, namespaceCode, projectCode string, environment commonTypes.Environment) (*model.ProjectEnvironment, error) {
+	projectEnvironment, err := projectService.GetEnvironment(c.Request().Context(), namespaceCode, projectCode, environment)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Errorf("project %s/%s has not been promoted to %s", namespaceCode, projectCode, environment))
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return projectEnvironment, nil
+}
+
+// paginateSnapshot returns the page of a snapshot matching the pagination
+func paginateSnapshot[T any](items []T, pagination *commonTypes.PaginationInput) []T {
+	offset := min(max(pagination.GetOffset(), 0), len(items))
+	end := len(items)
+	if limit := pagination.GetLimit(); limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	return items[offset:end]
+}
+
+// getPromotedVersion returns the environment fed by promotion without the content of its snapshot
+func getPromotedVersion(c echo.Context, projectService service.ProjectService, namespaceCode, projectCode string, environment commonTypes.Environment) (*model.ProjectEnvironment, error) {
+	projectEnvironments, err := projectService.GetEnvironments(c.Request().Context(), namespaceCode, projectCode)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	for _, projectEnvironment := range projectEnvironments {
+		if projectEnvironment.Environment == environment {
+			return &projectEnvironment, nil
+		}
+	}
+	return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Errorf("project %s/%s has not been promoted to %s", namespaceCode, projectCode, environment))
+}
+
+// isCurrentVersion tells whether the project is still at the version it had when the request started, the content
+// read meanwhile belonging to it
+func isCurrentVersion(c echo.Context, projectService service.ProjectService, project *model.Project) (bool, error) {
+	current, err := projectService.GetByCode(c.Request().Context(), project.NamespaceCode, project.ProjectCode)
+	if err != nil {
+		return false, echo.NewHTTPError(http.StatusInternalServerError, err)
+	}
+	return current.Version == project.Version, nil
+}
@@ -0,0 +1,48 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+)
+
+func PostHits(permissionChecker *auth.PermissionChecker, hitService service.HitService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		namespaceCode := c.Param(route.NamespaceCodeKey)
+		projectCode := c.Param(route.ProjectCodeKey)
+		if namespaceCode == "" || projectCode == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("namespaceCode and projectCode are required"))
+		}
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAgent, model.ActionWrite) {
+			return c.NoContent(http.StatusForbidden)
+		}
+		batch := commonTypes.HitBatch{}
+		err := c.Bind(&batch)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+
+		if len(batch.Hits) > service.MaxHitBatchSize {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("a batch can not contain more than %d hits", service.MaxHitBatchSize))
+		}
+		for _, hit := range batch.Hits {
+			if errValidate := commonTypes.ValidateHit(hit); errValidate != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, errValidate)
+			}
+		}
+		err = hitService.Ingest(ctx, namespaceCode, projectCode, batch.Hits)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
@@ -0,0 +1,169 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/http/route"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newHitsContext(body string, resource model.ResourceType) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/projects/ns1/proj1/hits", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+	c.SetParamValues("ns1", "proj1")
+
+	userCtx := &auth.UserContext{
+		UserID:   1,
+		Username: "testuser",
+		SubjectPermissions: &model.SubjectPermissions{
+			Resources: []model.ResourcePermission{
+				{Namespace: "*", Project: "*", Resource: resource, Action: model.ActionWrite},
+			},
+		},
+	}
+	c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+	return c, rec
+}
+
+func TestPostHits(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockHitService := mockFlectoService.NewMockHitService(ctrl)
+		permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+		mockHitService.EXPECT().
+			Ingest(gomock.Any(), "ns1", "proj1", []commonTypes.Hit{
+				{Type: commonTypes.HitResourceTypeRedirect, ID: 1, Count: 5, Date: "2026-03-01"},
+				{Type: commonTypes.HitResourceTypePage, ID: 2, Count: 1},
+			}).
+			Return(nil)
+
+		c, rec := newHitsContext(`{"hits":[{"type":"redirect","id":1,"count":5,"date":"2026-03-01"},{"type":"page","id":2,"count":1}]}`, model.ResourceTypeAgent)
+		err := PostHits(permissionChecker, mockHitService)(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("missing project code", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockHitService := mockFlectoService.NewMockHitService(ctrl)
+		permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+		c, _ := newHitsContext(`{"hits":[]}`, model.ResourceTypeAgent)
+		c.SetParamValues("ns1", "")
+		err := PostHits(permissionChecker, mockHitService)(c)
+
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockHitService := mockFlectoService.NewMockHitService(ctrl)
+		permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+		c, rec := newHitsContext(`{"hits":[]}`, model.ResourceTypeRedirect)
+		err := PostHits(permissionChecker, mockHitService)(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("invalid json body", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockHitService := mockFlectoService.NewMockHitService(ctrl)
+		permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+		c, _ := newHitsContext(`{"hits":`, model.ResourceTypeAgent)
+		err := PostHits(permissionChecker, mockHitService)(c)
+
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("validation error - invalid type", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockHitService := mockFlectoService.NewMockHitService(ctrl)
+		permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+		c, _ := newHitsContext(`{"hits":[{"type":"agent","id":1,"count":1}]}`, model.ResourceTypeAgent)
+		err := PostHits(permissionChecker, mockHitService)(c)
+
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("batch too large", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockHitService := mockFlectoService.NewMockHitService(ctrl)
+		permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+		hits := make([]string, service.MaxHitBatchSize+1)
+		for i := range hits {
+			hits[i] = fmt.Sprintf(`{"type":"redirect","id":%d,"count":1}`, i+1)
+		}
+		c, _ := newHitsContext(`{"hits":[`+strings.Join(hits, ",")+`]}`, model.ResourceTypeAgent)
+		err := PostHits(permissionChecker, mockHitService)(c)
+
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockHitService := mockFlectoService.NewMockHitService(ctrl)
+		permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+		mockHitService.EXPECT().
+			Ingest(gomock.Any(), "ns1", "proj1", gomock.Any()).
+			Return(errors.New("database error"))
+
+		c, _ := newHitsContext(`{"hits":[{"type":"redirect","id":1,"count":1}]}`, model.ResourceTypeAgent)
+		err := PostHits(permissionChecker, mockHitService)(c)
+
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
+	})
+}
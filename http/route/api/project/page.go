@@ -1,6 +1,7 @@
 package project
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -13,7 +14,7 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func GetPages(permissionChecker *auth.PermissionChecker, pageService service.PageService) func(echo.Context) error {
+func GetPages(permissionChecker *auth.PermissionChecker, pageService service.PageService, projectService service.ProjectService, snapshotCache service.SnapshotCache) func(echo.Context) error {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 		namespaceCode := c.Param(route.NamespaceCodeKey)
@@ -30,20 +31,110 @@ func GetPages(permissionChecker *auth.PermissionChecker, pageService service.Pag
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err)
 		}
-		pagesDB, total, err := pageService.FindByProjectPublished(ctx, namespaceCode, projectCode, pagination)
+		environment, err := getEnvironment(c)
+		if err != nil {
+			return err
+		}
+		// The cache durations are not published, they apply to all the environments
+		project, err := projectService.GetByCode(ctx, namespaceCode, projectCode)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, err)
 		}
-		pages := make([]commonTypes.Page, 0)
-		for _, page := range pagesDB {
-			pages = append(pages, *page.Page)
+		key := service.SnapshotCacheKey{
+			NamespaceCode: namespaceCode,
+			ProjectCode:   projectCode,
+			Environment:   environment,
+			Resource:      model.ResourceTypePage,
+			Version:       project.Version,
+			Limit:         pagination.GetLimit(),
+			Offset:        pagination.GetOffset(),
 		}
-		pageList := &commonTypes.PageList{
-			Total:  int(total),
-			Offset: pagination.GetOffset(),
-			Limit:  pagination.GetLimit(),
-			Items:  pages,
+		var page *service.SnapshotPage
+		if environment == commonTypes.EnvironmentProduction {
+			// The content of the snapshot is only loaded when the page is not cached
+			promoted, errVersion := getPromotedVersion(c, projectService, namespaceCode, projectCode, environment)
+			if errVersion != nil {
+				return errVersion
+			}
+			if acceptsSnapshotStream(c) {
+				projectEnvironment, errEnvironment := getPromotedEnvironment(c, projectService, namespaceCode, projectCode, environment)
+				if errEnvironment != nil {
+					return errEnvironment
+				}
+				return streamSnapshot(c, commonTypes.SnapshotHeader{Version: projectEnvironment.Version, CacheTTL: project.CacheTTL}, projectEnvironment.Pages)
+			}
+			key.Version = promoted.Version
+			builtVersion := 0
+			page, err = snapshotCache.Get(key, func() (*service.SnapshotPage, error) {
+				projectEnvironment, errEnvironment := getPromotedEnvironment(c, projectService, namespaceCode, projectCode, environment)
+				if errEnvironment != nil {
+					return nil, errEnvironment
+				}
+				builtVersion = projectEnvironment.Version
+				items, errMarshal := json.Marshal(paginateSnapshot(projectEnvironment.Pages, pagination))
+				if errMarshal != nil {
+					return nil, errMarshal
+				}
+				return &service.SnapshotPage{Items: items, Total: len(projectEnvironment.Pages)}, nil
+			}, func() (bool, error) {
+				return builtVersion == key.Version, nil
+			})
+		} else {
+			if acceptsSnapshotStream(c) {
+				return streamPages(c, pageService, project)
+			}
+			page, err = snapshotCache.Get(key, func() (*service.SnapshotPage, error) {
+				pagesDB, total, errFind := pageService.FindByProjectPublished(ctx, namespaceCode, projectCode, pagination)
+				if errFind != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, errFind)
+				}
+				pages := make([]commonTypes.Page, 0)
+				for _, page := range pagesDB {
+					pages = append(pages, page.Base())
+				}
+				items, errMarshal := json.Marshal(pages)
+				if errMarshal != nil {
+					return nil, errMarshal
+				}
+				return &service.SnapshotPage{Items: items, Total: int(total)}, nil
+			}, func() (bool, error) {
+				return isCurrentVersion(c, projectService, project)
+			})
 		}
-		return c.JSON(http.StatusOK, pageList)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, &cachedPageList{
+			Items:    page.Items,
+			Total:    page.Total,
+			Limit:    pagination.GetLimit(),
+			Offset:   pagination.GetOffset(),
+			CacheTTL: project.CacheTTL,
+		})
 	}
 }
+
+// cachedPageList is the commonTypes.PageList answered from a cached page of pages
+type cachedPageList struct {
+	Items    json.RawMessage
+	Total    int
+	Limit    int
+	Offset   int
+	CacheTTL commonTypes.CacheTTL
+}
+
+// streamPages streams the published pages of a project at its current version
+func streamPages(c echo.Context, pageService service.PageService, project *model.Project) error {
+	var stream *snapshotStream
+	return pageService.StreamByProjectPublished(c.Request().Context(), project.NamespaceCode, project.ProjectCode, project.Version, func(total int64) error {
+		var err error
+		stream, err = startSnapshotStream(c, commonTypes.SnapshotHeader{Version: project.Version, Total: int(total), CacheTTL: project.CacheTTL})
+		return err
+	}, func(pagesDB []model.Page) error {
+		pages := make([]commonTypes.Page, 0, len(pagesDB))
+		for _, page := range pagesDB {
+			pages = append(pages, page.Base())
+		}
+		return writeSnapshotItems(stream, pages)
+	})
+}
@@ -59,7 +59,12 @@ func TestGetPages(t *testing.T) {
 		ctx := auth.SetUserContext(req.Context(), userCtx)
 		c.SetRequest(req.WithContext(ctx))
 
-		handler := GetPages(permissionChecker, mockPageService)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			GetByCode(gomock.Any(), "ns1", "proj1").
+			Return(&model.Project{CacheTTL: commonTypes.CacheTTL{Pages: 3600, Redirects: 60}}, nil)
+
+		handler := GetPages(permissionChecker, mockPageService, mockProjectService, disabledSnapshotCache())
 		err := handler(c)
 
 		require.NoError(t, err)
@@ -67,6 +72,7 @@ func TestGetPages(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), `"Total":1`)
 		assert.Contains(t, rec.Body.String(), `"/index.html"`)
 		assert.Contains(t, rec.Body.String(), `"TEXT_PLAIN"`)
+		assert.Contains(t, rec.Body.String(), `"CacheTTL":{"pages":3600,"redirects":60}`)
 	})
 
 	t.Run("success empty list", func(t *testing.T) {
@@ -100,7 +106,9 @@ func TestGetPages(t *testing.T) {
 		ctx := auth.SetUserContext(req.Context(), userCtx)
 		c.SetRequest(req.WithContext(ctx))
 
-		handler := GetPages(permissionChecker, mockPageService)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(&model.Project{}, nil)
+		handler := GetPages(permissionChecker, mockPageService, mockProjectService, disabledSnapshotCache())
 		err := handler(c)
 
 		require.NoError(t, err)
@@ -123,7 +131,7 @@ func TestGetPages(t *testing.T) {
 		c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
 		c.SetParamValues("", "proj1")
 
-		handler := GetPages(permissionChecker, mockPageService)
+		handler := GetPages(permissionChecker, mockPageService, mockFlectoService.NewMockProjectService(ctrl), disabledSnapshotCache())
 		err := handler(c)
 
 		require.Error(t, err)
@@ -147,7 +155,7 @@ func TestGetPages(t *testing.T) {
 		c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
 		c.SetParamValues("ns1", "")
 
-		handler := GetPages(permissionChecker, mockPageService)
+		handler := GetPages(permissionChecker, mockPageService, mockFlectoService.NewMockProjectService(ctrl), disabledSnapshotCache())
 		err := handler(c)
 
 		require.Error(t, err)
@@ -180,7 +188,7 @@ func TestGetPages(t *testing.T) {
 		ctx := auth.SetUserContext(req.Context(), userCtx)
 		c.SetRequest(req.WithContext(ctx))
 
-		handler := GetPages(permissionChecker, mockPageService)
+		handler := GetPages(permissionChecker, mockPageService, mockFlectoService.NewMockProjectService(ctrl), disabledSnapshotCache())
 		err := handler(c)
 
 		require.NoError(t, err)
@@ -218,7 +226,9 @@ func TestGetPages(t *testing.T) {
 		ctx := auth.SetUserContext(req.Context(), userCtx)
 		c.SetRequest(req.WithContext(ctx))
 
-		handler := GetPages(permissionChecker, mockPageService)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(&model.Project{}, nil)
+		handler := GetPages(permissionChecker, mockPageService, mockProjectService, disabledSnapshotCache())
 		err := handler(c)
 
 		require.Error(t, err)
@@ -227,3 +237,50 @@ func TestGetPages(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
 	})
 }
+
+func TestGetPages_Environment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+	permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+	mockProjectService.EXPECT().
+		GetByCode(gomock.Any(), "ns1", "proj1").
+		Return(&model.Project{CacheTTL: commonTypes.CacheTTL{Pages: 600}}, nil)
+	mockProjectService.EXPECT().
+		GetEnvironments(gomock.Any(), "ns1", "proj1").
+		Return([]model.ProjectEnvironment{{Environment: commonTypes.EnvironmentProduction, Version: 1}}, nil)
+	mockProjectService.EXPECT().
+		GetEnvironment(gomock.Any(), "ns1", "proj1", commonTypes.EnvironmentProduction).
+		Return(&model.ProjectEnvironment{
+			Environment: commonTypes.EnvironmentProduction,
+			Version:     1,
+			Pages:       []commonTypes.Page{{Path: "/robots.txt", Content: "User-agent: *"}},
+		}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/projects/ns1/proj1/pages?environment=production", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+	c.SetParamValues("ns1", "proj1")
+	userCtx := &auth.UserContext{
+		UserID:   1,
+		Username: "agent",
+		SubjectPermissions: &model.SubjectPermissions{
+			Resources: []model.ResourcePermission{
+				{Namespace: "*", Project: "*", Resource: model.ResourceTypePage, Action: model.ActionRead},
+			},
+		},
+	}
+	c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+
+	err := GetPages(permissionChecker, mockFlectoService.NewMockPageService(ctrl), mockProjectService, disabledSnapshotCache())(c)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"Total":1`)
+	assert.Contains(t, rec.Body.String(), `"/robots.txt"`)
+	assert.Contains(t, rec.Body.String(), `"CacheTTL":{"pages":600,"redirects":0}`)
+}
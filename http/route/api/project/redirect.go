@@ -1,6 +1,7 @@
 package project
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -13,7 +14,7 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func GetRedirects(permissionChecker *auth.PermissionChecker, redirectService service.RedirectService) func(echo.Context) error {
+func GetRedirects(permissionChecker *auth.PermissionChecker, redirectService service.RedirectService, projectService service.ProjectService, snapshotCache service.SnapshotCache) func(echo.Context) error {
 	return func(c echo.Context) error {
 		ctx := c.Request().Context()
 		namespaceCode := c.Param(route.NamespaceCodeKey)
@@ -30,20 +31,129 @@ func GetRedirects(permissionChecker *auth.PermissionChecker, redirectService ser
 		if err != nil {
 			return echo.NewHTTPError(http.StatusBadRequest, err)
 		}
-		redirectsDB, total, err := redirectService.FindByProjectPublished(ctx, namespaceCode, projectCode, pagination)
+		environment, err := getEnvironment(c)
+		if err != nil {
+			return err
+		}
+		// The maintenance mode and the cache durations are not published, they apply to all the environments
+		project, err := projectService.GetByCode(ctx, namespaceCode, projectCode)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, err)
 		}
-		redirects := make([]commonTypes.Redirect, 0)
-		for _, redirect := range redirectsDB {
-			redirects = append(redirects, *redirect.Redirect)
+		key := service.SnapshotCacheKey{
+			NamespaceCode: namespaceCode,
+			ProjectCode:   projectCode,
+			Environment:   environment,
+			Resource:      model.ResourceTypeRedirect,
+			Version:       project.Version,
+			Limit:         pagination.GetLimit(),
+			Offset:        pagination.GetOffset(),
+		}
+		var page *service.SnapshotPage
+		options := project.RedirectOptions
+		if environment == commonTypes.EnvironmentProduction {
+			// The content of the snapshot is only loaded when the page is not cached
+			promoted, errVersion := getPromotedVersion(c, projectService, namespaceCode, projectCode, environment)
+			if errVersion != nil {
+				return errVersion
+			}
+			if acceptsSnapshotStream(c) {
+				projectEnvironment, errEnvironment := getPromotedEnvironment(c, projectService, namespaceCode, projectCode, environment)
+				if errEnvironment != nil {
+					return errEnvironment
+				}
+				return streamSnapshot(c, commonTypes.SnapshotHeader{
+					Version:     projectEnvironment.Version,
+					Options:     &projectEnvironment.RedirectOptions,
+					Maintenance: &project.Maintenance,
+					CacheTTL:    project.CacheTTL,
+				}, projectEnvironment.Redirects)
+			}
+			key.Version = promoted.Version
+			builtVersion := 0
+			page, err = snapshotCache.Get(key, func() (*service.SnapshotPage, error) {
+				projectEnvironment, errEnvironment := getPromotedEnvironment(c, projectService, namespaceCode, projectCode, environment)
+				if errEnvironment != nil {
+					return nil, errEnvironment
+				}
+				builtVersion = projectEnvironment.Version
+				items, errMarshal := json.Marshal(paginateSnapshot(projectEnvironment.Redirects, pagination))
+				if errMarshal != nil {
+					return nil, errMarshal
+				}
+				return &service.SnapshotPage{Items: items, Total: len(projectEnvironment.Redirects), Options: projectEnvironment.RedirectOptions}, nil
+			}, func() (bool, error) {
+				return builtVersion == key.Version, nil
+			})
+		} else {
+			if acceptsSnapshotStream(c) {
+				return streamRedirects(c, redirectService, project)
+			}
+			page, err = snapshotCache.Get(key, func() (*service.SnapshotPage, error) {
+				redirectsDB, total, errFind := redirectService.FindByProjectPublished(ctx, namespaceCode, projectCode, pagination)
+				if errFind != nil {
+					return nil, echo.NewHTTPError(http.StatusInternalServerError, errFind)
+				}
+				redirects := make([]commonTypes.Redirect, 0)
+				for _, redirect := range redirectsDB {
+					redirects = append(redirects, redirect.Base())
+				}
+				items, errMarshal := json.Marshal(redirects)
+				if errMarshal != nil {
+					return nil, errMarshal
+				}
+				return &service.SnapshotPage{Items: items, Total: int(total)}, nil
+			}, func() (bool, error) {
+				return isCurrentVersion(c, projectService, project)
+			})
+		}
+		if err != nil {
+			return err
 		}
-		redirectList := &commonTypes.RedirectList{
-			Total:  int(total),
-			Offset: pagination.GetOffset(),
-			Limit:  pagination.GetLimit(),
-			Items:  redirects,
+		if environment == commonTypes.EnvironmentProduction {
+			options = page.Options
 		}
-		return c.JSON(http.StatusOK, redirectList)
+		return c.JSON(http.StatusOK, &cachedRedirectList{
+			Items:       page.Items,
+			Total:       page.Total,
+			Limit:       pagination.GetLimit(),
+			Offset:      pagination.GetOffset(),
+			Options:     options,
+			Maintenance: project.Maintenance,
+			CacheTTL:    project.CacheTTL,
+		})
 	}
 }
+
+// cachedRedirectList is the commonTypes.RedirectList answered from a cached page of redirects
+type cachedRedirectList struct {
+	Items       json.RawMessage
+	Total       int
+	Limit       int
+	Offset      int
+	Options     commonTypes.RedirectOptions
+	Maintenance commonTypes.Maintenance
+	CacheTTL    commonTypes.CacheTTL
+}
+
+// streamRedirects streams the published redirects of a project at its current version
+func streamRedirects(c echo.Context, redirectService service.RedirectService, project *model.Project) error {
+	var stream *snapshotStream
+	return redirectService.StreamByProjectPublished(c.Request().Context(), project.NamespaceCode, project.ProjectCode, project.Version, func(total int64) error {
+		var err error
+		stream, err = startSnapshotStream(c, commonTypes.SnapshotHeader{
+			Version:     project.Version,
+			Total:       int(total),
+			Options:     &project.RedirectOptions,
+			Maintenance: &project.Maintenance,
+			CacheTTL:    project.CacheTTL,
+		})
+		return err
+	}, func(redirectsDB []model.Redirect) error {
+		redirects := make([]commonTypes.Redirect, 0, len(redirectsDB))
+		for _, redirect := range redirectsDB {
+			redirects = append(redirects, redirect.Base())
+		}
+		return writeSnapshotItems(stream, redirects)
+	})
+}
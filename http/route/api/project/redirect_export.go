@@ -0,0 +1,53 @@
+package project
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/bundle"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+)
+
+// GetRedirectsExport renders the published redirects of a project as the configuration of the web
+// server given by the format query parameter: nginx, apache, caddy or json
+func GetRedirectsExport(permissionChecker *auth.PermissionChecker, redirectExportService service.RedirectExportService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		namespaceCode := c.Param(route.NamespaceCodeKey)
+		projectCode := c.Param(route.ProjectCodeKey)
+		if namespaceCode == "" || projectCode == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("namespaceCode and projectCode are required"))
+		}
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeRedirect, model.ActionRead) {
+			return c.NoContent(http.StatusForbidden)
+		}
+
+		format := bundle.RedirectFormat(c.QueryParam("format"))
+		if !format.IsValid() {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("invalid format: %s", format))
+		}
+		version, err := getExportVersion(c)
+		if err != nil {
+			return err
+		}
+
+		content, err := redirectExportService.Export(ctx, namespaceCode, projectCode, version, format)
+		if err != nil {
+			return exportError(err)
+		}
+
+		contentType := echo.MIMETextPlainCharsetUTF8
+		if format == bundle.RedirectFormatJSON {
+			contentType = echo.MIMEApplicationJSON
+		}
+		fileName := fmt.Sprintf("%s-%s-%s", namespaceCode, projectCode, format.FileName())
+		c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+		return c.Blob(http.StatusOK, contentType, content)
+	}
+}
@@ -0,0 +1,89 @@
+package project
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/bundle"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestGetRedirectsExport(t *testing.T) {
+	permissionChecker := auth.NewPermissionChecker(nil)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRedirectExportService := mockFlectoService.NewMockRedirectExportService(ctrl)
+		mockRedirectExportService.EXPECT().
+			Export(gomock.Any(), "ns1", "proj1", 3, bundle.RedirectFormatApache).
+			Return([]byte("RewriteEngine On\n"), nil)
+
+		c, rec := newBundleContext("/api/namespace/ns1/project/proj1/redirects/export?format=apache&version=3", model.ResourceTypeRedirect)
+		require.NoError(t, GetRedirectsExport(permissionChecker, mockRedirectExportService)(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, echo.MIMETextPlainCharsetUTF8, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "attachment; filename=ns1-proj1-redirects.apache.conf", rec.Header().Get(echo.HeaderContentDisposition))
+		assert.Equal(t, "RewriteEngine On\n", rec.Body.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRedirectExportService := mockFlectoService.NewMockRedirectExportService(ctrl)
+		mockRedirectExportService.EXPECT().
+			Export(gomock.Any(), "ns1", "proj1", 0, bundle.RedirectFormatJSON).
+			Return([]byte(`{"redirects":[]}`), nil)
+
+		c, rec := newBundleContext("/api/namespace/ns1/project/proj1/redirects/export?format=json", model.ResourceTypeRedirect)
+		require.NoError(t, GetRedirectsExport(permissionChecker, mockRedirectExportService)(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, echo.MIMEApplicationJSON, rec.Header().Get(echo.HeaderContentType))
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		for _, query := range []string{"", "format=iis", "format=nginx&version=x"} {
+			ctrl := gomock.NewController(t)
+			mockRedirectExportService := mockFlectoService.NewMockRedirectExportService(ctrl)
+
+			c, _ := newBundleContext("/api/namespace/ns1/project/proj1/redirects/export?"+query, model.ResourceTypeRedirect)
+			err := GetRedirectsExport(permissionChecker, mockRedirectExportService)(c)
+
+			var httpErr *echo.HTTPError
+			require.ErrorAs(t, err, &httpErr, query)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code, query)
+		}
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRedirectExportService := mockFlectoService.NewMockRedirectExportService(ctrl)
+		mockRedirectExportService.EXPECT().
+			Export(gomock.Any(), "ns1", "proj1", 0, bundle.RedirectFormatNginx).
+			Return(nil, gorm.ErrRecordNotFound)
+
+		c, _ := newBundleContext("/api/namespace/ns1/project/proj1/redirects/export?format=nginx", model.ResourceTypeRedirect)
+		err := GetRedirectsExport(permissionChecker, mockRedirectExportService)(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRedirectExportService := mockFlectoService.NewMockRedirectExportService(ctrl)
+
+		c, rec := newBundleContext("/api/namespace/ns1/project/proj1/redirects/export?format=nginx", model.ResourceTypePage)
+		require.NoError(t, GetRedirectsExport(permissionChecker, mockRedirectExportService)(c))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -8,15 +8,22 @@ import (
 
 	"github.com/flectolab/flecto-manager/auth"
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
 	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/invalidation"
 	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
 	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
+func disabledSnapshotCache() service.SnapshotCache {
+	return service.NewSnapshotCache(config.SnapshotCacheConfig{}, invalidation.NewMemoryBus())
+}
+
 func TestGetRedirects(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
@@ -35,6 +42,10 @@ func TestGetRedirects(t *testing.T) {
 			},
 		}
 
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			GetByCode(gomock.Any(), "ns1", "proj1").
+			Return(&model.Project{RedirectOptions: commonTypes.RedirectOptions{CaseInsensitive: true}, CacheTTL: commonTypes.CacheTTL{Redirects: 300}}, nil)
 		mockRedirectService.EXPECT().
 			FindByProjectPublished(gomock.Any(), "ns1", "proj1", gomock.Any()).
 			Return(redirects, int64(1), nil)
@@ -59,7 +70,7 @@ func TestGetRedirects(t *testing.T) {
 		ctx := auth.SetUserContext(req.Context(), userCtx)
 		c.SetRequest(req.WithContext(ctx))
 
-		handler := GetRedirects(permissionChecker, mockRedirectService)
+		handler := GetRedirects(permissionChecker, mockRedirectService, mockProjectService, disabledSnapshotCache())
 		err := handler(c)
 
 		require.NoError(t, err)
@@ -67,6 +78,10 @@ func TestGetRedirects(t *testing.T) {
 		assert.Contains(t, rec.Body.String(), `"Total":1`)
 		assert.Contains(t, rec.Body.String(), `"/old"`)
 		assert.Contains(t, rec.Body.String(), `"/new"`)
+		assert.Contains(t, rec.Body.String(), `"id":1`)
+		assert.Contains(t, rec.Body.String(), `"Options":{"caseInsensitive":true,"ignoreTrailingSlash":false,"preserveQueryString":false,"normalizeUrl":false}`)
+		assert.Contains(t, rec.Body.String(), `"Maintenance":{"enabled":false,"pagePath":""}`)
+		assert.Contains(t, rec.Body.String(), `"CacheTTL":{"pages":0,"redirects":300}`)
 	})
 
 	t.Run("success empty list", func(t *testing.T) {
@@ -77,6 +92,10 @@ func TestGetRedirects(t *testing.T) {
 		mockRoleService := mockFlectoService.NewMockRoleService(ctrl)
 		permissionChecker := auth.NewPermissionChecker(mockRoleService)
 
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			GetByCode(gomock.Any(), "ns1", "proj1").
+			Return(&model.Project{RedirectOptions: commonTypes.RedirectOptions{}}, nil)
 		mockRedirectService.EXPECT().
 			FindByProjectPublished(gomock.Any(), "ns1", "proj1", gomock.Any()).
 			Return([]model.Redirect{}, int64(0), nil)
@@ -100,7 +119,7 @@ func TestGetRedirects(t *testing.T) {
 		ctx := auth.SetUserContext(req.Context(), userCtx)
 		c.SetRequest(req.WithContext(ctx))
 
-		handler := GetRedirects(permissionChecker, mockRedirectService)
+		handler := GetRedirects(permissionChecker, mockRedirectService, mockProjectService, disabledSnapshotCache())
 		err := handler(c)
 
 		require.NoError(t, err)
@@ -123,7 +142,7 @@ func TestGetRedirects(t *testing.T) {
 		c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
 		c.SetParamValues("", "proj1")
 
-		handler := GetRedirects(permissionChecker, mockRedirectService)
+		handler := GetRedirects(permissionChecker, mockRedirectService, mockFlectoService.NewMockProjectService(ctrl), disabledSnapshotCache())
 		err := handler(c)
 
 		require.Error(t, err)
@@ -147,7 +166,7 @@ func TestGetRedirects(t *testing.T) {
 		c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
 		c.SetParamValues("ns1", "")
 
-		handler := GetRedirects(permissionChecker, mockRedirectService)
+		handler := GetRedirects(permissionChecker, mockRedirectService, mockFlectoService.NewMockProjectService(ctrl), disabledSnapshotCache())
 		err := handler(c)
 
 		require.Error(t, err)
@@ -180,7 +199,7 @@ func TestGetRedirects(t *testing.T) {
 		ctx := auth.SetUserContext(req.Context(), userCtx)
 		c.SetRequest(req.WithContext(ctx))
 
-		handler := GetRedirects(permissionChecker, mockRedirectService)
+		handler := GetRedirects(permissionChecker, mockRedirectService, mockFlectoService.NewMockProjectService(ctrl), disabledSnapshotCache())
 		err := handler(c)
 
 		require.NoError(t, err)
@@ -195,6 +214,10 @@ func TestGetRedirects(t *testing.T) {
 		mockRoleService := mockFlectoService.NewMockRoleService(ctrl)
 		permissionChecker := auth.NewPermissionChecker(mockRoleService)
 
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			GetByCode(gomock.Any(), "ns1", "proj1").
+			Return(&model.Project{RedirectOptions: commonTypes.RedirectOptions{}}, nil)
 		mockRedirectService.EXPECT().
 			FindByProjectPublished(gomock.Any(), "ns1", "proj1", gomock.Any()).
 			Return(nil, int64(0), errors.New("database error"))
@@ -218,7 +241,7 @@ func TestGetRedirects(t *testing.T) {
 		ctx := auth.SetUserContext(req.Context(), userCtx)
 		c.SetRequest(req.WithContext(ctx))
 
-		handler := GetRedirects(permissionChecker, mockRedirectService)
+		handler := GetRedirects(permissionChecker, mockRedirectService, mockProjectService, disabledSnapshotCache())
 		err := handler(c)
 
 		require.Error(t, err)
@@ -227,3 +250,195 @@ func TestGetRedirects(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
 	})
 }
+
+func TestGetRedirects_Environment(t *testing.T) {
+	serve := func(t *testing.T, projectService *mockFlectoService.MockProjectService, query string) (*httptest.ResponseRecorder, error) {
+		ctrl := gomock.NewController(t)
+		permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/projects/ns1/proj1/redirects?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+		c.SetParamValues("ns1", "proj1")
+		userCtx := &auth.UserContext{
+			UserID:   1,
+			Username: "agent",
+			SubjectPermissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{
+					{Namespace: "*", Project: "*", Resource: model.ResourceTypeRedirect, Action: model.ActionRead},
+				},
+			},
+		}
+		c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+
+		return rec, GetRedirects(permissionChecker, mockFlectoService.NewMockRedirectService(ctrl), projectService, disabledSnapshotCache())(c)
+	}
+
+	t.Run("production snapshot is paginated", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			GetByCode(gomock.Any(), "ns1", "proj1").
+			Return(&model.Project{Maintenance: commonTypes.Maintenance{Enabled: true, PagePath: "/maintenance.html"}}, nil)
+		mockProjectService.EXPECT().
+			GetEnvironments(gomock.Any(), "ns1", "proj1").
+			Return([]model.ProjectEnvironment{{Environment: commonTypes.EnvironmentProduction, Version: 3}}, nil)
+		mockProjectService.EXPECT().
+			GetEnvironment(gomock.Any(), "ns1", "proj1", commonTypes.EnvironmentProduction).
+			Return(&model.ProjectEnvironment{
+				Environment: commonTypes.EnvironmentProduction,
+				Version:     3,
+				Redirects: []commonTypes.Redirect{
+					{Source: "/one", Target: "/new"},
+					{Source: "/two", Target: "/new"},
+					{Source: "/three", Target: "/new"},
+				},
+				RedirectOptions: commonTypes.RedirectOptions{PreserveQueryString: true},
+			}, nil)
+
+		rec, err := serve(t, mockProjectService, "environment=production&limit=2&offset=1")
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"Total":3`)
+		assert.NotContains(t, rec.Body.String(), `"/one"`)
+		assert.Contains(t, rec.Body.String(), `"/two"`)
+		assert.Contains(t, rec.Body.String(), `"/three"`)
+		assert.Contains(t, rec.Body.String(), `"preserveQueryString":true`)
+		assert.Contains(t, rec.Body.String(), `"Maintenance":{"enabled":true,"pagePath":"/maintenance.html"}`, "maintenance of the project, not of the snapshot")
+	})
+
+	t.Run("production not promoted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			GetByCode(gomock.Any(), "ns1", "proj1").
+			Return(&model.Project{}, nil)
+		mockProjectService.EXPECT().
+			GetEnvironments(gomock.Any(), "ns1", "proj1").
+			Return([]model.ProjectEnvironment{}, nil)
+
+		_, err := serve(t, mockProjectService, "environment=production")
+
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+
+	t.Run("invalid environment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		_, err := serve(t, mockFlectoService.NewMockProjectService(ctrl), "environment=qa")
+
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+}
+
+func TestGetRedirects_SnapshotCache(t *testing.T) {
+	serve := func(t *testing.T, handler echo.HandlerFunc, query string) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/projects/ns1/proj1/redirects?"+query, nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+		c.SetParamValues("ns1", "proj1")
+		userCtx := &auth.UserContext{
+			UserID:   1,
+			Username: "agent",
+			SubjectPermissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{
+					{Namespace: "*", Project: "*", Resource: model.ResourceTypeRedirect, Action: model.ActionRead},
+				},
+			},
+		}
+		c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+		require.NoError(t, handler(c))
+		return rec
+	}
+	redirects := []model.Redirect{{ID: 1, Redirect: &commonTypes.Redirect{Source: "/old", Target: "/new"}}}
+
+	t.Run("staging hit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockRedirectService := mockFlectoService.NewMockRedirectService(ctrl)
+		cache := service.NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 1024}, invalidation.NewMemoryBus())
+		gomock.InOrder(
+			mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(&model.Project{NamespaceCode: "ns1", ProjectCode: "proj1", Version: 2}, nil),
+			mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(&model.Project{NamespaceCode: "ns1", ProjectCode: "proj1", Version: 2}, nil),
+			mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(&model.Project{
+				NamespaceCode: "ns1",
+				ProjectCode:   "proj1",
+				Version:       2,
+				Maintenance:   commonTypes.Maintenance{Enabled: true},
+			}, nil),
+		)
+		mockRedirectService.EXPECT().FindByProjectPublished(gomock.Any(), "ns1", "proj1", gomock.Any()).Return(redirects, int64(1), nil).Times(1)
+		handler := GetRedirects(auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl)), mockRedirectService, mockProjectService, cache)
+
+		first := serve(t, handler, "")
+		second := serve(t, handler, "")
+
+		assert.Contains(t, first.Body.String(), `"/old"`)
+		assert.Contains(t, second.Body.String(), `"/old"`)
+		assert.Contains(t, second.Body.String(), `"Total":1`)
+		assert.Contains(t, second.Body.String(), `"Maintenance":{"enabled":true,"pagePath":""}`, "maintenance of the project, not of the cache")
+		assert.Equal(t, uint64(1), cache.Stats().Hits)
+		assert.Equal(t, uint64(1), cache.Stats().Misses)
+	})
+
+	t.Run("published meanwhile", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockRedirectService := mockFlectoService.NewMockRedirectService(ctrl)
+		cache := service.NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 1024}, invalidation.NewMemoryBus())
+		gomock.InOrder(
+			mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(&model.Project{NamespaceCode: "ns1", ProjectCode: "proj1", Version: 2}, nil),
+			mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(&model.Project{NamespaceCode: "ns1", ProjectCode: "proj1", Version: 3}, nil),
+		)
+		mockRedirectService.EXPECT().FindByProjectPublished(gomock.Any(), "ns1", "proj1", gomock.Any()).Return(redirects, int64(1), nil)
+
+		rec := serve(t, GetRedirects(auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl)), mockRedirectService, mockProjectService, cache), "")
+
+		assert.Contains(t, rec.Body.String(), `"/old"`)
+		assert.Equal(t, 0, cache.Stats().Entries, "the redirects read may belong to the next version")
+	})
+
+	t.Run("production hit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		cache := service.NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 1024}, invalidation.NewMemoryBus())
+		mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(&model.Project{NamespaceCode: "ns1", ProjectCode: "proj1", Version: 4}, nil).Times(2)
+		mockProjectService.EXPECT().
+			GetEnvironments(gomock.Any(), "ns1", "proj1").
+			Return([]model.ProjectEnvironment{{Environment: commonTypes.EnvironmentProduction, Version: 3}}, nil).
+			Times(2)
+		mockProjectService.EXPECT().
+			GetEnvironment(gomock.Any(), "ns1", "proj1", commonTypes.EnvironmentProduction).
+			Return(&model.ProjectEnvironment{
+				Environment:     commonTypes.EnvironmentProduction,
+				Version:         3,
+				Redirects:       []commonTypes.Redirect{{Source: "/old", Target: "/new"}},
+				RedirectOptions: commonTypes.RedirectOptions{PreserveQueryString: true},
+			}, nil).
+			Times(1)
+		handler := GetRedirects(auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl)), mockFlectoService.NewMockRedirectService(ctrl), mockProjectService, cache)
+
+		serve(t, handler, "environment=production")
+		rec := serve(t, handler, "environment=production")
+
+		assert.Contains(t, rec.Body.String(), `"/old"`)
+		assert.Contains(t, rec.Body.String(), `"preserveQueryString":true`, "options of the promoted snapshot")
+		assert.Equal(t, uint64(1), cache.Stats().Hits)
+	})
+}
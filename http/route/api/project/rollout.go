@@ -0,0 +1,45 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// GetRollout reports how many registered agents applied the latest version of the environment of the project and
+// which ones lag behind, so that a deployment can wait for the agents after a publish
+func GetRollout(permissionChecker *auth.PermissionChecker, agentInstanceService service.AgentInstanceService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		namespaceCode := c.Param(route.NamespaceCodeKey)
+		projectCode := c.Param(route.ProjectCodeKey)
+		if namespaceCode == "" || projectCode == "" {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Errorf("namespaceCode and projectCode are required"))
+		}
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanResource(userCtx.SubjectPermissions, namespaceCode, projectCode, model.ResourceTypeAgent, model.ActionRead) {
+			return c.NoContent(http.StatusForbidden)
+		}
+		environment, err := getEnvironment(c)
+		if err != nil {
+			return err
+		}
+
+		status, err := agentInstanceService.GetRollout(ctx, namespaceCode, projectCode, environment)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return echo.NewHTTPError(http.StatusNotFound, err)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+
+		return c.JSON(http.StatusOK, status)
+	}
+}
@@ -0,0 +1,97 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/http/route"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func newRolloutContext(target string, resource model.ResourceType) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+	c.SetParamValues("ns1", "proj1")
+	userCtx := &auth.UserContext{
+		Username: "deployer",
+		SubjectPermissions: &model.SubjectPermissions{
+			Resources: []model.ResourcePermission{
+				{Namespace: "*", Project: "*", Resource: resource, Action: model.ActionRead},
+			},
+		},
+	}
+	c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+	return c, rec
+}
+
+func TestGetRollout(t *testing.T) {
+	permissionChecker := auth.NewPermissionChecker(nil)
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+		mockAgentInstanceService.EXPECT().
+			GetRollout(gomock.Any(), "ns1", "proj1", commonTypes.EnvironmentProduction).
+			Return(&model.RolloutStatus{
+				NamespaceCode: "ns1", ProjectCode: "proj1", Environment: commonTypes.EnvironmentProduction, Version: 4, Agents: 2, Applied: 1,
+				Lagging: []model.RolloutAgent{{Name: "edge-2", State: model.AgentInstanceStateOnline, Version: 3}},
+			}, nil)
+
+		c, rec := newRolloutContext("/api/namespace/ns1/project/proj1/rollout?environment=production", model.ResourceTypeAgent)
+		require.NoError(t, GetRollout(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"applied":1`)
+		assert.Contains(t, rec.Body.String(), `"name":"edge-2"`)
+	})
+
+	t.Run("invalid environment", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+
+		c, _ := newRolloutContext("/api/namespace/ns1/project/proj1/rollout?environment=qa", model.ResourceTypeAgent)
+		err := GetRollout(permissionChecker, mockAgentInstanceService)(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+		mockAgentInstanceService.EXPECT().
+			GetRollout(gomock.Any(), "ns1", "proj1", commonTypes.EnvironmentStaging).
+			Return(nil, fmt.Errorf("project: %w", gorm.ErrRecordNotFound))
+
+		c, _ := newRolloutContext("/api/namespace/ns1/project/proj1/rollout", model.ResourceTypeAgent)
+		err := GetRollout(permissionChecker, mockAgentInstanceService)(c)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+
+	t.Run("forbidden without the agent permission", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockAgentInstanceService := mockFlectoService.NewMockAgentInstanceService(ctrl)
+
+		c, rec := newRolloutContext("/api/namespace/ns1/project/proj1/rollout", model.ResourceTypeRedirect)
+		require.NoError(t, GetRollout(permissionChecker, mockAgentInstanceService)(c))
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
@@ -0,0 +1,66 @@
+package project
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+)
+
+// acceptsSnapshotStream tells whether the agent asked with the Accept header for the whole snapshot streamed as NDJSON
+// instead of a page of it
+func acceptsSnapshotStream(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), commonTypes.MIMEApplicationNDJSON)
+}
+
+// snapshotStream writes a snapshot as NDJSON, its header then a line for each of its items. The items are flushed
+// batch by batch, the response being never held in memory. An error after the header ends the response early, the
+// agents seeing fewer items than the total of the header.
+type snapshotStream struct {
+	response *echo.Response
+	encoder  *json.Encoder
+}
+
+func startSnapshotStream(c echo.Context, header commonTypes.SnapshotHeader) (*snapshotStream, error) {
+	response := c.Response()
+	response.Header().Set(echo.HeaderContentType, commonTypes.MIMEApplicationNDJSON)
+	response.Header().Set(commonTypes.HeaderVersion, strconv.Itoa(header.Version))
+	response.WriteHeader(http.StatusOK)
+
+	stream := &snapshotStream{response: response, encoder: json.NewEncoder(response)}
+	if err := stream.encoder.Encode(header); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+// writeSnapshotItems writes a batch of items and flushes it to the agent
+func writeSnapshotItems[T any](stream *snapshotStream, items []T) error {
+	for _, item := range items {
+		if err := stream.encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	stream.response.Flush()
+	return nil
+}
+
+// streamSnapshot streams the items of a snapshot already loaded, the ones promoted to an environment
+func streamSnapshot[T any](c echo.Context, header commonTypes.SnapshotHeader, items []T) error {
+	header.Total = len(items)
+	stream, err := startSnapshotStream(c, header)
+	if err != nil {
+		return err
+	}
+	for start := 0; start < len(items); start += service.SnapshotStreamBatchSize {
+		end := min(start+service.SnapshotStreamBatchSize, len(items))
+		if err = writeSnapshotItems(stream, items[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/http/route"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newSnapshotStreamContext(path, query string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, path+"?"+query, nil)
+	req.Header.Set(echo.HeaderAccept, commonTypes.MIMEApplicationNDJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+	c.SetParamValues("ns1", "proj1")
+	userCtx := &auth.UserContext{
+		UserID:   1,
+		Username: "agent",
+		SubjectPermissions: &model.SubjectPermissions{
+			Resources: []model.ResourcePermission{
+				{Namespace: "*", Project: "*", Resource: model.ResourceTypeRedirect, Action: model.ActionRead},
+				{Namespace: "*", Project: "*", Resource: model.ResourceTypePage, Action: model.ActionRead},
+			},
+		},
+	}
+	c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+	return c, rec
+}
+
+func TestGetRedirects_Stream(t *testing.T) {
+	project := &model.Project{
+		NamespaceCode:   "ns1",
+		ProjectCode:     "proj1",
+		Version:         7,
+		RedirectOptions: commonTypes.RedirectOptions{CaseInsensitive: true},
+		Maintenance:     commonTypes.Maintenance{Enabled: true, PagePath: "/maintenance.html"},
+		CacheTTL:        commonTypes.CacheTTL{Redirects: 300},
+	}
+
+	t.Run("staging", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockRedirectService := mockFlectoService.NewMockRedirectService(ctrl)
+		mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(project, nil)
+		mockRedirectService.EXPECT().
+			StreamByProjectPublished(gomock.Any(), "ns1", "proj1", 7, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ string, _ int, start func(int64) error, fn func([]model.Redirect) error) error {
+				require.NoError(t, start(3))
+				require.NoError(t, fn([]model.Redirect{
+					{ID: 1, Redirect: &commonTypes.Redirect{Source: "/a", Target: "/new"}},
+					{ID: 2, Redirect: &commonTypes.Redirect{Source: "/b", Target: "/new"}},
+				}))
+				return fn([]model.Redirect{{ID: 3, Redirect: &commonTypes.Redirect{Source: "/c", Target: "/new"}}})
+			})
+
+		c, rec := newSnapshotStreamContext("/api/namespace/ns1/project/proj1/redirects", "limit=1")
+		err := GetRedirects(auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl)), mockRedirectService, mockProjectService, disabledSnapshotCache())(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, commonTypes.MIMEApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, "7", rec.Header().Get(commonTypes.HeaderVersion))
+		var sources []string
+		header, err := commonTypes.ReadSnapshot(rec.Body, func(redirect commonTypes.Redirect) error {
+			sources = append(sources, redirect.Source)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 7, header.Version)
+		assert.True(t, header.Options.CaseInsensitive)
+		assert.Equal(t, "/maintenance.html", header.Maintenance.PagePath)
+		assert.Equal(t, 300, header.CacheTTL.Redirects)
+		assert.Equal(t, []string{"/a", "/b", "/c"}, sources, "the whole snapshot whatever the pagination")
+	})
+
+	t.Run("published meanwhile", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockRedirectService := mockFlectoService.NewMockRedirectService(ctrl)
+		mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(project, nil)
+		mockRedirectService.EXPECT().
+			StreamByProjectPublished(gomock.Any(), "ns1", "proj1", 7, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ string, _ int, start func(int64) error, fn func([]model.Redirect) error) error {
+				require.NoError(t, start(2))
+				require.NoError(t, fn([]model.Redirect{{ID: 1, Redirect: &commonTypes.Redirect{Source: "/a", Target: "/new"}}}))
+				return service.ErrSnapshotChanged
+			})
+
+		c, rec := newSnapshotStreamContext("/api/namespace/ns1/project/proj1/redirects", "")
+		err := GetRedirects(auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl)), mockRedirectService, mockProjectService, disabledSnapshotCache())(c)
+
+		assert.ErrorIs(t, err, service.ErrSnapshotChanged)
+		assert.True(t, c.Response().Committed)
+		_, err = commonTypes.ReadSnapshot(rec.Body, func(redirect commonTypes.Redirect) error { return nil })
+		assert.ErrorIs(t, err, commonTypes.ErrSnapshotIncomplete)
+	})
+
+	t.Run("production", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(project, nil)
+		mockProjectService.EXPECT().
+			GetEnvironments(gomock.Any(), "ns1", "proj1").
+			Return([]model.ProjectEnvironment{{Environment: commonTypes.EnvironmentProduction, Version: 5}}, nil)
+		redirects := make([]commonTypes.Redirect, service.SnapshotStreamBatchSize+1)
+		for i := range redirects {
+			redirects[i] = commonTypes.Redirect{Source: fmt.Sprintf("/r%d", i), Target: "/new"}
+		}
+		mockProjectService.EXPECT().
+			GetEnvironment(gomock.Any(), "ns1", "proj1", commonTypes.EnvironmentProduction).
+			Return(&model.ProjectEnvironment{
+				Environment:     commonTypes.EnvironmentProduction,
+				Version:         5,
+				Redirects:       redirects,
+				RedirectOptions: commonTypes.RedirectOptions{PreserveQueryString: true},
+			}, nil)
+
+		c, rec := newSnapshotStreamContext("/api/namespace/ns1/project/proj1/redirects", "environment=production")
+		err := GetRedirects(auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl)), mockFlectoService.NewMockRedirectService(ctrl), mockProjectService, disabledSnapshotCache())(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, "5", rec.Header().Get(commonTypes.HeaderVersion))
+		count := 0
+		header, err := commonTypes.ReadSnapshot(rec.Body, func(redirect commonTypes.Redirect) error {
+			assert.Equal(t, fmt.Sprintf("/r%d", count), redirect.Source)
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 5, header.Version)
+		assert.Equal(t, len(redirects), header.Total)
+		assert.True(t, header.Options.PreserveQueryString, "options of the snapshot")
+		assert.True(t, header.Maintenance.Enabled, "maintenance of the project")
+	})
+}
+
+func TestGetPages_Stream(t *testing.T) {
+	project := &model.Project{NamespaceCode: "ns1", ProjectCode: "proj1", Version: 7, CacheTTL: commonTypes.CacheTTL{Pages: 600}}
+
+	t.Run("staging", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockPageService := mockFlectoService.NewMockPageService(ctrl)
+		mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(project, nil)
+		mockPageService.EXPECT().
+			StreamByProjectPublished(gomock.Any(), "ns1", "proj1", 7, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ string, _ int, start func(int64) error, fn func([]model.Page) error) error {
+				require.NoError(t, start(1))
+				return fn([]model.Page{{ID: 1, Page: &commonTypes.Page{Path: "/robots.txt", Content: "User-agent: *"}}})
+			})
+
+		c, rec := newSnapshotStreamContext("/api/namespace/ns1/project/proj1/pages", "")
+		err := GetPages(auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl)), mockPageService, mockProjectService, disabledSnapshotCache())(c)
+
+		require.NoError(t, err)
+		var pages []commonTypes.Page
+		header, err := commonTypes.ReadSnapshot(rec.Body, func(page commonTypes.Page) error {
+			pages = append(pages, page)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 7, header.Version)
+		assert.Nil(t, header.Options)
+		assert.Equal(t, 600, header.CacheTTL.Pages)
+		require.Len(t, pages, 1)
+		assert.Equal(t, "/robots.txt", pages[0].Path)
+	})
+
+	t.Run("production", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().GetByCode(gomock.Any(), "ns1", "proj1").Return(project, nil)
+		mockProjectService.EXPECT().
+			GetEnvironments(gomock.Any(), "ns1", "proj1").
+			Return([]model.ProjectEnvironment{{Environment: commonTypes.EnvironmentProduction, Version: 5}}, nil)
+		mockProjectService.EXPECT().
+			GetEnvironment(gomock.Any(), "ns1", "proj1", commonTypes.EnvironmentProduction).
+			Return(&model.ProjectEnvironment{
+				Environment: commonTypes.EnvironmentProduction,
+				Version:     5,
+				Pages:       []commonTypes.Page{{Path: "/a"}, {Path: "/b"}},
+			}, nil)
+
+		c, rec := newSnapshotStreamContext("/api/namespace/ns1/project/proj1/pages", "environment=production")
+		err := GetPages(auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl)), mockFlectoService.NewMockPageService(ctrl), mockProjectService, disabledSnapshotCache())(c)
+
+		require.NoError(t, err)
+		header, err := commonTypes.ReadSnapshot(rec.Body, func(page commonTypes.Page) error { return nil })
+		require.NoError(t, err)
+		assert.Equal(t, 5, header.Version)
+		assert.Equal(t, 2, header.Total)
+	})
+}
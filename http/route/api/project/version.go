@@ -3,8 +3,10 @@ package project
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/http/route"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/service"
@@ -24,10 +26,23 @@ func GetVersion(permissionChecker *auth.PermissionChecker, projectService servic
 			return c.NoContent(http.StatusForbidden)
 		}
 
+		environment, err := getEnvironment(c)
+		if err != nil {
+			return err
+		}
 		project, err := projectService.GetByCode(ctx, namespaceCode, projectCode)
 		if err != nil {
 			return echo.NewHTTPError(http.StatusInternalServerError, err)
 		}
+		// Agents compare the header to the maintenance mode of their last sync, it changes without a new version
+		c.Response().Header().Set(commonTypes.HeaderMaintenance, strconv.FormatBool(project.Maintenance.Enabled))
+		if environment == commonTypes.EnvironmentProduction {
+			projectEnvironment, errEnvironment := getPromotedVersion(c, projectService, namespaceCode, projectCode, environment)
+			if errEnvironment != nil {
+				return errEnvironment
+			}
+			return c.JSON(http.StatusOK, projectEnvironment.Version)
+		}
 
 		return c.JSON(http.StatusOK, project.Version)
 	}
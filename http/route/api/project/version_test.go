@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/http/route"
 	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
 	"github.com/flectolab/flecto-manager/model"
@@ -62,6 +63,7 @@ func TestGetVersion(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Equal(t, "42\n", rec.Body.String())
+		assert.Equal(t, "false", rec.Header().Get(commonTypes.HeaderMaintenance))
 	})
 
 	t.Run("missing namespace code", func(t *testing.T) {
@@ -184,3 +186,69 @@ func TestGetVersion(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
 	})
 }
+
+func TestGetVersion_Environment(t *testing.T) {
+	serve := func(t *testing.T, projectService *mockFlectoService.MockProjectService) (*httptest.ResponseRecorder, error) {
+		ctrl := gomock.NewController(t)
+		permissionChecker := auth.NewPermissionChecker(mockFlectoService.NewMockRoleService(ctrl))
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/projects/ns1/proj1/version?environment=production", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+		c.SetParamValues("ns1", "proj1")
+		userCtx := &auth.UserContext{
+			UserID:   1,
+			Username: "agent",
+			SubjectPermissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{
+					{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+				},
+			},
+		}
+		c.SetRequest(req.WithContext(auth.SetUserContext(req.Context(), userCtx)))
+
+		return rec, GetVersion(permissionChecker, projectService)(c)
+	}
+
+	t.Run("production version", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			GetByCode(gomock.Any(), "ns1", "proj1").
+			Return(&model.Project{Version: 9, Maintenance: commonTypes.Maintenance{Enabled: true, PagePath: "/maintenance.html"}}, nil)
+		mockProjectService.EXPECT().
+			GetEnvironments(gomock.Any(), "ns1", "proj1").
+			Return([]model.ProjectEnvironment{{Environment: commonTypes.EnvironmentProduction, Version: 7}}, nil)
+
+		rec, err := serve(t, mockProjectService)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "7\n", rec.Body.String())
+		assert.Equal(t, "true", rec.Header().Get(commonTypes.HeaderMaintenance))
+	})
+
+	t.Run("production not promoted", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockProjectService := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectService.EXPECT().
+			GetByCode(gomock.Any(), "ns1", "proj1").
+			Return(&model.Project{}, nil)
+		mockProjectService.EXPECT().
+			GetEnvironments(gomock.Any(), "ns1", "proj1").
+			Return([]model.ProjectEnvironment{}, nil)
+
+		_, err := serve(t, mockProjectService)
+
+		require.Error(t, err)
+		httpErr, ok := err.(*echo.HTTPError)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, httpErr.Code)
+	})
+}
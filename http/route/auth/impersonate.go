@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/flectolab/flecto-manager/auth"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+func GetImpersonate(ctx *appContext.Context, permissionChecker *auth.PermissionChecker, authService service.AuthService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		userCtx := auth.GetUser(c.Request().Context())
+
+		// API tokens have no user to record and an impersonation cannot be chained
+		if userCtx.AuthType == types.AuthTypeToken || userCtx.IsImpersonated() ||
+			!permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionImpersonate, model.ActionWrite) {
+			return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeForbidden, "Impersonation is not allowed"))
+		}
+
+		var req types.ImpersonateRequest
+		if err := c.Bind(&req); err != nil {
+			return route.ErrorJSON(c, http.StatusBadRequest, flectoErrors.New(flectoErrors.CodeInvalidRequest, "Invalid request body"))
+		}
+
+		if err := ctx.Validator.Struct(&req); err != nil {
+			return route.ErrorJSON(c, http.StatusBadRequest, err)
+		}
+
+		// The target may not have permissions the administrator lacks, which would be gained by impersonating it
+		covered, err := permissionChecker.CoversUsername(c.Request().Context(), userCtx.SubjectPermissions, req.Username)
+		if err != nil {
+			return impersonateError(c, err)
+		}
+		if !covered {
+			return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeForbidden, "Cannot impersonate a user having permissions you do not have"))
+		}
+
+		adminUser := &model.User{ID: userCtx.UserID, Username: userCtx.Username}
+		user, tokens, err := authService.Impersonate(c.Request().Context(), adminUser, req.Username)
+		if err != nil {
+			return impersonateError(c, err)
+		}
+
+		return c.JSON(http.StatusOK, types.AuthResponse{
+			User:   authService.ToUserResponse(user),
+			Tokens: tokens,
+		})
+	}
+}
+
+// impersonateError answers the error of an impersonation
+func impersonateError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return route.ErrorJSON(c, http.StatusNotFound, flectoErrors.New(flectoErrors.CodeNotFound, "User account not exist"))
+	case errors.Is(err, service.ErrUserInactive):
+		return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeUserInactive, "User account is inactive"))
+	case errors.Is(err, service.ErrImpersonateSelf):
+		return route.ErrorJSON(c, http.StatusBadRequest, flectoErrors.New(flectoErrors.CodeInvalidRequest, "Cannot impersonate yourself"))
+	default:
+		return route.ErrorJSON(c, http.StatusInternalServerError, flectoErrors.New(flectoErrors.CodeInternal, "Impersonation failed"))
+	}
+}
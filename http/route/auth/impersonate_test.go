@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	flectoAuth "github.com/flectolab/flecto-manager/auth"
+	appContext "github.com/flectolab/flecto-manager/context"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func impersonateAdminContext() *flectoAuth.UserContext {
+	return &flectoAuth.UserContext{
+		UserID:   1,
+		Username: "admin",
+		AuthType: types.AuthTypeBasic,
+		SubjectPermissions: &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Section: model.AdminSectionImpersonate, Action: model.ActionWrite}},
+		},
+	}
+}
+
+func serveImpersonate(t *testing.T, authService service.AuthService, userCtx *flectoAuth.UserContext, body string) *httptest.ResponseRecorder {
+	return serveImpersonateTarget(t, authService, userCtx, &model.SubjectPermissions{}, nil, body)
+}
+
+// serveImpersonateTarget serves an impersonation of a target having targetPermissions, or whose permissions fail with targetErr
+func serveImpersonateTarget(t *testing.T, authService service.AuthService, userCtx *flectoAuth.UserContext, targetPermissions *model.SubjectPermissions, targetErr error, body string) *httptest.ResponseRecorder {
+	ctrl := gomock.NewController(t)
+	mockRoleService := mockFlectoService.NewMockRoleService(ctrl)
+	mockRoleService.EXPECT().GetPermissionsByUsername(gomock.Any(), gomock.Any()).Return(targetPermissions, targetErr).AnyTimes()
+	permissionChecker := flectoAuth.NewPermissionChecker(mockRoleService)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/auth/impersonate", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req = req.WithContext(flectoAuth.SetUserContext(req.Context(), userCtx))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := GetImpersonate(appContext.TestContext(nil), permissionChecker, authService)
+	require.NoError(t, handler(c))
+	return rec
+}
+
+func TestGetImpersonate(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		mockAuthService := mockFlectoService.NewMockAuthService(ctrl)
+		user := &model.User{ID: 2, Username: "target"}
+		tokens := &types.TokenPair{AccessToken: "access-token"}
+
+		mockAuthService.EXPECT().
+			Impersonate(gomock.Any(), &model.User{ID: 1, Username: "admin"}, "target").
+			Return(user, tokens, nil)
+		mockAuthService.EXPECT().
+			ToUserResponse(user).
+			Return(&types.UserResponse{ID: 2, Username: "target"})
+
+		rec := serveImpersonate(t, mockAuthService, impersonateAdminContext(), `{"username":"target"}`)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"accessToken":"access-token"`)
+		assert.Contains(t, rec.Body.String(), `"username":"target"`)
+	})
+
+	t.Run("missing permission", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userCtx := impersonateAdminContext()
+		userCtx.SubjectPermissions = &model.SubjectPermissions{}
+
+		rec := serveImpersonate(t, mockFlectoService.NewMockAuthService(ctrl), userCtx, `{"username":"target"}`)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("target with more permissions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		globalAdmin := &model.SubjectPermissions{
+			Admin: []model.AdminPermission{{Namespace: "*", Section: model.AdminSectionAll, Action: model.ActionAll}},
+		}
+
+		rec := serveImpersonateTarget(t, mockFlectoService.NewMockAuthService(ctrl), impersonateAdminContext(), globalAdmin, nil, `{"username":"target"}`)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Cannot impersonate a user having permissions you do not have")
+	})
+
+	t.Run("target not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		rec := serveImpersonateTarget(t, mockFlectoService.NewMockAuthService(ctrl), impersonateAdminContext(), nil, service.ErrUserNotFound, `{"username":"target"}`)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("api token cannot impersonate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userCtx := impersonateAdminContext()
+		userCtx.AuthType = types.AuthTypeToken
+
+		rec := serveImpersonate(t, mockFlectoService.NewMockAuthService(ctrl), userCtx, `{"username":"target"}`)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("impersonation cannot be chained", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		userCtx := impersonateAdminContext()
+		userCtx.ImpersonatorID = 3
+		userCtx.ImpersonatorUsername = "other-admin"
+
+		rec := serveImpersonate(t, mockFlectoService.NewMockAuthService(ctrl), userCtx, `{"username":"target"}`)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		rec := serveImpersonate(t, mockFlectoService.NewMockAuthService(ctrl), impersonateAdminContext(), `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"VALIDATION_FAILED"`)
+	})
+
+	errorCases := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantBody string
+	}{
+		{name: "user not found", err: service.ErrUserNotFound, wantCode: http.StatusNotFound, wantBody: `"code":"NOT_FOUND"`},
+		{name: "user inactive", err: service.ErrUserInactive, wantCode: http.StatusForbidden, wantBody: `"code":"USER_INACTIVE"`},
+		{name: "self impersonation", err: service.ErrImpersonateSelf, wantCode: http.StatusBadRequest, wantBody: "Cannot impersonate yourself"},
+		{name: "internal error", err: errors.New("database error"), wantCode: http.StatusInternalServerError, wantBody: `"code":"INTERNAL"`},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAuthService := mockFlectoService.NewMockAuthService(ctrl)
+			mockAuthService.EXPECT().
+				Impersonate(gomock.Any(), gomock.Any(), "target").
+				Return(nil, nil, tt.err)
+
+			rec := serveImpersonate(t, mockAuthService, impersonateAdminContext(), `{"username":"target"}`)
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
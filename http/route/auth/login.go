@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/http/route"
 	"github.com/flectolab/flecto-manager/service"
 	"github.com/flectolab/flecto-manager/types"
 	"github.com/labstack/echo/v4"
@@ -14,37 +16,22 @@ func GetLogin(ctx *appContext.Context, authService service.AuthService) func(ech
 	return func(c echo.Context) error {
 		var req types.LoginRequest
 		if err := c.Bind(&req); err != nil {
-			return c.JSON(http.StatusBadRequest, types.ErrorResponse{
-				Error:   "invalid_request",
-				Message: "Invalid request body",
-			})
+			return route.ErrorJSON(c, http.StatusBadRequest, flectoErrors.New(flectoErrors.CodeInvalidRequest, "Invalid request body"))
 		}
 
 		if err := ctx.Validator.Struct(&req); err != nil {
-			return c.JSON(http.StatusBadRequest, types.ErrorResponse{
-				Error:   "validation_error",
-				Message: err.Error(),
-			})
+			return route.ErrorJSON(c, http.StatusBadRequest, err)
 		}
 
 		user, tokens, err := authService.Login(c.Request().Context(), &req)
 		if err != nil {
 			switch {
 			case errors.Is(err, service.ErrInvalidCredentials):
-				return c.JSON(http.StatusUnauthorized, types.ErrorResponse{
-					Error:   "invalid_credentials",
-					Message: "Invalid email or password",
-				})
+				return route.ErrorJSON(c, http.StatusUnauthorized, flectoErrors.New(flectoErrors.CodeInvalidCredentials, "Invalid email or password"))
 			case errors.Is(err, service.ErrUserNotFound):
-				return c.JSON(http.StatusForbidden, types.ErrorResponse{
-					Error:   "user_not_exist",
-					Message: "User account not exist",
-				})
+				return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeForbidden, "User account not exist"))
 			default:
-				return c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-					Error:   "internal_error",
-					Message: "Authentication failed",
-				})
+				return route.ErrorJSON(c, http.StatusInternalServerError, flectoErrors.New(flectoErrors.CodeInternal, "Authentication failed"))
 			}
 		}
 
@@ -71,7 +71,7 @@ func TestGetLogin(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"invalid_request"`)
+		assert.Contains(t, rec.Body.String(), `"code":"INVALID_REQUEST"`)
 	})
 
 	t.Run("validation error - missing username", func(t *testing.T) {
@@ -92,7 +92,7 @@ func TestGetLogin(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"validation_error"`)
+		assert.Contains(t, rec.Body.String(), `"code":"VALIDATION_FAILED"`)
 	})
 
 	t.Run("validation error - missing password", func(t *testing.T) {
@@ -113,7 +113,7 @@ func TestGetLogin(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"validation_error"`)
+		assert.Contains(t, rec.Body.String(), `"code":"VALIDATION_FAILED"`)
 	})
 
 	t.Run("invalid credentials", func(t *testing.T) {
@@ -138,7 +138,7 @@ func TestGetLogin(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"invalid_credentials"`)
+		assert.Contains(t, rec.Body.String(), `"code":"INVALID_CREDENTIALS"`)
 	})
 
 	t.Run("user not found", func(t *testing.T) {
@@ -163,7 +163,7 @@ func TestGetLogin(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusForbidden, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"user_not_exist"`)
+		assert.Contains(t, rec.Body.String(), `"code":"FORBIDDEN"`)
 	})
 
 	t.Run("internal error", func(t *testing.T) {
@@ -188,6 +188,6 @@ func TestGetLogin(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"internal_error"`)
+		assert.Contains(t, rec.Body.String(), `"code":"INTERNAL"`)
 	})
 }
@@ -5,8 +5,9 @@ import (
 
 	"github.com/flectolab/flecto-manager/auth"
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/http/route"
 	"github.com/flectolab/flecto-manager/service"
-	"github.com/flectolab/flecto-manager/types"
 	"github.com/labstack/echo/v4"
 )
 
@@ -14,11 +15,13 @@ func GetLogout(ctx *appContext.Context, authService service.AuthService) func(ec
 	return func(c echo.Context) error {
 		userCtx := auth.GetUser(c.Request().Context())
 
+		// an impersonation token has no refresh token, the user's own sessions stay valid
+		if userCtx.IsImpersonated() {
+			return c.NoContent(http.StatusNoContent)
+		}
+
 		if err := authService.Logout(c.Request().Context(), userCtx.UserID); err != nil {
-			return c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-				Error:   "internal_error",
-				Message: "Logout failed",
-			})
+			return route.ErrorJSON(c, http.StatusInternalServerError, flectoErrors.New(flectoErrors.CodeInternal, "Logout failed"))
 		}
 
 		return c.NoContent(http.StatusNoContent)
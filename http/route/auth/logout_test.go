@@ -72,6 +72,28 @@ func TestGetLogout(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"internal_error"`)
+		assert.Contains(t, rec.Body.String(), `"code":"INTERNAL"`)
+	})
+
+	t.Run("impersonation keeps the user sessions", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := appContext.TestContext(nil)
+		mockAuthService := mockFlectoService.NewMockAuthService(ctrl)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/auth/logout", nil)
+		userCtx := &flectoAuth.UserContext{UserID: 2, Username: "target", ImpersonatorID: 1, ImpersonatorUsername: "admin"}
+		req = req.WithContext(flectoAuth.SetUserContext(req.Context(), userCtx))
+
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := GetLogout(ctx, mockAuthService)
+		err := handler(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
 	})
 }
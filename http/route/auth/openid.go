@@ -7,7 +7,8 @@ import (
 	"github.com/flectolab/flecto-manager/auth/openid"
 	"github.com/flectolab/flecto-manager/config"
 	appContext "github.com/flectolab/flecto-manager/context"
-	"github.com/flectolab/flecto-manager/types"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/http/route"
 	"github.com/labstack/echo/v4"
 )
 
@@ -31,10 +32,7 @@ func GetOpenIDConfig(ctx *appContext.Context, cfg *config.OpenIDConfig, openidSe
 		authURL, state, err := openidService.BeginAuth()
 		if err != nil {
 			ctx.Logger.Error("failed to generate OpenID auth URL", "error", err)
-			return c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-				Error:   "internal_error",
-				Message: "Failed to generate auth URL",
-			})
+			return route.ErrorJSON(c, http.StatusInternalServerError, flectoErrors.New(flectoErrors.CodeInternal, "Failed to generate auth URL"))
 		}
 
 		setStateCookie(c, state)
@@ -126,4 +124,4 @@ func clearStateCookie(c echo.Context) {
 		MaxAge:   -1,
 	}
 	c.SetCookie(cookie)
-}
\ No newline at end of file
+}
@@ -112,7 +112,7 @@ func TestGetOpenIDConfig(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"internal_error"`)
+		assert.Contains(t, rec.Body.String(), `"code":"INTERNAL"`)
 	})
 }
 
@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/http/route"
 	flectoJwt "github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/service"
 	"github.com/flectolab/flecto-manager/types"
@@ -16,17 +18,11 @@ func GetRefresh(ctx *appContext.Context, authService service.AuthService) func(e
 	return func(c echo.Context) error {
 		var req types.RefreshRequest
 		if err := c.Bind(&req); err != nil {
-			return c.JSON(http.StatusBadRequest, types.ErrorResponse{
-				Error:   "invalid_request",
-				Message: "Invalid request body",
-			})
+			return route.ErrorJSON(c, http.StatusBadRequest, flectoErrors.New(flectoErrors.CodeInvalidRequest, "Invalid request body"))
 		}
 
 		if err := ctx.Validator.Struct(&req); err != nil {
-			return c.JSON(http.StatusBadRequest, types.ErrorResponse{
-				Error:   "validation_error",
-				Message: err.Error(),
-			})
+			return route.ErrorJSON(c, http.StatusBadRequest, err)
 		}
 
 		// Parse and validate refresh token
@@ -34,38 +30,25 @@ func GetRefresh(ctx *appContext.Context, authService service.AuthService) func(e
 			return []byte(ctx.Config.Auth.JWT.Secret), nil
 		})
 		if err != nil {
-			return c.JSON(http.StatusUnauthorized, types.ErrorResponse{
-				Error:   "invalid_token",
-				Message: "Invalid or expired refresh token",
-			})
+			return route.ErrorJSON(c, http.StatusUnauthorized, flectoErrors.New(flectoErrors.CodeUnauthenticated, "Invalid or expired refresh token"))
 		}
 
 		claims, ok := token.Claims.(*flectoJwt.Claims)
 		if !ok || !token.Valid {
-			return c.JSON(http.StatusUnauthorized, types.ErrorResponse{
-				Error:   "invalid_token",
-				Message: "Invalid refresh token",
-			})
+			return route.ErrorJSON(c, http.StatusUnauthorized, flectoErrors.New(flectoErrors.CodeUnauthenticated, "Invalid refresh token"))
 		}
 
 		user, tokens, err := authService.RefreshTokens(c.Request().Context(), req.RefreshToken, claims)
 		if err != nil {
 			switch {
 			case errors.Is(err, service.ErrInvalidCredentials):
-				return c.JSON(http.StatusUnauthorized, types.ErrorResponse{
-					Error:   "invalid_token",
-					Message: "Refresh token has been revoked",
-				})
+				return route.ErrorJSON(c, http.StatusUnauthorized, flectoErrors.New(flectoErrors.CodeUnauthenticated, "Refresh token has been revoked"))
+			case errors.Is(err, service.ErrRefreshTokenReused):
+				return route.ErrorJSON(c, http.StatusUnauthorized, flectoErrors.New(flectoErrors.CodeUnauthenticated, "Refresh token has already been used, the session has been revoked"))
 			case errors.Is(err, service.ErrUserInactive):
-				return c.JSON(http.StatusForbidden, types.ErrorResponse{
-					Error:   "user_inactive",
-					Message: "User account is inactive",
-				})
+				return route.ErrorJSON(c, http.StatusForbidden, flectoErrors.New(flectoErrors.CodeUserInactive, "User account is inactive"))
 			default:
-				return c.JSON(http.StatusInternalServerError, types.ErrorResponse{
-					Error:   "internal_error",
-					Message: "Token refresh failed",
-				})
+				return route.ErrorJSON(c, http.StatusInternalServerError, flectoErrors.New(flectoErrors.CodeInternal, "Token refresh failed"))
 			}
 		}
 
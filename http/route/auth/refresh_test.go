@@ -104,7 +104,7 @@ func TestGetRefresh(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"invalid_request"`)
+		assert.Contains(t, rec.Body.String(), `"code":"INVALID_REQUEST"`)
 	})
 
 	t.Run("validation error - missing refresh token", func(t *testing.T) {
@@ -125,7 +125,7 @@ func TestGetRefresh(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"validation_error"`)
+		assert.Contains(t, rec.Body.String(), `"code":"VALIDATION_FAILED"`)
 	})
 
 	t.Run("invalid token format", func(t *testing.T) {
@@ -147,7 +147,7 @@ func TestGetRefresh(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"invalid_token"`)
+		assert.Contains(t, rec.Body.String(), `"code":"UNAUTHENTICATED"`)
 		assert.Contains(t, rec.Body.String(), "Invalid or expired refresh token")
 	})
 
@@ -172,7 +172,7 @@ func TestGetRefresh(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"invalid_token"`)
+		assert.Contains(t, rec.Body.String(), `"code":"UNAUTHENTICATED"`)
 	})
 
 	t.Run("wrong secret", func(t *testing.T) {
@@ -196,7 +196,7 @@ func TestGetRefresh(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"invalid_token"`)
+		assert.Contains(t, rec.Body.String(), `"code":"UNAUTHENTICATED"`)
 	})
 
 	t.Run("token revoked (invalid credentials)", func(t *testing.T) {
@@ -224,10 +224,39 @@ func TestGetRefresh(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusUnauthorized, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"invalid_token"`)
+		assert.Contains(t, rec.Body.String(), `"code":"UNAUTHENTICATED"`)
 		assert.Contains(t, rec.Body.String(), "Refresh token has been revoked")
 	})
 
+	t.Run("token reused", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := appContext.TestContext(nil)
+		ctx.Config.Auth.JWT.Secret = secret
+		mockAuthService := mockFlectoService.NewMockAuthService(ctrl)
+
+		refreshToken := createTestRefreshToken(t, secret, 1, "test@example.com", false)
+
+		mockAuthService.EXPECT().
+			RefreshTokens(gomock.Any(), refreshToken, gomock.Any()).
+			Return(nil, nil, service.ErrRefreshTokenReused)
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(`{"refreshToken":"`+refreshToken+`"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		handler := GetRefresh(ctx, mockAuthService)
+		err := handler(c)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"code":"UNAUTHENTICATED"`)
+		assert.Contains(t, rec.Body.String(), "the session has been revoked")
+	})
+
 	t.Run("user inactive", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -253,7 +282,7 @@ func TestGetRefresh(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusForbidden, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"user_inactive"`)
+		assert.Contains(t, rec.Body.String(), `"code":"USER_INACTIVE"`)
 	})
 
 	t.Run("internal error", func(t *testing.T) {
@@ -281,7 +310,7 @@ func TestGetRefresh(t *testing.T) {
 
 		require.NoError(t, err)
 		assert.Equal(t, http.StatusInternalServerError, rec.Code)
-		assert.Contains(t, rec.Body.String(), `"error":"internal_error"`)
+		assert.Contains(t, rec.Body.String(), `"code":"INTERNAL"`)
 	})
 }
 
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+// SubjectType is the kind of subject authenticated by a request
+type SubjectType string
+
+const (
+	SubjectTypeUser  SubjectType = "user"
+	SubjectTypeToken SubjectType = "token"
+)
+
+type WhoamiResponse struct {
+	SubjectType SubjectType    `json:"subjectType"`
+	AuthType    types.AuthType `json:"authType"`
+	Username    string         `json:"username"`
+	UserID      int64          `json:"userId,omitempty"`
+	// ImpersonatedBy is the administrator acting as the user
+	ImpersonatedBy string `json:"impersonatedBy,omitempty"`
+	// ExpiresAt is the expiration of the token sent, null when it does not expire
+	ExpiresAt   *time.Time        `json:"expiresAt"`
+	Permissions WhoamiPermissions `json:"permissions"`
+}
+
+// WhoamiPermissions are the permissions resolved for the request, from all the roles of the subject
+type WhoamiPermissions struct {
+	Resources []WhoamiResourcePermission `json:"resources"`
+	Admin     []WhoamiAdminPermission    `json:"admin"`
+}
+
+type WhoamiResourcePermission struct {
+	Namespace string                 `json:"namespace"`
+	Project   string                 `json:"project"`
+	Resource  model.ResourceType     `json:"resource"`
+	Action    model.ActionType       `json:"action"`
+	Effect    model.PermissionEffect `json:"effect"`
+}
+
+type WhoamiAdminPermission struct {
+	Namespace string                 `json:"namespace"`
+	Section   model.SectionType      `json:"section"`
+	Action    model.ActionType       `json:"action"`
+	Effect    model.PermissionEffect `json:"effect"`
+}
+
+// GetWhoami returns the subject authenticated by the request with its resolved permissions, so that
+// an automation can check it has the rights it needs before running its operations
+func GetWhoami() func(echo.Context) error {
+	return func(c echo.Context) error {
+		userCtx := auth.GetUser(c.Request().Context())
+
+		response := WhoamiResponse{
+			SubjectType:    SubjectTypeUser,
+			AuthType:       userCtx.AuthType,
+			Username:       userCtx.Username,
+			UserID:         userCtx.UserID,
+			ImpersonatedBy: userCtx.ImpersonatorUsername,
+			ExpiresAt:      userCtx.ExpiresAt,
+			Permissions: WhoamiPermissions{
+				Resources: []WhoamiResourcePermission{},
+				Admin:     []WhoamiAdminPermission{},
+			},
+		}
+		if userCtx.AuthType == types.AuthTypeToken || userCtx.IsProjectAPIKey() {
+			response.SubjectType = SubjectTypeToken
+		}
+
+		if userCtx.SubjectPermissions != nil {
+			for _, p := range userCtx.SubjectPermissions.Resources {
+				response.Permissions.Resources = append(response.Permissions.Resources, WhoamiResourcePermission{
+					Namespace: p.Namespace,
+					Project:   p.Project,
+					Resource:  p.Resource,
+					Action:    p.Action,
+					Effect:    model.EffectOrDefault(&p.Effect),
+				})
+			}
+			for _, p := range userCtx.SubjectPermissions.Admin {
+				response.Permissions.Admin = append(response.Permissions.Admin, WhoamiAdminPermission{
+					Namespace: model.NamespaceOrAll(&p.Namespace),
+					Section:   p.Section,
+					Action:    p.Action,
+					Effect:    model.EffectOrDefault(&p.Effect),
+				})
+			}
+		}
+
+		return c.JSON(http.StatusOK, response)
+	}
+}
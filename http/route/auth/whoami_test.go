@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	flectoAuth "github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveWhoami(t *testing.T, userCtx *flectoAuth.UserContext) WhoamiResponse {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/auth/whoami", nil)
+	req = req.WithContext(flectoAuth.SetUserContext(req.Context(), userCtx))
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NoError(t, GetWhoami()(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response WhoamiResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return response
+}
+
+func TestGetWhoami(t *testing.T) {
+	t.Run("user", func(t *testing.T) {
+		expiresAt := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+		response := serveWhoami(t, &flectoAuth.UserContext{
+			UserID:               2,
+			Username:             "jane",
+			AuthType:             types.AuthTypeImpersonation,
+			ImpersonatorUsername: "admin",
+			ExpiresAt:            &expiresAt,
+			SubjectPermissions: &model.SubjectPermissions{
+				Resources: []model.ResourcePermission{{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeRedirect, Action: model.ActionWrite}},
+				Admin:     []model.AdminPermission{{Section: model.AdminSectionProjects, Action: model.ActionRead, Effect: model.PermissionEffectDeny}},
+			},
+		})
+
+		assert.Equal(t, SubjectTypeUser, response.SubjectType)
+		assert.Equal(t, types.AuthTypeImpersonation, response.AuthType)
+		assert.Equal(t, "jane", response.Username)
+		assert.Equal(t, int64(2), response.UserID)
+		assert.Equal(t, "admin", response.ImpersonatedBy)
+		require.NotNil(t, response.ExpiresAt)
+		assert.True(t, expiresAt.Equal(*response.ExpiresAt))
+		assert.Equal(t, []WhoamiResourcePermission{
+			{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeRedirect, Action: model.ActionWrite, Effect: model.PermissionEffectAllow},
+		}, response.Permissions.Resources)
+		assert.Equal(t, []WhoamiAdminPermission{
+			{Namespace: "*", Section: model.AdminSectionProjects, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+		}, response.Permissions.Admin)
+	})
+
+	t.Run("token without expiry nor permissions", func(t *testing.T) {
+		response := serveWhoami(t, &flectoAuth.UserContext{
+			Username:           "ci",
+			AuthType:           types.AuthTypeToken,
+			SubjectPermissions: &model.SubjectPermissions{},
+		})
+
+		assert.Equal(t, SubjectTypeToken, response.SubjectType)
+		assert.Equal(t, "ci", response.Username)
+		assert.Nil(t, response.ExpiresAt)
+		assert.Empty(t, response.Permissions.Resources)
+		assert.NotNil(t, response.Permissions.Resources)
+		assert.NotNil(t, response.Permissions.Admin)
+	})
+
+	t.Run("project API key", func(t *testing.T) {
+		key := &model.ProjectAPIKey{NamespaceCode: "ns1", ProjectCode: "proj1", Name: "ci", Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead}}
+		response := serveWhoami(t, &flectoAuth.UserContext{
+			Username:           key.GetSubject(),
+			AuthType:           types.AuthTypeProjectAPIKey,
+			SubjectPermissions: key.Permissions(),
+		})
+
+		assert.Equal(t, SubjectTypeToken, response.SubjectType)
+		assert.Equal(t, types.AuthTypeProjectAPIKey, response.AuthType)
+		assert.Len(t, response.Permissions.Resources, 2)
+	})
+}
@@ -0,0 +1,39 @@
+package route
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/labstack/echo/v4"
+)
+
+// BodyLimitMiddleware answers 413 Request Entity Too Large to the requests with a body larger than limit bytes, the
+// body being cut at the limit when its length is not announced. The multipart forms sent to the uploadPaths, the
+// uploads of the imports, are limited to uploadLimit bytes instead.
+func BodyLimitMiddleware(limit, uploadLimit int64, uploadPaths ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Body == nil {
+				return next(c)
+			}
+			max := limit
+			if strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) && slices.Contains(uploadPaths, c.Path()) {
+				max = uploadLimit
+			}
+			if req.ContentLength > max {
+				return bodyTooLarge(max)
+			}
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, max)
+			return next(c)
+		}
+	}
+}
+
+// bodyTooLarge is the error of a request body larger than limit bytes
+func bodyTooLarge(limit int64) *flectoErrors.Error {
+	return flectoErrors.Newf(flectoErrors.CodeSizeLimitExceeded, "request body is larger than %d bytes", limit).
+		WithDetails(map[string]any{"limit": limit})
+}
@@ -0,0 +1,80 @@
+package route
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	e.Use(BodyLimitMiddleware(16, 128, "/uploads"))
+	handler := func(c echo.Context) error {
+		if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEMultipartForm) {
+			if _, err := c.MultipartForm(); err != nil {
+				return err
+			}
+			return c.NoContent(http.StatusNoContent)
+		}
+		var item map[string]any
+		if err := c.Bind(&item); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+	e.POST("/items", handler)
+	e.POST("/uploads", handler)
+
+	sendTo := func(path, contentType string, body io.Reader) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, body)
+		req.Header.Set(echo.HeaderContentType, contentType)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	send := func(contentType string, body io.Reader) *httptest.ResponseRecorder {
+		return sendTo("/items", contentType, body)
+	}
+	form := "--x\r\nContent-Disposition: form-data; name=\"name\"\r\n\r\nabcdefghijklmnop\r\n--x--\r\n"
+	tooLarge := `{"code":"SIZE_LIMIT_EXCEEDED","message":"request body is larger than 16 bytes","details":{"limit":16}}`
+
+	t.Run("under the limit", func(t *testing.T) {
+		rec := send(echo.MIMEApplicationJSON, strings.NewReader(`{"name":"a"}`))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("announced length over the limit", func(t *testing.T) {
+		rec := send(echo.MIMEApplicationJSON, strings.NewReader(`{"name":"abcdefghijklmnop"}`))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.JSONEq(t, tooLarge, rec.Body.String())
+	})
+
+	t.Run("unannounced length over the limit", func(t *testing.T) {
+		// A reader of unknown length, as a chunked body
+		rec := send(echo.MIMEApplicationJSON, io.MultiReader(strings.NewReader(`{"name":"abcdefghijklmnop"}`)))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.JSONEq(t, tooLarge, rec.Body.String())
+	})
+
+	t.Run("multipart form to an upload path", func(t *testing.T) {
+		rec := sendTo("/uploads", echo.MIMEMultipartForm+"; boundary=x", strings.NewReader(form))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = sendTo("/uploads", echo.MIMEMultipartForm+"; boundary=x", strings.NewReader(form+strings.Repeat(" ", 128)))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.JSONEq(t, `{"code":"SIZE_LIMIT_EXCEEDED","message":"request body is larger than 128 bytes","details":{"limit":128}}`, rec.Body.String())
+	})
+
+	t.Run("multipart form to another path", func(t *testing.T) {
+		rec := send(echo.MIMEMultipartForm+"; boundary=x", strings.NewReader(form))
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.JSONEq(t, tooLarge, rec.Body.String())
+	})
+}
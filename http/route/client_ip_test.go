@@ -0,0 +1,44 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientIPExtractor(t *testing.T) {
+	request := func(remoteAddr, forwardedFor string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+		req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+		return req
+	}
+
+	t.Run("without trusted proxy", func(t *testing.T) {
+		extractor, err := ClientIPExtractor(nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "192.0.2.1", extractor(request("192.0.2.1:1234", "198.51.100.1")))
+		assert.Equal(t, "10.0.0.1", extractor(request("10.0.0.1:1234", "198.51.100.1")))
+	})
+
+	t.Run("with trusted proxies", func(t *testing.T) {
+		extractor, err := ClientIPExtractor([]string{"10.0.0.0/8", "192.0.2.10"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "198.51.100.1", extractor(request("10.1.2.3:1234", "198.51.100.1")))
+		assert.Equal(t, "198.51.100.1", extractor(request("192.0.2.10:1234", "198.51.100.1")))
+		assert.Equal(t, "192.0.2.11", extractor(request("192.0.2.11:1234", "198.51.100.1")), "the other addresses are not trusted")
+		assert.Equal(t, "127.0.0.1", extractor(request("127.0.0.1:1234", "198.51.100.1")), "the loopback is not trusted by default")
+	})
+
+	t.Run("invalid trusted proxy", func(t *testing.T) {
+		_, err := ClientIPExtractor([]string{"10.0.0.0/40"})
+		assert.ErrorContains(t, err, "invalid trusted proxy '10.0.0.0/40'")
+	})
+}
@@ -0,0 +1,68 @@
+package route
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/i18n"
+	"github.com/labstack/echo/v4"
+)
+
+// ErrorHandler answers the errors returned by the handlers with an errors.Error. An echo.HTTPError keeps its status,
+// its code being the one of the error it wraps, or the code of its status. The other errors are answered with the
+// status of their code. The message of the server errors without code is hidden from the clients and logged. The
+// errors are localized in the language of the request.
+func ErrorHandler(logger *slog.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			// The response has started, a streamed response is ended early
+			logger.WarnContext(c.Request().Context(), "response interrupted", "method", c.Request().Method, "route", c.Path(), "error", err)
+			return
+		}
+
+		status, apiErr, coded := httpError(err)
+		if !coded && status >= http.StatusInternalServerError {
+			logger.ErrorContext(c.Request().Context(), "request failed", "method", c.Request().Method, "route", c.Path(), "error", err)
+			apiErr = flectoErrors.New(flectoErrors.CodeInternal, http.StatusText(status))
+		}
+		apiErr = i18n.Localize(c.Request().Context(), apiErr)
+
+		if c.Request().Method == http.MethodHead {
+			err = c.NoContent(status)
+		} else {
+			err = c.JSON(status, apiErr)
+		}
+		if err != nil {
+			logger.ErrorContext(c.Request().Context(), "failed to send error response", "error", err)
+		}
+	}
+}
+
+// httpError returns the status and the error answered for err, coded being false when err wraps no errors.Error
+func httpError(err error) (status int, apiErr *flectoErrors.Error, coded bool) {
+	// A body cut by BodyLimitMiddleware, whatever the error its reader was wrapped in
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return http.StatusRequestEntityTooLarge, bodyTooLarge(maxBytesErr.Limit), true
+	}
+
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) {
+		apiErr = flectoErrors.From(err)
+		return apiErr.Code.HTTPStatus(), apiErr, flectoErrors.As(err) != nil
+	}
+
+	cause, ok := httpErr.Message.(error)
+	if !ok {
+		cause = errors.New(fmt.Sprint(httpErr.Message))
+	}
+	apiErr = flectoErrors.From(cause)
+	coded = flectoErrors.As(cause) != nil
+	if !coded && apiErr.Code == flectoErrors.CodeInternal {
+		apiErr = flectoErrors.New(flectoErrors.StatusCode(httpErr.Code), apiErr.Message)
+	}
+	return httpErr.Code, apiErr, coded
+}
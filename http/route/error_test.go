@@ -0,0 +1,88 @@
+package route
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorHandler(t *testing.T) {
+	errNothingToPublish := flectoErrors.New(flectoErrors.CodeNothingToPublish, "nothing to publish")
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "coded error",
+			err:        fmt.Errorf("%w: ns/proj", errNothingToPublish),
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"code":"NOTHING_TO_PUBLISH","message":"nothing to publish: ns/proj"}`,
+		},
+		{
+			name:       "http error wrapping a coded error",
+			err:        echo.NewHTTPError(http.StatusBadRequest, errNothingToPublish.WithField("project")),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":"NOTHING_TO_PUBLISH","message":"nothing to publish","field":"project"}`,
+		},
+		{
+			name:       "http error",
+			err:        echo.NewHTTPError(http.StatusBadRequest, errors.New("name is required")),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"code":"INVALID_REQUEST","message":"name is required"}`,
+		},
+		{
+			name:       "route not found",
+			err:        echo.ErrNotFound,
+			wantStatus: http.StatusNotFound,
+			wantBody:   `{"code":"NOT_FOUND","message":"Not Found"}`,
+		},
+		{
+			name:       "uncoded error",
+			err:        errors.New("connection refused"),
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"code":"INTERNAL","message":"Internal Server Error"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = ErrorHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+			e.GET("/", func(c echo.Context) error { return tt.err })
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.JSONEq(t, tt.wantBody, rec.Body.String())
+		})
+	}
+}
+
+func TestErrorHandler_Committed(t *testing.T) {
+	var logs bytes.Buffer
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(slog.New(slog.NewTextHandler(&logs, nil)))
+	e.GET("/", func(c echo.Context) error {
+		if err := c.String(http.StatusOK, "partial"); err != nil {
+			return err
+		}
+		return errors.New("connection reset")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "partial", rec.Body.String())
+	assert.Contains(t, logs.String(), `msg="response interrupted"`)
+	assert.Contains(t, logs.String(), `error="connection reset"`)
+}
@@ -0,0 +1,32 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+// GetLiveness returns the liveness report, with a 503 status when a background worker is stuck
+func GetLiveness(probeService service.ProbeService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		return reportJSON(c, probeService.Liveness(c.Request().Context()))
+	}
+}
+
+// GetReadiness returns the readiness report, with a 503 status when a database is unreachable
+// or not migrated, or when a background worker is stuck
+func GetReadiness(probeService service.ProbeService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		return reportJSON(c, probeService.Readiness(c.Request().Context()))
+	}
+}
+
+func reportJSON(c echo.Context, report *types.HealthReport) error {
+	status := http.StatusOK
+	if report.Status != types.HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	return c.JSON(status, report)
+}
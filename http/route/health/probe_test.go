@@ -0,0 +1,58 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetLiveness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockProbeService := mockFlectoService.NewMockProbeService(ctrl)
+	mockProbeService.EXPECT().Liveness(gomock.Any()).Return(&types.HealthReport{
+		Status: types.HealthStatusOK,
+		Checks: map[string]types.HealthCheck{"workers": {Status: types.HealthStatusOK}},
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NoError(t, GetLiveness(mockProbeService)(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var report types.HealthReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, types.HealthStatusOK, report.Status)
+	assert.Equal(t, types.HealthStatusOK, report.Checks["workers"].Status)
+}
+
+func TestGetReadiness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockProbeService := mockFlectoService.NewMockProbeService(ctrl)
+	mockProbeService.EXPECT().Readiness(gomock.Any()).Return(&types.HealthReport{
+		Status: types.HealthStatusFail,
+		Checks: map[string]types.HealthCheck{
+			"database":   {Status: types.HealthStatusOK},
+			"migrations": {Status: types.HealthStatusFail, Error: "pending migrations"},
+		},
+	})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	require.NoError(t, GetReadiness(mockProbeService)(c))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"status":"fail","checks":{"database":{"status":"ok"},"migrations":{"status":"fail","error":"pending migrations"}}}`, rec.Body.String())
+}
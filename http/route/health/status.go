@@ -0,0 +1,29 @@
+package health
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+// GetStatus returns the public status report, with a 503 status when a subsystem fails. The clients and proxies
+// may cache the report for cacheTTL, as the checks are not run again before.
+func GetStatus(statusService service.StatusService, cacheTTL time.Duration) func(echo.Context) error {
+	cacheControl := "no-cache"
+	if seconds := int(cacheTTL.Seconds()); seconds > 0 {
+		cacheControl = fmt.Sprintf("public, max-age=%d", seconds)
+	}
+	return func(c echo.Context) error {
+		report := statusService.Status(c.Request().Context())
+		c.Response().Header().Set(echo.HeaderCacheControl, cacheControl)
+		status := http.StatusOK
+		if report.Status != types.HealthStatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		return c.JSON(status, report)
+	}
+}
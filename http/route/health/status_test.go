@@ -0,0 +1,53 @@
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetStatus(t *testing.T) {
+	t.Run("ok", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStatusService := mockFlectoService.NewMockStatusService(ctrl)
+		mockStatusService.EXPECT().Status(gomock.Any()).Return(&types.StatusReport{Status: types.HealthStatusOK})
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		require.NoError(t, GetStatus(mockStatusService, 10*time.Second)(c))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "public, max-age=10", rec.Header().Get(echo.HeaderCacheControl))
+		assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockStatusService := mockFlectoService.NewMockStatusService(ctrl)
+		mockStatusService.EXPECT().Status(gomock.Any()).Return(&types.StatusReport{
+			Status:     types.HealthStatusFail,
+			Uptime:     3600,
+			Subsystems: map[string]types.HealthCheck{"database": {Status: types.HealthStatusFail}},
+		})
+
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+
+		require.NoError(t, GetStatus(mockStatusService, 0)(c))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Equal(t, "no-cache", rec.Header().Get(echo.HeaderCacheControl))
+		assert.JSONEq(t, `{"status":"fail","uptime":3600,"subsystems":{"database":{"status":"fail"}}}`, rec.Body.String())
+	})
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"github.com/flectolab/flecto-manager/i18n"
+	"github.com/labstack/echo/v4"
+)
+
+// LanguageMiddleware sets the language the errors of the requests are localized in, negotiated from their
+// Accept-Language header. The language of the preference of the authenticated users replaces it.
+func LanguageMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			lang := i18n.Negotiate(req.Header.Get("Accept-Language"))
+			c.SetRequest(req.WithContext(i18n.WithLanguage(req.Context(), lang)))
+			return next(c)
+		}
+	}
+}
+
+// ErrorJSON answers the error with the status, in the language of the request
+func ErrorJSON(c echo.Context, status int, err error) error {
+	return c.JSON(status, i18n.Localize(c.Request().Context(), err))
+}
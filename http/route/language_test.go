@@ -0,0 +1,39 @@
+package route
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLanguageMiddleware(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = ErrorHandler(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	e.Use(LanguageMiddleware())
+	e.GET("/", func(c echo.Context) error {
+		return flectoErrors.New(flectoErrors.CodeForbidden, "user jdoe has no permission")
+	})
+
+	t.Run("negotiated", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Language", "fr-FR,fr;q=0.9,en;q=0.8")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+		assert.JSONEq(t, `{"code":"FORBIDDEN","message":"Vous n'avez pas la permission d'effectuer cette action"}`, rec.Body.String())
+	})
+
+	t.Run("default", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.JSONEq(t, `{"code":"FORBIDDEN","message":"user jdoe has no permission"}`, rec.Body.String())
+	})
+}
@@ -0,0 +1,19 @@
+package route
+
+import (
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/labstack/echo/v4"
+)
+
+// NamespaceMiddleware runs the database statements of the request against the database of its namespace
+func NamespaceMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if namespaceCode := c.Param(NamespaceCodeKey); namespaceCode != "" {
+				req := c.Request()
+				c.SetRequest(req.WithContext(database.WithNamespace(req.Context(), namespaceCode)))
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/labstack/echo/v4"
+)
+
+// PrimaryMiddleware runs the reads of the requests changing data against the primary database,
+// so that they see their own writes instead of reading from a lagging replica
+func PrimaryMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+			default:
+				req := c.Request()
+				c.SetRequest(req.WithContext(database.WithPrimary(req.Context())))
+			}
+			return next(c)
+		}
+	}
+}
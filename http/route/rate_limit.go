@@ -0,0 +1,28 @@
+package route
+
+import (
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitExpiry is the time after which the limiter of a client IP without request is forgotten
+const rateLimitExpiry = 3 * time.Minute
+
+// RateLimitMiddleware answers 429 Too Many Requests to the client IPs sending more than limit requests per second,
+// burst requests being allowed at once. The client IPs are read by ipExtractor, see ClientIPExtractor. The limiters
+// are kept in memory, each replica of the manager limiting its own requests.
+func RateLimitMiddleware(limit float64, burst int, ipExtractor echo.IPExtractor) echo.MiddlewareFunc {
+	return middleware.RateLimiterWithConfig(middleware.RateLimiterConfig{
+		IdentifierExtractor: func(c echo.Context) (string, error) {
+			return ipExtractor(c.Request()), nil
+		},
+		Store: middleware.NewRateLimiterMemoryStoreWithConfig(middleware.RateLimiterMemoryStoreConfig{
+			Rate:      rate.Limit(limit),
+			Burst:     burst,
+			ExpiresIn: rateLimitExpiry,
+		}),
+	})
+}
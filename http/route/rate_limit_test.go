@@ -0,0 +1,33 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	e := echo.New()
+	e.GET("/status", func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }, RateLimitMiddleware(0.001, 2, echo.ExtractIPDirect()))
+
+	status := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/status", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set(echo.HeaderXForwardedFor, forwardedFor)
+			req.Header.Set(echo.HeaderXRealIP, forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, status("192.0.2.1:1234", ""))
+	assert.Equal(t, http.StatusNoContent, status("192.0.2.1:1234", ""))
+	assert.Equal(t, http.StatusTooManyRequests, status("192.0.2.1:1234", ""))
+	assert.Equal(t, http.StatusTooManyRequests, status("192.0.2.1:1234", "198.51.100.1"), "the forwarded IPs are not trusted")
+	assert.Equal(t, http.StatusNoContent, status("192.0.2.2:1234", ""), "each client IP has its own limit")
+}
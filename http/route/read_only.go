@@ -0,0 +1,25 @@
+package route
+
+import (
+	"net/http"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/labstack/echo/v4"
+)
+
+// ReadOnlyMiddleware rejects the requests changing data while the manager is in read-only mode,
+// the reads being still served
+func ReadOnlyMiddleware(ctx *appContext.Context) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+			default:
+				if err := ctx.ReadOnlyError(); err != nil {
+					return ErrorJSON(c, http.StatusServiceUnavailable, err)
+				}
+			}
+			return next(c)
+		}
+	}
+}
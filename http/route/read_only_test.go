@@ -0,0 +1,36 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+	e := echo.New()
+	handler := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.GET("/resource", handler, ReadOnlyMiddleware(ctx))
+	e.POST("/resource", handler, ReadOnlyMiddleware(ctx))
+
+	serve := func(method string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(method, "/resource", nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodPost).Code)
+
+	ctx.SetReadOnly(config.ReadOnlyConfig{Enabled: true, Message: "database migration"})
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodGet).Code)
+	rec := serve(http.MethodPost)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), string(flectoErrors.CodeReadOnly))
+	assert.Contains(t, rec.Body.String(), "database migration")
+}
@@ -0,0 +1,65 @@
+package route
+
+import (
+	"crypto/rand"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+// requestIDPattern are the request IDs accepted from the clients, the others being replaced by a generated one
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestIDMiddleware assigns an ID to each request, the X-Request-ID header sent by the client or a proxy
+// being kept when valid. The ID is returned in the X-Request-ID header of the response and set in the
+// context of the request, the messages logged with it including the ID.
+func RequestIDMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if !requestIDPattern.MatchString(requestID) {
+				requestID = rand.Text()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+			c.SetRequest(req.WithContext(types.WithRequestID(req.Context(), requestID)))
+			return next(c)
+		}
+	}
+}
+
+// AccessLogMiddleware logs each request once answered with its subject, route, status, latency and
+// number of database statements, the errors being answered by the error handler of echo before
+func AccessLogMiddleware(logger *slog.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			c.SetRequest(c.Request().WithContext(database.WithQueryCounter(c.Request().Context())))
+
+			if err := next(c); err != nil {
+				c.Error(err)
+			}
+
+			ctx := c.Request().Context()
+			status := c.Response().Status
+			level := slog.LevelInfo
+			if status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.Log(ctx, level, "http request",
+				"method", c.Request().Method,
+				"route", c.Path(),
+				"status", status,
+				"subject", types.SubjectFromContext(ctx),
+				"latency", time.Since(start),
+				"db_queries", database.QueryCount(ctx),
+			)
+			return nil
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package route
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIDMiddleware(t *testing.T) {
+	e := echo.New()
+	e.Use(RequestIDMiddleware())
+	e.GET("/test", func(c echo.Context) error {
+		return c.String(http.StatusOK, types.RequestIDFromContext(c.Request().Context()))
+	})
+
+	t.Run("keeps a valid request ID", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(echo.HeaderXRequestID, "proxy-id:42")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "proxy-id:42", rec.Header().Get(echo.HeaderXRequestID))
+		assert.Equal(t, "proxy-id:42", rec.Body.String())
+	})
+
+	t.Run("generates a request ID", func(t *testing.T) {
+		for _, requestID := range []string{"", "invalid id\n"} {
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(echo.HeaderXRequestID, requestID)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			generated := rec.Header().Get(echo.HeaderXRequestID)
+			assert.Regexp(t, requestIDPattern, generated)
+			assert.NotEqual(t, requestID, generated)
+			assert.Equal(t, generated, rec.Body.String())
+		}
+	})
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	logBuffer := &bytes.Buffer{}
+	ctx := appContext.TestContext(logBuffer)
+
+	e := echo.New()
+	e.Use(RequestIDMiddleware(), AccessLogMiddleware(ctx.Logger))
+	e.GET("/projects/:code", func(c echo.Context) error {
+		c.SetRequest(c.Request().WithContext(types.WithSubject(c.Request().Context(), "alice")))
+		return c.NoContent(http.StatusNoContent)
+	})
+	e.GET("/failure", func(c echo.Context) error {
+		return errors.New("failure")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/projects/proj1", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-1")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	logged := logBuffer.String()
+	assert.Contains(t, logged, `level=INFO msg="http request" method=GET route=/projects/:code status=204 subject=alice`)
+	assert.Contains(t, logged, "db_queries=0 request_id=req-1")
+
+	logBuffer.Reset()
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/failure", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, logBuffer.String(), `level=ERROR msg="http request" method=GET route=/failure status=500`)
+}
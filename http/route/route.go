@@ -4,4 +4,5 @@ const (
 	NamespaceCodeKey = "namespaceCode"
 	ProjectCodeKey   = "projectCode"
 	NameKey          = "name"
+	EnvironmentKey   = "environment"
 )
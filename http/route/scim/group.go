@@ -0,0 +1,368 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+// Group is the SCIM representation of a named role, its displayName being the code of the role
+type Group struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []MemberRef `json:"members"`
+	Meta        *Meta       `json:"meta,omitempty"`
+}
+
+var memberFilterPathRegex = regexp.MustCompile(`^(?i:members)\[\s*(?i:value)\s+(?i:eq)\s+"([^"]*)"\s*\]$`)
+
+// GetGroups lists the named roles, filtered by displayName when the filter is set.
+// The members are left out when excludedAttributes is members.
+func GetGroups(permissionChecker *auth.PermissionChecker, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
+			return writeForbidden(c)
+		}
+
+		query := roleService.GetQuery(ctx).Where("type = ?", model.RoleTypeRole)
+		if filter := c.QueryParam("filter"); filter != "" {
+			code, err := parseFilter(filter, "displayName")
+			if err != nil {
+				return writeError(c, http.StatusBadRequest, errorTypeInvalidFilter, err.Error())
+			}
+			query = query.Where("code = ?", code)
+		}
+
+		startIndex, count := pagination(c)
+		list, err := roleService.SearchPaginate(ctx, &commonTypes.PaginationInput{
+			Limit:   types.Ptr(max(count, 1)),
+			Offset:  types.Ptr(startIndex - 1),
+			OrderBy: []commonTypes.SortInput{{Column: "id", Direction: commonTypes.SortASC}},
+		}, query)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+
+		withMembers := !strings.EqualFold(c.QueryParam("excludedAttributes"), "members")
+		resources := make([]Group, 0, len(list.Items))
+		for _, role := range list.Items[:min(count, len(list.Items))] {
+			resource, errGroup := toGroup(c, roleService, &role, withMembers)
+			if errGroup != nil {
+				return writeServiceError(c, errGroup)
+			}
+			resources = append(resources, *resource)
+		}
+		return writeJSON(c, http.StatusOK, ListResponse{
+			Schemas:      []string{SchemaListResponse},
+			TotalResults: list.Total,
+			StartIndex:   startIndex,
+			ItemsPerPage: len(resources),
+			Resources:    resources,
+		})
+	}
+}
+
+func GetGroup(permissionChecker *auth.PermissionChecker, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionRead) {
+			return writeForbidden(c)
+		}
+
+		role, err := findGroup(c, roleService)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		return writeGroup(c, http.StatusOK, roleService, role)
+	}
+}
+
+// PostGroup creates a named role with the members of the group, its permissions being granted by an administrator
+func PostGroup(permissionChecker *auth.PermissionChecker, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
+			return writeForbidden(c)
+		}
+
+		var input Group
+		if err := bindBody(c, &input); err != nil {
+			return writeServiceError(c, err)
+		}
+		userIDs, err := memberIDs(input.Members)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+
+		role, err := roleService.Create(ctx, &model.Role{Code: input.DisplayName, Type: model.RoleTypeRole})
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		if err = updateMembers(ctx, roleService, role, userIDs); err != nil {
+			return writeServiceError(c, err)
+		}
+		return writeGroup(c, http.StatusCreated, roleService, role)
+	}
+}
+
+// PutGroup replaces the displayName and the members of a group
+func PutGroup(permissionChecker *auth.PermissionChecker, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
+			return writeForbidden(c)
+		}
+
+		role, err := findGroup(c, roleService)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		var input Group
+		if err = bindBody(c, &input); err != nil {
+			return writeServiceError(c, err)
+		}
+		userIDs, err := memberIDs(input.Members)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+
+		if role, err = renameGroup(ctx, roleService, role, input.DisplayName); err != nil {
+			return writeServiceError(c, err)
+		}
+		if err = updateMembers(ctx, roleService, role, userIDs); err != nil {
+			return writeServiceError(c, err)
+		}
+		return writeGroup(c, http.StatusOK, roleService, role)
+	}
+}
+
+// PatchGroup applies the operations on the displayName and the members of a group
+func PatchGroup(permissionChecker *auth.PermissionChecker, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
+			return writeForbidden(c)
+		}
+
+		role, err := findGroup(c, roleService)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		var patch PatchRequest
+		if err = bindBody(c, &patch); err != nil {
+			return writeServiceError(c, err)
+		}
+
+		for _, operation := range patch.Operations {
+			if role, err = patchGroup(ctx, roleService, role, operation); err != nil {
+				return writeServiceError(c, err)
+			}
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// DeleteGroup deletes a named role
+func DeleteGroup(permissionChecker *auth.PermissionChecker, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionRoles, model.ActionWrite) {
+			return writeForbidden(c)
+		}
+
+		role, err := findGroup(c, roleService)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		if _, err = roleService.Delete(ctx, role.ID); err != nil {
+			return writeServiceError(c, err)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// findGroup returns the named role of the path, the personal roles of the users and the roles of the tokens not being groups
+func findGroup(c echo.Context, roleService service.RoleService) (*model.Role, error) {
+	id, ok := parseID(c)
+	if !ok {
+		return nil, service.ErrRoleNotFound
+	}
+	role, err := roleService.GetByID(c.Request().Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if role.Type != model.RoleTypeRole {
+		return nil, service.ErrRoleNotFound
+	}
+	return role, nil
+}
+
+// renameGroup changes the code of the role to the displayName when it differs
+func renameGroup(ctx context.Context, roleService service.RoleService, role *model.Role, displayName string) (*model.Role, error) {
+	if displayName == "" || displayName == role.Code {
+		return role, nil
+	}
+	if _, err := roleService.GetByCode(ctx, displayName, model.RoleTypeRole); err == nil {
+		return nil, service.ErrRoleAlreadyExists
+	} else if !errors.Is(err, service.ErrRoleNotFound) {
+		return nil, err
+	}
+	return roleService.Update(ctx, role.ID, model.Role{Code: displayName, Type: model.RoleTypeRole})
+}
+
+// patchGroup applies an operation to the group, returning the group renamed by the operation
+func patchGroup(ctx context.Context, roleService service.RoleService, role *model.Role, operation PatchOperation) (*model.Role, error) {
+	op := strings.ToLower(operation.Op)
+	path := operation.Path
+
+	// Without path, the value holds the attributes to set
+	if path == "" && op != "remove" {
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+			return nil, badRequest(errorTypeInvalidValue, err)
+		}
+		var err error
+		for attribute, value := range attributes {
+			if role, err = patchGroup(ctx, roleService, role, PatchOperation{Op: op, Path: attribute, Value: value}); err != nil {
+				return nil, err
+			}
+		}
+		return role, nil
+	}
+
+	if matches := memberFilterPathRegex.FindStringSubmatch(path); matches != nil && op == "remove" {
+		userIDs, err := memberIDs([]MemberRef{{Value: matches[1]}})
+		if err != nil {
+			return nil, err
+		}
+		return role, removeMembers(ctx, roleService, role, userIDs)
+	}
+
+	switch {
+	case strings.EqualFold(path, "displayName") && op != "remove":
+		var displayName string
+		if err := json.Unmarshal(operation.Value, &displayName); err != nil {
+			return nil, badRequest(errorTypeInvalidValue, err)
+		}
+		return renameGroup(ctx, roleService, role, displayName)
+	case strings.EqualFold(path, "members"):
+		var members []MemberRef
+		if len(operation.Value) > 0 {
+			if err := json.Unmarshal(operation.Value, &members); err != nil {
+				return nil, badRequest(errorTypeInvalidValue, err)
+			}
+		}
+		userIDs, err := memberIDs(members)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case op == "add":
+			return role, addMembers(ctx, roleService, role, userIDs)
+		case op == "remove" && len(members) > 0:
+			return role, removeMembers(ctx, roleService, role, userIDs)
+		case op == "remove" || op == "replace":
+			return role, updateMembers(ctx, roleService, role, userIDs)
+		}
+	}
+	return nil, badRequest(errorTypeInvalidPath, fmt.Errorf("unsupported operation %s on path %s", operation.Op, path))
+}
+
+// addMembers adds the users to the role, the users already members being kept
+func addMembers(ctx context.Context, roleService service.RoleService, role *model.Role, userIDs []int64) error {
+	users, err := roleService.GetRoleUsers(ctx, role.ID)
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	return updateMembers(ctx, roleService, role, userIDs)
+}
+
+// removeMembers removes the users from the role, the users not being members being ignored
+func removeMembers(ctx context.Context, roleService service.RoleService, role *model.Role, userIDs []int64) error {
+	for _, userID := range userIDs {
+		if err := roleService.RemoveUserFromRole(ctx, userID, role.ID); err != nil && !errors.Is(err, service.ErrUserNotInRole) {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateMembers replaces the users of the role, an unknown user being an invalid member rather than a missing resource
+func updateMembers(ctx context.Context, roleService service.RoleService, role *model.Role, userIDs []int64) error {
+	err := roleService.UpdateRoleUsers(ctx, role.ID, userIDs)
+	if errors.Is(err, service.ErrUserNotFound) {
+		return badRequest(errorTypeNoTarget, errors.New("unknown member"))
+	}
+	return err
+}
+
+// memberIDs returns the ids of the users referenced by the members
+func memberIDs(members []MemberRef) ([]int64, error) {
+	userIDs := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member.Value, 10, 64)
+		if err != nil {
+			return nil, badRequest(errorTypeInvalidValue, fmt.Errorf("invalid member %q", member.Value))
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, nil
+}
+
+func writeGroup(c echo.Context, status int, roleService service.RoleService, role *model.Role) error {
+	resource, err := toGroup(c, roleService, role, true)
+	if err != nil {
+		return writeServiceError(c, err)
+	}
+	return writeJSON(c, status, resource)
+}
+
+// toGroup returns the SCIM representation of a named role
+func toGroup(c echo.Context, roleService service.RoleService, role *model.Role, withMembers bool) (*Group, error) {
+	members := make([]MemberRef, 0)
+	if withMembers {
+		users, err := roleService.GetRoleUsers(c.Request().Context(), role.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			members = append(members, MemberRef{Value: strconv.FormatInt(user.ID, 10), Display: user.Username})
+		}
+	}
+
+	return &Group{
+		Schemas:     []string{SchemaGroup},
+		ID:          strconv.FormatInt(role.ID, 10),
+		DisplayName: role.Code,
+		Members:     members,
+		Meta: &Meta{
+			ResourceType: "Group",
+			Created:      role.CreatedAt,
+			LastModified: role.UpdatedAt,
+			Location:     location(c, "Groups", role.ID),
+		},
+	}, nil
+}
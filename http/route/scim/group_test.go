@@ -0,0 +1,245 @@
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createSCIMUsers provisions users with the given usernames and returns their ids
+func createSCIMUsers(t *testing.T, st *scimTest, usernames ...string) []string {
+	ids := make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		var user User
+		rec := st.do(t, http.MethodPost, "/scim/v2/Users", fmt.Sprintf(`{"userName":%q,"name":{"givenName":"G","familyName":"F"}}`, username), &user)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+func TestPostGroup(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		ids := createSCIMUsers(t, st, "alice", "bob")
+
+		var group Group
+		rec := st.do(t, http.MethodPost, "/scim/v2/Groups",
+			fmt.Sprintf(`{"schemas":["urn:ietf:params:scim:schemas:core:2.0:Group"],"displayName":"editors","members":[{"value":%q},{"value":%q}]}`, ids[0], ids[1]), &group)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "editors", group.DisplayName)
+		assert.Equal(t, []MemberRef{{Value: ids[0], Display: "alice"}, {Value: ids[1], Display: "bob"}}, group.Members)
+		role, err := st.roleService.GetByCode(t.Context(), "editors", model.RoleTypeRole)
+		require.NoError(t, err)
+		assert.Equal(t, group.ID, fmt.Sprint(role.ID))
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		st.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName":"editors"}`, nil)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName":"editors"}`, &scimErr)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, errorTypeUniqueness, scimErr.ScimType)
+	})
+
+	t.Run("invalid displayName", func(t *testing.T) {
+		st := setupSCIMTest(t)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName":"Content Editors"}`, &scimErr)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errorTypeInvalidValue, scimErr.ScimType)
+	})
+
+	t.Run("unknown member", func(t *testing.T) {
+		st := setupSCIMTest(t)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName":"editors","members":[{"value":"999"}]}`, &scimErr)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errorTypeNoTarget, scimErr.ScimType)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		st.permissions = &model.SubjectPermissions{Admin: []model.AdminPermission{{Section: model.AdminSectionUsers, Action: model.ActionAll}}}
+
+		rec := st.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName":"editors"}`, nil)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestGetGroups(t *testing.T) {
+	st := setupSCIMTest(t)
+	ids := createSCIMUsers(t, st, "alice")
+	st.do(t, http.MethodPost, "/scim/v2/Groups", fmt.Sprintf(`{"displayName":"editors","members":[{"value":%q}]}`, ids[0]), nil)
+	st.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName":"viewers"}`, nil)
+
+	t.Run("named roles only", func(t *testing.T) {
+		var list struct {
+			ListResponse
+			Resources []Group `json:"Resources"`
+		}
+		rec := st.do(t, http.MethodGet, "/scim/v2/Groups", "", &list)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 2, list.TotalResults)
+		require.Len(t, list.Resources, 2)
+		assert.Equal(t, "editors", list.Resources[0].DisplayName)
+		assert.Len(t, list.Resources[0].Members, 1)
+	})
+
+	t.Run("filter without members", func(t *testing.T) {
+		var list struct {
+			ListResponse
+			Resources []Group `json:"Resources"`
+		}
+		st.do(t, http.MethodGet, "/scim/v2/Groups?filter=displayName+eq+%22editors%22&excludedAttributes=members", "", &list)
+
+		assert.Equal(t, 1, list.TotalResults)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, "editors", list.Resources[0].DisplayName)
+		assert.Empty(t, list.Resources[0].Members)
+	})
+}
+
+func TestGetGroup(t *testing.T) {
+	st := setupSCIMTest(t)
+	var group Group
+	st.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName":"editors"}`, &group)
+	personal, err := st.roleService.Create(t.Context(), &model.Role{Code: "alice", Type: model.RoleTypeUser})
+	require.NoError(t, err)
+
+	rec := st.do(t, http.MethodGet, "/scim/v2/Groups/"+group.ID, "", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = st.do(t, http.MethodGet, fmt.Sprintf("/scim/v2/Groups/%d", personal.ID), "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPutGroup(t *testing.T) {
+	st := setupSCIMTest(t)
+	ids := createSCIMUsers(t, st, "alice", "bob")
+	var created Group
+	st.do(t, http.MethodPost, "/scim/v2/Groups", fmt.Sprintf(`{"displayName":"editors","members":[{"value":%q}]}`, ids[0]), &created)
+
+	var group Group
+	rec := st.do(t, http.MethodPut, "/scim/v2/Groups/"+created.ID,
+		fmt.Sprintf(`{"displayName":"writers","members":[{"value":%q}]}`, ids[1]), &group)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, created.ID, group.ID)
+	assert.Equal(t, "writers", group.DisplayName)
+	assert.Equal(t, []MemberRef{{Value: ids[1], Display: "bob"}}, group.Members)
+}
+
+func TestPatchGroup(t *testing.T) {
+	setup := func(t *testing.T) (*scimTest, []string, Group) {
+		st := setupSCIMTest(t)
+		ids := createSCIMUsers(t, st, "alice", "bob", "carol")
+		var group Group
+		st.do(t, http.MethodPost, "/scim/v2/Groups", fmt.Sprintf(`{"displayName":"editors","members":[{"value":%q}]}`, ids[0]), &group)
+		return st, ids, group
+	}
+	members := func(t *testing.T, st *scimTest, id string) []MemberRef {
+		var group Group
+		st.do(t, http.MethodGet, "/scim/v2/Groups/"+id, "", &group)
+		return group.Members
+	}
+
+	t.Run("add members", func(t *testing.T) {
+		st, ids, group := setup(t)
+
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Groups/"+group.ID,
+			fmt.Sprintf(`{"Operations":[{"op":"Add","path":"members","value":[{"value":%q},{"value":%q}]}]}`, ids[0], ids[1]), nil)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, []MemberRef{{Value: ids[0], Display: "alice"}, {Value: ids[1], Display: "bob"}}, members(t, st, group.ID))
+	})
+
+	t.Run("remove member by filter", func(t *testing.T) {
+		st, ids, group := setup(t)
+
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Groups/"+group.ID,
+			fmt.Sprintf(`{"Operations":[{"op":"remove","path":"members[value eq \"%s\"]"},{"op":"remove","path":"members[value eq \"%s\"]"}]}`, ids[0], ids[2]), nil)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, members(t, st, group.ID))
+	})
+
+	t.Run("remove members by value", func(t *testing.T) {
+		st, ids, group := setup(t)
+
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Groups/"+group.ID,
+			fmt.Sprintf(`{"Operations":[{"op":"remove","path":"members","value":[{"value":%q}]}]}`, ids[0]), nil)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Empty(t, members(t, st, group.ID))
+	})
+
+	t.Run("replace members and displayName", func(t *testing.T) {
+		st, ids, group := setup(t)
+
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Groups/"+group.ID,
+			fmt.Sprintf(`{"Operations":[{"op":"replace","path":"members","value":[{"value":%q}]},{"op":"replace","value":{"displayName":"writers"}}]}`, ids[2]), nil)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, []MemberRef{{Value: ids[2], Display: "carol"}}, members(t, st, group.ID))
+		_, err := st.roleService.GetByCode(t.Context(), "writers", model.RoleTypeRole)
+		assert.NoError(t, err)
+	})
+
+	t.Run("rename to an existing group", func(t *testing.T) {
+		st, _, group := setup(t)
+		st.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName":"viewers"}`, nil)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Groups/"+group.ID, `{"Operations":[{"op":"replace","path":"displayName","value":"viewers"}]}`, &scimErr)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, errorTypeUniqueness, scimErr.ScimType)
+	})
+
+	t.Run("unknown member", func(t *testing.T) {
+		st, _, group := setup(t)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Groups/"+group.ID, `{"Operations":[{"op":"add","path":"members","value":[{"value":"999"}]}]}`, &scimErr)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errorTypeNoTarget, scimErr.ScimType)
+	})
+
+	t.Run("unsupported path", func(t *testing.T) {
+		st, _, group := setup(t)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Groups/"+group.ID, `{"Operations":[{"op":"replace","path":"externalId","value":"x"}]}`, &scimErr)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errorTypeInvalidPath, scimErr.ScimType)
+	})
+}
+
+func TestDeleteGroup(t *testing.T) {
+	st := setupSCIMTest(t)
+	var group Group
+	st.do(t, http.MethodPost, "/scim/v2/Groups", `{"displayName":"editors"}`, &group)
+
+	rec := st.do(t, http.MethodDelete, "/scim/v2/Groups/"+group.ID, "", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = st.do(t, http.MethodDelete, "/scim/v2/Groups/"+group.ID, "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
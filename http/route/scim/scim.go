@@ -0,0 +1,228 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	SchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SchemaError                 = "urn:ietf:params:scim:api:messages:2.0:Error"
+	SchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+
+	// MIMEApplicationSCIM is the content type of the SCIM requests and responses
+	MIMEApplicationSCIM = "application/scim+json"
+
+	// IDKey is the path parameter holding the id of a user or a group
+	IDKey = "id"
+
+	// MaxResults is the max number of resources returned by a list request
+	MaxResults = 200
+
+	maxBodySize = 1 << 20
+)
+
+// SCIM error types of RFC 7644 section 3.12
+const (
+	errorTypeInvalidFilter = "invalidFilter"
+	errorTypeInvalidSyntax = "invalidSyntax"
+	errorTypeInvalidValue  = "invalidValue"
+	errorTypeInvalidPath   = "invalidPath"
+	errorTypeUniqueness    = "uniqueness"
+	errorTypeMutability    = "mutability"
+	errorTypeNoTarget      = "noTarget"
+)
+
+// Meta describes a resource
+type Meta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+}
+
+// ListResponse is the response of a list request
+type ListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    any      `json:"Resources"`
+}
+
+// ErrorResponse is the response of a failed request
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// PatchRequest is the body of a PATCH request
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// PatchOperation is an operation of a PATCH request, its op being add, replace or remove regardless of its case
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+// GetServiceProviderConfig returns the SCIM features supported by the endpoints
+func GetServiceProviderConfig() func(echo.Context) error {
+	return func(c echo.Context) error {
+		return writeJSON(c, http.StatusOK, map[string]any{
+			"schemas":        []string{SchemaServiceProviderConfig},
+			"patch":          map[string]any{"supported": true},
+			"bulk":           map[string]any{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+			"filter":         map[string]any{"supported": true, "maxResults": MaxResults},
+			"changePassword": map[string]any{"supported": false},
+			"sort":           map[string]any{"supported": false},
+			"etag":           map[string]any{"supported": false},
+			"authenticationSchemes": []map[string]any{{
+				"type":        "oauthbearertoken",
+				"name":        "API token",
+				"description": "Flecto API token sent as a bearer token",
+			}},
+		})
+	}
+}
+
+func writeJSON(c echo.Context, status int, body any) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.Blob(status, MIMEApplicationSCIM, data)
+}
+
+func writeError(c echo.Context, status int, scimType, detail string) error {
+	return writeJSON(c, status, ErrorResponse{
+		Schemas:  []string{SchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	})
+}
+
+// requestError is an error of the request detected by the endpoints
+type requestError struct {
+	status   int
+	scimType string
+	detail   string
+}
+
+func (e *requestError) Error() string {
+	return e.detail
+}
+
+func badRequest(scimType string, err error) error {
+	return &requestError{status: http.StatusBadRequest, scimType: scimType, detail: err.Error()}
+}
+
+// writeServiceError returns the SCIM error of an error of the request or of the user and role services
+func writeServiceError(c echo.Context, err error) error {
+	var reqErr *requestError
+	var validationErrors validator.ValidationErrors
+	switch {
+	case errors.As(err, &reqErr):
+		return writeError(c, reqErr.status, reqErr.scimType, reqErr.detail)
+	case errors.Is(err, service.ErrUserNotFound):
+		return writeError(c, http.StatusNotFound, "", err.Error())
+	case errors.Is(err, service.ErrRoleNotFound):
+		return writeError(c, http.StatusNotFound, "", err.Error())
+	case errors.Is(err, service.ErrUserAlreadyExists), errors.Is(err, service.ErrRoleAlreadyExists):
+		return writeError(c, http.StatusConflict, errorTypeUniqueness, err.Error())
+	case errors.As(err, &validationErrors):
+		return writeError(c, http.StatusBadRequest, errorTypeInvalidValue, err.Error())
+	default:
+		c.Logger().Error(err)
+		return writeError(c, http.StatusInternalServerError, "", "internal error")
+	}
+}
+
+func writeForbidden(c echo.Context) error {
+	return writeError(c, http.StatusForbidden, "", "no permission to access the resource")
+}
+
+// bindBody decodes the JSON body of the request, sent either as application/scim+json or as application/json
+func bindBody(c echo.Context, body any) error {
+	decoder := json.NewDecoder(io.LimitReader(c.Request().Body, maxBodySize))
+	if err := decoder.Decode(body); err != nil {
+		return badRequest(errorTypeInvalidSyntax, err)
+	}
+	return nil
+}
+
+// parseID returns the id of the path, false when it is not the id of a resource
+func parseID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param(IDKey), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// pagination returns the 1-based index of the first resource and the number of resources of a list request
+func pagination(c echo.Context) (startIndex, count int) {
+	startIndex, err := strconv.Atoi(c.QueryParam("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err = strconv.Atoi(c.QueryParam("count"))
+	if err != nil || count > MaxResults {
+		count = MaxResults
+	}
+	if count < 0 {
+		count = 0
+	}
+	return startIndex, count
+}
+
+var filterRegex = regexp.MustCompile(`^\s*([A-Za-z.]+)\s+(?i:eq)\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// parseFilter returns the value of a filter comparing the attribute with eq, the only filter supported.
+// The attribute name is matched regardless of its case.
+func parseFilter(filter, attribute string) (string, error) {
+	matches := filterRegex.FindStringSubmatch(filter)
+	if matches == nil || !strings.EqualFold(matches[1], attribute) {
+		return "", fmt.Errorf("only the filter %s eq \"value\" is supported", attribute)
+	}
+	var value string
+	if err := json.Unmarshal([]byte(`"`+matches[2]+`"`), &value); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// location returns the URL of a resource of the endpoints
+func location(c echo.Context, resource string, id int64) string {
+	return fmt.Sprintf("%s://%s/scim/v2/%s/%d", c.Scheme(), c.Request().Host, resource, id)
+}
+
+// parseBool decodes a boolean sent either as a JSON boolean or as a string, as some identity providers do
+func parseBool(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return false, err
+	}
+	return strconv.ParseBool(strings.ToLower(s))
+}
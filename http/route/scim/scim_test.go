@@ -0,0 +1,137 @@
+package scim
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/auth"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type scimTest struct {
+	e           *echo.Echo
+	db          *gorm.DB
+	userService service.UserService
+	roleService service.RoleService
+	permissions *model.SubjectPermissions
+}
+
+// setupSCIMTest registers the endpoints on the user and role services of an in-memory database,
+// the requests being made by a subject with the users and roles admin permissions
+func setupSCIMTest(t *testing.T) *scimTest {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.User{}, &model.UserPasswordHistory{}, &model.Role{}, &model.UserRole{}, &model.RoleParent{},
+		&model.ResourcePermission{}, &model.AdminPermission{}, &model.ProjectMember{}, &model.GroupRole{}))
+
+	ctx := appContext.TestContext(nil)
+	bus := invalidation.NewMemoryBus()
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	st := &scimTest{
+		e:           echo.New(),
+		db:          db,
+		userService: service.NewUserService(ctx, userRepo, roleRepo, bus),
+		roleService: service.NewRoleService(ctx, roleRepo, userRepo, repository.NewProjectMemberRepository(db), bus),
+		permissions: &model.SubjectPermissions{Admin: []model.AdminPermission{
+			{Section: model.AdminSectionUsers, Action: model.ActionAll},
+			{Section: model.AdminSectionRoles, Action: model.ActionAll},
+		}},
+	}
+	permissionChecker := auth.NewPermissionChecker(st.roleService)
+
+	g := st.e.Group("/scim/v2", func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userCtx := &auth.UserContext{Username: "idp", SubjectPermissions: st.permissions}
+			c.SetRequest(c.Request().WithContext(auth.SetUserContext(c.Request().Context(), userCtx)))
+			return next(c)
+		}
+	})
+	g.GET("/ServiceProviderConfig", GetServiceProviderConfig())
+	g.GET("/Users", GetUsers(permissionChecker, st.userService, st.roleService))
+	g.POST("/Users", PostUser(permissionChecker, st.userService, st.roleService))
+	g.GET("/Users/:"+IDKey, GetUser(permissionChecker, st.userService, st.roleService))
+	g.PUT("/Users/:"+IDKey, PutUser(permissionChecker, st.userService, st.roleService))
+	g.PATCH("/Users/:"+IDKey, PatchUser(permissionChecker, st.userService, st.roleService))
+	g.DELETE("/Users/:"+IDKey, DeleteUser(permissionChecker, st.userService))
+	g.GET("/Groups", GetGroups(permissionChecker, st.roleService))
+	g.POST("/Groups", PostGroup(permissionChecker, st.roleService))
+	g.GET("/Groups/:"+IDKey, GetGroup(permissionChecker, st.roleService))
+	g.PUT("/Groups/:"+IDKey, PutGroup(permissionChecker, st.roleService))
+	g.PATCH("/Groups/:"+IDKey, PatchGroup(permissionChecker, st.roleService))
+	g.DELETE("/Groups/:"+IDKey, DeleteGroup(permissionChecker, st.roleService))
+	return st
+}
+
+// do sends a request to the endpoints and decodes the JSON response into out when set
+func (st *scimTest) do(t *testing.T, method, path, body string, out any) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, MIMEApplicationSCIM)
+	rec := httptest.NewRecorder()
+	st.e.ServeHTTP(rec, req)
+	if out != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out), rec.Body.String())
+	}
+	return rec
+}
+
+func TestGetServiceProviderConfig(t *testing.T) {
+	st := setupSCIMTest(t)
+
+	var config map[string]any
+	rec := st.do(t, http.MethodGet, "/scim/v2/ServiceProviderConfig", "", &config)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, MIMEApplicationSCIM, rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, []any{SchemaServiceProviderConfig}, config["schemas"])
+	assert.Equal(t, map[string]any{"supported": true}, config["patch"])
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		filter  string
+		want    string
+		wantErr bool
+	}{
+		{name: "eq", filter: `userName eq "jdoe@example.com"`, want: "jdoe@example.com"},
+		{name: "case insensitive", filter: `USERNAME EQ "jdoe"`, want: "jdoe"},
+		{name: "escaped quote", filter: `userName eq "a\"b"`, want: `a"b`},
+		{name: "other attribute", filter: `externalId eq "jdoe"`, wantErr: true},
+		{name: "other operator", filter: `userName sw "j"`, wantErr: true},
+		{name: "logical expression", filter: `userName eq "a" or userName eq "b"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFilter(tt.filter, "userName")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	for value, want := range map[string]bool{`true`: true, `false`: false, `"True"`: true, `"False"`: false} {
+		got, err := parseBool(json.RawMessage(value))
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, value)
+	}
+	_, err := parseBool(json.RawMessage(`"maybe"`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,312 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/flectolab/flecto-manager/auth"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/labstack/echo/v4"
+)
+
+// User is the SCIM representation of a user
+type User struct {
+	Schemas  []string    `json:"schemas"`
+	ID       string      `json:"id,omitempty"`
+	UserName string      `json:"userName"`
+	Name     UserName    `json:"name"`
+	Active   *bool       `json:"active,omitempty"`
+	Groups   []MemberRef `json:"groups,omitempty"`
+	Meta     *Meta       `json:"meta,omitempty"`
+}
+
+// UserName is the name of a user
+type UserName struct {
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+}
+
+// MemberRef references a group of a user or a member of a group
+type MemberRef struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// GetUsers lists the users, filtered by userName when the filter is set
+func GetUsers(permissionChecker *auth.PermissionChecker, userService service.UserService, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionRead) {
+			return writeForbidden(c)
+		}
+
+		query := userService.GetQuery(ctx)
+		if filter := c.QueryParam("filter"); filter != "" {
+			username, err := parseFilter(filter, "userName")
+			if err != nil {
+				return writeError(c, http.StatusBadRequest, errorTypeInvalidFilter, err.Error())
+			}
+			query = query.Where("username = ?", username)
+		}
+
+		startIndex, count := pagination(c)
+		list, err := userService.SearchPaginate(ctx, &commonTypes.PaginationInput{
+			Limit:   types.Ptr(max(count, 1)),
+			Offset:  types.Ptr(startIndex - 1),
+			OrderBy: []commonTypes.SortInput{{Column: "id", Direction: commonTypes.SortASC}},
+		}, query)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+
+		resources := make([]User, 0, len(list.Items))
+		for _, user := range list.Items[:min(count, len(list.Items))] {
+			resource, errUser := toUser(c, roleService, &user)
+			if errUser != nil {
+				return writeServiceError(c, errUser)
+			}
+			resources = append(resources, *resource)
+		}
+		return writeJSON(c, http.StatusOK, ListResponse{
+			Schemas:      []string{SchemaListResponse},
+			TotalResults: list.Total,
+			StartIndex:   startIndex,
+			ItemsPerPage: len(resources),
+			Resources:    resources,
+		})
+	}
+}
+
+func GetUser(permissionChecker *auth.PermissionChecker, userService service.UserService, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionRead) {
+			return writeForbidden(c)
+		}
+
+		user, err := findUser(c, userService)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		return writeUser(c, http.StatusOK, roleService, user)
+	}
+}
+
+// PostUser provisions a user, active unless the request says otherwise. The user has no password,
+// it logs in with OpenID Connect.
+func PostUser(permissionChecker *auth.PermissionChecker, userService service.UserService, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
+			return writeForbidden(c)
+		}
+
+		var input User
+		if err := bindBody(c, &input); err != nil {
+			return writeServiceError(c, err)
+		}
+		user, err := userService.Create(ctx, &model.User{
+			Username:  input.UserName,
+			Firstname: input.Name.GivenName,
+			Lastname:  input.Name.FamilyName,
+			Active:    types.Ptr(input.Active == nil || *input.Active),
+		})
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		return writeUser(c, http.StatusCreated, roleService, user)
+	}
+}
+
+// PutUser replaces the name and the status of a user, its userName cannot change
+func PutUser(permissionChecker *auth.PermissionChecker, userService service.UserService, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
+			return writeForbidden(c)
+		}
+
+		user, err := findUser(c, userService)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		var input User
+		if err = bindBody(c, &input); err != nil {
+			return writeServiceError(c, err)
+		}
+		if input.Active == nil {
+			input.Active = types.Ptr(true)
+		}
+		return saveUser(c, userService, roleService, user, input)
+	}
+}
+
+// PatchUser applies the operations on the name and the status of a user, an identity provider
+// deprovisioning a user by replacing active with false
+func PatchUser(permissionChecker *auth.PermissionChecker, userService service.UserService, roleService service.RoleService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
+			return writeForbidden(c)
+		}
+
+		user, err := findUser(c, userService)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		var patch PatchRequest
+		if err = bindBody(c, &patch); err != nil {
+			return writeServiceError(c, err)
+		}
+
+		input := User{
+			UserName: user.Username,
+			Name:     UserName{GivenName: user.Firstname, FamilyName: user.Lastname},
+			Active:   types.Ptr(user.IsActive()),
+		}
+		for _, operation := range patch.Operations {
+			if err = patchUser(&input, operation); err != nil {
+				return writeServiceError(c, err)
+			}
+		}
+		return saveUser(c, userService, roleService, user, input)
+	}
+}
+
+// DeleteUser deletes a user, with its personal role
+func DeleteUser(permissionChecker *auth.PermissionChecker, userService service.UserService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		userCtx := auth.GetUser(ctx)
+		if !permissionChecker.CanAdmin(userCtx.SubjectPermissions, model.AdminSectionUsers, model.ActionWrite) {
+			return writeForbidden(c)
+		}
+
+		user, err := findUser(c, userService)
+		if err != nil {
+			return writeServiceError(c, err)
+		}
+		if _, err = userService.Delete(ctx, user.ID); err != nil {
+			return writeServiceError(c, err)
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func findUser(c echo.Context, userService service.UserService) (*model.User, error) {
+	id, ok := parseID(c)
+	if !ok {
+		return nil, service.ErrUserNotFound
+	}
+	return userService.GetByID(c.Request().Context(), id)
+}
+
+// saveUser updates the name and the status of the user from the input
+func saveUser(c echo.Context, userService service.UserService, roleService service.RoleService, user *model.User, input User) error {
+	ctx := c.Request().Context()
+	if input.UserName != "" && input.UserName != user.Username {
+		return writeError(c, http.StatusBadRequest, errorTypeMutability, "userName cannot be changed")
+	}
+
+	user, err := userService.Update(ctx, user.ID, model.User{Firstname: input.Name.GivenName, Lastname: input.Name.FamilyName})
+	if err != nil {
+		return writeServiceError(c, err)
+	}
+	if input.Active != nil && *input.Active != user.IsActive() {
+		if user, err = userService.UpdateStatus(ctx, user.ID, *input.Active); err != nil {
+			return writeServiceError(c, err)
+		}
+	}
+	return writeUser(c, http.StatusOK, roleService, user)
+}
+
+// patchUser applies an operation to the user
+func patchUser(input *User, operation PatchOperation) error {
+	op := strings.ToLower(operation.Op)
+	if op != "add" && op != "replace" {
+		return badRequest(errorTypeInvalidPath, errors.New("only the add and replace operations are supported on users"))
+	}
+
+	// Without path, the value holds the attributes to set
+	if operation.Path == "" {
+		var attributes map[string]json.RawMessage
+		if err := json.Unmarshal(operation.Value, &attributes); err != nil {
+			return badRequest(errorTypeInvalidValue, err)
+		}
+		for path, value := range attributes {
+			if err := patchUser(input, PatchOperation{Op: op, Path: path, Value: value}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var err error
+	switch strings.ToLower(operation.Path) {
+	case "active":
+		var active bool
+		active, err = parseBool(operation.Value)
+		input.Active = &active
+	case "username":
+		err = json.Unmarshal(operation.Value, &input.UserName)
+	case "name":
+		err = json.Unmarshal(operation.Value, &input.Name)
+	case "name.givenname":
+		err = json.Unmarshal(operation.Value, &input.Name.GivenName)
+	case "name.familyname":
+		err = json.Unmarshal(operation.Value, &input.Name.FamilyName)
+	default:
+		return badRequest(errorTypeInvalidPath, fmt.Errorf("unsupported path %s", operation.Path))
+	}
+	if err != nil {
+		return badRequest(errorTypeInvalidValue, err)
+	}
+	return nil
+}
+
+func writeUser(c echo.Context, status int, roleService service.RoleService, user *model.User) error {
+	resource, err := toUser(c, roleService, user)
+	if err != nil {
+		return writeServiceError(c, err)
+	}
+	return writeJSON(c, status, resource)
+}
+
+// toUser returns the SCIM representation of a user, its groups being its named roles
+func toUser(c echo.Context, roleService service.RoleService, user *model.User) (*User, error) {
+	roles, err := roleService.GetUserRolesByType(c.Request().Context(), user.ID, model.RoleTypeRole)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]MemberRef, 0, len(roles))
+	for _, role := range roles {
+		groups = append(groups, MemberRef{Value: strconv.FormatInt(role.ID, 10), Display: role.Code})
+	}
+
+	return &User{
+		Schemas:  []string{SchemaUser},
+		ID:       strconv.FormatInt(user.ID, 10),
+		UserName: user.Username,
+		Name:     UserName{GivenName: user.Firstname, FamilyName: user.Lastname},
+		Active:   types.Ptr(user.IsActive()),
+		Groups:   groups,
+		Meta: &Meta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     location(c, "Users", user.ID),
+		},
+	}, nil
+}
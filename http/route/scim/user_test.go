@@ -0,0 +1,267 @@
+package scim
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const jdoeBody = `{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"jdoe@example.com","name":{"givenName":"John","familyName":"Doe"},"active":true}`
+
+func TestPostUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		st := setupSCIMTest(t)
+
+		var user User
+		rec := st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &user)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.NotEmpty(t, user.ID)
+		assert.Equal(t, "jdoe@example.com", user.UserName)
+		assert.Equal(t, UserName{GivenName: "John", FamilyName: "Doe"}, user.Name)
+		assert.True(t, *user.Active)
+		assert.Equal(t, "User", user.Meta.ResourceType)
+		assert.Equal(t, "http://example.com/scim/v2/Users/"+user.ID, user.Meta.Location)
+
+		created, err := st.userService.GetByUsername(t.Context(), "jdoe@example.com")
+		require.NoError(t, err)
+		assert.False(t, created.HasPassword())
+		_, err = st.roleService.GetByCode(t.Context(), "jdoe@example.com", model.RoleTypeUser)
+		assert.NoError(t, err)
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, nil)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &scimErr)
+
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, "409", scimErr.Status)
+		assert.Equal(t, errorTypeUniqueness, scimErr.ScimType)
+	})
+
+	t.Run("missing name", func(t *testing.T) {
+		st := setupSCIMTest(t)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPost, "/scim/v2/Users", `{"userName":"jdoe@example.com"}`, &scimErr)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errorTypeInvalidValue, scimErr.ScimType)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		st := setupSCIMTest(t)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPost, "/scim/v2/Users", `{`, &scimErr)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errorTypeInvalidSyntax, scimErr.ScimType)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		st.permissions = &model.SubjectPermissions{Admin: []model.AdminPermission{{Section: model.AdminSectionUsers, Action: model.ActionRead}}}
+
+		rec := st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, nil)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
+
+func TestGetUsers(t *testing.T) {
+	st := setupSCIMTest(t)
+	for _, username := range []string{"alice", "bob", "carol"} {
+		st.do(t, http.MethodPost, "/scim/v2/Users", fmt.Sprintf(`{"userName":%q,"name":{"givenName":"G","familyName":"F"}}`, username), nil)
+	}
+
+	t.Run("all", func(t *testing.T) {
+		var list struct {
+			ListResponse
+			Resources []User `json:"Resources"`
+		}
+		rec := st.do(t, http.MethodGet, "/scim/v2/Users", "", &list)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 3, list.TotalResults)
+		assert.Equal(t, 1, list.StartIndex)
+		assert.Equal(t, 3, list.ItemsPerPage)
+		assert.Equal(t, "alice", list.Resources[0].UserName)
+	})
+
+	t.Run("page", func(t *testing.T) {
+		var list struct {
+			ListResponse
+			Resources []User `json:"Resources"`
+		}
+		st.do(t, http.MethodGet, "/scim/v2/Users?startIndex=2&count=1", "", &list)
+
+		assert.Equal(t, 3, list.TotalResults)
+		assert.Equal(t, 2, list.StartIndex)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, "bob", list.Resources[0].UserName)
+	})
+
+	t.Run("count zero", func(t *testing.T) {
+		var list struct {
+			ListResponse
+			Resources []User `json:"Resources"`
+		}
+		st.do(t, http.MethodGet, "/scim/v2/Users?count=0", "", &list)
+
+		assert.Equal(t, 3, list.TotalResults)
+		assert.Empty(t, list.Resources)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		var list struct {
+			ListResponse
+			Resources []User `json:"Resources"`
+		}
+		st.do(t, http.MethodGet, "/scim/v2/Users?filter=userName+eq+%22carol%22", "", &list)
+
+		assert.Equal(t, 1, list.TotalResults)
+		require.Len(t, list.Resources, 1)
+		assert.Equal(t, "carol", list.Resources[0].UserName)
+	})
+
+	t.Run("unsupported filter", func(t *testing.T) {
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodGet, "/scim/v2/Users?filter=displayName+eq+%22carol%22", "", &scimErr)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errorTypeInvalidFilter, scimErr.ScimType)
+	})
+}
+
+func TestGetUser(t *testing.T) {
+	st := setupSCIMTest(t)
+	var created User
+	st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &created)
+	var group Group
+	st.do(t, http.MethodPost, "/scim/v2/Groups", fmt.Sprintf(`{"displayName":"editors","members":[{"value":%q}]}`, created.ID), &group)
+
+	t.Run("success", func(t *testing.T) {
+		var user User
+		rec := st.do(t, http.MethodGet, "/scim/v2/Users/"+created.ID, "", &user)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "jdoe@example.com", user.UserName)
+		assert.Equal(t, []MemberRef{{Value: group.ID, Display: "editors"}}, user.Groups)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		rec := st.do(t, http.MethodGet, "/scim/v2/Users/999", "", nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		rec := st.do(t, http.MethodGet, "/scim/v2/Users/abc", "", nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestPutUser(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		var created User
+		st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &created)
+
+		var user User
+		rec := st.do(t, http.MethodPut, "/scim/v2/Users/"+created.ID,
+			`{"userName":"jdoe@example.com","name":{"givenName":"Johnny","familyName":"Doe"},"active":false}`, &user)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Johnny", user.Name.GivenName)
+		assert.False(t, *user.Active)
+		saved, err := st.userService.GetByUsername(t.Context(), "jdoe@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "Johnny", saved.Firstname)
+		assert.False(t, saved.IsActive())
+	})
+
+	t.Run("userName changed", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		var created User
+		st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &created)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPut, "/scim/v2/Users/"+created.ID,
+			`{"userName":"other","name":{"givenName":"John","familyName":"Doe"}}`, &scimErr)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errorTypeMutability, scimErr.ScimType)
+	})
+}
+
+func TestPatchUser(t *testing.T) {
+	t.Run("deactivate", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		var created User
+		st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &created)
+
+		var user User
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Users/"+created.ID,
+			`{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"Replace","path":"active","value":"False"}]}`, &user)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, *user.Active)
+		assert.Equal(t, "John", user.Name.GivenName)
+	})
+
+	t.Run("attributes without path", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		var created User
+		st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &created)
+
+		var user User
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Users/"+created.ID,
+			`{"Operations":[{"op":"replace","value":{"name.familyName":"Smith","active":false}}]}`, &user)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, UserName{GivenName: "John", FamilyName: "Smith"}, user.Name)
+		assert.False(t, *user.Active)
+	})
+
+	t.Run("unsupported path", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		var created User
+		st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &created)
+
+		var scimErr ErrorResponse
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Users/"+created.ID,
+			`{"Operations":[{"op":"replace","path":"emails","value":[]}]}`, &scimErr)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, errorTypeInvalidPath, scimErr.ScimType)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		st := setupSCIMTest(t)
+		var created User
+		st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &created)
+
+		rec := st.do(t, http.MethodPatch, "/scim/v2/Users/"+created.ID, `{"Operations":[{"op":"remove","path":"active"}]}`, nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	st := setupSCIMTest(t)
+	var created User
+	st.do(t, http.MethodPost, "/scim/v2/Users", jdoeBody, &created)
+
+	rec := st.do(t, http.MethodDelete, "/scim/v2/Users/"+created.ID, "", nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = st.do(t, http.MethodDelete, "/scim/v2/Users/"+created.ID, "", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/service"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+const (
+	// HeaderGitHubSignature holds the HMAC SHA-256 of the body signed with the webhook secret
+	HeaderGitHubSignature = "X-Hub-Signature-256"
+	// HeaderGitLabToken holds the webhook secret
+	HeaderGitLabToken = "X-Gitlab-Token"
+
+	maxPushBodySize = 10 << 20
+)
+
+// pushEvent is the part of the GitHub and GitLab push events used to filter the pushed branch
+type pushEvent struct {
+	Ref string `json:"ref"`
+}
+
+// PostGitPush syncs a project from its Git repository on the push notifications of the Git hosting service.
+// The request is authenticated by the webhook secret of the project sync, either as a GitHub signature or
+// as a GitLab token. Pushes to other branches are ignored, the sync runs in the background.
+func PostGitPush(gitSyncService service.GitSyncService) func(echo.Context) error {
+	return func(c echo.Context) error {
+		ctx := c.Request().Context()
+		namespaceCode := c.Param(route.NamespaceCodeKey)
+		projectCode := c.Param(route.ProjectCodeKey)
+
+		body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxPushBodySize))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err)
+		}
+
+		gitSync, err := gitSyncService.GetByProject(ctx, namespaceCode, projectCode)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.NoContent(http.StatusNotFound)
+			}
+			return echo.NewHTTPError(http.StatusInternalServerError, err)
+		}
+		if !verifyPush(gitSync, c.Request().Header, body) {
+			return c.NoContent(http.StatusUnauthorized)
+		}
+
+		var event pushEvent
+		if len(body) > 0 {
+			if err = json.Unmarshal(body, &event); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err)
+			}
+		}
+		if event.Ref != "" && event.Ref != "refs/heads/"+gitSync.Branch {
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		gitSyncService.Trigger(namespaceCode, projectCode)
+		return c.NoContent(http.StatusAccepted)
+	}
+}
+
+// verifyPush checks the GitHub signature of the body, or the GitLab token when there is no signature
+func verifyPush(gitSync *model.ProjectGitSync, header http.Header, body []byte) bool {
+	if gitSync.WebhookSecret == "" {
+		return false
+	}
+	if signature := header.Get(HeaderGitHubSignature); signature != "" {
+		expected, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+		if err != nil {
+			return false
+		}
+		mac := hmac.New(sha256.New, []byte(gitSync.WebhookSecret))
+		mac.Write(body)
+		return hmac.Equal(mac.Sum(nil), expected)
+	}
+	if token := header.Get(HeaderGitLabToken); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(gitSync.WebhookSecret)) == 1
+	}
+	return false
+}
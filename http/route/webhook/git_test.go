@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/http/route"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+const testSecret = "secret"
+
+func newPushContext(body string, header map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/webhook/git/namespace/ns1/project/proj1", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames(route.NamespaceCodeKey, route.ProjectCodeKey)
+	c.SetParamValues("ns1", "proj1")
+	return c, rec
+}
+
+func sign(body string) string {
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestPostGitPush(t *testing.T) {
+	gitSync := &model.ProjectGitSync{NamespaceCode: "ns1", ProjectCode: "proj1", Branch: "main", WebhookSecret: testSecret}
+	mainPush := `{"ref":"refs/heads/main"}`
+
+	tests := []struct {
+		name     string
+		body     string
+		header   map[string]string
+		trigger  bool
+		wantCode int
+	}{
+		{name: "github signature", body: mainPush, header: map[string]string{HeaderGitHubSignature: sign(mainPush)}, trigger: true, wantCode: http.StatusAccepted},
+		{name: "gitlab token", body: mainPush, header: map[string]string{HeaderGitLabToken: testSecret}, trigger: true, wantCode: http.StatusAccepted},
+		{name: "without ref", body: "", header: map[string]string{HeaderGitLabToken: testSecret}, trigger: true, wantCode: http.StatusAccepted},
+		{name: "other branch", body: `{"ref":"refs/heads/dev"}`, header: map[string]string{HeaderGitLabToken: testSecret}, wantCode: http.StatusNoContent},
+		{name: "bad signature", body: mainPush, header: map[string]string{HeaderGitHubSignature: sign("other")}, wantCode: http.StatusUnauthorized},
+		{name: "bad token", body: mainPush, header: map[string]string{HeaderGitLabToken: "other"}, wantCode: http.StatusUnauthorized},
+		{name: "unauthenticated", body: mainPush, wantCode: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockGitSyncService := mockFlectoService.NewMockGitSyncService(ctrl)
+			mockGitSyncService.EXPECT().GetByProject(gomock.Any(), "ns1", "proj1").Return(gitSync, nil)
+			if tt.trigger {
+				mockGitSyncService.EXPECT().Trigger("ns1", "proj1")
+			}
+
+			c, rec := newPushContext(tt.body, tt.header)
+			require.NoError(t, PostGitPush(mockGitSyncService)(c))
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGitSyncService := mockFlectoService.NewMockGitSyncService(ctrl)
+		mockGitSyncService.EXPECT().GetByProject(gomock.Any(), "ns1", "proj1").Return(nil, gorm.ErrRecordNotFound)
+
+		c, rec := newPushContext(mainPush, map[string]string{HeaderGitLabToken: testSecret})
+		require.NoError(t, PostGitPush(mockGitSyncService)(c))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGitSyncService := mockFlectoService.NewMockGitSyncService(ctrl)
+		mockGitSyncService.EXPECT().GetByProject(gomock.Any(), "ns1", "proj1").Return(nil, errors.New("db error"))
+
+		c, _ := newPushContext(mainPush, map[string]string{HeaderGitLabToken: testSecret})
+		err := PostGitPush(mockGitSyncService)(c)
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockGitSyncService := mockFlectoService.NewMockGitSyncService(ctrl)
+		mockGitSyncService.EXPECT().GetByProject(gomock.Any(), "ns1", "proj1").Return(gitSync, nil)
+
+		c, _ := newPushContext("{", map[string]string{HeaderGitLabToken: testSecret})
+		err := PostGitPush(mockGitSyncService)(c)
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	})
+}
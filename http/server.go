@@ -19,11 +19,17 @@ import (
 	"github.com/flectolab/flecto-manager/context"
 	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/graph"
+	"github.com/flectolab/flecto-manager/graph/loader"
 	"github.com/flectolab/flecto-manager/graph/resolver"
 	"github.com/flectolab/flecto-manager/http/route"
+	"github.com/flectolab/flecto-manager/http/route/admin"
+	"github.com/flectolab/flecto-manager/http/route/api/agent"
 	"github.com/flectolab/flecto-manager/http/route/api/project"
 	routeAuth "github.com/flectolab/flecto-manager/http/route/auth"
 	"github.com/flectolab/flecto-manager/http/route/health"
+	"github.com/flectolab/flecto-manager/http/route/scim"
+	"github.com/flectolab/flecto-manager/http/route/webhook"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/metrics"
 	"github.com/flectolab/flecto-manager/repository"
@@ -37,31 +43,76 @@ import (
 func CreateServerHTTP(ctx *context.Context) (*echo.Echo, error) {
 	e := createServerHTTP()
 	e.Logger.SetOutput(os.Stdout)
+	e.HTTPErrorHandler = route.ErrorHandler(ctx.Logger)
+	ipExtractor, err := route.ClientIPExtractor(ctx.Config.HTTP.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	e.IPExtractor = ipExtractor
 
+	e.Use(route.RequestIDMiddleware())
+	e.Use(route.LanguageMiddleware())
+	if ctx.Config.HTTP.AccessLog {
+		e.Use(route.AccessLogMiddleware(ctx.Logger))
+	}
+	if ctx.Config.HTTP.BodyLimit > 0 {
+		// The imports are uploaded to the GraphQL API, with room for the other form fields
+		e.Use(route.BodyLimitMiddleware(ctx.Config.HTTP.BodyLimit, ctx.Config.Import.MaxFileSize+1<<20, "/graphql"))
+	}
 	setupCORS(e, ctx)
+	if len(ctx.Config.DB.Replicas) > 0 {
+		e.Use(route.PrimaryMiddleware())
+	}
 
 	db, err := database.CreateDB(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	bus, err := invalidation.NewBus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	jwtService := jwt.NewServiceJWT(&ctx.Config.Auth.JWT)
 	repos := repository.NewRepositories(db)
-	services := service.NewServices(ctx, repos, jwtService)
+	services := service.NewServices(ctx, repos, jwtService, bus)
+	services.RedirectImport.StartWorkers()
+	services.Outbox.StartWorker()
+	services.Scheduler.Start()
+	if hooks := ctx.Extensions.Hooks(); len(hooks) > 0 {
+		ctx.Logger.Info("extension hooks registered", "hooks", hooks, "disabled", ctx.Config.Extensions.Disabled)
+	}
 	permissionChecker := auth.NewPermissionChecker(services.Role)
 
-	authMiddleware := auth.UserCtxAuthMiddleware(&ctx.Config.Auth.JWT, services.User, services.Role, services.Token)
+	authMiddleware := auth.UserCtxAuthMiddleware(&ctx.Config.Auth.JWT, services.User, services.Role, services.Token, services.ProjectAPIKey)
 
 	e.GET("/health/ping", health.GetPing())
-	if err = setupAuthRoutes(ctx, e, services, jwtService, authMiddleware); err != nil {
+	e.GET("/healthz", health.GetLiveness(services.Probe))
+	e.GET("/readyz", health.GetReadiness(services.Probe))
+	if statusCfg := ctx.Config.HTTP.Status; statusCfg.Enabled {
+		var middlewares []echo.MiddlewareFunc
+		if statusCfg.RateLimit > 0 {
+			middlewares = append(middlewares, route.RateLimitMiddleware(statusCfg.RateLimit, statusCfg.Burst, ipExtractor))
+		}
+		e.GET("/status", health.GetStatus(services.Status, statusCfg.CacheTTL), middlewares...)
+	}
+	if err = setupAuthRoutes(ctx, e, services, permissionChecker, authMiddleware); err != nil {
+		return nil, err
+	}
+	if err = setupGraphQLRoutes(ctx, e, services, permissionChecker, authMiddleware); err != nil {
 		return nil, err
 	}
-	setupGraphQLRoutes(ctx, e, services, permissionChecker, authMiddleware)
 	setupAPIRoutes(e, services, permissionChecker, authMiddleware)
+	setupWebhookRoutes(ctx, e, services)
+	setupAdminRoutes(ctx, e, services, permissionChecker, authMiddleware)
+	if ctx.Config.Auth.SCIM.Enabled {
+		setupSCIMRoutes(ctx, e, services, permissionChecker, authMiddleware)
+	}
 
 	// Setup metrics if enabled
 	if ctx.Config.Metrics.Enabled {
-		setupMetrics(ctx, e, services.Agent)
+		setupMetrics(ctx, e, services.Agent, services.Retention, services.SnapshotCache)
 	}
 
 	registerUI(ctx, e)
@@ -79,17 +130,20 @@ func createServerHTTP() *echo.Echo {
 
 func setupCORS(e *echo.Echo, ctx *context.Context) {
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"},
-		AllowMethods: []string{echo.GET, echo.POST, echo.PATCH, echo.OPTIONS},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, ctx.Config.Auth.JWT.HeaderName},
+		AllowOrigins:  []string{"*"},
+		AllowMethods:  []string{echo.GET, echo.POST, echo.PATCH, echo.OPTIONS},
+		AllowHeaders:  []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderXRequestID, ctx.Config.Auth.JWT.HeaderName},
+		ExposeHeaders: []string{echo.HeaderXRequestID},
 	}))
 }
 
-func setupAuthRoutes(ctx *context.Context, e *echo.Echo, services *service.Services, jwtService *jwt.ServiceJWT, authMiddleware echo.MiddlewareFunc) error {
+func setupAuthRoutes(ctx *context.Context, e *echo.Echo, services *service.Services, permissionChecker *auth.PermissionChecker, authMiddleware echo.MiddlewareFunc) error {
 	authGroup := e.Group("/auth")
 	authGroup.POST("/login", routeAuth.GetLogin(ctx, services.Auth))
 	authGroup.POST("/refresh", routeAuth.GetRefresh(ctx, services.Auth))
 	authGroup.POST("/logout", routeAuth.GetLogout(ctx, services.Auth), authMiddleware)
+	authGroup.POST("/impersonate", routeAuth.GetImpersonate(ctx, permissionChecker, services.Auth), authMiddleware)
+	authGroup.GET("/whoami", routeAuth.GetWhoami(), authMiddleware)
 
 	// OpenID Connect (if enabled)
 	if ctx.Config.Auth.OpenID.Enabled {
@@ -97,7 +151,7 @@ func setupAuthRoutes(ctx *context.Context, e *echo.Echo, services *service.Servi
 		if err != nil {
 			return fmt.Errorf("failed to create OpenID provider: %w", err)
 		}
-		openidService := openid.NewService(openidProvider, services.User, jwtService)
+		openidService := openid.NewService(openidProvider, services.User, services.Auth)
 		authGroup.GET("/openid", routeAuth.GetOpenIDConfig(ctx, &ctx.Config.Auth.OpenID, openidService))
 		authGroup.GET("/openid/callback", routeAuth.GetOpenIDCallback(ctx, openidService))
 	} else {
@@ -107,19 +161,24 @@ func setupAuthRoutes(ctx *context.Context, e *echo.Echo, services *service.Servi
 	return nil
 }
 
-func setupGraphQLRoutes(ctx *context.Context, e *echo.Echo, services *service.Services, permissionChecker *auth.PermissionChecker, authMiddleware echo.MiddlewareFunc) {
-	srv := createGraphQLHandler(ctx, services, permissionChecker)
+func setupGraphQLRoutes(ctx *context.Context, e *echo.Echo, services *service.Services, permissionChecker *auth.PermissionChecker, authMiddleware echo.MiddlewareFunc) error {
+	srv, err := createGraphQLHandler(ctx, services, permissionChecker)
+	if err != nil {
+		return err
+	}
 
 	graphqlGroup := e.Group("")
 	graphqlGroup.Use(authMiddleware)
 	graphqlGroup.POST("/graphql", echo.WrapHandler(srv))
+	return nil
 }
 
-func createGraphQLHandler(ctx *context.Context, services *service.Services, permissionChecker *auth.PermissionChecker) *handler.Server {
+func createGraphQLHandler(ctx *context.Context, services *service.Services, permissionChecker *auth.PermissionChecker) (*handler.Server, error) {
 	srv := handler.New(graph.NewExecutableSchema(graph.Config{
 		Resolvers: &resolver.Resolver{
 			PermissionChecker:       permissionChecker,
 			NamespaceService:        services.Namespace,
+			NamespacePolicyService:  services.NamespacePolicy,
 			ProjectService:          services.Project,
 			UserService:             services.User,
 			RoleService:             services.Role,
@@ -127,34 +186,77 @@ func createGraphQLHandler(ctx *context.Context, services *service.Services, perm
 			RedirectService:         services.Redirect,
 			RedirectDraftService:    services.RedirectDraft,
 			RedirectImportService:   services.RedirectImport,
+			RedirectHealthService:   services.RedirectHealth,
+			PageLinkService:         services.PageLink,
+			HitService:              services.Hit,
 			PageService:             services.Page,
 			PageDraftService:        services.PageDraft,
+			PageTemplateService:     services.PageTemplate,
 			AgentService:            services.Agent,
+			AgentInstanceService:    services.AgentInstance,
 			ProjectDashboardService: services.ProjectDashboard,
+			ProjectApplyService:     services.ProjectApply,
+			GitSyncService:          services.GitSync,
+			DraftLockService:        services.DraftLock,
+			DraftBatchService:       services.DraftBatch,
+			ChangesetService:        services.Changeset,
+			ImportProfileService:    services.ImportProfile,
+			NotificationService:     services.Notification,
+			ProjectAPIKeyService:    services.ProjectAPIKey,
+			ProjectMemberService:    services.ProjectMember,
+			GroupService:            services.Group,
+			SearchService:           services.Search,
 			AgentConfig:             ctx.Config.Agent,
+			PasswordConfig:          ctx.Config.Auth.Password,
 		},
 		Directives: graph.DirectiveRoot{Public: graph.PublicDirective},
 	}))
 
+	srv.SetErrorPresenter(graph.ErrorPresenter)
 	srv.AroundFields(graph.AuthMiddleware)
+	srv.AroundOperations(loader.Middleware(services.Namespace, services.Project))
+	srv.AroundOperations(graph.ReadOnlyMiddleware(ctx.ReadOnlyError))
+	if len(ctx.Config.DB.Shards) > 0 {
+		srv.AroundFields(graph.NamespaceMiddleware)
+	}
+	if len(ctx.Config.DB.Replicas) > 0 {
+		srv.AroundOperations(graph.PrimaryMiddleware)
+	}
 
 	// Add transports
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
 	srv.AddTransport(transport.MultipartForm{
-		MaxMemory:     2 << 20, // 2MB
-		MaxUploadSize: 2 << 20, // 2MB
+		MaxMemory:     2 << 20,                               // 2MB, larger uploads are buffered on disk
+		MaxUploadSize: ctx.Config.Import.MaxFileSize + 1<<20, // room for the other form fields
 	})
 
 	// Add extensions
+	graphqlCfg := ctx.Config.HTTP.GraphQL
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
-	srv.Use(extension.Introspection{})
-	srv.Use(extension.AutomaticPersistedQuery{
-		Cache: lru.New[string](100),
-	})
+	if graphqlCfg.Introspection {
+		srv.Use(extension.Introspection{})
+	} else {
+		srv.AroundOperations(graph.NoIntrospectionMiddleware)
+	}
+	if graphqlCfg.PersistedQueries.File != "" {
+		persistedQueries, err := graph.LoadPersistedQueries(graphqlCfg.PersistedQueries.File, graphqlCfg.PersistedQueries.Enforce)
+		if err != nil {
+			return nil, err
+		}
+		srv.Use(persistedQueries)
+	}
+	if !graphqlCfg.PersistedQueries.Enforce {
+		srv.Use(extension.AutomaticPersistedQuery{
+			Cache: lru.New[string](100),
+		})
+	}
+	if graphqlCfg.DepthLimit > 0 || graphqlCfg.ComplexityLimit > 0 {
+		srv.Use(&graph.OperationLimits{Depth: graphqlCfg.DepthLimit, Complexity: graphqlCfg.ComplexityLimit})
+	}
 
-	return srv
+	return srv, nil
 }
 
 func setupAPIRoutes(e *echo.Echo, services *service.Services, permissionChecker *auth.PermissionChecker, authMiddleware echo.MiddlewareFunc) {
@@ -162,18 +264,64 @@ func setupAPIRoutes(e *echo.Echo, services *service.Services, permissionChecker
 	apiGroup.Use(authMiddleware)
 
 	namespacesGroup := apiGroup.Group("/namespace")
-	namespaceGroup := namespacesGroup.Group("/:" + route.NamespaceCodeKey)
+	namespaceGroup := namespacesGroup.Group("/:"+route.NamespaceCodeKey, route.NamespaceMiddleware())
 	projectsGroup := namespaceGroup.Group("/project")
 	projectGroup := projectsGroup.Group("/:" + route.ProjectCodeKey)
 
 	projectGroup.GET("/version", project.GetVersion(permissionChecker, services.Project))
-	projectGroup.GET("/redirects", project.GetRedirects(permissionChecker, services.Redirect))
-	projectGroup.GET("/pages", project.GetPages(permissionChecker, services.Page))
+	projectGroup.GET("/redirects", project.GetRedirects(permissionChecker, services.Redirect, services.Project, services.SnapshotCache))
+	projectGroup.GET("/redirects/export", project.GetRedirectsExport(permissionChecker, services.RedirectExport))
+	projectGroup.GET("/pages", project.GetPages(permissionChecker, services.Page, services.Project, services.SnapshotCache))
+	projectGroup.GET("/bundle", project.GetBundle(permissionChecker, services.Project))
 	projectGroup.POST("/agents", project.PostAgent(permissionChecker, services.Agent))
 	projectGroup.PATCH(fmt.Sprintf("/agents/:%s/hit", route.NameKey), project.PatchAgentHit(permissionChecker, services.Agent))
+	projectGroup.GET("/rollout", project.GetRollout(permissionChecker, services.AgentInstance))
+	projectGroup.POST("/hits", project.PostHits(permissionChecker, services.Hit))
+
+	agentsGroup := apiGroup.Group("/agents")
+	agentsGroup.GET("", agent.GetAgents(permissionChecker, services.AgentInstance))
+	agentsGroup.POST("/register", agent.PostRegister(permissionChecker, services.AgentInstance))
+	agentsGroup.POST(fmt.Sprintf("/:%s/heartbeat", route.NameKey), agent.PostHeartbeat(permissionChecker, services.AgentInstance))
+	agentsGroup.POST(fmt.Sprintf("/:%s/ack", route.NameKey), agent.PostAck(permissionChecker, services.AgentInstance))
 }
 
-func setupMetrics(ctx *context.Context, e *echo.Echo, agentService service.AgentService) {
+// setupWebhookRoutes registers the routes called by external services, authenticated by their own secret
+func setupWebhookRoutes(ctx *context.Context, e *echo.Echo, services *service.Services) {
+	namespaceGroup := e.Group("/webhook/git/namespace/:"+route.NamespaceCodeKey, route.NamespaceMiddleware(), route.ReadOnlyMiddleware(ctx))
+	namespaceGroup.POST("/project/:"+route.ProjectCodeKey, webhook.PostGitPush(services.GitSync))
+}
+
+// setupSCIMRoutes registers the SCIM 2.0 endpoints provisioning the users and the groups from an identity provider,
+// authenticated by an API token with the users and roles admin permissions
+func setupSCIMRoutes(ctx *context.Context, e *echo.Echo, services *service.Services, permissionChecker *auth.PermissionChecker, authMiddleware echo.MiddlewareFunc) {
+	scimGroup := e.Group("/scim/v2")
+	scimGroup.Use(authMiddleware, route.ReadOnlyMiddleware(ctx))
+
+	scimGroup.GET("/ServiceProviderConfig", scim.GetServiceProviderConfig())
+	scimGroup.GET("/Users", scim.GetUsers(permissionChecker, services.User, services.Role))
+	scimGroup.POST("/Users", scim.PostUser(permissionChecker, services.User, services.Role))
+	scimGroup.GET("/Users/:"+scim.IDKey, scim.GetUser(permissionChecker, services.User, services.Role))
+	scimGroup.PUT("/Users/:"+scim.IDKey, scim.PutUser(permissionChecker, services.User, services.Role))
+	scimGroup.PATCH("/Users/:"+scim.IDKey, scim.PatchUser(permissionChecker, services.User, services.Role))
+	scimGroup.DELETE("/Users/:"+scim.IDKey, scim.DeleteUser(permissionChecker, services.User))
+	scimGroup.GET("/Groups", scim.GetGroups(permissionChecker, services.Role))
+	scimGroup.POST("/Groups", scim.PostGroup(permissionChecker, services.Role))
+	scimGroup.GET("/Groups/:"+scim.IDKey, scim.GetGroup(permissionChecker, services.Role))
+	scimGroup.PUT("/Groups/:"+scim.IDKey, scim.PutGroup(permissionChecker, services.Role))
+	scimGroup.PATCH("/Groups/:"+scim.IDKey, scim.PatchGroup(permissionChecker, services.Role))
+	scimGroup.DELETE("/Groups/:"+scim.IDKey, scim.DeleteGroup(permissionChecker, services.Role))
+}
+
+func setupAdminRoutes(ctx *context.Context, e *echo.Echo, services *service.Services, permissionChecker *auth.PermissionChecker, authMiddleware echo.MiddlewareFunc) {
+	adminGroup := e.Group("/admin")
+	adminGroup.POST("/config/reload", admin.PostConfigReload(ctx, permissionChecker), authMiddleware)
+	adminGroup.GET("/read-only", admin.GetReadOnly(ctx, permissionChecker), authMiddleware)
+	adminGroup.PUT("/read-only", admin.PutReadOnly(ctx, permissionChecker), authMiddleware)
+	adminGroup.GET("/scheduler/tasks", admin.GetScheduledTasks(permissionChecker, services.Scheduler), authMiddleware)
+	adminGroup.PUT("/scheduler/tasks/:"+admin.TaskNameKey, admin.PutScheduledTask(ctx, permissionChecker, services.Scheduler), authMiddleware)
+}
+
+func setupMetrics(ctx *context.Context, e *echo.Echo, agentService service.AgentService, retentionService service.RetentionService, snapshotCache service.SnapshotCache) {
 	// Add HTTP metrics middleware
 	e.Use(metrics.EchoMiddleware())
 
@@ -185,6 +333,13 @@ func setupMetrics(ctx *context.Context, e *echo.Echo, agentService service.Agent
 	// Start metrics collector (updates agent metrics periodically)
 	provider := metrics.NewAgentMetricsProvider(agentService)
 	metrics.StartCollector(ctx, provider, 30*time.Second)
+
+	if err := metrics.RegisterRetentionCollector(retentionService); err != nil {
+		ctx.Logger.Error("failed to register retention metrics", "error", err)
+	}
+	if err := metrics.RegisterSnapshotCacheCollector(snapshotCache); err != nil {
+		ctx.Logger.Error("failed to register snapshot cache metrics", "error", err)
+	}
 }
 
 func registerUI(ctx *context.Context, e *echo.Echo) {
@@ -194,7 +349,8 @@ func registerUI(ctx *context.Context, e *echo.Echo) {
 	handlerIndex := func(c echo.Context) error {
 		c.Response().Header().Set("Content-Type", "text/html; charset=utf-8")
 		indexTmpl, err := template.New("index.html").Funcs(map[string]any{
-			"AuthHeaderName": func() string { return ctx.Config.Auth.JWT.HeaderName },
+			"AuthHeaderName":    func() string { return ctx.Config.Auth.JWT.HeaderName },
+			"ImportMaxFileSize": func() int64 { return ctx.Config.Import.MaxFileSize },
 		}).ParseFS(distFS, "index.html")
 		if err != nil {
 			return c.String(http.StatusNotFound, "Not found")
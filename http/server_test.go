@@ -1,19 +1,28 @@
 package http
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/flectolab/flecto-manager/auth"
+	"github.com/flectolab/flecto-manager/config"
 	appContext "github.com/flectolab/flecto-manager/context"
 	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/service"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -41,7 +50,7 @@ func setupTestServices(t *testing.T, ctx *appContext.Context) (*service.Services
 	db := setupTestDB(t)
 	repos := repository.NewRepositories(db)
 	jwtService := jwt.NewServiceJWT(&ctx.Config.Auth.JWT)
-	services := service.NewServices(ctx, repos, jwtService)
+	services := service.NewServices(ctx, repos, jwtService, invalidation.NewMemoryBus())
 	return services, jwtService
 }
 
@@ -82,12 +91,13 @@ func TestSetupAuthRoutes(t *testing.T) {
 		ctx := setupTestContext(t)
 		ctx.Config.Auth.OpenID.Enabled = false
 		e := createServerHTTP()
-		services, jwtService := setupTestServices(t, ctx)
+		services, _ := setupTestServices(t, ctx)
+		permissionChecker := auth.NewPermissionChecker(services.Role)
 		authMiddleware := echo.MiddlewareFunc(func(next echo.HandlerFunc) echo.HandlerFunc {
 			return next
 		})
 
-		err := setupAuthRoutes(ctx, e, services, jwtService, authMiddleware)
+		err := setupAuthRoutes(ctx, e, services, permissionChecker, authMiddleware)
 
 		assert.NoError(t, err)
 
@@ -101,6 +111,8 @@ func TestSetupAuthRoutes(t *testing.T) {
 		assert.True(t, routePaths["POST:/auth/login"])
 		assert.True(t, routePaths["POST:/auth/refresh"])
 		assert.True(t, routePaths["POST:/auth/logout"])
+		assert.True(t, routePaths["POST:/auth/impersonate"])
+		assert.True(t, routePaths["GET:/auth/whoami"])
 		assert.True(t, routePaths["GET:/auth/openid"])
 	})
 
@@ -112,12 +124,13 @@ func TestSetupAuthRoutes(t *testing.T) {
 		ctx.Config.Auth.OpenID.ClientSecret = "test-secret"
 		ctx.Config.Auth.OpenID.RedirectURL = "http://localhost/callback"
 		e := createServerHTTP()
-		services, jwtService := setupTestServices(t, ctx)
+		services, _ := setupTestServices(t, ctx)
+		permissionChecker := auth.NewPermissionChecker(services.Role)
 		authMiddleware := echo.MiddlewareFunc(func(next echo.HandlerFunc) echo.HandlerFunc {
 			return next
 		})
 
-		err := setupAuthRoutes(ctx, e, services, jwtService, authMiddleware)
+		err := setupAuthRoutes(ctx, e, services, permissionChecker, authMiddleware)
 
 		// Should fail because provider URL is invalid
 		assert.Error(t, err)
@@ -134,7 +147,8 @@ func TestSetupGraphQLRoutes(t *testing.T) {
 		return next
 	})
 
-	setupGraphQLRoutes(ctx, e, services, permissionChecker, authMiddleware)
+	err := setupGraphQLRoutes(ctx, e, services, permissionChecker, authMiddleware)
+	assert.NoError(t, err)
 
 	// Verify GraphQL route is registered
 	routes := e.Routes()
@@ -151,11 +165,83 @@ func TestCreateGraphQLHandler(t *testing.T) {
 	services, _ := setupTestServices(t, ctx)
 	permissionChecker := auth.NewPermissionChecker(services.Role)
 
-	handler := createGraphQLHandler(ctx, services, permissionChecker)
+	handler, err := createGraphQLHandler(ctx, services, permissionChecker)
 
+	assert.NoError(t, err)
 	assert.NotNil(t, handler)
 }
 
+func TestCreateGraphQLHandler_Hardening(t *testing.T) {
+	post := func(t *testing.T, srv http.Handler, body string) map[string]any {
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, req)
+		var resp map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp
+	}
+	errorCode := func(resp map[string]any) any {
+		errs, _ := resp["errors"].([]any)
+		if len(errs) == 0 {
+			return nil
+		}
+		return errs[0].(map[string]any)["extensions"].(map[string]any)["code"]
+	}
+	persisted := `{ __typename }`
+	sum := sha256.Sum256([]byte(persisted))
+	hash := hex.EncodeToString(sum[:])
+
+	t.Run("introspection disabled", func(t *testing.T) {
+		ctx := setupTestContext(t)
+		services, _ := setupTestServices(t, ctx)
+		// Answered once authenticated
+		assert.Equal(t, "UNAUTHENTICATED", errorCode(post(t, mustGraphQLHandler(t, ctx, services), `{"query": "{ __schema { queryType { name } } }"}`)))
+
+		ctx.Config.HTTP.GraphQL.Introspection = false
+		srv := mustGraphQLHandler(t, ctx, services)
+		assert.Equal(t, "FORBIDDEN", errorCode(post(t, srv, `{"query": "{ __schema { queryType { name } } }"}`)))
+		assert.Nil(t, errorCode(post(t, srv, `{"query": "{ __typename }"}`)))
+	})
+
+	t.Run("complexity limit", func(t *testing.T) {
+		ctx := setupTestContext(t)
+		ctx.Config.HTTP.GraphQL.ComplexityLimit = 1
+		services, _ := setupTestServices(t, ctx)
+		resp := post(t, mustGraphQLHandler(t, ctx, services), `{"query": "{ agentInstances { name state } }"}`)
+		assert.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", errorCode(resp))
+	})
+
+	t.Run("persisted queries enforced", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "queries.json")
+		require.NoError(t, os.WriteFile(file, []byte(`{"`+hash+`": "{ __typename }"}`), 0o600))
+		ctx := setupTestContext(t)
+		ctx.Config.HTTP.GraphQL.PersistedQueries = config.PersistedQueriesConfig{File: file, Enforce: true}
+		services, _ := setupTestServices(t, ctx)
+		srv := mustGraphQLHandler(t, ctx, services)
+
+		resp := post(t, srv, `{"extensions": {"persistedQuery": {"version": 1, "sha256Hash": "`+hash+`"}}}`)
+		assert.Nil(t, errorCode(resp))
+		assert.Equal(t, map[string]any{"__typename": "Query"}, resp["data"])
+		assert.Nil(t, errorCode(post(t, srv, `{"query": "{ __typename }"}`)))
+		assert.Equal(t, "FORBIDDEN", errorCode(post(t, srv, `{"query": "{ __typename __schema { queryType { name } } }"}`)))
+	})
+
+	t.Run("invalid persisted queries file", func(t *testing.T) {
+		ctx := setupTestContext(t)
+		ctx.Config.HTTP.GraphQL.PersistedQueries.File = filepath.Join(t.TempDir(), "missing.json")
+		services, _ := setupTestServices(t, ctx)
+		_, err := createGraphQLHandler(ctx, services, auth.NewPermissionChecker(services.Role))
+		assert.Error(t, err)
+	})
+}
+
+func mustGraphQLHandler(t *testing.T, ctx *appContext.Context, services *service.Services) http.Handler {
+	srv, err := createGraphQLHandler(ctx, services, auth.NewPermissionChecker(services.Role))
+	require.NoError(t, err)
+	return srv
+}
+
 func TestSetupAPIRoutes(t *testing.T) {
 	ctx := setupTestContext(t)
 	e := createServerHTTP()
@@ -176,11 +262,63 @@ func TestSetupAPIRoutes(t *testing.T) {
 
 	assert.True(t, routePaths["GET:/api/namespace/:namespaceCode/project/:projectCode/version"])
 	assert.True(t, routePaths["GET:/api/namespace/:namespaceCode/project/:projectCode/redirects"])
+	assert.True(t, routePaths["GET:/api/namespace/:namespaceCode/project/:projectCode/redirects/export"])
 	assert.True(t, routePaths["GET:/api/namespace/:namespaceCode/project/:projectCode/pages"])
+	assert.True(t, routePaths["GET:/api/namespace/:namespaceCode/project/:projectCode/bundle"])
 	assert.True(t, routePaths["POST:/api/namespace/:namespaceCode/project/:projectCode/agents"])
 	assert.True(t, routePaths["PATCH:/api/namespace/:namespaceCode/project/:projectCode/agents/:name/hit"])
 }
 
+func TestSetupAdminRoutes(t *testing.T) {
+	ctx := setupTestContext(t)
+	e := createServerHTTP()
+	authMiddleware := echo.MiddlewareFunc(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return next
+	})
+
+	services, _ := setupTestServices(t, ctx)
+	setupAdminRoutes(ctx, e, services, auth.NewPermissionChecker(nil), authMiddleware)
+
+	routePaths := make(map[string]bool)
+	for _, r := range e.Routes() {
+		routePaths[r.Method+":"+r.Path] = true
+	}
+
+	assert.True(t, routePaths["POST:/admin/config/reload"])
+	assert.True(t, routePaths["GET:/admin/read-only"])
+	assert.True(t, routePaths["PUT:/admin/read-only"])
+	assert.True(t, routePaths["GET:/admin/scheduler/tasks"])
+	assert.True(t, routePaths["PUT:/admin/scheduler/tasks/:name"])
+}
+
+func TestSetupSCIMRoutes(t *testing.T) {
+	ctx := setupTestContext(t)
+	e := createServerHTTP()
+	services, _ := setupTestServices(t, ctx)
+	permissionChecker := auth.NewPermissionChecker(services.Role)
+	authMiddleware := echo.MiddlewareFunc(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return next
+	})
+
+	setupSCIMRoutes(ctx, e, services, permissionChecker, authMiddleware)
+
+	routes := e.Routes()
+	routePaths := make(map[string]bool)
+	for _, r := range routes {
+		routePaths[r.Method+":"+r.Path] = true
+	}
+
+	assert.True(t, routePaths["GET:/scim/v2/ServiceProviderConfig"])
+	for _, resource := range []string{"Users", "Groups"} {
+		assert.True(t, routePaths["GET:/scim/v2/"+resource])
+		assert.True(t, routePaths["POST:/scim/v2/"+resource])
+		assert.True(t, routePaths["GET:/scim/v2/"+resource+"/:id"])
+		assert.True(t, routePaths["PUT:/scim/v2/"+resource+"/:id"])
+		assert.True(t, routePaths["PATCH:/scim/v2/"+resource+"/:id"])
+		assert.True(t, routePaths["DELETE:/scim/v2/"+resource+"/:id"])
+	}
+}
+
 func TestRegisterUI(t *testing.T) {
 	ctx := setupTestContext(t)
 	e := createServerHTTP()
@@ -233,7 +371,7 @@ func TestSetupMetrics(t *testing.T) {
 		e := createServerHTTP()
 		services, _ := setupTestServices(t, ctx)
 
-		setupMetrics(ctx, e, services.Agent)
+		setupMetrics(ctx, e, services.Agent, services.Retention, services.SnapshotCache)
 
 		// Verify /metrics route is registered
 		routes := e.Routes()
@@ -250,6 +388,7 @@ func TestSetupMetrics(t *testing.T) {
 
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Contains(t, rec.Body.String(), "go_gc_duration_seconds")
+		assert.Contains(t, rec.Body.String(), `flecto_retention_purged_rows_total{category="import_jobs"} 0`)
 	})
 
 	t.Run("with separate listen address", func(t *testing.T) {
@@ -260,7 +399,7 @@ func TestSetupMetrics(t *testing.T) {
 		e := createServerHTTP()
 		services, _ := setupTestServices(t, ctx)
 
-		setupMetrics(ctx, e, services.Agent)
+		setupMetrics(ctx, e, services.Agent, services.Retention, services.SnapshotCache)
 
 		// Verify /metrics route is NOT registered on main server
 		routes := e.Routes()
@@ -279,7 +418,7 @@ func TestSetupMetrics(t *testing.T) {
 		e := createServerHTTP()
 		services, _ := setupTestServices(t, ctx)
 
-		setupMetrics(ctx, e, services.Agent)
+		setupMetrics(ctx, e, services.Agent, services.Retention, services.SnapshotCache)
 
 		// Add a test route
 		e.GET("/test", func(c echo.Context) error {
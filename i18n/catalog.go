@@ -0,0 +1,143 @@
+package i18n
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/go-playground/validator/v10"
+)
+
+// catalog are the messages of the codes in the languages other than English, the English messages of the errors
+// being more precise than the message of their code
+var catalog = map[Language]map[flectoErrors.Code]string{
+	French: {
+		flectoErrors.CodeInternal:                "Une erreur inattendue est survenue",
+		flectoErrors.CodeInvalidRequest:          "La requête est invalide",
+		flectoErrors.CodeUnauthenticated:         "Authentification requise",
+		flectoErrors.CodeInvalidCredentials:      "Identifiant ou mot de passe incorrect",
+		flectoErrors.CodeTokenExpired:            "Le jeton a expiré",
+		flectoErrors.CodeUserInactive:            "Le compte utilisateur est désactivé",
+		flectoErrors.CodePasswordPolicy:          "Le mot de passe ne respecte pas la politique de mots de passe",
+		flectoErrors.CodeForbidden:               "Vous n'avez pas la permission d'effectuer cette action",
+		flectoErrors.CodeNotFound:                "La ressource est introuvable",
+		flectoErrors.CodeAlreadyExists:           "La ressource existe déjà",
+		flectoErrors.CodeConflict:                "La modification est incompatible avec l'état actuel de la ressource",
+		flectoErrors.CodeRateLimited:             "Trop de requêtes, réessayez plus tard",
+		flectoErrors.CodeUnavailable:             "Le service est indisponible, réessayez plus tard",
+		flectoErrors.CodeUnsupported:             "Cette fonctionnalité n'est pas prise en charge",
+		flectoErrors.CodeNothingToPublish:        "Il n'y a rien à publier",
+		flectoErrors.CodeNothingToPromote:        "L'environnement est déjà à jour",
+		flectoErrors.CodePublishInProgress:       "Une publication du projet est déjà en cours",
+		flectoErrors.CodePublishVetoed:           "La publication a été refusée par un hook de validation",
+		flectoErrors.CodeMassDeletion:            "Suppression massive refusée, forcez-la pour continuer",
+		flectoErrors.CodeRedirectChainTooLong:    "La publication laisserait une chaîne de redirections trop longue",
+		flectoErrors.CodeDraftLocked:             "Le brouillon est verrouillé par un autre utilisateur",
+		flectoErrors.CodeSizeLimitExceeded:       "La taille maximale autorisée est dépassée",
+		flectoErrors.CodeSecretDetected:          "Le contenu semble contenir un secret",
+		flectoErrors.CodePolicyViolation:         "La redirection enfreint la politique de son espace de noms",
+		flectoErrors.CodeReadOnly:                "Le gestionnaire est en lecture seule pour maintenance",
+		flectoErrors.CodeDepthLimitExceeded:      "La requête imbrique trop de champs",
+		flectoErrors.CodeComplexityLimitExceeded: "La requête est trop complexe",
+	},
+	German: {
+		flectoErrors.CodeInternal:                "Ein unerwarteter Fehler ist aufgetreten",
+		flectoErrors.CodeInvalidRequest:          "Die Anfrage ist ungültig",
+		flectoErrors.CodeUnauthenticated:         "Anmeldung erforderlich",
+		flectoErrors.CodeInvalidCredentials:      "Benutzername oder Passwort ist falsch",
+		flectoErrors.CodeTokenExpired:            "Das Token ist abgelaufen",
+		flectoErrors.CodeUserInactive:            "Das Benutzerkonto ist deaktiviert",
+		flectoErrors.CodePasswordPolicy:          "Das Passwort entspricht nicht der Passwortrichtlinie",
+		flectoErrors.CodeForbidden:               "Sie haben keine Berechtigung für diese Aktion",
+		flectoErrors.CodeNotFound:                "Die Ressource wurde nicht gefunden",
+		flectoErrors.CodeAlreadyExists:           "Die Ressource existiert bereits",
+		flectoErrors.CodeConflict:                "Die Änderung ist mit dem aktuellen Zustand der Ressource nicht vereinbar",
+		flectoErrors.CodeRateLimited:             "Zu viele Anfragen, versuchen Sie es später erneut",
+		flectoErrors.CodeUnavailable:             "Der Dienst ist nicht verfügbar, versuchen Sie es später erneut",
+		flectoErrors.CodeUnsupported:             "Diese Funktion wird nicht unterstützt",
+		flectoErrors.CodeNothingToPublish:        "Es gibt nichts zu veröffentlichen",
+		flectoErrors.CodeNothingToPromote:        "Die Umgebung ist bereits aktuell",
+		flectoErrors.CodePublishInProgress:       "Eine Veröffentlichung des Projekts läuft bereits",
+		flectoErrors.CodePublishVetoed:           "Die Veröffentlichung wurde von einem Validierungs-Hook abgelehnt",
+		flectoErrors.CodeMassDeletion:            "Massenlöschung abgelehnt, erzwingen Sie sie, um fortzufahren",
+		flectoErrors.CodeRedirectChainTooLong:    "Die Veröffentlichung würde eine zu lange Weiterleitungskette hinterlassen",
+		flectoErrors.CodeDraftLocked:             "Der Entwurf ist von einem anderen Benutzer gesperrt",
+		flectoErrors.CodeSizeLimitExceeded:       "Die maximal zulässige Größe ist überschritten",
+		flectoErrors.CodeSecretDetected:          "Der Inhalt scheint ein Geheimnis zu enthalten",
+		flectoErrors.CodePolicyViolation:         "Die Weiterleitung verstößt gegen die Richtlinie ihres Namensraums",
+		flectoErrors.CodeReadOnly:                "Der Manager ist wegen Wartung schreibgeschützt",
+		flectoErrors.CodeDepthLimitExceeded:      "Die Abfrage verschachtelt zu viele Felder",
+		flectoErrors.CodeComplexityLimitExceeded: "Die Abfrage ist zu komplex",
+	},
+	Spanish: {
+		flectoErrors.CodeInternal:                "Se ha producido un error inesperado",
+		flectoErrors.CodeInvalidRequest:          "La solicitud no es válida",
+		flectoErrors.CodeUnauthenticated:         "Se requiere autenticación",
+		flectoErrors.CodeInvalidCredentials:      "Usuario o contraseña incorrectos",
+		flectoErrors.CodeTokenExpired:            "El token ha caducado",
+		flectoErrors.CodeUserInactive:            "La cuenta de usuario está desactivada",
+		flectoErrors.CodePasswordPolicy:          "La contraseña no cumple la política de contraseñas",
+		flectoErrors.CodeForbidden:               "No tiene permiso para realizar esta acción",
+		flectoErrors.CodeNotFound:                "No se ha encontrado el recurso",
+		flectoErrors.CodeAlreadyExists:           "El recurso ya existe",
+		flectoErrors.CodeConflict:                "El cambio no es compatible con el estado actual del recurso",
+		flectoErrors.CodeRateLimited:             "Demasiadas solicitudes, inténtelo de nuevo más tarde",
+		flectoErrors.CodeUnavailable:             "El servicio no está disponible, inténtelo de nuevo más tarde",
+		flectoErrors.CodeUnsupported:             "Esta funcionalidad no está soportada",
+		flectoErrors.CodeNothingToPublish:        "No hay nada que publicar",
+		flectoErrors.CodeNothingToPromote:        "El entorno ya está actualizado",
+		flectoErrors.CodePublishInProgress:       "Ya hay una publicación del proyecto en curso",
+		flectoErrors.CodePublishVetoed:           "La publicación ha sido rechazada por un hook de validación",
+		flectoErrors.CodeMassDeletion:            "Eliminación masiva rechazada, fuércela para continuar",
+		flectoErrors.CodeRedirectChainTooLong:    "La publicación dejaría una cadena de redirecciones demasiado larga",
+		flectoErrors.CodeDraftLocked:             "El borrador está bloqueado por otro usuario",
+		flectoErrors.CodeSizeLimitExceeded:       "Se ha superado el tamaño máximo permitido",
+		flectoErrors.CodeSecretDetected:          "El contenido parece contener un secreto",
+		flectoErrors.CodePolicyViolation:         "La redirección infringe la política de su espacio de nombres",
+		flectoErrors.CodeReadOnly:                "El gestor está en modo de solo lectura por mantenimiento",
+		flectoErrors.CodeDepthLimitExceeded:      "La consulta anida demasiados campos",
+		flectoErrors.CodeComplexityLimitExceeded: "La consulta es demasiado compleja",
+	},
+}
+
+// Localize returns the error returned to the clients for err, see errors.From, in the language of the context. The
+// message of a VALIDATION_FAILED error is the translation of each violation, also set in the details of the
+// violation, the message of the other errors being the one of their code in the catalog, when the language is not
+// English.
+func Localize(ctx context.Context, err error) *flectoErrors.Error {
+	apiErr := flectoErrors.From(err)
+	lang := FromContext(ctx)
+
+	if apiErr.Code == flectoErrors.CodeValidationFailed {
+		if validationErrs := validationErrors(apiErr); len(validationErrs) > 0 {
+			return localizeValidation(lang, apiErr, validationErrs)
+		}
+	}
+	if message, ok := catalog[lang][apiErr.Code]; ok {
+		return apiErr.WithMessage(message)
+	}
+	return apiErr
+}
+
+func localizeValidation(lang Language, apiErr *flectoErrors.Error, validationErrs validator.ValidationErrors) *flectoErrors.Error {
+	violations, _ := apiErr.Details["violations"].([]map[string]string)
+	localized := make([]map[string]string, 0, len(violations))
+	messages := make([]string, 0, len(validationErrs))
+	for i, fieldErr := range validationErrs {
+		message := translateValidation(lang, fieldErr)
+		messages = append(messages, message)
+		if i < len(violations) {
+			violation := maps.Clone(violations[i])
+			violation["message"] = message
+			localized = append(localized, violation)
+		}
+	}
+
+	details := maps.Clone(apiErr.Details)
+	if details == nil {
+		details = make(map[string]any)
+	}
+	details["violations"] = localized
+	return apiErr.WithMessage(strings.Join(messages, "; ")).WithDetails(details)
+}
@@ -0,0 +1,83 @@
+// Package i18n localizes the errors returned to the API clients in the language of the operator, the one of their
+// preference or the one negotiated from the Accept-Language header of their requests.
+package i18n
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Language is a supported language, identified by its ISO 639-1 code
+type Language string
+
+const (
+	English Language = "en"
+	French  Language = "fr"
+	German  Language = "de"
+	Spanish Language = "es"
+)
+
+// DefaultLanguage is the language of the requests without a supported language, the messages being written in it
+const DefaultLanguage = English
+
+// Languages are the supported languages
+var Languages = []Language{English, French, German, Spanish}
+
+// Parse returns the supported language of a language tag, its region being ignored ("fr-CA" is French)
+func Parse(tag string) (Language, bool) {
+	primary, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	lang := Language(strings.ToLower(primary))
+	return lang, slices.Contains(Languages, lang)
+}
+
+// Negotiate returns the supported language of the highest quality in an Accept-Language header, DefaultLanguage
+// when it has none
+func Negotiate(acceptLanguage string) Language {
+	type candidate struct {
+		lang    Language
+		quality float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		lang, ok := Parse(tag)
+		if !ok {
+			continue
+		}
+		quality := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = q
+			}
+		}
+		if quality > 0 {
+			candidates = append(candidates, candidate{lang: lang, quality: quality})
+		}
+	}
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	return candidates[0].lang
+}
+
+type languageKey struct{}
+
+// WithLanguage returns a context carrying the language the errors of the request are localized in
+func WithLanguage(ctx context.Context, lang Language) context.Context {
+	return context.WithValue(ctx, languageKey{}, lang)
+}
+
+// FromContext returns the language set by WithLanguage, DefaultLanguage when none is set
+func FromContext(ctx context.Context) Language {
+	if ctx == nil {
+		return DefaultLanguage
+	}
+	if lang, ok := ctx.Value(languageKey{}).(Language); ok {
+		return lang
+	}
+	return DefaultLanguage
+}
@@ -0,0 +1,90 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           Language
+	}{
+		{acceptLanguage: "", want: English},
+		{acceptLanguage: "fr-FR,fr;q=0.9,en;q=0.8", want: French},
+		{acceptLanguage: "it-IT, de;q=0.7, en;q=0.5", want: German},
+		{acceptLanguage: "en;q=0.5, es;q=0.8", want: Spanish},
+		{acceptLanguage: "fr;q=0, en", want: English},
+		{acceptLanguage: "ja", want: English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			assert.Equal(t, tt.want, Negotiate(tt.acceptLanguage))
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	lang, ok := Parse("FR-ca")
+	assert.True(t, ok)
+	assert.Equal(t, French, lang)
+
+	_, ok = Parse("it")
+	assert.False(t, ok)
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, English, FromContext(context.Background()))
+	assert.Equal(t, German, FromContext(WithLanguage(context.Background(), German)))
+}
+
+func TestLocalize(t *testing.T) {
+	errNothing := flectoErrors.New(flectoErrors.CodeNothingToPublish, "nothing to publish")
+	err := fmt.Errorf("%w: project ns/proj", errNothing)
+
+	t.Run("english", func(t *testing.T) {
+		apiErr := Localize(context.Background(), err)
+		assert.Equal(t, "nothing to publish: project ns/proj", apiErr.Message)
+	})
+
+	t.Run("catalog", func(t *testing.T) {
+		apiErr := Localize(WithLanguage(context.Background(), French), err)
+		assert.Equal(t, flectoErrors.CodeNothingToPublish, apiErr.Code)
+		assert.Equal(t, "Il n'y a rien à publier", apiErr.Message)
+		assert.ErrorIs(t, apiErr, errNothing)
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		type input struct {
+			Name string `validate:"required"`
+			Code string `validate:"code"`
+		}
+		v := validator.New()
+		require.NoError(t, v.RegisterValidation("code", func(validator.FieldLevel) bool { return false }))
+		require.NoError(t, RegisterTranslations(v))
+		require.NoError(t, RegisterTranslations(validator.New()), "the translations are registered in each validator")
+
+		apiErr := Localize(WithLanguage(context.Background(), French), v.Struct(input{}))
+		assert.Equal(t, flectoErrors.CodeValidationFailed, apiErr.Code)
+		assert.Equal(t, "name", apiErr.Field)
+		assert.Equal(t, "Name est un champ obligatoire; Code ne peut contenir que des lettres, des chiffres, '_' et '-'", apiErr.Message)
+		assert.Equal(t, map[string]any{"violations": []map[string]string{
+			{"field": "name", "rule": "required", "message": "Name est un champ obligatoire"},
+			{"field": "code", "rule": "code", "message": "Code ne peut contenir que des lettres, des chiffres, '_' et '-'"},
+		}}, apiErr.Details)
+	})
+
+	t.Run("validation without translation", func(t *testing.T) {
+		type input struct {
+			Name string `validate:"required"`
+		}
+		apiErr := Localize(context.Background(), validator.New().Struct(input{}))
+		assert.Equal(t, "Name: required", apiErr.Message)
+	})
+}
@@ -0,0 +1,155 @@
+package i18n
+
+import (
+	"errors"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	"github.com/go-playground/locales/fr"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	deTranslations "github.com/go-playground/validator/v10/translations/de"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	esTranslations "github.com/go-playground/validator/v10/translations/es"
+	frTranslations "github.com/go-playground/validator/v10/translations/fr"
+)
+
+// defaultTranslations register the translations of the built-in validation rules of validator
+var defaultTranslations = map[Language]func(v *validator.Validate, trans ut.Translator) error{
+	English: enTranslations.RegisterDefaultTranslations,
+	French:  frTranslations.RegisterDefaultTranslations,
+	German:  deTranslations.RegisterDefaultTranslations,
+	Spanish: esTranslations.RegisterDefaultTranslations,
+}
+
+// ruleTranslations are the translations of the validation rules of flecto, {0} being the field
+var ruleTranslations = map[string]map[Language]string{
+	"code": {
+		English: "{0} may only contain letters, digits, '_' and '-'",
+		French:  "{0} ne peut contenir que des lettres, des chiffres, '_' et '-'",
+		German:  "{0} darf nur Buchstaben, Ziffern, '_' und '-' enthalten",
+		Spanish: "{0} solo puede contener letras, dígitos, '_' y '-'",
+	},
+	"username": {
+		English: "{0} must be a code or an email address",
+		French:  "{0} doit être un code ou une adresse e-mail",
+		German:  "{0} muss ein Code oder eine E-Mail-Adresse sein",
+		Spanish: "{0} debe ser un código o una dirección de correo electrónico",
+	},
+	"pattern": {
+		English: "{0} must be a valid regular expression",
+		French:  "{0} doit être une expression régulière valide",
+		German:  "{0} muss ein gültiger regulärer Ausdruck sein",
+		Spanish: "{0} debe ser una expresión regular válida",
+	},
+	"schedule": {
+		English: "{0} must be a valid cron expression",
+		French:  "{0} doit être une expression cron valide",
+		German:  "{0} muss ein gültiger Cron-Ausdruck sein",
+		Spanish: "{0} debe ser una expresión cron válida",
+	},
+	"git_url": {
+		English: "{0} must be an https URL without credentials",
+		French:  "{0} doit être une URL https sans identifiants",
+		German:  "{0} muss eine https-URL ohne Zugangsdaten sein",
+		Spanish: "{0} debe ser una URL https sin credenciales",
+	},
+	"invalid path": {
+		English: "{0} must be a valid path",
+		French:  "{0} doit être un chemin valide",
+		German:  "{0} muss ein gültiger Pfad sein",
+		Spanish: "{0} debe ser una ruta válida",
+	},
+	"invalid regex": {
+		English: "{0} must be a valid regular expression",
+		French:  "{0} doit être une expression régulière valide",
+		German:  "{0} muss ein gültiger regulärer Ausdruck sein",
+		Spanish: "{0} debe ser una expresión regular válida",
+	},
+}
+
+// translators are the translators of the validation errors. Their messages are added once, at init, the validators
+// registering them through a registeredTranslator.
+var translators = map[Language]ut.Translator{}
+
+func init() {
+	uni := ut.New(en.New(), en.New(), fr.New(), de.New(), es.New())
+	v := validator.New()
+	for _, lang := range Languages {
+		trans, _ := uni.GetTranslator(string(lang))
+		if err := registerTranslations(v, lang, trans); err != nil {
+			panic(err)
+		}
+		translators[lang] = registeredTranslator{Translator: trans}
+	}
+}
+
+// registeredTranslator is a translator whose messages are already added, the validators sharing it without adding
+// them again
+type registeredTranslator struct {
+	ut.Translator
+}
+
+func (registeredTranslator) Add(any, string, bool) error {
+	return nil
+}
+
+func (registeredTranslator) AddCardinal(any, string, locales.PluralRule, bool) error {
+	return nil
+}
+
+func (registeredTranslator) AddOrdinal(any, string, locales.PluralRule, bool) error {
+	return nil
+}
+
+func (registeredTranslator) AddRange(any, string, locales.PluralRule, bool) error {
+	return nil
+}
+
+// RegisterTranslations registers the translations of the validation errors of the validator in the supported
+// languages, the errors of the validators without them keeping the message of validator
+func RegisterTranslations(v *validator.Validate) error {
+	for _, lang := range Languages {
+		if err := registerTranslations(v, lang, translators[lang]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func registerTranslations(v *validator.Validate, lang Language, trans ut.Translator) error {
+	if err := defaultTranslations[lang](v, trans); err != nil {
+		return err
+	}
+	for rule, texts := range ruleTranslations {
+		text := texts[lang]
+		err := v.RegisterTranslation(rule, trans, func(trans ut.Translator) error {
+			return trans.Add(rule, text, false)
+		}, func(trans ut.Translator, fieldErr validator.FieldError) string {
+			message, _ := trans.T(fieldErr.Tag(), fieldErr.Field())
+			return message
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// translateValidation returns the message of a validation error in the language, the errors of the rules without
+// translation being described by their field and their rule
+func translateValidation(lang Language, fieldErr validator.FieldError) string {
+	if message := fieldErr.Translate(translators[lang]); message != "" && message != fieldErr.(error).Error() {
+		return message
+	}
+	return fieldErr.Field() + ": " + fieldErr.Tag()
+}
+
+// validationErrors returns the validation errors wrapped by err
+func validationErrors(err error) validator.ValidationErrors {
+	var validationErrs validator.ValidationErrors
+	errors.As(err, &validationErrs)
+	return validationErrs
+}
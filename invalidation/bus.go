@@ -0,0 +1,93 @@
+package invalidation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+)
+
+type EventType string
+
+const (
+	// EventTypeProjectPublished is sent when the drafts of a project are published
+	EventTypeProjectPublished EventType = "project_published"
+	// EventTypeProjectPromoted is sent when a project version is promoted to production
+	EventTypeProjectPromoted EventType = "project_promoted"
+	// EventTypeProjectDeleted is sent when a project is deleted or moved to another namespace
+	EventTypeProjectDeleted EventType = "project_deleted"
+	// EventTypePermissionsChanged is sent when roles, their permissions or their users change
+	EventTypePermissionsChanged EventType = "permissions_changed"
+	// EventTypeReset is sent to the local handlers when events from the other replicas may have been
+	// missed, e.g. after the listener reconnected, so they drop everything they cached
+	EventTypeReset EventType = "reset"
+)
+
+// Event tells the replicas that some of their cached data is stale
+type Event struct {
+	Type          EventType `json:"type"`
+	NamespaceCode string    `json:"namespaceCode,omitempty"`
+	ProjectCode   string    `json:"projectCode,omitempty"`
+	Version       int       `json:"version,omitempty"`
+	// Origin identifies the replica publishing the event
+	Origin string `json:"origin,omitempty"`
+}
+
+type Handler func(event Event)
+
+// Bus broadcasts the invalidation events to the handlers of every replica of the manager
+type Bus interface {
+	// Publish sends the event to the local handlers, then to the other replicas
+	Publish(ctx context.Context, event Event) error
+	Subscribe(handler Handler)
+	Close() error
+}
+
+// NewBus returns the bus of the driver set in the invalidation configuration
+func NewBus(ctx *appContext.Context) (Bus, error) {
+	switch ctx.Config.Invalidation.Driver {
+	case "", config.InvalidationDriverMemory:
+		return NewMemoryBus(), nil
+	case config.InvalidationDriverPostgres:
+		return NewPostgresBus(ctx)
+	default:
+		return nil, fmt.Errorf("unknown invalidation driver '%s'", ctx.Config.Invalidation.Driver)
+	}
+}
+
+// memoryBus only delivers the events to the handlers of the current replica, it is enough
+// when the manager runs a single replica
+type memoryBus struct {
+	mu       sync.RWMutex
+	handlers []Handler
+}
+
+func NewMemoryBus() Bus {
+	return &memoryBus{}
+}
+
+func (b *memoryBus) Publish(_ context.Context, event Event) error {
+	b.dispatch(event)
+	return nil
+}
+
+func (b *memoryBus) Subscribe(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
+
+func (b *memoryBus) Close() error {
+	return nil
+}
+
+func (b *memoryBus) dispatch(event Event) {
+	b.mu.RLock()
+	handlers := b.handlers
+	b.mu.RUnlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
@@ -0,0 +1,69 @@
+package invalidation
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBus(t *testing.T) {
+	ctx := appContext.TestContext(nil)
+
+	bus, err := NewBus(ctx)
+	require.NoError(t, err)
+	assert.IsType(t, &memoryBus{}, bus)
+
+	ctx.Config.Invalidation.Driver = config.InvalidationDriverPostgres
+	_, err = NewBus(ctx)
+	assert.ErrorContains(t, err, "dsn is required")
+
+	ctx.Config.Invalidation.Postgres.DSN = "postgres://localhost/flecto"
+	ctx.Config.Invalidation.Postgres.Channel = ""
+	_, err = NewBus(ctx)
+	assert.ErrorContains(t, err, "channel is required")
+
+	ctx.Config.Invalidation.Driver = "redis"
+	_, err = NewBus(ctx)
+	assert.ErrorContains(t, err, "unknown invalidation driver")
+}
+
+func TestMemoryBus(t *testing.T) {
+	bus := NewMemoryBus()
+	var first, second []Event
+	bus.Subscribe(func(event Event) { first = append(first, event) })
+	bus.Subscribe(func(event Event) { second = append(second, event) })
+
+	event := Event{Type: EventTypeProjectPublished, NamespaceCode: "ns", ProjectCode: "proj", Version: 2}
+	require.NoError(t, bus.Publish(context.Background(), event))
+
+	assert.Equal(t, []Event{event}, first)
+	assert.Equal(t, []Event{event}, second)
+	assert.NoError(t, bus.Close())
+}
+
+func TestPostgresBus_HandleNotification(t *testing.T) {
+	bus := &postgresBus{memoryBus: &memoryBus{}, ctx: appContext.TestContext(nil), channel: "flecto_invalidation", origin: "self"}
+	var events []Event
+	bus.Subscribe(func(event Event) { events = append(events, event) })
+
+	bus.handleNotification(`{"type":"project_published","namespaceCode":"ns","projectCode":"proj","version":3,"origin":"other"}`)
+	bus.handleNotification(`{"type":"project_deleted","namespaceCode":"ns","projectCode":"proj","origin":"self"}`)
+	bus.handleNotification(`not json`)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, Event{Type: EventTypeProjectPublished, NamespaceCode: "ns", ProjectCode: "proj", Version: 3, Origin: "other"}, events[0])
+}
+
+func TestNewOrigin(t *testing.T) {
+	first, err := newOrigin()
+	require.NoError(t, err)
+	second, err := newOrigin()
+	require.NoError(t, err)
+
+	assert.Len(t, first, 16)
+	assert.NotEqual(t, first, second)
+}
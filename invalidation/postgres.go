@@ -0,0 +1,178 @@
+package invalidation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	postgresConnectTimeout = 10 * time.Second
+	defaultRetryDelay      = 5 * time.Second
+)
+
+// postgresBus broadcasts the events to the other replicas with Postgres LISTEN/NOTIFY. Each replica
+// keeps a connection listening on the channel, the events it publishes itself being skipped since
+// they are already dispatched to its handlers when published.
+type postgresBus struct {
+	*memoryBus
+	ctx        *appContext.Context
+	pool       *pgxpool.Pool
+	channel    string
+	retryDelay time.Duration
+	origin     string
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func NewPostgresBus(ctx *appContext.Context) (Bus, error) {
+	cfg := ctx.Config.Invalidation.Postgres
+	if cfg.DSN == "" {
+		return nil, errors.New("invalidation.postgres.dsn is required with the postgres invalidation driver")
+	}
+	if cfg.Channel == "" {
+		return nil, errors.New("invalidation.postgres.channel is required with the postgres invalidation driver")
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = defaultRetryDelay
+	}
+	origin, err := newOrigin()
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.New(context.Background(), cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open invalidation database: %w", err)
+	}
+	pingCtx, cancelPing := context.WithTimeout(context.Background(), postgresConnectTimeout)
+	defer cancelPing()
+	if err = pool.Ping(pingCtx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to invalidation database: %w", err)
+	}
+
+	listenCtx, cancel := context.WithCancel(context.Background())
+	b := &postgresBus{
+		memoryBus:  &memoryBus{},
+		ctx:        ctx,
+		pool:       pool,
+		channel:    cfg.Channel,
+		retryDelay: cfg.RetryDelay,
+		origin:     origin,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go b.listen(listenCtx)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = b.Close()
+		case <-listenCtx.Done():
+		}
+	}()
+	return b, nil
+}
+
+func (b *postgresBus) Publish(ctx context.Context, event Event) error {
+	event.Origin = b.origin
+	b.dispatch(event)
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, err = b.pool.Exec(ctx, "SELECT pg_notify($1, $2)", b.channel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify invalidation event: %w", err)
+	}
+	return nil
+}
+
+func (b *postgresBus) Close() error {
+	b.closeOnce.Do(func() {
+		b.cancel()
+		<-b.done
+		b.pool.Close()
+	})
+	return nil
+}
+
+// listen receives the events of the other replicas until the bus is closed, reconnecting when the
+// connection is lost. Events sent while disconnected are lost, so a reset event is dispatched to
+// the local handlers once listening again.
+func (b *postgresBus) listen(ctx context.Context) {
+	defer close(b.done)
+	reconnected := false
+	for {
+		err := b.listenConn(ctx, func() {
+			if reconnected {
+				b.ctx.Logger.Info("invalidation listener reconnected", "channel", b.channel)
+				b.dispatch(Event{Type: EventTypeReset, Origin: b.origin})
+			}
+			reconnected = true
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		b.ctx.Logger.Warn("invalidation listener disconnected", "channel", b.channel, "retryIn", b.retryDelay, "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.retryDelay):
+		}
+	}
+}
+
+func (b *postgresBus) listenConn(ctx context.Context, onListen func()) error {
+	poolConn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// The connection is taken out of the pool since it stays subscribed to the channel
+	conn := poolConn.Hijack()
+	defer func() { _ = conn.Close(context.Background()) }()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{b.channel}.Sanitize()); err != nil {
+		return err
+	}
+	onListen()
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		b.handleNotification(notification.Payload)
+	}
+}
+
+// handleNotification dispatches an event received from the channel to the local handlers
+func (b *postgresBus) handleNotification(payload string) {
+	var event Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		b.ctx.Logger.Warn("invalid invalidation event", "channel", b.channel, "error", err)
+		return
+	}
+	if event.Origin == b.origin {
+		return
+	}
+	b.dispatch(event)
+}
+
+func newOrigin() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
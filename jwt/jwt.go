@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"time"
@@ -19,6 +20,9 @@ type Claims struct {
 	TokenType          types.TokenType           `json:"type"`
 	ExtraRoles         []string                  `json:"roles,omitempty"`
 	SubjectPermissions *model.SubjectPermissions `json:"permissions,omitempty"`
+	// ImpersonatorID and ImpersonatorUsername identify the administrator acting as the user
+	ImpersonatorID       int64  `json:"impUid,omitempty"`
+	ImpersonatorUsername string `json:"imp,omitempty"`
 }
 
 type ServiceJWT struct {
@@ -63,12 +67,39 @@ func (s *ServiceJWT) GenerateRefreshToken(user *model.User, authType types.AuthT
 	return s.generateToken(user, authType, types.TokenTypeRefresh, subjectPermissions, extraRoles, s.config.RefreshTokenTTL)
 }
 
+// GenerateImpersonationToken creates an access token acting as user on behalf of impersonator.
+// No refresh token is issued, the impersonation ends when the access token expires.
+func (s *ServiceJWT) GenerateImpersonationToken(user *model.User, impersonator *model.User) (string, int64, error) {
+	claims, expiresAt, err := s.newClaims(user, types.AuthTypeImpersonation, types.TokenTypeAccess, nil, nil, s.config.AccessTokenTTL)
+	if err != nil {
+		return "", 0, err
+	}
+	claims.ImpersonatorID = impersonator.ID
+	claims.ImpersonatorUsername = impersonator.Username
+	return s.signClaims(claims, expiresAt)
+}
+
 func (s *ServiceJWT) generateToken(user *model.User, authType types.AuthType, tokenType types.TokenType, subjectPermissions *model.SubjectPermissions, extraRoles []string, ttl time.Duration) (string, int64, error) {
+	claims, expiresAt, err := s.newClaims(user, authType, tokenType, subjectPermissions, extraRoles, ttl)
+	if err != nil {
+		return "", 0, err
+	}
+	return s.signClaims(claims, expiresAt)
+}
+
+func (s *ServiceJWT) newClaims(user *model.User, authType types.AuthType, tokenType types.TokenType, subjectPermissions *model.SubjectPermissions, extraRoles []string, ttl time.Duration) (*Claims, time.Time, error) {
 	now := time.Now()
 	expiresAt := now.Add(ttl)
 
+	// a unique ID keeps two tokens issued in the same second distinct, refresh tokens are stored by hash
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return nil, expiresAt, err
+	}
+
 	claims := &Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        hex.EncodeToString(jti),
 			Issuer:    s.config.Issuer,
 			Subject:   user.Username,
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -83,7 +114,10 @@ func (s *ServiceJWT) generateToken(user *model.User, authType types.AuthType, to
 	if len(extraRoles) > 0 {
 		claims.ExtraRoles = extraRoles
 	}
+	return claims, expiresAt, nil
+}
 
+func (s *ServiceJWT) signClaims(claims *Claims, expiresAt time.Time) (string, int64, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signedToken, err := token.SignedString(s.GetSecret())
 	if err != nil {
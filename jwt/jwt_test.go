@@ -97,6 +97,31 @@ func TestServiceJWT_GenerateAccessToken(t *testing.T) {
 	assert.Equal(t, cfg.Issuer, claims.Issuer)
 }
 
+func TestServiceJWT_GenerateImpersonationToken(t *testing.T) {
+	cfg := testConfig()
+	service := NewServiceJWT(cfg)
+	user := testUser()
+	admin := &model.User{ID: 1, Username: "admin"}
+
+	token, expiresAt, err := service.GenerateImpersonationToken(user, admin)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.InDelta(t, time.Now().Add(cfg.AccessTokenTTL).Unix(), expiresAt, 2)
+
+	claims := &Claims{}
+	parsedToken, err := service.parseToken(token, claims)
+
+	assert.NoError(t, err)
+	assert.True(t, parsedToken.Valid)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, user.Username, claims.Username)
+	assert.Equal(t, types.TokenTypeAccess, claims.TokenType)
+	assert.Equal(t, types.AuthTypeImpersonation, claims.AuthType)
+	assert.Equal(t, int64(1), claims.ImpersonatorID)
+	assert.Equal(t, "admin", claims.ImpersonatorUsername)
+}
+
 func TestServiceJWT_GenerateRefreshToken(t *testing.T) {
 	cfg := testConfig()
 	service := NewServiceJWT(cfg)
@@ -150,6 +175,20 @@ func TestServiceJWT_GenerateTokenPair(t *testing.T) {
 	assert.NotEqual(t, tokenPair.AccessToken, tokenPair.RefreshToken)
 }
 
+func TestServiceJWT_GenerateTokenPair_UniqueTokens(t *testing.T) {
+	service := NewServiceJWT(testConfig())
+	user := testUser()
+
+	first, err := service.GenerateTokenPair(user, types.AuthTypeBasic, nil, nil)
+	assert.NoError(t, err)
+	second, err := service.GenerateTokenPair(user, types.AuthTypeBasic, nil, nil)
+	assert.NoError(t, err)
+
+	// Tokens issued within the same second must still differ
+	assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+	assert.NotEqual(t, first.AccessToken, second.AccessToken)
+}
+
 func TestServiceJWT_generateToken(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -198,6 +237,7 @@ func TestServiceJWT_generateToken(t *testing.T) {
 			assert.Equal(t, user.Username, claims.Username)
 			assert.Equal(t, cfg.Issuer, claims.Issuer)
 			assert.Equal(t, user.Username, claims.Subject)
+			assert.Len(t, claims.ID, 32)
 		})
 	}
 }
@@ -0,0 +1,57 @@
+package markdown
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+// policy sanitizes the rendered HTML, keeping the markup of the markdown and the disabled checkboxes of the task lists
+var policy = newPolicy()
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// Render renders the markdown source into HTML with the options of cfg, and sanitizes it
+func Render(cfg config.PageMarkdownConfig, source string) (string, error) {
+	var buf bytes.Buffer
+	if err := newRenderer(cfg).Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return policy.Sanitize(buf.String()), nil
+}
+
+func newRenderer(cfg config.PageMarkdownConfig) goldmark.Markdown {
+	var extensions []goldmark.Extender
+	if cfg.GFM {
+		extensions = append(extensions, extension.GFM)
+	}
+	if cfg.Typographer {
+		extensions = append(extensions, extension.Typographer)
+	}
+	var parserOptions []parser.Option
+	if cfg.HeadingIDs {
+		parserOptions = append(parserOptions, parser.WithAutoHeadingID())
+	}
+	var rendererOptions []goldmark.Option
+	if cfg.HardWraps {
+		rendererOptions = append(rendererOptions, goldmark.WithRendererOptions(html.WithHardWraps()))
+	}
+	if cfg.RawHTML {
+		rendererOptions = append(rendererOptions, goldmark.WithRendererOptions(html.WithUnsafe()))
+	}
+	return goldmark.New(append(rendererOptions,
+		goldmark.WithExtensions(extensions...),
+		goldmark.WithParserOptions(parserOptions...),
+	)...)
+}
@@ -0,0 +1,83 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    config.PageMarkdownConfig
+		source string
+		want   string
+	}{
+		{
+			name:   "success basic markdown",
+			source: "# Title\n\nSome *text* with a [link](/page).",
+			want:   "<h1>Title</h1>\n<p>Some <em>text</em> with a <a href=\"/page\" rel=\"nofollow\">link</a>.</p>\n",
+		},
+		{
+			name:   "success gfm table and strikethrough",
+			cfg:    config.PageMarkdownConfig{GFM: true},
+			source: "| a |\n|---|\n| ~~b~~ |",
+			want:   "<table>\n<thead>\n<tr>\n<th>a</th>\n</tr>\n</thead>\n<tbody>\n<tr>\n<td><del>b</del></td>\n</tr>\n</tbody>\n</table>\n",
+		},
+		{
+			name:   "success gfm task list keeps the checkboxes",
+			cfg:    config.PageMarkdownConfig{GFM: true},
+			source: "- [x] done",
+			want:   "<ul>\n<li><input checked=\"\" disabled=\"\" type=\"checkbox\"> done</li>\n</ul>\n",
+		},
+		{
+			name:   "success without gfm",
+			source: "~~b~~",
+			want:   "<p>~~b~~</p>\n",
+		},
+		{
+			name:   "success hard wraps",
+			cfg:    config.PageMarkdownConfig{HardWraps: true},
+			source: "a\nb",
+			want:   "<p>a<br>\nb</p>\n",
+		},
+		{
+			name:   "success heading ids",
+			cfg:    config.PageMarkdownConfig{HeadingIDs: true},
+			source: "## Getting Started",
+			want:   "<h2 id=\"getting-started\">Getting Started</h2>\n",
+		},
+		{
+			name:   "success typographer",
+			cfg:    config.PageMarkdownConfig{Typographer: true},
+			source: "a -- b...",
+			want:   "<p>a – b…</p>\n",
+		},
+		{
+			name:   "success raw html left out by default",
+			source: "<div>kept?</div>\n\ntext",
+			want:   "\n<p>text</p>\n",
+		},
+		{
+			name:   "success raw html kept and sanitized",
+			cfg:    config.PageMarkdownConfig{RawHTML: true},
+			source: "<div onclick=\"alert(1)\">kept</div>\n<script>alert(1)</script>\n\ntext",
+			want:   "<div>kept</div>\n\n<p>text</p>\n",
+		},
+		{
+			name:   "success javascript links removed",
+			source: "[link](javascript:alert(1))",
+			want:   "<p>link</p>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.cfg, tt.source)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
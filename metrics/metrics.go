@@ -2,6 +2,7 @@ package metrics
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -192,6 +193,110 @@ func collectAgentMetrics(ctx *appContext.Context, provider AgentMetricsProvider)
 	}
 }
 
+// retentionPurgedRowsDesc describes the rows purged by the retention worker per category
+var retentionPurgedRowsDesc = prometheus.NewDesc(
+	"flecto_retention_purged_rows_total",
+	"Total number of rows purged by the retention worker",
+	[]string{"category"}, nil,
+)
+
+// retentionCollector exposes the rows purged by the retention service, read at each scrape
+type retentionCollector struct {
+	retentionService service.RetentionService
+}
+
+// NewRetentionCollector creates a collector of the rows purged by the retention service
+func NewRetentionCollector(retentionService service.RetentionService) prometheus.Collector {
+	return &retentionCollector{retentionService: retentionService}
+}
+
+func (c *retentionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- retentionPurgedRowsDesc
+}
+
+func (c *retentionCollector) Collect(ch chan<- prometheus.Metric) {
+	for category, count := range c.retentionService.PurgedRows() {
+		ch <- prometheus.MustNewConstMetric(retentionPurgedRowsDesc, prometheus.CounterValue, float64(count), string(category))
+	}
+}
+
+// RegisterRetentionCollector registers the collector of the rows purged by the retention service,
+// a collector already registered being kept
+func RegisterRetentionCollector(retentionService service.RetentionService) error {
+	err := prometheus.Register(NewRetentionCollector(retentionService))
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+	return err
+}
+
+var (
+	snapshotCacheHitsDesc = prometheus.NewDesc(
+		"flecto_snapshot_cache_hits_total",
+		"Total number of snapshot pages served to the agents from the cache",
+		nil, nil,
+	)
+	snapshotCacheMissesDesc = prometheus.NewDesc(
+		"flecto_snapshot_cache_misses_total",
+		"Total number of snapshot pages built because they were not cached",
+		nil, nil,
+	)
+	snapshotCacheEvictionsDesc = prometheus.NewDesc(
+		"flecto_snapshot_cache_evictions_total",
+		"Total number of snapshot pages evicted from the full cache",
+		nil, nil,
+	)
+	snapshotCacheEntriesDesc = prometheus.NewDesc(
+		"flecto_snapshot_cache_entries",
+		"Number of snapshot pages in the cache",
+		nil, nil,
+	)
+	snapshotCacheSizeDesc = prometheus.NewDesc(
+		"flecto_snapshot_cache_size_bytes",
+		"Size of the snapshot pages in the cache",
+		nil, nil,
+	)
+)
+
+// snapshotCacheCollector exposes the counters of the snapshot cache, read at each scrape
+type snapshotCacheCollector struct {
+	snapshotCache service.SnapshotCache
+}
+
+// NewSnapshotCacheCollector creates a collector of the counters of the snapshot cache
+func NewSnapshotCacheCollector(snapshotCache service.SnapshotCache) prometheus.Collector {
+	return &snapshotCacheCollector{snapshotCache: snapshotCache}
+}
+
+func (c *snapshotCacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- snapshotCacheHitsDesc
+	ch <- snapshotCacheMissesDesc
+	ch <- snapshotCacheEvictionsDesc
+	ch <- snapshotCacheEntriesDesc
+	ch <- snapshotCacheSizeDesc
+}
+
+func (c *snapshotCacheCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.snapshotCache.Stats()
+	ch <- prometheus.MustNewConstMetric(snapshotCacheHitsDesc, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(snapshotCacheMissesDesc, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(snapshotCacheEvictionsDesc, prometheus.CounterValue, float64(stats.Evictions))
+	ch <- prometheus.MustNewConstMetric(snapshotCacheEntriesDesc, prometheus.GaugeValue, float64(stats.Entries))
+	ch <- prometheus.MustNewConstMetric(snapshotCacheSizeDesc, prometheus.GaugeValue, float64(stats.Size))
+}
+
+// RegisterSnapshotCacheCollector registers the collector of the snapshot cache, a collector already registered
+// being kept
+func RegisterSnapshotCacheCollector(snapshotCache service.SnapshotCache) error {
+	err := prometheus.Register(NewSnapshotCacheCollector(snapshotCache))
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	if errors.As(err, &alreadyRegistered) {
+		return nil
+	}
+	return err
+}
+
 // StartServer starts a dedicated metrics server on the specified address
 func StartServer(ctx *appContext.Context, listen string) *http.Server {
 	mux := http.NewServeMux()
@@ -6,13 +6,16 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/config"
 	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/service"
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -27,6 +30,26 @@ func (m *mockAgentMetricsProvider) GetAgentCounts(ctx context.Context, onlineThr
 	return m.counts, m.err
 }
 
+// mockRetentionService is a mock implementation of the purged rows of RetentionService
+type mockRetentionService struct {
+	service.RetentionService
+	purged map[service.RetentionCategory]int64
+}
+
+func (m *mockRetentionService) PurgedRows() map[service.RetentionCategory]int64 {
+	return m.purged
+}
+
+// mockSnapshotCache is a mock implementation of the counters of SnapshotCache
+type mockSnapshotCache struct {
+	service.SnapshotCache
+	stats service.SnapshotCacheStats
+}
+
+func (m *mockSnapshotCache) Stats() service.SnapshotCacheStats {
+	return m.stats
+}
+
 func TestHandler(t *testing.T) {
 	h := Handler()
 	assert.NotNil(t, h)
@@ -405,3 +428,54 @@ func parseKey(key string) (string, string) {
 	}
 	return key, ""
 }
+
+func TestRetentionCollector(t *testing.T) {
+	collector := NewRetentionCollector(&mockRetentionService{purged: map[service.RetentionCategory]int64{
+		service.RetentionCategoryImportJobs: 12,
+		service.RetentionCategoryTombstones: 0,
+	}})
+
+	expected := `
+# HELP flecto_retention_purged_rows_total Total number of rows purged by the retention worker
+# TYPE flecto_retention_purged_rows_total counter
+flecto_retention_purged_rows_total{category="import_jobs"} 12
+flecto_retention_purged_rows_total{category="tombstones"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
+
+func TestRegisterRetentionCollector(t *testing.T) {
+	retentionService := &mockRetentionService{purged: map[service.RetentionCategory]int64{}}
+	t.Cleanup(func() {
+		prometheus.Unregister(NewRetentionCollector(retentionService))
+	})
+
+	assert.NoError(t, RegisterRetentionCollector(retentionService))
+	// Registering again keeps the collector already registered
+	assert.NoError(t, RegisterRetentionCollector(retentionService))
+}
+
+func TestSnapshotCacheCollector(t *testing.T) {
+	collector := NewSnapshotCacheCollector(&mockSnapshotCache{stats: service.SnapshotCacheStats{
+		Hits: 40, Misses: 2, Evictions: 1, Entries: 1, Size: 2048,
+	}})
+
+	expected := `
+# HELP flecto_snapshot_cache_entries Number of snapshot pages in the cache
+# TYPE flecto_snapshot_cache_entries gauge
+flecto_snapshot_cache_entries 1
+# HELP flecto_snapshot_cache_evictions_total Total number of snapshot pages evicted from the full cache
+# TYPE flecto_snapshot_cache_evictions_total counter
+flecto_snapshot_cache_evictions_total 1
+# HELP flecto_snapshot_cache_hits_total Total number of snapshot pages served to the agents from the cache
+# TYPE flecto_snapshot_cache_hits_total counter
+flecto_snapshot_cache_hits_total 40
+# HELP flecto_snapshot_cache_misses_total Total number of snapshot pages built because they were not cached
+# TYPE flecto_snapshot_cache_misses_total counter
+flecto_snapshot_cache_misses_total 2
+# HELP flecto_snapshot_cache_size_bytes Size of the snapshot pages in the cache
+# TYPE flecto_snapshot_cache_size_bytes gauge
+flecto_snapshot_cache_size_bytes 2048
+`
+	assert.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected)))
+}
@@ -0,0 +1,2 @@
+-- reverse: create "import_jobs" table
+DROP TABLE `import_jobs`;
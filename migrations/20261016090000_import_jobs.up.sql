@@ -0,0 +1,21 @@
+-- create "import_jobs" table
+CREATE TABLE `import_jobs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `status` varchar(20) NOT NULL,
+  `overwrite` bool NULL,
+  `total_lines` bigint NULL,
+  `processed_count` bigint NULL,
+  `imported_count` bigint NULL,
+  `skipped_count` bigint NULL,
+  `error_count` bigint NULL,
+  `errors` longtext NULL,
+  `error_message` varchar(500) NULL,
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  `finished_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_import_jobs_namespace_project` (`namespace_code`, `project_code`),
+  CONSTRAINT `fk_import_jobs_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,6 @@
+-- reverse: create "redirect_draft_tags" table
+DROP TABLE `redirect_draft_tags`;
+-- reverse: create "redirect_tags" table
+DROP TABLE `redirect_tags`;
+-- reverse: create "tags" table
+DROP TABLE `tags`;
@@ -0,0 +1,29 @@
+-- create "tags" table
+CREATE TABLE `tags` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `name` varchar(50) NOT NULL,
+  `created_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_tags_namespace_project_name` (`namespace_code`, `project_code`, `name`),
+  CONSTRAINT `fk_tags_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
+-- create "redirect_tags" table
+CREATE TABLE `redirect_tags` (
+  `redirect_id` bigint NOT NULL,
+  `tag_id` bigint NOT NULL,
+  PRIMARY KEY (`redirect_id`, `tag_id`),
+  INDEX `fk_redirect_tags_tag` (`tag_id`),
+  CONSTRAINT `fk_redirect_tags_redirect` FOREIGN KEY (`redirect_id`) REFERENCES `redirects` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE,
+  CONSTRAINT `fk_redirect_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
+-- create "redirect_draft_tags" table
+CREATE TABLE `redirect_draft_tags` (
+  `redirect_draft_id` bigint NOT NULL,
+  `tag_id` bigint NOT NULL,
+  PRIMARY KEY (`redirect_draft_id`, `tag_id`),
+  INDEX `fk_redirect_draft_tags_tag` (`tag_id`),
+  CONSTRAINT `fk_redirect_draft_tags_redirect_draft` FOREIGN KEY (`redirect_draft_id`) REFERENCES `redirect_drafts` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE,
+  CONSTRAINT `fk_redirect_draft_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
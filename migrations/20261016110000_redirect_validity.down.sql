@@ -0,0 +1,4 @@
+-- reverse: modify "redirects" table
+ALTER TABLE `redirects` DROP COLUMN `valid_until`, DROP COLUMN `valid_from`;
+-- reverse: modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP COLUMN `new_valid_until`, DROP COLUMN `new_valid_from`;
@@ -0,0 +1,4 @@
+-- modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` ADD COLUMN `new_valid_from` timestamp NULL, ADD COLUMN `new_valid_until` timestamp NULL;
+-- modify "redirects" table
+ALTER TABLE `redirects` ADD COLUMN `valid_from` timestamp NULL, ADD COLUMN `valid_until` timestamp NULL;
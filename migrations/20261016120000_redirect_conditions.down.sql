@@ -0,0 +1,4 @@
+-- reverse: modify "redirects" table
+ALTER TABLE `redirects` DROP INDEX `idx_redirects_source`, DROP COLUMN `conditions`, ADD UNIQUE INDEX `idx_redirects_source_unique` (`namespace_code`, `project_code`, `source`);
+-- reverse: modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP INDEX `idx_redirect_drafts_source`, DROP COLUMN `new_conditions`, ADD UNIQUE INDEX `idx_redirect_drafts_source_unique` (`namespace_code`, `project_code`, `new_source`);
@@ -0,0 +1,4 @@
+-- modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP INDEX `idx_redirect_drafts_source_unique`, ADD COLUMN `new_conditions` text NULL, ADD INDEX `idx_redirect_drafts_source` (`namespace_code`, `project_code`, `new_source`);
+-- modify "redirects" table
+ALTER TABLE `redirects` DROP INDEX `idx_redirects_source_unique`, ADD COLUMN `conditions` text NULL, ADD INDEX `idx_redirects_source` (`namespace_code`, `project_code`, `source`);
@@ -0,0 +1,2 @@
+-- reverse: create "redirect_health" table
+DROP TABLE `redirect_health`;
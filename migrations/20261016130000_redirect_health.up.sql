@@ -0,0 +1,10 @@
+-- create "redirect_health" table
+CREATE TABLE `redirect_health` (
+  `redirect_id` bigint NOT NULL,
+  `status_code` bigint NULL,
+  `latency_ms` bigint NULL,
+  `error` varchar(500) NULL,
+  `checked_at` timestamp NULL,
+  PRIMARY KEY (`redirect_id`),
+  CONSTRAINT `fk_redirects_health` FOREIGN KEY (`redirect_id`) REFERENCES `redirects` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,4 @@
+-- reverse: modify "redirects" table
+ALTER TABLE `redirects` DROP INDEX `idx_redirects_fulltext`, DROP INDEX `idx_redirects_fulltext_source`;
+-- reverse: modify "pages" table
+ALTER TABLE `pages` DROP INDEX `idx_pages_fulltext`, DROP INDEX `idx_pages_fulltext_path`;
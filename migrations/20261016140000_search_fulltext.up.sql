@@ -0,0 +1,8 @@
+-- modify "pages" table
+ALTER TABLE `pages` ADD FULLTEXT INDEX `idx_pages_fulltext_path` (`path`);
+-- modify "pages" table
+ALTER TABLE `pages` ADD FULLTEXT INDEX `idx_pages_fulltext` (`path`, `content`);
+-- modify "redirects" table
+ALTER TABLE `redirects` ADD FULLTEXT INDEX `idx_redirects_fulltext_source` (`source`);
+-- modify "redirects" table
+ALTER TABLE `redirects` ADD FULLTEXT INDEX `idx_redirects_fulltext` (`source`, `target`);
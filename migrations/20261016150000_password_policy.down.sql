@@ -0,0 +1,4 @@
+-- reverse: create "user_password_history" table
+DROP TABLE `user_password_history`;
+-- reverse: modify "users" table
+ALTER TABLE `users` DROP COLUMN `password_changed_at`, DROP COLUMN `must_change_password`;
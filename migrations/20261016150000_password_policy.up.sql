@@ -0,0 +1,12 @@
+-- modify "users" table
+ALTER TABLE `users` ADD COLUMN `must_change_password` bool NOT NULL DEFAULT 0, ADD COLUMN `password_changed_at` timestamp NULL;
+-- create "user_password_history" table
+CREATE TABLE `user_password_history` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` bigint NOT NULL,
+  `password` varchar(255) NOT NULL,
+  `created_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_user_password_history_user_id` (`user_id`),
+  CONSTRAINT `fk_user_password_history_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,4 @@
+-- reverse: create "refresh_tokens" table
+DROP TABLE `refresh_tokens`;
+-- reverse: modify "users" table
+ALTER TABLE `users` ADD COLUMN `refresh_token_hash` varchar(255) NULL;
@@ -0,0 +1,4 @@
+-- reverse: modify "resource_permissions" table
+ALTER TABLE `resource_permissions` DROP COLUMN `effect`;
+-- reverse: modify "admin_permissions" table
+ALTER TABLE `admin_permissions` DROP COLUMN `effect`;
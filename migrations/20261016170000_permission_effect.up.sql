@@ -0,0 +1,4 @@
+-- modify "admin_permissions" table
+ALTER TABLE `admin_permissions` ADD COLUMN `effect` varchar(10) NOT NULL DEFAULT 'ALLOW';
+-- modify "resource_permissions" table
+ALTER TABLE `resource_permissions` ADD COLUMN `effect` varchar(10) NOT NULL DEFAULT 'ALLOW';
@@ -0,0 +1,2 @@
+-- reverse: create "role_parents" table
+DROP TABLE `role_parents`;
@@ -0,0 +1,10 @@
+-- create "role_parents" table
+CREATE TABLE `role_parents` (
+  `role_id` bigint NOT NULL,
+  `parent_id` bigint NOT NULL,
+  `created_at` timestamp NULL,
+  PRIMARY KEY (`role_id`, `parent_id`),
+  INDEX `idx_role_parents_parent_id` (`parent_id`),
+  CONSTRAINT `fk_role_parents_parent` FOREIGN KEY (`parent_id`) REFERENCES `roles` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE,
+  CONSTRAINT `fk_role_parents_role` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
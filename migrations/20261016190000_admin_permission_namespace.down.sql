@@ -0,0 +1,2 @@
+-- reverse: modify "admin_permissions" table
+ALTER TABLE `admin_permissions` DROP COLUMN `namespace`;
@@ -0,0 +1,2 @@
+-- modify "admin_permissions" table
+ALTER TABLE `admin_permissions` ADD COLUMN `namespace` varchar(50) NOT NULL DEFAULT '*';
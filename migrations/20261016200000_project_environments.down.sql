@@ -0,0 +1,4 @@
+-- reverse: create "project_environments" table
+DROP TABLE `project_environments`;
+-- reverse: modify "agents" table
+ALTER TABLE `agents` DROP COLUMN `environment`;
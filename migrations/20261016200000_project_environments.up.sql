@@ -0,0 +1,21 @@
+-- modify "agents" table
+ALTER TABLE `agents` ADD COLUMN `environment` varchar(20) NULL DEFAULT 'staging';
+-- create "project_environments" table
+CREATE TABLE `project_environments` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `environment` varchar(20) NULL,
+  `version` bigint NOT NULL,
+  `count_redirects` bigint NOT NULL DEFAULT 0,
+  `count_pages` bigint NOT NULL DEFAULT 0,
+  `redirects` longtext NULL,
+  `pages` longtext NULL,
+  `promoted_by` varchar(100) NULL,
+  `promoted_at` timestamp NULL,
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_project_environments_namespace_project_environment` (`namespace_code`, `project_code`, `environment`),
+  CONSTRAINT `fk_project_environments_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
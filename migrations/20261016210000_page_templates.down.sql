@@ -0,0 +1,2 @@
+-- reverse: create "page_templates" table
+DROP TABLE `page_templates`;
@@ -0,0 +1,14 @@
+-- create "page_templates" table
+CREATE TABLE `page_templates` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `code` varchar(50) NULL,
+  `name` varchar(255) NULL,
+  `content_type` varchar(50) NULL,
+  `content` longtext NULL,
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_page_templates_namespace_code` (`namespace_code`, `code`),
+  CONSTRAINT `fk_page_templates_namespace` FOREIGN KEY (`namespace_code`) REFERENCES `namespaces` (`namespace_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,4 @@
+-- reverse: modify "page_drafts" table
+ALTER TABLE `page_drafts` DROP COLUMN `new_mime_type`;
+-- reverse: modify "pages" table
+ALTER TABLE `pages` DROP COLUMN `mime_type`;
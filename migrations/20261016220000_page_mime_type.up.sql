@@ -0,0 +1,4 @@
+-- modify "pages" table
+ALTER TABLE `pages` ADD COLUMN `mime_type` varchar(100) NULL;
+-- modify "page_drafts" table
+ALTER TABLE `page_drafts` ADD COLUMN `new_mime_type` varchar(100) NULL;
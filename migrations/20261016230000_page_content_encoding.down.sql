@@ -0,0 +1,4 @@
+-- reverse: modify "page_drafts" table
+ALTER TABLE `page_drafts` DROP COLUMN `stored_content_size`, DROP COLUMN `content_encoding`;
+-- reverse: modify "pages" table
+ALTER TABLE `pages` DROP COLUMN `stored_content_size`, DROP COLUMN `content_encoding`;
@@ -0,0 +1,7 @@
+-- modify "pages" table
+ALTER TABLE `pages` ADD COLUMN `content_encoding` varchar(10) NOT NULL DEFAULT '', ADD COLUMN `stored_content_size` bigint NOT NULL DEFAULT 0;
+-- modify "page_drafts" table
+ALTER TABLE `page_drafts` ADD COLUMN `content_encoding` varchar(10) NOT NULL DEFAULT '', ADD COLUMN `stored_content_size` bigint NOT NULL DEFAULT 0;
+-- existing contents are stored uncompressed
+UPDATE `pages` SET `stored_content_size` = LENGTH(`content`) WHERE `content` IS NOT NULL;
+UPDATE `page_drafts` SET `stored_content_size` = LENGTH(`new_content`) WHERE `new_content` IS NOT NULL;
@@ -0,0 +1,4 @@
+-- reverse: create "page_hits" table
+DROP TABLE `page_hits`;
+-- reverse: create "redirect_hits" table
+DROP TABLE `redirect_hits`;
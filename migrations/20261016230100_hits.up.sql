@@ -0,0 +1,18 @@
+-- create "redirect_hits" table
+CREATE TABLE `redirect_hits` (
+  `redirect_id` bigint NOT NULL,
+  `day` date NOT NULL,
+  `hits` bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (`redirect_id`, `day`),
+  INDEX `idx_redirect_hits_day` (`day`),
+  CONSTRAINT `fk_redirect_hits_redirect` FOREIGN KEY (`redirect_id`) REFERENCES `redirects` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
+-- create "page_hits" table
+CREATE TABLE `page_hits` (
+  `page_id` bigint NOT NULL,
+  `day` date NOT NULL,
+  `hits` bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY (`page_id`, `day`),
+  INDEX `idx_page_hits_day` (`day`),
+  CONSTRAINT `fk_page_hits_page` FOREIGN KEY (`page_id`) REFERENCES `pages` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,4 @@
+-- reverse: create "project_git_syncs" table
+DROP TABLE `project_git_syncs`;
+-- reverse: modify "projects" table
+ALTER TABLE `projects` DROP COLUMN `revision`;
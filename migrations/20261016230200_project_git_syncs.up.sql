@@ -0,0 +1,22 @@
+-- modify "projects" table
+ALTER TABLE `projects` ADD COLUMN `revision` varchar(64) NOT NULL DEFAULT '';
+-- create "project_git_syncs" table
+CREATE TABLE `project_git_syncs` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `repo_url` varchar(500) NOT NULL,
+  `branch` varchar(255) NOT NULL,
+  `path` varchar(500) NOT NULL,
+  `auto_publish` bool NOT NULL DEFAULT 0,
+  `poll` bool NOT NULL DEFAULT 0,
+  `webhook_secret` varchar(64) NOT NULL,
+  `last_commit` varchar(64) NOT NULL DEFAULT '',
+  `last_synced_at` timestamp NULL,
+  `last_error` varchar(1000) NOT NULL DEFAULT '',
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_project_git_syncs_namespace_project` (`namespace_code`, `project_code`),
+  CONSTRAINT `fk_project_git_syncs_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
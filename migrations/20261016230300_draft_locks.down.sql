@@ -0,0 +1,2 @@
+-- reverse: create "draft_locks" table
+DROP TABLE `draft_locks`;
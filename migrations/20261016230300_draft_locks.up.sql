@@ -0,0 +1,15 @@
+-- create "draft_locks" table
+CREATE TABLE `draft_locks` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `target` varchar(20) NOT NULL,
+  `draft_id` bigint NOT NULL DEFAULT 0,
+  `locked_by` varchar(255) NOT NULL,
+  `expires_at` timestamp NULL,
+  `created_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_draft_locks_target` (`namespace_code`, `project_code`, `target`, `draft_id`),
+  INDEX `idx_draft_locks_expires_at` (`expires_at`),
+  CONSTRAINT `fk_draft_locks_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,6 @@
+-- reverse: modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP COLUMN `updated_by`, DROP COLUMN `created_by`;
+-- reverse: modify "projects" table
+ALTER TABLE `projects` DROP COLUMN `published_by`;
+-- reverse: modify "page_drafts" table
+ALTER TABLE `page_drafts` DROP COLUMN `updated_by`, DROP COLUMN `created_by`;
@@ -0,0 +1,6 @@
+-- modify "page_drafts" table
+ALTER TABLE `page_drafts` ADD COLUMN `created_by` varchar(255) NOT NULL DEFAULT '', ADD COLUMN `updated_by` varchar(255) NOT NULL DEFAULT '';
+-- modify "projects" table
+ALTER TABLE `projects` ADD COLUMN `published_by` varchar(255) NOT NULL DEFAULT '';
+-- modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` ADD COLUMN `created_by` varchar(255) NOT NULL DEFAULT '', ADD COLUMN `updated_by` varchar(255) NOT NULL DEFAULT '';
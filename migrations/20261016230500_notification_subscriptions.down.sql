@@ -0,0 +1,2 @@
+-- reverse: create "notification_subscriptions" table
+DROP TABLE `notification_subscriptions`;
@@ -0,0 +1,15 @@
+-- create "notification_subscriptions" table
+CREATE TABLE `notification_subscriptions` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `username` varchar(100) NOT NULL,
+  `channel` varchar(20) NOT NULL,
+  `target` varchar(500) NOT NULL,
+  `events` text NULL,
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_notification_subscriptions_user_channel` (`namespace_code`, `project_code`, `username`, `channel`),
+  CONSTRAINT `fk_notification_subscriptions_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,2 @@
+-- reverse: modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP COLUMN `comment`;
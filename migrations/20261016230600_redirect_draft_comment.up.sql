@@ -0,0 +1,2 @@
+-- modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` ADD COLUMN `comment` varchar(500) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+-- reverse: modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP COLUMN `new_priority`;
+-- reverse: modify "redirects" table
+ALTER TABLE `redirects` DROP COLUMN `priority`;
@@ -0,0 +1,4 @@
+-- modify "redirects" table
+ALTER TABLE `redirects` ADD COLUMN `priority` bigint NOT NULL DEFAULT 0;
+-- modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` ADD COLUMN `new_priority` bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- reverse: modify "project_environments" table
+ALTER TABLE `project_environments` DROP COLUMN `redirect_preserve_query_string`, DROP COLUMN `redirect_ignore_trailing_slash`, DROP COLUMN `redirect_case_insensitive`;
+-- reverse: modify "projects" table
+ALTER TABLE `projects` DROP COLUMN `redirect_preserve_query_string`, DROP COLUMN `redirect_ignore_trailing_slash`, DROP COLUMN `redirect_case_insensitive`;
@@ -0,0 +1,4 @@
+-- modify "projects" table
+ALTER TABLE `projects` ADD COLUMN `redirect_case_insensitive` bool NOT NULL DEFAULT 0, ADD COLUMN `redirect_ignore_trailing_slash` bool NOT NULL DEFAULT 0, ADD COLUMN `redirect_preserve_query_string` bool NOT NULL DEFAULT 0;
+-- modify "project_environments" table
+ALTER TABLE `project_environments` ADD COLUMN `redirect_case_insensitive` bool NOT NULL DEFAULT 0, ADD COLUMN `redirect_ignore_trailing_slash` bool NOT NULL DEFAULT 0, ADD COLUMN `redirect_preserve_query_string` bool NOT NULL DEFAULT 0;
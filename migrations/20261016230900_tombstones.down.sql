@@ -0,0 +1,4 @@
+-- reverse: create "redirect_tombstones" table
+DROP TABLE `redirect_tombstones`;
+-- reverse: create "page_tombstones" table
+DROP TABLE `page_tombstones`;
@@ -0,0 +1,43 @@
+-- create "page_tombstones" table
+CREATE TABLE `page_tombstones` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `page_id` bigint NOT NULL,
+  `content_size` bigint NOT NULL DEFAULT 0,
+  `content_encoding` varchar(10) NOT NULL DEFAULT '',
+  `stored_content_size` bigint NOT NULL DEFAULT 0,
+  `type` varchar(50) NULL,
+  `path` varchar(600) NULL,
+  `content` longtext NULL,
+  `content_type` varchar(50) NULL,
+  `mime_type` varchar(100) NULL,
+  `deleted_by` varchar(255) NOT NULL DEFAULT '',
+  `deleted_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_page_tombstones_namespace_project` (`namespace_code`, `project_code`),
+  UNIQUE INDEX `idx_page_tombstones_page_id` (`page_id`),
+  CONSTRAINT `fk_page_tombstones_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
+-- create "redirect_tombstones" table
+CREATE TABLE `redirect_tombstones` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `redirect_id` bigint NOT NULL,
+  `type` varchar(50) NULL,
+  `source` varchar(600) NULL,
+  `target` varchar(2048) NULL,
+  `status` varchar(50) NULL,
+  `priority` bigint NOT NULL DEFAULT 0,
+  `valid_from` timestamp NULL,
+  `valid_until` timestamp NULL,
+  `conditions` text NULL,
+  `tags` text NULL,
+  `deleted_by` varchar(255) NOT NULL DEFAULT '',
+  `deleted_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_redirect_tombstones_namespace_project` (`namespace_code`, `project_code`),
+  UNIQUE INDEX `idx_redirect_tombstones_redirect_id` (`redirect_id`),
+  CONSTRAINT `fk_redirect_tombstones_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,2 @@
+-- reverse: create "project_api_keys" table
+DROP TABLE `project_api_keys`;
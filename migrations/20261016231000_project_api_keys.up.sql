@@ -0,0 +1,18 @@
+-- create "project_api_keys" table
+CREATE TABLE `project_api_keys` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `name` varchar(100) NOT NULL,
+  `key_hash` varchar(64) NOT NULL,
+  `key_preview` varchar(30) NOT NULL,
+  `scopes` text NULL,
+  `expires_at` timestamp NULL,
+  `created_by` varchar(255) NOT NULL DEFAULT '',
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_project_api_keys_key_hash` (`key_hash`),
+  UNIQUE INDEX `idx_project_api_keys_name` (`namespace_code`, `project_code`, `name`),
+  CONSTRAINT `fk_project_api_keys_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,6 @@
+-- reverse: modify "page_tombstones" table
+ALTER TABLE `page_tombstones` DROP COLUMN `content_store`;
+-- reverse: modify "page_drafts" table
+ALTER TABLE `page_drafts` DROP COLUMN `content_store`;
+-- reverse: modify "pages" table
+ALTER TABLE `pages` DROP COLUMN `content_store`;
@@ -0,0 +1,6 @@
+-- modify "pages" table
+ALTER TABLE `pages` ADD COLUMN `content_store` varchar(10) NOT NULL DEFAULT '';
+-- modify "page_drafts" table
+ALTER TABLE `page_drafts` ADD COLUMN `content_store` varchar(10) NOT NULL DEFAULT '';
+-- modify "page_tombstones" table
+ALTER TABLE `page_tombstones` ADD COLUMN `content_store` varchar(10) NOT NULL DEFAULT '';
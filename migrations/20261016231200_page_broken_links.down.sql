@@ -0,0 +1,2 @@
+-- reverse: create "page_broken_links" table
+DROP TABLE `page_broken_links`;
@@ -0,0 +1,17 @@
+-- create "page_broken_links" table
+CREATE TABLE `page_broken_links` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `page_id` bigint NOT NULL,
+  `page_path` varchar(600) NULL,
+  `link` varchar(2048) NULL,
+  `url` varchar(2048) NULL,
+  `suggested_target` varchar(2048) NULL,
+  `draft_created` bool NOT NULL DEFAULT 0,
+  `checked_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_page_broken_links_namespace_project` (`namespace_code`, `project_code`),
+  INDEX `idx_page_broken_links_page_id` (`page_id`),
+  CONSTRAINT `fk_page_broken_links_page` FOREIGN KEY (`page_id`) REFERENCES `pages` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
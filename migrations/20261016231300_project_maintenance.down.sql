@@ -0,0 +1,2 @@
+-- reverse: modify "projects" table
+ALTER TABLE `projects` DROP COLUMN `maintenance_page_path`, DROP COLUMN `maintenance_enabled`;
@@ -0,0 +1,2 @@
+-- modify "projects" table
+ALTER TABLE `projects` ADD COLUMN `maintenance_enabled` bool NOT NULL DEFAULT 0, ADD COLUMN `maintenance_page_path` varchar(600) NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- reverse: modify "redirects" table
+ALTER TABLE `redirects` DROP COLUMN `targets`;
+-- reverse: modify "redirect_tombstones" table
+ALTER TABLE `redirect_tombstones` DROP COLUMN `targets`;
+-- reverse: modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP COLUMN `new_targets`;
@@ -0,0 +1,6 @@
+-- modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` ADD COLUMN `new_targets` text NULL;
+-- modify "redirect_tombstones" table
+ALTER TABLE `redirect_tombstones` ADD COLUMN `targets` text NULL;
+-- modify "redirects" table
+ALTER TABLE `redirects` ADD COLUMN `targets` text NULL;
@@ -0,0 +1,12 @@
+-- reverse: modify "redirects" table
+ALTER TABLE `redirects` DROP COLUMN `headers`;
+-- reverse: modify "redirect_tombstones" table
+ALTER TABLE `redirect_tombstones` DROP COLUMN `headers`;
+-- reverse: modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP COLUMN `new_headers`;
+-- reverse: modify "pages" table
+ALTER TABLE `pages` DROP COLUMN `headers`;
+-- reverse: modify "page_tombstones" table
+ALTER TABLE `page_tombstones` DROP COLUMN `headers`;
+-- reverse: modify "page_drafts" table
+ALTER TABLE `page_drafts` DROP COLUMN `new_headers`;
@@ -0,0 +1,12 @@
+-- modify "page_drafts" table
+ALTER TABLE `page_drafts` ADD COLUMN `new_headers` text NULL;
+-- modify "page_tombstones" table
+ALTER TABLE `page_tombstones` ADD COLUMN `headers` text NULL;
+-- modify "pages" table
+ALTER TABLE `pages` ADD COLUMN `headers` text NULL;
+-- modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` ADD COLUMN `new_headers` text NULL;
+-- modify "redirect_tombstones" table
+ALTER TABLE `redirect_tombstones` ADD COLUMN `headers` text NULL;
+-- modify "redirects" table
+ALTER TABLE `redirects` ADD COLUMN `headers` text NULL;
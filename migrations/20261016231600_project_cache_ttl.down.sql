@@ -0,0 +1,2 @@
+-- reverse: modify "projects" table
+ALTER TABLE `projects` DROP COLUMN `cache_ttl_redirects`, DROP COLUMN `cache_ttl_pages`;
@@ -0,0 +1,2 @@
+-- modify "projects" table
+ALTER TABLE `projects` ADD COLUMN `cache_ttl_pages` bigint NOT NULL DEFAULT 0, ADD COLUMN `cache_ttl_redirects` bigint NOT NULL DEFAULT 0;
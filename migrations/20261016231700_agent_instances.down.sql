@@ -0,0 +1,4 @@
+-- reverse: create "agent_instance_projects" table
+DROP TABLE `agent_instance_projects`;
+-- reverse: create "agent_instances" table
+DROP TABLE `agent_instances`;
@@ -0,0 +1,28 @@
+-- create "agent_instances" table
+CREATE TABLE `agent_instances` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `name` varchar(100) NOT NULL,
+  `type` varchar(50) NOT NULL,
+  `hostname` varchar(255) NULL,
+  `agent_version` varchar(50) NULL,
+  `registered_by` varchar(100) NOT NULL,
+  `last_heartbeat_at` timestamp NULL,
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_agent_instances_name` (`name`)
+) COLLATE utf8mb4_uca1400_ai_ci;
+-- create "agent_instance_projects" table
+CREATE TABLE `agent_instance_projects` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `agent_instance_id` bigint NOT NULL,
+  `namespace_code` varchar(50) NOT NULL,
+  `project_code` varchar(50) NOT NULL,
+  `environment` varchar(20) NOT NULL,
+  `version` bigint NOT NULL,
+  `applied_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_agent_instance_projects_agent_instance_id` (`agent_instance_id`),
+  INDEX `idx_agent_instance_projects_namespace_project` (`namespace_code`, `project_code`),
+  CONSTRAINT `fk_agent_instances_projects` FOREIGN KEY (`agent_instance_id`) REFERENCES `agent_instances` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
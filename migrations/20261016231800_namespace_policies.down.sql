@@ -0,0 +1,2 @@
+-- reverse: create "namespace_policies" table
+DROP TABLE `namespace_policies`;
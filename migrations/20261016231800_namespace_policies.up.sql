@@ -0,0 +1,12 @@
+-- create "namespace_policies" table
+CREATE TABLE `namespace_policies` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `rules` text NULL,
+  `updated_by` varchar(255) NOT NULL DEFAULT '',
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_namespace_policies_namespace_code` (`namespace_code`),
+  CONSTRAINT `fk_namespace_policies_namespace` FOREIGN KEY (`namespace_code`) REFERENCES `namespaces` (`namespace_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
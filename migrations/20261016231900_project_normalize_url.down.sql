@@ -0,0 +1,4 @@
+-- reverse: modify "project_environments" table
+ALTER TABLE `project_environments` DROP COLUMN `redirect_normalize_url`;
+-- reverse: modify "projects" table
+ALTER TABLE `projects` DROP COLUMN `redirect_normalize_url`;
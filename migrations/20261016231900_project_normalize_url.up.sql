@@ -0,0 +1,4 @@
+-- modify "projects" table
+ALTER TABLE `projects` ADD COLUMN `redirect_normalize_url` bool NOT NULL DEFAULT 0;
+-- modify "project_environments" table
+ALTER TABLE `project_environments` ADD COLUMN `redirect_normalize_url` bool NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+-- reverse: modify "page_tombstones" table
+ALTER TABLE `page_tombstones` DROP COLUMN `source`;
+-- reverse: modify "page_drafts" table
+ALTER TABLE `page_drafts` DROP COLUMN `new_source`;
+-- reverse: modify "pages" table
+ALTER TABLE `pages` DROP COLUMN `source`;
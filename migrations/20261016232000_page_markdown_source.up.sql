@@ -0,0 +1,6 @@
+-- modify "pages" table
+ALTER TABLE `pages` ADD COLUMN `source` longtext NULL;
+-- modify "page_drafts" table
+ALTER TABLE `page_drafts` ADD COLUMN `new_source` longtext NULL;
+-- modify "page_tombstones" table
+ALTER TABLE `page_tombstones` ADD COLUMN `source` longtext NULL;
@@ -0,0 +1,2 @@
+-- reverse: modify "page_drafts" table
+ALTER TABLE `page_drafts` DROP COLUMN `lint_warnings`;
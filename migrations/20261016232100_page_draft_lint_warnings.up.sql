@@ -0,0 +1,2 @@
+-- modify "page_drafts" table
+ALTER TABLE `page_drafts` ADD COLUMN `lint_warnings` text NULL;
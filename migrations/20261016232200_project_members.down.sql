@@ -0,0 +1,2 @@
+-- reverse: create "project_members" table
+DROP TABLE `project_members`;
@@ -0,0 +1,16 @@
+-- create "project_members" table
+CREATE TABLE `project_members` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `user_id` bigint NOT NULL,
+  `role` varchar(20) NOT NULL,
+  `created_by` varchar(255) NOT NULL DEFAULT '',
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  INDEX `idx_project_members_user_id` (`user_id`),
+  UNIQUE INDEX `idx_project_members_user` (`namespace_code`, `project_code`, `user_id`),
+  CONSTRAINT `fk_project_members_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE,
+  CONSTRAINT `fk_project_members_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,6 @@
+-- reverse: create "user_groups" table
+DROP TABLE `user_groups`;
+-- reverse: create "group_roles" table
+DROP TABLE `group_roles`;
+-- reverse: create "groups" table
+DROP TABLE `groups`;
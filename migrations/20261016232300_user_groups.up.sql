@@ -0,0 +1,31 @@
+-- create "groups" table
+CREATE TABLE `groups` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `code` varchar(100) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `description` varchar(1000) NOT NULL DEFAULT '',
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_group_code` (`code`)
+) COLLATE utf8mb4_uca1400_ai_ci;
+-- create "group_roles" table
+CREATE TABLE `group_roles` (
+  `group_id` bigint NOT NULL,
+  `role_id` bigint NOT NULL,
+  `created_at` timestamp NULL,
+  PRIMARY KEY (`group_id`, `role_id`),
+  INDEX `idx_group_roles_role_id` (`role_id`),
+  CONSTRAINT `fk_group_roles_group` FOREIGN KEY (`group_id`) REFERENCES `groups` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE,
+  CONSTRAINT `fk_group_roles_role` FOREIGN KEY (`role_id`) REFERENCES `roles` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
+-- create "user_groups" table
+CREATE TABLE `user_groups` (
+  `user_id` bigint NOT NULL,
+  `group_id` bigint NOT NULL,
+  `created_at` timestamp NULL,
+  PRIMARY KEY (`user_id`, `group_id`),
+  INDEX `idx_user_groups_group_id` (`group_id`),
+  CONSTRAINT `fk_user_groups_group` FOREIGN KEY (`group_id`) REFERENCES `groups` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE,
+  CONSTRAINT `fk_user_groups_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,2 @@
+-- modify "users" table
+ALTER TABLE `users` ADD COLUMN `language` varchar(10) NOT NULL DEFAULT '';
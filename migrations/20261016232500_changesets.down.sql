@@ -0,0 +1,6 @@
+-- reverse: modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP INDEX `idx_redirect_drafts_changeset_id`, DROP COLUMN `changeset_id`;
+-- reverse: modify "page_drafts" table
+ALTER TABLE `page_drafts` DROP INDEX `idx_page_drafts_changeset_id`, DROP COLUMN `changeset_id`;
+-- reverse: create "changesets" table
+DROP TABLE `changesets`;
@@ -0,0 +1,18 @@
+-- create "changesets" table
+CREATE TABLE `changesets` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `name` varchar(100) NOT NULL,
+  `description` varchar(1000) NOT NULL DEFAULT '',
+  `created_by` varchar(255) NOT NULL DEFAULT '',
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_changesets_name` (`namespace_code`, `project_code`, `name`),
+  CONSTRAINT `fk_changesets_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
+-- modify "page_drafts" table
+ALTER TABLE `page_drafts` ADD COLUMN `changeset_id` bigint NULL, ADD INDEX `idx_page_drafts_changeset_id` (`changeset_id`);
+-- modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` ADD COLUMN `changeset_id` bigint NULL, ADD INDEX `idx_redirect_drafts_changeset_id` (`changeset_id`);
@@ -0,0 +1,2 @@
+-- reverse: create "import_profiles" table
+DROP TABLE `import_profiles`;
@@ -0,0 +1,19 @@
+-- create "import_profiles" table
+CREATE TABLE `import_profiles` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NULL,
+  `project_code` varchar(50) NULL,
+  `name` varchar(100) NOT NULL,
+  `columns` text NULL,
+  `skip_header` bool NOT NULL DEFAULT 0,
+  `delimiter` varchar(20) NOT NULL DEFAULT 'TAB',
+  `default_type` varchar(50) NULL,
+  `default_status` varchar(50) NULL,
+  `overwrite` bool NOT NULL,
+  `created_by` varchar(255) NOT NULL DEFAULT '',
+  `created_at` timestamp NULL,
+  `updated_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_import_profiles_name` (`namespace_code`, `project_code`, `name`),
+  CONSTRAINT `fk_import_profiles_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE
+) COLLATE utf8mb4_uca1400_ai_ci;
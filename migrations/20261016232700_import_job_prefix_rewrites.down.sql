@@ -0,0 +1,2 @@
+-- reverse: modify "import_jobs" table
+ALTER TABLE `import_jobs` DROP COLUMN `rewritten_count`, DROP COLUMN `target_prefix`, DROP COLUMN `source_prefix`;
@@ -0,0 +1,2 @@
+-- modify "import_jobs" table
+ALTER TABLE `import_jobs` ADD COLUMN `source_prefix` text NULL, ADD COLUMN `target_prefix` text NULL, ADD COLUMN `rewritten_count` bigint NOT NULL DEFAULT 0;
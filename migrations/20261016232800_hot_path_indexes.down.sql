@@ -0,0 +1,8 @@
+-- reverse: modify "redirects" table
+ALTER TABLE `redirects` DROP INDEX `idx_redirects_updated_at`, DROP INDEX `idx_redirects_published`, DROP INDEX `idx_redirects_expiry`;
+-- reverse: modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` DROP INDEX `idx_redirect_drafts_updated_at`, DROP INDEX `idx_redirect_drafts_change_type`;
+-- reverse: modify "pages" table
+ALTER TABLE `pages` DROP INDEX `idx_pages_updated_at`, DROP INDEX `idx_pages_published`;
+-- reverse: modify "page_drafts" table
+ALTER TABLE `page_drafts` DROP INDEX `idx_page_drafts_updated_at`, DROP INDEX `idx_page_drafts_change_type`;
@@ -0,0 +1,8 @@
+-- modify "page_drafts" table
+ALTER TABLE `page_drafts` ADD INDEX `idx_page_drafts_change_type` (`namespace_code`, `project_code`, `change_type`), ADD INDEX `idx_page_drafts_updated_at` (`namespace_code`, `project_code`, `updated_at`);
+-- modify "pages" table
+ALTER TABLE `pages` ADD INDEX `idx_pages_published` (`namespace_code`, `project_code`, `is_published`), ADD INDEX `idx_pages_updated_at` (`namespace_code`, `project_code`, `updated_at`);
+-- modify "redirect_drafts" table
+ALTER TABLE `redirect_drafts` ADD INDEX `idx_redirect_drafts_change_type` (`namespace_code`, `project_code`, `change_type`), ADD INDEX `idx_redirect_drafts_updated_at` (`namespace_code`, `project_code`, `updated_at`);
+-- modify "redirects" table
+ALTER TABLE `redirects` ADD INDEX `idx_redirects_expiry` (`is_published`, `valid_until`), ADD INDEX `idx_redirects_published` (`namespace_code`, `project_code`, `is_published`, `priority` DESC, `id`), ADD INDEX `idx_redirects_updated_at` (`namespace_code`, `project_code`, `updated_at`);
@@ -0,0 +1,2 @@
+-- reverse: create "outbox_events" table
+DROP TABLE `outbox_events`;
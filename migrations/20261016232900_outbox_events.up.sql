@@ -0,0 +1,20 @@
+-- create "outbox_events" table
+CREATE TABLE `outbox_events` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `namespace_code` varchar(50) NOT NULL DEFAULT '',
+  `project_code` varchar(50) NOT NULL DEFAULT '',
+  `topic` varchar(30) NOT NULL,
+  `payload` text NOT NULL,
+  `dedup_key` varchar(100) NULL,
+  `status` varchar(20) NOT NULL,
+  `attempts` bigint NOT NULL DEFAULT 0,
+  `last_error` varchar(1000) NOT NULL DEFAULT '',
+  `available_at` timestamp NULL,
+  `locked_until` timestamp NULL,
+  `processed_at` timestamp NULL,
+  `created_at` timestamp NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE INDEX `idx_outbox_events_dedup_key` (`dedup_key`),
+  INDEX `idx_outbox_events_pending` (`status`, `available_at`),
+  INDEX `idx_outbox_events_processed_at` (`processed_at`)
+) COLLATE utf8mb4_uca1400_ai_ci;
@@ -0,0 +1,2 @@
+-- reverse: modify "project_members" table
+ALTER TABLE `project_members` ADD CONSTRAINT `fk_project_members_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE;
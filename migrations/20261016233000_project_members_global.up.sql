@@ -0,0 +1,2 @@
+-- modify "project_members" table
+ALTER TABLE `project_members` DROP FOREIGN KEY `fk_project_members_project`;
@@ -0,0 +1,2 @@
+-- reverse: modify "project_api_keys" table
+ALTER TABLE `project_api_keys` ADD CONSTRAINT `fk_project_api_keys_project` FOREIGN KEY (`namespace_code`, `project_code`) REFERENCES `projects` (`namespace_code`, `project_code`) ON UPDATE RESTRICT ON DELETE CASCADE;
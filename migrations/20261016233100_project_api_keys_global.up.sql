@@ -0,0 +1,2 @@
+-- modify "project_api_keys" table
+ALTER TABLE `project_api_keys` DROP FOREIGN KEY `fk_project_api_keys_project`;
@@ -1,6 +1,28 @@
 package migrations
 
-import "embed"
+import (
+	"embed"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4/source"
+)
 
 //go:embed *.sql
 var MigrationsFS embed.FS
+
+// LatestVersion returns the version of the last migration, the one of an up-to-date database
+func LatestVersion() (uint, error) {
+	entries, err := fs.ReadDir(MigrationsFS, ".")
+	if err != nil {
+		return 0, err
+	}
+	var latest uint
+	for _, entry := range entries {
+		migration, errParse := source.Parse(entry.Name())
+		if errParse != nil {
+			continue
+		}
+		latest = max(latest, migration.Version)
+	}
+	return latest, nil
+}
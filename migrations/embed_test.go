@@ -0,0 +1,14 @@
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestVersion(t *testing.T) {
+	latest, err := LatestVersion()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, latest, uint(20261016231000))
+}
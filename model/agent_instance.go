@@ -0,0 +1,70 @@
+package model
+
+import (
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// AgentInstanceState is the state of a registered agent, derived from the time of its last heartbeat
+type AgentInstanceState string
+
+const (
+	AgentInstanceStateOnline AgentInstanceState = "ONLINE"
+	AgentInstanceStateStale  AgentInstanceState = "STALE"
+)
+
+func (s AgentInstanceState) IsValid() bool {
+	switch s {
+	case AgentInstanceStateOnline, AgentInstanceStateStale:
+		return true
+	default:
+		return false
+	}
+}
+
+// AgentInstance is an agent registered with the manager, identified by its name across all the projects it serves
+type AgentInstance struct {
+	ID           int64                 `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name         string                `json:"name" gorm:"size:100;not null;uniqueIndex:idx_agent_instances_name"`
+	Type         commonTypes.AgentType `json:"type" gorm:"size:50;not null"`
+	Hostname     string                `json:"hostname" gorm:"size:255"`
+	AgentVersion string                `json:"agentVersion" gorm:"size:50"`
+	// RegisteredBy is the name of the API token the agent registered with, the only one allowed to send its heartbeats
+	RegisteredBy    string                 `json:"registeredBy" gorm:"size:100;not null"`
+	Projects        []AgentInstanceProject `json:"projects" gorm:"foreignKey:AgentInstanceID;constraint:OnDelete:CASCADE;"`
+	LastHeartbeatAt time.Time              `json:"lastHeartbeatAt" gorm:"type:timestamp"`
+	CreatedAt       time.Time              `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt       time.Time              `json:"updatedAt" gorm:"type:timestamp"`
+	// State is computed from LastHeartbeatAt when the agents are listed
+	State AgentInstanceState `json:"state" gorm:"-"`
+}
+
+// IsOutdated returns true if the agent serves an older version than the latest one of any of its projects
+func (a AgentInstance) IsOutdated() bool {
+	for _, project := range a.Projects {
+		if project.IsOutdated() {
+			return true
+		}
+	}
+	return false
+}
+
+// AgentInstanceProject is the version of a project applied by an agent, as reported by its last heartbeat
+type AgentInstanceProject struct {
+	ID              int64                   `json:"-" gorm:"primaryKey;autoIncrement"`
+	AgentInstanceID int64                   `json:"-" gorm:"not null;index:idx_agent_instance_projects_agent_instance_id"`
+	NamespaceCode   string                  `json:"namespaceCode" gorm:"size:50;not null;index:idx_agent_instance_projects_namespace_project"`
+	ProjectCode     string                  `json:"projectCode" gorm:"size:50;not null;index:idx_agent_instance_projects_namespace_project"`
+	Environment     commonTypes.Environment `json:"environment" gorm:"size:20;not null"`
+	Version         int                     `json:"version" gorm:"not null"`
+	// AppliedAt is the time of the first heartbeat reporting the version
+	AppliedAt time.Time `json:"appliedAt" gorm:"type:timestamp"`
+	// LatestVersion is the version published to the environment, computed when the agents are listed
+	LatestVersion int `json:"latestVersion" gorm:"-"`
+}
+
+// IsOutdated returns true if a newer version of the project was published to the environment
+func (p AgentInstanceProject) IsOutdated() bool {
+	return p.Version < p.LatestVersion
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+// recordAuthor sets the author and the last editor of a record being created to the subject of the statement context,
+// keeping the values already set, e.g. when copying records
+func recordAuthor(tx *gorm.DB, createdBy, updatedBy *string) {
+	subject := types.SubjectFromContext(tx.Statement.Context)
+	if *createdBy == "" {
+		*createdBy = subject
+	}
+	if *updatedBy == "" {
+		*updatedBy = *createdBy
+	}
+}
+
+// recordEditor sets the last editor of a record being updated to the subject of the statement context, if any
+func recordEditor(tx *gorm.DB, updatedBy *string) {
+	if subject := types.SubjectFromContext(tx.Statement.Context); subject != "" {
+		*updatedBy = subject
+	}
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+// Changeset groups related redirect and page drafts of a project under a name, to publish or discard them together
+// instead of publishing all the drafts of the project
+type Changeset struct {
+	ID            int64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string   `json:"-" gorm:"size:50;uniqueIndex:idx_changesets_name"`
+	ProjectCode   string   `json:"-" gorm:"size:50;uniqueIndex:idx_changesets_name"`
+	Project       *Project `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;constraint:OnDelete:CASCADE;"`
+	Name          string   `json:"name" gorm:"size:100;not null;uniqueIndex:idx_changesets_name" validate:"required,max=100"`
+	Description   string   `json:"description" gorm:"size:1000;default:'';not null" validate:"max=1000"`
+	// RedirectDraftCount and PageDraftCount are the number of drafts of the changeset, set by the changeset service
+	RedirectDraftCount int64 `json:"redirectDraftCount" gorm:"-"`
+	PageDraftCount     int64 `json:"pageDraftCount" gorm:"-"`
+	// CreatedBy is the subject who created the changeset
+	CreatedBy string    `json:"createdBy" gorm:"size:255;default:'';not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+func (Changeset) TableName() string {
+	return "changesets"
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+)
+
+// DraftOperationTarget is the kind of draft a draft operation changes
+type DraftOperationTarget string
+
+const (
+	DraftOperationTargetRedirect DraftOperationTarget = "REDIRECT"
+	DraftOperationTargetPage     DraftOperationTarget = "PAGE"
+)
+
+// LockTarget returns the target of the draft locks covering the drafts of the operation
+func (t DraftOperationTarget) LockTarget() DraftLockTarget {
+	if t == DraftOperationTargetPage {
+		return DraftLockTargetPageDraft
+	}
+	return DraftLockTargetRedirectDraft
+}
+
+// ResourceType returns the type of the resource the permissions of the operation are checked on
+func (t DraftOperationTarget) ResourceType() ResourceType {
+	if t == DraftOperationTargetPage {
+		return ResourceTypePage
+	}
+	return ResourceTypeRedirect
+}
+
+type DraftOperationAction string
+
+const (
+	// DraftOperationActionCreate stages a new redirect or page, a change of a published one when OldID is set,
+	// or its deletion when OldID is set without NewRedirect or NewPage
+	DraftOperationActionCreate DraftOperationAction = "CREATE"
+	// DraftOperationActionUpdate changes the draft DraftID
+	DraftOperationActionUpdate DraftOperationAction = "UPDATE"
+	// DraftOperationActionDelete discards the draft DraftID
+	DraftOperationActionDelete DraftOperationAction = "DELETE"
+)
+
+// DraftOperation is a change of a redirect or page draft of a batch, see the mutations of the drafts for its fields
+type DraftOperation struct {
+	Target      DraftOperationTarget  `json:"target"`
+	Action      DraftOperationAction  `json:"action"`
+	DraftID     *int64                `json:"draftID"`
+	OldID       *int64                `json:"oldID"`
+	NewRedirect *commonTypes.Redirect `json:"newRedirect"`
+	NewPage     *commonTypes.Page     `json:"newPage"`
+	Tags        []string              `json:"tags"`
+}
+
+// DraftOperationResult is the outcome of an operation of a batch. RedirectDraft or PageDraft is the draft created or
+// updated by the operation once the batch is applied, Error is set when the operation failed.
+type DraftOperationResult struct {
+	Index         int                 `json:"index"`
+	RedirectDraft *RedirectDraft      `json:"redirectDraft"`
+	PageDraft     *PageDraft          `json:"pageDraft"`
+	Error         *flectoErrors.Error `json:"error"`
+}
+
+// DraftBatchResult reports the operations of a batch, applied only when all of them succeeded
+type DraftBatchResult struct {
+	Applied bool                   `json:"applied"`
+	Results []DraftOperationResult `json:"results"`
+}
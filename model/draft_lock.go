@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+)
+
+// DraftLockTarget is what a draft lock covers
+type DraftLockTarget string
+
+const (
+	// DraftLockTargetProject covers all the drafts of a project
+	DraftLockTargetProject       DraftLockTarget = "PROJECT"
+	DraftLockTargetRedirectDraft DraftLockTarget = "REDIRECT_DRAFT"
+	DraftLockTargetPageDraft     DraftLockTarget = "PAGE_DRAFT"
+)
+
+// DraftLock claims a draft, or all the drafts of a project, for a user until it expires.
+// The changes of the other users to the locked drafts are refused.
+type DraftLock struct {
+	ID            int64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string          `json:"-" gorm:"size:50;uniqueIndex:idx_draft_locks_target"`
+	ProjectCode   string          `json:"-" gorm:"size:50;uniqueIndex:idx_draft_locks_target"`
+	Project       *Project        `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;constraint:OnDelete:CASCADE;"`
+	Target        DraftLockTarget `json:"target" gorm:"size:20;not null;uniqueIndex:idx_draft_locks_target"`
+	// DraftID is the id of the redirect or page draft, 0 for a project lock
+	DraftID   int64     `json:"draftID" gorm:"not null;default:0;uniqueIndex:idx_draft_locks_target"`
+	LockedBy  string    `json:"lockedBy" gorm:"size:255;not null"`
+	ExpiresAt time.Time `json:"expiresAt" gorm:"type:timestamp;index"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+}
+
+// IsActive returns true when the lock has not expired at now
+func (l *DraftLock) IsActive(now time.Time) bool {
+	return l.ExpiresAt.After(now)
+}
+
+// Covers returns true when the lock applies to the given draft, a project lock applying to all of them
+func (l *DraftLock) Covers(target DraftLockTarget, draftID int64) bool {
+	return l.Target == DraftLockTargetProject || (l.Target == target && l.DraftID == draftID)
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDraftLock_IsActive(t *testing.T) {
+	now := time.Now()
+	assert.True(t, (&DraftLock{ExpiresAt: now.Add(time.Minute)}).IsActive(now))
+	assert.False(t, (&DraftLock{ExpiresAt: now}).IsActive(now))
+	assert.False(t, (&DraftLock{ExpiresAt: now.Add(-time.Minute)}).IsActive(now))
+}
+
+func TestDraftLock_Covers(t *testing.T) {
+	projectLock := &DraftLock{Target: DraftLockTargetProject}
+	assert.True(t, projectLock.Covers(DraftLockTargetRedirectDraft, 1))
+	assert.True(t, projectLock.Covers(DraftLockTargetPageDraft, 0))
+
+	draftLock := &DraftLock{Target: DraftLockTargetRedirectDraft, DraftID: 1}
+	assert.True(t, draftLock.Covers(DraftLockTargetRedirectDraft, 1))
+	assert.False(t, draftLock.Covers(DraftLockTargetRedirectDraft, 2))
+	assert.False(t, draftLock.Covers(DraftLockTargetPageDraft, 1))
+	assert.False(t, draftLock.Covers(DraftLockTargetRedirectDraft, 0))
+}
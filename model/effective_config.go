@@ -0,0 +1,38 @@
+package model
+
+import (
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// EffectiveRedirect is a redirect of the effective config of a project. Change is PUBLISHED for a redirect the drafts
+// leave unchanged, and the change type of its draft otherwise, a redirect to delete keeping its published values.
+type EffectiveRedirect struct {
+	// ID is the id of the redirect, the one a created redirect gets once published
+	ID       int64                 `json:"id"`
+	Redirect *commonTypes.Redirect `json:"redirect"`
+	Tags     []string              `json:"tags"`
+	Change   DraftChangeType       `json:"change"`
+	DraftID  *int64                `json:"draftID"`
+}
+
+// EffectivePage is a page of the effective config of a project, see EffectiveRedirect for its change
+type EffectivePage struct {
+	ID      int64             `json:"id"`
+	Page    *commonTypes.Page `json:"page"`
+	Change  DraftChangeType   `json:"change"`
+	DraftID *int64            `json:"draftID"`
+}
+
+// EffectiveConfig is the redirects and pages a project would serve once its drafts published, or its published ones
+// when the drafts are not included. The redirects are in the order the agents evaluate them, the pages in the order
+// of their ids.
+type EffectiveConfig struct {
+	NamespaceCode string `json:"namespaceCode"`
+	ProjectCode   string `json:"projectCode"`
+	// Version is the published version of the project the drafts apply to
+	Version         int                         `json:"version"`
+	IncludeDrafts   bool                        `json:"includeDrafts"`
+	RedirectOptions commonTypes.RedirectOptions `json:"redirectOptions"`
+	Redirects       []EffectiveRedirect         `json:"redirects"`
+	Pages           []EffectivePage             `json:"pages"`
+}
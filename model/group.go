@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+)
+
+// Group gathers users sharing the same roles: the roles assigned to a group are granted to all its users, on top of
+// the roles assigned to each user
+type Group struct {
+	ID          int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Code        string    `json:"code" gorm:"uniqueIndex:idx_group_code;size:100;not null" validate:"required,code,max=100"`
+	Name        string    `json:"name" gorm:"size:255;not null" validate:"required,max=255"`
+	Description string    `json:"description" gorm:"size:1000;default:'';not null" validate:"max=1000"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt   time.Time `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+func (Group) TableName() string {
+	return "groups"
+}
+
+// UserGroup links a user to a group they belong to
+type UserGroup struct {
+	UserID    int64     `json:"userId" gorm:"primaryKey"`
+	GroupID   int64     `json:"groupId" gorm:"primaryKey;index"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+
+	User  User  `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	Group Group `json:"group" gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE;"`
+}
+
+func (UserGroup) TableName() string {
+	return "user_groups"
+}
+
+// GroupRole links a group to a role granted to its users
+type GroupRole struct {
+	GroupID   int64     `json:"groupId" gorm:"primaryKey"`
+	RoleID    int64     `json:"roleId" gorm:"primaryKey;index"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+
+	Group Group `json:"group" gorm:"foreignKey:GroupID;constraint:OnDelete:CASCADE;"`
+	Role  Role  `json:"role" gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE;"`
+}
+
+func (GroupRole) TableName() string {
+	return "group_roles"
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroup_TableName(t *testing.T) {
+	assert.Equal(t, "groups", Group{}.TableName())
+}
+
+func TestUserGroup_TableName(t *testing.T) {
+	assert.Equal(t, "user_groups", UserGroup{}.TableName())
+}
+
+func TestGroupRole_TableName(t *testing.T) {
+	assert.Equal(t, "group_roles", GroupRole{}.TableName())
+}
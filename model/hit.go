@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// RedirectHit is the number of requests served by a redirect on a day, as reported by the agents
+type RedirectHit struct {
+	RedirectID int64     `json:"redirectId" gorm:"primaryKey;autoIncrement:false"`
+	Redirect   *Redirect `json:"-" gorm:"foreignKey:RedirectID;constraint:OnDelete:CASCADE;"`
+	Day        time.Time `json:"day" gorm:"primaryKey;type:date;index:idx_redirect_hits_day"`
+	Hits       int64     `json:"hits" gorm:"default:0;not null"`
+}
+
+// PageHit is the number of requests served by a page on a day, as reported by the agents
+type PageHit struct {
+	PageID int64     `json:"pageId" gorm:"primaryKey;autoIncrement:false"`
+	Page   *Page     `json:"-" gorm:"foreignKey:PageID;constraint:OnDelete:CASCADE;"`
+	Day    time.Time `json:"day" gorm:"primaryKey;type:date;index:idx_page_hits_day"`
+	Hits   int64     `json:"hits" gorm:"default:0;not null"`
+}
+
+// RedirectHitCount is the number of requests served by a redirect over a period
+type RedirectHitCount struct {
+	Redirect Redirect
+	Hits     int64
+}
+
+// PageHitCount is the number of requests served by a page over a period
+type PageHitCount struct {
+	Page Page
+	Hits int64
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+type ImportJobStatus string
+
+const (
+	ImportJobStatusPending   ImportJobStatus = "PENDING"
+	ImportJobStatusRunning   ImportJobStatus = "RUNNING"
+	ImportJobStatusCompleted ImportJobStatus = "COMPLETED"
+	ImportJobStatusFailed    ImportJobStatus = "FAILED"
+)
+
+// ImportJobError is a line rejected during an import job
+type ImportJobError struct {
+	Line    int    `json:"line"`
+	Source  string `json:"source,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
+}
+
+// ImportPrefixRewrite replaces the prefix From of the sources or targets of the imported rows with To, the values not
+// starting with From being kept. An empty From prepends To to all the values.
+type ImportPrefixRewrite struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Apply returns the value with its prefix rewritten, and whether the rewrite changed it
+func (r *ImportPrefixRewrite) Apply(value string) (string, bool) {
+	if r == nil || !strings.HasPrefix(value, r.From) {
+		return value, false
+	}
+	rewritten := r.To + value[len(r.From):]
+	return rewritten, rewritten != value
+}
+
+type ImportJob struct {
+	ID             int64            `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode  string           `json:"-" gorm:"size:50;index:idx_import_jobs_namespace_project"`
+	ProjectCode    string           `json:"-" gorm:"size:50;index:idx_import_jobs_namespace_project"`
+	Project        *Project         `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;"`
+	Status         ImportJobStatus  `json:"status" gorm:"size:20;not null"`
+	Overwrite      bool             `json:"overwrite"`
+	TotalLines     int              `json:"totalLines"`
+	ProcessedCount int              `json:"processedCount"`
+	ImportedCount  int              `json:"importedCount"`
+	SkippedCount   int              `json:"skippedCount"`
+	ErrorCount     int              `json:"errorCount"`
+	Errors         []ImportJobError `json:"errors" gorm:"type:longtext;serializer:json"`
+	ErrorMessage   string           `json:"errorMessage" gorm:"size:500"`
+	CreatedAt      time.Time        `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt      time.Time        `json:"updatedAt" gorm:"type:timestamp"`
+	FinishedAt     *time.Time       `json:"finishedAt" gorm:"type:timestamp"`
+	// SourcePrefix and TargetPrefix are the prefix rewrites applied to the rows of the job, nil when it has none
+	SourcePrefix *ImportPrefixRewrite `json:"sourcePrefix" gorm:"type:text;serializer:json"`
+	TargetPrefix *ImportPrefixRewrite `json:"targetPrefix" gorm:"type:text;serializer:json"`
+	// RewrittenCount is the number of rows whose source or target was changed by the prefix rewrites
+	RewrittenCount int `json:"rewrittenCount" gorm:"not null;default:0"`
+}
+
+// IsFinished returns true when the job will not make any further progress
+func (j *ImportJob) IsFinished() bool {
+	return j.Status == ImportJobStatusCompleted || j.Status == ImportJobStatusFailed
+}
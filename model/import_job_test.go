@@ -0,0 +1,31 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportPrefixRewrite_Apply(t *testing.T) {
+	tests := []struct {
+		name        string
+		rewrite     *ImportPrefixRewrite
+		value       string
+		want        string
+		wantChanged bool
+	}{
+		{name: "nil rewrite", rewrite: nil, value: "/en/page", want: "/en/page"},
+		{name: "strips the prefix", rewrite: &ImportPrefixRewrite{From: "/en/", To: "/"}, value: "/en/page", want: "/page", wantChanged: true},
+		{name: "replaces the prefix", rewrite: &ImportPrefixRewrite{From: "https://old.example.com", To: "https://example.com"}, value: "https://old.example.com/page", want: "https://example.com/page", wantChanged: true},
+		{name: "prepends to all the values", rewrite: &ImportPrefixRewrite{To: "/blog"}, value: "/post", want: "/blog/post", wantChanged: true},
+		{name: "value without the prefix", rewrite: &ImportPrefixRewrite{From: "/en/", To: "/"}, value: "/fr/page", want: "/fr/page"},
+		{name: "same prefix", rewrite: &ImportPrefixRewrite{From: "/en/", To: "/en/"}, value: "/en/page", want: "/en/page"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := tt.rewrite.Apply(tt.value)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantChanged, changed)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// ImportDelimiter is the separator of the columns of a delimited import file
+type ImportDelimiter string
+
+const (
+	ImportDelimiterTab       ImportDelimiter = "TAB"
+	ImportDelimiterComma     ImportDelimiter = "COMMA"
+	ImportDelimiterSemicolon ImportDelimiter = "SEMICOLON"
+	ImportDelimiterPipe      ImportDelimiter = "PIPE"
+)
+
+// Rune returns the character separating the columns, a tab when the delimiter is not set
+func (d ImportDelimiter) Rune() rune {
+	switch d {
+	case ImportDelimiterComma:
+		return ','
+	case ImportDelimiterSemicolon:
+		return ';'
+	case ImportDelimiterPipe:
+		return '|'
+	default:
+		return '\t'
+	}
+}
+
+// ImportProfile is a saved set of redirect import options of a project, so that the recurring imports of the files
+// of the same upstream system do not have to specify them again
+type ImportProfile struct {
+	ID            int64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string   `json:"-" gorm:"size:50;uniqueIndex:idx_import_profiles_name"`
+	ProjectCode   string   `json:"-" gorm:"size:50;uniqueIndex:idx_import_profiles_name"`
+	Project       *Project `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;constraint:OnDelete:CASCADE;"`
+	Name          string   `json:"name" gorm:"size:100;not null;uniqueIndex:idx_import_profiles_name" validate:"required,max=100"`
+	// Columns are the import columns in the order of the file, replacing its header. Without columns, the header of
+	// the file gives them.
+	Columns []string `json:"columns" gorm:"type:text;serializer:json"`
+	// SkipHeader skips the first row of the file, a header whose names differ from the import columns
+	SkipHeader bool            `json:"skipHeader" gorm:"not null;default:false"`
+	Delimiter  ImportDelimiter `json:"delimiter" gorm:"size:20;not null;default:'TAB'" validate:"required,oneof=TAB COMMA SEMICOLON PIPE"`
+	// DefaultType and DefaultStatus apply to the rows without type or status, nil when the rows must give them
+	DefaultType   *commonTypes.RedirectType   `json:"defaultType" gorm:"size:50"`
+	DefaultStatus *commonTypes.RedirectStatus `json:"defaultStatus" gorm:"size:50"`
+	// Overwrite replaces the existing drafts of the sources of the file, unless the import sets it
+	Overwrite bool `json:"overwrite" gorm:"not null"`
+	// CreatedBy is the subject who created the profile
+	CreatedBy string    `json:"createdBy" gorm:"size:255;default:'';not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+func (ImportProfile) TableName() string {
+	return "import_profiles"
+}
@@ -0,0 +1,35 @@
+package model
+
+// MassDeletionOperation is the operation deleting published redirects or pages of a project
+type MassDeletionOperation string
+
+const (
+	MassDeletionOperationPublish     MassDeletionOperation = "publish"
+	MassDeletionOperationApply       MassDeletionOperation = "apply"
+	MassDeletionOperationDeleteByTag MassDeletionOperation = "delete_by_tag"
+)
+
+// MassDeletion is the number of the published redirects and pages of a project deleted by an operation,
+// out of the published ones
+type MassDeletion struct {
+	Operation      MassDeletionOperation
+	Redirects      int64
+	RedirectsTotal int64
+	Pages          int64
+	PagesTotal     int64
+	// Forced is true when the operation was forced through the safeguard
+	Forced bool
+}
+
+// Exceeds returns true if the operation deletes at least minCount redirects, or pages, and more than maxPercent
+// of them. It is always false when maxPercent is 0.
+func (d MassDeletion) Exceeds(maxPercent, minCount int) bool {
+	return exceedsShare(d.Redirects, d.RedirectsTotal, maxPercent, minCount) || exceedsShare(d.Pages, d.PagesTotal, maxPercent, minCount)
+}
+
+func exceedsShare(deleted, total int64, maxPercent, minCount int) bool {
+	if maxPercent <= 0 || deleted == 0 || deleted < int64(minCount) {
+		return false
+	}
+	return deleted*100 > total*int64(maxPercent)
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMassDeletion_Exceeds(t *testing.T) {
+	tests := []struct {
+		name       string
+		deletion   MassDeletion
+		maxPercent int
+		minCount   int
+		want       bool
+	}{
+		{name: "redirects above the share", deletion: MassDeletion{Redirects: 60, RedirectsTotal: 100}, maxPercent: 50, minCount: 10, want: true},
+		{name: "redirects at the share", deletion: MassDeletion{Redirects: 50, RedirectsTotal: 100}, maxPercent: 50, minCount: 10},
+		{name: "pages above the share", deletion: MassDeletion{Redirects: 1, RedirectsTotal: 100, Pages: 20, PagesTotal: 20}, maxPercent: 50, minCount: 10, want: true},
+		{name: "below the min count", deletion: MassDeletion{Redirects: 9, RedirectsTotal: 9}, maxPercent: 50, minCount: 10},
+		{name: "no deletion", deletion: MassDeletion{RedirectsTotal: 100}, maxPercent: 50},
+		{name: "disabled", deletion: MassDeletion{Redirects: 100, RedirectsTotal: 100}, maxPercent: 0, minCount: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.deletion.Exceeds(tt.maxPercent, tt.minCount))
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package model
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// NamespacePolicyRuleType is the constraint a rule of a namespace policy puts on the redirects
+type NamespacePolicyRuleType string
+
+const (
+	// NamespacePolicyRuleSourcePattern requires the sources to match the pattern
+	NamespacePolicyRuleSourcePattern NamespacePolicyRuleType = "SOURCE_PATTERN"
+	// NamespacePolicyRuleTargetPattern requires the targets to match the pattern
+	NamespacePolicyRuleTargetPattern NamespacePolicyRuleType = "TARGET_PATTERN"
+	// NamespacePolicyRuleBannedSource rejects the sources matching the pattern
+	NamespacePolicyRuleBannedSource NamespacePolicyRuleType = "BANNED_SOURCE"
+	// NamespacePolicyRuleBannedTarget rejects the targets matching the pattern
+	NamespacePolicyRuleBannedTarget NamespacePolicyRuleType = "BANNED_TARGET"
+	// NamespacePolicyRuleSameOriginTarget requires the targets to be paths, or URLs on the host of a BASIC_HOST source
+	NamespacePolicyRuleSameOriginTarget NamespacePolicyRuleType = "SAME_ORIGIN_TARGET"
+)
+
+func (t NamespacePolicyRuleType) IsValid() bool {
+	switch t {
+	case NamespacePolicyRuleSourcePattern, NamespacePolicyRuleTargetPattern, NamespacePolicyRuleBannedSource,
+		NamespacePolicyRuleBannedTarget, NamespacePolicyRuleSameOriginTarget:
+		return true
+	}
+	return false
+}
+
+// HasPattern returns true if the rule type checks the redirects against a pattern
+func (t NamespacePolicyRuleType) HasPattern() bool {
+	return t != NamespacePolicyRuleSameOriginTarget
+}
+
+// AppliesToSource returns true if the rule type checks the source of the redirects, the target otherwise
+func (t NamespacePolicyRuleType) AppliesToSource() bool {
+	return t == NamespacePolicyRuleSourcePattern || t == NamespacePolicyRuleBannedSource
+}
+
+// NamespacePolicyRule is a constraint the redirect drafts of the projects of a namespace must satisfy
+type NamespacePolicyRule struct {
+	Type NamespacePolicyRuleType `json:"type"`
+	// Pattern is the regular expression of the pattern rules, unused by SAME_ORIGIN_TARGET
+	Pattern string `json:"pattern,omitempty"`
+	// Message explains the rule to the users whose drafts violate it, a description of the rule being used when empty
+	Message string `json:"message,omitempty"`
+}
+
+// Description returns the message of the rule, or describes the rule when it has none
+func (r NamespacePolicyRule) Description() string {
+	if r.Message != "" {
+		return r.Message
+	}
+	switch r.Type {
+	case NamespacePolicyRuleSourcePattern:
+		return fmt.Sprintf("sources must match %s", r.Pattern)
+	case NamespacePolicyRuleTargetPattern:
+		return fmt.Sprintf("targets must match %s", r.Pattern)
+	case NamespacePolicyRuleBannedSource:
+		return fmt.Sprintf("sources must not match %s", r.Pattern)
+	case NamespacePolicyRuleBannedTarget:
+		return fmt.Sprintf("targets must not match %s", r.Pattern)
+	case NamespacePolicyRuleSameOriginTarget:
+		return "targets must be on the same origin"
+	}
+	return string(r.Type)
+}
+
+// NamespacePolicy holds the rules enforced on the redirect drafts of the projects of a namespace, when they are
+// created, updated or imported
+type NamespacePolicy struct {
+	ID            int64                 `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string                `json:"namespaceCode" gorm:"size:50;uniqueIndex:idx_namespace_policies_namespace_code"`
+	Namespace     *Namespace            `json:"-" gorm:"foreignKey:NamespaceCode;references:NamespaceCode;constraint:OnDelete:CASCADE;"`
+	Rules         []NamespacePolicyRule `json:"rules" gorm:"type:text;serializer:json"`
+	// UpdatedBy is the subject who last changed the rules
+	UpdatedBy string    `json:"updatedBy" gorm:"size:255;default:'';not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+// NamespacePolicyViolation is a value of a redirect breaking a rule of the policy of its namespace
+type NamespacePolicyViolation struct {
+	Rule  NamespacePolicyRule
+	Value string
+}
+
+// CompiledNamespacePolicy is a namespace policy whose patterns are compiled, to check many redirects
+type CompiledNamespacePolicy struct {
+	rules    []NamespacePolicyRule
+	patterns []*regexp.Regexp
+}
+
+// Compile compiles the patterns of the rules, a nil policy having no rule
+func (p *NamespacePolicy) Compile() (*CompiledNamespacePolicy, error) {
+	compiled := &CompiledNamespacePolicy{}
+	if p == nil {
+		return compiled, nil
+	}
+	for i, rule := range p.Rules {
+		if !rule.Type.IsValid() {
+			return nil, fmt.Errorf("rule %d: invalid type %q", i+1, rule.Type)
+		}
+		var pattern *regexp.Regexp
+		if rule.Type.HasPattern() {
+			if rule.Pattern == "" {
+				return nil, fmt.Errorf("rule %d: pattern is required", i+1)
+			}
+			var err error
+			if pattern, err = regexp.Compile(rule.Pattern); err != nil {
+				return nil, fmt.Errorf("rule %d: invalid pattern: %w", i+1, err)
+			}
+		}
+		compiled.rules = append(compiled.rules, rule)
+		compiled.patterns = append(compiled.patterns, pattern)
+	}
+	return compiled, nil
+}
+
+// Check returns the first violation of the rules by the redirect, nil when it satisfies them all.
+// The source rules do not apply to the catch-all redirect, which has no source.
+func (p *CompiledNamespacePolicy) Check(redirect *commonTypes.Redirect) *NamespacePolicyViolation {
+	if redirect == nil {
+		return nil
+	}
+	targets := []string{redirect.Target}
+	for _, target := range redirect.Targets {
+		targets = append(targets, target.Target)
+	}
+	for i, rule := range p.rules {
+		if rule.Type.AppliesToSource() {
+			if redirect.Type == commonTypes.RedirectTypeCatchAll {
+				continue
+			}
+			if p.patterns[i].MatchString(redirect.Source) != (rule.Type == NamespacePolicyRuleSourcePattern) {
+				return &NamespacePolicyViolation{Rule: rule, Value: redirect.Source}
+			}
+			continue
+		}
+		for _, target := range targets {
+			if target == "" {
+				continue
+			}
+			var satisfied bool
+			switch rule.Type {
+			case NamespacePolicyRuleTargetPattern:
+				satisfied = p.patterns[i].MatchString(target)
+			case NamespacePolicyRuleBannedTarget:
+				satisfied = !p.patterns[i].MatchString(target)
+			case NamespacePolicyRuleSameOriginTarget:
+				satisfied = isSameOriginTarget(redirect, target)
+			}
+			if !satisfied {
+				return &NamespacePolicyViolation{Rule: rule, Value: target}
+			}
+		}
+	}
+	return nil
+}
+
+// isSameOriginTarget returns true if the target is a path, or an URL on the host of the BASIC_HOST source of the redirect
+func isSameOriginTarget(redirect *commonTypes.Redirect, target string) bool {
+	if strings.HasPrefix(target, "//") {
+		return false
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	if u.Scheme == "" && u.Host == "" {
+		return true
+	}
+	if redirect.Type != commonTypes.RedirectTypeBasicHost {
+		return false
+	}
+	sourceHost, _, _ := strings.Cut(redirect.Source, "/")
+	return (u.Scheme == "http" || u.Scheme == "https") && strings.EqualFold(u.Host, sourceHost)
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespacePolicy_Compile(t *testing.T) {
+	compiled, err := (*NamespacePolicy)(nil).Compile()
+	require.NoError(t, err)
+	assert.Nil(t, compiled.Check(&commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "old", Target: "https://other.com"}))
+
+	tests := []struct {
+		rule     NamespacePolicyRule
+		expected string
+	}{
+		{NamespacePolicyRule{Type: "UNKNOWN"}, `rule 1: invalid type "UNKNOWN"`},
+		{NamespacePolicyRule{Type: NamespacePolicyRuleBannedTarget}, "rule 1: pattern is required"},
+		{NamespacePolicyRule{Type: NamespacePolicyRuleSourcePattern, Pattern: "(["}, "rule 1: invalid pattern: error parsing regexp: missing closing ]: `[`"},
+	}
+	for _, tt := range tests {
+		_, err = (&NamespacePolicy{Rules: []NamespacePolicyRule{tt.rule}}).Compile()
+		assert.EqualError(t, err, tt.expected)
+	}
+	_, err = (&NamespacePolicy{Rules: []NamespacePolicyRule{{Type: NamespacePolicyRuleSameOriginTarget}}}).Compile()
+	assert.NoError(t, err)
+}
+
+func TestCompiledNamespacePolicy_Check(t *testing.T) {
+	policy, err := (&NamespacePolicy{Rules: []NamespacePolicyRule{
+		{Type: NamespacePolicyRuleSourcePattern, Pattern: "^/", Message: "sources must start with /"},
+		{Type: NamespacePolicyRuleBannedSource, Pattern: `^/admin`},
+		{Type: NamespacePolicyRuleBannedTarget, Pattern: `\.exe$`},
+		{Type: NamespacePolicyRuleSameOriginTarget},
+	}}).Compile()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		redirect  commonTypes.Redirect
+		violation *NamespacePolicyViolation
+	}{
+		{"valid", commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new"}, nil},
+		{"source pattern", commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "old", Target: "/new"},
+			&NamespacePolicyViolation{Rule: NamespacePolicyRule{Type: NamespacePolicyRuleSourcePattern, Pattern: "^/", Message: "sources must start with /"}, Value: "old"}},
+		{"banned source", commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/admin/login", Target: "/new"},
+			&NamespacePolicyViolation{Rule: NamespacePolicyRule{Type: NamespacePolicyRuleBannedSource, Pattern: `^/admin`}, Value: "/admin/login"}},
+		{"banned weighted target", commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new",
+			Targets: []commonTypes.RedirectTarget{{Target: "/new", Weight: 50}, {Target: "/setup.exe", Weight: 50}}},
+			&NamespacePolicyViolation{Rule: NamespacePolicyRule{Type: NamespacePolicyRuleBannedTarget, Pattern: `\.exe$`}, Value: "/setup.exe"}},
+		{"other origin", commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "https://other.com/new"},
+			&NamespacePolicyViolation{Rule: NamespacePolicyRule{Type: NamespacePolicyRuleSameOriginTarget}, Value: "https://other.com/new"}},
+		{"protocol relative target", commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "//other.com/new"},
+			&NamespacePolicyViolation{Rule: NamespacePolicyRule{Type: NamespacePolicyRuleSameOriginTarget}, Value: "//other.com/new"}},
+		{"catch-all without source", commonTypes.Redirect{Type: commonTypes.RedirectTypeCatchAll, Target: "/"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.violation, policy.Check(&tt.redirect))
+		})
+	}
+
+	sameOrigin, err := (&NamespacePolicy{Rules: []NamespacePolicyRule{{Type: NamespacePolicyRuleSameOriginTarget}}}).Compile()
+	require.NoError(t, err)
+	assert.Nil(t, sameOrigin.Check(&commonTypes.Redirect{Type: commonTypes.RedirectTypeBasicHost, Source: "Example.com/old", Target: "https://example.com/new"}))
+	assert.NotNil(t, sameOrigin.Check(&commonTypes.Redirect{Type: commonTypes.RedirectTypeBasicHost, Source: "example.com/old", Target: "https://other.com/new"}))
+	assert.NotNil(t, sameOrigin.Check(&commonTypes.Redirect{Type: commonTypes.RedirectTypeRegexHost, Source: `example\.com/(.*)`, Target: "https://example.com/$1"}))
+}
+
+func TestNamespacePolicyRule_Description(t *testing.T) {
+	assert.Equal(t, "no external target", NamespacePolicyRule{Type: NamespacePolicyRuleSameOriginTarget, Message: "no external target"}.Description())
+	assert.Equal(t, "targets must be on the same origin", NamespacePolicyRule{Type: NamespacePolicyRuleSameOriginTarget}.Description())
+	assert.Equal(t, "sources must match ^/", NamespacePolicyRule{Type: NamespacePolicyRuleSourcePattern, Pattern: "^/"}.Description())
+	assert.Equal(t, "targets must not match x", NamespacePolicyRule{Type: NamespacePolicyRuleBannedTarget, Pattern: "x"}.Description())
+}
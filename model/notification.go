@@ -0,0 +1,67 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// NotificationChannel is the transport of the notifications of a subscription
+type NotificationChannel string
+
+const (
+	// NotificationChannelEmail sends the notifications by email to the target address
+	NotificationChannelEmail NotificationChannel = "EMAIL"
+	// NotificationChannelSlack posts the notifications to the target Slack incoming webhook URL
+	NotificationChannelSlack NotificationChannel = "SLACK"
+)
+
+// NotificationEventType is the kind of event a subscription is notified of
+type NotificationEventType string
+
+const (
+	NotificationEventPublishSucceeded NotificationEventType = "PUBLISH_SUCCEEDED"
+	NotificationEventPublishFailed    NotificationEventType = "PUBLISH_FAILED"
+	// NotificationEventQuotaWarning is sent by the publishes leaving the pages of a project close to the total size limit
+	NotificationEventQuotaWarning NotificationEventType = "QUOTA_WARNING"
+	// NotificationEventMassDeletion is sent by the operations deleting a large share of the redirects or pages of a project
+	NotificationEventMassDeletion NotificationEventType = "MASS_DELETION"
+)
+
+// NotificationSubscription is the choice of a user to be notified of some events of a project on a channel
+type NotificationSubscription struct {
+	ID            int64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string   `json:"-" gorm:"size:50;uniqueIndex:idx_notification_subscriptions_user_channel"`
+	ProjectCode   string   `json:"-" gorm:"size:50;uniqueIndex:idx_notification_subscriptions_user_channel"`
+	Project       *Project `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;constraint:OnDelete:CASCADE;"`
+	// Username is the subscribed user, a user having a single subscription per project and channel
+	Username string              `json:"username" gorm:"size:100;not null;uniqueIndex:idx_notification_subscriptions_user_channel"`
+	Channel  NotificationChannel `json:"channel" gorm:"size:20;not null;uniqueIndex:idx_notification_subscriptions_user_channel" validate:"required,oneof=EMAIL SLACK"`
+	// Target is the email address or the Slack webhook URL the notifications are sent to
+	Target    string                  `json:"target" gorm:"size:500;not null" validate:"required,max=500"`
+	Events    []NotificationEventType `json:"events" gorm:"type:text;serializer:json" validate:"required,min=1,dive,oneof=PUBLISH_SUCCEEDED PUBLISH_FAILED QUOTA_WARNING MASS_DELETION"`
+	CreatedAt time.Time               `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time               `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+// Wants returns true if the subscription is notified of the event type
+func (s *NotificationSubscription) Wants(eventType NotificationEventType) bool {
+	return slices.Contains(s.Events, eventType)
+}
+
+// NotificationEvent is an event of a project sent to the subscriptions asking for its type
+type NotificationEvent struct {
+	Type          NotificationEventType
+	NamespaceCode string
+	ProjectCode   string
+	// Subject is the user or automation behind the event
+	Subject string
+	// Version is the project version published
+	Version int
+	// Error is the reason of a failed publish
+	Error string
+	// TotalContentSize and TotalContentSizeLimit are the page sizes of a quota warning
+	TotalContentSize      int64
+	TotalContentSizeLimit int64
+	// MassDeletion holds the counts of a MASS_DELETION event
+	MassDeletion *MassDeletion
+}
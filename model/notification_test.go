@@ -0,0 +1,16 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotificationSubscription_Wants(t *testing.T) {
+	subscription := &NotificationSubscription{Events: []NotificationEventType{NotificationEventPublishFailed, NotificationEventQuotaWarning}}
+
+	assert.True(t, subscription.Wants(NotificationEventPublishFailed))
+	assert.True(t, subscription.Wants(NotificationEventQuotaWarning))
+	assert.False(t, subscription.Wants(NotificationEventPublishSucceeded))
+	assert.False(t, (&NotificationSubscription{}).Wants(NotificationEventPublishFailed))
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxTopic is the kind of an event of the outbox, telling which handler delivers it
+type OutboxTopic string
+
+const (
+	// OutboxTopicInvalidation is a cache invalidation event broadcast to the replicas
+	OutboxTopicInvalidation OutboxTopic = "INVALIDATION"
+	// OutboxTopicNotification is a project event, turned into a delivery per subscription asking for it
+	OutboxTopicNotification OutboxTopic = "NOTIFICATION"
+	// OutboxTopicNotificationDelivery is a notification sent to a subscription
+	OutboxTopicNotificationDelivery OutboxTopic = "NOTIFICATION_DELIVERY"
+)
+
+// OutboxStatus is the state of the delivery of an event of the outbox
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "PENDING"
+	OutboxStatusDelivered OutboxStatus = "DELIVERED"
+	// OutboxStatusFailed is an event given up after its last attempt
+	OutboxStatusFailed OutboxStatus = "FAILED"
+)
+
+// OutboxEvent is an event written in the transaction of the change it announces, and delivered by the relay once
+// committed, so that it is neither lost when the manager stops nor sent for a change rolled back. The events have no
+// foreign key on their project, the deletion of a project being announced too.
+type OutboxEvent struct {
+	ID            int64       `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string      `json:"namespaceCode" gorm:"size:50;not null;default:''"`
+	ProjectCode   string      `json:"projectCode" gorm:"size:50;not null;default:''"`
+	Topic         OutboxTopic `json:"topic" gorm:"size:30;not null"`
+	// Payload is the JSON event delivered by the handler of the topic
+	Payload string `json:"payload" gorm:"type:text;not null"`
+	// DedupKey prevents writing the same event twice, when set
+	DedupKey *string      `json:"dedupKey,omitempty" gorm:"size:100;uniqueIndex:idx_outbox_events_dedup_key"`
+	Status   OutboxStatus `json:"status" gorm:"size:20;not null;index:idx_outbox_events_pending,priority:1"`
+	Attempts int          `json:"attempts" gorm:"not null;default:0"`
+	// LastError is the error of the last failed attempt
+	LastError string `json:"lastError" gorm:"size:1000;not null;default:''"`
+	// AvailableAt is when the next attempt can start
+	AvailableAt time.Time `json:"availableAt" gorm:"type:timestamp;index:idx_outbox_events_pending,priority:2"`
+	// LockedUntil is when the replica delivering the event loses it, another one being able to take it
+	LockedUntil *time.Time `json:"lockedUntil,omitempty" gorm:"type:timestamp"`
+	// ProcessedAt is when the event was delivered or given up
+	ProcessedAt *time.Time `json:"processedAt,omitempty" gorm:"type:timestamp;index"`
+	CreatedAt   time.Time  `json:"createdAt" gorm:"type:timestamp"`
+}
+
+// NewOutboxEvent returns a pending event of the topic carrying the payload serialized as JSON
+func NewOutboxEvent(topic OutboxTopic, namespaceCode, projectCode string, payload interface{}) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		Topic:         topic,
+		Payload:       string(data),
+		Status:        OutboxStatusPending,
+		AvailableAt:   time.Now(),
+	}, nil
+}
+
+// Decode reads the payload of the event into v
+func (e *OutboxEvent) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(e.Payload), v)
+}
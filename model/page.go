@@ -4,6 +4,7 @@ import (
 	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"gorm.io/gorm"
 )
 
 var PageSortableColumns = map[string]string{
@@ -13,6 +14,34 @@ var PageSortableColumns = map[string]string{
 	"updatedAt":   "updated_at",
 }
 
+var PageDraftSortableColumns = map[string]string{
+	"path":        "new_path",
+	"contentType": "new_content_type",
+	"type":        "new_type",
+	"changeType":  "change_type",
+	"updatedAt":   "updated_at",
+}
+
+// PageContentEncoding is the encoding of the page content stored in the database,
+// compressed contents are stored base64 encoded
+type PageContentEncoding string
+
+const (
+	PageContentEncodingIdentity PageContentEncoding = ""
+	PageContentEncodingGzip     PageContentEncoding = "gzip"
+	PageContentEncodingZstd     PageContentEncoding = "zstd"
+)
+
+// PageContentStore is the store of a page content kept outside the database,
+// the content column then holding the key of the content in the store
+type PageContentStore string
+
+const (
+	PageContentStoreDatabase   PageContentStore = ""
+	PageContentStoreS3         PageContentStore = "s3"
+	PageContentStoreFilesystem PageContentStore = "fs"
+)
+
 type Page struct {
 	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement"`
 	NamespaceCode string    `json:"-" gorm:"size:50;index:idx_pages_namespace_project"`
@@ -21,6 +50,11 @@ type Page struct {
 	IsPublished   *bool     `json:"is_published" gorm:"default:false;not null"`
 	PublishedAt   time.Time `json:"publishedAt" gorm:"type:timestamp"`
 	ContentSize   int64     `json:"contentSize" gorm:"default:0;not null"`
+	// ContentEncoding and StoredContentSize describe the content as stored, it is always decompressed on read
+	ContentEncoding   PageContentEncoding `json:"-" gorm:"size:10;default:'';not null"`
+	StoredContentSize int64               `json:"-" gorm:"default:0;not null"`
+	// ContentStore is where the content is stored, it is always loaded from there on read
+	ContentStore PageContentStore `json:"-" gorm:"size:10;default:'';not null"`
 	*commonTypes.Page
 	PageDraft *PageDraft `json:"draft" gorm:"foreignKey:OldPageID;references:ID"`
 	CreatedAt time.Time  `json:"createdAt" gorm:"type:timestamp"`
@@ -29,18 +63,54 @@ type Page struct {
 
 type PageList = commonTypes.PaginatedResult[Page]
 
+// Base returns the page as sent to the agents, along with its id and without the markdown source
+func (p Page) Base() commonTypes.Page {
+	base := *p.Page
+	base.ID = p.ID
+	base.Source = ""
+	return base
+}
+
+type PageCursorList = commonTypes.CursorResult[Page]
+
 type PageDraft struct {
-	ID            int64             `json:"id" gorm:"primaryKey;autoIncrement"`
-	NamespaceCode string            `json:"-" gorm:"size:50;index:idx_page_drafts_namespace_project"`
-	ProjectCode   string            `json:"-" gorm:"size:50;index:idx_page_drafts_namespace_project"`
-	Project       *Project          `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;"`
-	ChangeType    DraftChangeType   `json:"changeType" gorm:"size:50;" validate:"required"`
-	OldPageID     *int64            `json:"-" gorm:"index:idx_page_drafts_old_page_id"`
-	OldPage       *Page             `json:"oldPage" gorm:"foreignKey:OldPageID;"`
-	ContentSize   int64             `json:"contentSize" gorm:"default:0;not null"`
-	NewPage       *commonTypes.Page `gorm:"embedded;embeddedPrefix:new_"`
-	CreatedAt     time.Time         `json:"createdAt" gorm:"type:timestamp"`
-	UpdatedAt     time.Time         `json:"updatedAt" gorm:"type:timestamp"`
+	ID            int64           `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string          `json:"-" gorm:"size:50;index:idx_page_drafts_namespace_project"`
+	ProjectCode   string          `json:"-" gorm:"size:50;index:idx_page_drafts_namespace_project"`
+	Project       *Project        `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;"`
+	ChangeType    DraftChangeType `json:"changeType" gorm:"size:50;" validate:"required"`
+	OldPageID     *int64          `json:"-" gorm:"index:idx_page_drafts_old_page_id"`
+	OldPage       *Page           `json:"oldPage" gorm:"foreignKey:OldPageID;"`
+	ContentSize   int64           `json:"contentSize" gorm:"default:0;not null"`
+	// ContentEncoding and StoredContentSize describe the new content as stored, it is always decompressed on read
+	ContentEncoding   PageContentEncoding `json:"-" gorm:"size:10;default:'';not null"`
+	StoredContentSize int64               `json:"-" gorm:"default:0;not null"`
+	// ContentStore is where the content is stored, it is always loaded from there on read
+	ContentStore PageContentStore  `json:"-" gorm:"size:10;default:'';not null"`
+	NewPage      *commonTypes.Page `gorm:"embedded;embeddedPrefix:new_"`
+	// LintWarnings are the issues found in the content of the new page when the draft was last saved
+	LintWarnings []PageLintWarning `json:"lintWarnings" gorm:"type:text;serializer:json"`
+	// ChangesetID is the changeset grouping the draft, nil when it belongs to none
+	ChangesetID *int64 `json:"changesetID" gorm:"index:idx_page_drafts_changeset_id"`
+	// CreatedBy and UpdatedBy are the subjects who staged the draft and last edited it
+	CreatedBy string    `json:"createdBy" gorm:"size:255;default:'';not null"`
+	UpdatedBy string    `json:"updatedBy" gorm:"size:255;default:'';not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+// BeforeCreate records the subject of the context as the author of the draft, unless already set
+func (d *PageDraft) BeforeCreate(tx *gorm.DB) error {
+	recordAuthor(tx, &d.CreatedBy, &d.UpdatedBy)
+	return nil
+}
+
+// BeforeUpdate records the subject of the context as the last editor of the draft
+func (d *PageDraft) BeforeUpdate(tx *gorm.DB) error {
+	recordEditor(tx, &d.UpdatedBy)
+	return nil
 }
 
 type PageDraftList = commonTypes.PaginatedResult[PageDraft]
+
+type PageDraftCursorList = commonTypes.CursorResult[PageDraft]
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// PageBrokenLink is an internal link of a published page matching neither a page nor a redirect of its project
+type PageBrokenLink struct {
+	ID            int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string `json:"-" gorm:"size:50;index:idx_page_broken_links_namespace_project"`
+	ProjectCode   string `json:"-" gorm:"size:50;index:idx_page_broken_links_namespace_project"`
+	PageID        int64  `json:"pageId" gorm:"not null;index:idx_page_broken_links_page_id"`
+	PagePath      string `json:"pagePath" gorm:"size:600"`
+	// Link is the link as written in the page, URL is the request it resolves to, with the host of BASIC_HOST pages
+	Link string `json:"link" gorm:"size:2048"`
+	URL  string `json:"url" gorm:"size:2048"`
+	// SuggestedTarget is an existing path the link could be redirected to, empty when none was found
+	SuggestedTarget string `json:"suggestedTarget" gorm:"size:2048"`
+	// DraftCreated is true once a redirect draft was created for the suggestion, it is not created again
+	DraftCreated bool      `json:"draftCreated" gorm:"default:false;not null"`
+	CheckedAt    time.Time `json:"checkedAt" gorm:"type:timestamp"`
+}
+
+// PageLinkReport lists the broken links of the published pages of a project
+type PageLinkReport struct {
+	// PageCount is the number of pages with at least one broken link
+	PageCount   int              `json:"pageCount"`
+	BrokenCount int              `json:"brokenCount"`
+	Links       []PageBrokenLink `json:"links"`
+}
+
+// NewPageLinkReport returns the report of the broken links of a project
+func NewPageLinkReport(links []PageBrokenLink) *PageLinkReport {
+	pages := make(map[int64]bool)
+	for _, link := range links {
+		pages[link.PageID] = true
+	}
+	return &PageLinkReport{PageCount: len(pages), BrokenCount: len(links), Links: links}
+}
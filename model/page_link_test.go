@@ -0,0 +1,18 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPageLinkReport(t *testing.T) {
+	report := NewPageLinkReport([]PageBrokenLink{{PageID: 1, Link: "/a"}, {PageID: 1, Link: "/b"}, {PageID: 2, Link: "/a"}})
+	assert.Equal(t, 2, report.PageCount)
+	assert.Equal(t, 3, report.BrokenCount)
+	assert.Len(t, report.Links, 3)
+
+	report = NewPageLinkReport(nil)
+	assert.Equal(t, 0, report.PageCount)
+	assert.Equal(t, 0, report.BrokenCount)
+}
@@ -0,0 +1,38 @@
+package model
+
+// PageLintRule is the check of the content of a page raising a lint warning
+type PageLintRule string
+
+const (
+	// PageLintRuleHTMLMalformed reports an HTML page with unclosed or mismatched elements
+	PageLintRuleHTMLMalformed PageLintRule = "HTML_MALFORMED"
+	// PageLintRuleXMLMalformed reports an XML page which is not well-formed
+	PageLintRuleXMLMalformed PageLintRule = "XML_MALFORMED"
+	// PageLintRuleRobotsInvalidLine reports a robots.txt line which is neither a directive, a comment nor blank
+	PageLintRuleRobotsInvalidLine PageLintRule = "ROBOTS_INVALID_LINE"
+	// PageLintRuleRobotsUnknownDirective reports a robots.txt directive unknown to the crawlers
+	PageLintRuleRobotsUnknownDirective PageLintRule = "ROBOTS_UNKNOWN_DIRECTIVE"
+	// PageLintRuleRobotsMissingUserAgent reports a robots.txt rule before any User-agent line, ignored by the crawlers
+	PageLintRuleRobotsMissingUserAgent PageLintRule = "ROBOTS_MISSING_USER_AGENT"
+	// PageLintRuleRobotsInvalidValue reports a robots.txt directive with an invalid value
+	PageLintRuleRobotsInvalidValue PageLintRule = "ROBOTS_INVALID_VALUE"
+	// PageLintRuleSitemapInvalidRoot reports a sitemap whose root is neither a urlset nor a sitemapindex of the
+	// sitemap protocol
+	PageLintRuleSitemapInvalidRoot PageLintRule = "SITEMAP_INVALID_ROOT"
+	// PageLintRuleSitemapInvalidElement reports an element of a sitemap not allowed by the sitemap schema
+	PageLintRuleSitemapInvalidElement PageLintRule = "SITEMAP_INVALID_ELEMENT"
+	// PageLintRuleSitemapInvalidValue reports a value of a sitemap not allowed by the sitemap schema
+	PageLintRuleSitemapInvalidValue PageLintRule = "SITEMAP_INVALID_VALUE"
+	// PageLintRuleSitemapTooManyEntries reports a sitemap with more URLs or sitemaps than the protocol allows
+	PageLintRuleSitemapTooManyEntries PageLintRule = "SITEMAP_TOO_MANY_ENTRIES"
+	// PageLintRuleSecretDetected reports a likely credential or API key, found by the secrets scanning
+	PageLintRuleSecretDetected PageLintRule = "SECRET_DETECTED"
+)
+
+// PageLintWarning is an issue found in the content of a page, which does not prevent saving it
+type PageLintWarning struct {
+	Rule PageLintRule `json:"rule"`
+	// Line is the line of the content the issue is found on, 0 when it applies to the whole content
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
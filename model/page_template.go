@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// pageTemplateVariableRegex matches the {{variable}} placeholders of a template, spaces around the name being allowed
+var pageTemplateVariableRegex = regexp.MustCompile(`{{\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*}}`)
+
+// PageTemplate is a namespace scoped page content with {{variables}}, rendered to create the pages of its projects
+type PageTemplate struct {
+	ID            int64                       `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string                      `json:"-" gorm:"size:50;uniqueIndex:idx_page_templates_namespace_code"`
+	Namespace     *Namespace                  `json:"namespace" gorm:"foreignKey:NamespaceCode;references:NamespaceCode;constraint:OnDelete:CASCADE;"`
+	Code          string                      `json:"code" gorm:"size:50;uniqueIndex:idx_page_templates_namespace_code" validate:"required,code"`
+	Name          string                      `json:"name" gorm:"size:255" validate:"required"`
+	ContentType   commonTypes.PageContentType `json:"contentType" gorm:"size:50" validate:"required"`
+	Content       string                      `json:"content" gorm:"type:longtext"`
+	CreatedAt     time.Time                   `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt     time.Time                   `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+// Variables returns the sorted names of the variables used in the template content
+func (t PageTemplate) Variables() []string {
+	seen := make(map[string]bool)
+	variables := make([]string, 0)
+	for _, match := range pageTemplateVariableRegex.FindAllStringSubmatch(t.Content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			variables = append(variables, match[1])
+		}
+	}
+	sort.Strings(variables)
+	return variables
+}
+
+// Render replaces the variables of the template content with values, every variable must have a value
+func (t PageTemplate) Render(values map[string]string) (string, error) {
+	var missing []string
+	for _, variable := range t.Variables() {
+		if _, ok := values[variable]; !ok {
+			missing = append(missing, variable)
+		}
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing values for template variables: %s", strings.Join(missing, ", "))
+	}
+
+	return pageTemplateVariableRegex.ReplaceAllStringFunc(t.Content, func(placeholder string) string {
+		return values[pageTemplateVariableRegex.FindStringSubmatch(placeholder)[1]]
+	}), nil
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageTemplate_Variables(t *testing.T) {
+	assert.Equal(t, []string{}, PageTemplate{Content: "User-agent: *"}.Variables())
+	assert.Equal(t, []string{"host", "until"}, PageTemplate{Content: "{{ until }} {{host}} {{host}} {{ not a var }}"}.Variables())
+}
+
+func TestPageTemplate_Render(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		values  map[string]string
+		want    string
+		wantErr string
+	}{
+		{name: "without variables", content: "User-agent: *", want: "User-agent: *"},
+		{name: "replaces every occurrence", content: "Sitemap: https://{{host}}/sitemap.xml\nHost: {{ host }}", values: map[string]string{"host": "example.com"}, want: "Sitemap: https://example.com/sitemap.xml\nHost: example.com"},
+		{name: "extra values are ignored", content: "{{a}}", values: map[string]string{"a": "1", "b": "2"}, want: "1"},
+		{name: "values are not rendered again", content: "{{a}}", values: map[string]string{"a": "{{b}}"}, want: "{{b}}"},
+		{name: "missing values", content: "{{a}} {{b}} {{c}}", values: map[string]string{"b": ""}, wantErr: "missing values for template variables: a, c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PageTemplate{Content: tt.content}.Render(tt.values)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -7,14 +7,19 @@ import (
 type SectionType string
 type ActionType string
 type ResourceType string
+type PermissionEffect string
 
 const (
-	AdminSectionUsers      SectionType = "users"
-	AdminSectionRoles      SectionType = "roles"
-	AdminSectionProjects   SectionType = "projects"
-	AdminSectionNamespaces SectionType = "namespaces"
-	AdminSectionTokens     SectionType = "tokens"
-	AdminSectionAll        SectionType = "*"
+	AdminSectionUsers       SectionType = "users"
+	AdminSectionRoles       SectionType = "roles"
+	AdminSectionProjects    SectionType = "projects"
+	AdminSectionNamespaces  SectionType = "namespaces"
+	AdminSectionTokens      SectionType = "tokens"
+	AdminSectionImpersonate SectionType = "impersonate"
+	AdminSectionDraftLocks  SectionType = "draft_locks"
+	AdminSectionConfig      SectionType = "config"
+	AdminSectionAgents      SectionType = "agents"
+	AdminSectionAll         SectionType = "*"
 
 	ActionRead  ActionType = "read"
 	ActionWrite ActionType = "write"
@@ -25,14 +30,18 @@ const (
 	ResourceTypeAgent    ResourceType = "agent"
 	ResourceTypeAll      ResourceType = "*"
 	ResourceTypeAny      ResourceType = "any"
+
+	PermissionEffectAllow PermissionEffect = "ALLOW"
+	PermissionEffectDeny  PermissionEffect = "DENY"
 )
 
 type ResourcePermission struct {
-	ID        int64        `json:"id" gorm:"primaryKey;autoIncrement"`
-	Namespace string       `json:"namespace" gorm:"size:50;not null;index:idx_res_perm_namespace"`
-	Project   string       `json:"project" gorm:"size:50;index:idx_res_perm_project"`
-	Resource  ResourceType `json:"resource" gorm:"size:50;not null"`
-	Action    ActionType   `json:"action" gorm:"size:50;not null"`
+	ID        int64            `json:"id" gorm:"primaryKey;autoIncrement"`
+	Namespace string           `json:"namespace" gorm:"size:50;not null;index:idx_res_perm_namespace"`
+	Project   string           `json:"project" gorm:"size:50;index:idx_res_perm_project"`
+	Resource  ResourceType     `json:"resource" gorm:"size:50;not null"`
+	Action    ActionType       `json:"action" gorm:"size:50;not null"`
+	Effect    PermissionEffect `json:"effect" gorm:"size:10;not null;default:ALLOW"`
 	RoleID    int64
 	Role      Role      `json:"role,omitempty"`
 	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
@@ -42,10 +51,17 @@ func (ResourcePermission) TableName() string {
 	return "resource_permissions"
 }
 
+// IsDeny returns true if the permission removes access instead of granting it
+func (p ResourcePermission) IsDeny() bool {
+	return p.Effect == PermissionEffectDeny
+}
+
 type AdminPermission struct {
-	ID        int64       `json:"id" gorm:"primaryKey;autoIncrement"`
-	Section   SectionType `json:"section" gorm:"size:100;not null;index:idx_admin_perm_section"`
-	Action    ActionType  `json:"action" gorm:"size:50;not null"`
+	ID        int64            `json:"id" gorm:"primaryKey;autoIncrement"`
+	Namespace string           `json:"namespace" gorm:"size:50;not null;default:*"`
+	Section   SectionType      `json:"section" gorm:"size:100;not null;index:idx_admin_perm_section"`
+	Action    ActionType       `json:"action" gorm:"size:50;not null"`
+	Effect    PermissionEffect `json:"effect" gorm:"size:10;not null;default:ALLOW"`
 	RoleID    int64
 	Role      Role      `json:"role,omitempty"`
 	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
@@ -54,3 +70,29 @@ type AdminPermission struct {
 func (AdminPermission) TableName() string {
 	return "admin_permissions"
 }
+
+// IsDeny returns true if the permission removes access instead of granting it
+func (p AdminPermission) IsDeny() bool {
+	return p.Effect == PermissionEffectDeny
+}
+
+// IsGlobal returns true if the permission is not scoped to a namespace
+func (p AdminPermission) IsGlobal() bool {
+	return p.Namespace == "" || p.Namespace == "*"
+}
+
+// NamespaceOrAll returns the given admin permission namespace, or * when none is set
+func NamespaceOrAll(namespace *string) string {
+	if namespace == nil || *namespace == "" {
+		return "*"
+	}
+	return *namespace
+}
+
+// EffectOrDefault returns the given effect, or ALLOW when none is set
+func EffectOrDefault(effect *PermissionEffect) PermissionEffect {
+	if effect == nil || *effect == "" {
+		return PermissionEffectAllow
+	}
+	return *effect
+}
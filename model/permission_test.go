@@ -13,3 +13,39 @@ func TestResourcePermission_TableName(t *testing.T) {
 func TestAdminPermission_TableName(t *testing.T) {
 	assert.Equal(t, "admin_permissions", AdminPermission{}.TableName())
 }
+
+func TestResourcePermission_IsDeny(t *testing.T) {
+	assert.False(t, ResourcePermission{}.IsDeny())
+	assert.False(t, ResourcePermission{Effect: PermissionEffectAllow}.IsDeny())
+	assert.True(t, ResourcePermission{Effect: PermissionEffectDeny}.IsDeny())
+}
+
+func TestAdminPermission_IsDeny(t *testing.T) {
+	assert.False(t, AdminPermission{}.IsDeny())
+	assert.False(t, AdminPermission{Effect: PermissionEffectAllow}.IsDeny())
+	assert.True(t, AdminPermission{Effect: PermissionEffectDeny}.IsDeny())
+}
+
+func TestEffectOrDefault(t *testing.T) {
+	deny := PermissionEffectDeny
+	empty := PermissionEffect("")
+
+	assert.Equal(t, PermissionEffectAllow, EffectOrDefault(nil))
+	assert.Equal(t, PermissionEffectAllow, EffectOrDefault(&empty))
+	assert.Equal(t, PermissionEffectDeny, EffectOrDefault(&deny))
+}
+
+func TestAdminPermission_IsGlobal(t *testing.T) {
+	assert.True(t, AdminPermission{}.IsGlobal())
+	assert.True(t, AdminPermission{Namespace: "*"}.IsGlobal())
+	assert.False(t, AdminPermission{Namespace: "ns1"}.IsGlobal())
+}
+
+func TestNamespaceOrAll(t *testing.T) {
+	ns := "ns1"
+	empty := ""
+
+	assert.Equal(t, "*", NamespaceOrAll(nil))
+	assert.Equal(t, "*", NamespaceOrAll(&empty))
+	assert.Equal(t, "ns1", NamespaceOrAll(&ns))
+}
@@ -29,6 +29,59 @@ type Project struct {
 	CreatedAt     time.Time  `json:"createdAt" gorm:"type:timestamp"`
 	UpdatedAt     time.Time  `json:"UpdatedAt" gorm:"type:timestamp"`
 	PublishedAt   time.Time  `json:"publishedAt" gorm:"type:timestamp"`
+	// Revision identifies the source of the published version, the commit SHA for the projects synced from Git
+	Revision string `json:"revision" gorm:"size:64;default:'';not null"`
+	// PublishedBy is the subject who published the current version
+	PublishedBy string `json:"publishedBy" gorm:"size:255;default:'';not null"`
+	// RedirectOptions are the matching options of the redirects of the project
+	RedirectOptions types.RedirectOptions `json:"redirectOptions" gorm:"embedded;embeddedPrefix:redirect_"`
+	// Maintenance is the maintenance mode of the project, changed without publishing
+	Maintenance types.Maintenance `json:"maintenance" gorm:"embedded;embeddedPrefix:maintenance_"`
+	// CacheTTL are the cache durations hinted to the agents, they are not published and apply to all the environments
+	CacheTTL types.CacheTTL `json:"cacheTTL" gorm:"embedded;embeddedPrefix:cache_ttl_"`
+	// PublishAttempts is the number of attempts made by the publish returning the project
+	PublishAttempts int `json:"-" gorm:"-"`
 }
 
 type ProjectList = types.PaginatedResult[Project]
+
+// ProjectCounts are the numbers of redirects, pages and drafts of a project
+type ProjectCounts struct {
+	ProjectCode    string
+	Redirects      int64
+	RedirectDrafts int64
+	Pages          int64
+	PageDrafts     int64
+}
+
+// ProjectStats aggregates the redirects, pages and drafts of a project
+type ProjectStats struct {
+	Version     int
+	PublishedAt time.Time
+
+	RedirectTotal               int64
+	RedirectCountBasic          int64
+	RedirectCountBasicHost      int64
+	RedirectCountRegex          int64
+	RedirectCountRegexHost      int64
+	RedirectCountMovedPermanent int64
+	RedirectCountFound          int64
+	RedirectCountTemporary      int64
+	RedirectCountPermanent      int64
+
+	RedirectDraftTotal       int64
+	RedirectDraftCountCreate int64
+	RedirectDraftCountUpdate int64
+	RedirectDraftCountDelete int64
+
+	PageTotal            int64
+	PageCountBasic       int64
+	PageCountBasicHost   int64
+	PageContentSize      int64
+	PageContentSizeLimit int64 `gorm:"-"`
+
+	PageDraftTotal       int64
+	PageDraftCountCreate int64
+	PageDraftCountUpdate int64
+	PageDraftCountDelete int64
+}
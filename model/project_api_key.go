@@ -0,0 +1,95 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	// ProjectAPIKeyPrefix starts the project API keys, it differs from TokenPrefix so that the auth middleware tells them apart
+	ProjectAPIKeyPrefix        = "flectopk_"
+	ProjectAPIKeyNameMaxLength = 100
+)
+
+// ProjectAPIKeyScope is what a project API key is allowed to do on the drafts of its project
+type ProjectAPIKeyScope string
+
+const (
+	// ProjectAPIKeyScopeRead reads the redirects and pages of the project, with their drafts
+	ProjectAPIKeyScopeRead ProjectAPIKeyScope = "READ"
+	// ProjectAPIKeyScopeWrite creates, updates and deletes the redirect and page drafts of the project, without publishing them
+	ProjectAPIKeyScopeWrite ProjectAPIKeyScope = "WRITE"
+)
+
+// ProjectAPIKey is an API key limited to the drafts of a single project, managed from the project settings.
+// Only the hash of the key is stored, the plain key being returned once at creation. The keys are stored in the primary
+// database to be found from the key alone, without foreign key to their project which may be stored in a shard.
+type ProjectAPIKey struct {
+	ID            int64  `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string `json:"-" gorm:"size:50;uniqueIndex:idx_project_api_keys_name"`
+	ProjectCode   string `json:"-" gorm:"size:50;uniqueIndex:idx_project_api_keys_name"`
+	// Name is unique in the project
+	Name       string               `json:"name" gorm:"size:100;not null;uniqueIndex:idx_project_api_keys_name" validate:"required,max=100"`
+	KeyHash    string               `json:"-" gorm:"size:64;not null;uniqueIndex:idx_project_api_keys_key_hash"`
+	KeyPreview string               `json:"keyPreview" gorm:"size:30;not null"` // e.g., "flectopk_abcd...wxyz"
+	Scopes     []ProjectAPIKeyScope `json:"scopes" gorm:"type:text;serializer:json" validate:"required,min=1,dive,oneof=READ WRITE"`
+	ExpiresAt  *time.Time           `json:"expiresAt" gorm:"type:timestamp"`
+	// CreatedBy is the subject who created the key
+	CreatedBy string    `json:"createdBy" gorm:"size:255;default:'';not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+// IsExpired checks if the key has expired
+func (k *ProjectAPIKey) IsExpired() bool {
+	if k.ExpiresAt == nil {
+		return false
+	}
+	return time.Now().After(*k.ExpiresAt)
+}
+
+// HasScope returns true if the key was granted the scope
+func (k *ProjectAPIKey) HasScope(scope ProjectAPIKeyScope) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// GetSubject returns the subject recorded as the author of the changes made with the key
+func (k *ProjectAPIKey) GetSubject() string {
+	return "apikey:" + k.NamespaceCode + "/" + k.ProjectCode + "/" + k.Name
+}
+
+// Permissions returns the permissions of the key: its scopes on the redirects and the pages of its project, and nothing else
+func (k *ProjectAPIKey) Permissions() *SubjectPermissions {
+	permissions := &SubjectPermissions{}
+	for _, resource := range []ResourceType{ResourceTypeRedirect, ResourceTypePage} {
+		if k.HasScope(ProjectAPIKeyScopeRead) {
+			permissions.Resources = append(permissions.Resources, ResourcePermission{
+				Namespace: k.NamespaceCode,
+				Project:   k.ProjectCode,
+				Resource:  resource,
+				Action:    ActionRead,
+				Effect:    PermissionEffectAllow,
+			})
+		}
+		if k.HasScope(ProjectAPIKeyScopeWrite) {
+			permissions.Resources = append(permissions.Resources, ResourcePermission{
+				Namespace: k.NamespaceCode,
+				Project:   k.ProjectCode,
+				Resource:  resource,
+				Action:    ActionWrite,
+				Effect:    PermissionEffectAllow,
+			})
+		}
+	}
+	return permissions
+}
+
+// GenerateProjectAPIKeyPreview creates a preview string like "flectopk_abcd...wxyz" from the full key
+func GenerateProjectAPIKeyPreview(plainKey string) string {
+	if len(plainKey) <= len(ProjectAPIKeyPrefix)+TokenPreviewChars*2 {
+		return plainKey
+	}
+	start := plainKey[:len(ProjectAPIKeyPrefix)+TokenPreviewChars]
+	end := plainKey[len(plainKey)-TokenPreviewChars:]
+	return start + "..." + end
+}
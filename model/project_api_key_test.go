@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectAPIKey_IsExpired(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	assert.False(t, (&ProjectAPIKey{}).IsExpired())
+	assert.True(t, (&ProjectAPIKey{ExpiresAt: &past}).IsExpired())
+	assert.False(t, (&ProjectAPIKey{ExpiresAt: &future}).IsExpired())
+}
+
+func TestProjectAPIKey_GetSubject(t *testing.T) {
+	key := &ProjectAPIKey{NamespaceCode: "ns1", ProjectCode: "proj1", Name: "ci"}
+
+	assert.Equal(t, "apikey:ns1/proj1/ci", key.GetSubject())
+}
+
+func TestProjectAPIKey_Permissions(t *testing.T) {
+	t.Run("read", func(t *testing.T) {
+		key := &ProjectAPIKey{NamespaceCode: "ns1", ProjectCode: "proj1", Scopes: []ProjectAPIKeyScope{ProjectAPIKeyScopeRead}}
+
+		assert.Equal(t, []ResourcePermission{
+			{Namespace: "ns1", Project: "proj1", Resource: ResourceTypeRedirect, Action: ActionRead, Effect: PermissionEffectAllow},
+			{Namespace: "ns1", Project: "proj1", Resource: ResourceTypePage, Action: ActionRead, Effect: PermissionEffectAllow},
+		}, key.Permissions().Resources)
+		assert.Empty(t, key.Permissions().Admin)
+	})
+
+	t.Run("read and write", func(t *testing.T) {
+		key := &ProjectAPIKey{NamespaceCode: "ns1", ProjectCode: "proj1", Scopes: []ProjectAPIKeyScope{ProjectAPIKeyScopeRead, ProjectAPIKeyScopeWrite}}
+
+		assert.Len(t, key.Permissions().Resources, 4)
+		assert.True(t, key.HasScope(ProjectAPIKeyScopeWrite))
+	})
+
+	t.Run("no scope", func(t *testing.T) {
+		assert.Empty(t, (&ProjectAPIKey{}).Permissions().Resources)
+	})
+}
+
+func TestGenerateProjectAPIKeyPreview(t *testing.T) {
+	assert.Equal(t, "flectopk_abcd...wxyz", GenerateProjectAPIKeyPreview("flectopk_abcdefghijklmnopqrstuvwxyz"))
+	assert.Equal(t, "flectopk_short", GenerateProjectAPIKeyPreview("flectopk_short"))
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// ProjectEnvironment is the snapshot of the published redirects and pages of a project
+// promoted to an environment, agents subscribed to it are served from the snapshot
+type ProjectEnvironment struct {
+	ID             int64                   `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode  string                  `json:"-" gorm:"size:50;uniqueIndex:idx_project_environments_namespace_project_environment"`
+	ProjectCode    string                  `json:"-" gorm:"size:50;uniqueIndex:idx_project_environments_namespace_project_environment"`
+	Environment    commonTypes.Environment `json:"environment" gorm:"size:20;uniqueIndex:idx_project_environments_namespace_project_environment"`
+	Project        *Project                `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;constraint:OnDelete:CASCADE;"`
+	Version        int                     `json:"version" gorm:"not null"`
+	CountRedirects int64                   `json:"countRedirects" gorm:"default:0;not null"`
+	CountPages     int64                   `json:"countPages" gorm:"default:0;not null"`
+	Redirects      []commonTypes.Redirect  `json:"-" gorm:"type:longtext;serializer:json"`
+	Pages          []commonTypes.Page      `json:"-" gorm:"type:longtext;serializer:json"`
+	// RedirectOptions are the matching options of the project when promoted
+	RedirectOptions commonTypes.RedirectOptions `json:"redirectOptions" gorm:"embedded;embeddedPrefix:redirect_"`
+	PromotedBy      string                      `json:"promotedBy" gorm:"size:100"`
+	PromotedAt      time.Time                   `json:"promotedAt" gorm:"type:timestamp"`
+	CreatedAt       time.Time                   `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt       time.Time                   `json:"updatedAt" gorm:"type:timestamp"`
+}
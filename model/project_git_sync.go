@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+)
+
+// ProjectGitSync pulls the redirects and pages of a project from manifests stored in a Git repository,
+// see ProjectManifest for their format
+type ProjectGitSync struct {
+	ID            int64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string   `json:"-" gorm:"size:50;uniqueIndex:idx_project_git_syncs_namespace_project"`
+	ProjectCode   string   `json:"-" gorm:"size:50;uniqueIndex:idx_project_git_syncs_namespace_project"`
+	Project       *Project `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;constraint:OnDelete:CASCADE;"`
+	RepoURL       string   `json:"repoURL" gorm:"size:500;not null" validate:"required,git_url"`
+	Branch        string   `json:"branch" gorm:"size:255;not null" validate:"required,max=255"`
+	// Path is the manifest file, or the directory whose YAML and JSON files are merged, from the repository root
+	Path string `json:"path" gorm:"size:500;not null" validate:"max=500"`
+	// AutoPublish publishes the project after a sync bringing changes
+	AutoPublish bool `json:"autoPublish" gorm:"default:false;not null"`
+	// Poll syncs the project at the git_sync.poll_interval, in addition to the webhook calls
+	Poll bool `json:"poll" gorm:"default:false;not null"`
+	// WebhookSecret authenticates the push notifications of the Git hosting service
+	WebhookSecret string     `json:"-" gorm:"size:64;not null"`
+	LastCommit    string     `json:"lastCommit" gorm:"size:64;default:'';not null"`
+	LastSyncedAt  *time.Time `json:"lastSyncedAt" gorm:"type:timestamp"`
+	LastError     string     `json:"lastError" gorm:"size:1000;default:'';not null"`
+	CreatedAt     time.Time  `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt     time.Time  `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+// GitSyncResult is the outcome of the sync of a project from its Git repository
+type GitSyncResult struct {
+	Commit string `json:"commit"`
+	// Skipped is set when the commit was already synced, Apply being nil
+	Skipped bool         `json:"skipped"`
+	Apply   *ApplyResult `json:"apply"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// ProjectManifest is the full desired set of redirects and pages of a project, its fields
+// are named as in the API serving the redirects and pages to the agents
+type ProjectManifest struct {
+	Redirects []ManifestRedirect `json:"redirects"`
+	Pages     []commonTypes.Page `json:"pages"`
+}
+
+// ManifestRedirect is a redirect of a manifest with its tags
+type ManifestRedirect struct {
+	commonTypes.Redirect
+	Tags []string `json:"tags,omitempty"`
+}
+
+type ApplyAction string
+
+const (
+	ApplyActionCreate ApplyAction = "CREATE"
+	ApplyActionUpdate ApplyAction = "UPDATE"
+	ApplyActionDelete ApplyAction = "DELETE"
+)
+
+// ApplyChange is a difference between the published project and its manifest
+type ApplyChange struct {
+	Action ApplyAction `json:"action"`
+	// Key is the source of a redirect, followed by its conditions when it has some, or the path of a page
+	Key string `json:"key"`
+}
+
+// ApplyResult reports the differences between the published project and its manifest
+type ApplyResult struct {
+	Redirects []ApplyChange `json:"redirects"`
+	Pages     []ApplyChange `json:"pages"`
+	// Unchanged is the number of published redirects and pages already matching the manifest
+	Unchanged int `json:"unchanged"`
+	// Published is set when the project was published, Version being its new version
+	Published bool `json:"published"`
+	Version   int  `json:"version"`
+}
+
+// HasChanges reports whether the published project differs from its manifest
+func (r *ApplyResult) HasChanges() bool {
+	return len(r.Redirects) > 0 || len(r.Pages) > 0
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"time"
+)
+
+// ProjectMemberRole is the role of a user in a single project, granting permissions on top of the roles of the user
+type ProjectMemberRole string
+
+const (
+	// ProjectMemberRoleOwner reads and writes the redirects, pages and agents of the project, and manages its members.
+	// A project has at most one owner, set by transferring the ownership.
+	ProjectMemberRoleOwner ProjectMemberRole = "OWNER"
+	// ProjectMemberRoleEditor reads and writes the redirects, pages and agents of the project, and publishes it
+	ProjectMemberRoleEditor ProjectMemberRole = "EDITOR"
+	// ProjectMemberRoleViewer reads the redirects, pages and agents of the project
+	ProjectMemberRoleViewer ProjectMemberRole = "VIEWER"
+)
+
+// ProjectMember is a user granted a role scoped to one project, managed from the project settings by the namespace
+// administrators and the owner of the project. The members are stored with the users in the primary database, without
+// foreign key to their project which may be stored in a shard, and are deleted with it by the project repository.
+type ProjectMember struct {
+	ID            int64             `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string            `json:"-" gorm:"size:50;uniqueIndex:idx_project_members_user"`
+	ProjectCode   string            `json:"-" gorm:"size:50;uniqueIndex:idx_project_members_user"`
+	UserID        int64             `json:"userId" gorm:"not null;uniqueIndex:idx_project_members_user;index:idx_project_members_user_id"`
+	User          *User             `json:"user" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+	Role          ProjectMemberRole `json:"role" gorm:"size:20;not null" validate:"required,oneof=OWNER EDITOR VIEWER"`
+	// CreatedBy is the subject who added the member
+	CreatedBy string    `json:"createdBy" gorm:"size:255;default:'';not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+func (ProjectMember) TableName() string {
+	return "project_members"
+}
+
+// IsOwner returns true if the member owns the project
+func (m *ProjectMember) IsOwner() bool {
+	return m.Role == ProjectMemberRoleOwner
+}
+
+// Permissions returns the resource permissions granted by the role of the member on its project
+func (m *ProjectMember) Permissions() []ResourcePermission {
+	var action ActionType
+	switch m.Role {
+	case ProjectMemberRoleOwner, ProjectMemberRoleEditor:
+		action = ActionAll
+	case ProjectMemberRoleViewer:
+		action = ActionRead
+	default:
+		return nil
+	}
+	return []ResourcePermission{{
+		Namespace: m.NamespaceCode,
+		Project:   m.ProjectCode,
+		Resource:  ResourceTypeAll,
+		Action:    action,
+		Effect:    PermissionEffectAllow,
+	}}
+}
+
+// Username returns the username of the member, empty when the user is not loaded
+func (m *ProjectMember) Username() string {
+	if m.User == nil {
+		return ""
+	}
+	return m.User.Username
+}
+
+// Firstname returns the firstname of the member, empty when the user is not loaded
+func (m *ProjectMember) Firstname() string {
+	if m.User == nil {
+		return ""
+	}
+	return m.User.Firstname
+}
+
+// Lastname returns the lastname of the member, empty when the user is not loaded
+func (m *ProjectMember) Lastname() string {
+	if m.User == nil {
+		return ""
+	}
+	return m.User.Lastname
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProjectMember_TableName(t *testing.T) {
+	assert.Equal(t, "project_members", ProjectMember{}.TableName())
+}
+
+func TestProjectMember_IsOwner(t *testing.T) {
+	assert.True(t, (&ProjectMember{Role: ProjectMemberRoleOwner}).IsOwner())
+	assert.False(t, (&ProjectMember{Role: ProjectMemberRoleEditor}).IsOwner())
+}
+
+func TestProjectMember_Permissions(t *testing.T) {
+	tests := []struct {
+		name string
+		role ProjectMemberRole
+		want []ResourcePermission
+	}{
+		{
+			name: "owner",
+			role: ProjectMemberRoleOwner,
+			want: []ResourcePermission{{Namespace: "ns1", Project: "proj1", Resource: ResourceTypeAll, Action: ActionAll, Effect: PermissionEffectAllow}},
+		},
+		{
+			name: "editor",
+			role: ProjectMemberRoleEditor,
+			want: []ResourcePermission{{Namespace: "ns1", Project: "proj1", Resource: ResourceTypeAll, Action: ActionAll, Effect: PermissionEffectAllow}},
+		},
+		{
+			name: "viewer",
+			role: ProjectMemberRoleViewer,
+			want: []ResourcePermission{{Namespace: "ns1", Project: "proj1", Resource: ResourceTypeAll, Action: ActionRead, Effect: PermissionEffectAllow}},
+		},
+		{
+			name: "unknown role",
+			role: "ADMIN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member := &ProjectMember{NamespaceCode: "ns1", ProjectCode: "proj1", Role: tt.role}
+
+			assert.Equal(t, tt.want, member.Permissions())
+		})
+	}
+}
+
+func TestProjectMember_User(t *testing.T) {
+	member := &ProjectMember{User: &User{Username: "alice", Firstname: "Alice", Lastname: "Martin"}}
+
+	assert.Equal(t, "alice", member.Username())
+	assert.Equal(t, "Alice", member.Firstname())
+	assert.Equal(t, "Martin", member.Lastname())
+	assert.Empty(t, (&ProjectMember{}).Username())
+	assert.Empty(t, (&ProjectMember{}).Firstname())
+	assert.Empty(t, (&ProjectMember{}).Lastname())
+}
@@ -0,0 +1,23 @@
+package model
+
+// ProjectPublishStatus is the outcome of the publish of a project within the publish of its namespace
+type ProjectPublishStatus string
+
+const (
+	ProjectPublishStatusPublished ProjectPublishStatus = "PUBLISHED"
+	// ProjectPublishStatusLocked is set when another user holds a draft lock or a publish is in progress
+	ProjectPublishStatusLocked ProjectPublishStatus = "LOCKED"
+	ProjectPublishStatusFailed ProjectPublishStatus = "FAILED"
+	// ProjectPublishStatusSkipped is set when the project was left out after another project was locked or failed,
+	// or had no draft left to publish
+	ProjectPublishStatusSkipped ProjectPublishStatus = "SKIPPED"
+)
+
+// ProjectPublishResult is the result of the publish of a project of a namespace
+type ProjectPublishResult struct {
+	ProjectCode string               `json:"projectCode"`
+	Status      ProjectPublishStatus `json:"status"`
+	// Version is the new version of the project when published
+	Version int    `json:"version"`
+	Error   string `json:"error"`
+}
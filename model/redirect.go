@@ -4,6 +4,7 @@ import (
 	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"gorm.io/gorm"
 )
 
 const (
@@ -20,9 +21,20 @@ var RedirectSortableColumns = map[string]string{
 	"target":    "target",
 	"type":      "type",
 	"status":    "status",
+	"priority":  "priority",
 	"updatedAt": "updated_at",
 }
 
+var RedirectDraftSortableColumns = map[string]string{
+	"source":     "new_source",
+	"target":     "new_target",
+	"type":       "new_type",
+	"status":     "new_status",
+	"priority":   "new_priority",
+	"changeType": "change_type",
+	"updatedAt":  "updated_at",
+}
+
 type Redirect struct {
 	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement"`
 	NamespaceCode string    `json:"-" gorm:"size:50;index:idx_redirects_namespace_project"`
@@ -31,13 +43,24 @@ type Redirect struct {
 	IsPublished   *bool     `json:"is_published" gorm:"default:false;not null"`
 	PublishedAt   time.Time `json:"publishedAt" gorm:"type:timestamp"`
 	*commonTypes.Redirect
-	RedirectDraft *RedirectDraft `json:"draft" gorm:"foreignKey:OldRedirectID;references:ID"`
-	CreatedAt     time.Time      `json:"createdAt" gorm:"type:timestamp"`
-	UpdatedAt     time.Time      `json:"updatedAt" gorm:"type:timestamp"`
+	RedirectDraft *RedirectDraft  `json:"draft" gorm:"foreignKey:OldRedirectID;references:ID"`
+	Tags          []Tag           `json:"tags,omitempty" gorm:"many2many:redirect_tags;"`
+	Health        *RedirectHealth `json:"health,omitempty" gorm:"foreignKey:RedirectID;constraint:OnDelete:CASCADE;"`
+	CreatedAt     time.Time       `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt     time.Time       `json:"updatedAt" gorm:"type:timestamp"`
 }
 
 type RedirectList = commonTypes.PaginatedResult[Redirect]
 
+// Base returns the redirect as sent to the agents, along with its id
+func (r Redirect) Base() commonTypes.Redirect {
+	base := *r.Redirect
+	base.ID = r.ID
+	return base
+}
+
+type RedirectCursorList = commonTypes.CursorResult[Redirect]
+
 type RedirectDraft struct {
 	ID            int64                 `json:"id" gorm:"primaryKey;autoIncrement"`
 	NamespaceCode string                `json:"-" gorm:"size:50;index:idx_redirect_drafts_namespace_project"`
@@ -47,8 +70,30 @@ type RedirectDraft struct {
 	OldRedirectID *int64                `json:"-" gorm:"index:idx_redirect_drafts_old_redirect_id"`
 	OldRedirect   *Redirect             `json:"oldRedirect" gorm:"foreignKey:OldRedirectID;"`
 	NewRedirect   *commonTypes.Redirect `gorm:"embedded;embeddedPrefix:new_"`
-	CreatedAt     time.Time             `json:"createdAt" gorm:"type:timestamp"`
-	UpdatedAt     time.Time             `json:"updatedAt" gorm:"type:timestamp"`
+	Tags          []Tag                 `json:"tags,omitempty" gorm:"many2many:redirect_draft_tags;"`
+	// Comment is a note on the change, given by the imports
+	Comment string `json:"comment" gorm:"size:500;default:'';not null"`
+	// ChangesetID is the changeset grouping the draft, nil when it belongs to none
+	ChangesetID *int64 `json:"changesetID" gorm:"index:idx_redirect_drafts_changeset_id"`
+	// CreatedBy and UpdatedBy are the subjects who staged the draft and last edited it
+	CreatedBy string    `json:"createdBy" gorm:"size:255;default:'';not null"`
+	UpdatedBy string    `json:"updatedBy" gorm:"size:255;default:'';not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"type:timestamp"`
+}
+
+// BeforeCreate records the subject of the context as the author of the draft, unless already set
+func (d *RedirectDraft) BeforeCreate(tx *gorm.DB) error {
+	recordAuthor(tx, &d.CreatedBy, &d.UpdatedBy)
+	return nil
+}
+
+// BeforeUpdate records the subject of the context as the last editor of the draft
+func (d *RedirectDraft) BeforeUpdate(tx *gorm.DB) error {
+	recordEditor(tx, &d.UpdatedBy)
+	return nil
 }
 
 type RedirectDraftList = commonTypes.PaginatedResult[RedirectDraft]
+
+type RedirectDraftCursorList = commonTypes.CursorResult[RedirectDraft]
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+const MaxRedirectHealthErrorLength = 500
+
+// RedirectHealth is the result of the last check of the target of a published redirect
+type RedirectHealth struct {
+	RedirectID int64     `json:"redirectId" gorm:"primaryKey;autoIncrement:false"`
+	StatusCode int       `json:"statusCode"`
+	LatencyMs  int64     `json:"latencyMs"`
+	Error      string    `json:"error" gorm:"size:500"`
+	CheckedAt  time.Time `json:"checkedAt" gorm:"type:timestamp"`
+}
+
+func (RedirectHealth) TableName() string {
+	return "redirect_health"
+}
+
+// IsBroken returns true when the target could not be reached or answered with an error status
+func (h RedirectHealth) IsBroken() bool {
+	return h.Error != "" || h.StatusCode >= 400
+}
+
+// RedirectHealthBrokenCondition is the SQL condition selecting the redirect_health rows of broken targets
+const RedirectHealthBrokenCondition = "redirect_health.error <> '' OR redirect_health.status_code >= 400"
+
+// RedirectHealthReport summarizes the target checks of a project
+type RedirectHealthReport struct {
+	CheckedCount  int        `json:"checkedCount"`
+	BrokenCount   int        `json:"brokenCount"`
+	LastCheckedAt *time.Time `json:"lastCheckedAt"`
+}
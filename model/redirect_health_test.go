@@ -0,0 +1,19 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectHealth_TableName(t *testing.T) {
+	assert.Equal(t, "redirect_health", RedirectHealth{}.TableName())
+}
+
+func TestRedirectHealth_IsBroken(t *testing.T) {
+	assert.False(t, RedirectHealth{StatusCode: 200}.IsBroken())
+	assert.False(t, RedirectHealth{StatusCode: 301}.IsBroken())
+	assert.True(t, RedirectHealth{StatusCode: 404}.IsBroken())
+	assert.True(t, RedirectHealth{StatusCode: 503}.IsBroken())
+	assert.True(t, RedirectHealth{Error: "connection refused"}.IsBroken())
+}
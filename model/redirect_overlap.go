@@ -0,0 +1,33 @@
+package model
+
+const (
+	DefaultRedirectOverlapLimit = 100
+	MaxRedirectOverlapLimit     = 500
+)
+
+// RedirectOverlap is a source defined by the published redirects of several projects of a namespace, which would
+// conflict if the projects were merged or served by the same agents
+type RedirectOverlap struct {
+	Source string `json:"source"`
+	// Projects are the projects defining the source, ordered by code
+	Projects []Project `json:"projects"`
+	// Redirects are the redirects of the source, ordered by project
+	Redirects []Redirect `json:"redirects"`
+}
+
+// GroupRedirectOverlaps groups the redirects, ordered by source and project, by source
+func GroupRedirectOverlaps(redirects []Redirect) []RedirectOverlap {
+	overlaps := make([]RedirectOverlap, 0)
+	for _, redirect := range redirects {
+		source := redirect.Redirect.Source
+		if len(overlaps) == 0 || overlaps[len(overlaps)-1].Source != source {
+			overlaps = append(overlaps, RedirectOverlap{Source: source})
+		}
+		overlap := &overlaps[len(overlaps)-1]
+		overlap.Redirects = append(overlap.Redirects, redirect)
+		if redirect.Project != nil && (len(overlap.Projects) == 0 || overlap.Projects[len(overlap.Projects)-1].ProjectCode != redirect.ProjectCode) {
+			overlap.Projects = append(overlap.Projects, *redirect.Project)
+		}
+	}
+	return overlaps
+}
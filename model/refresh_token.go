@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// RefreshToken is an issued refresh token, stored by hash. Every rotation of a login
+// session keeps the same FamilyID so a replayed token can revoke the whole session.
+type RefreshToken struct {
+	ID        int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64      `json:"userId" gorm:"index;not null"`
+	FamilyID  string     `json:"familyId" gorm:"index;size:64;not null"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;size:64;not null"`
+	ExpiresAt time.Time  `json:"expiresAt" gorm:"type:timestamp;not null"`
+	RevokedAt *time.Time `json:"revokedAt" gorm:"type:timestamp"`
+	CreatedAt time.Time  `json:"createdAt" gorm:"type:timestamp"`
+
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+}
+
+// IsRevoked returns true if the token has already been rotated or revoked
+func (t *RefreshToken) IsRevoked() bool {
+	return t.RevokedAt != nil
+}
+
+// IsExpired checks if the token has expired
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefreshToken_IsExpired(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      bool
+	}{
+		{
+			name:      "future expiration returns false",
+			expiresAt: time.Now().Add(time.Hour),
+			want:      false,
+		},
+		{
+			name:      "past expiration returns true",
+			expiresAt: time.Now().Add(-time.Hour),
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &RefreshToken{
+				ExpiresAt: tt.expiresAt,
+			}
+			assert.Equal(t, tt.want, token.IsExpired())
+		})
+	}
+}
+
+func TestRefreshToken_IsRevoked(t *testing.T) {
+	revokedAt := time.Now()
+
+	assert.False(t, (&RefreshToken{}).IsRevoked())
+	assert.True(t, (&RefreshToken{RevokedAt: &revokedAt}).IsRevoked())
+}
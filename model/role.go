@@ -51,6 +51,20 @@ func (UserRole) TableName() string {
 	return "user_roles"
 }
 
+// RoleParent links a role to a parent role whose permissions it includes
+type RoleParent struct {
+	RoleID    int64     `json:"roleId" gorm:"primaryKey"`
+	ParentID  int64     `json:"parentId" gorm:"primaryKey;index"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+
+	Role   Role `json:"role" gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE;"`
+	Parent Role `json:"parent" gorm:"foreignKey:ParentID;constraint:OnDelete:CASCADE;"`
+}
+
+func (RoleParent) TableName() string {
+	return "role_parents"
+}
+
 type RoleList = types.PaginatedResult[Role]
 
 type SubjectPermissions struct {
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// RolloutStatus is the progress of the latest version of a project and environment across the registered agents serving it
+type RolloutStatus struct {
+	NamespaceCode string                  `json:"namespaceCode"`
+	ProjectCode   string                  `json:"projectCode"`
+	Environment   commonTypes.Environment `json:"environment"`
+	// Version is the version published to the environment, PublishedAt the time it was published or promoted
+	Version     int        `json:"version"`
+	PublishedAt *time.Time `json:"publishedAt"`
+	// Agents is the number of registered agents serving the environment, Applied the number of them serving the version
+	Agents  int            `json:"agents"`
+	Applied int            `json:"applied"`
+	Lagging []RolloutAgent `json:"lagging"`
+	// TimedOut is true when agents still lag once the rollout timeout elapsed since the version was published,
+	// Warning then describing them
+	TimedOut bool   `json:"timedOut"`
+	Warning  string `json:"warning,omitempty"`
+}
+
+// IsComplete returns true if all the agents serving the environment applied the version
+func (s RolloutStatus) IsComplete() bool {
+	return len(s.Lagging) == 0
+}
+
+// RolloutAgent is a registered agent serving an older version than the one rolled out
+type RolloutAgent struct {
+	Name            string             `json:"name"`
+	State           AgentInstanceState `json:"state"`
+	Version         int                `json:"version"`
+	AppliedAt       time.Time          `json:"appliedAt"`
+	LastHeartbeatAt time.Time          `json:"lastHeartbeatAt"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+type SearchResultType string
+
+const (
+	SearchResultTypeRedirect SearchResultType = "REDIRECT"
+	SearchResultTypePage     SearchResultType = "PAGE"
+)
+
+// SearchScope restricts a search to the projects whose redirects and pages can be read
+type SearchScope struct {
+	RedirectProjectCodes []string
+	PageProjectCodes     []string
+	Limit                int
+}
+
+// SearchResult is a redirect or a page matching a search, ordered by decreasing rank
+type SearchResult struct {
+	Type          SearchResultType `json:"type"`
+	ID            int64            `json:"id"`
+	NamespaceCode string           `json:"namespaceCode"`
+	ProjectCode   string           `json:"projectCode"`
+	// Title is the source of a redirect or the path of a page
+	Title string `json:"title"`
+	// Excerpt is the target of a redirect or an extract of the content of a page
+	Excerpt string  `json:"excerpt"`
+	Rank    float64 `json:"rank"`
+}
+
+// SearchTerms splits a search query into lowercase words, without duplicates.
+// Punctuation such as slashes and dots separates words, so "/blog/old-post" searches "blog", "old" and "post".
+func SearchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	terms := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		if !seen[word] {
+			seen[word] = true
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,13 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	assert.Equal(t, []string{"blog", "old", "post"}, SearchTerms("/Blog/old-post?blog"))
+	assert.Equal(t, []string{"café", "2024"}, SearchTerms("Café 2024"))
+	assert.Empty(t, SearchTerms(" /%_ "))
+}
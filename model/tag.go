@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const MaxTagNameLength = 50
+
+var ValidTagNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_.:/-]+$`)
+
+// Tag is a label shared by the redirects of a project, used to organize them by campaign or migration batch
+type Tag struct {
+	ID            int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string    `json:"-" gorm:"size:50;uniqueIndex:idx_tags_namespace_project_name"`
+	ProjectCode   string    `json:"-" gorm:"size:50;uniqueIndex:idx_tags_namespace_project_name"`
+	Project       *Project  `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;"`
+	Name          string    `json:"name" gorm:"size:50;not null;uniqueIndex:idx_tags_namespace_project_name"`
+	CreatedAt     time.Time `json:"createdAt" gorm:"type:timestamp"`
+}
+
+type RedirectTag struct {
+	RedirectID int64 `json:"redirectId" gorm:"primaryKey"`
+	TagID      int64 `json:"tagId" gorm:"primaryKey"`
+
+	Redirect Redirect `json:"-" gorm:"foreignKey:RedirectID;constraint:OnDelete:CASCADE;"`
+	Tag      Tag      `json:"-" gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE;"`
+}
+
+func (RedirectTag) TableName() string {
+	return "redirect_tags"
+}
+
+type RedirectDraftTag struct {
+	RedirectDraftID int64 `json:"redirectDraftId" gorm:"primaryKey"`
+	TagID           int64 `json:"tagId" gorm:"primaryKey"`
+
+	RedirectDraft RedirectDraft `json:"-" gorm:"foreignKey:RedirectDraftID;constraint:OnDelete:CASCADE;"`
+	Tag           Tag           `json:"-" gorm:"foreignKey:TagID;constraint:OnDelete:CASCADE;"`
+}
+
+func (RedirectDraftTag) TableName() string {
+	return "redirect_draft_tags"
+}
+
+// TagNames returns the names of the given tags
+func TagNames(tags []Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// NormalizeTagNames trims and deduplicates tag names, keeping their order, and validates them
+func NormalizeTagNames(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if len(name) > MaxTagNameLength {
+			return nil, fmt.Errorf("tag %q is too long: maximum length is %d", name, MaxTagNameLength)
+		}
+		if !ValidTagNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid tag %q: only letters, digits and _ . : / - are allowed", name)
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized, nil
+}
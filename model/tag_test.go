@@ -0,0 +1,43 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagTableNames(t *testing.T) {
+	assert.Equal(t, "redirect_tags", RedirectTag{}.TableName())
+	assert.Equal(t, "redirect_draft_tags", RedirectDraftTag{}.TableName())
+}
+
+func TestTagNames(t *testing.T) {
+	assert.Equal(t, []string{}, TagNames(nil))
+	assert.Equal(t, []string{"a", "b"}, TagNames([]Tag{{Name: "a"}, {Name: "b"}}))
+}
+
+func TestNormalizeTagNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []string
+		want    []string
+		wantErr string
+	}{
+		{name: "nil", input: nil, want: []string{}},
+		{name: "trims and deduplicates", input: []string{" summer-2026 ", "", "migration/v2", "summer-2026"}, want: []string{"summer-2026", "migration/v2"}},
+		{name: "invalid characters", input: []string{"black friday"}, wantErr: "invalid tag"},
+		{name: "too long", input: []string{strings.Repeat("a", MaxTagNameLength+1)}, wantErr: "too long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTagNames(tt.input)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// RedirectTombstone is the copy of a published redirect deleted by a publish, kept to restore it
+type RedirectTombstone struct {
+	ID            int64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string   `json:"-" gorm:"size:50;index:idx_redirect_tombstones_namespace_project"`
+	ProjectCode   string   `json:"-" gorm:"size:50;index:idx_redirect_tombstones_namespace_project"`
+	Project       *Project `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;constraint:OnDelete:CASCADE;"`
+	// RedirectID is the id of the deleted redirect, the restored redirect getting a new one
+	RedirectID int64 `json:"redirectID" gorm:"not null;uniqueIndex:idx_redirect_tombstones_redirect_id"`
+	*commonTypes.Redirect
+	// Tags are the names of the tags of the redirect when it was deleted
+	Tags []string `json:"tags" gorm:"type:text;serializer:json"`
+	// DeletedBy is the subject who published the deletion
+	DeletedBy string    `json:"deletedBy" gorm:"size:255;default:'';not null"`
+	DeletedAt time.Time `json:"deletedAt" gorm:"type:timestamp"`
+}
+
+type RedirectTombstoneList = commonTypes.PaginatedResult[RedirectTombstone]
+
+// NewRedirectTombstone returns the tombstone of a redirect deleted by the subject at the given time
+func NewRedirectTombstone(redirect Redirect, deletedBy string, deletedAt time.Time) RedirectTombstone {
+	tags := make([]string, 0, len(redirect.Tags))
+	for _, tag := range redirect.Tags {
+		tags = append(tags, tag.Name)
+	}
+	return RedirectTombstone{
+		NamespaceCode: redirect.NamespaceCode,
+		ProjectCode:   redirect.ProjectCode,
+		RedirectID:    redirect.ID,
+		Redirect:      redirect.Redirect,
+		Tags:          tags,
+		DeletedBy:     deletedBy,
+		DeletedAt:     deletedAt,
+	}
+}
+
+// PageTombstone is the copy of a published page deleted by a publish, kept to restore it
+type PageTombstone struct {
+	ID            int64    `json:"id" gorm:"primaryKey;autoIncrement"`
+	NamespaceCode string   `json:"-" gorm:"size:50;index:idx_page_tombstones_namespace_project"`
+	ProjectCode   string   `json:"-" gorm:"size:50;index:idx_page_tombstones_namespace_project"`
+	Project       *Project `json:"project" gorm:"foreignKey:NamespaceCode,ProjectCode;references:NamespaceCode,ProjectCode;constraint:OnDelete:CASCADE;"`
+	// PageID is the id of the deleted page, the restored page getting a new one
+	PageID      int64 `json:"pageID" gorm:"not null;uniqueIndex:idx_page_tombstones_page_id"`
+	ContentSize int64 `json:"contentSize" gorm:"default:0;not null"`
+	// ContentEncoding and StoredContentSize describe the content as stored, it is always decompressed on read
+	ContentEncoding   PageContentEncoding `json:"-" gorm:"size:10;default:'';not null"`
+	StoredContentSize int64               `json:"-" gorm:"default:0;not null"`
+	// ContentStore is where the content is stored, it is always loaded from there on read
+	ContentStore PageContentStore `json:"-" gorm:"size:10;default:'';not null"`
+	*commonTypes.Page
+	// DeletedBy is the subject who published the deletion
+	DeletedBy string    `json:"deletedBy" gorm:"size:255;default:'';not null"`
+	DeletedAt time.Time `json:"deletedAt" gorm:"type:timestamp"`
+}
+
+type PageTombstoneList = commonTypes.PaginatedResult[PageTombstone]
+
+// NewPageTombstone returns the tombstone of a page deleted by the subject at the given time
+func NewPageTombstone(page Page, deletedBy string, deletedAt time.Time) PageTombstone {
+	return PageTombstone{
+		NamespaceCode: page.NamespaceCode,
+		ProjectCode:   page.ProjectCode,
+		PageID:        page.ID,
+		ContentSize:   page.ContentSize,
+		Page:          page.Page,
+		DeletedBy:     deletedBy,
+		DeletedAt:     deletedAt,
+	}
+}
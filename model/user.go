@@ -16,15 +16,17 @@ var UserSortableColumns = map[string]string{
 }
 
 type User struct {
-	ID               int64     `json:"id" gorm:"primaryKey;autoIncrement"`
-	Username         string    `json:"username" gorm:"unique;size:100;not null" validate:"required,username"`
-	Password         string    `json:"-" gorm:"size:255"`
-	Lastname         string    `json:"lastname"  validate:"required"`
-	Firstname        string    `json:"firstname"  validate:"required"`
-	Active           *bool     `json:"active" gorm:"default:true;not null"`
-	RefreshTokenHash string    `json:"-" gorm:"size:255"`
-	CreatedAt        time.Time `json:"createdAt" gorm:"type:timestamp"`
-	UpdatedAt        time.Time `json:"updatedAt" gorm:"type:timestamp"`
+	ID                 int64      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Username           string     `json:"username" gorm:"unique;size:100;not null" validate:"required,username"`
+	Password           string     `json:"-" gorm:"size:255"`
+	Lastname           string     `json:"lastname"  validate:"required"`
+	Firstname          string     `json:"firstname"  validate:"required"`
+	Active             *bool      `json:"active" gorm:"default:true;not null"`
+	MustChangePassword bool       `json:"mustChangePassword" gorm:"default:false;not null"`
+	PasswordChangedAt  *time.Time `json:"passwordChangedAt" gorm:"type:timestamp"`
+	Language           string     `json:"language" gorm:"size:10;not null;default:''"`
+	CreatedAt          time.Time  `json:"createdAt" gorm:"type:timestamp"`
+	UpdatedAt          time.Time  `json:"updatedAt" gorm:"type:timestamp"`
 }
 
 func (u *User) IsActive() bool {
@@ -36,4 +38,35 @@ func (u *User) HasPassword() bool {
 	return u.Password != ""
 }
 
+// IsPasswordExpired returns true if the password is older than maxAge, 0 disables the expiry
+func (u *User) IsPasswordExpired(maxAge time.Duration) bool {
+	if maxAge <= 0 || !u.HasPassword() {
+		return false
+	}
+	changedAt := u.CreatedAt
+	if u.PasswordChangedAt != nil {
+		changedAt = *u.PasswordChangedAt
+	}
+	return time.Since(changedAt) > maxAge
+}
+
+// NeedsPasswordChange returns true if the user must set a new password at next login
+func (u *User) NeedsPasswordChange(maxAge time.Duration) bool {
+	return u.HasPassword() && (u.MustChangePassword || u.IsPasswordExpired(maxAge))
+}
+
 type UserList = types.PaginatedResult[User]
+
+// UserPasswordHistory is a previous password hash of a user, kept to prevent its reuse
+type UserPasswordHistory struct {
+	ID        int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int64     `json:"userId" gorm:"index;not null"`
+	Password  string    `json:"-" gorm:"size:255;not null"`
+	CreatedAt time.Time `json:"createdAt" gorm:"type:timestamp"`
+
+	User User `json:"-" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE;"`
+}
+
+func (UserPasswordHistory) TableName() string {
+	return "user_password_history"
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"testing"
+	"time"
 
 	"github.com/flectolab/flecto-manager/types"
 	"github.com/stretchr/testify/assert"
@@ -67,3 +68,57 @@ func TestUser_HasPassword(t *testing.T) {
 		})
 	}
 }
+
+func TestUser_NeedsPasswordChange(t *testing.T) {
+	recent := time.Now().Add(-time.Hour)
+	old := time.Now().Add(-48 * time.Hour)
+	tests := []struct {
+		name   string
+		user   User
+		maxAge time.Duration
+		want   bool
+	}{
+		{
+			name:   "flag set",
+			user:   User{Password: "hashed_password", MustChangePassword: true, PasswordChangedAt: &recent},
+			maxAge: 0,
+			want:   true,
+		},
+		{
+			name:   "flag set without password",
+			user:   User{MustChangePassword: true},
+			maxAge: 0,
+			want:   false,
+		},
+		{
+			name:   "max age disabled",
+			user:   User{Password: "hashed_password", PasswordChangedAt: &old},
+			maxAge: 0,
+			want:   false,
+		},
+		{
+			name:   "password expired",
+			user:   User{Password: "hashed_password", PasswordChangedAt: &old},
+			maxAge: 24 * time.Hour,
+			want:   true,
+		},
+		{
+			name:   "password not expired",
+			user:   User{Password: "hashed_password", PasswordChangedAt: &recent},
+			maxAge: 24 * time.Hour,
+			want:   false,
+		},
+		{
+			name:   "never changed falls back on creation date",
+			user:   User{Password: "hashed_password", CreatedAt: old},
+			maxAge: 24 * time.Hour,
+			want:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.user.NeedsPasswordChange(tt.maxAge))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package notification
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrInvalidTarget = errors.New("invalid notification target")
+
+// Message is a notification rendered for the channels
+type Message struct {
+	Subject string
+	Body    string
+}
+
+// Sender delivers the messages of a channel to its targets
+type Sender interface {
+	// Validate checks that the target is an address the sender can deliver to
+	Validate(target string) error
+	Send(ctx context.Context, target string, message Message) error
+}
@@ -0,0 +1,62 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+)
+
+type slackSender struct {
+	allowedHosts []string
+	client       *http.Client
+}
+
+// NewSlackSender returns a sender posting the messages to Slack incoming webhooks, the targets being
+// HTTPS webhook URLs on one of the allowed hosts
+func NewSlackSender(allowedHosts []string, client *http.Client) Sender {
+	return &slackSender{allowedHosts: allowedHosts, client: client}
+}
+
+func (s *slackSender) Validate(target string) error {
+	webhookURL, err := url.Parse(target)
+	if err != nil || webhookURL.Scheme != "https" || !slices.Contains(s.allowedHosts, webhookURL.Hostname()) {
+		// The webhook URL is a secret, it is kept out of the errors
+		return fmt.Errorf("%w: not a Slack webhook URL on an allowed host", ErrInvalidTarget)
+	}
+	return nil
+}
+
+func (s *slackSender) Send(ctx context.Context, target string, message Message) error {
+	if err := s.Validate(target); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(map[string]string{"text": "*" + message.Subject + "*\n" + message.Body})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
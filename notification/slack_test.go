@@ -0,0 +1,50 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackSender_Validate(t *testing.T) {
+	sender := NewSlackSender([]string{"hooks.slack.com"}, http.DefaultClient)
+
+	assert.NoError(t, sender.Validate("https://hooks.slack.com/services/T0/B0/secret"))
+	assert.ErrorIs(t, sender.Validate("http://hooks.slack.com/services/T0/B0/secret"), ErrInvalidTarget)
+	assert.ErrorIs(t, sender.Validate("https://internal.example.com/services"), ErrInvalidTarget)
+	assert.ErrorIs(t, sender.Validate("not a url"), ErrInvalidTarget)
+
+	err := sender.Validate("https://other.example.com/secret")
+	assert.NotContains(t, err.Error(), "secret")
+}
+
+func TestSlackSender_Send(t *testing.T) {
+	var payload map[string]string
+	status := http.StatusOK
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sender := NewSlackSender([]string{"127.0.0.1"}, server.Client())
+	ctx := context.Background()
+
+	err := sender.Send(ctx, server.URL+"/services/T0/B0", Message{Subject: "Published", Body: "Version 2"})
+	require.NoError(t, err)
+	assert.Equal(t, "*Published*\nVersion 2", payload["text"])
+
+	status = http.StatusNotFound
+	err = sender.Send(ctx, server.URL+"/services/T0/B0", Message{Subject: "Published"})
+	assert.EqualError(t, err, "slack webhook returned status 404")
+
+	err = sender.Send(ctx, "https://hooks.slack.com/services/T0/B0", Message{Subject: "Published"})
+	assert.ErrorIs(t, err, ErrInvalidTarget)
+}
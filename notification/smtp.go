@@ -0,0 +1,97 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flectolab/flecto-manager/config"
+)
+
+type smtpSender struct {
+	cfg config.SMTPConfig
+}
+
+// NewSMTPSender returns a sender emailing the messages through the SMTP server,
+// the targets being email addresses
+func NewSMTPSender(cfg config.SMTPConfig) Sender {
+	return &smtpSender{cfg: cfg}
+}
+
+func (s *smtpSender) Validate(target string) error {
+	address, err := mail.ParseAddress(target)
+	if err != nil || address.Address != target {
+		return fmt.Errorf("%w: %s is not an email address", ErrInvalidTarget, target)
+	}
+	return nil
+}
+
+func (s *smtpSender) Send(ctx context.Context, target string, message Message) error {
+	if err := s.Validate(target); err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if s.cfg.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(s.cfg.From); err != nil {
+		return err
+	}
+	if err = client.Rcpt(target); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(s.buildEmail(target, message)); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildEmail returns the plain text email of the message
+func (s *smtpSender) buildEmail(target string, message Message) []byte {
+	var email strings.Builder
+	email.WriteString("From: " + s.cfg.From + "\r\n")
+	email.WriteString("To: " + target + "\r\n")
+	email.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	email.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	email.WriteString("MIME-Version: 1.0\r\n")
+	email.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	email.WriteString("\r\n")
+	email.WriteString(strings.ReplaceAll(message.Body, "\n", "\r\n"))
+	email.WriteString("\r\n")
+	return []byte(email.String())
+}
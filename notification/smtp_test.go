@@ -0,0 +1,106 @@
+package notification
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts a single session, recording the commands and the email received
+type fakeSMTPServer struct {
+	listener net.Listener
+	commands []string
+	data     string
+	done     chan struct{}
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeSMTPServer{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	text := textproto.NewConn(conn)
+	defer func() { _ = text.Close() }()
+
+	_ = text.PrintfLine("220 localhost ready")
+	for {
+		line, errRead := text.ReadLine()
+		if errRead != nil {
+			return
+		}
+		s.commands = append(s.commands, line)
+		switch verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			_ = text.PrintfLine("250 localhost")
+		case "DATA":
+			_ = text.PrintfLine("354 go ahead")
+			data, _ := text.ReadDotBytes()
+			s.data = string(data)
+			_ = text.PrintfLine("250 queued")
+		case "QUIT":
+			_ = text.PrintfLine("221 bye")
+			return
+		default:
+			_ = text.PrintfLine("250 ok")
+		}
+	}
+}
+
+func TestSMTPSender_Validate(t *testing.T) {
+	sender := NewSMTPSender(config.SMTPConfig{})
+
+	assert.NoError(t, sender.Validate("alice@example.com"))
+	assert.ErrorIs(t, sender.Validate("Alice <alice@example.com>"), ErrInvalidTarget)
+	assert.ErrorIs(t, sender.Validate("alice@example.com\r\nBcc: bob@example.com"), ErrInvalidTarget)
+	assert.ErrorIs(t, sender.Validate("alice"), ErrInvalidTarget)
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	sender := NewSMTPSender(config.SMTPConfig{Host: "127.0.0.1", Port: server.port(), From: "flecto@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := sender.Send(ctx, "alice@example.com", Message{Subject: "Project published", Body: "Version 2\nby bob"})
+	require.NoError(t, err)
+	<-server.done
+
+	assert.Contains(t, server.commands, "MAIL FROM:<flecto@example.com>")
+	assert.Contains(t, server.commands, "RCPT TO:<alice@example.com>")
+	assert.Contains(t, server.data, "To: alice@example.com\n")
+	assert.Contains(t, server.data, "Subject: Project published\n")
+	assert.True(t, strings.HasSuffix(server.data, "\nVersion 2\nby bob\n"), server.data)
+}
+
+func TestSMTPSender_SendError(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	require.NoError(t, listener.Close())
+
+	sender := NewSMTPSender(config.SMTPConfig{Host: "127.0.0.1", Port: port, From: "flecto@example.com"})
+	err = sender.Send(context.Background(), "alice@example.com", Message{Subject: "Project published"})
+	assert.ErrorContains(t, err, strconv.Itoa(port))
+}
@@ -0,0 +1,142 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/flectolab/flecto-manager/config"
+)
+
+const (
+	// HeaderSignature holds the HMAC SHA-256 of the body signed with the secret of the hook, as sha256=<hex>
+	HeaderSignature = "X-Flecto-Signature"
+
+	defaultHookTimeout = 5 * time.Second
+	maxResponseSize    = 64 * 1024
+)
+
+// Verdict is the answer of a validation hook
+type Verdict struct {
+	Allow bool `json:"allow"`
+	// Reason explains a veto, shown to the user publishing
+	Reason string `json:"reason,omitempty"`
+}
+
+// Result is the outcome of the call to a hook, Err being set when the hook could not give a verdict
+type Result struct {
+	Hook     string
+	FailOpen bool
+	Verdict  Verdict
+	Err      error
+}
+
+// Vetoes returns true if the result blocks the publish: the hook denied it, or failed while being fail-closed
+func (r Result) Vetoes() bool {
+	if r.Err != nil {
+		return !r.FailOpen
+	}
+	return !r.Verdict.Allow
+}
+
+// Check posts the plan to the hooks applying to its namespace, in parallel, and returns their results in the order
+// of the hooks
+func Check(ctx context.Context, client *http.Client, hooks []config.PublishHookConfig, plan Plan) []Result {
+	var applying []config.PublishHookConfig
+	for _, hook := range hooks {
+		if len(hook.Namespaces) == 0 || slices.Contains(hook.Namespaces, plan.NamespaceCode) {
+			applying = append(applying, hook)
+		}
+	}
+
+	results := make([]Result, len(applying))
+	var wg sync.WaitGroup
+	for i, hook := range applying {
+		results[i] = Result{Hook: hook.Name, FailOpen: hook.FailOpen}
+		wg.Add(1)
+		go func(result *Result, hook config.PublishHookConfig) {
+			defer wg.Done()
+			result.Verdict, result.Err = call(ctx, client, hook, plan)
+		}(&results[i], hook)
+	}
+	wg.Wait()
+	return results
+}
+
+// Veto returns the error explaining why the results block the publish, nil when they allow it
+func Veto(results []Result) error {
+	var reasons []string
+	for _, result := range results {
+		if !result.Vetoes() {
+			continue
+		}
+		switch {
+		case result.Err != nil:
+			reasons = append(reasons, fmt.Sprintf("%s failed: %s", result.Hook, result.Err))
+		case result.Verdict.Reason != "":
+			reasons = append(reasons, fmt.Sprintf("%s: %s", result.Hook, result.Verdict.Reason))
+		default:
+			reasons = append(reasons, result.Hook)
+		}
+	}
+	if len(reasons) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(reasons, "; "))
+}
+
+// call posts the plan to the hook and returns its verdict, a hook answering anything else than a 2xx status with
+// a verdict failing
+func call(ctx context.Context, client *http.Client, hook config.PublishHookConfig, plan Plan) (Verdict, error) {
+	if !hook.IncludePageContent {
+		plan = plan.withoutPageContent()
+	}
+	body, err := json.Marshal(plan)
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if hook.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(hook.Secret))
+		mac.Write(body)
+		req.Header.Set(HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return Verdict{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Verdict{}, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var verdict Verdict
+	if err = json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&verdict); err != nil {
+		return Verdict{}, fmt.Errorf("invalid verdict: %w", err)
+	}
+	return verdict, nil
+}
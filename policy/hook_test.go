@@ -0,0 +1,112 @@
+package policy
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testPlan() Plan {
+	return Plan{
+		NamespaceCode: "ns1",
+		ProjectCode:   "proj1",
+		Version:       3,
+		PublishedBy:   "alice",
+		Redirects: []RedirectChange{
+			{ChangeType: "CREATE", New: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}},
+		},
+		Pages: []PageChange{
+			{ChangeType: "UPDATE",
+				Old: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "User-agent: *", ContentType: commonTypes.PageContentTypeTextPlain},
+				New: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "Disallow: /", ContentType: commonTypes.PageContentTypeTextPlain}},
+		},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var received Plan
+	var signature string
+	allow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(HeaderSignature)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), signature)
+		_ = json.Unmarshal(body, &received)
+		_, _ = w.Write([]byte(`{"allow": true}`))
+	}))
+	defer allow.Close()
+	deny := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var plan Plan
+		_ = json.NewDecoder(r.Body).Decode(&plan)
+		assert.Equal(t, "Disallow: /", plan.Pages[0].New.Content, "content asked by the hook")
+		_, _ = w.Write([]byte(`{"allow": false, "reason": "robots.txt is frozen"}`))
+	}))
+	defer deny.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+	}))
+	defer slow.Close()
+	invalid := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`allow`))
+	}))
+	defer invalid.Close()
+
+	results := Check(context.Background(), http.DefaultClient, []config.PublishHookConfig{
+		{Name: "allow", URL: allow.URL, Secret: "secret"},
+		{Name: "deny", URL: deny.URL, IncludePageContent: true},
+		{Name: "slow", URL: slow.URL, Timeout: 10 * time.Millisecond, FailOpen: true},
+		{Name: "invalid", URL: invalid.URL},
+		{Name: "other-namespace", URL: deny.URL, Namespaces: []string{"ns2"}},
+	}, testPlan())
+
+	require.Len(t, results, 4, "hooks of other namespaces are left out")
+	assert.Equal(t, "allow", results[0].Hook)
+	assert.NoError(t, results[0].Err)
+	assert.True(t, results[0].Verdict.Allow)
+	assert.False(t, results[0].Vetoes())
+	assert.Equal(t, "proj1", received.ProjectCode)
+	assert.Equal(t, "/old", received.Redirects[0].New.Source)
+	assert.Equal(t, "", received.Pages[0].Old.Content, "content left out by default")
+	assert.Equal(t, "/robots.txt", received.Pages[0].New.Path)
+
+	assert.Equal(t, Verdict{Allow: false, Reason: "robots.txt is frozen"}, results[1].Verdict)
+	assert.True(t, results[1].Vetoes())
+	assert.ErrorIs(t, results[2].Err, context.DeadlineExceeded)
+	assert.False(t, results[2].Vetoes(), "fail open")
+	assert.ErrorContains(t, results[3].Err, "invalid verdict")
+	assert.True(t, results[3].Vetoes(), "fail closed")
+
+	assert.EqualError(t, Veto(results), "deny: robots.txt is frozen; invalid failed: invalid verdict: invalid character 'a' looking for beginning of value")
+	assert.NoError(t, Veto(results[:1]))
+	assert.Empty(t, Check(context.Background(), http.DefaultClient, nil, testPlan()))
+}
+
+func TestCheck_Status(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "", r.Header.Get(HeaderSignature))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	results := Check(context.Background(), http.DefaultClient, []config.PublishHookConfig{{Name: "down", URL: server.URL}}, testPlan())
+
+	require.Len(t, results, 1)
+	assert.EqualError(t, results[0].Err, "status 503")
+	assert.EqualError(t, Veto(results), "down failed: status 503")
+}
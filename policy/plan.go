@@ -0,0 +1,50 @@
+package policy
+
+import (
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+)
+
+// Plan is the content of a publish, sent to the validation hooks before it is applied
+type Plan struct {
+	NamespaceCode string `json:"namespaceCode"`
+	ProjectCode   string `json:"projectCode"`
+	// Version is the version of the project the publish creates
+	Version     int              `json:"version"`
+	PublishedBy string           `json:"publishedBy"`
+	Redirects   []RedirectChange `json:"redirects"`
+	Pages       []PageChange     `json:"pages"`
+}
+
+// RedirectChange is a redirect draft of the publish, Old being nil for a creation and New for a deletion
+type RedirectChange struct {
+	ChangeType string                `json:"changeType"`
+	Old        *commonTypes.Redirect `json:"old,omitempty"`
+	New        *commonTypes.Redirect `json:"new,omitempty"`
+}
+
+// PageChange is a page draft of the publish, Old being nil for a creation and New for a deletion. The content of
+// the pages is only sent to the hooks asking for it.
+type PageChange struct {
+	ChangeType string            `json:"changeType"`
+	Old        *commonTypes.Page `json:"old,omitempty"`
+	New        *commonTypes.Page `json:"new,omitempty"`
+}
+
+// withoutPageContent returns a copy of the plan whose pages have no content
+func (p Plan) withoutPageContent() Plan {
+	pages := make([]PageChange, 0, len(p.Pages))
+	for _, change := range p.Pages {
+		pages = append(pages, PageChange{ChangeType: change.ChangeType, Old: pageWithoutContent(change.Old), New: pageWithoutContent(change.New)})
+	}
+	p.Pages = pages
+	return p
+}
+
+func pageWithoutContent(page *commonTypes.Page) *commonTypes.Page {
+	if page == nil {
+		return nil
+	}
+	withoutContent := *page
+	withoutContent.Content = ""
+	return &withoutContent
+}
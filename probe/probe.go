@@ -0,0 +1,109 @@
+package probe
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/flectolab/flecto-manager/types"
+)
+
+// Registry keeps the heartbeats of the background workers, so that the liveness check can tell when
+// one of them is stuck, and the queues they consume. A nil registry ignores the heartbeats and the queues.
+type Registry struct {
+	mu         sync.Mutex
+	heartbeats map[string]*Heartbeat
+	queues     map[string]func() (length, capacity int)
+	now        func() time.Time
+}
+
+func NewRegistry() *Registry {
+	return &Registry{heartbeats: make(map[string]*Heartbeat), queues: make(map[string]func() (int, int)), now: time.Now}
+}
+
+// Register returns the heartbeat of the worker, which is stalled when it does not beat for timeout.
+// Registering a name again replaces its heartbeat.
+func (r *Registry) Register(name string, timeout time.Duration) *Heartbeat {
+	if r == nil {
+		return nil
+	}
+	h := &Heartbeat{timeout: timeout, now: r.now}
+	h.Beat()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.heartbeats[name] = h
+	return h
+}
+
+// Unregister removes the heartbeat of a worker which stopped on purpose
+func (r *Registry) Unregister(name string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.heartbeats, name)
+}
+
+// Workers returns the status of the registered workers sorted by name
+func (r *Registry) Workers() []types.WorkerStatus {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	workers := make([]types.WorkerStatus, 0, len(r.heartbeats))
+	for name, h := range r.heartbeats {
+		last := h.last()
+		workers = append(workers, types.WorkerStatus{Name: name, LastBeat: last, Stalled: now.Sub(last) > h.timeout})
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].Name < workers[j].Name })
+	return workers
+}
+
+// RegisterQueue adds a queue whose length and capacity are returned by size, usually a buffered channel.
+// Registering a name again replaces its queue.
+func (r *Registry) RegisterQueue(name string, size func() (length, capacity int)) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queues[name] = size
+}
+
+// Queues returns the size of the registered queues sorted by name
+func (r *Registry) Queues() []types.QueueStatus {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queues := make([]types.QueueStatus, 0, len(r.queues))
+	for name, size := range r.queues {
+		length, capacity := size()
+		queues = append(queues, types.QueueStatus{Name: name, Length: length, Capacity: capacity})
+	}
+	sort.Slice(queues, func(i, j int) bool { return queues[i].Name < queues[j].Name })
+	return queues
+}
+
+// Heartbeat is beaten by a worker each time it makes progress. A nil heartbeat ignores the beats.
+type Heartbeat struct {
+	timeout  time.Duration
+	now      func() time.Time
+	lastBeat atomic.Int64
+}
+
+func (h *Heartbeat) Beat() {
+	if h == nil {
+		return
+	}
+	h.lastBeat.Store(h.now().UnixNano())
+}
+
+func (h *Heartbeat) last() time.Time {
+	return time.Unix(0, h.lastBeat.Load())
+}
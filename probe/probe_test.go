@@ -0,0 +1,59 @@
+package probe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.Local)
+	registry := NewRegistry()
+	registry.now = func() time.Time { return now }
+
+	expiry := registry.Register("expiry", time.Minute)
+	health := registry.Register("health", time.Hour)
+	assert.Equal(t, []types.WorkerStatus{
+		{Name: "expiry", LastBeat: now},
+		{Name: "health", LastBeat: now},
+	}, registry.Workers())
+
+	now = now.Add(2 * time.Minute)
+	health.Beat()
+	workers := registry.Workers()
+	assert.True(t, workers[0].Stalled)
+	assert.False(t, workers[1].Stalled)
+	assert.Equal(t, now, workers[1].LastBeat)
+
+	expiry.Beat()
+	assert.False(t, registry.Workers()[0].Stalled)
+
+	registry.Unregister("expiry")
+	assert.Equal(t, []types.WorkerStatus{{Name: "health", LastBeat: now}}, registry.Workers())
+}
+
+func TestRegistry_Queues(t *testing.T) {
+	registry := NewRegistry()
+	notifications := make(chan int, 3)
+	notifications <- 1
+	registry.RegisterQueue("notification", func() (int, int) { return len(notifications), cap(notifications) })
+	registry.RegisterQueue("import", func() (int, int) { return 0, 10 })
+
+	assert.Equal(t, []types.QueueStatus{
+		{Name: "import", Length: 0, Capacity: 10},
+		{Name: "notification", Length: 1, Capacity: 3},
+	}, registry.Queues())
+}
+
+func TestRegistry_Nil(t *testing.T) {
+	var registry *Registry
+	heartbeat := registry.Register("expiry", time.Minute)
+	assert.Nil(t, heartbeat)
+	assert.NotPanics(t, heartbeat.Beat)
+	assert.Nil(t, registry.Workers())
+	registry.Unregister("expiry")
+	registry.RegisterQueue("notification", func() (int, int) { return 0, 1 })
+	assert.Nil(t, registry.Queues())
+}
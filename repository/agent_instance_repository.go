@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type AgentInstanceRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	FindByName(ctx context.Context, name string) (*model.AgentInstance, error)
+	FindAll(ctx context.Context) ([]model.AgentInstance, error)
+	Create(ctx context.Context, agent *model.AgentInstance) error
+	Update(ctx context.Context, agent *model.AgentInstance) error
+	ReplaceProjects(ctx context.Context, agentInstanceID int64, projects []model.AgentInstanceProject, heartbeatAt time.Time) error
+	SaveProject(ctx context.Context, project *model.AgentInstanceProject, heartbeatAt time.Time) error
+	Delete(ctx context.Context, name string) error
+}
+
+type agentInstanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAgentInstanceRepository(db *gorm.DB) AgentInstanceRepository {
+	return &agentInstanceRepository{db: db}
+}
+
+func (r *agentInstanceRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *agentInstanceRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.AgentInstance{})
+}
+
+func (r *agentInstanceRepository) FindByName(ctx context.Context, name string) (*model.AgentInstance, error) {
+	var agent model.AgentInstance
+	err := r.db.WithContext(ctx).
+		Preload("Projects", func(db *gorm.DB) *gorm.DB { return db.Order("namespace_code, project_code, environment") }).
+		Where("name = ?", name).
+		First(&agent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// FindAll returns the registered agents by name, with the projects of their last heartbeat
+func (r *agentInstanceRepository) FindAll(ctx context.Context) ([]model.AgentInstance, error) {
+	var agents []model.AgentInstance
+	err := r.db.WithContext(ctx).
+		Preload("Projects", func(db *gorm.DB) *gorm.DB { return db.Order("namespace_code, project_code, environment") }).
+		Order("name").
+		Find(&agents).Error
+	return agents, err
+}
+
+func (r *agentInstanceRepository) Create(ctx context.Context, agent *model.AgentInstance) error {
+	return r.db.WithContext(ctx).Omit("Projects").Create(agent).Error
+}
+
+// Update saves the registration fields of an agent, its projects being replaced by ReplaceProjects
+func (r *agentInstanceRepository) Update(ctx context.Context, agent *model.AgentInstance) error {
+	return r.db.WithContext(ctx).
+		Model(&model.AgentInstance{ID: agent.ID}).
+		Select("type", "hostname", "agent_version", "last_heartbeat_at").
+		Updates(agent).Error
+}
+
+// ReplaceProjects replaces the projects of an agent by those of its last heartbeat
+func (r *agentInstanceRepository) ReplaceProjects(ctx context.Context, agentInstanceID int64, projects []model.AgentInstanceProject, heartbeatAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("agent_instance_id = ?", agentInstanceID).Delete(&model.AgentInstanceProject{}).Error; err != nil {
+			return err
+		}
+		for i := range projects {
+			projects[i].ID = 0
+			projects[i].AgentInstanceID = agentInstanceID
+		}
+		if len(projects) > 0 {
+			if err := tx.Create(&projects).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&model.AgentInstance{}).
+			Where("id = ?", agentInstanceID).
+			UpdateColumn("last_heartbeat_at", heartbeatAt).Error
+	})
+}
+
+// SaveProject creates or updates a single project of an agent, its other projects being left as they are
+func (r *agentInstanceRepository) SaveProject(ctx context.Context, project *model.AgentInstanceProject, heartbeatAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(project).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.AgentInstance{}).
+			Where("id = ?", project.AgentInstanceID).
+			UpdateColumn("last_heartbeat_at", heartbeatAt).Error
+	})
+}
+
+func (r *agentInstanceRepository) Delete(ctx context.Context, name string) error {
+	agent, err := r.FindByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("agent_instance_id = ?", agent.ID).Delete(&model.AgentInstanceProject{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&model.AgentInstance{}, agent.ID).Error
+	})
+}
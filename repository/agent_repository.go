@@ -7,6 +7,7 @@ import (
 	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -17,7 +18,7 @@ type AgentRepository interface {
 	Upsert(ctx context.Context, agent *model.Agent) error
 	FindByName(ctx context.Context, namespaceCode, projectCode, name string) (*model.Agent, error)
 	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.Agent, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Agent, int64, error)
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Agent, int64, error)
 	CountByProjectAndStatus(ctx context.Context, namespaceCode, projectCode string, status commonTypes.AgentStatus, lastHitAfter time.Time) (int64, error)
 	UpdateLastHit(ctx context.Context, namespaceCode, projectCode, name string) error
 	Delete(ctx context.Context, namespaceCode, projectCode, name string) error
@@ -96,7 +97,7 @@ func (r *agentRepository) FindByProject(ctx context.Context, namespaceCode, proj
 	return agents, nil
 }
 
-func (r *agentRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Agent, int64, error) {
+func (r *agentRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Agent, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.Agent{})
@@ -106,6 +107,11 @@ func (r *agentRepository) SearchPaginate(ctx context.Context, query *gorm.DB, li
 		return nil, 0, err
 	}
 
+	query, err := database.ApplyOrderBy(query, model.AgentSortableColumns, orderBy, "agents")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit != 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
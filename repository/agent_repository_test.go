@@ -507,7 +507,7 @@ func TestAgentRepository_SearchPaginate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, total, err := repo.SearchPaginate(ctx, tt.query, tt.limit, tt.offset)
+			results, total, err := repo.SearchPaginate(ctx, tt.query, tt.limit, tt.offset, nil)
 
 			assert.NoError(t, err)
 			assert.Len(t, results, tt.wantCount)
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type ChangesetRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.Changeset, error)
+	FindByID(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.Changeset, error)
+	FindByName(ctx context.Context, namespaceCode, projectCode, name string) (*model.Changeset, error)
+	Create(ctx context.Context, changeset *model.Changeset) error
+	Update(ctx context.Context, changeset *model.Changeset) error
+	// Delete deletes the changeset, discarding its drafts when discardDrafts is set and leaving them out of any
+	// changeset otherwise
+	Delete(ctx context.Context, id int64, discardDrafts bool) error
+	// AssignDrafts moves the drafts of the project to the changeset, out of any changeset when changesetID is nil, and
+	// returns the number of drafts moved
+	AssignDrafts(ctx context.Context, namespaceCode, projectCode string, changesetID *int64, redirectDraftIDs, pageDraftIDs []int64) (int64, error)
+}
+
+type changesetRepository struct {
+	db *gorm.DB
+}
+
+func NewChangesetRepository(db *gorm.DB) ChangesetRepository {
+	return &changesetRepository{db: db}
+}
+
+func (r *changesetRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *changesetRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.Changeset{})
+}
+
+// FindByProject returns the changesets of a project in the order of their names, with their draft counts
+func (r *changesetRepository) FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.Changeset, error) {
+	var changesets []model.Changeset
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Order("name").
+		Find(&changesets).Error
+	if err != nil {
+		return nil, err
+	}
+	if err = r.countDrafts(ctx, changesets); err != nil {
+		return nil, err
+	}
+	return changesets, nil
+}
+
+func (r *changesetRepository) FindByID(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.Changeset, error) {
+	return r.findOne(ctx, fmt.Sprintf("%s = ? AND %s = ? AND id = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, id)
+}
+
+func (r *changesetRepository) FindByName(ctx context.Context, namespaceCode, projectCode, name string) (*model.Changeset, error) {
+	return r.findOne(ctx, fmt.Sprintf("%s = ? AND %s = ? AND name = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, name)
+}
+
+func (r *changesetRepository) findOne(ctx context.Context, query string, args ...any) (*model.Changeset, error) {
+	var changeset model.Changeset
+	if err := r.db.WithContext(ctx).Where(query, args...).First(&changeset).Error; err != nil {
+		return nil, err
+	}
+	changesets := []model.Changeset{changeset}
+	if err := r.countDrafts(ctx, changesets); err != nil {
+		return nil, err
+	}
+	return &changesets[0], nil
+}
+
+// countDrafts sets the number of redirect and page drafts of the changesets
+func (r *changesetRepository) countDrafts(ctx context.Context, changesets []model.Changeset) error {
+	if len(changesets) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(changesets))
+	for _, changeset := range changesets {
+		ids = append(ids, changeset.ID)
+	}
+
+	type draftCount struct {
+		ChangesetID int64
+		Count       int64
+	}
+	var redirectCounts, pageCounts []draftCount
+	if err := r.db.WithContext(ctx).Model(&model.RedirectDraft{}).
+		Select("changeset_id, COUNT(*) AS count").
+		Where("changeset_id IN ?", ids).
+		Group("changeset_id").
+		Scan(&redirectCounts).Error; err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Model(&model.PageDraft{}).
+		Select("changeset_id, COUNT(*) AS count").
+		Where("changeset_id IN ?", ids).
+		Group("changeset_id").
+		Scan(&pageCounts).Error; err != nil {
+		return err
+	}
+
+	redirects := make(map[int64]int64, len(redirectCounts))
+	for _, count := range redirectCounts {
+		redirects[count.ChangesetID] = count.Count
+	}
+	pages := make(map[int64]int64, len(pageCounts))
+	for _, count := range pageCounts {
+		pages[count.ChangesetID] = count.Count
+	}
+	for i := range changesets {
+		changesets[i].RedirectDraftCount = redirects[changesets[i].ID]
+		changesets[i].PageDraftCount = pages[changesets[i].ID]
+	}
+	return nil
+}
+
+func (r *changesetRepository) Create(ctx context.Context, changeset *model.Changeset) error {
+	return r.db.WithContext(ctx).Omit("Project").Create(changeset).Error
+}
+
+func (r *changesetRepository) Update(ctx context.Context, changeset *model.Changeset) error {
+	return r.db.WithContext(ctx).
+		Model(&model.Changeset{}).
+		Where("id = ?", changeset.ID).
+		Updates(map[string]any{"name": changeset.Name, "description": changeset.Description}).Error
+}
+
+func (r *changesetRepository) Delete(ctx context.Context, id int64, discardDrafts bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if discardDrafts {
+			// The create drafts stage an unpublished redirect or page, discarded with them
+			if err := tx.Where("is_published = ? AND id IN (?)", false,
+				tx.Model(&model.RedirectDraft{}).Select("old_redirect_id").Where("changeset_id = ? AND change_type = ?", id, model.DraftChangeTypeCreate),
+			).Delete(&model.Redirect{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("is_published = ? AND id IN (?)", false,
+				tx.Model(&model.PageDraft{}).Select("old_page_id").Where("changeset_id = ? AND change_type = ?", id, model.DraftChangeTypeCreate),
+			).Delete(&model.Page{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("changeset_id = ?", id).Delete(&model.RedirectDraft{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("changeset_id = ?", id).Delete(&model.PageDraft{}).Error; err != nil {
+				return err
+			}
+		} else {
+			if err := tx.Model(&model.RedirectDraft{}).Where("changeset_id = ?", id).UpdateColumn("changeset_id", nil).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&model.PageDraft{}).Where("changeset_id = ?", id).UpdateColumn("changeset_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&model.Changeset{}, id).Error
+	})
+}
+
+func (r *changesetRepository) AssignDrafts(ctx context.Context, namespaceCode, projectCode string, changesetID *int64, redirectDraftIDs, pageDraftIDs []int64) (int64, error) {
+	var assigned int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		projectScope := fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode)
+		if len(redirectDraftIDs) > 0 {
+			result := tx.Model(&model.RedirectDraft{}).
+				Where(projectScope+" AND id IN ?", namespaceCode, projectCode, redirectDraftIDs).
+				UpdateColumn("changeset_id", changesetID)
+			if result.Error != nil {
+				return result.Error
+			}
+			assigned += result.RowsAffected
+		}
+		if len(pageDraftIDs) > 0 {
+			result := tx.Model(&model.PageDraft{}).
+				Where(projectScope+" AND id IN ?", namespaceCode, projectCode, pageDraftIDs).
+				UpdateColumn("changeset_id", changesetID)
+			if result.Error != nil {
+				return result.Error
+			}
+			assigned += result.RowsAffected
+		}
+		return nil
+	})
+	return assigned, err
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupChangesetTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Changeset{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewChangesetRepository(t *testing.T) {
+	db := setupChangesetTestDB(t)
+	repo := NewChangesetRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+// createChangesetDrafts stages a create and an update redirect draft and a create page draft in the project
+func createChangesetDrafts(t *testing.T, db *gorm.DB, projectCode string) (*model.RedirectDraft, *model.RedirectDraft, *model.PageDraft) {
+	newRedirect := &model.Redirect{NamespaceCode: "ns1", ProjectCode: projectCode, IsPublished: types.Ptr(false)}
+	require.NoError(t, db.Create(newRedirect).Error)
+	published := &model.Redirect{
+		NamespaceCode: "ns1", ProjectCode: projectCode, IsPublished: types.Ptr(true),
+		Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/published", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+	}
+	require.NoError(t, db.Create(published).Error)
+	newPage := &model.Page{NamespaceCode: "ns1", ProjectCode: projectCode, IsPublished: types.Ptr(false)}
+	require.NoError(t, db.Create(newPage).Error)
+
+	createDraft := &model.RedirectDraft{
+		NamespaceCode: "ns1", ProjectCode: projectCode, ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &newRedirect.ID,
+		NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/new", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+	}
+	updateDraft := &model.RedirectDraft{
+		NamespaceCode: "ns1", ProjectCode: projectCode, ChangeType: model.DraftChangeTypeUpdate, OldRedirectID: &published.ID,
+		NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/published", Target: "/elsewhere", Status: commonTypes.RedirectStatusMovedPermanent},
+	}
+	require.NoError(t, db.Create(createDraft).Error)
+	require.NoError(t, db.Create(updateDraft).Error)
+	pageDraft := &model.PageDraft{
+		NamespaceCode: "ns1", ProjectCode: projectCode, ChangeType: model.DraftChangeTypeCreate, OldPageID: &newPage.ID,
+		NewPage: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/new.txt", Content: "new", ContentType: commonTypes.PageContentTypeTextPlain},
+	}
+	require.NoError(t, db.Create(pageDraft).Error)
+	return createDraft, updateDraft, pageDraft
+}
+
+func TestChangesetRepository(t *testing.T) {
+	db := setupChangesetTestDB(t)
+	repo := NewChangesetRepository(db)
+	ctx := context.Background()
+
+	migration := &model.Changeset{NamespaceCode: "ns1", ProjectCode: "proj1", Name: "migration", Description: "Q3 blog migration"}
+	require.NoError(t, repo.Create(ctx, migration))
+	assert.NotZero(t, migration.ID)
+	archive := &model.Changeset{NamespaceCode: "ns1", ProjectCode: "proj1", Name: "archive"}
+	require.NoError(t, repo.Create(ctx, archive))
+	require.NoError(t, repo.Create(ctx, &model.Changeset{NamespaceCode: "ns1", ProjectCode: "proj2", Name: "migration"}))
+
+	createDraft, updateDraft, pageDraft := createChangesetDrafts(t, db, "proj1")
+	otherDraft, _, _ := createChangesetDrafts(t, db, "proj2")
+
+	t.Run("unique name", func(t *testing.T) {
+		assert.Error(t, repo.Create(ctx, &model.Changeset{NamespaceCode: "ns1", ProjectCode: "proj1", Name: "migration"}))
+	})
+
+	t.Run("assign drafts", func(t *testing.T) {
+		assigned, err := repo.AssignDrafts(ctx, "ns1", "proj1", &migration.ID, []int64{createDraft.ID, updateDraft.ID, otherDraft.ID}, []int64{pageDraft.ID})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), assigned, "the drafts of the other projects are left out")
+
+		changesets, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		require.Len(t, changesets, 2)
+		assert.Equal(t, "archive", changesets[0].Name)
+		assert.Zero(t, changesets[0].RedirectDraftCount)
+		assert.Equal(t, "migration", changesets[1].Name)
+		assert.Equal(t, int64(2), changesets[1].RedirectDraftCount)
+		assert.Equal(t, int64(1), changesets[1].PageDraftCount)
+
+		assigned, err = repo.AssignDrafts(ctx, "ns1", "proj1", nil, []int64{updateDraft.ID}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), assigned)
+		var draft model.RedirectDraft
+		require.NoError(t, db.First(&draft, updateDraft.ID).Error)
+		assert.Nil(t, draft.ChangesetID)
+	})
+
+	t.Run("find", func(t *testing.T) {
+		changeset, err := repo.FindByID(ctx, "ns1", "proj1", migration.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "Q3 blog migration", changeset.Description)
+		assert.Equal(t, int64(1), changeset.RedirectDraftCount)
+
+		_, err = repo.FindByID(ctx, "ns1", "proj2", migration.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		changeset, err = repo.FindByName(ctx, "ns1", "proj1", "archive")
+		require.NoError(t, err)
+		assert.Equal(t, archive.ID, changeset.ID)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		archive.Name = "archives"
+		archive.Description = "old pages"
+		require.NoError(t, repo.Update(ctx, archive))
+
+		changeset, err := repo.FindByID(ctx, "ns1", "proj1", archive.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "archives", changeset.Name)
+		assert.Equal(t, "old pages", changeset.Description)
+	})
+
+	t.Run("delete keeping the drafts", func(t *testing.T) {
+		_, err := repo.AssignDrafts(ctx, "ns1", "proj1", &archive.ID, []int64{updateDraft.ID}, nil)
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, archive.ID, false))
+
+		var draft model.RedirectDraft
+		require.NoError(t, db.First(&draft, updateDraft.ID).Error)
+		assert.Nil(t, draft.ChangesetID)
+		_, err = repo.FindByID(ctx, "ns1", "proj1", archive.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("delete discarding the drafts", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, migration.ID, true))
+
+		var redirectDrafts []model.RedirectDraft
+		require.NoError(t, db.Where("project_code = ?", "proj1").Find(&redirectDrafts).Error)
+		require.Len(t, redirectDrafts, 1)
+		assert.Equal(t, updateDraft.ID, redirectDrafts[0].ID)
+		var pageDrafts int64
+		require.NoError(t, db.Model(&model.PageDraft{}).Where("project_code = ?", "proj1").Count(&pageDrafts).Error)
+		assert.Zero(t, pageDrafts)
+
+		var redirects []model.Redirect
+		require.NoError(t, db.Where("project_code = ?", "proj1").Find(&redirects).Error)
+		require.Len(t, redirects, 1, "the unpublished redirect of the create draft is discarded")
+		assert.True(t, *redirects[0].IsPublished)
+		var pages int64
+		require.NoError(t, db.Model(&model.Page{}).Where("project_code = ?", "proj1").Count(&pages).Error)
+		assert.Zero(t, pages)
+	})
+}
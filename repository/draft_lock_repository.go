@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type DraftLockRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.DraftLock, error)
+	Create(ctx context.Context, lock *model.DraftLock) error
+	UpdateExpiry(ctx context.Context, lock *model.DraftLock) error
+	Delete(ctx context.Context, id int64) error
+	DeleteExpired(ctx context.Context, namespaceCode, projectCode string, now time.Time) error
+	DeleteAllExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type draftLockRepository struct {
+	db *gorm.DB
+}
+
+func NewDraftLockRepository(db *gorm.DB) DraftLockRepository {
+	return &draftLockRepository{db: db}
+}
+
+func (r *draftLockRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *draftLockRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.DraftLock{})
+}
+
+// FindByProject returns the locks of a project, expired ones included, in creation order
+func (r *draftLockRepository) FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.DraftLock, error) {
+	var locks []model.DraftLock
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Order("id").
+		Find(&locks).Error
+	return locks, err
+}
+
+func (r *draftLockRepository) Create(ctx context.Context, lock *model.DraftLock) error {
+	return r.db.WithContext(ctx).Omit("Project").Create(lock).Error
+}
+
+// UpdateExpiry extends a lock held by the same user
+func (r *draftLockRepository) UpdateExpiry(ctx context.Context, lock *model.DraftLock) error {
+	return r.db.WithContext(ctx).
+		Model(&model.DraftLock{}).
+		Where("id = ?", lock.ID).
+		UpdateColumn("expires_at", lock.ExpiresAt).Error
+}
+
+func (r *draftLockRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.DraftLock{}, id).Error
+}
+
+// DeleteExpired removes the locks of a project expired at now
+func (r *draftLockRepository) DeleteExpired(ctx context.Context, namespaceCode, projectCode string, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND expires_at <= ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, now).
+		Delete(&model.DraftLock{}).Error
+}
+
+// DeleteAllExpired removes the locks of all the projects expired at now and returns how many were removed
+func (r *draftLockRepository) DeleteAllExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", now).Delete(&model.DraftLock{})
+	return result.RowsAffected, result.Error
+}
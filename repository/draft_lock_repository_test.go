@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupDraftLockTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.DraftLock{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewDraftLockRepository(t *testing.T) {
+	db := setupDraftLockTestDB(t)
+	repo := NewDraftLockRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestDraftLockRepository(t *testing.T) {
+	db := setupDraftLockTestDB(t)
+	repo := NewDraftLockRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	projectLock := &model.DraftLock{NamespaceCode: "ns1", ProjectCode: "proj1", Target: model.DraftLockTargetProject, LockedBy: "alice", ExpiresAt: now.Add(time.Hour)}
+	require.NoError(t, repo.Create(ctx, projectLock))
+	assert.NotZero(t, projectLock.ID)
+	expired := &model.DraftLock{NamespaceCode: "ns1", ProjectCode: "proj1", Target: model.DraftLockTargetRedirectDraft, DraftID: 3, LockedBy: "bob", ExpiresAt: now.Add(-time.Minute)}
+	require.NoError(t, repo.Create(ctx, expired))
+	require.NoError(t, repo.Create(ctx, &model.DraftLock{NamespaceCode: "ns1", ProjectCode: "proj2", Target: model.DraftLockTargetPageDraft, DraftID: 1, LockedBy: "bob", ExpiresAt: now.Add(-time.Minute)}))
+
+	t.Run("unique target", func(t *testing.T) {
+		err := repo.Create(ctx, &model.DraftLock{NamespaceCode: "ns1", ProjectCode: "proj1", Target: model.DraftLockTargetProject, LockedBy: "bob", ExpiresAt: now.Add(time.Hour)})
+		assert.Error(t, err)
+	})
+
+	t.Run("find by project", func(t *testing.T) {
+		locks, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		require.Len(t, locks, 2)
+		assert.Equal(t, projectLock.ID, locks[0].ID)
+		assert.Equal(t, int64(3), locks[1].DraftID)
+	})
+
+	t.Run("update expiry", func(t *testing.T) {
+		projectLock.ExpiresAt = now.Add(2 * time.Hour)
+		require.NoError(t, repo.UpdateExpiry(ctx, projectLock))
+
+		locks, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		assert.WithinDuration(t, now.Add(2*time.Hour), locks[0].ExpiresAt, time.Second)
+	})
+
+	t.Run("delete expired", func(t *testing.T) {
+		require.NoError(t, repo.DeleteExpired(ctx, "ns1", "proj1", now))
+
+		locks, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		require.Len(t, locks, 1)
+		assert.Equal(t, projectLock.ID, locks[0].ID)
+
+		// The locks of the other projects are kept
+		locks, err = repo.FindByProject(ctx, "ns1", "proj2")
+		require.NoError(t, err)
+		assert.Len(t, locks, 1)
+	})
+
+	t.Run("delete all expired", func(t *testing.T) {
+		deleted, err := repo.DeleteAllExpired(ctx, now)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), deleted)
+
+		locks, err := repo.FindByProject(ctx, "ns1", "proj2")
+		require.NoError(t, err)
+		assert.Empty(t, locks)
+		locks, err = repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		assert.Len(t, locks, 1)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, projectLock.ID))
+
+		locks, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		assert.Empty(t, locks)
+	})
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type GroupRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	Create(ctx context.Context, group *model.Group) error
+	Update(ctx context.Context, group *model.Group) error
+	Delete(ctx context.Context, id int64) error
+	FindByID(ctx context.Context, id int64) (*model.Group, error)
+	FindByCode(ctx context.Context, code string) (*model.Group, error)
+	FindAll(ctx context.Context) ([]model.Group, error)
+
+	// Group members and roles
+	GetGroupUsers(ctx context.Context, groupID int64) ([]model.User, error)
+	SetGroupUsers(ctx context.Context, groupID int64, userIDs []int64) error
+	GetGroupRoles(ctx context.Context, groupID int64) ([]model.Role, error)
+	SetGroupRoles(ctx context.Context, groupID int64, roleIDs []int64) error
+	GetUserGroups(ctx context.Context, userID int64) ([]model.Group, error)
+}
+
+type groupRepository struct {
+	db *gorm.DB
+}
+
+func NewGroupRepository(db *gorm.DB) GroupRepository {
+	return &groupRepository{db: db}
+}
+
+func (r *groupRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *groupRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.Group{})
+}
+
+func (r *groupRepository) Create(ctx context.Context, group *model.Group) error {
+	return r.db.WithContext(ctx).Create(group).Error
+}
+
+func (r *groupRepository) Update(ctx context.Context, group *model.Group) error {
+	return r.db.WithContext(ctx).Save(group).Error
+}
+
+func (r *groupRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", id).Delete(&model.UserGroup{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", id).Delete(&model.GroupRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&model.Group{}).Error
+	})
+}
+
+func (r *groupRepository) FindByID(ctx context.Context, id int64) (*model.Group, error) {
+	var group model.Group
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&group).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) FindByCode(ctx context.Context, code string) (*model.Group, error) {
+	var group model.Group
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&group).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) FindAll(ctx context.Context) ([]model.Group, error) {
+	var groups []model.Group
+	err := r.db.WithContext(ctx).Order("code").Find(&groups).Error
+	return groups, err
+}
+
+// GetGroupUsers returns the users of a group, ordered by username
+func (r *groupRepository) GetGroupUsers(ctx context.Context, groupID int64) ([]model.User, error) {
+	var users []model.User
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Model(&model.UserGroup{}).Select("user_id").Where("group_id = ?", groupID)).
+		Order("username").
+		Find(&users).Error
+	return users, err
+}
+
+// SetGroupUsers replaces the users of a group
+func (r *groupRepository) SetGroupUsers(ctx context.Context, groupID int64, userIDs []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", groupID).Delete(&model.UserGroup{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+		links := make([]model.UserGroup, len(userIDs))
+		for i, userID := range userIDs {
+			links[i] = model.UserGroup{UserID: userID, GroupID: groupID}
+		}
+		return tx.Omit("User", "Group").Create(&links).Error
+	})
+}
+
+// GetGroupRoles returns the roles assigned to a group, ordered by code
+func (r *groupRepository) GetGroupRoles(ctx context.Context, groupID int64) ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Model(&model.GroupRole{}).Select("role_id").Where("group_id = ?", groupID)).
+		Order("code").
+		Find(&roles).Error
+	return roles, err
+}
+
+// SetGroupRoles replaces the roles assigned to a group
+func (r *groupRepository) SetGroupRoles(ctx context.Context, groupID int64, roleIDs []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", groupID).Delete(&model.GroupRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+		links := make([]model.GroupRole, len(roleIDs))
+		for i, roleID := range roleIDs {
+			links[i] = model.GroupRole{GroupID: groupID, RoleID: roleID}
+		}
+		return tx.Omit("Group", "Role").Create(&links).Error
+	})
+}
+
+// GetUserGroups returns the groups a user belongs to, ordered by code
+func (r *groupRepository) GetUserGroups(ctx context.Context, userID int64) ([]model.Group, error) {
+	var groups []model.Group
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", r.db.Model(&model.UserGroup{}).Select("group_id").Where("user_id = ?", userID)).
+		Order("code").
+		Find(&groups).Error
+	return groups, err
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupGroupTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.User{}, &model.Role{}, &model.Group{}, &model.UserGroup{}, &model.GroupRole{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewGroupRepository(t *testing.T) {
+	db := setupGroupTestDB(t)
+	repo := NewGroupRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestGroupRepository(t *testing.T) {
+	db := setupGroupTestDB(t)
+	repo := NewGroupRepository(db)
+	ctx := context.Background()
+
+	alice := &model.User{Username: "alice", Firstname: "Alice", Lastname: "Martin"}
+	bob := &model.User{Username: "bob", Firstname: "Bob", Lastname: "Durand"}
+	require.NoError(t, db.Create(alice).Error)
+	require.NoError(t, db.Create(bob).Error)
+	editor := &model.Role{Code: "editor", Type: model.RoleTypeRole}
+	viewer := &model.Role{Code: "viewer", Type: model.RoleTypeRole}
+	require.NoError(t, db.Create(editor).Error)
+	require.NoError(t, db.Create(viewer).Error)
+
+	support := &model.Group{Code: "support", Name: "Support"}
+	require.NoError(t, repo.Create(ctx, support))
+	assert.NotZero(t, support.ID)
+	require.NoError(t, repo.Create(ctx, &model.Group{Code: "marketing", Name: "Marketing"}))
+
+	t.Run("unique code", func(t *testing.T) {
+		assert.Error(t, repo.Create(ctx, &model.Group{Code: "support", Name: "Other"}))
+	})
+
+	t.Run("find", func(t *testing.T) {
+		group, err := repo.FindByCode(ctx, "support")
+		require.NoError(t, err)
+		assert.Equal(t, support.ID, group.ID)
+
+		group, err = repo.FindByID(ctx, support.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "support", group.Code)
+
+		_, err = repo.FindByCode(ctx, "unknown")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		_, err = repo.FindByID(ctx, 999)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		groups, err := repo.FindAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, groups, 2)
+		assert.Equal(t, "marketing", groups[0].Code)
+		assert.Equal(t, "support", groups[1].Code)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		support.Description = "Customer support team"
+		require.NoError(t, repo.Update(ctx, support))
+
+		group, err := repo.FindByCode(ctx, "support")
+		require.NoError(t, err)
+		assert.Equal(t, "Customer support team", group.Description)
+	})
+
+	t.Run("users", func(t *testing.T) {
+		require.NoError(t, repo.SetGroupUsers(ctx, support.ID, []int64{bob.ID, alice.ID}))
+		users, err := repo.GetGroupUsers(ctx, support.ID)
+		require.NoError(t, err)
+		require.Len(t, users, 2)
+		assert.Equal(t, "alice", users[0].Username)
+		assert.Equal(t, "bob", users[1].Username)
+
+		groups, err := repo.GetUserGroups(ctx, alice.ID)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "support", groups[0].Code)
+
+		require.NoError(t, repo.SetGroupUsers(ctx, support.ID, []int64{bob.ID}))
+		users, err = repo.GetGroupUsers(ctx, support.ID)
+		require.NoError(t, err)
+		require.Len(t, users, 1)
+		assert.Equal(t, "bob", users[0].Username)
+	})
+
+	t.Run("roles", func(t *testing.T) {
+		require.NoError(t, repo.SetGroupRoles(ctx, support.ID, []int64{viewer.ID, editor.ID}))
+		roles, err := repo.GetGroupRoles(ctx, support.ID)
+		require.NoError(t, err)
+		require.Len(t, roles, 2)
+		assert.Equal(t, "editor", roles[0].Code)
+		assert.Equal(t, "viewer", roles[1].Code)
+
+		require.NoError(t, repo.SetGroupRoles(ctx, support.ID, nil))
+		roles, err = repo.GetGroupRoles(ctx, support.ID)
+		require.NoError(t, err)
+		assert.Empty(t, roles)
+	})
+
+	t.Run("delete removes the links", func(t *testing.T) {
+		require.NoError(t, repo.SetGroupRoles(ctx, support.ID, []int64{editor.ID}))
+		require.NoError(t, repo.Delete(ctx, support.ID))
+
+		_, err := repo.FindByCode(ctx, "support")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		var links int64
+		require.NoError(t, db.Model(&model.UserGroup{}).Where("group_id = ?", support.ID).Count(&links).Error)
+		assert.Zero(t, links)
+		require.NoError(t, db.Model(&model.GroupRole{}).Where("group_id = ?", support.ID).Count(&links).Error)
+		assert.Zero(t, links)
+	})
+}
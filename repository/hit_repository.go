@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const hitBatchSize = 500
+
+type HitRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	AddRedirectHits(ctx context.Context, hits []model.RedirectHit) error
+	AddPageHits(ctx context.Context, hits []model.PageHit) error
+	FilterRedirectIDs(ctx context.Context, namespaceCode, projectCode string, ids []int64) ([]int64, error)
+	FilterPageIDs(ctx context.Context, namespaceCode, projectCode string, ids []int64) ([]int64, error)
+	TopRedirects(ctx context.Context, namespaceCode, projectCode string, since time.Time, limit int) ([]model.RedirectHitCount, error)
+	TopPages(ctx context.Context, namespaceCode, projectCode string, since time.Time, limit int) ([]model.PageHitCount, error)
+	UnusedRedirects(ctx context.Context, namespaceCode, projectCode string, since time.Time, limit, offset int) ([]model.Redirect, int64, error)
+	UnusedPages(ctx context.Context, namespaceCode, projectCode string, since time.Time, limit, offset int) ([]model.Page, int64, error)
+}
+
+type hitRepository struct {
+	db *gorm.DB
+}
+
+func NewHitRepository(db *gorm.DB) HitRepository {
+	return &hitRepository{db: db}
+}
+
+func (r *hitRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+// AddRedirectHits adds the hits to the counters of the redirects, creating the missing days
+func (r *hitRepository) AddRedirectHits(ctx context.Context, hits []model.RedirectHit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	db := r.db.WithContext(ctx)
+	return db.Clauses(hitsIncrement(db, "redirect_hits", "redirect_id")).CreateInBatches(&hits, hitBatchSize).Error
+}
+
+// AddPageHits adds the hits to the counters of the pages, creating the missing days
+func (r *hitRepository) AddPageHits(ctx context.Context, hits []model.PageHit) error {
+	if len(hits) == 0 {
+		return nil
+	}
+	db := r.db.WithContext(ctx)
+	return db.Clauses(hitsIncrement(db, "page_hits", "page_id")).CreateInBatches(&hits, hitBatchSize).Error
+}
+
+// hitsIncrement returns the upsert clause adding the inserted hits to the existing counter
+func hitsIncrement(db *gorm.DB, table, idColumn string) clause.OnConflict {
+	increment := gorm.Expr(fmt.Sprintf("%s.hits + excluded.hits", table))
+	if db.Dialector.Name() == "mysql" {
+		increment = gorm.Expr("hits + VALUES(hits)")
+	}
+	return clause.OnConflict{
+		Columns:   []clause.Column{{Name: idColumn}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"hits": increment}),
+	}
+}
+
+// FilterRedirectIDs returns the ids belonging to redirects of the project
+func (r *hitRepository) FilterRedirectIDs(ctx context.Context, namespaceCode, projectCode string, ids []int64) ([]int64, error) {
+	return r.filterIDs(ctx, &model.Redirect{}, namespaceCode, projectCode, ids)
+}
+
+// FilterPageIDs returns the ids belonging to pages of the project
+func (r *hitRepository) FilterPageIDs(ctx context.Context, namespaceCode, projectCode string, ids []int64) ([]int64, error) {
+	return r.filterIDs(ctx, &model.Page{}, namespaceCode, projectCode, ids)
+}
+
+func (r *hitRepository) filterIDs(ctx context.Context, value interface{}, namespaceCode, projectCode string, ids []int64) ([]int64, error) {
+	found := make([]int64, 0, len(ids))
+	for i := 0; i < len(ids); i += hitBatchSize {
+		end := min(i+hitBatchSize, len(ids))
+		var batch []int64
+		err := r.db.WithContext(ctx).
+			Model(value).
+			Where(fmt.Sprintf("%s = ? AND %s = ? AND id IN ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, ids[i:end]).
+			Pluck("id", &batch).Error
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, batch...)
+	}
+	return found, nil
+}
+
+type hitSum struct {
+	ID   int64
+	Hits int64
+}
+
+// TopRedirects returns the published redirects of the project having the most hits since the given day
+func (r *hitRepository) TopRedirects(ctx context.Context, namespaceCode, projectCode string, since time.Time, limit int) ([]model.RedirectHitCount, error) {
+	sums, err := r.topHits(ctx, "redirects", "redirect_hits", "redirect_id", namespaceCode, projectCode, since, limit)
+	if err != nil || len(sums) == 0 {
+		return []model.RedirectHitCount{}, err
+	}
+
+	var redirects []model.Redirect
+	if err = r.db.WithContext(ctx).Where("id IN ?", hitSumIDs(sums)).Find(&redirects).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]model.Redirect, len(redirects))
+	for _, redirect := range redirects {
+		byID[redirect.ID] = redirect
+	}
+
+	counts := make([]model.RedirectHitCount, 0, len(sums))
+	for _, sum := range sums {
+		if redirect, ok := byID[sum.ID]; ok {
+			counts = append(counts, model.RedirectHitCount{Redirect: redirect, Hits: sum.Hits})
+		}
+	}
+	return counts, nil
+}
+
+// TopPages returns the published pages of the project having the most hits since the given day
+func (r *hitRepository) TopPages(ctx context.Context, namespaceCode, projectCode string, since time.Time, limit int) ([]model.PageHitCount, error) {
+	sums, err := r.topHits(ctx, "pages", "page_hits", "page_id", namespaceCode, projectCode, since, limit)
+	if err != nil || len(sums) == 0 {
+		return []model.PageHitCount{}, err
+	}
+
+	var pages []model.Page
+	if err = r.db.WithContext(ctx).Where("id IN ?", hitSumIDs(sums)).Find(&pages).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]model.Page, len(pages))
+	for _, page := range pages {
+		byID[page.ID] = page
+	}
+
+	counts := make([]model.PageHitCount, 0, len(sums))
+	for _, sum := range sums {
+		if page, ok := byID[sum.ID]; ok {
+			counts = append(counts, model.PageHitCount{Page: page, Hits: sum.Hits})
+		}
+	}
+	return counts, nil
+}
+
+func (r *hitRepository) topHits(ctx context.Context, table, hitTable, idColumn, namespaceCode, projectCode string, since time.Time, limit int) ([]hitSum, error) {
+	var sums []hitSum
+	err := r.db.WithContext(ctx).
+		Table(hitTable).
+		Select(fmt.Sprintf("%[1]s.%[2]s AS id, SUM(%[1]s.hits) AS hits", hitTable, idColumn)).
+		Joins(fmt.Sprintf("JOIN %[1]s ON %[1]s.id = %[2]s.%[3]s", table, hitTable, idColumn)).
+		Where(fmt.Sprintf("%[1]s.%[2]s = ? AND %[1]s.%[3]s = ? AND %[1]s.is_published = 1 AND %[4]s.day >= ?", table, model.ColumnNamespaceCode, model.ColumnProjectCode, hitTable), namespaceCode, projectCode, since).
+		Group(fmt.Sprintf("%s.%s", hitTable, idColumn)).
+		Having(fmt.Sprintf("SUM(%s.hits) > 0", hitTable)).
+		Order("hits DESC, id").
+		Limit(limit).
+		Scan(&sums).Error
+	return sums, err
+}
+
+// UnusedRedirects returns the published redirects of the project without any hit since the given day,
+// the redirects created after this day being ignored
+func (r *hitRepository) UnusedRedirects(ctx context.Context, namespaceCode, projectCode string, since time.Time, limit, offset int) ([]model.Redirect, int64, error) {
+	var total int64
+	query := r.unusedQuery(ctx, &model.Redirect{}, "redirects", "redirect_hits", "redirect_id", namespaceCode, projectCode, since)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var redirects []model.Redirect
+	query = query.Order("redirects.id")
+	if limit != 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	if err := query.Find(&redirects).Error; err != nil {
+		return nil, 0, err
+	}
+	return redirects, total, nil
+}
+
+// UnusedPages returns the published pages of the project without any hit since the given day,
+// the pages created after this day being ignored
+func (r *hitRepository) UnusedPages(ctx context.Context, namespaceCode, projectCode string, since time.Time, limit, offset int) ([]model.Page, int64, error) {
+	var total int64
+	query := r.unusedQuery(ctx, &model.Page{}, "pages", "page_hits", "page_id", namespaceCode, projectCode, since)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var pages []model.Page
+	query = query.Order("pages.id")
+	if limit != 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+	if err := query.Find(&pages).Error; err != nil {
+		return nil, 0, err
+	}
+	return pages, total, nil
+}
+
+func (r *hitRepository) unusedQuery(ctx context.Context, value interface{}, table, hitTable, idColumn, namespaceCode, projectCode string, since time.Time) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(value).
+		Where(fmt.Sprintf("%[1]s.%[2]s = ? AND %[1]s.%[3]s = ? AND %[1]s.is_published = 1 AND %[1]s.created_at < ?", table, model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, since).
+		Where(fmt.Sprintf("NOT EXISTS (SELECT 1 FROM %[2]s WHERE %[2]s.%[3]s = %[1]s.id AND %[2]s.day >= ? AND %[2]s.hits > 0)", table, hitTable, idColumn), since)
+}
+
+func hitSumIDs(sums []hitSum) []int64 {
+	ids := make([]int64, 0, len(sums))
+	for _, sum := range sums {
+		ids = append(ids, sum.ID)
+	}
+	return ids
+}
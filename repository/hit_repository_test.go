@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupHitTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.Page{}, &model.RedirectHit{}, &model.PageHit{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func createTestHitRedirect(t *testing.T, db *gorm.DB, projectCode, source string, createdAt time.Time) *model.Redirect {
+	redirect := &model.Redirect{
+		NamespaceCode: "ns1",
+		ProjectCode:   projectCode,
+		IsPublished:   boolPtr(true),
+		Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "https://example.com" + source},
+		CreatedAt:     createdAt,
+	}
+	require.NoError(t, db.Create(redirect).Error)
+	return redirect
+}
+
+func createTestHitPage(t *testing.T, db *gorm.DB, projectCode, path string, createdAt time.Time) *model.Page {
+	page := &model.Page{
+		NamespaceCode: "ns1",
+		ProjectCode:   projectCode,
+		IsPublished:   boolPtr(true),
+		Page:          &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: path, Content: "content", ContentType: commonTypes.PageContentTypeTextPlain},
+		CreatedAt:     createdAt,
+	}
+	require.NoError(t, db.Create(page).Error)
+	return page
+}
+
+func TestNewHitRepository(t *testing.T) {
+	db := setupHitTestDB(t)
+	repo := NewHitRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+}
+
+func TestHitRepository_AddRedirectHits(t *testing.T) {
+	db := setupHitTestDB(t)
+	repo := NewHitRepository(db)
+	ctx := context.Background()
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	redirect := createTestHitRedirect(t, db, "proj1", "/a", day)
+
+	require.NoError(t, repo.AddRedirectHits(ctx, []model.RedirectHit{{RedirectID: redirect.ID, Day: day, Hits: 3}}))
+	require.NoError(t, repo.AddRedirectHits(ctx, []model.RedirectHit{
+		{RedirectID: redirect.ID, Day: day, Hits: 4},
+		{RedirectID: redirect.ID, Day: day.AddDate(0, 0, 1), Hits: 1},
+	}))
+	require.NoError(t, repo.AddRedirectHits(ctx, nil))
+
+	var hits []model.RedirectHit
+	require.NoError(t, db.Order("day").Find(&hits).Error)
+	require.Len(t, hits, 2)
+	assert.Equal(t, int64(7), hits[0].Hits)
+	assert.Equal(t, int64(1), hits[1].Hits)
+}
+
+func TestHitRepository_AddPageHits(t *testing.T) {
+	db := setupHitTestDB(t)
+	repo := NewHitRepository(db)
+	ctx := context.Background()
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	page := createTestHitPage(t, db, "proj1", "/robots.txt", day)
+
+	require.NoError(t, repo.AddPageHits(ctx, []model.PageHit{{PageID: page.ID, Day: day, Hits: 2}}))
+	require.NoError(t, repo.AddPageHits(ctx, []model.PageHit{{PageID: page.ID, Day: day, Hits: 5}}))
+
+	var hits []model.PageHit
+	require.NoError(t, db.Find(&hits).Error)
+	require.Len(t, hits, 1)
+	assert.Equal(t, int64(7), hits[0].Hits)
+}
+
+func TestHitRepository_FilterIDs(t *testing.T) {
+	db := setupHitTestDB(t)
+	repo := NewHitRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+	redirect := createTestHitRedirect(t, db, "proj1", "/a", now)
+	otherRedirect := createTestHitRedirect(t, db, "proj2", "/b", now)
+	page := createTestHitPage(t, db, "proj1", "/a", now)
+
+	ids, err := repo.FilterRedirectIDs(ctx, "ns1", "proj1", []int64{redirect.ID, otherRedirect.ID, 999})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{redirect.ID}, ids)
+
+	ids, err = repo.FilterPageIDs(ctx, "ns1", "proj1", []int64{page.ID, 999})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{page.ID}, ids)
+}
+
+func TestHitRepository_Reports(t *testing.T) {
+	db := setupHitTestDB(t)
+	repo := NewHitRepository(db)
+	ctx := context.Background()
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	before := since.AddDate(0, 0, -10)
+
+	popular := createTestHitRedirect(t, db, "proj1", "/popular", before)
+	rare := createTestHitRedirect(t, db, "proj1", "/rare", before)
+	old := createTestHitRedirect(t, db, "proj1", "/old", before)
+	unused := createTestHitRedirect(t, db, "proj1", "/unused", before)
+	createTestHitRedirect(t, db, "proj1", "/recent", since.AddDate(0, 0, 1))
+	other := createTestHitRedirect(t, db, "proj2", "/other", before)
+	require.NoError(t, repo.AddRedirectHits(ctx, []model.RedirectHit{
+		{RedirectID: popular.ID, Day: since, Hits: 10},
+		{RedirectID: popular.ID, Day: since.AddDate(0, 0, 1), Hits: 5},
+		{RedirectID: rare.ID, Day: since, Hits: 1},
+		{RedirectID: old.ID, Day: before, Hits: 100},
+		{RedirectID: other.ID, Day: since, Hits: 50},
+	}))
+
+	page := createTestHitPage(t, db, "proj1", "/robots.txt", before)
+	unusedPage := createTestHitPage(t, db, "proj1", "/old.txt", before)
+	require.NoError(t, repo.AddPageHits(ctx, []model.PageHit{{PageID: page.ID, Day: since, Hits: 3}}))
+
+	t.Run("top redirects", func(t *testing.T) {
+		counts, err := repo.TopRedirects(ctx, "ns1", "proj1", since, 10)
+		require.NoError(t, err)
+		require.Len(t, counts, 2)
+		assert.Equal(t, popular.ID, counts[0].Redirect.ID)
+		assert.Equal(t, int64(15), counts[0].Hits)
+		assert.Equal(t, rare.ID, counts[1].Redirect.ID)
+		assert.Equal(t, int64(1), counts[1].Hits)
+
+		counts, err = repo.TopRedirects(ctx, "ns1", "proj1", since, 1)
+		require.NoError(t, err)
+		assert.Len(t, counts, 1)
+	})
+
+	t.Run("top redirects without hits", func(t *testing.T) {
+		counts, err := repo.TopRedirects(ctx, "ns1", "proj3", since, 10)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+
+	t.Run("unused redirects", func(t *testing.T) {
+		redirects, total, err := repo.UnusedRedirects(ctx, "ns1", "proj1", since, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, redirects, 2)
+		assert.Equal(t, old.ID, redirects[0].ID)
+		assert.Equal(t, unused.ID, redirects[1].ID)
+
+		redirects, total, err = repo.UnusedRedirects(ctx, "ns1", "proj1", since, 1, 1)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, redirects, 1)
+		assert.Equal(t, unused.ID, redirects[0].ID)
+	})
+
+	t.Run("top pages", func(t *testing.T) {
+		counts, err := repo.TopPages(ctx, "ns1", "proj1", since, 10)
+		require.NoError(t, err)
+		require.Len(t, counts, 1)
+		assert.Equal(t, page.ID, counts[0].Page.ID)
+		assert.Equal(t, "content", counts[0].Page.Content)
+		assert.Equal(t, int64(3), counts[0].Hits)
+	})
+
+	t.Run("unused pages", func(t *testing.T) {
+		pages, total, err := repo.UnusedPages(ctx, "ns1", "proj1", since, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, pages, 1)
+		assert.Equal(t, unusedPage.ID, pages[0].ID)
+	})
+}
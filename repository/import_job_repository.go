@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type ImportJobRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	FindByIDWithProject(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.ImportJob, error)
+	Create(ctx context.Context, job *model.ImportJob) error
+	Update(ctx context.Context, job *model.ImportJob) error
+	FailUnfinished(ctx context.Context, message string) (int64, error)
+}
+
+type importJobRepository struct {
+	db *gorm.DB
+}
+
+func NewImportJobRepository(db *gorm.DB) ImportJobRepository {
+	return &importJobRepository{db: db}
+}
+
+func (r *importJobRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *importJobRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.ImportJob{})
+}
+
+func (r *importJobRepository) FindByIDWithProject(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.ImportJob, error) {
+	var job model.ImportJob
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("id = ? AND %s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), id, namespaceCode, projectCode).
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *importJobRepository) Create(ctx context.Context, job *model.ImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *importJobRepository) Update(ctx context.Context, job *model.ImportJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// FailUnfinished marks every pending or running job as failed, used when jobs were interrupted by a shutdown
+func (r *importJobRepository) FailUnfinished(ctx context.Context, message string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.ImportJob{}).
+		Where("status IN ?", []model.ImportJobStatus{model.ImportJobStatusPending, model.ImportJobStatusRunning}).
+		Updates(map[string]any{
+			"status":        model.ImportJobStatusFailed,
+			"error_message": message,
+			"finished_at":   r.db.NowFunc(),
+		})
+	return result.RowsAffected, result.Error
+}
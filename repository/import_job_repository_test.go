@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupImportJobTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ImportJob{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func TestNewImportJobRepository(t *testing.T) {
+	db := setupImportJobTestDB(t)
+	repo := NewImportJobRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestImportJobRepository_CreateAndFind(t *testing.T) {
+	db := setupImportJobTestDB(t)
+	repo := NewImportJobRepository(db)
+	ctx := context.Background()
+
+	job := &model.ImportJob{
+		NamespaceCode: "ns1",
+		ProjectCode:   "proj1",
+		Status:        model.ImportJobStatusPending,
+		TotalLines:    3,
+		ErrorCount:    1,
+		Errors:        []model.ImportJobError{{Line: 2, Reason: "INVALID_TYPE", Message: "invalid type"}},
+	}
+	err := repo.Create(ctx, job)
+	assert.NoError(t, err)
+	assert.NotZero(t, job.ID)
+
+	t.Run("found", func(t *testing.T) {
+		found, err := repo.FindByIDWithProject(ctx, "ns1", "proj1", job.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ImportJobStatusPending, found.Status)
+		assert.Equal(t, 3, found.TotalLines)
+		assert.Equal(t, job.Errors, found.Errors)
+	})
+
+	t.Run("other project", func(t *testing.T) {
+		found, err := repo.FindByIDWithProject(ctx, "ns1", "proj2", job.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, found)
+	})
+}
+
+func TestImportJobRepository_Update(t *testing.T) {
+	db := setupImportJobTestDB(t)
+	repo := NewImportJobRepository(db)
+	ctx := context.Background()
+
+	job := &model.ImportJob{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusPending}
+	assert.NoError(t, repo.Create(ctx, job))
+
+	job.Status = model.ImportJobStatusCompleted
+	job.ImportedCount = 10
+	assert.NoError(t, repo.Update(ctx, job))
+
+	found, err := repo.FindByIDWithProject(ctx, "ns1", "proj1", job.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportJobStatusCompleted, found.Status)
+	assert.Equal(t, 10, found.ImportedCount)
+}
+
+func TestImportJobRepository_FailUnfinished(t *testing.T) {
+	db := setupImportJobTestDB(t)
+	repo := NewImportJobRepository(db)
+	ctx := context.Background()
+
+	pending := &model.ImportJob{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusPending}
+	running := &model.ImportJob{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusRunning}
+	completed := &model.ImportJob{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusCompleted}
+	for _, job := range []*model.ImportJob{pending, running, completed} {
+		assert.NoError(t, repo.Create(ctx, job))
+	}
+
+	count, err := repo.FailUnfinished(ctx, "interrupted")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	found, err := repo.FindByIDWithProject(ctx, "ns1", "proj1", running.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportJobStatusFailed, found.Status)
+	assert.Equal(t, "interrupted", found.ErrorMessage)
+	assert.NotNil(t, found.FinishedAt)
+
+	found, err = repo.FindByIDWithProject(ctx, "ns1", "proj1", completed.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, model.ImportJobStatusCompleted, found.Status)
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type ImportProfileRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ImportProfile, error)
+	FindByID(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.ImportProfile, error)
+	FindByName(ctx context.Context, namespaceCode, projectCode, name string) (*model.ImportProfile, error)
+	Create(ctx context.Context, profile *model.ImportProfile) error
+	Update(ctx context.Context, profile *model.ImportProfile) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type importProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewImportProfileRepository(db *gorm.DB) ImportProfileRepository {
+	return &importProfileRepository{db: db}
+}
+
+func (r *importProfileRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *importProfileRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.ImportProfile{})
+}
+
+// FindByProject returns the import profiles of a project in the order of their names
+func (r *importProfileRepository) FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ImportProfile, error) {
+	var profiles []model.ImportProfile
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Order("name").
+		Find(&profiles).Error
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+func (r *importProfileRepository) FindByID(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.ImportProfile, error) {
+	var profile model.ImportProfile
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND id = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, id).
+		First(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *importProfileRepository) FindByName(ctx context.Context, namespaceCode, projectCode, name string) (*model.ImportProfile, error) {
+	var profile model.ImportProfile
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND name = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, name).
+		First(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (r *importProfileRepository) Create(ctx context.Context, profile *model.ImportProfile) error {
+	return r.db.WithContext(ctx).Omit("Project").Create(profile).Error
+}
+
+func (r *importProfileRepository) Update(ctx context.Context, profile *model.ImportProfile) error {
+	return r.db.WithContext(ctx).Omit("Project").Save(profile).Error
+}
+
+func (r *importProfileRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.ImportProfile{}, id).Error
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupImportProfileTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ImportProfile{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewImportProfileRepository(t *testing.T) {
+	db := setupImportProfileTestDB(t)
+	repo := NewImportProfileRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestImportProfileRepository(t *testing.T) {
+	db := setupImportProfileTestDB(t)
+	repo := NewImportProfileRepository(db)
+	ctx := context.Background()
+
+	status := commonTypes.RedirectStatusFound
+	legacy := &model.ImportProfile{
+		NamespaceCode: "ns1", ProjectCode: "proj1", Name: "legacy cms", Columns: []string{"source", "target"},
+		SkipHeader: true, Delimiter: model.ImportDelimiterSemicolon, DefaultStatus: &status,
+	}
+	require.NoError(t, repo.Create(ctx, legacy))
+	assert.NotZero(t, legacy.ID)
+	require.NoError(t, repo.Create(ctx, &model.ImportProfile{NamespaceCode: "ns1", ProjectCode: "proj1", Name: "crm", Delimiter: model.ImportDelimiterTab, Overwrite: true}))
+	require.NoError(t, repo.Create(ctx, &model.ImportProfile{NamespaceCode: "ns1", ProjectCode: "proj2", Name: "legacy cms", Delimiter: model.ImportDelimiterTab}))
+
+	t.Run("unique name", func(t *testing.T) {
+		err := repo.Create(ctx, &model.ImportProfile{NamespaceCode: "ns1", ProjectCode: "proj1", Name: "legacy cms", Delimiter: model.ImportDelimiterTab})
+		assert.Error(t, err)
+	})
+
+	t.Run("find by project", func(t *testing.T) {
+		profiles, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		require.Len(t, profiles, 2)
+		assert.Equal(t, "crm", profiles[0].Name)
+		assert.Equal(t, "legacy cms", profiles[1].Name)
+	})
+
+	t.Run("find by id", func(t *testing.T) {
+		profile, err := repo.FindByID(ctx, "ns1", "proj1", legacy.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"source", "target"}, profile.Columns)
+		assert.Equal(t, model.ImportDelimiterSemicolon, profile.Delimiter)
+		assert.Equal(t, &status, profile.DefaultStatus)
+		assert.Nil(t, profile.DefaultType)
+		assert.False(t, profile.Overwrite)
+
+		_, err = repo.FindByID(ctx, "ns1", "proj2", legacy.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("find by name", func(t *testing.T) {
+		profile, err := repo.FindByName(ctx, "ns1", "proj2", "legacy cms")
+		require.NoError(t, err)
+		assert.NotEqual(t, legacy.ID, profile.ID)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		legacy.Columns = []string{"source", "target", "status"}
+		legacy.DefaultStatus = nil
+		require.NoError(t, repo.Update(ctx, legacy))
+
+		profile, err := repo.FindByID(ctx, "ns1", "proj1", legacy.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"source", "target", "status"}, profile.Columns)
+		assert.Nil(t, profile.DefaultStatus)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, legacy.ID))
+		_, err := repo.FindByID(ctx, "ns1", "proj1", legacy.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NamespacePolicyRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	FindByNamespace(ctx context.Context, namespaceCode string) (*model.NamespacePolicy, error)
+	Save(ctx context.Context, policy *model.NamespacePolicy) error
+}
+
+type namespacePolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewNamespacePolicyRepository(db *gorm.DB) NamespacePolicyRepository {
+	return &namespacePolicyRepository{db: db}
+}
+
+func (r *namespacePolicyRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *namespacePolicyRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.NamespacePolicy{})
+}
+
+// FindByNamespace returns the policy of a namespace, nil when it has none
+func (r *namespacePolicyRepository) FindByNamespace(ctx context.Context, namespaceCode string) (*model.NamespacePolicy, error) {
+	var policies []model.NamespacePolicy
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ?", model.ColumnNamespaceCode), namespaceCode).
+		Limit(1).
+		Find(&policies).Error
+	if err != nil || len(policies) == 0 {
+		return nil, err
+	}
+	return &policies[0], nil
+}
+
+// Save creates the policy of its namespace or replaces its rules
+func (r *namespacePolicyRepository) Save(ctx context.Context, policy *model.NamespacePolicy) error {
+	return r.db.WithContext(ctx).
+		Omit("Namespace").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: model.ColumnNamespaceCode}},
+			DoUpdates: clause.AssignmentColumns([]string{"rules", "updated_by", "updated_at"}),
+		}).
+		Create(policy).Error
+}
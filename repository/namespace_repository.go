@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -14,9 +16,10 @@ type NamespaceRepository interface {
 	Update(ctx context.Context, namespace *model.Namespace) error
 	DeleteByCode(ctx context.Context, code string) error
 	FindByCode(ctx context.Context, code string) (*model.Namespace, error)
+	FindByCodes(ctx context.Context, codes []string) ([]model.Namespace, error)
 	FindAll(ctx context.Context) ([]model.Namespace, error)
 	Search(ctx context.Context, query *gorm.DB) ([]model.Namespace, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Namespace, int64, error)
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Namespace, int64, error)
 }
 
 type namespaceRepository struct {
@@ -56,6 +59,15 @@ func (r *namespaceRepository) FindByCode(ctx context.Context, code string) (*mod
 	return &namespace, nil
 }
 
+func (r *namespaceRepository) FindByCodes(ctx context.Context, codes []string) ([]model.Namespace, error) {
+	var namespaces []model.Namespace
+	if len(codes) == 0 {
+		return namespaces, nil
+	}
+	err := r.db.WithContext(ctx).Where("namespace_code IN ?", codes).Find(&namespaces).Error
+	return namespaces, err
+}
+
 func (r *namespaceRepository) FindAll(ctx context.Context) ([]model.Namespace, error) {
 	var namespaces []model.Namespace
 	err := r.db.WithContext(ctx).WithContext(ctx).Find(&namespaces).Error
@@ -63,11 +75,11 @@ func (r *namespaceRepository) FindAll(ctx context.Context) ([]model.Namespace, e
 }
 
 func (r *namespaceRepository) Search(ctx context.Context, query *gorm.DB) ([]model.Namespace, error) {
-	namespaces, _, err := r.SearchPaginate(ctx, query, 0, 0)
+	namespaces, _, err := r.SearchPaginate(ctx, query, 0, 0, nil)
 	return namespaces, err
 }
 
-func (r *namespaceRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Namespace, int64, error) {
+func (r *namespaceRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Namespace, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.Namespace{})
@@ -76,6 +88,11 @@ func (r *namespaceRepository) SearchPaginate(ctx context.Context, query *gorm.DB
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
+	query, err := database.ApplyOrderBy(query, model.NamespaceSortableColumns, orderBy, "namespaces")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit != 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
@@ -234,6 +234,32 @@ func TestNamespaceRepository_FindByCode(t *testing.T) {
 	}
 }
 
+func TestNamespaceRepository_FindByCodes(t *testing.T) {
+	db := setupNamespaceTestDB(t)
+	repo := NewNamespaceRepository(db)
+	ctx := context.Background()
+
+	_ = repo.Create(ctx, &model.Namespace{NamespaceCode: "ns-1", Name: "Namespace 1"})
+	_ = repo.Create(ctx, &model.Namespace{NamespaceCode: "ns-2", Name: "Namespace 2"})
+	_ = repo.Create(ctx, &model.Namespace{NamespaceCode: "ns-3", Name: "Namespace 3"})
+
+	t.Run("find several namespaces", func(t *testing.T) {
+		namespaces, err := repo.FindByCodes(ctx, []string{"ns-1", "ns-3", "not-found"})
+		assert.NoError(t, err)
+		codes := []string{}
+		for _, namespace := range namespaces {
+			codes = append(codes, namespace.NamespaceCode)
+		}
+		assert.ElementsMatch(t, []string{"ns-1", "ns-3"}, codes)
+	})
+
+	t.Run("no codes", func(t *testing.T) {
+		namespaces, err := repo.FindByCodes(ctx, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, namespaces)
+	})
+}
+
 func TestNamespaceRepository_FindAll(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -313,28 +339,28 @@ func TestNamespaceRepository_SearchPaginate(t *testing.T) {
 	}
 
 	t.Run("paginate with limit", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 0)
+		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 0, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 5)
 		assert.Equal(t, int64(10), total)
 	})
 
 	t.Run("paginate with offset", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 5)
+		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 5, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 5)
 		assert.Equal(t, int64(10), total)
 	})
 
 	t.Run("paginate with offset beyond total", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 15)
+		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 15, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 0)
 		assert.Equal(t, int64(10), total)
 	})
 
 	t.Run("paginate without limit returns all", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 0, 0)
+		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 0, 0, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 10)
 		assert.Equal(t, int64(10), total)
@@ -351,7 +377,7 @@ func TestNamespaceRepository_SearchPaginate_WithCustomQuery(t *testing.T) {
 	_ = repo.Create(ctx, &model.Namespace{NamespaceCode: "beta-1", Name: "Beta One"})
 
 	query := db.Model(&model.Namespace{}).Where("namespace_code LIKE ?", "alpha%")
-	results, total, err := repo.SearchPaginate(ctx, query, 10, 0)
+	results, total, err := repo.SearchPaginate(ctx, query, 10, 0, nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 2)
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type NotificationSubscriptionRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.NotificationSubscription, error)
+	FindByUser(ctx context.Context, namespaceCode, projectCode, username string) ([]model.NotificationSubscription, error)
+	FindByUserChannel(ctx context.Context, namespaceCode, projectCode, username string, channel model.NotificationChannel) (*model.NotificationSubscription, error)
+	Save(ctx context.Context, subscription *model.NotificationSubscription) error
+	Delete(ctx context.Context, namespaceCode, projectCode, username string, channel model.NotificationChannel) (bool, error)
+}
+
+type notificationSubscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationSubscriptionRepository(db *gorm.DB) NotificationSubscriptionRepository {
+	return &notificationSubscriptionRepository{db: db}
+}
+
+func (r *notificationSubscriptionRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *notificationSubscriptionRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.NotificationSubscription{})
+}
+
+// FindByProject returns the subscriptions of all the users to a project
+func (r *notificationSubscriptionRepository) FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.NotificationSubscription, error) {
+	var subscriptions []model.NotificationSubscription
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Order("id").
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// FindByUser returns the subscriptions of a user to a project
+func (r *notificationSubscriptionRepository) FindByUser(ctx context.Context, namespaceCode, projectCode, username string) ([]model.NotificationSubscription, error) {
+	var subscriptions []model.NotificationSubscription
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND username = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, username).
+		Order("channel").
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *notificationSubscriptionRepository) FindByUserChannel(ctx context.Context, namespaceCode, projectCode, username string, channel model.NotificationChannel) (*model.NotificationSubscription, error) {
+	var subscription model.NotificationSubscription
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND username = ? AND channel = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, username, channel).
+		First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *notificationSubscriptionRepository) Save(ctx context.Context, subscription *model.NotificationSubscription) error {
+	return r.db.WithContext(ctx).Omit("Project").Save(subscription).Error
+}
+
+func (r *notificationSubscriptionRepository) Delete(ctx context.Context, namespaceCode, projectCode, username string, channel model.NotificationChannel) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND username = ? AND channel = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, username, channel).
+		Delete(&model.NotificationSubscription{})
+	return result.RowsAffected > 0, result.Error
+}
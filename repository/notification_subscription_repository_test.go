@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupNotificationSubscriptionTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.NotificationSubscription{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewNotificationSubscriptionRepository(t *testing.T) {
+	db := setupNotificationSubscriptionTestDB(t)
+	repo := NewNotificationSubscriptionRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestNotificationSubscriptionRepository(t *testing.T) {
+	db := setupNotificationSubscriptionTestDB(t)
+	repo := NewNotificationSubscriptionRepository(db)
+	ctx := context.Background()
+
+	email := &model.NotificationSubscription{
+		NamespaceCode: "ns1", ProjectCode: "proj1", Username: "alice", Channel: model.NotificationChannelEmail,
+		Target: "alice@example.com", Events: []model.NotificationEventType{model.NotificationEventPublishFailed},
+	}
+	require.NoError(t, repo.Save(ctx, email))
+	assert.NotZero(t, email.ID)
+	require.NoError(t, repo.Save(ctx, &model.NotificationSubscription{
+		NamespaceCode: "ns1", ProjectCode: "proj1", Username: "bob", Channel: model.NotificationChannelSlack,
+		Target: "https://hooks.slack.com/services/T/B/X", Events: []model.NotificationEventType{model.NotificationEventPublishSucceeded},
+	}))
+	require.NoError(t, repo.Save(ctx, &model.NotificationSubscription{
+		NamespaceCode: "ns1", ProjectCode: "proj2", Username: "alice", Channel: model.NotificationChannelEmail,
+		Target: "alice@example.com", Events: []model.NotificationEventType{model.NotificationEventQuotaWarning},
+	}))
+
+	t.Run("unique channel per user", func(t *testing.T) {
+		err := repo.Save(ctx, &model.NotificationSubscription{
+			NamespaceCode: "ns1", ProjectCode: "proj1", Username: "alice", Channel: model.NotificationChannelEmail,
+			Target: "other@example.com", Events: []model.NotificationEventType{model.NotificationEventPublishFailed},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("find by project", func(t *testing.T) {
+		subscriptions, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 2)
+		assert.Equal(t, email.ID, subscriptions[0].ID)
+		assert.Equal(t, []model.NotificationEventType{model.NotificationEventPublishFailed}, subscriptions[0].Events)
+	})
+
+	t.Run("find by user", func(t *testing.T) {
+		subscriptions, err := repo.FindByUser(ctx, "ns1", "proj1", "alice")
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 1)
+		assert.Equal(t, "alice@example.com", subscriptions[0].Target)
+
+		subscription, err := repo.FindByUserChannel(ctx, "ns1", "proj1", "bob", model.NotificationChannelSlack)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", subscription.Username)
+
+		_, err = repo.FindByUserChannel(ctx, "ns1", "proj1", "bob", model.NotificationChannelEmail)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleted, err := repo.Delete(ctx, "ns1", "proj1", "alice", model.NotificationChannelEmail)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+
+		deleted, err = repo.Delete(ctx, "ns1", "proj1", "alice", model.NotificationChannelEmail)
+		require.NoError(t, err)
+		assert.False(t, deleted)
+
+		subscriptions, err := repo.FindByUser(ctx, "ns1", "proj2", "alice")
+		require.NoError(t, err)
+		assert.Len(t, subscriptions, 1)
+	})
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type OutboxRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxEvent, error)
+	ClaimByID(ctx context.Context, id int64, now time.Time, lease time.Duration) (bool, error)
+	MarkDelivered(ctx context.Context, id int64, now time.Time) error
+	MarkFailed(ctx context.Context, event *model.OutboxEvent) error
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *outboxRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.OutboxEvent{})
+}
+
+// Claim takes for lease the pending events available at now, in the order they were written, up to limit. The events
+// taken by another replica whose lease has not ended are skipped.
+func (r *outboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxEvent, error) {
+	var candidates []model.OutboxEvent
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND available_at <= ? AND (locked_until IS NULL OR locked_until <= ?)", model.OutboxStatusPending, now, now).
+		Order("id").
+		Limit(limit).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	claimed := make([]model.OutboxEvent, 0, len(candidates))
+	for _, event := range candidates {
+		ok, err := r.ClaimByID(ctx, event.ID, now, lease)
+		if err != nil {
+			return claimed, err
+		}
+		if ok {
+			lockedUntil := now.Add(lease)
+			event.LockedUntil = &lockedUntil
+			claimed = append(claimed, event)
+		}
+	}
+	return claimed, nil
+}
+
+// ClaimByID takes a pending event for lease, it returns false when the event was delivered or taken by another
+// replica in the meantime
+func (r *outboxRepository) ClaimByID(ctx context.Context, id int64, now time.Time, lease time.Duration) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id = ? AND status = ? AND (locked_until IS NULL OR locked_until <= ?)", id, model.OutboxStatusPending, now).
+		UpdateColumn("locked_until", now.Add(lease))
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *outboxRepository) MarkDelivered(ctx context.Context, id int64, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"status":       model.OutboxStatusDelivered,
+			"locked_until": nil,
+			"processed_at": now,
+		}).Error
+}
+
+// MarkFailed saves the attempts, the error and the status of an event whose delivery failed, releasing its lease
+func (r *outboxRepository) MarkFailed(ctx context.Context, event *model.OutboxEvent) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OutboxEvent{}).
+		Where("id = ?", event.ID).
+		UpdateColumns(map[string]interface{}{
+			"status":       event.Status,
+			"attempts":     event.Attempts,
+			"last_error":   event.LastError,
+			"available_at": event.AvailableAt,
+			"locked_until": nil,
+			"processed_at": event.ProcessedAt,
+		}).Error
+}
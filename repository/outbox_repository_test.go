@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupOutboxTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.OutboxEvent{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewOutboxRepository(t *testing.T) {
+	db := setupOutboxTestDB(t)
+	repo := NewOutboxRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestOutboxRepository_Claim(t *testing.T) {
+	db := setupOutboxTestDB(t)
+	repo := NewOutboxRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	past := now.Add(-time.Minute)
+	future := now.Add(time.Minute)
+
+	events := []model.OutboxEvent{
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusPending, AvailableAt: past},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusPending, AvailableAt: future},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusPending, AvailableAt: past, LockedUntil: &future},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusPending, AvailableAt: past, LockedUntil: &past},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusDelivered, AvailableAt: past},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusPending, AvailableAt: past},
+	}
+	require.NoError(t, db.Create(&events).Error)
+
+	claimed, err := repo.Claim(ctx, now, time.Minute, 2)
+	require.NoError(t, err)
+	require.Len(t, claimed, 2)
+	assert.Equal(t, events[0].ID, claimed[0].ID)
+	assert.Equal(t, events[3].ID, claimed[1].ID, "the event whose lease ended is taken again")
+	require.NotNil(t, claimed[0].LockedUntil)
+	assert.True(t, claimed[0].LockedUntil.Equal(future))
+
+	claimed, err = repo.Claim(ctx, now, time.Minute, 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1, "the events under lease are skipped")
+	assert.Equal(t, events[5].ID, claimed[0].ID)
+
+	ok, err := repo.ClaimByID(ctx, events[5].ID, now, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	ok, err = repo.ClaimByID(ctx, events[5].ID, future, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestOutboxRepository_Mark(t *testing.T) {
+	db := setupOutboxTestDB(t)
+	repo := NewOutboxRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	delivered := &model.OutboxEvent{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusPending, AvailableAt: now, LockedUntil: &now}
+	failed := &model.OutboxEvent{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusPending, AvailableAt: now, LockedUntil: &now}
+	require.NoError(t, db.Create(delivered).Error)
+	require.NoError(t, db.Create(failed).Error)
+
+	require.NoError(t, repo.MarkDelivered(ctx, delivered.ID, now))
+	var event model.OutboxEvent
+	require.NoError(t, db.First(&event, delivered.ID).Error)
+	assert.Equal(t, model.OutboxStatusDelivered, event.Status)
+	assert.Nil(t, event.LockedUntil)
+	require.NotNil(t, event.ProcessedAt)
+
+	failed.Attempts = 2
+	failed.LastError = "unavailable"
+	failed.AvailableAt = now.Add(time.Minute)
+	require.NoError(t, repo.MarkFailed(ctx, failed))
+	event = model.OutboxEvent{}
+	require.NoError(t, db.First(&event, failed.ID).Error)
+	assert.Equal(t, model.OutboxStatusPending, event.Status)
+	assert.Equal(t, 2, event.Attempts)
+	assert.Equal(t, "unavailable", event.LastError)
+	assert.True(t, event.AvailableAt.Equal(now.Add(time.Minute)))
+	assert.Nil(t, event.LockedUntil)
+	assert.Nil(t, event.ProcessedAt)
+}
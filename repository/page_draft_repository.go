@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -17,8 +19,11 @@ type PageDraftRepository interface {
 	Update(ctx context.Context, draft *model.PageDraft) error
 	Delete(ctx context.Context, id int64) error
 	Search(ctx context.Context, query *gorm.DB) ([]model.PageDraft, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.PageDraft, int64, error)
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.PageDraft, int64, error)
+	SearchCursor(ctx context.Context, query *gorm.DB, afterID int64, limit int) ([]model.PageDraft, bool, error)
 	CheckPathAvailability(ctx context.Context, namespaceCode, projectCode, path string, excludePageID, excludeDraftID *int64) (bool, error)
+	FindTombstone(ctx context.Context, namespaceCode, projectCode string, pageID int64) (*model.PageTombstone, error)
+	SearchTombstones(ctx context.Context, namespaceCode, projectCode string, limit, offset int) ([]model.PageTombstone, int64, error)
 }
 
 type pageDraftRepository struct {
@@ -86,11 +91,11 @@ func (r *pageDraftRepository) Delete(ctx context.Context, id int64) error {
 }
 
 func (r *pageDraftRepository) Search(ctx context.Context, query *gorm.DB) ([]model.PageDraft, error) {
-	drafts, _, err := r.SearchPaginate(ctx, query, 0, 0)
+	drafts, _, err := r.SearchPaginate(ctx, query, 0, 0, nil)
 	return drafts, err
 }
 
-func (r *pageDraftRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.PageDraft, int64, error) {
+func (r *pageDraftRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.PageDraft, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.PageDraft{})
@@ -100,6 +105,11 @@ func (r *pageDraftRepository) SearchPaginate(ctx context.Context, query *gorm.DB
 		return nil, 0, err
 	}
 
+	query, err := database.ApplyOrderBy(query, model.PageDraftSortableColumns, orderBy, "page_drafts")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit != 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
@@ -112,6 +122,27 @@ func (r *pageDraftRepository) SearchPaginate(ctx context.Context, query *gorm.DB
 	return drafts, total, nil
 }
 
+// SearchCursor returns the items following afterID, ordered by id, and whether more items follow
+func (r *pageDraftRepository) SearchCursor(ctx context.Context, query *gorm.DB, afterID int64, limit int) ([]model.PageDraft, bool, error) {
+	if query == nil {
+		query = r.db.WithContext(ctx).Model(&model.PageDraft{})
+	}
+	if afterID > 0 {
+		query = query.Where("page_drafts.id > ?", afterID)
+	}
+
+	var drafts []model.PageDraft
+	if err := query.Preload("OldPage").Order("page_drafts.id").Limit(limit + 1).Find(&drafts).Error; err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(drafts) > limit
+	if hasMore {
+		drafts = drafts[:limit]
+	}
+	return drafts, hasMore, nil
+}
+
 // CheckPathAvailability checks if a path is available for a project.
 // Returns true if available, false if already used.
 func (r *pageDraftRepository) CheckPathAvailability(ctx context.Context, namespaceCode, projectCode, path string, excludePageID, excludeDraftID *int64) (bool, error) {
@@ -150,4 +181,37 @@ func (r *pageDraftRepository) CheckPathAvailability(ctx context.Context, namespa
 	}
 
 	return !exists, nil
-}
\ No newline at end of file
+}
+
+// FindTombstone returns the tombstone of a page of the project deleted by a publish
+func (r *pageDraftRepository) FindTombstone(ctx context.Context, namespaceCode, projectCode string, pageID int64) (*model.PageTombstone, error) {
+	var tombstone model.PageTombstone
+	err := r.db.WithContext(ctx).
+		Where("namespace_code = ? AND project_code = ? AND page_id = ?", namespaceCode, projectCode, pageID).
+		First(&tombstone).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tombstone, nil
+}
+
+// SearchTombstones returns the tombstones of the project, the latest deletions first
+func (r *pageDraftRepository) SearchTombstones(ctx context.Context, namespaceCode, projectCode string, limit, offset int) ([]model.PageTombstone, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.PageTombstone{}).
+		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit != 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	var tombstones []model.PageTombstone
+	if err := query.Order("deleted_at DESC, id DESC").Find(&tombstones).Error; err != nil {
+		return nil, 0, err
+	}
+	return tombstones, total, nil
+}
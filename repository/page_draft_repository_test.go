@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -15,7 +17,7 @@ func setupPageDraftTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Page{}, &model.PageDraft{})
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Page{}, &model.PageDraft{}, &model.PageTombstone{})
 	assert.NoError(t, err)
 
 	return db
@@ -345,6 +347,37 @@ func TestPageDraftRepository_Update(t *testing.T) {
 	assert.Equal(t, "/updated", found.NewPage.Path)
 }
 
+func TestPageDraftRepository_Authors(t *testing.T) {
+	db := setupPageDraftTestDB(t)
+	createTestPageDraftNamespace(t, db, "test-ns", "Test Namespace")
+	createTestPageDraftProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewPageDraftRepository(db)
+
+	draft := &model.PageDraft{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		ChangeType:    model.DraftChangeTypeCreate,
+		NewPage: &commonTypes.Page{
+			Type:        commonTypes.PageTypeBasic,
+			Path:        "/path",
+			Content:     "content",
+			ContentType: commonTypes.PageContentTypeTextPlain,
+		},
+		CreatedBy: "importer",
+	}
+	assert.NoError(t, repo.Create(types.WithSubject(context.Background(), "alice"), draft))
+	assert.Equal(t, "importer", draft.CreatedBy)
+	assert.Equal(t, "importer", draft.UpdatedBy)
+
+	draft.NewPage.Content = "updated"
+	assert.NoError(t, repo.Update(types.WithSubject(context.Background(), "bob"), draft))
+
+	var found model.PageDraft
+	db.First(&found, draft.ID)
+	assert.Equal(t, "importer", found.CreatedBy)
+	assert.Equal(t, "bob", found.UpdatedBy)
+}
+
 func TestPageDraftRepository_Delete(t *testing.T) {
 	db := setupPageDraftTestDB(t)
 	createTestPageDraftNamespace(t, db, "test-ns", "Test Namespace")
@@ -450,7 +483,7 @@ func TestPageDraftRepository_SearchPaginate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, total, err := repo.SearchPaginate(ctx, nil, tt.limit, tt.offset)
+			results, total, err := repo.SearchPaginate(ctx, nil, tt.limit, tt.offset, nil)
 
 			assert.NoError(t, err)
 			assert.Len(t, results, tt.wantCount)
@@ -475,7 +508,7 @@ func TestPageDraftRepository_SearchPaginate_PreloadsOldPage(t *testing.T) {
 	}
 	db.Create(draft)
 
-	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0)
+	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0, nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
@@ -660,4 +693,70 @@ func TestPageDraftRepository_CheckPathAvailability(t *testing.T) {
 		assert.Error(t, err)
 		assert.False(t, available)
 	})
-}
\ No newline at end of file
+}
+
+func TestPageDraftRepository_SearchCursor(t *testing.T) {
+	db := setupPageDraftTestDB(t)
+	createTestPageDraftNamespace(t, db, "test-ns", "Test Namespace")
+	createTestPageDraftProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewPageDraftRepository(db)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		db.Create(&model.PageDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate})
+	}
+
+	var ids []int64
+	afterID := int64(0)
+	for page := 0; page < 3; page++ {
+		results, hasMore, err := repo.SearchCursor(ctx, nil, afterID, 2)
+
+		assert.NoError(t, err)
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		if !hasMore {
+			assert.Equal(t, 2, page)
+			assert.Len(t, results, 1)
+			break
+		}
+		assert.Len(t, results, 2)
+		afterID = results[len(results)-1].ID
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+
+	results, hasMore, err := repo.SearchCursor(ctx, nil, 5, 2)
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, results)
+}
+
+func TestPageDraftRepository_Tombstones(t *testing.T) {
+	db := setupPageDraftTestDB(t)
+	repo := NewPageDraftRepository(db)
+	ctx := context.Background()
+
+	createTestPageDraftNamespace(t, db, "ns1", "Namespace 1")
+	createTestPageDraftProject(t, db, "ns1", "proj1", "Project 1")
+
+	deletedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, tombstone := range []model.PageTombstone{
+		{NamespaceCode: "ns1", ProjectCode: "proj1", PageID: 10, Page: &commonTypes.Page{Path: "/first", Content: "first"}, DeletedAt: deletedAt},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", PageID: 11, Page: &commonTypes.Page{Path: "/second", Content: "second"}, DeletedAt: deletedAt.Add(time.Hour)},
+	} {
+		assert.NoError(t, db.Create(&tombstone).Error)
+	}
+
+	tombstone, err := repo.FindTombstone(ctx, "ns1", "proj1", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", tombstone.Content)
+
+	_, err = repo.FindTombstone(ctx, "ns1", "proj1", 12)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	tombstones, total, err := repo.SearchTombstones(ctx, "ns1", "proj1", 0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, tombstones, 2)
+	assert.Equal(t, int64(11), tombstones[0].PageID)
+}
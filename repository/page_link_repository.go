@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type PageLinkRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.PageBrokenLink, error)
+	ReplaceForProject(ctx context.Context, namespaceCode, projectCode string, links []model.PageBrokenLink) error
+}
+
+type pageLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewPageLinkRepository(db *gorm.DB) PageLinkRepository {
+	return &pageLinkRepository{db: db}
+}
+
+func (r *pageLinkRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *pageLinkRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.PageBrokenLink{})
+}
+
+// FindByProject returns the broken links of a project ordered by page path and link
+func (r *pageLinkRepository) FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.PageBrokenLink, error) {
+	var links []model.PageBrokenLink
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Order("page_path, link").
+		Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// ReplaceForProject replaces the broken links of a project by the result of its last scan
+func (r *pageLinkRepository) ReplaceForProject(ctx context.Context, namespaceCode, projectCode string, links []model.PageBrokenLink) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.
+			Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+			Delete(&model.PageBrokenLink{}).Error
+		if err != nil {
+			return err
+		}
+		if len(links) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(links, 500).Error
+	})
+}
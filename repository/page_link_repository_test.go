@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupPageLinkTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&model.PageBrokenLink{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func TestNewPageLinkRepository(t *testing.T) {
+	db := setupPageLinkTestDB(t)
+	repo := NewPageLinkRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestPageLinkRepository_ReplaceForProject(t *testing.T) {
+	db := setupPageLinkTestDB(t)
+	repo := NewPageLinkRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	assert.NoError(t, repo.ReplaceForProject(ctx, "ns1", "proj1", []model.PageBrokenLink{
+		{NamespaceCode: "ns1", ProjectCode: "proj1", PageID: 2, PagePath: "/b.html", Link: "/z", URL: "/z", CheckedAt: now},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", PageID: 1, PagePath: "/a.html", Link: "/y", URL: "/y", CheckedAt: now},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", PageID: 1, PagePath: "/a.html", Link: "/x", URL: "/x", CheckedAt: now},
+	}))
+	assert.NoError(t, repo.ReplaceForProject(ctx, "ns1", "proj2", []model.PageBrokenLink{
+		{NamespaceCode: "ns1", ProjectCode: "proj2", PageID: 3, PagePath: "/c.html", Link: "/w", URL: "/w", CheckedAt: now},
+	}))
+
+	links, err := repo.FindByProject(ctx, "ns1", "proj1")
+	assert.NoError(t, err)
+	assert.Len(t, links, 3)
+	assert.Equal(t, []string{"/x", "/y", "/z"}, []string{links[0].Link, links[1].Link, links[2].Link})
+
+	// The next scan replaces the links of the project only
+	assert.NoError(t, repo.ReplaceForProject(ctx, "ns1", "proj1", nil))
+	links, err = repo.FindByProject(ctx, "ns1", "proj1")
+	assert.NoError(t, err)
+	assert.Empty(t, links)
+	links, err = repo.FindByProject(ctx, "ns1", "proj2")
+	assert.NoError(t, err)
+	assert.Len(t, links, 1)
+}
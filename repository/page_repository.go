@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -14,9 +16,12 @@ type PageRepository interface {
 	FindByID(ctx context.Context, namespaceCode, projectCode string, pageID int64) (*model.Page, error)
 	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.Page, error)
 	FindByProjectPublished(ctx context.Context, namespaceCode, projectCode string, limit, offset int) ([]model.Page, int64, error)
+	FindByProjectPublishedAfter(ctx context.Context, namespaceCode, projectCode string, afterID int64, limit int) ([]model.Page, error)
 	Search(ctx context.Context, query *gorm.DB) ([]model.Page, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Page, int64, error)
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Page, int64, error)
+	SearchCursor(ctx context.Context, query *gorm.DB, afterID int64, limit int) ([]model.Page, bool, error)
 	GetTotalContentSize(ctx context.Context, namespaceCode, projectCode string) (int64, error)
+	GetTotalStoredContentSize(ctx context.Context, namespaceCode, projectCode string) (int64, error)
 }
 
 type pageRepository struct {
@@ -81,12 +86,27 @@ func (r *pageRepository) FindByProjectPublished(ctx context.Context, namespaceCo
 	return pages, total, nil
 }
 
+// FindByProjectPublishedAfter returns the published pages with an id above afterID in the order of their id. The pages
+// are read by keyset, without the cost of the offset on large projects.
+func (r *pageRepository) FindByProjectPublishedAfter(ctx context.Context, namespaceCode, projectCode string, afterID int64, limit int) ([]model.Page, error) {
+	var pages []model.Page
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND is_published = 1 AND id > ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, afterID).
+		Order("id").
+		Limit(limit).
+		Find(&pages).Error
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
 func (r *pageRepository) Search(ctx context.Context, query *gorm.DB) ([]model.Page, error) {
-	pages, _, err := r.SearchPaginate(ctx, query, 0, 0)
+	pages, _, err := r.SearchPaginate(ctx, query, 0, 0, nil)
 	return pages, err
 }
 
-func (r *pageRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Page, int64, error) {
+func (r *pageRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Page, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.Page{})
@@ -96,6 +116,11 @@ func (r *pageRepository) SearchPaginate(ctx context.Context, query *gorm.DB, lim
 		return nil, 0, err
 	}
 
+	query, err := database.ApplyOrderBy(query, model.PageSortableColumns, orderBy, "pages")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit != 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
@@ -108,17 +133,61 @@ func (r *pageRepository) SearchPaginate(ctx context.Context, query *gorm.DB, lim
 	return pages, total, nil
 }
 
+// SearchCursor returns the items following afterID, ordered by id, and whether more items follow
+func (r *pageRepository) SearchCursor(ctx context.Context, query *gorm.DB, afterID int64, limit int) ([]model.Page, bool, error) {
+	if query == nil {
+		query = r.db.WithContext(ctx).Model(&model.Page{})
+	}
+	if afterID > 0 {
+		query = query.Where("pages.id > ?", afterID)
+	}
+
+	var pages []model.Page
+	if err := query.Preload("PageDraft").Order("pages.id").Limit(limit + 1).Find(&pages).Error; err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(pages) > limit
+	if hasMore {
+		pages = pages[:limit]
+	}
+	return pages, hasMore, nil
+}
+
 // GetTotalContentSize returns the projected total content size for a project.
 // It sums:
 // - ContentSize of published pages that don't have a pending draft
 // - ContentSize of all CREATE/UPDATE drafts (which represent the new sizes)
 func (r *pageRepository) GetTotalContentSize(ctx context.Context, namespaceCode, projectCode string) (int64, error) {
+	return r.sumProjectedSize(ctx, "content_size", namespaceCode, projectCode)
+}
+
+// GetTotalStoredContentSize returns the projected total size of the contents as stored in the database,
+// which is smaller than the total content size when the contents are compressed
+func (r *pageRepository) GetTotalStoredContentSize(ctx context.Context, namespaceCode, projectCode string) (int64, error) {
+	return r.sumProjectedSize(ctx, "stored_content_size", namespaceCode, projectCode)
+}
+
+func (r *pageRepository) sumProjectedSize(ctx context.Context, column, namespaceCode, projectCode string) (int64, error) {
 	var totalSize int64
 
-	err := r.db.WithContext(ctx).Raw(`
-		SELECT
+	err := r.db.WithContext(ctx).
+		Raw("SELECT "+projectedPageSizeQuery(column)+" as total_size", namespaceCode, projectCode, namespaceCode, projectCode).
+		Scan(&totalSize).Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return totalSize, nil
+}
+
+// projectedPageSizeQuery returns the expression summing a size column of the published pages without a
+// pending draft and of the CREATE/UPDATE drafts, it takes the namespace and project codes twice as arguments
+func projectedPageSizeQuery(column string) string {
+	return fmt.Sprintf(`
 			COALESCE((
-				SELECT SUM(p.content_size)
+				SELECT SUM(p.%[1]s)
 				FROM pages p
 				WHERE p.namespace_code = ?
 				AND p.project_code = ?
@@ -129,17 +198,10 @@ func (r *pageRepository) GetTotalContentSize(ctx context.Context, namespaceCode,
 				)
 			), 0) +
 			COALESCE((
-				SELECT SUM(pd.content_size)
+				SELECT SUM(pd.%[1]s)
 				FROM page_drafts pd
 				WHERE pd.namespace_code = ?
 				AND pd.project_code = ?
 				AND pd.change_type IN ('CREATE', 'UPDATE')
-			), 0) as total_size
-	`, namespaceCode, projectCode, namespaceCode, projectCode).Scan(&totalSize).Error
-
-	if err != nil {
-		return 0, err
-	}
-
-	return totalSize, nil
-}
\ No newline at end of file
+			), 0)`, column)
+}
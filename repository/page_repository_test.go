@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
@@ -466,6 +467,39 @@ func TestPageRepository_FindByProjectPublished(t *testing.T) {
 	})
 }
 
+func TestPageRepository_FindByProjectPublishedAfter(t *testing.T) {
+	db := setupPageTestDB(t)
+	createTestPageNamespace(t, db, "test-ns", "Test Namespace")
+	createTestPageProject(t, db, "test-ns", "test-proj", "Test Project")
+	createTestPageProject(t, db, "test-ns", "other-proj", "Other Project")
+	repo := NewPageRepository(db)
+	ctx := context.Background()
+
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		page := &model.Page{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: boolPtr(i != 2)}
+		db.Create(page)
+		ids = append(ids, page.ID)
+	}
+	db.Create(&model.Page{NamespaceCode: "test-ns", ProjectCode: "other-proj", IsPublished: boolPtr(true)})
+
+	results, err := repo.FindByProjectPublishedAfter(ctx, "test-ns", "test-proj", 0, 2)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, ids[0], results[0].ID)
+	assert.Equal(t, ids[1], results[1].ID)
+
+	results, err = repo.FindByProjectPublishedAfter(ctx, "test-ns", "test-proj", results[1].ID, 2)
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+	assert.Equal(t, ids[3], results[0].ID)
+	assert.Equal(t, ids[4], results[1].ID)
+
+	results, err = repo.FindByProjectPublishedAfter(ctx, "test-ns", "test-proj", results[1].ID, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
 func TestPageRepository_Search(t *testing.T) {
 	db := setupPageTestDB(t)
 	createTestPageNamespace(t, db, "test-ns", "Test Namespace")
@@ -554,7 +588,7 @@ func TestPageRepository_SearchPaginate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, total, err := repo.SearchPaginate(ctx, tt.query, tt.limit, tt.offset)
+			results, total, err := repo.SearchPaginate(ctx, tt.query, tt.limit, tt.offset, nil)
 
 			assert.NoError(t, err)
 			assert.Len(t, results, tt.wantCount)
@@ -588,7 +622,7 @@ func TestPageRepository_SearchPaginate_WithFilter(t *testing.T) {
 	}
 
 	query := db.Model(&model.Page{}).Where("namespace_code = ? AND project_code = ?", "test-ns", "test-proj")
-	results, total, err := repo.SearchPaginate(ctx, query, 5, 0)
+	results, total, err := repo.SearchPaginate(ctx, query, 5, 0, nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 5)
@@ -622,7 +656,7 @@ func TestPageRepository_SearchPaginate_PreloadsPageDraft(t *testing.T) {
 	}
 	db.Create(draft)
 
-	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0)
+	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0, nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
@@ -927,4 +961,80 @@ func TestPageRepository_GetTotalContentSize(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, int64(450), total)
 	})
-}
\ No newline at end of file
+}
+
+func TestPageRepository_GetTotalStoredContentSize(t *testing.T) {
+	db := setupPageTestDB(t)
+	createTestPageNamespace(t, db, "test-ns", "Test Namespace")
+	createTestPageProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewPageRepository(db)
+	ctx := context.Background()
+
+	db.Create(&model.Page{
+		NamespaceCode:     "test-ns",
+		ProjectCode:       "test-proj",
+		IsPublished:       boolPtr(true),
+		ContentSize:       1000,
+		StoredContentSize: 300,
+	})
+	pageWithDraft := &model.Page{
+		NamespaceCode:     "test-ns",
+		ProjectCode:       "test-proj",
+		IsPublished:       boolPtr(true),
+		ContentSize:       500,
+		StoredContentSize: 500,
+	}
+	db.Create(pageWithDraft)
+	db.Create(&model.PageDraft{
+		NamespaceCode:     "test-ns",
+		ProjectCode:       "test-proj",
+		OldPageID:         &pageWithDraft.ID,
+		ChangeType:        model.DraftChangeTypeUpdate,
+		ContentSize:       2000,
+		StoredContentSize: 400,
+	})
+
+	total, err := repo.GetTotalStoredContentSize(ctx, "test-ns", "test-proj")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(700), total) // 300 (published) + 400 (draft)
+
+	total, err = repo.GetTotalContentSize(ctx, "test-ns", "test-proj")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3000), total)
+}
+
+func TestPageRepository_SearchCursor(t *testing.T) {
+	db := setupPageTestDB(t)
+	createTestPageNamespace(t, db, "test-ns", "Test Namespace")
+	createTestPageProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewPageRepository(db)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		db.Create(&model.Page{NamespaceCode: "test-ns", ProjectCode: "test-proj", Page: &commonTypes.Page{Path: fmt.Sprintf("/page-%d", i)}})
+	}
+
+	var ids []int64
+	afterID := int64(0)
+	for page := 0; page < 3; page++ {
+		results, hasMore, err := repo.SearchCursor(ctx, db.Model(&model.Page{}).Joins("LEFT JOIN page_drafts ON page_drafts.old_page_id = pages.id"), afterID, 2)
+
+		assert.NoError(t, err)
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		if !hasMore {
+			assert.Equal(t, 2, page)
+			assert.Len(t, results, 1)
+			break
+		}
+		assert.Len(t, results, 2)
+		afterID = results[len(results)-1].ID
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+
+	results, hasMore, err := repo.SearchCursor(ctx, nil, 5, 2)
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, results)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type PageTemplateRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	Create(ctx context.Context, template *model.PageTemplate) error
+	Update(ctx context.Context, template *model.PageTemplate) error
+	Delete(ctx context.Context, namespaceCode, code string) error
+	FindByCode(ctx context.Context, namespaceCode, code string) (*model.PageTemplate, error)
+	FindByNamespace(ctx context.Context, namespaceCode string) ([]model.PageTemplate, error)
+}
+
+type pageTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewPageTemplateRepository(db *gorm.DB) PageTemplateRepository {
+	return &pageTemplateRepository{db: db}
+}
+
+func (r *pageTemplateRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *pageTemplateRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.PageTemplate{})
+}
+
+func (r *pageTemplateRepository) Create(ctx context.Context, template *model.PageTemplate) error {
+	return r.db.WithContext(ctx).Create(template).Error
+}
+
+func (r *pageTemplateRepository) Update(ctx context.Context, template *model.PageTemplate) error {
+	return r.db.WithContext(ctx).Save(template).Error
+}
+
+func (r *pageTemplateRepository) Delete(ctx context.Context, namespaceCode, code string) error {
+	return r.db.WithContext(ctx).
+		Where("namespace_code = ? AND code = ?", namespaceCode, code).
+		Delete(&model.PageTemplate{}).Error
+}
+
+func (r *pageTemplateRepository) FindByCode(ctx context.Context, namespaceCode, code string) (*model.PageTemplate, error) {
+	var template model.PageTemplate
+	err := r.db.WithContext(ctx).
+		Where("namespace_code = ? AND code = ?", namespaceCode, code).
+		First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (r *pageTemplateRepository) FindByNamespace(ctx context.Context, namespaceCode string) ([]model.PageTemplate, error) {
+	var templates []model.PageTemplate
+	err := r.db.WithContext(ctx).
+		Where("namespace_code = ?", namespaceCode).
+		Order("code").
+		Find(&templates).Error
+	return templates, err
+}
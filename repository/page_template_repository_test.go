@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupPageTemplateTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.PageTemplate{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, db.Create(&model.Namespace{NamespaceCode: "ns1", Name: "Namespace 1"}).Error)
+	assert.NoError(t, db.Create(&model.Namespace{NamespaceCode: "ns2", Name: "Namespace 2"}).Error)
+
+	return db
+}
+
+func newTestPageTemplate(namespaceCode, code string) *model.PageTemplate {
+	return &model.PageTemplate{
+		NamespaceCode: namespaceCode,
+		Code:          code,
+		Name:          "Template " + code,
+		ContentType:   commonTypes.PageContentTypeTextPlain,
+		Content:       "User-agent: *\nSitemap: https://{{host}}/sitemap.xml",
+	}
+}
+
+func TestNewPageTemplateRepository(t *testing.T) {
+	db := setupPageTemplateTestDB(t)
+	repo := NewPageTemplateRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestPageTemplateRepository_CreateAndFindByCode(t *testing.T) {
+	db := setupPageTemplateTestDB(t)
+	repo := NewPageTemplateRepository(db)
+	ctx := context.Background()
+
+	template := newTestPageTemplate("ns1", "robots")
+	assert.NoError(t, repo.Create(ctx, template))
+	assert.NotZero(t, template.ID)
+
+	found, err := repo.FindByCode(ctx, "ns1", "robots")
+	assert.NoError(t, err)
+	assert.Equal(t, template.ID, found.ID)
+	assert.Equal(t, template.Content, found.Content)
+
+	_, err = repo.FindByCode(ctx, "ns2", "robots")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+	t.Run("code is unique in a namespace", func(t *testing.T) {
+		assert.Error(t, repo.Create(ctx, newTestPageTemplate("ns1", "robots")))
+		assert.NoError(t, repo.Create(ctx, newTestPageTemplate("ns2", "robots")))
+	})
+}
+
+func TestPageTemplateRepository_Update(t *testing.T) {
+	db := setupPageTemplateTestDB(t)
+	repo := NewPageTemplateRepository(db)
+	ctx := context.Background()
+
+	template := newTestPageTemplate("ns1", "robots")
+	assert.NoError(t, repo.Create(ctx, template))
+
+	template.Name = "Robots"
+	template.Content = "User-agent: *"
+	assert.NoError(t, repo.Update(ctx, template))
+
+	found, err := repo.FindByCode(ctx, "ns1", "robots")
+	assert.NoError(t, err)
+	assert.Equal(t, "Robots", found.Name)
+	assert.Equal(t, "User-agent: *", found.Content)
+}
+
+func TestPageTemplateRepository_Delete(t *testing.T) {
+	db := setupPageTemplateTestDB(t)
+	repo := NewPageTemplateRepository(db)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.Create(ctx, newTestPageTemplate("ns1", "robots")))
+	assert.NoError(t, repo.Create(ctx, newTestPageTemplate("ns2", "robots")))
+
+	assert.NoError(t, repo.Delete(ctx, "ns1", "robots"))
+
+	_, err := repo.FindByCode(ctx, "ns1", "robots")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	_, err = repo.FindByCode(ctx, "ns2", "robots")
+	assert.NoError(t, err)
+}
+
+func TestPageTemplateRepository_FindByNamespace(t *testing.T) {
+	db := setupPageTemplateTestDB(t)
+	repo := NewPageTemplateRepository(db)
+	ctx := context.Background()
+
+	assert.NoError(t, repo.Create(ctx, newTestPageTemplate("ns1", "robots")))
+	assert.NoError(t, repo.Create(ctx, newTestPageTemplate("ns1", "maintenance")))
+	assert.NoError(t, repo.Create(ctx, newTestPageTemplate("ns2", "other")))
+
+	templates, err := repo.FindByNamespace(ctx, "ns1")
+	assert.NoError(t, err)
+	assert.Len(t, templates, 2)
+	assert.Equal(t, "maintenance", templates[0].Code)
+	assert.Equal(t, "robots", templates[1].Code)
+
+	templates, err = repo.FindByNamespace(ctx, "unknown")
+	assert.NoError(t, err)
+	assert.Empty(t, templates)
+}
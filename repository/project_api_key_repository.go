@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type ProjectAPIKeyRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	Create(ctx context.Context, key *model.ProjectAPIKey) error
+	Delete(ctx context.Context, namespaceCode, projectCode string, id int64) (bool, error)
+	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectAPIKey, error)
+	FindByName(ctx context.Context, namespaceCode, projectCode, name string) (*model.ProjectAPIKey, error)
+	FindByHash(ctx context.Context, keyHash string) (*model.ProjectAPIKey, error)
+}
+
+type projectAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectAPIKeyRepository(db *gorm.DB) ProjectAPIKeyRepository {
+	return &projectAPIKeyRepository{db: db}
+}
+
+func (r *projectAPIKeyRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *projectAPIKeyRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.ProjectAPIKey{})
+}
+
+func (r *projectAPIKeyRepository) Create(ctx context.Context, key *model.ProjectAPIKey) error {
+	return r.db.WithContext(ctx).Create(key).Error
+}
+
+func (r *projectAPIKeyRepository) Delete(ctx context.Context, namespaceCode, projectCode string, id int64) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND id = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, id).
+		Delete(&model.ProjectAPIKey{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindByProject returns the keys of a project, ordered by name
+func (r *projectAPIKeyRepository) FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectAPIKey, error) {
+	var keys []model.ProjectAPIKey
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Order("name").
+		Find(&keys).Error
+	return keys, err
+}
+
+func (r *projectAPIKeyRepository) FindByName(ctx context.Context, namespaceCode, projectCode, name string) (*model.ProjectAPIKey, error) {
+	var key model.ProjectAPIKey
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND name = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, name).
+		First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *projectAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*model.ProjectAPIKey, error) {
+	var key model.ProjectAPIKey
+	err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
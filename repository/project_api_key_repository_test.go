@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupProjectAPIKeyTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ProjectAPIKey{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewProjectAPIKeyRepository(t *testing.T) {
+	db := setupProjectAPIKeyTestDB(t)
+	repo := NewProjectAPIKeyRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestProjectAPIKeyRepository(t *testing.T) {
+	db := setupProjectAPIKeyTestDB(t)
+	repo := NewProjectAPIKeyRepository(db)
+	ctx := context.Background()
+
+	ci := &model.ProjectAPIKey{
+		NamespaceCode: "ns1", ProjectCode: "proj1", Name: "ci", KeyHash: "hash-ci", KeyPreview: "flectopk_ci",
+		Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead, model.ProjectAPIKeyScopeWrite},
+	}
+	require.NoError(t, repo.Create(ctx, ci))
+	assert.NotZero(t, ci.ID)
+	require.NoError(t, repo.Create(ctx, &model.ProjectAPIKey{
+		NamespaceCode: "ns1", ProjectCode: "proj1", Name: "backup", KeyHash: "hash-backup", KeyPreview: "flectopk_backup",
+		Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead},
+	}))
+	require.NoError(t, repo.Create(ctx, &model.ProjectAPIKey{
+		NamespaceCode: "ns1", ProjectCode: "proj2", Name: "ci", KeyHash: "hash-ci-2", KeyPreview: "flectopk_ci2",
+		Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead},
+	}))
+
+	t.Run("unique name per project", func(t *testing.T) {
+		err := repo.Create(ctx, &model.ProjectAPIKey{
+			NamespaceCode: "ns1", ProjectCode: "proj1", Name: "ci", KeyHash: "hash-other", KeyPreview: "flectopk_other",
+			Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("find by project", func(t *testing.T) {
+		keys, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		require.Len(t, keys, 2)
+		assert.Equal(t, "backup", keys[0].Name)
+		assert.Equal(t, "ci", keys[1].Name)
+		assert.Equal(t, []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead, model.ProjectAPIKeyScopeWrite}, keys[1].Scopes)
+	})
+
+	t.Run("find by name and hash", func(t *testing.T) {
+		key, err := repo.FindByName(ctx, "ns1", "proj2", "ci")
+		require.NoError(t, err)
+		assert.Equal(t, "hash-ci-2", key.KeyHash)
+
+		key, err = repo.FindByHash(ctx, "hash-ci")
+		require.NoError(t, err)
+		assert.Equal(t, ci.ID, key.ID)
+
+		_, err = repo.FindByHash(ctx, "unknown")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleted, err := repo.Delete(ctx, "ns1", "proj2", ci.ID)
+		require.NoError(t, err)
+		assert.False(t, deleted)
+
+		deleted, err = repo.Delete(ctx, "ns1", "proj1", ci.ID)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+
+		_, err = repo.FindByName(ctx, "ns1", "proj1", "ci")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type ProjectGitSyncRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	FindByProject(ctx context.Context, namespaceCode, projectCode string) (*model.ProjectGitSync, error)
+	FindPolled(ctx context.Context) ([]model.ProjectGitSync, error)
+	Save(ctx context.Context, sync *model.ProjectGitSync) error
+	UpdateStatus(ctx context.Context, sync *model.ProjectGitSync) error
+	Delete(ctx context.Context, namespaceCode, projectCode string) (bool, error)
+}
+
+type projectGitSyncRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectGitSyncRepository(db *gorm.DB) ProjectGitSyncRepository {
+	return &projectGitSyncRepository{db: db}
+}
+
+func (r *projectGitSyncRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *projectGitSyncRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.ProjectGitSync{})
+}
+
+func (r *projectGitSyncRepository) FindByProject(ctx context.Context, namespaceCode, projectCode string) (*model.ProjectGitSync, error) {
+	var sync model.ProjectGitSync
+	err := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		First(&sync).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sync, nil
+}
+
+// FindPolled returns the syncs of the projects polling their repository
+func (r *projectGitSyncRepository) FindPolled(ctx context.Context) ([]model.ProjectGitSync, error) {
+	var syncs []model.ProjectGitSync
+	err := r.db.WithContext(ctx).
+		Where("poll = ?", true).
+		Order("namespace_code, project_code").
+		Find(&syncs).Error
+	return syncs, err
+}
+
+func (r *projectGitSyncRepository) Save(ctx context.Context, sync *model.ProjectGitSync) error {
+	return r.db.WithContext(ctx).Omit("Project").Save(sync).Error
+}
+
+// UpdateStatus records the outcome of the last sync, leaving the settings changed meanwhile untouched
+func (r *projectGitSyncRepository) UpdateStatus(ctx context.Context, sync *model.ProjectGitSync) error {
+	return r.db.WithContext(ctx).
+		Model(&model.ProjectGitSync{}).
+		Where("id = ?", sync.ID).
+		Updates(map[string]any{
+			"last_commit":    sync.LastCommit,
+			"last_synced_at": sync.LastSyncedAt,
+			"last_error":     sync.LastError,
+		}).Error
+}
+
+func (r *projectGitSyncRepository) Delete(ctx context.Context, namespaceCode, projectCode string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Delete(&model.ProjectGitSync{})
+	return result.RowsAffected > 0, result.Error
+}
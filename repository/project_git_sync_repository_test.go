@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupProjectGitSyncTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ProjectGitSync{})
+	require.NoError(t, err)
+
+	return db
+}
+
+func TestNewProjectGitSyncRepository(t *testing.T) {
+	db := setupProjectGitSyncTestDB(t)
+	repo := NewProjectGitSyncRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestProjectGitSyncRepository(t *testing.T) {
+	db := setupProjectGitSyncTestDB(t)
+	repo := NewProjectGitSyncRepository(db)
+	ctx := context.Background()
+
+	polled := &model.ProjectGitSync{NamespaceCode: "ns1", ProjectCode: "proj1", RepoURL: "https://git.example.com/redirects.git", Branch: "main", Poll: true, WebhookSecret: "secret"}
+	require.NoError(t, repo.Save(ctx, polled))
+	assert.NotZero(t, polled.ID)
+	require.NoError(t, repo.Save(ctx, &model.ProjectGitSync{NamespaceCode: "ns1", ProjectCode: "proj2", RepoURL: "https://git.example.com/other.git", Branch: "main"}))
+
+	t.Run("find by project", func(t *testing.T) {
+		found, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		assert.Equal(t, "main", found.Branch)
+		assert.Equal(t, "secret", found.WebhookSecret)
+
+		_, err = repo.FindByProject(ctx, "ns1", "proj3")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("find polled", func(t *testing.T) {
+		syncs, err := repo.FindPolled(ctx)
+		require.NoError(t, err)
+		require.Len(t, syncs, 1)
+		assert.Equal(t, "proj1", syncs[0].ProjectCode)
+	})
+
+	t.Run("update status keeps the settings", func(t *testing.T) {
+		now := time.Now()
+		stale := *polled
+		stale.Branch = "stale"
+		stale.LastCommit = "abc123"
+		stale.LastSyncedAt = &now
+		require.NoError(t, repo.UpdateStatus(ctx, &stale))
+
+		found, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		assert.Equal(t, "main", found.Branch)
+		assert.Equal(t, "abc123", found.LastCommit)
+		assert.NotNil(t, found.LastSyncedAt)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		deleted, err := repo.Delete(ctx, "ns1", "proj2")
+		require.NoError(t, err)
+		assert.True(t, deleted)
+
+		deleted, err = repo.Delete(ctx, "ns1", "proj2")
+		require.NoError(t, err)
+		assert.False(t, deleted)
+	})
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+type ProjectMemberRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	Create(ctx context.Context, member *model.ProjectMember) error
+	UpdateRole(ctx context.Context, id int64, role model.ProjectMemberRole) error
+	Delete(ctx context.Context, id int64) error
+	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectMember, error)
+	FindByProjectAndUser(ctx context.Context, namespaceCode, projectCode string, userID int64) (*model.ProjectMember, error)
+	FindByUser(ctx context.Context, userID int64) ([]model.ProjectMember, error)
+	// TransferOwnership makes the user the owner of the project, adding them as a member when they are not one, and
+	// demotes the previous owner to editor
+	TransferOwnership(ctx context.Context, namespaceCode, projectCode string, userID int64, transferredBy string) error
+}
+
+type projectMemberRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectMemberRepository(db *gorm.DB) ProjectMemberRepository {
+	return &projectMemberRepository{db: db}
+}
+
+func (r *projectMemberRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *projectMemberRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.ProjectMember{})
+}
+
+func (r *projectMemberRepository) Create(ctx context.Context, member *model.ProjectMember) error {
+	return r.db.WithContext(ctx).Omit("User").Create(member).Error
+}
+
+func (r *projectMemberRepository) UpdateRole(ctx context.Context, id int64, role model.ProjectMemberRole) error {
+	return r.db.WithContext(ctx).Model(&model.ProjectMember{}).Where("id = ?", id).Update("role", role).Error
+}
+
+func (r *projectMemberRepository) Delete(ctx context.Context, id int64) error {
+	return r.db.WithContext(ctx).Delete(&model.ProjectMember{}, id).Error
+}
+
+// FindByProject returns the members of a project with their user, the owner first
+func (r *projectMemberRepository) FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectMember, error) {
+	var members []model.ProjectMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Order(fmt.Sprintf("CASE role WHEN '%s' THEN 0 WHEN '%s' THEN 1 ELSE 2 END, id", model.ProjectMemberRoleOwner, model.ProjectMemberRoleEditor)).
+		Find(&members).Error
+	return members, err
+}
+
+func (r *projectMemberRepository) FindByProjectAndUser(ctx context.Context, namespaceCode, projectCode string, userID int64) (*model.ProjectMember, error) {
+	var member model.ProjectMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND user_id = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// FindByUser returns the memberships of a user in all the projects
+func (r *projectMemberRepository) FindByUser(ctx context.Context, userID int64) ([]model.ProjectMember, error) {
+	var members []model.ProjectMember
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&members).Error
+	return members, err
+}
+
+func (r *projectMemberRepository) TransferOwnership(ctx context.Context, namespaceCode, projectCode string, userID int64, transferredBy string) error {
+	// The members are stored in the primary database, the transaction must not be started on the shard of the namespace
+	return r.db.WithContext(database.WithNamespace(ctx, "")).Transaction(func(tx *gorm.DB) error {
+		projectScope := fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode)
+		if err := tx.Model(&model.ProjectMember{}).
+			Where(projectScope+" AND role = ? AND user_id <> ?", namespaceCode, projectCode, model.ProjectMemberRoleOwner, userID).
+			Update("role", model.ProjectMemberRoleEditor).Error; err != nil {
+			return err
+		}
+
+		var member model.ProjectMember
+		err := tx.Where(projectScope+" AND user_id = ?", namespaceCode, projectCode, userID).First(&member).Error
+		if err == nil {
+			return tx.Model(&member).Update("role", model.ProjectMemberRoleOwner).Error
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return tx.Omit("User").Create(&model.ProjectMember{
+			NamespaceCode: namespaceCode,
+			ProjectCode:   projectCode,
+			UserID:        userID,
+			Role:          model.ProjectMemberRoleOwner,
+			CreatedBy:     transferredBy,
+		}).Error
+	})
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupProjectMemberTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.User{}, &model.ProjectMember{})
+	require.NoError(t, err)
+
+	for _, username := range []string{"alice", "bob", "carol"} {
+		require.NoError(t, db.Create(&model.User{Username: username, Firstname: username, Lastname: username}).Error)
+	}
+	return db
+}
+
+func TestNewProjectMemberRepository(t *testing.T) {
+	db := setupProjectMemberTestDB(t)
+	repo := NewProjectMemberRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestProjectMemberRepository(t *testing.T) {
+	db := setupProjectMemberTestDB(t)
+	repo := NewProjectMemberRepository(db)
+	ctx := context.Background()
+
+	viewer := &model.ProjectMember{NamespaceCode: "ns1", ProjectCode: "proj1", UserID: 1, Role: model.ProjectMemberRoleViewer}
+	require.NoError(t, repo.Create(ctx, viewer))
+	assert.NotZero(t, viewer.ID)
+	require.NoError(t, repo.Create(ctx, &model.ProjectMember{NamespaceCode: "ns1", ProjectCode: "proj1", UserID: 2, Role: model.ProjectMemberRoleOwner}))
+	require.NoError(t, repo.Create(ctx, &model.ProjectMember{NamespaceCode: "ns1", ProjectCode: "proj2", UserID: 1, Role: model.ProjectMemberRoleEditor}))
+
+	t.Run("unique user per project", func(t *testing.T) {
+		err := repo.Create(ctx, &model.ProjectMember{NamespaceCode: "ns1", ProjectCode: "proj1", UserID: 1, Role: model.ProjectMemberRoleEditor})
+		assert.Error(t, err)
+	})
+
+	t.Run("find by project lists the owner first", func(t *testing.T) {
+		members, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, model.ProjectMemberRoleOwner, members[0].Role)
+		assert.Equal(t, "bob", members[0].User.Username)
+		assert.Equal(t, "alice", members[1].User.Username)
+	})
+
+	t.Run("find by project and user", func(t *testing.T) {
+		member, err := repo.FindByProjectAndUser(ctx, "ns1", "proj2", 1)
+		require.NoError(t, err)
+		assert.Equal(t, model.ProjectMemberRoleEditor, member.Role)
+		assert.Equal(t, "alice", member.User.Username)
+
+		_, err = repo.FindByProjectAndUser(ctx, "ns1", "proj2", 2)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("find by user", func(t *testing.T) {
+		members, err := repo.FindByUser(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, "proj1", members[0].ProjectCode)
+		assert.Equal(t, "proj2", members[1].ProjectCode)
+	})
+
+	t.Run("update role", func(t *testing.T) {
+		require.NoError(t, repo.UpdateRole(ctx, viewer.ID, model.ProjectMemberRoleEditor))
+
+		member, err := repo.FindByProjectAndUser(ctx, "ns1", "proj1", 1)
+		require.NoError(t, err)
+		assert.Equal(t, model.ProjectMemberRoleEditor, member.Role)
+	})
+
+	t.Run("transfer ownership to a member", func(t *testing.T) {
+		require.NoError(t, repo.TransferOwnership(ctx, "ns1", "proj1", 1, "bob"))
+
+		members, err := repo.FindByProject(ctx, "ns1", "proj1")
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, "alice", members[0].User.Username)
+		assert.Equal(t, model.ProjectMemberRoleOwner, members[0].Role)
+		assert.Equal(t, "bob", members[1].User.Username)
+		assert.Equal(t, model.ProjectMemberRoleEditor, members[1].Role)
+	})
+
+	t.Run("transfer ownership to a user who is not a member", func(t *testing.T) {
+		require.NoError(t, repo.TransferOwnership(ctx, "ns1", "proj1", 3, "alice"))
+
+		member, err := repo.FindByProjectAndUser(ctx, "ns1", "proj1", 3)
+		require.NoError(t, err)
+		assert.Equal(t, model.ProjectMemberRoleOwner, member.Role)
+		assert.Equal(t, "alice", member.CreatedBy)
+
+		previous, err := repo.FindByProjectAndUser(ctx, "ns1", "proj1", 1)
+		require.NoError(t, err)
+		assert.Equal(t, model.ProjectMemberRoleEditor, previous.Role)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, viewer.ID))
+
+		_, err := repo.FindByProjectAndUser(ctx, "ns1", "proj1", 1)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -19,11 +22,26 @@ type ProjectRepository interface {
 	FindAll(ctx context.Context) ([]model.Project, error)
 	FindByNamespace(ctx context.Context, namespaceCode string) ([]model.Project, error)
 	Search(ctx context.Context, query *gorm.DB) ([]model.Project, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Project, int64, error)
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Project, int64, error)
 	CountRedirects(ctx context.Context, namespaceCode, projectCode string) (int64, error)
 	CountRedirectDrafts(ctx context.Context, namespaceCode, projectCode string) (int64, error)
 	CountPages(ctx context.Context, namespaceCode, projectCode string) (int64, error)
 	CountPageDrafts(ctx context.Context, namespaceCode, projectCode string) (int64, error)
+	// CountByProjects returns the counts of several projects of a namespace indexed by project code, the
+	// projects without redirects, pages or drafts being returned with zero counts
+	CountByProjects(ctx context.Context, namespaceCode string, projectCodes []string) (map[string]model.ProjectCounts, error)
+	// CountsByNamespace returns the counts of all the projects of a namespace ordered by project code
+	CountsByNamespace(ctx context.Context, namespaceCode string) ([]model.ProjectCounts, error)
+	GetStats(ctx context.Context, namespaceCode, projectCode string) (*model.ProjectStats, error)
+	FindEnvironment(ctx context.Context, namespaceCode, projectCode string, environment commonTypes.Environment) (*model.ProjectEnvironment, error)
+	FindEnvironments(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectEnvironment, error)
+}
+
+// ProjectGlobalModels are the models attached to a project stored in the global tables, see database.GlobalTables.
+// Their project may be stored in a shard, so they have no foreign key to it and are deleted with it by the repository.
+var ProjectGlobalModels = []interface{}{
+	&model.ProjectMember{},
+	&model.ProjectAPIKey{},
 }
 
 type projectRepository struct {
@@ -51,13 +69,24 @@ func (r *projectRepository) Update(ctx context.Context, project *model.Project)
 }
 
 func (r *projectRepository) Delete(ctx context.Context, namespaceCode, projectCode string) error {
-	return r.db.WithContext(ctx).
-		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
-		Delete(&model.Project{}).Error
+	return r.delete(ctx, "namespace_code = ? AND project_code = ?", namespaceCode, projectCode)
 }
 
 func (r *projectRepository) DeleteByNamespaceCode(ctx context.Context, namespaceCode string) error {
-	return r.db.WithContext(ctx).Where("namespace_code = ?", namespaceCode).Delete(&model.Project{}).Error
+	return r.delete(ctx, "namespace_code = ?", namespaceCode)
+}
+
+// delete deletes the projects matching the condition, then their rows of the global tables
+func (r *projectRepository) delete(ctx context.Context, query string, args ...interface{}) error {
+	if err := r.db.WithContext(ctx).Where(query, args...).Delete(&model.Project{}).Error; err != nil {
+		return err
+	}
+	for _, global := range ProjectGlobalModels {
+		if err := r.db.WithContext(ctx).Where(query, args...).Delete(global).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *projectRepository) FindByCode(ctx context.Context, namespaceCode, projectCode string) (*model.Project, error) {
@@ -96,11 +125,11 @@ func (r *projectRepository) FindByNamespace(ctx context.Context, namespaceCode s
 }
 
 func (r *projectRepository) Search(ctx context.Context, query *gorm.DB) ([]model.Project, error) {
-	projects, _, err := r.SearchPaginate(ctx, query, 0, 0)
+	projects, _, err := r.SearchPaginate(ctx, query, 0, 0, nil)
 	return projects, err
 }
 
-func (r *projectRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Project, int64, error) {
+func (r *projectRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Project, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.Project{})
@@ -110,6 +139,11 @@ func (r *projectRepository) SearchPaginate(ctx context.Context, query *gorm.DB,
 		return nil, 0, err
 	}
 
+	query, err := database.ApplyOrderBy(query, model.ProjectSortableColumns, orderBy, "projects")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit != 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
@@ -157,3 +191,168 @@ func (r *projectRepository) CountPageDrafts(ctx context.Context, namespaceCode,
 		Count(&count).Error
 	return count, err
 }
+
+func (r *projectRepository) CountByProjects(ctx context.Context, namespaceCode string, projectCodes []string) (map[string]model.ProjectCounts, error) {
+	counts := make(map[string]model.ProjectCounts, len(projectCodes))
+	for _, projectCode := range projectCodes {
+		counts[projectCode] = model.ProjectCounts{ProjectCode: projectCode}
+	}
+	if len(projectCodes) == 0 {
+		return counts, nil
+	}
+
+	projectCounts, err := r.countProjects(ctx, namespaceCode, projectCodes)
+	if err != nil {
+		return nil, err
+	}
+	for _, projectCount := range projectCounts {
+		counts[projectCount.ProjectCode] = projectCount
+	}
+	return counts, nil
+}
+
+func (r *projectRepository) CountsByNamespace(ctx context.Context, namespaceCode string) ([]model.ProjectCounts, error) {
+	return r.countProjects(ctx, namespaceCode, nil)
+}
+
+// countProjects counts the redirects, pages and drafts of the projects of a namespace in a single grouped query,
+// limited to projectCodes when not nil, each table being scanned once
+func (r *projectRepository) countProjects(ctx context.Context, namespaceCode string, projectCodes []string) ([]model.ProjectCounts, error) {
+	filter := func(alias string) (string, []any) {
+		if projectCodes == nil {
+			return fmt.Sprintf("%[1]snamespace_code = ?", alias), []any{namespaceCode}
+		}
+		return fmt.Sprintf("%[1]snamespace_code = ? AND %[1]sproject_code IN ?", alias), []any{namespaceCode, projectCodes}
+	}
+	tableFilter, tableArgs := filter("")
+	projectFilter, projectArgs := filter("p.")
+
+	var args []any
+	for range 4 {
+		args = append(args, tableArgs...)
+	}
+	args = append(args, projectArgs...)
+
+	var counts []model.ProjectCounts
+	err := r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT
+			p.project_code,
+			COALESCE(SUM(c.redirects), 0) AS redirects,
+			COALESCE(SUM(c.redirect_drafts), 0) AS redirect_drafts,
+			COALESCE(SUM(c.pages), 0) AS pages,
+			COALESCE(SUM(c.page_drafts), 0) AS page_drafts
+		FROM projects p
+		LEFT JOIN (
+			SELECT project_code, COUNT(*) AS redirects, 0 AS redirect_drafts, 0 AS pages, 0 AS page_drafts
+			FROM redirects WHERE %[1]s GROUP BY project_code
+			UNION ALL
+			SELECT project_code, 0, COUNT(*), 0, 0
+			FROM redirect_drafts WHERE %[1]s GROUP BY project_code
+			UNION ALL
+			SELECT project_code, 0, 0, COUNT(*), 0
+			FROM pages WHERE %[1]s GROUP BY project_code
+			UNION ALL
+			SELECT project_code, 0, 0, 0, COUNT(*)
+			FROM page_drafts WHERE %[1]s GROUP BY project_code
+		) c ON c.project_code = p.project_code
+		WHERE %[2]s
+		GROUP BY p.project_code
+		ORDER BY p.project_code
+	`, tableFilter, projectFilter), args...).Scan(&counts).Error
+	return counts, err
+}
+
+// GetStats computes the statistics of a project in a single query, each table being scanned once,
+// gorm.ErrRecordNotFound is returned when the project does not exist
+func (r *projectRepository) GetStats(ctx context.Context, namespaceCode, projectCode string) (*model.ProjectStats, error) {
+	var stats model.ProjectStats
+	result := r.db.WithContext(ctx).Raw(fmt.Sprintf(`
+		SELECT
+			p.version, p.published_at,
+			r.*, rd.*, pg.*, pd.*,
+			%s AS page_content_size
+		FROM projects p
+		CROSS JOIN (
+			SELECT
+				COUNT(*) AS redirect_total,
+				COALESCE(SUM(CASE WHEN type = ? THEN 1 ELSE 0 END), 0) AS redirect_count_basic,
+				COALESCE(SUM(CASE WHEN type = ? THEN 1 ELSE 0 END), 0) AS redirect_count_basic_host,
+				COALESCE(SUM(CASE WHEN type = ? THEN 1 ELSE 0 END), 0) AS redirect_count_regex,
+				COALESCE(SUM(CASE WHEN type = ? THEN 1 ELSE 0 END), 0) AS redirect_count_regex_host,
+				COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS redirect_count_moved_permanent,
+				COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS redirect_count_found,
+				COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS redirect_count_temporary,
+				COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS redirect_count_permanent
+			FROM redirects
+			WHERE namespace_code = ? AND project_code = ?
+		) r
+		CROSS JOIN (
+			SELECT
+				COUNT(*) AS redirect_draft_total,
+				COALESCE(SUM(CASE WHEN change_type = ? THEN 1 ELSE 0 END), 0) AS redirect_draft_count_create,
+				COALESCE(SUM(CASE WHEN change_type = ? THEN 1 ELSE 0 END), 0) AS redirect_draft_count_update,
+				COALESCE(SUM(CASE WHEN change_type = ? THEN 1 ELSE 0 END), 0) AS redirect_draft_count_delete
+			FROM redirect_drafts
+			WHERE namespace_code = ? AND project_code = ?
+		) rd
+		CROSS JOIN (
+			SELECT
+				COUNT(*) AS page_total,
+				COALESCE(SUM(CASE WHEN type = ? THEN 1 ELSE 0 END), 0) AS page_count_basic,
+				COALESCE(SUM(CASE WHEN type = ? THEN 1 ELSE 0 END), 0) AS page_count_basic_host
+			FROM pages
+			WHERE namespace_code = ? AND project_code = ?
+		) pg
+		CROSS JOIN (
+			SELECT
+				COUNT(*) AS page_draft_total,
+				COALESCE(SUM(CASE WHEN change_type = ? THEN 1 ELSE 0 END), 0) AS page_draft_count_create,
+				COALESCE(SUM(CASE WHEN change_type = ? THEN 1 ELSE 0 END), 0) AS page_draft_count_update,
+				COALESCE(SUM(CASE WHEN change_type = ? THEN 1 ELSE 0 END), 0) AS page_draft_count_delete
+			FROM page_drafts
+			WHERE namespace_code = ? AND project_code = ?
+		) pd
+		WHERE p.namespace_code = ? AND p.project_code = ?
+	`, projectedPageSizeQuery("content_size")),
+		namespaceCode, projectCode, namespaceCode, projectCode,
+		commonTypes.RedirectTypeBasic, commonTypes.RedirectTypeBasicHost, commonTypes.RedirectTypeRegex, commonTypes.RedirectTypeRegexHost,
+		commonTypes.RedirectStatusMovedPermanent, commonTypes.RedirectStatusFound, commonTypes.RedirectStatusTemporary, commonTypes.RedirectStatusPermanent,
+		namespaceCode, projectCode,
+		model.DraftChangeTypeCreate, model.DraftChangeTypeUpdate, model.DraftChangeTypeDelete,
+		namespaceCode, projectCode,
+		commonTypes.PageTypeBasic, commonTypes.PageTypeBasicHost,
+		namespaceCode, projectCode,
+		model.DraftChangeTypeCreate, model.DraftChangeTypeUpdate, model.DraftChangeTypeDelete,
+		namespaceCode, projectCode,
+		namespaceCode, projectCode,
+	).Scan(&stats)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &stats, nil
+}
+
+func (r *projectRepository) FindEnvironment(ctx context.Context, namespaceCode, projectCode string, environment commonTypes.Environment) (*model.ProjectEnvironment, error) {
+	var projectEnvironment model.ProjectEnvironment
+	err := r.db.WithContext(ctx).
+		Where("namespace_code = ? AND project_code = ? AND environment = ?", namespaceCode, projectCode, environment).
+		First(&projectEnvironment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &projectEnvironment, nil
+}
+
+// FindEnvironments returns the environments of a project without their snapshot content
+func (r *projectRepository) FindEnvironments(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectEnvironment, error) {
+	var projectEnvironments []model.ProjectEnvironment
+	err := r.db.WithContext(ctx).
+		Omit("redirects", "pages").
+		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
+		Order("environment").
+		Find(&projectEnvironments).Error
+	return projectEnvironments, err
+}
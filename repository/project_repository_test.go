@@ -3,7 +3,9 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/driver/sqlite"
@@ -14,7 +16,7 @@ func setupProjectTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{}, &model.ProjectEnvironment{}, &model.User{}, &model.ProjectMember{}, &model.ProjectAPIKey{})
 	assert.NoError(t, err)
 
 	return db
@@ -243,10 +245,20 @@ func TestProjectRepository_DeleteByNamespaceCode(t *testing.T) {
 	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-1", NamespaceCode: "ns-to-delete", Name: "Project 1"})
 	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-2", NamespaceCode: "ns-to-delete", Name: "Project 2"})
 	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-3", NamespaceCode: "ns-to-keep", Name: "Project 3"})
+	user := &model.User{Username: "member", Firstname: "Member", Lastname: "Member"}
+	_ = db.Create(user)
+	_ = db.Create(&model.ProjectMember{NamespaceCode: "ns-to-delete", ProjectCode: "proj-1", UserID: user.ID, Role: model.ProjectMemberRoleEditor})
+	_ = db.Create(&model.ProjectMember{NamespaceCode: "ns-to-keep", ProjectCode: "proj-3", UserID: user.ID, Role: model.ProjectMemberRoleEditor})
 
 	err := repo.DeleteByNamespaceCode(ctx, "ns-to-delete")
 	assert.NoError(t, err)
 
+	// The members have no foreign key to their project and are deleted with it
+	var members []model.ProjectMember
+	assert.NoError(t, db.Find(&members).Error)
+	assert.Len(t, members, 1)
+	assert.Equal(t, "proj-3", members[0].ProjectCode)
+
 	projects, err := repo.FindByNamespace(ctx, "ns-to-delete")
 	assert.NoError(t, err)
 	assert.Empty(t, projects)
@@ -512,7 +524,7 @@ func TestProjectRepository_SearchPaginate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, total, err := repo.SearchPaginate(ctx, tt.query, tt.limit, tt.offset)
+			results, total, err := repo.SearchPaginate(ctx, tt.query, tt.limit, tt.offset, nil)
 
 			assert.NoError(t, err)
 			assert.Len(t, results, tt.wantCount)
@@ -529,7 +541,7 @@ func TestProjectRepository_SearchPaginate_PreloadsNamespace(t *testing.T) {
 
 	_ = repo.Create(ctx, &model.Project{ProjectCode: "preload-test", NamespaceCode: "test-ns", Name: "Preload Test"})
 
-	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0)
+	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0, nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
@@ -668,3 +680,229 @@ func TestProjectRepository_CountPageDrafts(t *testing.T) {
 		assert.Equal(t, int64(0), count)
 	})
 }
+
+func TestProjectRepository_CountByProjects(t *testing.T) {
+	db := setupProjectTestDB(t)
+	createTestNamespace(t, db, "test-ns", "Test Namespace")
+	createTestNamespace(t, db, "other-ns", "Other Namespace")
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-1", NamespaceCode: "test-ns", Name: "Project 1"})
+	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-2", NamespaceCode: "test-ns", Name: "Project 2"})
+	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-1", NamespaceCode: "other-ns", Name: "Project 1"})
+
+	isPublished := true
+	_ = db.Create(&model.Redirect{NamespaceCode: "test-ns", ProjectCode: "proj-1", IsPublished: &isPublished}).Error
+	_ = db.Create(&model.Redirect{NamespaceCode: "test-ns", ProjectCode: "proj-1", IsPublished: &isPublished}).Error
+	_ = db.Create(&model.Redirect{NamespaceCode: "other-ns", ProjectCode: "proj-1", IsPublished: &isPublished}).Error
+	_ = db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "proj-2", ChangeType: model.DraftChangeTypeCreate}).Error
+	_ = db.Create(&model.Page{NamespaceCode: "test-ns", ProjectCode: "proj-2", IsPublished: &isPublished}).Error
+	_ = db.Create(&model.PageDraft{NamespaceCode: "test-ns", ProjectCode: "proj-1", ChangeType: model.DraftChangeTypeCreate}).Error
+	_ = db.Create(&model.PageDraft{NamespaceCode: "test-ns", ProjectCode: "proj-1", ChangeType: model.DraftChangeTypeDelete}).Error
+
+	t.Run("count several projects", func(t *testing.T) {
+		counts, err := repo.CountByProjects(ctx, "test-ns", []string{"proj-1", "proj-2", "non-existing"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]model.ProjectCounts{
+			"proj-1":       {ProjectCode: "proj-1", Redirects: 2, PageDrafts: 2},
+			"proj-2":       {ProjectCode: "proj-2", RedirectDrafts: 1, Pages: 1},
+			"non-existing": {ProjectCode: "non-existing"},
+		}, counts)
+	})
+
+	t.Run("count a namespace", func(t *testing.T) {
+		counts, err := repo.CountsByNamespace(ctx, "test-ns")
+		assert.NoError(t, err)
+		assert.Equal(t, []model.ProjectCounts{
+			{ProjectCode: "proj-1", Redirects: 2, PageDrafts: 2},
+			{ProjectCode: "proj-2", RedirectDrafts: 1, Pages: 1},
+		}, counts)
+
+		counts, err = repo.CountsByNamespace(ctx, "other-ns")
+		assert.NoError(t, err)
+		assert.Equal(t, []model.ProjectCounts{{ProjectCode: "proj-1", Redirects: 1}}, counts)
+	})
+
+	t.Run("no projects", func(t *testing.T) {
+		counts, err := repo.CountByProjects(ctx, "test-ns", nil)
+		assert.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+}
+
+func TestProjectRepository_GetStats(t *testing.T) {
+	db := setupProjectTestDB(t)
+	createTestNamespace(t, db, "test-ns", "Test Namespace")
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	publishedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-1", NamespaceCode: "test-ns", Name: "Project 1", Version: 3, PublishedAt: publishedAt})
+	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-2", NamespaceCode: "test-ns", Name: "Project 2"})
+	_ = repo.Create(ctx, &model.Project{ProjectCode: "empty", NamespaceCode: "test-ns", Name: "Empty"})
+
+	redirect := func(projectCode string, redirectType commonTypes.RedirectType, status commonTypes.RedirectStatus) *model.Redirect {
+		return &model.Redirect{NamespaceCode: "test-ns", ProjectCode: projectCode, Redirect: &commonTypes.Redirect{Type: redirectType, Status: status}}
+	}
+	_ = db.Create(redirect("proj-1", commonTypes.RedirectTypeBasic, commonTypes.RedirectStatusMovedPermanent)).Error
+	_ = db.Create(redirect("proj-1", commonTypes.RedirectTypeBasic, commonTypes.RedirectStatusFound)).Error
+	_ = db.Create(redirect("proj-1", commonTypes.RedirectTypeRegex, commonTypes.RedirectStatusMovedPermanent)).Error
+	_ = db.Create(redirect("proj-1", commonTypes.RedirectTypeRegexHost, commonTypes.RedirectStatusPermanent)).Error
+	_ = db.Create(redirect("proj-2", commonTypes.RedirectTypeBasicHost, commonTypes.RedirectStatusTemporary)).Error
+
+	_ = db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "proj-1", ChangeType: model.DraftChangeTypeCreate}).Error
+	_ = db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "proj-1", ChangeType: model.DraftChangeTypeDelete}).Error
+	_ = db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "proj-2", ChangeType: model.DraftChangeTypeUpdate}).Error
+
+	published := &model.Page{NamespaceCode: "test-ns", ProjectCode: "proj-1", IsPublished: boolPtr(true), ContentSize: 100, Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/a"}}
+	_ = db.Create(published).Error
+	_ = db.Create(&model.Page{NamespaceCode: "test-ns", ProjectCode: "proj-1", IsPublished: boolPtr(true), ContentSize: 50, Page: &commonTypes.Page{Type: commonTypes.PageTypeBasicHost, Path: "/b"}}).Error
+	_ = db.Create(&model.PageDraft{NamespaceCode: "test-ns", ProjectCode: "proj-1", OldPageID: &published.ID, ChangeType: model.DraftChangeTypeUpdate, ContentSize: 200}).Error
+
+	t.Run("aggregates the project", func(t *testing.T) {
+		stats, err := repo.GetStats(ctx, "test-ns", "proj-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, stats.Version)
+		assert.Equal(t, publishedAt.Unix(), stats.PublishedAt.Unix())
+
+		assert.Equal(t, int64(4), stats.RedirectTotal)
+		assert.Equal(t, int64(2), stats.RedirectCountBasic)
+		assert.Equal(t, int64(0), stats.RedirectCountBasicHost)
+		assert.Equal(t, int64(1), stats.RedirectCountRegex)
+		assert.Equal(t, int64(1), stats.RedirectCountRegexHost)
+		assert.Equal(t, int64(2), stats.RedirectCountMovedPermanent)
+		assert.Equal(t, int64(1), stats.RedirectCountFound)
+		assert.Equal(t, int64(0), stats.RedirectCountTemporary)
+		assert.Equal(t, int64(1), stats.RedirectCountPermanent)
+
+		assert.Equal(t, int64(2), stats.RedirectDraftTotal)
+		assert.Equal(t, int64(1), stats.RedirectDraftCountCreate)
+		assert.Equal(t, int64(0), stats.RedirectDraftCountUpdate)
+		assert.Equal(t, int64(1), stats.RedirectDraftCountDelete)
+
+		assert.Equal(t, int64(2), stats.PageTotal)
+		assert.Equal(t, int64(1), stats.PageCountBasic)
+		assert.Equal(t, int64(1), stats.PageCountBasicHost)
+		assert.Equal(t, int64(250), stats.PageContentSize) // 50 (published) + 200 (draft)
+
+		assert.Equal(t, int64(1), stats.PageDraftTotal)
+		assert.Equal(t, int64(1), stats.PageDraftCountUpdate)
+	})
+
+	t.Run("redirect statuses and pending page drafts", func(t *testing.T) {
+		_ = db.Create(redirect("proj-2", commonTypes.RedirectTypeBasic, commonTypes.RedirectStatusTemporary)).Error
+		_ = db.Create(redirect("proj-2", commonTypes.RedirectTypeBasic, commonTypes.RedirectStatusFound)).Error
+		_ = db.Create(redirect("proj-2", commonTypes.RedirectTypeRegex, commonTypes.RedirectStatusPermanent)).Error
+
+		kept := &model.Page{NamespaceCode: "test-ns", ProjectCode: "proj-2", IsPublished: boolPtr(true), ContentSize: 40, Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/kept"}}
+		deleted := &model.Page{NamespaceCode: "test-ns", ProjectCode: "proj-2", IsPublished: boolPtr(true), ContentSize: 300, Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/deleted"}}
+		unpublished := &model.Page{NamespaceCode: "test-ns", ProjectCode: "proj-2", IsPublished: boolPtr(false), ContentSize: 1000, Page: &commonTypes.Page{Type: commonTypes.PageTypeBasicHost, Path: "/unpublished"}}
+		for _, page := range []*model.Page{kept, deleted, unpublished} {
+			_ = db.Create(page).Error
+		}
+		_ = db.Create(&model.PageDraft{NamespaceCode: "test-ns", ProjectCode: "proj-2", OldPageID: &deleted.ID, ChangeType: model.DraftChangeTypeDelete, ContentSize: 300}).Error
+		_ = db.Create(&model.PageDraft{NamespaceCode: "test-ns", ProjectCode: "proj-2", ChangeType: model.DraftChangeTypeCreate, ContentSize: 70}).Error
+
+		stats, err := repo.GetStats(ctx, "test-ns", "proj-2")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), stats.RedirectTotal)
+		assert.Equal(t, int64(0), stats.RedirectCountMovedPermanent)
+		assert.Equal(t, int64(1), stats.RedirectCountFound)
+		assert.Equal(t, int64(2), stats.RedirectCountTemporary)
+		assert.Equal(t, int64(1), stats.RedirectCountPermanent)
+
+		assert.Equal(t, int64(3), stats.PageTotal)
+		assert.Equal(t, int64(2), stats.PageCountBasic)
+		assert.Equal(t, int64(1), stats.PageCountBasicHost)
+		// 40 (published without draft) + 70 (created), the deleted and unpublished pages not counted
+		assert.Equal(t, int64(110), stats.PageContentSize)
+
+		assert.Equal(t, int64(2), stats.PageDraftTotal)
+		assert.Equal(t, int64(1), stats.PageDraftCountCreate)
+		assert.Equal(t, int64(0), stats.PageDraftCountUpdate)
+		assert.Equal(t, int64(1), stats.PageDraftCountDelete)
+	})
+
+	t.Run("empty project", func(t *testing.T) {
+		stats, err := repo.GetStats(ctx, "test-ns", "empty")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.ProjectStats{Version: 1}, *stats)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		stats, err := repo.GetStats(ctx, "test-ns", "non-existing")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, stats)
+	})
+}
+
+func TestProjectRepository_FindEnvironment(t *testing.T) {
+	db := setupProjectTestDB(t)
+	createTestNamespace(t, db, "test-ns", "Test Namespace")
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-1", NamespaceCode: "test-ns", Name: "Project 1"})
+	_ = db.Create(&model.ProjectEnvironment{
+		NamespaceCode:  "test-ns",
+		ProjectCode:    "proj-1",
+		Environment:    commonTypes.EnvironmentProduction,
+		Version:        3,
+		CountRedirects: 1,
+		Redirects:      []commonTypes.Redirect{{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusFound}},
+		Pages:          []commonTypes.Page{},
+	}).Error
+
+	t.Run("found with snapshot", func(t *testing.T) {
+		projectEnvironment, err := repo.FindEnvironment(ctx, "test-ns", "proj-1", commonTypes.EnvironmentProduction)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, projectEnvironment.Version)
+		assert.Len(t, projectEnvironment.Redirects, 1)
+		assert.Equal(t, "/old", projectEnvironment.Redirects[0].Source)
+		assert.Empty(t, projectEnvironment.Pages)
+	})
+
+	t.Run("not promoted environment", func(t *testing.T) {
+		projectEnvironment, err := repo.FindEnvironment(ctx, "test-ns", "proj-1", commonTypes.EnvironmentStaging)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, projectEnvironment)
+	})
+}
+
+func TestProjectRepository_FindEnvironments(t *testing.T) {
+	db := setupProjectTestDB(t)
+	createTestNamespace(t, db, "test-ns", "Test Namespace")
+	repo := NewProjectRepository(db)
+	ctx := context.Background()
+
+	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-1", NamespaceCode: "test-ns", Name: "Project 1"})
+	_ = repo.Create(ctx, &model.Project{ProjectCode: "proj-2", NamespaceCode: "test-ns", Name: "Project 2"})
+	_ = db.Create(&model.ProjectEnvironment{
+		NamespaceCode:  "test-ns",
+		ProjectCode:    "proj-1",
+		Environment:    commonTypes.EnvironmentProduction,
+		Version:        2,
+		CountRedirects: 1,
+		Redirects:      []commonTypes.Redirect{{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusFound}},
+	}).Error
+
+	t.Run("snapshot content is not loaded", func(t *testing.T) {
+		projectEnvironments, err := repo.FindEnvironments(ctx, "test-ns", "proj-1")
+		assert.NoError(t, err)
+		assert.Len(t, projectEnvironments, 1)
+		assert.Equal(t, commonTypes.EnvironmentProduction, projectEnvironments[0].Environment)
+		assert.Equal(t, int64(1), projectEnvironments[0].CountRedirects)
+		assert.Nil(t, projectEnvironments[0].Redirects)
+	})
+
+	t.Run("project without environment", func(t *testing.T) {
+		projectEnvironments, err := repo.FindEnvironments(ctx, "test-ns", "proj-2")
+		assert.NoError(t, err)
+		assert.Empty(t, projectEnvironments)
+	})
+}
@@ -0,0 +1,272 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/migrations"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/golang-migrate/migrate/v4"
+	migrateMysql "github.com/golang-migrate/migrate/v4/database/mysql"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// queryPlanDSNEnv is the DSN of the MySQL database the query plans are checked against, the test being skipped when
+// it is not set. The database is migrated and the test data removed once done.
+const queryPlanDSNEnv = "FLECTO_TEST_MYSQL_DSN"
+
+const (
+	queryPlanNamespace = "query-plan"
+	queryPlanProjects  = 20
+	queryPlanRedirects = 50
+	queryPlanPages     = 20
+)
+
+// queryPlanRecorder records the statements read with while recording, to explain their plan
+type queryPlanRecorder struct {
+	db         *gorm.DB
+	recording  bool
+	statements []queryPlanStatement
+}
+
+type queryPlanStatement struct {
+	sql  string
+	vars []interface{}
+}
+
+// queryPlanRow is a row of the EXPLAIN output, by column
+type queryPlanRow map[string]string
+
+// setupQueryPlanTestDB migrates the MySQL database and fills it with projects large enough for the optimizer to pick
+// the indexes of the hot paths
+func setupQueryPlanTestDB(t *testing.T) *queryPlanRecorder {
+	dsn := os.Getenv(queryPlanDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", queryPlanDSNEnv)
+	}
+	// The migrations need several statements by query and the models their timestamps parsed
+	for _, param := range []string{"multiStatements", "parseTime"} {
+		if strings.Contains(dsn, param+"=") {
+			continue
+		}
+		if strings.Contains(dsn, "?") {
+			dsn += "&" + param + "=true"
+		} else {
+			dsn += "?" + param + "=true"
+		}
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+
+	sourceDriver, err := iofs.New(migrations.MigrationsFS, ".")
+	require.NoError(t, err)
+	dbDriver, err := migrateMysql.WithInstance(sqlDB, &migrateMysql.Config{})
+	require.NoError(t, err)
+	m, err := migrate.NewWithInstance("iofs", sourceDriver, "mysql", dbDriver)
+	require.NoError(t, err)
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		require.NoError(t, err)
+	}
+
+	// The projects and their content are removed along with the namespace
+	cleanup := func() {
+		db.Where("namespace_code = ?", queryPlanNamespace).Delete(&model.Namespace{})
+	}
+	cleanup()
+	t.Cleanup(func() {
+		cleanup()
+		_ = sqlDB.Close()
+	})
+
+	published, unpublished := true, false
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: queryPlanNamespace, Name: queryPlanNamespace}).Error)
+	for p := 0; p < queryPlanProjects; p++ {
+		projectCode := fmt.Sprintf("project-%02d", p)
+		require.NoError(t, db.Create(&model.Project{NamespaceCode: queryPlanNamespace, ProjectCode: projectCode, Name: projectCode}).Error)
+
+		redirects := make([]model.Redirect, 0, queryPlanRedirects)
+		redirectDrafts := make([]model.RedirectDraft, 0, queryPlanRedirects)
+		for i := 0; i < queryPlanRedirects; i++ {
+			isPublished := &published
+			if i%2 == 1 {
+				isPublished = &unpublished
+			}
+			redirect := &commonTypes.Redirect{
+				Type:     commonTypes.RedirectTypeBasic,
+				Source:   fmt.Sprintf("/source-%d", i),
+				Target:   fmt.Sprintf("/target-%d", i),
+				Status:   commonTypes.RedirectStatusMovedPermanent,
+				Priority: i % 5,
+			}
+			if i == 0 {
+				redirect.ValidUntil = &expired
+			}
+			redirects = append(redirects, model.Redirect{NamespaceCode: queryPlanNamespace, ProjectCode: projectCode, IsPublished: isPublished, Redirect: redirect})
+			redirectDrafts = append(redirectDrafts, model.RedirectDraft{
+				NamespaceCode: queryPlanNamespace,
+				ProjectCode:   projectCode,
+				ChangeType:    model.DraftChangeTypeCreate,
+				NewRedirect:   &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: fmt.Sprintf("/draft-%d", i), Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+			})
+		}
+		require.NoError(t, db.Create(&redirects).Error)
+		require.NoError(t, db.Create(&redirectDrafts).Error)
+
+		pages := make([]model.Page, 0, queryPlanPages)
+		pageDrafts := make([]model.PageDraft, 0, queryPlanPages)
+		for i := 0; i < queryPlanPages; i++ {
+			isPublished := &published
+			if i%2 == 1 {
+				isPublished = &unpublished
+			}
+			pages = append(pages, model.Page{NamespaceCode: queryPlanNamespace, ProjectCode: projectCode, IsPublished: isPublished, Page: &commonTypes.Page{
+				Type:        commonTypes.PageTypeBasic,
+				Path:        fmt.Sprintf("/page-%d.txt", i),
+				Content:     "content",
+				ContentType: commonTypes.PageContentTypeTextPlain,
+			}})
+			pageDrafts = append(pageDrafts, model.PageDraft{
+				NamespaceCode: queryPlanNamespace,
+				ProjectCode:   projectCode,
+				ChangeType:    model.DraftChangeTypeCreate,
+				NewPage:       &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: fmt.Sprintf("/draft-%d.txt", i), Content: "content", ContentType: commonTypes.PageContentTypeTextPlain},
+			})
+		}
+		require.NoError(t, db.Create(&pages).Error)
+		require.NoError(t, db.Create(&pageDrafts).Error)
+	}
+	require.NoError(t, db.Exec("ANALYZE TABLE redirects, redirect_drafts, pages, page_drafts").Error)
+
+	recorder := &queryPlanRecorder{db: db}
+	record := func(tx *gorm.DB) {
+		if recorder.recording {
+			recorder.statements = append(recorder.statements, queryPlanStatement{sql: tx.Statement.SQL.String(), vars: tx.Statement.Vars})
+		}
+	}
+	require.NoError(t, db.Callback().Query().After("*").Register("test:query_plan", record))
+	require.NoError(t, db.Callback().Row().After("*").Register("test:query_plan", record))
+	return recorder
+}
+
+// plans runs fn and returns the EXPLAIN rows of each statement it read with
+func (r *queryPlanRecorder) plans(t *testing.T, fn func(db *gorm.DB)) [][]queryPlanRow {
+	r.recording, r.statements = true, nil
+	fn(r.db)
+	r.recording = false
+
+	plans := make([][]queryPlanRow, 0, len(r.statements))
+	for _, stmt := range r.statements {
+		rows, err := r.db.Raw("EXPLAIN "+stmt.sql, stmt.vars...).Rows()
+		require.NoError(t, err)
+		columns, err := rows.Columns()
+		require.NoError(t, err)
+		var plan []queryPlanRow
+		for rows.Next() {
+			values := make([]sql.NullString, len(columns))
+			dest := make([]interface{}, len(columns))
+			for i := range values {
+				dest[i] = &values[i]
+			}
+			require.NoError(t, rows.Scan(dest...))
+			row := queryPlanRow{}
+			for i, column := range columns {
+				row[column] = values[i].String
+			}
+			plan = append(plan, row)
+		}
+		require.NoError(t, rows.Close())
+		plans = append(plans, plan)
+	}
+	return plans
+}
+
+// tableRow returns the row of the plan reading table
+func tableRow(t *testing.T, plan []queryPlanRow, table string) queryPlanRow {
+	for _, row := range plan {
+		if row["table"] == table {
+			return row
+		}
+	}
+	require.Failf(t, "table not in the plan", "%s not in %v", table, plan)
+	return nil
+}
+
+func TestQueryPlans(t *testing.T) {
+	ctx := context.Background()
+	recorder := setupQueryPlanTestDB(t)
+	projectCode := "project-00"
+
+	t.Run("published redirects of the agents", func(t *testing.T) {
+		plans := recorder.plans(t, func(db *gorm.DB) {
+			_, _, err := NewRedirectRepository(db).FindByProjectPublished(ctx, queryPlanNamespace, projectCode, 100, 0)
+			require.NoError(t, err)
+		})
+		require.Len(t, plans, 2)
+		for _, plan := range plans {
+			row := tableRow(t, plan, "redirects")
+			assert.Equal(t, "idx_redirects_published", row["key"])
+			assert.NotContains(t, row["Extra"], "Using filesort")
+		}
+	})
+
+	t.Run("published pages of the agents", func(t *testing.T) {
+		plans := recorder.plans(t, func(db *gorm.DB) {
+			_, _, err := NewPageRepository(db).FindByProjectPublished(ctx, queryPlanNamespace, projectCode, 100, 0)
+			require.NoError(t, err)
+		})
+		require.Len(t, plans, 2)
+		for _, plan := range plans {
+			assert.Equal(t, "idx_pages_published", tableRow(t, plan, "pages")["key"])
+		}
+	})
+
+	t.Run("draft counts of the project statistics", func(t *testing.T) {
+		plans := recorder.plans(t, func(db *gorm.DB) {
+			_, _ = NewProjectRepository(db).GetStats(ctx, queryPlanNamespace, projectCode)
+		})
+		require.NotEmpty(t, plans)
+		for _, table := range []string{"redirect_drafts", "page_drafts"} {
+			row := tableRow(t, plans[0], table)
+			assert.Equal(t, fmt.Sprintf("idx_%s_change_type", table), row["key"])
+			assert.Contains(t, row["Extra"], "Using index")
+		}
+	})
+
+	t.Run("redirects sorted by update", func(t *testing.T) {
+		plans := recorder.plans(t, func(db *gorm.DB) {
+			var redirects []model.Redirect
+			require.NoError(t, db.Where("namespace_code = ? AND project_code = ?", queryPlanNamespace, projectCode).Order("updated_at DESC").Limit(20).Find(&redirects).Error)
+		})
+		require.Len(t, plans, 1)
+		row := tableRow(t, plans[0], "redirects")
+		assert.Equal(t, "idx_redirects_updated_at", row["key"])
+		assert.NotContains(t, row["Extra"], "Using filesort")
+	})
+
+	t.Run("expired redirects", func(t *testing.T) {
+		// The query of the expiry worker, going through the redirects of all the projects
+		plans := recorder.plans(t, func(db *gorm.DB) {
+			var redirects []model.Redirect
+			require.NoError(t, db.Where("is_published = ? AND valid_until IS NOT NULL AND valid_until <= ?", true, time.Now()).Find(&redirects).Error)
+		})
+		require.Len(t, plans, 1)
+		assert.Equal(t, "idx_redirects_expiry", tableRow(t, plans[0], "redirects")["key"])
+	})
+}
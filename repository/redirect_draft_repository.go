@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"strings"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -17,8 +20,12 @@ type RedirectDraftRepository interface {
 	Update(ctx context.Context, draft *model.RedirectDraft) error
 	Delete(ctx context.Context, id int64) error
 	Search(ctx context.Context, query *gorm.DB) ([]model.RedirectDraft, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.RedirectDraft, int64, error)
-	CheckSourceAvailability(ctx context.Context, namespaceCode, projectCode, source string, excludeRedirectID, excludeDraftID *int64) (bool, error)
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.RedirectDraft, int64, error)
+	SearchCursor(ctx context.Context, query *gorm.DB, afterID int64, limit int) ([]model.RedirectDraft, bool, error)
+	CheckSourceAvailability(ctx context.Context, namespaceCode, projectCode, source string, conditions []commonTypes.RedirectCondition, excludeRedirectID, excludeDraftID *int64) (bool, error)
+	FindNormalizedSourceConflict(ctx context.Context, namespaceCode, projectCode, source string, conditions []commonTypes.RedirectCondition, options commonTypes.RedirectOptions, excludeRedirectID, excludeDraftID *int64) (string, error)
+	FindTombstone(ctx context.Context, namespaceCode, projectCode string, redirectID int64) (*model.RedirectTombstone, error)
+	SearchTombstones(ctx context.Context, namespaceCode, projectCode string, limit, offset int) ([]model.RedirectTombstone, int64, error)
 }
 
 type redirectDraftRepository struct {
@@ -41,6 +48,7 @@ func (r *redirectDraftRepository) FindByID(ctx context.Context, id int64) (*mode
 	var draft model.RedirectDraft
 	err := r.db.WithContext(ctx).
 		Preload("OldRedirect").
+		Preload("Tags").
 		Where("id = ?", id).
 		First(&draft).Error
 	if err != nil {
@@ -53,6 +61,7 @@ func (r *redirectDraftRepository) FindByIDWithProject(ctx context.Context, names
 	var draft model.RedirectDraft
 	err := r.db.WithContext(ctx).
 		Preload("OldRedirect").
+		Preload("Tags").
 		Where("id = ? AND namespace_code = ? AND project_code = ?", id, namespaceCode, projectCode).
 		First(&draft).Error
 	if err != nil {
@@ -65,6 +74,7 @@ func (r *redirectDraftRepository) FindByProject(ctx context.Context, namespaceCo
 	var drafts []model.RedirectDraft
 	err := r.db.WithContext(ctx).
 		Preload("OldRedirect").
+		Preload("Tags").
 		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
 		Find(&drafts).Error
 	if err != nil {
@@ -86,11 +96,11 @@ func (r *redirectDraftRepository) Delete(ctx context.Context, id int64) error {
 }
 
 func (r *redirectDraftRepository) Search(ctx context.Context, query *gorm.DB) ([]model.RedirectDraft, error) {
-	drafts, _, err := r.SearchPaginate(ctx, query, 0, 0)
+	drafts, _, err := r.SearchPaginate(ctx, query, 0, 0, nil)
 	return drafts, err
 }
 
-func (r *redirectDraftRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.RedirectDraft, int64, error) {
+func (r *redirectDraftRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.RedirectDraft, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.RedirectDraft{})
@@ -100,22 +110,50 @@ func (r *redirectDraftRepository) SearchPaginate(ctx context.Context, query *gor
 		return nil, 0, err
 	}
 
+	query, err := database.ApplyOrderBy(query, model.RedirectDraftSortableColumns, orderBy, "redirect_drafts")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit != 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
 
 	var drafts []model.RedirectDraft
-	if err := query.Preload("OldRedirect").Find(&drafts).Error; err != nil {
+	if err := query.Preload("OldRedirect").Preload("Tags").Find(&drafts).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return drafts, total, nil
 }
 
-// CheckSourceAvailability checks if a source is available for a project.
+// SearchCursor returns the items following afterID, ordered by id, and whether more items follow
+func (r *redirectDraftRepository) SearchCursor(ctx context.Context, query *gorm.DB, afterID int64, limit int) ([]model.RedirectDraft, bool, error) {
+	if query == nil {
+		query = r.db.WithContext(ctx).Model(&model.RedirectDraft{})
+	}
+	if afterID > 0 {
+		query = query.Where("redirect_drafts.id > ?", afterID)
+	}
+
+	var drafts []model.RedirectDraft
+	if err := query.Preload("OldRedirect").Preload("Tags").Order("redirect_drafts.id").Limit(limit + 1).Find(&drafts).Error; err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(drafts) > limit
+	if hasMore {
+		drafts = drafts[:limit]
+	}
+	return drafts, hasMore, nil
+}
+
+// CheckSourceAvailability checks if a source is available for a project with the given normalized conditions.
+// A source can be used by several redirects having different conditions.
 // Returns true if available, false if already used.
-func (r *redirectDraftRepository) CheckSourceAvailability(ctx context.Context, namespaceCode, projectCode, source string, excludeRedirectID, excludeDraftID *int64) (bool, error) {
+func (r *redirectDraftRepository) CheckSourceAvailability(ctx context.Context, namespaceCode, projectCode, source string, conditions []commonTypes.RedirectCondition, excludeRedirectID, excludeDraftID *int64) (bool, error) {
 	var exists bool
+	conditionsKey := commonTypes.RedirectConditionsKey(conditions)
 
 	excludeRedirect := int64(0)
 	if excludeRedirectID != nil {
@@ -132,17 +170,19 @@ func (r *redirectDraftRepository) CheckSourceAvailability(ctx context.Context, n
 			WHERE namespace_code = ?
 			AND project_code = ?
 			AND source = ?
+			AND COALESCE(conditions, '') = ?
 			AND id != ?
 			UNION
 			SELECT 1 FROM redirect_drafts
 			WHERE namespace_code = ?
 			AND project_code = ?
 			AND new_source = ?
+			AND COALESCE(new_conditions, '') = ?
 			AND id != ?
 			AND change_type != 'DELETE'
 		)
-	`, namespaceCode, projectCode, source, excludeRedirect,
-		namespaceCode, projectCode, source, excludeDraft,
+	`, namespaceCode, projectCode, source, conditionsKey, excludeRedirect,
+		namespaceCode, projectCode, source, conditionsKey, excludeDraft,
 	).Scan(&exists).Error
 
 	if err != nil {
@@ -151,3 +191,93 @@ func (r *redirectDraftRepository) CheckSourceAvailability(ctx context.Context, n
 
 	return !exists, nil
 }
+
+// FindNormalizedSourceConflict returns the source of a BASIC or BASIC_HOST redirect or draft of the project having the given
+// normalized conditions and matching the same requests as source with the redirect options, or an empty string when none does.
+func (r *redirectDraftRepository) FindNormalizedSourceConflict(ctx context.Context, namespaceCode, projectCode, source string, conditions []commonTypes.RedirectCondition, options commonTypes.RedirectOptions, excludeRedirectID, excludeDraftID *int64) (string, error) {
+	conditionsKey := commonTypes.RedirectConditionsKey(conditions)
+	normalized := options.NormalizeSource(source)
+
+	excludeRedirect := int64(0)
+	if excludeRedirectID != nil {
+		excludeRedirect = *excludeRedirectID
+	}
+	excludeDraft := int64(0)
+	if excludeDraftID != nil {
+		excludeDraft = *excludeDraftID
+	}
+
+	// The candidates share the path of the normalized source, without its trailing slash, whatever their case. The raw
+	// sources differing in their encoding when the URLs are normalized, all the sources are candidates then.
+	prefix := "%"
+	if !options.NormalizeURL {
+		path, _, _ := strings.Cut(normalized, "?")
+		prefix = database.EscapeLike(strings.ToLower(strings.TrimSuffix(path, "/"))) + "%"
+	}
+	basicTypes := []commonTypes.RedirectType{commonTypes.RedirectTypeBasic, commonTypes.RedirectTypeBasicHost}
+
+	var candidates []string
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT source FROM redirects
+		WHERE namespace_code = ?
+		AND project_code = ?
+		AND type IN ?
+		AND LOWER(source) LIKE ? ESCAPE '!'
+		AND COALESCE(conditions, '') = ?
+		AND id != ?
+		UNION ALL
+		SELECT new_source FROM redirect_drafts
+		WHERE namespace_code = ?
+		AND project_code = ?
+		AND new_type IN ?
+		AND LOWER(new_source) LIKE ? ESCAPE '!'
+		AND COALESCE(new_conditions, '') = ?
+		AND id != ?
+		AND change_type != 'DELETE'
+	`, namespaceCode, projectCode, basicTypes, prefix, conditionsKey, excludeRedirect,
+		namespaceCode, projectCode, basicTypes, prefix, conditionsKey, excludeDraft,
+	).Scan(&candidates).Error
+	if err != nil {
+		return "", err
+	}
+
+	for _, candidate := range candidates {
+		if options.NormalizeSource(candidate) == normalized {
+			return candidate, nil
+		}
+	}
+	return "", nil
+}
+
+// FindTombstone returns the tombstone of a redirect of the project deleted by a publish
+func (r *redirectDraftRepository) FindTombstone(ctx context.Context, namespaceCode, projectCode string, redirectID int64) (*model.RedirectTombstone, error) {
+	var tombstone model.RedirectTombstone
+	err := r.db.WithContext(ctx).
+		Where("namespace_code = ? AND project_code = ? AND redirect_id = ?", namespaceCode, projectCode, redirectID).
+		First(&tombstone).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tombstone, nil
+}
+
+// SearchTombstones returns the tombstones of the project, the latest deletions first
+func (r *redirectDraftRepository) SearchTombstones(ctx context.Context, namespaceCode, projectCode string, limit, offset int) ([]model.RedirectTombstone, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.RedirectTombstone{}).
+		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit != 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	var tombstones []model.RedirectTombstone
+	if err := query.Order("deleted_at DESC, id DESC").Find(&tombstones).Error; err != nil {
+		return nil, 0, err
+	}
+	return tombstones, total, nil
+}
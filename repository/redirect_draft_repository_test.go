@@ -3,10 +3,13 @@ package repository
 import (
 	"context"
 	"testing"
+	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -15,7 +18,7 @@ func setupRedirectDraftTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectTombstone{})
 	assert.NoError(t, err)
 
 	return db
@@ -349,6 +352,39 @@ func TestRedirectDraftRepository_Update(t *testing.T) {
 	assert.Equal(t, "/updated", found.NewRedirect.Source)
 }
 
+func TestRedirectDraftRepository_Authors(t *testing.T) {
+	db := setupRedirectDraftTestDB(t)
+	createTestDraftNamespace(t, db, "test-ns", "Test Namespace")
+	createTestDraftProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewRedirectDraftRepository(db)
+
+	draft := &model.RedirectDraft{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		ChangeType:    model.DraftChangeTypeCreate,
+		NewRedirect: &commonTypes.Redirect{
+			Type:   commonTypes.RedirectTypeBasic,
+			Source: "/source",
+			Target: "/target",
+			Status: commonTypes.RedirectStatusMovedPermanent,
+		},
+	}
+	assert.NoError(t, repo.Create(types.WithSubject(context.Background(), "alice"), draft))
+	assert.Equal(t, "alice", draft.CreatedBy)
+	assert.Equal(t, "alice", draft.UpdatedBy)
+
+	draft.NewRedirect.Target = "/updated"
+	assert.NoError(t, repo.Update(types.WithSubject(context.Background(), "bob"), draft))
+
+	// An update without subject keeps the last editor
+	assert.NoError(t, repo.Update(context.Background(), draft))
+
+	var found model.RedirectDraft
+	db.First(&found, draft.ID)
+	assert.Equal(t, "alice", found.CreatedBy)
+	assert.Equal(t, "bob", found.UpdatedBy)
+}
+
 func TestRedirectDraftRepository_Delete(t *testing.T) {
 	db := setupRedirectDraftTestDB(t)
 	createTestDraftNamespace(t, db, "test-ns", "Test Namespace")
@@ -454,7 +490,7 @@ func TestRedirectDraftRepository_SearchPaginate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, total, err := repo.SearchPaginate(ctx, nil, tt.limit, tt.offset)
+			results, total, err := repo.SearchPaginate(ctx, nil, tt.limit, tt.offset, nil)
 
 			assert.NoError(t, err)
 			assert.Len(t, results, tt.wantCount)
@@ -479,7 +515,7 @@ func TestRedirectDraftRepository_SearchPaginate_PreloadsOldRedirect(t *testing.T
 	}
 	db.Create(draft)
 
-	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0)
+	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0, nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
@@ -495,7 +531,7 @@ func TestRedirectDraftRepository_CheckSourceAvailability(t *testing.T) {
 		repo := NewRedirectDraftRepository(db)
 		ctx := context.Background()
 
-		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/new-source", nil, nil)
+		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/new-source", nil, nil, nil)
 
 		assert.NoError(t, err)
 		assert.True(t, available)
@@ -519,7 +555,7 @@ func TestRedirectDraftRepository_CheckSourceAvailability(t *testing.T) {
 		}
 		db.Create(redirect)
 
-		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/existing-source", nil, nil)
+		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/existing-source", nil, nil, nil)
 
 		assert.NoError(t, err)
 		assert.False(t, available)
@@ -544,7 +580,7 @@ func TestRedirectDraftRepository_CheckSourceAvailability(t *testing.T) {
 		}
 		db.Create(draft)
 
-		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/draft-source", nil, nil)
+		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/draft-source", nil, nil, nil)
 
 		assert.NoError(t, err)
 		assert.False(t, available)
@@ -568,7 +604,7 @@ func TestRedirectDraftRepository_CheckSourceAvailability(t *testing.T) {
 		db.Create(redirect)
 
 		// Exclude the redirect that has this source
-		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/my-source", &redirect.ID, nil)
+		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/my-source", nil, &redirect.ID, nil)
 
 		assert.NoError(t, err)
 		assert.True(t, available)
@@ -593,7 +629,7 @@ func TestRedirectDraftRepository_CheckSourceAvailability(t *testing.T) {
 		db.Create(draft)
 
 		// Exclude the draft that has this source
-		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/my-draft-source", nil, &draft.ID)
+		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/my-draft-source", nil, nil, &draft.ID)
 
 		assert.NoError(t, err)
 		assert.True(t, available)
@@ -620,7 +656,7 @@ func TestRedirectDraftRepository_CheckSourceAvailability(t *testing.T) {
 		}
 		db.Create(draft)
 
-		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/delete-source", nil, nil)
+		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/delete-source", nil, nil, nil)
 
 		assert.NoError(t, err)
 		assert.True(t, available)
@@ -646,12 +682,46 @@ func TestRedirectDraftRepository_CheckSourceAvailability(t *testing.T) {
 		db.Create(redirect)
 
 		// Check availability in proj-b (should be available)
-		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "proj-b", "/same-source", nil, nil)
+		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "proj-b", "/same-source", nil, nil, nil)
 
 		assert.NoError(t, err)
 		assert.True(t, available)
 	})
 
+	t.Run("source available with other conditions", func(t *testing.T) {
+		db := setupRedirectDraftTestDB(t)
+		createTestDraftNamespace(t, db, "test-ns", "Test Namespace")
+		createTestDraftProject(t, db, "test-ns", "test-proj", "Test Project")
+		repo := NewRedirectDraftRepository(db)
+		ctx := context.Background()
+
+		langFr := []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeQuery, Name: "lang", Value: "fr"}}
+		langEn := []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeQuery, Name: "lang", Value: "en"}}
+		db.Create(&model.Redirect{
+			NamespaceCode: "test-ns",
+			ProjectCode:   "test-proj",
+			Redirect:      &commonTypes.Redirect{Source: "/page", Target: "/target"},
+		})
+		db.Create(&model.RedirectDraft{
+			NamespaceCode: "test-ns",
+			ProjectCode:   "test-proj",
+			ChangeType:    model.DraftChangeTypeCreate,
+			NewRedirect:   &commonTypes.Redirect{Source: "/page", Target: "/fr", Conditions: langFr},
+		})
+
+		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/page", langEn, nil, nil)
+		assert.NoError(t, err)
+		assert.True(t, available)
+
+		available, err = repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/page", langFr, nil, nil)
+		assert.NoError(t, err)
+		assert.False(t, available)
+
+		available, err = repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/page", nil, nil, nil)
+		assert.NoError(t, err)
+		assert.False(t, available)
+	})
+
 	t.Run("returns error on database failure", func(t *testing.T) {
 		db := setupRedirectDraftTestDB(t)
 		repo := NewRedirectDraftRepository(db)
@@ -660,9 +730,152 @@ func TestRedirectDraftRepository_CheckSourceAvailability(t *testing.T) {
 		sqlDB, _ := db.DB()
 		sqlDB.Close()
 
-		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, nil)
+		available, err := repo.CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, nil, nil)
 
 		assert.Error(t, err)
 		assert.False(t, available)
 	})
 }
+
+func TestRedirectDraftRepository_SearchCursor(t *testing.T) {
+	db := setupRedirectDraftTestDB(t)
+	createTestDraftNamespace(t, db, "test-ns", "Test Namespace")
+	createTestDraftProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewRedirectDraftRepository(db)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate})
+	}
+
+	var ids []int64
+	afterID := int64(0)
+	for page := 0; page < 3; page++ {
+		results, hasMore, err := repo.SearchCursor(ctx, nil, afterID, 2)
+
+		assert.NoError(t, err)
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		if !hasMore {
+			assert.Equal(t, 2, page)
+			assert.Len(t, results, 1)
+			break
+		}
+		assert.Len(t, results, 2)
+		afterID = results[len(results)-1].ID
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+
+	results, hasMore, err := repo.SearchCursor(ctx, nil, 5, 2)
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, results)
+}
+
+func TestRedirectDraftRepository_FindNormalizedSourceConflict(t *testing.T) {
+	db := setupRedirectDraftTestDB(t)
+	createTestDraftNamespace(t, db, "test-ns", "Test Namespace")
+	createTestDraftProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewRedirectDraftRepository(db)
+	ctx := context.Background()
+
+	redirect := &model.Redirect{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/About_Us/", Target: "/target"},
+	}
+	require.NoError(t, db.Create(redirect).Error)
+	draft := &model.RedirectDraft{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		ChangeType:    model.DraftChangeTypeCreate,
+		NewRedirect:   &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/Contact", Target: "/target"},
+	}
+	require.NoError(t, db.Create(draft).Error)
+	regex := &model.Redirect{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeRegex, Source: "/shop", Target: "/target"},
+	}
+	require.NoError(t, db.Create(regex).Error)
+
+	options := commonTypes.RedirectOptions{CaseInsensitive: true, IgnoreTrailingSlash: true}
+
+	conflict, err := repo.FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/about_us", nil, options, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/About_Us/", conflict)
+
+	conflict, err = repo.FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/contact/", nil, options, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/Contact", conflict)
+
+	// The wildcards of LIKE are escaped
+	conflict, err = repo.FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/aboutXus", nil, options, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, conflict)
+
+	// Only the case is ignored
+	conflict, err = repo.FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/contact/", nil, commonTypes.RedirectOptions{CaseInsensitive: true}, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, conflict)
+
+	// The sources differing in their encoding conflict when the URLs are normalized
+	conflict, err = repo.FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/%41bout_Us/", nil, commonTypes.RedirectOptions{NormalizeURL: true}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/About_Us/", conflict)
+	conflict, err = repo.FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/%41bout_Us/", nil, commonTypes.RedirectOptions{CaseInsensitive: true}, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, conflict)
+
+	// Regex redirects, excluded redirects and different conditions do not conflict
+	conflict, err = repo.FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/Shop", nil, options, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, conflict)
+	conflict, err = repo.FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/about_us", nil, options, &redirect.ID, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, conflict)
+	conditions := []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeQuery, Name: "lang", Value: "fr"}}
+	conflict, err = repo.FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/about_us", conditions, options, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, conflict)
+}
+
+func TestRedirectDraftRepository_Tombstones(t *testing.T) {
+	db := setupRedirectDraftTestDB(t)
+	repo := NewRedirectDraftRepository(db)
+	ctx := context.Background()
+
+	createTestDraftNamespace(t, db, "ns1", "Namespace 1")
+	createTestDraftProject(t, db, "ns1", "proj1", "Project 1")
+	createTestDraftProject(t, db, "ns1", "proj2", "Project 2")
+
+	deletedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, tombstone := range []model.RedirectTombstone{
+		{NamespaceCode: "ns1", ProjectCode: "proj1", RedirectID: 10, Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/first"}, Tags: []string{"seo"}, DeletedAt: deletedAt},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", RedirectID: 11, Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/second"}, DeletedAt: deletedAt.Add(time.Hour)},
+		{NamespaceCode: "ns1", ProjectCode: "proj2", RedirectID: 12, Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/other"}, DeletedAt: deletedAt},
+	} {
+		require.NoError(t, db.Create(&tombstone).Error, i)
+	}
+
+	t.Run("find tombstone", func(t *testing.T) {
+		tombstone, err := repo.FindTombstone(ctx, "ns1", "proj1", 10)
+		require.NoError(t, err)
+		assert.Equal(t, "/first", tombstone.Source)
+		assert.Equal(t, []string{"seo"}, tombstone.Tags)
+	})
+
+	t.Run("tombstone of another project", func(t *testing.T) {
+		_, err := repo.FindTombstone(ctx, "ns1", "proj1", 12)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("search tombstones latest first", func(t *testing.T) {
+		tombstones, total, err := repo.SearchTombstones(ctx, "ns1", "proj1", 1, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, tombstones, 1)
+		assert.Equal(t, int64(11), tombstones[0].RedirectID)
+	})
+}
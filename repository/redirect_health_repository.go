@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RedirectHealthRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	Upsert(ctx context.Context, healths []model.RedirectHealth) error
+	DeleteByRedirectIDs(ctx context.Context, redirectIDs []int64) error
+	GetReport(ctx context.Context, namespaceCode, projectCode string) (*model.RedirectHealthReport, error)
+}
+
+type redirectHealthRepository struct {
+	db *gorm.DB
+}
+
+func NewRedirectHealthRepository(db *gorm.DB) RedirectHealthRepository {
+	return &redirectHealthRepository{db: db}
+}
+
+func (r *redirectHealthRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *redirectHealthRepository) GetQuery(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.RedirectHealth{})
+}
+
+// Upsert saves the check results, replacing the previous result of each redirect
+func (r *redirectHealthRepository) Upsert(ctx context.Context, healths []model.RedirectHealth) error {
+	if len(healths) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "redirect_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status_code", "latency_ms", "error", "checked_at"}),
+		}).
+		Create(&healths).Error
+}
+
+func (r *redirectHealthRepository) DeleteByRedirectIDs(ctx context.Context, redirectIDs []int64) error {
+	if len(redirectIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Where("redirect_id IN ?", redirectIDs).Delete(&model.RedirectHealth{}).Error
+}
+
+// GetReport counts the checked and broken targets of the published redirects of a project
+func (r *redirectHealthRepository) GetReport(ctx context.Context, namespaceCode, projectCode string) (*model.RedirectHealthReport, error) {
+	var row struct {
+		CheckedCount int
+		BrokenCount  int
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.RedirectHealth{}).
+		Select(fmt.Sprintf("COUNT(*) AS checked_count, COALESCE(SUM(CASE WHEN %s THEN 1 ELSE 0 END), 0) AS broken_count", model.RedirectHealthBrokenCondition)).
+		Joins("JOIN redirects ON redirects.id = redirect_health.redirect_id").
+		Where(fmt.Sprintf("redirects.%s = ? AND redirects.%s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.RedirectHealthReport{CheckedCount: row.CheckedCount, BrokenCount: row.BrokenCount}
+	if row.CheckedCount > 0 {
+		var last model.RedirectHealth
+		err = r.db.WithContext(ctx).
+			Joins("JOIN redirects ON redirects.id = redirect_health.redirect_id").
+			Where(fmt.Sprintf("redirects.%s = ? AND redirects.%s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+			Order("redirect_health.checked_at DESC").
+			First(&last).Error
+		if err != nil {
+			return nil, err
+		}
+		report.LastCheckedAt = &last.CheckedAt
+	}
+	return report, nil
+}
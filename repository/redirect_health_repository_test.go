@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupRedirectHealthTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectHealth{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func createTestHealthRedirect(t *testing.T, db *gorm.DB, projectCode, source string) *model.Redirect {
+	redirect := &model.Redirect{
+		NamespaceCode: "ns1",
+		ProjectCode:   projectCode,
+		Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "https://example.com" + source},
+	}
+	assert.NoError(t, db.Create(redirect).Error)
+	return redirect
+}
+
+func TestNewRedirectHealthRepository(t *testing.T) {
+	db := setupRedirectHealthTestDB(t)
+	repo := NewRedirectHealthRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestRedirectHealthRepository_Upsert(t *testing.T) {
+	db := setupRedirectHealthTestDB(t)
+	repo := NewRedirectHealthRepository(db)
+	ctx := context.Background()
+	redirect := createTestHealthRedirect(t, db, "proj1", "/a")
+
+	assert.NoError(t, repo.Upsert(ctx, nil))
+	assert.NoError(t, repo.Upsert(ctx, []model.RedirectHealth{{RedirectID: redirect.ID, StatusCode: 200, LatencyMs: 12, CheckedAt: time.Now()}}))
+	assert.NoError(t, repo.Upsert(ctx, []model.RedirectHealth{{RedirectID: redirect.ID, Error: "timeout", CheckedAt: time.Now()}}))
+
+	var healths []model.RedirectHealth
+	db.Find(&healths)
+	assert.Len(t, healths, 1)
+	assert.Equal(t, 0, healths[0].StatusCode)
+	assert.Equal(t, "timeout", healths[0].Error)
+}
+
+func TestRedirectHealthRepository_DeleteByRedirectIDs(t *testing.T) {
+	db := setupRedirectHealthTestDB(t)
+	repo := NewRedirectHealthRepository(db)
+	ctx := context.Background()
+	a := createTestHealthRedirect(t, db, "proj1", "/a")
+	b := createTestHealthRedirect(t, db, "proj1", "/b")
+	assert.NoError(t, repo.Upsert(ctx, []model.RedirectHealth{{RedirectID: a.ID, StatusCode: 200}, {RedirectID: b.ID, StatusCode: 200}}))
+
+	assert.NoError(t, repo.DeleteByRedirectIDs(ctx, nil))
+	assert.NoError(t, repo.DeleteByRedirectIDs(ctx, []int64{a.ID}))
+
+	var ids []int64
+	db.Model(&model.RedirectHealth{}).Pluck("redirect_id", &ids)
+	assert.Equal(t, []int64{b.ID}, ids)
+}
+
+func TestRedirectHealthRepository_GetReport(t *testing.T) {
+	db := setupRedirectHealthTestDB(t)
+	repo := NewRedirectHealthRepository(db)
+	ctx := context.Background()
+
+	t.Run("empty project", func(t *testing.T) {
+		report, err := repo.GetReport(ctx, "ns1", "proj1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &model.RedirectHealthReport{}, report)
+	})
+
+	t.Run("counts broken targets of the project", func(t *testing.T) {
+		checkedAt := time.Date(2026, 10, 16, 10, 0, 0, 0, time.UTC)
+		ok := createTestHealthRedirect(t, db, "proj1", "/ok")
+		notFound := createTestHealthRedirect(t, db, "proj1", "/not-found")
+		unreachable := createTestHealthRedirect(t, db, "proj1", "/unreachable")
+		other := createTestHealthRedirect(t, db, "proj2", "/other")
+		assert.NoError(t, repo.Upsert(ctx, []model.RedirectHealth{
+			{RedirectID: ok.ID, StatusCode: 200, CheckedAt: checkedAt},
+			{RedirectID: notFound.ID, StatusCode: 404, CheckedAt: checkedAt.Add(time.Minute)},
+			{RedirectID: unreachable.ID, Error: "connection refused", CheckedAt: checkedAt},
+			{RedirectID: other.ID, StatusCode: 500, CheckedAt: checkedAt.Add(time.Hour)},
+		}))
+
+		report, err := repo.GetReport(ctx, "ns1", "proj1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, report.CheckedCount)
+		assert.Equal(t, 2, report.BrokenCount)
+		assert.NotNil(t, report.LastCheckedAt)
+		assert.True(t, report.LastCheckedAt.Equal(checkedAt.Add(time.Minute)))
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -14,8 +16,11 @@ type RedirectRepository interface {
 	FindByID(ctx context.Context, namespaceCode, projectCode string, redirectID int64) (*model.Redirect, error)
 	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.Redirect, error)
 	FindByProjectPublished(ctx context.Context, namespaceCode, projectCode string, limit, offset int) ([]model.Redirect, int64, error)
+	FindByProjectPublishedAfter(ctx context.Context, namespaceCode, projectCode string, after *model.Redirect, limit int) ([]model.Redirect, error)
 	Search(ctx context.Context, query *gorm.DB) ([]model.Redirect, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Redirect, int64, error)
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Redirect, int64, error)
+	SearchCursor(ctx context.Context, query *gorm.DB, afterID int64, limit int) ([]model.Redirect, bool, error)
+	FindSourceOverlaps(ctx context.Context, namespaceCode string, projectCodes []string, limit int) ([]model.Redirect, error)
 }
 
 type redirectRepository struct {
@@ -38,6 +43,8 @@ func (r *redirectRepository) FindByID(ctx context.Context, namespaceCode, projec
 	var redirect model.Redirect
 	err := r.db.WithContext(ctx).
 		Preload("RedirectDraft").
+		Preload("Tags").
+		Preload("Health").
 		Where(fmt.Sprintf("id = ? AND %s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), redirectID, namespaceCode, projectCode).
 		First(&redirect).Error
 	if err != nil {
@@ -50,6 +57,7 @@ func (r *redirectRepository) FindByProject(ctx context.Context, namespaceCode, p
 	var redirects []model.Redirect
 	err := r.db.WithContext(ctx).
 		Preload("RedirectDraft").
+		Preload("Tags").
 		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
 		Find(&redirects).Error
 	if err != nil {
@@ -72,20 +80,37 @@ func (r *redirectRepository) FindByProjectPublished(ctx context.Context, namespa
 		query = query.Limit(limit).Offset(offset)
 	}
 
+	// Redirects are sent to the agents in their evaluation order, the id keeping the pages stable
 	var redirects []model.Redirect
-	if err := query.Find(&redirects).Error; err != nil {
+	if err := query.Order("priority DESC, id").Find(&redirects).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return redirects, total, nil
 }
 
+// FindByProjectPublishedAfter returns the published redirects following after in the order of FindByProjectPublished,
+// the first ones when after is nil. The redirects are read by keyset, without the cost of the offset on large projects.
+func (r *redirectRepository) FindByProjectPublishedAfter(ctx context.Context, namespaceCode, projectCode string, after *model.Redirect, limit int) ([]model.Redirect, error) {
+	query := r.db.WithContext(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND is_published = 1", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode)
+	if after != nil {
+		query = query.Where("priority < ? OR (priority = ? AND id > ?)", after.Priority, after.Priority, after.ID)
+	}
+
+	var redirects []model.Redirect
+	if err := query.Order("priority DESC, id").Limit(limit).Find(&redirects).Error; err != nil {
+		return nil, err
+	}
+	return redirects, nil
+}
+
 func (r *redirectRepository) Search(ctx context.Context, query *gorm.DB) ([]model.Redirect, error) {
-	redirects, _, err := r.SearchPaginate(ctx, query, 0, 0)
+	redirects, _, err := r.SearchPaginate(ctx, query, 0, 0, nil)
 	return redirects, err
 }
 
-func (r *redirectRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Redirect, int64, error) {
+func (r *redirectRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Redirect, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.Redirect{})
@@ -95,14 +120,72 @@ func (r *redirectRepository) SearchPaginate(ctx context.Context, query *gorm.DB,
 		return nil, 0, err
 	}
 
+	query, err := database.ApplyOrderBy(query, model.RedirectSortableColumns, orderBy, "redirects")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit != 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
 
 	var redirects []model.Redirect
-	if err := query.Preload("RedirectDraft").Find(&redirects).Error; err != nil {
+	if err := query.Preload("RedirectDraft.Tags").Preload("Tags").Preload("Health").Find(&redirects).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return redirects, total, nil
 }
+
+// SearchCursor returns the items following afterID, ordered by id, and whether more items follow
+func (r *redirectRepository) SearchCursor(ctx context.Context, query *gorm.DB, afterID int64, limit int) ([]model.Redirect, bool, error) {
+	if query == nil {
+		query = r.db.WithContext(ctx).Model(&model.Redirect{})
+	}
+	if afterID > 0 {
+		query = query.Where("redirects.id > ?", afterID)
+	}
+
+	var redirects []model.Redirect
+	if err := query.Preload("RedirectDraft.Tags").Preload("Tags").Preload("Health").Order("redirects.id").Limit(limit + 1).Find(&redirects).Error; err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(redirects) > limit
+	if hasMore {
+		redirects = redirects[:limit]
+	}
+	return redirects, hasMore, nil
+}
+
+// FindSourceOverlaps returns the published redirects of the first limit sources defined by several of the projects,
+// ordered by source and project, with their project
+func (r *redirectRepository) FindSourceOverlaps(ctx context.Context, namespaceCode string, projectCodes []string, limit int) ([]model.Redirect, error) {
+	redirects := make([]model.Redirect, 0)
+	if len(projectCodes) < 2 {
+		return redirects, nil
+	}
+
+	db := r.db.WithContext(ctx)
+	scope := fmt.Sprintf("%s = ? AND %s IN ? AND is_published = 1", model.ColumnNamespaceCode, model.ColumnProjectCode)
+	var sources []string
+	err := db.Model(&model.Redirect{}).
+		Where(scope, namespaceCode, projectCodes).
+		Group("source").
+		Having(fmt.Sprintf("COUNT(DISTINCT %s) > 1", model.ColumnProjectCode)).
+		Order("source").
+		Limit(limit).
+		Pluck("source", &sources).Error
+	if err != nil || len(sources) == 0 {
+		return redirects, err
+	}
+
+	err = db.Preload("Project").Preload("Tags").
+		Where(scope+" AND source IN ?", namespaceCode, projectCodes, sources).
+		Order(fmt.Sprintf("source, %s, id", model.ColumnProjectCode)).
+		Find(&redirects).Error
+	if err != nil {
+		return nil, err
+	}
+	return redirects, nil
+}
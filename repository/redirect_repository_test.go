@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
@@ -15,7 +16,7 @@ func setupRedirectTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{})
 	assert.NoError(t, err)
 
 	return db
@@ -414,6 +415,32 @@ func TestRedirectRepository_FindByProjectPublished(t *testing.T) {
 		assert.Equal(t, int64(10), total)
 	})
 
+	t.Run("ordered by priority then id", func(t *testing.T) {
+		db := setupRedirectTestDB(t)
+		createTestRedirectNamespace(t, db, "test-ns", "Test Namespace")
+		createTestRedirectProject(t, db, "test-ns", "test-proj", "Test Project")
+		repo := NewRedirectRepository(db)
+		ctx := context.Background()
+
+		for i, priority := range []int{0, 5, 0, -1, 5} {
+			db.Create(&model.Redirect{
+				NamespaceCode: "test-ns",
+				ProjectCode:   "test-proj",
+				IsPublished:   boolPtr(true),
+				Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: fmt.Sprintf("/r%d", i), Target: "/t", Status: commonTypes.RedirectStatusFound, Priority: priority},
+			})
+		}
+
+		results, _, err := repo.FindByProjectPublished(ctx, "test-ns", "test-proj", 0, 0)
+
+		assert.NoError(t, err)
+		sources := make([]string, 0, len(results))
+		for _, redirect := range results {
+			sources = append(sources, redirect.Source)
+		}
+		assert.Equal(t, []string{"/r1", "/r4", "/r0", "/r2", "/r3"}, sources)
+	})
+
 	t.Run("returns empty when no published redirects", func(t *testing.T) {
 		db := setupRedirectTestDB(t)
 		createTestRedirectNamespace(t, db, "test-ns", "Test Namespace")
@@ -475,6 +502,39 @@ func TestRedirectRepository_FindByProjectPublished(t *testing.T) {
 	})
 }
 
+func TestRedirectRepository_FindByProjectPublishedAfter(t *testing.T) {
+	db := setupRedirectTestDB(t)
+	createTestRedirectNamespace(t, db, "test-ns", "Test Namespace")
+	createTestRedirectProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewRedirectRepository(db)
+	ctx := context.Background()
+
+	for i, priority := range []int{0, 5, 0, -1, 5, 0} {
+		db.Create(&model.Redirect{
+			NamespaceCode: "test-ns",
+			ProjectCode:   "test-proj",
+			IsPublished:   boolPtr(i != 5),
+			Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: fmt.Sprintf("/r%d", i), Target: "/t", Status: commonTypes.RedirectStatusFound, Priority: priority},
+		})
+	}
+
+	// Reading two by two gives the order of FindByProjectPublished, without the unpublished redirect
+	var sources []string
+	var after *model.Redirect
+	for {
+		results, err := repo.FindByProjectPublishedAfter(ctx, "test-ns", "test-proj", after, 2)
+		assert.NoError(t, err)
+		if len(results) == 0 {
+			break
+		}
+		for _, redirect := range results {
+			sources = append(sources, redirect.Source)
+		}
+		after = &results[len(results)-1]
+	}
+	assert.Equal(t, []string{"/r1", "/r4", "/r0", "/r2", "/r3"}, sources)
+}
+
 func TestRedirectRepository_Search(t *testing.T) {
 	db := setupRedirectTestDB(t)
 	createTestRedirectNamespace(t, db, "test-ns", "Test Namespace")
@@ -563,7 +623,7 @@ func TestRedirectRepository_SearchPaginate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			results, total, err := repo.SearchPaginate(ctx, tt.query, tt.limit, tt.offset)
+			results, total, err := repo.SearchPaginate(ctx, tt.query, tt.limit, tt.offset, nil)
 
 			assert.NoError(t, err)
 			assert.Len(t, results, tt.wantCount)
@@ -597,13 +657,51 @@ func TestRedirectRepository_SearchPaginate_WithFilter(t *testing.T) {
 	}
 
 	query := db.Model(&model.Redirect{}).Where("namespace_code = ? AND project_code = ?", "test-ns", "test-proj")
-	results, total, err := repo.SearchPaginate(ctx, query, 5, 0)
+	results, total, err := repo.SearchPaginate(ctx, query, 5, 0, nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 5)
 	assert.Equal(t, int64(10), total)
 }
 
+func TestRedirectRepository_SearchPaginate_OrderBy(t *testing.T) {
+	db := setupRedirectTestDB(t)
+	createTestRedirectNamespace(t, db, "test-ns", "Test Namespace")
+	createTestRedirectProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewRedirectRepository(db)
+	ctx := context.Background()
+
+	for _, source := range []string{"/b", "/c", "/a"} {
+		db.Create(&model.Redirect{
+			NamespaceCode: "test-ns",
+			ProjectCode:   "test-proj",
+			Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "/target"},
+		})
+	}
+	// The join makes unprefixed columns ambiguous
+	query := db.Model(&model.Redirect{}).Joins("LEFT JOIN redirect_drafts ON redirect_drafts.old_redirect_id = redirects.id")
+
+	t.Run("sorts by allowed columns", func(t *testing.T) {
+		results, _, err := repo.SearchPaginate(ctx, query, 10, 0, []commonTypes.SortInput{
+			{Column: "target", Direction: commonTypes.SortASC},
+			{Column: "source", Direction: commonTypes.SortDESC},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, "/c", results[0].Source)
+		assert.Equal(t, "/b", results[1].Source)
+		assert.Equal(t, "/a", results[2].Source)
+	})
+
+	t.Run("rejects unknown column", func(t *testing.T) {
+		results, _, err := repo.SearchPaginate(ctx, nil, 10, 0, []commonTypes.SortInput{{Column: "namespace_code", Direction: commonTypes.SortASC}})
+
+		assert.EqualError(t, err, "column namespace_code cannot be sorted")
+		assert.Nil(t, results)
+	})
+}
+
 func TestRedirectRepository_SearchPaginate_PreloadsRedirectDraft(t *testing.T) {
 	db := setupRedirectTestDB(t)
 	createTestRedirectNamespace(t, db, "test-ns", "Test Namespace")
@@ -631,10 +729,113 @@ func TestRedirectRepository_SearchPaginate_PreloadsRedirectDraft(t *testing.T) {
 	}
 	db.Create(draft)
 
-	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0)
+	results, _, err := repo.SearchPaginate(ctx, nil, 10, 0, nil)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
 	assert.NotNil(t, results[0].RedirectDraft)
 	assert.Equal(t, "/source", results[0].RedirectDraft.NewRedirect.Source)
 }
+
+func TestRedirectRepository_SearchCursor(t *testing.T) {
+	db := setupRedirectTestDB(t)
+	createTestRedirectNamespace(t, db, "test-ns", "Test Namespace")
+	createTestRedirectProject(t, db, "test-ns", "test-proj", "Test Project")
+	repo := NewRedirectRepository(db)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		db.Create(&model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: boolPtr(true)})
+	}
+
+	var ids []int64
+	afterID := int64(0)
+	for page := 0; page < 3; page++ {
+		results, hasMore, err := repo.SearchCursor(ctx, db.Model(&model.Redirect{}).Joins("LEFT JOIN redirect_drafts ON redirect_drafts.old_redirect_id = redirects.id"), afterID, 2)
+
+		assert.NoError(t, err)
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		if !hasMore {
+			assert.Equal(t, 2, page)
+			assert.Len(t, results, 1)
+			break
+		}
+		assert.Len(t, results, 2)
+		afterID = results[len(results)-1].ID
+	}
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+
+	results, hasMore, err := repo.SearchCursor(ctx, nil, 5, 2)
+	assert.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.Empty(t, results)
+}
+
+func TestRedirectRepository_FindSourceOverlaps(t *testing.T) {
+	db := setupRedirectTestDB(t)
+	createTestRedirectNamespace(t, db, "test-ns", "Test Namespace")
+	createTestRedirectNamespace(t, db, "other-ns", "Other Namespace")
+	for _, code := range []string{"proj1", "proj2", "proj3"} {
+		createTestRedirectProject(t, db, "test-ns", code, code)
+	}
+	createTestRedirectProject(t, db, "other-ns", "proj1", "proj1")
+	repo := NewRedirectRepository(db)
+	ctx := context.Background()
+
+	create := func(namespaceCode, projectCode, source string, published bool) {
+		assert.NoError(t, db.Create(&model.Redirect{
+			NamespaceCode: namespaceCode,
+			ProjectCode:   projectCode,
+			IsPublished:   boolPtr(published),
+			Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent},
+		}).Error)
+	}
+	create("test-ns", "proj2", "/shared", true)
+	create("test-ns", "proj1", "/shared", true)
+	create("test-ns", "proj3", "/shared", true)
+	create("test-ns", "proj1", "/also-shared", true)
+	create("test-ns", "proj2", "/also-shared", true)
+	create("test-ns", "proj1", "/same-project", true)
+	create("test-ns", "proj1", "/same-project", true)
+	create("test-ns", "proj1", "/draft", true)
+	create("test-ns", "proj2", "/draft", false)
+	create("test-ns", "proj1", "/other-namespace", true)
+	create("other-ns", "proj1", "/other-namespace", true)
+
+	t.Run("sources of several projects", func(t *testing.T) {
+		redirects, err := repo.FindSourceOverlaps(ctx, "test-ns", []string{"proj1", "proj2", "proj3"}, 10)
+
+		assert.NoError(t, err)
+		var got []string
+		for _, redirect := range redirects {
+			got = append(got, redirect.Source+" "+redirect.ProjectCode)
+			assert.NotNil(t, redirect.Project)
+		}
+		assert.Equal(t, []string{"/also-shared proj1", "/also-shared proj2", "/shared proj1", "/shared proj2", "/shared proj3"}, got)
+	})
+
+	t.Run("limited sources", func(t *testing.T) {
+		redirects, err := repo.FindSourceOverlaps(ctx, "test-ns", []string{"proj1", "proj2", "proj3"}, 1)
+
+		assert.NoError(t, err)
+		assert.Len(t, redirects, 2)
+		assert.Equal(t, "/also-shared", redirects[0].Source)
+	})
+
+	t.Run("restricted to the projects", func(t *testing.T) {
+		redirects, err := repo.FindSourceOverlaps(ctx, "test-ns", []string{"proj1", "proj3"}, 10)
+
+		assert.NoError(t, err)
+		assert.Len(t, redirects, 2)
+		assert.Equal(t, "/shared", redirects[0].Source)
+	})
+
+	t.Run("less than two projects", func(t *testing.T) {
+		redirects, err := repo.FindSourceOverlaps(ctx, "test-ns", []string{"proj1"}, 10)
+
+		assert.NoError(t, err)
+		assert.Empty(t, redirects)
+	})
+}
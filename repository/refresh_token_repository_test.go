@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupRefreshTokenRepositoryTest(t *testing.T) (*gorm.DB, RefreshTokenRepository) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&model.User{}, &model.RefreshToken{})
+	assert.NoError(t, err)
+
+	repo := NewRefreshTokenRepository(db)
+	return db, repo
+}
+
+func createTestRefreshToken(t *testing.T, repo RefreshTokenRepository, userID int64, familyID, hash string) *model.RefreshToken {
+	token := &model.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	assert.NoError(t, repo.Create(context.Background(), token))
+	return token
+}
+
+func TestNewRefreshTokenRepository(t *testing.T) {
+	_, repo := setupRefreshTokenRepositoryTest(t)
+	assert.NotNil(t, repo)
+}
+
+func TestRefreshTokenRepository_GetTx(t *testing.T) {
+	_, repo := setupRefreshTokenRepositoryTest(t)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+}
+
+func TestRefreshTokenRepository_GetQuery(t *testing.T) {
+	_, repo := setupRefreshTokenRepositoryTest(t)
+	assert.NotNil(t, repo.GetQuery(context.Background()))
+}
+
+func TestRefreshTokenRepository_Create(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		_, repo := setupRefreshTokenRepositoryTest(t)
+
+		token := createTestRefreshToken(t, repo, 1, "family", "hash1")
+		assert.NotZero(t, token.ID)
+	})
+
+	t.Run("duplicate hash", func(t *testing.T) {
+		_, repo := setupRefreshTokenRepositoryTest(t)
+		createTestRefreshToken(t, repo, 1, "family", "hash1")
+
+		err := repo.Create(context.Background(), &model.RefreshToken{UserID: 1, FamilyID: "family", TokenHash: "hash1", ExpiresAt: time.Now()})
+		assert.Error(t, err)
+	})
+}
+
+func TestRefreshTokenRepository_FindByHash(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		_, repo := setupRefreshTokenRepositoryTest(t)
+		created := createTestRefreshToken(t, repo, 1, "family", "hash1")
+
+		found, err := repo.FindByHash(context.Background(), "hash1")
+		assert.NoError(t, err)
+		assert.Equal(t, created.ID, found.ID)
+		assert.Equal(t, "family", found.FamilyID)
+		assert.False(t, found.IsRevoked())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, repo := setupRefreshTokenRepositoryTest(t)
+
+		found, err := repo.FindByHash(context.Background(), "unknown")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, found)
+	})
+}
+
+func TestRefreshTokenRepository_Revoke(t *testing.T) {
+	_, repo := setupRefreshTokenRepositoryTest(t)
+	ctx := context.Background()
+	token := createTestRefreshToken(t, repo, 1, "family", "hash1")
+
+	revoked, err := repo.Revoke(ctx, token.ID)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	found, err := repo.FindByHash(ctx, "hash1")
+	assert.NoError(t, err)
+	assert.True(t, found.IsRevoked())
+
+	// a second revoke of the same token reports it was already used
+	revoked, err = repo.Revoke(ctx, token.ID)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestRefreshTokenRepository_RevokeFamily(t *testing.T) {
+	_, repo := setupRefreshTokenRepositoryTest(t)
+	ctx := context.Background()
+	createTestRefreshToken(t, repo, 1, "family-a", "hash1")
+	createTestRefreshToken(t, repo, 1, "family-a", "hash2")
+	createTestRefreshToken(t, repo, 1, "family-b", "hash3")
+
+	err := repo.RevokeFamily(ctx, "family-a")
+	assert.NoError(t, err)
+
+	for hash, want := range map[string]bool{"hash1": true, "hash2": true, "hash3": false} {
+		found, err := repo.FindByHash(ctx, hash)
+		assert.NoError(t, err)
+		assert.Equal(t, want, found.IsRevoked(), hash)
+	}
+}
+
+func TestRefreshTokenRepository_RevokeByUser(t *testing.T) {
+	_, repo := setupRefreshTokenRepositoryTest(t)
+	ctx := context.Background()
+	createTestRefreshToken(t, repo, 1, "family-a", "hash1")
+	createTestRefreshToken(t, repo, 1, "family-b", "hash2")
+	createTestRefreshToken(t, repo, 2, "family-c", "hash3")
+
+	err := repo.RevokeByUser(ctx, 1)
+	assert.NoError(t, err)
+
+	for hash, want := range map[string]bool{"hash1": true, "hash2": true, "hash3": false} {
+		found, err := repo.FindByHash(ctx, hash)
+		assert.NoError(t, err)
+		assert.Equal(t, want, found.IsRevoked(), hash)
+	}
+}
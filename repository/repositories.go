@@ -3,29 +3,67 @@ package repository
 import "gorm.io/gorm"
 
 type Repositories struct {
-	Namespace     NamespaceRepository
-	Project       ProjectRepository
-	User          UserRepository
-	Role          RoleRepository
-	Redirect      RedirectRepository
-	RedirectDraft RedirectDraftRepository
-	Page          PageRepository
-	PageDraft     PageDraftRepository
-	Agent         AgentRepository
-	Token         TokenRepository
+	Namespace       NamespaceRepository
+	Project         ProjectRepository
+	User            UserRepository
+	Role            RoleRepository
+	Redirect        RedirectRepository
+	RedirectDraft   RedirectDraftRepository
+	Page            PageRepository
+	PageDraft       PageDraftRepository
+	PageTemplate    PageTemplateRepository
+	Agent           AgentRepository
+	AgentInstance   AgentInstanceRepository
+	Token           TokenRepository
+	ImportJob       ImportJobRepository
+	RedirectHealth  RedirectHealthRepository
+	Hit             HitRepository
+	Search          SearchRepository
+	RefreshToken    RefreshTokenRepository
+	ProjectGitSync  ProjectGitSyncRepository
+	DraftLock       DraftLockRepository
+	Notification    NotificationSubscriptionRepository
+	Retention       RetentionRepository
+	ProjectAPIKey   ProjectAPIKeyRepository
+	PageLink        PageLinkRepository
+	NamespacePolicy NamespacePolicyRepository
+	ProjectMember   ProjectMemberRepository
+	Group           GroupRepository
+	Changeset       ChangesetRepository
+	ImportProfile   ImportProfileRepository
+	Outbox          OutboxRepository
 }
 
 func NewRepositories(db *gorm.DB) *Repositories {
 	return &Repositories{
-		Namespace:     NewNamespaceRepository(db),
-		Project:       NewProjectRepository(db),
-		User:          NewUserRepository(db),
-		Role:          NewRoleRepository(db),
-		Redirect:      NewRedirectRepository(db),
-		RedirectDraft: NewRedirectDraftRepository(db),
-		Page:          NewPageRepository(db),
-		PageDraft:     NewPageDraftRepository(db),
-		Agent:         NewAgentRepository(db),
-		Token:         NewTokenRepository(db),
+		Namespace:       NewNamespaceRepository(db),
+		Project:         NewProjectRepository(db),
+		User:            NewUserRepository(db),
+		Role:            NewRoleRepository(db),
+		Redirect:        NewRedirectRepository(db),
+		RedirectDraft:   NewRedirectDraftRepository(db),
+		Page:            NewPageRepository(db),
+		PageDraft:       NewPageDraftRepository(db),
+		PageTemplate:    NewPageTemplateRepository(db),
+		Agent:           NewAgentRepository(db),
+		AgentInstance:   NewAgentInstanceRepository(db),
+		Token:           NewTokenRepository(db),
+		ImportJob:       NewImportJobRepository(db),
+		RedirectHealth:  NewRedirectHealthRepository(db),
+		Hit:             NewHitRepository(db),
+		Search:          NewSearchRepository(db),
+		RefreshToken:    NewRefreshTokenRepository(db),
+		ProjectGitSync:  NewProjectGitSyncRepository(db),
+		DraftLock:       NewDraftLockRepository(db),
+		Notification:    NewNotificationSubscriptionRepository(db),
+		Retention:       NewRetentionRepository(db),
+		ProjectAPIKey:   NewProjectAPIKeyRepository(db),
+		PageLink:        NewPageLinkRepository(db),
+		NamespacePolicy: NewNamespacePolicyRepository(db),
+		ProjectMember:   NewProjectMemberRepository(db),
+		Group:           NewGroupRepository(db),
+		Changeset:       NewChangesetRepository(db),
+		ImportProfile:   NewImportProfileRepository(db),
+		Outbox:          NewOutboxRepository(db),
 	}
 }
@@ -29,6 +29,22 @@ func TestNewRepositories(t *testing.T) {
 	assert.NotNil(t, repos.RedirectDraft)
 	assert.NotNil(t, repos.Page)
 	assert.NotNil(t, repos.PageDraft)
+	assert.NotNil(t, repos.PageTemplate)
 	assert.NotNil(t, repos.Agent)
 	assert.NotNil(t, repos.Token)
+	assert.NotNil(t, repos.ImportJob)
+	assert.NotNil(t, repos.RedirectHealth)
+	assert.NotNil(t, repos.Hit)
+	assert.NotNil(t, repos.Search)
+	assert.NotNil(t, repos.ProjectGitSync)
+	assert.NotNil(t, repos.DraftLock)
+	assert.NotNil(t, repos.Notification)
+	assert.NotNil(t, repos.Retention)
+	assert.NotNil(t, repos.ProjectAPIKey)
+	assert.NotNil(t, repos.PageLink)
+	assert.NotNil(t, repos.ProjectMember)
+	assert.NotNil(t, repos.Group)
+	assert.NotNil(t, repos.Changeset)
+	assert.NotNil(t, repos.ImportProfile)
+	assert.NotNil(t, repos.Outbox)
 }
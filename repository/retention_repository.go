@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+// RetentionRepository purges the records older than the retention of their category
+type RetentionRepository interface {
+	GetTx(ctx context.Context) *gorm.DB
+	PurgeImportJobs(ctx context.Context, before time.Time, batchSize int) (int64, error)
+	PurgeTombstones(ctx context.Context, before time.Time, batchSize int) (int64, error)
+	PurgeOutboxEvents(ctx context.Context, before time.Time, batchSize int) (int64, error)
+}
+
+type retentionRepository struct {
+	db *gorm.DB
+}
+
+func NewRetentionRepository(db *gorm.DB) RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+func (r *retentionRepository) GetTx(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+// PurgeImportJobs deletes the import jobs finished before the given time, the running ones being kept
+func (r *retentionRepository) PurgeImportJobs(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return r.purge(ctx, &model.ImportJob{}, batchSize, "status IN ? AND finished_at < ?",
+		[]model.ImportJobStatus{model.ImportJobStatusCompleted, model.ImportJobStatusFailed}, before)
+}
+
+// PurgeTombstones deletes the tombstones of the redirects and pages deleted before the given time
+func (r *retentionRepository) PurgeTombstones(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	redirects, err := r.purge(ctx, &model.RedirectTombstone{}, batchSize, "deleted_at < ?", before)
+	if err != nil {
+		return redirects, err
+	}
+	pages, err := r.purge(ctx, &model.PageTombstone{}, batchSize, "deleted_at < ?", before)
+	return redirects + pages, err
+}
+
+// PurgeOutboxEvents deletes the events of the outbox delivered or given up before the given time, the pending ones being kept
+func (r *retentionRepository) PurgeOutboxEvents(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	return r.purge(ctx, &model.OutboxEvent{}, batchSize, "status IN ? AND processed_at < ?",
+		[]model.OutboxStatus{model.OutboxStatusDelivered, model.OutboxStatusFailed}, before)
+}
+
+// purge deletes the rows of the model matching the condition by batches of batchSize rows,
+// so that a large purge does not lock the table for long. It returns the number of rows deleted.
+func (r *retentionRepository) purge(ctx context.Context, value interface{}, batchSize int, condition string, args ...interface{}) (int64, error) {
+	var total int64
+	for {
+		var ids []int64
+		if err := r.db.WithContext(ctx).Model(value).Where(condition, args...).Order("id").Limit(batchSize).Pluck("id", &ids).Error; err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+
+		result := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(value)
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+		if len(ids) < batchSize {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupRetentionTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ImportJob{}, &model.RedirectTombstone{}, &model.PageTombstone{}, &model.OutboxEvent{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func TestNewRetentionRepository(t *testing.T) {
+	db := setupRetentionTestDB(t)
+	repo := NewRetentionRepository(db)
+
+	assert.NotNil(t, repo)
+	assert.NotNil(t, repo.GetTx(context.Background()))
+}
+
+func TestRetentionRepository_PurgeImportJobs(t *testing.T) {
+	db := setupRetentionTestDB(t)
+	repo := NewRetentionRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	old := now.Add(-48 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	jobs := []model.ImportJob{
+		{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusCompleted, FinishedAt: &old},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusFailed, FinishedAt: &old},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusCompleted, FinishedAt: &old},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusCompleted, FinishedAt: &recent},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusRunning},
+	}
+	assert.NoError(t, db.Create(&jobs).Error)
+
+	count, err := repo.PurgeImportJobs(ctx, now.Add(-24*time.Hour), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	var remaining []model.ImportJob
+	assert.NoError(t, db.Order("id").Find(&remaining).Error)
+	assert.Len(t, remaining, 2)
+	assert.Equal(t, jobs[3].ID, remaining[0].ID)
+	assert.Equal(t, jobs[4].ID, remaining[1].ID)
+
+	count, err = repo.PurgeImportJobs(ctx, now.Add(-24*time.Hour), 2)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestRetentionRepository_PurgeTombstones(t *testing.T) {
+	db := setupRetentionTestDB(t)
+	repo := NewRetentionRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	redirect := func(id int64, deletedAt time.Time) model.RedirectTombstone {
+		return model.RedirectTombstone{NamespaceCode: "ns1", ProjectCode: "proj1", RedirectID: id, DeletedAt: deletedAt,
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}}
+	}
+	page := func(id int64, deletedAt time.Time) model.PageTombstone {
+		return model.PageTombstone{NamespaceCode: "ns1", ProjectCode: "proj1", PageID: id, DeletedAt: deletedAt,
+			Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/page.txt", Content: "content", ContentType: commonTypes.PageContentTypeTextPlain}}
+	}
+	assert.NoError(t, db.Create(&[]model.RedirectTombstone{redirect(1, now.Add(-48*time.Hour)), redirect(2, now)}).Error)
+	assert.NoError(t, db.Create(&[]model.PageTombstone{page(1, now.Add(-48*time.Hour)), page(2, now.Add(-72*time.Hour)), page(3, now)}).Error)
+
+	count, err := repo.PurgeTombstones(ctx, now.Add(-24*time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	var redirects []model.RedirectTombstone
+	assert.NoError(t, db.Find(&redirects).Error)
+	assert.Len(t, redirects, 1)
+	assert.Equal(t, int64(2), redirects[0].RedirectID)
+
+	var pages []model.PageTombstone
+	assert.NoError(t, db.Find(&pages).Error)
+	assert.Len(t, pages, 1)
+	assert.Equal(t, int64(3), pages[0].PageID)
+}
+
+func TestRetentionRepository_PurgeOutboxEvents(t *testing.T) {
+	db := setupRetentionTestDB(t)
+	repo := NewRetentionRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	old := now.Add(-48 * time.Hour)
+
+	events := []model.OutboxEvent{
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusDelivered, ProcessedAt: &old},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusFailed, ProcessedAt: &old},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusDelivered, ProcessedAt: &now},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusPending, CreatedAt: old},
+	}
+	assert.NoError(t, db.Create(&events).Error)
+
+	count, err := repo.PurgeOutboxEvents(ctx, now.Add(-24*time.Hour), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	var remaining []model.OutboxEvent
+	assert.NoError(t, db.Order("id").Find(&remaining).Error)
+	assert.Len(t, remaining, 2)
+	assert.Equal(t, events[2].ID, remaining[0].ID)
+	assert.Equal(t, model.OutboxStatusPending, remaining[1].Status)
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -18,17 +20,23 @@ type RoleRepository interface {
 	FindByCodeAndType(ctx context.Context, code string, roleType model.RoleType) (*model.Role, error)
 	FindAll(ctx context.Context) ([]model.Role, error)
 	FindAllByType(ctx context.Context, roleType model.RoleType) ([]model.Role, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Role, int64, error)
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Role, int64, error)
 
 	// User-Role associations
 	AddUserToRole(ctx context.Context, userID, roleID int64) error
 	RemoveUserFromRole(ctx context.Context, userID, roleID int64) error
 	GetUserRoles(ctx context.Context, userID int64) ([]model.Role, error)
 	GetUserRolesByType(ctx context.Context, userID int64, roleType model.RoleType) ([]model.Role, error)
+	GetUserGroupRoles(ctx context.Context, userID int64) ([]model.Role, error)
 	GetRoleUsers(ctx context.Context, roleID int64) ([]model.User, error)
 	GetRoleUsersPaginate(ctx context.Context, roleID int64, search string, limit, offset int) ([]model.User, int64, error)
 	GetUsersNotInRole(ctx context.Context, roleID int64, search string, limit int) ([]model.User, error)
 	HasUserRole(ctx context.Context, userID, roleID int64) (bool, error)
+
+	// Role inheritance
+	GetParentRoles(ctx context.Context, roleIDs []int64) ([]model.Role, error)
+	FindAllRoleParents(ctx context.Context) ([]model.RoleParent, error)
+	SetRoleParents(ctx context.Context, roleID int64, parentIDs []int64) error
 }
 
 type roleRepository struct {
@@ -61,6 +69,14 @@ func (r *roleRepository) Delete(ctx context.Context, id int64) error {
 		if err := tx.Where("role_id = ?", id).Delete(&model.UserRole{}).Error; err != nil {
 			return err
 		}
+		// Delete group_roles associations
+		if err := tx.Where("role_id = ?", id).Delete(&model.GroupRole{}).Error; err != nil {
+			return err
+		}
+		// Delete role_parents links in both directions
+		if err := tx.Where("role_id = ? OR parent_id = ?", id, id).Delete(&model.RoleParent{}).Error; err != nil {
+			return err
+		}
 		// Delete role
 		return tx.Where("id = ?", id).Delete(&model.Role{}).Error
 	})
@@ -105,7 +121,7 @@ func (r *roleRepository) FindAllByType(ctx context.Context, roleType model.RoleT
 	return roles, err
 }
 
-func (r *roleRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Role, int64, error) {
+func (r *roleRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Role, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.Role{}).Preload("Resources").Preload("Admin")
@@ -115,6 +131,11 @@ func (r *roleRepository) SearchPaginate(ctx context.Context, query *gorm.DB, lim
 		return nil, 0, err
 	}
 
+	query, err := database.ApplyOrderBy(query, model.RoleSortableColumns, orderBy, "roles")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit > 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
@@ -159,6 +180,18 @@ func (r *roleRepository) GetUserRolesByType(ctx context.Context, userID int64, r
 	return roles, err
 }
 
+// GetUserGroupRoles returns the roles assigned to the groups of a user, with their permissions
+func (r *roleRepository) GetUserGroupRoles(ctx context.Context, userID int64) ([]model.Role, error) {
+	var roles []model.Role
+	err := r.db.WithContext(ctx).Preload("Resources").Preload("Admin").
+		Where("id IN (?)", r.db.Model(&model.GroupRole{}).Select("group_roles.role_id").
+			Joins("JOIN user_groups ON user_groups.group_id = group_roles.group_id").
+			Where("user_groups.user_id = ?", userID)).
+		Order("code").
+		Find(&roles).Error
+	return roles, err
+}
+
 func (r *roleRepository) GetRoleUsers(ctx context.Context, roleID int64) ([]model.User, error) {
 	var users []model.User
 	err := r.db.WithContext(ctx).
@@ -234,3 +267,40 @@ func (r *roleRepository) GetUsersNotInRole(ctx context.Context, roleID int64, se
 
 	return users, nil
 }
+
+// GetParentRoles returns the direct parent roles of the given roles, with their permissions
+func (r *roleRepository) GetParentRoles(ctx context.Context, roleIDs []int64) ([]model.Role, error) {
+	var roles []model.Role
+	if len(roleIDs) == 0 {
+		return roles, nil
+	}
+	err := r.db.WithContext(ctx).Preload("Resources").Preload("Admin").
+		Where("id IN (?)", r.db.Model(&model.RoleParent{}).Select("parent_id").Where("role_id IN ?", roleIDs)).
+		Order("code").
+		Find(&roles).Error
+	return roles, err
+}
+
+// FindAllRoleParents returns every inheritance link, used to detect cycles
+func (r *roleRepository) FindAllRoleParents(ctx context.Context) ([]model.RoleParent, error) {
+	var links []model.RoleParent
+	err := r.db.WithContext(ctx).Find(&links).Error
+	return links, err
+}
+
+// SetRoleParents replaces the parents of a role
+func (r *roleRepository) SetRoleParents(ctx context.Context, roleID int64, parentIDs []int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.RoleParent{}).Error; err != nil {
+			return err
+		}
+		if len(parentIDs) == 0 {
+			return nil
+		}
+		links := make([]model.RoleParent, len(parentIDs))
+		for i, parentID := range parentIDs {
+			links[i] = model.RoleParent{RoleID: roleID, ParentID: parentID}
+		}
+		return tx.Create(&links).Error
+	})
+}
@@ -14,7 +14,8 @@ func setupRoleTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&model.User{}, &model.Role{}, &model.UserRole{}, &model.AdminPermission{}, &model.ResourcePermission{})
+	err = db.AutoMigrate(&model.User{}, &model.Role{}, &model.UserRole{}, &model.RoleParent{}, &model.AdminPermission{}, &model.ResourcePermission{},
+		&model.Group{}, &model.UserGroup{}, &model.GroupRole{})
 	assert.NoError(t, err)
 
 	return db
@@ -354,21 +355,21 @@ func TestRoleRepository_SearchPaginate(t *testing.T) {
 	}
 
 	t.Run("paginate with limit", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, nil, 5, 0)
+		results, total, err := repo.SearchPaginate(ctx, nil, 5, 0, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 5)
 		assert.Equal(t, int64(10), total)
 	})
 
 	t.Run("paginate with offset", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, nil, 5, 5)
+		results, total, err := repo.SearchPaginate(ctx, nil, 5, 5, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 5)
 		assert.Equal(t, int64(10), total)
 	})
 
 	t.Run("paginate without limit returns all", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, nil, 0, 0)
+		results, total, err := repo.SearchPaginate(ctx, nil, 0, 0, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 10)
 		assert.Equal(t, int64(10), total)
@@ -465,6 +466,55 @@ func TestRoleRepository_GetUserRoles(t *testing.T) {
 	})
 }
 
+func TestRoleRepository_GetUserGroupRoles(t *testing.T) {
+	db := setupRoleTestDB(t)
+	repo := NewRoleRepository(db)
+	groupRepo := NewGroupRepository(db)
+	userRepo := NewUserRepository(db)
+	ctx := context.Background()
+
+	user := &model.User{Username: "testuser", Active: boolPtr(true)}
+	assert.NoError(t, userRepo.Create(ctx, user))
+	editor := &model.Role{Code: "editor", Type: model.RoleTypeRole, Resources: []model.ResourcePermission{
+		{Namespace: "ns1", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionWrite},
+	}}
+	viewer := &model.Role{Code: "viewer", Type: model.RoleTypeRole}
+	other := &model.Role{Code: "other", Type: model.RoleTypeRole}
+	for _, role := range []*model.Role{editor, viewer, other} {
+		assert.NoError(t, repo.Create(ctx, role))
+	}
+
+	writers := &model.Group{Code: "writers", Name: "Writers"}
+	readers := &model.Group{Code: "readers", Name: "Readers"}
+	others := &model.Group{Code: "others", Name: "Others"}
+	for _, group := range []*model.Group{writers, readers, others} {
+		assert.NoError(t, groupRepo.Create(ctx, group))
+	}
+	assert.NoError(t, groupRepo.SetGroupRoles(ctx, writers.ID, []int64{editor.ID, viewer.ID}))
+	assert.NoError(t, groupRepo.SetGroupRoles(ctx, readers.ID, []int64{viewer.ID}))
+	assert.NoError(t, groupRepo.SetGroupRoles(ctx, others.ID, []int64{other.ID}))
+	assert.NoError(t, groupRepo.SetGroupUsers(ctx, writers.ID, []int64{user.ID}))
+	assert.NoError(t, groupRepo.SetGroupUsers(ctx, readers.ID, []int64{user.ID}))
+
+	t.Run("roles of the groups of the user", func(t *testing.T) {
+		roles, err := repo.GetUserGroupRoles(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Len(t, roles, 2)
+		assert.Equal(t, "editor", roles[0].Code)
+		assert.Len(t, roles[0].Resources, 1)
+		assert.Equal(t, "viewer", roles[1].Code)
+	})
+
+	t.Run("deleting a role removes it from the groups", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(ctx, viewer.ID))
+
+		roles, err := repo.GetUserGroupRoles(ctx, user.ID)
+		assert.NoError(t, err)
+		assert.Len(t, roles, 1)
+		assert.Equal(t, "editor", roles[0].Code)
+	})
+}
+
 func TestRoleRepository_GetRoleUsers(t *testing.T) {
 	db := setupRoleTestDB(t)
 	repo := NewRoleRepository(db)
@@ -525,3 +575,86 @@ func TestRoleRepository_HasUserRole(t *testing.T) {
 		assert.True(t, hasRole)
 	})
 }
+
+func TestRoleRepository_SetRoleParents(t *testing.T) {
+	db := setupRoleTestDB(t)
+	repo := NewRoleRepository(db)
+	ctx := context.Background()
+
+	child := &model.Role{Code: "editor", Type: model.RoleTypeRole}
+	parentA := &model.Role{Code: "reader", Type: model.RoleTypeRole}
+	parentB := &model.Role{Code: "auditor", Type: model.RoleTypeRole}
+	for _, role := range []*model.Role{child, parentA, parentB} {
+		assert.NoError(t, repo.Create(ctx, role))
+	}
+
+	err := repo.SetRoleParents(ctx, child.ID, []int64{parentA.ID, parentB.ID})
+	assert.NoError(t, err)
+
+	links, err := repo.FindAllRoleParents(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, links, 2)
+
+	// replacing drops the previous links
+	err = repo.SetRoleParents(ctx, child.ID, []int64{parentB.ID})
+	assert.NoError(t, err)
+
+	links, err = repo.FindAllRoleParents(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.RoleParent{{RoleID: child.ID, ParentID: parentB.ID, CreatedAt: links[0].CreatedAt}}, links)
+
+	err = repo.SetRoleParents(ctx, child.ID, nil)
+	assert.NoError(t, err)
+
+	links, err = repo.FindAllRoleParents(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, links)
+}
+
+func TestRoleRepository_GetParentRoles(t *testing.T) {
+	db := setupRoleTestDB(t)
+	repo := NewRoleRepository(db)
+	ctx := context.Background()
+
+	child := &model.Role{Code: "editor", Type: model.RoleTypeRole}
+	parent := &model.Role{
+		Code: "reader",
+		Type: model.RoleTypeRole,
+		Resources: []model.ResourcePermission{
+			{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+		},
+	}
+	other := &model.Role{Code: "other", Type: model.RoleTypeRole}
+	for _, role := range []*model.Role{child, parent, other} {
+		assert.NoError(t, repo.Create(ctx, role))
+	}
+	assert.NoError(t, repo.SetRoleParents(ctx, child.ID, []int64{parent.ID}))
+
+	t.Run("returns direct parents with permissions", func(t *testing.T) {
+		parents, err := repo.GetParentRoles(ctx, []int64{child.ID})
+		assert.NoError(t, err)
+		assert.Len(t, parents, 1)
+		assert.Equal(t, "reader", parents[0].Code)
+		assert.Len(t, parents[0].Resources, 1)
+	})
+
+	t.Run("role without parents", func(t *testing.T) {
+		parents, err := repo.GetParentRoles(ctx, []int64{other.ID})
+		assert.NoError(t, err)
+		assert.Empty(t, parents)
+	})
+
+	t.Run("no role ids", func(t *testing.T) {
+		parents, err := repo.GetParentRoles(ctx, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, parents)
+	})
+
+	t.Run("delete removes links", func(t *testing.T) {
+		assert.NoError(t, repo.Delete(ctx, parent.ID))
+
+		links, err := repo.FindAllRoleParents(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, links)
+	})
+}
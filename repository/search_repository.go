@@ -0,0 +1,371 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+const (
+	searchExcerptLength = 160
+	// searchExcerptContext is the length of the content kept before the first matching term
+	searchExcerptContext = 40
+	// searchExactMatchScore ranks a redirect source or a page path equal to the query first
+	searchExactMatchScore = 10
+)
+
+type SearchRepository interface {
+	SearchAll(ctx context.Context, namespaceCode, query string, scope model.SearchScope) ([]model.SearchResult, error)
+}
+
+// searchTable describes how a table is searched, the title column is weighted over the text column
+type searchTable struct {
+	resultType  model.SearchResultType
+	table       string
+	titleColumn string
+	textColumn  string
+	// plainTextCondition restricts the matches on the text column to the rows holding it as plain text, empty when
+	// it always does
+	plainTextCondition string
+}
+
+var (
+	redirectSearchTable = searchTable{resultType: model.SearchResultTypeRedirect, table: "redirects", titleColumn: "source", textColumn: "target"}
+	// The compressed page contents and the ones moved to a content store cannot be searched in the database, the
+	// column holding their encoded form or their key, those pages are only matched on their path
+	pageSearchTable = searchTable{
+		resultType:         model.SearchResultTypePage,
+		table:              "pages",
+		titleColumn:        "path",
+		textColumn:         "content",
+		plainTextCondition: fmt.Sprintf("content_encoding = '%s' AND content_store = '%s'", model.PageContentEncodingIdentity, model.PageContentStoreDatabase),
+	}
+)
+
+// textMatch restricts a condition matching the text column to the rows holding it as plain text
+func (t searchTable) textMatch(condition string) string {
+	if t.plainTextCondition == "" {
+		return condition
+	}
+	return "(" + condition + " AND " + t.plainTextCondition + ")"
+}
+
+type searchRank struct {
+	ID    int64
+	Score float64
+}
+
+// searchRanker returns the ids of the rows of a table matching all the terms, best ranked first
+type searchRanker interface {
+	rank(db *gorm.DB, table searchTable, namespaceCode string, projectCodes []string, query string, terms []string, limit int) ([]searchRank, error)
+}
+
+type searchRepository struct {
+	db     *gorm.DB
+	ranker searchRanker
+}
+
+// NewSearchRepository uses the FULLTEXT indexes on MySQL and the FTS5 tables on SQLite when its library enables FTS5,
+// other databases are searched with LIKE
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	var ranker searchRanker = likeSearchRanker{}
+	switch {
+	case db.Dialector.Name() == "mysql":
+		ranker = mysqlSearchRanker{}
+	case db.Dialector.Name() == "sqlite" && sqliteFTS5Enabled(db):
+		ranker = sqliteSearchRanker{}
+	}
+	return &searchRepository{db: db, ranker: ranker}
+}
+
+func (r *searchRepository) SearchAll(ctx context.Context, namespaceCode, query string, scope model.SearchScope) ([]model.SearchResult, error) {
+	results := make([]model.SearchResult, 0)
+	terms := model.SearchTerms(query)
+	if len(terms) == 0 || scope.Limit <= 0 {
+		return results, nil
+	}
+	query = strings.TrimSpace(query)
+	db := r.db.WithContext(ctx)
+
+	var redirectRanks, pageRanks []searchRank
+	var err error
+	if len(scope.RedirectProjectCodes) > 0 {
+		redirectRanks, err = r.ranker.rank(db, redirectSearchTable, namespaceCode, scope.RedirectProjectCodes, query, terms, scope.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(scope.PageProjectCodes) > 0 {
+		pageRanks, err = r.ranker.rank(db, pageSearchTable, namespaceCode, scope.PageProjectCodes, query, terms, scope.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	redirects := make(map[int64]model.Redirect, len(redirectRanks))
+	if len(redirectRanks) > 0 {
+		var rows []model.Redirect
+		err = db.Select("id", model.ColumnNamespaceCode, model.ColumnProjectCode, "source", "target").
+			Where("id IN ?", searchRankIDs(redirectRanks)).
+			Find(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			redirects[row.ID] = row
+		}
+	}
+	pages := make(map[int64]model.Page, len(pageRanks))
+	if len(pageRanks) > 0 {
+		var rows []model.Page
+		err = db.Select("id", model.ColumnNamespaceCode, model.ColumnProjectCode, "path", "content", "content_encoding", "content_store").
+			Where("id IN ?", searchRankIDs(pageRanks)).
+			Find(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			pages[row.ID] = row
+		}
+	}
+
+	for _, rank := range redirectRanks {
+		redirect, ok := redirects[rank.ID]
+		if !ok || redirect.Redirect == nil {
+			continue
+		}
+		results = append(results, model.SearchResult{
+			Type:          model.SearchResultTypeRedirect,
+			ID:            redirect.ID,
+			NamespaceCode: redirect.NamespaceCode,
+			ProjectCode:   redirect.ProjectCode,
+			Title:         redirect.Source,
+			Excerpt:       redirect.Target,
+			Rank:          rank.Score,
+		})
+	}
+	for _, rank := range pageRanks {
+		page, ok := pages[rank.ID]
+		if !ok || page.Page == nil {
+			continue
+		}
+		results = append(results, model.SearchResult{
+			Type:          model.SearchResultTypePage,
+			ID:            page.ID,
+			NamespaceCode: page.NamespaceCode,
+			ProjectCode:   page.ProjectCode,
+			Title:         page.Path,
+			Excerpt:       searchExcerpt(page.Content, terms),
+			Rank:          rank.Score,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+	if len(results) > scope.Limit {
+		results = results[:scope.Limit]
+	}
+	return results, nil
+}
+
+func searchRankIDs(ranks []searchRank) []int64 {
+	ids := make([]int64, 0, len(ranks))
+	for _, rank := range ranks {
+		ids = append(ids, rank.ID)
+	}
+	return ids
+}
+
+// mysqlSearchRanker ranks rows with MATCH ... AGAINST on the FULLTEXT indexes of the title and text columns
+type mysqlSearchRanker struct{}
+
+func (mysqlSearchRanker) rank(db *gorm.DB, table searchTable, namespaceCode string, projectCodes []string, query string, terms []string, limit int) ([]searchRank, error) {
+	against := mysqlBooleanQuery(terms)
+	match := fmt.Sprintf("MATCH(%s, %s) AGAINST (? IN BOOLEAN MODE)", table.titleColumn, table.textColumn)
+	matchArgs := []interface{}{against}
+	if table.plainTextCondition != "" {
+		match = fmt.Sprintf("MATCH(%s) AGAINST (? IN BOOLEAN MODE) OR %s", table.titleColumn, table.textMatch(match))
+		matchArgs = append(matchArgs, against)
+	}
+	var ranks []searchRank
+	err := db.Table(table.table).
+		Select(fmt.Sprintf(
+			"id, CASE WHEN %[1]s = ? THEN %[3]d ELSE 0 END + MATCH(%[1]s) AGAINST (? IN BOOLEAN MODE) * 2 + MATCH(%[1]s, %[2]s) AGAINST (? IN BOOLEAN MODE) AS score",
+			table.titleColumn, table.textColumn, searchExactMatchScore,
+		), query, against, against).
+		Where(fmt.Sprintf("%s = ? AND %s IN ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCodes).
+		Where(match, matchArgs...).
+		Order("score DESC, id").
+		Limit(limit).
+		Scan(&ranks).Error
+	return ranks, err
+}
+
+// mysqlBooleanQuery requires every term, each term also matching the words it prefixes
+func mysqlBooleanQuery(terms []string) string {
+	words := make([]string, 0, len(terms))
+	for _, term := range terms {
+		words = append(words, "+"+term+"*")
+	}
+	return strings.Join(words, " ")
+}
+
+// sqliteFTS5Enabled returns true if the SQLite library was built with FTS5, e.g. with the sqlite_fts5 build tag
+func sqliteFTS5Enabled(db *gorm.DB) bool {
+	var enabled bool
+	return db.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&enabled).Error == nil && enabled
+}
+
+// sqliteSearchRanker ranks rows with bm25 on a FTS5 table indexing the title and text columns of the searched table,
+// a match in the title weighing twice a match in the text
+type sqliteSearchRanker struct{}
+
+// sqliteFTSTable returns the name of the FTS5 table indexing a searched table
+func sqliteFTSTable(table searchTable) string {
+	return table.table + "_fts"
+}
+
+// ensureIndex creates the FTS5 table of a searched table, with the triggers keeping it in sync, and indexes the rows
+// of the table when the FTS5 table does not exist yet
+func (sqliteSearchRanker) ensureIndex(db *gorm.DB, table searchTable) error {
+	fts := sqliteFTSTable(table)
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", fts).Scan(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	columns := table.titleColumn + ", " + table.textColumn
+	newValues := "new." + table.titleColumn + ", new." + table.textColumn
+	oldValues := "old." + table.titleColumn + ", old." + table.textColumn
+	statements := []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %[1]s USING fts5(%[2]s, content='%[3]s', content_rowid='id')", fts, columns, table.table),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %[1]s_insert AFTER INSERT ON %[2]s BEGIN INSERT INTO %[1]s(rowid, %[3]s) VALUES (new.id, %[4]s); END", fts, table.table, columns, newValues),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %[1]s_delete AFTER DELETE ON %[2]s BEGIN INSERT INTO %[1]s(%[1]s, rowid, %[3]s) VALUES ('delete', old.id, %[4]s); END", fts, table.table, columns, oldValues),
+		fmt.Sprintf("CREATE TRIGGER IF NOT EXISTS %[1]s_update AFTER UPDATE ON %[2]s BEGIN INSERT INTO %[1]s(%[1]s, rowid, %[3]s) VALUES ('delete', old.id, %[4]s); INSERT INTO %[1]s(rowid, %[3]s) VALUES (new.id, %[5]s); END", fts, table.table, columns, oldValues, newValues),
+		fmt.Sprintf("INSERT INTO %[1]s(%[1]s) VALUES ('rebuild')", fts),
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r sqliteSearchRanker) rank(db *gorm.DB, table searchTable, namespaceCode string, projectCodes []string, query string, terms []string, limit int) ([]searchRank, error) {
+	if err := r.ensureIndex(db, table); err != nil {
+		return nil, err
+	}
+
+	fts := sqliteFTSTable(table)
+	match := sqliteMatchQuery(terms)
+	q := db.Table(fts).
+		Joins(fmt.Sprintf("JOIN %[1]s ON %[1]s.id = %[2]s.rowid", table.table, fts)).
+		Where(fmt.Sprintf("%s MATCH ?", fts), match).
+		Where(fmt.Sprintf("%[1]s.%[2]s = ? AND %[1]s.%[3]s IN ?", table.table, model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCodes)
+	if table.plainTextCondition != "" {
+		// The rows whose text is not plain text must match all the terms in their title
+		q = q.Where(fmt.Sprintf("(%[1]s) OR %[2]s.id IN (SELECT rowid FROM %[3]s WHERE %[3]s MATCH ?)", table.plainTextCondition, table.table, fts),
+			fmt.Sprintf("{%s} : (%s)", table.titleColumn, match))
+	}
+
+	var ranks []searchRank
+	err := q.Select(fmt.Sprintf(
+		"%[1]s.id AS id, CASE WHEN LOWER(%[1]s.%[2]s) = ? THEN %[3]d ELSE 0 END - bm25(%[4]s, 2.0, 1.0) AS score",
+		table.table, table.titleColumn, searchExactMatchScore, fts,
+	), strings.ToLower(query)).
+		Order("score DESC, id").
+		Limit(limit).
+		Scan(&ranks).Error
+	return ranks, err
+}
+
+// sqliteMatchQuery requires every term, each term also matching the words it prefixes
+func sqliteMatchQuery(terms []string) string {
+	words := make([]string, 0, len(terms))
+	for _, term := range terms {
+		words = append(words, `"`+term+`"*`)
+	}
+	return strings.Join(words, " AND ")
+}
+
+// likeSearchRanker ranks rows on the number of terms found in the title and text columns.
+// Terms only contain letters and digits, they never need to be escaped in a LIKE pattern.
+type likeSearchRanker struct{}
+
+func (likeSearchRanker) rank(db *gorm.DB, table searchTable, namespaceCode string, projectCodes []string, query string, terms []string, limit int) ([]searchRank, error) {
+	scoreParts := []string{fmt.Sprintf("CASE WHEN LOWER(%s) = ? THEN %d ELSE 0 END", table.titleColumn, searchExactMatchScore)}
+	scoreArgs := []interface{}{strings.ToLower(query)}
+	q := db.Table(table.table).
+		Where(fmt.Sprintf("%s = ? AND %s IN ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCodes)
+	for _, term := range terms {
+		pattern := "%" + term + "%"
+		scoreParts = append(scoreParts,
+			fmt.Sprintf("CASE WHEN LOWER(%s) LIKE ? THEN 2 ELSE 0 END", table.titleColumn),
+			fmt.Sprintf("CASE WHEN %s THEN 1 ELSE 0 END", table.textMatch(fmt.Sprintf("LOWER(%s) LIKE ?", table.textColumn))),
+		)
+		scoreArgs = append(scoreArgs, pattern, pattern)
+		q = q.Where(fmt.Sprintf("LOWER(%s) LIKE ? OR %s", table.titleColumn, table.textMatch(fmt.Sprintf("LOWER(%s) LIKE ?", table.textColumn))), pattern, pattern)
+	}
+
+	var ranks []searchRank
+	err := q.Select("id, "+strings.Join(scoreParts, " + ")+" AS score", scoreArgs...).
+		Order("score DESC, id").
+		Limit(limit).
+		Scan(&ranks).Error
+	return ranks, err
+}
+
+// searchExcerpt returns an extract of the content around the first matching term, whitespace being collapsed
+func searchExcerpt(content string, terms []string) string {
+	text := strings.Join(strings.Fields(content), " ")
+	if len(text) <= searchExcerptLength {
+		return text
+	}
+
+	position := -1
+	lower := strings.ToLower(text)
+	// Lowercasing can change the length of some characters, positions are then not reliable
+	if len(lower) == len(text) {
+		for _, term := range terms {
+			if i := strings.Index(lower, term); i >= 0 && (position < 0 || i < position) {
+				position = i
+			}
+		}
+	}
+
+	start := 0
+	if position > searchExcerptContext {
+		start = position - searchExcerptContext
+	}
+	if start+searchExcerptLength > len(text) {
+		start = len(text) - searchExcerptLength
+	}
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+	end := start + searchExcerptLength
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end--
+	}
+
+	excerpt := text[start:end]
+	if start > 0 {
+		excerpt = "…" + excerpt
+	}
+	if end < len(text) {
+		excerpt += "…"
+	}
+	return excerpt
+}
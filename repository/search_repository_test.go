@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSearchTestDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.Page{})
+	assert.NoError(t, err)
+
+	return db
+}
+
+func createTestSearchRedirect(t *testing.T, db *gorm.DB, projectCode, source, target string) *model.Redirect {
+	redirect := &model.Redirect{
+		NamespaceCode: "ns1",
+		ProjectCode:   projectCode,
+		Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: target},
+	}
+	assert.NoError(t, db.Create(redirect).Error)
+	return redirect
+}
+
+func createTestSearchPage(t *testing.T, db *gorm.DB, projectCode, path, content string) *model.Page {
+	page := &model.Page{
+		NamespaceCode: "ns1",
+		ProjectCode:   projectCode,
+		Page:          &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: path, Content: content, ContentType: commonTypes.PageContentTypeTextPlain},
+	}
+	assert.NoError(t, db.Create(page).Error)
+	return page
+}
+
+func TestNewSearchRepository(t *testing.T) {
+	db := setupSearchTestDB(t)
+	repo := NewSearchRepository(db)
+
+	assert.NotNil(t, repo)
+	if sqliteFTS5Enabled(db) {
+		assert.IsType(t, sqliteSearchRanker{}, repo.(*searchRepository).ranker)
+	} else {
+		assert.IsType(t, likeSearchRanker{}, repo.(*searchRepository).ranker)
+	}
+}
+
+func TestSearchRepository_SearchAll(t *testing.T) {
+	db := setupSearchTestDB(t)
+	repo := &searchRepository{db: db, ranker: likeSearchRanker{}}
+	ctx := context.Background()
+
+	blogRedirect := createTestSearchRedirect(t, db, "proj1", "/blog", "/news")
+	postRedirect := createTestSearchRedirect(t, db, "proj1", "/old/post", "https://example.com/blog/post")
+	createTestSearchRedirect(t, db, "proj1", "/contact", "/about")
+	otherProjectRedirect := createTestSearchRedirect(t, db, "proj2", "/blog/archive", "/archive")
+	blogPage := createTestSearchPage(t, db, "proj1", "/robots.txt", "Disallow: /Blog/drafts")
+	createTestSearchPage(t, db, "proj2", "/blog.txt", "blog")
+	scope := model.SearchScope{RedirectProjectCodes: []string{"proj1"}, PageProjectCodes: []string{"proj1"}, Limit: 20}
+
+	t.Run("ranks exact and title matches first", func(t *testing.T) {
+		results, err := repo.SearchAll(ctx, "ns1", "/Blog", scope)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, blogRedirect.ID, results[0].ID)
+		assert.Equal(t, model.SearchResultTypeRedirect, results[0].Type)
+		assert.Equal(t, "/blog", results[0].Title)
+		assert.Equal(t, "/news", results[0].Excerpt)
+		assert.Equal(t, float64(12), results[0].Rank)
+		assert.Equal(t, postRedirect.ID, results[1].ID)
+		assert.Equal(t, blogPage.ID, results[2].ID)
+		assert.Equal(t, model.SearchResultTypePage, results[2].Type)
+		assert.Equal(t, "Disallow: /Blog/drafts", results[2].Excerpt)
+		assert.Equal(t, "proj1", results[2].ProjectCode)
+		assert.Equal(t, "ns1", results[2].NamespaceCode)
+	})
+
+	t.Run("requires all terms", func(t *testing.T) {
+		results, err := repo.SearchAll(ctx, "ns1", "old post", scope)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, postRedirect.ID, results[0].ID)
+	})
+
+	t.Run("searches the projects of the scope only", func(t *testing.T) {
+		results, err := repo.SearchAll(ctx, "ns1", "archive", model.SearchScope{RedirectProjectCodes: []string{"proj2"}, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, otherProjectRedirect.ID, results[0].ID)
+
+		results, err = repo.SearchAll(ctx, "ns1", "blog", model.SearchScope{PageProjectCodes: []string{"proj1"}, Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, blogPage.ID, results[0].ID)
+	})
+
+	t.Run("applies the limit", func(t *testing.T) {
+		results, err := repo.SearchAll(ctx, "ns1", "blog", model.SearchScope{RedirectProjectCodes: []string{"proj1"}, PageProjectCodes: []string{"proj1"}, Limit: 1})
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, blogRedirect.ID, results[0].ID)
+	})
+
+	t.Run("matches the compressed and externally stored pages on their path only", func(t *testing.T) {
+		compressedPage := createTestSearchPage(t, db, "proj3", "/compressed", "KLUv/QBYfAAAbmV3cyBibG9n")
+		assert.NoError(t, db.Model(compressedPage).Update("content_encoding", model.PageContentEncodingZstd).Error)
+		storedPage := createTestSearchPage(t, db, "proj3", "/stored/news", "news/kluv")
+		assert.NoError(t, db.Model(storedPage).Update("content_store", model.PageContentStoreS3).Error)
+		pageScope := model.SearchScope{PageProjectCodes: []string{"proj3"}, Limit: 20}
+
+		results, err := repo.SearchAll(ctx, "ns1", "kluv", pageScope)
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+
+		results, err = repo.SearchAll(ctx, "ns1", "news", pageScope)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, storedPage.ID, results[0].ID)
+	})
+
+	t.Run("empty query or scope", func(t *testing.T) {
+		results, err := repo.SearchAll(ctx, "ns1", " / ", scope)
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+
+		results, err = repo.SearchAll(ctx, "ns1", "blog", model.SearchScope{Limit: 20})
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+
+		results, err = repo.SearchAll(ctx, "other", "blog", scope)
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+// TestSearchRepository_SearchAll_FTS5 needs a SQLite library built with FTS5, e.g. go test -tags sqlite_fts5
+func TestSearchRepository_SearchAll_FTS5(t *testing.T) {
+	db := setupSearchTestDB(t)
+	if !sqliteFTS5Enabled(db) {
+		t.Skip("the SQLite library is built without FTS5")
+	}
+	ctx := context.Background()
+
+	// Indexed when the FTS5 table is created
+	blogRedirect := createTestSearchRedirect(t, db, "proj1", "/blog", "/news")
+	repo := &searchRepository{db: db, ranker: sqliteSearchRanker{}}
+	// Indexed by the triggers
+	postRedirect := createTestSearchRedirect(t, db, "proj1", "/old/post", "https://example.com/blog/post")
+	archiveRedirect := createTestSearchRedirect(t, db, "proj1", "/blog/2020", "/archive")
+	createTestSearchRedirect(t, db, "proj1", "/contact", "/about")
+	blogPage := createTestSearchPage(t, db, "proj1", "/robots.txt", "Disallow: /Blog/drafts")
+	scope := model.SearchScope{RedirectProjectCodes: []string{"proj1"}, PageProjectCodes: []string{"proj1"}, Limit: 20}
+
+	t.Run("ranks exact and title matches first", func(t *testing.T) {
+		results, err := repo.SearchAll(ctx, "ns1", "/Blog", scope)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 4)
+		assert.Equal(t, blogRedirect.ID, results[0].ID)
+		assert.Greater(t, results[0].Rank, float64(searchExactMatchScore))
+		assert.Equal(t, archiveRedirect.ID, results[1].ID)
+		assert.Less(t, results[1].Rank, float64(searchExactMatchScore))
+		assert.ElementsMatch(t, []int64{postRedirect.ID, blogPage.ID}, []int64{results[2].ID, results[3].ID})
+	})
+
+	t.Run("requires all terms and matches their prefixes", func(t *testing.T) {
+		results, err := repo.SearchAll(ctx, "ns1", "ol pos", scope)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, postRedirect.ID, results[0].ID)
+	})
+
+	t.Run("follows the updates and deletions", func(t *testing.T) {
+		contact := createTestSearchRedirect(t, db, "proj1", "/team", "/people")
+		assert.NoError(t, db.Model(contact).Update("target", "/staff").Error)
+
+		results, err := repo.SearchAll(ctx, "ns1", "staff", scope)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+
+		results, err = repo.SearchAll(ctx, "ns1", "people", scope)
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+
+		assert.NoError(t, db.Delete(contact).Error)
+		results, err = repo.SearchAll(ctx, "ns1", "staff", scope)
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+	})
+
+	t.Run("matches the compressed and externally stored pages on their path only", func(t *testing.T) {
+		compressedPage := createTestSearchPage(t, db, "proj3", "/compressed", "KLUv/QBYfAAAbmV3cyBibG9n")
+		assert.NoError(t, db.Model(compressedPage).Update("content_encoding", model.PageContentEncodingZstd).Error)
+		storedPage := createTestSearchPage(t, db, "proj3", "/stored/news", "news/kluv")
+		assert.NoError(t, db.Model(storedPage).Update("content_store", model.PageContentStoreS3).Error)
+		pageScope := model.SearchScope{PageProjectCodes: []string{"proj3"}, Limit: 20}
+
+		results, err := repo.SearchAll(ctx, "ns1", "kluv", pageScope)
+		assert.NoError(t, err)
+		assert.Empty(t, results)
+
+		results, err = repo.SearchAll(ctx, "ns1", "news", pageScope)
+		assert.NoError(t, err)
+		assert.Len(t, results, 1)
+		assert.Equal(t, storedPage.ID, results[0].ID)
+	})
+}
+
+func TestSqliteMatchQuery(t *testing.T) {
+	assert.Equal(t, `"blog"* AND "post"*`, sqliteMatchQuery([]string{"blog", "post"}))
+}
+
+func TestMysqlBooleanQuery(t *testing.T) {
+	assert.Equal(t, "+blog* +post*", mysqlBooleanQuery([]string{"blog", "post"}))
+}
+
+func TestSearchExcerpt(t *testing.T) {
+	assert.Equal(t, "short content", searchExcerpt("short\n  content", []string{"content"}))
+
+	long := strings.Repeat("lorem ipsum ", 30) + "needle " + strings.Repeat("dolor sit ", 30)
+	excerpt := searchExcerpt(long, []string{"needle"})
+	assert.True(t, strings.HasPrefix(excerpt, "…"))
+	assert.True(t, strings.HasSuffix(excerpt, "…"))
+	assert.Contains(t, excerpt, "needle")
+
+	excerpt = searchExcerpt(long, []string{"missing"})
+	assert.True(t, strings.HasPrefix(excerpt, "lorem ipsum"))
+	assert.True(t, strings.HasSuffix(excerpt, "…"))
+
+	excerpt = searchExcerpt(strings.Repeat("é", 200)+"needle", []string{"needle"})
+	assert.True(t, strings.HasSuffix(excerpt, "needle"))
+}
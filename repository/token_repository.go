@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -16,7 +18,7 @@ type TokenRepository interface {
 	FindByName(ctx context.Context, name string) (*model.Token, error)
 	FindByHash(ctx context.Context, hash string) (*model.Token, error)
 	FindAll(ctx context.Context) ([]model.Token, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Token, int64, error)
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Token, int64, error)
 }
 
 type tokenRepository struct {
@@ -76,7 +78,7 @@ func (r *tokenRepository) FindAll(ctx context.Context) ([]model.Token, error) {
 	return tokens, err
 }
 
-func (r *tokenRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.Token, int64, error) {
+func (r *tokenRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.Token, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.Token{})
@@ -85,6 +87,11 @@ func (r *tokenRepository) SearchPaginate(ctx context.Context, query *gorm.DB, li
 		return nil, 0, err
 	}
 
+	query, err := database.ApplyOrderBy(query, model.TokenSortableColumns, orderBy, "tokens")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit > 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
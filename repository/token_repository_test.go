@@ -200,7 +200,7 @@ func TestTokenRepository_SearchPaginate(t *testing.T) {
 			db.Create(&model.Token{Name: "token" + string(rune('A'+i)), TokenHash: "hash" + string(rune('A'+i))})
 		}
 
-		result, total, err := repo.SearchPaginate(ctx, nil, 5, 0)
+		result, total, err := repo.SearchPaginate(ctx, nil, 5, 0, nil)
 		assert.NoError(t, err)
 		assert.Len(t, result, 5)
 		assert.Equal(t, int64(15), total)
@@ -214,7 +214,7 @@ func TestTokenRepository_SearchPaginate(t *testing.T) {
 			db.Create(&model.Token{Name: "token" + string(rune('A'+i)), TokenHash: "hash" + string(rune('A'+i))})
 		}
 
-		result, total, err := repo.SearchPaginate(ctx, nil, 5, 5)
+		result, total, err := repo.SearchPaginate(ctx, nil, 5, 5, nil)
 		assert.NoError(t, err)
 		assert.Len(t, result, 5)
 		assert.Equal(t, int64(10), total)
@@ -228,7 +228,7 @@ func TestTokenRepository_SearchPaginate(t *testing.T) {
 		db.Create(&model.Token{Name: "web-token", TokenHash: "hash2"})
 
 		query := db.Model(&model.Token{}).Where("name LIKE ?", "api%")
-		result, total, err := repo.SearchPaginate(ctx, query, 10, 0)
+		result, total, err := repo.SearchPaginate(ctx, query, 10, 0, nil)
 
 		assert.NoError(t, err)
 		assert.Len(t, result, 1)
@@ -244,7 +244,7 @@ func TestTokenRepository_SearchPaginate(t *testing.T) {
 			db.Create(&model.Token{Name: "token" + string(rune('A'+i)), TokenHash: "hash" + string(rune('A'+i))})
 		}
 
-		result, total, err := repo.SearchPaginate(ctx, nil, 0, 0)
+		result, total, err := repo.SearchPaginate(ctx, nil, 0, 0, nil)
 		assert.NoError(t, err)
 		assert.Len(t, result, 5)
 		assert.Equal(t, int64(5), total)
@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/database"
 	"github.com/flectolab/flecto-manager/model"
 	"gorm.io/gorm"
 )
@@ -17,10 +20,12 @@ type UserRepository interface {
 	FindByUsername(ctx context.Context, username string) (*model.User, error)
 	FindAll(ctx context.Context) ([]model.User, error)
 	Search(ctx context.Context, query *gorm.DB) ([]model.User, error)
-	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.User, int64, error)
-	UpdatePassword(ctx context.Context, id int64, hashedPassword string) error
+	SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.User, int64, error)
+	UpdatePassword(ctx context.Context, id int64, hashedPassword string, mustChangePassword bool) error
+	FindPasswordHistory(ctx context.Context, userID int64, limit int) ([]model.UserPasswordHistory, error)
+	AddPasswordHistory(ctx context.Context, userID int64, hashedPassword string, keep int) error
 	UpdateStatus(ctx context.Context, id int64, active bool) error
-	UpdateRefreshTokenHash(ctx context.Context, id int64, hash string) error
+	UpdateLanguage(ctx context.Context, id int64, language string) error
 }
 
 type userRepository struct {
@@ -76,11 +81,11 @@ func (r *userRepository) FindAll(ctx context.Context) ([]model.User, error) {
 }
 
 func (r *userRepository) Search(ctx context.Context, query *gorm.DB) ([]model.User, error) {
-	users, _, err := r.SearchPaginate(ctx, query, 0, 0)
+	users, _, err := r.SearchPaginate(ctx, query, 0, 0, nil)
 	return users, err
 }
 
-func (r *userRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int) ([]model.User, int64, error) {
+func (r *userRepository) SearchPaginate(ctx context.Context, query *gorm.DB, limit, offset int, orderBy []commonTypes.SortInput) ([]model.User, int64, error) {
 	var total int64
 	if query == nil {
 		query = r.db.WithContext(ctx).Model(&model.User{})
@@ -90,6 +95,11 @@ func (r *userRepository) SearchPaginate(ctx context.Context, query *gorm.DB, lim
 		return nil, 0, err
 	}
 
+	query, err := database.ApplyOrderBy(query, model.UserSortableColumns, orderBy, "users")
+	if err != nil {
+		return nil, 0, err
+	}
+
 	if limit != 0 {
 		query = query.Limit(limit).Offset(offset)
 	}
@@ -102,14 +112,39 @@ func (r *userRepository) SearchPaginate(ctx context.Context, query *gorm.DB, lim
 	return users, total, nil
 }
 
-func (r *userRepository) UpdatePassword(ctx context.Context, id int64, hashedPassword string) error {
-	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("password", hashedPassword).Error
+func (r *userRepository) UpdatePassword(ctx context.Context, id int64, hashedPassword string, mustChangePassword bool) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"password":             hashedPassword,
+		"must_change_password": mustChangePassword,
+		"password_changed_at":  time.Now(),
+	}).Error
+}
+
+// FindPasswordHistory returns the last limit previous password hashes of a user, most recent first
+func (r *userRepository) FindPasswordHistory(ctx context.Context, userID int64, limit int) ([]model.UserPasswordHistory, error) {
+	var history []model.UserPasswordHistory
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Limit(limit).Find(&history).Error
+	return history, err
+}
+
+// AddPasswordHistory stores a previous password hash of a user and only keeps the last keep ones
+func (r *userRepository) AddPasswordHistory(ctx context.Context, userID int64, hashedPassword string, keep int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&model.UserPasswordHistory{UserID: userID, Password: hashedPassword}).Error; err != nil {
+			return err
+		}
+
+		var keptIDs []int64
+		if err := tx.Model(&model.UserPasswordHistory{}).Where("user_id = ?", userID).Order("id DESC").Limit(keep).Pluck("id", &keptIDs).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND id NOT IN ?", userID, keptIDs).Delete(&model.UserPasswordHistory{}).Error
+	})
 }
 
 func (r *userRepository) UpdateStatus(ctx context.Context, id int64, active bool) error {
 	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("active", active).Error
 }
-
-func (r *userRepository) UpdateRefreshTokenHash(ctx context.Context, id int64, hash string) error {
-	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("refresh_token_hash", hash).Error
-}
\ No newline at end of file
+func (r *userRepository) UpdateLanguage(ctx context.Context, id int64, language string) error {
+	return r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Update("language", language).Error
+}
@@ -18,7 +18,7 @@ func setupUserTestDB(t *testing.T) *gorm.DB {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&model.User{})
+	err = db.AutoMigrate(&model.User{}, &model.UserPasswordHistory{})
 	assert.NoError(t, err)
 
 	return db
@@ -362,28 +362,28 @@ func TestUserRepository_SearchPaginate(t *testing.T) {
 	}
 
 	t.Run("paginate with limit", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 0)
+		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 0, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 5)
 		assert.Equal(t, int64(10), total)
 	})
 
 	t.Run("paginate with offset", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 5)
+		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 5, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 5)
 		assert.Equal(t, int64(10), total)
 	})
 
 	t.Run("paginate with offset beyond total", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 15)
+		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 5, 15, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 0)
 		assert.Equal(t, int64(10), total)
 	})
 
 	t.Run("paginate without limit returns all", func(t *testing.T) {
-		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 0, 0)
+		results, total, err := repo.SearchPaginate(ctx, baseQuery(), 0, 0, nil)
 		assert.NoError(t, err)
 		assert.Len(t, results, 10)
 		assert.Equal(t, int64(10), total)
@@ -403,73 +403,73 @@ func TestUserRepository_UpdatePassword(t *testing.T) {
 	err := repo.Create(ctx, user)
 	assert.NoError(t, err)
 
-	err = repo.UpdatePassword(ctx, user.ID, "newhash")
+	err = repo.UpdatePassword(ctx, user.ID, "newhash", true)
 	assert.NoError(t, err)
 
 	found, err := repo.FindByID(ctx, user.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, "newhash", found.Password)
+	assert.True(t, found.MustChangePassword)
+	assert.NotNil(t, found.PasswordChangedAt)
 }
 
-func TestUserRepository_UpdateStatus(t *testing.T) {
+func TestUserRepository_PasswordHistory(t *testing.T) {
 	db := setupUserTestDB(t)
 	repo := NewUserRepository(db)
 	ctx := context.Background()
 
-	user := &model.User{
-		Username: "statususer",
-		Active:   boolPtr(true),
-	}
-	err := repo.Create(ctx, user)
-	assert.NoError(t, err)
+	user := &model.User{Username: "historyuser", Active: boolPtr(true)}
+	assert.NoError(t, repo.Create(ctx, user))
+	other := &model.User{Username: "otheruser", Active: boolPtr(true)}
+	assert.NoError(t, repo.Create(ctx, other))
 
-	t.Run("deactivate user", func(t *testing.T) {
-		err := repo.UpdateStatus(ctx, user.ID, false)
-		assert.NoError(t, err)
+	assert.NoError(t, repo.AddPasswordHistory(ctx, other.ID, "otherhash", 2))
+	for _, hash := range []string{"hash1", "hash2", "hash3"} {
+		assert.NoError(t, repo.AddPasswordHistory(ctx, user.ID, hash, 2))
+	}
 
-		found, err := repo.FindByID(ctx, user.ID)
-		assert.NoError(t, err)
-		assert.False(t, *found.Active)
-	})
+	history, err := repo.FindPasswordHistory(ctx, user.ID, 5)
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, "hash3", history[0].Password)
+	assert.Equal(t, "hash2", history[1].Password)
 
-	t.Run("activate user", func(t *testing.T) {
-		err := repo.UpdateStatus(ctx, user.ID, true)
-		assert.NoError(t, err)
+	history, err = repo.FindPasswordHistory(ctx, user.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
 
-		found, err := repo.FindByID(ctx, user.ID)
-		assert.NoError(t, err)
-		assert.True(t, *found.Active)
-	})
+	history, err = repo.FindPasswordHistory(ctx, other.ID, 5)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
 }
 
-func TestUserRepository_UpdateRefreshTokenHash(t *testing.T) {
+func TestUserRepository_UpdateStatus(t *testing.T) {
 	db := setupUserTestDB(t)
 	repo := NewUserRepository(db)
 	ctx := context.Background()
 
 	user := &model.User{
-		Username:         "tokenuser",
-		RefreshTokenHash: "oldhash",
-		Active:           boolPtr(true),
+		Username: "statususer",
+		Active:   boolPtr(true),
 	}
 	err := repo.Create(ctx, user)
 	assert.NoError(t, err)
 
-	t.Run("update refresh token hash", func(t *testing.T) {
-		err := repo.UpdateRefreshTokenHash(ctx, user.ID, "newtokenhash")
+	t.Run("deactivate user", func(t *testing.T) {
+		err := repo.UpdateStatus(ctx, user.ID, false)
 		assert.NoError(t, err)
 
 		found, err := repo.FindByID(ctx, user.ID)
 		assert.NoError(t, err)
-		assert.Equal(t, "newtokenhash", found.RefreshTokenHash)
+		assert.False(t, *found.Active)
 	})
 
-	t.Run("clear refresh token hash", func(t *testing.T) {
-		err := repo.UpdateRefreshTokenHash(ctx, user.ID, "")
+	t.Run("activate user", func(t *testing.T) {
+		err := repo.UpdateStatus(ctx, user.ID, true)
 		assert.NoError(t, err)
 
 		found, err := repo.FindByID(ctx, user.ID)
 		assert.NoError(t, err)
-		assert.Equal(t, "", found.RefreshTokenHash)
+		assert.True(t, *found.Active)
 	})
 }
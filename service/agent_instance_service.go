@@ -0,0 +1,386 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAgentInstanceNotFound      = flectoErrors.New(flectoErrors.CodeNotFound, "agent not registered")
+	ErrAgentInstanceNameTaken     = flectoErrors.New(flectoErrors.CodeAlreadyExists, "agent name registered by another token")
+	ErrAgentInstanceNotOwned      = flectoErrors.New(flectoErrors.CodeForbidden, "agent registered by another token")
+	ErrInvalidAgentInstance       = flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid agent")
+	ErrInvalidAgentHeartbeat      = flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid agent heartbeat")
+	ErrInvalidAgentInstanceFilter = flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid agent filter")
+)
+
+// AgentInstanceService keeps the registry of the agents, each one registering with an API token then sending
+// heartbeats with the versions of the projects it applies, so that the stale and outdated agents can be found
+// and the rollout of a publish followed
+type AgentInstanceService interface {
+	Register(ctx context.Context, registration commonTypes.AgentRegistration, registeredBy string) (*model.AgentInstance, error)
+	Heartbeat(ctx context.Context, name string, heartbeat commonTypes.AgentHeartbeat, sentBy string) (*model.AgentInstance, error)
+	Acknowledge(ctx context.Context, name string, applied commonTypes.AgentAppliedVersion, sentBy string) (*model.AgentInstance, error)
+	GetRollout(ctx context.Context, namespaceCode, projectCode string, environment commonTypes.Environment) (*model.RolloutStatus, error)
+	List(ctx context.Context, filter types.AgentInstanceFilter) ([]model.AgentInstance, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type agentInstanceService struct {
+	ctx            *appContext.Context
+	repo           repository.AgentInstanceRepository
+	projectService ProjectService
+}
+
+func NewAgentInstanceService(ctx *appContext.Context, repo repository.AgentInstanceRepository, projectService ProjectService) AgentInstanceService {
+	return &agentInstanceService{
+		ctx:            ctx,
+		repo:           repo,
+		projectService: projectService,
+	}
+}
+
+// Register records the agent, or updates its registration when it was registered by the same token
+func (s *agentInstanceService) Register(ctx context.Context, registration commonTypes.AgentRegistration, registeredBy string) (*model.AgentInstance, error) {
+	if err := commonTypes.ValidateAgentRegistration(registration); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAgentInstance, err)
+	}
+
+	now := time.Now()
+	agent, err := s.repo.FindByName(ctx, registration.Name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if agent == nil {
+		agent = &model.AgentInstance{
+			Name:            registration.Name,
+			Type:            registration.Type,
+			Hostname:        registration.Hostname,
+			AgentVersion:    registration.AgentVersion,
+			RegisteredBy:    registeredBy,
+			LastHeartbeatAt: now,
+		}
+		if err = s.repo.Create(ctx, agent); err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "failed to register agent", "agent", registration.Name, "error", err)
+			return nil, err
+		}
+		s.ctx.Logger.InfoContext(ctx, "agent registered", "agent", agent.Name, "type", agent.Type, "registeredBy", registeredBy)
+		return s.withState(agent, now), nil
+	}
+
+	if agent.RegisteredBy != registeredBy {
+		return nil, fmt.Errorf("%w: %s", ErrAgentInstanceNameTaken, registration.Name)
+	}
+	agent.Type = registration.Type
+	agent.Hostname = registration.Hostname
+	agent.AgentVersion = registration.AgentVersion
+	agent.LastHeartbeatAt = now
+	if err = s.repo.Update(ctx, agent); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to register agent", "agent", registration.Name, "error", err)
+		return nil, err
+	}
+	return s.withState(agent, now), nil
+}
+
+// Heartbeat records that the agent is alive and replaces its projects by those of the heartbeat. The time a
+// version was applied is kept from the previous heartbeats while the agent reports the same version.
+func (s *agentInstanceService) Heartbeat(ctx context.Context, name string, heartbeat commonTypes.AgentHeartbeat, sentBy string) (*model.AgentInstance, error) {
+	if err := commonTypes.ValidateAgentHeartbeat(heartbeat); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAgentHeartbeat, err)
+	}
+	agent, err := s.getOwned(ctx, name, sentBy)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	projects := make([]model.AgentInstanceProject, 0, len(heartbeat.Projects))
+	for _, applied := range heartbeat.Projects {
+		projects = append(projects, appliedProject(agent, applied, now))
+	}
+	if err = s.repo.ReplaceProjects(ctx, agent.ID, projects, now); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to record agent heartbeat", "agent", name, "error", err)
+		return nil, err
+	}
+
+	agent.Projects = projects
+	agent.LastHeartbeatAt = now
+	s.withState(agent, now)
+	if err = s.setLatestVersions(ctx, []model.AgentInstance{*agent}); err != nil {
+		return nil, err
+	}
+	return agent, nil
+}
+
+// Acknowledge records that the agent applied a version of a project, as soon as it did and without waiting for its
+// next heartbeat. The other projects of the agent are left as they are.
+func (s *agentInstanceService) Acknowledge(ctx context.Context, name string, applied commonTypes.AgentAppliedVersion, sentBy string) (*model.AgentInstance, error) {
+	if err := commonTypes.ValidateAgentHeartbeat(commonTypes.AgentHeartbeat{Projects: []commonTypes.AgentAppliedVersion{applied}}); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidAgentHeartbeat, err)
+	}
+	agent, err := s.getOwned(ctx, name, sentBy)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	project := appliedProject(agent, applied, now)
+	index := slices.IndexFunc(agent.Projects, func(existing model.AgentInstanceProject) bool {
+		return existing.NamespaceCode == project.NamespaceCode && existing.ProjectCode == project.ProjectCode && existing.Environment == project.Environment
+	})
+	if index >= 0 {
+		project.ID = agent.Projects[index].ID
+	}
+	project.AgentInstanceID = agent.ID
+	if err = s.repo.SaveProject(ctx, &project, now); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to record agent acknowledgement", "agent", name, "error", err)
+		return nil, err
+	}
+	if index >= 0 {
+		agent.Projects[index] = project
+	} else {
+		agent.Projects = append(agent.Projects, project)
+	}
+
+	agent.LastHeartbeatAt = now
+	s.withState(agent, now)
+	if err = s.setLatestVersions(ctx, []model.AgentInstance{*agent}); err != nil {
+		return nil, err
+	}
+	return agent, nil
+}
+
+// GetRollout returns how many of the registered agents serving the environment of the project applied its latest
+// version and which ones lag behind, staging serving the published version and production the promoted one
+func (s *agentInstanceService) GetRollout(ctx context.Context, namespaceCode, projectCode string, environment commonTypes.Environment) (*model.RolloutStatus, error) {
+	if environment == "" {
+		environment = commonTypes.EnvironmentStaging
+	}
+	version, publishedAt, err := s.release(ctx, namespaceCode, projectCode, environment)
+	if err != nil {
+		return nil, err
+	}
+	agents, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	status := &model.RolloutStatus{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		Environment:   environment,
+		Version:       version,
+		PublishedAt:   publishedAt,
+		Lagging:       []model.RolloutAgent{},
+	}
+	for i := range agents {
+		agent := s.withState(&agents[i], now)
+		for _, project := range agent.Projects {
+			if project.NamespaceCode != namespaceCode || project.ProjectCode != projectCode || project.Environment != environment {
+				continue
+			}
+			status.Agents++
+			if project.Version >= version {
+				status.Applied++
+				continue
+			}
+			status.Lagging = append(status.Lagging, model.RolloutAgent{
+				Name:            agent.Name,
+				State:           agent.State,
+				Version:         project.Version,
+				AppliedAt:       project.AppliedAt,
+				LastHeartbeatAt: agent.LastHeartbeatAt,
+			})
+		}
+	}
+
+	timeout := s.ctx.CurrentConfig().Agent.RolloutTimeout
+	if len(status.Lagging) > 0 && publishedAt != nil && now.Sub(*publishedAt) > timeout {
+		names := make([]string, 0, len(status.Lagging))
+		for _, lagging := range status.Lagging {
+			names = append(names, lagging.Name)
+		}
+		status.TimedOut = true
+		status.Warning = fmt.Sprintf("%d of the %d agents did not apply version %d within %s: %s",
+			len(status.Lagging), status.Agents, version, timeout, strings.Join(names, ", "))
+	}
+	return status, nil
+}
+
+// List returns the registered agents matching the filter, with their state and the latest version of their projects
+func (s *agentInstanceService) List(ctx context.Context, filter types.AgentInstanceFilter) ([]model.AgentInstance, error) {
+	if filter.State != "" && !model.AgentInstanceState(filter.State).IsValid() {
+		return nil, fmt.Errorf("%w: unknown state %s", ErrInvalidAgentInstanceFilter, filter.State)
+	}
+	agents, err := s.repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range agents {
+		s.withState(&agents[i], now)
+	}
+	if err = s.setLatestVersions(ctx, agents); err != nil {
+		return nil, err
+	}
+
+	result := make([]model.AgentInstance, 0, len(agents))
+	for _, agent := range agents {
+		if filter.State != "" && agent.State != model.AgentInstanceState(filter.State) {
+			continue
+		}
+		if filter.Outdated != nil && agent.IsOutdated() != *filter.Outdated {
+			continue
+		}
+		if filter.NamespaceCode != "" && !servesProject(agent, filter.NamespaceCode, filter.ProjectCode) {
+			continue
+		}
+		result = append(result, agent)
+	}
+	return result, nil
+}
+
+func (s *agentInstanceService) Delete(ctx context.Context, name string) error {
+	if err := s.repo.Delete(ctx, name); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: %s", ErrAgentInstanceNotFound, name)
+		}
+		return err
+	}
+	s.ctx.Logger.InfoContext(ctx, "agent deleted", "agent", name)
+	return nil
+}
+
+// withState sets the state of the agent from the time of its last heartbeat
+func (s *agentInstanceService) withState(agent *model.AgentInstance, now time.Time) *model.AgentInstance {
+	agent.State = model.AgentInstanceStateOnline
+	if now.Sub(agent.LastHeartbeatAt) > s.ctx.CurrentConfig().Agent.StaleThreshold {
+		agent.State = model.AgentInstanceStateStale
+	}
+	return agent
+}
+
+// setLatestVersions sets the version published to the environment of each project of the agents, staging serving
+// the published version of the project and production its promoted version. The projects which no longer exist
+// keep a latest version of 0, so they are never outdated.
+func (s *agentInstanceService) setLatestVersions(ctx context.Context, agents []model.AgentInstance) error {
+	type projectEnvironment struct {
+		namespaceCode, projectCode string
+		environment                commonTypes.Environment
+	}
+	latest := make(map[projectEnvironment]int)
+	for i := range agents {
+		for j := range agents[i].Projects {
+			project := &agents[i].Projects[j]
+			key := projectEnvironment{project.NamespaceCode, project.ProjectCode, project.Environment}
+			version, ok := latest[key]
+			if !ok {
+				var err error
+				if version, err = s.latestVersion(ctx, key.namespaceCode, key.projectCode, key.environment); err != nil {
+					return err
+				}
+				latest[key] = version
+			}
+			project.LatestVersion = version
+		}
+	}
+	return nil
+}
+
+func (s *agentInstanceService) latestVersion(ctx context.Context, namespaceCode, projectCode string, environment commonTypes.Environment) (int, error) {
+	version, _, err := s.release(ctx, namespaceCode, projectCode, environment)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return version, err
+}
+
+// release returns the version served to the environment of the project and the time it was published or promoted,
+// nil when it never was. The production environment of a project never promoted serves the version 0.
+func (s *agentInstanceService) release(ctx context.Context, namespaceCode, projectCode string, environment commonTypes.Environment) (int, *time.Time, error) {
+	ctx = database.WithNamespace(ctx, namespaceCode)
+	project, err := s.projectService.GetByCode(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return 0, nil, err
+	}
+	if environment == commonTypes.EnvironmentProduction {
+		projectEnvironment, errEnvironment := s.projectService.GetEnvironment(ctx, namespaceCode, projectCode, environment)
+		if errEnvironment != nil {
+			if errors.Is(errEnvironment, gorm.ErrRecordNotFound) {
+				return 0, nil, nil
+			}
+			return 0, nil, errEnvironment
+		}
+		return projectEnvironment.Version, timeOrNil(projectEnvironment.PromotedAt), nil
+	}
+	return project.Version, timeOrNil(project.PublishedAt), nil
+}
+
+// getOwned returns the registered agent, refused unless it was registered by the token sending the call
+func (s *agentInstanceService) getOwned(ctx context.Context, name, sentBy string) (*model.AgentInstance, error) {
+	agent, err := s.repo.FindByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrAgentInstanceNotFound, name)
+		}
+		return nil, err
+	}
+	if agent.RegisteredBy != sentBy {
+		return nil, fmt.Errorf("%w: %s", ErrAgentInstanceNotOwned, name)
+	}
+	return agent, nil
+}
+
+// appliedProject returns the project applied by the agent, the time the version was applied being kept from the
+// previous calls while the agent reports the same version
+func appliedProject(agent *model.AgentInstance, applied commonTypes.AgentAppliedVersion, now time.Time) model.AgentInstanceProject {
+	if applied.Environment == "" {
+		applied.Environment = commonTypes.EnvironmentStaging
+	}
+	project := model.AgentInstanceProject{
+		NamespaceCode: applied.NamespaceCode,
+		ProjectCode:   applied.ProjectCode,
+		Environment:   applied.Environment,
+		Version:       applied.Version,
+		AppliedAt:     now,
+	}
+	for _, existing := range agent.Projects {
+		if existing.NamespaceCode == project.NamespaceCode && existing.ProjectCode == project.ProjectCode &&
+			existing.Environment == project.Environment && existing.Version == project.Version {
+			project.AppliedAt = existing.AppliedAt
+		}
+	}
+	return project
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// servesProject returns true if the agent reported the project, or any project of the namespace when projectCode is empty
+func servesProject(agent model.AgentInstance, namespaceCode, projectCode string) bool {
+	for _, project := range agent.Projects {
+		if project.NamespaceCode == namespaceCode && (projectCode == "" || project.ProjectCode == projectCode) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// agentProjects are the projects the agents of the tests register for
+var agentProjects = []commonTypes.AgentProject{{NamespaceCode: "ns", ProjectCode: "site"}}
+
+func setupAgentInstanceServiceTest(t *testing.T) (*gorm.DB, AgentInstanceService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ProjectEnvironment{}, &model.AgentInstance{}, &model.AgentInstanceProject{})
+	require.NoError(t, err)
+
+	ctx := appContext.TestContext(nil)
+	projectSrv := NewProjectService(ctx, repository.NewProjectRepository(db), repository.NewPageRepository(db), repository.NewRedirectDraftRepository(db), repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+	svc := NewAgentInstanceService(ctx, repository.NewAgentInstanceRepository(db), projectSrv)
+
+	db.Create(&model.Namespace{NamespaceCode: "ns", Name: "Test"})
+	db.Create(&model.Project{NamespaceCode: "ns", ProjectCode: "site", Name: "Site", Version: 4})
+	db.Create(&model.Project{NamespaceCode: "ns", ProjectCode: "shop", Name: "Shop", Version: 2})
+	db.Create(&model.ProjectEnvironment{NamespaceCode: "ns", ProjectCode: "site", Environment: commonTypes.EnvironmentProduction, Version: 3})
+	return db, svc
+}
+
+func TestAgentInstanceService_Register(t *testing.T) {
+	_, svc := setupAgentInstanceServiceTest(t)
+	ctx := context.Background()
+
+	agent, err := svc.Register(ctx, commonTypes.AgentRegistration{Name: "edge-1", Type: commonTypes.AgentTypeTraefik, Hostname: "host1", Projects: agentProjects}, "edge-token")
+	require.NoError(t, err)
+	assert.Equal(t, "edge-token", agent.RegisteredBy)
+	assert.Equal(t, model.AgentInstanceStateOnline, agent.State)
+
+	// Registering again with the same token updates the registration
+	agent, err = svc.Register(ctx, commonTypes.AgentRegistration{Name: "edge-1", Type: commonTypes.AgentTypeTraefik, Hostname: "host2", AgentVersion: "1.2.0", Projects: agentProjects}, "edge-token")
+	require.NoError(t, err)
+	agents, err := svc.List(ctx, types.AgentInstanceFilter{})
+	require.NoError(t, err)
+	require.Len(t, agents, 1)
+	assert.Equal(t, agent.ID, agents[0].ID)
+	assert.Equal(t, "host2", agents[0].Hostname)
+	assert.Equal(t, "1.2.0", agents[0].AgentVersion)
+
+	_, err = svc.Register(ctx, commonTypes.AgentRegistration{Name: "edge-1", Type: commonTypes.AgentTypeTraefik, Projects: agentProjects}, "other-token")
+	assert.ErrorIs(t, err, ErrAgentInstanceNameTaken)
+
+	_, err = svc.Register(ctx, commonTypes.AgentRegistration{Name: "edge 1", Type: commonTypes.AgentTypeTraefik, Projects: agentProjects}, "edge-token")
+	assert.ErrorIs(t, err, ErrInvalidAgentInstance)
+}
+
+func TestAgentInstanceService_Heartbeat(t *testing.T) {
+	db, svc := setupAgentInstanceServiceTest(t)
+	ctx := context.Background()
+	_, err := svc.Register(ctx, commonTypes.AgentRegistration{Name: "edge-1", Type: commonTypes.AgentTypeDefault, Projects: agentProjects}, "edge-token")
+	require.NoError(t, err)
+
+	heartbeat := commonTypes.AgentHeartbeat{Projects: []commonTypes.AgentAppliedVersion{
+		{NamespaceCode: "ns", ProjectCode: "site", Version: 4},
+		{NamespaceCode: "ns", ProjectCode: "site", Environment: commonTypes.EnvironmentProduction, Version: 2},
+		{NamespaceCode: "ns", ProjectCode: "gone", Version: 9},
+	}}
+	agent, err := svc.Heartbeat(ctx, "edge-1", heartbeat, "edge-token")
+	require.NoError(t, err)
+	require.Len(t, agent.Projects, 3)
+	assert.Equal(t, commonTypes.EnvironmentStaging, agent.Projects[0].Environment)
+	assert.Equal(t, 4, agent.Projects[0].LatestVersion)
+	assert.False(t, agent.Projects[0].IsOutdated())
+	assert.Equal(t, 3, agent.Projects[1].LatestVersion)
+	assert.True(t, agent.Projects[1].IsOutdated())
+	assert.Equal(t, 0, agent.Projects[2].LatestVersion, "project no longer existing")
+	assert.True(t, agent.IsOutdated())
+
+	// The time a version was applied is kept while the agent reports it
+	appliedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, db.Model(&model.AgentInstanceProject{}).Where("1 = 1").Update("applied_at", appliedAt).Error)
+	heartbeat.Projects = heartbeat.Projects[:2]
+	heartbeat.Projects[1].Version = 3
+	agent, err = svc.Heartbeat(ctx, "edge-1", heartbeat, "edge-token")
+	require.NoError(t, err)
+	require.Len(t, agent.Projects, 2)
+	assert.True(t, agent.Projects[0].AppliedAt.Equal(appliedAt))
+	assert.True(t, agent.Projects[1].AppliedAt.After(appliedAt))
+	assert.False(t, agent.IsOutdated())
+	var count int64
+	db.Model(&model.AgentInstanceProject{}).Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	_, err = svc.Heartbeat(ctx, "edge-1", heartbeat, "other-token")
+	assert.ErrorIs(t, err, ErrAgentInstanceNotOwned)
+	_, err = svc.Heartbeat(ctx, "edge-2", heartbeat, "edge-token")
+	assert.ErrorIs(t, err, ErrAgentInstanceNotFound)
+	_, err = svc.Heartbeat(ctx, "edge-1", commonTypes.AgentHeartbeat{Projects: []commonTypes.AgentAppliedVersion{{NamespaceCode: "ns"}}}, "edge-token")
+	assert.ErrorIs(t, err, ErrInvalidAgentHeartbeat)
+}
+
+func TestAgentInstanceService_List(t *testing.T) {
+	db, svc := setupAgentInstanceServiceTest(t)
+	ctx := context.Background()
+	for _, name := range []string{"edge-1", "edge-2", "edge-3"} {
+		_, err := svc.Register(ctx, commonTypes.AgentRegistration{Name: name, Type: commonTypes.AgentTypeDefault, Projects: agentProjects}, "edge-token")
+		require.NoError(t, err)
+	}
+	_, err := svc.Heartbeat(ctx, "edge-1", commonTypes.AgentHeartbeat{Projects: []commonTypes.AgentAppliedVersion{{NamespaceCode: "ns", ProjectCode: "site", Version: 4}}}, "edge-token")
+	require.NoError(t, err)
+	_, err = svc.Heartbeat(ctx, "edge-2", commonTypes.AgentHeartbeat{Projects: []commonTypes.AgentAppliedVersion{{NamespaceCode: "ns", ProjectCode: "shop", Version: 1}}}, "edge-token")
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&model.AgentInstance{}).Where("name = ?", "edge-3").Update("last_heartbeat_at", time.Now().Add(-time.Hour)).Error)
+
+	names := func(filter types.AgentInstanceFilter) []string {
+		agents, err := svc.List(ctx, filter)
+		require.NoError(t, err)
+		var result []string
+		for _, agent := range agents {
+			result = append(result, agent.Name)
+		}
+		return result
+	}
+	outdated, upToDate := true, false
+	assert.Equal(t, []string{"edge-1", "edge-2", "edge-3"}, names(types.AgentInstanceFilter{}))
+	assert.Equal(t, []string{"edge-3"}, names(types.AgentInstanceFilter{State: string(model.AgentInstanceStateStale)}))
+	assert.Equal(t, []string{"edge-1", "edge-2"}, names(types.AgentInstanceFilter{State: string(model.AgentInstanceStateOnline)}))
+	assert.Equal(t, []string{"edge-2"}, names(types.AgentInstanceFilter{Outdated: &outdated}))
+	assert.Equal(t, []string{"edge-1", "edge-3"}, names(types.AgentInstanceFilter{Outdated: &upToDate}))
+	assert.Equal(t, []string{"edge-1", "edge-2"}, names(types.AgentInstanceFilter{NamespaceCode: "ns"}))
+	assert.Equal(t, []string{"edge-1"}, names(types.AgentInstanceFilter{NamespaceCode: "ns", ProjectCode: "site"}))
+
+	_, err = svc.List(ctx, types.AgentInstanceFilter{State: "OFFLINE"})
+	assert.ErrorIs(t, err, ErrInvalidAgentInstanceFilter)
+}
+
+func TestAgentInstanceService_Delete(t *testing.T) {
+	db, svc := setupAgentInstanceServiceTest(t)
+	ctx := context.Background()
+	_, err := svc.Register(ctx, commonTypes.AgentRegistration{Name: "edge-1", Type: commonTypes.AgentTypeDefault, Projects: agentProjects}, "edge-token")
+	require.NoError(t, err)
+	_, err = svc.Heartbeat(ctx, "edge-1", commonTypes.AgentHeartbeat{Projects: []commonTypes.AgentAppliedVersion{{NamespaceCode: "ns", ProjectCode: "site", Version: 4}}}, "edge-token")
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Delete(ctx, "edge-1"))
+	var count int64
+	db.Model(&model.AgentInstanceProject{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+	assert.ErrorIs(t, svc.Delete(ctx, "edge-1"), ErrAgentInstanceNotFound)
+}
+
+func TestAgentInstanceService_Acknowledge(t *testing.T) {
+	db, svc := setupAgentInstanceServiceTest(t)
+	ctx := context.Background()
+	_, err := svc.Register(ctx, commonTypes.AgentRegistration{Name: "edge-1", Type: commonTypes.AgentTypeDefault, Projects: agentProjects}, "edge-token")
+	require.NoError(t, err)
+	_, err = svc.Heartbeat(ctx, "edge-1", commonTypes.AgentHeartbeat{Projects: []commonTypes.AgentAppliedVersion{
+		{NamespaceCode: "ns", ProjectCode: "site", Version: 3},
+		{NamespaceCode: "ns", ProjectCode: "shop", Version: 2},
+	}}, "edge-token")
+	require.NoError(t, err)
+
+	agent, err := svc.Acknowledge(ctx, "edge-1", commonTypes.AgentAppliedVersion{NamespaceCode: "ns", ProjectCode: "site", Version: 4}, "edge-token")
+	require.NoError(t, err)
+	require.Len(t, agent.Projects, 2)
+	assert.False(t, agent.IsOutdated())
+
+	// The acknowledged project is updated in place, the other ones being kept
+	var projects []model.AgentInstanceProject
+	require.NoError(t, db.Order("project_code").Find(&projects).Error)
+	require.Len(t, projects, 2)
+	assert.Equal(t, "shop", projects[0].ProjectCode)
+	assert.Equal(t, 2, projects[0].Version)
+	assert.Equal(t, "site", projects[1].ProjectCode)
+	assert.Equal(t, 4, projects[1].Version)
+
+	agent, err = svc.Acknowledge(ctx, "edge-1", commonTypes.AgentAppliedVersion{NamespaceCode: "ns", ProjectCode: "site", Environment: commonTypes.EnvironmentProduction, Version: 3}, "edge-token")
+	require.NoError(t, err)
+	assert.Len(t, agent.Projects, 3)
+
+	_, err = svc.Acknowledge(ctx, "edge-1", commonTypes.AgentAppliedVersion{NamespaceCode: "ns", ProjectCode: "site", Version: 4}, "other-token")
+	assert.ErrorIs(t, err, ErrAgentInstanceNotOwned)
+	_, err = svc.Acknowledge(ctx, "edge-1", commonTypes.AgentAppliedVersion{ProjectCode: "site", Version: 4}, "edge-token")
+	assert.ErrorIs(t, err, ErrInvalidAgentHeartbeat)
+}
+
+func TestAgentInstanceService_GetRollout(t *testing.T) {
+	db, svc := setupAgentInstanceServiceTest(t)
+	ctx := context.Background()
+	for name, version := range map[string]int{"edge-1": 4, "edge-2": 3, "edge-3": 4} {
+		_, err := svc.Register(ctx, commonTypes.AgentRegistration{Name: name, Type: commonTypes.AgentTypeDefault, Projects: agentProjects}, "edge-token")
+		require.NoError(t, err)
+		_, err = svc.Acknowledge(ctx, name, commonTypes.AgentAppliedVersion{NamespaceCode: "ns", ProjectCode: "site", Version: version}, "edge-token")
+		require.NoError(t, err)
+	}
+	_, err := svc.Register(ctx, commonTypes.AgentRegistration{Name: "edge-4", Type: commonTypes.AgentTypeDefault, Projects: agentProjects}, "edge-token")
+	require.NoError(t, err)
+
+	t.Run("in progress", func(t *testing.T) {
+		require.NoError(t, db.Model(&model.Project{}).Where("project_code = ?", "site").Update("published_at", time.Now()).Error)
+
+		status, err := svc.GetRollout(ctx, "ns", "site", "")
+		require.NoError(t, err)
+		assert.Equal(t, commonTypes.EnvironmentStaging, status.Environment)
+		assert.Equal(t, 4, status.Version)
+		assert.Equal(t, 3, status.Agents)
+		assert.Equal(t, 2, status.Applied)
+		require.Len(t, status.Lagging, 1)
+		assert.Equal(t, "edge-2", status.Lagging[0].Name)
+		assert.Equal(t, 3, status.Lagging[0].Version)
+		assert.Equal(t, model.AgentInstanceStateOnline, status.Lagging[0].State)
+		assert.False(t, status.TimedOut)
+		assert.Empty(t, status.Warning)
+	})
+
+	t.Run("timed out", func(t *testing.T) {
+		require.NoError(t, db.Model(&model.Project{}).Where("project_code = ?", "site").Update("published_at", time.Now().Add(-time.Hour)).Error)
+
+		status, err := svc.GetRollout(ctx, "ns", "site", commonTypes.EnvironmentStaging)
+		require.NoError(t, err)
+		assert.True(t, status.TimedOut)
+		assert.Equal(t, "1 of the 3 agents did not apply version 4 within 10m0s: edge-2", status.Warning)
+	})
+
+	t.Run("production environment", func(t *testing.T) {
+		status, err := svc.GetRollout(ctx, "ns", "site", commonTypes.EnvironmentProduction)
+		require.NoError(t, err)
+		assert.Equal(t, 3, status.Version)
+		assert.Equal(t, 0, status.Agents)
+		assert.True(t, status.IsComplete())
+		assert.Nil(t, status.PublishedAt, "never promoted")
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		_, err := svc.GetRollout(ctx, "ns", "unknown", "")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -59,7 +59,7 @@ func (s *agentService) FindByProject(ctx context.Context, namespaceCode, project
 }
 
 func (s *agentService) SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.AgentList, error) {
-	agents, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	agents, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
@@ -269,7 +269,7 @@ func TestAgentService_SearchPaginate(t *testing.T) {
 		}
 
 		mockAgentRepo.EXPECT().
-			SearchPaginate(ctx, nil, 10, 5).
+			SearchPaginate(ctx, nil, 10, 5, nil).
 			Return(expectedAgents, int64(50), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -293,7 +293,7 @@ func TestAgentService_SearchPaginate(t *testing.T) {
 		}
 
 		mockAgentRepo.EXPECT().
-			SearchPaginate(ctx, nil, commonTypes.DefaultLimit, commonTypes.DefaultOffset).
+			SearchPaginate(ctx, nil, commonTypes.DefaultLimit, commonTypes.DefaultOffset, nil).
 			Return(expectedAgents, int64(1), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -313,7 +313,7 @@ func TestAgentService_SearchPaginate(t *testing.T) {
 		expectedErr := errors.New("search error")
 
 		mockAgentRepo.EXPECT().
-			SearchPaginate(ctx, nil, commonTypes.DefaultLimit, commonTypes.DefaultOffset).
+			SearchPaginate(ctx, nil, commonTypes.DefaultLimit, commonTypes.DefaultOffset, nil).
 			Return(nil, int64(0), expectedErr)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
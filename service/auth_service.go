@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"time"
 
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/hash"
 	"github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/model"
@@ -13,24 +17,33 @@ import (
 	"gorm.io/gorm"
 )
 
+var (
+	ErrRefreshTokenReused = flectoErrors.New(flectoErrors.CodeUnauthenticated, "refresh token has already been used")
+	ErrImpersonateSelf    = flectoErrors.New(flectoErrors.CodeInvalidRequest, "cannot impersonate yourself")
+)
+
 type AuthService interface {
 	Login(ctx context.Context, req *types.LoginRequest) (*model.User, *types.TokenPair, error)
+	IssueTokens(ctx context.Context, user *model.User, authType types.AuthType, subjectPermissions *model.SubjectPermissions, extraRoles []string) (*types.TokenPair, error)
 	RefreshTokens(ctx context.Context, refreshToken string, claims *jwt.Claims) (*model.User, *types.TokenPair, error)
 	Logout(ctx context.Context, userID int64) error
+	Impersonate(ctx context.Context, adminUser *model.User, targetUsername string) (*model.User, *types.TokenPair, error)
 	ToUserResponse(user *model.User) *types.UserResponse
 }
 
 type authService struct {
-	ctx        *appContext.Context
-	userRepo   repository.UserRepository
-	jwtService *jwt.ServiceJWT
+	ctx              *appContext.Context
+	userRepo         repository.UserRepository
+	refreshTokenRepo repository.RefreshTokenRepository
+	jwtService       *jwt.ServiceJWT
 }
 
-func NewAuthService(ctx *appContext.Context, userRepo repository.UserRepository, jwtService *jwt.ServiceJWT) AuthService {
+func NewAuthService(ctx *appContext.Context, userRepo repository.UserRepository, refreshTokenRepo repository.RefreshTokenRepository, jwtService *jwt.ServiceJWT) AuthService {
 	return &authService{
-		ctx:        ctx,
-		userRepo:   userRepo,
-		jwtService: jwtService,
+		ctx:              ctx,
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		jwtService:       jwtService,
 	}
 }
 
@@ -39,44 +52,63 @@ func (s *authService) Login(ctx context.Context, req *types.LoginRequest) (*mode
 	user, err := s.userRepo.FindByUsername(ctx, req.Username)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			s.ctx.Logger.Warn("login failed: user not found", "username", req.Username)
+			s.ctx.Logger.WarnContext(ctx, "login failed: user not found", "username", req.Username)
 			return nil, nil, ErrInvalidCredentials
 		}
 		return nil, nil, err
 	}
 
 	if !user.IsActive() || !user.HasPassword() {
-		s.ctx.Logger.Warn("login failed: user inactive or has no password", "username", req.Username)
+		s.ctx.Logger.WarnContext(ctx, "login failed: user inactive or has no password", "username", req.Username)
 		return nil, nil, ErrUserNotFound
 	}
 
 	if err = hash.CheckPassword(user.Password, req.Password); err != nil {
-		s.ctx.Logger.Warn("login failed: invalid password", "username", req.Username)
+		s.ctx.Logger.WarnContext(ctx, "login failed: invalid password", "username", req.Username)
 		return nil, nil, ErrInvalidCredentials
 	}
-	// Generate tokens
-	tokenPair, err := s.jwtService.GenerateTokenPair(user, types.AuthTypeBasic, nil, nil)
+
+	tokenPair, err := s.IssueTokens(ctx, user, types.AuthTypeBasic, nil, nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	// Store refresh token hash
-	user.RefreshTokenHash = jwt.HashToken(tokenPair.RefreshToken)
+	s.ctx.Logger.InfoContext(ctx, "user logged in", "username", req.Username, "id", user.ID)
+	return user, tokenPair, nil
+}
 
-	if err = s.userRepo.Update(ctx, user); err != nil {
-		return nil, nil, err
+// IssueTokens generates a token pair for a new session and stores its refresh token
+func (s *authService) IssueTokens(ctx context.Context, user *model.User, authType types.AuthType, subjectPermissions *model.SubjectPermissions, extraRoles []string) (*types.TokenPair, error) {
+	familyID, err := generateFamilyID()
+	if err != nil {
+		return nil, err
 	}
-
-	s.ctx.Logger.Info("user logged in", "username", req.Username, "id", user.ID)
-	return user, tokenPair, nil
+	return s.issueTokens(ctx, user, authType, subjectPermissions, extraRoles, familyID)
 }
 
-// RefreshTokens generates new token pair using a valid refresh token
+// RefreshTokens rotates a valid refresh token into a new token pair of the same session.
+// Presenting a token that was already rotated revokes the whole session.
 func (s *authService) RefreshTokens(ctx context.Context, refreshToken string, claims *jwt.Claims) (*model.User, *types.TokenPair, error) {
 	if claims.TokenType != types.TokenTypeRefresh {
 		return nil, nil, ErrInvalidCredentials
 	}
 
+	stored, err := s.refreshTokenRepo.FindByHash(ctx, jwt.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidCredentials
+		}
+		return nil, nil, err
+	}
+
+	if stored.UserID != claims.UserID || stored.IsExpired() {
+		return nil, nil, ErrInvalidCredentials
+	}
+
+	if stored.IsRevoked() {
+		return nil, nil, s.revokeReusedFamily(ctx, stored)
+	}
+
 	user, err := s.userRepo.FindByID(ctx, claims.UserID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -89,36 +121,97 @@ func (s *authService) RefreshTokens(ctx context.Context, refreshToken string, cl
 		return nil, nil, ErrUserInactive
 	}
 
-	// Verify refresh token hash matches (for revocation check)
-	if user.RefreshTokenHash != jwt.HashToken(refreshToken) {
-		return nil, nil, ErrInvalidCredentials
-	}
-
-	// Generate new tokens
-	tokenPair, err := s.jwtService.GenerateTokenPair(user, types.AuthTypeBasic, claims.SubjectPermissions, claims.ExtraRoles)
+	revoked, err := s.refreshTokenRepo.Revoke(ctx, stored.ID)
 	if err != nil {
 		return nil, nil, err
 	}
+	if !revoked {
+		// another request rotated the same token in the meantime
+		return nil, nil, s.revokeReusedFamily(ctx, stored)
+	}
 
-	// Update refresh token hash
-	user.RefreshTokenHash = jwt.HashToken(tokenPair.RefreshToken)
-	if err = s.userRepo.GetQuery(ctx).Where("id = ?", user.ID).UpdateColumn("refresh_token_hash", user.RefreshTokenHash).Error; err != nil {
+	tokenPair, err := s.issueTokens(ctx, user, claims.AuthType, claims.SubjectPermissions, claims.ExtraRoles, stored.FamilyID)
+	if err != nil {
 		return nil, nil, err
 	}
 
 	return user, tokenPair, nil
 }
 
-// Logout invalidates the user's refresh token
+// Logout revokes every refresh token of the user
 func (s *authService) Logout(ctx context.Context, userID int64) error {
-	return s.userRepo.GetQuery(ctx).Where("id = ?", userID).Update("refresh_token_hash", "").Error
+	return s.refreshTokenRepo.RevokeByUser(ctx, userID)
+}
+
+// Impersonate issues an access token acting as the target user on behalf of adminUser,
+// the caller is responsible for checking the impersonate admin permission
+func (s *authService) Impersonate(ctx context.Context, adminUser *model.User, targetUsername string) (*model.User, *types.TokenPair, error) {
+	user, err := s.userRepo.FindByUsername(ctx, targetUsername)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrUserNotFound
+		}
+		return nil, nil, err
+	}
+
+	if user.ID == adminUser.ID {
+		return nil, nil, ErrImpersonateSelf
+	}
+
+	if !user.IsActive() {
+		return nil, nil, ErrUserInactive
+	}
+
+	accessToken, expiresAt, err := s.jwtService.GenerateImpersonationToken(user, adminUser)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "user impersonation started", "impersonator", adminUser.Username, "impersonatorId", adminUser.ID, "username", user.Username, "id", user.ID)
+	return user, &types.TokenPair{AccessToken: accessToken, ExpiresAt: expiresAt}, nil
+}
+
+func (s *authService) issueTokens(ctx context.Context, user *model.User, authType types.AuthType, subjectPermissions *model.SubjectPermissions, extraRoles []string, familyID string) (*types.TokenPair, error) {
+	tokenPair, err := s.jwtService.GenerateTokenPair(user, authType, subjectPermissions, extraRoles)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.refreshTokenRepo.Create(ctx, &model.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: jwt.HashToken(tokenPair.RefreshToken),
+		ExpiresAt: time.Now().Add(s.ctx.Config.Auth.JWT.RefreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenPair, nil
+}
+
+func (s *authService) revokeReusedFamily(ctx context.Context, token *model.RefreshToken) error {
+	s.ctx.Logger.WarnContext(ctx, "refresh token reuse detected, revoking session", "userId", token.UserID, "familyId", token.FamilyID)
+	if err := s.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func generateFamilyID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
 }
 
 func (s *authService) ToUserResponse(user *model.User) *types.UserResponse {
 	return &types.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Firstname: user.Firstname,
-		Lastname:  user.Lastname,
+		ID:                 user.ID,
+		Username:           user.Username,
+		Firstname:          user.Firstname,
+		Lastname:           user.Lastname,
+		MustChangePassword: user.NeedsPasswordChange(s.ctx.Config.Auth.Password.MaxAge),
 	}
 }
@@ -12,16 +12,17 @@ import (
 	mockFlectoRepository "github.com/flectolab/flecto-manager/mocks/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/types"
+	gojwt "github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/crypto/bcrypt"
-	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
-func setupAuthServiceTest(t *testing.T) (*gomock.Controller, *mockFlectoRepository.MockUserRepository, *jwt.ServiceJWT, AuthService) {
+func setupAuthServiceTest(t *testing.T) (*gomock.Controller, *mockFlectoRepository.MockUserRepository, *mockFlectoRepository.MockRefreshTokenRepository, *jwt.ServiceJWT, AuthService) {
 	ctrl := gomock.NewController(t)
 	mockUserRepo := mockFlectoRepository.NewMockUserRepository(ctrl)
+	mockRefreshTokenRepo := mockFlectoRepository.NewMockRefreshTokenRepository(ctrl)
 	jwtService := jwt.NewServiceJWT(&config.JWTConfig{
 		Secret:          "test-secret-key-32-bytes-long!!!",
 		Issuer:          "test-issuer",
@@ -29,21 +30,22 @@ func setupAuthServiceTest(t *testing.T) (*gomock.Controller, *mockFlectoReposito
 		RefreshTokenTTL: 24 * time.Hour,
 	})
 	ctx := appContext.TestContext(nil)
-	svc := NewAuthService(ctx, mockUserRepo, jwtService)
-	return ctrl, mockUserRepo, jwtService, svc
+	svc := NewAuthService(ctx, mockUserRepo, mockRefreshTokenRepo, jwtService)
+	return ctrl, mockUserRepo, mockRefreshTokenRepo, jwtService, svc
 }
 
 func TestNewAuthService(t *testing.T) {
-	ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+	ctrl, mockUserRepo, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
 	defer ctrl.Finish()
 
 	assert.NotNil(t, svc)
 	assert.NotNil(t, mockUserRepo)
+	assert.NotNil(t, mockRefreshTokenRepo)
 }
 
 func TestAuthService_Login(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+		ctrl, mockUserRepo, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -66,10 +68,11 @@ func TestAuthService_Login(t *testing.T) {
 			FindByUsername(ctx, "testuser").
 			Return(user, nil)
 
-		mockUserRepo.EXPECT().
-			Update(ctx, gomock.Any()).
-			DoAndReturn(func(ctx context.Context, u *model.User) error {
-				assert.NotEmpty(t, u.RefreshTokenHash)
+		var stored *model.RefreshToken
+		mockRefreshTokenRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token *model.RefreshToken) error {
+				stored = token
 				return nil
 			})
 
@@ -80,10 +83,14 @@ func TestAuthService_Login(t *testing.T) {
 		assert.NotNil(t, tokens)
 		assert.NotEmpty(t, tokens.AccessToken)
 		assert.NotEmpty(t, tokens.RefreshToken)
+		assert.Equal(t, int64(1), stored.UserID)
+		assert.Equal(t, jwt.HashToken(tokens.RefreshToken), stored.TokenHash)
+		assert.NotEmpty(t, stored.FamilyID)
+		assert.True(t, stored.ExpiresAt.After(time.Now()))
 	})
 
 	t.Run("user not found", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+		ctrl, mockUserRepo, _, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -104,7 +111,7 @@ func TestAuthService_Login(t *testing.T) {
 	})
 
 	t.Run("database error on find", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+		ctrl, mockUserRepo, _, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -126,7 +133,7 @@ func TestAuthService_Login(t *testing.T) {
 	})
 
 	t.Run("user inactive", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+		ctrl, mockUserRepo, _, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -154,7 +161,7 @@ func TestAuthService_Login(t *testing.T) {
 	})
 
 	t.Run("user has no password", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+		ctrl, mockUserRepo, _, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -182,7 +189,7 @@ func TestAuthService_Login(t *testing.T) {
 	})
 
 	t.Run("wrong password", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+		ctrl, mockUserRepo, _, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -211,8 +218,8 @@ func TestAuthService_Login(t *testing.T) {
 		assert.Nil(t, tokens)
 	})
 
-	t.Run("update error", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+	t.Run("store refresh token error", func(t *testing.T) {
+		ctrl, mockUserRepo, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -236,8 +243,8 @@ func TestAuthService_Login(t *testing.T) {
 			FindByUsername(ctx, "testuser").
 			Return(user, nil)
 
-		mockUserRepo.EXPECT().
-			Update(ctx, gomock.Any()).
+		mockRefreshTokenRepo.EXPECT().
+			Create(ctx, gomock.Any()).
 			Return(updateErr)
 
 		resultUser, tokens, err := svc.Login(ctx, req)
@@ -248,9 +255,36 @@ func TestAuthService_Login(t *testing.T) {
 	})
 }
 
+func TestAuthService_IssueTokens(t *testing.T) {
+	t.Run("starts a new token family", func(t *testing.T) {
+		ctrl, _, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		user := &model.User{ID: 1, Username: "testuser", Active: boolPtr(true)}
+
+		var families []string
+		mockRefreshTokenRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token *model.RefreshToken) error {
+				families = append(families, token.FamilyID)
+				return nil
+			}).
+			Times(2)
+
+		_, err := svc.IssueTokens(ctx, user, types.AuthTypeOpenID, nil, []string{"admin"})
+		assert.NoError(t, err)
+		_, err = svc.IssueTokens(ctx, user, types.AuthTypeOpenID, nil, []string{"admin"})
+		assert.NoError(t, err)
+
+		assert.Len(t, families, 2)
+		assert.NotEqual(t, families[0], families[1])
+	})
+}
+
 func TestAuthService_RefreshTokens(t *testing.T) {
-	t.Run("success", func(t *testing.T) {
-		ctrl, mockUserRepo, jwtService, svc := setupAuthServiceTest(t)
+	t.Run("success rotates the token in the same family", func(t *testing.T) {
+		ctrl, mockUserRepo, mockRefreshTokenRepo, jwtService, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -260,39 +294,59 @@ func TestAuthService_RefreshTokens(t *testing.T) {
 			Active:   boolPtr(true),
 		}
 
-		// Generate initial tokens to get a valid refresh token
-		tokenPair, _ := jwtService.GenerateTokenPair(user, types.AuthTypeBasic, nil, nil)
-		user.RefreshTokenHash = jwt.HashToken(tokenPair.RefreshToken)
-
+		tokenPair, _ := jwtService.GenerateTokenPair(user, types.AuthTypeOpenID, nil, []string{"admin"})
 		claims := &jwt.Claims{
+			UserID:     1,
+			TokenType:  types.TokenTypeRefresh,
+			AuthType:   types.AuthTypeOpenID,
+			ExtraRoles: []string{"admin"},
+		}
+		stored := &model.RefreshToken{
+			ID:        10,
 			UserID:    1,
-			TokenType: types.TokenTypeRefresh,
+			FamilyID:  "family",
+			TokenHash: jwt.HashToken(tokenPair.RefreshToken),
+			ExpiresAt: time.Now().Add(time.Hour),
 		}
 
-		// Setup mock for GetQuery to handle the update
-		db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-		db.AutoMigrate(&model.User{})
-		db.Create(user)
-
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, stored.TokenHash).
+			Return(stored, nil)
 		mockUserRepo.EXPECT().
 			FindByID(ctx, int64(1)).
 			Return(user, nil)
-
-		mockUserRepo.EXPECT().
-			GetQuery(ctx).
-			Return(db.Model(&model.User{}))
+		mockRefreshTokenRepo.EXPECT().
+			Revoke(ctx, int64(10)).
+			Return(true, nil)
+
+		var rotated *model.RefreshToken
+		mockRefreshTokenRepo.EXPECT().
+			Create(ctx, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, token *model.RefreshToken) error {
+				rotated = token
+				return nil
+			})
 
 		resultUser, tokens, err := svc.RefreshTokens(ctx, tokenPair.RefreshToken, claims)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, resultUser)
 		assert.NotNil(t, tokens)
-		assert.NotEmpty(t, tokens.AccessToken)
-		assert.NotEmpty(t, tokens.RefreshToken)
+		assert.NotEqual(t, tokenPair.RefreshToken, tokens.RefreshToken)
+		assert.Equal(t, "family", rotated.FamilyID)
+		assert.Equal(t, jwt.HashToken(tokens.RefreshToken), rotated.TokenHash)
+
+		newClaims := &jwt.Claims{}
+		_, err = gojwt.ParseWithClaims(tokens.AccessToken, newClaims, func(token *gojwt.Token) (interface{}, error) {
+			return jwtService.GetSecret(), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, types.AuthTypeOpenID, newClaims.AuthType)
+		assert.Equal(t, []string{"admin"}, newClaims.ExtraRoles)
 	})
 
 	t.Run("wrong token type", func(t *testing.T) {
-		ctrl, _, _, svc := setupAuthServiceTest(t)
+		ctrl, _, _, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -308,29 +362,29 @@ func TestAuthService_RefreshTokens(t *testing.T) {
 		assert.Nil(t, tokens)
 	})
 
-	t.Run("user not found", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+	t.Run("unknown token", func(t *testing.T) {
+		ctrl, _, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
 		claims := &jwt.Claims{
-			UserID:    999,
+			UserID:    1,
 			TokenType: types.TokenTypeRefresh,
 		}
 
-		mockUserRepo.EXPECT().
-			FindByID(ctx, int64(999)).
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, jwt.HashToken("some-token")).
 			Return(nil, gorm.ErrRecordNotFound)
 
 		resultUser, tokens, err := svc.RefreshTokens(ctx, "some-token", claims)
 
-		assert.Equal(t, ErrUserNotFound, err)
+		assert.Equal(t, ErrInvalidCredentials, err)
 		assert.Nil(t, resultUser)
 		assert.Nil(t, tokens)
 	})
 
-	t.Run("database error on find", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+	t.Run("database error on token lookup", func(t *testing.T) {
+		ctrl, _, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -340,8 +394,8 @@ func TestAuthService_RefreshTokens(t *testing.T) {
 		}
 		dbErr := errors.New("database error")
 
-		mockUserRepo.EXPECT().
-			FindByID(ctx, int64(1)).
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, gomock.Any()).
 			Return(nil, dbErr)
 
 		resultUser, tokens, err := svc.RefreshTokens(ctx, "some-token", claims)
@@ -351,95 +405,175 @@ func TestAuthService_RefreshTokens(t *testing.T) {
 		assert.Nil(t, tokens)
 	})
 
-	t.Run("user inactive", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+	t.Run("token of another user", func(t *testing.T) {
+		ctrl, _, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
-		user := &model.User{
-			ID:       1,
-			Username: "testuser",
-			Active:   boolPtr(false),
+		claims := &jwt.Claims{
+			UserID:    1,
+			TokenType: types.TokenTypeRefresh,
 		}
 
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, gomock.Any()).
+			Return(&model.RefreshToken{ID: 10, UserID: 2, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+		resultUser, tokens, err := svc.RefreshTokens(ctx, "some-token", claims)
+
+		assert.Equal(t, ErrInvalidCredentials, err)
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		ctrl, _, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
 		claims := &jwt.Claims{
 			UserID:    1,
 			TokenType: types.TokenTypeRefresh,
 		}
 
-		mockUserRepo.EXPECT().
-			FindByID(ctx, int64(1)).
-			Return(user, nil)
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, gomock.Any()).
+			Return(&model.RefreshToken{ID: 10, UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
 
 		resultUser, tokens, err := svc.RefreshTokens(ctx, "some-token", claims)
 
-		assert.Equal(t, ErrUserInactive, err)
+		assert.Equal(t, ErrInvalidCredentials, err)
 		assert.Nil(t, resultUser)
 		assert.Nil(t, tokens)
 	})
 
-	t.Run("invalid refresh token hash", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+	t.Run("reused token revokes the family", func(t *testing.T) {
+		ctrl, _, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
-		user := &model.User{
-			ID:               1,
-			Username:         "testuser",
-			Active:           boolPtr(true),
-			RefreshTokenHash: "different-hash",
+		claims := &jwt.Claims{
+			UserID:    1,
+			TokenType: types.TokenTypeRefresh,
 		}
+		revokedAt := time.Now().Add(-time.Minute)
+
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, gomock.Any()).
+			Return(&model.RefreshToken{ID: 10, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour), RevokedAt: &revokedAt}, nil)
+		mockRefreshTokenRepo.EXPECT().
+			RevokeFamily(ctx, "family").
+			Return(nil)
+
+		resultUser, tokens, err := svc.RefreshTokens(ctx, "some-token", claims)
+
+		assert.Equal(t, ErrRefreshTokenReused, err)
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+	})
 
+	t.Run("concurrent rotation revokes the family", func(t *testing.T) {
+		ctrl, mockUserRepo, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
 		claims := &jwt.Claims{
 			UserID:    1,
 			TokenType: types.TokenTypeRefresh,
 		}
 
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, gomock.Any()).
+			Return(&model.RefreshToken{ID: 10, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}, nil)
 		mockUserRepo.EXPECT().
 			FindByID(ctx, int64(1)).
-			Return(user, nil)
+			Return(&model.User{ID: 1, Username: "testuser", Active: boolPtr(true)}, nil)
+		mockRefreshTokenRepo.EXPECT().
+			Revoke(ctx, int64(10)).
+			Return(false, nil)
+		mockRefreshTokenRepo.EXPECT().
+			RevokeFamily(ctx, "family").
+			Return(nil)
 
 		resultUser, tokens, err := svc.RefreshTokens(ctx, "some-token", claims)
 
-		assert.Equal(t, ErrInvalidCredentials, err)
+		assert.Equal(t, ErrRefreshTokenReused, err)
 		assert.Nil(t, resultUser)
 		assert.Nil(t, tokens)
 	})
 
-	t.Run("update error", func(t *testing.T) {
-		ctrl, mockUserRepo, jwtService, svc := setupAuthServiceTest(t)
+	t.Run("user not found", func(t *testing.T) {
+		ctrl, mockUserRepo, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
-		user := &model.User{
-			ID:       1,
-			Username: "testuser",
-			Active:   boolPtr(true),
+		claims := &jwt.Claims{
+			UserID:    999,
+			TokenType: types.TokenTypeRefresh,
 		}
 
-		// Generate initial tokens to get a valid refresh token
-		tokenPair, _ := jwtService.GenerateTokenPair(user, types.AuthTypeBasic, nil, nil)
-		user.RefreshTokenHash = jwt.HashToken(tokenPair.RefreshToken)
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, gomock.Any()).
+			Return(&model.RefreshToken{ID: 10, UserID: 999, ExpiresAt: time.Now().Add(time.Hour)}, nil)
+		mockUserRepo.EXPECT().
+			FindByID(ctx, int64(999)).
+			Return(nil, gorm.ErrRecordNotFound)
+
+		resultUser, tokens, err := svc.RefreshTokens(ctx, "some-token", claims)
+
+		assert.Equal(t, ErrUserNotFound, err)
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+	})
 
+	t.Run("user inactive", func(t *testing.T) {
+		ctrl, mockUserRepo, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
 		claims := &jwt.Claims{
 			UserID:    1,
 			TokenType: types.TokenTypeRefresh,
 		}
 
-		// Setup mock for GetQuery to return a db that will fail (no table)
-		db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, gomock.Any()).
+			Return(&model.RefreshToken{ID: 10, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
 		mockUserRepo.EXPECT().
 			FindByID(ctx, int64(1)).
-			Return(user, nil)
+			Return(&model.User{ID: 1, Username: "testuser", Active: boolPtr(false)}, nil)
+
+		resultUser, tokens, err := svc.RefreshTokens(ctx, "some-token", claims)
+
+		assert.Equal(t, ErrUserInactive, err)
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("revoke error", func(t *testing.T) {
+		ctrl, mockUserRepo, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
 
+		ctx := context.Background()
+		claims := &jwt.Claims{
+			UserID:    1,
+			TokenType: types.TokenTypeRefresh,
+		}
+		dbErr := errors.New("database error")
+
+		mockRefreshTokenRepo.EXPECT().
+			FindByHash(ctx, gomock.Any()).
+			Return(&model.RefreshToken{ID: 10, UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}, nil)
 		mockUserRepo.EXPECT().
-			GetQuery(ctx).
-			Return(db.Model(&model.User{}))
+			FindByID(ctx, int64(1)).
+			Return(&model.User{ID: 1, Username: "testuser", Active: boolPtr(true)}, nil)
+		mockRefreshTokenRepo.EXPECT().
+			Revoke(ctx, int64(10)).
+			Return(false, dbErr)
 
-		resultUser, tokens, err := svc.RefreshTokens(ctx, tokenPair.RefreshToken, claims)
+		resultUser, tokens, err := svc.RefreshTokens(ctx, "some-token", claims)
 
-		assert.Error(t, err)
+		assert.Equal(t, dbErr, err)
 		assert.Nil(t, resultUser)
 		assert.Nil(t, tokens)
 	})
@@ -447,56 +581,130 @@ func TestAuthService_RefreshTokens(t *testing.T) {
 
 func TestAuthService_Logout(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+		ctrl, _, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
 
-		// Setup mock for GetQuery
-		db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
-		db.AutoMigrate(&model.User{})
-		db.Create(&model.User{
-			ID:               1,
-			Username:         "testuser",
-			RefreshTokenHash: "some-hash",
-		})
+		mockRefreshTokenRepo.EXPECT().
+			RevokeByUser(ctx, int64(1)).
+			Return(nil)
 
-		mockUserRepo.EXPECT().
-			GetQuery(ctx).
-			Return(db.Model(&model.User{}))
+		err := svc.Logout(ctx, 1)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl, _, mockRefreshTokenRepo, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		mockRefreshTokenRepo.EXPECT().
+			RevokeByUser(ctx, int64(1)).
+			Return(errors.New("database error"))
 
 		err := svc.Logout(ctx, 1)
 
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthService_Impersonate(t *testing.T) {
+	admin := &model.User{ID: 1, Username: "admin"}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl, mockUserRepo, _, jwtService, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		target := &model.User{ID: 2, Username: "target", Active: boolPtr(true)}
+
+		mockUserRepo.EXPECT().
+			FindByUsername(ctx, "target").
+			Return(target, nil)
+
+		resultUser, tokens, err := svc.Impersonate(ctx, admin, "target")
+
 		assert.NoError(t, err)
+		assert.Equal(t, target, resultUser)
+		assert.NotEmpty(t, tokens.AccessToken)
+		assert.Empty(t, tokens.RefreshToken)
 
-		// Verify token hash was cleared
-		var user model.User
-		db.First(&user, 1)
-		assert.Empty(t, user.RefreshTokenHash)
+		claims := &jwt.Claims{}
+		_, err = gojwt.ParseWithClaims(tokens.AccessToken, claims, func(token *gojwt.Token) (interface{}, error) {
+			return jwtService.GetSecret(), nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), claims.UserID)
+		assert.Equal(t, types.AuthTypeImpersonation, claims.AuthType)
+		assert.Equal(t, "admin", claims.ImpersonatorUsername)
 	})
 
-	t.Run("error", func(t *testing.T) {
-		ctrl, mockUserRepo, _, svc := setupAuthServiceTest(t)
+	t.Run("user not found", func(t *testing.T) {
+		ctrl, mockUserRepo, _, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
+		mockUserRepo.EXPECT().
+			FindByUsername(ctx, "unknown").
+			Return(nil, gorm.ErrRecordNotFound)
+
+		resultUser, tokens, err := svc.Impersonate(ctx, admin, "unknown")
+
+		assert.Equal(t, ErrUserNotFound, err)
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+	})
 
-		// Setup mock for GetQuery with a db that will fail (no table)
-		db, _ := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	t.Run("database error", func(t *testing.T) {
+		ctrl, mockUserRepo, _, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
 
+		ctx := context.Background()
+		dbErr := errors.New("database error")
 		mockUserRepo.EXPECT().
-			GetQuery(ctx).
-			Return(db.Model(&model.User{}))
+			FindByUsername(ctx, "target").
+			Return(nil, dbErr)
 
-		err := svc.Logout(ctx, 1)
+		_, _, err := svc.Impersonate(ctx, admin, "target")
 
-		assert.Error(t, err)
+		assert.Equal(t, dbErr, err)
+	})
+
+	t.Run("self impersonation", func(t *testing.T) {
+		ctrl, mockUserRepo, _, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		mockUserRepo.EXPECT().
+			FindByUsername(ctx, "admin").
+			Return(&model.User{ID: 1, Username: "admin", Active: boolPtr(true)}, nil)
+
+		_, _, err := svc.Impersonate(ctx, admin, "admin")
+
+		assert.Equal(t, ErrImpersonateSelf, err)
+	})
+
+	t.Run("user inactive", func(t *testing.T) {
+		ctrl, mockUserRepo, _, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		mockUserRepo.EXPECT().
+			FindByUsername(ctx, "target").
+			Return(&model.User{ID: 2, Username: "target", Active: boolPtr(false)}, nil)
+
+		_, _, err := svc.Impersonate(ctx, admin, "target")
+
+		assert.Equal(t, ErrUserInactive, err)
 	})
 }
 
 func TestAuthService_ToUserResponse(t *testing.T) {
 	t.Run("converts user to response", func(t *testing.T) {
-		ctrl, _, _, svc := setupAuthServiceTest(t)
+		ctrl, _, _, _, svc := setupAuthServiceTest(t)
 		defer ctrl.Finish()
 
 		user := &model.User{
@@ -513,5 +721,22 @@ func TestAuthService_ToUserResponse(t *testing.T) {
 		assert.Equal(t, "testuser", response.Username)
 		assert.Equal(t, "Test", response.Firstname)
 		assert.Equal(t, "User", response.Lastname)
+		assert.False(t, response.MustChangePassword)
+	})
+
+	t.Run("surfaces forced password change", func(t *testing.T) {
+		ctrl, _, _, _, svc := setupAuthServiceTest(t)
+		defer ctrl.Finish()
+
+		user := &model.User{
+			ID:                 123,
+			Username:           "testuser",
+			Password:           "hashed_password",
+			MustChangePassword: true,
+		}
+
+		response := svc.ToUserResponse(user)
+
+		assert.True(t, response.MustChangePassword)
 	})
 }
\ No newline at end of file
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrChangesetNotFound      = flectoErrors.New(flectoErrors.CodeNotFound, "changeset not found")
+	ErrChangesetAlreadyExists = flectoErrors.New(flectoErrors.CodeAlreadyExists, "changeset already exists")
+)
+
+// ChangesetService manages the changesets grouping related drafts of a project, published or discarded together
+type ChangesetService interface {
+	GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.Changeset, error)
+	GetByID(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.Changeset, error)
+	Create(ctx context.Context, namespaceCode, projectCode, name, description string) (*model.Changeset, error)
+	Update(ctx context.Context, namespaceCode, projectCode string, id int64, name, description string) (*model.Changeset, error)
+	// Delete deletes the changeset, discarding its drafts when discardDrafts is set, its drafts being left out of any
+	// changeset otherwise
+	Delete(ctx context.Context, namespaceCode, projectCode string, id int64, discardDrafts bool) (bool, error)
+	// AssignDrafts moves drafts of the project to the changeset, a draft belonging to a single changeset, or out of any
+	// changeset when changesetID is nil. It returns the number of drafts moved.
+	AssignDrafts(ctx context.Context, namespaceCode, projectCode string, changesetID *int64, redirectDraftIDs, pageDraftIDs []int64) (int, error)
+	// Publish publishes the drafts of the changeset only, like ProjectService.Publish, and deletes the changeset
+	Publish(ctx context.Context, namespaceCode, projectCode string, id int64, force bool) (*model.Project, error)
+}
+
+type changesetService struct {
+	ctx         *appContext.Context
+	repo        repository.ChangesetRepository
+	projectRepo repository.ProjectRepository
+	projectSrv  ProjectService
+}
+
+func NewChangesetService(ctx *appContext.Context, repo repository.ChangesetRepository, projectRepo repository.ProjectRepository, projectSrv ProjectService) ChangesetService {
+	return &changesetService{
+		ctx:         ctx,
+		repo:        repo,
+		projectRepo: projectRepo,
+		projectSrv:  projectSrv,
+	}
+}
+
+func (s *changesetService) GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.Changeset, error) {
+	return s.repo.FindByProject(ctx, namespaceCode, projectCode)
+}
+
+func (s *changesetService) GetByID(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.Changeset, error) {
+	changeset, err := s.repo.FindByID(ctx, namespaceCode, projectCode, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: changeset %d of project %s/%s", ErrChangesetNotFound, id, namespaceCode, projectCode)
+		}
+		return nil, err
+	}
+	return changeset, nil
+}
+
+func (s *changesetService) Create(ctx context.Context, namespaceCode, projectCode, name, description string) (*model.Changeset, error) {
+	if _, err := s.projectRepo.FindByCode(ctx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+
+	changeset := &model.Changeset{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		Name:          name,
+		Description:   description,
+		CreatedBy:     types.SubjectFromContext(ctx),
+	}
+	if err := s.ctx.Validator.Struct(changeset); err != nil {
+		return nil, err
+	}
+	if err := s.checkNameAvailable(ctx, namespaceCode, projectCode, name, 0); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, changeset); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to create changeset", "namespace", namespaceCode, "project", projectCode, "name", name, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "changeset created", "namespace", namespaceCode, "project", projectCode, "changeset", changeset.ID, "name", name)
+	return changeset, nil
+}
+
+func (s *changesetService) Update(ctx context.Context, namespaceCode, projectCode string, id int64, name, description string) (*model.Changeset, error) {
+	changeset, err := s.GetByID(ctx, namespaceCode, projectCode, id)
+	if err != nil {
+		return nil, err
+	}
+
+	changeset.Name = name
+	changeset.Description = description
+	if err = s.ctx.Validator.Struct(changeset); err != nil {
+		return nil, err
+	}
+	if err = s.checkNameAvailable(ctx, namespaceCode, projectCode, name, id); err != nil {
+		return nil, err
+	}
+	if err = s.repo.Update(ctx, changeset); err != nil {
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "changeset updated", "namespace", namespaceCode, "project", projectCode, "changeset", id, "name", name)
+	return changeset, nil
+}
+
+func (s *changesetService) Delete(ctx context.Context, namespaceCode, projectCode string, id int64, discardDrafts bool) (bool, error) {
+	changeset, err := s.GetByID(ctx, namespaceCode, projectCode, id)
+	if err != nil {
+		return false, err
+	}
+
+	if err = s.repo.Delete(ctx, id, discardDrafts); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete changeset", "namespace", namespaceCode, "project", projectCode, "changeset", id, "error", err)
+		return false, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "changeset deleted", "namespace", namespaceCode, "project", projectCode, "changeset", id,
+		"discardDrafts", discardDrafts, "redirectDrafts", changeset.RedirectDraftCount, "pageDrafts", changeset.PageDraftCount)
+	return true, nil
+}
+
+func (s *changesetService) AssignDrafts(ctx context.Context, namespaceCode, projectCode string, changesetID *int64, redirectDraftIDs, pageDraftIDs []int64) (int, error) {
+	if changesetID != nil {
+		if _, err := s.GetByID(ctx, namespaceCode, projectCode, *changesetID); err != nil {
+			return 0, err
+		}
+	}
+
+	assigned, err := s.repo.AssignDrafts(ctx, namespaceCode, projectCode, changesetID, redirectDraftIDs, pageDraftIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "drafts assigned to changeset", "namespace", namespaceCode, "project", projectCode, "changeset", changesetID, "drafts", assigned)
+	return int(assigned), nil
+}
+
+func (s *changesetService) Publish(ctx context.Context, namespaceCode, projectCode string, id int64, force bool) (*model.Project, error) {
+	if _, err := s.GetByID(ctx, namespaceCode, projectCode, id); err != nil {
+		return nil, err
+	}
+	return s.projectSrv.Publish(ctx, namespaceCode, projectCode, types.PublishOptions{Force: force, ChangesetID: id})
+}
+
+// checkNameAvailable returns ErrChangesetAlreadyExists when another changeset of the project than id has the name
+func (s *changesetService) checkNameAvailable(ctx context.Context, namespaceCode, projectCode, name string, id int64) error {
+	existing, err := s.repo.FindByName(ctx, namespaceCode, projectCode, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != id {
+		return fmt.Errorf("%w: %s", ErrChangesetAlreadyExists, name)
+	}
+	return nil
+}
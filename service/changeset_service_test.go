@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupChangesetServiceTest(t *testing.T) (*gorm.DB, ChangesetService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Changeset{}, &model.Tag{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{},
+		&model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 1}).Error)
+
+	ctx := testContextWithPageConfig(defaultProjectCfg)
+	projectRepo := repository.NewProjectRepository(db)
+	projectSrv := NewProjectService(ctx, projectRepo, repository.NewPageRepository(db), repository.NewRedirectDraftRepository(db), repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+	return db, NewChangesetService(ctx, repository.NewChangesetRepository(db), projectRepo, projectSrv)
+}
+
+// createChangesetRedirectDraft stages the creation of a redirect from source
+func createChangesetRedirectDraft(t *testing.T, db *gorm.DB, source string) *model.RedirectDraft {
+	redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(false)}
+	require.NoError(t, db.Create(redirect).Error)
+	draft := &model.RedirectDraft{
+		NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &redirect.ID,
+		NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+	}
+	require.NoError(t, db.Create(draft).Error)
+	return draft
+}
+
+func TestChangesetService_Create(t *testing.T) {
+	_, svc := setupChangesetServiceTest(t)
+	ctx := context.Background()
+
+	changeset, err := svc.Create(ctx, "test-ns", "test-proj", "Q3 blog migration", "Move the blog to /articles")
+	require.NoError(t, err)
+	assert.NotZero(t, changeset.ID)
+	assert.Equal(t, "Move the blog to /articles", changeset.Description)
+
+	_, err = svc.Create(ctx, "test-ns", "test-proj", "Q3 blog migration", "")
+	assert.ErrorIs(t, err, ErrChangesetAlreadyExists)
+
+	_, err = svc.Create(ctx, "test-ns", "test-proj", "", "")
+	assert.Equal(t, flectoErrors.CodeValidationFailed, flectoErrors.From(err).Code)
+
+	_, err = svc.Create(ctx, "test-ns", "unknown", "migration", "")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestChangesetService_Update(t *testing.T) {
+	_, svc := setupChangesetServiceTest(t)
+	ctx := context.Background()
+	changeset, err := svc.Create(ctx, "test-ns", "test-proj", "migration", "")
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, "test-ns", "test-proj", "archive", "")
+	require.NoError(t, err)
+
+	updated, err := svc.Update(ctx, "test-ns", "test-proj", changeset.ID, "migration", "Q3")
+	require.NoError(t, err)
+	assert.Equal(t, "Q3", updated.Description)
+
+	_, err = svc.Update(ctx, "test-ns", "test-proj", changeset.ID, "archive", "")
+	assert.ErrorIs(t, err, ErrChangesetAlreadyExists)
+
+	_, err = svc.Update(ctx, "test-ns", "test-proj", 999, "other", "")
+	assert.ErrorIs(t, err, ErrChangesetNotFound)
+}
+
+func TestChangesetService_AssignDrafts(t *testing.T) {
+	db, svc := setupChangesetServiceTest(t)
+	ctx := context.Background()
+	changeset, err := svc.Create(ctx, "test-ns", "test-proj", "migration", "")
+	require.NoError(t, err)
+	draft := createChangesetRedirectDraft(t, db, "/old")
+
+	assigned, err := svc.AssignDrafts(ctx, "test-ns", "test-proj", &changeset.ID, []int64{draft.ID}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, assigned)
+
+	changesets, err := svc.GetByProject(ctx, "test-ns", "test-proj")
+	require.NoError(t, err)
+	require.Len(t, changesets, 1)
+	assert.Equal(t, int64(1), changesets[0].RedirectDraftCount)
+
+	unknown := int64(999)
+	_, err = svc.AssignDrafts(ctx, "test-ns", "test-proj", &unknown, []int64{draft.ID}, nil)
+	assert.ErrorIs(t, err, ErrChangesetNotFound)
+}
+
+func TestChangesetService_Publish(t *testing.T) {
+	db, svc := setupChangesetServiceTest(t)
+	ctx := context.Background()
+	changeset, err := svc.Create(ctx, "test-ns", "test-proj", "migration", "")
+	require.NoError(t, err)
+	inChangeset := createChangesetRedirectDraft(t, db, "/in")
+	outside := createChangesetRedirectDraft(t, db, "/out")
+	_, err = svc.AssignDrafts(ctx, "test-ns", "test-proj", &changeset.ID, []int64{inChangeset.ID}, nil)
+	require.NoError(t, err)
+
+	project, err := svc.Publish(ctx, "test-ns", "test-proj", changeset.ID, false)
+	require.NoError(t, err)
+	assert.Equal(t, 2, project.Version)
+
+	var published model.Redirect
+	require.NoError(t, db.First(&published, *inChangeset.OldRedirectID).Error)
+	assert.True(t, *published.IsPublished)
+	assert.Equal(t, "/in", published.Source)
+	var unpublished model.Redirect
+	require.NoError(t, db.First(&unpublished, *outside.OldRedirectID).Error)
+	assert.False(t, *unpublished.IsPublished, "the drafts out of the changeset are not published")
+	assert.NoError(t, db.First(&model.RedirectDraft{}, outside.ID).Error)
+
+	_, err = svc.GetByID(ctx, "test-ns", "test-proj", changeset.ID)
+	assert.ErrorIs(t, err, ErrChangesetNotFound, "the changeset is deleted once published")
+
+	empty, err := svc.Create(ctx, "test-ns", "test-proj", "empty", "")
+	require.NoError(t, err)
+	_, err = svc.Publish(ctx, "test-ns", "test-proj", empty.ID, false)
+	assert.ErrorIs(t, err, ErrNothingToPublish)
+}
+
+func TestChangesetService_Delete(t *testing.T) {
+	db, svc := setupChangesetServiceTest(t)
+	ctx := context.Background()
+	changeset, err := svc.Create(ctx, "test-ns", "test-proj", "migration", "")
+	require.NoError(t, err)
+	draft := createChangesetRedirectDraft(t, db, "/old")
+	_, err = svc.AssignDrafts(ctx, "test-ns", "test-proj", &changeset.ID, []int64{draft.ID}, nil)
+	require.NoError(t, err)
+
+	deleted, err := svc.Delete(ctx, "test-ns", "test-proj", changeset.ID, true)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	assert.ErrorIs(t, db.First(&model.RedirectDraft{}, draft.ID).Error, gorm.ErrRecordNotFound)
+	assert.ErrorIs(t, db.First(&model.Redirect{}, *draft.OldRedirectID).Error, gorm.ErrRecordNotFound)
+
+	_, err = svc.Delete(ctx, "test-ns", "test-proj", changeset.ID, false)
+	assert.ErrorIs(t, err, ErrChangesetNotFound)
+}
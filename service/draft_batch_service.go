@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/i18n"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"gorm.io/gorm"
+)
+
+// MaxDraftOperations is the number of operations a batch may hold
+const MaxDraftOperations = 500
+
+var (
+	ErrNoDraftOperation       = flectoErrors.New(flectoErrors.CodeInvalidRequest, "no draft operation")
+	ErrTooManyDraftOperations = flectoErrors.New(flectoErrors.CodeInvalidRequest, "too many draft operations")
+	ErrInvalidDraftOperation  = flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid draft operation")
+
+	// errDraftBatchFailed rolls back the batches having a failed operation
+	errDraftBatchFailed = errors.New("draft batch failed")
+)
+
+// DraftBatchService applies several changes of the redirect and page drafts of a project at once
+type DraftBatchService interface {
+	// Apply runs the operations in order in a single transaction, committed only when all of them succeed. Each
+	// operation runs the checks of the mutation of the draft it changes, the errors of the failed operations being
+	// reported in their result. The other errors roll back the batch and are returned.
+	Apply(ctx context.Context, namespaceCode, projectCode string, operations []model.DraftOperation) (*model.DraftBatchResult, error)
+}
+
+type draftBatchService struct {
+	ctx     *appContext.Context
+	repo    repository.RedirectDraftRepository
+	lintSrv PageLintService
+	// notifications is given to the redirect draft services of the batches, nil sending no notification
+	notifications NotificationService
+}
+
+func NewDraftBatchService(ctx *appContext.Context, repo repository.RedirectDraftRepository, lintSrv PageLintService, notifications NotificationService) DraftBatchService {
+	return &draftBatchService{
+		ctx:           ctx,
+		repo:          repo,
+		lintSrv:       lintSrv,
+		notifications: notifications,
+	}
+}
+
+func (s *draftBatchService) Apply(ctx context.Context, namespaceCode, projectCode string, operations []model.DraftOperation) (*model.DraftBatchResult, error) {
+	if len(operations) == 0 {
+		return nil, ErrNoDraftOperation
+	}
+	if len(operations) > MaxDraftOperations {
+		return nil, fmt.Errorf("%w: %d operations, at most %d", ErrTooManyDraftOperations, len(operations), MaxDraftOperations)
+	}
+
+	result := &model.DraftBatchResult{Results: make([]model.DraftOperationResult, 0, len(operations))}
+	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		failed := false
+		for i, operation := range operations {
+			opResult := model.DraftOperationResult{Index: i}
+			// Each operation runs in a savepoint, so that the batch goes on after a failed one to report all of them
+			errOp := tx.Transaction(func(opTx *gorm.DB) error {
+				return s.applyOperation(ctx, opTx, namespaceCode, projectCode, operation, &opResult)
+			})
+			if errOp != nil {
+				if flectoErrors.From(errOp).Code == flectoErrors.CodeInternal {
+					return errOp
+				}
+				opResult.Error = i18n.Localize(ctx, errOp)
+				opResult.RedirectDraft = nil
+				opResult.PageDraft = nil
+				failed = true
+			}
+			result.Results = append(result.Results, opResult)
+		}
+		if failed {
+			return errDraftBatchFailed
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDraftBatchFailed) {
+		s.ctx.Logger.ErrorContext(ctx, "draft batch failed", "namespace", namespaceCode, "project", projectCode, "operations", len(operations), "error", err)
+		return nil, err
+	}
+
+	result.Applied = err == nil
+	if !result.Applied {
+		// The drafts of the succeeded operations were rolled back with the batch
+		for i := range result.Results {
+			result.Results[i].RedirectDraft = nil
+			result.Results[i].PageDraft = nil
+		}
+	}
+	s.ctx.Logger.InfoContext(ctx, "draft batch completed", "namespace", namespaceCode, "project", projectCode, "operations", len(operations), "applied", result.Applied)
+	return result, nil
+}
+
+// applyOperation runs the operation with the draft services of the transaction, setting the draft it creates or
+// updates in the result
+func (s *draftBatchService) applyOperation(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, operation model.DraftOperation, opResult *model.DraftOperationResult) error {
+	if operation.Action != model.DraftOperationActionCreate && operation.DraftID == nil {
+		return fmt.Errorf("%w: draftID must be provided to %s a draft", ErrInvalidDraftOperation, operation.Action)
+	}
+
+	var err error
+	switch operation.Target {
+	case model.DraftOperationTargetRedirect:
+		if operation.NewPage != nil {
+			return fmt.Errorf("%w: newPage given to a redirect operation", ErrInvalidDraftOperation)
+		}
+		drafts := NewRedirectDraftService(s.ctx, repository.NewRedirectDraftRepository(tx), s.notifications)
+		switch operation.Action {
+		case model.DraftOperationActionCreate:
+			opResult.RedirectDraft, err = drafts.Create(ctx, namespaceCode, projectCode, operation.OldID, operation.NewRedirect, operation.Tags)
+			return err
+		case model.DraftOperationActionUpdate:
+			if _, err = drafts.GetByIDWithProject(ctx, namespaceCode, projectCode, *operation.DraftID); err != nil {
+				return err
+			}
+			opResult.RedirectDraft, err = drafts.Update(ctx, *operation.DraftID, operation.NewRedirect, operation.Tags)
+			return err
+		case model.DraftOperationActionDelete:
+			if _, err = drafts.GetByIDWithProject(ctx, namespaceCode, projectCode, *operation.DraftID); err != nil {
+				return err
+			}
+			_, err = drafts.Delete(ctx, *operation.DraftID)
+			return err
+		}
+	case model.DraftOperationTargetPage:
+		if operation.NewRedirect != nil || operation.Tags != nil {
+			return fmt.Errorf("%w: newRedirect or tags given to a page operation", ErrInvalidDraftOperation)
+		}
+		drafts := NewPageDraftService(s.ctx, repository.NewPageDraftRepository(tx), repository.NewPageRepository(tx), s.lintSrv)
+		switch operation.Action {
+		case model.DraftOperationActionCreate:
+			opResult.PageDraft, err = drafts.Create(ctx, namespaceCode, projectCode, operation.OldID, operation.NewPage)
+			return err
+		case model.DraftOperationActionUpdate:
+			if _, err = drafts.GetByIDWithProject(ctx, namespaceCode, projectCode, *operation.DraftID); err != nil {
+				return err
+			}
+			opResult.PageDraft, err = drafts.Update(ctx, *operation.DraftID, operation.NewPage)
+			return err
+		case model.DraftOperationActionDelete:
+			if _, err = drafts.GetByIDWithProject(ctx, namespaceCode, projectCode, *operation.DraftID); err != nil {
+				return err
+			}
+			_, err = drafts.Delete(ctx, *operation.DraftID)
+			return err
+		}
+	}
+	return fmt.Errorf("%w: %s of a %s", ErrInvalidDraftOperation, operation.Action, operation.Target)
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupDraftBatchServiceTest(t *testing.T) (*gorm.DB, DraftBatchService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.NamespacePolicy{}, &model.Project{}, &model.Tag{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 1}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "other-proj", NamespaceCode: "test-ns", Name: "Other", Version: 1}).Error)
+
+	ctx := testContextWithPageConfig(defaultPageDraftTestConfig)
+	return db, NewDraftBatchService(ctx, repository.NewRedirectDraftRepository(db), NewPageLintService(appContext.TestContext(nil)), nil)
+}
+
+func newBatchRedirect(source string) *commonTypes.Redirect {
+	return &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent}
+}
+
+func newBatchPage(path string) *commonTypes.Page {
+	return &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: path, Content: "text", ContentType: commonTypes.PageContentTypeTextPlain}
+}
+
+func TestDraftBatchService_Apply(t *testing.T) {
+	t.Run("all operations applied", func(t *testing.T) {
+		db, svc := setupDraftBatchServiceTest(t)
+		existing := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate, NewRedirect: newBatchRedirect("/old")}
+		require.NoError(t, db.Create(existing).Error)
+
+		result, err := svc.Apply(context.Background(), "test-ns", "test-proj", []model.DraftOperation{
+			{Target: model.DraftOperationTargetRedirect, Action: model.DraftOperationActionCreate, NewRedirect: newBatchRedirect("/new"), Tags: []string{"summer"}},
+			{Target: model.DraftOperationTargetPage, Action: model.DraftOperationActionCreate, NewPage: newBatchPage("/new.txt")},
+			{Target: model.DraftOperationTargetRedirect, Action: model.DraftOperationActionDelete, DraftID: &existing.ID},
+		})
+
+		require.NoError(t, err)
+		assert.True(t, result.Applied)
+		require.Len(t, result.Results, 3)
+		for i, opResult := range result.Results {
+			assert.Equal(t, i, opResult.Index)
+			assert.Nil(t, opResult.Error)
+		}
+		require.NotNil(t, result.Results[0].RedirectDraft)
+		assert.Equal(t, "/new", result.Results[0].RedirectDraft.NewRedirect.Source)
+		require.NotNil(t, result.Results[1].PageDraft)
+		assert.Equal(t, "/new.txt", result.Results[1].PageDraft.NewPage.Path)
+
+		var redirectDrafts []model.RedirectDraft
+		require.NoError(t, db.Find(&redirectDrafts).Error)
+		require.Len(t, redirectDrafts, 1)
+		assert.Equal(t, "/new", redirectDrafts[0].NewRedirect.Source)
+		var pageDrafts int64
+		require.NoError(t, db.Model(&model.PageDraft{}).Count(&pageDrafts).Error)
+		assert.Equal(t, int64(1), pageDrafts)
+	})
+
+	t.Run("failed operation rolls back the batch", func(t *testing.T) {
+		db, svc := setupDraftBatchServiceTest(t)
+		missing := int64(999)
+
+		result, err := svc.Apply(context.Background(), "test-ns", "test-proj", []model.DraftOperation{
+			{Target: model.DraftOperationTargetRedirect, Action: model.DraftOperationActionCreate, NewRedirect: newBatchRedirect("/new")},
+			{Target: model.DraftOperationTargetPage, Action: model.DraftOperationActionUpdate, DraftID: &missing, NewPage: newBatchPage("/new.txt")},
+			{Target: model.DraftOperationTargetRedirect, Action: model.DraftOperationActionCreate, NewRedirect: newBatchRedirect("/new")},
+		})
+
+		require.NoError(t, err)
+		assert.False(t, result.Applied)
+		require.Len(t, result.Results, 3)
+		assert.Nil(t, result.Results[0].Error)
+		assert.Nil(t, result.Results[0].RedirectDraft)
+		require.NotNil(t, result.Results[1].Error)
+		assert.Equal(t, flectoErrors.CodeNotFound, result.Results[1].Error.Code)
+		require.NotNil(t, result.Results[2].Error, "the source is taken by the first operation of the batch")
+		assert.Equal(t, flectoErrors.CodeAlreadyExists, result.Results[2].Error.Code)
+
+		var redirectDrafts int64
+		require.NoError(t, db.Model(&model.RedirectDraft{}).Count(&redirectDrafts).Error)
+		assert.Equal(t, int64(0), redirectDrafts)
+	})
+
+	t.Run("draft of another project", func(t *testing.T) {
+		db, svc := setupDraftBatchServiceTest(t)
+		other := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "other-proj", ChangeType: model.DraftChangeTypeCreate, NewRedirect: newBatchRedirect("/other")}
+		require.NoError(t, db.Create(other).Error)
+
+		result, err := svc.Apply(context.Background(), "test-ns", "test-proj", []model.DraftOperation{
+			{Target: model.DraftOperationTargetRedirect, Action: model.DraftOperationActionDelete, DraftID: &other.ID},
+		})
+
+		require.NoError(t, err)
+		assert.False(t, result.Applied)
+		require.NotNil(t, result.Results[0].Error)
+		assert.Equal(t, flectoErrors.CodeNotFound, result.Results[0].Error.Code)
+		assert.NoError(t, db.First(&model.RedirectDraft{}, other.ID).Error)
+	})
+
+	t.Run("invalid operations", func(t *testing.T) {
+		_, svc := setupDraftBatchServiceTest(t)
+
+		result, err := svc.Apply(context.Background(), "test-ns", "test-proj", []model.DraftOperation{
+			{Target: model.DraftOperationTargetRedirect, Action: model.DraftOperationActionUpdate, NewRedirect: newBatchRedirect("/new")},
+			{Target: model.DraftOperationTargetPage, Action: model.DraftOperationActionCreate, NewPage: newBatchPage("/new.txt"), Tags: []string{"summer"}},
+		})
+
+		require.NoError(t, err)
+		assert.False(t, result.Applied)
+		for _, opResult := range result.Results {
+			require.NotNil(t, opResult.Error)
+			assert.ErrorIs(t, opResult.Error, ErrInvalidDraftOperation)
+		}
+	})
+
+	t.Run("no operation", func(t *testing.T) {
+		_, svc := setupDraftBatchServiceTest(t)
+
+		_, err := svc.Apply(context.Background(), "test-ns", "test-proj", nil)
+
+		assert.ErrorIs(t, err, ErrNoDraftOperation)
+	})
+
+	t.Run("too many operations", func(t *testing.T) {
+		_, svc := setupDraftBatchServiceTest(t)
+
+		_, err := svc.Apply(context.Background(), "test-ns", "test-proj", make([]model.DraftOperation, MaxDraftOperations+1))
+
+		assert.ErrorIs(t, err, ErrTooManyDraftOperations)
+	})
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+// draftLockPurgeInterval is the default interval of the purge of the expired locks
+const draftLockPurgeInterval = time.Hour
+
+var (
+	ErrDraftLocked       = flectoErrors.New(flectoErrors.CodeDraftLocked, "draft locked")
+	ErrDraftLockTarget   = flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid draft lock target")
+	ErrDraftLockNotOwned = flectoErrors.New(flectoErrors.CodeForbidden, "draft lock held by another user")
+)
+
+// DraftLockService lets a user claim a draft, or all the drafts of a project, so that the changes
+// of the other users are refused until the lock is released or expires
+type DraftLockService interface {
+	GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.DraftLock, error)
+	Lock(ctx context.Context, namespaceCode, projectCode string, target model.DraftLockTarget, draftID int64, username string, ttl time.Duration) (*model.DraftLock, error)
+	Unlock(ctx context.Context, namespaceCode, projectCode string, target model.DraftLockTarget, draftID int64, username string, override bool) (bool, error)
+	CheckDraft(ctx context.Context, namespaceCode, projectCode string, target model.DraftLockTarget, draftID int64, username string) error
+	CheckProject(ctx context.Context, namespaceCode, projectCode, username string) error
+	ScheduledTask() types.ScheduledTask
+}
+
+type draftLockService struct {
+	ctx  *appContext.Context
+	repo repository.DraftLockRepository
+}
+
+func NewDraftLockService(ctx *appContext.Context, repo repository.DraftLockRepository) DraftLockService {
+	return &draftLockService{
+		ctx:  ctx,
+		repo: repo,
+	}
+}
+
+// ScheduledTask removes the expired locks of all the projects of all the shards, hourly by default. The expired locks
+// are ignored anyway, the task only keeps the table small.
+func (s *draftLockService) ScheduledTask() types.ScheduledTask {
+	return types.ScheduledTask{
+		Name:     "draft_locks",
+		Interval: draftLockPurgeInterval,
+		Enabled:  true,
+		Run: func(ctx context.Context, now time.Time) error {
+			var errs []error
+			for _, ctx := range database.ShardContexts(s.repo.GetTx(ctx), ctx) {
+				deleted, err := s.repo.DeleteAllExpired(ctx, now)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if deleted > 0 {
+					s.ctx.Logger.InfoContext(ctx, "expired draft locks purged", "count", deleted)
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// GetByProject returns the active locks of a project
+func (s *draftLockService) GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.DraftLock, error) {
+	locks, err := s.repo.FindByProject(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	active := make([]model.DraftLock, 0, len(locks))
+	for _, lock := range locks {
+		if lock.IsActive(now) {
+			active = append(active, lock)
+		}
+	}
+	return active, nil
+}
+
+// Lock claims the target for the user during ttl, the configured TTL when 0, bounded by the configured maximum.
+// Locking a target already held by the user extends the lock. A project lock is refused while another user holds
+// a lock in the project, and a draft lock while another user holds the draft or the project.
+func (s *draftLockService) Lock(ctx context.Context, namespaceCode, projectCode string, target model.DraftLockTarget, draftID int64, username string, ttl time.Duration) (*model.DraftLock, error) {
+	if err := s.validateTarget(ctx, namespaceCode, projectCode, target, draftID); err != nil {
+		return nil, err
+	}
+	if ttl <= 0 {
+		ttl = s.ctx.CurrentConfig().DraftLock.TTL
+	}
+	ttl = min(ttl, s.ctx.CurrentConfig().DraftLock.MaxTTL)
+
+	now := time.Now()
+	if err := s.repo.DeleteExpired(ctx, namespaceCode, projectCode, now); err != nil {
+		return nil, err
+	}
+	locks, err := s.repo.FindByProject(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var owned *model.DraftLock
+	for i, lock := range locks {
+		if lock.Target == target && lock.DraftID == draftID && lock.LockedBy == username {
+			owned = &locks[i]
+			continue
+		}
+		if lock.LockedBy != username && (target == model.DraftLockTargetProject || lock.Covers(target, draftID)) {
+			return nil, lockedError(&lock)
+		}
+	}
+
+	if owned != nil {
+		owned.ExpiresAt = now.Add(ttl)
+		if err = s.repo.UpdateExpiry(ctx, owned); err != nil {
+			return nil, err
+		}
+		return owned, nil
+	}
+
+	lock := &model.DraftLock{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		Target:        target,
+		DraftID:       draftID,
+		LockedBy:      username,
+		ExpiresAt:     now.Add(ttl),
+	}
+	if err = s.repo.Create(ctx, lock); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to lock drafts", "namespace", namespaceCode, "project", projectCode, "target", target, "draftID", draftID, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "drafts locked", "namespace", namespaceCode, "project", projectCode, "target", target, "draftID", draftID, "lockedBy", username, "expiresAt", lock.ExpiresAt)
+	return lock, nil
+}
+
+// Unlock releases a lock, a lock of another user being only released with override.
+// It returns false when the target is not locked.
+func (s *draftLockService) Unlock(ctx context.Context, namespaceCode, projectCode string, target model.DraftLockTarget, draftID int64, username string, override bool) (bool, error) {
+	locks, err := s.GetByProject(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return false, err
+	}
+	for _, lock := range locks {
+		if lock.Target != target || lock.DraftID != draftID {
+			continue
+		}
+		if lock.LockedBy != username && !override {
+			return false, fmt.Errorf("%w: %s", ErrDraftLockNotOwned, lock.LockedBy)
+		}
+		if err = s.repo.Delete(ctx, lock.ID); err != nil {
+			return false, err
+		}
+		s.ctx.Logger.InfoContext(ctx, "drafts unlocked", "namespace", namespaceCode, "project", projectCode, "target", target, "draftID", draftID, "lockedBy", lock.LockedBy, "unlockedBy", username)
+		return true, nil
+	}
+	return false, nil
+}
+
+// CheckDraft returns ErrDraftLocked when another user locked the draft or the project.
+// A draftID of 0 checks the creation of a draft, only refused by a project lock.
+func (s *draftLockService) CheckDraft(ctx context.Context, namespaceCode, projectCode string, target model.DraftLockTarget, draftID int64, username string) error {
+	locks, err := s.GetByProject(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return err
+	}
+	for _, lock := range locks {
+		if lock.LockedBy != username && lock.Covers(target, draftID) {
+			return lockedError(&lock)
+		}
+	}
+	return nil
+}
+
+// CheckProject returns ErrDraftLocked when another user holds any lock in the project,
+// for the changes affecting all the drafts of a project
+func (s *draftLockService) CheckProject(ctx context.Context, namespaceCode, projectCode, username string) error {
+	locks, err := s.GetByProject(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return err
+	}
+	for _, lock := range locks {
+		if lock.LockedBy != username {
+			return lockedError(&lock)
+		}
+	}
+	return nil
+}
+
+// validateTarget checks that a draft lock targets an existing draft of the project
+func (s *draftLockService) validateTarget(ctx context.Context, namespaceCode, projectCode string, target model.DraftLockTarget, draftID int64) error {
+	var draftModel any
+	switch target {
+	case model.DraftLockTargetProject:
+		if draftID != 0 {
+			return fmt.Errorf("%w: a project lock has no draft id", ErrDraftLockTarget)
+		}
+		return nil
+	case model.DraftLockTargetRedirectDraft:
+		draftModel = &model.RedirectDraft{}
+	case model.DraftLockTargetPageDraft:
+		draftModel = &model.PageDraft{}
+	default:
+		return fmt.Errorf("%w: %s", ErrDraftLockTarget, target)
+	}
+
+	var count int64
+	err := s.repo.GetTx(ctx).Model(draftModel).
+		Where("id = ? AND namespace_code = ? AND project_code = ?", draftID, namespaceCode, projectCode).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func lockedError(lock *model.DraftLock) error {
+	if lock.Target == model.DraftLockTargetProject {
+		return fmt.Errorf("%w: the drafts of the project are locked by %s until %s", ErrDraftLocked, lock.LockedBy, lock.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return fmt.Errorf("%w: the draft is locked by %s until %s", ErrDraftLocked, lock.LockedBy, lock.ExpiresAt.UTC().Format(time.RFC3339))
+}
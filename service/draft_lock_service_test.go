@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupDraftLockServiceTest(t *testing.T) (*gorm.DB, DraftLockService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Tag{}, &model.RedirectDraft{}, &model.PageDraft{}, &model.DraftLock{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test"}).Error)
+	for _, source := range []string{"/a", "/b"} {
+		require.NoError(t, db.Create(&model.RedirectDraft{
+			NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate,
+			NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+		}).Error)
+	}
+
+	ctx := testContextWithPageConfig(defaultProjectCfg)
+	return db, NewDraftLockService(ctx, repository.NewDraftLockRepository(db))
+}
+
+func TestDraftLockService_Lock(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ttl", func(t *testing.T) {
+		_, svc := setupDraftLockServiceTest(t)
+
+		lock, err := svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 1, "alice", 0)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(30*time.Minute), lock.ExpiresAt, time.Second)
+
+		// Locking again extends the lock, up to the maximum duration
+		again, err := svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 1, "alice", 100*time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, lock.ID, again.ID)
+		assert.WithinDuration(t, time.Now().Add(8*time.Hour), again.ExpiresAt, time.Second)
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		_, svc := setupDraftLockServiceTest(t)
+
+		_, err := svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 1, "alice", 0)
+		require.NoError(t, err)
+
+		_, err = svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 1, "bob", 0)
+		assert.ErrorIs(t, err, ErrDraftLocked)
+		assert.ErrorContains(t, err, "the draft is locked by alice until ")
+
+		_, err = svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetProject, 0, "bob", 0)
+		assert.ErrorIs(t, err, ErrDraftLocked)
+
+		_, err = svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 2, "bob", 0)
+		require.NoError(t, err)
+
+		_, err = svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetProject, 0, "alice", 0)
+		assert.ErrorIs(t, err, ErrDraftLocked)
+		assert.ErrorContains(t, err, "locked by bob")
+	})
+
+	t.Run("expired lock", func(t *testing.T) {
+		db, svc := setupDraftLockServiceTest(t)
+		require.NoError(t, db.Create(&model.DraftLock{NamespaceCode: "test-ns", ProjectCode: "test-proj", Target: model.DraftLockTargetProject, LockedBy: "alice", ExpiresAt: time.Now().Add(-time.Minute)}).Error)
+
+		lock, err := svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetProject, 0, "bob", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", lock.LockedBy)
+
+		locks, err := svc.GetByProject(ctx, "test-ns", "test-proj")
+		require.NoError(t, err)
+		require.Len(t, locks, 1)
+		assert.Equal(t, "bob", locks[0].LockedBy)
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		_, svc := setupDraftLockServiceTest(t)
+
+		_, err := svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetPageDraft, 1, "alice", 0)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		_, err = svc.Lock(ctx, "test-ns", "other-proj", model.DraftLockTargetRedirectDraft, 1, "alice", 0)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		_, err = svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetProject, 1, "alice", 0)
+		assert.ErrorIs(t, err, ErrDraftLockTarget)
+
+		_, err = svc.Lock(ctx, "test-ns", "test-proj", "OTHER", 1, "alice", 0)
+		assert.ErrorIs(t, err, ErrDraftLockTarget)
+	})
+}
+
+func TestDraftLockService_Unlock(t *testing.T) {
+	ctx := context.Background()
+	_, svc := setupDraftLockServiceTest(t)
+
+	_, err := svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetProject, 0, "alice", 0)
+	require.NoError(t, err)
+
+	_, err = svc.Unlock(ctx, "test-ns", "test-proj", model.DraftLockTargetProject, 0, "bob", false)
+	assert.ErrorIs(t, err, ErrDraftLockNotOwned)
+
+	unlocked, err := svc.Unlock(ctx, "test-ns", "test-proj", model.DraftLockTargetProject, 0, "bob", true)
+	require.NoError(t, err)
+	assert.True(t, unlocked)
+
+	unlocked, err = svc.Unlock(ctx, "test-ns", "test-proj", model.DraftLockTargetProject, 0, "alice", false)
+	require.NoError(t, err)
+	assert.False(t, unlocked)
+}
+
+func TestDraftLockService_Check(t *testing.T) {
+	ctx := context.Background()
+	_, svc := setupDraftLockServiceTest(t)
+
+	_, err := svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 1, "alice", 0)
+	require.NoError(t, err)
+
+	assert.NoError(t, svc.CheckDraft(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 1, "alice"))
+	assert.ErrorIs(t, svc.CheckDraft(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 1, "bob"), ErrDraftLocked)
+	assert.NoError(t, svc.CheckDraft(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 2, "bob"))
+	assert.NoError(t, svc.CheckDraft(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 0, "bob"))
+	assert.NoError(t, svc.CheckProject(ctx, "test-ns", "test-proj", "alice"))
+	assert.ErrorIs(t, svc.CheckProject(ctx, "test-ns", "test-proj", "bob"), ErrDraftLocked)
+
+	_, err = svc.Unlock(ctx, "test-ns", "test-proj", model.DraftLockTargetRedirectDraft, 1, "alice", false)
+	require.NoError(t, err)
+	_, err = svc.Lock(ctx, "test-ns", "test-proj", model.DraftLockTargetProject, 0, "alice", 0)
+	require.NoError(t, err)
+
+	err = svc.CheckDraft(ctx, "test-ns", "test-proj", model.DraftLockTargetPageDraft, 0, "bob")
+	assert.ErrorIs(t, err, ErrDraftLocked)
+	assert.ErrorContains(t, err, "the drafts of the project are locked by alice")
+	assert.NoError(t, svc.CheckDraft(ctx, "test-ns", "test-proj", model.DraftLockTargetPageDraft, 0, "alice"))
+	assert.NoError(t, svc.CheckDraft(ctx, "other-ns", "test-proj", model.DraftLockTargetPageDraft, 0, "bob"))
+}
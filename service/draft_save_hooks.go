@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/extension"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+)
+
+// ErrDraftRefused is returned when a pre-draft-save hook of an extension refuses a draft
+var ErrDraftRefused = flectoErrors.New(flectoErrors.CodeInvalidRequest, "draft refused")
+
+// runPreDraftSaveHooks runs the pre-draft-save hooks of the extensions on the new redirect or page of a draft, which
+// they can change in place. The drafts of the bulk rewrites and of the redirect expiry are saved without running them.
+func runPreDraftSaveHooks(ctx context.Context, appCtx *appContext.Context, namespaceCode, projectCode string, changeType model.DraftChangeType, newRedirect *commonTypes.Redirect, newPage *commonTypes.Page) error {
+	draft := &extension.DraftSave{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		ChangeType:    string(changeType),
+		SavedBy:       types.SubjectFromContext(ctx),
+		Redirect:      newRedirect,
+		Page:          newPage,
+	}
+	if err := appCtx.Extensions.PreDraftSave(ctx, appCtx.CurrentConfig().Extensions, draft); err != nil {
+		appCtx.Logger.WarnContext(ctx, "draft refused", "namespace", namespaceCode, "project", projectCode, "changeType", changeType, "error", err)
+		return fmt.Errorf("%w for project %s/%s: %s", ErrDraftRefused, namespaceCode, projectCode, err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+// GetEffectiveConfig returns the redirects and pages of the project once its drafts published, each marked with the
+// change of its draft. The redirects and pages to delete are kept, marked DELETE, so that the previews can show them.
+// Without the drafts, the published redirects and pages are returned, all marked PUBLISHED.
+func (s *projectService) GetEffectiveConfig(ctx context.Context, namespaceCode, projectCode string, includeDrafts bool) (*model.EffectiveConfig, error) {
+	result := &model.EffectiveConfig{NamespaceCode: namespaceCode, ProjectCode: projectCode, IncludeDrafts: includeDrafts}
+
+	// The published redirects and pages are read in a transaction with the drafts so that they apply to them
+	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		var project model.Project
+		if err := tx.Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).First(&project).Error; err != nil {
+			return err
+		}
+		result.Version = project.Version
+		result.RedirectOptions = project.RedirectOptions
+
+		var redirects []model.Redirect
+		if err := tx.Preload("Tags").
+			Where("namespace_code = ? AND project_code = ? AND is_published = ?", namespaceCode, projectCode, true).
+			Find(&redirects).Error; err != nil {
+			return err
+		}
+		var pages []model.Page
+		if err := tx.Where("namespace_code = ? AND project_code = ? AND is_published = ?", namespaceCode, projectCode, true).
+			Find(&pages).Error; err != nil {
+			return err
+		}
+
+		var redirectDrafts []model.RedirectDraft
+		var pageDrafts []model.PageDraft
+		if includeDrafts {
+			if err := tx.Preload("Tags").Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).Find(&redirectDrafts).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).Find(&pageDrafts).Error; err != nil {
+				return err
+			}
+		}
+
+		result.Redirects = effectiveRedirects(redirects, redirectDrafts)
+		result.Pages = effectivePages(pages, pageDrafts)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// effectiveRedirects applies the drafts to the published redirects, sorted by decreasing priority then by id
+func effectiveRedirects(redirects []model.Redirect, drafts []model.RedirectDraft) []model.EffectiveRedirect {
+	draftsByRedirect := make(map[int64]*model.RedirectDraft, len(drafts))
+	for i := range drafts {
+		if drafts[i].OldRedirectID != nil {
+			draftsByRedirect[*drafts[i].OldRedirectID] = &drafts[i]
+		}
+	}
+
+	result := make([]model.EffectiveRedirect, 0, len(redirects)+len(drafts))
+	for _, redirect := range redirects {
+		effective := model.EffectiveRedirect{ID: redirect.ID, Redirect: redirect.Redirect, Tags: model.TagNames(redirect.Tags), Change: model.DraftChangeTypePublished}
+		if draft, ok := draftsByRedirect[redirect.ID]; ok {
+			delete(draftsByRedirect, redirect.ID)
+			effective.Change = draft.ChangeType
+			effective.DraftID = &draft.ID
+			if draft.ChangeType != model.DraftChangeTypeDelete && draft.NewRedirect != nil {
+				effective.Redirect = draft.NewRedirect
+				effective.Tags = model.TagNames(draft.Tags)
+			}
+		}
+		result = append(result, effective)
+	}
+	// The drafts left create the redirects, staged with an unpublished redirect
+	for _, draft := range drafts {
+		if draft.OldRedirectID == nil || draftsByRedirect[*draft.OldRedirectID] == nil || draft.NewRedirect == nil {
+			continue
+		}
+		result = append(result, model.EffectiveRedirect{
+			ID:       *draft.OldRedirectID,
+			Redirect: draft.NewRedirect,
+			Tags:     model.TagNames(draft.Tags),
+			Change:   model.DraftChangeTypeCreate,
+			DraftID:  &draft.ID,
+		})
+	}
+
+	slices.SortStableFunc(result, func(a, b model.EffectiveRedirect) int {
+		if c := cmp.Compare(b.Redirect.Priority, a.Redirect.Priority); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return result
+}
+
+// effectivePages applies the drafts to the published pages, sorted by id
+func effectivePages(pages []model.Page, drafts []model.PageDraft) []model.EffectivePage {
+	draftsByPage := make(map[int64]*model.PageDraft, len(drafts))
+	for i := range drafts {
+		if drafts[i].OldPageID != nil {
+			draftsByPage[*drafts[i].OldPageID] = &drafts[i]
+		}
+	}
+
+	result := make([]model.EffectivePage, 0, len(pages)+len(drafts))
+	for _, page := range pages {
+		effective := model.EffectivePage{ID: page.ID, Page: page.Page, Change: model.DraftChangeTypePublished}
+		if draft, ok := draftsByPage[page.ID]; ok {
+			delete(draftsByPage, page.ID)
+			effective.Change = draft.ChangeType
+			effective.DraftID = &draft.ID
+			if draft.ChangeType != model.DraftChangeTypeDelete && draft.NewPage != nil {
+				effective.Page = draft.NewPage
+			}
+		}
+		result = append(result, effective)
+	}
+	for _, draft := range drafts {
+		if draft.OldPageID == nil || draftsByPage[*draft.OldPageID] == nil || draft.NewPage == nil {
+			continue
+		}
+		result = append(result, model.EffectivePage{ID: *draft.OldPageID, Page: draft.NewPage, Change: model.DraftChangeTypeCreate, DraftID: &draft.ID})
+	}
+
+	slices.SortStableFunc(result, func(a, b model.EffectivePage) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	return result
+}
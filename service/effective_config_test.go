@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupEffectiveConfigTest(t *testing.T) (*gorm.DB, ProjectService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Tag{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 3}).Error)
+
+	ctx := testContextWithPageConfig(defaultProjectCfg)
+	return db, NewProjectService(ctx, repository.NewProjectRepository(db), repository.NewPageRepository(db), repository.NewRedirectDraftRepository(db), repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+}
+
+func createEffectiveRedirect(t *testing.T, db *gorm.DB, source string, priority int, published bool) *model.Redirect {
+	redirect := &model.Redirect{
+		NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(published),
+		Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent, Priority: priority},
+	}
+	require.NoError(t, db.Create(redirect).Error)
+	return redirect
+}
+
+func TestProjectService_GetEffectiveConfig(t *testing.T) {
+	db, svc := setupEffectiveConfigTest(t)
+
+	keep := createEffectiveRedirect(t, db, "/keep", 0, true)
+	change := createEffectiveRedirect(t, db, "/change", 0, true)
+	remove := createEffectiveRedirect(t, db, "/remove", 0, true)
+	created := createEffectiveRedirect(t, db, "", 0, false)
+	updateDraft := &model.RedirectDraft{
+		NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeUpdate, OldRedirectID: &change.ID,
+		NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/change", Target: "/elsewhere", Status: commonTypes.RedirectStatusFound},
+		Tags:        []model.Tag{{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "summer"}},
+	}
+	deleteDraft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeDelete, OldRedirectID: &remove.ID}
+	createDraft := &model.RedirectDraft{
+		NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &created.ID,
+		NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/new", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent, Priority: 10},
+	}
+	for _, draft := range []*model.RedirectDraft{updateDraft, deleteDraft, createDraft} {
+		require.NoError(t, db.Create(draft).Error)
+	}
+
+	page := &model.Page{
+		NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true),
+		Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "old", ContentType: commonTypes.PageContentTypeTextPlain},
+	}
+	require.NoError(t, db.Create(page).Error)
+	pageDraft := &model.PageDraft{
+		NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeUpdate, OldPageID: &page.ID,
+		NewPage: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "new", ContentType: commonTypes.PageContentTypeTextPlain},
+	}
+	require.NoError(t, db.Create(pageDraft).Error)
+
+	t.Run("with drafts", func(t *testing.T) {
+		config, err := svc.GetEffectiveConfig(context.Background(), "test-ns", "test-proj", true)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, config.Version)
+		assert.True(t, config.IncludeDrafts)
+		require.Len(t, config.Redirects, 4)
+
+		assert.Equal(t, created.ID, config.Redirects[0].ID, "the redirects are sorted by priority")
+		assert.Equal(t, "/new", config.Redirects[0].Redirect.Source)
+		assert.Equal(t, model.DraftChangeTypeCreate, config.Redirects[0].Change)
+		assert.Equal(t, &createDraft.ID, config.Redirects[0].DraftID)
+
+		assert.Equal(t, keep.ID, config.Redirects[1].ID)
+		assert.Equal(t, model.DraftChangeTypePublished, config.Redirects[1].Change)
+		assert.Nil(t, config.Redirects[1].DraftID)
+
+		assert.Equal(t, change.ID, config.Redirects[2].ID)
+		assert.Equal(t, model.DraftChangeTypeUpdate, config.Redirects[2].Change)
+		assert.Equal(t, "/elsewhere", config.Redirects[2].Redirect.Target)
+		assert.Equal(t, []string{"summer"}, config.Redirects[2].Tags)
+
+		assert.Equal(t, remove.ID, config.Redirects[3].ID)
+		assert.Equal(t, model.DraftChangeTypeDelete, config.Redirects[3].Change)
+		assert.Equal(t, "/remove", config.Redirects[3].Redirect.Source)
+
+		require.Len(t, config.Pages, 1)
+		assert.Equal(t, model.DraftChangeTypeUpdate, config.Pages[0].Change)
+		assert.Equal(t, "new", config.Pages[0].Page.Content)
+	})
+
+	t.Run("published only", func(t *testing.T) {
+		config, err := svc.GetEffectiveConfig(context.Background(), "test-ns", "test-proj", false)
+
+		require.NoError(t, err)
+		assert.False(t, config.IncludeDrafts)
+		require.Len(t, config.Redirects, 3)
+		for _, redirect := range config.Redirects {
+			assert.Equal(t, model.DraftChangeTypePublished, redirect.Change)
+			assert.Nil(t, redirect.DraftID)
+		}
+		assert.Equal(t, "/target", config.Redirects[1].Redirect.Target)
+		require.Len(t, config.Pages, 1)
+		assert.Equal(t, "old", config.Pages[0].Page.Content)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		_, err := svc.GetEffectiveConfig(context.Background(), "test-ns", "unknown", true)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/gitrepo"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+const (
+	// gitSyncErrorMaxLength is the size of the last_error column
+	gitSyncErrorMaxLength = 1000
+	// gitSyncSubject is recorded as the author of the changes made by the syncs triggered by a webhook or polling
+	gitSyncSubject = "git-sync"
+)
+
+var ErrGitSyncNoManifest = flectoErrors.New(flectoErrors.CodeNotFound, "no manifest found in the repository path")
+
+// GitSyncService pulls the redirects and pages of projects from manifests stored in Git repositories,
+// staging drafts for the differences with the published project
+type GitSyncService interface {
+	GetByProject(ctx context.Context, namespaceCode, projectCode string) (*model.ProjectGitSync, error)
+	Configure(ctx context.Context, namespaceCode, projectCode string, input model.ProjectGitSync) (*model.ProjectGitSync, error)
+	Delete(ctx context.Context, namespaceCode, projectCode string) (bool, error)
+	Sync(ctx context.Context, namespaceCode, projectCode string, force bool) (*model.GitSyncResult, error)
+	Trigger(namespaceCode, projectCode string)
+	ScheduledTask() types.ScheduledTask
+}
+
+type gitSyncService struct {
+	ctx          *appContext.Context
+	repo         repository.ProjectGitSyncRepository
+	applyService ProjectApplyService
+	fetcher      gitrepo.Fetcher
+
+	mu sync.Mutex
+	// running holds the projects being synced, pending those to sync again once done
+	running map[string]bool
+	pending map[string]bool
+}
+
+func NewGitSyncService(ctx *appContext.Context, repo repository.ProjectGitSyncRepository, applyService ProjectApplyService, fetcher gitrepo.Fetcher) GitSyncService {
+	return &gitSyncService{
+		ctx:          ctx,
+		repo:         repo,
+		applyService: applyService,
+		fetcher:      fetcher,
+		running:      make(map[string]bool),
+		pending:      make(map[string]bool),
+	}
+}
+
+func (s *gitSyncService) GetByProject(ctx context.Context, namespaceCode, projectCode string) (*model.ProjectGitSync, error) {
+	return s.repo.FindByProject(ctx, namespaceCode, projectCode)
+}
+
+// Configure creates or updates the Git sync of a project, a webhook secret being generated on creation.
+// Changing the repository, branch or path makes the next sync apply the manifests even when the commit is unchanged.
+func (s *gitSyncService) Configure(ctx context.Context, namespaceCode, projectCode string, input model.ProjectGitSync) (*model.ProjectGitSync, error) {
+	gitSync, err := s.repo.FindByProject(ctx, namespaceCode, projectCode)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if gitSync == nil {
+		secret, errSecret := generateWebhookSecret()
+		if errSecret != nil {
+			return nil, errSecret
+		}
+		gitSync = &model.ProjectGitSync{NamespaceCode: namespaceCode, ProjectCode: projectCode, WebhookSecret: secret}
+	}
+
+	if gitSync.RepoURL != input.RepoURL || gitSync.Branch != input.Branch || gitSync.Path != input.Path {
+		gitSync.LastCommit = ""
+	}
+	gitSync.RepoURL = input.RepoURL
+	gitSync.Branch = input.Branch
+	gitSync.Path = input.Path
+	gitSync.AutoPublish = input.AutoPublish
+	gitSync.Poll = input.Poll
+	if err = s.ctx.Validator.Struct(gitSync); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Save(ctx, gitSync); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to configure git sync", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "git sync configured", "namespace", namespaceCode, "project", projectCode, "repoURL", gitSync.RepoURL, "branch", gitSync.Branch)
+	return gitSync, nil
+}
+
+func (s *gitSyncService) Delete(ctx context.Context, namespaceCode, projectCode string) (bool, error) {
+	deleted, err := s.repo.Delete(ctx, namespaceCode, projectCode)
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete git sync", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return false, err
+	}
+	if deleted {
+		s.ctx.Logger.InfoContext(ctx, "git sync deleted", "namespace", namespaceCode, "project", projectCode)
+	}
+	return deleted, nil
+}
+
+// Sync applies the manifests of the last commit of the branch to the project drafts, publishing the project
+// with the commit as revision when auto publish is enabled. A commit already synced is skipped unless force is set.
+// The outcome is recorded on the sync, a failed sync keeping the last synced commit.
+func (s *gitSyncService) Sync(ctx context.Context, namespaceCode, projectCode string, force bool) (*model.GitSyncResult, error) {
+	gitSync, err := s.repo.FindByProject(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.sync(ctx, gitSync, force)
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "git sync failed", "namespace", namespaceCode, "project", projectCode, "error", err)
+		gitSync.LastError = truncate(err.Error(), gitSyncErrorMaxLength)
+	} else {
+		if !result.Skipped {
+			s.ctx.Logger.InfoContext(ctx, "git sync completed", "namespace", namespaceCode, "project", projectCode, "commit", result.Commit,
+				"changes", len(result.Apply.Redirects)+len(result.Apply.Pages), "published", result.Apply.Published)
+		}
+		now := time.Now()
+		gitSync.LastCommit = result.Commit
+		gitSync.LastSyncedAt = &now
+		gitSync.LastError = ""
+	}
+
+	if errStatus := s.repo.UpdateStatus(ctx, gitSync); errStatus != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to record git sync status", "namespace", namespaceCode, "project", projectCode, "error", errStatus)
+		if err == nil {
+			err = errStatus
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *gitSyncService) sync(ctx context.Context, gitSync *model.ProjectGitSync, force bool) (*model.GitSyncResult, error) {
+	fetchCtx := ctx
+	if timeout := s.ctx.Config.GitSync.Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		fetchCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// The URLs saved before being checked on configuration are refused here
+	if err := gitrepo.CheckURL(gitSync.RepoURL); err != nil {
+		return nil, err
+	}
+	source := gitrepo.Source{URL: gitSync.RepoURL, Branch: gitSync.Branch, Path: gitSync.Path}
+	commit, err := s.fetcher.Head(fetchCtx, source)
+	if err != nil {
+		return nil, err
+	}
+	if commit == gitSync.LastCommit && !force {
+		return &model.GitSyncResult{Commit: commit, Skipped: true}, nil
+	}
+
+	snapshot, err := s.fetcher.Fetch(fetchCtx, source)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := mergeManifests(snapshot.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	applyResult, err := s.applyService.Apply(ctx, gitSync.NamespaceCode, gitSync.ProjectCode, manifest, types.ApplyProjectOptions{
+		Publish:  gitSync.AutoPublish,
+		Revision: snapshot.Commit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &model.GitSyncResult{Commit: snapshot.Commit, Apply: applyResult}, nil
+}
+
+// Trigger syncs the project in the background. A trigger received while the project is being synced
+// runs a single new sync once the current one is done.
+func (s *gitSyncService) Trigger(namespaceCode, projectCode string) {
+	if !s.acquire(namespaceCode, projectCode, true) {
+		return
+	}
+	go s.run(database.WithNamespace(types.WithSubject(context.Background(), gitSyncSubject), namespaceCode), namespaceCode, projectCode)
+}
+
+// ScheduledTask syncs the projects of all the shards polling their repository, at the configured interval by
+// default. The task is disabled when polling is disabled.
+func (s *gitSyncService) ScheduledTask() types.ScheduledTask {
+	interval := max(s.ctx.Config.GitSync.PollInterval, 0)
+	return types.ScheduledTask{
+		Name:     "git_sync",
+		Interval: interval,
+		Enabled:  interval > 0,
+		Run: func(ctx context.Context, now time.Time) error {
+			var errs []error
+			for _, ctx := range database.ShardContexts(s.repo.GetTx(ctx), types.WithSubject(ctx, gitSyncSubject)) {
+				errs = append(errs, s.poll(ctx))
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// poll syncs one after the other the projects polling their repository, skipping those already being synced
+func (s *gitSyncService) poll(ctx context.Context) error {
+	syncs, err := s.repo.FindPolled(ctx)
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "git sync poll failed", "error", err)
+		return err
+	}
+	for _, gitSync := range syncs {
+		if s.acquire(gitSync.NamespaceCode, gitSync.ProjectCode, false) {
+			s.run(ctx, gitSync.NamespaceCode, gitSync.ProjectCode)
+		}
+	}
+	return nil
+}
+
+// acquire reserves the sync of a project, queueing a new sync when requeue is set and the project is being synced
+func (s *gitSyncService) acquire(namespaceCode, projectCode string, requeue bool) bool {
+	key := namespaceCode + "/" + projectCode
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[key] {
+		if requeue {
+			s.pending[key] = true
+		}
+		return false
+	}
+	s.running[key] = true
+	return true
+}
+
+// run syncs a project reserved by acquire until no new sync is pending, then releases it
+func (s *gitSyncService) run(ctx context.Context, namespaceCode, projectCode string) {
+	key := namespaceCode + "/" + projectCode
+	for {
+		// Errors are logged and recorded on the sync
+		_, _ = s.Sync(ctx, namespaceCode, projectCode, false)
+
+		s.mu.Lock()
+		if !s.pending[key] {
+			delete(s.running, key)
+			s.mu.Unlock()
+			return
+		}
+		delete(s.pending, key)
+		s.mu.Unlock()
+	}
+}
+
+// mergeManifests parses the manifest files and merges their redirects and pages, the entries
+// being checked for duplicates when applied
+func mergeManifests(files []gitrepo.File) (*model.ProjectManifest, error) {
+	if len(files) == 0 {
+		return nil, ErrGitSyncNoManifest
+	}
+	merged := &model.ProjectManifest{}
+	for _, file := range files {
+		manifest, err := ParseProjectManifest(bytes.NewReader(file.Content))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file.Path, err)
+		}
+		merged.Redirects = append(merged.Redirects, manifest.Redirects...)
+		merged.Pages = append(merged.Pages, manifest.Pages...)
+	}
+	return merged, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	return value[:maxLength]
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/gitrepo"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// fakeFetcher serves a snapshot of a repository, counting the fetches
+type fakeFetcher struct {
+	snapshot gitrepo.Snapshot
+	err      error
+	fetches  int
+}
+
+func (f *fakeFetcher) Head(_ context.Context, _ gitrepo.Source) (string, error) {
+	return f.snapshot.Commit, f.err
+}
+
+func (f *fakeFetcher) Fetch(_ context.Context, _ gitrepo.Source) (*gitrepo.Snapshot, error) {
+	f.fetches++
+	if f.err != nil {
+		return nil, f.err
+	}
+	snapshot := f.snapshot
+	return &snapshot, nil
+}
+
+func setupGitSyncServiceTest(t *testing.T) (*gorm.DB, *fakeFetcher, GitSyncService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Tag{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{}, &model.ProjectGitSync{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 1}).Error)
+
+	ctx := testContextWithPageConfig(defaultProjectCfg)
+	redirectDraftRepo := repository.NewRedirectDraftRepository(db)
+	projectSrv := NewProjectService(ctx, repository.NewProjectRepository(db), repository.NewPageRepository(db), redirectDraftRepo, repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+	fetcher := &fakeFetcher{snapshot: gitrepo.Snapshot{
+		Commit: "c1",
+		Files:  []gitrepo.File{{Path: "flecto/manifest.yaml", Content: []byte(testProjectManifest)}},
+	}}
+	return db, fetcher, NewGitSyncService(ctx, repository.NewProjectGitSyncRepository(db), NewProjectApplyService(ctx, redirectDraftRepo, projectSrv, nil), fetcher)
+}
+
+func TestGitSyncService_Configure(t *testing.T) {
+	ctx := context.Background()
+	_, _, svc := setupGitSyncServiceTest(t)
+
+	_, err := svc.Configure(ctx, "test-ns", "test-proj", model.ProjectGitSync{RepoURL: "not a url", Branch: "main"})
+	assert.Error(t, err)
+	_, err = svc.Configure(ctx, "test-ns", "test-proj", model.ProjectGitSync{RepoURL: "file:///srv/redirects.git", Branch: "main"})
+	assert.Error(t, err)
+
+	created, err := svc.Configure(ctx, "test-ns", "test-proj", model.ProjectGitSync{RepoURL: "https://git.example.com/redirects.git", Branch: "main", Path: "flecto"})
+	require.NoError(t, err)
+	assert.NotZero(t, created.ID)
+	assert.Len(t, created.WebhookSecret, 64)
+
+	_, err = svc.Sync(ctx, "test-ns", "test-proj", false)
+	require.NoError(t, err)
+
+	// The secret is kept, the last commit is forgotten when the source changes
+	updated, err := svc.Configure(ctx, "test-ns", "test-proj", model.ProjectGitSync{RepoURL: "https://git.example.com/redirects.git", Branch: "main", Path: "flecto", Poll: true})
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, updated.ID)
+	assert.Equal(t, created.WebhookSecret, updated.WebhookSecret)
+	assert.Equal(t, "c1", updated.LastCommit)
+	assert.True(t, updated.Poll)
+
+	updated, err = svc.Configure(ctx, "test-ns", "test-proj", model.ProjectGitSync{RepoURL: "https://git.example.com/redirects.git", Branch: "release"})
+	require.NoError(t, err)
+	assert.Empty(t, updated.LastCommit)
+
+	deleted, err := svc.Delete(ctx, "test-ns", "test-proj")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	_, err = svc.GetByProject(ctx, "test-ns", "test-proj")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestGitSyncService_Sync(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not configured", func(t *testing.T) {
+		_, _, svc := setupGitSyncServiceTest(t)
+		_, err := svc.Sync(ctx, "test-ns", "test-proj", false)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("drafts then skips the same commit", func(t *testing.T) {
+		db, fetcher, svc := setupGitSyncServiceTest(t)
+		seedProjectApplyTest(t, db)
+		_, err := svc.Configure(ctx, "test-ns", "test-proj", model.ProjectGitSync{RepoURL: "https://git.example.com/redirects.git", Branch: "main"})
+		require.NoError(t, err)
+
+		result, err := svc.Sync(ctx, "test-ns", "test-proj", false)
+		require.NoError(t, err)
+		assert.Equal(t, "c1", result.Commit)
+		assert.False(t, result.Skipped)
+		assert.Len(t, result.Apply.Redirects, 3)
+		assert.False(t, result.Apply.Published)
+
+		gitSync, err := svc.GetByProject(ctx, "test-ns", "test-proj")
+		require.NoError(t, err)
+		assert.Equal(t, "c1", gitSync.LastCommit)
+		assert.NotNil(t, gitSync.LastSyncedAt)
+
+		result, err = svc.Sync(ctx, "test-ns", "test-proj", false)
+		require.NoError(t, err)
+		assert.True(t, result.Skipped)
+		assert.Equal(t, 1, fetcher.fetches)
+
+		result, err = svc.Sync(ctx, "test-ns", "test-proj", true)
+		require.NoError(t, err)
+		assert.False(t, result.Skipped)
+		assert.Equal(t, 2, fetcher.fetches)
+	})
+
+	t.Run("auto publish records the revision", func(t *testing.T) {
+		db, _, svc := setupGitSyncServiceTest(t)
+		seedProjectApplyTest(t, db)
+		_, err := svc.Configure(ctx, "test-ns", "test-proj", model.ProjectGitSync{RepoURL: "https://git.example.com/redirects.git", Branch: "main", AutoPublish: true})
+		require.NoError(t, err)
+
+		result, err := svc.Sync(types.WithSubject(ctx, "alice"), "test-ns", "test-proj", false)
+		require.NoError(t, err)
+		assert.True(t, result.Apply.Published)
+
+		var project model.Project
+		require.NoError(t, db.First(&project).Error)
+		assert.Equal(t, 2, project.Version)
+		assert.Equal(t, "c1", project.Revision)
+		assert.Equal(t, "alice", project.PublishedBy)
+	})
+
+	t.Run("records the error", func(t *testing.T) {
+		_, fetcher, svc := setupGitSyncServiceTest(t)
+		_, err := svc.Configure(ctx, "test-ns", "test-proj", model.ProjectGitSync{RepoURL: "https://git.example.com/redirects.git", Branch: "main"})
+		require.NoError(t, err)
+		_, err = svc.Sync(ctx, "test-ns", "test-proj", false)
+		require.NoError(t, err)
+
+		fetcher.snapshot = gitrepo.Snapshot{Commit: "c2", Files: []gitrepo.File{{Path: "manifest.yaml", Content: []byte("redirect: []")}}}
+		_, err = svc.Sync(ctx, "test-ns", "test-proj", false)
+		assert.ErrorIs(t, err, ErrInvalidManifest)
+		assert.ErrorContains(t, err, "manifest.yaml: ")
+
+		fetcher.snapshot = gitrepo.Snapshot{Commit: "c3"}
+		_, err = svc.Sync(ctx, "test-ns", "test-proj", false)
+		assert.ErrorIs(t, err, ErrGitSyncNoManifest)
+
+		fetcher.err = errors.New("repository not found")
+		_, err = svc.Sync(ctx, "test-ns", "test-proj", false)
+		assert.EqualError(t, err, "repository not found")
+
+		gitSync, err := svc.GetByProject(ctx, "test-ns", "test-proj")
+		require.NoError(t, err)
+		assert.Equal(t, "c1", gitSync.LastCommit)
+		assert.Equal(t, "repository not found", gitSync.LastError)
+	})
+
+	t.Run("refuses a repository URL saved before the check", func(t *testing.T) {
+		db, fetcher, svc := setupGitSyncServiceTest(t)
+		require.NoError(t, db.Create(&model.ProjectGitSync{NamespaceCode: "test-ns", ProjectCode: "test-proj", RepoURL: "file:///srv/redirects.git", Branch: "main"}).Error)
+
+		_, err := svc.Sync(ctx, "test-ns", "test-proj", false)
+		assert.ErrorIs(t, err, gitrepo.ErrUnsupportedURL)
+		assert.Zero(t, fetcher.fetches)
+	})
+}
+
+func TestGitSyncService_Trigger(t *testing.T) {
+	ctx := context.Background()
+	db, _, svc := setupGitSyncServiceTest(t)
+	_, err := svc.Configure(ctx, "test-ns", "test-proj", model.ProjectGitSync{RepoURL: "https://git.example.com/redirects.git", Branch: "main"})
+	require.NoError(t, err)
+
+	svc.Trigger("test-ns", "test-proj")
+	assert.Eventually(t, func() bool {
+		gitSync, errGet := svc.GetByProject(ctx, "test-ns", "test-proj")
+		return errGet == nil && gitSync.LastCommit == "c1"
+	}, time.Second, 10*time.Millisecond)
+
+	var drafts []model.RedirectDraft
+	require.NoError(t, db.Find(&drafts).Error)
+	require.NotEmpty(t, drafts)
+	for _, draft := range drafts {
+		assert.Equal(t, gitSyncSubject, draft.CreatedBy)
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGroupNotFound      = flectoErrors.New(flectoErrors.CodeNotFound, "group not found")
+	ErrGroupAlreadyExists = flectoErrors.New(flectoErrors.CodeAlreadyExists, "group already exists")
+)
+
+// GroupService manages the groups of users, the roles assigned to a group being granted to all its users
+type GroupService interface {
+	Create(ctx context.Context, input *model.Group) (*model.Group, error)
+	// Update changes the name and the description of a group
+	Update(ctx context.Context, id int64, input model.Group) (*model.Group, error)
+	Delete(ctx context.Context, id int64) (bool, error)
+	GetByCode(ctx context.Context, code string) (*model.Group, error)
+	GetAll(ctx context.Context) ([]model.Group, error)
+
+	GetGroupUsers(ctx context.Context, groupID int64) ([]model.User, error)
+	GetGroupRoles(ctx context.Context, groupID int64) ([]model.Role, error)
+	GetUserGroups(ctx context.Context, userID int64) ([]model.Group, error)
+	// UpdateGroupUsers replaces the users of a group
+	UpdateGroupUsers(ctx context.Context, groupID int64, usernames []string) error
+	// UpdateGroupRoles replaces the roles assigned to a group, only named roles can be assigned
+	UpdateGroupRoles(ctx context.Context, groupID int64, roleCodes []string) error
+}
+
+type groupService struct {
+	ctx      *appContext.Context
+	repo     repository.GroupRepository
+	roleRepo repository.RoleRepository
+	userRepo repository.UserRepository
+	bus      invalidation.Bus
+}
+
+func NewGroupService(
+	ctx *appContext.Context,
+	repo repository.GroupRepository,
+	roleRepo repository.RoleRepository,
+	userRepo repository.UserRepository,
+	bus invalidation.Bus,
+) GroupService {
+	return &groupService{
+		ctx:      ctx,
+		repo:     repo,
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+		bus:      bus,
+	}
+}
+
+func (s *groupService) Create(ctx context.Context, input *model.Group) (*model.Group, error) {
+	if err := s.ctx.Validator.Struct(input); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByCode(ctx, input.Code)
+	if err == nil && existing != nil {
+		return nil, ErrGroupAlreadyExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err = s.repo.Create(ctx, input); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to create group", "groupCode", input.Code, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "group created", "groupCode", input.Code, "groupID", input.ID)
+	return input, nil
+}
+
+func (s *groupService) Update(ctx context.Context, id int64, input model.Group) (*model.Group, error) {
+	group, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	group.Name = input.Name
+	group.Description = input.Description
+	if err = s.ctx.Validator.Struct(group); err != nil {
+		return nil, err
+	}
+	if err = s.repo.Update(ctx, group); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to update group", "groupCode", group.Code, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "group updated", "groupCode", group.Code, "groupID", group.ID)
+	return group, nil
+}
+
+func (s *groupService) Delete(ctx context.Context, id int64) (bool, error) {
+	group, err := s.findByID(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	if err = s.repo.Delete(ctx, id); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete group", "groupCode", group.Code, "error", err)
+		return false, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "group deleted", "groupCode", group.Code, "groupID", id)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
+	return true, nil
+}
+
+func (s *groupService) GetByCode(ctx context.Context, code string) (*model.Group, error) {
+	group, err := s.repo.FindByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+func (s *groupService) GetAll(ctx context.Context) ([]model.Group, error) {
+	return s.repo.FindAll(ctx)
+}
+
+func (s *groupService) GetGroupUsers(ctx context.Context, groupID int64) ([]model.User, error) {
+	return s.repo.GetGroupUsers(ctx, groupID)
+}
+
+func (s *groupService) GetGroupRoles(ctx context.Context, groupID int64) ([]model.Role, error) {
+	return s.repo.GetGroupRoles(ctx, groupID)
+}
+
+func (s *groupService) GetUserGroups(ctx context.Context, userID int64) ([]model.Group, error) {
+	return s.repo.GetUserGroups(ctx, userID)
+}
+
+func (s *groupService) UpdateGroupUsers(ctx context.Context, groupID int64, usernames []string) error {
+	group, err := s.findByID(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	userIDs := make([]int64, 0, len(usernames))
+	seen := make(map[int64]struct{}, len(usernames))
+	for _, username := range usernames {
+		user, err := s.userRepo.FindByUsername(ctx, username)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+		if _, ok := seen[user.ID]; ok {
+			continue
+		}
+		seen[user.ID] = struct{}{}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	if err = s.repo.SetGroupUsers(ctx, groupID, userIDs); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to update group users", "groupCode", group.Code, "groupID", groupID, "error", err)
+		return err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "group users updated", "groupCode", group.Code, "groupID", groupID, "users", usernames)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
+	return nil
+}
+
+func (s *groupService) UpdateGroupRoles(ctx context.Context, groupID int64, roleCodes []string) error {
+	group, err := s.findByID(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	roleIDs := make([]int64, 0, len(roleCodes))
+	seen := make(map[int64]struct{}, len(roleCodes))
+	for _, code := range roleCodes {
+		role, err := s.roleRepo.FindByCodeAndType(ctx, code, model.RoleTypeRole)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+		if _, ok := seen[role.ID]; ok {
+			continue
+		}
+		seen[role.ID] = struct{}{}
+		roleIDs = append(roleIDs, role.ID)
+	}
+
+	if err = s.repo.SetGroupRoles(ctx, groupID, roleIDs); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to update group roles", "groupCode", group.Code, "groupID", groupID, "error", err)
+		return err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "group roles updated", "groupCode", group.Code, "groupID", groupID, "roles", roleCodes)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
+	return nil
+}
+
+func (s *groupService) findByID(ctx context.Context, id int64) (*model.Group, error) {
+	group, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, err
+	}
+	return group, nil
+}
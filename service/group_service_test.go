@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupGroupServiceTest(t *testing.T) (*gorm.DB, GroupService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.User{}, &model.Role{}, &model.ResourcePermission{}, &model.AdminPermission{},
+		&model.Group{}, &model.UserGroup{}, &model.GroupRole{}))
+
+	for _, username := range []string{"alice", "bob"} {
+		require.NoError(t, db.Create(&model.User{Username: username, Firstname: username, Lastname: username}).Error)
+	}
+	require.NoError(t, db.Create(&model.Role{Code: "editor", Type: model.RoleTypeRole}).Error)
+	require.NoError(t, db.Create(&model.Role{Code: "alice", Type: model.RoleTypeUser}).Error)
+
+	ctx := testContextWithPageConfig(defaultProjectCfg)
+	return db, NewGroupService(ctx, repository.NewGroupRepository(db), repository.NewRoleRepository(db),
+		repository.NewUserRepository(db), invalidation.NewMemoryBus())
+}
+
+func TestGroupService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+
+		group, err := svc.Create(ctx, &model.Group{Code: "support", Name: "Support"})
+		require.NoError(t, err)
+		assert.NotZero(t, group.ID)
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+
+		_, err := svc.Create(ctx, &model.Group{Code: "support", Name: "Support"})
+		require.NoError(t, err)
+		_, err = svc.Create(ctx, &model.Group{Code: "support", Name: "Other"})
+		assert.ErrorIs(t, err, ErrGroupAlreadyExists)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+
+		_, err := svc.Create(ctx, &model.Group{Code: "support"})
+		assert.Error(t, err)
+	})
+}
+
+func TestGroupService_Update(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+		group, err := svc.Create(ctx, &model.Group{Code: "support", Name: "Support"})
+		require.NoError(t, err)
+
+		updated, err := svc.Update(ctx, group.ID, model.Group{Code: "ignored", Name: "Support team", Description: "First line"})
+		require.NoError(t, err)
+		assert.Equal(t, "support", updated.Code)
+		assert.Equal(t, "Support team", updated.Name)
+		assert.Equal(t, "First line", updated.Description)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+
+		_, err := svc.Update(ctx, 42, model.Group{Name: "Support"})
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+	})
+}
+
+func TestGroupService_Delete(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+		group, err := svc.Create(ctx, &model.Group{Code: "support", Name: "Support"})
+		require.NoError(t, err)
+
+		deleted, err := svc.Delete(ctx, group.ID)
+		require.NoError(t, err)
+		assert.True(t, deleted)
+
+		_, err = svc.GetByCode(ctx, "support")
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+
+		_, err := svc.Delete(ctx, 42)
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+	})
+}
+
+func TestGroupService_UpdateGroupUsers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+		group, err := svc.Create(ctx, &model.Group{Code: "support", Name: "Support"})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.UpdateGroupUsers(ctx, group.ID, []string{"bob", "alice", "bob"}))
+
+		users, err := svc.GetGroupUsers(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, users, 2)
+		assert.Equal(t, "alice", users[0].Username)
+		assert.Equal(t, "bob", users[1].Username)
+
+		groups, err := svc.GetUserGroups(ctx, users[0].ID)
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		assert.Equal(t, "support", groups[0].Code)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+		group, err := svc.Create(ctx, &model.Group{Code: "support", Name: "Support"})
+		require.NoError(t, err)
+
+		err = svc.UpdateGroupUsers(ctx, group.ID, []string{"unknown"})
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("group not found", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+
+		err := svc.UpdateGroupUsers(ctx, 42, []string{"alice"})
+		assert.ErrorIs(t, err, ErrGroupNotFound)
+	})
+}
+
+func TestGroupService_UpdateGroupRoles(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+		group, err := svc.Create(ctx, &model.Group{Code: "support", Name: "Support"})
+		require.NoError(t, err)
+
+		require.NoError(t, svc.UpdateGroupRoles(ctx, group.ID, []string{"editor"}))
+
+		roles, err := svc.GetGroupRoles(ctx, group.ID)
+		require.NoError(t, err)
+		require.Len(t, roles, 1)
+		assert.Equal(t, "editor", roles[0].Code)
+	})
+
+	t.Run("personal role refused", func(t *testing.T) {
+		_, svc := setupGroupServiceTest(t)
+		group, err := svc.Create(ctx, &model.Group{Code: "support", Name: "Support"})
+		require.NoError(t, err)
+
+		err = svc.UpdateGroupRoles(ctx, group.ID, []string{"alice"})
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+	})
+}
+
+func TestGroupService_GetAll(t *testing.T) {
+	ctx := context.Background()
+	_, svc := setupGroupServiceTest(t)
+	_, err := svc.Create(ctx, &model.Group{Code: "support", Name: "Support"})
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, &model.Group{Code: "admins", Name: "Admins"})
+	require.NoError(t, err)
+
+	groups, err := svc.GetAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "admins", groups[0].Code)
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+)
+
+const (
+	// MaxHitBatchSize is the maximum number of hits an agent can send at once
+	MaxHitBatchSize = 10000
+	// MaxHitPeriodDays is the longest period the hit reports can cover
+	MaxHitPeriodDays = 366
+	// MaxTopHits is the maximum number of items of the top hit reports
+	MaxTopHits = 100
+)
+
+var ErrInvalidHitPeriod = flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "hit period must be between 1 and %d days", MaxHitPeriodDays)
+
+type HitService interface {
+	Ingest(ctx context.Context, namespaceCode, projectCode string, hits []commonTypes.Hit) error
+	TopRedirects(ctx context.Context, namespaceCode, projectCode string, days, limit int) ([]model.RedirectHitCount, error)
+	TopPages(ctx context.Context, namespaceCode, projectCode string, days, limit int) ([]model.PageHitCount, error)
+	UnusedRedirects(ctx context.Context, namespaceCode, projectCode string, days int, pagination *commonTypes.PaginationInput) (*model.RedirectList, error)
+	UnusedPages(ctx context.Context, namespaceCode, projectCode string, days int, pagination *commonTypes.PaginationInput) (*model.PageList, error)
+}
+
+type hitService struct {
+	ctx  *appContext.Context
+	repo repository.HitRepository
+}
+
+func NewHitService(ctx *appContext.Context, repo repository.HitRepository) HitService {
+	return &hitService{
+		ctx:  ctx,
+		repo: repo,
+	}
+}
+
+type hitKey struct {
+	id  int64
+	day time.Time
+}
+
+// Ingest adds the hits reported by an agent to the daily counters, the hits of a same redirect or page
+// and day being summed. Hits of redirects and pages not belonging to the project are ignored, they
+// can be sent by an agent still serving a deleted redirect.
+func (s *hitService) Ingest(ctx context.Context, namespaceCode, projectCode string, hits []commonTypes.Hit) error {
+	now := time.Now()
+	redirectHits := make(map[hitKey]int64)
+	pageHits := make(map[hitKey]int64)
+	for _, hit := range hits {
+		if hit.Count == 0 {
+			continue
+		}
+		day, err := hit.Day(now)
+		if err != nil {
+			return err
+		}
+		switch hit.Type {
+		case commonTypes.HitResourceTypeRedirect:
+			redirectHits[hitKey{id: hit.ID, day: day}] += hit.Count
+		case commonTypes.HitResourceTypePage:
+			pageHits[hitKey{id: hit.ID, day: day}] += hit.Count
+		}
+	}
+
+	redirectIDs, err := s.repo.FilterRedirectIDs(ctx, namespaceCode, projectCode, hitKeyIDs(redirectHits))
+	if err != nil {
+		return err
+	}
+	pageIDs, err := s.repo.FilterPageIDs(ctx, namespaceCode, projectCode, hitKeyIDs(pageHits))
+	if err != nil {
+		return err
+	}
+
+	knownRedirects := toIDSet(redirectIDs)
+	newRedirectHits := make([]model.RedirectHit, 0, len(redirectHits))
+	for key, count := range redirectHits {
+		if knownRedirects[key.id] {
+			newRedirectHits = append(newRedirectHits, model.RedirectHit{RedirectID: key.id, Day: key.day, Hits: count})
+		}
+	}
+	knownPages := toIDSet(pageIDs)
+	newPageHits := make([]model.PageHit, 0, len(pageHits))
+	for key, count := range pageHits {
+		if knownPages[key.id] {
+			newPageHits = append(newPageHits, model.PageHit{PageID: key.id, Day: key.day, Hits: count})
+		}
+	}
+
+	if err = s.repo.AddRedirectHits(ctx, newRedirectHits); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to store redirect hits", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return err
+	}
+	if err = s.repo.AddPageHits(ctx, newPageHits); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to store page hits", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (s *hitService) TopRedirects(ctx context.Context, namespaceCode, projectCode string, days, limit int) ([]model.RedirectHitCount, error) {
+	since, err := hitsSince(days)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.TopRedirects(ctx, namespaceCode, projectCode, since, topHitsLimit(limit))
+}
+
+func (s *hitService) TopPages(ctx context.Context, namespaceCode, projectCode string, days, limit int) ([]model.PageHitCount, error) {
+	since, err := hitsSince(days)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.TopPages(ctx, namespaceCode, projectCode, since, topHitsLimit(limit))
+}
+
+func (s *hitService) UnusedRedirects(ctx context.Context, namespaceCode, projectCode string, days int, pagination *commonTypes.PaginationInput) (*model.RedirectList, error) {
+	since, err := hitsSince(days)
+	if err != nil {
+		return nil, err
+	}
+	redirects, total, err := s.repo.UnusedRedirects(ctx, namespaceCode, projectCode, since, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		return nil, err
+	}
+	return &model.RedirectList{
+		Total:  int(total),
+		Offset: pagination.GetOffset(),
+		Limit:  pagination.GetLimit(),
+		Items:  redirects,
+	}, nil
+}
+
+func (s *hitService) UnusedPages(ctx context.Context, namespaceCode, projectCode string, days int, pagination *commonTypes.PaginationInput) (*model.PageList, error) {
+	since, err := hitsSince(days)
+	if err != nil {
+		return nil, err
+	}
+	pages, total, err := s.repo.UnusedPages(ctx, namespaceCode, projectCode, since, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		return nil, err
+	}
+	return &model.PageList{
+		Total:  int(total),
+		Offset: pagination.GetOffset(),
+		Limit:  pagination.GetLimit(),
+		Items:  pages,
+	}, nil
+}
+
+// hitsSince returns the first day of a period of days ending today
+func hitsSince(days int) (time.Time, error) {
+	if days < 1 || days > MaxHitPeriodDays {
+		return time.Time{}, ErrInvalidHitPeriod
+	}
+	today, _ := commonTypes.Hit{}.Day(time.Now())
+	return today.AddDate(0, 0, 1-days), nil
+}
+
+func topHitsLimit(limit int) int {
+	if limit <= 0 || limit > MaxTopHits {
+		return MaxTopHits
+	}
+	return limit
+}
+
+func hitKeyIDs(hits map[hitKey]int64) []int64 {
+	seen := make(map[int64]bool, len(hits))
+	ids := make([]int64, 0, len(hits))
+	for key := range hits {
+		if !seen[key.id] {
+			seen[key.id] = true
+			ids = append(ids, key.id)
+		}
+	}
+	return ids
+}
+
+func toIDSet(ids []int64) map[int64]bool {
+	set := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	flectoTypes "github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupHitServiceTest(t *testing.T) (*gorm.DB, HitService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.Page{}, &model.RedirectHit{}, &model.PageHit{})
+	require.NoError(t, err)
+	svc := NewHitService(appContext.TestContext(nil), repository.NewHitRepository(db))
+	return db, svc
+}
+
+func createHitTestRedirect(t *testing.T, db *gorm.DB, projectCode, source string, createdAt time.Time) *model.Redirect {
+	redirect := &model.Redirect{
+		NamespaceCode: "test-ns",
+		ProjectCode:   projectCode,
+		IsPublished:   flectoTypes.Ptr(true),
+		Redirect:      &types.Redirect{Type: types.RedirectTypeBasic, Source: source, Target: "https://example.com" + source, Status: types.RedirectStatusMovedPermanent},
+		CreatedAt:     createdAt,
+	}
+	require.NoError(t, db.Create(redirect).Error)
+	return redirect
+}
+
+func createHitTestPage(t *testing.T, db *gorm.DB, path string, createdAt time.Time) *model.Page {
+	page := &model.Page{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		IsPublished:   flectoTypes.Ptr(true),
+		Page:          &types.Page{Type: types.PageTypeBasic, Path: path, Content: "content", ContentType: types.PageContentTypeTextPlain},
+		CreatedAt:     createdAt,
+	}
+	require.NoError(t, db.Create(page).Error)
+	return page
+}
+
+func TestNewHitService(t *testing.T) {
+	_, svc := setupHitServiceTest(t)
+
+	assert.NotNil(t, svc)
+}
+
+func TestHitService_Ingest(t *testing.T) {
+	db, svc := setupHitServiceTest(t)
+	ctx := context.Background()
+	redirect := createHitTestRedirect(t, db, "test-proj", "/a", time.Now())
+	otherRedirect := createHitTestRedirect(t, db, "other-proj", "/b", time.Now())
+	page := createHitTestPage(t, db, "/robots.txt", time.Now())
+
+	err := svc.Ingest(ctx, "test-ns", "test-proj", []types.Hit{
+		{Type: types.HitResourceTypeRedirect, ID: redirect.ID, Count: 3, Date: "2026-03-01"},
+		{Type: types.HitResourceTypeRedirect, ID: redirect.ID, Count: 2, Date: "2026-03-01"},
+		{Type: types.HitResourceTypeRedirect, ID: redirect.ID, Count: 1, Date: "2026-03-02"},
+		{Type: types.HitResourceTypeRedirect, ID: redirect.ID, Count: 0, Date: "2026-03-03"},
+		{Type: types.HitResourceTypeRedirect, ID: otherRedirect.ID, Count: 8, Date: "2026-03-01"},
+		{Type: types.HitResourceTypeRedirect, ID: 999, Count: 8, Date: "2026-03-01"},
+		{Type: types.HitResourceTypePage, ID: page.ID, Count: 4},
+	})
+	require.NoError(t, err)
+
+	var redirectHits []model.RedirectHit
+	require.NoError(t, db.Order("day").Find(&redirectHits).Error)
+	require.Len(t, redirectHits, 2)
+	assert.Equal(t, redirect.ID, redirectHits[0].RedirectID)
+	assert.Equal(t, int64(5), redirectHits[0].Hits)
+	assert.Equal(t, int64(1), redirectHits[1].Hits)
+
+	var pageHits []model.PageHit
+	require.NoError(t, db.Find(&pageHits).Error)
+	require.Len(t, pageHits, 1)
+	assert.Equal(t, int64(4), pageHits[0].Hits)
+	today, _ := types.Hit{}.Day(time.Now())
+	assert.True(t, today.Equal(pageHits[0].Day.UTC()))
+
+	t.Run("error on invalid date", func(t *testing.T) {
+		err := svc.Ingest(ctx, "test-ns", "test-proj", []types.Hit{{Type: types.HitResourceTypeRedirect, ID: redirect.ID, Count: 1, Date: "yesterday"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestHitService_Reports(t *testing.T) {
+	db, svc := setupHitServiceTest(t)
+	ctx := context.Background()
+	old := time.Now().AddDate(0, -3, 0)
+	today, _ := types.Hit{}.Day(time.Now())
+
+	used := createHitTestRedirect(t, db, "test-proj", "/used", old)
+	unused := createHitTestRedirect(t, db, "test-proj", "/unused", old)
+	createHitTestRedirect(t, db, "test-proj", "/new", time.Now())
+	usedPage := createHitTestPage(t, db, "/used.txt", old)
+	unusedPage := createHitTestPage(t, db, "/unused.txt", old)
+	require.NoError(t, db.Create(&[]model.RedirectHit{
+		{RedirectID: used.ID, Day: today, Hits: 4},
+		{RedirectID: unused.ID, Day: today.AddDate(0, 0, -40), Hits: 10},
+	}).Error)
+	require.NoError(t, db.Create(&model.PageHit{PageID: usedPage.ID, Day: today.AddDate(0, 0, -1), Hits: 2}).Error)
+
+	t.Run("top redirects", func(t *testing.T) {
+		counts, err := svc.TopRedirects(ctx, "test-ns", "test-proj", 30, 10)
+		require.NoError(t, err)
+		require.Len(t, counts, 1)
+		assert.Equal(t, used.ID, counts[0].Redirect.ID)
+		assert.Equal(t, int64(4), counts[0].Hits)
+
+		counts, err = svc.TopRedirects(ctx, "test-ns", "test-proj", 60, 0)
+		require.NoError(t, err)
+		assert.Len(t, counts, 2)
+	})
+
+	t.Run("top pages", func(t *testing.T) {
+		counts, err := svc.TopPages(ctx, "test-ns", "test-proj", 1, 10)
+		require.NoError(t, err)
+		assert.Empty(t, counts)
+
+		counts, err = svc.TopPages(ctx, "test-ns", "test-proj", 2, 10)
+		require.NoError(t, err)
+		require.Len(t, counts, 1)
+		assert.Equal(t, usedPage.ID, counts[0].Page.ID)
+	})
+
+	t.Run("unused redirects", func(t *testing.T) {
+		list, err := svc.UnusedRedirects(ctx, "test-ns", "test-proj", 30, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, list.Total)
+		assert.Equal(t, types.DefaultLimit, list.Limit)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, unused.ID, list.Items[0].ID)
+	})
+
+	t.Run("unused pages", func(t *testing.T) {
+		list, err := svc.UnusedPages(ctx, "test-ns", "test-proj", 30, &types.PaginationInput{Limit: flectoTypes.Ptr(5)})
+		require.NoError(t, err)
+		assert.Equal(t, 1, list.Total)
+		assert.Equal(t, 5, list.Limit)
+		require.Len(t, list.Items, 1)
+		assert.Equal(t, unusedPage.ID, list.Items[0].ID)
+	})
+
+	t.Run("error on invalid period", func(t *testing.T) {
+		_, err := svc.TopRedirects(ctx, "test-ns", "test-proj", 0, 10)
+		assert.ErrorIs(t, err, ErrInvalidHitPeriod)
+		_, err = svc.TopPages(ctx, "test-ns", "test-proj", MaxHitPeriodDays+1, 10)
+		assert.ErrorIs(t, err, ErrInvalidHitPeriod)
+		_, err = svc.UnusedRedirects(ctx, "test-ns", "test-proj", -1, nil)
+		assert.ErrorIs(t, err, ErrInvalidHitPeriod)
+		_, err = svc.UnusedPages(ctx, "test-ns", "test-proj", 0, nil)
+		assert.ErrorIs(t, err, ErrInvalidHitPeriod)
+	})
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrImportProfileNotFound      = flectoErrors.New(flectoErrors.CodeNotFound, "import profile not found")
+	ErrImportProfileAlreadyExists = flectoErrors.New(flectoErrors.CodeAlreadyExists, "import profile already exists")
+)
+
+// ImportProfileService manages the saved redirect import options of the projects
+type ImportProfileService interface {
+	GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ImportProfile, error)
+	GetByID(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.ImportProfile, error)
+	Create(ctx context.Context, namespaceCode, projectCode string, input *model.ImportProfile) (*model.ImportProfile, error)
+	Update(ctx context.Context, namespaceCode, projectCode string, id int64, input model.ImportProfile) (*model.ImportProfile, error)
+	Delete(ctx context.Context, namespaceCode, projectCode string, id int64) (bool, error)
+}
+
+type importProfileService struct {
+	ctx         *appContext.Context
+	repo        repository.ImportProfileRepository
+	projectRepo repository.ProjectRepository
+}
+
+func NewImportProfileService(ctx *appContext.Context, repo repository.ImportProfileRepository, projectRepo repository.ProjectRepository) ImportProfileService {
+	return &importProfileService{
+		ctx:         ctx,
+		repo:        repo,
+		projectRepo: projectRepo,
+	}
+}
+
+func (s *importProfileService) GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ImportProfile, error) {
+	return s.repo.FindByProject(ctx, namespaceCode, projectCode)
+}
+
+func (s *importProfileService) GetByID(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.ImportProfile, error) {
+	profile, err := s.repo.FindByID(ctx, namespaceCode, projectCode, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: import profile %d of project %s/%s", ErrImportProfileNotFound, id, namespaceCode, projectCode)
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (s *importProfileService) Create(ctx context.Context, namespaceCode, projectCode string, input *model.ImportProfile) (*model.ImportProfile, error) {
+	if _, err := s.projectRepo.FindByCode(ctx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+
+	input.ID = 0
+	input.NamespaceCode = namespaceCode
+	input.ProjectCode = projectCode
+	input.CreatedBy = types.SubjectFromContext(ctx)
+	if err := s.validate(ctx, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, input); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to create import profile", "namespace", namespaceCode, "project", projectCode, "name", input.Name, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "import profile created", "namespace", namespaceCode, "project", projectCode, "profile", input.ID, "name", input.Name)
+	return input, nil
+}
+
+func (s *importProfileService) Update(ctx context.Context, namespaceCode, projectCode string, id int64, input model.ImportProfile) (*model.ImportProfile, error) {
+	profile, err := s.GetByID(ctx, namespaceCode, projectCode, id)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.Name = input.Name
+	profile.Columns = input.Columns
+	profile.SkipHeader = input.SkipHeader
+	profile.Delimiter = input.Delimiter
+	profile.DefaultType = input.DefaultType
+	profile.DefaultStatus = input.DefaultStatus
+	profile.Overwrite = input.Overwrite
+	if err = s.validate(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Update(ctx, profile); err != nil {
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "import profile updated", "namespace", namespaceCode, "project", projectCode, "profile", id, "name", profile.Name)
+	return profile, nil
+}
+
+func (s *importProfileService) Delete(ctx context.Context, namespaceCode, projectCode string, id int64) (bool, error) {
+	if _, err := s.GetByID(ctx, namespaceCode, projectCode, id); err != nil {
+		return false, err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete import profile", "namespace", namespaceCode, "project", projectCode, "profile", id, "error", err)
+		return false, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "import profile deleted", "namespace", namespaceCode, "project", projectCode, "profile", id)
+	return true, nil
+}
+
+// validate checks the profile, its columns and default values being checked as the import does, and that its name is
+// not used by another profile of the project
+func (s *importProfileService) validate(ctx context.Context, profile *model.ImportProfile) error {
+	if profile.Delimiter == "" {
+		profile.Delimiter = model.ImportDelimiterTab
+	}
+	if err := s.ctx.Validator.Struct(profile); err != nil {
+		return err
+	}
+
+	for i, col := range profile.Columns {
+		profile.Columns[i] = strings.ToLower(strings.TrimSpace(col))
+	}
+	if _, err := profileImportLayout(profile); err != nil {
+		return flectoErrors.Wrap(flectoErrors.CodeValidationFailed, err).WithField("columns")
+	}
+	if profile.DefaultType != nil {
+		if _, err := parseRedirectType(string(*profile.DefaultType)); err != nil {
+			return flectoErrors.Wrap(flectoErrors.CodeValidationFailed, err).WithField("defaultType")
+		}
+	}
+	if profile.DefaultStatus != nil {
+		if _, err := parseRedirectStatus(string(*profile.DefaultStatus)); err != nil {
+			return flectoErrors.Wrap(flectoErrors.CodeValidationFailed, err).WithField("defaultStatus")
+		}
+	}
+	// The columns left out by the profile take the default values
+	if len(profile.Columns) > 0 {
+		if profile.DefaultType == nil && !slices.Contains(profile.Columns, "type") {
+			return flectoErrors.New(flectoErrors.CodeValidationFailed, "the profile needs a type column or a default type").WithField("defaultType")
+		}
+		if profile.DefaultStatus == nil && !slices.Contains(profile.Columns, "status") {
+			return flectoErrors.New(flectoErrors.CodeValidationFailed, "the profile needs a status column or a default status").WithField("defaultStatus")
+		}
+	}
+
+	existing, err := s.repo.FindByName(ctx, profile.NamespaceCode, profile.ProjectCode, profile.Name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != profile.ID {
+		return fmt.Errorf("%w: %s", ErrImportProfileAlreadyExists, profile.Name)
+	}
+	return nil
+}
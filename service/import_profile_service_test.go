@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupImportProfileServiceTest(t *testing.T) ImportProfileService {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ImportProfile{}))
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "other-proj", NamespaceCode: "test-ns", Name: "Other"}).Error)
+
+	return NewImportProfileService(testContextWithPageConfig(defaultProjectCfg), repository.NewImportProfileRepository(db), repository.NewProjectRepository(db))
+}
+
+func TestImportProfileService_Create(t *testing.T) {
+	svc := setupImportProfileServiceTest(t)
+	ctx := context.Background()
+	status := commonTypes.RedirectStatusFound
+	redirectType := commonTypes.RedirectTypeBasic
+
+	profile, err := svc.Create(ctx, "test-ns", "test-proj", &model.ImportProfile{
+		Name: "legacy cms", Columns: []string{" Source ", "TARGET"}, SkipHeader: true,
+		DefaultType: &redirectType, DefaultStatus: &status,
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, profile.ID)
+	assert.Equal(t, []string{"source", "target"}, profile.Columns)
+	assert.Equal(t, model.ImportDelimiterTab, profile.Delimiter)
+
+	tests := []struct {
+		name  string
+		input model.ImportProfile
+		code  flectoErrors.Code
+		field string
+	}{
+		{name: "duplicate name", input: model.ImportProfile{Name: "legacy cms"}, code: flectoErrors.CodeAlreadyExists},
+		{name: "missing name", input: model.ImportProfile{}, code: flectoErrors.CodeValidationFailed, field: "name"},
+		{name: "invalid delimiter", input: model.ImportProfile{Name: "crm", Delimiter: "SPACE"}, code: flectoErrors.CodeValidationFailed, field: "delimiter"},
+		{name: "unknown column", input: model.ImportProfile{Name: "crm", Columns: []string{"type", "source", "target", "status", "url"}}, code: flectoErrors.CodeValidationFailed, field: "columns"},
+		{name: "missing target column", input: model.ImportProfile{Name: "crm", Columns: []string{"type", "source", "status"}}, code: flectoErrors.CodeValidationFailed, field: "columns"},
+		{name: "missing status default", input: model.ImportProfile{Name: "crm", Columns: []string{"type", "source", "target"}}, code: flectoErrors.CodeValidationFailed, field: "defaultStatus"},
+		{name: "invalid default type", input: model.ImportProfile{Name: "crm", DefaultType: new(commonTypes.RedirectType)}, code: flectoErrors.CodeValidationFailed, field: "defaultType"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := tt.input
+			_, err := svc.Create(ctx, "test-ns", "test-proj", &input)
+			apiErr := flectoErrors.From(err)
+			assert.Equal(t, tt.code, apiErr.Code)
+			assert.Equal(t, tt.field, apiErr.Field)
+		})
+	}
+
+	_, err = svc.Create(ctx, "test-ns", "other-proj", &model.ImportProfile{Name: "legacy cms"})
+	assert.NoError(t, err, "the names are unique per project")
+	_, err = svc.Create(ctx, "test-ns", "unknown", &model.ImportProfile{Name: "legacy cms"})
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestImportProfileService_Update(t *testing.T) {
+	svc := setupImportProfileServiceTest(t)
+	ctx := context.Background()
+	profile, err := svc.Create(ctx, "test-ns", "test-proj", &model.ImportProfile{Name: "legacy cms", Overwrite: true})
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, "test-ns", "test-proj", &model.ImportProfile{Name: "crm"})
+	require.NoError(t, err)
+
+	updated, err := svc.Update(ctx, "test-ns", "test-proj", profile.ID, model.ImportProfile{Name: "legacy cms", Delimiter: model.ImportDelimiterComma})
+	require.NoError(t, err)
+	assert.Equal(t, model.ImportDelimiterComma, updated.Delimiter)
+	assert.False(t, updated.Overwrite)
+
+	_, err = svc.Update(ctx, "test-ns", "test-proj", profile.ID, model.ImportProfile{Name: "crm"})
+	assert.ErrorIs(t, err, ErrImportProfileAlreadyExists)
+
+	_, err = svc.Update(ctx, "test-ns", "other-proj", profile.ID, model.ImportProfile{Name: "legacy cms"})
+	assert.ErrorIs(t, err, ErrImportProfileNotFound)
+}
+
+func TestImportProfileService_Delete(t *testing.T) {
+	svc := setupImportProfileServiceTest(t)
+	ctx := context.Background()
+	profile, err := svc.Create(ctx, "test-ns", "test-proj", &model.ImportProfile{Name: "legacy cms"})
+	require.NoError(t, err)
+
+	_, err = svc.Delete(ctx, "test-ns", "other-proj", profile.ID)
+	assert.ErrorIs(t, err, ErrImportProfileNotFound)
+
+	deleted, err := svc.Delete(ctx, "test-ns", "test-proj", profile.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	profiles, err := svc.GetByProject(ctx, "test-ns", "test-proj")
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+}
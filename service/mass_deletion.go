@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+// ErrMassDeletion is returned when an operation deletes more than publish.mass_deletion.max_percent of the published
+// redirects or pages of a project without being forced
+var ErrMassDeletion = flectoErrors.New(flectoErrors.CodeMassDeletion, "mass deletion refused")
+
+// countPublished sets the number of published redirects and pages of the project in the totals of the deletion,
+// for the kinds it deletes
+func countPublished(tx *gorm.DB, namespaceCode, projectCode string, deletion *model.MassDeletion) error {
+	if deletion.Redirects > 0 {
+		if err := tx.Model(&model.Redirect{}).
+			Where("namespace_code = ? AND project_code = ? AND is_published = ?", namespaceCode, projectCode, true).
+			Count(&deletion.RedirectsTotal).Error; err != nil {
+			return err
+		}
+	}
+	if deletion.Pages > 0 {
+		return tx.Model(&model.Page{}).
+			Where("namespace_code = ? AND project_code = ? AND is_published = ?", namespaceCode, projectCode, true).
+			Count(&deletion.PagesTotal).Error
+	}
+	return nil
+}
+
+// guardMassDeletion refuses the deletion when it exceeds the safeguard of the configuration, unless it is forced.
+// An exceeding deletion is logged and notified, forced or not, notifications being nil sending no notification.
+func guardMassDeletion(ctx context.Context, appCtx *appContext.Context, notifications NotificationService, namespaceCode, projectCode string, deletion model.MassDeletion, force bool) error {
+	cfg := appCtx.CurrentConfig().Publish.MassDeletion
+	if !deletion.Exceeds(cfg.MaxPercent, cfg.MinCount) {
+		return nil
+	}
+
+	deletion.Forced = force
+	appCtx.Logger.WarnContext(ctx, "mass deletion detected", "namespace", namespaceCode, "project", projectCode, "operation", deletion.Operation,
+		"redirects", deletion.Redirects, "redirectsTotal", deletion.RedirectsTotal, "pages", deletion.Pages, "pagesTotal", deletion.PagesTotal, "forced", force)
+	if notifications != nil {
+		notifications.Notify(model.NotificationEvent{
+			Type: model.NotificationEventMassDeletion, NamespaceCode: namespaceCode, ProjectCode: projectCode, Subject: types.SubjectFromContext(ctx),
+			MassDeletion: &deletion,
+		})
+	}
+	if force {
+		return nil
+	}
+	return fmt.Errorf("%w: the %s of project %s/%s deletes %d of the %d published redirects and %d of the %d published pages, more than %d%%, force it to proceed",
+		ErrMassDeletion, deletion.Operation, namespaceCode, projectCode, deletion.Redirects, deletion.RedirectsTotal, deletion.Pages, deletion.PagesTotal, cfg.MaxPercent)
+}
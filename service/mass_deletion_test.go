@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGuardMassDeletion(t *testing.T) {
+	ctx := types.WithSubject(context.Background(), "alice")
+	deletion := model.MassDeletion{Operation: model.MassDeletionOperationPublish, Redirects: 60, RedirectsTotal: 100}
+
+	t.Run("below the limit", func(t *testing.T) {
+		db, appCtx, _, notifications := setupNotificationServiceTest(t)
+
+		err := guardMassDeletion(ctx, appCtx, notifications, "test-ns", "test-proj", model.MassDeletion{Redirects: 50, RedirectsTotal: 100}, false)
+		assert.NoError(t, err)
+		assert.Empty(t, notifiedEvents(t, db))
+	})
+
+	t.Run("refused", func(t *testing.T) {
+		db, appCtx, _, notifications := setupNotificationServiceTest(t)
+
+		err := guardMassDeletion(ctx, appCtx, notifications, "test-ns", "test-proj", deletion, false)
+		assert.ErrorIs(t, err, ErrMassDeletion)
+		assert.Contains(t, err.Error(), "deletes 60 of the 100 published redirects")
+
+		events := notifiedEvents(t, db)
+		require.Len(t, events, 1)
+		event := events[0]
+		assert.Equal(t, model.NotificationEventMassDeletion, event.Type)
+		assert.Equal(t, "alice", event.Subject)
+		assert.Equal(t, int64(60), event.MassDeletion.Redirects)
+		assert.False(t, event.MassDeletion.Forced)
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		db, appCtx, _, notifications := setupNotificationServiceTest(t)
+
+		err := guardMassDeletion(ctx, appCtx, notifications, "test-ns", "test-proj", deletion, true)
+		assert.NoError(t, err)
+
+		events := notifiedEvents(t, db)
+		require.Len(t, events, 1)
+		event := events[0]
+		assert.True(t, event.MassDeletion.Forced)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		db, appCtx, _, _ := setupNotificationServiceTest(t)
+		appCtx.Config.Publish.MassDeletion.MaxPercent = 0
+
+		err := guardMassDeletion(ctx, appCtx, nil, "test-ns", "test-proj", deletion, false)
+		assert.NoError(t, err)
+		assert.Empty(t, notifiedEvents(t, db))
+	})
+}
+
+func TestProjectService_Publish_MassDeletion(t *testing.T) {
+	db, appCtx, _, notifications := setupNotificationServiceTest(t)
+	require.NoError(t, db.AutoMigrate(&model.RedirectTombstone{}))
+	for i := range 12 {
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true),
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: fmt.Sprintf("/old-%d", i), Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}}
+		require.NoError(t, db.Create(redirect).Error)
+		if i < 10 {
+			require.NoError(t, db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeDelete, OldRedirectID: &redirect.ID}).Error)
+		}
+	}
+	svc := NewProjectService(appCtx, repository.NewProjectRepository(db), repository.NewPageRepository(db), repository.NewRedirectDraftRepository(db), repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), notifications.outbox, notifications)
+
+	_, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+	assert.ErrorIs(t, err, ErrMassDeletion)
+	var count int64
+	require.NoError(t, db.Model(&model.Redirect{}).Count(&count).Error)
+	assert.Equal(t, int64(12), count)
+	events := notifiedEvents(t, db)
+	require.Len(t, events, 2)
+	assert.Equal(t, model.NotificationEventMassDeletion, events[0].Type)
+	assert.Equal(t, model.NotificationEventPublishFailed, events[1].Type)
+
+	project, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{Force: true})
+	require.NoError(t, err)
+	assert.Equal(t, 2, project.Version)
+	require.NoError(t, db.Model(&model.Redirect{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidNamespacePolicy is returned when a rule of a namespace policy is invalid
+	ErrInvalidNamespacePolicy = flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid namespace policy")
+	// ErrNamespacePolicyViolation is returned when a redirect draft breaks a rule of the policy of its namespace
+	ErrNamespacePolicyViolation = flectoErrors.New(flectoErrors.CodePolicyViolation, "namespace policy violation")
+)
+
+type NamespacePolicyService interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	Get(ctx context.Context, namespaceCode string) (*model.NamespacePolicy, error)
+	Update(ctx context.Context, namespaceCode string, rules []model.NamespacePolicyRule) (*model.NamespacePolicy, error)
+}
+
+type namespacePolicyService struct {
+	ctx  *appContext.Context
+	repo repository.NamespacePolicyRepository
+}
+
+func NewNamespacePolicyService(ctx *appContext.Context, repo repository.NamespacePolicyRepository) NamespacePolicyService {
+	return &namespacePolicyService{
+		ctx:  ctx,
+		repo: repo,
+	}
+}
+
+func (s *namespacePolicyService) GetTx(ctx context.Context) *gorm.DB {
+	return s.repo.GetTx(ctx)
+}
+
+func (s *namespacePolicyService) GetQuery(ctx context.Context) *gorm.DB {
+	return s.repo.GetQuery(ctx)
+}
+
+// Get returns the policy of a namespace, nil when none was defined
+func (s *namespacePolicyService) Get(ctx context.Context, namespaceCode string) (*model.NamespacePolicy, error) {
+	return s.repo.FindByNamespace(ctx, namespaceCode)
+}
+
+// Update replaces the rules of the policy of a namespace, no rule removing every constraint
+func (s *namespacePolicyService) Update(ctx context.Context, namespaceCode string, rules []model.NamespacePolicyRule) (*model.NamespacePolicy, error) {
+	if rules == nil {
+		rules = []model.NamespacePolicyRule{}
+	}
+	policy := &model.NamespacePolicy{
+		NamespaceCode: namespaceCode,
+		Rules:         rules,
+		UpdatedBy:     types.SubjectFromContext(ctx),
+	}
+	if _, err := policy.Compile(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidNamespacePolicy, err)
+	}
+	if err := s.repo.Save(ctx, policy); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to update namespace policy", "namespace", namespaceCode, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "namespace policy updated", "namespace", namespaceCode, "rules", len(rules))
+	return s.Get(ctx, namespaceCode)
+}
+
+// loadNamespacePolicy returns the compiled policy of a namespace read with db, a namespace without policy having no rule
+func loadNamespacePolicy(db *gorm.DB, namespaceCode string) (*model.CompiledNamespacePolicy, error) {
+	var policies []model.NamespacePolicy
+	if err := db.Where(fmt.Sprintf("%s = ?", model.ColumnNamespaceCode), namespaceCode).Limit(1).Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return (*model.NamespacePolicy)(nil).Compile()
+	}
+	return policies[0].Compile()
+}
+
+// namespacePolicyError returns the error of a redirect breaking a rule of the policy of its namespace, nil when it satisfies them
+func namespacePolicyError(policy *model.CompiledNamespacePolicy, redirect *commonTypes.Redirect) error {
+	violation := policy.Check(redirect)
+	if violation == nil {
+		return nil
+	}
+	return fmt.Errorf("%w: %s (%s)", ErrNamespacePolicyViolation, violation.Rule.Description(), violation.Value)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupNamespacePolicyServiceTest(t *testing.T) NamespacePolicyService {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.NamespacePolicy{}))
+	db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"})
+	return NewNamespacePolicyService(appContext.TestContext(nil), repository.NewNamespacePolicyRepository(db))
+}
+
+func TestNamespacePolicyService_Update(t *testing.T) {
+	svc := setupNamespacePolicyServiceTest(t)
+	ctx := types.WithSubject(context.Background(), "alice")
+
+	policy, err := svc.Get(ctx, "test-ns")
+	require.NoError(t, err)
+	assert.Nil(t, policy, "no policy defined")
+
+	policy, err = svc.Update(ctx, "test-ns", []model.NamespacePolicyRule{
+		{Type: model.NamespacePolicyRuleSourcePattern, Pattern: "^/"},
+		{Type: model.NamespacePolicyRuleSameOriginTarget},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "test-ns", policy.NamespaceCode)
+	assert.Len(t, policy.Rules, 2)
+	assert.Equal(t, "alice", policy.UpdatedBy)
+
+	// Updating the policy replaces its rules
+	policy, err = svc.Update(context.Background(), "test-ns", []model.NamespacePolicyRule{{Type: model.NamespacePolicyRuleBannedTarget, Pattern: `\.exe$`}})
+	require.NoError(t, err)
+	assert.Equal(t, []model.NamespacePolicyRule{{Type: model.NamespacePolicyRuleBannedTarget, Pattern: `\.exe$`}}, policy.Rules)
+	assert.Equal(t, "", policy.UpdatedBy)
+
+	policy, err = svc.Update(context.Background(), "test-ns", nil)
+	require.NoError(t, err)
+	assert.Empty(t, policy.Rules)
+}
+
+func TestNamespacePolicyService_Update_Invalid(t *testing.T) {
+	svc := setupNamespacePolicyServiceTest(t)
+
+	policy, err := svc.Update(context.Background(), "test-ns", []model.NamespacePolicyRule{{Type: model.NamespacePolicyRuleSourcePattern, Pattern: "(["}})
+
+	assert.ErrorIs(t, err, ErrInvalidNamespacePolicy)
+	assert.Nil(t, policy)
+	policy, err = svc.Get(context.Background(), "test-ns")
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+}
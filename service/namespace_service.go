@@ -17,6 +17,7 @@ type NamespaceService interface {
 	Update(ctx context.Context, namespaceCode string, input model.Namespace) (*model.Namespace, error)
 	Delete(ctx context.Context, namespaceCode string) (bool, error)
 	GetByCode(ctx context.Context, namespaceCode string) (*model.Namespace, error)
+	GetByCodes(ctx context.Context, namespaceCodes []string) ([]model.Namespace, error)
 	GetAll(ctx context.Context) ([]model.Namespace, error)
 	Search(ctx context.Context, query *gorm.DB) ([]model.Namespace, error)
 	SearchPaginate(ctx context.Context, pagination *types.PaginationInput, query *gorm.DB) (*model.NamespaceList, error)
@@ -54,11 +55,11 @@ func (s *namespaceService) Create(ctx context.Context, input *model.Namespace) (
 		return nil, err
 	}
 	if err = s.repo.Create(ctx, input); err != nil {
-		s.ctx.Logger.Error("failed to create namespace", "code", input.NamespaceCode, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to create namespace", "code", input.NamespaceCode, "error", err)
 		return nil, err
 	}
 
-	s.ctx.Logger.Info("namespace created", "code", input.NamespaceCode)
+	s.ctx.Logger.InfoContext(ctx, "namespace created", "code", input.NamespaceCode)
 	return input, nil
 }
 
@@ -84,16 +85,16 @@ func (s *namespaceService) Update(ctx context.Context, namespaceCode string, inp
 func (s *namespaceService) Delete(ctx context.Context, namespaceCode string) (bool, error) {
 	// Delete associated projects first
 	if err := s.projectRepo.DeleteByNamespaceCode(ctx, namespaceCode); err != nil {
-		s.ctx.Logger.Error("failed to delete namespace projects", "code", namespaceCode, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete namespace projects", "code", namespaceCode, "error", err)
 		return false, err
 	}
 
 	if err := s.repo.DeleteByCode(ctx, namespaceCode); err != nil {
-		s.ctx.Logger.Error("failed to delete namespace", "code", namespaceCode, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete namespace", "code", namespaceCode, "error", err)
 		return false, err
 	}
 
-	s.ctx.Logger.Info("namespace deleted", "code", namespaceCode)
+	s.ctx.Logger.InfoContext(ctx, "namespace deleted", "code", namespaceCode)
 	return true, nil
 }
 
@@ -101,6 +102,10 @@ func (s *namespaceService) GetByCode(ctx context.Context, namespaceCode string)
 	return s.repo.FindByCode(ctx, namespaceCode)
 }
 
+func (s *namespaceService) GetByCodes(ctx context.Context, namespaceCodes []string) ([]model.Namespace, error) {
+	return s.repo.FindByCodes(ctx, namespaceCodes)
+}
+
 func (s *namespaceService) GetAll(ctx context.Context) ([]model.Namespace, error) {
 	return s.repo.FindAll(ctx)
 }
@@ -110,7 +115,7 @@ func (s *namespaceService) Search(ctx context.Context, query *gorm.DB) ([]model.
 }
 
 func (s *namespaceService) SearchPaginate(ctx context.Context, pagination *types.PaginationInput, query *gorm.DB) (*model.NamespaceList, error) {
-	namespaces, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	namespaces, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
@@ -408,7 +408,7 @@ func TestNamespaceService_SearchPaginate(t *testing.T) {
 		}
 
 		mockNsRepo.EXPECT().
-			SearchPaginate(ctx, nil, 10, 5).
+			SearchPaginate(ctx, nil, 10, 5, nil).
 			Return(expectedNs, int64(20), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -432,7 +432,7 @@ func TestNamespaceService_SearchPaginate(t *testing.T) {
 		}
 
 		mockNsRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(expectedNs, int64(1), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -452,7 +452,7 @@ func TestNamespaceService_SearchPaginate(t *testing.T) {
 		expectedErr := errors.New("search error")
 
 		mockNsRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(nil, int64(0), expectedErr)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync/atomic"
+
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/notification"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+var ErrNotificationChannelDisabled = flectoErrors.New(flectoErrors.CodeInvalidRequest, "notification channel disabled")
+
+// NotificationService sends the events of the projects to the users subscribed to them, on the channels
+// enabled in the configuration. The events go through the outbox, each one being turned into a delivery per
+// subscription asking for it, so that a channel failing is attempted again without sending twice to the others.
+type NotificationService interface {
+	GetByUser(ctx context.Context, namespaceCode, projectCode, username string) ([]model.NotificationSubscription, error)
+	Subscribe(ctx context.Context, namespaceCode, projectCode, username string, input model.NotificationSubscription) (*model.NotificationSubscription, error)
+	Unsubscribe(ctx context.Context, namespaceCode, projectCode, username string, channel model.NotificationChannel) (bool, error)
+	Channels() []model.NotificationChannel
+	// SetSenders replaces the senders of the channels, when the configuration is reloaded
+	SetSenders(senders map[model.NotificationChannel]notification.Sender)
+	Notify(event model.NotificationEvent)
+}
+
+type notificationService struct {
+	ctx     *appContext.Context
+	repo    repository.NotificationSubscriptionRepository
+	senders atomic.Pointer[map[model.NotificationChannel]notification.Sender]
+	outbox  OutboxService
+}
+
+// notificationDelivery is the payload of the outbox event sending a notification to a subscription
+type notificationDelivery struct {
+	Username  string                      `json:"username"`
+	Channel   model.NotificationChannel   `json:"channel"`
+	Target    string                      `json:"target"`
+	EventType model.NotificationEventType `json:"eventType"`
+	Message   notification.Message        `json:"message"`
+}
+
+func NewNotificationService(ctx *appContext.Context, repo repository.NotificationSubscriptionRepository, senders map[model.NotificationChannel]notification.Sender, outbox OutboxService) NotificationService {
+	s := &notificationService{
+		ctx:    ctx,
+		repo:   repo,
+		outbox: outbox,
+	}
+	s.SetSenders(senders)
+	outbox.Handle(model.OutboxTopicNotification, s.fanOut)
+	outbox.Handle(model.OutboxTopicNotificationDelivery, s.send)
+	return s
+}
+
+// NewNotificationOutboxEvent returns the outbox event notifying the subscriptions of the project of the event
+func NewNotificationOutboxEvent(event model.NotificationEvent) (*model.OutboxEvent, error) {
+	return model.NewOutboxEvent(model.OutboxTopicNotification, event.NamespaceCode, event.ProjectCode, event)
+}
+
+// NewNotificationSenders returns the senders of the channels enabled in the configuration
+func NewNotificationSenders(cfg config.NotificationConfig) map[model.NotificationChannel]notification.Sender {
+	senders := make(map[model.NotificationChannel]notification.Sender)
+	if cfg.SMTP.Host != "" {
+		senders[model.NotificationChannelEmail] = notification.NewSMTPSender(cfg.SMTP)
+	}
+	if len(cfg.Slack.AllowedHosts) > 0 {
+		senders[model.NotificationChannelSlack] = notification.NewSlackSender(cfg.Slack.AllowedHosts, &http.Client{})
+	}
+	return senders
+}
+
+func (s *notificationService) GetByUser(ctx context.Context, namespaceCode, projectCode, username string) ([]model.NotificationSubscription, error) {
+	return s.repo.FindByUser(ctx, namespaceCode, projectCode, username)
+}
+
+// Subscribe creates or replaces the subscription of the user to the project on the channel of the input
+func (s *notificationService) Subscribe(ctx context.Context, namespaceCode, projectCode, username string, input model.NotificationSubscription) (*model.NotificationSubscription, error) {
+	sender, ok := (*s.senders.Load())[input.Channel]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationChannelDisabled, input.Channel)
+	}
+	if err := sender.Validate(input.Target); err != nil {
+		return nil, err
+	}
+
+	subscription, err := s.repo.FindByUserChannel(ctx, namespaceCode, projectCode, username, input.Channel)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if subscription == nil {
+		subscription = &model.NotificationSubscription{NamespaceCode: namespaceCode, ProjectCode: projectCode, Username: username, Channel: input.Channel}
+	}
+	subscription.Target = input.Target
+	subscription.Events = slices.Compact(slices.Sorted(slices.Values(input.Events)))
+	if err = s.ctx.Validator.Struct(subscription); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Save(ctx, subscription); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to save notification subscription", "namespace", namespaceCode, "project", projectCode, "username", username, "channel", input.Channel, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "notification subscription saved", "namespace", namespaceCode, "project", projectCode, "username", username, "channel", input.Channel, "events", subscription.Events)
+	return subscription, nil
+}
+
+func (s *notificationService) Unsubscribe(ctx context.Context, namespaceCode, projectCode, username string, channel model.NotificationChannel) (bool, error) {
+	deleted, err := s.repo.Delete(ctx, namespaceCode, projectCode, username, channel)
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete notification subscription", "namespace", namespaceCode, "project", projectCode, "username", username, "channel", channel, "error", err)
+		return false, err
+	}
+	if deleted {
+		s.ctx.Logger.InfoContext(ctx, "notification subscription deleted", "namespace", namespaceCode, "project", projectCode, "username", username, "channel", channel)
+	}
+	return deleted, nil
+}
+
+// Channels returns the channels enabled in the configuration
+func (s *notificationService) Channels() []model.NotificationChannel {
+	senders := *s.senders.Load()
+	channels := make([]model.NotificationChannel, 0, len(senders))
+	for channel := range senders {
+		channels = append(channels, channel)
+	}
+	slices.Sort(channels)
+	return channels
+}
+
+func (s *notificationService) SetSenders(senders map[model.NotificationChannel]notification.Sender) {
+	s.senders.Store(&senders)
+}
+
+// Notify writes the event in the outbox and fans it out to the subscriptions, the failures being logged
+func (s *notificationService) Notify(event model.NotificationEvent) {
+	ctx := database.WithNamespace(context.Background(), event.NamespaceCode)
+	outboxEvent, err := NewNotificationOutboxEvent(event)
+	if err == nil {
+		err = s.outbox.Publish(ctx, outboxEvent)
+	}
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to write notification", "namespace", event.NamespaceCode, "project", event.ProjectCode, "event", event.Type, "error", err)
+	}
+}
+
+// fanOut writes a delivery per subscription of the project asking for the event, sent by the relay so that the
+// notifications are not sent in the request announcing the event. The deliveries have the outbox event and the
+// subscription as dedup key, so that a fan out attempted again does not send a notification twice.
+func (s *notificationService) fanOut(ctx context.Context, outboxEvent *model.OutboxEvent) error {
+	var event model.NotificationEvent
+	if err := outboxEvent.Decode(&event); err != nil {
+		return err
+	}
+	subscriptions, err := s.repo.FindByProject(ctx, event.NamespaceCode, event.ProjectCode)
+	if err != nil {
+		return fmt.Errorf("load notification subscriptions: %w", err)
+	}
+
+	message := renderNotification(event)
+	deliveries := make([]*model.OutboxEvent, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if !subscription.Wants(event.Type) {
+			continue
+		}
+		delivery, errDelivery := model.NewOutboxEvent(model.OutboxTopicNotificationDelivery, event.NamespaceCode, event.ProjectCode, notificationDelivery{
+			Username:  subscription.Username,
+			Channel:   subscription.Channel,
+			Target:    subscription.Target,
+			EventType: event.Type,
+			Message:   message,
+		})
+		if errDelivery != nil {
+			return errDelivery
+		}
+		delivery.DedupKey = types.Ptr(fmt.Sprintf("%d:%d", outboxEvent.ID, subscription.ID))
+		deliveries = append(deliveries, delivery)
+	}
+	return s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.outbox.Add(tx, deliveries...)
+	})
+}
+
+// send sends a delivery on its channel, a channel disabled by a reload of the configuration being skipped
+func (s *notificationService) send(ctx context.Context, outboxEvent *model.OutboxEvent) error {
+	var delivery notificationDelivery
+	if err := outboxEvent.Decode(&delivery); err != nil {
+		return err
+	}
+	sender, ok := (*s.senders.Load())[delivery.Channel]
+	if !ok {
+		s.ctx.Logger.WarnContext(ctx, "notification skipped: channel disabled", "namespace", outboxEvent.NamespaceCode, "project", outboxEvent.ProjectCode,
+			"event", delivery.EventType, "username", delivery.Username, "channel", delivery.Channel)
+		return nil
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, s.ctx.CurrentConfig().Notification.Timeout)
+	defer cancel()
+	if err := sender.Send(sendCtx, delivery.Target, delivery.Message); err != nil {
+		return fmt.Errorf("send notification to %s on %s: %w", delivery.Username, delivery.Channel, err)
+	}
+	s.ctx.Logger.DebugContext(ctx, "notification sent", "namespace", outboxEvent.NamespaceCode, "project", outboxEvent.ProjectCode, "event", delivery.EventType,
+		"username", delivery.Username, "channel", delivery.Channel)
+	return nil
+}
+
+// renderNotification returns the message sent for an event
+func renderNotification(event model.NotificationEvent) notification.Message {
+	project := event.NamespaceCode + "/" + event.ProjectCode
+	by := ""
+	if event.Subject != "" {
+		by = " by " + event.Subject
+	}
+
+	switch event.Type {
+	case model.NotificationEventPublishSucceeded:
+		return notification.Message{
+			Subject: fmt.Sprintf("[Flecto] %s published (version %d)", project, event.Version),
+			Body:    fmt.Sprintf("Version %d of project %s was published%s.", event.Version, project, by),
+		}
+	case model.NotificationEventPublishFailed:
+		return notification.Message{
+			Subject: fmt.Sprintf("[Flecto] %s publish failed", project),
+			Body:    fmt.Sprintf("The publish of project %s%s failed: %s", project, by, event.Error),
+		}
+	case model.NotificationEventQuotaWarning:
+		percent := 0.0
+		if event.TotalContentSizeLimit > 0 {
+			percent = float64(event.TotalContentSize) * 100 / float64(event.TotalContentSizeLimit)
+		}
+		return notification.Message{
+			Subject: fmt.Sprintf("[Flecto] %s pages close to the size limit", project),
+			Body: fmt.Sprintf("The pages of project %s use %d of the %d bytes allowed (%.0f%%), new pages will be refused once the limit is reached.",
+				project, event.TotalContentSize, event.TotalContentSizeLimit, percent),
+		}
+	case model.NotificationEventMassDeletion:
+		deletion := model.MassDeletion{}
+		if event.MassDeletion != nil {
+			deletion = *event.MassDeletion
+		}
+		outcome := "It was refused, it has to be forced to proceed."
+		if deletion.Forced {
+			outcome = "It was forced through the safeguard."
+		}
+		return notification.Message{
+			Subject: fmt.Sprintf("[Flecto] %s mass deletion", project),
+			Body: fmt.Sprintf("A %s of project %s%s deletes %d of the %d published redirects and %d of the %d published pages. %s",
+				deletion.Operation, project, by, deletion.Redirects, deletion.RedirectsTotal, deletion.Pages, deletion.PagesTotal, outcome),
+		}
+	default:
+		return notification.Message{
+			Subject: fmt.Sprintf("[Flecto] %s %s", project, event.Type),
+			Body:    fmt.Sprintf("Event %s on project %s%s.", event.Type, project, by),
+		}
+	}
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/notification"
+	"github.com/flectolab/flecto-manager/repository"
+	types "github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type sentNotification struct {
+	target  string
+	message notification.Message
+}
+
+type fakeSender struct {
+	mu   sync.Mutex
+	sent []sentNotification
+	err  error
+}
+
+func (f *fakeSender) Validate(target string) error {
+	if !strings.Contains(target, "@") {
+		return notification.ErrInvalidTarget
+	}
+	return nil
+}
+
+func (f *fakeSender) Send(_ context.Context, target string, message notification.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, sentNotification{target: target, message: message})
+	return nil
+}
+
+func setupNotificationServiceTest(t *testing.T) (*gorm.DB, *appContext.Context, *fakeSender, *notificationService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectTag{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.NotificationSubscription{}, &model.OutboxEvent{}))
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 1}).Error)
+
+	appCtx := testContextWithPageConfig(defaultProjectCfg)
+	sender := &fakeSender{}
+	outbox := NewOutboxService(appCtx, repository.NewOutboxRepository(db), invalidation.NewMemoryBus())
+	svc := NewNotificationService(appCtx, repository.NewNotificationSubscriptionRepository(db), map[model.NotificationChannel]notification.Sender{
+		model.NotificationChannelEmail: sender,
+	}, outbox)
+	return db, appCtx, sender, svc.(*notificationService)
+}
+
+// notifiedEvents returns the events written in the outbox by Notify, in their order
+func notifiedEvents(t *testing.T, db *gorm.DB) []model.NotificationEvent {
+	var outboxEvents []model.OutboxEvent
+	require.NoError(t, db.Where("topic = ?", model.OutboxTopicNotification).Order("id").Find(&outboxEvents).Error)
+	events := make([]model.NotificationEvent, 0, len(outboxEvents))
+	for _, outboxEvent := range outboxEvents {
+		var event model.NotificationEvent
+		require.NoError(t, outboxEvent.Decode(&event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestNewNotificationSenders(t *testing.T) {
+	cfg := testContextWithPageConfig(defaultProjectCfg).Config.Notification
+	senders := NewNotificationSenders(cfg)
+	assert.Len(t, senders, 1)
+	assert.Contains(t, senders, model.NotificationChannelSlack)
+
+	cfg.SMTP.Host = "smtp.example.com"
+	cfg.Slack.AllowedHosts = nil
+	senders = NewNotificationSenders(cfg)
+	assert.Len(t, senders, 1)
+	assert.Contains(t, senders, model.NotificationChannelEmail)
+}
+
+func TestNotificationService_Channels(t *testing.T) {
+	_, _, _, svc := setupNotificationServiceTest(t)
+	assert.Equal(t, []model.NotificationChannel{model.NotificationChannelEmail}, svc.Channels())
+
+	svc.SetSenders(map[model.NotificationChannel]notification.Sender{
+		model.NotificationChannelEmail: &fakeSender{},
+		model.NotificationChannelSlack: &fakeSender{},
+	})
+	assert.Equal(t, []model.NotificationChannel{model.NotificationChannelEmail, model.NotificationChannelSlack}, svc.Channels())
+}
+
+func TestNotificationService_Subscribe(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("upsert", func(t *testing.T) {
+		_, _, _, svc := setupNotificationServiceTest(t)
+
+		subscription, err := svc.Subscribe(ctx, "test-ns", "test-proj", "alice", model.NotificationSubscription{
+			Channel: model.NotificationChannelEmail,
+			Target:  "alice@example.com",
+			Events:  []model.NotificationEventType{model.NotificationEventQuotaWarning, model.NotificationEventPublishFailed, model.NotificationEventQuotaWarning},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []model.NotificationEventType{model.NotificationEventPublishFailed, model.NotificationEventQuotaWarning}, subscription.Events)
+
+		again, err := svc.Subscribe(ctx, "test-ns", "test-proj", "alice", model.NotificationSubscription{
+			Channel: model.NotificationChannelEmail,
+			Target:  "alice@example.org",
+			Events:  []model.NotificationEventType{model.NotificationEventPublishSucceeded},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, subscription.ID, again.ID)
+
+		subscriptions, err := svc.GetByUser(ctx, "test-ns", "test-proj", "alice")
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 1)
+		assert.Equal(t, "alice@example.org", subscriptions[0].Target)
+		assert.Equal(t, []model.NotificationEventType{model.NotificationEventPublishSucceeded}, subscriptions[0].Events)
+
+		subscriptions, err = svc.GetByUser(ctx, "test-ns", "test-proj", "bob")
+		require.NoError(t, err)
+		assert.Empty(t, subscriptions)
+	})
+
+	t.Run("disabled channel", func(t *testing.T) {
+		_, _, _, svc := setupNotificationServiceTest(t)
+
+		_, err := svc.Subscribe(ctx, "test-ns", "test-proj", "alice", model.NotificationSubscription{
+			Channel: model.NotificationChannelSlack,
+			Target:  "https://hooks.slack.com/services/T/B/X",
+			Events:  []model.NotificationEventType{model.NotificationEventPublishFailed},
+		})
+		assert.ErrorIs(t, err, ErrNotificationChannelDisabled)
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		_, _, _, svc := setupNotificationServiceTest(t)
+
+		_, err := svc.Subscribe(ctx, "test-ns", "test-proj", "alice", model.NotificationSubscription{
+			Channel: model.NotificationChannelEmail,
+			Target:  "alice",
+			Events:  []model.NotificationEventType{model.NotificationEventPublishFailed},
+		})
+		assert.ErrorIs(t, err, notification.ErrInvalidTarget)
+	})
+
+	t.Run("invalid events", func(t *testing.T) {
+		_, _, _, svc := setupNotificationServiceTest(t)
+
+		_, err := svc.Subscribe(ctx, "test-ns", "test-proj", "alice", model.NotificationSubscription{
+			Channel: model.NotificationChannelEmail,
+			Target:  "alice@example.com",
+		})
+		assert.Error(t, err)
+
+		_, err = svc.Subscribe(ctx, "test-ns", "test-proj", "alice", model.NotificationSubscription{
+			Channel: model.NotificationChannelEmail,
+			Target:  "alice@example.com",
+			Events:  []model.NotificationEventType{"OTHER"},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestNotificationService_Unsubscribe(t *testing.T) {
+	ctx := context.Background()
+	_, _, _, svc := setupNotificationServiceTest(t)
+
+	_, err := svc.Subscribe(ctx, "test-ns", "test-proj", "alice", model.NotificationSubscription{
+		Channel: model.NotificationChannelEmail,
+		Target:  "alice@example.com",
+		Events:  []model.NotificationEventType{model.NotificationEventPublishFailed},
+	})
+	require.NoError(t, err)
+
+	deleted, err := svc.Unsubscribe(ctx, "test-ns", "test-proj", "alice", model.NotificationChannelEmail)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = svc.Unsubscribe(ctx, "test-ns", "test-proj", "alice", model.NotificationChannelEmail)
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
+
+func TestNotificationService_Notify(t *testing.T) {
+	db, _, sender, svc := setupNotificationServiceTest(t)
+	require.NoError(t, db.Create(&model.NotificationSubscription{NamespaceCode: "test-ns", ProjectCode: "test-proj", Username: "alice", Channel: model.NotificationChannelEmail,
+		Target: "alice@example.com", Events: []model.NotificationEventType{model.NotificationEventPublishFailed}}).Error)
+	event := model.NotificationEvent{Type: model.NotificationEventPublishFailed, NamespaceCode: "test-ns", ProjectCode: "test-proj"}
+
+	svc.Notify(event)
+	assert.Equal(t, []model.NotificationEvent{event}, notifiedEvents(t, db))
+
+	// The event is fanned out at once, the delivery being left to the relay
+	var outboxEvents []model.OutboxEvent
+	require.NoError(t, db.Order("id").Find(&outboxEvents).Error)
+	require.Len(t, outboxEvents, 2)
+	assert.Equal(t, model.OutboxStatusDelivered, outboxEvents[0].Status)
+	assert.Equal(t, model.OutboxTopicNotificationDelivery, outboxEvents[1].Topic)
+	assert.Equal(t, model.OutboxStatusPending, outboxEvents[1].Status)
+	assert.Empty(t, sender.sent)
+
+	count, err := svc.outbox.Relay(context.Background(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Len(t, sender.sent, 1)
+}
+
+func TestNotificationService_fanOut(t *testing.T) {
+	db, _, _, svc := setupNotificationServiceTest(t)
+	require.NoError(t, db.Create(&model.NotificationSubscription{NamespaceCode: "test-ns", ProjectCode: "test-proj", Username: "alice", Channel: model.NotificationChannelEmail,
+		Target: "alice@example.com", Events: []model.NotificationEventType{model.NotificationEventPublishFailed}}).Error)
+	event, err := NewNotificationOutboxEvent(model.NotificationEvent{Type: model.NotificationEventPublishFailed, NamespaceCode: "test-ns", ProjectCode: "test-proj"})
+	require.NoError(t, err)
+	require.NoError(t, db.Create(event).Error)
+
+	// A fan out attempted again writes no delivery twice
+	require.NoError(t, svc.fanOut(context.Background(), event))
+	require.NoError(t, svc.fanOut(context.Background(), event))
+
+	var deliveries []model.OutboxEvent
+	require.NoError(t, db.Where("topic = ?", model.OutboxTopicNotificationDelivery).Find(&deliveries).Error)
+	require.Len(t, deliveries, 1)
+	require.NotNil(t, deliveries[0].DedupKey)
+	assert.Equal(t, fmt.Sprintf("%d:1", event.ID), *deliveries[0].DedupKey)
+}
+
+func TestNotificationService_send(t *testing.T) {
+	db, _, sender, svc := setupNotificationServiceTest(t)
+	slack := &fakeSender{}
+	svc.SetSenders(map[model.NotificationChannel]notification.Sender{
+		model.NotificationChannelEmail: sender,
+		model.NotificationChannelSlack: slack,
+	})
+	require.NoError(t, db.Create(&[]model.NotificationSubscription{
+		{NamespaceCode: "test-ns", ProjectCode: "test-proj", Username: "alice", Channel: model.NotificationChannelEmail, Target: "alice@example.com",
+			Events: []model.NotificationEventType{model.NotificationEventPublishFailed}},
+		{NamespaceCode: "test-ns", ProjectCode: "test-proj", Username: "bob", Channel: model.NotificationChannelEmail, Target: "bob@example.com",
+			Events: []model.NotificationEventType{model.NotificationEventPublishSucceeded}},
+		{NamespaceCode: "test-ns", ProjectCode: "test-proj", Username: "bob", Channel: model.NotificationChannelSlack, Target: "https://hooks.slack.com/services/T/B/X",
+			Events: []model.NotificationEventType{model.NotificationEventPublishFailed}},
+	}).Error)
+	relay := func(now time.Time) {
+		_, err := svc.outbox.Relay(context.Background(), now)
+		require.NoError(t, err)
+	}
+
+	svc.Notify(model.NotificationEvent{Type: model.NotificationEventPublishFailed, NamespaceCode: "test-ns", ProjectCode: "test-proj", Subject: "carol", Error: "boom"})
+	relay(time.Now())
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "alice@example.com", sender.sent[0].target)
+	assert.Equal(t, "[Flecto] test-ns/test-proj publish failed", sender.sent[0].message.Subject)
+	assert.Equal(t, "The publish of project test-ns/test-proj by carol failed: boom", sender.sent[0].message.Body)
+	require.Len(t, slack.sent, 1)
+
+	// A failing channel is attempted again, without sending twice to the others
+	slack.err = errors.New("unavailable")
+	svc.Notify(model.NotificationEvent{Type: model.NotificationEventPublishFailed, NamespaceCode: "test-ns", ProjectCode: "test-proj"})
+	relay(time.Now())
+	assert.Len(t, sender.sent, 2)
+	var failed model.OutboxEvent
+	require.NoError(t, db.Where("status = ? AND attempts = ?", model.OutboxStatusPending, 1).First(&failed).Error)
+	assert.Contains(t, failed.LastError, "unavailable")
+	slack.err = nil
+	relay(failed.AvailableAt)
+	assert.Len(t, sender.sent, 2)
+	assert.Len(t, slack.sent, 2)
+
+	// A channel disabled by a reload of the configuration is skipped
+	svc.SetSenders(map[model.NotificationChannel]notification.Sender{model.NotificationChannelEmail: sender})
+	svc.Notify(model.NotificationEvent{Type: model.NotificationEventPublishFailed, NamespaceCode: "test-ns", ProjectCode: "test-proj"})
+	relay(time.Now())
+	assert.Len(t, sender.sent, 3)
+	assert.Len(t, slack.sent, 2)
+
+	svc.Notify(model.NotificationEvent{Type: model.NotificationEventQuotaWarning, NamespaceCode: "test-ns", ProjectCode: "other-proj"})
+	relay(time.Now())
+	assert.Len(t, sender.sent, 3)
+}
+
+func TestRenderNotification(t *testing.T) {
+	message := renderNotification(model.NotificationEvent{Type: model.NotificationEventPublishSucceeded, NamespaceCode: "ns", ProjectCode: "proj", Version: 3, Subject: "alice"})
+	assert.Equal(t, "[Flecto] ns/proj published (version 3)", message.Subject)
+	assert.Equal(t, "Version 3 of project ns/proj was published by alice.", message.Body)
+
+	message = renderNotification(model.NotificationEvent{Type: model.NotificationEventQuotaWarning, NamespaceCode: "ns", ProjectCode: "proj", TotalContentSize: 900, TotalContentSizeLimit: 1000})
+	assert.Equal(t, "[Flecto] ns/proj pages close to the size limit", message.Subject)
+	assert.Contains(t, message.Body, "use 900 of the 1000 bytes allowed (90%)")
+
+	message = renderNotification(model.NotificationEvent{Type: model.NotificationEventMassDeletion, NamespaceCode: "ns", ProjectCode: "proj", Subject: "alice",
+		MassDeletion: &model.MassDeletion{Operation: model.MassDeletionOperationPublish, Redirects: 80, RedirectsTotal: 100, PagesTotal: 5}})
+	assert.Equal(t, "[Flecto] ns/proj mass deletion", message.Subject)
+	assert.Equal(t, "A publish of project ns/proj by alice deletes 80 of the 100 published redirects and 0 of the 5 published pages. It was refused, it has to be forced to proceed.", message.Body)
+
+	message = renderNotification(model.NotificationEvent{Type: "OTHER", NamespaceCode: "ns", ProjectCode: "proj"})
+	assert.Equal(t, "[Flecto] ns/proj OTHER", message.Subject)
+}
+
+func createNotificationTestDraft(t *testing.T, db *gorm.DB) {
+	redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(false), Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}}
+	require.NoError(t, db.Create(redirect).Error)
+	require.NoError(t, db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &redirect.ID,
+		NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}}).Error)
+}
+
+func TestProjectService_Publish_Notifications(t *testing.T) {
+	newProjectService := func(db *gorm.DB, appCtx *appContext.Context, notifications *notificationService) ProjectService {
+		return NewProjectService(appCtx, repository.NewProjectRepository(db), repository.NewPageRepository(db), repository.NewRedirectDraftRepository(db), repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), notifications.outbox, notifications)
+	}
+
+	t.Run("success with quota warning", func(t *testing.T) {
+		db, appCtx, _, notifications := setupNotificationServiceTest(t)
+		require.NoError(t, db.Create(&model.Page{
+			NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true), ContentSize: 1800,
+			Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/big", Content: "content", ContentType: commonTypes.PageContentTypeTextPlain},
+		}).Error)
+		createNotificationTestDraft(t, db)
+		svc := newProjectService(db, appCtx, notifications)
+
+		_, err := svc.Publish(types.WithSubject(context.Background(), "alice"), "test-ns", "test-proj", types.PublishOptions{})
+		require.NoError(t, err)
+
+		events := notifiedEvents(t, db)
+		require.Len(t, events, 2)
+		assert.Equal(t, model.NotificationEvent{Type: model.NotificationEventPublishSucceeded, NamespaceCode: "test-ns", ProjectCode: "test-proj", Subject: "alice", Version: 2}, events[0])
+		assert.Equal(t, model.NotificationEventQuotaWarning, events[1].Type)
+		assert.Equal(t, int64(1800), events[1].TotalContentSize)
+		assert.Equal(t, int64(2048), events[1].TotalContentSizeLimit)
+
+		// The invalidation and the success are written in the transaction of the publish, then delivered
+		var outboxEvents []model.OutboxEvent
+		require.NoError(t, db.Where("topic IN ?", []model.OutboxTopic{model.OutboxTopicInvalidation, model.OutboxTopicNotification}).Order("id").Find(&outboxEvents).Error)
+		require.Len(t, outboxEvents, 3)
+		assert.Equal(t, model.OutboxTopicInvalidation, outboxEvents[0].Topic)
+		assert.Equal(t, model.OutboxStatusDelivered, outboxEvents[0].Status)
+		assert.Equal(t, model.OutboxStatusDelivered, outboxEvents[1].Status)
+	})
+
+	t.Run("nothing to publish", func(t *testing.T) {
+		db, appCtx, _, notifications := setupNotificationServiceTest(t)
+		svc := newProjectService(db, appCtx, notifications)
+
+		_, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		assert.ErrorIs(t, err, ErrNothingToPublish)
+		assert.Empty(t, notifiedEvents(t, db))
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		db, appCtx, _, notifications := setupNotificationServiceTest(t)
+		createNotificationTestDraft(t, db)
+		require.NoError(t, db.Migrator().DropTable(&model.RedirectHealth{}))
+		svc := newProjectService(db, appCtx, notifications)
+
+		_, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		require.Error(t, err)
+		events := notifiedEvents(t, db)
+		require.Len(t, events, 1)
+		assert.Equal(t, model.NotificationEventPublishFailed, events[0].Type)
+		assert.NotEmpty(t, events[0].Error)
+		var invalidations int64
+		require.NoError(t, db.Model(&model.OutboxEvent{}).Where("topic = ?", model.OutboxTopicInvalidation).Count(&invalidations).Error)
+		assert.Zero(t, invalidations, "the events of a publish rolled back are not written")
+	})
+}
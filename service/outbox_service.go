@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// outboxWorkerTimeout is the time after which the outbox relay without heartbeat is reported as stalled, a round
+// possibly sending a whole batch of notifications
+const outboxWorkerTimeout = 10 * time.Minute
+
+// outboxLastErrorSize is the size of the column keeping the error of the last attempt of an event
+const outboxLastErrorSize = 1000
+
+// OutboxHandler delivers an event of the outbox, an error leading to another attempt later
+type OutboxHandler func(ctx context.Context, event *model.OutboxEvent) error
+
+// OutboxService delivers the events of the outbox to the handlers of their topic. The events are written in the
+// transaction of the change they announce and delivered once it is committed, the relay delivering every
+// outbox.poll_interval those left behind by a failure or a stop of the replica. An event is thus delivered at least
+// once, and once unless its delivery outlasts outbox.lease.
+type OutboxService interface {
+	// Handle sets the handler delivering the events of the topic
+	Handle(topic model.OutboxTopic, handler OutboxHandler)
+	// Add writes the events in tx, the transaction of the change they announce, Dispatch delivering them once committed
+	Add(tx *gorm.DB, events ...*model.OutboxEvent) error
+	// Publish writes the events in their own transaction and delivers them
+	Publish(ctx context.Context, events ...*model.OutboxEvent) error
+	// Dispatch delivers the committed events, those failing being attempted again by the relay
+	Dispatch(ctx context.Context, events ...*model.OutboxEvent)
+	// Relay delivers a batch of the pending events of the database of ctx available at now, and returns how many
+	// events it took
+	Relay(ctx context.Context, now time.Time) (int, error)
+	// StartWorker runs the relay on every database until the application context is done
+	StartWorker()
+}
+
+type outboxService struct {
+	ctx  *appContext.Context
+	repo repository.OutboxRepository
+	now  func() time.Time
+
+	mu       sync.RWMutex
+	handlers map[model.OutboxTopic]OutboxHandler
+}
+
+// NewOutboxService returns the outbox delivering the invalidation events to bus, the other topics being handled by
+// the services writing them
+func NewOutboxService(ctx *appContext.Context, repo repository.OutboxRepository, bus invalidation.Bus) OutboxService {
+	s := &outboxService{
+		ctx:      ctx,
+		repo:     repo,
+		now:      time.Now,
+		handlers: make(map[model.OutboxTopic]OutboxHandler),
+	}
+	s.Handle(model.OutboxTopicInvalidation, func(ctx context.Context, event *model.OutboxEvent) error {
+		var invalidationEvent invalidation.Event
+		if err := event.Decode(&invalidationEvent); err != nil {
+			return err
+		}
+		return bus.Publish(ctx, invalidationEvent)
+	})
+	return s
+}
+
+// NewInvalidationOutboxEvent returns the outbox event broadcasting an invalidation event to the replicas
+func NewInvalidationOutboxEvent(event invalidation.Event) (*model.OutboxEvent, error) {
+	return model.NewOutboxEvent(model.OutboxTopicInvalidation, event.NamespaceCode, event.ProjectCode, event)
+}
+
+func (s *outboxService) Handle(topic model.OutboxTopic, handler OutboxHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[topic] = handler
+}
+
+// Add writes the events one by one, an event whose dedup key was already written being skipped and keeping a zero ID
+func (s *outboxService) Add(tx *gorm.DB, events ...*model.OutboxEvent) error {
+	for _, event := range events {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			event.ID = 0
+		}
+	}
+	return nil
+}
+
+func (s *outboxService) Publish(ctx context.Context, events ...*model.OutboxEvent) error {
+	if err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.Add(tx, events...)
+	}); err != nil {
+		return err
+	}
+	s.Dispatch(ctx, events...)
+	return nil
+}
+
+func (s *outboxService) Dispatch(ctx context.Context, events ...*model.OutboxEvent) {
+	lease := s.ctx.Config.Outbox.Lease
+	for _, event := range events {
+		// An event skipped by the dedup key of another one has no ID
+		if event.ID == 0 {
+			continue
+		}
+		now := s.now()
+		claimed, err := s.repo.ClaimByID(ctx, event.ID, now, lease)
+		if err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "failed to claim outbox event", "id", event.ID, "topic", event.Topic, "error", err)
+			continue
+		}
+		if claimed {
+			s.deliver(ctx, event, now)
+		}
+	}
+}
+
+func (s *outboxService) Relay(ctx context.Context, now time.Time) (int, error) {
+	cfg := s.ctx.Config.Outbox
+	events, err := s.repo.Claim(ctx, now, cfg.Lease, cfg.BatchSize)
+	for i := range events {
+		s.deliver(ctx, &events[i], now)
+	}
+	return len(events), err
+}
+
+// deliver hands a claimed event to the handler of its topic, then marks it as delivered, or schedules another attempt
+// after an exponential backoff, the event being given up after outbox.max_attempts attempts
+func (s *outboxService) deliver(ctx context.Context, event *model.OutboxEvent, now time.Time) {
+	s.mu.RLock()
+	handler, ok := s.handlers[event.Topic]
+	s.mu.RUnlock()
+
+	var err error
+	if ok {
+		err = handler(ctx, event)
+	} else {
+		err = fmt.Errorf("no handler for outbox topic %s", event.Topic)
+	}
+	if err == nil {
+		if err = s.repo.MarkDelivered(ctx, event.ID, s.now()); err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "failed to mark outbox event as delivered", "id", event.ID, "topic", event.Topic, "error", err)
+		}
+		return
+	}
+
+	cfg := s.ctx.Config.Outbox
+	event.Attempts++
+	event.LastError = err.Error()
+	if len(event.LastError) > outboxLastErrorSize {
+		event.LastError = event.LastError[:outboxLastErrorSize]
+	}
+	if event.Attempts >= cfg.MaxAttempts {
+		processedAt := now
+		event.Status = model.OutboxStatusFailed
+		event.ProcessedAt = &processedAt
+		s.ctx.Logger.ErrorContext(ctx, "outbox event given up", "id", event.ID, "topic", event.Topic, "namespace", event.NamespaceCode,
+			"project", event.ProjectCode, "attempts", event.Attempts, "error", err)
+	} else {
+		delay := cfg.RetryDelay
+		for i := 1; i < event.Attempts && delay < cfg.MaxRetryDelay; i++ {
+			delay *= 2
+		}
+		delay = min(delay, cfg.MaxRetryDelay)
+		event.AvailableAt = now.Add(delay)
+		s.ctx.Logger.WarnContext(ctx, "outbox event delivery failed", "id", event.ID, "topic", event.Topic, "namespace", event.NamespaceCode,
+			"project", event.ProjectCode, "attempts", event.Attempts, "retryIn", delay, "error", err)
+	}
+	if errMark := s.repo.MarkFailed(ctx, event); errMark != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to save outbox event attempt", "id", event.ID, "topic", event.Topic, "error", errMark)
+	}
+}
+
+// StartWorker relays the pending events of every database at each poll, a database being relayed until it has no
+// more events available. The relay pauses while the manager is in read-only mode.
+func (s *outboxService) StartWorker() {
+	heartbeat := s.ctx.Workers.Register("outbox", outboxWorkerTimeout)
+	go func() {
+		ticker := time.NewTicker(s.ctx.Config.Outbox.PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if !s.ctx.CurrentConfig().ReadOnly.Enabled {
+				s.relayAll(context.Background())
+			}
+			heartbeat.Beat()
+		}
+	}()
+}
+
+func (s *outboxService) relayAll(ctx context.Context) {
+	batchSize := s.ctx.Config.Outbox.BatchSize
+	for _, shardCtx := range database.ShardContexts(s.repo.GetTx(ctx), ctx) {
+		for {
+			count, err := s.Relay(shardCtx, s.now())
+			if err != nil {
+				s.ctx.Logger.ErrorContext(shardCtx, "outbox relay failed", "error", err)
+				break
+			}
+			if count < batchSize {
+				break
+			}
+		}
+	}
+}
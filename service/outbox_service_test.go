@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupOutboxServiceTest(t *testing.T) (*gorm.DB, *appContext.Context, invalidation.Bus, *outboxService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.OutboxEvent{}))
+
+	ctx := appContext.TestContext(nil)
+	ctx.Config.Outbox.MaxAttempts = 3
+	ctx.Config.Outbox.RetryDelay = time.Second
+	ctx.Config.Outbox.MaxRetryDelay = 3 * time.Second
+	bus := invalidation.NewMemoryBus()
+	return db, ctx, bus, NewOutboxService(ctx, repository.NewOutboxRepository(db), bus).(*outboxService)
+}
+
+func findOutboxEvent(t *testing.T, db *gorm.DB, id int64) model.OutboxEvent {
+	var event model.OutboxEvent
+	require.NoError(t, db.First(&event, id).Error)
+	return event
+}
+
+func TestOutboxService_Publish(t *testing.T) {
+	db, _, bus, svc := setupOutboxServiceTest(t)
+	var received []invalidation.Event
+	bus.Subscribe(func(event invalidation.Event) { received = append(received, event) })
+	event := invalidation.Event{Type: invalidation.EventTypeProjectPublished, NamespaceCode: "ns", ProjectCode: "proj", Version: 2}
+	outboxEvent, err := NewInvalidationOutboxEvent(event)
+	require.NoError(t, err)
+
+	require.NoError(t, svc.Publish(context.Background(), outboxEvent))
+
+	assert.Equal(t, []invalidation.Event{event}, received)
+	stored := findOutboxEvent(t, db, outboxEvent.ID)
+	assert.Equal(t, model.OutboxStatusDelivered, stored.Status)
+	assert.Equal(t, "ns", stored.NamespaceCode)
+	assert.NotNil(t, stored.ProcessedAt)
+}
+
+func TestOutboxService_Add(t *testing.T) {
+	db, _, _, svc := setupOutboxServiceTest(t)
+	newEvent := func() *model.OutboxEvent {
+		event, err := model.NewOutboxEvent(model.OutboxTopicNotificationDelivery, "ns", "proj", "payload")
+		require.NoError(t, err)
+		event.DedupKey = types.Ptr("1:1")
+		return event
+	}
+
+	first, second := newEvent(), newEvent()
+	require.NoError(t, svc.Add(db, first))
+	require.NoError(t, svc.Add(db, second))
+
+	assert.NotZero(t, first.ID)
+	assert.Zero(t, second.ID, "the event with the same dedup key is skipped")
+	var count int64
+	require.NoError(t, db.Model(&model.OutboxEvent{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestOutboxService_Relay(t *testing.T) {
+	t.Run("retried then given up", func(t *testing.T) {
+		db, _, _, svc := setupOutboxServiceTest(t)
+		var attempts int
+		svc.Handle(model.OutboxTopicNotification, func(ctx context.Context, event *model.OutboxEvent) error {
+			attempts++
+			return errors.New("unavailable")
+		})
+		event, err := model.NewOutboxEvent(model.OutboxTopicNotification, "ns", "proj", "payload")
+		require.NoError(t, err)
+		require.NoError(t, svc.Add(db, event))
+		now := event.AvailableAt
+
+		count, err := svc.Relay(context.Background(), now)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+		stored := findOutboxEvent(t, db, event.ID)
+		assert.Equal(t, model.OutboxStatusPending, stored.Status)
+		assert.Equal(t, 1, stored.Attempts)
+		assert.Equal(t, "unavailable", stored.LastError)
+		assert.WithinDuration(t, now.Add(time.Second), stored.AvailableAt, time.Millisecond)
+		assert.Nil(t, stored.LockedUntil)
+
+		// Not available before the end of its backoff
+		count, err = svc.Relay(context.Background(), now)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+
+		now = stored.AvailableAt
+		_, err = svc.Relay(context.Background(), now)
+		require.NoError(t, err)
+		stored = findOutboxEvent(t, db, event.ID)
+		assert.WithinDuration(t, now.Add(2*time.Second), stored.AvailableAt, time.Millisecond)
+
+		now = stored.AvailableAt
+		_, err = svc.Relay(context.Background(), now)
+		require.NoError(t, err)
+		stored = findOutboxEvent(t, db, event.ID)
+		assert.Equal(t, model.OutboxStatusFailed, stored.Status)
+		assert.Equal(t, 3, stored.Attempts)
+		assert.NotNil(t, stored.ProcessedAt)
+		assert.Equal(t, 3, attempts)
+
+		count, err = svc.Relay(context.Background(), now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+
+	t.Run("without handler", func(t *testing.T) {
+		db, _, _, svc := setupOutboxServiceTest(t)
+		event, err := model.NewOutboxEvent("UNKNOWN", "ns", "proj", "payload")
+		require.NoError(t, err)
+		require.NoError(t, svc.Add(db, event))
+
+		_, err = svc.Relay(context.Background(), event.AvailableAt)
+		require.NoError(t, err)
+		stored := findOutboxEvent(t, db, event.ID)
+		assert.Equal(t, 1, stored.Attempts)
+		assert.Contains(t, stored.LastError, "no handler for outbox topic UNKNOWN")
+	})
+}
+
+func TestOutboxService_Dispatch(t *testing.T) {
+	db, _, bus, svc := setupOutboxServiceTest(t)
+	var received int
+	bus.Subscribe(func(event invalidation.Event) { received++ })
+	event, err := NewInvalidationOutboxEvent(invalidation.Event{Type: invalidation.EventTypeProjectDeleted, NamespaceCode: "ns", ProjectCode: "proj"})
+	require.NoError(t, err)
+	require.NoError(t, svc.Add(db, event))
+
+	// The event taken by the relay of another replica is not delivered twice
+	_, err = svc.repo.ClaimByID(context.Background(), event.ID, time.Now(), time.Minute)
+	require.NoError(t, err)
+	svc.Dispatch(context.Background(), event)
+	assert.Zero(t, received)
+
+	require.NoError(t, db.Model(event).UpdateColumn("locked_until", nil).Error)
+	svc.Dispatch(context.Background(), event)
+	assert.Equal(t, 1, received)
+	svc.Dispatch(context.Background(), event)
+	assert.Equal(t, 1, received, "a delivered event is not delivered again")
+}
+
+func TestOutboxService_StartWorker(t *testing.T) {
+	db, ctx, bus, svc := setupOutboxServiceTest(t)
+	ctx.Config.Outbox.PollInterval = 10 * time.Millisecond
+	defer ctx.Cancel()
+	received := make(chan invalidation.Event, 1)
+	bus.Subscribe(func(event invalidation.Event) { received <- event })
+	event, err := NewInvalidationOutboxEvent(invalidation.Event{Type: invalidation.EventTypeProjectDeleted, NamespaceCode: "ns", ProjectCode: "proj"})
+	require.NoError(t, err)
+	require.NoError(t, svc.Add(db, event))
+
+	svc.StartWorker()
+
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("the pending event was not relayed")
+	}
+	workers := ctx.Workers.Workers()
+	require.Len(t, workers, 1)
+	assert.Equal(t, "outbox", workers[0].Name)
+}
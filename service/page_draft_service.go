@@ -2,11 +2,17 @@ package service
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/markdown"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/types"
@@ -14,9 +20,9 @@ import (
 )
 
 var (
-	ErrPathAlreadyUsed       = errors.New("path is already used in this project")
-	ErrContentSizeExceeded   = errors.New("content size exceeds the maximum allowed size")
-	ErrTotalSizeLimitReached = errors.New("total content size limit for the project would be exceeded")
+	ErrPathAlreadyUsed       = flectoErrors.New(flectoErrors.CodeAlreadyExists, "path is already used in this project")
+	ErrContentSizeExceeded   = flectoErrors.New(flectoErrors.CodeSizeLimitExceeded, "content size exceeds the maximum allowed size")
+	ErrTotalSizeLimitReached = flectoErrors.New(flectoErrors.CodeSizeLimitExceeded, "total content size limit for the project would be exceeded")
 )
 
 type PageDraftService interface {
@@ -28,25 +34,31 @@ type PageDraftService interface {
 	Update(ctx context.Context, id int64, newPage *commonTypes.Page) (*model.PageDraft, error)
 	Delete(ctx context.Context, id int64) (bool, error)
 	Rollback(ctx context.Context, namespaceCode, projectCode string) (bool, error)
+	GetDeleted(ctx context.Context, namespaceCode, projectCode string, pagination *commonTypes.PaginationInput) (*model.PageTombstoneList, error)
+	RestoreDeleted(ctx context.Context, namespaceCode, projectCode string, pageID int64) (*model.PageDraft, error)
 	Search(ctx context.Context, query *gorm.DB) ([]model.PageDraft, error)
 	SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.PageDraftList, error)
+	SearchCursor(ctx context.Context, cursor *commonTypes.CursorInput, query *gorm.DB) (*model.PageDraftCursorList, error)
 }
 
 type pageDraftService struct {
 	ctx      *appContext.Context
 	repo     repository.PageDraftRepository
 	pageRepo repository.PageRepository
+	lintSrv  PageLintService
 }
 
 func NewPageDraftService(
 	ctx *appContext.Context,
 	repo repository.PageDraftRepository,
 	pageRepo repository.PageRepository,
+	lintSrv PageLintService,
 ) PageDraftService {
 	return &pageDraftService{
 		ctx:      ctx,
 		repo:     repo,
 		pageRepo: pageRepo,
+		lintSrv:  lintSrv,
 	}
 }
 
@@ -68,7 +80,7 @@ func (s *pageDraftService) GetByIDWithProject(ctx context.Context, namespaceCode
 
 func (s *pageDraftService) Create(ctx context.Context, namespaceCode, projectCode string, oldPageID *int64, newPage *commonTypes.Page) (*model.PageDraft, error) {
 	if oldPageID == nil && newPage == nil {
-		return nil, fmt.Errorf("oldPageID or newPage must be provided")
+		return nil, flectoErrors.New(flectoErrors.CodeInvalidRequest, "oldPageID or newPage must be provided")
 	}
 
 	pageDraft := &model.PageDraft{
@@ -81,14 +93,29 @@ func (s *pageDraftService) Create(ctx context.Context, namespaceCode, projectCod
 		pageDraft.OldPageID = oldPageID
 		pageDraft.ChangeType = model.DraftChangeTypeUpdate
 	}
+	if newPage == nil {
+		pageDraft.ChangeType = model.DraftChangeTypeDelete
+	}
+
+	if err := runPreDraftSaveHooks(ctx, s.ctx, namespaceCode, projectCode, pageDraft.ChangeType, nil, newPage); err != nil {
+		return nil, err
+	}
 
 	if newPage != nil {
 		pageDraft.NewPage = newPage
-		contentSize := int64(len(newPage.Content))
+		contentSize, err := preparePage(s.ctx.CurrentConfig().Page.Markdown, newPage)
+		if err != nil {
+			return nil, err
+		}
 		pageDraft.ContentSize = contentSize
+		secretWarnings, err := checkPageSecrets(s.ctx.CurrentConfig().Page.Secrets, newPage)
+		if err != nil {
+			return nil, err
+		}
+		pageDraft.LintWarnings = append(s.lintSrv.Lint(newPage), secretWarnings...)
 
 		// Check content size limit
-		if contentSize > int64(s.ctx.Config.Page.SizeLimit) {
+		if contentSize > int64(s.ctx.CurrentConfig().Page.SizeLimit) {
 			return nil, ErrContentSizeExceeded
 		}
 
@@ -105,8 +132,6 @@ func (s *pageDraftService) Create(ctx context.Context, namespaceCode, projectCod
 		if err := s.checkTotalSizeLimit(ctx, namespaceCode, projectCode, contentSize); err != nil {
 			return nil, err
 		}
-	} else {
-		pageDraft.ChangeType = model.DraftChangeTypeDelete
 	}
 
 	if pageDraft.ChangeType != model.DraftChangeTypeDelete {
@@ -143,7 +168,7 @@ func (s *pageDraftService) Create(ctx context.Context, namespaceCode, projectCod
 
 func (s *pageDraftService) Update(ctx context.Context, id int64, newPage *commonTypes.Page) (*model.PageDraft, error) {
 	if newPage == nil {
-		return nil, fmt.Errorf("newPage must be provided")
+		return nil, flectoErrors.New(flectoErrors.CodeInvalidRequest, "newPage must be provided")
 	}
 
 	draft, err := s.repo.FindByID(ctx, id)
@@ -152,7 +177,11 @@ func (s *pageDraftService) Update(ctx context.Context, id int64, newPage *common
 	}
 
 	if draft.ChangeType == model.DraftChangeTypeDelete {
-		return nil, fmt.Errorf("cannot update a delete draft")
+		return nil, flectoErrors.New(flectoErrors.CodeConflict, "cannot update a delete draft")
+	}
+
+	if err = runPreDraftSaveHooks(ctx, s.ctx, draft.NamespaceCode, draft.ProjectCode, draft.ChangeType, nil, newPage); err != nil {
+		return nil, err
 	}
 
 	errValidate := s.ctx.Validator.Struct(newPage)
@@ -160,10 +189,17 @@ func (s *pageDraftService) Update(ctx context.Context, id int64, newPage *common
 		return nil, errValidate
 	}
 
-	contentSize := int64(len(newPage.Content))
+	contentSize, err := preparePage(s.ctx.CurrentConfig().Page.Markdown, newPage)
+	if err != nil {
+		return nil, err
+	}
+	secretWarnings, err := checkPageSecrets(s.ctx.CurrentConfig().Page.Secrets, newPage)
+	if err != nil {
+		return nil, err
+	}
 
 	// Check content size limit
-	if contentSize > int64(s.ctx.Config.Page.SizeLimit) {
+	if contentSize > int64(s.ctx.CurrentConfig().Page.SizeLimit) {
 		return nil, ErrContentSizeExceeded
 	}
 
@@ -189,6 +225,7 @@ func (s *pageDraftService) Update(ctx context.Context, id int64, newPage *common
 
 	draft.NewPage = newPage
 	draft.ContentSize = contentSize
+	draft.LintWarnings = append(s.lintSrv.Lint(newPage), secretWarnings...)
 
 	if err = s.repo.Update(ctx, draft); err != nil {
 		return nil, err
@@ -222,7 +259,7 @@ func (s *pageDraftService) Delete(ctx context.Context, id int64) (bool, error) {
 }
 
 func (s *pageDraftService) Rollback(ctx context.Context, namespaceCode, projectCode string) (bool, error) {
-	s.ctx.Logger.Info("page drafts rollback started", "namespace", namespaceCode, "project", projectCode)
+	s.ctx.Logger.InfoContext(ctx, "page drafts rollback started", "namespace", namespaceCode, "project", projectCode)
 
 	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
@@ -238,20 +275,46 @@ func (s *pageDraftService) Rollback(ctx context.Context, namespaceCode, projectC
 		return nil
 	})
 	if err != nil {
-		s.ctx.Logger.Error("page drafts rollback failed", "namespace", namespaceCode, "project", projectCode, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "page drafts rollback failed", "namespace", namespaceCode, "project", projectCode, "error", err)
 		return false, err
 	}
 
-	s.ctx.Logger.Info("page drafts rollback completed", "namespace", namespaceCode, "project", projectCode)
+	s.ctx.Logger.InfoContext(ctx, "page drafts rollback completed", "namespace", namespaceCode, "project", projectCode)
 	return true, nil
 }
 
+// GetDeleted returns the tombstones of the pages of the project deleted by the publishes, the latest first
+func (s *pageDraftService) GetDeleted(ctx context.Context, namespaceCode, projectCode string, pagination *commonTypes.PaginationInput) (*model.PageTombstoneList, error) {
+	tombstones, total, err := s.repo.SearchTombstones(ctx, namespaceCode, projectCode, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.PageTombstoneList{
+		Total:  int(total),
+		Offset: pagination.GetOffset(),
+		Limit:  pagination.GetLimit(),
+		Items:  tombstones,
+	}, nil
+}
+
+// RestoreDeleted stages a create draft recreating a page deleted by a publish, from its tombstone.
+// The tombstone is kept until the project is deleted, so a discarded draft can be staged again.
+func (s *pageDraftService) RestoreDeleted(ctx context.Context, namespaceCode, projectCode string, pageID int64) (*model.PageDraft, error) {
+	tombstone, err := s.repo.FindTombstone(ctx, namespaceCode, projectCode, pageID)
+	if err != nil {
+		return nil, err
+	}
+	restored := *tombstone.Page
+	return s.Create(ctx, namespaceCode, projectCode, nil, &restored)
+}
+
 func (s *pageDraftService) Search(ctx context.Context, query *gorm.DB) ([]model.PageDraft, error) {
 	return s.repo.Search(ctx, query)
 }
 
 func (s *pageDraftService) SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.PageDraftList, error) {
-	drafts, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	drafts, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
@@ -264,6 +327,64 @@ func (s *pageDraftService) SearchPaginate(ctx context.Context, pagination *commo
 	}, nil
 }
 
+func (s *pageDraftService) SearchCursor(ctx context.Context, cursor *commonTypes.CursorInput, query *gorm.DB) (*model.PageDraftCursorList, error) {
+	afterID, err := cursor.GetAfterID()
+	if err != nil {
+		return nil, err
+	}
+
+	drafts, hasMore, err := s.repo.SearchCursor(ctx, query, afterID, cursor.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PageDraftCursorList{Items: drafts}
+	if hasMore {
+		nextCursor := commonTypes.EncodeCursor(drafts[len(drafts)-1].ID)
+		result.NextCursor = &nextCursor
+	}
+	return result, nil
+}
+
+// preparePage normalizes the headers of a page, renders the content of a markdown page from its source and detects
+// the mime type of a binary page when it is not provided, and returns the size of the page body
+func preparePage(cfg config.PageMarkdownConfig, page *commonTypes.Page) (int64, error) {
+	page.Headers = commonTypes.NormalizeResponseHeaders(page.Headers)
+	if page.ContentType == commonTypes.PageContentTypeMarkdown {
+		content, err := markdown.Render(cfg, page.Source)
+		if err != nil {
+			return 0, err
+		}
+		page.Content = content
+	} else {
+		page.Source = ""
+	}
+	if !page.IsBinary() {
+		page.MimeType = ""
+		return int64(len(page.Content)), nil
+	}
+	if page.MimeType == "" {
+		page.MimeType = detectMimeType(page)
+	}
+	return page.BodySize(), nil
+}
+
+// detectMimeType sniffs the mime type of a binary page body, the path extension being used
+// when sniffing only finds generic binary or text content, like for svg images
+func detectMimeType(page *commonTypes.Page) string {
+	body, err := page.Body()
+	if err != nil {
+		return ""
+	}
+	mimeType := http.DetectContentType(body)
+	if mimeType == "application/octet-stream" || strings.HasPrefix(mimeType, "text/") {
+		if extensionType := mime.TypeByExtension(path.Ext(page.Path)); extensionType != "" {
+			return extensionType
+		}
+	}
+	return mimeType
+}
+
 // checkTotalSizeLimit checks if adding a new page with the given content size would exceed the total limit
 func (s *pageDraftService) checkTotalSizeLimit(ctx context.Context, namespaceCode, projectCode string, newContentSize int64) error {
 	currentTotal, err := s.pageRepo.GetTotalContentSize(ctx, namespaceCode, projectCode)
@@ -271,7 +392,7 @@ func (s *pageDraftService) checkTotalSizeLimit(ctx context.Context, namespaceCod
 		return err
 	}
 
-	if currentTotal+newContentSize > int64(s.ctx.Config.Page.TotalSizeLimit) {
+	if currentTotal+newContentSize > int64(s.ctx.CurrentConfig().Page.TotalSizeLimit) {
 		return ErrTotalSizeLimitReached
 	}
 
@@ -285,7 +406,7 @@ func (s *pageDraftService) checkTotalSizeLimitDiff(ctx context.Context, namespac
 		return err
 	}
 
-	if currentTotal+sizeDiff > int64(s.ctx.Config.Page.TotalSizeLimit) {
+	if currentTotal+sizeDiff > int64(s.ctx.CurrentConfig().Page.TotalSizeLimit) {
 		return ErrTotalSizeLimitReached
 	}
 
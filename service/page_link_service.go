@@ -0,0 +1,417 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"golang.org/x/net/html"
+)
+
+const (
+	pageLinkBatchSize = 100
+	// PageLinkDraftTag tags the redirect drafts created for the broken links
+	PageLinkDraftTag  = "broken-link"
+	maxPageLinkLength = 2048
+)
+
+type PageLinkService interface {
+	CheckAll(ctx context.Context) (int, error)
+	CheckProject(ctx context.Context, project model.Project) ([]model.PageBrokenLink, error)
+	GetReport(ctx context.Context, namespaceCode, projectCode string) (*model.PageLinkReport, error)
+	ScheduledTask() types.ScheduledTask
+}
+
+type pageLinkService struct {
+	ctx                  *appContext.Context
+	projectRepo          repository.ProjectRepository
+	pageRepo             repository.PageRepository
+	redirectRepo         repository.RedirectRepository
+	linkRepo             repository.PageLinkRepository
+	redirectDraftService RedirectDraftService
+}
+
+func NewPageLinkService(
+	ctx *appContext.Context,
+	projectRepo repository.ProjectRepository,
+	pageRepo repository.PageRepository,
+	redirectRepo repository.RedirectRepository,
+	linkRepo repository.PageLinkRepository,
+	redirectDraftService RedirectDraftService,
+) PageLinkService {
+	return &pageLinkService{
+		ctx:                  ctx,
+		projectRepo:          projectRepo,
+		pageRepo:             pageRepo,
+		redirectRepo:         redirectRepo,
+		linkRepo:             linkRepo,
+		redirectDraftService: redirectDraftService,
+	}
+}
+
+// ScheduledTask scans the pages of all projects of all the shards, at the configured interval by default.
+// The task is disabled when the link check is disabled.
+func (s *pageLinkService) ScheduledTask() types.ScheduledTask {
+	return types.ScheduledTask{
+		Name:     "page_link_check",
+		Interval: s.ctx.Config.LinkCheck.Interval,
+		Enabled:  s.ctx.Config.LinkCheck.Enabled,
+		Run: func(ctx context.Context, now time.Time) error {
+			var errs []error
+			for _, ctx := range database.ShardContexts(s.projectRepo.GetTx(ctx), ctx) {
+				_, err := s.CheckAll(ctx)
+				errs = append(errs, err)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// CheckAll scans the published pages of all projects and returns the number of broken links found
+func (s *pageLinkService) CheckAll(ctx context.Context) (int, error) {
+	s.ctx.Logger.InfoContext(ctx, "page link check started")
+	broken := 0
+	lastID := int64(0)
+	for {
+		var projects []model.Project
+		err := s.projectRepo.GetTx(ctx).
+			Where("id > ?", lastID).
+			Order("id").
+			Limit(pageLinkBatchSize).
+			Find(&projects).Error
+		if err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "page link check failed", "error", err)
+			return broken, err
+		}
+		if len(projects) == 0 {
+			break
+		}
+		lastID = projects[len(projects)-1].ID
+
+		for _, project := range projects {
+			links, errCheck := s.CheckProject(ctx, project)
+			if errCheck != nil {
+				s.ctx.Logger.ErrorContext(ctx, "page link check failed", "error", errCheck, "namespace", project.NamespaceCode, "project", project.ProjectCode)
+				return broken, errCheck
+			}
+			broken += len(links)
+		}
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "page link check completed", "broken", broken)
+	return broken, nil
+}
+
+// CheckProject scans the published HTML pages of a project for internal links matching neither a published page nor
+// a published redirect, the catch-all excepted, and replaces the broken links of the project by the ones found
+func (s *pageLinkService) CheckProject(ctx context.Context, project model.Project) ([]model.PageBrokenLink, error) {
+	var pages []model.Page
+	err := s.pageRepo.GetTx(ctx).
+		Where("namespace_code = ? AND project_code = ? AND is_published = ?", project.NamespaceCode, project.ProjectCode, true).
+		Order("id").
+		Find(&pages).Error
+	if err != nil {
+		return nil, err
+	}
+	var redirects []model.Redirect
+	err = s.redirectRepo.GetTx(ctx).
+		Where("namespace_code = ? AND project_code = ? AND is_published = ?", project.NamespaceCode, project.ProjectCode, true).
+		Order("priority DESC, id").
+		Find(&redirects).Error
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.linkRepo.FindByProject(ctx, project.NamespaceCode, project.ProjectCode)
+	if err != nil {
+		return nil, err
+	}
+	drafted := make(map[string]bool)
+	for _, link := range previous {
+		if link.DraftCreated {
+			drafted[link.URL] = true
+		}
+	}
+
+	checker := newPageLinkChecker(pages, redirects, project.RedirectOptions)
+	links := make([]model.PageBrokenLink, 0)
+	checkedAt := time.Now()
+	for _, page := range pages {
+		if page.Page == nil || !isHTMLPage(*page.Page) {
+			continue
+		}
+		body, errBody := page.Body()
+		if errBody != nil {
+			continue
+		}
+		for _, broken := range checker.brokenLinks(*page.Page, body) {
+			link := model.PageBrokenLink{
+				NamespaceCode:   project.NamespaceCode,
+				ProjectCode:     project.ProjectCode,
+				PageID:          page.ID,
+				PagePath:        page.Path,
+				Link:            truncateLink(broken.link),
+				URL:             truncateLink(broken.url),
+				SuggestedTarget: truncateLink(broken.suggestion),
+				CheckedAt:       checkedAt,
+			}
+			if link.SuggestedTarget != "" && s.ctx.Config.LinkCheck.SuggestDrafts && !drafted[link.URL] {
+				drafted[link.URL] = s.createDraft(ctx, project, broken)
+			}
+			link.DraftCreated = drafted[link.URL]
+			links = append(links, link)
+		}
+	}
+
+	if err = s.linkRepo.ReplaceForProject(ctx, project.NamespaceCode, project.ProjectCode, links); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+func (s *pageLinkService) GetReport(ctx context.Context, namespaceCode, projectCode string) (*model.PageLinkReport, error) {
+	links, err := s.linkRepo.FindByProject(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewPageLinkReport(links), nil
+}
+
+// createDraft creates a redirect draft from the broken link to its suggested target, and returns true when
+// the draft was created or a draft for the link already exists
+func (s *pageLinkService) createDraft(ctx context.Context, project model.Project, broken brokenPageLink) bool {
+	redirect := &commonTypes.Redirect{
+		Type:   commonTypes.RedirectTypeBasic,
+		Source: broken.uri,
+		Target: broken.suggestion,
+		Status: commonTypes.RedirectStatusMovedPermanent,
+	}
+	if broken.host != "" {
+		redirect.Type = commonTypes.RedirectTypeBasicHost
+		redirect.Source = broken.host + broken.uri
+	}
+	_, err := s.redirectDraftService.Create(ctx, project.NamespaceCode, project.ProjectCode, nil, redirect, []string{PageLinkDraftTag})
+	if errors.Is(err, ErrSourceAlreadyUsed) {
+		return true
+	}
+	if err != nil {
+		s.ctx.Logger.WarnContext(ctx, "page link draft not created", "error", err, "namespace", project.NamespaceCode, "project", project.ProjectCode, "source", redirect.Source)
+		return false
+	}
+	return true
+}
+
+// isHTMLPage returns true for the markdown pages and the binary pages served as HTML
+func isHTMLPage(page commonTypes.Page) bool {
+	if page.ContentType == commonTypes.PageContentTypeMarkdown {
+		return true
+	}
+	if !page.IsBinary() {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(page.MimeType)
+	return err == nil && (mediaType == "text/html" || mediaType == "application/xhtml+xml")
+}
+
+func truncateLink(link string) string {
+	if len(link) > maxPageLinkLength {
+		return link[:maxPageLinkLength]
+	}
+	return link
+}
+
+// brokenPageLink is a link of a page matching no page or redirect, the host being empty for the links of BASIC pages
+type brokenPageLink struct {
+	link       string
+	url        string
+	host       string
+	uri        string
+	suggestion string
+}
+
+// pageLinkChecker matches the links of the pages of a project against its published pages and redirects
+type pageLinkChecker struct {
+	pages     commonTypes.PageTreeMatcher
+	redirects commonTypes.RedirectTreeMatcher
+	// hosts are the hosts of the BASIC_HOST pages and redirects, the absolute links to other hosts are external
+	hosts map[string]bool
+	// paths are the paths of the pages by host, the empty host holding the BASIC pages
+	paths map[string][]string
+}
+
+func newPageLinkChecker(pages []model.Page, redirects []model.Redirect, options commonTypes.RedirectOptions) *pageLinkChecker {
+	checker := &pageLinkChecker{
+		pages:     commonTypes.NewPageTreeMatcher(),
+		redirects: commonTypes.NewRedirectTreeMatcherWithOptions(options),
+		hosts:     make(map[string]bool),
+		paths:     make(map[string][]string),
+	}
+	for _, page := range pages {
+		if page.Page == nil {
+			continue
+		}
+		checker.pages.Insert(page.Page)
+		host, pagePath := "", page.Path
+		if page.Type == commonTypes.PageTypeBasicHost {
+			host, pagePath = splitHostPath(page.Path)
+			checker.hosts[host] = true
+		}
+		checker.paths[host] = append(checker.paths[host], pagePath)
+	}
+	for _, redirect := range redirects {
+		// The links only matching the catch-all are broken, it is the fallback of the missing paths
+		if redirect.Redirect == nil || redirect.Type == commonTypes.RedirectTypeCatchAll {
+			continue
+		}
+		if err := checker.redirects.Insert(redirect.Redirect); err != nil {
+			continue
+		}
+		if redirect.Type == commonTypes.RedirectTypeBasicHost {
+			host, _ := splitHostPath(redirect.Source)
+			checker.hosts[host] = true
+		}
+	}
+	for host := range checker.paths {
+		sort.Strings(checker.paths[host])
+	}
+	return checker
+}
+
+// brokenLinks returns the internal links of an HTML page body matching no page or redirect, each URL once
+func (c *pageLinkChecker) brokenLinks(page commonTypes.Page, body []byte) []brokenPageLink {
+	base := &url.URL{Path: page.Path}
+	if page.Type == commonTypes.PageTypeBasicHost {
+		host, pagePath := splitHostPath(page.Path)
+		base = &url.URL{Host: host, Path: pagePath}
+	}
+
+	seen := make(map[string]bool)
+	broken := make([]brokenPageLink, 0)
+	for _, link := range extractHTMLLinks(body) {
+		target, ok := c.resolve(base, link)
+		if !ok {
+			continue
+		}
+		host, uri := target.Host, target.RequestURI()
+		if seen[host+uri] {
+			continue
+		}
+		seen[host+uri] = true
+		if c.exists(host, target.Path, uri) {
+			continue
+		}
+		broken = append(broken, brokenPageLink{
+			link:       link,
+			url:        host + uri,
+			host:       host,
+			uri:        uri,
+			suggestion: c.suggest(host, target.Path),
+		})
+	}
+	return broken
+}
+
+// resolve returns the URL of an internal link relative to the page URL. Links to other schemes or hosts, and the
+// links to a fragment of the page itself, are not internal.
+func (c *pageLinkChecker) resolve(base *url.URL, link string) (*url.URL, bool) {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.HasPrefix(link, "#") {
+		return nil, false
+	}
+	ref, err := url.Parse(link)
+	if err != nil {
+		return nil, false
+	}
+	if ref.Scheme != "" && ref.Scheme != "http" && ref.Scheme != "https" {
+		return nil, false
+	}
+	if ref.Host != "" && ref.Host != base.Host && !c.hosts[ref.Host] {
+		return nil, false
+	}
+	target := base.ResolveReference(ref)
+	target.Scheme = ""
+	target.Fragment = ""
+	return target, true
+}
+
+// exists returns true when a page or a redirect, other than the catch-all, matches the request
+func (c *pageLinkChecker) exists(host, linkPath, uri string) bool {
+	if c.pages.Match(host, linkPath) != nil || c.pages.Match(host, uri) != nil {
+		return true
+	}
+	redirect, _ := c.redirects.Match(host, uri)
+	return redirect != nil
+}
+
+// suggest returns an existing page path the link could be redirected to: the path of a page with the same name,
+// else the closest parent of the link having a page, at its path or its index.html
+func (c *pageLinkChecker) suggest(host, linkPath string) string {
+	candidates := c.paths[""]
+	if host != "" {
+		candidates = append(append([]string{}, c.paths[host]...), candidates...)
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	name := path.Base(linkPath)
+	if name != "/" && name != "." {
+		for _, candidate := range candidates {
+			if path.Base(candidate) == name && candidate != linkPath {
+				return candidate
+			}
+		}
+	}
+	exists := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		exists[candidate] = true
+	}
+	for dir := path.Dir(strings.TrimSuffix(linkPath, "/")); ; dir = path.Dir(dir) {
+		for _, candidate := range []string{dir, strings.TrimSuffix(dir, "/") + "/", path.Join(dir, "index.html")} {
+			if exists[candidate] {
+				return candidate
+			}
+		}
+		if dir == "/" || dir == "." {
+			return ""
+		}
+	}
+}
+
+// splitHostPath splits the source of a BASIC_HOST page or redirect into its host and its path
+func splitHostPath(source string) (string, string) {
+	host, hostPath, _ := strings.Cut(source, "/")
+	return host, "/" + hostPath
+}
+
+// extractHTMLLinks returns the href and src attributes of the elements of an HTML document, in document order
+func extractHTMLLinks(body []byte) []string {
+	links := make([]string, 0)
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return links
+		case html.StartTagToken, html.SelfClosingTagToken:
+			_, hasAttr := tokenizer.TagName()
+			for hasAttr {
+				var key, value []byte
+				key, value, hasAttr = tokenizer.TagAttr()
+				if name := string(key); name == "href" || name == "src" {
+					links = append(links, string(value))
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	flectoTypes "github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupPageLinkServiceTest(t *testing.T, suggestDrafts bool) (*gorm.DB, PageLinkService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{}, &model.Tag{}, &model.Page{}, &model.PageBrokenLink{})
+	require.NoError(t, err)
+
+	ctx := appContext.TestContext(nil)
+	ctx.Config.LinkCheck.SuggestDrafts = suggestDrafts
+	svc := NewPageLinkService(
+		ctx,
+		repository.NewProjectRepository(db),
+		repository.NewPageRepository(db),
+		repository.NewRedirectRepository(db),
+		repository.NewPageLinkRepository(db),
+		NewRedirectDraftService(ctx, repository.NewRedirectDraftRepository(db), nil),
+	)
+
+	db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"})
+	db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test"})
+	return db, svc
+}
+
+func createLinkTestPage(t *testing.T, db *gorm.DB, pageType types.PageType, path, htmlContent string, published bool) *model.Page {
+	page := &model.Page{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		IsPublished:   flectoTypes.Ptr(published),
+		Page: &types.Page{
+			Type:        pageType,
+			Path:        path,
+			Content:     base64.StdEncoding.EncodeToString([]byte(htmlContent)),
+			ContentType: types.PageContentTypeBinary,
+			MimeType:    "text/html; charset=utf-8",
+		},
+	}
+	require.NoError(t, db.Create(page).Error)
+	return page
+}
+
+func createLinkTestRedirect(t *testing.T, db *gorm.DB, redirectType types.RedirectType, source string) {
+	redirect := &model.Redirect{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		IsPublished:   flectoTypes.Ptr(true),
+		Redirect:      &types.Redirect{Type: redirectType, Source: source, Target: "/", Status: types.RedirectStatusMovedPermanent},
+	}
+	require.NoError(t, db.Create(redirect).Error)
+}
+
+func TestPageLinkService_CheckAll(t *testing.T) {
+	db, svc := setupPageLinkServiceTest(t, false)
+	index := createLinkTestPage(t, db, types.PageTypeBasic, "/docs/index.html", `<html><body>
+		<a href="guide.html">Guide</a>
+		<a href="/docs/missing.html#top">Missing</a>
+		<a href="/docs/missing.html">Missing again</a>
+		<a href="/old">Redirected</a>
+		<a href="/blog/2024/post">Redirected by regex</a>
+		<a href="#section">Anchor</a>
+		<a href="mailto:contact@example.com">Mail</a>
+		<a href="https://external.com/nowhere">External</a>
+		<img src="/img/logo.png">
+		<a href="https://example.com/about">Other host page</a>
+	</body></html>`, true)
+	createLinkTestPage(t, db, types.PageTypeBasic, "/docs/guide.html", `<a href="index.html">Back</a>`, true)
+	createLinkTestPage(t, db, types.PageTypeBasicHost, "example.com/about", `<a href="/contact">Contact</a>`, true)
+	createLinkTestPage(t, db, types.PageTypeBasic, "/draft.html", `<a href="/nowhere">Nowhere</a>`, false)
+	require.NoError(t, db.Create(&model.Page{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true),
+		Page: &types.Page{Type: types.PageTypeBasic, Path: "/robots.txt", Content: "<a href=\"/nowhere\">", ContentType: types.PageContentTypeTextPlain}}).Error)
+	createLinkTestRedirect(t, db, types.RedirectTypeBasic, "/old")
+	createLinkTestRedirect(t, db, types.RedirectTypeRegex, `^/blog/\d+/`)
+	createLinkTestRedirect(t, db, types.RedirectTypeCatchAll, "")
+
+	broken, err := svc.CheckAll(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, broken)
+	report, err := svc.GetReport(context.Background(), "test-ns", "test-proj")
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.PageCount)
+	assert.Equal(t, 3, report.BrokenCount)
+
+	links := make(map[string]model.PageBrokenLink)
+	for _, link := range report.Links {
+		links[link.URL] = link
+	}
+	assert.Equal(t, index.ID, links["/docs/missing.html"].PageID)
+	assert.Equal(t, "/docs/missing.html#top", links["/docs/missing.html"].Link)
+	assert.Equal(t, "/docs/index.html", links["/docs/missing.html"].PagePath)
+	assert.Equal(t, "/docs/index.html", links["/docs/missing.html"].SuggestedTarget, "closest parent having a page")
+	assert.Equal(t, "", links["/img/logo.png"].SuggestedTarget)
+	assert.Equal(t, "example.com/contact", links["example.com/contact"].URL)
+	assert.False(t, links["example.com/contact"].DraftCreated)
+
+	var drafts int64
+	db.Model(&model.RedirectDraft{}).Count(&drafts)
+	assert.Equal(t, int64(0), drafts)
+}
+
+func TestPageLinkService_CheckProject_SuggestDrafts(t *testing.T) {
+	db, svc := setupPageLinkServiceTest(t, true)
+	createLinkTestPage(t, db, types.PageTypeBasic, "/guides/setup.html", `<a href="/docs/setup.html">Setup</a><a href="/unknown.html">Unknown</a>`, true)
+	project := model.Project{NamespaceCode: "test-ns", ProjectCode: "test-proj"}
+
+	links, err := svc.CheckProject(context.Background(), project)
+
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	assert.Equal(t, "/guides/setup.html", links[0].SuggestedTarget, "page with the same name")
+	assert.True(t, links[0].DraftCreated)
+	assert.Equal(t, "", links[1].SuggestedTarget)
+	assert.False(t, links[1].DraftCreated)
+
+	var drafts []model.RedirectDraft
+	require.NoError(t, db.Preload("Tags").Find(&drafts).Error)
+	require.Len(t, drafts, 1)
+	assert.Equal(t, types.RedirectTypeBasic, drafts[0].NewRedirect.Type)
+	assert.Equal(t, "/docs/setup.html", drafts[0].NewRedirect.Source)
+	assert.Equal(t, "/guides/setup.html", drafts[0].NewRedirect.Target)
+	require.Len(t, drafts[0].Tags, 1)
+	assert.Equal(t, PageLinkDraftTag, drafts[0].Tags[0].Name)
+
+	// The draft is created once, even when it is deleted afterwards
+	require.NoError(t, db.Delete(&drafts[0]).Error)
+	links, err = svc.CheckProject(context.Background(), project)
+	require.NoError(t, err)
+	assert.True(t, links[0].DraftCreated)
+	var count int64
+	db.Model(&model.RedirectDraft{}).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestExtractHTMLLinks(t *testing.T) {
+	links := extractHTMLLinks([]byte(`<!DOCTYPE html><html><head><link rel="stylesheet" href="/style.css"><script src="app.js"></script></head>
+		<body><a href='/a'>A</a><a name="anchor">no link</a><img src="/b.png"/><!-- <a href="/commented"> --></body></html>`))
+	assert.Equal(t, []string{"/style.css", "app.js", "/a", "/b.png"}, links)
+}
+
+func TestIsHTMLPage(t *testing.T) {
+	assert.True(t, isHTMLPage(types.Page{ContentType: types.PageContentTypeBinary, MimeType: "text/html"}))
+	assert.True(t, isHTMLPage(types.Page{ContentType: types.PageContentTypeBinary, MimeType: "application/xhtml+xml"}))
+	assert.True(t, isHTMLPage(types.Page{ContentType: types.PageContentTypeMarkdown}))
+	assert.False(t, isHTMLPage(types.Page{ContentType: types.PageContentTypeBinary, MimeType: "image/png"}))
+	assert.False(t, isHTMLPage(types.Page{ContentType: types.PageContentTypeXML}))
+}
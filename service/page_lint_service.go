@@ -0,0 +1,365 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"golang.org/x/net/html"
+)
+
+const (
+	// maxPageLintWarnings bounds the number of warnings kept for a page
+	maxPageLintWarnings = 50
+	// sitemapNamespace is the namespace of the elements of the sitemap protocol
+	sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+	// sitemapMaxEntries is the maximum number of URLs of a sitemap, or of sitemaps of a sitemap index
+	sitemapMaxEntries = 50000
+	// sitemapMaxLocLength is the maximum length of the URLs of a sitemap
+	sitemapMaxLocLength = 2048
+)
+
+// htmlVoidElements are the HTML elements without content nor end tag
+var htmlVoidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true, "input": true,
+	"link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlOptionalEndElements are the HTML elements whose end tag can be omitted
+var htmlOptionalEndElements = map[string]bool{
+	"html": true, "head": true, "body": true, "p": true, "li": true, "dt": true, "dd": true, "option": true,
+	"optgroup": true, "rt": true, "rp": true, "thead": true, "tbody": true, "tfoot": true, "tr": true, "td": true,
+	"th": true, "colgroup": true,
+}
+
+// robotsDirectives are the robots.txt directives known to the crawlers, lowercased
+var robotsDirectives = map[string]bool{
+	"user-agent": true, "allow": true, "disallow": true, "sitemap": true, "crawl-delay": true, "host": true,
+	"clean-param": true,
+}
+
+// sitemapChangeFrequencies are the values allowed for the changefreq of the sitemap URLs
+var sitemapChangeFrequencies = map[string]bool{
+	"always": true, "hourly": true, "daily": true, "weekly": true, "monthly": true, "yearly": true, "never": true,
+}
+
+// sitemapDateLayouts are the W3C datetime layouts allowed for the lastmod of the sitemaps
+var sitemapDateLayouts = []string{"2006", "2006-01", "2006-01-02", "2006-01-02T15:04Z07:00", time.RFC3339, time.RFC3339Nano}
+
+// PageLintService checks the content of the pages against their type, the issues found being warnings which do not
+// prevent saving the pages
+type PageLintService interface {
+	Lint(page *commonTypes.Page) []model.PageLintWarning
+}
+
+type pageLintService struct {
+	ctx *appContext.Context
+}
+
+func NewPageLintService(ctx *appContext.Context) PageLintService {
+	return &pageLintService{
+		ctx: ctx,
+	}
+}
+
+// Lint returns the warnings of the content of the page: the HTML pages must be well-formed, the robots.txt pages
+// must only hold valid directives, and the XML pages must be well-formed, the sitemaps following the sitemap schema
+func (s *pageLintService) Lint(page *commonTypes.Page) []model.PageLintWarning {
+	l := &pageLinter{warnings: make([]model.PageLintWarning, 0)}
+	if page == nil {
+		return l.warnings
+	}
+	switch {
+	case isHTMLPage(*page):
+		body, err := page.Body()
+		if err == nil {
+			l.lintHTML(body)
+		}
+	case page.ContentType == commonTypes.PageContentTypeXML:
+		l.lintXML([]byte(page.Content), strings.Contains(strings.ToLower(path.Base(page.Path)), "sitemap"))
+	case page.ContentType == commonTypes.PageContentTypeTextPlain && path.Base(page.Path) == "robots.txt":
+		l.lintRobots(page.Content)
+	}
+	return l.warnings
+}
+
+// pageLinter collects the warnings of a page content
+type pageLinter struct {
+	warnings []model.PageLintWarning
+}
+
+func (l *pageLinter) warn(rule model.PageLintRule, line int, format string, args ...any) {
+	if len(l.warnings) < maxPageLintWarnings {
+		l.warnings = append(l.warnings, model.PageLintWarning{Rule: rule, Line: line, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// htmlElement is an element opened in an HTML content
+type htmlElement struct {
+	name string
+	line int
+}
+
+// lintHTML reports the elements of the HTML content which are not closed, or closed without being opened. The end
+// tags which HTML allows to omit are not required.
+func (l *pageLinter) lintHTML(content []byte) {
+	z := html.NewTokenizer(bytes.NewReader(content))
+	open := make([]htmlElement, 0)
+	line := 1
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if err := z.Err(); !errors.Is(err, io.EOF) {
+				l.warn(model.PageLintRuleHTMLMalformed, line, "%s", err)
+			}
+			break
+		}
+		tokenLine := line
+		line += bytes.Count(z.Raw(), []byte("\n"))
+		switch tt {
+		case html.StartTagToken:
+			name, _ := z.TagName()
+			if !htmlVoidElements[string(name)] {
+				open = append(open, htmlElement{name: string(name), line: tokenLine})
+			}
+		case html.EndTagToken:
+			nameBytes, _ := z.TagName()
+			name := string(nameBytes)
+			if htmlVoidElements[name] {
+				continue
+			}
+			i := len(open) - 1
+			for i >= 0 && open[i].name != name {
+				i--
+			}
+			if i < 0 {
+				if !htmlOptionalEndElements[name] {
+					l.warn(model.PageLintRuleHTMLMalformed, tokenLine, "end tag </%s> without a matching <%s>", name, name)
+				}
+				continue
+			}
+			for _, element := range open[i+1:] {
+				if !htmlOptionalEndElements[element.name] {
+					l.warn(model.PageLintRuleHTMLMalformed, element.line, "<%s> is not closed before </%s>", element.name, name)
+				}
+			}
+			open = open[:i]
+		}
+	}
+	for _, element := range open {
+		if !htmlOptionalEndElements[element.name] {
+			l.warn(model.PageLintRuleHTMLMalformed, element.line, "<%s> is not closed", element.name)
+		}
+	}
+}
+
+// lintRobots reports the lines of the robots.txt content which are not valid directives
+func (l *pageLinter) lintRobots(content string) {
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	userAgent := false
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+		field, value, ok := strings.Cut(text, ":")
+		if !ok {
+			l.warn(model.PageLintRuleRobotsInvalidLine, line, "%q is not a directive", text)
+			continue
+		}
+		field = strings.ToLower(strings.TrimSpace(field))
+		value = strings.TrimSpace(value)
+		if !robotsDirectives[field] {
+			l.warn(model.PageLintRuleRobotsUnknownDirective, line, "unknown directive %q", field)
+			continue
+		}
+		switch field {
+		case "user-agent":
+			userAgent = true
+			if value == "" {
+				l.warn(model.PageLintRuleRobotsInvalidValue, line, "user-agent without value")
+			}
+		case "allow", "disallow":
+			if !userAgent {
+				l.warn(model.PageLintRuleRobotsMissingUserAgent, line, "%s before any user-agent is ignored", field)
+			}
+			if value != "" && !strings.HasPrefix(value, "/") && !strings.HasPrefix(value, "*") {
+				l.warn(model.PageLintRuleRobotsInvalidValue, line, "%s path %q must start with / or *", field, value)
+			}
+		case "crawl-delay":
+			if !userAgent {
+				l.warn(model.PageLintRuleRobotsMissingUserAgent, line, "%s before any user-agent is ignored", field)
+			}
+			if delay, err := strconv.ParseFloat(value, 64); err != nil || delay < 0 {
+				l.warn(model.PageLintRuleRobotsInvalidValue, line, "crawl-delay %q must be a positive number", value)
+			}
+		case "sitemap":
+			if !isAbsoluteHTTPURL(value) {
+				l.warn(model.PageLintRuleRobotsInvalidValue, line, "sitemap %q must be an absolute URL", value)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		l.warn(model.PageLintRuleRobotsInvalidLine, 0, "%s", err)
+	}
+}
+
+// sitemapEntry is the url or sitemap element being read in a sitemap
+type sitemapEntry struct {
+	line   int
+	hasLoc bool
+}
+
+// lintXML reports the XML content which is not well-formed, and checks the sitemaps against the sitemap schema.
+// The content is a sitemap when its root is a urlset or a sitemapindex, or when sitemap is true.
+func (l *pageLinter) lintXML(content []byte, sitemap bool) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var (
+		root    string
+		entry   *sitemapEntry
+		entries int
+		field   string
+		value   strings.Builder
+		depth   int
+	)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := decoder.InputPos()
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				line = syntaxErr.Line
+			}
+			l.warn(model.PageLintRuleXMLMalformed, line, "%s", err)
+			return
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch depth {
+			case 1:
+				sitemap = sitemap || t.Name.Local == "urlset" || t.Name.Local == "sitemapindex"
+				if !sitemap {
+					continue
+				}
+				if (t.Name.Local != "urlset" && t.Name.Local != "sitemapindex") || t.Name.Space != sitemapNamespace {
+					l.warn(model.PageLintRuleSitemapInvalidRoot, line, "the root of a sitemap must be a urlset or a sitemapindex in the namespace %s", sitemapNamespace)
+					sitemap = false
+					continue
+				}
+				root = t.Name.Local
+			case 2:
+				if !sitemap || t.Name.Space != sitemapNamespace {
+					continue
+				}
+				if name := sitemapEntryName(root); t.Name.Local != name {
+					l.warn(model.PageLintRuleSitemapInvalidElement, line, "<%s> is not allowed in a %s, only <%s>", t.Name.Local, root, name)
+					continue
+				}
+				entry = &sitemapEntry{line: line}
+				entries++
+			case 3:
+				if entry == nil || t.Name.Space != sitemapNamespace {
+					continue
+				}
+				if !isSitemapField(root, t.Name.Local) {
+					l.warn(model.PageLintRuleSitemapInvalidElement, line, "<%s> is not allowed in a <%s>", t.Name.Local, sitemapEntryName(root))
+					continue
+				}
+				field = t.Name.Local
+				value.Reset()
+			}
+		case xml.CharData:
+			if field != "" {
+				value.Write(t)
+			}
+		case xml.EndElement:
+			switch depth {
+			case 2:
+				if entry != nil && !entry.hasLoc {
+					l.warn(model.PageLintRuleSitemapInvalidElement, entry.line, "<%s> without <loc>", sitemapEntryName(root))
+				}
+				entry = nil
+			case 3:
+				if field != "" && t.Name.Local == field {
+					if field == "loc" {
+						entry.hasLoc = true
+					}
+					l.lintSitemapValue(line, field, strings.TrimSpace(value.String()))
+					field = ""
+				}
+			}
+			depth--
+		}
+	}
+	if entries > sitemapMaxEntries {
+		l.warn(model.PageLintRuleSitemapTooManyEntries, 0, "%d entries in the %s, at most %d are allowed", entries, root, sitemapMaxEntries)
+	}
+}
+
+// lintSitemapValue reports the value of a field of a sitemap not allowed by the sitemap schema
+func (l *pageLinter) lintSitemapValue(line int, field, value string) {
+	switch field {
+	case "loc":
+		if !isAbsoluteHTTPURL(value) {
+			l.warn(model.PageLintRuleSitemapInvalidValue, line, "loc %q must be an absolute URL", value)
+		} else if len(value) > sitemapMaxLocLength {
+			l.warn(model.PageLintRuleSitemapInvalidValue, line, "loc is longer than %d characters", sitemapMaxLocLength)
+		}
+	case "lastmod":
+		for _, layout := range sitemapDateLayouts {
+			if _, err := time.Parse(layout, value); err == nil {
+				return
+			}
+		}
+		l.warn(model.PageLintRuleSitemapInvalidValue, line, "lastmod %q must be a W3C datetime", value)
+	case "changefreq":
+		if !sitemapChangeFrequencies[value] {
+			l.warn(model.PageLintRuleSitemapInvalidValue, line, "changefreq %q must be always, hourly, daily, weekly, monthly, yearly or never", value)
+		}
+	case "priority":
+		if priority, err := strconv.ParseFloat(value, 64); err != nil || priority < 0 || priority > 1 {
+			l.warn(model.PageLintRuleSitemapInvalidValue, line, "priority %q must be between 0.0 and 1.0", value)
+		}
+	}
+}
+
+// sitemapEntryName returns the name of the entries of a sitemap root
+func sitemapEntryName(root string) string {
+	if root == "sitemapindex" {
+		return "sitemap"
+	}
+	return "url"
+}
+
+// isSitemapField returns true if the sitemap schema allows the field in the entries of the root
+func isSitemapField(root, field string) bool {
+	switch field {
+	case "loc", "lastmod":
+		return true
+	case "changefreq", "priority":
+		return root == "urlset"
+	}
+	return false
+}
+
+func isAbsoluteHTTPURL(value string) bool {
+	u, err := url.Parse(value)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
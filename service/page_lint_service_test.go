@@ -0,0 +1,167 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageLintService_Lint(t *testing.T) {
+	htmlPage := func(content string) *commonTypes.Page {
+		return &commonTypes.Page{
+			Path:        "/index.html",
+			ContentType: commonTypes.PageContentTypeBinary,
+			MimeType:    "text/html; charset=utf-8",
+			Content:     base64.StdEncoding.EncodeToString([]byte(content)),
+		}
+	}
+	robotsPage := func(content string) *commonTypes.Page {
+		return &commonTypes.Page{Path: "example.com/robots.txt", ContentType: commonTypes.PageContentTypeTextPlain, Content: content}
+	}
+	xmlPage := func(path, content string) *commonTypes.Page {
+		return &commonTypes.Page{Path: path, ContentType: commonTypes.PageContentTypeXML, Content: content}
+	}
+	sitemap := func(urls string) string {
+		return `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` + "\n" + urls + "\n</urlset>"
+	}
+
+	tests := []struct {
+		name string
+		page *commonTypes.Page
+		want []model.PageLintWarning
+	}{
+		{
+			name: "nil page",
+			want: []model.PageLintWarning{},
+		},
+		{
+			name: "text page is not linted",
+			page: &commonTypes.Page{Path: "/humans.txt", ContentType: commonTypes.PageContentTypeTextPlain, Content: "not a directive"},
+			want: []model.PageLintWarning{},
+		},
+		{
+			name: "well-formed html with optional end tags",
+			page: htmlPage("<!DOCTYPE html>\n<html><body>\n<p>one\n<p>two<br><img src=\"/a.png\">\n<ul><li>a<li>b</ul>\n</body></html>"),
+			want: []model.PageLintWarning{},
+		},
+		{
+			name: "html with unclosed and unexpected elements",
+			page: htmlPage("<div>\n<span>text\n</div>\n</section>\n<main>"),
+			want: []model.PageLintWarning{
+				{Rule: model.PageLintRuleHTMLMalformed, Line: 2, Message: "<span> is not closed before </div>"},
+				{Rule: model.PageLintRuleHTMLMalformed, Line: 4, Message: "end tag </section> without a matching <section>"},
+				{Rule: model.PageLintRuleHTMLMalformed, Line: 5, Message: "<main> is not closed"},
+			},
+		},
+		{
+			name: "markdown page is linted as html",
+			page: &commonTypes.Page{Path: "/about", ContentType: commonTypes.PageContentTypeMarkdown, Content: "<h1>About</h1>\n<div>"},
+			want: []model.PageLintWarning{
+				{Rule: model.PageLintRuleHTMLMalformed, Line: 2, Message: "<div> is not closed"},
+			},
+		},
+		{
+			name: "valid robots.txt",
+			page: robotsPage("# robots\nUser-agent: *\nDisallow: /admin/ # private\nAllow: *.css\nDisallow:\nCrawl-delay: 2.5\n\nSitemap: https://example.com/sitemap.xml"),
+			want: []model.PageLintWarning{},
+		},
+		{
+			name: "invalid robots.txt",
+			page: robotsPage("Disallow: /admin\nUser-agent:\nNoindex: /private\nDisallow admin\nDisallow: admin\nCrawl-delay: soon\nSitemap: /sitemap.xml"),
+			want: []model.PageLintWarning{
+				{Rule: model.PageLintRuleRobotsMissingUserAgent, Line: 1, Message: "disallow before any user-agent is ignored"},
+				{Rule: model.PageLintRuleRobotsInvalidValue, Line: 2, Message: "user-agent without value"},
+				{Rule: model.PageLintRuleRobotsUnknownDirective, Line: 3, Message: `unknown directive "noindex"`},
+				{Rule: model.PageLintRuleRobotsInvalidLine, Line: 4, Message: `"Disallow admin" is not a directive`},
+				{Rule: model.PageLintRuleRobotsInvalidValue, Line: 5, Message: `disallow path "admin" must start with / or *`},
+				{Rule: model.PageLintRuleRobotsInvalidValue, Line: 6, Message: `crawl-delay "soon" must be a positive number`},
+				{Rule: model.PageLintRuleRobotsInvalidValue, Line: 7, Message: `sitemap "/sitemap.xml" must be an absolute URL`},
+			},
+		},
+		{
+			name: "malformed xml",
+			page: xmlPage("/feed.xml", "<feed>\n<entry>\n</feed>"),
+			want: []model.PageLintWarning{
+				{Rule: model.PageLintRuleXMLMalformed, Line: 3, Message: "XML syntax error on line 3: element <entry> closed by </feed>"},
+			},
+		},
+		{
+			name: "xml which is not a sitemap",
+			page: xmlPage("/feed.xml", "<feed><entry/></feed>"),
+			want: []model.PageLintWarning{},
+		},
+		{
+			name: "valid sitemap",
+			page: xmlPage("/sitemap.xml", sitemap(`<url><loc>https://example.com/</loc><lastmod>2026-10-16</lastmod><changefreq>daily</changefreq><priority>0.8</priority></url>
+<url><loc>https://example.com/a</loc><lastmod>2026-10-16T10:00:00+02:00</lastmod><image:image xmlns:image="http://www.google.com/schemas/sitemap-image/1.1"><image:loc>https://example.com/a.png</image:loc></image:image></url>`)),
+			want: []model.PageLintWarning{},
+		},
+		{
+			name: "valid sitemap index",
+			page: xmlPage("/sitemaps.xml", `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><sitemap><loc>https://example.com/sitemap-1.xml</loc></sitemap></sitemapindex>`),
+			want: []model.PageLintWarning{},
+		},
+		{
+			name: "invalid sitemap values and elements",
+			page: xmlPage("/sitemap.xml", sitemap(`<url><loc>/relative</loc><lastmod>yesterday</lastmod><changefreq>often</changefreq><priority>2</priority><title>Home</title></url>
+<url><lastmod>2026-10-16</lastmod></url>
+<page><loc>https://example.com/b</loc></page>`)),
+			want: []model.PageLintWarning{
+				{Rule: model.PageLintRuleSitemapInvalidValue, Line: 3, Message: `loc "/relative" must be an absolute URL`},
+				{Rule: model.PageLintRuleSitemapInvalidValue, Line: 3, Message: `lastmod "yesterday" must be a W3C datetime`},
+				{Rule: model.PageLintRuleSitemapInvalidValue, Line: 3, Message: `changefreq "often" must be always, hourly, daily, weekly, monthly, yearly or never`},
+				{Rule: model.PageLintRuleSitemapInvalidValue, Line: 3, Message: `priority "2" must be between 0.0 and 1.0`},
+				{Rule: model.PageLintRuleSitemapInvalidElement, Line: 3, Message: "<title> is not allowed in a <url>"},
+				{Rule: model.PageLintRuleSitemapInvalidElement, Line: 4, Message: "<url> without <loc>"},
+				{Rule: model.PageLintRuleSitemapInvalidElement, Line: 5, Message: "<page> is not allowed in a urlset, only <url>"},
+			},
+		},
+		{
+			name: "sitemap index entries only have loc and lastmod",
+			page: xmlPage("/sitemaps.xml", `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><sitemap><loc>https://example.com/sitemap-1.xml</loc><priority>0.5</priority></sitemap></sitemapindex>`),
+			want: []model.PageLintWarning{
+				{Rule: model.PageLintRuleSitemapInvalidElement, Line: 1, Message: "<priority> is not allowed in a <sitemap>"},
+			},
+		},
+		{
+			name: "sitemap path without sitemap root",
+			page: xmlPage("/sitemap.xml", `<urls><url><loc>https://example.com/</loc></url></urls>`),
+			want: []model.PageLintWarning{
+				{Rule: model.PageLintRuleSitemapInvalidRoot, Line: 1, Message: "the root of a sitemap must be a urlset or a sitemapindex in the namespace http://www.sitemaps.org/schemas/sitemap/0.9"},
+			},
+		},
+		{
+			name: "sitemap without namespace",
+			page: xmlPage("/pages.xml", `<urlset><url><loc>https://example.com/</loc></url></urlset>`),
+			want: []model.PageLintWarning{
+				{Rule: model.PageLintRuleSitemapInvalidRoot, Line: 1, Message: "the root of a sitemap must be a urlset or a sitemapindex in the namespace http://www.sitemaps.org/schemas/sitemap/0.9"},
+			},
+		},
+	}
+
+	svc := NewPageLintService(appContext.TestContext(nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, svc.Lint(tt.page))
+		})
+	}
+
+	t.Run("too many sitemap entries", func(t *testing.T) {
+		urls := strings.Repeat("<url><loc>https://example.com/</loc></url>", sitemapMaxEntries+1)
+		warnings := svc.Lint(xmlPage("/sitemap.xml", sitemap(urls)))
+		assert.Equal(t, []model.PageLintWarning{
+			{Rule: model.PageLintRuleSitemapTooManyEntries, Message: fmt.Sprintf("%d entries in the urlset, at most %d are allowed", sitemapMaxEntries+1, sitemapMaxEntries)},
+		}, warnings)
+	})
+
+	t.Run("warnings are bounded", func(t *testing.T) {
+		warnings := svc.Lint(robotsPage(strings.Repeat("invalid\n", maxPageLintWarnings+10)))
+		assert.Len(t, warnings, maxPageLintWarnings)
+	})
+}
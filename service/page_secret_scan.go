@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// ErrPageSecretDetected is returned when the content of a page draft holds a likely secret and page.secrets.mode
+// is block
+var ErrPageSecretDetected = flectoErrors.New(flectoErrors.CodeSecretDetected, "page content holds a likely secret")
+
+// defaultPageSecretRules are the built-in rules of the secrets scanning
+var defaultPageSecretRules = []config.PageSecretRule{
+	{Name: "aws-access-key-id", Pattern: `\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`},
+	{Name: "github-token", Pattern: `\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`},
+	{Name: "gitlab-token", Pattern: `\bglpat-[A-Za-z0-9_-]{20,}\b`},
+	{Name: "google-api-key", Pattern: `\bAIza[0-9A-Za-z_-]{35}\b`},
+	{Name: "slack-token", Pattern: `\bxox[abprs]-[A-Za-z0-9-]{10,}\b`},
+	{Name: "slack-webhook", Pattern: `https://hooks\.slack\.com/services/[A-Za-z0-9]+/[A-Za-z0-9]+/[A-Za-z0-9]+`},
+	{Name: "stripe-secret-key", Pattern: `\b[rs]k_live_[A-Za-z0-9]{20,}\b`},
+	{Name: "private-key", Pattern: `-----BEGIN (?:[A-Z]+ )?PRIVATE KEY-----`},
+	{Name: "jwt", Pattern: `\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`},
+	{Name: "secret-assignment", Pattern: `(?i)\b(?:api[_-]?key|secret|password|passwd|access[_-]?token)["']?\s*[:=]\s*["'][^"'\s]{12,}["']`},
+}
+
+// pageSecret is a likely secret found in the content of a page
+type pageSecret struct {
+	rule string
+	line int
+}
+
+func (s pageSecret) String() string {
+	return fmt.Sprintf("%s on line %d", s.rule, s.line)
+}
+
+// scanPageSecrets returns the likely secrets found with the rules of cfg in the content served for the page, at most
+// one per rule. The markdown source of the pages is not served, and the binary pages are only scanned when they are
+// served as text. The patterns of the rules are checked when the configuration is loaded.
+func scanPageSecrets(cfg config.PageSecretsConfig, page *commonTypes.Page) []pageSecret {
+	if cfg.Mode == "" || cfg.Mode == config.PageSecretsOff || page == nil {
+		return nil
+	}
+	if page.IsBinary() && !isTextMimeType(page.MimeType) {
+		return nil
+	}
+	body, err := page.Body()
+	if err != nil {
+		return nil
+	}
+	content := string(body)
+	rules := cfg.Rules
+	if cfg.DefaultRules {
+		rules = append(append([]config.PageSecretRule{}, defaultPageSecretRules...), cfg.Rules...)
+	}
+
+	secrets := make([]pageSecret, 0)
+	for _, rule := range rules {
+		pattern, errCompile := regexp.Compile(rule.Pattern)
+		if errCompile != nil {
+			continue
+		}
+		if loc := pattern.FindStringIndex(content); loc != nil {
+			secrets = append(secrets, pageSecret{rule: rule.Name, line: strings.Count(content[:loc[0]], "\n") + 1})
+		}
+	}
+	return secrets
+}
+
+// checkPageSecrets scans the page for likely secrets, and returns an error when page.secrets.mode is block, or the
+// warnings of the secrets otherwise
+func checkPageSecrets(cfg config.PageSecretsConfig, page *commonTypes.Page) ([]model.PageLintWarning, error) {
+	secrets := scanPageSecrets(cfg, page)
+	if len(secrets) == 0 {
+		return nil, nil
+	}
+	if cfg.Mode == config.PageSecretsBlock {
+		return nil, fmt.Errorf("%w: %s", ErrPageSecretDetected, secrets[0])
+	}
+	warnings := make([]model.PageLintWarning, 0, len(secrets))
+	for _, secret := range secrets {
+		warnings = append(warnings, model.PageLintWarning{
+			Rule:    model.PageLintRuleSecretDetected,
+			Line:    secret.line,
+			Message: fmt.Sprintf("likely secret: %s", secret.rule),
+		})
+	}
+	return warnings, nil
+}
+
+// isTextMimeType returns true for the mime types served as text, like HTML, JSON or JavaScript
+func isTextMimeType(mimeType string) bool {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	mediaType = strings.TrimSpace(strings.ToLower(mediaType))
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+xml") || strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/json" || mediaType == "application/javascript" || mediaType == "application/xml"
+}
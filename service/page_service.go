@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
@@ -16,8 +17,10 @@ type PageService interface {
 	GetByID(ctx context.Context, namespaceCode, projectCode string, pageID int64) (*model.Page, error)
 	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.Page, error)
 	FindByProjectPublished(ctx context.Context, namespaceCode, projectCode string, pagination *commonTypes.PaginationInput) ([]model.Page, int64, error)
+	StreamByProjectPublished(ctx context.Context, namespaceCode, projectCode string, version int, start func(total int64) error, fn func(pages []model.Page) error) error
 	Search(ctx context.Context, query *gorm.DB) ([]model.Page, error)
 	SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.PageList, error)
+	SearchCursor(ctx context.Context, cursor *commonTypes.CursorInput, query *gorm.DB) (*model.PageCursorList, error)
 }
 
 type pageService struct {
@@ -52,12 +55,36 @@ func (s *pageService) FindByProjectPublished(ctx context.Context, namespaceCode,
 	return s.repo.FindByProjectPublished(ctx, namespaceCode, projectCode, pagination.GetLimit(), pagination.GetOffset())
 }
 
+// StreamByProjectPublished calls start with the number of published pages of a project then fn with their batches,
+// in the order of their id. It fails with ErrSnapshotChanged when the project is published meanwhile, version being
+// the one of the project when the stream started.
+func (s *pageService) StreamByProjectPublished(ctx context.Context, namespaceCode, projectCode string, version int, start func(total int64) error, fn func(pages []model.Page) error) error {
+	var total int64
+	err := s.repo.GetQuery(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND is_published = 1", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Count(&total).Error
+	if err != nil {
+		return err
+	}
+	if err = start(total); err != nil {
+		return err
+	}
+
+	return streamPublished(ctx, s.repo.GetTx(ctx), namespaceCode, projectCode, version, func(last *model.Page) ([]model.Page, error) {
+		afterID := int64(0)
+		if last != nil {
+			afterID = last.ID
+		}
+		return s.repo.FindByProjectPublishedAfter(ctx, namespaceCode, projectCode, afterID, SnapshotStreamBatchSize)
+	}, fn)
+}
+
 func (s *pageService) Search(ctx context.Context, query *gorm.DB) ([]model.Page, error) {
 	return s.repo.Search(ctx, query)
 }
 
 func (s *pageService) SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.PageList, error) {
-	pages, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	pages, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
@@ -68,4 +95,23 @@ func (s *pageService) SearchPaginate(ctx context.Context, pagination *commonType
 		Limit:  pagination.GetLimit(),
 		Items:  pages,
 	}, nil
-}
\ No newline at end of file
+}
+
+func (s *pageService) SearchCursor(ctx context.Context, cursor *commonTypes.CursorInput, query *gorm.DB) (*model.PageCursorList, error) {
+	afterID, err := cursor.GetAfterID()
+	if err != nil {
+		return nil, err
+	}
+
+	pages, hasMore, err := s.repo.SearchCursor(ctx, query, afterID, cursor.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.PageCursorList{Items: pages}
+	if hasMore {
+		nextCursor := commonTypes.EncodeCursor(pages[len(pages)-1].ID)
+		result.NextCursor = &nextCursor
+	}
+	return result, nil
+}
@@ -261,6 +261,41 @@ func TestPageService_Search(t *testing.T) {
 	})
 }
 
+func TestPageService_SearchCursor(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl, mockRepo, svc := setupPageServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		limit := 1
+		after := types.EncodeCursor(4)
+		expectedItems := []model.Page{{ID: 5}}
+
+		mockRepo.EXPECT().SearchCursor(ctx, nil, int64(4), 1).Return(expectedItems, true, nil)
+
+		result, err := svc.SearchCursor(ctx, &types.CursorInput{After: &after, Limit: &limit}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedItems, result.Items)
+		assert.Equal(t, types.EncodeCursor(5), *result.NextCursor)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl, mockRepo, svc := setupPageServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		expectedErr := errors.New("search error")
+
+		mockRepo.EXPECT().SearchCursor(ctx, nil, int64(0), types.DefaultLimit).Return(nil, false, expectedErr)
+
+		result, err := svc.SearchCursor(ctx, nil, nil)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, result)
+	})
+}
+
 func TestPageService_SearchPaginate(t *testing.T) {
 	t.Run("success with pagination", func(t *testing.T) {
 		ctrl, mockPageRepo, svc := setupPageServiceTest(t)
@@ -279,7 +314,7 @@ func TestPageService_SearchPaginate(t *testing.T) {
 		}
 
 		mockPageRepo.EXPECT().
-			SearchPaginate(ctx, nil, 10, 5).
+			SearchPaginate(ctx, nil, 10, 5, nil).
 			Return(expectedPages, int64(50), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -303,7 +338,7 @@ func TestPageService_SearchPaginate(t *testing.T) {
 		}
 
 		mockPageRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(expectedPages, int64(1), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -323,7 +358,7 @@ func TestPageService_SearchPaginate(t *testing.T) {
 		expectedErr := errors.New("search error")
 
 		mockPageRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(nil, int64(0), expectedErr)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"gorm.io/gorm"
+)
+
+var ErrPageTemplateBinary = flectoErrors.New(flectoErrors.CodeInvalidRequest, "page templates can not have binary content")
+
+type PageTemplateService interface {
+	GetTx(ctx context.Context) *gorm.DB
+	GetQuery(ctx context.Context) *gorm.DB
+	GetByCode(ctx context.Context, namespaceCode, code string) (*model.PageTemplate, error)
+	GetByNamespace(ctx context.Context, namespaceCode string) ([]model.PageTemplate, error)
+	Create(ctx context.Context, namespaceCode string, input *model.PageTemplate) (*model.PageTemplate, error)
+	Update(ctx context.Context, namespaceCode, code string, input model.PageTemplate) (*model.PageTemplate, error)
+	Delete(ctx context.Context, namespaceCode, code string) (bool, error)
+	CreatePageFromTemplate(ctx context.Context, namespaceCode, projectCode, code string, pageType commonTypes.PageType, path string, values map[string]string) (*model.PageDraft, error)
+}
+
+type pageTemplateService struct {
+	ctx          *appContext.Context
+	repo         repository.PageTemplateRepository
+	pageDraftSrv PageDraftService
+}
+
+func NewPageTemplateService(
+	ctx *appContext.Context,
+	repo repository.PageTemplateRepository,
+	pageDraftSrv PageDraftService,
+) PageTemplateService {
+	return &pageTemplateService{
+		ctx:          ctx,
+		repo:         repo,
+		pageDraftSrv: pageDraftSrv,
+	}
+}
+
+func (s *pageTemplateService) GetTx(ctx context.Context) *gorm.DB {
+	return s.repo.GetTx(ctx)
+}
+
+func (s *pageTemplateService) GetQuery(ctx context.Context) *gorm.DB {
+	return s.repo.GetQuery(ctx)
+}
+
+func (s *pageTemplateService) GetByCode(ctx context.Context, namespaceCode, code string) (*model.PageTemplate, error) {
+	return s.repo.FindByCode(ctx, namespaceCode, code)
+}
+
+func (s *pageTemplateService) GetByNamespace(ctx context.Context, namespaceCode string) ([]model.PageTemplate, error) {
+	return s.repo.FindByNamespace(ctx, namespaceCode)
+}
+
+func (s *pageTemplateService) Create(ctx context.Context, namespaceCode string, input *model.PageTemplate) (*model.PageTemplate, error) {
+	input.NamespaceCode = namespaceCode
+	if err := s.validate(input); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, input); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to create page template", "namespace", namespaceCode, "code", input.Code, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "page template created", "namespace", namespaceCode, "code", input.Code)
+	return input, nil
+}
+
+func (s *pageTemplateService) Update(ctx context.Context, namespaceCode, code string, input model.PageTemplate) (*model.PageTemplate, error) {
+	template, err := s.repo.FindByCode(ctx, namespaceCode, code)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = input.Name
+	template.ContentType = input.ContentType
+	template.Content = input.Content
+	if err = s.validate(template); err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.Update(ctx, template); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (s *pageTemplateService) Delete(ctx context.Context, namespaceCode, code string) (bool, error) {
+	if err := s.repo.Delete(ctx, namespaceCode, code); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete page template", "namespace", namespaceCode, "code", code, "error", err)
+		return false, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "page template deleted", "namespace", namespaceCode, "code", code)
+	return true, nil
+}
+
+// CreatePageFromTemplate renders the template with values and creates a draft of a new page with the result,
+// the rendered content being checked against the page size limits like any page draft.
+func (s *pageTemplateService) CreatePageFromTemplate(ctx context.Context, namespaceCode, projectCode, code string, pageType commonTypes.PageType, path string, values map[string]string) (*model.PageDraft, error) {
+	template, err := s.repo.FindByCode(ctx, namespaceCode, code)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := template.Render(values)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &commonTypes.Page{
+		Type:        pageType,
+		Path:        path,
+		Content:     content,
+		ContentType: template.ContentType,
+	}
+	// The content of a markdown template is the source of its pages, their content being rendered from it
+	if template.ContentType == commonTypes.PageContentTypeMarkdown {
+		page.Source = content
+	}
+	return s.pageDraftSrv.Create(ctx, namespaceCode, projectCode, nil, page)
+}
+
+func (s *pageTemplateService) validate(template *model.PageTemplate) error {
+	if err := s.ctx.Validator.Struct(template); err != nil {
+		return err
+	}
+	if template.ContentType == commonTypes.PageContentTypeBinary {
+		return ErrPageTemplateBinary
+	}
+	if int64(len(template.Content)) > int64(s.ctx.CurrentConfig().Page.SizeLimit) {
+		return ErrContentSizeExceeded
+	}
+	return nil
+}
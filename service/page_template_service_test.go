@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	mockFlectoRepository "github.com/flectolab/flecto-manager/mocks/flecto-manager/repository"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func setupPageTemplateServiceTest(t *testing.T) (*gomock.Controller, *mockFlectoRepository.MockPageTemplateRepository, *mockFlectoService.MockPageDraftService, PageTemplateService) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mockFlectoRepository.NewMockPageTemplateRepository(ctrl)
+	mockPageDraftSrv := mockFlectoService.NewMockPageDraftService(ctrl)
+	svc := NewPageTemplateService(testContextWithPageConfig(defaultPageDraftTestConfig), mockRepo, mockPageDraftSrv)
+	return ctrl, mockRepo, mockPageDraftSrv, svc
+}
+
+func newRobotsPageTemplate() *model.PageTemplate {
+	return &model.PageTemplate{
+		NamespaceCode: "ns",
+		Code:          "robots",
+		Name:          "Robots",
+		ContentType:   commonTypes.PageContentTypeTextPlain,
+		Content:       "User-agent: *\nSitemap: https://{{host}}/sitemap.xml",
+	}
+}
+
+func TestNewPageTemplateService(t *testing.T) {
+	ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+	defer ctrl.Finish()
+
+	assert.NotNil(t, svc)
+	mockRepo.EXPECT().GetTx(gomock.Any()).Return(&gorm.DB{})
+	mockRepo.EXPECT().GetQuery(gomock.Any()).Return(&gorm.DB{})
+	assert.NotNil(t, svc.GetTx(context.Background()))
+	assert.NotNil(t, svc.GetQuery(context.Background()))
+}
+
+func TestPageTemplateService_Create(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		input := newRobotsPageTemplate()
+		input.NamespaceCode = ""
+		mockRepo.EXPECT().Create(ctx, input).Return(nil)
+
+		result, err := svc.Create(ctx, "ns", input)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "ns", result.NamespaceCode)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		ctrl, _, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		input := newRobotsPageTemplate()
+		input.Code = "Invalid Code"
+
+		_, err := svc.Create(ctx, "ns", input)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("content too large", func(t *testing.T) {
+		ctrl, _, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		input := newRobotsPageTemplate()
+		input.Content = strings.Repeat("a", defaultPageDraftTestConfig.SizeLimit+1)
+
+		_, err := svc.Create(ctx, "ns", input)
+
+		assert.ErrorIs(t, err, ErrContentSizeExceeded)
+	})
+
+	t.Run("binary content", func(t *testing.T) {
+		ctrl, _, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		input := newRobotsPageTemplate()
+		input.ContentType = commonTypes.PageContentTypeBinary
+
+		_, err := svc.Create(ctx, "ns", input)
+
+		assert.ErrorIs(t, err, ErrPageTemplateBinary)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().Create(ctx, gomock.Any()).Return(errors.New("db error"))
+
+		_, err := svc.Create(ctx, "ns", newRobotsPageTemplate())
+
+		assert.EqualError(t, err, "db error")
+	})
+}
+
+func TestPageTemplateService_Update(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		existing := newRobotsPageTemplate()
+		mockRepo.EXPECT().FindByCode(ctx, "ns", "robots").Return(existing, nil)
+		mockRepo.EXPECT().Update(ctx, existing).Return(nil)
+
+		result, err := svc.Update(ctx, "ns", "robots", model.PageTemplate{Code: "ignored", Name: "Sitemap", ContentType: commonTypes.PageContentTypeXML, Content: "<urlset/>"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "robots", result.Code)
+		assert.Equal(t, "Sitemap", result.Name)
+		assert.Equal(t, commonTypes.PageContentTypeXML, result.ContentType)
+		assert.Equal(t, "<urlset/>", result.Content)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().FindByCode(ctx, "ns", "unknown").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.Update(ctx, "ns", "unknown", *newRobotsPageTemplate())
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().FindByCode(ctx, "ns", "robots").Return(newRobotsPageTemplate(), nil)
+
+		_, err := svc.Update(ctx, "ns", "robots", model.PageTemplate{ContentType: commonTypes.PageContentTypeXML})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestPageTemplateService_Delete(t *testing.T) {
+	ctx := context.Background()
+	ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+	defer ctrl.Finish()
+
+	mockRepo.EXPECT().Delete(ctx, "ns", "robots").Return(nil)
+	result, err := svc.Delete(ctx, "ns", "robots")
+	assert.NoError(t, err)
+	assert.True(t, result)
+
+	mockRepo.EXPECT().Delete(ctx, "ns", "robots").Return(errors.New("db error"))
+	result, err = svc.Delete(ctx, "ns", "robots")
+	assert.Error(t, err)
+	assert.False(t, result)
+}
+
+func TestPageTemplateService_GetByNamespace(t *testing.T) {
+	ctx := context.Background()
+	ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+	defer ctrl.Finish()
+
+	templates := []model.PageTemplate{*newRobotsPageTemplate()}
+	mockRepo.EXPECT().FindByNamespace(ctx, "ns").Return(templates, nil)
+	mockRepo.EXPECT().FindByCode(ctx, "ns", "robots").Return(&templates[0], nil)
+
+	result, err := svc.GetByNamespace(ctx, "ns")
+	assert.NoError(t, err)
+	assert.Equal(t, templates, result)
+
+	template, err := svc.GetByCode(ctx, "ns", "robots")
+	assert.NoError(t, err)
+	assert.Equal(t, "robots", template.Code)
+}
+
+func TestPageTemplateService_CreatePageFromTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("renders the template into a page draft", func(t *testing.T) {
+		ctrl, mockRepo, mockPageDraftSrv, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().FindByCode(ctx, "ns", "robots").Return(newRobotsPageTemplate(), nil)
+		expectedPage := &commonTypes.Page{
+			Type:        commonTypes.PageTypeBasic,
+			Path:        "/robots.txt",
+			Content:     "User-agent: *\nSitemap: https://example.com/sitemap.xml",
+			ContentType: commonTypes.PageContentTypeTextPlain,
+		}
+		draft := &model.PageDraft{ID: 1, NewPage: expectedPage}
+		mockPageDraftSrv.EXPECT().Create(ctx, "ns", "proj", nil, expectedPage).Return(draft, nil)
+
+		result, err := svc.CreatePageFromTemplate(ctx, "ns", "proj", "robots", commonTypes.PageTypeBasic, "/robots.txt", map[string]string{"host": "example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, draft, result)
+	})
+
+	t.Run("missing values", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().FindByCode(ctx, "ns", "robots").Return(newRobotsPageTemplate(), nil)
+
+		_, err := svc.CreatePageFromTemplate(ctx, "ns", "proj", "robots", commonTypes.PageTypeBasic, "/robots.txt", nil)
+
+		assert.EqualError(t, err, "missing values for template variables: host")
+	})
+
+	t.Run("rendered content too large", func(t *testing.T) {
+		ctrl, mockRepo, mockPageDraftSrv, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().FindByCode(ctx, "ns", "robots").Return(newRobotsPageTemplate(), nil)
+		mockPageDraftSrv.EXPECT().Create(ctx, "ns", "proj", nil, gomock.Any()).Return(nil, ErrContentSizeExceeded)
+
+		_, err := svc.CreatePageFromTemplate(ctx, "ns", "proj", "robots", commonTypes.PageTypeBasic, "/robots.txt", map[string]string{"host": strings.Repeat("a", 2048)})
+
+		assert.ErrorIs(t, err, ErrContentSizeExceeded)
+	})
+
+	t.Run("template not found", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupPageTemplateServiceTest(t)
+		defer ctrl.Finish()
+
+		mockRepo.EXPECT().FindByCode(ctx, "ns", "unknown").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.CreatePageFromTemplate(ctx, "ns", "proj", "unknown", commonTypes.PageTypeBasic, "/robots.txt", nil)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+const (
+	permissionCacheUser  = "user:"
+	permissionCacheRole  = "role:"
+	permissionCacheToken = "token:"
+)
+
+type permissionCacheEntry struct {
+	permissions *model.SubjectPermissions
+	expiresAt   time.Time
+}
+
+// permissionCache keeps the resolved permissions of users, roles and tokens for a TTL. It is cleared
+// as a whole on any change, since a change on a role affects all the roles inheriting from it and
+// all their users. A nil cache is disabled.
+type permissionCache struct {
+	ttl       time.Duration
+	now       func() time.Time
+	mu        sync.RWMutex
+	entries   map[string]permissionCacheEntry
+	lastSweep time.Time
+	// generation is incremented on clear, so permissions loaded before a change are not cached
+	generation uint64
+}
+
+func newPermissionCache(ttl time.Duration) *permissionCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &permissionCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]permissionCacheEntry),
+	}
+}
+
+// get returns the cached permissions of the key, they are shared and must not be modified.
+// It also returns the generation of the cache to give to set once the permissions are loaded.
+func (c *permissionCache) get(key string) (*model.SubjectPermissions, uint64, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	generation := c.generation
+	c.mu.RUnlock()
+	if !ok || !c.now().Before(entry.expiresAt) {
+		return nil, generation, false
+	}
+	return entry.permissions, generation, true
+}
+
+func (c *permissionCache) set(key string, permissions *model.SubjectPermissions, generation uint64) {
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	// Drop the expired entries once per TTL so that the subjects not seen anymore are released
+	if now.Sub(c.lastSweep) >= c.ttl {
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.lastSweep = now
+	}
+	c.entries[key] = permissionCacheEntry{permissions: permissions, expiresAt: now.Add(c.ttl)}
+}
+
+func (c *permissionCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]permissionCacheEntry)
+	c.generation++
+}
+
+// cachedPermissions returns the permissions of the key from the cache, loading and caching them on a miss
+func (c *permissionCache) cachedPermissions(key string, load func() (*model.SubjectPermissions, error)) (*model.SubjectPermissions, error) {
+	if c == nil {
+		return load()
+	}
+	permissions, generation, ok := c.get(key)
+	if ok {
+		return permissions, nil
+	}
+	permissions, err := load()
+	if err != nil {
+		return nil, err
+	}
+	c.set(key, permissions, generation)
+	return permissions, nil
+}
+
+// publishPermissionsChanged tells all the replicas, this one included, to clear their permission cache
+func publishPermissionsChanged(ctx context.Context, appCtx *appContext.Context, bus invalidation.Bus) {
+	if err := bus.Publish(ctx, invalidation.Event{Type: invalidation.EventTypePermissionsChanged}); err != nil {
+		appCtx.Logger.Error("failed to publish invalidation event", "type", invalidation.EventTypePermissionsChanged, "error", err)
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPermissionCache(t *testing.T) {
+	assert.Nil(t, newPermissionCache(0))
+	assert.NotNil(t, newPermissionCache(time.Second))
+}
+
+func TestPermissionCache(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	cache := newPermissionCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	permissions := &model.SubjectPermissions{Admin: []model.AdminPermission{{Section: model.AdminSectionUsers, Action: model.ActionRead}}}
+	loads := 0
+	load := func() (*model.SubjectPermissions, error) {
+		loads++
+		return permissions, nil
+	}
+
+	t.Run("caches until the TTL expires", func(t *testing.T) {
+		got, err := cache.cachedPermissions(permissionCacheUser+"alice", load)
+		require.NoError(t, err)
+		assert.Same(t, permissions, got)
+		_, err = cache.cachedPermissions(permissionCacheUser+"alice", load)
+		require.NoError(t, err)
+		assert.Equal(t, 1, loads)
+
+		now = now.Add(time.Minute)
+		_, err = cache.cachedPermissions(permissionCacheUser+"alice", load)
+		require.NoError(t, err)
+		assert.Equal(t, 2, loads)
+	})
+
+	t.Run("does not cache errors", func(t *testing.T) {
+		expectedErr := errors.New("database error")
+		_, err := cache.cachedPermissions(permissionCacheRole+"admin", func() (*model.SubjectPermissions, error) { return nil, expectedErr })
+		assert.ErrorIs(t, err, expectedErr)
+		_, _, ok := cache.get(permissionCacheRole + "admin")
+		assert.False(t, ok)
+	})
+
+	t.Run("clear drops the entries and the permissions loaded before", func(t *testing.T) {
+		_, generation, _ := cache.get(permissionCacheToken + "ci")
+		cache.clear()
+		cache.set(permissionCacheToken+"ci", permissions, generation)
+		_, _, ok := cache.get(permissionCacheToken + "ci")
+		assert.False(t, ok)
+		_, _, ok = cache.get(permissionCacheUser + "alice")
+		assert.False(t, ok)
+	})
+
+	t.Run("disabled cache", func(t *testing.T) {
+		var disabled *permissionCache
+		loads = 0
+		_, err := disabled.cachedPermissions(permissionCacheUser+"alice", load)
+		require.NoError(t, err)
+		_, err = disabled.cachedPermissions(permissionCacheUser+"alice", load)
+		require.NoError(t, err)
+		assert.Equal(t, 2, loads)
+		disabled.clear()
+	})
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+)
+
+const (
+	ProbeCheckDatabase   = "database"
+	ProbeCheckMigrations = "migrations"
+	ProbeCheckWorkers    = "workers"
+)
+
+// ProbeService runs the checks of the liveness and readiness probes
+type ProbeService interface {
+	// Liveness checks that no background worker is stuck
+	Liveness(ctx context.Context) *types.HealthReport
+	// Readiness checks that the databases are reachable and up to date, and that no background worker is stuck
+	Readiness(ctx context.Context) *types.HealthReport
+}
+
+type probeService struct {
+	ctx  *appContext.Context
+	repo repository.NamespaceRepository
+}
+
+func NewProbeService(ctx *appContext.Context, repo repository.NamespaceRepository) ProbeService {
+	return &probeService{
+		ctx:  ctx,
+		repo: repo,
+	}
+}
+
+func (s *probeService) Liveness(_ context.Context) *types.HealthReport {
+	report := newHealthReport()
+	s.checkWorkers(report)
+	return report
+}
+
+func (s *probeService) Readiness(ctx context.Context) *types.HealthReport {
+	report := newHealthReport()
+	dbErr, migrationErr := s.checkDatabases(ctx)
+	addHealthCheck(report, ProbeCheckDatabase, dbErr)
+	addHealthCheck(report, ProbeCheckMigrations, migrationErr)
+	s.checkWorkers(report)
+	return report
+}
+
+func (s *probeService) checkWorkers(report *types.HealthReport) {
+	report.Workers = s.ctx.Workers.Workers()
+	var err error
+	for _, worker := range report.Workers {
+		if worker.Stalled {
+			err = fmt.Errorf("worker %s stalled since %s", worker.Name, worker.LastBeat.Format(time.RFC3339))
+			break
+		}
+	}
+	addHealthCheck(report, ProbeCheckWorkers, err)
+}
+
+// checkDatabases pings the primary database and every shard, then checks that all their
+// migrations are applied. Read replicas are not checked.
+func (s *probeService) checkDatabases(ctx context.Context) (dbErr error, migrationErr error) {
+	ctx, cancel := context.WithTimeout(database.WithPrimary(ctx), config.DefaultRequestTimeout)
+	defer cancel()
+
+	db := s.repo.GetTx(ctx)
+	for _, dbCtx := range database.ShardContexts(db, ctx) {
+		var one int
+		if err := db.WithContext(dbCtx).Raw("SELECT 1").Scan(&one).Error; err != nil {
+			name := database.Name(dbCtx)
+			return fmt.Errorf("%s: %w", name, err), fmt.Errorf("%s: not checked, the database is unreachable", name)
+		}
+	}
+	return nil, database.CheckSchema(ctx, db)
+}
+
+// workerTimeout returns the time after which a worker running every interval is reported as stalled,
+// leaving it time for a slow run
+func workerTimeout(interval time.Duration) time.Duration {
+	return 3 * interval
+}
+
+func newHealthReport() *types.HealthReport {
+	return &types.HealthReport{Status: types.HealthStatusOK, Checks: make(map[string]types.HealthCheck)}
+}
+
+// addHealthCheck sets the result of the check, failing the report on error
+func addHealthCheck(report *types.HealthReport, name string, err error) {
+	if err != nil {
+		report.Status = types.HealthStatusFail
+		report.Checks[name] = types.HealthCheck{Status: types.HealthStatusFail, Error: err.Error()}
+		return
+	}
+	report.Checks[name] = types.HealthCheck{Status: types.HealthStatusOK}
+}
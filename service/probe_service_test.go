@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/migrations"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupProbeServiceTest(t *testing.T) (*gorm.DB, *appContext.Context, ProbeService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec("CREATE TABLE schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)").Error)
+
+	ctx := appContext.TestContext(nil)
+	return db, ctx, NewProbeService(ctx, repository.NewNamespaceRepository(db))
+}
+
+func TestProbeService_Readiness(t *testing.T) {
+	latest, err := migrations.LatestVersion()
+	require.NoError(t, err)
+
+	t.Run("ready", func(t *testing.T) {
+		db, ctx, svc := setupProbeServiceTest(t)
+		require.NoError(t, db.Exec("INSERT INTO schema_migrations VALUES (?, ?)", latest, false).Error)
+		ctx.Workers.Register("redirect_expiry", time.Minute)
+
+		report := svc.Readiness(context.Background())
+		assert.Equal(t, types.HealthStatusOK, report.Status)
+		assert.Equal(t, map[string]types.HealthCheck{
+			ProbeCheckDatabase:   {Status: types.HealthStatusOK},
+			ProbeCheckMigrations: {Status: types.HealthStatusOK},
+			ProbeCheckWorkers:    {Status: types.HealthStatusOK},
+		}, report.Checks)
+		require.Len(t, report.Workers, 1)
+		assert.Equal(t, "redirect_expiry", report.Workers[0].Name)
+	})
+
+	t.Run("pending migrations", func(t *testing.T) {
+		db, _, svc := setupProbeServiceTest(t)
+		require.NoError(t, db.Exec("INSERT INTO schema_migrations VALUES (?, ?)", latest-1, false).Error)
+
+		report := svc.Readiness(context.Background())
+		assert.Equal(t, types.HealthStatusFail, report.Status)
+		assert.Equal(t, types.HealthStatusOK, report.Checks[ProbeCheckDatabase].Status)
+		assert.Contains(t, report.Checks[ProbeCheckMigrations].Error, "pending migrations")
+	})
+
+	t.Run("no migration applied", func(t *testing.T) {
+		_, _, svc := setupProbeServiceTest(t)
+
+		report := svc.Readiness(context.Background())
+		assert.Equal(t, types.HealthStatusFail, report.Status)
+		assert.Contains(t, report.Checks[ProbeCheckMigrations].Error, "at version 0")
+	})
+
+	t.Run("dirty migration", func(t *testing.T) {
+		db, _, svc := setupProbeServiceTest(t)
+		require.NoError(t, db.Exec("INSERT INTO schema_migrations VALUES (?, ?)", latest, true).Error)
+
+		report := svc.Readiness(context.Background())
+		assert.Equal(t, types.HealthStatusFail, report.Status)
+		assert.Contains(t, report.Checks[ProbeCheckMigrations].Error, "manual fix required")
+	})
+
+	t.Run("database unreachable", func(t *testing.T) {
+		db, _, svc := setupProbeServiceTest(t)
+		sqlDB, err := db.DB()
+		require.NoError(t, err)
+		require.NoError(t, sqlDB.Close())
+
+		report := svc.Readiness(context.Background())
+		assert.Equal(t, types.HealthStatusFail, report.Status)
+		assert.Equal(t, types.HealthStatusFail, report.Checks[ProbeCheckDatabase].Status)
+		assert.Equal(t, types.HealthStatusFail, report.Checks[ProbeCheckMigrations].Status)
+	})
+}
+
+func TestProbeService_Liveness(t *testing.T) {
+	_, ctx, svc := setupProbeServiceTest(t)
+	ctx.Workers.Register("redirect_import_1", time.Nanosecond)
+
+	time.Sleep(time.Millisecond)
+	report := svc.Liveness(context.Background())
+	assert.Equal(t, types.HealthStatusFail, report.Status)
+	assert.Contains(t, report.Checks[ProbeCheckWorkers].Error, "redirect_import_1")
+	assert.NotContains(t, report.Checks, ProbeCheckDatabase)
+
+	ctx.Workers.Register("redirect_import_1", time.Hour)
+	assert.Equal(t, types.HealthStatusOK, svc.Liveness(context.Background()).Status)
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/jwt"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProjectAPIKeyNotFound      = flectoErrors.New(flectoErrors.CodeNotFound, "project API key not found")
+	ErrProjectAPIKeyAlreadyExists = flectoErrors.New(flectoErrors.CodeAlreadyExists, "project API key with this name already exists")
+	ErrProjectAPIKeyExpired       = flectoErrors.New(flectoErrors.CodeTokenExpired, "project API key has expired")
+	ErrInvalidProjectAPIKey       = flectoErrors.New(flectoErrors.CodeUnauthenticated, "invalid project API key")
+)
+
+// ProjectAPIKeyService manages the API keys limited to the drafts of a single project
+type ProjectAPIKeyService interface {
+	GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectAPIKey, error)
+	// Create creates a key from the name, the scopes and the expiration of the input, returning it with the plain key
+	// which is not stored and cannot be retrieved later
+	Create(ctx context.Context, namespaceCode, projectCode string, input model.ProjectAPIKey) (*model.ProjectAPIKey, string, error)
+	Delete(ctx context.Context, namespaceCode, projectCode string, id int64) (bool, error)
+	// Validate returns the key of a plain key, refusing the unknown and the expired ones
+	Validate(ctx context.Context, plainKey string) (*model.ProjectAPIKey, error)
+}
+
+type projectAPIKeyService struct {
+	ctx         *appContext.Context
+	repo        repository.ProjectAPIKeyRepository
+	projectRepo repository.ProjectRepository
+}
+
+func NewProjectAPIKeyService(ctx *appContext.Context, repo repository.ProjectAPIKeyRepository, projectRepo repository.ProjectRepository) ProjectAPIKeyService {
+	return &projectAPIKeyService{ctx: ctx, repo: repo, projectRepo: projectRepo}
+}
+
+func (s *projectAPIKeyService) GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectAPIKey, error) {
+	return s.repo.FindByProject(ctx, namespaceCode, projectCode)
+}
+
+func (s *projectAPIKeyService) Create(ctx context.Context, namespaceCode, projectCode string, input model.ProjectAPIKey) (*model.ProjectAPIKey, string, error) {
+	scopes := slices.Clone(input.Scopes)
+	slices.Sort(scopes)
+	key := &model.ProjectAPIKey{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		Name:          input.Name,
+		Scopes:        slices.Compact(scopes),
+		ExpiresAt:     input.ExpiresAt,
+		CreatedBy:     types.SubjectFromContext(ctx),
+	}
+	if err := s.ctx.Validator.Struct(key); err != nil {
+		return nil, "", err
+	}
+	if _, err := s.projectRepo.FindByCode(ctx, namespaceCode, projectCode); err != nil {
+		return nil, "", err
+	}
+
+	existing, err := s.repo.FindByName(ctx, namespaceCode, projectCode, key.Name)
+	if err == nil && existing != nil {
+		return nil, "", ErrProjectAPIKeyAlreadyExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", err
+	}
+
+	randomBytes := make([]byte, 32)
+	if _, err = rand.Read(randomBytes); err != nil {
+		return nil, "", err
+	}
+	plainKey := model.ProjectAPIKeyPrefix + base64.RawURLEncoding.EncodeToString(randomBytes)
+	key.KeyHash = jwt.HashToken(plainKey)
+	key.KeyPreview = model.GenerateProjectAPIKeyPreview(plainKey)
+
+	if err = s.repo.Create(ctx, key); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to create project API key", "namespace", namespaceCode, "project", projectCode, "name", key.Name, "error", err)
+		return nil, "", err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "project API key created", "namespace", namespaceCode, "project", projectCode, "name", key.Name, "id", key.ID)
+	return key, plainKey, nil
+}
+
+func (s *projectAPIKeyService) Delete(ctx context.Context, namespaceCode, projectCode string, id int64) (bool, error) {
+	deleted, err := s.repo.Delete(ctx, namespaceCode, projectCode, id)
+	if err != nil {
+		return false, err
+	}
+	if !deleted {
+		return false, ErrProjectAPIKeyNotFound
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "project API key deleted", "namespace", namespaceCode, "project", projectCode, "id", id)
+	return true, nil
+}
+
+func (s *projectAPIKeyService) Validate(ctx context.Context, plainKey string) (*model.ProjectAPIKey, error) {
+	if !strings.HasPrefix(plainKey, model.ProjectAPIKeyPrefix) {
+		s.ctx.Logger.WarnContext(ctx, "project API key validation failed: invalid prefix")
+		return nil, ErrInvalidProjectAPIKey
+	}
+
+	key, err := s.repo.FindByHash(ctx, jwt.HashToken(plainKey))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.ctx.Logger.WarnContext(ctx, "project API key validation failed: key not found")
+			return nil, ErrInvalidProjectAPIKey
+		}
+		return nil, err
+	}
+
+	if key.IsExpired() {
+		s.ctx.Logger.WarnContext(ctx, "project API key validation failed: key expired", "namespace", key.NamespaceCode, "project", key.ProjectCode, "name", key.Name)
+		return nil, ErrProjectAPIKeyExpired
+	}
+	return key, nil
+}
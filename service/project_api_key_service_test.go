@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/jwt"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupProjectAPIKeyServiceTest(t *testing.T) (*gorm.DB, ProjectAPIKeyService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ProjectAPIKey{}))
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test"}).Error)
+
+	ctx := testContextWithPageConfig(defaultProjectCfg)
+	return db, NewProjectAPIKeyService(ctx, repository.NewProjectAPIKeyRepository(db), repository.NewProjectRepository(db))
+}
+
+func TestProjectAPIKeyService_Create(t *testing.T) {
+	ctx := types.WithSubject(context.Background(), "alice")
+
+	t.Run("success", func(t *testing.T) {
+		db, svc := setupProjectAPIKeyServiceTest(t)
+
+		key, plainKey, err := svc.Create(ctx, "test-ns", "test-proj", model.ProjectAPIKey{
+			Name:   "ci",
+			Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeWrite, model.ProjectAPIKeyScopeRead, model.ProjectAPIKeyScopeWrite},
+		})
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(plainKey, model.ProjectAPIKeyPrefix))
+		assert.Equal(t, model.GenerateProjectAPIKeyPreview(plainKey), key.KeyPreview)
+		assert.Equal(t, []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead, model.ProjectAPIKeyScopeWrite}, key.Scopes)
+		assert.Equal(t, "alice", key.CreatedBy)
+
+		// Only the hash of the key is stored
+		var stored model.ProjectAPIKey
+		require.NoError(t, db.First(&stored, key.ID).Error)
+		assert.Equal(t, jwt.HashToken(plainKey), stored.KeyHash)
+	})
+
+	t.Run("name already used in the project", func(t *testing.T) {
+		_, svc := setupProjectAPIKeyServiceTest(t)
+		input := model.ProjectAPIKey{Name: "ci", Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead}}
+
+		_, _, err := svc.Create(ctx, "test-ns", "test-proj", input)
+		require.NoError(t, err)
+		_, _, err = svc.Create(ctx, "test-ns", "test-proj", input)
+		assert.ErrorIs(t, err, ErrProjectAPIKeyAlreadyExists)
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		_, svc := setupProjectAPIKeyServiceTest(t)
+
+		_, _, err := svc.Create(ctx, "test-ns", "unknown", model.ProjectAPIKey{Name: "ci", Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead}})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("invalid scopes", func(t *testing.T) {
+		_, svc := setupProjectAPIKeyServiceTest(t)
+		var validationErrors validator.ValidationErrors
+
+		_, _, err := svc.Create(ctx, "test-ns", "test-proj", model.ProjectAPIKey{Name: "ci"})
+		assert.ErrorAs(t, err, &validationErrors)
+
+		_, _, err = svc.Create(ctx, "test-ns", "test-proj", model.ProjectAPIKey{Name: "ci", Scopes: []model.ProjectAPIKeyScope{"PUBLISH"}})
+		assert.ErrorAs(t, err, &validationErrors)
+	})
+}
+
+func TestProjectAPIKeyService_Validate(t *testing.T) {
+	ctx := context.Background()
+	db, svc := setupProjectAPIKeyServiceTest(t)
+
+	key, plainKey, err := svc.Create(ctx, "test-ns", "test-proj", model.ProjectAPIKey{Name: "ci", Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead}})
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		found, errValidate := svc.Validate(ctx, plainKey)
+		require.NoError(t, errValidate)
+		assert.Equal(t, key.ID, found.ID)
+		assert.Equal(t, "test-proj", found.ProjectCode)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, errValidate := svc.Validate(ctx, model.ProjectAPIKeyPrefix+"unknown")
+		assert.ErrorIs(t, errValidate, ErrInvalidProjectAPIKey)
+
+		_, errValidate = svc.Validate(ctx, "flecto_"+strings.TrimPrefix(plainKey, model.ProjectAPIKeyPrefix))
+		assert.ErrorIs(t, errValidate, ErrInvalidProjectAPIKey)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		require.NoError(t, db.Model(&model.ProjectAPIKey{}).Where("id = ?", key.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error)
+
+		_, errValidate := svc.Validate(ctx, plainKey)
+		assert.ErrorIs(t, errValidate, ErrProjectAPIKeyExpired)
+	})
+}
+
+func TestProjectAPIKeyService_Delete(t *testing.T) {
+	ctx := context.Background()
+	_, svc := setupProjectAPIKeyServiceTest(t)
+
+	key, plainKey, err := svc.Create(ctx, "test-ns", "test-proj", model.ProjectAPIKey{Name: "ci", Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead}})
+	require.NoError(t, err)
+
+	_, err = svc.Delete(ctx, "other-ns", "test-proj", key.ID)
+	assert.ErrorIs(t, err, ErrProjectAPIKeyNotFound)
+
+	deleted, err := svc.Delete(ctx, "test-ns", "test-proj", key.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	keys, err := svc.GetByProject(ctx, "test-ns", "test-proj")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+
+	_, err = svc.Validate(ctx, plainKey)
+	assert.ErrorIs(t, err, ErrInvalidProjectAPIKey)
+}
+
+func TestProjectAPIKeyService_ShardedNamespace(t *testing.T) {
+	db, shard := setupShardedTestDB(t)
+	projectRepo := repository.NewProjectRepository(db)
+	svc := NewProjectAPIKeyService(testContextWithPageConfig(defaultProjectCfg), repository.NewProjectAPIKeyRepository(db), projectRepo)
+	ctx := database.WithNamespace(context.Background(), "ns-eu")
+
+	key, plainKey, err := svc.Create(ctx, "ns-eu", "proj", model.ProjectAPIKey{Name: "ci", Scopes: []model.ProjectAPIKeyScope{model.ProjectAPIKeyScopeRead}})
+	require.NoError(t, err)
+	assert.False(t, shard.Migrator().HasTable(&model.ProjectAPIKey{}))
+
+	// The keys are validated before the namespace of the request is known
+	found, err := svc.Validate(context.Background(), plainKey)
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, found.ID)
+
+	require.NoError(t, projectRepo.Delete(ctx, "ns-eu", "proj"))
+	_, err = svc.Validate(context.Background(), plainKey)
+	assert.ErrorIs(t, err, ErrInvalidProjectAPIKey)
+}
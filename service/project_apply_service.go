@@ -0,0 +1,579 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidManifest   = flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid manifest")
+	ErrManifestDuplicate = flectoErrors.New(flectoErrors.CodeInvalidRequest, "duplicate entry in manifest")
+)
+
+// ProjectApplyService brings the drafts of a project in line with a manifest describing its full
+// desired set of redirects and pages
+type ProjectApplyService interface {
+	Apply(ctx context.Context, namespaceCode, projectCode string, manifest *model.ProjectManifest, opts types.ApplyProjectOptions) (*model.ApplyResult, error)
+}
+
+type projectApplyService struct {
+	ctx            *appContext.Context
+	repo           repository.RedirectDraftRepository
+	projectService ProjectService
+	// notifications is alerted of the manifests deleting most of a project, nil sending no notification
+	notifications NotificationService
+}
+
+func NewProjectApplyService(ctx *appContext.Context, repo repository.RedirectDraftRepository, projectService ProjectService, notifications NotificationService) ProjectApplyService {
+	return &projectApplyService{
+		ctx:            ctx,
+		repo:           repo,
+		projectService: projectService,
+		notifications:  notifications,
+	}
+}
+
+// ParseProjectManifest reads a YAML or JSON manifest, JSON being a subset of YAML.
+// The ids of the manifest entries are ignored, redirects being matched on their source and conditions
+// and pages on their path.
+func ParseProjectManifest(reader io.Reader) (*model.ProjectManifest, error) {
+	var document any
+	if err := yaml.NewDecoder(reader).Decode(&document); err != nil {
+		if errors.Is(err, io.EOF) {
+			// An empty manifest would delete every redirect and page of the project
+			return nil, fmt.Errorf("%w: empty document", ErrInvalidManifest)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	// The document goes through JSON so that the manifest fields are those of the API
+	content, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	manifest := &model.ProjectManifest{}
+	if err = decoder.Decode(manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+
+	for i := range manifest.Redirects {
+		manifest.Redirects[i].ID = 0
+	}
+	for i := range manifest.Pages {
+		manifest.Pages[i].ID = 0
+	}
+	return manifest, nil
+}
+
+// desiredRedirect is a redirect of the manifest, matched is set once a redirect of the project has its source
+type desiredRedirect struct {
+	redirect *commonTypes.Redirect
+	tags     []string
+	matched  bool
+}
+
+// desiredPage is a page of the manifest, matched is set once a page of the project has its path
+type desiredPage struct {
+	page    *commonTypes.Page
+	size    int64
+	matched bool
+}
+
+// Apply computes the differences between the published project and the manifest and rewrites the drafts
+// of the project so that publishing them gives the manifest: drafts of redirects and pages already published
+// as in the manifest are discarded, the other ones are created, updated or marked for deletion.
+// The project is published afterward when requested and the manifest brings changes. A manifest deleting most
+// of the project is refused unless opts.Force is set.
+func (s *projectApplyService) Apply(ctx context.Context, namespaceCode, projectCode string, manifest *model.ProjectManifest, opts types.ApplyProjectOptions) (*model.ApplyResult, error) {
+	redirects, redirectKeys, err := s.desiredRedirects(manifest.Redirects)
+	if err != nil {
+		return nil, err
+	}
+	pages, pagePaths, err := s.desiredPages(manifest.Pages)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = s.projectService.GetByCode(ctx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "project apply started", "namespace", namespaceCode, "project", projectCode, "redirects", len(redirectKeys), "pages", len(pagePaths), "dryRun", opts.DryRun)
+
+	result := &model.ApplyResult{
+		Redirects: []model.ApplyChange{},
+		Pages:     []model.ApplyChange{},
+	}
+	err = s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		if errApply := s.applyRedirects(ctx, tx, namespaceCode, projectCode, redirects, redirectKeys, opts.DryRun, result); errApply != nil {
+			return errApply
+		}
+		if errApply := s.applyPages(ctx, tx, namespaceCode, projectCode, pages, pagePaths, opts.DryRun, result); errApply != nil {
+			return errApply
+		}
+		if opts.DryRun {
+			return nil
+		}
+		return s.guardMassDeletion(ctx, tx, namespaceCode, projectCode, result, opts.Force)
+	})
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "project apply failed", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return nil, err
+	}
+
+	if opts.Publish && !opts.DryRun && result.HasChanges() {
+		project, errPublish := s.projectService.Publish(ctx, namespaceCode, projectCode, types.PublishOptions{Force: opts.Force})
+		if errPublish != nil {
+			return nil, fmt.Errorf("drafts applied but publish failed: %w", errPublish)
+		}
+		result.Published = true
+		result.Version = project.Version
+		if opts.Revision != "" {
+			err = s.repo.GetTx(ctx).Model(&model.Project{}).
+				Where(fmt.Sprintf("%s = ? AND %s = ? AND version = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, project.Version).
+				UpdateColumn("revision", opts.Revision).Error
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "project apply completed", "namespace", namespaceCode, "project", projectCode, "redirects", len(result.Redirects), "pages", len(result.Pages), "unchanged", result.Unchanged, "published", result.Published)
+	return result, nil
+}
+
+// guardMassDeletion refuses the apply when the manifest deletes most of the published redirects or pages
+func (s *projectApplyService) guardMassDeletion(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, result *model.ApplyResult, force bool) error {
+	deletion := model.MassDeletion{Operation: model.MassDeletionOperationApply}
+	for _, change := range result.Redirects {
+		if change.Action == model.ApplyActionDelete {
+			deletion.Redirects++
+		}
+	}
+	for _, change := range result.Pages {
+		if change.Action == model.ApplyActionDelete {
+			deletion.Pages++
+		}
+	}
+	if deletion.Redirects == 0 && deletion.Pages == 0 {
+		return nil
+	}
+	if err := countPublished(tx, namespaceCode, projectCode, &deletion); err != nil {
+		return err
+	}
+	return guardMassDeletion(ctx, s.ctx, s.notifications, namespaceCode, projectCode, deletion, force)
+}
+
+func (s *projectApplyService) desiredRedirects(entries []model.ManifestRedirect) (map[string]*desiredRedirect, []string, error) {
+	desired := make(map[string]*desiredRedirect, len(entries))
+	keys := make([]string, 0, len(entries))
+	for i := range entries {
+		redirect := entries[i].Redirect
+		if err := s.ctx.Validator.Struct(&redirect); err != nil {
+			return nil, nil, fmt.Errorf("redirect %d (%s): %w", i+1, redirect.Source, err)
+		}
+		tags, err := model.NormalizeTagNames(entries[i].Tags)
+		if err != nil {
+			return nil, nil, fmt.Errorf("redirect %d (%s): %w", i+1, redirect.Source, err)
+		}
+		redirect.Conditions = commonTypes.NormalizeRedirectConditions(redirect.Conditions)
+		redirect.Headers = commonTypes.NormalizeResponseHeaders(redirect.Headers)
+
+		key := redirectSourceKey(&redirect)
+		if _, ok := desired[key]; ok {
+			return nil, nil, fmt.Errorf("%w: redirect %d (%s)", ErrManifestDuplicate, i+1, redirect.Source)
+		}
+		desired[key] = &desiredRedirect{redirect: &redirect, tags: tags}
+		keys = append(keys, key)
+	}
+	return desired, keys, nil
+}
+
+func (s *projectApplyService) desiredPages(entries []commonTypes.Page) (map[string]*desiredPage, []string, error) {
+	desired := make(map[string]*desiredPage, len(entries))
+	paths := make([]string, 0, len(entries))
+	var totalSize int64
+	for i := range entries {
+		page := entries[i]
+		size, err := preparePage(s.ctx.CurrentConfig().Page.Markdown, &page)
+		if err != nil {
+			return nil, nil, fmt.Errorf("page %d (%s): %w", i+1, page.Path, err)
+		}
+		if err := s.ctx.Validator.Struct(&page); err != nil {
+			return nil, nil, fmt.Errorf("page %d (%s): %w", i+1, page.Path, err)
+		}
+		// The drafts of the manifest have no lint warnings, only the blocking secrets scanning applies
+		if _, err := checkPageSecrets(s.ctx.CurrentConfig().Page.Secrets, &page); err != nil {
+			return nil, nil, fmt.Errorf("page %d (%s): %w", i+1, page.Path, err)
+		}
+		if size > int64(s.ctx.CurrentConfig().Page.SizeLimit) {
+			return nil, nil, fmt.Errorf("page %d (%s): %w", i+1, page.Path, ErrContentSizeExceeded)
+		}
+		if _, ok := desired[page.Path]; ok {
+			return nil, nil, fmt.Errorf("%w: page %d (%s)", ErrManifestDuplicate, i+1, page.Path)
+		}
+		totalSize += size
+		desired[page.Path] = &desiredPage{page: &page, size: size}
+		paths = append(paths, page.Path)
+	}
+	// The manifest holds every page of the project, its size is the total size once published
+	if totalSize > int64(s.ctx.CurrentConfig().Page.TotalSizeLimit) {
+		return nil, nil, ErrTotalSizeLimitReached
+	}
+	return desired, paths, nil
+}
+
+func (s *projectApplyService) applyRedirects(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, desired map[string]*desiredRedirect, keys []string, dryRun bool, result *model.ApplyResult) error {
+	var redirects []model.Redirect
+	err := tx.Preload("RedirectDraft.Tags").
+		Preload("Tags").
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Order("id").
+		Find(&redirects).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range redirects {
+		redirect := &redirects[i]
+		draft := redirect.RedirectDraft
+
+		// A redirect not published yet only exists through its create draft
+		if redirect.IsPublished == nil || !*redirect.IsPublished {
+			var want *desiredRedirect
+			if draft != nil && draft.NewRedirect != nil {
+				want = claimRedirect(desired, draft.NewRedirect)
+			}
+			if want == nil {
+				if !dryRun {
+					if err = discardRedirect(tx, redirect); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			result.Redirects = append(result.Redirects, model.ApplyChange{Action: model.ApplyActionCreate, Key: redirectChangeKey(want.redirect)})
+			if !dryRun && !redirectMatches(draft.NewRedirect, draft.Tags, want) {
+				if err = s.saveRedirectDraft(ctx, tx, draft, want); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		want := claimRedirect(desired, redirect.Redirect)
+		switch {
+		case want == nil:
+			result.Redirects = append(result.Redirects, model.ApplyChange{Action: model.ApplyActionDelete, Key: redirectChangeKey(redirect.Redirect)})
+			if !dryRun {
+				if err = runPreDraftSaveHooks(ctx, s.ctx, namespaceCode, projectCode, model.DraftChangeTypeDelete, nil, nil); err != nil {
+					return err
+				}
+				if _, err = markRedirectForDeletion(tx, redirect); err != nil {
+					return err
+				}
+			}
+		case redirectMatches(redirect.Redirect, redirect.Tags, want):
+			result.Unchanged++
+			if !dryRun && draft != nil {
+				if err = tx.Select("Tags").Delete(draft).Error; err != nil {
+					return err
+				}
+			}
+		default:
+			result.Redirects = append(result.Redirects, model.ApplyChange{Action: model.ApplyActionUpdate, Key: redirectChangeKey(want.redirect)})
+			if dryRun || (draft != nil && draft.ChangeType == model.DraftChangeTypeUpdate && redirectMatches(draft.NewRedirect, draft.Tags, want)) {
+				continue
+			}
+			if draft == nil {
+				draft = &model.RedirectDraft{
+					NamespaceCode: namespaceCode,
+					ProjectCode:   projectCode,
+					OldRedirectID: types.Ptr(redirect.ID),
+				}
+			}
+			draft.ChangeType = model.DraftChangeTypeUpdate
+			if err = s.saveRedirectDraft(ctx, tx, draft, want); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range keys {
+		want := desired[key]
+		if want.matched {
+			continue
+		}
+		result.Redirects = append(result.Redirects, model.ApplyChange{Action: model.ApplyActionCreate, Key: redirectChangeKey(want.redirect)})
+		if dryRun {
+			continue
+		}
+		redirect := &model.Redirect{
+			NamespaceCode: namespaceCode,
+			ProjectCode:   projectCode,
+			IsPublished:   types.Ptr(false),
+		}
+		if err = tx.Create(redirect).Error; err != nil {
+			return err
+		}
+		draft := &model.RedirectDraft{
+			NamespaceCode: namespaceCode,
+			ProjectCode:   projectCode,
+			OldRedirectID: types.Ptr(redirect.ID),
+			ChangeType:    model.DraftChangeTypeCreate,
+		}
+		if err = s.saveRedirectDraft(ctx, tx, draft, want); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimRedirect returns the redirect of the manifest having the source and conditions of the redirect,
+// unless already matched by another redirect of the project
+func claimRedirect(desired map[string]*desiredRedirect, redirect *commonTypes.Redirect) *desiredRedirect {
+	if redirect == nil {
+		return nil
+	}
+	want, ok := desired[redirectSourceKey(redirect)]
+	if !ok || want.matched {
+		return nil
+	}
+	want.matched = true
+	return want
+}
+
+// redirectMatches reports whether a redirect and its tags are those of the manifest
+func redirectMatches(redirect *commonTypes.Redirect, tags []model.Tag, want *desiredRedirect) bool {
+	return redirectsAreEqual(redirect, want.redirect) &&
+		timesAreEqual(redirect.ValidFrom, want.redirect.ValidFrom) &&
+		timesAreEqual(redirect.ValidUntil, want.redirect.ValidUntil) &&
+		tagsAreUnchanged(tags, want.tags)
+}
+
+func timesAreEqual(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func redirectChangeKey(redirect *commonTypes.Redirect) string {
+	if len(redirect.Conditions) == 0 {
+		return redirect.Source
+	}
+	return redirect.Source + " " + commonTypes.RedirectConditionsKey(redirect.Conditions)
+}
+
+// saveRedirectDraft creates or updates the draft with the redirect and tags of the manifest, once accepted by the
+// pre-draft-save hooks
+func (s *projectApplyService) saveRedirectDraft(ctx context.Context, tx *gorm.DB, draft *model.RedirectDraft, want *desiredRedirect) error {
+	redirect := *want.redirect
+	if err := runPreDraftSaveHooks(ctx, s.ctx, draft.NamespaceCode, draft.ProjectCode, draft.ChangeType, &redirect, nil); err != nil {
+		return err
+	}
+	tags, err := findOrCreateTags(tx, draft.NamespaceCode, draft.ProjectCode, want.tags)
+	if err != nil {
+		return err
+	}
+	draft.NewRedirect = &redirect
+	if err = tx.Omit(clause.Associations).Save(draft).Error; err != nil {
+		return err
+	}
+	if err = tx.Model(draft).Association("Tags").Replace(tags); err != nil {
+		return err
+	}
+	draft.Tags = tags
+	return nil
+}
+
+// discardRedirect deletes a redirect never published along with its create draft
+func discardRedirect(tx *gorm.DB, redirect *model.Redirect) error {
+	if redirect.RedirectDraft != nil {
+		if err := tx.Select("Tags").Delete(redirect.RedirectDraft).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Delete(&model.Redirect{}, redirect.ID).Error
+}
+
+func (s *projectApplyService) applyPages(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, desired map[string]*desiredPage, paths []string, dryRun bool, result *model.ApplyResult) error {
+	var pages []model.Page
+	err := tx.Preload("PageDraft").
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Order("id").
+		Find(&pages).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range pages {
+		page := &pages[i]
+		draft := page.PageDraft
+
+		// A page not published yet only exists through its create draft
+		if page.IsPublished == nil || !*page.IsPublished {
+			var want *desiredPage
+			if draft != nil && draft.NewPage != nil {
+				want = claimPage(desired, draft.NewPage)
+			}
+			if want == nil {
+				if !dryRun {
+					if err = discardPage(tx, page); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+			result.Pages = append(result.Pages, model.ApplyChange{Action: model.ApplyActionCreate, Key: want.page.Path})
+			if !dryRun && !pagesAreEqual(draft.NewPage, want.page) {
+				if err = s.savePageDraft(ctx, tx, draft, want); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		want := claimPage(desired, page.Page)
+		switch {
+		case want == nil:
+			result.Pages = append(result.Pages, model.ApplyChange{Action: model.ApplyActionDelete, Key: page.Path})
+			if dryRun || (draft != nil && draft.ChangeType == model.DraftChangeTypeDelete) {
+				continue
+			}
+			if draft == nil {
+				draft = &model.PageDraft{
+					NamespaceCode: namespaceCode,
+					ProjectCode:   projectCode,
+					OldPageID:     types.Ptr(page.ID),
+				}
+			}
+			if err = runPreDraftSaveHooks(ctx, s.ctx, namespaceCode, projectCode, model.DraftChangeTypeDelete, nil, nil); err != nil {
+				return err
+			}
+			draft.ChangeType = model.DraftChangeTypeDelete
+			draft.NewPage = nil
+			draft.ContentSize = 0
+			if err = tx.Omit(clause.Associations).Save(draft).Error; err != nil {
+				return err
+			}
+		case pagesAreEqual(page.Page, want.page):
+			result.Unchanged++
+			if !dryRun && draft != nil {
+				if err = tx.Delete(draft).Error; err != nil {
+					return err
+				}
+			}
+		default:
+			result.Pages = append(result.Pages, model.ApplyChange{Action: model.ApplyActionUpdate, Key: want.page.Path})
+			if dryRun || (draft != nil && draft.ChangeType == model.DraftChangeTypeUpdate && pagesAreEqual(draft.NewPage, want.page)) {
+				continue
+			}
+			if draft == nil {
+				draft = &model.PageDraft{
+					NamespaceCode: namespaceCode,
+					ProjectCode:   projectCode,
+					OldPageID:     types.Ptr(page.ID),
+				}
+			}
+			draft.ChangeType = model.DraftChangeTypeUpdate
+			if err = s.savePageDraft(ctx, tx, draft, want); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, path := range paths {
+		want := desired[path]
+		if want.matched {
+			continue
+		}
+		result.Pages = append(result.Pages, model.ApplyChange{Action: model.ApplyActionCreate, Key: path})
+		if dryRun {
+			continue
+		}
+		page := &model.Page{
+			NamespaceCode: namespaceCode,
+			ProjectCode:   projectCode,
+			IsPublished:   types.Ptr(false),
+		}
+		if err = tx.Create(page).Error; err != nil {
+			return err
+		}
+		draft := &model.PageDraft{
+			NamespaceCode: namespaceCode,
+			ProjectCode:   projectCode,
+			OldPageID:     types.Ptr(page.ID),
+			ChangeType:    model.DraftChangeTypeCreate,
+		}
+		if err = s.savePageDraft(ctx, tx, draft, want); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// claimPage returns the page of the manifest having the path of the page, unless already matched
+// by another page of the project
+func claimPage(desired map[string]*desiredPage, page *commonTypes.Page) *desiredPage {
+	if page == nil {
+		return nil
+	}
+	want, ok := desired[page.Path]
+	if !ok || want.matched {
+		return nil
+	}
+	want.matched = true
+	return want
+}
+
+func pagesAreEqual(a, b *commonTypes.Page) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Type == b.Type &&
+		a.Path == b.Path &&
+		a.ContentType == b.ContentType &&
+		a.MimeType == b.MimeType &&
+		a.Content == b.Content &&
+		a.Source == b.Source &&
+		maps.Equal(a.Headers, b.Headers)
+}
+
+// savePageDraft creates or updates the draft with the page of the manifest, once accepted by the pre-draft-save hooks
+func (s *projectApplyService) savePageDraft(ctx context.Context, tx *gorm.DB, draft *model.PageDraft, want *desiredPage) error {
+	page := *want.page
+	if err := runPreDraftSaveHooks(ctx, s.ctx, draft.NamespaceCode, draft.ProjectCode, draft.ChangeType, nil, &page); err != nil {
+		return err
+	}
+	draft.NewPage = &page
+	draft.ContentSize = want.size
+	return tx.Omit(clause.Associations).Save(draft).Error
+}
+
+// discardPage deletes a page never published along with its create draft
+func discardPage(tx *gorm.DB, page *model.Page) error {
+	if page.PageDraft != nil {
+		if err := tx.Delete(page.PageDraft).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Delete(&model.Page{}, page.ID).Error
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/extension"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupProjectApplyServiceTest(t *testing.T) (*gorm.DB, ProjectApplyService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Tag{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 1}).Error)
+
+	ctx := testContextWithPageConfig(defaultProjectCfg)
+	redirectDraftRepo := repository.NewRedirectDraftRepository(db)
+	projectSrv := NewProjectService(ctx, repository.NewProjectRepository(db), repository.NewPageRepository(db), redirectDraftRepo, repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+	return db, NewProjectApplyService(ctx, redirectDraftRepo, projectSrv, nil)
+}
+
+// seedProjectApplyTest publishes /keep, /change and /remove redirects and the /keep.txt and /remove.txt pages,
+// and drafts a /draft redirect
+func seedProjectApplyTest(t *testing.T, db *gorm.DB) {
+	for _, source := range []string{"/keep", "/change", "/remove"} {
+		require.NoError(t, db.Create(&model.Redirect{
+			NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true),
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+		}).Error)
+	}
+	for _, path := range []string{"/keep.txt", "/remove.txt"} {
+		require.NoError(t, db.Create(&model.Page{
+			NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true), ContentSize: 4,
+			Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: path, Content: "text", ContentType: commonTypes.PageContentTypeTextPlain},
+		}).Error)
+	}
+	redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(false)}
+	require.NoError(t, db.Create(redirect).Error)
+	require.NoError(t, db.Create(&model.RedirectDraft{
+		NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &redirect.ID,
+		NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/draft", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+	}).Error)
+}
+
+const testProjectManifest = `
+redirects:
+  - type: BASIC
+    source: /keep
+    target: /target
+    status: MOVED_PERMANENT
+  - type: BASIC
+    source: /change
+    target: /elsewhere
+    status: MOVED_PERMANENT
+    tags: [summer]
+  - type: BASIC
+    source: /new
+    target: /target
+    status: FOUND
+pages:
+  - type: BASIC
+    path: /keep.txt
+    content: text
+    contentType: TEXT_PLAIN
+  - type: BASIC
+    path: /new.txt
+    content: new
+    contentType: TEXT_PLAIN
+`
+
+func TestParseProjectManifest(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		manifest, err := ParseProjectManifest(strings.NewReader(testProjectManifest))
+		require.NoError(t, err)
+		require.Len(t, manifest.Redirects, 3)
+		assert.Equal(t, "/change", manifest.Redirects[1].Source)
+		assert.Equal(t, []string{"summer"}, manifest.Redirects[1].Tags)
+		require.Len(t, manifest.Pages, 2)
+		assert.Equal(t, commonTypes.PageContentTypeTextPlain, manifest.Pages[0].ContentType)
+	})
+
+	t.Run("json ignoring ids", func(t *testing.T) {
+		manifest, err := ParseProjectManifest(strings.NewReader(`{"redirects": [{"id": 12, "source": "/a"}]}`))
+		require.NoError(t, err)
+		require.Len(t, manifest.Redirects, 1)
+		assert.Equal(t, int64(0), manifest.Redirects[0].ID)
+		assert.Empty(t, manifest.Pages)
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "empty", content: "", wantErr: "invalid manifest: empty document"},
+		{name: "not yaml", content: "redirects: [", wantErr: "invalid manifest"},
+		{name: "unknown field", content: "redirect: []", wantErr: "unknown field \"redirect\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProjectManifest(strings.NewReader(tt.content))
+			assert.ErrorIs(t, err, ErrInvalidManifest)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProjectApplyService_Apply(t *testing.T) {
+	ctx := context.Background()
+	manifest, err := ParseProjectManifest(strings.NewReader(testProjectManifest))
+	require.NoError(t, err)
+
+	wantRedirects := []model.ApplyChange{
+		{Action: model.ApplyActionUpdate, Key: "/change"},
+		{Action: model.ApplyActionDelete, Key: "/remove"},
+		{Action: model.ApplyActionCreate, Key: "/new"},
+	}
+	wantPages := []model.ApplyChange{
+		{Action: model.ApplyActionDelete, Key: "/remove.txt"},
+		{Action: model.ApplyActionCreate, Key: "/new.txt"},
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		db, svc := setupProjectApplyServiceTest(t)
+		seedProjectApplyTest(t, db)
+
+		result, err := svc.Apply(ctx, "test-ns", "test-proj", manifest, types.ApplyProjectOptions{DryRun: true, Publish: true})
+		require.NoError(t, err)
+		assert.Equal(t, wantRedirects, result.Redirects)
+		assert.Equal(t, wantPages, result.Pages)
+		assert.Equal(t, 2, result.Unchanged)
+		assert.False(t, result.Published)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(1), draftCount)
+	})
+
+	t.Run("drafts", func(t *testing.T) {
+		db, svc := setupProjectApplyServiceTest(t)
+		seedProjectApplyTest(t, db)
+
+		result, err := svc.Apply(ctx, "test-ns", "test-proj", manifest, types.ApplyProjectOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, wantRedirects, result.Redirects)
+		assert.Equal(t, wantPages, result.Pages)
+
+		var drafts []model.RedirectDraft
+		require.NoError(t, db.Preload("Tags").Order("id").Find(&drafts).Error)
+		require.Len(t, drafts, 3)
+		assert.Equal(t, model.DraftChangeTypeUpdate, drafts[0].ChangeType)
+		assert.Equal(t, "/elsewhere", drafts[0].NewRedirect.Target)
+		require.Len(t, drafts[0].Tags, 1)
+		assert.Equal(t, "summer", drafts[0].Tags[0].Name)
+		assert.Equal(t, model.DraftChangeTypeDelete, drafts[1].ChangeType)
+		assert.Equal(t, model.DraftChangeTypeCreate, drafts[2].ChangeType)
+		assert.Equal(t, "/new", drafts[2].NewRedirect.Source)
+
+		// The redirect only drafted is not in the manifest
+		var redirectCount int64
+		db.Model(&model.Redirect{}).Where("is_published = ?", false).Count(&redirectCount)
+		assert.Equal(t, int64(1), redirectCount)
+
+		// Applying the same manifest again keeps the drafts
+		again, err := svc.Apply(ctx, "test-ns", "test-proj", manifest, types.ApplyProjectOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, result, again)
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(3), draftCount)
+	})
+
+	t.Run("publish", func(t *testing.T) {
+		db, svc := setupProjectApplyServiceTest(t)
+		seedProjectApplyTest(t, db)
+
+		result, err := svc.Apply(ctx, "test-ns", "test-proj", manifest, types.ApplyProjectOptions{Publish: true})
+		require.NoError(t, err)
+		assert.True(t, result.Published)
+		assert.Equal(t, 2, result.Version)
+
+		var redirects []model.Redirect
+		require.NoError(t, db.Order("source").Find(&redirects).Error)
+		require.Len(t, redirects, 3)
+		assert.Equal(t, "/change", redirects[0].Source)
+		assert.Equal(t, "/elsewhere", redirects[0].Target)
+		assert.Equal(t, "/keep", redirects[1].Source)
+		assert.Equal(t, "/new", redirects[2].Source)
+
+		var pages []model.Page
+		require.NoError(t, db.Order("path").Find(&pages).Error)
+		require.Len(t, pages, 2)
+		assert.Equal(t, "/keep.txt", pages[0].Path)
+		assert.Equal(t, "/new.txt", pages[1].Path)
+
+		// The project now matches its manifest
+		result, err = svc.Apply(ctx, "test-ns", "test-proj", manifest, types.ApplyProjectOptions{Publish: true})
+		require.NoError(t, err)
+		assert.False(t, result.HasChanges())
+		assert.Equal(t, 5, result.Unchanged)
+		assert.False(t, result.Published)
+	})
+
+	t.Run("mass deletion", func(t *testing.T) {
+		db, _ := setupProjectApplyServiceTest(t)
+		seedProjectApplyTest(t, db)
+		appCtx := testContextWithPageConfig(defaultProjectCfg)
+		appCtx.Config.Publish.MassDeletion.MinCount = 1
+		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
+		projectSrv := NewProjectService(appCtx, repository.NewProjectRepository(db), repository.NewPageRepository(db), redirectDraftRepo, repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+		svc := NewProjectApplyService(appCtx, redirectDraftRepo, projectSrv, nil)
+		empty := &model.ProjectManifest{}
+
+		_, err := svc.Apply(ctx, "test-ns", "test-proj", empty, types.ApplyProjectOptions{Publish: true})
+		assert.ErrorIs(t, err, ErrMassDeletion)
+		var count int64
+		require.NoError(t, db.Model(&model.RedirectDraft{}).Where("change_type = ?", model.DraftChangeTypeDelete).Count(&count).Error)
+		assert.Zero(t, count)
+
+		// The dry runs report the deletions
+		result, err := svc.Apply(ctx, "test-ns", "test-proj", empty, types.ApplyProjectOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.Len(t, result.Redirects, 3)
+
+		result, err = svc.Apply(ctx, "test-ns", "test-proj", empty, types.ApplyProjectOptions{Publish: true, Force: true})
+		require.NoError(t, err)
+		assert.True(t, result.Published)
+		require.NoError(t, db.Model(&model.Redirect{}).Count(&count).Error)
+		assert.Zero(t, count)
+	})
+
+	t.Run("pre-draft-save hooks of the extensions", func(t *testing.T) {
+		db, svc := setupProjectApplyServiceTest(t)
+		seedProjectApplyTest(t, db)
+		extensions := svc.(*projectApplyService).ctx.Extensions
+		extensions.RegisterPreDraftSave("lowercase", func(ctx context.Context, draft *extension.DraftSave) error {
+			if draft.Redirect != nil {
+				draft.Redirect.Target = strings.ToLower(draft.Redirect.Target)
+			}
+			return nil
+		})
+		extensions.RegisterPreDraftSave("no-delete", func(ctx context.Context, draft *extension.DraftSave) error {
+			if draft.ChangeType == string(model.DraftChangeTypeDelete) {
+				return errors.New("deletions are reviewed by the SEO team")
+			}
+			return nil
+		})
+
+		// The manifest deletes /remove and /remove.txt, the whole apply is refused
+		_, err := svc.Apply(ctx, "test-ns", "test-proj", manifest, types.ApplyProjectOptions{})
+		assert.ErrorIs(t, err, ErrDraftRefused)
+		var draftCount int64
+		require.NoError(t, db.Model(&model.RedirectDraft{}).Count(&draftCount).Error)
+		assert.Equal(t, int64(1), draftCount)
+
+		keepAll := &model.ProjectManifest{
+			Redirects: append([]model.ManifestRedirect{}, manifest.Redirects...),
+			Pages:     append([]commonTypes.Page{}, manifest.Pages...),
+		}
+		keepAll.Redirects = append(keepAll.Redirects, model.ManifestRedirect{Redirect: commonTypes.Redirect{
+			Type: commonTypes.RedirectTypeBasic, Source: "/remove", Target: "/TARGET", Status: commonTypes.RedirectStatusMovedPermanent,
+		}})
+		keepAll.Pages = append(keepAll.Pages, commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/remove.txt", Content: "text", ContentType: commonTypes.PageContentTypeTextPlain})
+		_, err = svc.Apply(ctx, "test-ns", "test-proj", keepAll, types.ApplyProjectOptions{})
+		require.NoError(t, err)
+		var draft model.RedirectDraft
+		require.NoError(t, db.Where("new_source = ?", "/remove").First(&draft).Error)
+		assert.Equal(t, "/target", draft.NewRedirect.Target)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, svc := setupProjectApplyServiceTest(t)
+		redirect := commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/b", Status: commonTypes.RedirectStatusFound}
+		page := commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/a.txt", Content: "a", ContentType: commonTypes.PageContentTypeTextPlain}
+
+		tests := []struct {
+			name     string
+			project  string
+			manifest *model.ProjectManifest
+			wantErr  error
+		}{
+			{
+				name:     "duplicate redirect",
+				project:  "test-proj",
+				manifest: &model.ProjectManifest{Redirects: []model.ManifestRedirect{{Redirect: redirect}, {Redirect: redirect}}},
+				wantErr:  ErrManifestDuplicate,
+			},
+			{
+				name:     "duplicate page",
+				project:  "test-proj",
+				manifest: &model.ProjectManifest{Pages: []commonTypes.Page{page, page}},
+				wantErr:  ErrManifestDuplicate,
+			},
+			{
+				name:    "page too large",
+				project: "test-proj",
+				manifest: &model.ProjectManifest{Pages: []commonTypes.Page{
+					{Type: commonTypes.PageTypeBasic, Path: "/a.txt", Content: strings.Repeat("a", 1025), ContentType: commonTypes.PageContentTypeTextPlain},
+				}},
+				wantErr: ErrContentSizeExceeded,
+			},
+			{
+				name:     "unknown project",
+				project:  "other",
+				manifest: &model.ProjectManifest{},
+				wantErr:  gorm.ErrRecordNotFound,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := svc.Apply(ctx, "test-ns", tt.project, tt.manifest, types.ApplyProjectOptions{})
+				assert.ErrorIs(t, err, tt.wantErr)
+			})
+		}
+
+		_, err := svc.Apply(ctx, "test-ns", "test-proj", &model.ProjectManifest{Redirects: []model.ManifestRedirect{{Redirect: commonTypes.Redirect{Source: "/a"}}}}, types.ApplyProjectOptions{})
+		assert.ErrorContains(t, err, "redirect 1 (/a)")
+	})
+}
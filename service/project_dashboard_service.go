@@ -6,7 +6,6 @@ import (
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
-	"github.com/flectolab/flecto-manager/model"
 )
 
 type ProjectDashboardStats struct {
@@ -15,11 +14,15 @@ type ProjectDashboardStats struct {
 	PublishedAt *time.Time
 
 	// Redirect stats
-	RedirectTotal          int64
-	RedirectCountBasic     int64
-	RedirectCountBasicHost int64
-	RedirectCountRegex     int64
-	RedirectCountRegexHost int64
+	RedirectTotal               int64
+	RedirectCountBasic          int64
+	RedirectCountBasicHost      int64
+	RedirectCountRegex          int64
+	RedirectCountRegexHost      int64
+	RedirectCountMovedPermanent int64
+	RedirectCountFound          int64
+	RedirectCountTemporary      int64
+	RedirectCountPermanent      int64
 
 	// Redirect draft stats
 	RedirectDraftTotal       int64
@@ -28,9 +31,11 @@ type ProjectDashboardStats struct {
 	RedirectDraftCountDelete int64
 
 	// Page stats
-	PageTotal          int64
-	PageCountBasic     int64
-	PageCountBasicHost int64
+	PageTotal            int64
+	PageCountBasic       int64
+	PageCountBasicHost   int64
+	PageContentSize      int64
+	PageContentSizeLimit int64
 
 	// Page draft stats
 	PageDraftTotal       int64
@@ -48,146 +53,57 @@ type ProjectDashboardService interface {
 }
 
 type projectDashboardService struct {
-	ctx                  *appContext.Context
-	projectService       ProjectService
-	redirectService      RedirectService
-	redirectDraftService RedirectDraftService
-	pageService          PageService
-	pageDraftService     PageDraftService
-	agentService         AgentService
+	ctx            *appContext.Context
+	projectService ProjectService
+	agentService   AgentService
 }
 
 func NewProjectDashboardService(
 	ctx *appContext.Context,
 	projectService ProjectService,
-	redirectService RedirectService,
-	redirectDraftService RedirectDraftService,
-	pageService PageService,
-	pageDraftService PageDraftService,
 	agentService AgentService,
 ) ProjectDashboardService {
 	return &projectDashboardService{
-		ctx:                  ctx,
-		projectService:       projectService,
-		redirectService:      redirectService,
-		redirectDraftService: redirectDraftService,
-		pageService:          pageService,
-		pageDraftService:     pageDraftService,
-		agentService:         agentService,
+		ctx:            ctx,
+		projectService: projectService,
+		agentService:   agentService,
 	}
 }
 
 func (s *projectDashboardService) GetStats(ctx context.Context, namespaceCode, projectCode string) (*ProjectDashboardStats, error) {
-	stats := &ProjectDashboardStats{}
-
-	// Get project info
-	project, err := s.projectService.GetByCode(ctx, namespaceCode, projectCode)
+	// Get project, redirect and page stats
+	projectStats, err := s.projectService.GetProjectStats(ctx, namespaceCode, projectCode)
 	if err != nil {
 		return nil, err
 	}
-	stats.Version = project.Version
-	if !project.PublishedAt.IsZero() {
-		stats.PublishedAt = &project.PublishedAt
-	}
-
-	// Get redirect stats by type
-	type redirectTypeCount struct {
-		Type  commonTypes.RedirectType
-		Count int64
-	}
-	var redirectCounts []redirectTypeCount
-	if err = s.redirectService.GetQuery(ctx).
-		Select("type, count(*) as count").
-		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
-		Group("type").
-		Scan(&redirectCounts).Error; err != nil {
-		return nil, err
-	}
-
-	for _, rc := range redirectCounts {
-		stats.RedirectTotal += rc.Count
-		switch rc.Type {
-		case commonTypes.RedirectTypeBasic:
-			stats.RedirectCountBasic = rc.Count
-		case commonTypes.RedirectTypeBasicHost:
-			stats.RedirectCountBasicHost = rc.Count
-		case commonTypes.RedirectTypeRegex:
-			stats.RedirectCountRegex = rc.Count
-		case commonTypes.RedirectTypeRegexHost:
-			stats.RedirectCountRegexHost = rc.Count
-		}
-	}
-
-	// Get redirect draft stats by change type
-	type draftTypeCount struct {
-		ChangeType model.DraftChangeType
-		Count      int64
-	}
-	var redirectDraftCounts []draftTypeCount
-	if err = s.redirectDraftService.GetQuery(ctx).
-		Select("change_type, count(*) as count").
-		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
-		Group("change_type").
-		Scan(&redirectDraftCounts).Error; err != nil {
-		return nil, err
-	}
-
-	for _, dc := range redirectDraftCounts {
-		stats.RedirectDraftTotal += dc.Count
-		switch dc.ChangeType {
-		case model.DraftChangeTypeCreate:
-			stats.RedirectDraftCountCreate = dc.Count
-		case model.DraftChangeTypeUpdate:
-			stats.RedirectDraftCountUpdate = dc.Count
-		case model.DraftChangeTypeDelete:
-			stats.RedirectDraftCountDelete = dc.Count
-		}
-	}
-
-	// Get page stats by type
-	type pageTypeCount struct {
-		Type  commonTypes.PageType
-		Count int64
-	}
-	var pageCounts []pageTypeCount
-	if err = s.pageService.GetQuery(ctx).
-		Select("type, count(*) as count").
-		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
-		Group("type").
-		Scan(&pageCounts).Error; err != nil {
-		return nil, err
-	}
-
-	for _, pc := range pageCounts {
-		stats.PageTotal += pc.Count
-		switch pc.Type {
-		case commonTypes.PageTypeBasic:
-			stats.PageCountBasic = pc.Count
-		case commonTypes.PageTypeBasicHost:
-			stats.PageCountBasicHost = pc.Count
-		}
-	}
-
-	// Get page draft stats by change type
-	var pageDraftCounts []draftTypeCount
-	if err = s.pageDraftService.GetQuery(ctx).
-		Select("change_type, count(*) as count").
-		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
-		Group("change_type").
-		Scan(&pageDraftCounts).Error; err != nil {
-		return nil, err
-	}
 
-	for _, dc := range pageDraftCounts {
-		stats.PageDraftTotal += dc.Count
-		switch dc.ChangeType {
-		case model.DraftChangeTypeCreate:
-			stats.PageDraftCountCreate = dc.Count
-		case model.DraftChangeTypeUpdate:
-			stats.PageDraftCountUpdate = dc.Count
-		case model.DraftChangeTypeDelete:
-			stats.PageDraftCountDelete = dc.Count
-		}
+	stats := &ProjectDashboardStats{
+		Version:                     projectStats.Version,
+		RedirectTotal:               projectStats.RedirectTotal,
+		RedirectCountBasic:          projectStats.RedirectCountBasic,
+		RedirectCountBasicHost:      projectStats.RedirectCountBasicHost,
+		RedirectCountRegex:          projectStats.RedirectCountRegex,
+		RedirectCountRegexHost:      projectStats.RedirectCountRegexHost,
+		RedirectCountMovedPermanent: projectStats.RedirectCountMovedPermanent,
+		RedirectCountFound:          projectStats.RedirectCountFound,
+		RedirectCountTemporary:      projectStats.RedirectCountTemporary,
+		RedirectCountPermanent:      projectStats.RedirectCountPermanent,
+		RedirectDraftTotal:          projectStats.RedirectDraftTotal,
+		RedirectDraftCountCreate:    projectStats.RedirectDraftCountCreate,
+		RedirectDraftCountUpdate:    projectStats.RedirectDraftCountUpdate,
+		RedirectDraftCountDelete:    projectStats.RedirectDraftCountDelete,
+		PageTotal:                   projectStats.PageTotal,
+		PageCountBasic:              projectStats.PageCountBasic,
+		PageCountBasicHost:          projectStats.PageCountBasicHost,
+		PageContentSize:             projectStats.PageContentSize,
+		PageContentSizeLimit:        projectStats.PageContentSizeLimit,
+		PageDraftTotal:              projectStats.PageDraftTotal,
+		PageDraftCountCreate:        projectStats.PageDraftCountCreate,
+		PageDraftCountUpdate:        projectStats.PageDraftCountUpdate,
+		PageDraftCountDelete:        projectStats.PageDraftCountDelete,
+	}
+	if !projectStats.PublishedAt.IsZero() {
+		stats.PublishedAt = &projectStats.PublishedAt
 	}
 
 	// Get agent stats
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrProjectMemberNotFound      = flectoErrors.New(flectoErrors.CodeNotFound, "project member not found")
+	ErrProjectMemberAlreadyExists = flectoErrors.New(flectoErrors.CodeAlreadyExists, "user is already a member of the project")
+	ErrProjectMemberOwnerRole     = flectoErrors.New(flectoErrors.CodeInvalidRequest, "the owner of a project is set by transferring the ownership")
+	ErrProjectOwnerRemoval        = flectoErrors.New(flectoErrors.CodeConflict, "the owner of a project cannot be removed or demoted, transfer the ownership first")
+)
+
+// ProjectMemberService manages the users granted a role scoped to one project, on top of the roles of the users.
+// The permissions of the members are merged into the permissions of the users by the role service.
+type ProjectMemberService interface {
+	GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectMember, error)
+	// IsOwner returns true if the user owns the project
+	IsOwner(ctx context.Context, namespaceCode, projectCode string, userID int64) (bool, error)
+	// Add adds the user to the project as an editor or a viewer
+	Add(ctx context.Context, namespaceCode, projectCode, username string, role model.ProjectMemberRole) (*model.ProjectMember, error)
+	// UpdateRole changes the role of a member between editor and viewer
+	UpdateRole(ctx context.Context, namespaceCode, projectCode, username string, role model.ProjectMemberRole) (*model.ProjectMember, error)
+	// Remove removes a member other than the owner from the project
+	Remove(ctx context.Context, namespaceCode, projectCode, username string) (bool, error)
+	// TransferOwnership makes the user the owner of the project, the previous owner staying an editor
+	TransferOwnership(ctx context.Context, namespaceCode, projectCode, username string) (*model.ProjectMember, error)
+}
+
+type projectMemberService struct {
+	ctx         *appContext.Context
+	repo        repository.ProjectMemberRepository
+	projectRepo repository.ProjectRepository
+	userRepo    repository.UserRepository
+	bus         invalidation.Bus
+}
+
+func NewProjectMemberService(
+	ctx *appContext.Context,
+	repo repository.ProjectMemberRepository,
+	projectRepo repository.ProjectRepository,
+	userRepo repository.UserRepository,
+	bus invalidation.Bus,
+) ProjectMemberService {
+	return &projectMemberService{
+		ctx:         ctx,
+		repo:        repo,
+		projectRepo: projectRepo,
+		userRepo:    userRepo,
+		bus:         bus,
+	}
+}
+
+func (s *projectMemberService) GetByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectMember, error) {
+	return s.repo.FindByProject(ctx, namespaceCode, projectCode)
+}
+
+func (s *projectMemberService) IsOwner(ctx context.Context, namespaceCode, projectCode string, userID int64) (bool, error) {
+	member, err := s.repo.FindByProjectAndUser(ctx, namespaceCode, projectCode, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return member.IsOwner(), nil
+}
+
+func (s *projectMemberService) Add(ctx context.Context, namespaceCode, projectCode, username string, role model.ProjectMemberRole) (*model.ProjectMember, error) {
+	if role == model.ProjectMemberRoleOwner {
+		return nil, ErrProjectMemberOwnerRole
+	}
+	if _, err := s.projectRepo.FindByCode(ctx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	member := &model.ProjectMember{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		UserID:        user.ID,
+		Role:          role,
+		CreatedBy:     types.SubjectFromContext(ctx),
+	}
+	if err = s.ctx.Validator.Struct(member); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByProjectAndUser(ctx, namespaceCode, projectCode, user.ID)
+	if err == nil && existing != nil {
+		return nil, ErrProjectMemberAlreadyExists
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err = s.repo.Create(ctx, member); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to add project member", "namespace", namespaceCode, "project", projectCode, "username", username, "error", err)
+		return nil, err
+	}
+	member.User = user
+
+	s.ctx.Logger.InfoContext(ctx, "project member added", "namespace", namespaceCode, "project", projectCode, "username", username, "role", role)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
+	return member, nil
+}
+
+func (s *projectMemberService) UpdateRole(ctx context.Context, namespaceCode, projectCode, username string, role model.ProjectMemberRole) (*model.ProjectMember, error) {
+	if role == model.ProjectMemberRoleOwner {
+		return nil, ErrProjectMemberOwnerRole
+	}
+	member, err := s.findMember(ctx, namespaceCode, projectCode, username)
+	if err != nil {
+		return nil, err
+	}
+	if member.IsOwner() {
+		return nil, ErrProjectOwnerRemoval
+	}
+
+	member.Role = role
+	if err = s.ctx.Validator.Struct(member); err != nil {
+		return nil, err
+	}
+	if err = s.repo.UpdateRole(ctx, member.ID, role); err != nil {
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "project member role updated", "namespace", namespaceCode, "project", projectCode, "username", username, "role", role)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
+	return member, nil
+}
+
+func (s *projectMemberService) Remove(ctx context.Context, namespaceCode, projectCode, username string) (bool, error) {
+	member, err := s.findMember(ctx, namespaceCode, projectCode, username)
+	if err != nil {
+		return false, err
+	}
+	if member.IsOwner() {
+		return false, ErrProjectOwnerRemoval
+	}
+
+	if err = s.repo.Delete(ctx, member.ID); err != nil {
+		return false, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "project member removed", "namespace", namespaceCode, "project", projectCode, "username", username)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
+	return true, nil
+}
+
+func (s *projectMemberService) TransferOwnership(ctx context.Context, namespaceCode, projectCode, username string) (*model.ProjectMember, error) {
+	if _, err := s.projectRepo.FindByCode(ctx, namespaceCode, projectCode); err != nil {
+		return nil, err
+	}
+	user, err := s.findUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.repo.TransferOwnership(ctx, namespaceCode, projectCode, user.ID, types.SubjectFromContext(ctx)); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to transfer project ownership", "namespace", namespaceCode, "project", projectCode, "username", username, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "project ownership transferred", "namespace", namespaceCode, "project", projectCode, "username", username)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
+	return s.repo.FindByProjectAndUser(ctx, namespaceCode, projectCode, user.ID)
+}
+
+func (s *projectMemberService) findUser(ctx context.Context, username string) (*model.User, error) {
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *projectMemberService) findMember(ctx context.Context, namespaceCode, projectCode, username string) (*model.ProjectMember, error) {
+	user, err := s.findUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.repo.FindByProjectAndUser(ctx, namespaceCode, projectCode, user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectMemberNotFound
+		}
+		return nil, err
+	}
+	return member, nil
+}
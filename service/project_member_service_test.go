@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupProjectMemberServiceTest(t *testing.T) (*gorm.DB, ProjectMemberService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.User{}, &model.ProjectMember{}))
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test"}).Error)
+	for _, username := range []string{"alice", "bob", "carol"} {
+		require.NoError(t, db.Create(&model.User{Username: username, Firstname: username, Lastname: username}).Error)
+	}
+
+	ctx := testContextWithPageConfig(defaultProjectCfg)
+	return db, NewProjectMemberService(ctx, repository.NewProjectMemberRepository(db), repository.NewProjectRepository(db),
+		repository.NewUserRepository(db), invalidation.NewMemoryBus())
+}
+
+// setupShardedTestDB returns a primary database sharding the namespace ns-eu, holding the project proj, and its shard.
+// The users and the global models of the projects are only migrated in the primary database.
+func setupShardedTestDB(t *testing.T) (db *gorm.DB, shard *gorm.DB) {
+	open := func(name string, models ...interface{}) *gorm.DB {
+		conn, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), name+".db")), &gorm.Config{})
+		require.NoError(t, err)
+		require.NoError(t, conn.AutoMigrate(append([]interface{}{&model.Namespace{}, &model.Project{}}, models...)...))
+		return conn
+	}
+	db = open("primary", append([]interface{}{&model.User{}}, repository.ProjectGlobalModels...)...)
+	shard = open("shard")
+	router, err := database.NewShardRouter(map[string]*gorm.DB{"eu": shard}, map[string]string{"ns-eu": "eu"})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(router))
+
+	ctx := database.WithNamespace(context.Background(), "ns-eu")
+	require.NoError(t, db.WithContext(ctx).Create(&model.Namespace{NamespaceCode: "ns-eu", Name: "EU"}).Error)
+	require.NoError(t, db.WithContext(ctx).Create(&model.Project{ProjectCode: "proj", NamespaceCode: "ns-eu", Name: "Project"}).Error)
+	return db, shard
+}
+
+func TestProjectMemberService_Add(t *testing.T) {
+	ctx := types.WithSubject(context.Background(), "admin")
+
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		member, err := svc.Add(ctx, "test-ns", "test-proj", "alice", model.ProjectMemberRoleEditor)
+		require.NoError(t, err)
+		assert.NotZero(t, member.ID)
+		assert.Equal(t, "alice", member.User.Username)
+		assert.Equal(t, model.ProjectMemberRoleEditor, member.Role)
+		assert.Equal(t, "admin", member.CreatedBy)
+	})
+
+	t.Run("already a member", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		_, err := svc.Add(ctx, "test-ns", "test-proj", "alice", model.ProjectMemberRoleViewer)
+		require.NoError(t, err)
+		_, err = svc.Add(ctx, "test-ns", "test-proj", "alice", model.ProjectMemberRoleEditor)
+		assert.ErrorIs(t, err, ErrProjectMemberAlreadyExists)
+	})
+
+	t.Run("owner role refused", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		_, err := svc.Add(ctx, "test-ns", "test-proj", "alice", model.ProjectMemberRoleOwner)
+		assert.ErrorIs(t, err, ErrProjectMemberOwnerRole)
+	})
+
+	t.Run("invalid role", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		_, err := svc.Add(ctx, "test-ns", "test-proj", "alice", "ADMIN")
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		_, err := svc.Add(ctx, "test-ns", "test-proj", "unknown", model.ProjectMemberRoleViewer)
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		_, err := svc.Add(ctx, "test-ns", "unknown", "alice", model.ProjectMemberRoleViewer)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestProjectMemberService_UpdateRole(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+		_, err := svc.Add(ctx, "test-ns", "test-proj", "alice", model.ProjectMemberRoleViewer)
+		require.NoError(t, err)
+
+		member, err := svc.UpdateRole(ctx, "test-ns", "test-proj", "alice", model.ProjectMemberRoleEditor)
+		require.NoError(t, err)
+		assert.Equal(t, model.ProjectMemberRoleEditor, member.Role)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		_, err := svc.UpdateRole(ctx, "test-ns", "test-proj", "alice", model.ProjectMemberRoleEditor)
+		assert.ErrorIs(t, err, ErrProjectMemberNotFound)
+	})
+
+	t.Run("owner cannot be demoted", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+		_, err := svc.TransferOwnership(ctx, "test-ns", "test-proj", "alice")
+		require.NoError(t, err)
+
+		_, err = svc.UpdateRole(ctx, "test-ns", "test-proj", "alice", model.ProjectMemberRoleViewer)
+		assert.ErrorIs(t, err, ErrProjectOwnerRemoval)
+	})
+}
+
+func TestProjectMemberService_Remove(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+		_, err := svc.Add(ctx, "test-ns", "test-proj", "alice", model.ProjectMemberRoleViewer)
+		require.NoError(t, err)
+
+		removed, err := svc.Remove(ctx, "test-ns", "test-proj", "alice")
+		require.NoError(t, err)
+		assert.True(t, removed)
+
+		members, err := svc.GetByProject(ctx, "test-ns", "test-proj")
+		require.NoError(t, err)
+		assert.Empty(t, members)
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		_, err := svc.Remove(ctx, "test-ns", "test-proj", "alice")
+		assert.ErrorIs(t, err, ErrProjectMemberNotFound)
+	})
+
+	t.Run("owner cannot be removed", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+		_, err := svc.TransferOwnership(ctx, "test-ns", "test-proj", "alice")
+		require.NoError(t, err)
+
+		_, err = svc.Remove(ctx, "test-ns", "test-proj", "alice")
+		assert.ErrorIs(t, err, ErrProjectOwnerRemoval)
+	})
+}
+
+func TestProjectMemberService_TransferOwnership(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+		_, err := svc.TransferOwnership(ctx, "test-ns", "test-proj", "alice")
+		require.NoError(t, err)
+		_, err = svc.Add(ctx, "test-ns", "test-proj", "bob", model.ProjectMemberRoleViewer)
+		require.NoError(t, err)
+
+		owner, err := svc.TransferOwnership(ctx, "test-ns", "test-proj", "bob")
+		require.NoError(t, err)
+		assert.Equal(t, "bob", owner.User.Username)
+		assert.True(t, owner.IsOwner())
+
+		isOwner, err := svc.IsOwner(ctx, "test-ns", "test-proj", owner.UserID)
+		require.NoError(t, err)
+		assert.True(t, isOwner)
+
+		members, err := svc.GetByProject(ctx, "test-ns", "test-proj")
+		require.NoError(t, err)
+		require.Len(t, members, 2)
+		assert.Equal(t, "alice", members[1].User.Username)
+		assert.Equal(t, model.ProjectMemberRoleEditor, members[1].Role)
+
+		isOwner, err = svc.IsOwner(ctx, "test-ns", "test-proj", members[1].UserID)
+		require.NoError(t, err)
+		assert.False(t, isOwner)
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		_, err := svc.TransferOwnership(ctx, "test-ns", "test-proj", "unknown")
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		_, svc := setupProjectMemberServiceTest(t)
+
+		_, err := svc.TransferOwnership(ctx, "test-ns", "unknown", "alice")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestProjectMemberService_ShardedNamespace(t *testing.T) {
+	db, shard := setupShardedTestDB(t)
+	for _, username := range []string{"alice", "bob"} {
+		require.NoError(t, db.Create(&model.User{Username: username, Firstname: username, Lastname: username}).Error)
+	}
+	projectRepo := repository.NewProjectRepository(db)
+	svc := NewProjectMemberService(testContextWithPageConfig(defaultProjectCfg), repository.NewProjectMemberRepository(db), projectRepo,
+		repository.NewUserRepository(db), invalidation.NewMemoryBus())
+	ctx := database.WithNamespace(context.Background(), "ns-eu")
+
+	// The members are stored with the users in the primary database
+	_, err := svc.Add(ctx, "ns-eu", "proj", "alice", model.ProjectMemberRoleEditor)
+	require.NoError(t, err)
+	owner, err := svc.TransferOwnership(ctx, "ns-eu", "proj", "bob")
+	require.NoError(t, err)
+	assert.True(t, owner.IsOwner())
+	assert.False(t, shard.Migrator().HasTable(&model.ProjectMember{}))
+
+	// The permissions of the users are loaded from the primary database
+	members, err := repository.NewProjectMemberRepository(db).FindByUser(context.Background(), owner.UserID)
+	require.NoError(t, err)
+	require.Len(t, members, 1)
+	assert.Equal(t, model.ProjectMemberRoleOwner, members[0].Role)
+
+	require.NoError(t, projectRepo.Delete(ctx, "ns-eu", "proj"))
+	var count int64
+	require.NoError(t, db.Model(&model.ProjectMember{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestProjectMemberService_IsOwner(t *testing.T) {
+	_, svc := setupProjectMemberServiceTest(t)
+
+	isOwner, err := svc.IsOwner(context.Background(), "test-ns", "test-proj", 1)
+	require.NoError(t, err)
+	assert.False(t, isOwner)
+}
@@ -1,15 +1,28 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/flectolab/flecto-manager/bundle"
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/extension"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/markdown"
 	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/policy"
 	"github.com/flectolab/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/types"
 	"gorm.io/gorm"
@@ -17,13 +30,46 @@ import (
 )
 
 // ErrPublishInProgress is returned when a publish is already in progress for the project
-var ErrPublishInProgress = errors.New("publish already in progress for this project")
+var ErrPublishInProgress = flectoErrors.New(flectoErrors.CodePublishInProgress, "publish already in progress for this project")
+
+// ErrNothingToPublish is returned when the project has no draft to publish
+var ErrNothingToPublish = flectoErrors.New(flectoErrors.CodeNothingToPublish, "nothing to publish")
+
+// ErrPublishVetoed is returned when a validation hook blocks the publish
+var ErrPublishVetoed = flectoErrors.New(flectoErrors.CodePublishVetoed, "publish vetoed")
+
+// ErrProjectAlreadyExists is returned when the target project of a clone already exists
+var ErrProjectAlreadyExists = flectoErrors.New(flectoErrors.CodeAlreadyExists, "project already exists")
+
+// ErrProjectMoveSameNamespace is returned when a project is moved to the namespace it already belongs to
+var ErrProjectMoveSameNamespace = flectoErrors.New(flectoErrors.CodeInvalidRequest, "project already belongs to this namespace")
+
+// ErrProjectCrossDatabase is returned when a project is moved or cloned to a namespace stored in another database
+var ErrProjectCrossDatabase = flectoErrors.New(flectoErrors.CodeUnsupported, "project cannot be moved or cloned to a namespace stored in another database")
+
+// ErrNothingToPromote is returned when the staging version of the project is already in production
+var ErrNothingToPromote = flectoErrors.New(flectoErrors.CodeNothingToPromote, "nothing to promote for this project")
+
+// ErrBundleVersionNotFound is returned when the exported version is neither the published version of the project nor the version of one of its environments
+var ErrBundleVersionNotFound = flectoErrors.New(flectoErrors.CodeNotFound, "version not found")
+
+// ErrRedirectOptionsConflict is returned when the redirect options would make two redirects of the project match the same requests
+var ErrRedirectOptionsConflict = flectoErrors.New(flectoErrors.CodeInvalidRequest, "redirect options conflict")
+
+// ErrMaintenancePageNotFound is returned when the maintenance page of a project is not one of its published pages
+var ErrMaintenancePageNotFound = flectoErrors.New(flectoErrors.CodeNotFound, "maintenance page not found")
+
+// ErrInvalidCacheTTL is returned when a cache duration of a project is negative or above commonTypes.CacheTTLMax
+var ErrInvalidCacheTTL = flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid cache TTL")
 
 type ProjectService interface {
 	GetTx(ctx context.Context) *gorm.DB
 	GetQuery(ctx context.Context) *gorm.DB
 	Create(ctx context.Context, input *model.Project) (*model.Project, error)
 	Update(ctx context.Context, namespaceCode, projectCode string, input model.Project) (*model.Project, error)
+	UpdateRedirectOptions(ctx context.Context, namespaceCode, projectCode string, options commonTypes.RedirectOptions) (*model.Project, error)
+	SetMaintenance(ctx context.Context, namespaceCode, projectCode string, maintenance commonTypes.Maintenance, changedBy string) (*model.Project, error)
+	UpdateCacheTTL(ctx context.Context, namespaceCode, projectCode string, cacheTTL commonTypes.CacheTTL) (*model.Project, error)
 	Delete(ctx context.Context, namespaceCode, projectCode string) (bool, error)
 	GetByCode(ctx context.Context, namespaceCode, projectCode string) (*model.Project, error)
 	GetByCodeWithNamespace(ctx context.Context, namespaceCode, projectCode string) (*model.Project, error)
@@ -35,9 +81,21 @@ type ProjectService interface {
 	CountRedirectDrafts(ctx context.Context, namespaceCode, projectCode string) (int64, error)
 	CountPages(ctx context.Context, namespaceCode, projectCode string) (int64, error)
 	CountPageDrafts(ctx context.Context, namespaceCode, projectCode string) (int64, error)
+	CountByProjects(ctx context.Context, namespaceCode string, projectCodes []string) (map[string]model.ProjectCounts, error)
+	CountsByNamespace(ctx context.Context, namespaceCode string) ([]model.ProjectCounts, error)
 	TotalPageContentSize(ctx context.Context, namespaceCode, projectCode string) (int64, error)
+	TotalPageStoredContentSize(ctx context.Context, namespaceCode, projectCode string) (int64, error)
 	TotalPageContentSizeLimit() int64
-	Publish(ctx context.Context, namespaceCode, projectCode string) (*model.Project, error)
+	GetProjectStats(ctx context.Context, namespaceCode, projectCode string) (*model.ProjectStats, error)
+	Publish(ctx context.Context, namespaceCode, projectCode string, opts types.PublishOptions) (*model.Project, error)
+	PublishNamespace(ctx context.Context, namespaceCode string, opts types.PublishNamespaceOptions) ([]model.ProjectPublishResult, error)
+	PromoteEnvironment(ctx context.Context, namespaceCode, projectCode, promotedBy string) (*model.ProjectEnvironment, error)
+	MoveProject(ctx context.Context, namespaceCode, projectCode, targetNamespaceCode, movedBy string) (*model.Project, error)
+	CloneProject(ctx context.Context, srcNamespaceCode, srcProjectCode, dstNamespaceCode, dstProjectCode string, opts types.CloneProjectOptions) (*model.Project, error)
+	GetEnvironment(ctx context.Context, namespaceCode, projectCode string, environment commonTypes.Environment) (*model.ProjectEnvironment, error)
+	GetEnvironments(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectEnvironment, error)
+	ExportBundle(ctx context.Context, namespaceCode, projectCode string, version int) (*bundle.Bundle, error)
+	GetEffectiveConfig(ctx context.Context, namespaceCode, projectCode string, includeDrafts bool) (*model.EffectiveConfig, error)
 }
 
 type projectService struct {
@@ -46,6 +104,14 @@ type projectService struct {
 	pageRepo          repository.PageRepository
 	repoRedirectDraft repository.RedirectDraftRepository
 	repoPageDraft     repository.PageDraftRepository
+	bus               invalidation.Bus
+	// outbox delivers the events of the publishes, promotions and moves written in their transaction, nil broadcasting
+	// them to bus once committed
+	outbox OutboxService
+	// notifications is told the outcome of the publishes, nil sending no notification
+	notifications NotificationService
+	// hookClient calls the validation hooks of the publishes, each call being bounded by the timeout of its hook
+	hookClient *http.Client
 }
 
 func NewProjectService(
@@ -54,6 +120,9 @@ func NewProjectService(
 	pageRepo repository.PageRepository,
 	repoRedirectDraft repository.RedirectDraftRepository,
 	repoPageDraft repository.PageDraftRepository,
+	bus invalidation.Bus,
+	outbox OutboxService,
+	notifications NotificationService,
 ) ProjectService {
 	return &projectService{
 		ctx:               ctx,
@@ -61,6 +130,10 @@ func NewProjectService(
 		pageRepo:          pageRepo,
 		repoRedirectDraft: repoRedirectDraft,
 		repoPageDraft:     repoPageDraft,
+		bus:               bus,
+		outbox:            outbox,
+		notifications:     notifications,
+		hookClient:        &http.Client{},
 	}
 }
 
@@ -78,10 +151,10 @@ func (s *projectService) Create(ctx context.Context, input *model.Project) (*mod
 		return nil, err
 	}
 	if err = s.repo.Create(ctx, input); err != nil {
-		s.ctx.Logger.Error("failed to create project", "namespace", input.NamespaceCode, "project", input.ProjectCode, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to create project", "namespace", input.NamespaceCode, "project", input.ProjectCode, "error", err)
 		return nil, err
 	}
-	s.ctx.Logger.Info("project created", "namespace", input.NamespaceCode, "project", input.ProjectCode)
+	s.ctx.Logger.InfoContext(ctx, "project created", "namespace", input.NamespaceCode, "project", input.ProjectCode)
 	return input, nil
 }
 
@@ -103,12 +176,131 @@ func (s *projectService) Update(ctx context.Context, namespaceCode, projectCode
 	return project, nil
 }
 
+// UpdateRedirectOptions changes the matching options of the redirects of the project.
+// Options making two redirects of the project, as currently drafted, match the same requests are refused.
+func (s *projectService) UpdateRedirectOptions(ctx context.Context, namespaceCode, projectCode string, options commonTypes.RedirectOptions) (*model.Project, error) {
+	project, err := s.repo.FindByCode(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+	if project.RedirectOptions == options {
+		return project, nil
+	}
+
+	if options.NormalizesSources() {
+		if err = s.checkRedirectOptions(ctx, namespaceCode, projectCode, options); err != nil {
+			return nil, err
+		}
+	}
+	project.RedirectOptions = options
+	if err = s.repo.Update(ctx, project); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to update redirect options", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "redirect options updated", "namespace", namespaceCode, "project", projectCode,
+		"caseInsensitive", options.CaseInsensitive, "ignoreTrailingSlash", options.IgnoreTrailingSlash, "preserveQueryString", options.PreserveQueryString,
+		"normalizeUrl", options.NormalizeURL)
+	return project, nil
+}
+
+// SetMaintenance enables or disables the maintenance mode of the project, taken into account by the agents at their next check
+// without publishing. The maintenance page must be a published page of the project.
+func (s *projectService) SetMaintenance(ctx context.Context, namespaceCode, projectCode string, maintenance commonTypes.Maintenance, changedBy string) (*model.Project, error) {
+	project, err := s.repo.FindByCode(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+	if maintenance.Enabled {
+		var count int64
+		if err = s.pageRepo.GetQuery(ctx).Model(&model.Page{}).
+			Where("namespace_code = ? AND project_code = ? AND path = ? AND is_published = ?", namespaceCode, projectCode, maintenance.PagePath, true).
+			Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("%w: %s is not a published page of project %s/%s", ErrMaintenancePageNotFound, maintenance.PagePath, namespaceCode, projectCode)
+		}
+	}
+	if project.Maintenance == maintenance {
+		return project, nil
+	}
+
+	project.Maintenance = maintenance
+	// Only the maintenance columns are written, a publish may be bumping the version meanwhile
+	if err = s.repo.GetTx(ctx).Model(project).Select("maintenance_enabled", "maintenance_page_path").Updates(project).Error; err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to update maintenance mode", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "maintenance mode updated", "namespace", namespaceCode, "project", projectCode,
+		"enabled", maintenance.Enabled, "pagePath", maintenance.PagePath, "changedBy", changedBy)
+	return project, nil
+}
+
+// UpdateCacheTTL changes the cache durations hinted to the agents, taken into account at their next sync without publishing
+func (s *projectService) UpdateCacheTTL(ctx context.Context, namespaceCode, projectCode string, cacheTTL commonTypes.CacheTTL) (*model.Project, error) {
+	if !cacheTTL.IsValid() {
+		return nil, fmt.Errorf("%w: durations must be between 0 and %d seconds", ErrInvalidCacheTTL, commonTypes.CacheTTLMax)
+	}
+	project, err := s.repo.FindByCode(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+	if project.CacheTTL == cacheTTL {
+		return project, nil
+	}
+
+	project.CacheTTL = cacheTTL
+	// Only the cache columns are written, a publish may be bumping the version meanwhile
+	if err = s.repo.GetTx(ctx).Model(project).Select("cache_ttl_pages", "cache_ttl_redirects").Updates(project).Error; err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to update cache TTL", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "cache TTL updated", "namespace", namespaceCode, "project", projectCode,
+		"pages", cacheTTL.Pages, "redirects", cacheTTL.Redirects)
+	return project, nil
+}
+
+// checkRedirectOptions returns an error when two BASIC or BASIC_HOST redirects of the project, as currently drafted,
+// have sources matching the same requests with the options
+func (s *projectService) checkRedirectOptions(ctx context.Context, namespaceCode, projectCode string, options commonTypes.RedirectOptions) error {
+	var redirects []model.Redirect
+	if err := s.repo.GetTx(ctx).Preload("RedirectDraft").
+		Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
+		Order("id").
+		Find(&redirects).Error; err != nil {
+		return err
+	}
+
+	sources := make(map[string]string, len(redirects))
+	for _, redirect := range redirects {
+		current := redirect.Redirect
+		if redirect.RedirectDraft != nil {
+			current = redirect.RedirectDraft.NewRedirect
+		} else if redirect.IsPublished == nil || !*redirect.IsPublished {
+			continue
+		}
+		if current == nil || !current.Type.IsBasic() {
+			continue
+		}
+		key := options.NormalizeSource(current.Source) + " " + commonTypes.RedirectConditionsKey(current.Conditions)
+		if other, ok := sources[key]; ok {
+			return fmt.Errorf("%w: %s and %s match the same requests", ErrRedirectOptionsConflict, other, current.Source)
+		}
+		sources[key] = current.Source
+	}
+	return nil
+}
+
 func (s *projectService) Delete(ctx context.Context, namespaceCode, projectCode string) (bool, error) {
 	if err := s.repo.Delete(ctx, namespaceCode, projectCode); err != nil {
-		s.ctx.Logger.Error("failed to delete project", "namespace", namespaceCode, "project", projectCode, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete project", "namespace", namespaceCode, "project", projectCode, "error", err)
 		return false, err
 	}
-	s.ctx.Logger.Info("project deleted", "namespace", namespaceCode, "project", projectCode)
+	s.ctx.Logger.InfoContext(ctx, "project deleted", "namespace", namespaceCode, "project", projectCode)
+	s.invalidate(ctx, invalidation.Event{Type: invalidation.EventTypeProjectDeleted, NamespaceCode: namespaceCode, ProjectCode: projectCode})
 	return true, nil
 }
 
@@ -132,8 +324,16 @@ func (s *projectService) Search(ctx context.Context, query *gorm.DB) ([]model.Pr
 	return s.repo.Search(ctx, query)
 }
 
+// SearchPaginate returns a page of the projects of the query. Without namespace in the context, a query is run against
+// every database when namespaces are sharded, see searchPaginateShards.
 func (s *projectService) SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.ProjectList, error) {
-	projects, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	if query != nil && database.NamespaceFromContext(ctx) == "" {
+		if contexts := database.ShardContexts(query, ctx); len(contexts) > 1 {
+			return s.searchPaginateShards(contexts, pagination, query)
+		}
+	}
+
+	projects, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
@@ -146,6 +346,73 @@ func (s *projectService) SearchPaginate(ctx context.Context, pagination *commonT
 	}, nil
 }
 
+// projectComparators compare the projects by sortable column, to merge the projects read from several databases
+var projectComparators = map[string]func(a, b *model.Project) int{
+	"id":             func(a, b *model.Project) int { return cmp.Compare(a.ID, b.ID) },
+	"namespace_code": func(a, b *model.Project) int { return compareFolded(a.NamespaceCode, b.NamespaceCode) },
+	"code":           func(a, b *model.Project) int { return compareFolded(a.ProjectCode, b.ProjectCode) },
+	"name":           func(a, b *model.Project) int { return compareFolded(a.Name, b.Name) },
+	"createdAt":      func(a, b *model.Project) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	"updatedAt":      func(a, b *model.Project) int { return a.UpdatedAt.Compare(b.UpdatedAt) },
+}
+
+// compareFolded compares strings regardless of their case, as the collation of the database does
+func compareFolded(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// searchPaginateShards returns a page of the projects of the query read from each database. Each database returns the
+// projects up to the end of the page, ordered by the pagination and then by their codes so that the orders of the
+// databases agree, and the page is cut from their merge.
+func (s *projectService) searchPaginateShards(contexts []context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.ProjectList, error) {
+	orderBy := append(slices.Clone(pagination.GetOrderBy()),
+		commonTypes.SortInput{Column: "namespace_code", Direction: commonTypes.SortASC},
+		commonTypes.SortInput{Column: "code", Direction: commonTypes.SortASC})
+	limit, offset := pagination.GetLimit(), pagination.GetOffset()
+	end := 0
+	if limit != 0 {
+		end = offset + limit
+	}
+
+	var projects []model.Project
+	var total int64
+	for _, shardCtx := range contexts {
+		shardProjects, shardTotal, err := s.repo.SearchPaginate(shardCtx, query.WithContext(shardCtx), end, 0, orderBy)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, shardProjects...)
+		total += shardTotal
+	}
+
+	slices.SortStableFunc(projects, func(a, b model.Project) int {
+		for _, sort := range orderBy {
+			compare, ok := projectComparators[sort.Column]
+			if !ok {
+				continue
+			}
+			if c := compare(&a, &b); c != 0 {
+				if sort.Direction == commonTypes.SortDESC {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	})
+	projects = projects[min(offset, len(projects)):]
+	if limit != 0 {
+		projects = projects[:min(limit, len(projects))]
+	}
+
+	return &model.ProjectList{
+		Total:  int(total),
+		Offset: offset,
+		Limit:  limit,
+		Items:  projects,
+	}, nil
+}
+
 func (s *projectService) CountRedirects(ctx context.Context, namespaceCode, projectCode string) (int64, error) {
 	return s.repo.CountRedirects(ctx, namespaceCode, projectCode)
 }
@@ -162,20 +429,130 @@ func (s *projectService) CountPageDrafts(ctx context.Context, namespaceCode, pro
 	return s.repo.CountPageDrafts(ctx, namespaceCode, projectCode)
 }
 
+func (s *projectService) CountByProjects(ctx context.Context, namespaceCode string, projectCodes []string) (map[string]model.ProjectCounts, error) {
+	return s.repo.CountByProjects(ctx, namespaceCode, projectCodes)
+}
+
+func (s *projectService) CountsByNamespace(ctx context.Context, namespaceCode string) ([]model.ProjectCounts, error) {
+	return s.repo.CountsByNamespace(ctx, namespaceCode)
+}
+
 func (s *projectService) TotalPageContentSize(ctx context.Context, namespaceCode, projectCode string) (int64, error) {
 	return s.pageRepo.GetTotalContentSize(ctx, namespaceCode, projectCode)
 }
 
+func (s *projectService) TotalPageStoredContentSize(ctx context.Context, namespaceCode, projectCode string) (int64, error) {
+	return s.pageRepo.GetTotalStoredContentSize(ctx, namespaceCode, projectCode)
+}
+
 func (s *projectService) TotalPageContentSizeLimit() int64 {
-	return int64(s.ctx.Config.Page.TotalSizeLimit)
+	return int64(s.ctx.CurrentConfig().Page.TotalSizeLimit)
+}
+
+func (s *projectService) GetProjectStats(ctx context.Context, namespaceCode, projectCode string) (*model.ProjectStats, error) {
+	stats, err := s.repo.GetStats(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+	stats.PageContentSizeLimit = s.TotalPageContentSizeLimit()
+	return stats, nil
+}
+
+// Publish publishes the drafts of the project. While another publish holds the lock of the project,
+// it is attempted again after a jittered exponential backoff, up to publish.retry.max_attempts times.
+// The users subscribed to the project are notified of the outcome. Drafts deleting most of the project are
+// refused unless opts.Force is set.
+func (s *projectService) Publish(ctx context.Context, namespaceCode, projectCode string, opts types.PublishOptions) (*model.Project, error) {
+	project, err := s.publishWithRetry(ctx, namespaceCode, projectCode, opts)
+	s.notifyPublish(ctx, namespaceCode, projectCode, project, err)
+	return project, err
+}
+
+func (s *projectService) publishWithRetry(ctx context.Context, namespaceCode, projectCode string, opts types.PublishOptions) (*model.Project, error) {
+	retry := s.ctx.CurrentConfig().Publish.Retry
+	delay := retry.InitialDelay
+	for attempt := 1; ; attempt++ {
+		project, err := s.publish(ctx, namespaceCode, projectCode, opts)
+		if err == nil {
+			project.PublishAttempts = attempt
+			return project, nil
+		}
+		if !errors.Is(err, ErrPublishInProgress) {
+			return nil, err
+		}
+		if attempt >= retry.MaxAttempts {
+			if attempt > 1 {
+				return nil, fmt.Errorf("%w (%d attempts)", err, attempt)
+			}
+			return nil, err
+		}
+
+		wait := jitter(delay)
+		s.ctx.Logger.InfoContext(ctx, "publish retry scheduled", "namespace", namespaceCode, "project", projectCode, "attempt", attempt, "retryIn", wait)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, retry.MaxDelay)
+	}
 }
 
-func (s *projectService) Publish(ctx context.Context, namespaceCode, projectCode string) (*model.Project, error) {
-	s.ctx.Logger.Info("publish started", "namespace", namespaceCode, "project", projectCode)
+// notifyPublish notifies the outcome of a publish, and warns when it leaves the pages of the project close to
+// the total size limit. Missing projects and publishes without drafts are not notified.
+func (s *projectService) notifyPublish(ctx context.Context, namespaceCode, projectCode string, project *model.Project, err error) {
+	if s.notifications == nil {
+		return
+	}
+	subject := types.SubjectFromContext(ctx)
+	if err != nil {
+		if !errors.Is(err, ErrNothingToPublish) && !errors.Is(err, gorm.ErrRecordNotFound) {
+			s.notifications.Notify(model.NotificationEvent{
+				Type: model.NotificationEventPublishFailed, NamespaceCode: namespaceCode, ProjectCode: projectCode, Subject: subject, Error: err.Error(),
+			})
+		}
+		return
+	}
+	// With the outbox, the success is written in the transaction of the publish
+	if s.outbox == nil {
+		s.notifications.Notify(model.NotificationEvent{
+			Type: model.NotificationEventPublishSucceeded, NamespaceCode: namespaceCode, ProjectCode: projectCode, Subject: subject, Version: project.Version,
+		})
+	}
+
+	ratio := s.ctx.CurrentConfig().Notification.QuotaWarningRatio
+	if ratio <= 0 {
+		return
+	}
+	total, errSize := s.pageRepo.GetTotalContentSize(ctx, namespaceCode, projectCode)
+	if errSize != nil {
+		s.ctx.Logger.WarnContext(ctx, "quota check failed", "namespace", namespaceCode, "project", projectCode, "error", errSize)
+		return
+	}
+	limit := s.TotalPageContentSizeLimit()
+	if float64(total) >= ratio*float64(limit) {
+		s.notifications.Notify(model.NotificationEvent{
+			Type: model.NotificationEventQuotaWarning, NamespaceCode: namespaceCode, ProjectCode: projectCode, Subject: subject,
+			TotalContentSize: total, TotalContentSizeLimit: limit,
+		})
+	}
+}
+
+// jitter returns a random delay between half and the whole of delay, so that the publishes
+// waiting for the same lock do not retry at the same time
+func jitter(delay time.Duration) time.Duration {
+	if delay <= 1 {
+		return delay
+	}
+	return delay/2 + rand.N(delay/2)
+}
+
+func (s *projectService) publish(ctx context.Context, namespaceCode, projectCode string, opts types.PublishOptions) (*model.Project, error) {
+	s.ctx.Logger.InfoContext(ctx, "publish started", "namespace", namespaceCode, "project", projectCode)
 
 	project, err := s.repo.FindByCode(ctx, namespaceCode, projectCode)
 	if err != nil {
-		s.ctx.Logger.Error("publish failed: project not found", "namespace", namespaceCode, "project", projectCode, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "publish failed: project not found", "namespace", namespaceCode, "project", projectCode, "error", err)
 		return nil, err
 	}
 
@@ -189,22 +566,44 @@ func (s *projectService) Publish(ctx context.Context, namespaceCode, projectCode
 	}
 
 	if redirectDraftCount == 0 && pageDraftCount == 0 {
-		s.ctx.Logger.Warn("publish aborted: nothing to publish", "namespace", namespaceCode, "project", projectCode)
-		return nil, fmt.Errorf("nothing to publish for project %s/%s", namespaceCode, projectCode)
+		s.ctx.Logger.WarnContext(ctx, "publish aborted: nothing to publish", "namespace", namespaceCode, "project", projectCode)
+		return nil, fmt.Errorf("%w for project %s/%s", ErrNothingToPublish, namespaceCode, projectCode)
 	}
 	publishedAt := time.Now()
+	publishedBy := types.SubjectFromContext(ctx)
 
 	// Prepare redirect drafts
 	redirectDrafts, errGetRedirectDraft := s.repoRedirectDraft.FindByProject(ctx, namespaceCode, projectCode)
 	if errGetRedirectDraft != nil {
 		return nil, errGetRedirectDraft
 	}
+	// Prepare page drafts
+	pageDrafts, errGetPageDraft := s.repoPageDraft.FindByProject(ctx, namespaceCode, projectCode)
+	if errGetPageDraft != nil {
+		return nil, errGetPageDraft
+	}
+	if opts.ChangesetID != 0 {
+		redirectDrafts = slices.DeleteFunc(redirectDrafts, func(draft model.RedirectDraft) bool {
+			return draft.ChangesetID == nil || *draft.ChangesetID != opts.ChangesetID
+		})
+		pageDrafts = slices.DeleteFunc(pageDrafts, func(draft model.PageDraft) bool {
+			return draft.ChangesetID == nil || *draft.ChangesetID != opts.ChangesetID
+		})
+		if len(redirectDrafts) == 0 && len(pageDrafts) == 0 {
+			s.ctx.Logger.WarnContext(ctx, "publish aborted: nothing to publish in the changeset", "namespace", namespaceCode, "project", projectCode, "changeset", opts.ChangesetID)
+			return nil, fmt.Errorf("%w for changeset %d of project %s/%s", ErrNothingToPublish, opts.ChangesetID, namespaceCode, projectCode)
+		}
+	}
 
 	redirects := make([]*model.Redirect, 0)
+	redirectTags := make([]model.RedirectTag, 0)
 	redirectsToDelete := make([]int64, 0)
 	for _, draft := range redirectDrafts {
 		switch draft.ChangeType {
 		case model.DraftChangeTypeCreate, model.DraftChangeTypeUpdate:
+			for _, tag := range draft.Tags {
+				redirectTags = append(redirectTags, model.RedirectTag{RedirectID: *draft.OldRedirectID, TagID: tag.ID})
+			}
 			redirects = append(redirects, &model.Redirect{
 				ID:            *draft.OldRedirectID,
 				IsPublished:   types.Ptr(true),
@@ -218,24 +617,26 @@ func (s *projectService) Publish(ctx context.Context, namespaceCode, projectCode
 		}
 	}
 
-	// Prepare page drafts
-	pageDrafts, errGetPageDraft := s.repoPageDraft.FindByProject(ctx, namespaceCode, projectCode)
-	if errGetPageDraft != nil {
-		return nil, errGetPageDraft
-	}
-
 	pages := make([]*model.Page, 0)
 	pagesToDelete := make([]int64, 0)
 	for _, draft := range pageDrafts {
 		switch draft.ChangeType {
 		case model.DraftChangeTypeCreate, model.DraftChangeTypeUpdate:
+			contentSize := draft.ContentSize
+			// The markdown pages are rendered again, with the renderer options of the publish
+			if draft.NewPage.ContentType == commonTypes.PageContentTypeMarkdown {
+				if draft.NewPage.Content, err = markdown.Render(s.ctx.CurrentConfig().Page.Markdown, draft.NewPage.Source); err != nil {
+					return nil, fmt.Errorf("render markdown page %s: %w", draft.NewPage.Path, err)
+				}
+				contentSize = int64(len(draft.NewPage.Content))
+			}
 			pages = append(pages, &model.Page{
 				ID:            *draft.OldPageID,
 				IsPublished:   types.Ptr(true),
 				PublishedAt:   publishedAt,
 				NamespaceCode: namespaceCode,
 				ProjectCode:   projectCode,
-				ContentSize:   draft.ContentSize,
+				ContentSize:   contentSize,
 				Page:          draft.NewPage,
 			})
 		case model.DraftChangeTypeDelete:
@@ -243,6 +644,20 @@ func (s *projectService) Publish(ctx context.Context, namespaceCode, projectCode
 		}
 	}
 
+	deletion := model.MassDeletion{Operation: model.MassDeletionOperationPublish, Redirects: int64(len(redirectsToDelete)), Pages: int64(len(pagesToDelete))}
+	if err = countPublished(s.repo.GetTx(ctx), namespaceCode, projectCode, &deletion); err != nil {
+		return nil, err
+	}
+	if err = guardMassDeletion(ctx, s.ctx, s.notifications, namespaceCode, projectCode, deletion, opts.Force); err != nil {
+		return nil, err
+	}
+
+	if err = s.validatePublish(ctx, publishPlan(project, publishedBy, redirectDrafts, pageDrafts)); err != nil {
+		return nil, err
+	}
+
+	var outboxEvents []*model.OutboxEvent
+	var invalidationEvent invalidation.Event
 	err = s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
 		// Lock the project row to prevent concurrent publishes
 		// NOWAIT will return an error immediately if the row is already locked
@@ -270,6 +685,31 @@ func (s *projectService) Publish(ctx context.Context, namespaceCode, projectCode
 			}
 		}
 
+		// Replace the tags of the saved redirects by the tags of their draft and reset their health
+		for i := 0; i < len(redirects); i += batchSize {
+			end := i + batchSize
+			if end > len(redirects) {
+				end = len(redirects)
+			}
+
+			redirectIDs := make([]int64, 0, end-i)
+			for _, redirect := range redirects[i:end] {
+				redirectIDs = append(redirectIDs, redirect.ID)
+			}
+			if err = tx.Where("redirect_id IN ?", redirectIDs).Delete(&model.RedirectTag{}).Error; err != nil {
+				return err
+			}
+			// The target may have changed, it is checked again by the next health check
+			if err = tx.Where("redirect_id IN ?", redirectIDs).Delete(&model.RedirectHealth{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(redirectTags) > 0 {
+			if err = tx.CreateInBatches(redirectTags, batchSize).Error; err != nil {
+				return err
+			}
+		}
+
 		// Delete redirect drafts
 		if len(redirectDrafts) > 0 {
 			err = tx.Delete(redirectDrafts).Error
@@ -278,14 +718,40 @@ func (s *projectService) Publish(ctx context.Context, namespaceCode, projectCode
 			}
 		}
 
-		// Delete redirects marked for deletion
+		// Delete redirects marked for deletion, keeping a tombstone to restore them
 		if len(redirectsToDelete) > 0 {
+			var deletedRedirects []model.Redirect
+			if err = tx.Preload("Tags").Where("id in ?", redirectsToDelete).Find(&deletedRedirects).Error; err != nil {
+				return err
+			}
+			tombstones := make([]model.RedirectTombstone, 0, len(deletedRedirects))
+			for _, redirect := range deletedRedirects {
+				tombstones = append(tombstones, model.NewRedirectTombstone(redirect, publishedBy, publishedAt))
+			}
+			if err = tx.CreateInBatches(tombstones, batchSize).Error; err != nil {
+				return err
+			}
 			err = tx.Where("id in ?", redirectsToDelete).Delete(&model.Redirect{}).Error
 			if err != nil {
 				return err
 			}
 		}
 
+		if err = s.resolveRedirectChains(ctx, tx, project); err != nil {
+			return err
+		}
+
+		// The drafts are checked one by one, the published redirects must not end up with two catch-all
+		var catchAllCount int64
+		if err = tx.Model(&model.Redirect{}).
+			Where("namespace_code = ? AND project_code = ? AND is_published = ? AND type = ?", namespaceCode, projectCode, true, commonTypes.RedirectTypeCatchAll).
+			Count(&catchAllCount).Error; err != nil {
+			return err
+		}
+		if catchAllCount > 1 {
+			return ErrCatchAllExists
+		}
+
 		// Save pages
 		for i := 0; i < len(pages); i += batchSize {
 			end := i + batchSize
@@ -306,35 +772,809 @@ func (s *projectService) Publish(ctx context.Context, namespaceCode, projectCode
 			}
 		}
 
-		// Delete pages marked for deletion
+		// Delete pages marked for deletion, keeping a tombstone to restore them
 		if len(pagesToDelete) > 0 {
+			var deletedPages []model.Page
+			if err = tx.Where("id in ?", pagesToDelete).Find(&deletedPages).Error; err != nil {
+				return err
+			}
+			tombstones := make([]model.PageTombstone, 0, len(deletedPages))
+			for _, page := range deletedPages {
+				tombstones = append(tombstones, model.NewPageTombstone(page, publishedBy, publishedAt))
+			}
+			if err = tx.CreateInBatches(tombstones, batchSize).Error; err != nil {
+				return err
+			}
 			err = tx.Where("id in ?", pagesToDelete).Delete(&model.Page{}).Error
 			if err != nil {
 				return err
 			}
 		}
 
+		if opts.ChangesetID != 0 {
+			if err = tx.Delete(&model.Changeset{}, opts.ChangesetID).Error; err != nil {
+				return err
+			}
+		}
+
 		project.Version++
 		project.PublishedAt = publishedAt
+		project.Revision = ""
+		project.PublishedBy = publishedBy
 		err = tx.Save(project).Error
 		if err != nil {
 			return err
 		}
-		return nil
+
+		invalidationEvent = invalidation.Event{Type: invalidation.EventTypeProjectPublished, NamespaceCode: namespaceCode, ProjectCode: projectCode, Version: project.Version}
+		outboxEvents, err = s.writeOutbox(tx, invalidationEvent, model.NotificationEvent{
+			Type: model.NotificationEventPublishSucceeded, NamespaceCode: namespaceCode, ProjectCode: projectCode, Subject: publishedBy, Version: project.Version,
+		})
+		return err
 	})
 	if err != nil {
 		if err == ErrPublishInProgress {
-			s.ctx.Logger.Warn("publish failed: already in progress", "namespace", namespaceCode, "project", projectCode)
+			s.ctx.Logger.WarnContext(ctx, "publish failed: already in progress", "namespace", namespaceCode, "project", projectCode)
 		} else {
-			s.ctx.Logger.Error("publish failed", "namespace", namespaceCode, "project", projectCode, "error", err)
+			s.ctx.Logger.ErrorContext(ctx, "publish failed", "namespace", namespaceCode, "project", projectCode, "error", err)
 		}
 		return nil, err
 	}
 
-	s.ctx.Logger.Info("publish completed", "namespace", namespaceCode, "project", projectCode, "version", project.Version, "redirects", len(redirects), "pages", len(pages))
+	s.ctx.Logger.InfoContext(ctx, "publish completed", "namespace", namespaceCode, "project", projectCode, "version", project.Version, "redirects", len(redirects), "pages", len(pages))
+	s.dispatch(ctx, invalidationEvent, outboxEvents)
+	if err = s.ctx.Extensions.PostPublish(ctx, s.ctx.CurrentConfig().Extensions, extension.PublishResult{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		Version:       project.Version,
+		PublishedBy:   publishedBy,
+		PublishedAt:   publishedAt,
+		Redirects:     len(redirectDrafts),
+		Pages:         len(pageDrafts),
+	}); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "post-publish hooks failed", "namespace", namespaceCode, "project", projectCode, "version", project.Version, "error", err)
+	}
 	return project, nil
 }
 
+// validatePublish asks the validation hooks, then the pre-publish hooks of the extensions, to approve the plan, the
+// validation hooks failing being logged
+func (s *projectService) validatePublish(ctx context.Context, plan policy.Plan) error {
+	results := policy.Check(ctx, s.hookClient, s.ctx.CurrentConfig().Publish.ValidationHooks, plan)
+	for _, result := range results {
+		if result.Err != nil {
+			s.ctx.Logger.WarnContext(ctx, "publish validation hook failed", "namespace", plan.NamespaceCode, "project", plan.ProjectCode,
+				"hook", result.Hook, "fail_open", result.FailOpen, "error", result.Err)
+		}
+	}
+	if err := policy.Veto(results); err != nil {
+		s.ctx.Logger.WarnContext(ctx, "publish aborted: vetoed", "namespace", plan.NamespaceCode, "project", plan.ProjectCode, "error", err)
+		return fmt.Errorf("%w for project %s/%s: %s", ErrPublishVetoed, plan.NamespaceCode, plan.ProjectCode, err)
+	}
+	if err := s.ctx.Extensions.PrePublish(ctx, s.ctx.CurrentConfig().Extensions, plan); err != nil {
+		s.ctx.Logger.WarnContext(ctx, "publish aborted: vetoed", "namespace", plan.NamespaceCode, "project", plan.ProjectCode, "error", err)
+		return fmt.Errorf("%w for project %s/%s: %s", ErrPublishVetoed, plan.NamespaceCode, plan.ProjectCode, err)
+	}
+	return nil
+}
+
+// publishPlan returns the plan of the publish of the drafts, sent to the validation hooks
+func publishPlan(project *model.Project, publishedBy string, redirectDrafts []model.RedirectDraft, pageDrafts []model.PageDraft) policy.Plan {
+	plan := policy.Plan{
+		NamespaceCode: project.NamespaceCode,
+		ProjectCode:   project.ProjectCode,
+		Version:       project.Version + 1,
+		PublishedBy:   publishedBy,
+		Redirects:     make([]policy.RedirectChange, 0, len(redirectDrafts)),
+		Pages:         make([]policy.PageChange, 0, len(pageDrafts)),
+	}
+	for _, draft := range redirectDrafts {
+		change := policy.RedirectChange{ChangeType: string(draft.ChangeType)}
+		if draft.ChangeType != model.DraftChangeTypeCreate && draft.OldRedirect != nil {
+			change.Old = draft.OldRedirect.Redirect
+		}
+		if draft.ChangeType != model.DraftChangeTypeDelete {
+			change.New = draft.NewRedirect
+		}
+		plan.Redirects = append(plan.Redirects, change)
+	}
+	for _, draft := range pageDrafts {
+		change := policy.PageChange{ChangeType: string(draft.ChangeType)}
+		if draft.ChangeType != model.DraftChangeTypeCreate && draft.OldPage != nil {
+			change.Old = draft.OldPage.Page
+		}
+		if draft.ChangeType != model.DraftChangeTypeDelete {
+			change.New = draft.NewPage
+		}
+		plan.Pages = append(plan.Pages, change)
+	}
+	return plan
+}
+
+// maxPublishNamespaceConcurrency bounds the number of projects of a namespace published in parallel
+const maxPublishNamespaceConcurrency = 10
+
+// PublishNamespace publishes the drafts of all the projects of a namespace having some, in the order of their codes,
+// opts.Concurrency at a time. Each project is published like by Publish, with its retries and notifications. The projects
+// where another user holds a draft lock are not published, unless opts.IgnoreDraftLocks is set. A failed publish does
+// not stop the others, unless opts.StopOnFailure is set, a locked project then counting as a failure. The result of each project is returned in the same order.
+func (s *projectService) PublishNamespace(ctx context.Context, namespaceCode string, opts types.PublishNamespaceOptions) ([]model.ProjectPublishResult, error) {
+	counts, err := s.repo.CountsByNamespace(ctx, namespaceCode)
+	if err != nil {
+		return nil, err
+	}
+	projectCodes := make([]string, 0, len(counts))
+	for _, count := range counts {
+		if count.RedirectDrafts > 0 || count.PageDrafts > 0 {
+			projectCodes = append(projectCodes, count.ProjectCode)
+		}
+	}
+	s.ctx.Logger.InfoContext(ctx, "namespace publish started", "namespace", namespaceCode, "projects", len(projectCodes))
+
+	concurrency := min(max(opts.Concurrency, 1), maxPublishNamespaceConcurrency)
+	results := make([]model.ProjectPublishResult, len(projectCodes))
+	var failed atomic.Bool
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, projectCode := range projectCodes {
+		sem <- struct{}{}
+		if opts.StopOnFailure && failed.Load() {
+			<-sem
+			results[i] = model.ProjectPublishResult{ProjectCode: projectCode, Status: model.ProjectPublishStatusSkipped, Error: "not published after a failed publish"}
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.publishNamespaceProject(ctx, namespaceCode, projectCode, opts)
+			if results[i].Status == model.ProjectPublishStatusFailed || results[i].Status == model.ProjectPublishStatusLocked {
+				failed.Store(true)
+			}
+		}()
+	}
+	wg.Wait()
+
+	published := 0
+	for _, result := range results {
+		if result.Status == model.ProjectPublishStatusPublished {
+			published++
+		}
+	}
+	s.ctx.Logger.InfoContext(ctx, "namespace publish completed", "namespace", namespaceCode, "projects", len(projectCodes), "published", published)
+	return results, nil
+}
+
+// publishNamespaceProject publishes a project of a namespace publish, unless another user holds a draft lock in it
+func (s *projectService) publishNamespaceProject(ctx context.Context, namespaceCode, projectCode string, opts types.PublishNamespaceOptions) model.ProjectPublishResult {
+	result := model.ProjectPublishResult{ProjectCode: projectCode}
+	if !opts.IgnoreDraftLocks {
+		var locks []model.DraftLock
+		err := s.repo.GetTx(ctx).
+			Where("namespace_code = ? AND project_code = ? AND locked_by <> ? AND expires_at > ?", namespaceCode, projectCode, opts.Username, time.Now()).
+			Order("expires_at DESC").
+			Find(&locks).Error
+		if err != nil {
+			result.Status = model.ProjectPublishStatusFailed
+			result.Error = err.Error()
+			return result
+		}
+		if len(locks) > 0 {
+			result.Status = model.ProjectPublishStatusLocked
+			result.Error = lockedError(&locks[0]).Error()
+			return result
+		}
+	}
+
+	project, err := s.Publish(ctx, namespaceCode, projectCode, types.PublishOptions{Force: opts.Force})
+	switch {
+	case err == nil:
+		result.Status = model.ProjectPublishStatusPublished
+		result.Version = project.Version
+	case errors.Is(err, ErrNothingToPublish):
+		result.Status = model.ProjectPublishStatusSkipped
+		result.Error = err.Error()
+	case errors.Is(err, ErrPublishInProgress):
+		result.Status = model.ProjectPublishStatusLocked
+		result.Error = err.Error()
+	default:
+		result.Status = model.ProjectPublishStatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// PromoteEnvironment copies the published redirects and pages of the project, which form the staging
+// environment, to the production snapshot served to the agents subscribed to production
+func (s *projectService) PromoteEnvironment(ctx context.Context, namespaceCode, projectCode, promotedBy string) (*model.ProjectEnvironment, error) {
+	s.ctx.Logger.InfoContext(ctx, "promote started", "namespace", namespaceCode, "project", projectCode, "environment", commonTypes.EnvironmentProduction)
+
+	project, err := s.repo.FindByCode(ctx, namespaceCode, projectCode)
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "promote failed: project not found", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return nil, err
+	}
+	if project.PublishedAt.IsZero() {
+		return nil, fmt.Errorf("%w: project %s/%s has never been published", ErrNothingToPromote, namespaceCode, projectCode)
+	}
+
+	production, err := s.repo.FindEnvironment(ctx, namespaceCode, projectCode, commonTypes.EnvironmentProduction)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if production != nil && production.Version == project.Version {
+		return nil, fmt.Errorf("%w: version %d of project %s/%s is already in production", ErrNothingToPromote, project.Version, namespaceCode, projectCode)
+	}
+
+	projectEnvironment := &model.ProjectEnvironment{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		Environment:   commonTypes.EnvironmentProduction,
+		PromotedBy:    promotedBy,
+		PromotedAt:    time.Now(),
+	}
+
+	var outboxEvents []*model.OutboxEvent
+	var invalidationEvent invalidation.Event
+	err = s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the project row so that the snapshot is not taken in the middle of a publish
+		var lockedProject model.Project
+		if err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
+			First(&lockedProject).Error; err != nil {
+			if isLockError(err) {
+				return ErrPublishInProgress
+			}
+			return err
+		}
+
+		// Redirects are sent in their evaluation order
+		var redirects []model.Redirect
+		if err = tx.Where("namespace_code = ? AND project_code = ? AND is_published = ?", namespaceCode, projectCode, true).
+			Order("priority DESC, id").
+			Find(&redirects).Error; err != nil {
+			return err
+		}
+		var pages []model.Page
+		if err = tx.Where("namespace_code = ? AND project_code = ? AND is_published = ?", namespaceCode, projectCode, true).
+			Order("id").
+			Find(&pages).Error; err != nil {
+			return err
+		}
+
+		projectEnvironment.Version = lockedProject.Version
+		projectEnvironment.RedirectOptions = lockedProject.RedirectOptions
+		projectEnvironment.CountRedirects = int64(len(redirects))
+		projectEnvironment.CountPages = int64(len(pages))
+		projectEnvironment.Redirects = make([]commonTypes.Redirect, 0, len(redirects))
+		for _, redirect := range redirects {
+			projectEnvironment.Redirects = append(projectEnvironment.Redirects, redirect.Base())
+		}
+		projectEnvironment.Pages = make([]commonTypes.Page, 0, len(pages))
+		for _, page := range pages {
+			projectEnvironment.Pages = append(projectEnvironment.Pages, page.Base())
+		}
+
+		if err = tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "namespace_code"}, {Name: "project_code"}, {Name: "environment"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"version", "count_redirects", "count_pages", "redirects", "pages", "promoted_by", "promoted_at", "updated_at",
+				"redirect_case_insensitive", "redirect_ignore_trailing_slash", "redirect_preserve_query_string",
+			}),
+		}).Create(projectEnvironment).Error; err != nil {
+			return err
+		}
+
+		invalidationEvent = invalidation.Event{Type: invalidation.EventTypeProjectPromoted, NamespaceCode: namespaceCode, ProjectCode: projectCode, Version: projectEnvironment.Version}
+		outboxEvents, err = s.writeOutbox(tx, invalidationEvent)
+		return err
+	})
+	if err != nil {
+		if err == ErrPublishInProgress {
+			s.ctx.Logger.WarnContext(ctx, "promote failed: publish in progress", "namespace", namespaceCode, "project", projectCode)
+		} else {
+			s.ctx.Logger.ErrorContext(ctx, "promote failed", "namespace", namespaceCode, "project", projectCode, "error", err)
+		}
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "promote completed", "namespace", namespaceCode, "project", projectCode, "environment", projectEnvironment.Environment, "version", projectEnvironment.Version, "redirects", projectEnvironment.CountRedirects, "pages", projectEnvironment.CountPages)
+	s.dispatch(ctx, invalidationEvent, outboxEvents)
+	return projectEnvironment, nil
+}
+
+func (s *projectService) GetEnvironment(ctx context.Context, namespaceCode, projectCode string, environment commonTypes.Environment) (*model.ProjectEnvironment, error) {
+	return s.repo.FindEnvironment(ctx, namespaceCode, projectCode, environment)
+}
+
+func (s *projectService) GetEnvironments(ctx context.Context, namespaceCode, projectCode string) ([]model.ProjectEnvironment, error) {
+	return s.repo.FindEnvironments(ctx, namespaceCode, projectCode)
+}
+
+// ExportBundle returns the published redirects and pages of the project at a version, the published
+// version when version is 0. The other versions are read from the snapshots of the environments.
+func (s *projectService) ExportBundle(ctx context.Context, namespaceCode, projectCode string, version int) (*bundle.Bundle, error) {
+	result := &bundle.Bundle{NamespaceCode: namespaceCode, ProjectCode: projectCode, CreatedAt: time.Now()}
+	published := false
+
+	// The project, redirects and pages are read in a transaction so that they belong to the same version
+	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		var project model.Project
+		if err := tx.Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).First(&project).Error; err != nil {
+			return err
+		}
+		if version != 0 && version != project.Version {
+			return nil
+		}
+
+		var redirects []model.Redirect
+		if err := tx.Where("namespace_code = ? AND project_code = ? AND is_published = ?", namespaceCode, projectCode, true).
+			Order("priority DESC, id").
+			Find(&redirects).Error; err != nil {
+			return err
+		}
+		var pages []model.Page
+		if err := tx.Where("namespace_code = ? AND project_code = ? AND is_published = ?", namespaceCode, projectCode, true).
+			Order("id").
+			Find(&pages).Error; err != nil {
+			return err
+		}
+
+		published = true
+		result.Version = project.Version
+		result.RedirectOptions = project.RedirectOptions
+		result.Redirects = make([]commonTypes.Redirect, 0, len(redirects))
+		for _, redirect := range redirects {
+			result.Redirects = append(result.Redirects, redirect.Base())
+		}
+		result.Pages = make([]commonTypes.Page, 0, len(pages))
+		for _, page := range pages {
+			result.Pages = append(result.Pages, page.Base())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if published {
+		return result, nil
+	}
+
+	projectEnvironments, err := s.repo.FindEnvironments(ctx, namespaceCode, projectCode)
+	if err != nil {
+		return nil, err
+	}
+	for _, environment := range projectEnvironments {
+		if environment.Version == version {
+			// The environments are listed without their snapshot
+			projectEnvironment, errEnvironment := s.repo.FindEnvironment(ctx, namespaceCode, projectCode, environment.Environment)
+			if errEnvironment != nil {
+				return nil, errEnvironment
+			}
+			result.Version = projectEnvironment.Version
+			result.RedirectOptions = projectEnvironment.RedirectOptions
+			result.Redirects = projectEnvironment.Redirects
+			result.Pages = projectEnvironment.Pages
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: version %d of project %s/%s", ErrBundleVersionNotFound, version, namespaceCode, projectCode)
+}
+
+// projectChildModels are the models attached to a project by its namespace and project codes
+var projectChildModels = []interface{}{
+	&model.Redirect{},
+	&model.RedirectDraft{},
+	&model.Page{},
+	&model.PageDraft{},
+	&model.Tag{},
+	&model.Agent{},
+	&model.ImportJob{},
+	&model.ProjectEnvironment{},
+	&model.ProjectGitSync{},
+	&model.DraftLock{},
+	&model.NotificationSubscription{},
+	&model.RedirectTombstone{},
+	&model.PageTombstone{},
+	&model.PageBrokenLink{},
+	&model.Changeset{},
+	&model.ImportProfile{},
+}
+
+// MoveProject moves a project with its redirects, pages, drafts and other related rows to another namespace.
+// The project row is recreated in the target namespace since its codes are referenced by foreign keys.
+func (s *projectService) MoveProject(ctx context.Context, namespaceCode, projectCode, targetNamespaceCode, movedBy string) (*model.Project, error) {
+	if namespaceCode == targetNamespaceCode {
+		return nil, ErrProjectMoveSameNamespace
+	}
+	// The transaction runs on the database of the source namespace, the rows cannot reach another one
+	if !database.SameDatabase(s.repo.GetTx(ctx), namespaceCode, targetNamespaceCode) {
+		return nil, fmt.Errorf("%w: %s and %s", ErrProjectCrossDatabase, namespaceCode, targetNamespaceCode)
+	}
+
+	var moved *model.Project
+	var outboxEvents []*model.OutboxEvent
+	invalidationEvent := invalidation.Event{Type: invalidation.EventTypeProjectDeleted, NamespaceCode: namespaceCode, ProjectCode: projectCode}
+	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the project row to prevent a concurrent publish
+		var project model.Project
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
+			First(&project).Error; err != nil {
+			if isLockError(err) {
+				return ErrPublishInProgress
+			}
+			return err
+		}
+
+		var namespace model.Namespace
+		if err := tx.Where("namespace_code = ?", targetNamespaceCode).First(&namespace).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("target namespace %s: %w", targetNamespaceCode, err)
+			}
+			return err
+		}
+
+		var conflicts int64
+		if err := tx.Model(&model.Project{}).
+			Where("namespace_code = ? AND project_code = ?", targetNamespaceCode, projectCode).
+			Count(&conflicts).Error; err != nil {
+			return err
+		}
+		if conflicts > 0 {
+			return fmt.Errorf("%w: %s/%s", ErrProjectAlreadyExists, targetNamespaceCode, projectCode)
+		}
+
+		// The copy keeps every setting of the project, new columns included
+		copied := project
+		copied.ID = 0
+		copied.NamespaceCode = targetNamespaceCode
+		moved = &copied
+		if err := tx.Create(moved).Error; err != nil {
+			return err
+		}
+		for _, child := range projectChildModels {
+			if err := tx.Model(child).
+				Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
+				UpdateColumn("namespace_code", targetNamespaceCode).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&model.Project{}, project.ID).Error; err != nil {
+			return err
+		}
+
+		var err error
+		outboxEvents, err = s.writeOutbox(tx, invalidationEvent)
+		return err
+	})
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to move project", "namespace", namespaceCode, "project", projectCode, "targetNamespace", targetNamespaceCode, "movedBy", movedBy, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "project moved", "namespace", namespaceCode, "project", projectCode, "targetNamespace", targetNamespaceCode, "movedBy", movedBy)
+	s.dispatch(ctx, invalidationEvent, outboxEvents)
+
+	// The rows of the global tables are stored in the primary database, not in the one of the namespace the
+	// transaction ran on, they are moved once the project is
+	for _, global := range repository.ProjectGlobalModels {
+		if err = s.repo.GetTx(ctx).Model(global).
+			Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
+			UpdateColumn("namespace_code", targetNamespaceCode).Error; err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "failed to move project global rows", "namespace", namespaceCode, "project", projectCode, "targetNamespace", targetNamespaceCode, "error", err)
+			return nil, err
+		}
+	}
+	return moved, nil
+}
+
+// writeOutbox writes in tx the outbox events announcing a change, its invalidation event and its notifications when the
+// service notifies. Without outbox, it writes nothing, the change being announced by dispatch once committed.
+func (s *projectService) writeOutbox(tx *gorm.DB, event invalidation.Event, notificationEvents ...model.NotificationEvent) ([]*model.OutboxEvent, error) {
+	if s.outbox == nil {
+		return nil, nil
+	}
+	invalidationEvent, err := NewInvalidationOutboxEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	events := []*model.OutboxEvent{invalidationEvent}
+	if s.notifications != nil {
+		for _, notificationEvent := range notificationEvents {
+			outboxEvent, errNotification := NewNotificationOutboxEvent(notificationEvent)
+			if errNotification != nil {
+				return nil, errNotification
+			}
+			events = append(events, outboxEvent)
+		}
+	}
+	return events, s.outbox.Add(tx, events...)
+}
+
+// dispatch delivers the outbox events of a committed change, or broadcasts its invalidation event without outbox
+func (s *projectService) dispatch(ctx context.Context, event invalidation.Event, outboxEvents []*model.OutboxEvent) {
+	if s.outbox == nil {
+		s.invalidate(ctx, event)
+		return
+	}
+	s.outbox.Dispatch(ctx, outboxEvents...)
+}
+
+// invalidate broadcasts an invalidation event to the replicas, through the outbox when there is one. A failure is only
+// logged since the change it announces is already committed.
+func (s *projectService) invalidate(ctx context.Context, event invalidation.Event) {
+	if s.outbox != nil {
+		outboxEvent, err := NewInvalidationOutboxEvent(event)
+		if err == nil {
+			err = s.outbox.Publish(ctx, outboxEvent)
+		}
+		if err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "failed to write invalidation event", "type", event.Type, "namespace", event.NamespaceCode, "project", event.ProjectCode, "error", err)
+		}
+		return
+	}
+	if err := s.bus.Publish(ctx, event); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to publish invalidation event", "type", event.Type, "namespace", event.NamespaceCode, "project", event.ProjectCode, "error", err)
+	}
+}
+
+// CloneProject creates a new project holding a copy of the published redirects, pages and tags of the
+// source project, and of its drafts when requested, in a single transaction
+func (s *projectService) CloneProject(ctx context.Context, srcNamespaceCode, srcProjectCode, dstNamespaceCode, dstProjectCode string, opts types.CloneProjectOptions) (*model.Project, error) {
+	if !database.SameDatabase(s.repo.GetTx(ctx), srcNamespaceCode, dstNamespaceCode) {
+		return nil, fmt.Errorf("%w: %s and %s", ErrProjectCrossDatabase, srcNamespaceCode, dstNamespaceCode)
+	}
+	source, err := s.repo.FindByCode(ctx, srcNamespaceCode, srcProjectCode)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &model.Project{
+		NamespaceCode:   dstNamespaceCode,
+		ProjectCode:     dstProjectCode,
+		Name:            opts.Name,
+		RedirectOptions: source.RedirectOptions,
+		CacheTTL:        source.CacheTTL,
+	}
+	if project.Name == "" {
+		project.Name = source.Name
+	}
+	if err = s.ctx.Validator.Struct(project); err != nil {
+		return nil, err
+	}
+	if _, err = s.repo.FindByCode(ctx, dstNamespaceCode, dstProjectCode); err == nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrProjectAlreadyExists, dstNamespaceCode, dstProjectCode)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	db := s.repo.GetTx(ctx)
+	sourceScope := func(query *gorm.DB) *gorm.DB {
+		query = query.Where("namespace_code = ? AND project_code = ?", srcNamespaceCode, srcProjectCode)
+		if !opts.IncludeDrafts {
+			query = query.Where("is_published = ?", true)
+		}
+		return query.Order("id")
+	}
+
+	var tags []model.Tag
+	if err = db.Where("namespace_code = ? AND project_code = ?", srcNamespaceCode, srcProjectCode).Order("id").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	var redirects []model.Redirect
+	if err = db.Scopes(sourceScope).Preload("Tags").Find(&redirects).Error; err != nil {
+		return nil, err
+	}
+	var pages []model.Page
+	if err = db.Scopes(sourceScope).Find(&pages).Error; err != nil {
+		return nil, err
+	}
+	var redirectDrafts []model.RedirectDraft
+	var pageDrafts []model.PageDraft
+	if opts.IncludeDrafts {
+		if redirectDrafts, err = s.repoRedirectDraft.FindByProject(ctx, srcNamespaceCode, srcProjectCode); err != nil {
+			return nil, err
+		}
+		if pageDrafts, err = s.repoPageDraft.FindByProject(ctx, srcNamespaceCode, srcProjectCode); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = s.checkCloneSizeLimits(pages, pageDrafts); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, page := range pages {
+			if page.IsPublished != nil && *page.IsPublished {
+				project.PublishedAt = now
+				project.PublishedBy = types.SubjectFromContext(ctx)
+				break
+			}
+		}
+		for _, redirect := range redirects {
+			if redirect.IsPublished != nil && *redirect.IsPublished {
+				project.PublishedAt = now
+				project.PublishedBy = types.SubjectFromContext(ctx)
+				break
+			}
+		}
+		if err = tx.Create(project).Error; err != nil {
+			return err
+		}
+
+		batchSize := 500
+
+		// Copy tags, keeping track of their new identifiers
+		tagIDs := make(map[int64]int64, len(tags))
+		if len(tags) > 0 {
+			newTags := make([]*model.Tag, 0, len(tags))
+			for _, tag := range tags {
+				newTags = append(newTags, &model.Tag{NamespaceCode: dstNamespaceCode, ProjectCode: dstProjectCode, Name: tag.Name})
+			}
+			if err = tx.CreateInBatches(newTags, batchSize).Error; err != nil {
+				return err
+			}
+			for i, tag := range tags {
+				tagIDs[tag.ID] = newTags[i].ID
+			}
+		}
+
+		// Copy redirects and their tags
+		redirectIDs := make(map[int64]int64, len(redirects))
+		if len(redirects) > 0 {
+			newRedirects := make([]*model.Redirect, 0, len(redirects))
+			for _, redirect := range redirects {
+				newRedirects = append(newRedirects, &model.Redirect{
+					NamespaceCode: dstNamespaceCode,
+					ProjectCode:   dstProjectCode,
+					IsPublished:   redirect.IsPublished,
+					PublishedAt:   redirect.PublishedAt,
+					Redirect:      redirect.Redirect,
+				})
+			}
+			if err = tx.Omit(clause.Associations).CreateInBatches(newRedirects, batchSize).Error; err != nil {
+				return err
+			}
+			redirectTags := make([]model.RedirectTag, 0)
+			for i, redirect := range redirects {
+				redirectIDs[redirect.ID] = newRedirects[i].ID
+				for _, tag := range redirect.Tags {
+					redirectTags = append(redirectTags, model.RedirectTag{RedirectID: newRedirects[i].ID, TagID: tagIDs[tag.ID]})
+				}
+			}
+			if len(redirectTags) > 0 {
+				if err = tx.CreateInBatches(redirectTags, batchSize).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		// Copy pages
+		pageIDs := make(map[int64]int64, len(pages))
+		if len(pages) > 0 {
+			newPages := make([]*model.Page, 0, len(pages))
+			for _, page := range pages {
+				newPages = append(newPages, &model.Page{
+					NamespaceCode: dstNamespaceCode,
+					ProjectCode:   dstProjectCode,
+					IsPublished:   page.IsPublished,
+					PublishedAt:   page.PublishedAt,
+					ContentSize:   page.ContentSize,
+					Page:          page.Page,
+				})
+			}
+			if err = tx.Omit(clause.Associations).CreateInBatches(newPages, batchSize).Error; err != nil {
+				return err
+			}
+			for i, page := range pages {
+				pageIDs[page.ID] = newPages[i].ID
+			}
+		}
+
+		// Copy drafts, pointing them to the copied redirects and pages
+		if len(redirectDrafts) > 0 {
+			newRedirectDrafts := make([]*model.RedirectDraft, 0, len(redirectDrafts))
+			for _, draft := range redirectDrafts {
+				newRedirectDrafts = append(newRedirectDrafts, &model.RedirectDraft{
+					NamespaceCode: dstNamespaceCode,
+					ProjectCode:   dstProjectCode,
+					ChangeType:    draft.ChangeType,
+					OldRedirectID: remapID(draft.OldRedirectID, redirectIDs),
+					NewRedirect:   draft.NewRedirect,
+					CreatedBy:     draft.CreatedBy,
+					UpdatedBy:     draft.UpdatedBy,
+				})
+			}
+			if err = tx.Omit(clause.Associations).CreateInBatches(newRedirectDrafts, batchSize).Error; err != nil {
+				return err
+			}
+			redirectDraftTags := make([]model.RedirectDraftTag, 0)
+			for i, draft := range redirectDrafts {
+				for _, tag := range draft.Tags {
+					redirectDraftTags = append(redirectDraftTags, model.RedirectDraftTag{RedirectDraftID: newRedirectDrafts[i].ID, TagID: tagIDs[tag.ID]})
+				}
+			}
+			if len(redirectDraftTags) > 0 {
+				if err = tx.CreateInBatches(redirectDraftTags, batchSize).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if len(pageDrafts) > 0 {
+			newPageDrafts := make([]*model.PageDraft, 0, len(pageDrafts))
+			for _, draft := range pageDrafts {
+				newPageDrafts = append(newPageDrafts, &model.PageDraft{
+					NamespaceCode: dstNamespaceCode,
+					ProjectCode:   dstProjectCode,
+					ChangeType:    draft.ChangeType,
+					OldPageID:     remapID(draft.OldPageID, pageIDs),
+					ContentSize:   draft.ContentSize,
+					NewPage:       draft.NewPage,
+					LintWarnings:  draft.LintWarnings,
+					CreatedBy:     draft.CreatedBy,
+					UpdatedBy:     draft.UpdatedBy,
+				})
+			}
+			if err = tx.Omit(clause.Associations).CreateInBatches(newPageDrafts, batchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "clone failed", "namespace", srcNamespaceCode, "project", srcProjectCode, "targetNamespace", dstNamespaceCode, "targetProject", dstProjectCode, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "project cloned", "namespace", srcNamespaceCode, "project", srcProjectCode, "targetNamespace", dstNamespaceCode, "targetProject", dstProjectCode, "redirects", len(redirects), "pages", len(pages), "redirectDrafts", len(redirectDrafts), "pageDrafts", len(pageDrafts))
+	return project, nil
+}
+
+// checkCloneSizeLimits checks the pages copied by a clone against the page size limits, counting
+// the draft content instead of the published content for the pages having a draft
+func (s *projectService) checkCloneSizeLimits(pages []model.Page, pageDrafts []model.PageDraft) error {
+	drafted := make(map[int64]bool, len(pageDrafts))
+	var total int64
+	for _, draft := range pageDrafts {
+		if draft.OldPageID != nil {
+			drafted[*draft.OldPageID] = true
+		}
+		if draft.ChangeType == model.DraftChangeTypeDelete {
+			continue
+		}
+		if draft.ContentSize > int64(s.ctx.CurrentConfig().Page.SizeLimit) {
+			return ErrContentSizeExceeded
+		}
+		total += draft.ContentSize
+	}
+	for _, page := range pages {
+		if page.IsPublished == nil || !*page.IsPublished || drafted[page.ID] {
+			continue
+		}
+		if page.ContentSize > int64(s.ctx.CurrentConfig().Page.SizeLimit) {
+			return ErrContentSizeExceeded
+		}
+		total += page.ContentSize
+	}
+	if total > int64(s.ctx.CurrentConfig().Page.TotalSizeLimit) {
+		return ErrTotalSizeLimitReached
+	}
+	return nil
+}
+
+// remapID returns the identifier mapped to id, nil when id is nil
+func remapID(id *int64, ids map[int64]int64) *int64 {
+	if id == nil {
+		return nil
+	}
+	newID := ids[*id]
+	return &newID
+}
+
 // isLockError checks if the error is a database lock error
 func isLockError(err error) bool {
 	if err == nil {
@@ -2,16 +2,26 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/extension"
+	"github.com/flectolab/flecto-manager/invalidation"
 	mockFlectoRepository "github.com/flectolab/flecto-manager/mocks/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/policy"
 	"github.com/flectolab/flecto-manager/repository"
 	types "github.com/flectolab/flecto-manager/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -28,6 +38,7 @@ type projectServiceTestDeps struct {
 	mockPageRepo      *mockFlectoRepository.MockPageRepository
 	mockRedirectDraft *mockFlectoRepository.MockRedirectDraftRepository
 	mockPageDraft     *mockFlectoRepository.MockPageDraftRepository
+	events            *[]invalidation.Event
 	svc               ProjectService
 }
 
@@ -37,13 +48,17 @@ func setupProjectServiceTest(t *testing.T) *projectServiceTestDeps {
 	mockPageRepo := mockFlectoRepository.NewMockPageRepository(ctrl)
 	mockRedirectDraftRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
 	mockPageDraftRepo := mockFlectoRepository.NewMockPageDraftRepository(ctrl)
-	svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), mockProjRepo, mockPageRepo, mockRedirectDraftRepo, mockPageDraftRepo)
+	bus := invalidation.NewMemoryBus()
+	events := &[]invalidation.Event{}
+	bus.Subscribe(func(event invalidation.Event) { *events = append(*events, event) })
+	svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), mockProjRepo, mockPageRepo, mockRedirectDraftRepo, mockPageDraftRepo, bus, nil, nil)
 	return &projectServiceTestDeps{
 		ctrl:              ctrl,
 		mockProjRepo:      mockProjRepo,
 		mockPageRepo:      mockPageRepo,
 		mockRedirectDraft: mockRedirectDraftRepo,
 		mockPageDraft:     mockPageDraftRepo,
+		events:            events,
 		svc:               svc,
 	}
 }
@@ -241,6 +256,7 @@ func TestProjectService_Delete(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.True(t, result)
+		assert.Equal(t, []invalidation.Event{{Type: invalidation.EventTypeProjectDeleted, NamespaceCode: "test-ns", ProjectCode: "test-proj"}}, *deps.events)
 	})
 
 	t.Run("error", func(t *testing.T) {
@@ -257,6 +273,7 @@ func TestProjectService_Delete(t *testing.T) {
 		result, err := deps.svc.Delete(ctx, "test-ns", "test-proj")
 
 		assert.Error(t, err)
+		assert.Empty(t, *deps.events)
 		assert.Equal(t, expectedErr, err)
 		assert.False(t, result)
 	})
@@ -517,7 +534,7 @@ func TestProjectService_SearchPaginate(t *testing.T) {
 		}
 
 		deps.mockProjRepo.EXPECT().
-			SearchPaginate(ctx, nil, 10, 5).
+			SearchPaginate(ctx, nil, 10, 5, nil).
 			Return(expectedProjects, int64(20), nil)
 
 		result, err := deps.svc.SearchPaginate(ctx, pagination, nil)
@@ -541,7 +558,7 @@ func TestProjectService_SearchPaginate(t *testing.T) {
 		}
 
 		deps.mockProjRepo.EXPECT().
-			SearchPaginate(ctx, nil, commonTypes.DefaultLimit, commonTypes.DefaultOffset).
+			SearchPaginate(ctx, nil, commonTypes.DefaultLimit, commonTypes.DefaultOffset, nil).
 			Return(expectedProjects, int64(1), nil)
 
 		result, err := deps.svc.SearchPaginate(ctx, pagination, nil)
@@ -561,7 +578,7 @@ func TestProjectService_SearchPaginate(t *testing.T) {
 		expectedErr := errors.New("search error")
 
 		deps.mockProjRepo.EXPECT().
-			SearchPaginate(ctx, nil, commonTypes.DefaultLimit, commonTypes.DefaultOffset).
+			SearchPaginate(ctx, nil, commonTypes.DefaultLimit, commonTypes.DefaultOffset, nil).
 			Return(nil, int64(0), expectedErr)
 
 		result, err := deps.svc.SearchPaginate(ctx, pagination, nil)
@@ -826,6 +843,59 @@ func TestProjectService_TotalPageContentSize(t *testing.T) {
 	})
 }
 
+func TestProjectService_GetProjectStats(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		deps := setupProjectServiceTest(t)
+		defer deps.ctrl.Finish()
+
+		ctx := context.Background()
+
+		deps.mockProjRepo.EXPECT().
+			GetStats(ctx, "test-ns", "test-proj").
+			Return(&model.ProjectStats{Version: 2, RedirectTotal: 10, PageContentSize: 300}, nil)
+
+		result, err := deps.svc.GetProjectStats(ctx, "test-ns", "test-proj")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Version)
+		assert.Equal(t, int64(10), result.RedirectTotal)
+		assert.Equal(t, int64(300), result.PageContentSize)
+		assert.Equal(t, deps.svc.TotalPageContentSizeLimit(), result.PageContentSizeLimit)
+	})
+
+	t.Run("error from repository", func(t *testing.T) {
+		deps := setupProjectServiceTest(t)
+		defer deps.ctrl.Finish()
+
+		ctx := context.Background()
+
+		deps.mockProjRepo.EXPECT().
+			GetStats(ctx, "test-ns", "test-proj").
+			Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := deps.svc.GetProjectStats(ctx, "test-ns", "test-proj")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestProjectService_TotalPageStoredContentSize(t *testing.T) {
+	deps := setupProjectServiceTest(t)
+	defer deps.ctrl.Finish()
+
+	ctx := context.Background()
+
+	deps.mockPageRepo.EXPECT().
+		GetTotalStoredContentSize(ctx, "test-ns", "test-proj").
+		Return(int64(600), nil)
+
+	result, err := deps.svc.TotalPageStoredContentSize(ctx, "test-ns", "test-proj")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(600), result)
+}
+
 func TestProjectService_TotalPageContentSizeLimit(t *testing.T) {
 	t.Run("returns configured limit", func(t *testing.T) {
 		deps := setupProjectServiceTest(t)
@@ -849,7 +919,7 @@ func TestProjectService_Publish(t *testing.T) {
 			FindByCode(ctx, "test-ns", "non-existing").
 			Return(nil, expectedErr)
 
-		result, err := deps.svc.Publish(ctx, "test-ns", "non-existing")
+		result, err := deps.svc.Publish(ctx, "test-ns", "non-existing", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -878,7 +948,7 @@ func TestProjectService_Publish(t *testing.T) {
 			CountRedirectDrafts(ctx, "test-ns", "test-proj").
 			Return(int64(0), expectedErr)
 
-		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -911,7 +981,7 @@ func TestProjectService_Publish(t *testing.T) {
 			CountPageDrafts(ctx, "test-ns", "test-proj").
 			Return(int64(0), expectedErr)
 
-		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -943,7 +1013,7 @@ func TestProjectService_Publish(t *testing.T) {
 			CountPageDrafts(ctx, "test-ns", "test-proj").
 			Return(int64(0), nil)
 
-		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "nothing to publish")
@@ -980,7 +1050,7 @@ func TestProjectService_Publish(t *testing.T) {
 			FindByProject(ctx, "test-ns", "test-proj").
 			Return(nil, expectedErr)
 
-		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -1021,7 +1091,7 @@ func TestProjectService_Publish(t *testing.T) {
 			FindByProject(ctx, "test-ns", "test-proj").
 			Return(nil, expectedErr)
 
-		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := deps.svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -1031,7 +1101,7 @@ func TestProjectService_Publish(t *testing.T) {
 	t.Run("success with redirect drafts create/update", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1048,10 +1118,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -1071,7 +1141,7 @@ func TestProjectService_Publish(t *testing.T) {
 	t.Run("success with redirect drafts delete", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1088,10 +1158,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -1100,12 +1170,91 @@ func TestProjectService_Publish(t *testing.T) {
 		var redirectCount int64
 		db.Model(&model.Redirect{}).Count(&redirectCount)
 		assert.Equal(t, int64(0), redirectCount)
+
+		// Check a tombstone is kept to restore it
+		var tombstone model.RedirectTombstone
+		assert.NoError(t, db.First(&tombstone, "redirect_id = ?", redirect.ID).Error)
+		assert.Equal(t, "/old", tombstone.Source)
+		assert.Equal(t, "/new", tombstone.Target)
+		assert.Equal(t, result.PublishedAt.Unix(), tombstone.DeletedAt.Unix())
+	})
+
+	t.Run("error two catch-all redirects", func(t *testing.T) {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		assert.NoError(t, err)
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
+		assert.NoError(t, err)
+
+		// Setup data
+		ns := &model.Namespace{NamespaceCode: "test-ns", Name: "Test"}
+		db.Create(ns)
+		proj := &model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 1}
+		db.Create(proj)
+		catchAll := &commonTypes.Redirect{Type: commonTypes.RedirectTypeCatchAll, Target: "https://example.com", Status: commonTypes.RedirectStatusFound}
+		db.Create(&model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true), Redirect: catchAll})
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(false)}
+		db.Create(redirect)
+		draft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &redirect.ID, NewRedirect: catchAll}
+		db.Create(draft)
+
+		projRepo := repository.NewProjectRepository(db)
+		pageRepo := repository.NewPageRepository(db)
+		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
+		pageDraftRepo := repository.NewPageDraftRepository(db)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
+
+		result, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+
+		assert.ErrorIs(t, err, ErrCatchAllExists)
+		assert.Nil(t, result)
+
+		// The publish is rolled back
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(1), draftCount)
+	})
+
+	t.Run("success replaces redirect tags with draft tags", func(t *testing.T) {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		assert.NoError(t, err)
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.Tag{})
+		assert.NoError(t, err)
+
+		// Setup data
+		ns := &model.Namespace{NamespaceCode: "test-ns", Name: "Test"}
+		db.Create(ns)
+		proj := &model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 1}
+		db.Create(proj)
+		oldTag := model.Tag{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "old"}
+		newTag := model.Tag{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "new"}
+		db.Create(&[]*model.Tag{&oldTag, &newTag})
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true), Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}, Tags: []model.Tag{oldTag}}
+		db.Create(redirect)
+		draft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeUpdate, OldRedirectID: &redirect.ID, NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/newer", Status: commonTypes.RedirectStatusMovedPermanent}, Tags: []model.Tag{newTag}}
+		db.Create(draft)
+
+		projRepo := repository.NewProjectRepository(db)
+		pageRepo := repository.NewPageRepository(db)
+		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
+		pageDraftRepo := repository.NewPageDraftRepository(db)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
+
+		ctx := context.Background()
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+
+		var publishedRedirect model.Redirect
+		db.Preload("Tags").First(&publishedRedirect, redirect.ID)
+		assert.Equal(t, "/newer", publishedRedirect.Target)
+		assert.Equal(t, []string{"new"}, model.TagNames(publishedRedirect.Tags))
 	})
 
 	t.Run("success with page drafts create/update", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1122,10 +1271,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -1141,10 +1290,45 @@ func TestProjectService_Publish(t *testing.T) {
 		assert.Equal(t, int64(0), draftCount)
 	})
 
+	t.Run("success renders the markdown page drafts", func(t *testing.T) {
+		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+		assert.NoError(t, err)
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
+		assert.NoError(t, err)
+
+		ns := &model.Namespace{NamespaceCode: "test-ns", Name: "Test"}
+		db.Create(ns)
+		proj := &model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 1}
+		db.Create(proj)
+		page := &model.Page{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(false)}
+		db.Create(page)
+		newPage := &commonTypes.Page{Path: "/about", ContentType: commonTypes.PageContentTypeMarkdown, Content: "<h2>About</h2>\n", Source: "## About"}
+		draft := &model.PageDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate, OldPageID: &page.ID, ContentSize: 15, NewPage: newPage}
+		db.Create(draft)
+
+		pageCfg := defaultProjectCfg
+		pageCfg.Markdown = config.PageMarkdownConfig{HeadingIDs: true}
+		projRepo := repository.NewProjectRepository(db)
+		pageRepo := repository.NewPageRepository(db)
+		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
+		pageDraftRepo := repository.NewPageDraftRepository(db)
+		svc := NewProjectService(testContextWithPageConfig(pageCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
+
+		_, err = svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		assert.NoError(t, err)
+
+		var publishedPage model.Page
+		db.First(&publishedPage, page.ID)
+		assert.Equal(t, "<h2 id=\"about\">About</h2>\n", publishedPage.Content)
+		assert.Equal(t, "## About", publishedPage.Source)
+		assert.Equal(t, int64(len(publishedPage.Content)), publishedPage.ContentSize)
+		assert.Empty(t, publishedPage.Base().Source)
+	})
+
 	t.Run("success with page drafts delete", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1161,10 +1345,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -1173,12 +1357,18 @@ func TestProjectService_Publish(t *testing.T) {
 		var pageCount int64
 		db.Model(&model.Page{}).Count(&pageCount)
 		assert.Equal(t, int64(0), pageCount)
+
+		// Check a tombstone is kept to restore it
+		var tombstone model.PageTombstone
+		assert.NoError(t, db.First(&tombstone, "page_id = ?", page.ID).Error)
+		assert.Equal(t, "/page", tombstone.Path)
+		assert.Equal(t, "test content", tombstone.Content)
 	})
 
 	t.Run("error saving redirects in transaction", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1203,10 +1393,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errDb)
@@ -1216,7 +1406,7 @@ func TestProjectService_Publish(t *testing.T) {
 	t.Run("error delete redirect draft in transaction", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1240,10 +1430,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errDb)
@@ -1253,7 +1443,7 @@ func TestProjectService_Publish(t *testing.T) {
 	t.Run("error delete redirect in transaction", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1277,10 +1467,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errDb)
@@ -1290,7 +1480,7 @@ func TestProjectService_Publish(t *testing.T) {
 	t.Run("error saving pages in transaction", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1315,10 +1505,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errDb)
@@ -1328,7 +1518,7 @@ func TestProjectService_Publish(t *testing.T) {
 	t.Run("error delete page draft in transaction", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1353,10 +1543,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errDb)
@@ -1366,7 +1556,7 @@ func TestProjectService_Publish(t *testing.T) {
 	t.Run("error delete pages in transaction", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1391,10 +1581,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errDb)
@@ -1404,7 +1594,7 @@ func TestProjectService_Publish(t *testing.T) {
 	t.Run("error save project in transaction", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1429,10 +1619,10 @@ func TestProjectService_Publish(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, err, errDb)
@@ -1522,7 +1712,7 @@ func TestProjectService_Publish_LockError(t *testing.T) {
 	t.Run("lock error in transaction returns ErrPublishInProgress", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1547,10 +1737,12 @@ func TestProjectService_Publish_LockError(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		appCtx := testContextWithPageConfig(defaultProjectCfg)
+		appCtx.Config.Publish.Retry.MaxAttempts = 1
+		svc := NewProjectService(appCtx, projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, ErrPublishInProgress, err)
@@ -1560,7 +1752,7 @@ func TestProjectService_Publish_LockError(t *testing.T) {
 	t.Run("non-lock error in lock query is propagated", func(t *testing.T) {
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{})
 		assert.NoError(t, err)
 
 		// Setup data
@@ -1586,13 +1778,946 @@ func TestProjectService_Publish_LockError(t *testing.T) {
 		pageRepo := repository.NewPageRepository(db)
 		redirectDraftRepo := repository.NewRedirectDraftRepository(db)
 		pageDraftRepo := repository.NewPageDraftRepository(db)
-		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo)
+		svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), projRepo, pageRepo, redirectDraftRepo, pageDraftRepo, invalidation.NewMemoryBus(), nil, nil)
 
 		ctx := context.Background()
-		result, err := svc.Publish(ctx, "test-ns", "test-proj")
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 		assert.Nil(t, result)
 	})
 }
+
+// setupPublishRetryTest returns a service whose project lock is held by another publish for the
+// first lockedAttempts attempts
+func setupPublishRetryTest(t *testing.T, lockedAttempts int) (*gorm.DB, *appContext.Context, ProjectService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectTag{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{}))
+
+	db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"})
+	db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 1})
+	redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(false), Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}}
+	db.Create(redirect)
+	db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &redirect.ID, NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}})
+
+	attempts := 0
+	db.Callback().Query().Before("gorm:query").Register("simulate_lock", func(d *gorm.DB) {
+		_, hasForClause := d.Statement.Clauses["FOR"]
+		if d.Statement.Table == "projects" && hasForClause {
+			attempts++
+			if attempts <= lockedAttempts {
+				d.Error = errors.New("database is locked")
+			}
+		}
+	})
+
+	appCtx := testContextWithPageConfig(defaultProjectCfg)
+	appCtx.Config.Publish.Retry = config.PublishRetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	svc := NewProjectService(appCtx, repository.NewProjectRepository(db), repository.NewPageRepository(db), repository.NewRedirectDraftRepository(db), repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+	return db, appCtx, svc
+}
+
+func TestProjectService_Publish_Retry(t *testing.T) {
+	t.Run("succeeds once the lock is released", func(t *testing.T) {
+		db, _, svc := setupPublishRetryTest(t, 2)
+
+		result, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.PublishAttempts)
+		assert.Equal(t, 2, result.Version)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(0), draftCount)
+	})
+
+	t.Run("first attempt", func(t *testing.T) {
+		_, _, svc := setupPublishRetryTest(t, 0)
+
+		result, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.PublishAttempts)
+	})
+
+	t.Run("gives up after the max attempts", func(t *testing.T) {
+		_, _, svc := setupPublishRetryTest(t, 3)
+
+		result, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		assert.ErrorIs(t, err, ErrPublishInProgress)
+		assert.ErrorContains(t, err, "3 attempts")
+		assert.Nil(t, result)
+	})
+
+	t.Run("stops when the context is canceled", func(t *testing.T) {
+		_, appCtx, svc := setupPublishRetryTest(t, 3)
+		appCtx.Config.Publish.Retry.InitialDelay = time.Hour
+		appCtx.Config.Publish.Retry.MaxDelay = time.Hour
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		result, err := svc.Publish(ctx, "test-ns", "test-proj", types.PublishOptions{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Nil(t, result)
+	})
+}
+
+func TestProjectService_Publish_ValidationHooks(t *testing.T) {
+	var received policy.Plan
+	allow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&received)
+		_, _ = w.Write([]byte(`{"allow": true}`))
+	}))
+	defer allow.Close()
+	deny := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"allow": false, "reason": "no redirect to /new"}`))
+	}))
+	defer deny.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	t.Run("allowed", func(t *testing.T) {
+		db, appCtx, svc := setupPublishRetryTest(t, 0)
+		appCtx.Config.Publish.ValidationHooks = []config.PublishHookConfig{
+			{Name: "allow", URL: allow.URL},
+			{Name: "failing", URL: failing.URL, FailOpen: true},
+			{Name: "other-namespace", URL: deny.URL, Namespaces: []string{"other-ns"}},
+		}
+
+		result, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Version)
+		assert.Equal(t, 2, received.Version)
+		require.Len(t, received.Redirects, 1)
+		assert.Equal(t, string(model.DraftChangeTypeCreate), received.Redirects[0].ChangeType)
+		assert.Nil(t, received.Redirects[0].Old)
+		assert.Equal(t, "/old", received.Redirects[0].New.Source)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(0), draftCount)
+	})
+
+	t.Run("vetoed", func(t *testing.T) {
+		db, appCtx, svc := setupPublishRetryTest(t, 0)
+		appCtx.Config.Publish.ValidationHooks = []config.PublishHookConfig{{Name: "allow", URL: allow.URL}, {Name: "deny", URL: deny.URL}}
+
+		result, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		assert.ErrorIs(t, err, ErrPublishVetoed)
+		assert.ErrorContains(t, err, "deny: no redirect to /new")
+		assert.Nil(t, result)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(1), draftCount)
+	})
+
+	t.Run("failing closed", func(t *testing.T) {
+		_, appCtx, svc := setupPublishRetryTest(t, 0)
+		appCtx.Config.Publish.ValidationHooks = []config.PublishHookConfig{{Name: "failing", URL: failing.URL}}
+
+		_, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		assert.ErrorIs(t, err, ErrPublishVetoed)
+		assert.ErrorContains(t, err, "failing failed: status 500")
+	})
+}
+
+func TestProjectService_Publish_ExtensionHooks(t *testing.T) {
+	t.Run("published", func(t *testing.T) {
+		_, appCtx, svc := setupPublishRetryTest(t, 0)
+		var plan policy.Plan
+		var published []extension.PublishResult
+		appCtx.Extensions.RegisterPrePublish("recorder", func(ctx context.Context, p policy.Plan) error {
+			plan = p
+			return nil
+		})
+		appCtx.Extensions.RegisterPostPublish("failing", func(ctx context.Context, result extension.PublishResult) error {
+			return errors.New("unavailable")
+		})
+		appCtx.Extensions.RegisterPostPublish("recorder", func(ctx context.Context, result extension.PublishResult) error {
+			published = append(published, result)
+			return nil
+		})
+
+		result, err := svc.Publish(types.WithSubject(context.Background(), "alice"), "test-ns", "test-proj", types.PublishOptions{})
+		require.NoError(t, err, "the errors of the post-publish hooks are only logged")
+		assert.Equal(t, 2, plan.Version)
+		require.Len(t, plan.Redirects, 1)
+		require.Len(t, published, 1)
+		assert.Equal(t, "test-proj", published[0].ProjectCode)
+		assert.Equal(t, result.Version, published[0].Version)
+		assert.Equal(t, "alice", published[0].PublishedBy)
+		assert.Equal(t, 1, published[0].Redirects)
+		assert.Zero(t, published[0].Pages)
+	})
+
+	t.Run("vetoed", func(t *testing.T) {
+		db, appCtx, svc := setupPublishRetryTest(t, 0)
+		var postPublished bool
+		appCtx.Extensions.RegisterPrePublish("freeze", func(ctx context.Context, plan policy.Plan) error {
+			return errors.New("project is frozen")
+		})
+		appCtx.Extensions.RegisterPostPublish("recorder", func(ctx context.Context, result extension.PublishResult) error {
+			postPublished = true
+			return nil
+		})
+
+		result, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		assert.ErrorIs(t, err, ErrPublishVetoed)
+		assert.ErrorContains(t, err, "freeze: project is frozen")
+		assert.Nil(t, result)
+		assert.False(t, postPublished)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(1), draftCount)
+
+		appCtx.Config.Extensions.Disabled = []string{"freeze"}
+		_, err = svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		require.NoError(t, err)
+		assert.True(t, postPublished)
+	})
+}
+
+func setupPublishNamespaceTest(t *testing.T) (*gorm.DB, ProjectService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectTag{}, &model.RedirectDraft{}, &model.RedirectHealth{}, &model.Page{}, &model.PageDraft{}, &model.RedirectTombstone{}, &model.PageTombstone{}, &model.DraftLock{}))
+	// Every connection to an in-memory database opens a new database
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"})
+	for _, projectCode := range []string{"proj-a", "proj-b", "proj-c", "proj-d"} {
+		db.Create(&model.Project{ProjectCode: projectCode, NamespaceCode: "test-ns", Name: projectCode, Version: 1})
+		if projectCode == "proj-b" {
+			continue
+		}
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: projectCode, IsPublished: types.Ptr(false), Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}}
+		db.Create(redirect)
+		db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: projectCode, ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &redirect.ID, NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}})
+	}
+	db.Create(&model.DraftLock{NamespaceCode: "test-ns", ProjectCode: "proj-c", Target: model.DraftLockTargetProject, LockedBy: "other", ExpiresAt: time.Now().Add(time.Hour)})
+	db.Create(&model.DraftLock{NamespaceCode: "test-ns", ProjectCode: "proj-d", Target: model.DraftLockTargetProject, LockedBy: "other", ExpiresAt: time.Now().Add(-time.Hour)})
+
+	svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), repository.NewProjectRepository(db), repository.NewPageRepository(db), repository.NewRedirectDraftRepository(db), repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+	return db, svc
+}
+
+func TestProjectService_PublishNamespace(t *testing.T) {
+	statuses := func(results []model.ProjectPublishResult) map[string]model.ProjectPublishStatus {
+		m := make(map[string]model.ProjectPublishStatus)
+		for _, result := range results {
+			m[result.ProjectCode] = result.Status
+		}
+		return m
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		db, svc := setupPublishNamespaceTest(t)
+
+		results, err := svc.PublishNamespace(context.Background(), "test-ns", types.PublishNamespaceOptions{Username: "admin"})
+		require.NoError(t, err)
+		require.Len(t, results, 3, "projects without drafts are left out")
+		assert.Equal(t, []string{"proj-a", "proj-c", "proj-d"}, []string{results[0].ProjectCode, results[1].ProjectCode, results[2].ProjectCode})
+		assert.Equal(t, model.ProjectPublishStatusPublished, results[0].Status)
+		assert.Equal(t, 2, results[0].Version)
+		assert.Equal(t, model.ProjectPublishStatusLocked, results[1].Status)
+		assert.Contains(t, results[1].Error, "locked by other")
+		assert.Equal(t, model.ProjectPublishStatusPublished, results[2].Status, "expired locks are ignored")
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(1), draftCount)
+	})
+
+	t.Run("lock of the publishing user", func(t *testing.T) {
+		_, svc := setupPublishNamespaceTest(t)
+
+		results, err := svc.PublishNamespace(context.Background(), "test-ns", types.PublishNamespaceOptions{Username: "other"})
+		require.NoError(t, err)
+		assert.Equal(t, model.ProjectPublishStatusPublished, statuses(results)["proj-c"])
+	})
+
+	t.Run("stop on failure", func(t *testing.T) {
+		_, svc := setupPublishNamespaceTest(t)
+
+		results, err := svc.PublishNamespace(context.Background(), "test-ns", types.PublishNamespaceOptions{Username: "admin", StopOnFailure: true})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]model.ProjectPublishStatus{
+			"proj-a": model.ProjectPublishStatusPublished,
+			"proj-c": model.ProjectPublishStatusLocked,
+			"proj-d": model.ProjectPublishStatusSkipped,
+		}, statuses(results))
+	})
+
+	t.Run("parallel ignoring the draft locks", func(t *testing.T) {
+		db, svc := setupPublishNamespaceTest(t)
+
+		results, err := svc.PublishNamespace(context.Background(), "test-ns", types.PublishNamespaceOptions{Username: "admin", Concurrency: 3, IgnoreDraftLocks: true})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]model.ProjectPublishStatus{
+			"proj-a": model.ProjectPublishStatusPublished,
+			"proj-c": model.ProjectPublishStatusPublished,
+			"proj-d": model.ProjectPublishStatusPublished,
+		}, statuses(results))
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(0), draftCount)
+	})
+
+	t.Run("empty namespace", func(t *testing.T) {
+		_, svc := setupPublishNamespaceTest(t)
+
+		results, err := svc.PublishNamespace(context.Background(), "unknown-ns", types.PublishNamespaceOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
+
+func TestJitter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), jitter(0))
+	for i := 0; i < 100; i++ {
+		wait := jitter(time.Second)
+		assert.GreaterOrEqual(t, wait, 500*time.Millisecond)
+		assert.Less(t, wait, time.Second)
+	}
+}
+
+func setupProjectEnvironmentServiceTest(t *testing.T) (*gorm.DB, ProjectService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ProjectEnvironment{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{})
+	assert.NoError(t, err)
+
+	db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"})
+	db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 2, PublishedAt: time.Now()})
+	db.Create(&model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true), Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/published", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}})
+	db.Create(&model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(false), Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/draft", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}})
+	db.Create(&model.Page{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true), Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "User-agent: *", ContentType: commonTypes.PageContentTypeTextPlain}})
+
+	svc := NewProjectService(
+		testContextWithPageConfig(defaultProjectCfg),
+		repository.NewProjectRepository(db),
+		repository.NewPageRepository(db),
+		repository.NewRedirectDraftRepository(db),
+		repository.NewPageDraftRepository(db),
+		invalidation.NewMemoryBus(),
+		nil,
+		nil,
+	)
+	return db, svc
+}
+
+func TestProjectService_PromoteEnvironment(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		_, svc := setupProjectEnvironmentServiceTest(t)
+		ctx := context.Background()
+
+		result, err := svc.PromoteEnvironment(ctx, "test-ns", "test-proj", "admin")
+
+		assert.NoError(t, err)
+		assert.Equal(t, commonTypes.EnvironmentProduction, result.Environment)
+		assert.Equal(t, 2, result.Version)
+		assert.Equal(t, "admin", result.PromotedBy)
+
+		production, err := svc.GetEnvironment(ctx, "test-ns", "test-proj", commonTypes.EnvironmentProduction)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, production.Version)
+		assert.Equal(t, int64(1), production.CountRedirects)
+		assert.Equal(t, int64(1), production.CountPages)
+		assert.Len(t, production.Redirects, 1)
+		assert.Equal(t, "/published", production.Redirects[0].Source)
+		assert.Len(t, production.Pages, 1)
+		assert.Equal(t, "/robots.txt", production.Pages[0].Path)
+	})
+
+	t.Run("promoting a new version replaces the snapshot", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+		ctx := context.Background()
+
+		_, err := svc.PromoteEnvironment(ctx, "test-ns", "test-proj", "admin")
+		assert.NoError(t, err)
+
+		db.Model(&model.Redirect{}).Where("source = ?", "/draft").Update("is_published", true)
+		db.Model(&model.Project{}).Where("project_code = ?", "test-proj").Updates(map[string]interface{}{"version": 3, "redirect_preserve_query_string": true})
+
+		_, err = svc.PromoteEnvironment(ctx, "test-ns", "test-proj", "other")
+		assert.NoError(t, err)
+
+		environments, err := svc.GetEnvironments(ctx, "test-ns", "test-proj")
+		assert.NoError(t, err)
+		assert.Len(t, environments, 1)
+		assert.Equal(t, 3, environments[0].Version)
+		assert.Equal(t, int64(2), environments[0].CountRedirects)
+		assert.Equal(t, "other", environments[0].PromotedBy)
+		assert.Equal(t, commonTypes.RedirectOptions{PreserveQueryString: true}, environments[0].RedirectOptions)
+	})
+
+	t.Run("version already in production", func(t *testing.T) {
+		_, svc := setupProjectEnvironmentServiceTest(t)
+		ctx := context.Background()
+
+		_, err := svc.PromoteEnvironment(ctx, "test-ns", "test-proj", "admin")
+		assert.NoError(t, err)
+
+		result, err := svc.PromoteEnvironment(ctx, "test-ns", "test-proj", "admin")
+		assert.ErrorIs(t, err, ErrNothingToPromote)
+		assert.Nil(t, result)
+	})
+
+	t.Run("project never published", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+		ctx := context.Background()
+		db.Create(&model.Project{ProjectCode: "new-proj", NamespaceCode: "test-ns", Name: "New", Version: 1})
+
+		result, err := svc.PromoteEnvironment(ctx, "test-ns", "new-proj", "admin")
+		assert.ErrorIs(t, err, ErrNothingToPromote)
+		assert.Nil(t, result)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		_, svc := setupProjectEnvironmentServiceTest(t)
+
+		result, err := svc.PromoteEnvironment(context.Background(), "test-ns", "unknown", "admin")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("publish in progress", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+		db.Callback().Query().Before("gorm:query").Register("simulate_lock", func(d *gorm.DB) {
+			_, hasForClause := d.Statement.Clauses["FOR"]
+			if d.Statement.Table == "projects" && hasForClause {
+				d.Error = errors.New("database is locked")
+			}
+		})
+
+		result, err := svc.PromoteEnvironment(context.Background(), "test-ns", "test-proj", "admin")
+		assert.Equal(t, ErrPublishInProgress, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestProjectService_ExportBundle(t *testing.T) {
+	t.Run("published version", func(t *testing.T) {
+		_, svc := setupProjectEnvironmentServiceTest(t)
+
+		for _, version := range []int{0, 2} {
+			result, err := svc.ExportBundle(context.Background(), "test-ns", "test-proj", version)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 2, result.Version)
+			assert.Len(t, result.Redirects, 1)
+			assert.Equal(t, "/published", result.Redirects[0].Source)
+			assert.Len(t, result.Pages, 1)
+			assert.Equal(t, "User-agent: *", result.Pages[0].Content)
+		}
+	})
+
+	t.Run("version of an environment", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+		ctx := context.Background()
+		_, err := svc.PromoteEnvironment(ctx, "test-ns", "test-proj", "admin")
+		assert.NoError(t, err)
+		db.Model(&model.Redirect{}).Where("source = ?", "/draft").Update("is_published", true)
+		db.Model(&model.Project{}).Where("project_code = ?", "test-proj").Updates(map[string]interface{}{"version": 3, "redirect_case_insensitive": true})
+
+		result, err := svc.ExportBundle(ctx, "test-ns", "test-proj", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Version)
+		assert.Len(t, result.Redirects, 1)
+		assert.Equal(t, commonTypes.RedirectOptions{}, result.RedirectOptions)
+
+		result, err = svc.ExportBundle(ctx, "test-ns", "test-proj", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.Version)
+		assert.Len(t, result.Redirects, 2)
+		assert.Equal(t, commonTypes.RedirectOptions{CaseInsensitive: true}, result.RedirectOptions)
+	})
+
+	t.Run("unknown version", func(t *testing.T) {
+		_, svc := setupProjectEnvironmentServiceTest(t)
+
+		result, err := svc.ExportBundle(context.Background(), "test-ns", "test-proj", 1)
+		assert.ErrorIs(t, err, ErrBundleVersionNotFound)
+		assert.Nil(t, result)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		_, svc := setupProjectEnvironmentServiceTest(t)
+
+		result, err := svc.ExportBundle(context.Background(), "test-ns", "unknown", 0)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestProjectService_UpdateRedirectOptions(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+		ctx := context.Background()
+		options := commonTypes.RedirectOptions{CaseInsensitive: true, IgnoreTrailingSlash: true, PreserveQueryString: true}
+
+		project, err := svc.UpdateRedirectOptions(ctx, "test-ns", "test-proj", options)
+
+		assert.NoError(t, err)
+		assert.Equal(t, options, project.RedirectOptions)
+		var saved model.Project
+		assert.NoError(t, db.Where("project_code = ?", "test-proj").First(&saved).Error)
+		assert.Equal(t, options, saved.RedirectOptions)
+	})
+
+	t.Run("sources matching the same requests", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+		ctx := context.Background()
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(false)}
+		assert.NoError(t, db.Create(redirect).Error)
+		assert.NoError(t, db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &redirect.ID,
+			NewRedirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/Published/", Target: "/other", Status: commonTypes.RedirectStatusFound}}).Error)
+
+		_, err := svc.UpdateRedirectOptions(ctx, "test-ns", "test-proj", commonTypes.RedirectOptions{CaseInsensitive: true})
+		assert.NoError(t, err)
+
+		_, err = svc.UpdateRedirectOptions(ctx, "test-ns", "test-proj", commonTypes.RedirectOptions{CaseInsensitive: true, IgnoreTrailingSlash: true})
+		assert.ErrorIs(t, err, ErrRedirectOptionsConflict)
+		assert.ErrorContains(t, err, "/published and /Published/ match the same requests")
+
+		var saved model.Project
+		assert.NoError(t, db.Where("project_code = ?", "test-proj").First(&saved).Error)
+		assert.Equal(t, commonTypes.RedirectOptions{CaseInsensitive: true}, saved.RedirectOptions)
+	})
+}
+
+func TestProjectService_SetMaintenance(t *testing.T) {
+	t.Run("enable and disable", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+		ctx := context.Background()
+		maintenance := commonTypes.Maintenance{Enabled: true, PagePath: "/robots.txt"}
+
+		project, err := svc.SetMaintenance(ctx, "test-ns", "test-proj", maintenance, "admin")
+
+		assert.NoError(t, err)
+		assert.Equal(t, maintenance, project.Maintenance)
+		var saved model.Project
+		assert.NoError(t, db.Where("project_code = ?", "test-proj").First(&saved).Error)
+		assert.Equal(t, maintenance, saved.Maintenance)
+		assert.Equal(t, 2, saved.Version, "no new version is published")
+
+		_, err = svc.SetMaintenance(ctx, "test-ns", "test-proj", commonTypes.Maintenance{}, "admin")
+		assert.NoError(t, err)
+		assert.NoError(t, db.Where("project_code = ?", "test-proj").First(&saved).Error)
+		assert.False(t, saved.Maintenance.Enabled)
+	})
+
+	t.Run("page not published", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+		ctx := context.Background()
+		db.Create(&model.Page{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(false), Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/maintenance.html", Content: "Back soon", ContentType: commonTypes.PageContentTypeTextPlain}})
+
+		for _, pagePath := range []string{"", "/unknown.html", "/maintenance.html"} {
+			_, err := svc.SetMaintenance(ctx, "test-ns", "test-proj", commonTypes.Maintenance{Enabled: true, PagePath: pagePath}, "admin")
+			assert.ErrorIs(t, err, ErrMaintenancePageNotFound, pagePath)
+		}
+		var saved model.Project
+		assert.NoError(t, db.Where("project_code = ?", "test-proj").First(&saved).Error)
+		assert.False(t, saved.Maintenance.Enabled)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		_, svc := setupProjectEnvironmentServiceTest(t)
+
+		_, err := svc.SetMaintenance(context.Background(), "test-ns", "unknown", commonTypes.Maintenance{}, "admin")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestProjectService_UpdateCacheTTL(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+		cacheTTL := commonTypes.CacheTTL{Pages: 3600, Redirects: 60}
+
+		project, err := svc.UpdateCacheTTL(context.Background(), "test-ns", "test-proj", cacheTTL)
+
+		assert.NoError(t, err)
+		assert.Equal(t, cacheTTL, project.CacheTTL)
+		var saved model.Project
+		assert.NoError(t, db.Where("project_code = ?", "test-proj").First(&saved).Error)
+		assert.Equal(t, cacheTTL, saved.CacheTTL)
+		assert.Equal(t, 2, saved.Version, "no new version is published")
+	})
+
+	t.Run("invalid durations", func(t *testing.T) {
+		db, svc := setupProjectEnvironmentServiceTest(t)
+
+		for _, cacheTTL := range []commonTypes.CacheTTL{{Pages: -1}, {Redirects: commonTypes.CacheTTLMax + 1}} {
+			_, err := svc.UpdateCacheTTL(context.Background(), "test-ns", "test-proj", cacheTTL)
+			assert.ErrorIs(t, err, ErrInvalidCacheTTL)
+		}
+		var saved model.Project
+		assert.NoError(t, db.Where("project_code = ?", "test-proj").First(&saved).Error)
+		assert.Equal(t, commonTypes.CacheTTL{}, saved.CacheTTL)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		_, svc := setupProjectEnvironmentServiceTest(t)
+
+		_, err := svc.UpdateCacheTTL(context.Background(), "test-ns", "unknown", commonTypes.CacheTTL{Pages: 60})
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func setupProjectCloneServiceTest(t *testing.T, pageCfg config.PageConfig) (*gorm.DB, ProjectService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Tag{}, &model.Redirect{}, &model.RedirectTag{}, &model.RedirectDraft{}, &model.RedirectDraftTag{}, &model.Page{}, &model.PageDraft{})
+	assert.NoError(t, err)
+
+	db.Create(&model.Namespace{NamespaceCode: "src-ns", Name: "Source"})
+	db.Create(&model.Namespace{NamespaceCode: "dst-ns", Name: "Target"})
+	db.Create(&model.Project{ProjectCode: "src-proj", NamespaceCode: "src-ns", Name: "Source Project", Version: 4, PublishedAt: time.Now()})
+
+	tag := &model.Tag{NamespaceCode: "src-ns", ProjectCode: "src-proj", Name: "campaign"}
+	db.Create(tag)
+	published := &model.Redirect{NamespaceCode: "src-ns", ProjectCode: "src-proj", IsPublished: types.Ptr(true), Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/published", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}, Tags: []model.Tag{*tag}}
+	db.Create(published)
+	created := &model.Redirect{NamespaceCode: "src-ns", ProjectCode: "src-proj", IsPublished: types.Ptr(false), Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/created", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}}
+	db.Create(created)
+	db.Create(&model.RedirectDraft{NamespaceCode: "src-ns", ProjectCode: "src-proj", ChangeType: model.DraftChangeTypeCreate, OldRedirectID: &created.ID, NewRedirect: created.Redirect, Tags: []model.Tag{*tag}})
+
+	page := &model.Page{NamespaceCode: "src-ns", ProjectCode: "src-proj", IsPublished: types.Ptr(true), ContentSize: 100, Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "User-agent: *", ContentType: commonTypes.PageContentTypeTextPlain}}
+	db.Create(page)
+	db.Create(&model.PageDraft{NamespaceCode: "src-ns", ProjectCode: "src-proj", ChangeType: model.DraftChangeTypeUpdate, OldPageID: &page.ID, ContentSize: 300, NewPage: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "Disallow: /", ContentType: commonTypes.PageContentTypeTextPlain}})
+
+	svc := NewProjectService(
+		testContextWithPageConfig(pageCfg),
+		repository.NewProjectRepository(db),
+		repository.NewPageRepository(db),
+		repository.NewRedirectDraftRepository(db),
+		repository.NewPageDraftRepository(db),
+		invalidation.NewMemoryBus(),
+		nil,
+		nil,
+	)
+	return db, svc
+}
+
+func TestProjectService_CloneProject(t *testing.T) {
+	t.Run("copies published content only", func(t *testing.T) {
+		db, svc := setupProjectCloneServiceTest(t, defaultProjectCfg)
+		ctx := context.Background()
+
+		project, err := svc.CloneProject(ctx, "src-ns", "src-proj", "dst-ns", "dst-proj", types.CloneProjectOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Source Project", project.Name)
+		assert.Equal(t, 1, project.Version)
+		assert.False(t, project.PublishedAt.IsZero())
+
+		var redirects []model.Redirect
+		db.Preload("Tags").Where("namespace_code = ? AND project_code = ?", "dst-ns", "dst-proj").Find(&redirects)
+		assert.Len(t, redirects, 1)
+		assert.Equal(t, "/published", redirects[0].Source)
+		assert.Len(t, redirects[0].Tags, 1)
+		assert.Equal(t, "campaign", redirects[0].Tags[0].Name)
+		assert.Equal(t, "dst-proj", redirects[0].Tags[0].ProjectCode)
+
+		var pages []model.Page
+		db.Where("namespace_code = ? AND project_code = ?", "dst-ns", "dst-proj").Find(&pages)
+		assert.Len(t, pages, 1)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Where("project_code = ?", "dst-proj").Count(&draftCount)
+		assert.Equal(t, int64(0), draftCount)
+
+		// the source project is left untouched
+		var sourceCount int64
+		db.Model(&model.Redirect{}).Where("project_code = ?", "src-proj").Count(&sourceCount)
+		assert.Equal(t, int64(2), sourceCount)
+	})
+
+	t.Run("copies drafts when requested", func(t *testing.T) {
+		db, svc := setupProjectCloneServiceTest(t, defaultProjectCfg)
+		ctx := context.Background()
+
+		project, err := svc.CloneProject(ctx, "src-ns", "src-proj", "dst-ns", "dst-proj", types.CloneProjectOptions{Name: "Template copy", IncludeDrafts: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Template copy", project.Name)
+
+		var redirectDrafts []model.RedirectDraft
+		db.Preload("OldRedirect").Preload("Tags").Where("namespace_code = ? AND project_code = ?", "dst-ns", "dst-proj").Find(&redirectDrafts)
+		assert.Len(t, redirectDrafts, 1)
+		assert.Equal(t, "dst-proj", redirectDrafts[0].OldRedirect.ProjectCode)
+		assert.Equal(t, "/created", redirectDrafts[0].OldRedirect.Source)
+		assert.Len(t, redirectDrafts[0].Tags, 1)
+		assert.Equal(t, "dst-proj", redirectDrafts[0].Tags[0].ProjectCode)
+
+		var pageDrafts []model.PageDraft
+		db.Preload("OldPage").Where("namespace_code = ? AND project_code = ?", "dst-ns", "dst-proj").Find(&pageDrafts)
+		assert.Len(t, pageDrafts, 1)
+		assert.Equal(t, "dst-proj", pageDrafts[0].OldPage.ProjectCode)
+		assert.Equal(t, int64(300), pageDrafts[0].ContentSize)
+	})
+
+	t.Run("target project already exists", func(t *testing.T) {
+		db, svc := setupProjectCloneServiceTest(t, defaultProjectCfg)
+		db.Create(&model.Project{ProjectCode: "dst-proj", NamespaceCode: "dst-ns", Name: "Existing"})
+
+		project, err := svc.CloneProject(context.Background(), "src-ns", "src-proj", "dst-ns", "dst-proj", types.CloneProjectOptions{})
+
+		assert.ErrorIs(t, err, ErrProjectAlreadyExists)
+		assert.Nil(t, project)
+	})
+
+	t.Run("invalid target project code", func(t *testing.T) {
+		_, svc := setupProjectCloneServiceTest(t, defaultProjectCfg)
+
+		project, err := svc.CloneProject(context.Background(), "src-ns", "src-proj", "dst-ns", "invalid code!", types.CloneProjectOptions{})
+
+		assert.Error(t, err)
+		assert.Nil(t, project)
+	})
+
+	t.Run("source project not found", func(t *testing.T) {
+		_, svc := setupProjectCloneServiceTest(t, defaultProjectCfg)
+
+		project, err := svc.CloneProject(context.Background(), "src-ns", "unknown", "dst-ns", "dst-proj", types.CloneProjectOptions{})
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, project)
+	})
+
+	t.Run("draft content exceeds the size limit", func(t *testing.T) {
+		db, svc := setupProjectCloneServiceTest(t, config.PageConfig{SizeLimit: 200, TotalSizeLimit: 2048})
+
+		project, err := svc.CloneProject(context.Background(), "src-ns", "src-proj", "dst-ns", "dst-proj", types.CloneProjectOptions{IncludeDrafts: true})
+
+		assert.ErrorIs(t, err, ErrContentSizeExceeded)
+		assert.Nil(t, project)
+
+		var count int64
+		db.Model(&model.Project{}).Where("project_code = ?", "dst-proj").Count(&count)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("total content exceeds the limit", func(t *testing.T) {
+		_, svc := setupProjectCloneServiceTest(t, config.PageConfig{SizeLimit: 1024, TotalSizeLimit: 50})
+
+		project, err := svc.CloneProject(context.Background(), "src-ns", "src-proj", "dst-ns", "dst-proj", types.CloneProjectOptions{})
+
+		assert.ErrorIs(t, err, ErrTotalSizeLimitReached)
+		assert.Nil(t, project)
+	})
+}
+
+func TestProjectService_MoveProject(t *testing.T) {
+	setup := func(t *testing.T) (*gorm.DB, ProjectService) {
+		db, svc := setupProjectCloneServiceTest(t, defaultProjectCfg)
+		err := db.AutoMigrate(&model.Agent{}, &model.ImportJob{}, &model.ProjectEnvironment{}, &model.ProjectGitSync{}, &model.DraftLock{}, &model.NotificationSubscription{}, &model.RedirectTombstone{}, &model.PageTombstone{}, &model.ProjectAPIKey{}, &model.PageBrokenLink{}, &model.User{}, &model.ProjectMember{}, &model.Changeset{}, &model.ImportProfile{})
+		assert.NoError(t, err)
+		db.Create(&model.Agent{NamespaceCode: "src-ns", ProjectCode: "src-proj", Agent: commonTypes.Agent{Name: "agent-1", Type: commonTypes.AgentTypeDefault}})
+		user := &model.User{Username: "member", Firstname: "Member", Lastname: "Member"}
+		db.Create(user)
+		db.Create(&model.ProjectMember{NamespaceCode: "src-ns", ProjectCode: "src-proj", UserID: user.ID, Role: model.ProjectMemberRoleEditor})
+		return db, svc
+	}
+
+	t.Run("success", func(t *testing.T) {
+		db, svc := setup(t)
+		ctx := context.Background()
+
+		project, err := svc.MoveProject(ctx, "src-ns", "src-proj", "dst-ns", "admin")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "dst-ns", project.NamespaceCode)
+		assert.Equal(t, "src-proj", project.ProjectCode)
+		assert.Equal(t, "Source Project", project.Name)
+		assert.Equal(t, 4, project.Version)
+
+		var projectCount int64
+		db.Model(&model.Project{}).Where("namespace_code = ?", "src-ns").Count(&projectCount)
+		assert.Equal(t, int64(0), projectCount)
+
+		for _, child := range []interface{}{&model.Redirect{}, &model.RedirectDraft{}, &model.Page{}, &model.PageDraft{}, &model.Tag{}, &model.Agent{}, &model.ProjectMember{}} {
+			var oldCount, newCount int64
+			db.Model(child).Where("namespace_code = ? AND project_code = ?", "src-ns", "src-proj").Count(&oldCount)
+			db.Model(child).Where("namespace_code = ? AND project_code = ?", "dst-ns", "src-proj").Count(&newCount)
+			assert.Equal(t, int64(0), oldCount)
+			assert.NotZero(t, newCount)
+		}
+
+		// redirect tags follow the redirects since they reference identifiers
+		var redirect model.Redirect
+		db.Preload("Tags").Where("source = ?", "/published").First(&redirect)
+		assert.Len(t, redirect.Tags, 1)
+		assert.Equal(t, "dst-ns", redirect.Tags[0].NamespaceCode)
+	})
+
+	t.Run("same namespace", func(t *testing.T) {
+		_, svc := setup(t)
+
+		project, err := svc.MoveProject(context.Background(), "src-ns", "src-proj", "src-ns", "admin")
+
+		assert.ErrorIs(t, err, ErrProjectMoveSameNamespace)
+		assert.Nil(t, project)
+	})
+
+	t.Run("code conflict in target namespace", func(t *testing.T) {
+		db, svc := setup(t)
+		db.Create(&model.Project{ProjectCode: "src-proj", NamespaceCode: "dst-ns", Name: "Existing"})
+
+		project, err := svc.MoveProject(context.Background(), "src-ns", "src-proj", "dst-ns", "admin")
+
+		assert.ErrorIs(t, err, ErrProjectAlreadyExists)
+		assert.Nil(t, project)
+
+		var count int64
+		db.Model(&model.Redirect{}).Where("namespace_code = ?", "src-ns").Count(&count)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("target namespace not found", func(t *testing.T) {
+		_, svc := setup(t)
+
+		project, err := svc.MoveProject(context.Background(), "src-ns", "src-proj", "unknown", "admin")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, project)
+	})
+
+	t.Run("keeps the maintenance and the cache durations", func(t *testing.T) {
+		db, svc := setup(t)
+		maintenance := commonTypes.Maintenance{Enabled: true, PagePath: "/maintenance.html"}
+		cacheTTL := commonTypes.CacheTTL{Pages: 3600, Redirects: 60}
+		require.NoError(t, db.Model(&model.Project{}).Where("namespace_code = ? AND project_code = ?", "src-ns", "src-proj").
+			Updates(&model.Project{Maintenance: maintenance, CacheTTL: cacheTTL}).Error)
+
+		project, err := svc.MoveProject(context.Background(), "src-ns", "src-proj", "dst-ns", "admin")
+		require.NoError(t, err)
+		assert.Equal(t, maintenance, project.Maintenance)
+		assert.Equal(t, cacheTTL, project.CacheTTL)
+
+		var stored model.Project
+		require.NoError(t, db.Where("namespace_code = ? AND project_code = ?", "dst-ns", "src-proj").First(&stored).Error)
+		assert.Equal(t, maintenance, stored.Maintenance)
+		assert.Equal(t, cacheTTL, stored.CacheTTL)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		_, svc := setup(t)
+
+		project, err := svc.MoveProject(context.Background(), "src-ns", "unknown", "dst-ns", "admin")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, project)
+	})
+}
+
+func TestProjectService_MoveAndCloneProject_ShardedNamespace(t *testing.T) {
+	db, shard := setupShardedTestDB(t)
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "ns-main", Name: "Main"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "proj-main", NamespaceCode: "ns-main", Name: "Main"}).Error)
+	svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), repository.NewProjectRepository(db), repository.NewPageRepository(db),
+		repository.NewRedirectDraftRepository(db), repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+	euCtx := database.WithNamespace(context.Background(), "ns-eu")
+	mainCtx := database.WithNamespace(context.Background(), "ns-main")
+
+	t.Run("move from a shard to the primary database", func(t *testing.T) {
+		project, err := svc.MoveProject(euCtx, "ns-eu", "proj", "ns-main", "admin")
+
+		assert.ErrorIs(t, err, ErrProjectCrossDatabase)
+		assert.Nil(t, project)
+		assert.Equal(t, int64(1), countProjects(t, shard, "ns-eu", "proj"))
+		assert.Equal(t, int64(0), countProjects(t, db, "ns-main", "proj"))
+	})
+
+	t.Run("move from the primary database to a shard", func(t *testing.T) {
+		project, err := svc.MoveProject(mainCtx, "ns-main", "proj-main", "ns-eu", "admin")
+
+		assert.ErrorIs(t, err, ErrProjectCrossDatabase)
+		assert.Nil(t, project)
+		assert.Equal(t, int64(1), countProjects(t, db, "ns-main", "proj-main"))
+		assert.Equal(t, int64(0), countProjects(t, db, "ns-eu", "proj-main"))
+	})
+
+	t.Run("clone from a shard to the primary database", func(t *testing.T) {
+		project, err := svc.CloneProject(euCtx, "ns-eu", "proj", "ns-main", "copy", types.CloneProjectOptions{})
+
+		assert.ErrorIs(t, err, ErrProjectCrossDatabase)
+		assert.Nil(t, project)
+		assert.Equal(t, int64(0), countProjects(t, db, "ns-main", "copy"))
+	})
+
+	t.Run("clone from the primary database to a shard", func(t *testing.T) {
+		project, err := svc.CloneProject(mainCtx, "ns-main", "proj-main", "ns-eu", "copy", types.CloneProjectOptions{})
+
+		assert.ErrorIs(t, err, ErrProjectCrossDatabase)
+		assert.Nil(t, project)
+		assert.Equal(t, int64(0), countProjects(t, db, "ns-eu", "copy"))
+		assert.Equal(t, int64(0), countProjects(t, shard, "ns-eu", "copy"))
+	})
+}
+
+func TestProjectService_SearchPaginate_ShardedNamespace(t *testing.T) {
+	db, _ := setupShardedTestDB(t)
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "ns-main", Name: "Main"}).Error)
+	for _, code := range []string{"alpha", "zeta"} {
+		require.NoError(t, db.Create(&model.Project{ProjectCode: code, NamespaceCode: "ns-main", Name: code}).Error)
+	}
+	svc := NewProjectService(testContextWithPageConfig(defaultProjectCfg), repository.NewProjectRepository(db), repository.NewPageRepository(db),
+		repository.NewRedirectDraftRepository(db), repository.NewPageDraftRepository(db), invalidation.NewMemoryBus(), nil, nil)
+	ctx := context.Background()
+	codes := func(result *model.ProjectList) []string {
+		var codes []string
+		for _, project := range result.Items {
+			codes = append(codes, project.ProjectCode)
+		}
+		return codes
+	}
+
+	t.Run("merges the projects of every database", func(t *testing.T) {
+		pagination := &commonTypes.PaginationInput{Limit: types.Ptr(2), OrderBy: []commonTypes.SortInput{{Column: "code", Direction: commonTypes.SortASC}}}
+
+		result, err := svc.SearchPaginate(ctx, pagination, svc.GetQuery(ctx))
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, []string{"alpha", "proj"}, codes(result))
+		assert.Equal(t, "ns-eu", result.Items[1].Namespace.NamespaceCode)
+
+		pagination.Offset = types.Ptr(2)
+		result, err = svc.SearchPaginate(ctx, pagination, svc.GetQuery(ctx))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"zeta"}, codes(result))
+	})
+
+	t.Run("sorted descending and filtered", func(t *testing.T) {
+		pagination := &commonTypes.PaginationInput{OrderBy: []commonTypes.SortInput{{Column: "name", Direction: commonTypes.SortDESC}}}
+
+		result, err := svc.SearchPaginate(ctx, pagination, svc.GetQuery(ctx).Where("project_code != ?", "alpha"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Total)
+		assert.Equal(t, []string{"zeta", "proj"}, codes(result))
+	})
+
+	t.Run("namespace of the context", func(t *testing.T) {
+		euCtx := database.WithNamespace(ctx, "ns-eu")
+
+		result, err := svc.SearchPaginate(euCtx, nil, svc.GetQuery(euCtx))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"proj"}, codes(result))
+	})
+}
+
+func countProjects(t *testing.T, db *gorm.DB, namespaceCode, projectCode string) int64 {
+	var count int64
+	require.NoError(t, db.Model(&model.Project{}).Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).Count(&count).Error)
+	return count
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+var ErrRedirectChainTooLong = flectoErrors.New(flectoErrors.CodeRedirectChainTooLong, "redirect chain too long")
+
+// maxReportedChains bounds the number of chains listed in the error of a publish
+const maxReportedChains = 10
+
+// redirectChain is a redirect whose target is matched by other redirects of the project
+type redirectChain struct {
+	redirect *model.Redirect
+	// targets are the targets the requests are redirected to along the chain, the final target last
+	targets []string
+	// loop is true when the chain leads back to one of its redirects
+	loop bool
+}
+
+// length returns the number of redirects of the chain
+func (c redirectChain) length() int {
+	return len(c.targets)
+}
+
+// target returns the final target of the chain
+func (c redirectChain) target() string {
+	return c.targets[len(c.targets)-1]
+}
+
+func (c redirectChain) String() string {
+	description := c.redirect.Source + " -> " + strings.Join(c.targets, " -> ")
+	if c.loop {
+		return description + " (loop)"
+	}
+	return fmt.Sprintf("%s (%d redirects)", description, c.length())
+}
+
+// resolveRedirectChains flattens or checks the chains of the published redirects of the project, as configured in
+// publish.chains
+func (s *projectService) resolveRedirectChains(ctx context.Context, tx *gorm.DB, project *model.Project) error {
+	cfg := s.ctx.CurrentConfig().Publish.Chains
+	if !cfg.Flatten && cfg.MaxDepth == 0 {
+		return nil
+	}
+
+	var redirects []model.Redirect
+	if err := tx.Where("namespace_code = ? AND project_code = ? AND is_published = ?", project.NamespaceCode, project.ProjectCode, true).
+		Order("id").Find(&redirects).Error; err != nil {
+		return err
+	}
+
+	flattened := 0
+	invalid := make([]string, 0)
+	for _, chain := range findRedirectChains(redirects, project.RedirectOptions) {
+		if cfg.Flatten && !chain.loop {
+			if err := tx.Model(&model.Redirect{}).Where("id = ?", chain.redirect.ID).Update("target", chain.target()).Error; err != nil {
+				return err
+			}
+			// The target changed, it is checked again by the next health check
+			if err := tx.Where("redirect_id = ?", chain.redirect.ID).Delete(&model.RedirectHealth{}).Error; err != nil {
+				return err
+			}
+			flattened++
+			continue
+		}
+		if chain.loop || (cfg.MaxDepth > 0 && chain.length() > cfg.MaxDepth) {
+			invalid = append(invalid, chain.String())
+		}
+	}
+
+	if flattened > 0 {
+		s.ctx.Logger.InfoContext(ctx, "redirect chains flattened", "namespace", project.NamespaceCode, "project", project.ProjectCode, "redirects", flattened)
+	}
+	if len(invalid) == 0 {
+		return nil
+	}
+	report := invalid
+	if len(report) > maxReportedChains {
+		report = append(report[:maxReportedChains:maxReportedChains], fmt.Sprintf("and %d more", len(invalid)-maxReportedChains))
+	}
+	return fmt.Errorf("%w for project %s/%s: %s", ErrRedirectChainTooLong, project.NamespaceCode, project.ProjectCode, strings.Join(report, "; "))
+}
+
+// findRedirectChains returns the chains of the redirects, in their order. Only the redirects without conditions,
+// weighted targets nor validity period are followed, and the regex redirects whose target has placeholders do not
+// start a chain.
+func findRedirectChains(redirects []model.Redirect, options commonTypes.RedirectOptions) []redirectChain {
+	tree := commonTypes.NewRedirectTreeMatcherWithOptions(options)
+	chainable := make(map[*commonTypes.Redirect]bool, len(redirects))
+	for _, redirect := range redirects {
+		if isChainable(redirect.Redirect) && tree.Insert(redirect.Redirect) == nil {
+			chainable[redirect.Redirect] = true
+		}
+	}
+
+	chains := make([]redirectChain, 0)
+	for i := range redirects {
+		redirect := redirects[i].Redirect
+		if !chainable[redirect] || (!redirect.Type.IsBasic() && strings.Contains(redirect.Target, "$")) {
+			continue
+		}
+		chain := redirectChain{redirect: &redirects[i], targets: []string{redirect.Target}}
+		visited := map[*commonTypes.Redirect]bool{redirect: true}
+		host, _, _ := strings.Cut(redirect.Source, "/")
+		if redirect.Type != commonTypes.RedirectTypeBasicHost {
+			host = ""
+		}
+		target := redirect.Target
+		for {
+			next, nextTarget, nextHost := matchRedirectTarget(tree, host, target)
+			if next == nil {
+				break
+			}
+			if visited[next] {
+				chain.loop = true
+				break
+			}
+			chain.targets = append(chain.targets, nextTarget)
+			visited[next] = true
+			host, target = nextHost, nextTarget
+		}
+		if chain.length() > 1 || chain.loop {
+			chains = append(chains, chain)
+		}
+	}
+	return chains
+}
+
+// isChainable returns true if the redirect always redirects the requests matching its source to the same target
+func isChainable(redirect *commonTypes.Redirect) bool {
+	return redirect.Type != commonTypes.RedirectTypeCatchAll && len(redirect.Conditions) == 0 && len(redirect.Targets) == 0 &&
+		redirect.ValidFrom == nil && redirect.ValidUntil == nil
+}
+
+// matchRedirectTarget returns the redirect matching the requests redirected to target from host, along with the target
+// it redirects them to and their host. The absolute targets are only followed to the redirects of their host, which
+// can be served by other projects.
+func matchRedirectTarget(tree commonTypes.RedirectTreeMatcher, host, target string) (*commonTypes.Redirect, string, string) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, "", ""
+	}
+	absolute := u.Scheme != "" || u.Host != ""
+	if absolute {
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, "", ""
+		}
+		host = u.Host
+	}
+	redirect, next := tree.Match(host, u.RequestURI())
+	if redirect == nil || (absolute && redirect.Type != commonTypes.RedirectTypeBasicHost && redirect.Type != commonTypes.RedirectTypeRegexHost) {
+		return nil, "", ""
+	}
+	return redirect, next, host
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRedirectChains(t *testing.T) {
+	redirect := func(id int64, redirectType commonTypes.RedirectType, source, target string) model.Redirect {
+		return model.Redirect{ID: id, Redirect: &commonTypes.Redirect{Type: redirectType, Source: source, Target: target, Status: commonTypes.RedirectStatusMovedPermanent}}
+	}
+	until := time.Now().Add(time.Hour)
+	conditional := redirect(8, commonTypes.RedirectTypeBasic, "/conditional", "/a")
+	conditional.Conditions = []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeQuery, Name: "lang", Value: "fr"}}
+	temporary := redirect(9, commonTypes.RedirectTypeBasic, "/temporary", "/final")
+	temporary.ValidUntil = &until
+	redirects := []model.Redirect{
+		redirect(1, commonTypes.RedirectTypeBasic, "/a", "/B"),
+		redirect(2, commonTypes.RedirectTypeBasic, "/b", "https://example.com/c"),
+		redirect(3, commonTypes.RedirectTypeBasicHost, "example.com/c", "/blog/42"),
+		redirect(4, commonTypes.RedirectTypeRegex, `^/blog/(\d+)$`, "/posts/$1"),
+		redirect(5, commonTypes.RedirectTypeBasic, "/loop1", "/loop2"),
+		redirect(6, commonTypes.RedirectTypeBasic, "/loop2", "/loop1"),
+		redirect(7, commonTypes.RedirectTypeBasic, "/external", "https://other.example/a"),
+		conditional,
+		redirect(10, commonTypes.RedirectTypeBasic, "/old", "/temporary"),
+		{ID: 11, Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeCatchAll, Target: "/", Status: commonTypes.RedirectStatusFound}},
+		temporary,
+	}
+
+	chains := findRedirectChains(redirects, commonTypes.RedirectOptions{CaseInsensitive: true})
+	require.Len(t, chains, 5)
+	assert.Equal(t, "/a -> /B -> https://example.com/c -> /blog/42 -> /posts/42 (4 redirects)", chains[0].String())
+	assert.Equal(t, "/posts/42", chains[0].target())
+	assert.Equal(t, "/b -> https://example.com/c -> /blog/42 -> /posts/42 (3 redirects)", chains[1].String())
+	assert.Equal(t, "example.com/c -> /blog/42 -> /posts/42 (2 redirects)", chains[2].String())
+	assert.Equal(t, "/loop1 -> /loop2 -> /loop1 (loop)", chains[3].String())
+	assert.True(t, chains[3].loop)
+	assert.Equal(t, "/loop2 -> /loop1 -> /loop2 (loop)", chains[4].String())
+}
+
+func TestProjectService_Publish_RedirectChains(t *testing.T) {
+	publish := func(t *testing.T, chains config.PublishChainsConfig) (map[string]string, error) {
+		db, appCtx, svc := setupPublishRetryTest(t, 0)
+		appCtx.Config.Publish.Chains = chains
+		for _, redirect := range []*commonTypes.Redirect{
+			{Type: commonTypes.RedirectTypeBasic, Source: "/new", Target: "/newer", Status: commonTypes.RedirectStatusMovedPermanent},
+			{Type: commonTypes.RedirectTypeBasic, Source: "/newer", Target: "/final", Status: commonTypes.RedirectStatusMovedPermanent},
+		} {
+			require.NoError(t, db.Create(&model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: types.Ptr(true), Redirect: redirect}).Error)
+		}
+
+		_, err := svc.Publish(context.Background(), "test-ns", "test-proj", types.PublishOptions{})
+		var redirects []model.Redirect
+		require.NoError(t, db.Where("is_published = ?", true).Find(&redirects).Error)
+		targets := make(map[string]string, len(redirects))
+		for _, redirect := range redirects {
+			targets[redirect.Source] = redirect.Target
+		}
+		return targets, err
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		targets, err := publish(t, config.PublishChainsConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"/old": "/new", "/new": "/newer", "/newer": "/final"}, targets)
+	})
+
+	t.Run("flatten", func(t *testing.T) {
+		targets, err := publish(t, config.PublishChainsConfig{Flatten: true, MaxDepth: 1})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"/old": "/final", "/new": "/final", "/newer": "/final"}, targets)
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		targets, err := publish(t, config.PublishChainsConfig{MaxDepth: 2})
+		assert.ErrorIs(t, err, ErrRedirectChainTooLong)
+		assert.EqualError(t, err, "redirect chain too long for project test-ns/test-proj: /old -> /new -> /newer -> /final (3 redirects)")
+		// The publish is rolled back
+		assert.Equal(t, map[string]string{"/new": "/newer", "/newer": "/final"}, targets)
+	})
+}
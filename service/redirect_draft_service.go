@@ -4,39 +4,66 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/types"
+	"github.com/flectolab/flecto-manager/validator"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-var ErrSourceAlreadyUsed = errors.New("source is already used in this project")
+var (
+	ErrSourceAlreadyUsed = flectoErrors.New(flectoErrors.CodeAlreadyExists, "source is already used in this project")
+	ErrRewriteEmptyFind  = flectoErrors.New(flectoErrors.CodeInvalidRequest, "rewrite find must not be empty")
+	ErrRewriteNoField    = flectoErrors.New(flectoErrors.CodeInvalidRequest, "rewrite must apply to the source or the target")
+	ErrReorderDuplicate  = flectoErrors.New(flectoErrors.CodeInvalidRequest, "redirect is listed more than once")
+	ErrReorderDeleted    = flectoErrors.New(flectoErrors.CodeConflict, "redirect is marked for deletion")
+	ErrCatchAllExists    = flectoErrors.New(flectoErrors.CodeAlreadyExists, "project already has a catch-all redirect")
+	ErrRegexTestType     = flectoErrors.New(flectoErrors.CodeInvalidRequest, "regex test applies to REGEX and REGEX_HOST redirects")
+	ErrMoveSameProject   = flectoErrors.New(flectoErrors.CodeInvalidRequest, "redirects are moved to the project they belong to")
+	ErrMoveDuplicate     = flectoErrors.New(flectoErrors.CodeInvalidRequest, "redirect is listed more than once")
+	ErrMoveDeleted       = flectoErrors.New(flectoErrors.CodeConflict, "redirect is marked for deletion")
+)
 
 type RedirectDraftService interface {
 	GetTx(ctx context.Context) *gorm.DB
 	GetQuery(ctx context.Context) *gorm.DB
 	GetByID(ctx context.Context, id int64) (*model.RedirectDraft, error)
 	GetByIDWithProject(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.RedirectDraft, error)
-	Create(ctx context.Context, namespaceCode, projectCode string, oldRedirectID *int64, newRedirect *commonTypes.Redirect) (*model.RedirectDraft, error)
-	Update(ctx context.Context, id int64, newRedirect *commonTypes.Redirect) (*model.RedirectDraft, error)
+	Create(ctx context.Context, namespaceCode, projectCode string, oldRedirectID *int64, newRedirect *commonTypes.Redirect, tags []string) (*model.RedirectDraft, error)
+	Update(ctx context.Context, id int64, newRedirect *commonTypes.Redirect, tags []string) (*model.RedirectDraft, error)
 	Delete(ctx context.Context, id int64) (bool, error)
+	DeleteByTag(ctx context.Context, namespaceCode, projectCode, tag string, force bool) (int, error)
 	Rollback(ctx context.Context, namespaceCode, projectCode string) (bool, error)
+	GetDeleted(ctx context.Context, namespaceCode, projectCode string, pagination *commonTypes.PaginationInput) (*model.RedirectTombstoneList, error)
+	RestoreDeleted(ctx context.Context, namespaceCode, projectCode string, redirectID int64) (*model.RedirectDraft, error)
+	Rewrite(ctx context.Context, namespaceCode, projectCode string, input types.RedirectRewriteInput) (*types.RedirectRewriteResult, error)
+	Reorder(ctx context.Context, namespaceCode, projectCode string, redirectIDs []int64) (int, error)
+	MoveRedirects(ctx context.Context, fromNamespaceCode, fromProjectCode, toNamespaceCode, toProjectCode string, ids []int64) ([]model.RedirectDraft, error)
+	TestRegex(input types.RedirectRegexTestInput) (*types.RedirectRegexTestResult, error)
 	Search(ctx context.Context, query *gorm.DB) ([]model.RedirectDraft, error)
 	SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.RedirectDraftList, error)
+	SearchCursor(ctx context.Context, cursor *commonTypes.CursorInput, query *gorm.DB) (*model.RedirectDraftCursorList, error)
 }
 
 type redirectDraftService struct {
 	ctx  *appContext.Context
 	repo repository.RedirectDraftRepository
+	// notifications is alerted of the deletions by tag deleting most of a project, nil sending no notification
+	notifications NotificationService
 }
 
-func NewRedirectDraftService(ctx *appContext.Context, repo repository.RedirectDraftRepository) RedirectDraftService {
+func NewRedirectDraftService(ctx *appContext.Context, repo repository.RedirectDraftRepository, notifications NotificationService) RedirectDraftService {
 	return &redirectDraftService{
-		ctx:  ctx,
-		repo: repo,
+		ctx:           ctx,
+		repo:          repo,
+		notifications: notifications,
 	}
 }
 
@@ -56,9 +83,16 @@ func (s *redirectDraftService) GetByIDWithProject(ctx context.Context, namespace
 	return s.repo.FindByIDWithProject(ctx, namespaceCode, projectCode, id)
 }
 
-func (s *redirectDraftService) Create(ctx context.Context, namespaceCode, projectCode string, oldRedirectID *int64, newRedirect *commonTypes.Redirect) (*model.RedirectDraft, error) {
+// Create creates a draft, tags are ignored for a delete draft.
+// When tags is nil, an update draft keeps the tags of the redirect it updates.
+func (s *redirectDraftService) Create(ctx context.Context, namespaceCode, projectCode string, oldRedirectID *int64, newRedirect *commonTypes.Redirect, tags []string) (*model.RedirectDraft, error) {
 	if oldRedirectID == nil && newRedirect == nil {
-		return nil, fmt.Errorf("oldRedirectID or newRedirect must be provided")
+		return nil, flectoErrors.New(flectoErrors.CodeInvalidRequest, "oldRedirectID or newRedirect must be provided")
+	}
+
+	tagNames, err := model.NormalizeTagNames(tags)
+	if err != nil {
+		return nil, err
 	}
 
 	redirectDraft := &model.RedirectDraft{
@@ -71,20 +105,30 @@ func (s *redirectDraftService) Create(ctx context.Context, namespaceCode, projec
 		redirectDraft.OldRedirectID = oldRedirectID
 		redirectDraft.ChangeType = model.DraftChangeTypeUpdate
 	}
+	if newRedirect == nil {
+		redirectDraft.ChangeType = model.DraftChangeTypeDelete
+	}
+
+	if err = runPreDraftSaveHooks(ctx, s.ctx, namespaceCode, projectCode, redirectDraft.ChangeType, newRedirect, nil); err != nil {
+		return nil, err
+	}
 
 	if newRedirect != nil {
+		newRedirect.Conditions = commonTypes.NormalizeRedirectConditions(newRedirect.Conditions)
+		newRedirect.Headers = commonTypes.NormalizeResponseHeaders(newRedirect.Headers)
 		redirectDraft.NewRedirect = newRedirect
 
 		// Check source availability
-		available, err := s.repo.CheckSourceAvailability(ctx, namespaceCode, projectCode, newRedirect.Source, oldRedirectID, nil)
+		available, err := s.repo.CheckSourceAvailability(ctx, namespaceCode, projectCode, newRedirect.Source, newRedirect.Conditions, oldRedirectID, nil)
 		if err != nil {
 			return nil, err
 		}
 		if !available {
-			return nil, ErrSourceAlreadyUsed
+			return nil, sourceUnavailableError(newRedirect)
+		}
+		if err = s.checkNormalizedSource(ctx, namespaceCode, projectCode, newRedirect, oldRedirectID, nil); err != nil {
+			return nil, err
 		}
-	} else {
-		redirectDraft.ChangeType = model.DraftChangeTypeDelete
 	}
 
 	if redirectDraft.ChangeType != model.DraftChangeTypeDelete {
@@ -92,9 +136,27 @@ func (s *redirectDraftService) Create(ctx context.Context, namespaceCode, projec
 		if errValidate != nil {
 			return nil, errValidate
 		}
+		if err = s.checkNamespacePolicy(ctx, namespaceCode, redirectDraft.NewRedirect); err != nil {
+			return nil, err
+		}
 	}
 
-	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+	err = s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		switch {
+		case redirectDraft.ChangeType == model.DraftChangeTypeUpdate && tags == nil:
+			var redirectTags []model.Tag
+			if err := tx.Model(&model.Redirect{ID: *oldRedirectID}).Association("Tags").Find(&redirectTags); err != nil {
+				return err
+			}
+			redirectDraft.Tags = redirectTags
+		case redirectDraft.ChangeType != model.DraftChangeTypeDelete:
+			draftTags, err := findOrCreateTags(tx, namespaceCode, projectCode, tagNames)
+			if err != nil {
+				return err
+			}
+			redirectDraft.Tags = draftTags
+		}
+
 		if redirectDraft.ChangeType == model.DraftChangeTypeCreate {
 			redirect := &model.Redirect{
 				NamespaceCode: namespaceCode,
@@ -120,9 +182,15 @@ func (s *redirectDraftService) Create(ctx context.Context, namespaceCode, projec
 	return s.repo.FindByID(ctx, redirectDraft.ID)
 }
 
-func (s *redirectDraftService) Update(ctx context.Context, id int64, newRedirect *commonTypes.Redirect) (*model.RedirectDraft, error) {
+// Update updates the new redirect of a draft, tags are left unchanged when nil
+func (s *redirectDraftService) Update(ctx context.Context, id int64, newRedirect *commonTypes.Redirect, tags []string) (*model.RedirectDraft, error) {
 	if newRedirect == nil {
-		return nil, fmt.Errorf("newRedirect must be provided")
+		return nil, flectoErrors.New(flectoErrors.CodeInvalidRequest, "newRedirect must be provided")
+	}
+
+	tagNames, err := model.NormalizeTagNames(tags)
+	if err != nil {
+		return nil, err
 	}
 
 	draft, err := s.repo.FindByID(ctx, id)
@@ -131,34 +199,112 @@ func (s *redirectDraftService) Update(ctx context.Context, id int64, newRedirect
 	}
 
 	if draft.ChangeType == model.DraftChangeTypeDelete {
-		return nil, fmt.Errorf("cannot update a delete draft")
+		return nil, flectoErrors.New(flectoErrors.CodeConflict, "cannot update a delete draft")
+	}
+
+	if err = runPreDraftSaveHooks(ctx, s.ctx, draft.NamespaceCode, draft.ProjectCode, draft.ChangeType, newRedirect, nil); err != nil {
+		return nil, err
 	}
 
 	errValidate := s.ctx.Validator.Struct(newRedirect)
 	if errValidate != nil {
 		return nil, errValidate
 	}
+	if err = s.checkNamespacePolicy(ctx, draft.NamespaceCode, newRedirect); err != nil {
+		return nil, err
+	}
 
-	// Check source availability if source changed
-	if draft.NewRedirect == nil || draft.NewRedirect.Source != newRedirect.Source {
-		available, err := s.repo.CheckSourceAvailability(ctx, draft.NamespaceCode, draft.ProjectCode, newRedirect.Source, draft.OldRedirectID, &draft.ID)
+	// Check source availability if type, source or conditions changed
+	newRedirect.Conditions = commonTypes.NormalizeRedirectConditions(newRedirect.Conditions)
+	newRedirect.Headers = commonTypes.NormalizeResponseHeaders(newRedirect.Headers)
+	if draft.NewRedirect == nil || draft.NewRedirect.Type != newRedirect.Type || draft.NewRedirect.Source != newRedirect.Source ||
+		commonTypes.RedirectConditionsKey(draft.NewRedirect.Conditions) != commonTypes.RedirectConditionsKey(newRedirect.Conditions) {
+		available, err := s.repo.CheckSourceAvailability(ctx, draft.NamespaceCode, draft.ProjectCode, newRedirect.Source, newRedirect.Conditions, draft.OldRedirectID, &draft.ID)
 		if err != nil {
 			return nil, err
 		}
 		if !available {
-			return nil, ErrSourceAlreadyUsed
+			return nil, sourceUnavailableError(newRedirect)
+		}
+		if err = s.checkNormalizedSource(ctx, draft.NamespaceCode, draft.ProjectCode, newRedirect, draft.OldRedirectID, &draft.ID); err != nil {
+			return nil, err
 		}
 	}
 
 	draft.NewRedirect = newRedirect
 
-	if err = s.repo.Update(ctx, draft); err != nil {
+	if tags == nil {
+		if err = s.repo.Update(ctx, draft); err != nil {
+			return nil, err
+		}
+		return draft, nil
+	}
+
+	// The draft and its tags are saved together, a failure on the tags leaving the draft unchanged
+	err = s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		if errSave := tx.Save(draft).Error; errSave != nil {
+			return errSave
+		}
+		draftTags, errTags := findOrCreateTags(tx, draft.NamespaceCode, draft.ProjectCode, tagNames)
+		if errTags != nil {
+			return errTags
+		}
+		if errTags = tx.Model(draft).Association("Tags").Replace(draftTags); errTags != nil {
+			return errTags
+		}
+		draft.Tags = draftTags
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return draft, nil
 }
 
+// checkNamespacePolicy returns ErrNamespacePolicyViolation when the redirect breaks a rule of the policy of the namespace
+func (s *redirectDraftService) checkNamespacePolicy(ctx context.Context, namespaceCode string, newRedirect *commonTypes.Redirect) error {
+	policy, err := loadNamespacePolicy(s.repo.GetTx(ctx), namespaceCode)
+	if err != nil {
+		return err
+	}
+	return namespacePolicyError(policy, newRedirect)
+}
+
+// sourceUnavailableError returns the error of a redirect whose source is already used, the empty source
+// being the one of the catch-all redirect
+func sourceUnavailableError(newRedirect *commonTypes.Redirect) error {
+	if newRedirect.Type == commonTypes.RedirectTypeCatchAll {
+		return ErrCatchAllExists
+	}
+	return ErrSourceAlreadyUsed
+}
+
+// checkNormalizedSource returns ErrSourceAlreadyUsed when a BASIC or BASIC_HOST redirect matches the same requests
+// as another redirect of the project once its source is normalized with the redirect options of the project
+func (s *redirectDraftService) checkNormalizedSource(ctx context.Context, namespaceCode, projectCode string, newRedirect *commonTypes.Redirect, excludeRedirectID, excludeDraftID *int64) error {
+	if !newRedirect.Type.IsBasic() {
+		return nil
+	}
+	var project model.Project
+	if err := s.repo.GetTx(ctx).Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Limit(1).Find(&project).Error; err != nil {
+		return err
+	}
+	if !project.RedirectOptions.NormalizesSources() {
+		return nil
+	}
+
+	conflict, err := s.repo.FindNormalizedSourceConflict(ctx, namespaceCode, projectCode, newRedirect.Source, newRedirect.Conditions, project.RedirectOptions, excludeRedirectID, excludeDraftID)
+	if err != nil {
+		return err
+	}
+	if conflict != "" {
+		return fmt.Errorf("%w: %s matches the same requests with the redirect options of the project", ErrSourceAlreadyUsed, conflict)
+	}
+	return nil
+}
+
 func (s *redirectDraftService) Delete(ctx context.Context, id int64) (bool, error) {
 	draft, err := s.repo.FindByID(ctx, id)
 	if err != nil {
@@ -183,8 +329,101 @@ func (s *redirectDraftService) Delete(ctx context.Context, id int64) (bool, erro
 	return true, nil
 }
 
+// DeleteByTag creates a delete draft for every redirect having the tag, new redirects are discarded.
+// It returns the number of redirects affected. Marking most of the published redirects for deletion is refused
+// unless force is set.
+func (s *redirectDraftService) DeleteByTag(ctx context.Context, namespaceCode, projectCode, tag string, force bool) (int, error) {
+	s.ctx.Logger.InfoContext(ctx, "redirect delete by tag started", "namespace", namespaceCode, "project", projectCode, "tag", tag)
+
+	count := 0
+	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		// Published redirects having the tag
+		var redirects []model.Redirect
+		err := tx.Preload("RedirectDraft").
+			Joins("JOIN redirect_tags ON redirect_tags.redirect_id = redirects.id").
+			Joins("JOIN tags ON tags.id = redirect_tags.tag_id").
+			Where("redirects.namespace_code = ? AND redirects.project_code = ? AND redirects.is_published = ? AND tags.name = ?", namespaceCode, projectCode, true, tag).
+			Find(&redirects).Error
+		if err != nil {
+			return err
+		}
+
+		for _, redirect := range redirects {
+			marked, errMark := markRedirectForDeletion(tx, &redirect)
+			if errMark != nil {
+				return errMark
+			}
+			if marked {
+				count++
+			}
+		}
+		if count > 0 {
+			deletion := model.MassDeletion{Operation: model.MassDeletionOperationDeleteByTag, Redirects: int64(count)}
+			if err = countPublished(tx, namespaceCode, projectCode, &deletion); err != nil {
+				return err
+			}
+			if err = guardMassDeletion(ctx, s.ctx, s.notifications, namespaceCode, projectCode, deletion, force); err != nil {
+				return err
+			}
+		}
+
+		// New redirects having the tag only exist as drafts
+		var drafts []model.RedirectDraft
+		err = tx.Joins("JOIN redirect_draft_tags ON redirect_draft_tags.redirect_draft_id = redirect_drafts.id").
+			Joins("JOIN tags ON tags.id = redirect_draft_tags.tag_id").
+			Where("redirect_drafts.namespace_code = ? AND redirect_drafts.project_code = ? AND redirect_drafts.change_type = ? AND tags.name = ?", namespaceCode, projectCode, model.DraftChangeTypeCreate, tag).
+			Find(&drafts).Error
+		if err != nil {
+			return err
+		}
+
+		for _, draft := range drafts {
+			if err = tx.Select("Tags").Delete(&draft).Error; err != nil {
+				return err
+			}
+			if err = tx.Delete(&model.Redirect{}, *draft.OldRedirectID).Error; err != nil {
+				return err
+			}
+			count++
+		}
+
+		return nil
+	})
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "redirect delete by tag failed", "namespace", namespaceCode, "project", projectCode, "tag", tag, "error", err)
+		return 0, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "redirect delete by tag completed", "namespace", namespaceCode, "project", projectCode, "tag", tag, "count", count)
+	return count, nil
+}
+
+// markRedirectForDeletion turns the draft of a published redirect into a delete draft, creating it when needed.
+// It returns false when the redirect is already marked for deletion.
+func markRedirectForDeletion(tx *gorm.DB, redirect *model.Redirect) (bool, error) {
+	draft := redirect.RedirectDraft
+	if draft == nil {
+		draft = &model.RedirectDraft{
+			NamespaceCode: redirect.NamespaceCode,
+			ProjectCode:   redirect.ProjectCode,
+			OldRedirectID: types.Ptr(redirect.ID),
+		}
+	} else if draft.ChangeType == model.DraftChangeTypeDelete {
+		return false, nil
+	}
+	draft.ChangeType = model.DraftChangeTypeDelete
+	draft.NewRedirect = nil
+	if err := tx.Save(draft).Error; err != nil {
+		return false, err
+	}
+	if err := tx.Model(draft).Association("Tags").Clear(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 func (s *redirectDraftService) Rollback(ctx context.Context, namespaceCode, projectCode string) (bool, error) {
-	s.ctx.Logger.Info("redirect drafts rollback started", "namespace", namespaceCode, "project", projectCode)
+	s.ctx.Logger.InfoContext(ctx, "redirect drafts rollback started", "namespace", namespaceCode, "project", projectCode)
 
 	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
@@ -200,20 +439,443 @@ func (s *redirectDraftService) Rollback(ctx context.Context, namespaceCode, proj
 		return nil
 	})
 	if err != nil {
-		s.ctx.Logger.Error("redirect drafts rollback failed", "namespace", namespaceCode, "project", projectCode, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "redirect drafts rollback failed", "namespace", namespaceCode, "project", projectCode, "error", err)
 		return false, err
 	}
 
-	s.ctx.Logger.Info("redirect drafts rollback completed", "namespace", namespaceCode, "project", projectCode)
+	s.ctx.Logger.InfoContext(ctx, "redirect drafts rollback completed", "namespace", namespaceCode, "project", projectCode)
 	return true, nil
 }
 
+// redirectRewriteEntry is the current content of a redirect and its content once rewritten
+type redirectRewriteEntry struct {
+	redirect  *model.Redirect
+	current   *commonTypes.Redirect
+	rewritten *commonTypes.Redirect
+	reason    string
+}
+
+func (e *redirectRewriteEntry) changed() bool {
+	return e.rewritten.Source != e.current.Source || e.rewritten.Target != e.current.Target
+}
+
+func (e *redirectRewriteEntry) applied() bool {
+	return e.changed() && e.reason == ""
+}
+
+func (e *redirectRewriteEntry) match() types.RedirectRewriteMatch {
+	return types.RedirectRewriteMatch{
+		RedirectID: e.redirect.ID,
+		Source:     e.current.Source,
+		Target:     e.current.Target,
+		NewSource:  e.rewritten.Source,
+		NewTarget:  e.rewritten.Target,
+	}
+}
+
+func redirectSourceKey(redirect *commonTypes.Redirect) string {
+	return redirect.Source + "\x00" + commonTypes.RedirectConditionsKey(redirect.Conditions)
+}
+
+func newRewriteReplacer(input types.RedirectRewriteInput) (func(string) string, error) {
+	if input.Find == "" {
+		return nil, ErrRewriteEmptyFind
+	}
+	if !input.Source && !input.Target {
+		return nil, ErrRewriteNoField
+	}
+	if !input.Regex {
+		return func(value string) string {
+			return strings.ReplaceAll(value, input.Find, input.Replace)
+		}, nil
+	}
+	re, err := regexp.Compile(input.Find)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rewrite regex: %w", err)
+	}
+	return func(value string) string {
+		return re.ReplaceAllString(value, input.Replace)
+	}, nil
+}
+
+// Rewrite applies a find/replace to the source and/or target of every redirect of the project, as currently drafted,
+// and creates or updates the drafts of the affected redirects in a single transaction.
+// Redirects whose rewritten content is invalid or whose source would be already used are reported as conflicts and left unchanged.
+func (s *redirectDraftService) Rewrite(ctx context.Context, namespaceCode, projectCode string, input types.RedirectRewriteInput) (*types.RedirectRewriteResult, error) {
+	replace, err := newRewriteReplacer(input)
+	if err != nil {
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "redirect rewrite started", "namespace", namespaceCode, "project", projectCode, "find", input.Find, "replace", input.Replace, "regex", input.Regex, "dryRun", input.DryRun)
+
+	result := &types.RedirectRewriteResult{
+		Matches:   []types.RedirectRewriteMatch{},
+		Conflicts: []types.RedirectRewriteConflict{},
+	}
+	err = s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		var redirects []model.Redirect
+		err := tx.Preload("RedirectDraft").
+			Preload("Tags").
+			Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+			Order("id").
+			Find(&redirects).Error
+		if err != nil {
+			return err
+		}
+		policy, err := loadNamespacePolicy(tx, namespaceCode)
+		if err != nil {
+			return err
+		}
+
+		entries := make([]*redirectRewriteEntry, 0, len(redirects))
+		for i := range redirects {
+			redirect := &redirects[i]
+			draft := redirect.RedirectDraft
+			var current *commonTypes.Redirect
+			switch {
+			case draft != nil && draft.ChangeType == model.DraftChangeTypeDelete:
+				continue
+			case draft != nil && draft.NewRedirect != nil:
+				current = draft.NewRedirect
+			case redirect.Redirect != nil && redirect.IsPublished != nil && *redirect.IsPublished:
+				current = redirect.Redirect
+			default:
+				continue
+			}
+
+			rewritten := *current
+			if input.Source {
+				rewritten.Source = replace(current.Source)
+			}
+			if input.Target {
+				rewritten.Target = replace(current.Target)
+			}
+			entry := &redirectRewriteEntry{redirect: redirect, current: current, rewritten: &rewritten}
+			if entry.changed() {
+				if errValidate := s.ctx.Validator.Struct(entry.rewritten); errValidate != nil {
+					entry.reason = errValidate.Error()
+				} else if errPolicy := namespacePolicyError(policy, entry.rewritten); errPolicy != nil {
+					entry.reason = errPolicy.Error()
+				}
+			}
+			entries = append(entries, entry)
+		}
+
+		// A rewritten source must stay unique, rejecting a rewrite restores the previous source
+		// which can in turn collide with another rewrite, so loop until no new conflict appears
+		for {
+			counts := make(map[string]int, len(entries))
+			for _, entry := range entries {
+				if entry.applied() {
+					counts[redirectSourceKey(entry.rewritten)]++
+				} else {
+					counts[redirectSourceKey(entry.current)]++
+				}
+			}
+			conflict := false
+			for _, entry := range entries {
+				if entry.applied() && counts[redirectSourceKey(entry.rewritten)] > 1 {
+					entry.reason = ErrSourceAlreadyUsed.Error()
+					conflict = true
+				}
+			}
+			if !conflict {
+				break
+			}
+		}
+
+		var newDrafts []*model.RedirectDraft
+		for _, entry := range entries {
+			if !entry.changed() {
+				continue
+			}
+			if entry.reason != "" {
+				result.Conflicts = append(result.Conflicts, types.RedirectRewriteConflict{RedirectRewriteMatch: entry.match(), Reason: entry.reason})
+				continue
+			}
+			result.Matches = append(result.Matches, entry.match())
+			if input.DryRun {
+				continue
+			}
+
+			if draft := entry.redirect.RedirectDraft; draft != nil {
+				draft.NewRedirect = entry.rewritten
+				if err = tx.Omit(clause.Associations).Save(draft).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			newDrafts = append(newDrafts, &model.RedirectDraft{
+				NamespaceCode: namespaceCode,
+				ProjectCode:   projectCode,
+				ChangeType:    model.DraftChangeTypeUpdate,
+				OldRedirectID: types.Ptr(entry.redirect.ID),
+				NewRedirect:   entry.rewritten,
+				Tags:          entry.redirect.Tags,
+			})
+		}
+
+		if len(newDrafts) > 0 {
+			if err = tx.CreateInBatches(newDrafts, 500).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "redirect rewrite failed", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "redirect rewrite completed", "namespace", namespaceCode, "project", projectCode, "matches", len(result.Matches), "conflicts", len(result.Conflicts), "dryRun", input.DryRun)
+	return result, nil
+}
+
+// TestRegex checks the source of a REGEX or REGEX_HOST redirect as a draft would be, and runs the samples against it
+// like the agents do
+func (s *redirectDraftService) TestRegex(input types.RedirectRegexTestInput) (*types.RedirectRegexTestResult, error) {
+	if input.Type != commonTypes.RedirectTypeRegex && input.Type != commonTypes.RedirectTypeRegexHost {
+		return nil, ErrRegexTestType
+	}
+	result := &types.RedirectRegexTestResult{Matches: []types.RedirectRegexTestMatch{}}
+	if err := validator.CheckRegexSource(input.Source); err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+	re, err := regexp.Compile(input.Source)
+	if err != nil {
+		result.Error = err.Error()
+		return result, nil
+	}
+
+	for _, sample := range input.Samples {
+		match := types.RedirectRegexTestMatch{Sample: sample, Groups: []string{}}
+		if groups := re.FindStringSubmatch(sample); groups != nil {
+			match.Matched = true
+			match.Groups = groups[1:]
+			if input.Target != "" {
+				match.Target = commonTypes.ResolveTarget(input.Target, groups)
+			}
+		}
+		result.Matches = append(result.Matches, match)
+	}
+	return result, nil
+}
+
+// Reorder gives the redirects the priorities of their position in redirectIDs, the first one getting the highest,
+// and creates or updates their drafts in a single transaction. Redirects not listed keep their priority.
+// It returns the number of redirects whose priority changed.
+func (s *redirectDraftService) Reorder(ctx context.Context, namespaceCode, projectCode string, redirectIDs []int64) (int, error) {
+	s.ctx.Logger.InfoContext(ctx, "redirect reorder started", "namespace", namespaceCode, "project", projectCode, "redirects", len(redirectIDs))
+
+	count := 0
+	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		priorities := make(map[int64]int, len(redirectIDs))
+		for i, id := range redirectIDs {
+			if _, ok := priorities[id]; ok {
+				return fmt.Errorf("%w: %d", ErrReorderDuplicate, id)
+			}
+			priorities[id] = len(redirectIDs) - i
+		}
+
+		var redirects []model.Redirect
+		err := tx.Preload("RedirectDraft").
+			Preload("Tags").
+			Where(fmt.Sprintf("%s = ? AND %s = ? AND id IN ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, redirectIDs).
+			Order("id").
+			Find(&redirects).Error
+		if err != nil {
+			return err
+		}
+		if len(redirects) != len(redirectIDs) {
+			return gorm.ErrRecordNotFound
+		}
+
+		var newDrafts []*model.RedirectDraft
+		for i := range redirects {
+			redirect := &redirects[i]
+			priority := priorities[redirect.ID]
+			draft := redirect.RedirectDraft
+			switch {
+			case draft != nil && draft.ChangeType == model.DraftChangeTypeDelete:
+				return fmt.Errorf("%w: %d", ErrReorderDeleted, redirect.ID)
+			case draft != nil && draft.NewRedirect != nil:
+				if draft.NewRedirect.Priority == priority {
+					continue
+				}
+				draft.NewRedirect.Priority = priority
+				if err = tx.Omit(clause.Associations).Save(draft).Error; err != nil {
+					return err
+				}
+			case redirect.Redirect != nil && redirect.IsPublished != nil && *redirect.IsPublished:
+				if redirect.Redirect.Priority == priority {
+					continue
+				}
+				newRedirect := *redirect.Redirect
+				newRedirect.Priority = priority
+				newDrafts = append(newDrafts, &model.RedirectDraft{
+					NamespaceCode: namespaceCode,
+					ProjectCode:   projectCode,
+					ChangeType:    model.DraftChangeTypeUpdate,
+					OldRedirectID: types.Ptr(redirect.ID),
+					NewRedirect:   &newRedirect,
+					Tags:          redirect.Tags,
+				})
+			default:
+				return gorm.ErrRecordNotFound
+			}
+			count++
+		}
+
+		if len(newDrafts) > 0 {
+			if err = tx.CreateInBatches(newDrafts, 500).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "redirect reorder failed", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return 0, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "redirect reorder completed", "namespace", namespaceCode, "project", projectCode, "count", count)
+	return count, nil
+}
+
+// MoveRedirects moves the redirects to another project, possibly of another namespace, in a single transaction. The
+// published redirects get a delete draft in their project and the new ones have their create draft discarded, while
+// a create draft stages each redirect, with its pending changes and tags, in the destination project. The staged
+// redirects go through the checks of Create, their sources having to be available in the destination project.
+// It returns the create drafts of the destination project.
+func (s *redirectDraftService) MoveRedirects(ctx context.Context, fromNamespaceCode, fromProjectCode, toNamespaceCode, toProjectCode string, ids []int64) ([]model.RedirectDraft, error) {
+	if fromNamespaceCode == toNamespaceCode && fromProjectCode == toProjectCode {
+		return nil, ErrMoveSameProject
+	}
+	s.ctx.Logger.InfoContext(ctx, "redirect move started", "namespace", fromNamespaceCode, "project", fromProjectCode, "targetNamespace", toNamespaceCode, "targetProject", toProjectCode, "redirects", len(ids))
+
+	moved := make([]model.RedirectDraft, 0, len(ids))
+	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		seen := make(map[int64]bool, len(ids))
+		for _, id := range ids {
+			if seen[id] {
+				return fmt.Errorf("%w: %d", ErrMoveDuplicate, id)
+			}
+			seen[id] = true
+		}
+
+		err := tx.Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), toNamespaceCode, toProjectCode).
+			First(&model.Project{}).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("destination project %s/%s: %w", toNamespaceCode, toProjectCode, err)
+			}
+			return err
+		}
+
+		var redirects []model.Redirect
+		err = tx.Preload("RedirectDraft.Tags").
+			Preload("Tags").
+			Where(fmt.Sprintf("%s = ? AND %s = ? AND id IN ?", model.ColumnNamespaceCode, model.ColumnProjectCode), fromNamespaceCode, fromProjectCode, ids).
+			Order("id").
+			Find(&redirects).Error
+		if err != nil {
+			return err
+		}
+		if len(redirects) != len(ids) {
+			return gorm.ErrRecordNotFound
+		}
+
+		// The drafts of the destination project are staged by a draft service of the transaction
+		destination := NewRedirectDraftService(s.ctx, repository.NewRedirectDraftRepository(tx), s.notifications)
+		for i := range redirects {
+			newRedirect, tags, errDetach := detachRedirect(tx, &redirects[i])
+			if errDetach != nil {
+				return errDetach
+			}
+			draft, errCreate := destination.Create(ctx, toNamespaceCode, toProjectCode, nil, newRedirect, tags)
+			if errCreate != nil {
+				return errCreate
+			}
+			moved = append(moved, *draft)
+		}
+		return nil
+	})
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "redirect move failed", "namespace", fromNamespaceCode, "project", fromProjectCode, "targetNamespace", toNamespaceCode, "targetProject", toProjectCode, "error", err)
+		return nil, err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "redirect move completed", "namespace", fromNamespaceCode, "project", fromProjectCode, "targetNamespace", toNamespaceCode, "targetProject", toProjectCode, "count", len(moved))
+	return moved, nil
+}
+
+// detachRedirect stages the removal of a redirect from its project, and returns the redirect as its pending changes
+// leave it, with its tags
+func detachRedirect(tx *gorm.DB, redirect *model.Redirect) (*commonTypes.Redirect, []string, error) {
+	var newRedirect commonTypes.Redirect
+	var tags []string
+	draft := redirect.RedirectDraft
+	switch {
+	case draft != nil && draft.ChangeType == model.DraftChangeTypeDelete:
+		return nil, nil, fmt.Errorf("%w: %d", ErrMoveDeleted, redirect.ID)
+	case draft != nil && draft.ChangeType == model.DraftChangeTypeCreate && draft.NewRedirect != nil:
+		// A new redirect only exists as a draft, which is discarded
+		newRedirect, tags = *draft.NewRedirect, model.TagNames(draft.Tags)
+		if err := tx.Select("Tags").Delete(draft).Error; err != nil {
+			return nil, nil, err
+		}
+		if err := tx.Delete(&model.Redirect{}, redirect.ID).Error; err != nil {
+			return nil, nil, err
+		}
+		return &newRedirect, tags, nil
+	case draft != nil && draft.NewRedirect != nil:
+		newRedirect, tags = *draft.NewRedirect, model.TagNames(draft.Tags)
+	case redirect.Redirect != nil && redirect.IsPublished != nil && *redirect.IsPublished:
+		newRedirect, tags = *redirect.Redirect, model.TagNames(redirect.Tags)
+	default:
+		return nil, nil, gorm.ErrRecordNotFound
+	}
+
+	if _, err := markRedirectForDeletion(tx, redirect); err != nil {
+		return nil, nil, err
+	}
+	return &newRedirect, tags, nil
+}
+
+// GetDeleted returns the tombstones of the redirects of the project deleted by the publishes, the latest first
+func (s *redirectDraftService) GetDeleted(ctx context.Context, namespaceCode, projectCode string, pagination *commonTypes.PaginationInput) (*model.RedirectTombstoneList, error) {
+	tombstones, total, err := s.repo.SearchTombstones(ctx, namespaceCode, projectCode, pagination.GetLimit(), pagination.GetOffset())
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.RedirectTombstoneList{
+		Total:  int(total),
+		Offset: pagination.GetOffset(),
+		Limit:  pagination.GetLimit(),
+		Items:  tombstones,
+	}, nil
+}
+
+// RestoreDeleted stages a create draft recreating a redirect deleted by a publish with its tags, from its tombstone.
+// The tombstone is kept until the project is deleted, so a discarded draft can be staged again.
+func (s *redirectDraftService) RestoreDeleted(ctx context.Context, namespaceCode, projectCode string, redirectID int64) (*model.RedirectDraft, error) {
+	tombstone, err := s.repo.FindTombstone(ctx, namespaceCode, projectCode, redirectID)
+	if err != nil {
+		return nil, err
+	}
+	restored := *tombstone.Redirect
+	return s.Create(ctx, namespaceCode, projectCode, nil, &restored, tombstone.Tags)
+}
+
 func (s *redirectDraftService) Search(ctx context.Context, query *gorm.DB) ([]model.RedirectDraft, error) {
 	return s.repo.Search(ctx, query)
 }
 
 func (s *redirectDraftService) SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.RedirectDraftList, error) {
-	drafts, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	drafts, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
@@ -225,3 +887,53 @@ func (s *redirectDraftService) SearchPaginate(ctx context.Context, pagination *c
 		Items:  drafts,
 	}, nil
 }
+
+func (s *redirectDraftService) SearchCursor(ctx context.Context, cursor *commonTypes.CursorInput, query *gorm.DB) (*model.RedirectDraftCursorList, error) {
+	afterID, err := cursor.GetAfterID()
+	if err != nil {
+		return nil, err
+	}
+
+	drafts, hasMore, err := s.repo.SearchCursor(ctx, query, afterID, cursor.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.RedirectDraftCursorList{Items: drafts}
+	if hasMore {
+		nextCursor := commonTypes.EncodeCursor(drafts[len(drafts)-1].ID)
+		result.NextCursor = &nextCursor
+	}
+	return result, nil
+}
+
+// findOrCreateTags returns the tags of the project matching names, in the same order, creating the missing ones
+func findOrCreateTags(tx *gorm.DB, namespaceCode, projectCode string, names []string) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(names))
+	if len(names) == 0 {
+		return tags, nil
+	}
+
+	var existing []model.Tag
+	err := tx.Where(fmt.Sprintf("%s = ? AND %s = ? AND name IN ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode, names).
+		Find(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]model.Tag, len(existing))
+	for _, tag := range existing {
+		byName[tag.Name] = tag
+	}
+
+	for _, name := range names {
+		tag, ok := byName[name]
+		if !ok {
+			tag = model.Tag{NamespaceCode: namespaceCode, ProjectCode: projectCode, Name: name}
+			if err = tx.Create(&tag).Error; err != nil {
+				return nil, err
+			}
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
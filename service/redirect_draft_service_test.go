@@ -7,8 +7,10 @@ import (
 
 	"github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/extension"
 	mockFlectoRepository "github.com/flectolab/flecto-manager/mocks/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/model"
+	flectoTypes "github.com/flectolab/flecto-manager/types"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 	"gorm.io/driver/sqlite"
@@ -20,10 +22,10 @@ func setupRedirectDraftServiceTest(t *testing.T) (*gomock.Controller, *mockFlect
 	mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
-	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{})
 	assert.NoError(t, err)
 	mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
-	svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo)
+	svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo, nil)
 	return ctrl, mockRepo, db, svc
 }
 
@@ -112,13 +114,13 @@ func TestRedirectDraftService_Update(t *testing.T) {
 		}
 
 		mockRepo.EXPECT().FindByID(ctx, int64(1)).Return(existingDraft, nil)
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/new-source", &oldRedirectID, gomock.Any()).Return(true, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/new-source", nil, &oldRedirectID, gomock.Any()).Return(true, nil)
 		mockRepo.EXPECT().Update(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, draft *model.RedirectDraft) error {
 			assert.Equal(t, "/new-source", draft.NewRedirect.Source)
 			return nil
 		})
 
-		result, err := svc.Update(ctx, 1, newRedirect)
+		result, err := svc.Update(ctx, 1, newRedirect, nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "/new-source", result.NewRedirect.Source)
@@ -154,7 +156,7 @@ func TestRedirectDraftService_Update(t *testing.T) {
 		// No CheckSourceAvailability call because source didn't change
 		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(nil)
 
-		result, err := svc.Update(ctx, 1, newRedirect)
+		result, err := svc.Update(ctx, 1, newRedirect, nil)
 
 		assert.NoError(t, err)
 		assert.Equal(t, "/new-target", result.NewRedirect.Target)
@@ -184,9 +186,9 @@ func TestRedirectDraftService_Update(t *testing.T) {
 		}
 
 		mockRepo.EXPECT().FindByID(ctx, int64(1)).Return(existingDraft, nil)
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/existing-source", &oldRedirectID, gomock.Any()).Return(false, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/existing-source", nil, &oldRedirectID, gomock.Any()).Return(false, nil)
 
-		result, err := svc.Update(ctx, 1, newRedirect)
+		result, err := svc.Update(ctx, 1, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, ErrSourceAlreadyUsed)
@@ -218,9 +220,9 @@ func TestRedirectDraftService_Update(t *testing.T) {
 		expectedErr := errors.New("database error")
 
 		mockRepo.EXPECT().FindByID(ctx, int64(1)).Return(existingDraft, nil)
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/new-source", &oldRedirectID, gomock.Any()).Return(false, expectedErr)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/new-source", nil, &oldRedirectID, gomock.Any()).Return(false, expectedErr)
 
-		result, err := svc.Update(ctx, 1, newRedirect)
+		result, err := svc.Update(ctx, 1, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -247,7 +249,7 @@ func TestRedirectDraftService_Update(t *testing.T) {
 			Source: "/new-source",
 		}
 		mockRepo.EXPECT().FindByID(ctx, int64(1)).Return(existingDraft, nil)
-		result, err := svc.Update(ctx, 1, newRedirect)
+		result, err := svc.Update(ctx, 1, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "Field validation for 'Status' failed on the 'required' tag")
@@ -260,7 +262,7 @@ func TestRedirectDraftService_Update(t *testing.T) {
 
 		ctx := context.Background()
 
-		result, err := svc.Update(ctx, 1, nil)
+		result, err := svc.Update(ctx, 1, nil, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "newRedirect must be provided")
@@ -277,7 +279,7 @@ func TestRedirectDraftService_Update(t *testing.T) {
 
 		mockRepo.EXPECT().FindByID(ctx, int64(999)).Return(nil, expectedErr)
 
-		result, err := svc.Update(ctx, 999, newRedirect)
+		result, err := svc.Update(ctx, 999, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.Nil(t, result)
@@ -296,7 +298,7 @@ func TestRedirectDraftService_Update(t *testing.T) {
 
 		mockRepo.EXPECT().FindByID(ctx, int64(1)).Return(existingDraft, nil)
 
-		result, err := svc.Update(ctx, 1, newRedirect)
+		result, err := svc.Update(ctx, 1, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "cannot update a delete draft")
@@ -323,10 +325,10 @@ func TestRedirectDraftService_Update(t *testing.T) {
 		expectedErr := errors.New("update failed")
 
 		mockRepo.EXPECT().FindByID(ctx, int64(1)).Return(existingDraft, nil)
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", (*int64)(nil), gomock.Any()).Return(true, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, (*int64)(nil), gomock.Any()).Return(true, nil)
 		mockRepo.EXPECT().Update(ctx, gomock.Any()).Return(expectedErr)
 
-		result, err := svc.Update(ctx, 1, newRedirect)
+		result, err := svc.Update(ctx, 1, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -369,6 +371,41 @@ func TestRedirectDraftService_Search(t *testing.T) {
 	})
 }
 
+func TestRedirectDraftService_SearchCursor(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		limit := 1
+		after := types.EncodeCursor(4)
+		expectedItems := []model.RedirectDraft{{ID: 5}}
+
+		mockRepo.EXPECT().SearchCursor(ctx, nil, int64(4), 1).Return(expectedItems, true, nil)
+
+		result, err := svc.SearchCursor(ctx, &types.CursorInput{After: &after, Limit: &limit}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedItems, result.Items)
+		assert.Equal(t, types.EncodeCursor(5), *result.NextCursor)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		expectedErr := errors.New("search error")
+
+		mockRepo.EXPECT().SearchCursor(ctx, nil, int64(0), types.DefaultLimit).Return(nil, false, expectedErr)
+
+		result, err := svc.SearchCursor(ctx, nil, nil)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, result)
+	})
+}
+
 func TestRedirectDraftService_SearchPaginate(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		ctrl, mockRepo, _, svc := setupRedirectDraftServiceTest(t)
@@ -382,7 +419,7 @@ func TestRedirectDraftService_SearchPaginate(t *testing.T) {
 			{ID: 1, NamespaceCode: "test-ns"},
 		}
 
-		mockRepo.EXPECT().SearchPaginate(ctx, nil, 10, 5).Return(expectedDrafts, int64(50), nil)
+		mockRepo.EXPECT().SearchPaginate(ctx, nil, 10, 5, nil).Return(expectedDrafts, int64(50), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
 
@@ -402,7 +439,7 @@ func TestRedirectDraftService_SearchPaginate(t *testing.T) {
 		pagination := &types.PaginationInput{}
 		expectedErr := errors.New("search error")
 
-		mockRepo.EXPECT().SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).Return(nil, int64(0), expectedErr)
+		mockRepo.EXPECT().SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).Return(nil, int64(0), expectedErr)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
 
@@ -418,7 +455,7 @@ func TestRedirectDraftService_Create(t *testing.T) {
 
 		ctx := context.Background()
 
-		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, nil)
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, nil, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "oldRedirectID or newRedirect must be provided")
@@ -437,7 +474,7 @@ func TestRedirectDraftService_Create(t *testing.T) {
 			Status: types.RedirectStatusMovedPermanent,
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", (*int64)(nil), (*int64)(nil)).Return(true, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, (*int64)(nil), (*int64)(nil)).Return(true, nil)
 		// Mock FindByID called after creation to reload the draft
 		mockRepo.EXPECT().FindByID(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*model.RedirectDraft, error) {
 			var draft model.RedirectDraft
@@ -445,7 +482,7 @@ func TestRedirectDraftService_Create(t *testing.T) {
 			return &draft, nil
 		})
 
-		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect)
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -459,6 +496,131 @@ func TestRedirectDraftService_Create(t *testing.T) {
 		assert.False(t, *redirect.IsPublished)
 	})
 
+	t.Run("pre-draft-save hooks of the extensions", func(t *testing.T) {
+		ctrl, mockRepo, db, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := flectoTypes.WithSubject(context.Background(), "alice")
+		var saved extension.DraftSave
+		extensions := svc.(*redirectDraftService).ctx.Extensions
+		extensions.RegisterPreDraftSave("prefix", func(ctx context.Context, draft *extension.DraftSave) error {
+			if draft.Redirect != nil {
+				saved = *draft
+				draft.Redirect.Source = "/legacy" + draft.Redirect.Source
+			}
+			return nil
+		})
+		extensions.RegisterPreDraftSave("no-delete", func(ctx context.Context, draft *extension.DraftSave) error {
+			if draft.ChangeType == string(model.DraftChangeTypeDelete) {
+				return errors.New("deletions are reviewed by the SEO team")
+			}
+			return nil
+		})
+		newRedirect := &types.Redirect{
+			Type:   types.RedirectTypeBasic,
+			Source: "/source",
+			Target: "/target",
+			Status: types.RedirectStatusMovedPermanent,
+		}
+
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/legacy/source", nil, (*int64)(nil), (*int64)(nil)).Return(true, nil)
+		mockRepo.EXPECT().FindByID(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*model.RedirectDraft, error) {
+			var draft model.RedirectDraft
+			db.First(&draft, id)
+			return &draft, nil
+		})
+
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "/legacy/source", result.NewRedirect.Source)
+		assert.Equal(t, "alice", saved.SavedBy)
+		assert.Equal(t, string(model.DraftChangeTypeCreate), saved.ChangeType)
+		assert.Equal(t, "test-proj", saved.ProjectCode)
+
+		result, err = svc.Create(ctx, "test-ns", "test-proj", flectoTypes.Ptr(int64(1)), nil, nil)
+
+		assert.ErrorIs(t, err, ErrDraftRefused)
+		assert.ErrorContains(t, err, "no-delete: deletions are reviewed by the SEO team")
+		assert.Nil(t, result)
+	})
+
+	t.Run("source matching another one with the redirect options", func(t *testing.T) {
+		ctrl, mockRepo, db, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		options := types.RedirectOptions{CaseInsensitive: true}
+		assert.NoError(t, db.Create(&model.Project{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "Test", RedirectOptions: options}).Error)
+		newRedirect := &types.Redirect{
+			Type:   types.RedirectTypeBasic,
+			Source: "/source",
+			Target: "/target",
+			Status: types.RedirectStatusMovedPermanent,
+		}
+
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, (*int64)(nil), (*int64)(nil)).Return(true, nil)
+		mockRepo.EXPECT().FindNormalizedSourceConflict(ctx, "test-ns", "test-proj", "/source", nil, options, (*int64)(nil), (*int64)(nil)).Return("/Source", nil)
+
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
+
+		assert.ErrorIs(t, err, ErrSourceAlreadyUsed)
+		assert.ErrorContains(t, err, "/Source matches the same requests")
+		assert.Nil(t, result)
+	})
+
+	t.Run("error project already has a catch-all", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		newRedirect := &types.Redirect{
+			Type:   types.RedirectTypeCatchAll,
+			Target: "https://example.com",
+			Status: types.RedirectStatusFound,
+		}
+
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "", nil, (*int64)(nil), (*int64)(nil)).Return(false, nil)
+
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
+
+		assert.ErrorIs(t, err, ErrCatchAllExists)
+		assert.Nil(t, result)
+	})
+
+	t.Run("success create redirect draft with conditions", func(t *testing.T) {
+		ctrl, mockRepo, db, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		newRedirect := &types.Redirect{
+			Type:   types.RedirectTypeBasic,
+			Source: "/source",
+			Target: "/target",
+			Status: types.RedirectStatusMovedPermanent,
+			Conditions: []types.RedirectCondition{
+				{Type: types.RedirectConditionTypeQuery, Name: "lang", Value: "fr"},
+				{Type: types.RedirectConditionTypeHeader, Name: "x-platform", Value: "ios"},
+			},
+		}
+		normalized := []types.RedirectCondition{
+			{Type: types.RedirectConditionTypeHeader, Name: "X-Platform", Value: "ios"},
+			{Type: types.RedirectConditionTypeQuery, Name: "lang", Value: "fr"},
+		}
+
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", normalized, (*int64)(nil), (*int64)(nil)).Return(true, nil)
+		mockRepo.EXPECT().FindByID(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*model.RedirectDraft, error) {
+			var draft model.RedirectDraft
+			db.First(&draft, id)
+			return &draft, nil
+		})
+
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, normalized, result.NewRedirect.Conditions)
+	})
+
 	t.Run("success update existing redirect (ChangeType=UPDATE)", func(t *testing.T) {
 		ctrl, mockRepo, db, svc := setupRedirectDraftServiceTest(t)
 		defer ctrl.Finish()
@@ -481,14 +643,14 @@ func TestRedirectDraftService_Create(t *testing.T) {
 			Status: types.RedirectStatusMovedPermanent,
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/updated-source", &existingRedirect.ID, (*int64)(nil)).Return(true, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/updated-source", nil, &existingRedirect.ID, (*int64)(nil)).Return(true, nil)
 		mockRepo.EXPECT().FindByID(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*model.RedirectDraft, error) {
 			var draft model.RedirectDraft
 			db.Preload("OldRedirect").First(&draft, id)
 			return &draft, nil
 		})
 
-		result, err := svc.Create(ctx, "test-ns", "test-proj", &existingRedirect.ID, newRedirect)
+		result, err := svc.Create(ctx, "test-ns", "test-proj", &existingRedirect.ID, newRedirect, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -517,7 +679,7 @@ func TestRedirectDraftService_Create(t *testing.T) {
 			return &draft, nil
 		})
 
-		result, err := svc.Create(ctx, "test-ns", "test-proj", &existingRedirect.ID, nil)
+		result, err := svc.Create(ctx, "test-ns", "test-proj", &existingRedirect.ID, nil, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
@@ -531,7 +693,7 @@ func TestRedirectDraftService_Create(t *testing.T) {
 		// Create a fresh DB with callback to fail redirect creation
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{})
 		assert.NoError(t, err)
 
 		// Register callback to fail redirect creation
@@ -543,7 +705,7 @@ func TestRedirectDraftService_Create(t *testing.T) {
 
 		mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
 		mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
-		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo)
+		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo, nil)
 
 		ctx := context.Background()
 		newRedirect := &types.Redirect{
@@ -553,9 +715,9 @@ func TestRedirectDraftService_Create(t *testing.T) {
 			Status: types.RedirectStatusMovedPermanent,
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", (*int64)(nil), (*int64)(nil)).Return(true, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, (*int64)(nil), (*int64)(nil)).Return(true, nil)
 
-		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect)
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "forced redirect creation error")
@@ -569,7 +731,7 @@ func TestRedirectDraftService_Create(t *testing.T) {
 		// Create a fresh DB with callback to fail draft creation
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{})
 		assert.NoError(t, err)
 
 		// Register callback to fail only redirect_draft creation
@@ -581,7 +743,7 @@ func TestRedirectDraftService_Create(t *testing.T) {
 
 		mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
 		mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
-		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo)
+		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo, nil)
 
 		ctx := context.Background()
 		newRedirect := &types.Redirect{
@@ -591,9 +753,9 @@ func TestRedirectDraftService_Create(t *testing.T) {
 			Status: types.RedirectStatusMovedPermanent,
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", (*int64)(nil), (*int64)(nil)).Return(true, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, (*int64)(nil), (*int64)(nil)).Return(true, nil)
 
-		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect)
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "forced draft creation error")
@@ -612,9 +774,9 @@ func TestRedirectDraftService_Create(t *testing.T) {
 			Status: types.RedirectStatusMovedPermanent,
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/existing-source", (*int64)(nil), (*int64)(nil)).Return(false, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/existing-source", nil, (*int64)(nil), (*int64)(nil)).Return(false, nil)
 
-		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect)
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.ErrorIs(t, err, ErrSourceAlreadyUsed)
@@ -634,9 +796,9 @@ func TestRedirectDraftService_Create(t *testing.T) {
 		}
 		expectedErr := errors.New("database error")
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", (*int64)(nil), (*int64)(nil)).Return(false, expectedErr)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, (*int64)(nil), (*int64)(nil)).Return(false, expectedErr)
 
-		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect)
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
@@ -654,14 +816,38 @@ func TestRedirectDraftService_Create(t *testing.T) {
 			Status: types.RedirectStatusMovedPermanent,
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", (*int64)(nil), (*int64)(nil)).Return(true, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, (*int64)(nil), (*int64)(nil)).Return(true, nil)
 
-		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect)
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "Field validation for 'Target' failed on the 'required' tag")
 		assert.Nil(t, result)
 	})
+
+	t.Run("namespace policy violation", func(t *testing.T) {
+		ctrl, mockRepo, db, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		assert.NoError(t, db.Create(&model.NamespacePolicy{NamespaceCode: "test-ns", Rules: []model.NamespacePolicyRule{
+			{Type: model.NamespacePolicyRuleSameOriginTarget, Message: "targets must stay on the site"},
+		}}).Error)
+		newRedirect := &types.Redirect{
+			Type:   types.RedirectTypeBasic,
+			Source: "/source",
+			Target: "https://other.com/target",
+			Status: types.RedirectStatusMovedPermanent,
+		}
+
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, (*int64)(nil), (*int64)(nil)).Return(true, nil)
+
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect, nil)
+
+		assert.ErrorIs(t, err, ErrNamespacePolicyViolation)
+		assert.EqualError(t, err, "namespace policy violation: targets must stay on the site (https://other.com/target)")
+		assert.Nil(t, result)
+	})
 }
 
 func TestRedirectDraftService_Delete(t *testing.T) {
@@ -798,7 +984,7 @@ func TestRedirectDraftService_Delete(t *testing.T) {
 		// Create a fresh DB with callback to fail draft deletion
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{})
 		assert.NoError(t, err)
 
 		// Create redirect and draft
@@ -823,7 +1009,7 @@ func TestRedirectDraftService_Delete(t *testing.T) {
 
 		mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
 		mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
-		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo)
+		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo, nil)
 
 		ctx := context.Background()
 		mockRepo.EXPECT().FindByID(ctx, draft.ID).Return(draft, nil)
@@ -842,7 +1028,7 @@ func TestRedirectDraftService_Delete(t *testing.T) {
 		// Create a fresh DB with callback to fail redirect deletion
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{})
 		assert.NoError(t, err)
 
 		// Create redirect and draft with ChangeType=CREATE
@@ -867,7 +1053,7 @@ func TestRedirectDraftService_Delete(t *testing.T) {
 
 		mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
 		mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
-		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo)
+		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo, nil)
 
 		ctx := context.Background()
 		mockRepo.EXPECT().FindByID(ctx, draft.ID).Return(draft, nil)
@@ -880,6 +1066,496 @@ func TestRedirectDraftService_Delete(t *testing.T) {
 	})
 }
 
+func TestRedirectDraftService_Create_Tags(t *testing.T) {
+	newRedirect := func(source string) *types.Redirect {
+		return &types.Redirect{
+			Type:   types.RedirectTypeBasic,
+			Source: source,
+			Target: "/target",
+			Status: types.RedirectStatusMovedPermanent,
+		}
+	}
+	reload := func(db *gorm.DB) func(ctx context.Context, id int64) (*model.RedirectDraft, error) {
+		return func(ctx context.Context, id int64) (*model.RedirectDraft, error) {
+			var draft model.RedirectDraft
+			err := db.Preload("Tags").First(&draft, id).Error
+			return &draft, err
+		}
+	}
+
+	t.Run("creates missing tags and reuses existing ones", func(t *testing.T) {
+		ctrl, mockRepo, db, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		existing := &model.Tag{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "summer"}
+		assert.NoError(t, db.Create(existing).Error)
+
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, nil, nil).Return(true, nil)
+		mockRepo.EXPECT().FindByID(ctx, gomock.Any()).DoAndReturn(reload(db))
+
+		result, err := svc.Create(ctx, "test-ns", "test-proj", nil, newRedirect("/source"), []string{"summer", " migration ", "summer"})
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"summer", "migration"}, model.TagNames(result.Tags))
+
+		var tagCount int64
+		db.Model(&model.Tag{}).Count(&tagCount)
+		assert.Equal(t, int64(2), tagCount)
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		result, err := svc.Create(context.Background(), "test-ns", "test-proj", nil, newRedirect("/source"), []string{"not valid"})
+
+		assert.ErrorContains(t, err, "invalid tag")
+		assert.Nil(t, result)
+	})
+
+	t.Run("update draft keeps the redirect tags when tags are nil", func(t *testing.T) {
+		ctrl, mockRepo, db, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		redirect := &model.Redirect{
+			NamespaceCode: "test-ns",
+			ProjectCode:   "test-proj",
+			IsPublished:   flectoTypes.Ptr(true),
+			Redirect:      newRedirect("/source"),
+			Tags:          []model.Tag{{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "campaign"}},
+		}
+		assert.NoError(t, db.Create(redirect).Error)
+
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/source", nil, &redirect.ID, nil).Return(true, nil)
+		mockRepo.EXPECT().FindByID(ctx, gomock.Any()).DoAndReturn(reload(db))
+
+		result, err := svc.Create(ctx, "test-ns", "test-proj", &redirect.ID, newRedirect("/source"), nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.DraftChangeTypeUpdate, result.ChangeType)
+		assert.Equal(t, []string{"campaign"}, model.TagNames(result.Tags))
+	})
+}
+
+func TestRedirectDraftService_Update_Tags(t *testing.T) {
+	ctrl, mockRepo, db, svc := setupRedirectDraftServiceTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	draft := &model.RedirectDraft{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		ChangeType:    model.DraftChangeTypeCreate,
+		NewRedirect: &types.Redirect{
+			Type:   types.RedirectTypeBasic,
+			Source: "/source",
+			Target: "/target",
+			Status: types.RedirectStatusMovedPermanent,
+		},
+		Tags: []model.Tag{{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "old"}},
+	}
+	assert.NoError(t, db.Create(draft).Error)
+
+	mockRepo.EXPECT().FindByID(ctx, draft.ID).Return(draft, nil)
+
+	newRedirect := *draft.NewRedirect
+	newRedirect.Target = "/new-target"
+	result, err := svc.Update(ctx, draft.ID, &newRedirect, []string{"new"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"new"}, model.TagNames(result.Tags))
+
+	// The draft is saved in the transaction of its tags
+	var reloaded model.RedirectDraft
+	assert.NoError(t, db.Preload("Tags").First(&reloaded, draft.ID).Error)
+	assert.Equal(t, []string{"new"}, model.TagNames(reloaded.Tags))
+	assert.Equal(t, "/new-target", reloaded.NewRedirect.Target)
+}
+
+func TestRedirectDraftService_DeleteByTag(t *testing.T) {
+	ctrl, _, db, svc := setupRedirectDraftServiceTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	tag := model.Tag{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "campaign"}
+	other := model.Tag{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "other"}
+	assert.NoError(t, db.Create(&[]*model.Tag{&tag, &other}).Error)
+
+	newRedirect := func(source string) *types.Redirect {
+		return &types.Redirect{Type: types.RedirectTypeBasic, Source: source, Target: "/target", Status: types.RedirectStatusMovedPermanent}
+	}
+
+	// Published redirect with the tag and no draft
+	published := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/published"), Tags: []model.Tag{tag}}
+	assert.NoError(t, db.Create(published).Error)
+
+	// Published redirect with the tag and an update draft
+	updated := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/updated"), Tags: []model.Tag{tag}}
+	assert.NoError(t, db.Create(updated).Error)
+	updateDraft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &updated.ID, ChangeType: model.DraftChangeTypeUpdate, NewRedirect: newRedirect("/updated-new")}
+	assert.NoError(t, db.Create(updateDraft).Error)
+
+	// New redirect with the tag
+	unpublished := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(false)}
+	assert.NoError(t, db.Create(unpublished).Error)
+	createDraft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &unpublished.ID, ChangeType: model.DraftChangeTypeCreate, NewRedirect: newRedirect("/new"), Tags: []model.Tag{tag}}
+	assert.NoError(t, db.Create(createDraft).Error)
+
+	// Published redirect with another tag
+	untouched := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/untouched"), Tags: []model.Tag{other}}
+	assert.NoError(t, db.Create(untouched).Error)
+
+	count, err := svc.DeleteByTag(ctx, "test-ns", "test-proj", "campaign", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	var drafts []model.RedirectDraft
+	db.Order("old_redirect_id").Find(&drafts)
+	assert.Len(t, drafts, 2)
+	for _, draft := range drafts {
+		assert.Equal(t, model.DraftChangeTypeDelete, draft.ChangeType)
+	}
+	assert.Equal(t, published.ID, *drafts[0].OldRedirectID)
+	assert.Equal(t, updated.ID, *drafts[1].OldRedirectID)
+
+	var redirectCount int64
+	db.Model(&model.Redirect{}).Where("id = ?", unpublished.ID).Count(&redirectCount)
+	assert.Equal(t, int64(0), redirectCount)
+
+	t.Run("delete drafts are not counted twice", func(t *testing.T) {
+		count, err := svc.DeleteByTag(ctx, "test-ns", "test-proj", "campaign", false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+}
+
+func TestRedirectDraftService_Rewrite(t *testing.T) {
+	newRedirect := func(source, target string) *types.Redirect {
+		return &types.Redirect{Type: types.RedirectTypeBasicHost, Source: source, Target: target, Status: types.RedirectStatusMovedPermanent}
+	}
+	setup := func(t *testing.T) (*gomock.Controller, *gorm.DB, RedirectDraftService) {
+		ctrl, _, db, svc := setupRedirectDraftServiceTest(t)
+		return ctrl, db, svc
+	}
+
+	t.Run("creates update drafts and updates existing drafts", func(t *testing.T) {
+		ctrl, db, svc := setup(t)
+		defer ctrl.Finish()
+		ctx := context.Background()
+
+		tag := model.Tag{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "cdn"}
+		assert.NoError(t, db.Create(&tag).Error)
+
+		published := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("oldcdn.example/a", "https://oldcdn.example/b"), Tags: []model.Tag{tag}}
+		assert.NoError(t, db.Create(published).Error)
+
+		unpublished := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(false)}
+		assert.NoError(t, db.Create(unpublished).Error)
+		createDraft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &unpublished.ID, ChangeType: model.DraftChangeTypeCreate, NewRedirect: newRedirect("oldcdn.example/new", "/new")}
+		assert.NoError(t, db.Create(createDraft).Error)
+
+		deleted := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("oldcdn.example/deleted", "/deleted")}
+		assert.NoError(t, db.Create(deleted).Error)
+		deleteDraft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &deleted.ID, ChangeType: model.DraftChangeTypeDelete}
+		assert.NoError(t, db.Create(deleteDraft).Error)
+
+		untouched := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("other.example/a", "/a")}
+		assert.NoError(t, db.Create(untouched).Error)
+
+		result, err := svc.Rewrite(ctx, "test-ns", "test-proj", flectoTypes.RedirectRewriteInput{Find: "oldcdn.example", Replace: "newcdn.example", Source: true, Target: true})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Conflicts)
+		assert.Equal(t, []flectoTypes.RedirectRewriteMatch{
+			{RedirectID: published.ID, Source: "oldcdn.example/a", Target: "https://oldcdn.example/b", NewSource: "newcdn.example/a", NewTarget: "https://newcdn.example/b"},
+			{RedirectID: unpublished.ID, Source: "oldcdn.example/new", Target: "/new", NewSource: "newcdn.example/new", NewTarget: "/new"},
+		}, result.Matches)
+
+		var updateDraft model.RedirectDraft
+		assert.NoError(t, db.Preload("Tags").Where("old_redirect_id = ?", published.ID).First(&updateDraft).Error)
+		assert.Equal(t, model.DraftChangeTypeUpdate, updateDraft.ChangeType)
+		assert.Equal(t, "newcdn.example/a", updateDraft.NewRedirect.Source)
+		assert.Equal(t, "https://newcdn.example/b", updateDraft.NewRedirect.Target)
+		assert.Len(t, updateDraft.Tags, 1)
+
+		var updatedCreateDraft model.RedirectDraft
+		assert.NoError(t, db.First(&updatedCreateDraft, createDraft.ID).Error)
+		assert.Equal(t, model.DraftChangeTypeCreate, updatedCreateDraft.ChangeType)
+		assert.Equal(t, "newcdn.example/new", updatedCreateDraft.NewRedirect.Source)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(3), draftCount)
+	})
+
+	t.Run("regex with groups on target only", func(t *testing.T) {
+		ctrl, db, svc := setup(t)
+		defer ctrl.Finish()
+
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("example.com/a", "https://cdn1.example/img/a.png")}
+		assert.NoError(t, db.Create(redirect).Error)
+
+		result, err := svc.Rewrite(context.Background(), "test-ns", "test-proj", flectoTypes.RedirectRewriteInput{Find: `cdn(\d)\.example`, Replace: "static$1.example", Regex: true, Target: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Matches, 1)
+		assert.Equal(t, "example.com/a", result.Matches[0].NewSource)
+		assert.Equal(t, "https://static1.example/img/a.png", result.Matches[0].NewTarget)
+	})
+
+	t.Run("reports source conflicts", func(t *testing.T) {
+		ctrl, db, svc := setup(t)
+		defer ctrl.Finish()
+
+		existing := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("newcdn.example/a", "/a")}
+		assert.NoError(t, db.Create(existing).Error)
+		conflicting := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("oldcdn.example/a", "/a")}
+		assert.NoError(t, db.Create(conflicting).Error)
+		other := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("oldcdn.example/b", "/b")}
+		assert.NoError(t, db.Create(other).Error)
+
+		result, err := svc.Rewrite(context.Background(), "test-ns", "test-proj", flectoTypes.RedirectRewriteInput{Find: "oldcdn", Replace: "newcdn", Source: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Matches, 1)
+		assert.Equal(t, other.ID, result.Matches[0].RedirectID)
+		assert.Len(t, result.Conflicts, 1)
+		assert.Equal(t, conflicting.ID, result.Conflicts[0].RedirectID)
+		assert.Equal(t, ErrSourceAlreadyUsed.Error(), result.Conflicts[0].Reason)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(1), draftCount)
+	})
+
+	t.Run("dry run creates no draft", func(t *testing.T) {
+		ctrl, db, svc := setup(t)
+		defer ctrl.Finish()
+
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("oldcdn.example/a", "/a")}
+		assert.NoError(t, db.Create(redirect).Error)
+
+		result, err := svc.Rewrite(context.Background(), "test-ns", "test-proj", flectoTypes.RedirectRewriteInput{Find: "oldcdn", Replace: "newcdn", Source: true, DryRun: true})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Matches, 1)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(0), draftCount)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		ctrl, _, svc := setup(t)
+		defer ctrl.Finish()
+		ctx := context.Background()
+
+		_, err := svc.Rewrite(ctx, "test-ns", "test-proj", flectoTypes.RedirectRewriteInput{Source: true})
+		assert.ErrorIs(t, err, ErrRewriteEmptyFind)
+
+		_, err = svc.Rewrite(ctx, "test-ns", "test-proj", flectoTypes.RedirectRewriteInput{Find: "a"})
+		assert.ErrorIs(t, err, ErrRewriteNoField)
+
+		_, err = svc.Rewrite(ctx, "test-ns", "test-proj", flectoTypes.RedirectRewriteInput{Find: "(", Regex: true, Source: true})
+		assert.Error(t, err)
+	})
+}
+
+func TestRedirectDraftService_Reorder(t *testing.T) {
+	newRedirect := func(source string, priority int) *types.Redirect {
+		return &types.Redirect{Type: types.RedirectTypeBasic, Source: source, Target: "/target", Status: types.RedirectStatusMovedPermanent, Priority: priority}
+	}
+	setup := func(t *testing.T) (*gomock.Controller, *gorm.DB, RedirectDraftService) {
+		ctrl, _, db, svc := setupRedirectDraftServiceTest(t)
+		return ctrl, db, svc
+	}
+
+	t.Run("creates update drafts and updates existing drafts", func(t *testing.T) {
+		ctrl, db, svc := setup(t)
+		defer ctrl.Finish()
+		ctx := context.Background()
+
+		tag := model.Tag{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "legacy"}
+		assert.NoError(t, db.Create(&tag).Error)
+
+		published := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/a", 0), Tags: []model.Tag{tag}}
+		assert.NoError(t, db.Create(published).Error)
+
+		unpublished := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(false)}
+		assert.NoError(t, db.Create(unpublished).Error)
+		createDraft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &unpublished.ID, ChangeType: model.DraftChangeTypeCreate, NewRedirect: newRedirect("/new", 0)}
+		assert.NoError(t, db.Create(createDraft).Error)
+
+		// Already at its position
+		unchanged := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/unchanged", 1)}
+		assert.NoError(t, db.Create(unchanged).Error)
+
+		count, err := svc.Reorder(ctx, "test-ns", "test-proj", []int64{unpublished.ID, published.ID, unchanged.ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		var updateDraft model.RedirectDraft
+		assert.NoError(t, db.Preload("Tags").Where("old_redirect_id = ?", published.ID).First(&updateDraft).Error)
+		assert.Equal(t, model.DraftChangeTypeUpdate, updateDraft.ChangeType)
+		assert.Equal(t, "/a", updateDraft.NewRedirect.Source)
+		assert.Equal(t, 2, updateDraft.NewRedirect.Priority)
+		assert.Len(t, updateDraft.Tags, 1)
+
+		var updatedCreateDraft model.RedirectDraft
+		assert.NoError(t, db.First(&updatedCreateDraft, createDraft.ID).Error)
+		assert.Equal(t, model.DraftChangeTypeCreate, updatedCreateDraft.ChangeType)
+		assert.Equal(t, 3, updatedCreateDraft.NewRedirect.Priority)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(2), draftCount)
+	})
+
+	t.Run("invalid redirects", func(t *testing.T) {
+		ctrl, db, svc := setup(t)
+		defer ctrl.Finish()
+		ctx := context.Background()
+
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/a", 0)}
+		assert.NoError(t, db.Create(redirect).Error)
+		deleted := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/deleted", 0)}
+		assert.NoError(t, db.Create(deleted).Error)
+		assert.NoError(t, db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &deleted.ID, ChangeType: model.DraftChangeTypeDelete}).Error)
+		other := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "other-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/a", 0)}
+		assert.NoError(t, db.Create(other).Error)
+
+		_, err := svc.Reorder(ctx, "test-ns", "test-proj", []int64{redirect.ID, redirect.ID})
+		assert.ErrorIs(t, err, ErrReorderDuplicate)
+
+		_, err = svc.Reorder(ctx, "test-ns", "test-proj", []int64{redirect.ID, deleted.ID})
+		assert.ErrorIs(t, err, ErrReorderDeleted)
+
+		_, err = svc.Reorder(ctx, "test-ns", "test-proj", []int64{redirect.ID, other.ID})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(1), draftCount)
+	})
+}
+
+func TestRedirectDraftService_MoveRedirects(t *testing.T) {
+	newRedirect := func(source string) *types.Redirect {
+		return &types.Redirect{Type: types.RedirectTypeBasic, Source: source, Target: "/target", Status: types.RedirectStatusMovedPermanent}
+	}
+	setup := func(t *testing.T) (*gomock.Controller, *gorm.DB, RedirectDraftService) {
+		ctrl, _, db, svc := setupRedirectDraftServiceTest(t)
+		assert.NoError(t, db.Create(&model.Project{NamespaceCode: "test-ns", ProjectCode: "test-proj"}).Error)
+		assert.NoError(t, db.Create(&model.Project{NamespaceCode: "other-ns", ProjectCode: "dest-proj"}).Error)
+		return ctrl, db, svc
+	}
+
+	t.Run("stages the deletions and the creations", func(t *testing.T) {
+		ctrl, db, svc := setup(t)
+		defer ctrl.Finish()
+		ctx := context.Background()
+
+		tag := model.Tag{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "legacy"}
+		assert.NoError(t, db.Create(&tag).Error)
+		published := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/a"), Tags: []model.Tag{tag}}
+		assert.NoError(t, db.Create(published).Error)
+
+		updated := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/b")}
+		assert.NoError(t, db.Create(updated).Error)
+		updateDraft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &updated.ID, ChangeType: model.DraftChangeTypeUpdate, NewRedirect: newRedirect("/b-updated")}
+		assert.NoError(t, db.Create(updateDraft).Error)
+
+		unpublished := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(false)}
+		assert.NoError(t, db.Create(unpublished).Error)
+		createDraft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &unpublished.ID, ChangeType: model.DraftChangeTypeCreate, NewRedirect: newRedirect("/new")}
+		assert.NoError(t, db.Create(createDraft).Error)
+
+		drafts, err := svc.MoveRedirects(ctx, "test-ns", "test-proj", "other-ns", "dest-proj", []int64{published.ID, updated.ID, unpublished.ID})
+
+		assert.NoError(t, err)
+		assert.Len(t, drafts, 3)
+		var sources []string
+		for _, draft := range drafts {
+			assert.Equal(t, "other-ns", draft.NamespaceCode)
+			assert.Equal(t, "dest-proj", draft.ProjectCode)
+			assert.Equal(t, model.DraftChangeTypeCreate, draft.ChangeType)
+			sources = append(sources, draft.NewRedirect.Source)
+		}
+		assert.Equal(t, []string{"/a", "/b-updated", "/new"}, sources)
+		assert.Equal(t, []string{"legacy"}, model.TagNames(drafts[0].Tags))
+		assert.NotEqual(t, tag.ID, drafts[0].Tags[0].ID)
+
+		var sourceDrafts []model.RedirectDraft
+		assert.NoError(t, db.Where("project_code = ?", "test-proj").Order("old_redirect_id").Find(&sourceDrafts).Error)
+		assert.Len(t, sourceDrafts, 2)
+		for _, draft := range sourceDrafts {
+			assert.Equal(t, model.DraftChangeTypeDelete, draft.ChangeType)
+		}
+		assert.Equal(t, published.ID, *sourceDrafts[0].OldRedirectID)
+		assert.Equal(t, updateDraft.ID, sourceDrafts[1].ID)
+
+		var unpublishedCount int64
+		db.Model(&model.Redirect{}).Where("id = ?", unpublished.ID).Count(&unpublishedCount)
+		assert.Equal(t, int64(0), unpublishedCount)
+	})
+
+	t.Run("source unavailable in the destination", func(t *testing.T) {
+		ctrl, db, svc := setup(t)
+		defer ctrl.Finish()
+		ctx := context.Background()
+
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/a")}
+		assert.NoError(t, db.Create(redirect).Error)
+		taken := &model.Redirect{NamespaceCode: "other-ns", ProjectCode: "dest-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/a")}
+		assert.NoError(t, db.Create(taken).Error)
+
+		_, err := svc.MoveRedirects(ctx, "test-ns", "test-proj", "other-ns", "dest-proj", []int64{redirect.ID})
+
+		assert.ErrorIs(t, err, ErrSourceAlreadyUsed)
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(0), draftCount)
+	})
+
+	t.Run("invalid moves", func(t *testing.T) {
+		ctrl, db, svc := setup(t)
+		defer ctrl.Finish()
+		ctx := context.Background()
+
+		redirect := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/a")}
+		assert.NoError(t, db.Create(redirect).Error)
+		deleted := &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/deleted")}
+		assert.NoError(t, db.Create(deleted).Error)
+		assert.NoError(t, db.Create(&model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &deleted.ID, ChangeType: model.DraftChangeTypeDelete}).Error)
+		other := &model.Redirect{NamespaceCode: "other-ns", ProjectCode: "dest-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/other")}
+		assert.NoError(t, db.Create(other).Error)
+
+		_, err := svc.MoveRedirects(ctx, "test-ns", "test-proj", "test-ns", "test-proj", []int64{redirect.ID})
+		assert.ErrorIs(t, err, ErrMoveSameProject)
+
+		_, err = svc.MoveRedirects(ctx, "test-ns", "test-proj", "other-ns", "dest-proj", []int64{redirect.ID, redirect.ID})
+		assert.ErrorIs(t, err, ErrMoveDuplicate)
+
+		_, err = svc.MoveRedirects(ctx, "test-ns", "test-proj", "other-ns", "dest-proj", []int64{redirect.ID, deleted.ID})
+		assert.ErrorIs(t, err, ErrMoveDeleted)
+
+		_, err = svc.MoveRedirects(ctx, "test-ns", "test-proj", "other-ns", "dest-proj", []int64{redirect.ID, other.ID})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		_, err = svc.MoveRedirects(ctx, "test-ns", "test-proj", "other-ns", "missing-proj", []int64{redirect.ID})
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(1), draftCount)
+	})
+}
+
 func TestRedirectDraftService_Rollback(t *testing.T) {
 	t.Run("success deletes drafts and unpublished redirects", func(t *testing.T) {
 		ctrl, _, db, svc := setupRedirectDraftServiceTest(t)
@@ -1035,7 +1711,7 @@ func TestRedirectDraftService_Rollback(t *testing.T) {
 
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{})
 		assert.NoError(t, err)
 
 		// Register callback to fail draft deletion
@@ -1047,7 +1723,7 @@ func TestRedirectDraftService_Rollback(t *testing.T) {
 
 		mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
 		mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
-		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo)
+		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo, nil)
 
 		ctx := context.Background()
 
@@ -1064,7 +1740,7 @@ func TestRedirectDraftService_Rollback(t *testing.T) {
 
 		db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 		assert.NoError(t, err)
-		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+		err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{})
 		assert.NoError(t, err)
 
 		// Register callback to fail redirect deletion only
@@ -1076,7 +1752,7 @@ func TestRedirectDraftService_Rollback(t *testing.T) {
 
 		mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
 		mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
-		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo)
+		svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo, nil)
 
 		ctx := context.Background()
 
@@ -1093,7 +1769,7 @@ func TestRedirectDraftService_GetTx(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
-	svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo)
+	svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo, nil)
 
 	ctx := context.Background()
 	mockRepo.EXPECT().GetTx(ctx).Return(nil)
@@ -1107,7 +1783,7 @@ func TestRedirectDraftService_GetQuery(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
-	svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo)
+	svc := NewRedirectDraftService(appContext.TestContext(nil), mockRepo, nil)
 
 	ctx := context.Background()
 	mockRepo.EXPECT().GetQuery(ctx).Return(nil)
@@ -1115,3 +1791,123 @@ func TestRedirectDraftService_GetQuery(t *testing.T) {
 	result := svc.GetQuery(ctx)
 	assert.Nil(t, result)
 }
+
+func TestRedirectDraftService_RestoreDeleted(t *testing.T) {
+	t.Run("success stages a create draft", func(t *testing.T) {
+		ctrl, mockRepo, db, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		tombstone := &model.RedirectTombstone{
+			NamespaceCode: "test-ns",
+			ProjectCode:   "test-proj",
+			RedirectID:    42,
+			Redirect:      &types.Redirect{Type: types.RedirectTypeBasic, Source: "/deleted", Target: "/target", Status: types.RedirectStatusFound, Priority: 3},
+		}
+
+		mockRepo.EXPECT().FindTombstone(ctx, "test-ns", "test-proj", int64(42)).Return(tombstone, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/deleted", nil, (*int64)(nil), (*int64)(nil)).Return(true, nil)
+		mockRepo.EXPECT().FindByID(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, id int64) (*model.RedirectDraft, error) {
+			var draft model.RedirectDraft
+			db.First(&draft, id)
+			return &draft, nil
+		})
+
+		result, err := svc.RestoreDeleted(ctx, "test-ns", "test-proj", 42)
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.DraftChangeTypeCreate, result.ChangeType)
+		assert.Equal(t, "/deleted", result.NewRedirect.Source)
+		assert.Equal(t, 3, result.NewRedirect.Priority)
+		assert.NotEqual(t, int64(42), *result.OldRedirectID)
+	})
+
+	t.Run("error source used again", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		tombstone := &model.RedirectTombstone{
+			RedirectID: 42,
+			Redirect:   &types.Redirect{Type: types.RedirectTypeBasic, Source: "/deleted", Target: "/target", Status: types.RedirectStatusFound},
+		}
+
+		mockRepo.EXPECT().FindTombstone(ctx, "test-ns", "test-proj", int64(42)).Return(tombstone, nil)
+		mockRepo.EXPECT().CheckSourceAvailability(ctx, "test-ns", "test-proj", "/deleted", nil, (*int64)(nil), (*int64)(nil)).Return(false, nil)
+
+		result, err := svc.RestoreDeleted(ctx, "test-ns", "test-proj", 42)
+
+		assert.ErrorIs(t, err, ErrSourceAlreadyUsed)
+		assert.Nil(t, result)
+	})
+
+	t.Run("error tombstone not found", func(t *testing.T) {
+		ctrl, mockRepo, _, svc := setupRedirectDraftServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		mockRepo.EXPECT().FindTombstone(ctx, "test-ns", "test-proj", int64(42)).Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := svc.RestoreDeleted(ctx, "test-ns", "test-proj", 42)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, result)
+	})
+}
+
+func TestRedirectDraftService_GetDeleted(t *testing.T) {
+	ctrl, mockRepo, _, svc := setupRedirectDraftServiceTest(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	limit := 10
+	tombstones := []model.RedirectTombstone{{RedirectID: 1}, {RedirectID: 2}}
+	mockRepo.EXPECT().SearchTombstones(ctx, "test-ns", "test-proj", 10, types.DefaultOffset).Return(tombstones, int64(12), nil)
+
+	result, err := svc.GetDeleted(ctx, "test-ns", "test-proj", &types.PaginationInput{Limit: &limit})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12, result.Total)
+	assert.Equal(t, 10, result.Limit)
+	assert.Len(t, result.Items, 2)
+}
+
+func TestRedirectDraftService_TestRegex(t *testing.T) {
+	svc := NewRedirectDraftService(appContext.TestContext(nil), nil, nil)
+
+	t.Run("runs the samples", func(t *testing.T) {
+		result, err := svc.TestRegex(flectoTypes.RedirectRegexTestInput{
+			Type:    types.RedirectTypeRegex,
+			Source:  `^/blog/(\d+)/(.*)$`,
+			Target:  "/posts/$1?slug=$2",
+			Samples: []string{"/blog/12/hello", "/news/12"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &flectoTypes.RedirectRegexTestResult{Matches: []flectoTypes.RedirectRegexTestMatch{
+			{Sample: "/blog/12/hello", Matched: true, Groups: []string{"12", "hello"}, Target: "/posts/12?slug=hello"},
+			{Sample: "/news/12", Groups: []string{}},
+		}}, result)
+	})
+
+	t.Run("reports an unsafe source", func(t *testing.T) {
+		result, err := svc.TestRegex(flectoTypes.RedirectRegexTestInput{
+			Type:    types.RedirectTypeRegexHost,
+			Source:  `^example\.com/(a+)+$`,
+			Samples: []string{"example.com/aaa"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "regex with nested quantifiers", result.Error)
+		assert.Empty(t, result.Matches)
+	})
+
+	t.Run("reports an invalid source", func(t *testing.T) {
+		result, err := svc.TestRegex(flectoTypes.RedirectRegexTestInput{Type: types.RedirectTypeRegex, Source: "/blog/(", Samples: []string{"/blog/"}})
+		assert.NoError(t, err)
+		assert.Contains(t, result.Error, "missing closing )")
+	})
+
+	t.Run("error not a regex redirect", func(t *testing.T) {
+		_, err := svc.TestRegex(flectoTypes.RedirectRegexTestInput{Type: types.RedirectTypeBasic, Source: "/blog"})
+		assert.ErrorIs(t, err, ErrRegexTestType)
+	})
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// redirectExpirySubject is recorded as the author of the changes made by the expiry worker
+const redirectExpirySubject = "redirect-expiry"
+
+type RedirectExpiryService interface {
+	ExpireRedirects(ctx context.Context, now time.Time) (int, error)
+	ScheduledTask() types.ScheduledTask
+}
+
+type redirectExpiryService struct {
+	ctx  *appContext.Context
+	repo repository.RedirectRepository
+}
+
+func NewRedirectExpiryService(ctx *appContext.Context, repo repository.RedirectRepository) RedirectExpiryService {
+	return &redirectExpiryService{
+		ctx:  ctx,
+		repo: repo,
+	}
+}
+
+// ScheduledTask checks for expired redirects on all the shards, at the configured interval by default
+func (s *redirectExpiryService) ScheduledTask() types.ScheduledTask {
+	return types.ScheduledTask{
+		Name:     "redirect_expiry",
+		Interval: s.ctx.Config.Expiry.Interval,
+		Enabled:  true,
+		Run: func(ctx context.Context, now time.Time) error {
+			var errs []error
+			for _, ctx := range database.ShardContexts(s.repo.GetTx(ctx), types.WithSubject(ctx, redirectExpirySubject)) {
+				_, err := s.ExpireRedirects(ctx, now)
+				errs = append(errs, err)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// ExpireRedirects removes the published redirects whose validity period ended before now.
+// Delete drafts are created for them, or they are removed right away when auto publish is enabled.
+// It returns the number of redirects affected.
+func (s *redirectExpiryService) ExpireRedirects(ctx context.Context, now time.Time) (int, error) {
+	var redirects []model.Redirect
+	err := s.repo.GetTx(ctx).
+		Preload("RedirectDraft").
+		Where("is_published = ? AND valid_until IS NOT NULL AND valid_until <= ?", true, now).
+		Order("namespace_code, project_code, id").
+		Find(&redirects).Error
+	if err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "redirect expiry failed", "error", err)
+		return 0, err
+	}
+
+	// Group expired redirects by project, keeping the query order
+	type projectKey struct{ namespaceCode, projectCode string }
+	keys := make([]projectKey, 0)
+	byProject := make(map[projectKey][]model.Redirect)
+	for _, redirect := range redirects {
+		key := projectKey{redirect.NamespaceCode, redirect.ProjectCode}
+		if _, ok := byProject[key]; !ok {
+			keys = append(keys, key)
+		}
+		byProject[key] = append(byProject[key], redirect)
+	}
+
+	total := 0
+	var errs []error
+	for _, key := range keys {
+		var count int
+		if s.ctx.Config.Expiry.AutoPublish {
+			count, err = s.removeExpired(ctx, key.namespaceCode, key.projectCode, byProject[key], now)
+		} else {
+			count, err = s.markExpiredForDeletion(ctx, byProject[key])
+		}
+		if err != nil {
+			if errors.Is(err, ErrPublishInProgress) {
+				s.ctx.Logger.WarnContext(ctx, "redirect expiry postponed: publish in progress", "namespace", key.namespaceCode, "project", key.projectCode)
+			} else {
+				s.ctx.Logger.ErrorContext(ctx, "redirect expiry failed", "namespace", key.namespaceCode, "project", key.projectCode, "error", err)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		if count > 0 {
+			s.ctx.Logger.InfoContext(ctx, "expired redirects processed", "namespace", key.namespaceCode, "project", key.projectCode, "count", count, "autoPublish", s.ctx.Config.Expiry.AutoPublish)
+		}
+		total += count
+	}
+
+	return total, errors.Join(errs...)
+}
+
+// markExpiredForDeletion creates delete drafts for the expired redirects of a project
+func (s *redirectExpiryService) markExpiredForDeletion(ctx context.Context, redirects []model.Redirect) (int, error) {
+	count := 0
+	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, redirect := range redirects {
+			marked, err := markRedirectForDeletion(tx, &redirect)
+			if err != nil {
+				return err
+			}
+			if marked {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// removeExpired deletes the expired redirects of a project with their drafts and publishes a new project version
+func (s *redirectExpiryService) removeExpired(ctx context.Context, namespaceCode, projectCode string, redirects []model.Redirect, now time.Time) (int, error) {
+	redirectIDs := make([]int64, 0, len(redirects))
+	for _, redirect := range redirects {
+		redirectIDs = append(redirectIDs, redirect.ID)
+	}
+
+	err := s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the project row like a publish does, to not interleave with it
+		var project model.Project
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).
+			Where("namespace_code = ? AND project_code = ?", namespaceCode, projectCode).
+			First(&project).Error; err != nil {
+			if isLockError(err) {
+				return ErrPublishInProgress
+			}
+			return err
+		}
+
+		draftIDs := tx.Model(&model.RedirectDraft{}).Select("id").Where("old_redirect_id IN ?", redirectIDs)
+		if err := tx.Where("redirect_draft_id IN (?)", draftIDs).Delete(&model.RedirectDraftTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("old_redirect_id IN ?", redirectIDs).Delete(&model.RedirectDraft{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("redirect_id IN ?", redirectIDs).Delete(&model.RedirectTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id IN ?", redirectIDs).Delete(&model.Redirect{}).Error; err != nil {
+			return err
+		}
+
+		project.Version++
+		project.PublishedAt = now
+		project.PublishedBy = types.SubjectFromContext(ctx)
+		return tx.Save(&project).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(redirectIDs), nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	mockFlectoRepository "github.com/flectolab/flecto-manager/mocks/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/model"
+	flectoTypes "github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type redirectExpiryFixture struct {
+	expired      *model.Redirect
+	expiredDraft *model.Redirect
+	active       *model.Redirect
+	unlimited    *model.Redirect
+	project      *model.Project
+}
+
+func setupRedirectExpiryServiceTest(t *testing.T, autoPublish bool) (*gomock.Controller, *gorm.DB, RedirectExpiryService) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mockFlectoRepository.NewMockRedirectRepository(ctrl)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.Tag{})
+	assert.NoError(t, err)
+	mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
+	ctx := appContext.TestContext(nil)
+	ctx.Config.Expiry.AutoPublish = autoPublish
+	return ctrl, db, NewRedirectExpiryService(ctx, mockRepo)
+}
+
+func createRedirectExpiryFixture(t *testing.T, db *gorm.DB, now time.Time) *redirectExpiryFixture {
+	newRedirect := func(source string, validUntil *time.Time) *types.Redirect {
+		return &types.Redirect{Type: types.RedirectTypeBasic, Source: source, Target: "/target", Status: types.RedirectStatusMovedPermanent, ValidUntil: validUntil}
+	}
+	f := &redirectExpiryFixture{
+		project:      &model.Project{NamespaceCode: "test-ns", ProjectCode: "test-proj", Name: "Test", Version: 1},
+		expired:      &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/expired", flectoTypes.Ptr(now.Add(-time.Hour)))},
+		expiredDraft: &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/expired-draft", flectoTypes.Ptr(now))},
+		active:       &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/active", flectoTypes.Ptr(now.Add(time.Hour)))},
+		unlimited:    &model.Redirect{NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: flectoTypes.Ptr(true), Redirect: newRedirect("/unlimited", nil)},
+	}
+	assert.NoError(t, db.Create(f.project).Error)
+	assert.NoError(t, db.Create(&[]*model.Redirect{f.expired, f.expiredDraft, f.active, f.unlimited}).Error)
+	updateDraft := &model.RedirectDraft{NamespaceCode: "test-ns", ProjectCode: "test-proj", OldRedirectID: &f.expiredDraft.ID, ChangeType: model.DraftChangeTypeUpdate, NewRedirect: newRedirect("/expired-draft-new", nil)}
+	assert.NoError(t, db.Create(updateDraft).Error)
+	return f
+}
+
+func TestNewRedirectExpiryService(t *testing.T) {
+	ctrl, _, svc := setupRedirectExpiryServiceTest(t, false)
+	defer ctrl.Finish()
+
+	assert.NotNil(t, svc)
+}
+
+func TestRedirectExpiryService_ExpireRedirects(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("creates delete drafts", func(t *testing.T) {
+		ctrl, db, svc := setupRedirectExpiryServiceTest(t, false)
+		defer ctrl.Finish()
+		f := createRedirectExpiryFixture(t, db, now)
+
+		count, err := svc.ExpireRedirects(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		var drafts []model.RedirectDraft
+		db.Order("old_redirect_id").Find(&drafts)
+		assert.Len(t, drafts, 2)
+		assert.Equal(t, f.expired.ID, *drafts[0].OldRedirectID)
+		assert.Equal(t, f.expiredDraft.ID, *drafts[1].OldRedirectID)
+		for _, draft := range drafts {
+			assert.Equal(t, model.DraftChangeTypeDelete, draft.ChangeType)
+		}
+
+		var project model.Project
+		db.First(&project, f.project.ID)
+		assert.Equal(t, 1, project.Version)
+
+		t.Run("delete drafts are not counted twice", func(t *testing.T) {
+			count, err := svc.ExpireRedirects(context.Background(), now)
+
+			assert.NoError(t, err)
+			assert.Equal(t, 0, count)
+		})
+	})
+
+	t.Run("auto publish removes redirects", func(t *testing.T) {
+		ctrl, db, svc := setupRedirectExpiryServiceTest(t, true)
+		defer ctrl.Finish()
+		f := createRedirectExpiryFixture(t, db, now)
+
+		count, err := svc.ExpireRedirects(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		var sources []string
+		db.Model(&model.Redirect{}).Order("id").Pluck("source", &sources)
+		assert.Equal(t, []string{"/active", "/unlimited"}, sources)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(0), draftCount)
+
+		var project model.Project
+		db.First(&project, f.project.ID)
+		assert.Equal(t, 2, project.Version)
+		assert.True(t, project.PublishedAt.Equal(now))
+	})
+
+	t.Run("nothing expired", func(t *testing.T) {
+		ctrl, db, svc := setupRedirectExpiryServiceTest(t, true)
+		defer ctrl.Finish()
+		f := createRedirectExpiryFixture(t, db, now)
+
+		count, err := svc.ExpireRedirects(context.Background(), now.Add(-2*time.Hour))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+
+		var project model.Project
+		db.First(&project, f.project.ID)
+		assert.Equal(t, 1, project.Version)
+	})
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/bundle"
+	appContext "github.com/flectolab/flecto-manager/context"
+)
+
+// RedirectExportService renders the published redirects of a project as the configuration of a web
+// server, for the servers not running an agent
+type RedirectExportService interface {
+	Export(ctx context.Context, namespaceCode, projectCode string, version int, format bundle.RedirectFormat) ([]byte, error)
+}
+
+type redirectExportService struct {
+	ctx            *appContext.Context
+	projectService ProjectService
+}
+
+func NewRedirectExportService(ctx *appContext.Context, projectService ProjectService) RedirectExportService {
+	return &redirectExportService{
+		ctx:            ctx,
+		projectService: projectService,
+	}
+}
+
+// Export returns the redirects of the project at a version, the published version when version is 0
+func (s *redirectExportService) Export(ctx context.Context, namespaceCode, projectCode string, version int, format bundle.RedirectFormat) ([]byte, error) {
+	if !format.IsValid() {
+		return nil, fmt.Errorf("%w: %s", bundle.ErrUnsupportedRedirectFormat, format)
+	}
+	result, err := s.projectService.ExportBundle(ctx, namespaceCode, projectCode, version)
+	if err != nil {
+		return nil, err
+	}
+	return result.RedirectsManifest(format)
+}
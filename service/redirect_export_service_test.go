@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/bundle"
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	mockFlectoService "github.com/flectolab/flecto-manager/mocks/flecto-manager/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestRedirectExportService_Export(t *testing.T) {
+	published := &bundle.Bundle{
+		NamespaceCode: "ns1",
+		ProjectCode:   "proj1",
+		Version:       4,
+		Redirects: []commonTypes.Redirect{
+			{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent},
+		},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectSvc := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectSvc.EXPECT().ExportBundle(gomock.Any(), "ns1", "proj1", 0).Return(published, nil).Times(3)
+		svc := NewRedirectExportService(testContextWithPageConfig(defaultProjectCfg), mockProjectSvc)
+
+		content, err := svc.Export(context.Background(), "ns1", "proj1", 0, bundle.RedirectFormatNginx)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `"~^[^/]*+/old$" "/new";`)
+
+		content, err = svc.Export(context.Background(), "ns1", "proj1", 0, bundle.RedirectFormatApache)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `RewriteRule ^ "/new" [R=301,L,QSD,NE]`)
+
+		content, err = svc.Export(context.Background(), "ns1", "proj1", 0, bundle.RedirectFormatCaddy)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "redir @flecto_1 `/new` 301")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		svc := NewRedirectExportService(testContextWithPageConfig(defaultProjectCfg), mockFlectoService.NewMockProjectService(ctrl))
+
+		_, err := svc.Export(context.Background(), "ns1", "proj1", 0, "iis")
+		assert.ErrorIs(t, err, bundle.ErrUnsupportedRedirectFormat)
+	})
+
+	t.Run("project not found", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockProjectSvc := mockFlectoService.NewMockProjectService(ctrl)
+		mockProjectSvc.EXPECT().ExportBundle(gomock.Any(), "ns1", "unknown", 0).Return(nil, gorm.ErrRecordNotFound)
+		svc := NewRedirectExportService(testContextWithPageConfig(defaultProjectCfg), mockProjectSvc)
+
+		_, err := svc.Export(context.Background(), "ns1", "unknown", 0, bundle.RedirectFormatNginx)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
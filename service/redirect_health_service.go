@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+)
+
+const redirectHealthBatchSize = 500
+
+type RedirectHealthService interface {
+	CheckAll(ctx context.Context) (int, error)
+	GetReport(ctx context.Context, namespaceCode, projectCode string) (*model.RedirectHealthReport, error)
+	ScheduledTask() types.ScheduledTask
+}
+
+type redirectHealthService struct {
+	ctx          *appContext.Context
+	redirectRepo repository.RedirectRepository
+	healthRepo   repository.RedirectHealthRepository
+	client       *http.Client
+}
+
+func NewRedirectHealthService(ctx *appContext.Context, redirectRepo repository.RedirectRepository, healthRepo repository.RedirectHealthRepository) RedirectHealthService {
+	return &redirectHealthService{
+		ctx:          ctx,
+		redirectRepo: redirectRepo,
+		healthRepo:   healthRepo,
+		client: &http.Client{
+			Timeout: ctx.Config.Health.Timeout,
+			// A target redirecting elsewhere is reachable, its own redirect is not followed
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// ScheduledTask checks all targets of all the shards, at the configured interval by default.
+// The task is disabled when health checks are disabled.
+func (s *redirectHealthService) ScheduledTask() types.ScheduledTask {
+	return types.ScheduledTask{
+		Name:     "redirect_health",
+		Interval: s.ctx.Config.Health.Interval,
+		Enabled:  s.ctx.Config.Health.Enabled,
+		Run: func(ctx context.Context, now time.Time) error {
+			var errs []error
+			for _, ctx := range database.ShardContexts(s.redirectRepo.GetTx(ctx), ctx) {
+				_, err := s.CheckAll(ctx)
+				errs = append(errs, err)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// CheckAll checks the targets of all published redirects and returns the number of targets checked.
+// Only absolute http and https targets without capture group placeholders can be checked,
+// the results of the other redirects are removed.
+func (s *redirectHealthService) CheckAll(ctx context.Context) (int, error) {
+	s.ctx.Logger.InfoContext(ctx, "redirect health check started")
+	checked := 0
+	broken := 0
+	lastID := int64(0)
+	for {
+		var redirects []model.Redirect
+		err := s.redirectRepo.GetTx(ctx).
+			Where("is_published = ? AND id > ?", true, lastID).
+			Order("id").
+			Limit(redirectHealthBatchSize).
+			Find(&redirects).Error
+		if err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "redirect health check failed", "error", err)
+			return checked, err
+		}
+		if len(redirects) == 0 {
+			break
+		}
+		lastID = redirects[len(redirects)-1].ID
+
+		healths, uncheckable := s.checkBatch(ctx, redirects)
+		if err = s.healthRepo.Upsert(ctx, healths); err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "redirect health check failed", "error", err)
+			return checked, err
+		}
+		if err = s.healthRepo.DeleteByRedirectIDs(ctx, uncheckable); err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "redirect health check failed", "error", err)
+			return checked, err
+		}
+		checked += len(healths)
+		for _, health := range healths {
+			if health.IsBroken() {
+				broken++
+			}
+		}
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "redirect health check completed", "checked", checked, "broken", broken)
+	return checked, nil
+}
+
+func (s *redirectHealthService) GetReport(ctx context.Context, namespaceCode, projectCode string) (*model.RedirectHealthReport, error) {
+	return s.healthRepo.GetReport(ctx, namespaceCode, projectCode)
+}
+
+// checkBatch checks the targets of the redirects in parallel, a target shared by several redirects is requested once
+func (s *redirectHealthService) checkBatch(ctx context.Context, redirects []model.Redirect) ([]model.RedirectHealth, []int64) {
+	uncheckable := make([]int64, 0)
+	redirectsByTarget := make(map[string][]int64)
+	targets := make([]string, 0)
+	for _, redirect := range redirects {
+		if redirect.Redirect == nil || !isCheckableTarget(redirect.Target) {
+			uncheckable = append(uncheckable, redirect.ID)
+			continue
+		}
+		if _, ok := redirectsByTarget[redirect.Target]; !ok {
+			targets = append(targets, redirect.Target)
+		}
+		redirectsByTarget[redirect.Target] = append(redirectsByTarget[redirect.Target], redirect.ID)
+	}
+
+	results := make([]model.RedirectHealth, len(targets))
+	sem := make(chan struct{}, s.ctx.Config.Health.Concurrency)
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.checkTarget(ctx, target)
+		}()
+	}
+	wg.Wait()
+
+	healths := make([]model.RedirectHealth, 0, len(redirects))
+	for i, target := range targets {
+		for _, redirectID := range redirectsByTarget[target] {
+			health := results[i]
+			health.RedirectID = redirectID
+			healths = append(healths, health)
+		}
+	}
+	return healths, uncheckable
+}
+
+// checkTarget requests the target with HEAD, falling back to GET for servers not supporting HEAD
+func (s *redirectHealthService) checkTarget(ctx context.Context, target string) model.RedirectHealth {
+	start := time.Now()
+	statusCode, err := s.request(ctx, http.MethodHead, target)
+	if err == nil && (statusCode == http.StatusMethodNotAllowed || statusCode == http.StatusNotImplemented) {
+		statusCode, err = s.request(ctx, http.MethodGet, target)
+	}
+	health := model.RedirectHealth{
+		StatusCode: statusCode,
+		LatencyMs:  time.Since(start).Milliseconds(),
+		CheckedAt:  time.Now(),
+	}
+	if err != nil {
+		health.Error = err.Error()
+		if len(health.Error) > model.MaxRedirectHealthErrorLength {
+			health.Error = health.Error[:model.MaxRedirectHealthErrorLength]
+		}
+	}
+	return health
+}
+
+func (s *redirectHealthService) request(ctx context.Context, method, target string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "flecto-manager-health-check")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// isCheckableTarget returns true for absolute http and https targets without capture group placeholders
+func isCheckableTarget(target string) bool {
+	if strings.Contains(target, "$") {
+		return false
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	flectoTypes "github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupRedirectHealthServiceTest(t *testing.T) (*gorm.DB, RedirectHealthService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectHealth{})
+	assert.NoError(t, err)
+	svc := NewRedirectHealthService(appContext.TestContext(nil), repository.NewRedirectRepository(db), repository.NewRedirectHealthRepository(db))
+	return db, svc
+}
+
+func createHealthTestRedirect(t *testing.T, db *gorm.DB, source, target string, published bool) *model.Redirect {
+	redirect := &model.Redirect{
+		NamespaceCode: "test-ns",
+		ProjectCode:   "test-proj",
+		IsPublished:   flectoTypes.Ptr(published),
+		Redirect:      &types.Redirect{Type: types.RedirectTypeBasic, Source: source, Target: target, Status: types.RedirectStatusMovedPermanent},
+	}
+	assert.NoError(t, db.Create(redirect).Error)
+	return redirect
+}
+
+func TestNewRedirectHealthService(t *testing.T) {
+	_, svc := setupRedirectHealthServiceTest(t)
+
+	assert.NotNil(t, svc)
+}
+
+func TestRedirectHealthService_CheckAll(t *testing.T) {
+	var headRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			headRequests.Add(1)
+		}
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/elsewhere", http.StatusMovedPermanently)
+		case "/no-head":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	db, svc := setupRedirectHealthServiceTest(t)
+	ok := createHealthTestRedirect(t, db, "/a", server.URL+"/ok", true)
+	okShared := createHealthTestRedirect(t, db, "/b", server.URL+"/ok", true)
+	moved := createHealthTestRedirect(t, db, "/c", server.URL+"/moved", true)
+	noHead := createHealthTestRedirect(t, db, "/d", server.URL+"/no-head", true)
+	missing := createHealthTestRedirect(t, db, "/e", server.URL+"/missing", true)
+	unreachable := createHealthTestRedirect(t, db, "/f", "http://127.0.0.1:1/down", true)
+	relative := createHealthTestRedirect(t, db, "/g", "/relative", true)
+	createHealthTestRedirect(t, db, "/h", server.URL+"/ok", false)
+	assert.NoError(t, db.Create(&model.RedirectHealth{RedirectID: relative.ID, StatusCode: 200}).Error)
+
+	checked, err := svc.CheckAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 6, checked)
+	assert.Equal(t, int32(4), headRequests.Load(), "shared target is requested once")
+
+	healths := make(map[int64]model.RedirectHealth)
+	var rows []model.RedirectHealth
+	db.Find(&rows)
+	for _, row := range rows {
+		healths[row.RedirectID] = row
+	}
+	assert.Len(t, healths, 6)
+	assert.Equal(t, 200, healths[ok.ID].StatusCode)
+	assert.Equal(t, 200, healths[okShared.ID].StatusCode)
+	assert.Equal(t, 301, healths[moved.ID].StatusCode)
+	assert.False(t, healths[moved.ID].IsBroken())
+	assert.Equal(t, 200, healths[noHead.ID].StatusCode)
+	assert.Equal(t, 404, healths[missing.ID].StatusCode)
+	assert.True(t, healths[missing.ID].IsBroken())
+	assert.NotEmpty(t, healths[unreachable.ID].Error)
+	assert.True(t, healths[unreachable.ID].IsBroken())
+	_, found := healths[relative.ID]
+	assert.False(t, found, "result of an uncheckable target is removed")
+
+	report, err := svc.GetReport(context.Background(), "test-ns", "test-proj")
+	assert.NoError(t, err)
+	assert.Equal(t, 6, report.CheckedCount)
+	assert.Equal(t, 2, report.BrokenCount)
+}
+
+func TestIsCheckableTarget(t *testing.T) {
+	assert.True(t, isCheckableTarget("https://example.com/page"))
+	assert.True(t, isCheckableTarget("http://example.com"))
+	assert.False(t, isCheckableTarget("/relative"))
+	assert.False(t, isCheckableTarget("https://example.com/$1"))
+	assert.False(t, isCheckableTarget("ftp://example.com/file"))
+	assert.False(t, isCheckableTarget("https:///no-host"))
+}
@@ -3,20 +3,104 @@ package service
 import (
 	"context"
 	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
+	"os"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/types"
+	"github.com/flectolab/flecto-manager/xlsx"
 	"gorm.io/gorm"
 )
 
-const MaxImportFileSize = 2 * 1024 * 1024
+var (
+	ErrImportQueueFull      = flectoErrors.New(flectoErrors.CodeUnavailable, "too many import jobs are waiting, try again later")
+	ErrImportJobInterrupted = flectoErrors.New(flectoErrors.CodeInternal, "import job interrupted by a server restart")
+)
+
+// ImportFileFormat represents the format of an import file
+type ImportFileFormat string
+
+const (
+	ImportFileFormatTSV  ImportFileFormat = "TSV"
+	ImportFileFormatXLSX ImportFileFormat = "XLSX"
+	ImportFileFormatJSON ImportFileFormat = "JSON"
+)
+
+var importFileFormatByExtension = map[string]ImportFileFormat{
+	".csv":  ImportFileFormatTSV,
+	".tsv":  ImportFileFormatTSV,
+	".xlsx": ImportFileFormatXLSX,
+	".json": ImportFileFormatJSON,
+}
+
+var importFileContentTypes = map[ImportFileFormat][]string{
+	ImportFileFormatTSV: {
+		"text/csv",
+		"text/tab-separated-values",
+		"text/plain",
+		"application/csv",
+		"application/octet-stream",
+	},
+	ImportFileFormatXLSX: {
+		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		"application/zip",
+		"application/octet-stream",
+	},
+	ImportFileFormatJSON: {
+		"application/json",
+		"text/json",
+		"text/plain",
+		"application/octet-stream",
+	},
+}
+
+var importHeaderColumns = []string{"type", "source", "target", "status"}
+
+// Optional columns, accepted in any order after the required ones
+const (
+	// importTagsColumn holds comma separated tags
+	importTagsColumn       = "tags"
+	importValidFromColumn  = "valid_from"
+	importValidUntilColumn = "valid_until"
+	importPriorityColumn   = "priority"
+	// importCommentColumn holds a note stored on the draft
+	importCommentColumn = "comment"
+	// importTargetsColumn holds weighted targets as weight:target entries separated by |, e.g. 90:/new|10:/beta
+	importTargetsColumn = "targets"
+)
+
+var importOptionalColumns = []string{importTagsColumn, importValidFromColumn, importValidUntilColumn, importPriorityColumn, importCommentColumn, importTargetsColumn}
+
+// importTimeLayouts are the accepted formats of the validity columns, the values without time zone being UTC
+var importTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// importCommentMaxLength is the maximum length in characters of the comment column
+const importCommentMaxLength = 500
+
+const (
+	// importWorkerBeat is the interval of the heartbeats of the idle import workers
+	importWorkerBeat = time.Minute
+	// importWorkerTimeout is the time after which an import worker without heartbeat, i.e. stuck
+	// on a job, is reported as stalled
+	importWorkerTimeout = 30 * time.Minute
+)
 
 // ImportErrorReason represents the reason why a redirect import failed
 type ImportErrorReason string
@@ -31,6 +115,8 @@ const (
 	ImportErrorDuplicateInFile     ImportErrorReason = "DUPLICATE_SOURCE_IN_FILE"
 	ImportErrorSourceAlreadyExists ImportErrorReason = "SOURCE_ALREADY_EXISTS"
 	ImportErrorDatabaseError       ImportErrorReason = "DATABASE_ERROR"
+	ImportErrorPolicyViolation     ImportErrorReason = "POLICY_VIOLATION"
+	ImportErrorDraftRefused        ImportErrorReason = "DRAFT_REFUSED"
 )
 
 // ImportRedirectError represents a single import error
@@ -49,12 +135,19 @@ type ImportRedirectResult struct {
 	ImportedCount int
 	SkippedCount  int
 	ErrorCount    int
-	Errors        []ImportRedirectError
+	// RewrittenCount is the number of valid rows whose source or target was changed by the prefix rewrites
+	RewrittenCount int
+	Errors         []ImportRedirectError
 }
 
 // ImportRedirectOptions contains options for the import operation
 type ImportRedirectOptions struct {
 	Overwrite bool
+	// Profile gives the columns, delimiter and default values of the file, nil when the header of the file gives them
+	Profile *model.ImportProfile
+	// SourcePrefix and TargetPrefix rewrite the prefix of the sources and targets of all the rows, nil to keep them
+	SourcePrefix *model.ImportPrefixRewrite
+	TargetPrefix *model.ImportPrefixRewrite
 }
 
 // ParsedRedirectRow represents a parsed row from the import file
@@ -64,27 +157,88 @@ type ParsedRedirectRow struct {
 	Source  string
 	Target  string
 	Status  commonTypes.RedirectStatus
+	Tags    []string // nil when the file has no tags for the row
+	// ValidFrom and ValidUntil are the validity period of the row, HasValidFrom and HasValidUntil being false
+	// when the file has no such column, in which case the bound of the existing redirect is kept
+	ValidFrom     *time.Time
+	ValidUntil    *time.Time
+	HasValidFrom  bool
+	HasValidUntil bool
+	Priority      *int    // nil when the file has no priority for the row
+	Comment       *string // nil when the file has no comment for the row
+	// Targets are the weighted targets of the row, HasTargets being false when the file has no such column,
+	// in which case the targets of the existing redirect are kept
+	Targets    []commonTypes.RedirectTarget
+	HasTargets bool
+	// OriginalSource and OriginalTarget are the values of the file changed by the prefix rewrites, empty when the
+	// rewrites kept them
+	OriginalSource string
+	OriginalTarget string
+}
+
+// rewritten returns true when the prefix rewrites changed the source or the target of the row
+func (r ParsedRedirectRow) rewritten() bool {
+	return r.OriginalSource != "" || r.OriginalTarget != ""
+}
+
+// comment returns the comment of the row, empty when it has none
+func (r ParsedRedirectRow) comment() string {
+	if r.Comment == nil {
+		return ""
+	}
+	return *r.Comment
 }
 
 // RedirectImportService handles redirect import operations
 type RedirectImportService interface {
 	GetTx(ctx context.Context) *gorm.DB
 	GetQuery(ctx context.Context) *gorm.DB
-	ValidateFile(filename string, contentType string, size int64) error
-	ParseFile(reader io.Reader) ([]ParsedRedirectRow, []ImportRedirectError, error)
-	Import(ctx context.Context, namespaceCode, projectCode string, rows []ParsedRedirectRow, opts ImportRedirectOptions) (*ImportRedirectResult, error)
+	ValidateFile(filename string, contentType string, size int64) (ImportFileFormat, error)
+	ParseFile(reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions, onChunk func(rows []ParsedRedirectRow) error) (int, []ImportRedirectError, error)
+	ImportFile(ctx context.Context, namespaceCode, projectCode string, reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions) (*ImportRedirectResult, error)
+	PreviewFile(ctx context.Context, namespaceCode, projectCode string, reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions) (*ImportRedirectResult, error)
+	StartImportJob(ctx context.Context, namespaceCode, projectCode string, reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions) (*model.ImportJob, error)
+	GetImportJob(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.ImportJob, error)
+	StartWorkers()
+}
+
+// importJobTask is an import job waiting in the queue for a worker
+type importJobTask struct {
+	job *model.ImportJob
+	// file is the temporary copy of the uploaded file, removed once the job is processed
+	file   string
+	format ImportFileFormat
+	opts   ImportRedirectOptions
+	// subject is the author of the drafts created by the job
+	subject string
+}
+
+// importJobProgress holds the counters of a running job, it is only kept in memory
+// to avoid writing to the database for every processed row
+type importJobProgress struct {
+	processed int
+	imported  int
+	skipped   int
+	errored   int
 }
 
 type redirectImportService struct {
 	ctx               *appContext.Context
 	redirectDraftRepo repository.RedirectDraftRepository
+	importJobRepo     repository.ImportJobRepository
+	queue             chan importJobTask
+	progressMu        sync.RWMutex
+	progress          map[int64]importJobProgress
 }
 
 // NewRedirectImportService creates a new RedirectImportService
-func NewRedirectImportService(ctx *appContext.Context, redirectDraftRepo repository.RedirectDraftRepository) RedirectImportService {
+func NewRedirectImportService(ctx *appContext.Context, redirectDraftRepo repository.RedirectDraftRepository, importJobRepo repository.ImportJobRepository) RedirectImportService {
 	return &redirectImportService{
 		ctx:               ctx,
 		redirectDraftRepo: redirectDraftRepo,
+		importJobRepo:     importJobRepo,
+		queue:             make(chan importJobTask, ctx.Config.Import.QueueSize),
+		progress:          make(map[int64]importJobProgress),
 	}
 }
 
@@ -96,64 +250,168 @@ func (s *redirectImportService) GetQuery(ctx context.Context) *gorm.DB {
 	return s.redirectDraftRepo.GetQuery(ctx)
 }
 
-// ValidateFile validates the file metadata before parsing
-func (s *redirectImportService) ValidateFile(filename string, contentType string, size int64) error {
+// ValidateFile validates the file metadata before parsing and returns the detected file format
+func (s *redirectImportService) ValidateFile(filename string, contentType string, size int64) (ImportFileFormat, error) {
 	// Validate file size
-	if size > MaxImportFileSize {
-		return fmt.Errorf("file too large: maximum size is 2MB, got %.2fMB", float64(size)/(1024*1024))
+	maxSize := s.ctx.Config.Import.MaxFileSize
+	if size > maxSize {
+		return "", flectoErrors.Newf(flectoErrors.CodeSizeLimitExceeded, "file too large: maximum size is %.2fMB, got %.2fMB", float64(maxSize)/(1024*1024), float64(size)/(1024*1024))
 	}
 
 	// Validate file extension
-	ext := strings.ToLower(filepath.Ext(filename))
-	if ext != ".csv" && ext != ".tsv" {
-		return fmt.Errorf("invalid file type: only .csv and .tsv files are allowed")
+	format, ok := importFileFormatByExtension[strings.ToLower(filepath.Ext(filename))]
+	if !ok {
+		return "", flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid file type: only .csv, .tsv, .xlsx and .json files are allowed")
 	}
 
 	// Validate content type
 	ct := strings.ToLower(contentType)
-	allowedContentTypes := []string{
-		"text/csv",
-		"text/tab-separated-values",
-		"text/plain",
-		"application/csv",
-		"application/octet-stream",
-	}
-	for _, allowed := range allowedContentTypes {
+	for _, allowed := range importFileContentTypes[format] {
 		if strings.HasPrefix(ct, allowed) {
-			return nil
+			return format, nil
 		}
 	}
-	return fmt.Errorf("invalid content type: %s", contentType)
+	return "", flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "invalid content type: %s", contentType)
 }
 
-// ParseFile parses the CSV/TSV file and returns validated rows and parse errors
-func (s *redirectImportService) ParseFile(reader io.Reader) ([]ParsedRedirectRow, []ImportRedirectError, error) {
-	csvReader := csv.NewReader(reader)
-	csvReader.Comma = '\t'
-	csvReader.LazyQuotes = true
-	csvReader.FieldsPerRecord = -1 // Allow variable number of fields per row
+// ParseFile parses the file in the given format, laid out as the profile of the options when set, and hands the
+// validated rows, rewritten by the prefix rewrites of the options, to onChunk in batches of Import.BatchSize rows,
+// so that the file is never held in memory as a whole. It returns the number of lines and the parse errors.
+func (s *redirectImportService) ParseFile(reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions, onChunk func(rows []ParsedRedirectRow) error) (int, []ImportRedirectError, error) {
+	parser := newImportRowParser(s.ctx.Config.Import.BatchSize, onChunk)
+	if err := parseImportFile(reader, format, opts, s.ctx.Config.Import.MaxFileSize, parser); err != nil {
+		return 0, nil, err
+	}
+	return parser.total, parser.errors, nil
+}
+
+// ImportFile parses and imports the file chunk by chunk in a single transaction,
+// so that only one batch of rows is held in memory whatever the size of the file
+func (s *redirectImportService) ImportFile(ctx context.Context, namespaceCode, projectCode string, reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions) (*ImportRedirectResult, error) {
+	return s.runFile(ctx, namespaceCode, projectCode, reader, format, opts, false, nil)
+}
+
+// PreviewFile runs the same checks as ImportFile, chunk by chunk, and reports which rows would be imported, skipped
+// or rejected, without creating any redirect or draft
+func (s *redirectImportService) PreviewFile(ctx context.Context, namespaceCode, projectCode string, reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions) (*ImportRedirectResult, error) {
+	return s.runFile(ctx, namespaceCode, projectCode, reader, format, opts, true, nil)
+}
+
+// runFile parses and imports the file batch by batch, or only simulates the import when dryRun is true.
+// onProgress, when set, is called with the partial result after each batch.
+func (s *redirectImportService) runFile(ctx context.Context, namespaceCode, projectCode string, reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions, dryRun bool, onProgress func(result *ImportRedirectResult)) (*ImportRedirectResult, error) {
+	s.ctx.Logger.InfoContext(ctx, "redirect file import started", "namespace", namespaceCode, "project", projectCode, "format", format, "overwrite", opts.Overwrite, "sourcePrefix", opts.SourcePrefix, "targetPrefix", opts.TargetPrefix, "dryRun", dryRun)
+
+	result := &ImportRedirectResult{
+		Errors: make([]ImportRedirectError, 0),
+	}
+	var parseErrors []ImportRedirectError
+	importFile := func(tx *gorm.DB) error {
+		policy, err := loadNamespacePolicy(tx, namespaceCode)
+		if err != nil {
+			return err
+		}
+		sourceIndex, err := loadImportSourceIndex(tx, namespaceCode, projectCode)
+		if err != nil {
+			return err
+		}
+		result.TotalLines, parseErrors, err = s.ParseFile(reader, format, opts, func(chunk []ParsedRedirectRow) error {
+			// An import running when the manager switches to read-only mode is rolled back
+			if !dryRun {
+				if err := s.ctx.ReadOnlyError(); err != nil {
+					return err
+				}
+			}
+			if err := s.importChunk(ctx, tx, namespaceCode, projectCode, chunk, policy, sourceIndex, opts, dryRun, result); err != nil {
+				return err
+			}
+			if onProgress != nil {
+				onProgress(result)
+			}
+			return nil
+		})
+		return err
+	}
 
-	// Read and validate header
-	header, err := csvReader.Read()
+	// Import in a single transaction, a preview only needs read access
+	var err error
+	if dryRun {
+		err = importFile(s.redirectDraftRepo.GetTx(ctx))
+	} else {
+		err = s.redirectDraftRepo.GetTx(ctx).Transaction(importFile)
+	}
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read header: %w", err)
+		s.ctx.Logger.ErrorContext(ctx, "redirect file import failed", "namespace", namespaceCode, "project", projectCode, "error", err)
+		return nil, err
 	}
 
-	expectedColumns := []string{"type", "source", "target", "status"}
-	if len(header) != len(expectedColumns) {
-		return nil, nil, fmt.Errorf("invalid header: expected %d columns (type, source, target, status), got %d", len(expectedColumns), len(header))
+	result.ErrorCount += len(parseErrors)
+	result.Errors = append(parseErrors, result.Errors...)
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Line < result.Errors[j].Line
+	})
+	result.Success = result.ErrorCount == 0
+
+	s.ctx.Logger.InfoContext(ctx, "redirect file import completed", "namespace", namespaceCode, "project", projectCode, "lines", result.TotalLines, "imported", result.ImportedCount, "skipped", result.SkippedCount, "errors", result.ErrorCount, "rewritten", result.RewrittenCount, "dryRun", dryRun)
+	return result, nil
+}
+
+func parseImportFile(reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions, maxSize int64, parser *importRowParser) error {
+	profile := opts.Profile
+	if profile != nil {
+		parser.defaultType = profile.DefaultType
+		parser.defaultStatus = profile.DefaultStatus
 	}
-	for i, col := range expectedColumns {
-		if strings.ToLower(strings.TrimSpace(header[i])) != col {
-			return nil, nil, fmt.Errorf("invalid header: column %d should be '%s', got '%s'", i+1, col, header[i])
-		}
+	parser.sourcePrefix = opts.SourcePrefix
+	parser.targetPrefix = opts.TargetPrefix
+
+	var err error
+	switch format {
+	case ImportFileFormatTSV:
+		err = parseTSV(reader, profile, parser)
+	case ImportFileFormatXLSX:
+		err = parseXLSX(reader, maxSize, profile, parser)
+	case ImportFileFormatJSON:
+		err = parseJSON(reader, parser)
+	default:
+		return flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "unsupported import file format: %s", format)
+	}
+	if err != nil {
+		return err
 	}
+	return parser.flush()
+}
 
-	var rows []ParsedRedirectRow
-	var errors []ImportRedirectError
-	seenSources := make(map[string]int) // source -> first line number
+func parseTSV(reader io.Reader, profile *model.ImportProfile, parser *importRowParser) error {
+	layout, err := profileImportLayout(profile)
+	if err != nil {
+		return err
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.Comma = '\t'
+	if profile != nil {
+		csvReader.Comma = profile.Delimiter.Rune()
+	}
+	csvReader.LazyQuotes = true
+	csvReader.FieldsPerRecord = -1 // Allow variable number of fields per row
+	csvReader.ReuseRecord = true
+
+	lineNum := 0
+	if layout == nil || profile.SkipHeader {
+		// Read and validate header
+		header, errHeader := csvReader.Read()
+		if errHeader != nil {
+			return fmt.Errorf("failed to read header: %w", errHeader)
+		}
+		lineNum = 1
+		if layout == nil {
+			if layout, err = validateImportHeader(header); err != nil {
+				return err
+			}
+		}
+	}
 
-	lineNum := 1
 	for {
 		record, errRead := csvReader.Read()
 		if errRead == io.EOF {
@@ -162,7 +420,7 @@ func (s *redirectImportService) ParseFile(reader io.Reader) ([]ParsedRedirectRow
 		lineNum++
 
 		if errRead != nil {
-			errors = append(errors, ImportRedirectError{
+			parser.addError(ImportRedirectError{
 				Line:    lineNum,
 				Reason:  ImportErrorInvalidFormat,
 				Message: fmt.Sprintf("failed to read line: %v", errRead),
@@ -170,188 +428,906 @@ func (s *redirectImportService) ParseFile(reader io.Reader) ([]ParsedRedirectRow
 			continue
 		}
 
-		if len(record) != 4 {
-			errors = append(errors, ImportRedirectError{
-				Line:    lineNum,
-				Reason:  ImportErrorInvalidFormat,
-				Message: fmt.Sprintf("expected 4 columns, got %d", len(record)),
-			})
-			continue
+		record, extras := layout.split(record)
+		if err = parser.add(lineNum, record, extras); err != nil {
+			return err
 		}
+	}
 
-		// Parse type
-		redirectType, errType := parseRedirectType(strings.TrimSpace(record[0]))
-		if errType != nil {
-			errors = append(errors, ImportRedirectError{
-				Line:    lineNum,
-				Reason:  ImportErrorInvalidType,
-				Message: errType.Error(),
-			})
+	return nil
+}
+
+// parseXLSX parses the first sheet of a spreadsheet, the sheet is loaded in memory as a whole. The spreadsheet and
+// each of the files it compresses are limited to maxSize bytes.
+func parseXLSX(reader io.Reader, maxSize int64, profile *model.ImportProfile, parser *importRowParser) error {
+	layout, err := profileImportLayout(profile)
+	if err != nil {
+		return err
+	}
+	records, err := xlsx.ReadFirstSheet(reader, maxSize)
+	if errors.Is(err, xlsx.ErrTooLarge) {
+		return flectoErrors.Newf(flectoErrors.CodeSizeLimitExceeded, "spreadsheet too large: maximum size is %.2fMB once uncompressed", float64(maxSize)/(1024*1024))
+	}
+	if err != nil {
+		return err
+	}
+
+	start := 0
+	if layout == nil || profile.SkipHeader {
+		if len(records) == 0 {
+			return flectoErrors.New(flectoErrors.CodeInvalidRequest, "failed to read header: sheet is empty")
+		}
+		if layout == nil {
+			if layout, err = validateImportHeader(records[0]); err != nil {
+				return err
+			}
+		}
+		start = 1
+	}
+
+	for i, record := range records[start:] {
+		// Spreadsheets commonly contain trailing empty rows
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
 			continue
 		}
+		// Trailing empty cells are not stored in the sheet
+		for len(record) < layout.columns {
+			record = append(record, "")
+		}
+		// Dates are stored as serial numbers
+		for _, col := range []string{importValidFromColumn, importValidUntilColumn} {
+			if j, ok := layout.optional[col]; ok && j < len(record) {
+				record[j] = xlsxSerialDate(record[j])
+			}
+		}
+		record, extras := layout.split(record)
+		if err = parser.add(start+i+1, record, extras); err != nil {
+			return err
+		}
+	}
 
-		source := strings.TrimSpace(record[1])
-		target := strings.TrimSpace(record[2])
+	return nil
+}
 
-		if source == "" {
-			errors = append(errors, ImportRedirectError{
+// importJSONEntry is a single redirect of the JSON import format.
+// Status accepts both the status name and the numeric HTTP code.
+type importJSONEntry struct {
+	Type       string                       `json:"type"`
+	Source     string                       `json:"source"`
+	Target     string                       `json:"target"`
+	Status     json.RawMessage              `json:"status"`
+	Tags       []string                     `json:"tags"`
+	ValidFrom  *string                      `json:"validFrom"`
+	ValidUntil *string                      `json:"validUntil"`
+	Priority   json.RawMessage              `json:"priority"`
+	Comment    *string                      `json:"comment"`
+	Targets    []commonTypes.RedirectTarget `json:"targets"`
+}
+
+// parseJSON parses a JSON array of redirects, line numbers being the 1-based position in the array
+func parseJSON(reader io.Reader, parser *importRowParser) error {
+	decoder := json.NewDecoder(reader)
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("failed to read json: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return flectoErrors.New(flectoErrors.CodeInvalidRequest, "invalid json: expected an array of redirects")
+	}
+
+	lineNum := 0
+	for decoder.More() {
+		lineNum++
+		var entry importJSONEntry
+		if err = decoder.Decode(&entry); err != nil {
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				return fmt.Errorf("invalid json at entry %d: %w", lineNum, err)
+			}
+			parser.addError(ImportRedirectError{
 				Line:    lineNum,
-				Target:  target,
-				Reason:  ImportErrorEmptySource,
-				Message: "source cannot be empty",
+				Reason:  ImportErrorInvalidFormat,
+				Message: fmt.Sprintf("failed to read entry: %v", err),
 			})
 			continue
 		}
-		if target == "" {
-			errors = append(errors, ImportRedirectError{
-				Line:    lineNum,
-				Source:  source,
-				Reason:  ImportErrorEmptyTarget,
-				Message: "target cannot be empty",
-			})
+
+		extras := importRowExtras{tags: entry.Tags, validFrom: entry.ValidFrom, validUntil: entry.ValidUntil, comment: entry.Comment, targetList: entry.Targets}
+		if len(entry.Priority) > 0 && string(entry.Priority) != "null" {
+			extras.priority = types.Ptr(strings.Trim(string(entry.Priority), `"`))
+		}
+		if err = parser.add(lineNum, []string{entry.Type, entry.Source, entry.Target, strings.Trim(string(entry.Status), `"`)}, extras); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importLayout is the position of the columns of a file
+type importLayout struct {
+	columns int
+	// required are the positions of the type, source, target and status columns, -1 for a column the file has not
+	required []int
+	optional map[string]int
+}
+
+// importRowExtras are the values of the optional columns of a row, nil when the file has no such column
+type importRowExtras struct {
+	tags       []string
+	validFrom  *string
+	validUntil *string
+	priority   *string
+	comment    *string
+	targets    *string
+	// targetList are the targets of a JSON entry, already parsed
+	targetList []commonTypes.RedirectTarget
+	// columns is the number of columns of the file, 0 for the JSON entries
+	columns int
+	// cells is the number of cells of the row, 0 for the JSON entries
+	cells int
+}
+
+// validateImportHeader checks the required columns of the header and returns the position of the optional ones
+func validateImportHeader(header []string) (*importLayout, error) {
+	if len(header) < len(importHeaderColumns) {
+		return nil, flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "invalid header: expected %d columns (type, source, target, status) and the optional columns %s, got %d",
+			len(importHeaderColumns), strings.Join(importOptionalColumns, ", "), len(header))
+	}
+	for i, col := range importHeaderColumns {
+		if strings.ToLower(strings.TrimSpace(header[i])) != col {
+			return nil, flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "invalid header: column %d should be '%s', got '%s'", i+1, col, header[i])
+		}
+	}
+
+	layout := &importLayout{columns: len(header), required: []int{0, 1, 2, 3}, optional: make(map[string]int)}
+	for i := len(importHeaderColumns); i < len(header); i++ {
+		col := strings.ToLower(strings.TrimSpace(header[i]))
+		if !slices.Contains(importOptionalColumns, col) {
+			return nil, flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "invalid header: unknown column %d '%s', the optional columns are %s", i+1, header[i], strings.Join(importOptionalColumns, ", "))
+		}
+		if _, exists := layout.optional[col]; exists {
+			return nil, flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "invalid header: duplicate column '%s'", col)
+		}
+		layout.optional[col] = i
+	}
+	return layout, nil
+}
+
+// profileImportLayout returns the layout of the columns of the profile, nil when the header of the file gives them.
+// The profile may leave out the type and status columns, its default values applying to all the rows.
+func profileImportLayout(profile *model.ImportProfile) (*importLayout, error) {
+	if profile == nil || len(profile.Columns) == 0 {
+		return nil, nil
+	}
+
+	layout := &importLayout{columns: len(profile.Columns), required: []int{-1, -1, -1, -1}, optional: make(map[string]int)}
+	seen := make(map[string]bool, len(profile.Columns))
+	for i, col := range profile.Columns {
+		col = strings.ToLower(strings.TrimSpace(col))
+		if seen[col] {
+			return nil, flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "invalid import profile: duplicate column '%s'", col)
+		}
+		seen[col] = true
+		if j := slices.Index(importHeaderColumns, col); j >= 0 {
+			layout.required[j] = i
 			continue
 		}
+		if !slices.Contains(importOptionalColumns, col) {
+			return nil, flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "invalid import profile: unknown column %d '%s', the columns are %s",
+				i+1, profile.Columns[i], strings.Join(append(slices.Clone(importHeaderColumns), importOptionalColumns...), ", "))
+		}
+		layout.optional[col] = i
+	}
+	for _, col := range []string{"source", "target"} {
+		if !seen[col] {
+			return nil, flectoErrors.Newf(flectoErrors.CodeInvalidRequest, "invalid import profile: missing column '%s'", col)
+		}
+	}
+	return layout, nil
+}
 
-		// Parse status
-		redirectStatus, errStatus := parseRedirectStatus(strings.TrimSpace(record[3]))
-		if errStatus != nil {
-			errors = append(errors, ImportRedirectError{
+// split separates the optional columns from the redirect columns of a record, the redirect columns being returned
+// in the order type, source, target and status. A missing cell is empty, the values are nil only when the file has
+// no such column.
+func (l *importLayout) split(record []string) ([]string, importRowExtras) {
+	extras := importRowExtras{columns: l.columns, cells: len(record)}
+	if len(record) > l.columns {
+		// The row has extra columns and is rejected
+		return nil, extras
+	}
+
+	cell := func(col string) *string {
+		i, ok := l.optional[col]
+		if !ok {
+			return nil
+		}
+		if i >= len(record) {
+			return types.Ptr("")
+		}
+		return types.Ptr(record[i])
+	}
+	if tags := cell(importTagsColumn); tags != nil {
+		extras.tags = strings.Split(*tags, ",")
+	}
+	extras.validFrom = cell(importValidFromColumn)
+	extras.validUntil = cell(importValidUntilColumn)
+	extras.priority = cell(importPriorityColumn)
+	extras.comment = cell(importCommentColumn)
+	extras.targets = cell(importTargetsColumn)
+
+	redirect := make([]string, len(importHeaderColumns))
+	for j, i := range l.required {
+		if i >= len(record) {
+			// The row misses columns and is rejected
+			return nil, extras
+		}
+		if i >= 0 {
+			redirect[j] = record[i]
+		}
+	}
+	return redirect, extras
+}
+
+// parseImportTargets parses a targets cell, an empty cell meaning no weighted targets
+func parseImportTargets(value string) ([]commonTypes.RedirectTarget, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	var targets []commonTypes.RedirectTarget
+	for _, entry := range strings.Split(value, "|") {
+		weight, target, found := strings.Cut(strings.TrimSpace(entry), ":")
+		weightValue, err := strconv.Atoi(strings.TrimSpace(weight))
+		if !found || err != nil {
+			return nil, fmt.Errorf("expected weight:target entries separated by |, got '%s'", entry)
+		}
+		targets = append(targets, commonTypes.RedirectTarget{Target: strings.TrimSpace(target), Weight: weightValue})
+	}
+	return targets, nil
+}
+
+// parseImportTime parses a validity cell, an empty cell meaning no bound
+func parseImportTime(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range importTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("expected a RFC 3339 date time or a YYYY-MM-DD date, got '%s'", value)
+}
+
+// xlsxSerialDate converts a spreadsheet date, stored as the number of days since 1899-12-30, to RFC 3339.
+// Other values are returned unchanged.
+func xlsxSerialDate(value string) string {
+	days, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || days < 0 {
+		return value
+	}
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return epoch.Add(time.Duration(days * float64(24*time.Hour)).Round(time.Second)).Format(time.RFC3339)
+}
+
+// importRowParser validates records (type, source, target, status) regardless of the file format.
+// Valid rows are handed to onChunk every chunkSize rows, so a file never has to be held in memory as a whole.
+type importRowParser struct {
+	rows        []ParsedRedirectRow
+	errors      []ImportRedirectError
+	seenSources map[string]int // source -> first line number
+	total       int
+	chunkSize   int
+	onChunk     func(rows []ParsedRedirectRow) error
+	// defaultType and defaultStatus apply to the rows without type or status, nil when the rows must give them
+	defaultType   *commonTypes.RedirectType
+	defaultStatus *commonTypes.RedirectStatus
+	// sourcePrefix and targetPrefix rewrite the sources and targets of the rows, nil to keep them
+	sourcePrefix *model.ImportPrefixRewrite
+	targetPrefix *model.ImportPrefixRewrite
+}
+
+func newImportRowParser(chunkSize int, onChunk func(rows []ParsedRedirectRow) error) *importRowParser {
+	if chunkSize <= 0 {
+		chunkSize = 1
+	}
+	return &importRowParser{
+		seenSources: make(map[string]int),
+		chunkSize:   chunkSize,
+		onChunk:     onChunk,
+	}
+}
+
+func (p *importRowParser) addError(err ImportRedirectError) {
+	p.total++
+	p.errors = append(p.errors, err)
+}
+
+// flush hands the pending rows to onChunk
+func (p *importRowParser) flush() error {
+	if len(p.rows) == 0 {
+		return nil
+	}
+	rows := p.rows
+	p.rows = nil
+	return p.onChunk(rows)
+}
+
+// parseType parses the type of a row, the rows without type getting the default type when set
+func (p *importRowParser) parseType(value string) (commonTypes.RedirectType, error) {
+	if value == "" && p.defaultType != nil {
+		return *p.defaultType, nil
+	}
+	return parseRedirectType(value)
+}
+
+// parseStatus parses the status of a row, the rows without status getting the default status when set
+func (p *importRowParser) parseStatus(value string) (commonTypes.RedirectStatus, error) {
+	if value == "" && p.defaultStatus != nil {
+		return *p.defaultStatus, nil
+	}
+	return parseRedirectStatus(value)
+}
+
+func (p *importRowParser) add(lineNum int, record []string, extras importRowExtras) error {
+	if len(record) != len(importHeaderColumns) {
+		columns, cells := len(importHeaderColumns), len(record)
+		if extras.columns > 0 {
+			columns, cells = extras.columns, extras.cells
+		}
+		p.addError(ImportRedirectError{
+			Line:    lineNum,
+			Reason:  ImportErrorInvalidFormat,
+			Message: fmt.Sprintf("expected %d columns, got %d", columns, cells),
+		})
+		return nil
+	}
+
+	// Parse type
+	redirectType, errType := p.parseType(strings.TrimSpace(record[0]))
+	if errType != nil {
+		p.addError(ImportRedirectError{
+			Line:    lineNum,
+			Reason:  ImportErrorInvalidType,
+			Message: errType.Error(),
+		})
+		return nil
+	}
+
+	source := strings.TrimSpace(record[1])
+	target := strings.TrimSpace(record[2])
+
+	// Rewrite the prefixes, the row keeping the values of the file
+	var originalSource, originalTarget string
+	if rewritten, ok := p.sourcePrefix.Apply(source); ok && source != "" {
+		originalSource, source = source, rewritten
+	}
+	if rewritten, ok := p.targetPrefix.Apply(target); ok && target != "" {
+		originalTarget, target = target, rewritten
+	}
+
+	if source == "" {
+		p.addError(ImportRedirectError{
+			Line:    lineNum,
+			Target:  target,
+			Reason:  ImportErrorEmptySource,
+			Message: "source cannot be empty",
+		})
+		return nil
+	}
+	if target == "" {
+		p.addError(ImportRedirectError{
+			Line:    lineNum,
+			Source:  source,
+			Reason:  ImportErrorEmptyTarget,
+			Message: "target cannot be empty",
+		})
+		return nil
+	}
+
+	// Parse status
+	redirectStatus, errStatus := p.parseStatus(strings.TrimSpace(record[3]))
+	if errStatus != nil {
+		p.addError(ImportRedirectError{
+			Line:    lineNum,
+			Source:  source,
+			Target:  target,
+			Reason:  ImportErrorInvalidStatus,
+			Message: errStatus.Error(),
+		})
+		return nil
+	}
+
+	var tagNames []string
+	if extras.tags != nil {
+		var errTags error
+		if tagNames, errTags = model.NormalizeTagNames(extras.tags); errTags != nil {
+			p.addError(ImportRedirectError{
 				Line:    lineNum,
 				Source:  source,
 				Target:  target,
-				Reason:  ImportErrorInvalidStatus,
-				Message: errStatus.Error(),
+				Reason:  ImportErrorInvalidFormat,
+				Message: errTags.Error(),
 			})
-			continue
+			return nil
 		}
+	}
+
+	row := ParsedRedirectRow{
+		LineNum:        lineNum,
+		Type:           redirectType,
+		Source:         source,
+		Target:         target,
+		Status:         redirectStatus,
+		Tags:           tagNames,
+		HasValidFrom:   extras.validFrom != nil,
+		HasValidUntil:  extras.validUntil != nil,
+		OriginalSource: originalSource,
+		OriginalTarget: originalTarget,
+	}
+	var errTime error
+	if extras.validFrom != nil {
+		if row.ValidFrom, errTime = parseImportTime(*extras.validFrom); errTime != nil {
+			errTime = fmt.Errorf("invalid %s: %w", importValidFromColumn, errTime)
+		}
+	}
+	if errTime == nil && extras.validUntil != nil {
+		if row.ValidUntil, errTime = parseImportTime(*extras.validUntil); errTime != nil {
+			errTime = fmt.Errorf("invalid %s: %w", importValidUntilColumn, errTime)
+		}
+	}
+	if errTime != nil {
+		p.addError(ImportRedirectError{
+			Line:    lineNum,
+			Source:  source,
+			Target:  target,
+			Reason:  ImportErrorInvalidFormat,
+			Message: errTime.Error(),
+		})
+		return nil
+	}
+
+	if extras.priority != nil {
+		priority := 0
+		if value := strings.TrimSpace(*extras.priority); value != "" {
+			var errPriority error
+			if priority, errPriority = strconv.Atoi(value); errPriority != nil {
+				p.addError(ImportRedirectError{
+					Line:    lineNum,
+					Source:  source,
+					Target:  target,
+					Reason:  ImportErrorInvalidFormat,
+					Message: fmt.Sprintf("invalid %s: expected an integer, got '%s'", importPriorityColumn, value),
+				})
+				return nil
+			}
+		}
+		row.Priority = &priority
+	}
 
-		// Check for duplicate sources within the file
-		if firstLine, exists := seenSources[source]; exists {
-			errors = append(errors, ImportRedirectError{
+	if extras.comment != nil {
+		comment := strings.TrimSpace(*extras.comment)
+		if utf8.RuneCountInString(comment) > importCommentMaxLength {
+			p.addError(ImportRedirectError{
 				Line:    lineNum,
 				Source:  source,
 				Target:  target,
-				Reason:  ImportErrorDuplicateInFile,
-				Message: fmt.Sprintf("duplicate source in file, first occurrence at line %d", firstLine),
+				Reason:  ImportErrorInvalidFormat,
+				Message: fmt.Sprintf("comment cannot exceed %d characters", importCommentMaxLength),
 			})
-			continue
+			return nil
+		}
+		row.Comment = &comment
+	}
+
+	if extras.targets != nil || extras.targetList != nil {
+		targets := extras.targetList
+		if extras.targets != nil {
+			var errTargets error
+			if targets, errTargets = parseImportTargets(*extras.targets); errTargets != nil {
+				p.addError(ImportRedirectError{
+					Line:    lineNum,
+					Source:  source,
+					Target:  target,
+					Reason:  ImportErrorInvalidFormat,
+					Message: fmt.Sprintf("invalid %s: %v", importTargetsColumn, errTargets),
+				})
+				return nil
+			}
 		}
-		seenSources[source] = lineNum
+		row.Targets = targets
+		row.HasTargets = true
+	}
 
-		rows = append(rows, ParsedRedirectRow{
-			LineNum: lineNum,
-			Type:    redirectType,
+	// Check for duplicate sources within the file
+	if firstLine, exists := p.seenSources[source]; exists {
+		p.addError(ImportRedirectError{
+			Line:    lineNum,
 			Source:  source,
 			Target:  target,
-			Status:  redirectStatus,
+			Reason:  ImportErrorDuplicateInFile,
+			Message: fmt.Sprintf("duplicate source in file, first occurrence at line %d", firstLine),
 		})
+		return nil
 	}
+	p.seenSources[source] = lineNum
 
-	return rows, errors, nil
+	p.rows = append(p.rows, row)
+	p.total++
+	if len(p.rows) >= p.chunkSize {
+		return p.flush()
+	}
+	return nil
 }
 
-// Import imports the parsed rows into the database
-func (s *redirectImportService) Import(ctx context.Context, namespaceCode, projectCode string, rows []ParsedRedirectRow, opts ImportRedirectOptions) (*ImportRedirectResult, error) {
-	s.ctx.Logger.Info("redirect import started", "namespace", namespaceCode, "project", projectCode, "rows", len(rows), "overwrite", opts.Overwrite)
+// StartImportJob copies the file to a temporary file, creates an import job and queues it for the workers, the file
+// being parsed and imported in the background
+func (s *redirectImportService) StartImportJob(ctx context.Context, namespaceCode, projectCode string, reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions) (*model.ImportJob, error) {
+	file, err := spoolImportFile(reader)
+	if err != nil {
+		return nil, err
+	}
 
-	result := &ImportRedirectResult{
-		Success:    true,
-		TotalLines: len(rows),
-		Errors:     make([]ImportRedirectError, 0),
+	job := &model.ImportJob{
+		NamespaceCode: namespaceCode,
+		ProjectCode:   projectCode,
+		Status:        model.ImportJobStatusPending,
+		Overwrite:     opts.Overwrite,
+		SourcePrefix:  opts.SourcePrefix,
+		TargetPrefix:  opts.TargetPrefix,
+	}
+	if err = s.importJobRepo.Create(ctx, job); err != nil {
+		_ = os.Remove(file)
+		return nil, err
 	}
 
-	if len(rows) == 0 {
-		s.ctx.Logger.Info("redirect import completed: no rows to import", "namespace", namespaceCode, "project", projectCode)
-		return result, nil
+	select {
+	case s.queue <- importJobTask{job: job, file: file, format: format, opts: opts, subject: types.SubjectFromContext(ctx)}:
+	default:
+		_ = os.Remove(file)
+		s.finishImportJob(job, nil, ErrImportQueueFull)
+		return nil, ErrImportQueueFull
 	}
 
+	s.ctx.Logger.InfoContext(ctx, "redirect import job queued", "namespace", namespaceCode, "project", projectCode, "job", job.ID, "format", format, "sourcePrefix", opts.SourcePrefix, "targetPrefix", opts.TargetPrefix)
+	return job, nil
+}
+
+// spoolImportFile copies an uploaded file to a temporary file read by the import job, and returns its path
+func spoolImportFile(reader io.Reader) (string, error) {
+	file, err := os.CreateTemp("", "flecto-import-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create the import file: %w", err)
+	}
+	_, err = io.Copy(file, reader)
+	if errClose := file.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return "", fmt.Errorf("failed to copy the import file: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// GetImportJob returns an import job, with its live progress when it is running
+func (s *redirectImportService) GetImportJob(ctx context.Context, namespaceCode, projectCode string, id int64) (*model.ImportJob, error) {
+	job, err := s.importJobRepo.FindByIDWithProject(ctx, namespaceCode, projectCode, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.Status == model.ImportJobStatusRunning {
+		s.progressMu.RLock()
+		progress, ok := s.progress[job.ID]
+		s.progressMu.RUnlock()
+		if ok {
+			job.ProcessedCount += progress.processed
+			job.ImportedCount += progress.imported
+			job.SkippedCount += progress.skipped
+			job.ErrorCount += progress.errored
+		}
+	}
+
+	return job, nil
+}
+
+// StartWorkers starts the pool of workers processing import jobs until the application stops.
+// Jobs left unfinished by a previous run can not be resumed and are marked as failed.
+func (s *redirectImportService) StartWorkers() {
+	for _, ctx := range database.ShardContexts(s.importJobRepo.GetTx(context.Background()), context.Background()) {
+		count, err := s.importJobRepo.FailUnfinished(ctx, ErrImportJobInterrupted.Error())
+		if err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "failed to mark interrupted import jobs", "error", err)
+		} else if count > 0 {
+			s.ctx.Logger.WarnContext(ctx, "interrupted import jobs marked as failed", "count", count)
+		}
+	}
+
+	s.ctx.Workers.RegisterQueue("redirect_import", func() (int, int) { return len(s.queue), cap(s.queue) })
+	for i := 0; i < s.ctx.Config.Import.Workers; i++ {
+		heartbeat := s.ctx.Workers.Register(fmt.Sprintf("redirect_import_%d", i+1), importWorkerTimeout)
+		go func() {
+			ticker := time.NewTicker(importWorkerBeat)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-s.ctx.Done():
+					return
+				case <-ticker.C:
+					heartbeat.Beat()
+				case task := <-s.queue:
+					s.processImportJob(task)
+					heartbeat.Beat()
+				}
+			}
+		}()
+	}
+}
+
+// processImportJob parses and imports the file of a queued import job batch by batch, and stores its result.
+// The jobs still queued when the manager switches to read-only mode fail without importing anything.
+func (s *redirectImportService) processImportJob(task importJobTask) {
+	defer func() {
+		_ = os.Remove(task.file)
+	}()
+	job := task.job
+	if err := s.ctx.ReadOnlyError(); err != nil {
+		s.finishImportJob(job, nil, err)
+		return
+	}
+	ctx := database.WithNamespace(context.Background(), job.NamespaceCode)
+	job.Status = model.ImportJobStatusRunning
+	if err := s.importJobRepo.Update(ctx, job); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to start redirect import job", "job", job.ID, "error", err)
+		return
+	}
+
+	s.setImportJobProgress(job.ID, &ImportRedirectResult{})
+	defer func() {
+		s.progressMu.Lock()
+		delete(s.progress, job.ID)
+		s.progressMu.Unlock()
+	}()
+
+	file, err := os.Open(task.file)
+	if err != nil {
+		s.finishImportJob(job, nil, fmt.Errorf("failed to open the import file: %w", err))
+		return
+	}
+	defer file.Close()
+
+	result, err := s.runFile(types.WithSubject(ctx, task.subject), job.NamespaceCode, job.ProjectCode, file, task.format, task.opts, false, func(result *ImportRedirectResult) {
+		s.setImportJobProgress(job.ID, result)
+	})
+	s.finishImportJob(job, result, err)
+}
+
+func (s *redirectImportService) setImportJobProgress(id int64, result *ImportRedirectResult) {
+	s.progressMu.Lock()
+	defer s.progressMu.Unlock()
+	s.progress[id] = importJobProgress{
+		processed: result.ImportedCount + result.SkippedCount + result.ErrorCount,
+		imported:  result.ImportedCount,
+		skipped:   result.SkippedCount,
+		errored:   result.ErrorCount,
+	}
+}
+
+// finishImportJob stores the final state of a job, result is ignored when err is not nil
+func (s *redirectImportService) finishImportJob(job *model.ImportJob, result *ImportRedirectResult, err error) {
+	job.FinishedAt = types.Ptr(time.Now())
+	if err != nil {
+		job.Status = model.ImportJobStatusFailed
+		job.ErrorMessage = err.Error()
+	} else {
+		job.Status = model.ImportJobStatusCompleted
+		job.TotalLines = result.TotalLines
+		job.ProcessedCount += result.ImportedCount + result.SkippedCount + result.ErrorCount
+		job.ImportedCount = result.ImportedCount
+		job.SkippedCount = result.SkippedCount
+		job.RewrittenCount = result.RewrittenCount
+		job.ErrorCount += result.ErrorCount
+		job.Errors = append(job.Errors, toImportJobErrors(result.Errors)...)
+	}
+
+	if errUpdate := s.importJobRepo.Update(database.WithNamespace(context.Background(), job.NamespaceCode), job); errUpdate != nil {
+		s.ctx.Logger.Error("failed to save redirect import job", "job", job.ID, "error", errUpdate)
+	}
+}
+
+func toImportJobErrors(errs []ImportRedirectError) []model.ImportJobError {
+	jobErrors := make([]model.ImportJobError, 0, len(errs))
+	for _, e := range errs {
+		jobErrors = append(jobErrors, model.ImportJobError{
+			Line:    e.Line,
+			Source:  e.Source,
+			Target:  e.Target,
+			Reason:  string(e.Reason),
+			Message: e.Message,
+		})
+	}
+	return jobErrors
+}
+
+// importChunk imports a batch of rows and accumulates the outcome into result
+func (s *redirectImportService) importChunk(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, rows []ParsedRedirectRow, policy *model.CompiledNamespacePolicy, sourceIndex *importSourceIndex, opts ImportRedirectOptions, dryRun bool, result *ImportRedirectResult) error {
 	// Collect all sources for batch availability check
 	sources := make([]string, len(rows))
 	for i, row := range rows {
 		sources[i] = row.Source
+		if row.rewritten() {
+			result.RewrittenCount++
+		}
 	}
 
-	// Check source availability for all sources
-	unavailableSources, err := s.checkSourcesAvailability(ctx, namespaceCode, projectCode, sources)
+	// Check source availability for all sources of the batch at once
+	unavailableSources, err := checkSourcesAvailability(tx.WithContext(ctx), namespaceCode, projectCode, sources)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check source availability: %w", err)
+		return fmt.Errorf("failed to check source availability: %w", err)
 	}
 
-	// Filter rows based on availability and overwrite option
-	var rowsToImport []ParsedRedirectRow
 	for _, row := range rows {
-		if _, unavailable := unavailableSources[row.Source]; unavailable {
-			if !opts.Overwrite {
+		if _, unavailable := unavailableSources[row.Source]; unavailable && !opts.Overwrite {
+			result.Errors = append(result.Errors, ImportRedirectError{
+				Line:    row.LineNum,
+				Source:  row.Source,
+				Target:  row.Target,
+				Reason:  ImportErrorSourceAlreadyExists,
+				Message: "source already exists and overwrite is disabled",
+			})
+			result.ErrorCount++
+			continue
+		}
+		if _, unavailable := unavailableSources[row.Source]; !unavailable {
+			if conflict := sourceIndex.conflict(row); conflict != "" {
 				result.Errors = append(result.Errors, ImportRedirectError{
 					Line:    row.LineNum,
 					Source:  row.Source,
 					Target:  row.Target,
 					Reason:  ImportErrorSourceAlreadyExists,
-					Message: "source already exists and overwrite is disabled",
+					Message: fmt.Sprintf("%s matches the same requests with the redirect options of the project", conflict),
 				})
 				result.ErrorCount++
 				continue
 			}
-			// If overwrite is enabled, we'll handle it during import
 		}
-		rowsToImport = append(rowsToImport, row)
-	}
 
-	if len(rowsToImport) == 0 {
-		result.Success = result.ErrorCount == 0
-		return result, nil
+		imported, importErr := s.importRow(ctx, tx, namespaceCode, projectCode, row, policy, unavailableSources, dryRun)
+		if importErr != nil {
+			result.Errors = append(result.Errors, *importErr)
+			result.ErrorCount++
+		} else if imported {
+			sourceIndex.add(row)
+			result.ImportedCount++
+		} else {
+			result.SkippedCount++
+		}
 	}
+	return nil
+}
 
-	// Execute import in a single transaction
-	err = s.redirectDraftRepo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
-		for _, row := range rowsToImport {
-			imported, importErr := s.importRow(ctx, tx, namespaceCode, projectCode, row, unavailableSources)
-			if importErr != nil {
-				result.Errors = append(result.Errors, *importErr)
-				result.ErrorCount++
-			} else if imported {
-				result.ImportedCount++
-			} else {
-				result.SkippedCount++
-			}
-		}
-		return nil
-	})
+// importSourceIndex indexes the BASIC and BASIC_HOST sources without conditions of the redirects and drafts of a
+// project by their normalized form, to reject the rows matching the same requests as another source. A nil index is
+// used when the redirect options of the project do not normalize the sources.
+type importSourceIndex struct {
+	options commonTypes.RedirectOptions
+	sources map[string]string
+}
+
+// loadImportSourceIndex loads the index of the sources of the project read with db, nil when its redirect options do
+// not normalize the sources
+func loadImportSourceIndex(db *gorm.DB, namespaceCode, projectCode string) (*importSourceIndex, error) {
+	var project model.Project
+	if err := db.Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Limit(1).Find(&project).Error; err != nil {
+		return nil, err
+	}
+	if !project.RedirectOptions.NormalizesSources() {
+		return nil, nil
+	}
 
+	basicTypes := []commonTypes.RedirectType{commonTypes.RedirectTypeBasic, commonTypes.RedirectTypeBasicHost}
+	var sources []string
+	err := db.Raw(`
+		SELECT source FROM redirects
+		WHERE namespace_code = ?
+		AND project_code = ?
+		AND type IN ?
+		AND COALESCE(conditions, '') = ''
+		UNION ALL
+		SELECT new_source FROM redirect_drafts
+		WHERE namespace_code = ?
+		AND project_code = ?
+		AND new_type IN ?
+		AND COALESCE(new_conditions, '') = ''
+		AND change_type != 'DELETE'
+	`, namespaceCode, projectCode, basicTypes, namespaceCode, projectCode, basicTypes).Scan(&sources).Error
 	if err != nil {
-		s.ctx.Logger.Error("redirect import failed", "namespace", namespaceCode, "project", projectCode, "error", err)
 		return nil, err
 	}
 
-	result.Success = result.ErrorCount == 0
-	s.ctx.Logger.Info("redirect import completed", "namespace", namespaceCode, "project", projectCode, "imported", result.ImportedCount, "skipped", result.SkippedCount, "errors", result.ErrorCount)
-	return result, nil
+	index := &importSourceIndex{options: project.RedirectOptions, sources: make(map[string]string, len(sources))}
+	for _, source := range sources {
+		normalized := index.options.NormalizeSource(source)
+		if _, exists := index.sources[normalized]; !exists {
+			index.sources[normalized] = source
+		}
+	}
+	return index, nil
+}
+
+// conflict returns the other source matching the same requests as the source of the row, empty when there is none
+func (i *importSourceIndex) conflict(row ParsedRedirectRow) string {
+	if i == nil || !row.Type.IsBasic() {
+		return ""
+	}
+	if source, exists := i.sources[i.options.NormalizeSource(row.Source)]; exists && source != row.Source {
+		return source
+	}
+	return ""
 }
 
-// checkSourcesAvailability checks which sources already exist
-func (s *redirectImportService) checkSourcesAvailability(ctx context.Context, namespaceCode, projectCode string, sources []string) (map[string]bool, error) {
+// add indexes the source of an imported row
+func (i *importSourceIndex) add(row ParsedRedirectRow) {
+	if i == nil || !row.Type.IsBasic() {
+		return
+	}
+	normalized := i.options.NormalizeSource(row.Source)
+	if _, exists := i.sources[normalized]; !exists {
+		i.sources[normalized] = row.Source
+	}
+}
+
+// checkSourcesAvailability returns the sources without conditions already used by a redirect or a draft of the project.
+// It reads with the import transaction, to see the drafts created by the previous batches.
+func checkSourcesAvailability(tx *gorm.DB, namespaceCode, projectCode string, sources []string) (map[string]bool, error) {
 	unavailable := make(map[string]bool)
+	if len(sources) == 0 {
+		return unavailable, nil
+	}
 
-	for _, source := range sources {
-		available, err := s.redirectDraftRepo.CheckSourceAvailability(ctx, namespaceCode, projectCode, source, nil, nil)
-		if err != nil {
-			return nil, err
-		}
-		if !available {
-			unavailable[source] = true
-		}
+	var used []string
+	err := tx.Raw(`
+		SELECT source FROM redirects
+		WHERE namespace_code = ?
+		AND project_code = ?
+		AND source IN ?
+		AND COALESCE(conditions, '') = ''
+		UNION
+		SELECT new_source FROM redirect_drafts
+		WHERE namespace_code = ?
+		AND project_code = ?
+		AND new_source IN ?
+		AND COALESCE(new_conditions, '') = ''
+		AND change_type != 'DELETE'
+	`, namespaceCode, projectCode, sources, namespaceCode, projectCode, sources).Scan(&used).Error
+	if err != nil {
+		return nil, err
 	}
 
+	for _, source := range used {
+		unavailable[source] = true
+	}
 	return unavailable, nil
 }
 
-// importRow imports a single row, returns (imported, error)
-func (s *redirectImportService) importRow(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, row ParsedRedirectRow, unavailableSources map[string]bool) (bool, *ImportRedirectError) {
+// importRow imports a single row, returns (imported, error).
+// When dryRun is true, nothing is written and imported reports whether the row would be imported.
+func (s *redirectImportService) importRow(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, row ParsedRedirectRow, policy *model.CompiledNamespacePolicy, unavailableSources map[string]bool, dryRun bool) (bool, *ImportRedirectError) {
 	newRedirect := &commonTypes.Redirect{
-		Type:   row.Type,
-		Source: row.Source,
-		Target: row.Target,
-		Status: row.Status,
+		Type:       row.Type,
+		Source:     row.Source,
+		Target:     row.Target,
+		Status:     row.Status,
+		ValidFrom:  row.ValidFrom,
+		ValidUntil: row.ValidUntil,
+		Targets:    row.Targets,
+	}
+	if row.Priority != nil {
+		newRedirect.Priority = *row.Priority
 	}
 	errValidate := s.ctx.Validator.Struct(newRedirect)
 	if errValidate != nil {
@@ -363,55 +1339,86 @@ func (s *redirectImportService) importRow(ctx context.Context, tx *gorm.DB, name
 			Message: fmt.Sprintf("invalid data: %v", errValidate),
 		}
 	}
+	if errPolicy := namespacePolicyError(policy, newRedirect); errPolicy != nil {
+		return false, &ImportRedirectError{
+			Line:    row.LineNum,
+			Source:  row.Source,
+			Target:  row.Target,
+			Reason:  ImportErrorPolicyViolation,
+			Message: errPolicy.Error(),
+		}
+	}
 
 	// Check if source already exists (only reached when overwrite is enabled)
 	if _, exists := unavailableSources[row.Source]; exists {
-		return s.updateExistingDraft(ctx, tx, namespaceCode, projectCode, row, newRedirect)
+		return s.updateExistingDraft(ctx, tx, namespaceCode, projectCode, row, newRedirect, dryRun)
 	}
 
 	// Create new redirect and draft
-	return s.createNewDraft(tx, namespaceCode, projectCode, row, newRedirect)
+	return s.createNewDraft(ctx, tx, namespaceCode, projectCode, row, newRedirect, dryRun)
 }
 
 // updateExistingDraft updates an existing draft for a source
-func (s *redirectImportService) updateExistingDraft(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, row ParsedRedirectRow, newRedirect *commonTypes.Redirect) (bool, *ImportRedirectError) {
+func (s *redirectImportService) updateExistingDraft(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, row ParsedRedirectRow, newRedirect *commonTypes.Redirect, dryRun bool) (bool, *ImportRedirectError) {
 	// Find existing redirect with this source
 	var existingRedirect model.Redirect
 	err := tx.WithContext(ctx).
-		Preload("RedirectDraft").
-		Where("namespace_code = ? AND project_code = ? AND source = ?", namespaceCode, projectCode, row.Source).
+		Preload("RedirectDraft.Tags").
+		Preload("Tags").
+		Where("namespace_code = ? AND project_code = ? AND source = ? AND COALESCE(conditions, '') = ''", namespaceCode, projectCode, row.Source).
 		First(&existingRedirect).Error
 
 	if err == nil && existingRedirect.ID > 0 {
 		// Update or create draft for existing published redirect
 		if existingRedirect.RedirectDraft != nil {
+			keepExistingValues(row, newRedirect, existingRedirect.RedirectDraft.NewRedirect)
 			// Check if data is identical - skip if no changes
-			if redirectsAreEqual(existingRedirect.RedirectDraft.NewRedirect, newRedirect) {
+			if draftIsUnchanged(existingRedirect.RedirectDraft, row, newRedirect) {
 				return false, nil // Skip, no changes
 			}
-			existingRedirect.RedirectDraft.NewRedirect = newRedirect
-			if err = tx.Save(existingRedirect.RedirectDraft).Error; err != nil {
-				return false, &ImportRedirectError{
-					Line:    row.LineNum,
-					Source:  row.Source,
-					Target:  row.Target,
-					Reason:  ImportErrorDatabaseError,
-					Message: fmt.Sprintf("failed to update existing draft: %v", err),
-				}
+			if errImport := s.runPreDraftSaveHooks(ctx, namespaceCode, projectCode, row, existingRedirect.RedirectDraft.ChangeType, newRedirect); errImport != nil {
+				return false, errImport
 			}
-			return true, nil
+			if dryRun {
+				return true, nil
+			}
+			return s.saveExistingDraft(tx, row, existingRedirect.RedirectDraft, newRedirect)
 		}
 
 		// Check if the published redirect already has the same data
 		publishedRedirect := &commonTypes.Redirect{
-			Type:   existingRedirect.Type,
-			Source: existingRedirect.Source,
-			Target: existingRedirect.Target,
-			Status: existingRedirect.Status,
+			Type:       existingRedirect.Type,
+			Source:     existingRedirect.Source,
+			Target:     existingRedirect.Target,
+			Status:     existingRedirect.Status,
+			ValidFrom:  existingRedirect.ValidFrom,
+			ValidUntil: existingRedirect.ValidUntil,
+			Targets:    existingRedirect.Targets,
+			Headers:    existingRedirect.Headers,
 		}
-		if redirectsAreEqual(publishedRedirect, newRedirect) {
+		keepExistingValues(row, newRedirect, publishedRedirect)
+		if redirectsAreEqual(publishedRedirect, newRedirect) && validityIsUnchanged(publishedRedirect, newRedirect) && tagsAreUnchanged(existingRedirect.Tags, row.Tags) {
 			return false, nil // Skip, no changes from published version
 		}
+		if errImport := s.runPreDraftSaveHooks(ctx, namespaceCode, projectCode, row, model.DraftChangeTypeUpdate, newRedirect); errImport != nil {
+			return false, errImport
+		}
+		if dryRun {
+			return true, nil
+		}
+
+		draftTags := existingRedirect.Tags
+		if row.Tags != nil {
+			if draftTags, err = findOrCreateTags(tx, namespaceCode, projectCode, row.Tags); err != nil {
+				return false, &ImportRedirectError{
+					Line:    row.LineNum,
+					Source:  row.Source,
+					Target:  row.Target,
+					Reason:  ImportErrorDatabaseError,
+					Message: fmt.Sprintf("failed to create tags: %v", err),
+				}
+			}
+		}
 
 		// Create new draft for published redirect
 		draft := &model.RedirectDraft{
@@ -420,6 +1427,8 @@ func (s *redirectImportService) updateExistingDraft(ctx context.Context, tx *gor
 			OldRedirectID: types.Ptr(existingRedirect.ID),
 			ChangeType:    model.DraftChangeTypeUpdate,
 			NewRedirect:   newRedirect,
+			Tags:          draftTags,
+			Comment:       row.comment(),
 		}
 		if err = tx.Create(draft).Error; err != nil {
 			return false, &ImportRedirectError{
@@ -436,30 +1445,90 @@ func (s *redirectImportService) updateExistingDraft(ctx context.Context, tx *gor
 	// Check in redirect_drafts for unpublished redirects with matching new_source
 	var existingDraft model.RedirectDraft
 	err = tx.WithContext(ctx).
-		Where("namespace_code = ? AND project_code = ? AND new_source = ? AND change_type != ?",
+		Preload("Tags").
+		Where("namespace_code = ? AND project_code = ? AND new_source = ? AND COALESCE(new_conditions, '') = '' AND change_type != ?",
 			namespaceCode, projectCode, row.Source, model.DraftChangeTypeDelete).
 		First(&existingDraft).Error
 
 	if err == nil && existingDraft.ID > 0 {
+		keepExistingValues(row, newRedirect, existingDraft.NewRedirect)
 		// Check if data is identical - skip if no changes
-		if redirectsAreEqual(existingDraft.NewRedirect, newRedirect) {
+		if draftIsUnchanged(&existingDraft, row, newRedirect) {
 			return false, nil // Skip, no changes
 		}
-		existingDraft.NewRedirect = newRedirect
-		if err = tx.Save(&existingDraft).Error; err != nil {
+		if errImport := s.runPreDraftSaveHooks(ctx, namespaceCode, projectCode, row, existingDraft.ChangeType, newRedirect); errImport != nil {
+			return false, errImport
+		}
+		if dryRun {
+			return true, nil
+		}
+		return s.saveExistingDraft(tx, row, &existingDraft, newRedirect)
+	}
+
+	// If we get here, the source exists but we couldn't find it (shouldn't happen)
+	return s.createNewDraft(ctx, tx, namespaceCode, projectCode, row, newRedirect, dryRun)
+}
+
+// saveExistingDraft updates the new redirect of a draft, and its tags and comment when the row has them
+func (s *redirectImportService) saveExistingDraft(tx *gorm.DB, row ParsedRedirectRow, draft *model.RedirectDraft, newRedirect *commonTypes.Redirect) (bool, *ImportRedirectError) {
+	draft.NewRedirect = newRedirect
+	if row.Comment != nil {
+		draft.Comment = *row.Comment
+	}
+	if err := tx.Omit("Tags").Save(draft).Error; err != nil {
+		return false, &ImportRedirectError{
+			Line:    row.LineNum,
+			Source:  row.Source,
+			Target:  row.Target,
+			Reason:  ImportErrorDatabaseError,
+			Message: fmt.Sprintf("failed to update existing draft: %v", err),
+		}
+	}
+
+	if row.Tags != nil {
+		tags, err := findOrCreateTags(tx, draft.NamespaceCode, draft.ProjectCode, row.Tags)
+		if err == nil {
+			err = tx.Model(draft).Association("Tags").Replace(tags)
+		}
+		if err != nil {
 			return false, &ImportRedirectError{
 				Line:    row.LineNum,
 				Source:  row.Source,
 				Target:  row.Target,
 				Reason:  ImportErrorDatabaseError,
-				Message: fmt.Sprintf("failed to update existing draft: %v", err),
+				Message: fmt.Sprintf("failed to update draft tags: %v", err),
 			}
 		}
-		return true, nil
 	}
+	return true, nil
+}
 
-	// If we get here, the source exists but we couldn't find it (shouldn't happen)
-	return s.createNewDraft(tx, namespaceCode, projectCode, row, newRedirect)
+// draftIsUnchanged reports whether a row leaves an existing draft unchanged
+func draftIsUnchanged(draft *model.RedirectDraft, row ParsedRedirectRow, newRedirect *commonTypes.Redirect) bool {
+	return redirectsAreEqual(draft.NewRedirect, newRedirect) &&
+		validityIsUnchanged(draft.NewRedirect, newRedirect) &&
+		tagsAreUnchanged(draft.Tags, row.Tags) &&
+		(row.Comment == nil || *row.Comment == draft.Comment)
+}
+
+// tagsAreUnchanged reports whether the tag names of a row match the current tags, nil names leave the tags untouched
+func tagsAreUnchanged(tags []model.Tag, names []string) bool {
+	if names == nil {
+		return true
+	}
+	if len(tags) != len(names) {
+		return false
+	}
+	current := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		current[tag.Name] = true
+	}
+	for _, name := range names {
+		if !current[name] {
+			return false
+		}
+	}
+	return true
 }
 
 // redirectsAreEqual compares two redirects to check if they have identical data
@@ -470,11 +1539,63 @@ func redirectsAreEqual(a, b *commonTypes.Redirect) bool {
 	return a.Type == b.Type &&
 		a.Source == b.Source &&
 		a.Target == b.Target &&
-		a.Status == b.Status
+		a.Status == b.Status &&
+		a.Priority == b.Priority &&
+		slices.Equal(a.Targets, b.Targets) &&
+		maps.Equal(a.Headers, b.Headers)
+}
+
+// validityIsUnchanged compares the validity periods of two redirects
+func validityIsUnchanged(a, b *commonTypes.Redirect) bool {
+	return timesAreEqual(a.ValidFrom, b.ValidFrom) && timesAreEqual(a.ValidUntil, b.ValidUntil)
+}
+
+// keepExistingValues copies the priority, the bounds of the validity period and the weighted targets of the
+// existing redirect missing from the import file, and its headers. The weighted targets are dropped when the
+// target changes.
+func keepExistingValues(row ParsedRedirectRow, newRedirect, existing *commonTypes.Redirect) {
+	if existing == nil {
+		return
+	}
+	newRedirect.Headers = existing.Headers
+	if !row.HasTargets && existing.Target == newRedirect.Target {
+		newRedirect.Targets = existing.Targets
+	}
+	if row.Priority == nil {
+		newRedirect.Priority = existing.Priority
+	}
+	if !row.HasValidFrom {
+		newRedirect.ValidFrom = existing.ValidFrom
+	}
+	if !row.HasValidUntil {
+		newRedirect.ValidUntil = existing.ValidUntil
+	}
+}
+
+// runPreDraftSaveHooks runs the pre-draft-save hooks of the extensions on the new redirect of a row, a refusal being
+// reported as an error of the row
+func (s *redirectImportService) runPreDraftSaveHooks(ctx context.Context, namespaceCode, projectCode string, row ParsedRedirectRow, changeType model.DraftChangeType, newRedirect *commonTypes.Redirect) *ImportRedirectError {
+	if err := runPreDraftSaveHooks(ctx, s.ctx, namespaceCode, projectCode, changeType, newRedirect, nil); err != nil {
+		return &ImportRedirectError{
+			Line:    row.LineNum,
+			Source:  row.Source,
+			Target:  row.Target,
+			Reason:  ImportErrorDraftRefused,
+			Message: err.Error(),
+		}
+	}
+	return nil
 }
 
 // createNewDraft creates a new redirect and draft
-func (s *redirectImportService) createNewDraft(tx *gorm.DB, namespaceCode, projectCode string, row ParsedRedirectRow, newRedirect *commonTypes.Redirect) (bool, *ImportRedirectError) {
+func (s *redirectImportService) createNewDraft(ctx context.Context, tx *gorm.DB, namespaceCode, projectCode string, row ParsedRedirectRow, newRedirect *commonTypes.Redirect, dryRun bool) (bool, *ImportRedirectError) {
+	if errImport := s.runPreDraftSaveHooks(ctx, namespaceCode, projectCode, row, model.DraftChangeTypeCreate, newRedirect); errImport != nil {
+		return false, errImport
+	}
+	if dryRun {
+		return true, nil
+	}
+
 	// Create new unpublished redirect
 	redirect := &model.Redirect{
 		NamespaceCode: namespaceCode,
@@ -491,6 +1612,17 @@ func (s *redirectImportService) createNewDraft(tx *gorm.DB, namespaceCode, proje
 		}
 	}
 
+	tags, err := findOrCreateTags(tx, namespaceCode, projectCode, row.Tags)
+	if err != nil {
+		return false, &ImportRedirectError{
+			Line:    row.LineNum,
+			Source:  row.Source,
+			Target:  row.Target,
+			Reason:  ImportErrorDatabaseError,
+			Message: fmt.Sprintf("failed to create tags: %v", err),
+		}
+	}
+
 	// Create redirect draft
 	draft := &model.RedirectDraft{
 		NamespaceCode: namespaceCode,
@@ -498,8 +1630,10 @@ func (s *redirectImportService) createNewDraft(tx *gorm.DB, namespaceCode, proje
 		OldRedirectID: types.Ptr(redirect.ID),
 		ChangeType:    model.DraftChangeTypeCreate,
 		NewRedirect:   newRedirect,
+		Tags:          tags,
+		Comment:       row.comment(),
 	}
-	if err := tx.Create(draft).Error; err != nil {
+	if err = tx.Create(draft).Error; err != nil {
 		return false, &ImportRedirectError{
 			Line:    row.LineNum,
 			Source:  row.Source,
@@ -1,18 +1,29 @@
 package service
 
 import (
+	"archive/zip"
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/extension"
 	mockFlectoRepository "github.com/flectolab/flecto-manager/mocks/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -23,13 +34,27 @@ func setupRedirectImportServiceTest(t *testing.T) (*gomock.Controller, *mockFlec
 	mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
-	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{})
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{})
 	assert.NoError(t, err)
 	mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
-	svc := NewRedirectImportService(appContext.TestContext(nil), mockRepo)
+	svc := NewRedirectImportService(appContext.TestContext(nil), mockRepo, mockFlectoRepository.NewMockImportJobRepository(ctrl))
 	return ctrl, mockRepo, db, svc
 }
 
+// failSourceAvailability makes the availability check of the imported sources fail when it reads source
+func failSourceAvailability(t *testing.T, db *gorm.DB, source string) {
+	require.NoError(t, db.Callback().Row().Before("gorm:row").Register("fail_source_availability", func(d *gorm.DB) {
+		if !strings.Contains(d.Statement.SQL.String(), "new_source IN") {
+			return
+		}
+		for _, v := range d.Statement.Vars {
+			if v == source {
+				_ = d.AddError(errors.New("db error"))
+			}
+		}
+	}))
+}
+
 func TestNewRedirectImportService(t *testing.T) {
 	ctrl, mockRepo, _, svc := setupRedirectImportServiceTest(t)
 	defer ctrl.Finish()
@@ -44,6 +69,7 @@ func TestRedirectImportService_ValidateFile(t *testing.T) {
 		filename    string
 		contentType string
 		size        int64
+		wantFormat  ImportFileFormat
 		wantErr     bool
 		errContains string
 	}{
@@ -52,6 +78,7 @@ func TestRedirectImportService_ValidateFile(t *testing.T) {
 			filename:    "redirects.csv",
 			contentType: "text/csv",
 			size:        1024,
+			wantFormat:  ImportFileFormatTSV,
 			wantErr:     false,
 		},
 		{
@@ -59,6 +86,7 @@ func TestRedirectImportService_ValidateFile(t *testing.T) {
 			filename:    "redirects.tsv",
 			contentType: "text/tab-separated-values",
 			size:        1024,
+			wantFormat:  ImportFileFormatTSV,
 			wantErr:     false,
 		},
 		{
@@ -66,6 +94,7 @@ func TestRedirectImportService_ValidateFile(t *testing.T) {
 			filename:    "redirects.csv",
 			contentType: "text/plain",
 			size:        1024,
+			wantFormat:  ImportFileFormatTSV,
 			wantErr:     false,
 		},
 		{
@@ -73,6 +102,7 @@ func TestRedirectImportService_ValidateFile(t *testing.T) {
 			filename:    "redirects.tsv",
 			contentType: "application/octet-stream",
 			size:        1024,
+			wantFormat:  ImportFileFormatTSV,
 			wantErr:     false,
 		},
 		{
@@ -92,12 +122,28 @@ func TestRedirectImportService_ValidateFile(t *testing.T) {
 			errContains: "invalid file type",
 		},
 		{
-			name:        "invalid extension xlsx",
+			name:        "valid xlsx file",
 			filename:    "redirects.xlsx",
 			contentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
 			size:        1024,
+			wantFormat:  ImportFileFormatXLSX,
+			wantErr:     false,
+		},
+		{
+			name:        "valid json file",
+			filename:    "redirects.json",
+			contentType: "application/json",
+			size:        1024,
+			wantFormat:  ImportFileFormatJSON,
+			wantErr:     false,
+		},
+		{
+			name:        "invalid content type for xlsx",
+			filename:    "redirects.xlsx",
+			contentType: "text/csv",
+			size:        1024,
 			wantErr:     true,
-			errContains: "invalid file type",
+			errContains: "invalid content type",
 		},
 		{
 			name:        "invalid content type",
@@ -112,6 +158,7 @@ func TestRedirectImportService_ValidateFile(t *testing.T) {
 			filename:    "redirects.CSV",
 			contentType: "text/csv",
 			size:        1024,
+			wantFormat:  ImportFileFormatTSV,
 			wantErr:     false,
 		},
 	}
@@ -121,7 +168,7 @@ func TestRedirectImportService_ValidateFile(t *testing.T) {
 			ctrl, _, _, svc := setupRedirectImportServiceTest(t)
 			defer ctrl.Finish()
 
-			err := svc.ValidateFile(tt.filename, tt.contentType, tt.size)
+			format, err := svc.ValidateFile(tt.filename, tt.contentType, tt.size)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -130,11 +177,56 @@ func TestRedirectImportService_ValidateFile(t *testing.T) {
 				}
 			} else {
 				assert.NoError(t, err)
+				assert.Equal(t, tt.wantFormat, format)
 			}
 		})
 	}
 }
 
+// parseFile parses the file with ParseFile and returns all its rows
+func parseFile(svc RedirectImportService, reader io.Reader, format ImportFileFormat, opts ImportRedirectOptions) ([]ParsedRedirectRow, []ImportRedirectError, error) {
+	var rows []ParsedRedirectRow
+	_, errs, err := svc.ParseFile(reader, format, opts, func(chunk []ParsedRedirectRow) error {
+		rows = append(rows, chunk...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rows, errs, nil
+}
+
+// jsonImportFile writes rows as a JSON import file, the line numbers of the rows being their positions in the file
+func jsonImportFile(t *testing.T, rows []ParsedRedirectRow) io.Reader {
+	entries := make([]importJSONEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := importJSONEntry{
+			Type:    string(row.Type),
+			Source:  row.Source,
+			Target:  row.Target,
+			Status:  json.RawMessage(strconv.Quote(string(row.Status))),
+			Tags:    row.Tags,
+			Comment: row.Comment,
+		}
+		if row.HasValidFrom {
+			entry.ValidFrom = types.Ptr(row.ValidFrom.Format(time.RFC3339))
+		}
+		if row.HasValidUntil {
+			entry.ValidUntil = types.Ptr(row.ValidUntil.Format(time.RFC3339))
+		}
+		if row.Priority != nil {
+			entry.Priority = json.RawMessage(strconv.Itoa(*row.Priority))
+		}
+		if row.HasTargets {
+			entry.Targets = row.Targets
+		}
+		entries = append(entries, entry)
+	}
+	data, err := json.Marshal(entries)
+	require.NoError(t, err)
+	return bytes.NewReader(data)
+}
+
 func TestRedirectImportService_ParseFile(t *testing.T) {
 	t.Run("success with valid data", func(t *testing.T) {
 		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
@@ -143,7 +235,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		input := "type\tsource\ttarget\tstatus\nBASIC\t/old\t/new\t301\nREGEX\t/pattern/(.*)\t/target/$1\tMOVED_PERMANENT"
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 2)
@@ -159,6 +251,48 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		assert.Equal(t, commonTypes.RedirectTypeRegex, rows[1].Type)
 	})
 
+	t.Run("rows handed in batches", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+		svc.(*redirectImportService).ctx.Config.Import.BatchSize = 2
+
+		input := "type\tsource\ttarget\tstatus\n" +
+			"BASIC\t/old1\t/new1\t301\n" +
+			"BASIC\t/old2\t/new2\t301\n" +
+			"INVALID\t/old3\t/new3\t301\n" +
+			"BASIC\t/old4\t/new4\t301\n"
+
+		var batches [][]int
+		total, errs, err := svc.ParseFile(strings.NewReader(input), ImportFileFormatTSV, ImportRedirectOptions{}, func(rows []ParsedRedirectRow) error {
+			lines := make([]int, 0, len(rows))
+			for _, row := range rows {
+				lines = append(lines, row.LineNum)
+			}
+			batches = append(batches, lines)
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, total)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, [][]int{{2, 3}, {5}}, batches)
+	})
+
+	t.Run("error of a batch stops the parsing", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+		svc.(*redirectImportService).ctx.Config.Import.BatchSize = 1
+
+		calls := 0
+		_, _, err := svc.ParseFile(strings.NewReader("type\tsource\ttarget\tstatus\nBASIC\t/old1\t/new1\t301\nBASIC\t/old2\t/new2\t301\n"), ImportFileFormatTSV, ImportRedirectOptions{}, func(rows []ParsedRedirectRow) error {
+			calls++
+			return errors.New("db error")
+		})
+
+		assert.ErrorContains(t, err, "db error")
+		assert.Equal(t, 1, calls)
+	})
+
 	t.Run("error invalid header column count", func(t *testing.T) {
 		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
@@ -166,7 +300,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		input := "type\tsource\ttarget\n"
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "expected 4 columns")
@@ -181,7 +315,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		input := "type\tsrc\ttarget\tstatus\n"
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "column 2 should be 'source'")
@@ -196,7 +330,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		input := ""
 		reader := strings.NewReader(input)
 
-		_, _, err := svc.ParseFile(reader)
+		_, _, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to read header")
@@ -209,7 +343,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		input := "type\tsource\ttarget\tstatus\nINVALID_TYPE\t/old\t/new\t301"
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 0)
@@ -224,7 +358,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		input := "type\tsource\ttarget\tstatus\nBASIC\t/old\t/new\tINVALID_STATUS"
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 0)
@@ -241,7 +375,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 			"BASIC\t/same\t/target2\t301"
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 1)
@@ -257,7 +391,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		input := "type\tsource\ttarget\tstatus\nBASIC\t/old\t/new"
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 0)
@@ -277,7 +411,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 			"REGEX_HOST\t/g\t/h\t301"
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 4)
@@ -304,7 +438,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 			"BASIC\t/o\t/p\tPERMANENT_REDIRECT"
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 8)
@@ -323,7 +457,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		input := "type\tsource\ttarget\tstatus\n  BASIC  \t  /old  \t  /new  \t  301  "
 		reader := strings.NewReader(input)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 1)
@@ -342,7 +476,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		data := []byte("type\tsource\ttarget\tstatus\nBASIC\t\t/new\t301\n")
 		reader := bytes.NewReader(data)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 0)
@@ -360,7 +494,7 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 		data := []byte("type\tsource\ttarget\tstatus\nBASIC\t/old\t\t301\n")
 		reader := bytes.NewReader(data)
 
-		rows, errs, err := svc.ParseFile(reader)
+		rows, errs, err := parseFile(svc, reader, ImportFileFormatTSV, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.Len(t, rows, 0)
@@ -369,21 +503,179 @@ func TestRedirectImportService_ParseFile(t *testing.T) {
 	})
 }
 
+func TestRedirectImportService_ParseFile_JSON(t *testing.T) {
+	t.Run("valid json", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		data := `[
+			{"type": "BASIC", "source": "/old", "target": "/new", "status": "MOVED_PERMANENT"},
+			{"type": "REGEX", "source": "^/blog/(.*)$", "target": "/news/$1", "status": 302}
+		]`
+
+		rows, errs, err := parseFile(svc, strings.NewReader(data), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, errs, 0)
+		assert.Equal(t, []ParsedRedirectRow{
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 2, Type: commonTypes.RedirectTypeRegex, Source: "^/blog/(.*)$", Target: "/news/$1", Status: commonTypes.RedirectStatusFound},
+		}, rows)
+	})
+
+	t.Run("invalid entries are reported", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		data := `[
+			{"type": "BASIC", "source": "/old", "target": "/new", "status": 301},
+			{"type": 1, "source": "/a", "target": "/b", "status": 301},
+			{"type": "BASIC", "source": "/old", "target": "/other", "status": 301},
+			{"type": "BASIC", "source": "/c", "target": "/d"}
+		]`
+
+		rows, errs, err := parseFile(svc, strings.NewReader(data), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Len(t, errs, 3)
+		assert.Equal(t, ImportErrorInvalidFormat, errs[0].Reason)
+		assert.Equal(t, 2, errs[0].Line)
+		assert.Equal(t, ImportErrorDuplicateInFile, errs[1].Reason)
+		assert.Equal(t, ImportErrorInvalidStatus, errs[2].Reason)
+	})
+
+	t.Run("not an array", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		_, _, err := parseFile(svc, strings.NewReader(`{"type": "BASIC"}`), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "expected an array")
+	})
+
+	t.Run("malformed json", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		_, _, err := parseFile(svc, strings.NewReader(`[{"type": "BASIC",`), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestRedirectImportService_ParseFile_XLSX(t *testing.T) {
+	buildXLSX := func(t *testing.T, sheetRows string) *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		w := zip.NewWriter(buf)
+		files := map[string]string{
+			"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+			"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+			"xl/worksheets/sheet1.xml":   `<worksheet><sheetData>` + sheetRows + `</sheetData></worksheet>`,
+		}
+		for name, content := range files {
+			f, err := w.Create(name)
+			assert.NoError(t, err)
+			_, err = f.Write([]byte(content))
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, w.Close())
+		return buf
+	}
+	header := `<row r="1"><c r="A1" t="inlineStr"><is><t>type</t></is></c><c r="B1" t="inlineStr"><is><t>source</t></is></c><c r="C1" t="inlineStr"><is><t>target</t></is></c><c r="D1" t="inlineStr"><is><t>status</t></is></c></row>`
+
+	t.Run("valid sheet", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		data := header +
+			`<row r="2"><c r="A2" t="inlineStr"><is><t>BASIC</t></is></c><c r="B2" t="inlineStr"><is><t>/old</t></is></c><c r="C2" t="inlineStr"><is><t>/new</t></is></c><c r="D2"><v>301</v></c></row>` +
+			`<row r="3"></row>` +
+			`<row r="4"><c r="A4" t="inlineStr"><is><t>BASIC</t></is></c><c r="B4" t="inlineStr"><is><t>/foo</t></is></c><c r="C4" t="inlineStr"><is><t>/bar</t></is></c></row>`
+
+		rows, errs, err := parseFile(svc, buildXLSX(t, data), ImportFileFormatXLSX, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []ParsedRedirectRow{
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent},
+		}, rows)
+		assert.Len(t, errs, 1)
+		assert.Equal(t, 4, errs[0].Line)
+		assert.Equal(t, ImportErrorInvalidStatus, errs[0].Reason)
+	})
+
+	t.Run("rows left out of the sheet keep the line numbers", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		data := header +
+			`<row r="5"><c r="A5" t="inlineStr"><is><t>BASIC</t></is></c><c r="B5" t="inlineStr"><is><t>/old</t></is></c><c r="C5" t="inlineStr"><is><t>/new</t></is></c><c r="D5"><v>301</v></c></row>`
+
+		rows, _, err := parseFile(svc, buildXLSX(t, data), ImportFileFormatXLSX, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		require.Len(t, rows, 1)
+		assert.Equal(t, 5, rows[0].LineNum)
+	})
+
+	t.Run("uncompressed sheet too large", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+		svc.(*redirectImportService).ctx.Config.Import.MaxFileSize = 1024
+
+		data := header + `<row r="2"><c r="A2"><v>` + strings.Repeat("0", 4096) + `</v></c></row>`
+
+		_, _, err := parseFile(svc, buildXLSX(t, data), ImportFileFormatXLSX, ImportRedirectOptions{})
+
+		require.Error(t, err)
+		assert.Equal(t, flectoErrors.CodeSizeLimitExceeded, flectoErrors.From(err).Code)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		data := `<row r="1"><c r="A1" t="inlineStr"><is><t>source</t></is></c></row>`
+
+		_, _, err := parseFile(svc, buildXLSX(t, data), ImportFileFormatXLSX, ImportRedirectOptions{})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid header")
+	})
+
+	t.Run("empty sheet", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		_, _, err := parseFile(svc, buildXLSX(t, ""), ImportFileFormatXLSX, ImportRedirectOptions{})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "sheet is empty")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		_, _, err := parseFile(svc, strings.NewReader(""), ImportFileFormat("XML"), ImportRedirectOptions{})
+
+		assert.Error(t, err)
+	})
+}
+
 func TestRedirectImportService_Import(t *testing.T) {
 	t.Run("success create new redirects", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/old1", Target: "/new1", Status: commonTypes.RedirectStatusMovedPermanent},
-			{LineNum: 3, Type: commonTypes.RedirectTypeBasic, Source: "/old2", Target: "/new2", Status: commonTypes.RedirectStatusFound},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/old1", Target: "/new1", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/old2", Target: "/new2", Status: commonTypes.RedirectStatusFound},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/old1", nil, nil).Return(true, nil)
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/old2", nil, nil).Return(true, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: false})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: false})
 
 		assert.NoError(t, err)
 		assert.True(t, result.Success)
@@ -407,7 +699,7 @@ func TestRedirectImportService_Import(t *testing.T) {
 		defer ctrl.Finish()
 
 		ctx := context.Background()
-		result, err := svc.Import(ctx, "ns", "proj", []ParsedRedirectRow{}, ImportRedirectOptions{})
+		result, err := svc.ImportFile(ctx, "ns", "proj", strings.NewReader("[]"), ImportFileFormatJSON, ImportRedirectOptions{})
 
 		assert.NoError(t, err)
 		assert.True(t, result.Success)
@@ -416,17 +708,15 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("invalid data", func(t *testing.T) {
-		ctrl, mockRepo, _, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasicHost, Source: "/old1", Target: "/new1", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasicHost, Source: "/old1", Target: "/new1", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/old1", nil, nil).Return(true, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: false})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: false})
 
 		assert.NoError(t, err)
 		assert.False(t, result.Success)
@@ -437,18 +727,143 @@ func TestRedirectImportService_Import(t *testing.T) {
 
 	})
 
-	t.Run("error source already exists without overwrite", func(t *testing.T) {
-		ctrl, mockRepo, _, svc := setupRedirectImportServiceTest(t)
+	t.Run("namespace policy violation", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		assert.NoError(t, db.Create(&model.NamespacePolicy{NamespaceCode: "ns", Rules: []model.NamespacePolicyRule{
+			{Type: model.NamespacePolicyRuleBannedSource, Pattern: "^/admin"},
+		}}).Error)
+		rows := []ParsedRedirectRow{
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/admin/old", Target: "/new1", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/old2", Target: "/new2", Status: commonTypes.RedirectStatusMovedPermanent},
+		}
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.Equal(t, []ImportRedirectError{{
+			Line:    1,
+			Source:  "/admin/old",
+			Target:  "/new1",
+			Reason:  ImportErrorPolicyViolation,
+			Message: "namespace policy violation: sources must not match ^/admin (/admin/old)",
+		}}, result.Errors)
+	})
+
+	t.Run("row refused by a pre-draft-save hook", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		svc.(*redirectImportService).ctx.Extensions.RegisterPreDraftSave("no-admin", func(ctx context.Context, draft *extension.DraftSave) error {
+			if strings.HasPrefix(draft.Redirect.Source, "/admin") {
+				return errors.New("admin redirects are reviewed by the security team")
+			}
+			return nil
+		})
+		rows := []ParsedRedirectRow{
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/admin/old", Target: "/new1", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/old2", Target: "/new2", Status: commonTypes.RedirectStatusMovedPermanent},
+		}
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.Equal(t, []ImportRedirectError{{
+			Line:    1,
+			Source:  "/admin/old",
+			Target:  "/new1",
+			Reason:  ImportErrorDraftRefused,
+			Message: "draft refused for project ns/proj: refused by extension hook no-admin: admin redirects are reviewed by the security team",
+		}}, result.Errors)
+
+		var drafts []model.RedirectDraft
+		assert.NoError(t, db.Find(&drafts).Error)
+		require.Len(t, drafts, 1)
+		assert.Equal(t, "/old2", drafts[0].NewRedirect.Source)
+		var redirects int64
+		assert.NoError(t, db.Model(&model.Redirect{}).Count(&redirects).Error)
+		assert.Equal(t, int64(1), redirects)
+	})
+
+	t.Run("rolled back in read-only mode", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		svc.(*redirectImportService).ctx.SetReadOnly(config.ReadOnlyConfig{Enabled: true})
+		rows := []ParsedRedirectRow{
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/old1", Target: "/new1", Status: commonTypes.RedirectStatusMovedPermanent},
+		}
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		require.Error(t, err)
+		assert.Equal(t, flectoErrors.CodeReadOnly, flectoErrors.As(err).Code)
+		assert.Nil(t, result)
+		var count int64
+		db.Model(&model.RedirectDraft{}).Count(&count)
+		assert.Zero(t, count)
+
+		// A preview writes nothing and still runs
+		result, err = svc.PreviewFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ImportedCount)
+	})
+
+	t.Run("normalized source conflict", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
+		assert.NoError(t, db.Create(&model.Project{NamespaceCode: "ns", ProjectCode: "proj", Name: "Project",
+			RedirectOptions: commonTypes.RedirectOptions{NormalizeURL: true}}).Error)
+		assert.NoError(t, db.Create(&model.Redirect{NamespaceCode: "ns", ProjectCode: "proj",
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/café", Target: "/cafe", Status: commonTypes.RedirectStatusFound}}).Error)
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/caf%C3%A9", Target: "/new1", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/shop", Target: "/new2", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 3, Type: commonTypes.RedirectTypeBasic, Source: "//shop", Target: "/new3", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/existing", nil, nil).Return(false, nil)
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.Equal(t, []ImportRedirectError{{
+			Line:    1,
+			Source:  "/caf%C3%A9",
+			Target:  "/new1",
+			Reason:  ImportErrorSourceAlreadyExists,
+			Message: "/café matches the same requests with the redirect options of the project",
+		}, {
+			Line:    3,
+			Source:  "//shop",
+			Target:  "/new3",
+			Reason:  ImportErrorSourceAlreadyExists,
+			Message: "/shop matches the same requests with the redirect options of the project",
+		}}, result.Errors)
+	})
+
+	t.Run("error source already exists without overwrite", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		assert.NoError(t, db.Create(&model.Redirect{NamespaceCode: "ns", ProjectCode: "proj",
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/old", Status: commonTypes.RedirectStatusFound}}).Error)
+		rows := []ParsedRedirectRow{
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent},
+		}
 
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: false})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: false})
 
 		assert.NoError(t, err)
 		assert.False(t, result.Success)
@@ -457,7 +872,7 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("success overwrite existing draft", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -491,12 +906,10 @@ func TestRedirectImportService_Import(t *testing.T) {
 		db.Create(draft)
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/imported-target", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/imported-target", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/existing", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
 
 		assert.NoError(t, err)
 		assert.True(t, result.Success)
@@ -509,7 +922,7 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("skip when data is identical", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -543,12 +956,10 @@ func TestRedirectImportService_Import(t *testing.T) {
 		db.Create(draft)
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/existing", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
 
 		assert.NoError(t, err)
 		assert.True(t, result.Success)
@@ -557,7 +968,7 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("skip when published data is identical", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -577,12 +988,10 @@ func TestRedirectImportService_Import(t *testing.T) {
 		db.Create(redirect)
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/existing", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
 
 		assert.NoError(t, err)
 		assert.True(t, result.Success)
@@ -591,7 +1000,7 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("create draft for published redirect with different data", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -611,12 +1020,10 @@ func TestRedirectImportService_Import(t *testing.T) {
 		db.Create(redirect)
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new-target", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new-target", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/existing", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
 
 		assert.NoError(t, err)
 		assert.True(t, result.Success)
@@ -631,7 +1038,7 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("update existing unpublished draft", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -659,12 +1066,10 @@ func TestRedirectImportService_Import(t *testing.T) {
 		db.Create(draft)
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/new-source", Target: "/updated-target", Status: commonTypes.RedirectStatusFound},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/new-source", Target: "/updated-target", Status: commonTypes.RedirectStatusFound},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/new-source", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
 
 		assert.NoError(t, err)
 		assert.True(t, result.Success)
@@ -678,17 +1083,17 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("error checking source availability", func(t *testing.T) {
-		ctrl, mockRepo, _, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/source", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/source", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/source", nil, nil).Return(false, errors.New("database error"))
+		failSourceAvailability(t, db, "/source")
 
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{})
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to check source availability")
@@ -696,17 +1101,17 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("all rows filtered out by errors", func(t *testing.T) {
-		ctrl, mockRepo, _, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
+		assert.NoError(t, db.Create(&model.Redirect{NamespaceCode: "ns", ProjectCode: "proj",
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/old", Status: commonTypes.RedirectStatusFound}}).Error)
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/existing", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: false})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: false})
 
 		assert.NoError(t, err)
 		assert.False(t, result.Success)
@@ -715,7 +1120,7 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("error saving existing draft update", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -747,15 +1152,17 @@ func TestRedirectImportService_Import(t *testing.T) {
 		}
 		db.Create(draft)
 
-		db.Migrator().DropTable(&model.RedirectDraft{})
+		db.Callback().Update().Before("gorm:update").Register("fail_draft_update", func(d *gorm.DB) {
+			if d.Statement.Table == "redirect_drafts" {
+				_ = d.AddError(errors.New("db error"))
+			}
+		})
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new-target", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new-target", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/existing", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, result.ErrorCount)
@@ -763,20 +1170,22 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("error creating redirect in createNewDraft", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
 
-		db.Migrator().DropTable(&model.Redirect{})
+		db.Callback().Create().Before("gorm:create").Register("fail_redirect_create", func(d *gorm.DB) {
+			if d.Statement.Table == "redirects" {
+				_ = d.AddError(errors.New("db error"))
+			}
+		})
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/new", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/new", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/new", nil, nil).Return(true, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: false})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: false})
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, result.ErrorCount)
@@ -785,20 +1194,22 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("error creating draft in createNewDraft", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
 
-		db.Migrator().DropTable(&model.RedirectDraft{})
+		db.Callback().Create().Before("gorm:create").Register("fail_draft_create", func(d *gorm.DB) {
+			if d.Statement.Table == "redirect_drafts" {
+				_ = d.AddError(errors.New("db error"))
+			}
+		})
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/new", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/new", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/new", nil, nil).Return(true, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: false})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: false})
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, result.ErrorCount)
@@ -807,7 +1218,7 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("error creating draft for published redirect", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -825,24 +1236,26 @@ func TestRedirectImportService_Import(t *testing.T) {
 		}
 		db.Create(redirect)
 
-		db.Migrator().DropTable(&model.RedirectDraft{})
+		db.Callback().Create().Before("gorm:create").Register("fail_draft_create", func(d *gorm.DB) {
+			if d.Statement.Table == "redirect_drafts" {
+				_ = d.AddError(errors.New("db error"))
+			}
+		})
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new-target", Status: commonTypes.RedirectStatusFound},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new-target", Status: commonTypes.RedirectStatusFound},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/existing", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, result.ErrorCount)
 		assert.Equal(t, ImportErrorDatabaseError, result.Errors[0].Reason)
-		assert.Contains(t, result.Errors[0].Message, "no such table: redirect_drafts")
+		assert.Contains(t, result.Errors[0].Message, "failed to create draft for existing redirect")
 	})
 
 	t.Run("error updating unpublished draft", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -868,15 +1281,17 @@ func TestRedirectImportService_Import(t *testing.T) {
 		}
 		db.Create(draft)
 
-		db.Migrator().DropTable(&model.RedirectDraft{})
+		db.Callback().Update().Before("gorm:update").Register("fail_draft_update", func(d *gorm.DB) {
+			if d.Statement.Table == "redirect_drafts" {
+				_ = d.AddError(errors.New("db error"))
+			}
+		})
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/new-source", Target: "/updated-target", Status: commonTypes.RedirectStatusFound},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/new-source", Target: "/updated-target", Status: commonTypes.RedirectStatusFound},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/new-source", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
 
 		assert.NoError(t, err)
 		assert.Equal(t, 1, result.ErrorCount)
@@ -884,7 +1299,7 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("skip when unpublished draft data is identical", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -911,12 +1326,10 @@ func TestRedirectImportService_Import(t *testing.T) {
 		db.Create(draft)
 
 		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/new-source", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/new-source", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
 		}
 
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/new-source", nil, nil).Return(false, nil)
-
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
 
 		assert.NoError(t, err)
 		assert.True(t, result.Success)
@@ -925,65 +1338,617 @@ func TestRedirectImportService_Import(t *testing.T) {
 	})
 
 	t.Run("fallthrough to createNewDraft when source unavailable but not found", func(t *testing.T) {
-		ctrl, mockRepo, db, svc := setupRedirectImportServiceTest(t)
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
+		row := ParsedRedirectRow{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/ghost", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent}
 
-		rows := []ParsedRedirectRow{
-			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/ghost", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
-		}
-
-		mockRepo.EXPECT().CheckSourceAvailability(ctx, "ns", "proj", "/ghost", nil, nil).Return(false, nil)
+		policy, err := loadNamespacePolicy(db, "ns")
+		require.NoError(t, err)
 
-		result, err := svc.Import(ctx, "ns", "proj", rows, ImportRedirectOptions{Overwrite: true})
+		imported, importErr := svc.(*redirectImportService).importRow(ctx, db, "ns", "proj", row, policy, map[string]bool{"/ghost": true}, false)
 
-		assert.NoError(t, err)
-		assert.True(t, result.Success)
-		assert.Equal(t, 1, result.ImportedCount)
+		assert.Nil(t, importErr)
+		assert.True(t, imported)
 
 		var redirects []model.Redirect
 		db.Find(&redirects)
 		assert.Len(t, redirects, 1)
 	})
+
 }
 
-func TestParseRedirectType(t *testing.T) {
-	tests := []struct {
-		name    string
-		input   string
-		want    commonTypes.RedirectType
-		wantErr bool
-	}{
-		{"basic lowercase", "basic", commonTypes.RedirectTypeBasic, false},
-		{"basic uppercase", "BASIC", commonTypes.RedirectTypeBasic, false},
-		{"basic mixed", "Basic", commonTypes.RedirectTypeBasic, false},
-		{"basic_host", "BASIC_HOST", commonTypes.RedirectTypeBasicHost, false},
-		{"regex", "REGEX", commonTypes.RedirectTypeRegex, false},
-		{"regex_host", "REGEX_HOST", commonTypes.RedirectTypeRegexHost, false},
-		{"invalid", "INVALID", "", true},
-		{"empty", "", "", true},
-	}
+func TestRedirectImportService_PreviewFile(t *testing.T) {
+	t.Run("reports new redirects without writing", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseRedirectType(tt.input)
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.want, got)
-			}
-		})
-	}
-}
+		ctx := context.Background()
+		content := "type\tsource\ttarget\tstatus\n" +
+			"BASIC\t/old1\t/new1\tMOVED_PERMANENT\n" +
+			"BASIC_HOST\t/old2\t/new2\tFOUND\n"
 
-func TestParseRedirectStatus(t *testing.T) {
-	tests := []struct {
-		name    string
-		input   string
-		want    commonTypes.RedirectStatus
-		wantErr bool
+		result, err := svc.PreviewFile(ctx, "ns", "proj", strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{Overwrite: false})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, 2, result.TotalLines)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.Equal(t, 1, result.ErrorCount)
+		assert.Equal(t, ImportErrorInvalidRedirect, result.Errors[0].Reason)
+
+		var redirectCount, draftCount int64
+		db.Model(&model.Redirect{}).Count(&redirectCount)
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(0), redirectCount)
+		assert.Equal(t, int64(0), draftCount)
+	})
+
+	t.Run("reports updates and skips without writing", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		changed := &model.Redirect{
+			NamespaceCode: "ns",
+			ProjectCode:   "proj",
+			Redirect: &commonTypes.Redirect{
+				Source: "/changed",
+				Target: "/old-target",
+				Type:   commonTypes.RedirectTypeBasic,
+				Status: commonTypes.RedirectStatusMovedPermanent,
+			},
+			IsPublished: types.Ptr(true),
+		}
+		db.Create(changed)
+		unchanged := &model.Redirect{
+			NamespaceCode: "ns",
+			ProjectCode:   "proj",
+			Redirect: &commonTypes.Redirect{
+				Source: "/unchanged",
+				Target: "/target",
+				Type:   commonTypes.RedirectTypeBasic,
+				Status: commonTypes.RedirectStatusMovedPermanent,
+			},
+			IsPublished: types.Ptr(true),
+		}
+		db.Create(unchanged)
+
+		content := "type\tsource\ttarget\tstatus\n" +
+			"BASIC\t/changed\t/new-target\tMOVED_PERMANENT\n" +
+			"BASIC\t/unchanged\t/target\tMOVED_PERMANENT\n"
+
+		result, err := svc.PreviewFile(ctx, "ns", "proj", strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{Overwrite: true})
+
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.Equal(t, 1, result.SkippedCount)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(0), draftCount)
+	})
+
+	t.Run("reports existing sources without overwrite", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		assert.NoError(t, db.Create(&model.Redirect{NamespaceCode: "ns", ProjectCode: "proj",
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/old", Status: commonTypes.RedirectStatusFound}}).Error)
+		content := "type\tsource\ttarget\tstatus\nBASIC\t/existing\t/new\tMOVED_PERMANENT\n"
+
+		result, err := svc.PreviewFile(ctx, "ns", "proj", strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{Overwrite: false})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, 1, result.ErrorCount)
+		assert.Equal(t, ImportErrorSourceAlreadyExists, result.Errors[0].Reason)
+	})
+}
+
+func TestRedirectImportService_ValidateFile_ConfiguredMaxSize(t *testing.T) {
+	ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+	defer ctrl.Finish()
+	svc.(*redirectImportService).ctx.Config.Import.MaxFileSize = 10 * 1024 * 1024
+
+	format, err := svc.ValidateFile("redirects.tsv", "text/tab-separated-values", 5*1024*1024)
+	assert.NoError(t, err)
+	assert.Equal(t, ImportFileFormatTSV, format)
+
+	_, err = svc.ValidateFile("redirects.tsv", "text/tab-separated-values", 11*1024*1024)
+	assert.ErrorContains(t, err, "file too large: maximum size is 10.00MB, got 11.00MB")
+}
+
+func TestRedirectImportService_ImportFile(t *testing.T) {
+	t.Run("imports the file batch by batch", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+		svc.(*redirectImportService).ctx.Config.Import.BatchSize = 2
+
+		ctx := context.Background()
+		content := "type\tsource\ttarget\tstatus\n" +
+			"BASIC\t/old1\t/new1\tMOVED_PERMANENT\n" +
+			"INVALID\t/bad\t/new\tMOVED_PERMANENT\n" +
+			"BASIC\t/old2\t/new2\tFOUND\n" +
+			"BASIC\t/old3\t/new3\tFOUND\n" +
+			"BASIC\t/existing\t/new4\tFOUND\n"
+
+		assert.NoError(t, db.Create(&model.Redirect{NamespaceCode: "ns", ProjectCode: "proj",
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/existing", Target: "/new", Status: commonTypes.RedirectStatusFound}}).Error)
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{Overwrite: false})
+
+		assert.NoError(t, err)
+		assert.False(t, result.Success)
+		assert.Equal(t, 5, result.TotalLines)
+		assert.Equal(t, 3, result.ImportedCount)
+		assert.Equal(t, 2, result.ErrorCount)
+		assert.Len(t, result.Errors, 2)
+		assert.Equal(t, 3, result.Errors[0].Line)
+		assert.Equal(t, ImportErrorInvalidType, result.Errors[0].Reason)
+		assert.Equal(t, 6, result.Errors[1].Line)
+		assert.Equal(t, ImportErrorSourceAlreadyExists, result.Errors[1].Reason)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(3), draftCount)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		result, err := svc.ImportFile(context.Background(), "ns", "proj", strings.NewReader("foo\tbar\n"), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("rolls back on availability error", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+		svc.(*redirectImportService).ctx.Config.Import.BatchSize = 1
+
+		ctx := context.Background()
+		content := "type\tsource\ttarget\tstatus\n" +
+			"BASIC\t/old1\t/new1\tMOVED_PERMANENT\n" +
+			"BASIC\t/old2\t/new2\tFOUND\n"
+
+		failSourceAvailability(t, db, "/old2")
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.ErrorContains(t, err, "failed to check source availability")
+		assert.Nil(t, result)
+
+		var draftCount int64
+		db.Model(&model.RedirectDraft{}).Count(&draftCount)
+		assert.Equal(t, int64(0), draftCount)
+	})
+}
+
+func TestRedirectImportService_runFile_Batches(t *testing.T) {
+	ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+	defer ctrl.Finish()
+	svc.(*redirectImportService).ctx.Config.Import.BatchSize = 2
+
+	ctx := context.Background()
+	rows := []ParsedRedirectRow{
+		{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/old1", Target: "/new1", Status: commonTypes.RedirectStatusMovedPermanent},
+		{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/old2", Target: "/new2", Status: commonTypes.RedirectStatusMovedPermanent},
+		{LineNum: 3, Type: commonTypes.RedirectTypeBasic, Source: "/old3", Target: "/new3", Status: commonTypes.RedirectStatusMovedPermanent},
+	}
+
+	var progress []int
+	result, err := svc.(*redirectImportService).runFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{}, false, func(result *ImportRedirectResult) {
+		progress = append(progress, result.ImportedCount)
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.ImportedCount)
+	assert.Equal(t, []int{2, 3}, progress)
+}
+
+func TestRedirectImportService_ParseFile_Tags(t *testing.T) {
+	ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+	defer ctrl.Finish()
+
+	t.Run("tsv tags column", func(t *testing.T) {
+		content := "type\tsource\ttarget\tstatus\ttags\n" +
+			"BASIC\t/old1\t/new1\tMOVED_PERMANENT\tsummer, migration\n" +
+			"BASIC\t/old2\t/new2\tFOUND\t\n" +
+			"BASIC\t/old3\t/new3\tFOUND\n" +
+			"BASIC\t/old4\t/new4\tFOUND\tnot valid\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, []string{"summer", "migration"}, rows[0].Tags)
+		assert.Equal(t, []string{}, rows[1].Tags)
+		assert.Equal(t, []string{}, rows[2].Tags)
+		assert.Len(t, parseErrors, 1)
+		assert.Equal(t, 5, parseErrors[0].Line)
+		assert.Contains(t, parseErrors[0].Message, "invalid tag")
+	})
+
+	t.Run("tsv without tags column", func(t *testing.T) {
+		content := "type\tsource\ttarget\tstatus\n" +
+			"BASIC\t/old1\t/new1\tMOVED_PERMANENT\n"
+
+		rows, _, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Nil(t, rows[0].Tags)
+	})
+
+	t.Run("json tags", func(t *testing.T) {
+		content := `[
+			{"type": "BASIC", "source": "/old1", "target": "/new1", "status": 301, "tags": ["summer"]},
+			{"type": "BASIC", "source": "/old2", "target": "/new2", "status": 301}
+		]`
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, parseErrors)
+		assert.Equal(t, []string{"summer"}, rows[0].Tags)
+		assert.Nil(t, rows[1].Tags)
+	})
+
+	t.Run("invalid tags header", func(t *testing.T) {
+		_, _, err := parseFile(svc, strings.NewReader("type\tsource\ttarget\tstatus\tlabels\n"), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.ErrorContains(t, err, "invalid header")
+	})
+}
+
+func TestRedirectImportService_Import_Tags(t *testing.T) {
+	t.Run("new redirect with tags", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		rows := []ParsedRedirectRow{
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent, Tags: []string{"summer"}},
+		}
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ImportedCount)
+
+		var draft model.RedirectDraft
+		assert.NoError(t, db.Preload("Tags").First(&draft).Error)
+		assert.Equal(t, []string{"summer"}, model.TagNames(draft.Tags))
+	})
+
+	t.Run("published redirect with changed or unchanged tags", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		tag := model.Tag{NamespaceCode: "ns", ProjectCode: "proj", Name: "summer"}
+		assert.NoError(t, db.Create(&tag).Error)
+		for _, source := range []string{"/same", "/retagged"} {
+			assert.NoError(t, db.Create(&model.Redirect{
+				NamespaceCode: "ns",
+				ProjectCode:   "proj",
+				IsPublished:   types.Ptr(true),
+				Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+				Tags:          []model.Tag{tag},
+			}).Error)
+		}
+
+		rows := []ParsedRedirectRow{
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/same", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent, Tags: []string{"summer"}},
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/retagged", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent, Tags: []string{"winter"}},
+		}
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.Equal(t, 1, result.SkippedCount)
+
+		var draft model.RedirectDraft
+		assert.NoError(t, db.Preload("Tags").First(&draft).Error)
+		assert.Equal(t, model.DraftChangeTypeUpdate, draft.ChangeType)
+		assert.Equal(t, []string{"winter"}, model.TagNames(draft.Tags))
+	})
+}
+
+func TestTagsAreUnchanged(t *testing.T) {
+	tags := []model.Tag{{Name: "a"}, {Name: "b"}}
+
+	assert.True(t, tagsAreUnchanged(tags, nil))
+	assert.True(t, tagsAreUnchanged(tags, []string{"b", "a"}))
+	assert.False(t, tagsAreUnchanged(tags, []string{"a"}))
+	assert.False(t, tagsAreUnchanged(tags, []string{"a", "c"}))
+	assert.True(t, tagsAreUnchanged(nil, []string{}))
+}
+
+func setupRedirectImportJobTest(t *testing.T, queueSize int) (*gomock.Controller, *mockFlectoRepository.MockRedirectDraftRepository, *mockFlectoRepository.MockImportJobRepository, *gorm.DB, *redirectImportService) {
+	ctrl := gomock.NewController(t)
+	mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
+	mockJobRepo := mockFlectoRepository.NewMockImportJobRepository(ctrl)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.RedirectDraft{}, &model.NamespacePolicy{})
+	assert.NoError(t, err)
+	mockRepo.EXPECT().GetTx(gomock.Any()).Return(db).AnyTimes()
+	ctx := appContext.TestContext(nil)
+	ctx.Config.Import.QueueSize = queueSize
+	svc := NewRedirectImportService(ctx, mockRepo, mockJobRepo).(*redirectImportService)
+	return ctrl, mockRepo, mockJobRepo, db, svc
+}
+
+func TestRedirectImportService_StartImportJob(t *testing.T) {
+	content := "type\tsource\ttarget\tstatus\nBASIC\t/old1\t/new1\tMOVED_PERMANENT\n"
+
+	t.Run("job is created and queued", func(t *testing.T) {
+		ctrl, _, mockJobRepo, _, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		mockJobRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job *model.ImportJob) error {
+			job.ID = 1
+			return nil
+		})
+
+		sourcePrefix := &model.ImportPrefixRewrite{From: "/en/", To: "/"}
+		job, err := svc.StartImportJob(context.Background(), "ns1", "proj1", strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{Overwrite: true, SourcePrefix: sourcePrefix})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.ImportJobStatusPending, job.Status)
+		assert.Equal(t, sourcePrefix, job.SourcePrefix)
+		assert.Nil(t, job.TargetPrefix)
+		assert.Zero(t, job.TotalLines)
+		require.Len(t, svc.queue, 1)
+		task := <-svc.queue
+		t.Cleanup(func() { _ = os.Remove(task.file) })
+		assert.Equal(t, ImportFileFormatTSV, task.format)
+		spooled, err := os.ReadFile(task.file)
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(spooled))
+	})
+
+	t.Run("queue full", func(t *testing.T) {
+		ctrl, _, mockJobRepo, _, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		svc.queue <- importJobTask{}
+		mockJobRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+		mockJobRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job *model.ImportJob) error {
+			assert.Equal(t, model.ImportJobStatusFailed, job.Status)
+			assert.Equal(t, ErrImportQueueFull.Error(), job.ErrorMessage)
+			return nil
+		})
+
+		job, err := svc.StartImportJob(context.Background(), "ns1", "proj1", strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.ErrorIs(t, err, ErrImportQueueFull)
+		assert.Nil(t, job)
+	})
+
+	t.Run("create error", func(t *testing.T) {
+		ctrl, _, mockJobRepo, _, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		mockJobRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+
+		job, err := svc.StartImportJob(context.Background(), "ns1", "proj1", strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.Error(t, err)
+		assert.Nil(t, job)
+		assert.Len(t, svc.queue, 0)
+	})
+}
+
+func TestRedirectImportService_processImportJob(t *testing.T) {
+	t.Run("completed", func(t *testing.T) {
+		ctrl, _, mockJobRepo, db, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		job := &model.ImportJob{ID: 1, NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusPending}
+		file := writeImportJobFile(t, "type\tsource\ttarget\tstatus\n"+
+			"BASIC\t/en/old1\t/new1\tMOVED_PERMANENT\n"+
+			"BASIC_HOST\t/invalid\t/new2\tFOUND\n"+
+			"INVALID\t/old3\t/new3\tFOUND\n")
+		opts := ImportRedirectOptions{Overwrite: true, SourcePrefix: &model.ImportPrefixRewrite{From: "/en/", To: "/"}}
+
+		var statuses []model.ImportJobStatus
+		mockJobRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job *model.ImportJob) error {
+			statuses = append(statuses, job.Status)
+			return nil
+		}).Times(2)
+
+		svc.processImportJob(importJobTask{job: job, file: file, format: ImportFileFormatTSV, opts: opts})
+
+		assert.Equal(t, []model.ImportJobStatus{model.ImportJobStatusRunning, model.ImportJobStatusCompleted}, statuses)
+		assert.Equal(t, 3, job.TotalLines)
+		assert.Equal(t, 3, job.ProcessedCount)
+		assert.Equal(t, 1, job.ImportedCount)
+		assert.Equal(t, 0, job.SkippedCount)
+		assert.Equal(t, 2, job.ErrorCount)
+		assert.Equal(t, 1, job.RewrittenCount)
+		assert.Len(t, job.Errors, 2)
+		assert.NotNil(t, job.FinishedAt)
+		assert.Empty(t, svc.progress)
+		assert.NoFileExists(t, file, "the file is removed once the job is processed")
+
+		var count int64
+		db.Model(&model.RedirectDraft{}).Count(&count)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		ctrl, _, mockJobRepo, db, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		failSourceAvailability(t, db, "/old1")
+
+		job := &model.ImportJob{ID: 1, NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusPending}
+		file := writeImportJobFile(t, "type\tsource\ttarget\tstatus\nBASIC\t/old1\t/new1\tMOVED_PERMANENT\n")
+		mockJobRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		svc.processImportJob(importJobTask{job: job, file: file, format: ImportFileFormatTSV})
+
+		assert.Equal(t, model.ImportJobStatusFailed, job.Status)
+		assert.NotEmpty(t, job.ErrorMessage)
+		assert.NotNil(t, job.FinishedAt)
+		assert.NoFileExists(t, file)
+	})
+
+	t.Run("failed in read-only mode", func(t *testing.T) {
+		ctrl, _, mockJobRepo, db, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		svc.ctx.SetReadOnly(config.ReadOnlyConfig{Enabled: true, Message: "database migration"})
+		job := &model.ImportJob{ID: 1, NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusPending}
+		file := writeImportJobFile(t, "type\tsource\ttarget\tstatus\nBASIC\t/old1\t/new1\tMOVED_PERMANENT\n")
+		mockJobRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+		svc.processImportJob(importJobTask{job: job, file: file, format: ImportFileFormatTSV})
+
+		assert.Equal(t, model.ImportJobStatusFailed, job.Status)
+		assert.Equal(t, "the manager is in read-only mode: database migration", job.ErrorMessage)
+		assert.NoFileExists(t, file)
+		var count int64
+		db.Model(&model.RedirectDraft{}).Count(&count)
+		assert.Zero(t, count)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		ctrl, _, mockJobRepo, _, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		job := &model.ImportJob{ID: 1, NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusPending}
+		mockJobRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		svc.processImportJob(importJobTask{job: job, file: filepath.Join(t.TempDir(), "missing"), format: ImportFileFormatTSV})
+
+		assert.Equal(t, model.ImportJobStatusFailed, job.Status)
+		assert.Contains(t, job.ErrorMessage, "failed to open the import file")
+	})
+}
+
+// writeImportJobFile writes the file of an import job, as spooled by StartImportJob
+func writeImportJobFile(t *testing.T, content string) string {
+	file := filepath.Join(t.TempDir(), "import.tsv")
+	assert.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+	return file
+}
+
+func TestRedirectImportService_GetImportJob(t *testing.T) {
+	t.Run("running job with progress", func(t *testing.T) {
+		ctrl, _, mockJobRepo, _, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		mockJobRepo.EXPECT().FindByIDWithProject(gomock.Any(), "ns1", "proj1", int64(1)).Return(&model.ImportJob{
+			ID:             1,
+			Status:         model.ImportJobStatusRunning,
+			TotalLines:     10,
+			ProcessedCount: 1,
+			ErrorCount:     1,
+		}, nil)
+		svc.setImportJobProgress(1, &ImportRedirectResult{ImportedCount: 3, SkippedCount: 1, ErrorCount: 1})
+
+		job, err := svc.GetImportJob(context.Background(), "ns1", "proj1", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 6, job.ProcessedCount)
+		assert.Equal(t, 3, job.ImportedCount)
+		assert.Equal(t, 1, job.SkippedCount)
+		assert.Equal(t, 2, job.ErrorCount)
+	})
+
+	t.Run("completed job", func(t *testing.T) {
+		ctrl, _, mockJobRepo, _, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		stored := &model.ImportJob{ID: 1, Status: model.ImportJobStatusCompleted, ProcessedCount: 10, ImportedCount: 10}
+		mockJobRepo.EXPECT().FindByIDWithProject(gomock.Any(), "ns1", "proj1", int64(1)).Return(stored, nil)
+
+		job, err := svc.GetImportJob(context.Background(), "ns1", "proj1", 1)
+
+		assert.NoError(t, err)
+		assert.Equal(t, stored, job)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctrl, _, mockJobRepo, _, svc := setupRedirectImportJobTest(t, 1)
+		defer ctrl.Finish()
+
+		mockJobRepo.EXPECT().FindByIDWithProject(gomock.Any(), "ns1", "proj1", int64(1)).Return(nil, gorm.ErrRecordNotFound)
+
+		job, err := svc.GetImportJob(context.Background(), "ns1", "proj1", 1)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		assert.Nil(t, job)
+	})
+}
+
+func TestRedirectImportService_StartWorkers(t *testing.T) {
+	ctrl, _, mockJobRepo, _, svc := setupRedirectImportJobTest(t, 1)
+	defer ctrl.Finish()
+	defer svc.ctx.Cancel()
+
+	mockJobRepo.EXPECT().GetTx(gomock.Any()).Return(&gorm.DB{Config: &gorm.Config{}})
+	mockJobRepo.EXPECT().FailUnfinished(gomock.Any(), ErrImportJobInterrupted.Error()).Return(int64(2), nil)
+
+	svc.StartWorkers()
+
+	// an empty task is picked by a worker and fails to start
+	done := make(chan struct{})
+	mockJobRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ *model.ImportJob) error {
+		close(done)
+		return errors.New("db error")
+	})
+	svc.queue <- importJobTask{job: &model.ImportJob{ID: 1}}
+	<-done
+}
+
+func TestParseRedirectType(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    commonTypes.RedirectType
+		wantErr bool
+	}{
+		{"basic lowercase", "basic", commonTypes.RedirectTypeBasic, false},
+		{"basic uppercase", "BASIC", commonTypes.RedirectTypeBasic, false},
+		{"basic mixed", "Basic", commonTypes.RedirectTypeBasic, false},
+		{"basic_host", "BASIC_HOST", commonTypes.RedirectTypeBasicHost, false},
+		{"regex", "REGEX", commonTypes.RedirectTypeRegex, false},
+		{"regex_host", "REGEX_HOST", commonTypes.RedirectTypeRegexHost, false},
+		{"invalid", "INVALID", "", true},
+		{"empty", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRedirectType(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseRedirectStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    commonTypes.RedirectStatus
+		wantErr bool
 	}{
 		{"301", "301", commonTypes.RedirectStatusMovedPermanent, false},
 		{"302", "302", commonTypes.RedirectStatusFound, false},
@@ -1101,6 +2066,23 @@ func TestRedirectsAreEqual(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "different priority",
+			a: &commonTypes.Redirect{
+				Type:     commonTypes.RedirectTypeRegex,
+				Source:   "^/source",
+				Target:   "/target",
+				Status:   commonTypes.RedirectStatusMovedPermanent,
+				Priority: 1,
+			},
+			b: &commonTypes.Redirect{
+				Type:   commonTypes.RedirectTypeRegex,
+				Source: "^/source",
+				Target: "/target",
+				Status: commonTypes.RedirectStatusMovedPermanent,
+			},
+			want: false,
+		},
 		{
 			name: "different status",
 			a: &commonTypes.Redirect{
@@ -1127,12 +2109,477 @@ func TestRedirectsAreEqual(t *testing.T) {
 	}
 }
 
+func TestKeepExistingValues(t *testing.T) {
+	validFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	newRedirect := &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/b"}
+	keepExistingValues(ParsedRedirectRow{}, newRedirect, nil)
+	assert.Nil(t, newRedirect.ValidFrom)
+	assert.Nil(t, newRedirect.ValidUntil)
+
+	keepExistingValues(ParsedRedirectRow{}, newRedirect, &commonTypes.Redirect{ValidFrom: &validFrom, ValidUntil: &validUntil})
+	assert.Equal(t, &validFrom, newRedirect.ValidFrom)
+	assert.Equal(t, &validUntil, newRedirect.ValidUntil)
+
+	// The bounds given by the file are kept, even when empty
+	newRedirect = &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/b"}
+	keepExistingValues(ParsedRedirectRow{HasValidUntil: true}, newRedirect, &commonTypes.Redirect{ValidFrom: &validFrom, ValidUntil: &validUntil})
+	assert.Equal(t, &validFrom, newRedirect.ValidFrom)
+	assert.Nil(t, newRedirect.ValidUntil)
+
+	newRedirect = &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/b"}
+	keepExistingValues(ParsedRedirectRow{}, newRedirect, &commonTypes.Redirect{Priority: 5})
+	assert.Equal(t, 5, newRedirect.Priority)
+
+	newRedirect = &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/b"}
+	keepExistingValues(ParsedRedirectRow{Priority: types.Ptr(0)}, newRedirect, &commonTypes.Redirect{Priority: 5})
+	assert.Equal(t, 0, newRedirect.Priority)
+
+	targets := []commonTypes.RedirectTarget{{Target: "/b", Weight: 90}, {Target: "/c", Weight: 10}}
+	newRedirect = &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/b"}
+	keepExistingValues(ParsedRedirectRow{}, newRedirect, &commonTypes.Redirect{Target: "/b", Targets: targets})
+	assert.Equal(t, targets, newRedirect.Targets)
+
+	// The targets are dropped with a new target or an empty targets cell
+	newRedirect = &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/d"}
+	keepExistingValues(ParsedRedirectRow{}, newRedirect, &commonTypes.Redirect{Target: "/b", Targets: targets})
+	assert.Nil(t, newRedirect.Targets)
+
+	newRedirect = &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/a", Target: "/b"}
+	keepExistingValues(ParsedRedirectRow{HasTargets: true}, newRedirect, &commonTypes.Redirect{Target: "/b", Targets: targets})
+	assert.Nil(t, newRedirect.Targets)
+}
+
+func TestParseImportTime(t *testing.T) {
+	value, err := parseImportTime(" ")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	for input, want := range map[string]time.Time{
+		"2026-03-01T10:00:00+02:00": time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+		"2026-03-01T10:00:00":       time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		"2026-03-01 10:00:00":       time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		"2026-03-01":                time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		value, err = parseImportTime(input)
+		assert.NoError(t, err, input)
+		assert.True(t, want.Equal(*value), input)
+	}
+
+	_, err = parseImportTime("01/03/2026")
+	assert.ErrorContains(t, err, "got '01/03/2026'")
+}
+
+func TestXlsxSerialDate(t *testing.T) {
+	assert.Equal(t, "2026-01-01T00:00:00Z", xlsxSerialDate("46023"))
+	assert.Equal(t, "2026-01-01T12:00:00Z", xlsxSerialDate("46023.5"))
+	assert.Equal(t, "2026-01-01", xlsxSerialDate("2026-01-01"))
+	assert.Equal(t, "", xlsxSerialDate(""))
+}
+
+func TestRedirectImportService_ParseFile_OptionalColumns(t *testing.T) {
+	ctrl, _, _, svc := setupRedirectImportServiceTest(t)
+	defer ctrl.Finish()
+
+	t.Run("tsv columns in any order", func(t *testing.T) {
+		content := "type\tsource\ttarget\tstatus\tcomment\tvalid_until\ttags\tvalid_from\n" +
+			"BASIC\t/old1\t/new1\t301\tsummer sale\t2026-09-01\tsummer\t2026-06-01T08:00:00Z\n" +
+			"BASIC\t/old2\t/new2\t301\t\t\n" +
+			"BASIC\t/old3\t/new3\t301\t\tnot a date\n" +
+			"BASIC\t/old4\t/new4\t301\t\t\t\t\textra\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, []string{"summer"}, rows[0].Tags)
+		assert.Equal(t, "summer sale", *rows[0].Comment)
+		assert.Equal(t, time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC), rows[0].ValidFrom.UTC())
+		assert.Equal(t, time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), *rows[0].ValidUntil)
+		assert.True(t, rows[0].HasValidFrom)
+		assert.True(t, rows[0].HasValidUntil)
+
+		assert.Equal(t, []string{}, rows[1].Tags)
+		assert.Equal(t, "", *rows[1].Comment)
+		assert.Nil(t, rows[1].ValidFrom)
+		assert.Nil(t, rows[1].ValidUntil)
+		assert.True(t, rows[1].HasValidFrom)
+
+		assert.Len(t, parseErrors, 2)
+		assert.Equal(t, 4, parseErrors[0].Line)
+		assert.Contains(t, parseErrors[0].Message, "invalid valid_until")
+		assert.Equal(t, 5, parseErrors[1].Line)
+		assert.Equal(t, "expected 8 columns, got 9", parseErrors[1].Message)
+	})
+
+	t.Run("tsv without optional columns", func(t *testing.T) {
+		rows, _, err := parseFile(svc, strings.NewReader("type\tsource\ttarget\tstatus\nBASIC\t/old\t/new\t301\n"), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Nil(t, rows[0].Comment)
+		assert.False(t, rows[0].HasValidFrom)
+		assert.False(t, rows[0].HasValidUntil)
+	})
+
+	t.Run("priority", func(t *testing.T) {
+		content := "type\tsource\ttarget\tstatus\tpriority\n" +
+			"REGEX\t^/old1\t/new1\t301\t10\n" +
+			"REGEX\t^/old2\t/new2\t301\t\n" +
+			"REGEX\t^/old3\t/new3\t301\thigh\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, 10, *rows[0].Priority)
+		assert.Equal(t, 0, *rows[1].Priority)
+		assert.Len(t, parseErrors, 1)
+		assert.Equal(t, "invalid priority: expected an integer, got 'high'", parseErrors[0].Message)
+
+		rows, parseErrors, err = parseFile(svc, strings.NewReader(`[
+			{"type": "REGEX", "source": "^/old1", "target": "/new1", "status": 301, "priority": -2},
+			{"type": "REGEX", "source": "^/old2", "target": "/new2", "status": 301, "priority": null}
+		]`), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, parseErrors)
+		assert.Equal(t, -2, *rows[0].Priority)
+		assert.Nil(t, rows[1].Priority)
+	})
+
+	t.Run("targets", func(t *testing.T) {
+		content := "type\tsource\ttarget\tstatus\ttargets\n" +
+			"BASIC\t/old1\t/new\t302\t90:/new | 10:https://beta.example.com/new?a=1\n" +
+			"BASIC\t/old2\t/new\t302\t\n" +
+			"BASIC\t/old3\t/new\t302\t/new|/beta\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, []commonTypes.RedirectTarget{{Target: "/new", Weight: 90}, {Target: "https://beta.example.com/new?a=1", Weight: 10}}, rows[0].Targets)
+		assert.True(t, rows[0].HasTargets)
+		assert.Nil(t, rows[1].Targets)
+		assert.True(t, rows[1].HasTargets)
+		assert.Len(t, parseErrors, 1)
+		assert.Equal(t, "invalid targets: expected weight:target entries separated by |, got '/new'", parseErrors[0].Message)
+
+		rows, parseErrors, err = parseFile(svc, strings.NewReader(`[
+			{"type": "BASIC", "source": "/old1", "target": "/new", "status": 302, "targets": [{"target": "/new", "weight": 50}, {"target": "/beta", "weight": 50}]},
+			{"type": "BASIC", "source": "/old2", "target": "/new", "status": 302}
+		]`), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, parseErrors)
+		assert.Equal(t, []commonTypes.RedirectTarget{{Target: "/new", Weight: 50}, {Target: "/beta", Weight: 50}}, rows[0].Targets)
+		assert.True(t, rows[0].HasTargets)
+		assert.False(t, rows[1].HasTargets)
+	})
+
+	t.Run("comment too long", func(t *testing.T) {
+		content := "type\tsource\ttarget\tstatus\tcomment\n" +
+			"BASIC\t/old\t/new\t301\t" + strings.Repeat("é", 501) + "\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, rows)
+		assert.Len(t, parseErrors, 1)
+		assert.Contains(t, parseErrors[0].Message, "comment cannot exceed 500 characters")
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		_, _, err := parseFile(svc, strings.NewReader("type\tsource\ttarget\tstatus\tweight\n"), ImportFileFormatTSV, ImportRedirectOptions{})
+		assert.ErrorContains(t, err, "unknown column 5 'weight'")
+
+		_, _, err = parseFile(svc, strings.NewReader("type\tsource\ttarget\tstatus\ttags\tTags\n"), ImportFileFormatTSV, ImportRedirectOptions{})
+		assert.ErrorContains(t, err, "duplicate column 'tags'")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		content := `[
+			{"type": "BASIC", "source": "/old1", "target": "/new1", "status": 301, "validFrom": "2026-06-01", "validUntil": "", "comment": "summer sale"},
+			{"type": "BASIC", "source": "/old2", "target": "/new2", "status": 301},
+			{"type": "BASIC", "source": "/old3", "target": "/new3", "status": 301, "validUntil": "tomorrow"}
+		]`
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC), *rows[0].ValidFrom)
+		assert.Nil(t, rows[0].ValidUntil)
+		assert.True(t, rows[0].HasValidUntil)
+		assert.Equal(t, "summer sale", *rows[0].Comment)
+		assert.False(t, rows[1].HasValidFrom)
+		assert.Nil(t, rows[1].Comment)
+		assert.Len(t, parseErrors, 1)
+		assert.Equal(t, 3, parseErrors[0].Line)
+	})
+}
+
+func TestRedirectImportService_ParseFile_Profile(t *testing.T) {
+	_, _, _, svc := setupRedirectImportServiceTest(t)
+	redirectType := commonTypes.RedirectTypeBasic
+	status := commonTypes.RedirectStatusFound
+
+	t.Run("columns and delimiter of the profile", func(t *testing.T) {
+		profile := &model.ImportProfile{
+			Columns:       []string{"source", "comment", "target"},
+			SkipHeader:    true,
+			Delimiter:     model.ImportDelimiterSemicolon,
+			DefaultType:   &redirectType,
+			DefaultStatus: &status,
+		}
+		content := "Old URL;Note;New URL\n" +
+			"/old1;moved;/new1\n" +
+			"/old2;;/new2\n" +
+			"/old3\n" +
+			"BASIC;/old4;/new4;301\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{Profile: profile})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []ParsedRedirectRow{
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/old1", Target: "/new1", Status: commonTypes.RedirectStatusFound, Comment: types.Ptr("moved")},
+			{LineNum: 3, Type: commonTypes.RedirectTypeBasic, Source: "/old2", Target: "/new2", Status: commonTypes.RedirectStatusFound, Comment: types.Ptr("")},
+		}, rows)
+		assert.Len(t, parseErrors, 2)
+		assert.Equal(t, 4, parseErrors[0].Line)
+		assert.Equal(t, "expected 3 columns, got 1", parseErrors[0].Message)
+		assert.Equal(t, "expected 3 columns, got 4", parseErrors[1].Message)
+	})
+
+	t.Run("file without header", func(t *testing.T) {
+		profile := &model.ImportProfile{Columns: []string{"target", "source", "status", "type"}, Delimiter: model.ImportDelimiterComma}
+		content := "/new1,/old1,301,REGEX\n" +
+			"/new2,/old2,,\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{Profile: profile})
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, 1, rows[0].LineNum)
+		assert.Equal(t, commonTypes.RedirectTypeRegex, rows[0].Type)
+		assert.Equal(t, "/old1", rows[0].Source)
+		assert.Len(t, parseErrors, 1)
+		assert.Equal(t, 2, parseErrors[0].Line)
+		assert.Equal(t, ImportErrorInvalidType, parseErrors[0].Reason)
+	})
+
+	t.Run("default values applied to the empty cells", func(t *testing.T) {
+		profile := &model.ImportProfile{Delimiter: model.ImportDelimiterTab, DefaultType: &redirectType, DefaultStatus: &status}
+		content := "type\tsource\ttarget\tstatus\n" +
+			"\t/old1\t/new1\t\n" +
+			"REGEX\t/old2\t/new2\t301\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, ImportRedirectOptions{Profile: profile})
+
+		assert.NoError(t, err)
+		assert.Empty(t, parseErrors)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, commonTypes.RedirectTypeBasic, rows[0].Type)
+		assert.Equal(t, commonTypes.RedirectStatusFound, rows[0].Status)
+		assert.Equal(t, commonTypes.RedirectTypeRegex, rows[1].Type)
+		assert.Equal(t, commonTypes.RedirectStatusMovedPermanent, rows[1].Status)
+
+		rows, parseErrors, err = parseFile(svc, strings.NewReader(`[{"source": "/old", "target": "/new"}]`), ImportFileFormatJSON, ImportRedirectOptions{Profile: profile})
+
+		assert.NoError(t, err)
+		assert.Empty(t, parseErrors)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, commonTypes.RedirectStatusFound, rows[0].Status)
+	})
+
+	t.Run("invalid columns", func(t *testing.T) {
+		profile := &model.ImportProfile{Columns: []string{"source", "url"}}
+
+		_, _, err := parseFile(svc, strings.NewReader("/old,/new\n"), ImportFileFormatTSV, ImportRedirectOptions{Profile: profile})
+
+		assert.ErrorContains(t, err, "unknown column 2 'url'")
+	})
+}
+
+func TestRedirectImportService_ParseFile_PrefixRewrite(t *testing.T) {
+	_, _, _, svc := setupRedirectImportServiceTest(t)
+
+	t.Run("sources and targets rewritten", func(t *testing.T) {
+		opts := ImportRedirectOptions{
+			SourcePrefix: &model.ImportPrefixRewrite{From: "/en/", To: "/"},
+			TargetPrefix: &model.ImportPrefixRewrite{To: "/blog"},
+		}
+		content := "type\tsource\ttarget\tstatus\n" +
+			"BASIC\t/en/old1\t/new1\t301\n" +
+			"BASIC\t/fr/old2\t/new2\t301\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, opts)
+
+		assert.NoError(t, err)
+		assert.Empty(t, parseErrors)
+		assert.Equal(t, []ParsedRedirectRow{
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/old1", Target: "/blog/new1", Status: commonTypes.RedirectStatusMovedPermanent, OriginalSource: "/en/old1", OriginalTarget: "/new1"},
+			{LineNum: 3, Type: commonTypes.RedirectTypeBasic, Source: "/fr/old2", Target: "/blog/new2", Status: commonTypes.RedirectStatusMovedPermanent, OriginalTarget: "/new2"},
+		}, rows)
+		assert.True(t, rows[0].rewritten())
+	})
+
+	t.Run("duplicate sources after the rewrite", func(t *testing.T) {
+		opts := ImportRedirectOptions{SourcePrefix: &model.ImportPrefixRewrite{From: "/en/", To: "/"}}
+		content := "type\tsource\ttarget\tstatus\n" +
+			"BASIC\t/en/old\t/new1\t301\n" +
+			"BASIC\t/old\t/new2\t301\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, opts)
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Len(t, parseErrors, 1)
+		assert.Equal(t, 3, parseErrors[0].Line)
+		assert.Equal(t, ImportErrorDuplicateInFile, parseErrors[0].Reason)
+	})
+
+	t.Run("empty values are not rewritten", func(t *testing.T) {
+		opts := ImportRedirectOptions{SourcePrefix: &model.ImportPrefixRewrite{To: "/blog"}}
+		content := "type\tsource\ttarget\tstatus\n" +
+			"BASIC\t\t/new\t301\n"
+
+		rows, parseErrors, err := parseFile(svc, strings.NewReader(content), ImportFileFormatTSV, opts)
+
+		assert.NoError(t, err)
+		assert.Empty(t, rows)
+		assert.Len(t, parseErrors, 1)
+		assert.Equal(t, ImportErrorEmptySource, parseErrors[0].Reason)
+	})
+}
+
+func TestRedirectImportService_Import_OptionalColumns(t *testing.T) {
+	validFrom := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("new redirect", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		rows := []ParsedRedirectRow{
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent,
+				ValidFrom: &validFrom, ValidUntil: &validUntil, HasValidFrom: true, HasValidUntil: true, Comment: types.Ptr("summer sale")},
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/invalid", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent,
+				ValidFrom: &validUntil, ValidUntil: &validFrom, HasValidFrom: true, HasValidUntil: true},
+		}
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.Equal(t, 1, result.ErrorCount)
+		assert.Equal(t, ImportErrorInvalidRedirect, result.Errors[0].Reason)
+
+		var draft model.RedirectDraft
+		assert.NoError(t, db.First(&draft).Error)
+		assert.Equal(t, "summer sale", draft.Comment)
+		assert.True(t, validFrom.Equal(*draft.NewRedirect.ValidFrom))
+		assert.True(t, validUntil.Equal(*draft.NewRedirect.ValidUntil))
+	})
+
+	t.Run("weighted targets", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		targets := []commonTypes.RedirectTarget{{Target: "/new", Weight: 90}, {Target: "/beta", Weight: 10}}
+		rows := []ParsedRedirectRow{
+			{LineNum: 1, Type: commonTypes.RedirectTypeBasic, Source: "/split", Target: "/new", Status: commonTypes.RedirectStatusFound,
+				Targets: targets, HasTargets: true},
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/invalid", Target: "/new", Status: commonTypes.RedirectStatusFound,
+				Targets: []commonTypes.RedirectTarget{{Target: "/new", Weight: 90}, {Target: "/beta", Weight: 20}}, HasTargets: true},
+		}
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.Equal(t, 1, result.ErrorCount)
+		assert.Equal(t, ImportErrorInvalidRedirect, result.Errors[0].Reason)
+
+		var draft model.RedirectDraft
+		assert.NoError(t, db.First(&draft).Error)
+		assert.Equal(t, targets, draft.NewRedirect.Targets)
+	})
+
+	t.Run("published redirect", func(t *testing.T) {
+		ctrl, _, db, svc := setupRedirectImportServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		for _, source := range []string{"/same", "/extended"} {
+			assert.NoError(t, db.Create(&model.Redirect{
+				NamespaceCode: "ns",
+				ProjectCode:   "proj",
+				IsPublished:   types.Ptr(true),
+				Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: source, Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent,
+					ValidFrom: &validFrom, ValidUntil: &validUntil},
+			}).Error)
+		}
+
+		extended := validUntil.AddDate(0, 1, 0)
+		assert.NoError(t, db.Create(&model.Redirect{
+			NamespaceCode: "ns",
+			ProjectCode:   "proj",
+			IsPublished:   types.Ptr(true),
+			Redirect:      &commonTypes.Redirect{Type: commonTypes.RedirectTypeRegex, Source: "^/prioritized", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent, Priority: 3},
+		}).Error)
+		rows := []ParsedRedirectRow{
+			// Without priority column the priority of the redirect is kept
+			{LineNum: 1, Type: commonTypes.RedirectTypeRegex, Source: "^/prioritized", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent},
+			// Without validity columns the period of the redirect is kept, and a comment alone is not a change
+			{LineNum: 2, Type: commonTypes.RedirectTypeBasic, Source: "/same", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent, Comment: types.Ptr("note")},
+			{LineNum: 3, Type: commonTypes.RedirectTypeBasic, Source: "/extended", Target: "/target", Status: commonTypes.RedirectStatusMovedPermanent,
+				ValidUntil: &extended, HasValidUntil: true, Comment: types.Ptr("extended")},
+		}
+
+		result, err := svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.Equal(t, 2, result.SkippedCount)
+
+		var draft model.RedirectDraft
+		assert.NoError(t, db.First(&draft).Error)
+		assert.Equal(t, "/extended", draft.NewRedirect.Source)
+		assert.Equal(t, "extended", draft.Comment)
+		assert.True(t, validFrom.Equal(*draft.NewRedirect.ValidFrom))
+		assert.True(t, extended.Equal(*draft.NewRedirect.ValidUntil))
+
+		// Changing the comment of the draft updates it
+		rows[2].Comment = types.Ptr("extended again")
+		result, err = svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows[2:]), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ImportedCount)
+		assert.NoError(t, db.First(&draft, draft.ID).Error)
+		assert.Equal(t, "extended again", draft.Comment)
+
+		// A new priority is a change
+		rows[0].Priority = types.Ptr(1)
+		result, err = svc.ImportFile(ctx, "ns", "proj", jsonImportFile(t, rows[:1]), ImportFileFormatJSON, ImportRedirectOptions{Overwrite: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.ImportedCount)
+		var prioritized model.RedirectDraft
+		assert.NoError(t, db.Where("new_source = ?", "^/prioritized").First(&prioritized).Error)
+		assert.Equal(t, 1, prioritized.NewRedirect.Priority)
+	})
+}
+
 func TestRedirectImportService_GetTx(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
-	svc := NewRedirectImportService(appContext.TestContext(nil), mockRepo)
+	svc := NewRedirectImportService(appContext.TestContext(nil), mockRepo, mockFlectoRepository.NewMockImportJobRepository(ctrl))
 
 	ctx := context.Background()
 	mockRepo.EXPECT().GetTx(ctx).Return(nil)
@@ -1146,7 +2593,7 @@ func TestRedirectImportService_GetQuery(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mockFlectoRepository.NewMockRedirectDraftRepository(ctrl)
-	svc := NewRedirectImportService(appContext.TestContext(nil), mockRepo)
+	svc := NewRedirectImportService(appContext.TestContext(nil), mockRepo, mockFlectoRepository.NewMockImportJobRepository(ctrl))
 
 	ctx := context.Background()
 	mockRepo.EXPECT().GetQuery(ctx).Return(nil)
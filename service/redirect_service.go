@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"fmt"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
@@ -16,8 +17,11 @@ type RedirectService interface {
 	GetByID(ctx context.Context, namespaceCode, projectCode string, redirectID int64) (*model.Redirect, error)
 	FindByProject(ctx context.Context, namespaceCode, projectCode string) ([]model.Redirect, error)
 	FindByProjectPublished(ctx context.Context, namespaceCode, projectCode string, pagination *commonTypes.PaginationInput) ([]model.Redirect, int64, error)
+	StreamByProjectPublished(ctx context.Context, namespaceCode, projectCode string, version int, start func(total int64) error, fn func(redirects []model.Redirect) error) error
 	Search(ctx context.Context, query *gorm.DB) ([]model.Redirect, error)
 	SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.RedirectList, error)
+	SearchCursor(ctx context.Context, cursor *commonTypes.CursorInput, query *gorm.DB) (*model.RedirectCursorList, error)
+	FindSourceOverlaps(ctx context.Context, namespaceCode string, projectCodes []string, limit int) ([]model.RedirectOverlap, error)
 }
 
 type redirectService struct {
@@ -52,12 +56,32 @@ func (s *redirectService) FindByProjectPublished(ctx context.Context, namespaceC
 	return s.repo.FindByProjectPublished(ctx, namespaceCode, projectCode, pagination.GetLimit(), pagination.GetOffset())
 }
 
+// StreamByProjectPublished calls start with the number of published redirects of a project then fn with their batches,
+// in the order of FindByProjectPublished. It fails with ErrSnapshotChanged when the project is published meanwhile,
+// version being the one of the project when the stream started.
+func (s *redirectService) StreamByProjectPublished(ctx context.Context, namespaceCode, projectCode string, version int, start func(total int64) error, fn func(redirects []model.Redirect) error) error {
+	var total int64
+	err := s.repo.GetQuery(ctx).
+		Where(fmt.Sprintf("%s = ? AND %s = ? AND is_published = 1", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Count(&total).Error
+	if err != nil {
+		return err
+	}
+	if err = start(total); err != nil {
+		return err
+	}
+
+	return streamPublished(ctx, s.repo.GetTx(ctx), namespaceCode, projectCode, version, func(last *model.Redirect) ([]model.Redirect, error) {
+		return s.repo.FindByProjectPublishedAfter(ctx, namespaceCode, projectCode, last, SnapshotStreamBatchSize)
+	}, fn)
+}
+
 func (s *redirectService) Search(ctx context.Context, query *gorm.DB) ([]model.Redirect, error) {
 	return s.repo.Search(ctx, query)
 }
 
 func (s *redirectService) SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.RedirectList, error) {
-	redirects, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	redirects, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
@@ -69,3 +93,40 @@ func (s *redirectService) SearchPaginate(ctx context.Context, pagination *common
 		Items:  redirects,
 	}, nil
 }
+
+func (s *redirectService) SearchCursor(ctx context.Context, cursor *commonTypes.CursorInput, query *gorm.DB) (*model.RedirectCursorList, error) {
+	afterID, err := cursor.GetAfterID()
+	if err != nil {
+		return nil, err
+	}
+
+	redirects, hasMore, err := s.repo.SearchCursor(ctx, query, afterID, cursor.GetLimit())
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.RedirectCursorList{Items: redirects}
+	if hasMore {
+		nextCursor := commonTypes.EncodeCursor(redirects[len(redirects)-1].ID)
+		result.NextCursor = &nextCursor
+	}
+	return result, nil
+}
+
+// FindSourceOverlaps returns the sources defined by the published redirects of several of the projects of the
+// namespace, ordered by source. The limit of sources defaults to DefaultRedirectOverlapLimit and is capped to
+// MaxRedirectOverlapLimit.
+func (s *redirectService) FindSourceOverlaps(ctx context.Context, namespaceCode string, projectCodes []string, limit int) ([]model.RedirectOverlap, error) {
+	if limit <= 0 {
+		limit = model.DefaultRedirectOverlapLimit
+	}
+	if limit > model.MaxRedirectOverlapLimit {
+		limit = model.MaxRedirectOverlapLimit
+	}
+
+	redirects, err := s.repo.FindSourceOverlaps(ctx, namespaceCode, projectCodes, limit)
+	if err != nil {
+		return nil, err
+	}
+	return model.GroupRedirectOverlaps(redirects), nil
+}
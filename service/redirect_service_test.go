@@ -261,7 +261,7 @@ func TestRedirectService_SearchPaginate(t *testing.T) {
 		}
 
 		mockRedirectRepo.EXPECT().
-			SearchPaginate(ctx, nil, 10, 5).
+			SearchPaginate(ctx, nil, 10, 5, nil).
 			Return(expectedRedirects, int64(50), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -285,7 +285,7 @@ func TestRedirectService_SearchPaginate(t *testing.T) {
 		}
 
 		mockRedirectRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(expectedRedirects, int64(1), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -296,6 +296,24 @@ func TestRedirectService_SearchPaginate(t *testing.T) {
 		assert.Equal(t, types.DefaultOffset, result.Offset)
 	})
 
+	t.Run("success with order by", func(t *testing.T) {
+		ctrl, mockRedirectRepo, svc := setupRedirectServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		orderBy := []types.SortInput{{Column: "updatedAt", Direction: types.SortDESC}}
+		pagination := &types.PaginationInput{OrderBy: orderBy}
+
+		mockRedirectRepo.EXPECT().
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, orderBy).
+			Return([]model.Redirect{}, int64(0), nil)
+
+		result, err := svc.SearchPaginate(ctx, pagination, nil)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+	})
+
 	t.Run("error", func(t *testing.T) {
 		ctrl, mockRedirectRepo, svc := setupRedirectServiceTest(t)
 		defer ctrl.Finish()
@@ -305,7 +323,7 @@ func TestRedirectService_SearchPaginate(t *testing.T) {
 		expectedErr := errors.New("search error")
 
 		mockRedirectRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(nil, int64(0), expectedErr)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -316,6 +334,137 @@ func TestRedirectService_SearchPaginate(t *testing.T) {
 	})
 }
 
+func TestRedirectService_SearchCursor(t *testing.T) {
+	t.Run("first page with next cursor", func(t *testing.T) {
+		ctrl, mockRedirectRepo, svc := setupRedirectServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		limit := 2
+		expectedRedirects := []model.Redirect{{ID: 3}, {ID: 7}}
+
+		mockRedirectRepo.EXPECT().
+			SearchCursor(ctx, nil, int64(0), 2).
+			Return(expectedRedirects, true, nil)
+
+		result, err := svc.SearchCursor(ctx, &types.CursorInput{Limit: &limit}, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRedirects, result.Items)
+		assert.NotNil(t, result.NextCursor)
+		afterID, err := types.DecodeCursor(*result.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), afterID)
+	})
+
+	t.Run("last page without next cursor", func(t *testing.T) {
+		ctrl, mockRedirectRepo, svc := setupRedirectServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		after := types.EncodeCursor(7)
+
+		mockRedirectRepo.EXPECT().
+			SearchCursor(ctx, nil, int64(7), types.DefaultLimit).
+			Return([]model.Redirect{{ID: 8}}, false, nil)
+
+		result, err := svc.SearchCursor(ctx, &types.CursorInput{After: &after}, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 1)
+		assert.Nil(t, result.NextCursor)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		ctrl, _, svc := setupRedirectServiceTest(t)
+		defer ctrl.Finish()
+
+		after := "invalid"
+		result, err := svc.SearchCursor(context.Background(), &types.CursorInput{After: &after}, nil)
+
+		assert.ErrorIs(t, err, types.ErrInvalidCursor)
+		assert.Nil(t, result)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl, mockRedirectRepo, svc := setupRedirectServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		expectedErr := errors.New("search error")
+
+		mockRedirectRepo.EXPECT().
+			SearchCursor(ctx, nil, int64(0), types.DefaultLimit).
+			Return(nil, false, expectedErr)
+
+		result, err := svc.SearchCursor(ctx, nil, nil)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestRedirectService_FindSourceOverlaps(t *testing.T) {
+	t.Run("grouped by source", func(t *testing.T) {
+		ctrl, mockRedirectRepo, svc := setupRedirectServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		proj1 := &model.Project{NamespaceCode: "test-ns", ProjectCode: "proj1"}
+		proj2 := &model.Project{NamespaceCode: "test-ns", ProjectCode: "proj2"}
+		redirects := []model.Redirect{
+			{ID: 1, ProjectCode: "proj1", Project: proj1, Redirect: &types.Redirect{Source: "/a"}},
+			{ID: 2, ProjectCode: "proj2", Project: proj2, Redirect: &types.Redirect{Source: "/a"}},
+			{ID: 3, ProjectCode: "proj2", Project: proj2, Redirect: &types.Redirect{Source: "/a"}},
+			{ID: 4, ProjectCode: "proj1", Project: proj1, Redirect: &types.Redirect{Source: "/b"}},
+			{ID: 5, ProjectCode: "proj2", Project: proj2, Redirect: &types.Redirect{Source: "/b"}},
+		}
+
+		mockRedirectRepo.EXPECT().
+			FindSourceOverlaps(ctx, "test-ns", []string{"proj1", "proj2"}, model.DefaultRedirectOverlapLimit).
+			Return(redirects, nil)
+
+		result, err := svc.FindSourceOverlaps(ctx, "test-ns", []string{"proj1", "proj2"}, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.RedirectOverlap{
+			{Source: "/a", Projects: []model.Project{*proj1, *proj2}, Redirects: redirects[:3]},
+			{Source: "/b", Projects: []model.Project{*proj1, *proj2}, Redirects: redirects[3:]},
+		}, result)
+	})
+
+	t.Run("limit capped", func(t *testing.T) {
+		ctrl, mockRedirectRepo, svc := setupRedirectServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		mockRedirectRepo.EXPECT().
+			FindSourceOverlaps(ctx, "test-ns", nil, model.MaxRedirectOverlapLimit).
+			Return([]model.Redirect{}, nil)
+
+		result, err := svc.FindSourceOverlaps(ctx, "test-ns", nil, model.MaxRedirectOverlapLimit+1)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		ctrl, mockRedirectRepo, svc := setupRedirectServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		expectedErr := errors.New("db error")
+		mockRedirectRepo.EXPECT().
+			FindSourceOverlaps(ctx, "test-ns", nil, 10).
+			Return(nil, expectedErr)
+
+		result, err := svc.FindSourceOverlaps(ctx, "test-ns", nil, 10)
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, result)
+	})
+}
+
 func TestRedirectService_GetTx(t *testing.T) {
 	ctrl, mockRedirectRepo, svc := setupRedirectServiceTest(t)
 	defer ctrl.Finish()
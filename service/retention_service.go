@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/database"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/flectolab/flecto-manager/types"
+)
+
+// retentionSubject is recorded as the author of the changes made by the retention worker
+const retentionSubject = "retention"
+
+// RetentionCategory is a category of records purged once older than their days to keep
+type RetentionCategory string
+
+const (
+	RetentionCategoryImportJobs   RetentionCategory = "import_jobs"
+	RetentionCategoryTombstones   RetentionCategory = "tombstones"
+	RetentionCategoryOutboxEvents RetentionCategory = "outbox_events"
+)
+
+// RetentionCategories are the categories of records purged by the retention worker
+var RetentionCategories = []RetentionCategory{RetentionCategoryImportJobs, RetentionCategoryTombstones, RetentionCategoryOutboxEvents}
+
+// RetentionService purges the records older than the days to keep of their category, configured in the retention configuration
+type RetentionService interface {
+	Purge(ctx context.Context, now time.Time) (map[RetentionCategory]int64, error)
+	// PurgedRows returns the number of rows purged per category since the start of the application
+	PurgedRows() map[RetentionCategory]int64
+	ScheduledTask() types.ScheduledTask
+}
+
+type retentionService struct {
+	ctx  *appContext.Context
+	repo repository.RetentionRepository
+
+	mu     sync.Mutex
+	purged map[RetentionCategory]int64
+}
+
+func NewRetentionService(ctx *appContext.Context, repo repository.RetentionRepository) RetentionService {
+	purged := make(map[RetentionCategory]int64, len(RetentionCategories))
+	for _, category := range RetentionCategories {
+		purged[category] = 0
+	}
+	return &retentionService{
+		ctx:    ctx,
+		repo:   repo,
+		purged: purged,
+	}
+}
+
+// ScheduledTask purges the old records of all the shards, at the configured interval by default
+func (s *retentionService) ScheduledTask() types.ScheduledTask {
+	return types.ScheduledTask{
+		Name:     "retention",
+		Interval: s.ctx.Config.Retention.Interval,
+		Enabled:  true,
+		Run: func(ctx context.Context, now time.Time) error {
+			var errs []error
+			for _, ctx := range database.ShardContexts(s.repo.GetTx(ctx), types.WithSubject(ctx, retentionSubject)) {
+				_, err := s.Purge(ctx, now)
+				errs = append(errs, err)
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// Purge deletes the records older than the days to keep of their category, the categories kept forever being skipped.
+// It returns the number of rows deleted per category.
+func (s *retentionService) Purge(ctx context.Context, now time.Time) (map[RetentionCategory]int64, error) {
+	cfg := s.ctx.Config.Retention
+	purges := map[RetentionCategory]struct {
+		days  int
+		purge func(ctx context.Context, before time.Time, batchSize int) (int64, error)
+	}{
+		RetentionCategoryImportJobs:   {cfg.ImportJobs, s.repo.PurgeImportJobs},
+		RetentionCategoryTombstones:   {cfg.Tombstones, s.repo.PurgeTombstones},
+		RetentionCategoryOutboxEvents: {cfg.OutboxEvents, s.repo.PurgeOutboxEvents},
+	}
+
+	counts := make(map[RetentionCategory]int64)
+	var errs []error
+	for _, category := range RetentionCategories {
+		p := purges[category]
+		if p.days <= 0 {
+			continue
+		}
+		// A failed purge may have deleted some batches before failing, they are counted anyway
+		count, err := p.purge(ctx, now.AddDate(0, 0, -p.days), cfg.BatchSize)
+		s.addPurged(category, count)
+		if count > 0 {
+			counts[category] = count
+			s.ctx.Logger.InfoContext(ctx, "old records purged", "category", category, "days", p.days, "count", count)
+		}
+		if err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "retention purge failed", "category", category, "error", err)
+			errs = append(errs, err)
+		}
+	}
+
+	return counts, errors.Join(errs...)
+}
+
+func (s *retentionService) PurgedRows() map[RetentionCategory]int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	purged := make(map[RetentionCategory]int64, len(s.purged))
+	for category, count := range s.purged {
+		purged[category] = count
+	}
+	return purged
+}
+
+func (s *retentionService) addPurged(category RetentionCategory, count int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purged[category] += count
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupRetentionServiceTest(t *testing.T, importJobs, tombstones, outboxEvents int) (*gorm.DB, RetentionService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.ImportJob{}, &model.RedirectTombstone{}, &model.PageTombstone{}, &model.OutboxEvent{})
+	require.NoError(t, err)
+	ctx := appContext.TestContext(nil)
+	ctx.Config.Retention.BatchSize = 1
+	ctx.Config.Retention.ImportJobs = importJobs
+	ctx.Config.Retention.Tombstones = tombstones
+	ctx.Config.Retention.OutboxEvents = outboxEvents
+	return db, NewRetentionService(ctx, repository.NewRetentionRepository(db))
+}
+
+func createRetentionFixture(t *testing.T, db *gorm.DB, now time.Time) {
+	old := now.AddDate(0, 0, -10)
+	recent := now.AddDate(0, 0, -1)
+	jobs := []model.ImportJob{
+		{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusCompleted, FinishedAt: &old},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusFailed, FinishedAt: &old},
+		{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusCompleted, FinishedAt: &recent},
+	}
+	require.NoError(t, db.Create(&jobs).Error)
+	tombstones := []model.RedirectTombstone{
+		{NamespaceCode: "ns1", ProjectCode: "proj1", RedirectID: 1, DeletedAt: old,
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/old", Target: "/new", Status: commonTypes.RedirectStatusMovedPermanent}},
+	}
+	require.NoError(t, db.Create(&tombstones).Error)
+	outboxEvents := []model.OutboxEvent{
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusDelivered, ProcessedAt: &old},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusPending, CreatedAt: old},
+		{Topic: model.OutboxTopicInvalidation, Payload: "{}", Status: model.OutboxStatusFailed, ProcessedAt: &recent},
+	}
+	require.NoError(t, db.Create(&outboxEvents).Error)
+}
+
+func TestNewRetentionService(t *testing.T) {
+	_, svc := setupRetentionServiceTest(t, 30, 0, 0)
+
+	assert.NotNil(t, svc)
+	assert.Equal(t, map[RetentionCategory]int64{RetentionCategoryImportJobs: 0, RetentionCategoryTombstones: 0, RetentionCategoryOutboxEvents: 0}, svc.PurgedRows())
+}
+
+func TestRetentionService_Purge(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	t.Run("purges the old records of every category", func(t *testing.T) {
+		db, svc := setupRetentionServiceTest(t, 5, 5, 5)
+		createRetentionFixture(t, db, now)
+
+		counts, err := svc.Purge(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[RetentionCategory]int64{RetentionCategoryImportJobs: 2, RetentionCategoryTombstones: 1, RetentionCategoryOutboxEvents: 1}, counts)
+		var jobCount, tombstoneCount, outboxEventCount int64
+		db.Model(&model.ImportJob{}).Count(&jobCount)
+		db.Model(&model.RedirectTombstone{}).Count(&tombstoneCount)
+		db.Model(&model.OutboxEvent{}).Count(&outboxEventCount)
+		assert.Equal(t, int64(1), jobCount)
+		assert.Zero(t, tombstoneCount)
+		assert.Equal(t, int64(2), outboxEventCount, "the pending and recent events are kept")
+	})
+
+	t.Run("keeps the categories without retention", func(t *testing.T) {
+		db, svc := setupRetentionServiceTest(t, 5, 0, 0)
+		createRetentionFixture(t, db, now)
+
+		counts, err := svc.Purge(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[RetentionCategory]int64{RetentionCategoryImportJobs: 2}, counts)
+		var tombstoneCount int64
+		db.Model(&model.RedirectTombstone{}).Count(&tombstoneCount)
+		assert.Equal(t, int64(1), tombstoneCount)
+	})
+
+	t.Run("keeps the records within retention", func(t *testing.T) {
+		db, svc := setupRetentionServiceTest(t, 30, 30, 30)
+		createRetentionFixture(t, db, now)
+
+		counts, err := svc.Purge(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Empty(t, counts)
+	})
+
+	t.Run("accumulates the purged rows", func(t *testing.T) {
+		db, svc := setupRetentionServiceTest(t, 5, 5, 5)
+		createRetentionFixture(t, db, now)
+		_, err := svc.Purge(context.Background(), now)
+		require.NoError(t, err)
+		old := now.AddDate(0, 0, -10)
+		require.NoError(t, db.Create(&model.ImportJob{NamespaceCode: "ns1", ProjectCode: "proj1", Status: model.ImportJobStatusFailed, FinishedAt: &old}).Error)
+
+		_, err = svc.Purge(context.Background(), now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[RetentionCategory]int64{RetentionCategoryImportJobs: 3, RetentionCategoryTombstones: 1, RetentionCategoryOutboxEvents: 1}, svc.PurgedRows())
+	})
+
+	t.Run("error keeps purging the other categories", func(t *testing.T) {
+		db, svc := setupRetentionServiceTest(t, 5, 5, 5)
+		createRetentionFixture(t, db, now)
+		require.NoError(t, db.Migrator().DropTable(&model.ImportJob{}))
+
+		counts, err := svc.Purge(context.Background(), now)
+
+		assert.Error(t, err)
+		assert.Equal(t, map[RetentionCategory]int64{RetentionCategoryTombstones: 1, RetentionCategoryOutboxEvents: 1}, counts)
+	})
+}
@@ -3,20 +3,24 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/repository"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrRoleNotFound      = errors.New("role not found")
-	ErrRoleAlreadyExists = errors.New("role already exists")
-	ErrUserNotInRole     = errors.New("user is not in role")
-	ErrUserAlreadyInRole = errors.New("user is already in role")
+	ErrRoleNotFound      = flectoErrors.New(flectoErrors.CodeNotFound, "role not found")
+	ErrRoleAlreadyExists = flectoErrors.New(flectoErrors.CodeAlreadyExists, "role already exists")
+	ErrUserNotInRole     = flectoErrors.New(flectoErrors.CodeNotFound, "user is not in role")
+	ErrUserAlreadyInRole = flectoErrors.New(flectoErrors.CodeAlreadyExists, "user is already in role")
+	ErrRoleCycle         = flectoErrors.New(flectoErrors.CodeInvalidRequest, "role inheritance cycle detected")
 )
 
 type RoleService interface {
@@ -46,23 +50,45 @@ type RoleService interface {
 	GetPermissionsByTokenName(ctx context.Context, tokenName string) (*model.SubjectPermissions, error)
 	UpdateRolePermissions(ctx context.Context, roleID int64, permissions *model.SubjectPermissions) error
 	UpdateUserRoles(ctx context.Context, userID int64, roleCodes []string) error
+	UpdateRoleUsers(ctx context.Context, roleID int64, userIDs []int64) error
+
+	// Role inheritance
+	GetParentRoles(ctx context.Context, roleID int64) ([]model.Role, error)
+	UpdateRoleParents(ctx context.Context, roleID int64, parentCodes []string) error
 }
 
 type roleService struct {
-	ctx      *appContext.Context
-	repo     repository.RoleRepository
-	userRepo repository.UserRepository
+	ctx        *appContext.Context
+	repo       repository.RoleRepository
+	userRepo   repository.UserRepository
+	memberRepo repository.ProjectMemberRepository
+	bus        invalidation.Bus
+	cache      *permissionCache
 }
 
+// NewRoleService returns a role service caching the permissions for the TTL of the permission cache
+// configuration, the cache being cleared on the permission changes of any replica sent on the bus. The permissions of
+// the users include the ones of the roles of their groups and of their project memberships.
 func NewRoleService(
 	ctx *appContext.Context,
 	repo repository.RoleRepository,
 	userRepo repository.UserRepository,
+	memberRepo repository.ProjectMemberRepository,
+	bus invalidation.Bus,
 ) RoleService {
+	cache := newPermissionCache(ctx.Config.Auth.PermissionCache.TTL)
+	bus.Subscribe(func(event invalidation.Event) {
+		if event.Type == invalidation.EventTypePermissionsChanged || event.Type == invalidation.EventTypeReset {
+			cache.clear()
+		}
+	})
 	return &roleService{
-		ctx:      ctx,
-		repo:     repo,
-		userRepo: userRepo,
+		ctx:        ctx,
+		repo:       repo,
+		userRepo:   userRepo,
+		memberRepo: memberRepo,
+		bus:        bus,
+		cache:      cache,
 	}
 }
 
@@ -90,11 +116,11 @@ func (s *roleService) Create(ctx context.Context, input *model.Role) (*model.Rol
 	}
 
 	if err = s.repo.Create(ctx, input); err != nil {
-		s.ctx.Logger.Error("failed to create role", "code", input.Code, "type", input.Type, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to create role", "code", input.Code, "type", input.Type, "error", err)
 		return nil, err
 	}
 
-	s.ctx.Logger.Info("role created", "code", input.Code, "type", input.Type, "id", input.ID)
+	s.ctx.Logger.InfoContext(ctx, "role created", "code", input.Code, "type", input.Type, "id", input.ID)
 	return input, nil
 }
 
@@ -116,6 +142,7 @@ func (s *roleService) Update(ctx context.Context, id int64, input model.Role) (*
 	if err = s.repo.Update(ctx, role); err != nil {
 		return nil, err
 	}
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
 
 	return role, nil
 }
@@ -130,11 +157,12 @@ func (s *roleService) Delete(ctx context.Context, id int64) (bool, error) {
 	}
 
 	if err := s.repo.Delete(ctx, id); err != nil {
-		s.ctx.Logger.Error("failed to delete role", "code", role.Code, "id", id, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete role", "code", role.Code, "id", id, "error", err)
 		return false, err
 	}
 
-	s.ctx.Logger.Info("role deleted", "code", role.Code, "id", id)
+	s.ctx.Logger.InfoContext(ctx, "role deleted", "code", role.Code, "id", id)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
 	return true, nil
 }
 
@@ -169,7 +197,7 @@ func (s *roleService) GetAllByType(ctx context.Context, roleType model.RoleType)
 }
 
 func (s *roleService) SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.RoleList, error) {
-	roles, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	roles, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
@@ -202,11 +230,12 @@ func (s *roleService) AddUserToRole(ctx context.Context, userID, roleID int64) e
 	}
 
 	if err = s.repo.AddUserToRole(ctx, userID, roleID); err != nil {
-		s.ctx.Logger.Error("failed to add user to role", "userID", userID, "roleCode", role.Code, "roleID", roleID, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to add user to role", "userID", userID, "roleCode", role.Code, "roleID", roleID, "error", err)
 		return err
 	}
 
-	s.ctx.Logger.Info("user added to role", "userID", userID, "roleCode", role.Code, "roleID", roleID)
+	s.ctx.Logger.InfoContext(ctx, "user added to role", "userID", userID, "roleCode", role.Code, "roleID", roleID)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
 	return nil
 }
 
@@ -221,11 +250,12 @@ func (s *roleService) RemoveUserFromRole(ctx context.Context, userID, roleID int
 	}
 
 	if err = s.repo.RemoveUserFromRole(ctx, userID, roleID); err != nil {
-		s.ctx.Logger.Error("failed to remove user from role", "userID", userID, "roleID", roleID, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to remove user from role", "userID", userID, "roleID", roleID, "error", err)
 		return err
 	}
 
-	s.ctx.Logger.Info("user removed from role", "userID", userID, "roleID", roleID)
+	s.ctx.Logger.InfoContext(ctx, "user removed from role", "userID", userID, "roleID", roleID)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
 	return nil
 }
 
@@ -284,6 +314,24 @@ func (s *roleService) GetUsersNotInRole(ctx context.Context, roleCode string, se
 }
 
 func (s *roleService) GetPermissionsByRoleCode(ctx context.Context, code string) (*model.SubjectPermissions, error) {
+	return s.cache.cachedPermissions(permissionCacheRole+code, func() (*model.SubjectPermissions, error) {
+		return s.loadPermissionsByRoleCode(ctx, code)
+	})
+}
+
+func (s *roleService) GetPermissionsByUsername(ctx context.Context, username string) (*model.SubjectPermissions, error) {
+	return s.cache.cachedPermissions(permissionCacheUser+username, func() (*model.SubjectPermissions, error) {
+		return s.loadPermissionsByUsername(ctx, username)
+	})
+}
+
+func (s *roleService) GetPermissionsByTokenName(ctx context.Context, tokenName string) (*model.SubjectPermissions, error) {
+	return s.cache.cachedPermissions(permissionCacheToken+tokenName, func() (*model.SubjectPermissions, error) {
+		return s.loadPermissionsByTokenName(ctx, tokenName)
+	})
+}
+
+func (s *roleService) loadPermissionsByRoleCode(ctx context.Context, code string) (*model.SubjectPermissions, error) {
 	role, err := s.repo.FindByCodeAndType(ctx, code, model.RoleTypeRole)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -292,13 +340,15 @@ func (s *roleService) GetPermissionsByRoleCode(ctx context.Context, code string)
 		return nil, err
 	}
 
-	return &model.SubjectPermissions{
-		Resources: role.Resources,
-		Admin:     role.Admin,
-	}, nil
+	roles, err := s.resolveInheritedRoles(ctx, []model.Role{*role})
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeRolePermissions(roles), nil
 }
 
-func (s *roleService) GetPermissionsByUsername(ctx context.Context, username string) (*model.SubjectPermissions, error) {
+func (s *roleService) loadPermissionsByUsername(ctx context.Context, username string) (*model.SubjectPermissions, error) {
 	user, err := s.userRepo.FindByUsername(ctx, username)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -312,12 +362,72 @@ func (s *roleService) GetPermissionsByUsername(ctx context.Context, username str
 		return nil, err
 	}
 
-	if len(roles) == 0 {
+	groupRoles, err := s.repo.GetUserGroupRoles(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	roles = append(roles, groupRoles...)
+
+	members, err := s.memberRepo.FindByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(roles) == 0 && len(members) == 0 {
 		return &model.SubjectPermissions{
 			Resources: []model.ResourcePermission{},
 			Admin:     []model.AdminPermission{},
 		}, nil
 	}
+
+	roles, err = s.resolveInheritedRoles(ctx, roles)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := mergeRolePermissions(roles)
+	for _, member := range members {
+		permissions.Resources = append(permissions.Resources, member.Permissions()...)
+	}
+	permissions.Resources = deduplicateResourcePermissions(permissions.Resources)
+	return permissions, nil
+}
+
+// resolveInheritedRoles returns the given roles followed by all their ancestors.
+// Each role is visited once, so an inheritance cycle cannot loop forever.
+func (s *roleService) resolveInheritedRoles(ctx context.Context, roles []model.Role) ([]model.Role, error) {
+	visited := make(map[int64]struct{}, len(roles))
+	result := make([]model.Role, 0, len(roles))
+	pending := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		if _, ok := visited[role.ID]; ok {
+			continue
+		}
+		visited[role.ID] = struct{}{}
+		result = append(result, role)
+		pending = append(pending, role.ID)
+	}
+
+	for len(pending) > 0 {
+		parents, err := s.repo.GetParentRoles(ctx, pending)
+		if err != nil {
+			return nil, err
+		}
+		pending = pending[:0]
+		for _, parent := range parents {
+			if _, ok := visited[parent.ID]; ok {
+				continue
+			}
+			visited[parent.ID] = struct{}{}
+			result = append(result, parent)
+			pending = append(pending, parent.ID)
+		}
+	}
+
+	return result, nil
+}
+
+func mergeRolePermissions(roles []model.Role) *model.SubjectPermissions {
 	resources := make([]model.ResourcePermission, 0)
 	admin := make([]model.AdminPermission, 0)
 	for _, role := range roles {
@@ -328,10 +438,10 @@ func (s *roleService) GetPermissionsByUsername(ctx context.Context, username str
 	return &model.SubjectPermissions{
 		Resources: deduplicateResourcePermissions(resources),
 		Admin:     deduplicateAdminPermissions(admin),
-	}, nil
+	}
 }
 
-func (s *roleService) GetPermissionsByTokenName(ctx context.Context, tokenName string) (*model.SubjectPermissions, error) {
+func (s *roleService) loadPermissionsByTokenName(ctx context.Context, tokenName string) (*model.SubjectPermissions, error) {
 	roleCode := "token_" + tokenName
 	role, err := s.repo.FindByCodeAndType(ctx, roleCode, model.RoleTypeToken)
 	if err != nil {
@@ -355,7 +465,7 @@ func deduplicateResourcePermissions(perms []model.ResourcePermission) []model.Re
 	result := make([]model.ResourcePermission, 0, len(perms))
 
 	for _, p := range perms {
-		key := p.Namespace + "|" + p.Project + "|" + string(p.Resource) + "|" + string(p.Action)
+		key := p.Namespace + "|" + p.Project + "|" + string(p.Resource) + "|" + string(p.Action) + "|" + string(model.EffectOrDefault(&p.Effect))
 		if _, exists := seen[key]; !exists {
 			seen[key] = struct{}{}
 			result = append(result, p)
@@ -370,7 +480,7 @@ func deduplicateAdminPermissions(perms []model.AdminPermission) []model.AdminPer
 	result := make([]model.AdminPermission, 0, len(perms))
 
 	for _, p := range perms {
-		key := string(p.Section) + "|" + string(p.Action)
+		key := model.NamespaceOrAll(&p.Namespace) + "|" + string(p.Section) + "|" + string(p.Action) + "|" + string(model.EffectOrDefault(&p.Effect))
 		if _, exists := seen[key]; !exists {
 			seen[key] = struct{}{}
 			result = append(result, p)
@@ -410,6 +520,7 @@ func (s *roleService) UpdateRolePermissions(ctx context.Context, roleID int64, p
 					Project:   r.Project,
 					Resource:  r.Resource,
 					Action:    r.Action,
+					Effect:    r.Effect,
 				}
 			}
 			if err = tx.Create(&resourcePerms).Error; err != nil {
@@ -422,9 +533,11 @@ func (s *roleService) UpdateRolePermissions(ctx context.Context, roleID int64, p
 			adminPerms := make([]model.AdminPermission, len(permissions.Admin))
 			for i, a := range permissions.Admin {
 				adminPerms[i] = model.AdminPermission{
-					RoleID:  roleID,
-					Section: a.Section,
-					Action:  a.Action,
+					RoleID:    roleID,
+					Namespace: a.Namespace,
+					Section:   a.Section,
+					Action:    a.Action,
+					Effect:    a.Effect,
 				}
 			}
 			if err = tx.Create(&adminPerms).Error; err != nil {
@@ -440,11 +553,12 @@ func (s *roleService) UpdateRolePermissions(ctx context.Context, roleID int64, p
 		return nil
 	})
 	if err != nil {
-		s.ctx.Logger.Error("failed to update role permissions", "roleCode", role.Code, "roleID", roleID, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to update role permissions", "roleCode", role.Code, "roleID", roleID, "error", err)
 		return err
 	}
 
-	s.ctx.Logger.Info("role permissions updated", "roleCode", role.Code, "roleID", roleID, "resourcePermissions", len(permissions.Resources), "adminPermissions", len(permissions.Admin))
+	s.ctx.Logger.InfoContext(ctx, "role permissions updated", "roleCode", role.Code, "roleID", roleID, "resourcePermissions", len(permissions.Resources), "adminPermissions", len(permissions.Admin))
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
 	return nil
 }
 
@@ -471,7 +585,7 @@ func (s *roleService) UpdateUserRoles(ctx context.Context, userID int64, roleCod
 		roleIDs = append(roleIDs, role.ID)
 	}
 
-	return s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+	err = s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
 		// Delete all existing user-role associations for this user
 
 		if err = tx.Where("user_id = ? AND role_id IN (?)",
@@ -501,10 +615,144 @@ func (s *roleService) UpdateUserRoles(ctx context.Context, userID int64, roleCod
 		return nil
 	})
 	if err != nil {
-		s.ctx.Logger.Error("failed to update user roles", "userID", userID, "roleCodes", roleCodes, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to update user roles", "userID", userID, "roleCodes", roleCodes, "error", err)
+		return err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "user roles updated", "userID", userID, "roleCodes", roleCodes)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
+	return nil
+}
+
+// UpdateRoleUsers replaces the users of a named role, used by the provisioning of the groups of an identity provider
+func (s *roleService) UpdateRoleUsers(ctx context.Context, roleID int64, userIDs []int64) error {
+	role, err := s.repo.FindByID(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	if role.Type != model.RoleTypeRole {
+		return ErrRoleNotFound
+	}
+
+	userIDs = slices.Compact(slices.Sorted(slices.Values(userIDs)))
+	err = s.repo.GetTx(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(userIDs) > 0 {
+			var count int64
+			if err = tx.Model(&model.User{}).Where("id IN ?", userIDs).Count(&count).Error; err != nil {
+				return err
+			}
+			if count != int64(len(userIDs)) {
+				return ErrUserNotFound
+			}
+		}
+
+		if err = tx.Where("role_id = ?", roleID).Delete(&model.UserRole{}).Error; err != nil {
+			return err
+		}
+
+		if len(userIDs) > 0 {
+			userRoles := make([]model.UserRole, len(userIDs))
+			for i, userID := range userIDs {
+				userRoles[i] = model.UserRole{
+					UserID: userID,
+					RoleID: roleID,
+				}
+			}
+			if err = tx.Create(&userRoles).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, ErrUserNotFound) {
+			s.ctx.Logger.ErrorContext(ctx, "failed to update role users", "roleCode", role.Code, "roleID", roleID, "error", err)
+		}
+		return err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "role users updated", "roleCode", role.Code, "roleID", roleID, "users", len(userIDs))
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
+	return nil
+}
+
+func (s *roleService) GetParentRoles(ctx context.Context, roleID int64) ([]model.Role, error) {
+	return s.repo.GetParentRoles(ctx, []int64{roleID})
+}
+
+func (s *roleService) UpdateRoleParents(ctx context.Context, roleID int64, parentCodes []string) error {
+	role, err := s.repo.FindByID(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleNotFound
+		}
 		return err
 	}
 
-	s.ctx.Logger.Info("user roles updated", "userID", userID, "roleCodes", roleCodes)
+	// Resolve parent codes to IDs (only named roles can be inherited)
+	parentIDs := make([]int64, 0, len(parentCodes))
+	seen := make(map[int64]struct{}, len(parentCodes))
+	for _, code := range parentCodes {
+		parent, err := s.repo.FindByCodeAndType(ctx, code, model.RoleTypeRole)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRoleNotFound
+			}
+			return err
+		}
+		if _, ok := seen[parent.ID]; ok {
+			continue
+		}
+		seen[parent.ID] = struct{}{}
+		parentIDs = append(parentIDs, parent.ID)
+	}
+
+	links, err := s.repo.FindAllRoleParents(ctx)
+	if err != nil {
+		return err
+	}
+	if hasRoleCycle(roleID, parentIDs, links) {
+		return ErrRoleCycle
+	}
+
+	if err = s.repo.SetRoleParents(ctx, roleID, parentIDs); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to update role parents", "roleCode", role.Code, "roleID", roleID, "error", err)
+		return err
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "role parents updated", "roleCode", role.Code, "roleID", roleID, "parents", parentCodes)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
 	return nil
 }
+
+// hasRoleCycle reports whether giving roleID the parents parentIDs would make
+// roleID one of its own ancestors, using the existing links for the other roles.
+func hasRoleCycle(roleID int64, parentIDs []int64, links []model.RoleParent) bool {
+	graph := make(map[int64][]int64)
+	for _, link := range links {
+		if link.RoleID == roleID {
+			continue
+		}
+		graph[link.RoleID] = append(graph[link.RoleID], link.ParentID)
+	}
+
+	visited := make(map[int64]struct{})
+	pending := append([]int64{}, parentIDs...)
+	for len(pending) > 0 {
+		current := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if current == roleID {
+			return true
+		}
+		if _, ok := visited[current]; ok {
+			continue
+		}
+		visited[current] = struct{}{}
+		pending = append(pending, graph[current]...)
+	}
+	return false
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/invalidation"
 	mockFlectoRepository "github.com/flectolab/flecto-manager/mocks/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/repository"
@@ -18,19 +19,21 @@ import (
 )
 
 type roleServiceMocks struct {
-	ctrl     *gomock.Controller
-	roleRepo *mockFlectoRepository.MockRoleRepository
-	userRepo *mockFlectoRepository.MockUserRepository
+	ctrl       *gomock.Controller
+	roleRepo   *mockFlectoRepository.MockRoleRepository
+	userRepo   *mockFlectoRepository.MockUserRepository
+	memberRepo *mockFlectoRepository.MockProjectMemberRepository
 }
 
 func setupRoleServiceTest(t *testing.T) (*roleServiceMocks, RoleService) {
 	ctrl := gomock.NewController(t)
 	mocks := &roleServiceMocks{
-		ctrl:     ctrl,
-		roleRepo: mockFlectoRepository.NewMockRoleRepository(ctrl),
-		userRepo: mockFlectoRepository.NewMockUserRepository(ctrl),
+		ctrl:       ctrl,
+		roleRepo:   mockFlectoRepository.NewMockRoleRepository(ctrl),
+		userRepo:   mockFlectoRepository.NewMockUserRepository(ctrl),
+		memberRepo: mockFlectoRepository.NewMockProjectMemberRepository(ctrl),
 	}
-	svc := NewRoleService(appContext.TestContext(nil), mocks.roleRepo, mocks.userRepo)
+	svc := NewRoleService(appContext.TestContext(nil), mocks.roleRepo, mocks.userRepo, mocks.memberRepo, invalidation.NewMemoryBus())
 	return mocks, svc
 }
 
@@ -561,7 +564,7 @@ func TestRoleService_SearchPaginate(t *testing.T) {
 		}
 
 		mocks.roleRepo.EXPECT().
-			SearchPaginate(ctx, nil, 10, 5).
+			SearchPaginate(ctx, nil, 10, 5, nil).
 			Return(expectedRoles, int64(20), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -583,7 +586,7 @@ func TestRoleService_SearchPaginate(t *testing.T) {
 		expectedErr := errors.New("search error")
 
 		mocks.roleRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(nil, int64(0), expectedErr)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -1078,6 +1081,9 @@ func TestRoleService_GetPermissionsByRoleCode(t *testing.T) {
 		mocks.roleRepo.EXPECT().
 			FindByCodeAndType(ctx, "admin", model.RoleTypeRole).
 			Return(role, nil)
+		mocks.roleRepo.EXPECT().
+			GetParentRoles(ctx, []int64{1}).
+			Return([]model.Role{}, nil)
 
 		result, err := svc.GetPermissionsByRoleCode(ctx, "admin")
 
@@ -1087,6 +1093,67 @@ func TestRoleService_GetPermissionsByRoleCode(t *testing.T) {
 		assert.Len(t, result.Admin, 1)
 	})
 
+	t.Run("success with inherited roles and cycle", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+		role := &model.Role{
+			ID:   1,
+			Code: "editor",
+			Resources: []model.ResourcePermission{
+				{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionWrite},
+			},
+		}
+		parent := model.Role{
+			ID:   2,
+			Code: "reader",
+			Resources: []model.ResourcePermission{
+				{Namespace: "*", Project: "*", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+			},
+			Admin: []model.AdminPermission{
+				{Section: model.AdminSectionProjects, Action: model.ActionRead},
+			},
+		}
+
+		mocks.roleRepo.EXPECT().
+			FindByCodeAndType(ctx, "editor", model.RoleTypeRole).
+			Return(role, nil)
+		mocks.roleRepo.EXPECT().
+			GetParentRoles(ctx, []int64{1}).
+			Return([]model.Role{parent}, nil)
+		// reader inherits back from editor: already visited, resolution stops
+		mocks.roleRepo.EXPECT().
+			GetParentRoles(ctx, []int64{2}).
+			Return([]model.Role{*role}, nil)
+
+		result, err := svc.GetPermissionsByRoleCode(ctx, "editor")
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Resources, 2)
+		assert.Len(t, result.Admin, 1)
+	})
+
+	t.Run("error from GetParentRoles", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+		expectedErr := errors.New("database error")
+
+		mocks.roleRepo.EXPECT().
+			FindByCodeAndType(ctx, "admin", model.RoleTypeRole).
+			Return(&model.Role{ID: 1, Code: "admin"}, nil)
+		mocks.roleRepo.EXPECT().
+			GetParentRoles(ctx, []int64{1}).
+			Return(nil, expectedErr)
+
+		result, err := svc.GetPermissionsByRoleCode(ctx, "admin")
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, result)
+	})
+
 	t.Run("role not found", func(t *testing.T) {
 		mocks, svc := setupRoleServiceTest(t)
 		defer mocks.ctrl.Finish()
@@ -1123,6 +1190,55 @@ func TestRoleService_GetPermissionsByRoleCode(t *testing.T) {
 	})
 }
 
+func TestRoleService_PermissionCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	roleRepo := mockFlectoRepository.NewMockRoleRepository(ctrl)
+	userRepo := mockFlectoRepository.NewMockUserRepository(ctrl)
+	bus := invalidation.NewMemoryBus()
+	svc := NewRoleService(appContext.TestContext(nil), roleRepo, userRepo, mockFlectoRepository.NewMockProjectMemberRepository(ctrl), bus)
+	ctx := context.Background()
+	role := &model.Role{ID: 1, Code: "editor", Type: model.RoleTypeRole}
+
+	roleRepo.EXPECT().FindByCodeAndType(ctx, "editor", model.RoleTypeRole).Return(role, nil).Times(3)
+	roleRepo.EXPECT().GetParentRoles(ctx, []int64{1}).Return(nil, nil).Times(3)
+
+	_, err := svc.GetPermissionsByRoleCode(ctx, "editor")
+	assert.NoError(t, err)
+	_, err = svc.GetPermissionsByRoleCode(ctx, "editor")
+	assert.NoError(t, err)
+
+	t.Run("cleared on role permission updates", func(t *testing.T) {
+		roleRepo.EXPECT().FindByID(ctx, int64(1)).Return(role, nil)
+		roleRepo.EXPECT().FindAllRoleParents(ctx).Return(nil, nil)
+		roleRepo.EXPECT().SetRoleParents(ctx, int64(1), []int64{}).Return(nil)
+		assert.NoError(t, svc.UpdateRoleParents(ctx, 1, nil))
+
+		_, err = svc.GetPermissionsByRoleCode(ctx, "editor")
+		assert.NoError(t, err)
+	})
+
+	t.Run("cleared on events of other replicas", func(t *testing.T) {
+		assert.NoError(t, bus.Publish(ctx, invalidation.Event{Type: invalidation.EventTypePermissionsChanged, Origin: "other"}))
+
+		_, err = svc.GetPermissionsByRoleCode(ctx, "editor")
+		assert.NoError(t, err)
+	})
+
+	t.Run("disabled with a zero TTL", func(t *testing.T) {
+		appCtx := appContext.TestContext(nil)
+		appCtx.Config.Auth.PermissionCache.TTL = 0
+		svc := NewRoleService(appCtx, roleRepo, userRepo, mockFlectoRepository.NewMockProjectMemberRepository(ctrl), invalidation.NewMemoryBus())
+		viewer := &model.Role{ID: 2, Code: "viewer", Type: model.RoleTypeRole}
+		roleRepo.EXPECT().FindByCodeAndType(ctx, "viewer", model.RoleTypeRole).Return(viewer, nil).Times(2)
+		roleRepo.EXPECT().GetParentRoles(ctx, []int64{2}).Return(nil, nil).Times(2)
+
+		_, err := svc.GetPermissionsByRoleCode(ctx, "viewer")
+		assert.NoError(t, err)
+		_, err = svc.GetPermissionsByRoleCode(ctx, "viewer")
+		assert.NoError(t, err)
+	})
+}
+
 func TestRoleService_GetPermissionsByUsername(t *testing.T) {
 	t.Run("success with multiple roles and deduplication", func(t *testing.T) {
 		mocks, svc := setupRoleServiceTest(t)
@@ -1163,6 +1279,15 @@ func TestRoleService_GetPermissionsByUsername(t *testing.T) {
 		mocks.roleRepo.EXPECT().
 			GetUserRoles(ctx, int64(1)).
 			Return(roles, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserGroupRoles(ctx, int64(1)).
+			Return([]model.Role{}, nil)
+		mocks.memberRepo.EXPECT().
+			FindByUser(ctx, int64(1)).
+			Return([]model.ProjectMember{}, nil)
+		mocks.roleRepo.EXPECT().
+			GetParentRoles(ctx, []int64{1, 2}).
+			Return([]model.Role{}, nil)
 
 		result, err := svc.GetPermissionsByUsername(ctx, "testuser")
 
@@ -1172,6 +1297,125 @@ func TestRoleService_GetPermissionsByUsername(t *testing.T) {
 		assert.Len(t, result.Admin, 2)     // Deduplicated
 	})
 
+	t.Run("success with project memberships", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+		user := &model.User{ID: 1, Username: "member"}
+
+		mocks.userRepo.EXPECT().
+			FindByUsername(ctx, "member").
+			Return(user, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserRoles(ctx, int64(1)).
+			Return([]model.Role{}, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserGroupRoles(ctx, int64(1)).
+			Return([]model.Role{}, nil)
+		mocks.memberRepo.EXPECT().
+			FindByUser(ctx, int64(1)).
+			Return([]model.ProjectMember{
+				{NamespaceCode: "ns1", ProjectCode: "proj1", UserID: 1, Role: model.ProjectMemberRoleOwner},
+				{NamespaceCode: "ns1", ProjectCode: "proj2", UserID: 1, Role: model.ProjectMemberRoleViewer},
+			}, nil)
+
+		result, err := svc.GetPermissionsByUsername(ctx, "member")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []model.ResourcePermission{
+			{Namespace: "ns1", Project: "proj1", Resource: model.ResourceTypeAll, Action: model.ActionAll, Effect: model.PermissionEffectAllow},
+			{Namespace: "ns1", Project: "proj2", Resource: model.ResourceTypeAll, Action: model.ActionRead, Effect: model.PermissionEffectAllow},
+		}, result.Resources)
+		assert.Empty(t, result.Admin)
+	})
+
+	t.Run("success with roles through groups", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+		user := &model.User{ID: 1, Username: "testuser"}
+
+		mocks.userRepo.EXPECT().
+			FindByUsername(ctx, "testuser").
+			Return(user, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserRoles(ctx, int64(1)).
+			Return([]model.Role{
+				{ID: 1, Code: "role1", Resources: []model.ResourcePermission{{Namespace: "ns1", Project: "proj1", Action: model.ActionRead, RoleID: 1}}},
+			}, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserGroupRoles(ctx, int64(1)).
+			Return([]model.Role{
+				{ID: 1, Code: "role1", Resources: []model.ResourcePermission{{Namespace: "ns1", Project: "proj1", Action: model.ActionRead, RoleID: 1}}},
+				{ID: 2, Code: "role2", Admin: []model.AdminPermission{{Section: model.AdminSectionUsers, Action: model.ActionRead, RoleID: 2}}},
+			}, nil)
+		mocks.memberRepo.EXPECT().
+			FindByUser(ctx, int64(1)).
+			Return([]model.ProjectMember{}, nil)
+		mocks.roleRepo.EXPECT().
+			GetParentRoles(ctx, []int64{1, 2}).
+			Return([]model.Role{}, nil)
+
+		result, err := svc.GetPermissionsByUsername(ctx, "testuser")
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Resources, 1)
+		assert.Len(t, result.Admin, 1)
+	})
+
+	t.Run("error from GetUserGroupRoles", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+		user := &model.User{ID: 1, Username: "testuser"}
+		expectedErr := errors.New("group roles fetch error")
+
+		mocks.userRepo.EXPECT().
+			FindByUsername(ctx, "testuser").
+			Return(user, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserRoles(ctx, int64(1)).
+			Return([]model.Role{}, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserGroupRoles(ctx, int64(1)).
+			Return(nil, expectedErr)
+
+		result, err := svc.GetPermissionsByUsername(ctx, "testuser")
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, result)
+	})
+
+	t.Run("error from FindByUser", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+		user := &model.User{ID: 1, Username: "testuser"}
+		expectedErr := errors.New("members fetch error")
+
+		mocks.userRepo.EXPECT().
+			FindByUsername(ctx, "testuser").
+			Return(user, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserRoles(ctx, int64(1)).
+			Return([]model.Role{}, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserGroupRoles(ctx, int64(1)).
+			Return([]model.Role{}, nil)
+		mocks.memberRepo.EXPECT().
+			FindByUser(ctx, int64(1)).
+			Return(nil, expectedErr)
+
+		result, err := svc.GetPermissionsByUsername(ctx, "testuser")
+
+		assert.Equal(t, expectedErr, err)
+		assert.Nil(t, result)
+	})
+
 	t.Run("user not found", func(t *testing.T) {
 		mocks, svc := setupRoleServiceTest(t)
 		defer mocks.ctrl.Finish()
@@ -1221,6 +1465,12 @@ func TestRoleService_GetPermissionsByUsername(t *testing.T) {
 		mocks.roleRepo.EXPECT().
 			GetUserRoles(ctx, int64(1)).
 			Return([]model.Role{}, nil)
+		mocks.roleRepo.EXPECT().
+			GetUserGroupRoles(ctx, int64(1)).
+			Return([]model.Role{}, nil)
+		mocks.memberRepo.EXPECT().
+			FindByUser(ctx, int64(1)).
+			Return([]model.ProjectMember{}, nil)
 
 		result, err := svc.GetPermissionsByUsername(ctx, "noroles")
 
@@ -1254,6 +1504,129 @@ func TestRoleService_GetPermissionsByUsername(t *testing.T) {
 	})
 }
 
+func TestRoleService_GetParentRoles(t *testing.T) {
+	mocks, svc := setupRoleServiceTest(t)
+	defer mocks.ctrl.Finish()
+
+	ctx := context.Background()
+	parents := []model.Role{{ID: 2, Code: "reader"}}
+
+	mocks.roleRepo.EXPECT().
+		GetParentRoles(ctx, []int64{1}).
+		Return(parents, nil)
+
+	result, err := svc.GetParentRoles(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, parents, result)
+}
+
+func TestRoleService_UpdateRoleParents(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+
+		mocks.roleRepo.EXPECT().FindByID(ctx, int64(1)).Return(&model.Role{ID: 1, Code: "editor"}, nil)
+		mocks.roleRepo.EXPECT().FindByCodeAndType(ctx, "reader", model.RoleTypeRole).Return(&model.Role{ID: 2, Code: "reader"}, nil).Times(2)
+		mocks.roleRepo.EXPECT().FindAllRoleParents(ctx).Return([]model.RoleParent{{RoleID: 2, ParentID: 3}}, nil)
+		mocks.roleRepo.EXPECT().SetRoleParents(ctx, int64(1), []int64{2}).Return(nil)
+
+		err := svc.UpdateRoleParents(ctx, 1, []string{"reader", "reader"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("clear parents", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+
+		mocks.roleRepo.EXPECT().FindByID(ctx, int64(1)).Return(&model.Role{ID: 1, Code: "editor"}, nil)
+		mocks.roleRepo.EXPECT().FindAllRoleParents(ctx).Return([]model.RoleParent{{RoleID: 1, ParentID: 2}}, nil)
+		mocks.roleRepo.EXPECT().SetRoleParents(ctx, int64(1), []int64{}).Return(nil)
+
+		err := svc.UpdateRoleParents(ctx, 1, []string{})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("cycle detected", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+
+		mocks.roleRepo.EXPECT().FindByID(ctx, int64(1)).Return(&model.Role{ID: 1, Code: "editor"}, nil)
+		mocks.roleRepo.EXPECT().FindByCodeAndType(ctx, "reader", model.RoleTypeRole).Return(&model.Role{ID: 2, Code: "reader"}, nil)
+		mocks.roleRepo.EXPECT().FindAllRoleParents(ctx).Return([]model.RoleParent{{RoleID: 2, ParentID: 3}, {RoleID: 3, ParentID: 1}}, nil)
+
+		err := svc.UpdateRoleParents(ctx, 1, []string{"reader"})
+
+		assert.ErrorIs(t, err, ErrRoleCycle)
+	})
+
+	t.Run("role not found", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+
+		mocks.roleRepo.EXPECT().FindByID(ctx, int64(1)).Return(nil, gorm.ErrRecordNotFound)
+
+		err := svc.UpdateRoleParents(ctx, 1, []string{"reader"})
+
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+	})
+
+	t.Run("parent not found", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+
+		mocks.roleRepo.EXPECT().FindByID(ctx, int64(1)).Return(&model.Role{ID: 1, Code: "editor"}, nil)
+		mocks.roleRepo.EXPECT().FindByCodeAndType(ctx, "unknown", model.RoleTypeRole).Return(nil, gorm.ErrRecordNotFound)
+
+		err := svc.UpdateRoleParents(ctx, 1, []string{"unknown"})
+
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+	})
+
+	t.Run("error from SetRoleParents", func(t *testing.T) {
+		mocks, svc := setupRoleServiceTest(t)
+		defer mocks.ctrl.Finish()
+
+		ctx := context.Background()
+		expectedErr := errors.New("database error")
+
+		mocks.roleRepo.EXPECT().FindByID(ctx, int64(1)).Return(&model.Role{ID: 1, Code: "editor"}, nil)
+		mocks.roleRepo.EXPECT().FindByCodeAndType(ctx, "reader", model.RoleTypeRole).Return(&model.Role{ID: 2, Code: "reader"}, nil)
+		mocks.roleRepo.EXPECT().FindAllRoleParents(ctx).Return([]model.RoleParent{}, nil)
+		mocks.roleRepo.EXPECT().SetRoleParents(ctx, int64(1), []int64{2}).Return(expectedErr)
+
+		err := svc.UpdateRoleParents(ctx, 1, []string{"reader"})
+
+		assert.Equal(t, expectedErr, err)
+	})
+}
+
+func TestHasRoleCycle(t *testing.T) {
+	links := []model.RoleParent{
+		{RoleID: 2, ParentID: 3},
+		{RoleID: 3, ParentID: 4},
+		{RoleID: 1, ParentID: 4},
+	}
+
+	assert.True(t, hasRoleCycle(1, []int64{1}, links), "self parent")
+	assert.False(t, hasRoleCycle(1, []int64{2}, links))
+	assert.True(t, hasRoleCycle(4, []int64{2}, links), "4 -> 2 -> 3 -> 4")
+	// existing links of the updated role are replaced, not kept
+	assert.False(t, hasRoleCycle(1, []int64{}, append(links, model.RoleParent{RoleID: 4, ParentID: 1})))
+}
+
 func TestRoleService_GetPermissionsByTokenName(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mocks, svc := setupRoleServiceTest(t)
@@ -1366,6 +1739,15 @@ func TestDeduplicateResourcePermissions(t *testing.T) {
 			},
 			expected: 1,
 		},
+		{
+			name: "same rule with different effect",
+			input: []model.ResourcePermission{
+				{Namespace: "ns1", Project: "p1", Resource: model.ResourceTypeAll, Action: model.ActionRead},
+				{Namespace: "ns1", Project: "p1", Resource: model.ResourceTypeAll, Action: model.ActionRead, Effect: model.PermissionEffectAllow},
+				{Namespace: "ns1", Project: "p1", Resource: model.ResourceTypeAll, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+			},
+			expected: 2,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1404,6 +1786,23 @@ func TestDeduplicateAdminPermissions(t *testing.T) {
 			},
 			expected: 2,
 		},
+		{
+			name: "same rule with different effect",
+			input: []model.AdminPermission{
+				{Section: model.AdminSectionUsers, Action: model.ActionRead},
+				{Section: model.AdminSectionUsers, Action: model.ActionRead, Effect: model.PermissionEffectDeny},
+			},
+			expected: 2,
+		},
+		{
+			name: "global and namespace scoped",
+			input: []model.AdminPermission{
+				{Section: model.AdminSectionProjects, Action: model.ActionRead},
+				{Namespace: "*", Section: model.AdminSectionProjects, Action: model.ActionRead},
+				{Namespace: "ns1", Section: model.AdminSectionProjects, Action: model.ActionRead},
+			},
+			expected: 2,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1460,13 +1859,14 @@ func setupRoleServiceIntegrationTest(t *testing.T) (*gorm.DB, RoleService) {
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&model.Role{}, &model.User{}, &model.ResourcePermission{}, &model.AdminPermission{})
+	err = db.AutoMigrate(&model.Role{}, &model.User{}, &model.ResourcePermission{}, &model.AdminPermission{}, &model.ProjectMember{})
 	assert.NoError(t, err)
 
 	roleRepo := repository.NewRoleRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	memberRepo := repository.NewProjectMemberRepository(db)
 
-	svc := NewRoleService(appContext.TestContext(nil), roleRepo, userRepo)
+	svc := NewRoleService(appContext.TestContext(nil), roleRepo, userRepo, memberRepo, invalidation.NewMemoryBus())
 	return db, svc
 }
 
@@ -1811,13 +2211,14 @@ func setupRoleServiceIntegrationTestWithUserRoles(t *testing.T) (*gorm.DB, RoleS
 	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
 	assert.NoError(t, err)
 
-	err = db.AutoMigrate(&model.Role{}, &model.User{}, &model.UserRole{}, &model.ResourcePermission{}, &model.AdminPermission{})
+	err = db.AutoMigrate(&model.Role{}, &model.User{}, &model.UserRole{}, &model.ResourcePermission{}, &model.AdminPermission{}, &model.ProjectMember{})
 	assert.NoError(t, err)
 
 	roleRepo := repository.NewRoleRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	memberRepo := repository.NewProjectMemberRepository(db)
 
-	svc := NewRoleService(appContext.TestContext(nil), roleRepo, userRepo)
+	svc := NewRoleService(appContext.TestContext(nil), roleRepo, userRepo, memberRepo, invalidation.NewMemoryBus())
 	return db, svc
 }
 
@@ -2024,3 +2425,65 @@ func TestRoleService_GetQuery(t *testing.T) {
 	result := svc.GetQuery(ctx)
 	assert.Nil(t, result)
 }
+
+func TestRoleService_UpdateRoleUsers_Integration(t *testing.T) {
+	setup := func(t *testing.T) (*gorm.DB, RoleService, []model.User, *model.Role) {
+		db, svc := setupRoleServiceIntegrationTestWithUserRoles(t)
+		users := []model.User{{Username: "user1", Password: "test"}, {Username: "user2", Password: "test"}, {Username: "user3", Password: "test"}}
+		assert.NoError(t, db.Create(&users).Error)
+		role := &model.Role{Code: "editors", Type: model.RoleTypeRole}
+		assert.NoError(t, db.Create(role).Error)
+		assert.NoError(t, db.Create(&model.UserRole{UserID: users[0].ID, RoleID: role.ID}).Error)
+		return db, svc, users, role
+	}
+	roleUserIDs := func(t *testing.T, db *gorm.DB, roleID int64) []int64 {
+		var userIDs []int64
+		assert.NoError(t, db.Model(&model.UserRole{}).Where("role_id = ?", roleID).Order("user_id").Pluck("user_id", &userIDs).Error)
+		return userIDs
+	}
+
+	t.Run("success - replace the users", func(t *testing.T) {
+		db, svc, users, role := setup(t)
+
+		err := svc.UpdateRoleUsers(context.Background(), role.ID, []int64{users[2].ID, users[1].ID, users[2].ID})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{users[1].ID, users[2].ID}, roleUserIDs(t, db, role.ID))
+	})
+
+	t.Run("success - remove all users", func(t *testing.T) {
+		db, svc, _, role := setup(t)
+
+		err := svc.UpdateRoleUsers(context.Background(), role.ID, nil)
+
+		assert.NoError(t, err)
+		assert.Empty(t, roleUserIDs(t, db, role.ID))
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		db, svc, users, role := setup(t)
+
+		err := svc.UpdateRoleUsers(context.Background(), role.ID, []int64{users[1].ID, 999})
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+		assert.Equal(t, []int64{users[0].ID}, roleUserIDs(t, db, role.ID))
+	})
+
+	t.Run("role not found", func(t *testing.T) {
+		_, svc, users, _ := setup(t)
+
+		err := svc.UpdateRoleUsers(context.Background(), 999, []int64{users[0].ID})
+
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+	})
+
+	t.Run("personal role of a user", func(t *testing.T) {
+		db, svc, users, _ := setup(t)
+		personal := &model.Role{Code: "user1", Type: model.RoleTypeUser}
+		assert.NoError(t, db.Create(personal).Error)
+
+		err := svc.UpdateRoleUsers(context.Background(), personal.ID, []int64{users[1].ID})
+
+		assert.ErrorIs(t, err, ErrRoleNotFound)
+	})
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/cron"
+	"github.com/flectolab/flecto-manager/types"
+)
+
+var (
+	// ErrScheduledTaskNotFound is returned for a task the scheduler does not run
+	ErrScheduledTaskNotFound = errors.New("scheduled task not found")
+	// ErrScheduledTaskNoSchedule is returned when enabling a task which has no schedule
+	ErrScheduledTaskNoSchedule = errors.New("scheduled task has no schedule")
+)
+
+// SchedulerService runs the recurring tasks at the schedules of the scheduler configuration, or at their own interval.
+// Each replica runs its tasks and keeps their status.
+type SchedulerService interface {
+	// Start runs the tasks at their schedule until the application context is done
+	Start()
+	// Tasks returns the status of the tasks sorted by name
+	Tasks() []types.ScheduledTaskStatus
+	// UpdateTask changes the schedule or the switch of a task until the next configuration reload
+	UpdateTask(name string, update types.ScheduledTaskUpdate) (*types.ScheduledTaskStatus, error)
+}
+
+type schedulerService struct {
+	ctx   *appContext.Context
+	tasks []*scheduledTask
+	now   func() time.Time
+}
+
+// scheduledTask is a task with its schedule and the status of its runs
+type scheduledTask struct {
+	types.ScheduledTask
+	// changed wakes the loop of the task up when its schedule or switch changes
+	changed chan struct{}
+
+	mu sync.Mutex
+	// configuredSpec is the schedule of the configuration, taken again by an update with an empty schedule
+	configuredSpec     string
+	configuredSchedule cron.Schedule
+	spec               string
+	schedule           cron.Schedule
+	enabled            bool
+	overridden         bool
+	running            bool
+	lastRunAt          time.Time
+	lastDuration       time.Duration
+	lastError          string
+	nextRunAt          time.Time
+}
+
+func NewSchedulerService(ctx *appContext.Context, tasks ...types.ScheduledTask) SchedulerService {
+	s := &schedulerService{ctx: ctx, now: time.Now}
+	for _, task := range tasks {
+		s.tasks = append(s.tasks, &scheduledTask{ScheduledTask: task, changed: make(chan struct{}, 1)})
+	}
+	sort.Slice(s.tasks, func(i, j int) bool { return s.tasks[i].Name < s.tasks[j].Name })
+	s.configure(ctx.CurrentConfig().Scheduler)
+	ctx.OnConfigReload(func(cfg *config.Config) {
+		s.configure(cfg.Scheduler)
+	})
+	return s
+}
+
+// configure sets the schedules and switches of the configuration, dropping the changes of the admin API
+func (s *schedulerService) configure(cfg config.SchedulerConfig) {
+	for _, task := range s.tasks {
+		taskCfg := cfg.Tasks[task.Name]
+		spec := taskCfg.Schedule
+		var schedule cron.Schedule
+		if spec != "" {
+			var err error
+			if schedule, err = cron.Parse(spec); err != nil {
+				s.ctx.Logger.Error("invalid schedule of scheduled task", "task", task.Name, "error", err)
+				spec = ""
+			}
+		} else if task.Interval > 0 {
+			spec = "@every " + task.Interval.String()
+			schedule = cron.Every(task.Interval)
+		}
+
+		task.mu.Lock()
+		task.configuredSpec = spec
+		task.configuredSchedule = schedule
+		task.spec = spec
+		task.schedule = schedule
+		task.enabled = task.Enabled && !taskCfg.Disabled
+		task.overridden = false
+		task.mu.Unlock()
+		task.plan(s.now())
+		task.wake()
+	}
+}
+
+func (s *schedulerService) Start() {
+	// The runs are canceled with the application context, a long task stopping with the manager
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-s.ctx.Done()
+		cancel()
+	}()
+	for _, task := range s.tasks {
+		go s.loop(ctx, task)
+	}
+}
+
+// loop runs a task at its schedule, planning the next run again when the schedule changes. The heartbeat of the task
+// is only registered while it is enabled, with a timeout following the time to the next run as the runs of a cron
+// expression are not evenly spaced.
+func (s *schedulerService) loop(ctx context.Context, task *scheduledTask) {
+	for {
+		now := s.now()
+		next := task.plan(now)
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if next.IsZero() {
+			s.ctx.Workers.Unregister(task.Name)
+		} else {
+			s.ctx.Workers.Register(task.Name, workerTimeout(next.Sub(now)))
+			timer = time.NewTimer(next.Sub(now))
+			fire = timer.C
+		}
+
+		select {
+		case <-s.ctx.Done():
+			stopTimer(timer)
+			return
+		case <-task.changed:
+			stopTimer(timer)
+		case <-fire:
+			s.run(ctx, task)
+		}
+	}
+}
+
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
+
+// run runs a task once with ctx, unless the manager is in read-only mode
+func (s *schedulerService) run(ctx context.Context, task *scheduledTask) {
+	if s.ctx.CurrentConfig().ReadOnly.Enabled {
+		return
+	}
+	start := s.now()
+	task.mu.Lock()
+	task.running = true
+	task.lastRunAt = start
+	task.mu.Unlock()
+
+	err := task.Run(ctx, start)
+	duration := s.now().Sub(start)
+
+	task.mu.Lock()
+	task.running = false
+	task.lastDuration = duration
+	task.lastError = ""
+	if err != nil {
+		task.lastError = err.Error()
+	}
+	task.mu.Unlock()
+	s.ctx.Logger.Debug("scheduled task run", "task", task.Name, "duration", duration, "error", err)
+}
+
+func (s *schedulerService) Tasks() []types.ScheduledTaskStatus {
+	tasks := make([]types.ScheduledTaskStatus, 0, len(s.tasks))
+	for _, task := range s.tasks {
+		tasks = append(tasks, task.status())
+	}
+	return tasks
+}
+
+func (s *schedulerService) UpdateTask(name string, update types.ScheduledTaskUpdate) (*types.ScheduledTaskStatus, error) {
+	var task *scheduledTask
+	for _, t := range s.tasks {
+		if t.Name == name {
+			task = t
+		}
+	}
+	if task == nil {
+		return nil, fmt.Errorf("%w: %s", ErrScheduledTaskNotFound, name)
+	}
+
+	var schedule cron.Schedule
+	if update.Schedule != nil && *update.Schedule != "" {
+		var err error
+		if schedule, err = cron.Parse(*update.Schedule); err != nil {
+			return nil, err
+		}
+	}
+
+	task.mu.Lock()
+	spec, enabled := task.spec, task.enabled
+	switch {
+	case update.Schedule == nil:
+		schedule = task.schedule
+	case *update.Schedule == "":
+		spec, schedule = task.configuredSpec, task.configuredSchedule
+	default:
+		spec = *update.Schedule
+	}
+	if update.Enabled != nil {
+		enabled = *update.Enabled
+	}
+	if enabled && spec == "" {
+		task.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrScheduledTaskNoSchedule, name)
+	}
+	task.spec = spec
+	task.schedule = schedule
+	task.enabled = enabled
+	task.overridden = true
+	task.mu.Unlock()
+	task.plan(s.now())
+	task.wake()
+
+	status := task.status()
+	return &status, nil
+}
+
+func (t *scheduledTask) wake() {
+	select {
+	case t.changed <- struct{}{}:
+	default:
+	}
+}
+
+// plan returns the next run of the task after now, the zero time when the task is disabled
+func (t *scheduledTask) plan(now time.Time) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextRunAt = time.Time{}
+	if t.enabled && t.schedule != nil {
+		t.nextRunAt = t.schedule.Next(now)
+	}
+	return t.nextRunAt
+}
+
+func (t *scheduledTask) status() types.ScheduledTaskStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := types.ScheduledTaskStatus{
+		Name:           t.Name,
+		Schedule:       t.spec,
+		Enabled:        t.enabled,
+		Overridden:     t.overridden,
+		Running:        t.running,
+		LastDurationMs: t.lastDuration.Milliseconds(),
+		LastError:      t.lastError,
+	}
+	if !t.lastRunAt.IsZero() {
+		lastRunAt := t.lastRunAt
+		status.LastRunAt = &lastRunAt
+	}
+	if !t.nextRunAt.IsZero() {
+		nextRunAt := t.nextRunAt
+		status.NextRunAt = &nextRunAt
+	}
+	return status
+}
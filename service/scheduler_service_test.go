@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noopTask(name string, interval time.Duration, enabled bool) types.ScheduledTask {
+	return types.ScheduledTask{
+		Name:     name,
+		Interval: interval,
+		Enabled:  enabled,
+		Run:      func(ctx context.Context, now time.Time) error { return nil },
+	}
+}
+
+func setupSchedulerServiceTest(cfg config.SchedulerConfig, tasks ...types.ScheduledTask) (*appContext.Context, *schedulerService) {
+	ctx := appContext.TestContext(nil)
+	ctx.Config.Scheduler = cfg
+	s := NewSchedulerService(ctx, tasks...).(*schedulerService)
+	return ctx, s
+}
+
+func TestNewSchedulerService(t *testing.T) {
+	now := time.Now()
+	_, s := setupSchedulerServiceTest(config.SchedulerConfig{Tasks: map[string]config.ScheduledTaskConfig{
+		"retention":   {Schedule: "0 3 * * *"},
+		"draft_locks": {Disabled: true},
+	}},
+		noopTask("retention", 24*time.Hour, true),
+		noopTask("redirect_expiry", time.Minute, true),
+		noopTask("draft_locks", time.Hour, true),
+		noopTask("git_sync", 0, false),
+	)
+
+	tasks := s.Tasks()
+	require.Len(t, tasks, 4)
+	assert.Equal(t, []string{"draft_locks", "git_sync", "redirect_expiry", "retention"}, []string{tasks[0].Name, tasks[1].Name, tasks[2].Name, tasks[3].Name})
+
+	assert.Equal(t, "@every 1h0m0s", tasks[0].Schedule)
+	assert.False(t, tasks[0].Enabled, "disabled by the configuration")
+	assert.Nil(t, tasks[0].NextRunAt)
+
+	assert.Equal(t, "", tasks[1].Schedule)
+	assert.False(t, tasks[1].Enabled, "disabled feature")
+	assert.Nil(t, tasks[1].NextRunAt)
+
+	assert.Equal(t, "@every 1m0s", tasks[2].Schedule)
+	assert.True(t, tasks[2].Enabled)
+	require.NotNil(t, tasks[2].NextRunAt)
+	assert.WithinDuration(t, now.Add(time.Minute), *tasks[2].NextRunAt, time.Second)
+
+	assert.Equal(t, "0 3 * * *", tasks[3].Schedule)
+	assert.True(t, tasks[3].Enabled)
+	require.NotNil(t, tasks[3].NextRunAt)
+	assert.Equal(t, 3, tasks[3].NextRunAt.Hour())
+	assert.Equal(t, 0, tasks[3].NextRunAt.Minute())
+	assert.Nil(t, tasks[3].LastRunAt)
+}
+
+func TestSchedulerService_UpdateTask(t *testing.T) {
+	setup := func() *schedulerService {
+		_, s := setupSchedulerServiceTest(config.SchedulerConfig{Tasks: map[string]config.ScheduledTaskConfig{
+			"retention": {Schedule: "0 3 * * *"},
+		}},
+			noopTask("retention", 24*time.Hour, true),
+			noopTask("git_sync", 0, false),
+		)
+		return s
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := setup().UpdateTask("unknown", types.ScheduledTaskUpdate{Enabled: types.Ptr(false)})
+		assert.ErrorIs(t, err, ErrScheduledTaskNotFound)
+	})
+
+	t.Run("invalid schedule", func(t *testing.T) {
+		_, err := setup().UpdateTask("retention", types.ScheduledTaskUpdate{Schedule: types.Ptr("0 25 * * *")})
+		assert.Error(t, err)
+	})
+
+	t.Run("enabled without schedule", func(t *testing.T) {
+		_, err := setup().UpdateTask("git_sync", types.ScheduledTaskUpdate{Enabled: types.Ptr(true)})
+		assert.ErrorIs(t, err, ErrScheduledTaskNoSchedule)
+	})
+
+	t.Run("schedule and switch", func(t *testing.T) {
+		s := setup()
+
+		task, err := s.UpdateTask("git_sync", types.ScheduledTaskUpdate{Schedule: types.Ptr("*/5 * * * *"), Enabled: types.Ptr(true)})
+		require.NoError(t, err)
+		assert.Equal(t, "*/5 * * * *", task.Schedule)
+		assert.True(t, task.Enabled)
+		assert.True(t, task.Overridden)
+		require.NotNil(t, task.NextRunAt)
+		assert.Zero(t, task.NextRunAt.Minute()%5)
+
+		task, err = s.UpdateTask("git_sync", types.ScheduledTaskUpdate{Enabled: types.Ptr(false)})
+		require.NoError(t, err)
+		assert.Equal(t, "*/5 * * * *", task.Schedule, "the schedule is kept")
+		assert.False(t, task.Enabled)
+		assert.Nil(t, task.NextRunAt)
+	})
+
+	t.Run("configured schedule taken again", func(t *testing.T) {
+		s := setup()
+
+		_, err := s.UpdateTask("retention", types.ScheduledTaskUpdate{Schedule: types.Ptr("@hourly")})
+		require.NoError(t, err)
+		task, err := s.UpdateTask("retention", types.ScheduledTaskUpdate{Schedule: types.Ptr("")})
+		require.NoError(t, err)
+		assert.Equal(t, "0 3 * * *", task.Schedule)
+	})
+
+	t.Run("overrides dropped on reload", func(t *testing.T) {
+		s := setup()
+
+		_, err := s.UpdateTask("retention", types.ScheduledTaskUpdate{Enabled: types.Ptr(false)})
+		require.NoError(t, err)
+		s.configure(config.SchedulerConfig{Tasks: map[string]config.ScheduledTaskConfig{
+			"retention": {Schedule: "@daily"},
+		}})
+
+		task := s.Tasks()[1]
+		assert.Equal(t, "@daily", task.Schedule)
+		assert.True(t, task.Enabled)
+		assert.False(t, task.Overridden)
+	})
+}
+
+func TestSchedulerService_Start(t *testing.T) {
+	t.Run("runs the tasks", func(t *testing.T) {
+		var runs atomic.Int32
+		ctx, s := setupSchedulerServiceTest(config.SchedulerConfig{}, types.ScheduledTask{
+			Name:     "retention",
+			Interval: 10 * time.Millisecond,
+			Enabled:  true,
+			Run: func(ctx context.Context, now time.Time) error {
+				runs.Add(1)
+				return errors.New("database down")
+			},
+		})
+		defer ctx.Cancel()
+
+		s.Start()
+
+		assert.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		task := s.Tasks()[0]
+		assert.NotNil(t, task.LastRunAt)
+		assert.Equal(t, "database down", task.LastError)
+		assert.Eventually(t, func() bool {
+			workers := ctx.Workers.Workers()
+			return len(workers) == 1 && workers[0].Name == "retention"
+		}, time.Second, 5*time.Millisecond)
+
+		_, err := s.UpdateTask("retention", types.ScheduledTaskUpdate{Enabled: types.Ptr(false)})
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return len(ctx.Workers.Workers()) == 0 }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("skipped in read-only mode", func(t *testing.T) {
+		var runs atomic.Int32
+		ctx, s := setupSchedulerServiceTest(config.SchedulerConfig{}, types.ScheduledTask{
+			Name:     "retention",
+			Interval: 10 * time.Millisecond,
+			Enabled:  true,
+			Run: func(ctx context.Context, now time.Time) error {
+				runs.Add(1)
+				return nil
+			},
+		})
+		defer ctx.Cancel()
+		ctx.SetReadOnly(config.ReadOnlyConfig{Enabled: true})
+
+		s.Start()
+
+		time.Sleep(50 * time.Millisecond)
+		assert.Zero(t, runs.Load())
+		assert.Nil(t, s.Tasks()[0].LastRunAt)
+	})
+
+	t.Run("run canceled with the application", func(t *testing.T) {
+		started := make(chan struct{})
+		var canceled atomic.Bool
+		ctx, s := setupSchedulerServiceTest(config.SchedulerConfig{}, types.ScheduledTask{
+			Name:     "retention",
+			Interval: 10 * time.Millisecond,
+			Enabled:  true,
+			Run: func(ctx context.Context, now time.Time) error {
+				close(started)
+				<-ctx.Done()
+				canceled.Store(true)
+				return ctx.Err()
+			},
+		})
+
+		s.Start()
+		<-started
+		ctx.Cancel()
+
+		assert.Eventually(t, canceled.Load, time.Second, 5*time.Millisecond)
+		assert.Eventually(t, func() bool { return s.Tasks()[0].LastError == context.Canceled.Error() }, time.Second, 5*time.Millisecond)
+	})
+}
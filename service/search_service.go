@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+)
+
+type SearchService interface {
+	SearchAll(ctx context.Context, namespaceCode, query string, scope model.SearchScope) ([]model.SearchResult, error)
+}
+
+type searchService struct {
+	ctx  *appContext.Context
+	repo repository.SearchRepository
+}
+
+func NewSearchService(ctx *appContext.Context, repo repository.SearchRepository) SearchService {
+	return &searchService{
+		ctx:  ctx,
+		repo: repo,
+	}
+}
+
+// SearchAll searches the redirects and the pages of the projects of the scope, best ranked first.
+// The limit of the scope defaults to DefaultSearchLimit and is capped to MaxSearchLimit.
+func (s *searchService) SearchAll(ctx context.Context, namespaceCode, query string, scope model.SearchScope) ([]model.SearchResult, error) {
+	if scope.Limit <= 0 {
+		scope.Limit = model.DefaultSearchLimit
+	}
+	if scope.Limit > model.MaxSearchLimit {
+		scope.Limit = model.MaxSearchLimit
+	}
+	return s.repo.SearchAll(ctx, namespaceCode, query, scope)
+}
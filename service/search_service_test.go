@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSearchServiceTest(t *testing.T) (*gorm.DB, SearchService) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	err = db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.Page{})
+	assert.NoError(t, err)
+	svc := NewSearchService(appContext.TestContext(nil), repository.NewSearchRepository(db))
+	return db, svc
+}
+
+func TestNewSearchService(t *testing.T) {
+	_, svc := setupSearchServiceTest(t)
+
+	assert.NotNil(t, svc)
+}
+
+func TestSearchService_SearchAll(t *testing.T) {
+	db, svc := setupSearchServiceTest(t)
+	for i := 0; i < model.MaxSearchLimit+1; i++ {
+		assert.NoError(t, db.Create(&model.Redirect{
+			NamespaceCode: "test-ns",
+			ProjectCode:   "test-proj",
+			Redirect:      &types.Redirect{Type: types.RedirectTypeBasic, Source: fmt.Sprintf("/promo/%d", i), Target: "/"},
+		}).Error)
+	}
+	scope := model.SearchScope{RedirectProjectCodes: []string{"test-proj"}}
+
+	t.Run("default limit", func(t *testing.T) {
+		results, err := svc.SearchAll(context.Background(), "test-ns", "promo", scope)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, model.DefaultSearchLimit)
+	})
+
+	t.Run("limit is capped", func(t *testing.T) {
+		scope.Limit = model.MaxSearchLimit + 50
+		results, err := svc.SearchAll(context.Background(), "test-ns", "promo", scope)
+
+		assert.NoError(t, err)
+		assert.Len(t, results, model.MaxSearchLimit)
+	})
+}
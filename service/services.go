@@ -1,13 +1,17 @@
 package service
 
 import (
+	"github.com/flectolab/flecto-manager/config"
 	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/gitrepo"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/repository"
 )
 
 type Services struct {
 	Namespace        NamespaceService
+	NamespacePolicy  NamespacePolicyService
 	Project          ProjectService
 	User             UserService
 	Auth             AuthService
@@ -16,38 +20,93 @@ type Services struct {
 	Redirect         RedirectService
 	RedirectDraft    RedirectDraftService
 	RedirectImport   RedirectImportService
+	RedirectExport   RedirectExportService
+	RedirectExpiry   RedirectExpiryService
+	RedirectHealth   RedirectHealthService
+	PageLink         PageLinkService
 	Page             PageService
 	PageDraft        PageDraftService
+	PageLint         PageLintService
+	PageTemplate     PageTemplateService
 	Agent            AgentService
+	AgentInstance    AgentInstanceService
 	ProjectDashboard ProjectDashboardService
+	ProjectApply     ProjectApplyService
+	GitSync          GitSyncService
+	DraftLock        DraftLockService
+	DraftBatch       DraftBatchService
+	Notification     NotificationService
+	Outbox           OutboxService
+	Search           SearchService
+	Hit              HitService
+	Probe            ProbeService
+	Status           StatusService
+	Retention        RetentionService
+	Scheduler        SchedulerService
+	ProjectAPIKey    ProjectAPIKeyService
+	ProjectMember    ProjectMemberService
+	Group            GroupService
+	Changeset        ChangesetService
+	ImportProfile    ImportProfileService
+	SnapshotCache    SnapshotCache
+	Invalidation     invalidation.Bus
 }
 
-func NewServices(ctx *appContext.Context, repos *repository.Repositories, jwtService *jwt.ServiceJWT) *Services {
+func NewServices(ctx *appContext.Context, repos *repository.Repositories, jwtService *jwt.ServiceJWT, bus invalidation.Bus) *Services {
+	outboxSrv := NewOutboxService(ctx, repos.Outbox, bus)
+	notificationSrv := NewNotificationService(ctx, repos.Notification, NewNotificationSenders(ctx.Config.Notification), outboxSrv)
+	ctx.OnConfigReload(func(cfg *config.Config) {
+		notificationSrv.SetSenders(NewNotificationSenders(cfg.Notification))
+	})
 	namespaceSrv := NewNamespaceService(ctx, repos.Namespace, repos.Project)
-	projectSrv := NewProjectService(ctx, repos.Project, repos.Page, repos.RedirectDraft, repos.PageDraft)
-	userSrv := NewUserService(ctx, repos.User, repos.Role)
-	authSrv := NewAuthService(ctx, repos.User, jwtService)
-	roleSrv := NewRoleService(ctx, repos.Role, repos.User)
-	tokenSrv := NewTokenService(ctx, repos.Token, repos.Role)
+	namespacePolicySrv := NewNamespacePolicyService(ctx, repos.NamespacePolicy)
+	projectSrv := NewProjectService(ctx, repos.Project, repos.Page, repos.RedirectDraft, repos.PageDraft, bus, outboxSrv, notificationSrv)
+	userSrv := NewUserService(ctx, repos.User, repos.Role, bus)
+	authSrv := NewAuthService(ctx, repos.User, repos.RefreshToken, jwtService)
+	roleSrv := NewRoleService(ctx, repos.Role, repos.User, repos.ProjectMember, bus)
+	groupSrv := NewGroupService(ctx, repos.Group, repos.Role, repos.User, bus)
+	tokenSrv := NewTokenService(ctx, repos.Token, repos.Role, bus)
 	redirectSrv := NewRedirectService(ctx, repos.Redirect)
-	redirectDraftSrv := NewRedirectDraftService(ctx, repos.RedirectDraft)
-	redirectImportSrv := NewRedirectImportService(ctx, repos.RedirectDraft)
+	redirectDraftSrv := NewRedirectDraftService(ctx, repos.RedirectDraft, notificationSrv)
+	redirectImportSrv := NewRedirectImportService(ctx, repos.RedirectDraft, repos.ImportJob)
+	redirectExpirySrv := NewRedirectExpiryService(ctx, repos.Redirect)
+	redirectHealthSrv := NewRedirectHealthService(ctx, repos.Redirect, repos.RedirectHealth)
 	pageSrv := NewPageService(ctx, repos.Page)
-	pageDraftSrv := NewPageDraftService(ctx, repos.PageDraft, repos.Page)
+	pageLintSrv := NewPageLintService(ctx)
+	pageDraftSrv := NewPageDraftService(ctx, repos.PageDraft, repos.Page, pageLintSrv)
+	pageTemplateSrv := NewPageTemplateService(ctx, repos.PageTemplate, pageDraftSrv)
 	agentSrv := NewAgentService(ctx, repos.Agent)
+	searchSrv := NewSearchService(ctx, repos.Search)
+	hitSrv := NewHitService(ctx, repos.Hit)
+	probeSrv := NewProbeService(ctx, repos.Namespace)
+	statusSrv := NewStatusService(ctx, probeSrv)
+	draftLockSrv := NewDraftLockService(ctx, repos.DraftLock)
+	draftBatchSrv := NewDraftBatchService(ctx, repos.RedirectDraft, pageLintSrv, notificationSrv)
+	retentionSrv := NewRetentionService(ctx, repos.Retention)
+	projectAPIKeySrv := NewProjectAPIKeyService(ctx, repos.ProjectAPIKey, repos.Project)
+	projectMemberSrv := NewProjectMemberService(ctx, repos.ProjectMember, repos.Project, repos.User, bus)
+	pageLinkSrv := NewPageLinkService(ctx, repos.Project, repos.Page, repos.Redirect, repos.PageLink, redirectDraftSrv)
 
-	projectDashboardSrv := NewProjectDashboardService(
-		ctx,
-		projectSrv,
-		redirectSrv,
-		redirectDraftSrv,
-		pageSrv,
-		pageDraftSrv,
-		agentSrv,
+	projectDashboardSrv := NewProjectDashboardService(ctx, projectSrv, agentSrv)
+	agentInstanceSrv := NewAgentInstanceService(ctx, repos.AgentInstance, projectSrv)
+	redirectExportSrv := NewRedirectExportService(ctx, projectSrv)
+	projectApplySrv := NewProjectApplyService(ctx, repos.RedirectDraft, projectSrv, notificationSrv)
+	changesetSrv := NewChangesetService(ctx, repos.Changeset, repos.Project, projectSrv)
+	importProfileSrv := NewImportProfileService(ctx, repos.ImportProfile, repos.Project)
+	snapshotCache := NewSnapshotCache(ctx.Config.Agent.SnapshotCache, bus)
+	gitSyncSrv := NewGitSyncService(ctx, repos.ProjectGitSync, projectApplySrv, gitrepo.NewFetcher(ctx.Config.GitSync.Credentials, ctx.Config.Import.MaxFileSize))
+	schedulerSrv := NewSchedulerService(ctx,
+		redirectExpirySrv.ScheduledTask(),
+		redirectHealthSrv.ScheduledTask(),
+		pageLinkSrv.ScheduledTask(),
+		gitSyncSrv.ScheduledTask(),
+		retentionSrv.ScheduledTask(),
+		draftLockSrv.ScheduledTask(),
 	)
 
 	return &Services{
 		Namespace:        namespaceSrv,
+		NamespacePolicy:  namespacePolicySrv,
 		Project:          projectSrv,
 		User:             userSrv,
 		Auth:             authSrv,
@@ -56,9 +115,35 @@ func NewServices(ctx *appContext.Context, repos *repository.Repositories, jwtSer
 		Redirect:         redirectSrv,
 		RedirectDraft:    redirectDraftSrv,
 		RedirectImport:   redirectImportSrv,
+		RedirectExport:   redirectExportSrv,
+		RedirectExpiry:   redirectExpirySrv,
+		RedirectHealth:   redirectHealthSrv,
+		PageLink:         pageLinkSrv,
 		Page:             pageSrv,
 		PageDraft:        pageDraftSrv,
+		PageLint:         pageLintSrv,
+		PageTemplate:     pageTemplateSrv,
 		Agent:            agentSrv,
+		AgentInstance:    agentInstanceSrv,
 		ProjectDashboard: projectDashboardSrv,
+		ProjectApply:     projectApplySrv,
+		GitSync:          gitSyncSrv,
+		DraftLock:        draftLockSrv,
+		DraftBatch:       draftBatchSrv,
+		Notification:     notificationSrv,
+		Outbox:           outboxSrv,
+		Search:           searchSrv,
+		Hit:              hitSrv,
+		Probe:            probeSrv,
+		Status:           statusSrv,
+		Retention:        retentionSrv,
+		Scheduler:        schedulerSrv,
+		ProjectAPIKey:    projectAPIKeySrv,
+		ProjectMember:    projectMemberSrv,
+		Group:            groupSrv,
+		Changeset:        changesetSrv,
+		ImportProfile:    importProfileSrv,
+		SnapshotCache:    snapshotCache,
+		Invalidation:     bus,
 	}
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/flectolab/flecto-manager/config"
 	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/repository"
 	"github.com/stretchr/testify/assert"
@@ -36,10 +37,11 @@ func setupServicesTest(t *testing.T) (*appContext.Context, *repository.Repositor
 func TestNewServices(t *testing.T) {
 	ctx, repos, jwtService := setupServicesTest(t)
 
-	services := NewServices(ctx, repos, jwtService)
+	services := NewServices(ctx, repos, jwtService, invalidation.NewMemoryBus())
 
 	assert.NotNil(t, services)
 	assert.NotNil(t, services.Namespace)
+	assert.NotNil(t, services.Invalidation)
 	assert.NotNil(t, services.Project)
 	assert.NotNil(t, services.User)
 	assert.NotNil(t, services.Auth)
@@ -48,8 +50,26 @@ func TestNewServices(t *testing.T) {
 	assert.NotNil(t, services.Redirect)
 	assert.NotNil(t, services.RedirectDraft)
 	assert.NotNil(t, services.RedirectImport)
+	assert.NotNil(t, services.RedirectExport)
+	assert.NotNil(t, services.PageLink)
+	assert.NotNil(t, services.RedirectExpiry)
+	assert.NotNil(t, services.RedirectHealth)
 	assert.NotNil(t, services.Page)
 	assert.NotNil(t, services.PageDraft)
+	assert.NotNil(t, services.PageLint)
+	assert.NotNil(t, services.PageTemplate)
 	assert.NotNil(t, services.Agent)
 	assert.NotNil(t, services.ProjectDashboard)
+	assert.NotNil(t, services.ProjectApply)
+	assert.NotNil(t, services.GitSync)
+	assert.NotNil(t, services.DraftLock)
+	assert.NotNil(t, services.Notification)
+	assert.NotNil(t, services.Search)
+	assert.NotNil(t, services.Hit)
+	assert.NotNil(t, services.Retention)
+	assert.NotNil(t, services.ProjectAPIKey)
+	assert.NotNil(t, services.ProjectMember)
+	assert.NotNil(t, services.Group)
+	assert.NotNil(t, services.Changeset)
+	assert.NotNil(t, services.ImportProfile)
 }
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"golang.org/x/sync/singleflight"
+)
+
+// SnapshotCacheKey identifies a page of the redirects or pages served to the agents at a version of a project
+type SnapshotCacheKey struct {
+	NamespaceCode string
+	ProjectCode   string
+	Environment   commonTypes.Environment
+	Resource      model.ResourceType
+	Version       int
+	Limit         int
+	Offset        int
+}
+
+// SnapshotPage is a page of the redirects or pages of a project version, serialized once for all the agents
+type SnapshotPage struct {
+	Items json.RawMessage
+	Total int
+	// Options are the matching options of the redirects promoted to an environment
+	Options commonTypes.RedirectOptions
+}
+
+// SnapshotCacheStats are the counters of the snapshot cache since the manager started
+type SnapshotCacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Entries   int
+	Size      int64
+}
+
+// SnapshotCache keeps the pages of the snapshots served to the agents in memory, the published versions never
+// changing. The entries of the previous versions are dropped when a project is published, promoted or deleted on
+// any replica, and the least recently used ones when the cache is full.
+type SnapshotCache interface {
+	// Get returns the page of key, built by build on a miss. The concurrent misses of a key build it once. The page
+	// is only cached when current tells that the project is still at the version of the key once the page is built.
+	Get(key SnapshotCacheKey, build func() (*SnapshotPage, error), current func() (bool, error)) (*SnapshotPage, error)
+	Stats() SnapshotCacheStats
+}
+
+type snapshotCacheEntry struct {
+	key  SnapshotCacheKey
+	page *SnapshotPage
+}
+
+type snapshotCache struct {
+	maxSize int64
+	group   singleflight.Group
+
+	mu      sync.Mutex
+	entries map[SnapshotCacheKey]*list.Element
+	lru     *list.List
+	size    int64
+	stats   SnapshotCacheStats
+}
+
+// NewSnapshotCache creates the snapshot cache of the configuration, disabled when its size is 0, cleared on the
+// publishes, promotions and deletions of projects sent on the bus
+func NewSnapshotCache(cfg config.SnapshotCacheConfig, bus invalidation.Bus) SnapshotCache {
+	cache := &snapshotCache{
+		maxSize: cfg.MaxSize,
+		entries: make(map[SnapshotCacheKey]*list.Element),
+		lru:     list.New(),
+	}
+	bus.Subscribe(cache.invalidate)
+	return cache
+}
+
+func (c *snapshotCache) Get(key SnapshotCacheKey, build func() (*SnapshotPage, error), current func() (bool, error)) (*SnapshotPage, error) {
+	if c.maxSize <= 0 {
+		return build()
+	}
+
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(element)
+		c.stats.Hits++
+		c.mu.Unlock()
+		return element.Value.(*snapshotCacheEntry).page, nil
+	}
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	page, err, _ := c.group.Do(fmt.Sprintf("%+v", key), func() (interface{}, error) {
+		// The page may have been cached by a build of the key ending since the lookup
+		c.mu.Lock()
+		element, ok := c.entries[key]
+		c.mu.Unlock()
+		if ok {
+			return element.Value.(*snapshotCacheEntry).page, nil
+		}
+		page, errBuild := build()
+		if errBuild != nil {
+			return nil, errBuild
+		}
+		isCurrent, errCurrent := current()
+		if errCurrent != nil {
+			return nil, errCurrent
+		}
+		if isCurrent {
+			c.set(key, page)
+		}
+		return page, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page.(*SnapshotPage), nil
+}
+
+func (c *snapshotCache) Stats() SnapshotCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.Size = c.size
+	return stats
+}
+
+// set caches a page, the pages larger than the cache being not kept
+func (c *snapshotCache) set(key SnapshotCacheKey, page *SnapshotPage) {
+	size := int64(len(page.Items))
+	if size > c.maxSize {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; ok {
+		return
+	}
+	for c.size+size > c.maxSize {
+		c.remove(c.lru.Back())
+		c.stats.Evictions++
+	}
+	c.entries[key] = c.lru.PushFront(&snapshotCacheEntry{key: key, page: page})
+	c.size += size
+}
+
+func (c *snapshotCache) remove(element *list.Element) {
+	entry := c.lru.Remove(element).(*snapshotCacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= int64(len(entry.page.Items))
+}
+
+// invalidate drops the entries of the versions not served anymore after an event
+func (c *snapshotCache) invalidate(event invalidation.Event) {
+	var stale func(key SnapshotCacheKey) bool
+	switch event.Type {
+	case invalidation.EventTypeProjectPublished:
+		stale = func(key SnapshotCacheKey) bool {
+			return key.Environment == commonTypes.EnvironmentStaging && key.Version != event.Version
+		}
+	case invalidation.EventTypeProjectPromoted:
+		stale = func(key SnapshotCacheKey) bool {
+			return key.Environment == commonTypes.EnvironmentProduction && key.Version != event.Version
+		}
+	case invalidation.EventTypeProjectDeleted:
+		stale = func(key SnapshotCacheKey) bool { return true }
+	case invalidation.EventTypeReset:
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.entries = make(map[SnapshotCacheKey]*list.Element)
+		c.lru.Init()
+		c.size = 0
+		return
+	default:
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, element := range c.entries {
+		if key.NamespaceCode == event.NamespaceCode && key.ProjectCode == event.ProjectCode && stale(key) {
+			c.remove(element)
+		}
+	}
+}
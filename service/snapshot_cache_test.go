@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/invalidation"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotKey(environment commonTypes.Environment, version, offset int) SnapshotCacheKey {
+	return SnapshotCacheKey{
+		NamespaceCode: "ns",
+		ProjectCode:   "proj",
+		Environment:   environment,
+		Resource:      model.ResourceTypeRedirect,
+		Version:       version,
+		Limit:         10,
+		Offset:        offset,
+	}
+}
+
+func buildSnapshotPage(items string, builds *int) func() (*SnapshotPage, error) {
+	return func() (*SnapshotPage, error) {
+		*builds++
+		return &SnapshotPage{Items: []byte(items), Total: 1}, nil
+	}
+}
+
+func isCurrent(current bool) func() (bool, error) {
+	return func() (bool, error) { return current, nil }
+}
+
+func TestSnapshotCache_Get(t *testing.T) {
+	t.Run("hit", func(t *testing.T) {
+		cache := NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 100}, invalidation.NewMemoryBus())
+		builds := 0
+
+		first, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 0), buildSnapshotPage(`["a"]`, &builds), isCurrent(true))
+		require.NoError(t, err)
+		second, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 0), buildSnapshotPage(`["b"]`, &builds), isCurrent(true))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, builds)
+		assert.Equal(t, `["a"]`, string(first.Items))
+		assert.Same(t, first, second)
+		assert.Equal(t, SnapshotCacheStats{Hits: 1, Misses: 1, Entries: 1, Size: 5}, cache.Stats())
+	})
+
+	t.Run("other page or version", func(t *testing.T) {
+		cache := NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 100}, invalidation.NewMemoryBus())
+		builds := 0
+
+		_, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 0), buildSnapshotPage(`["a"]`, &builds), isCurrent(true))
+		require.NoError(t, err)
+		_, err = cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 10), buildSnapshotPage(`["b"]`, &builds), isCurrent(true))
+		require.NoError(t, err)
+		_, err = cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 2, 0), buildSnapshotPage(`["c"]`, &builds), isCurrent(true))
+		require.NoError(t, err)
+		_, err = cache.Get(snapshotKey(commonTypes.EnvironmentProduction, 1, 0), buildSnapshotPage(`["d"]`, &builds), isCurrent(true))
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, builds)
+		assert.Equal(t, 4, cache.Stats().Entries)
+	})
+
+	t.Run("not current", func(t *testing.T) {
+		cache := NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 100}, invalidation.NewMemoryBus())
+		builds := 0
+
+		page, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 0), buildSnapshotPage(`["a"]`, &builds), isCurrent(false))
+		require.NoError(t, err)
+
+		assert.Equal(t, `["a"]`, string(page.Items), "the page is still answered")
+		assert.Equal(t, 0, cache.Stats().Entries)
+	})
+
+	t.Run("build error", func(t *testing.T) {
+		cache := NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 100}, invalidation.NewMemoryBus())
+		errBuild := errors.New("database down")
+
+		_, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 0), func() (*SnapshotPage, error) {
+			return nil, errBuild
+		}, func() (bool, error) {
+			t.Fatal("current not expected")
+			return false, nil
+		})
+
+		assert.ErrorIs(t, err, errBuild)
+		assert.Equal(t, 0, cache.Stats().Entries)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cache := NewSnapshotCache(config.SnapshotCacheConfig{}, invalidation.NewMemoryBus())
+		builds := 0
+
+		for i := 0; i < 2; i++ {
+			_, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 0), buildSnapshotPage(`["a"]`, &builds), func() (bool, error) {
+				t.Fatal("current not expected")
+				return false, nil
+			})
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, builds)
+		assert.Equal(t, SnapshotCacheStats{}, cache.Stats())
+	})
+
+	t.Run("least recently used evicted", func(t *testing.T) {
+		cache := NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 10}, invalidation.NewMemoryBus())
+		builds := 0
+
+		for _, offset := range []int{0, 10, 0, 20} {
+			_, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, offset), buildSnapshotPage(`["a"]`, &builds), isCurrent(true))
+			require.NoError(t, err)
+		}
+		_, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 0), buildSnapshotPage(`["a"]`, &builds), isCurrent(true))
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, builds, "the page at offset 0 used again is kept")
+		stats := cache.Stats()
+		assert.Equal(t, uint64(1), stats.Evictions)
+		assert.Equal(t, 2, stats.Entries)
+		assert.Equal(t, int64(10), stats.Size)
+	})
+
+	t.Run("page larger than the cache", func(t *testing.T) {
+		cache := NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 4}, invalidation.NewMemoryBus())
+		builds := 0
+
+		page, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 0), buildSnapshotPage(`["a"]`, &builds), isCurrent(true))
+		require.NoError(t, err)
+
+		assert.Equal(t, `["a"]`, string(page.Items))
+		assert.Equal(t, 0, cache.Stats().Entries)
+	})
+
+	t.Run("concurrent misses built once", func(t *testing.T) {
+		cache := NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 100}, invalidation.NewMemoryBus())
+		var builds atomic.Int32
+		release := make(chan struct{})
+		build := func() (*SnapshotPage, error) {
+			builds.Add(1)
+			<-release
+			return &SnapshotPage{Items: []byte(`["a"]`)}, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				page, err := cache.Get(snapshotKey(commonTypes.EnvironmentStaging, 1, 0), build, isCurrent(true))
+				assert.NoError(t, err)
+				assert.Equal(t, `["a"]`, string(page.Items))
+			}()
+		}
+		assert.Eventually(t, func() bool { return cache.Stats().Misses+cache.Stats().Hits == 5 }, time.Second, 10*time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), builds.Load())
+	})
+}
+
+func TestSnapshotCache_Invalidate(t *testing.T) {
+	fill := func(t *testing.T, cache SnapshotCache) {
+		builds := 0
+		keys := []SnapshotCacheKey{
+			snapshotKey(commonTypes.EnvironmentStaging, 1, 0),
+			snapshotKey(commonTypes.EnvironmentStaging, 2, 0),
+			snapshotKey(commonTypes.EnvironmentProduction, 1, 0),
+			{NamespaceCode: "ns", ProjectCode: "other", Environment: commonTypes.EnvironmentStaging, Version: 1},
+		}
+		for _, key := range keys {
+			_, err := cache.Get(key, buildSnapshotPage(`["a"]`, &builds), isCurrent(true))
+			require.NoError(t, err)
+		}
+	}
+	cached := func(cache SnapshotCache, key SnapshotCacheKey) bool {
+		builds := 0
+		_, _ = cache.Get(key, buildSnapshotPage(`["a"]`, &builds), isCurrent(false))
+		return builds == 0
+	}
+
+	tests := []struct {
+		name     string
+		event    invalidation.Event
+		expected []bool
+	}{
+		{
+			name:     "published",
+			event:    invalidation.Event{Type: invalidation.EventTypeProjectPublished, NamespaceCode: "ns", ProjectCode: "proj", Version: 2},
+			expected: []bool{false, true, true, true},
+		},
+		{
+			name:     "promoted",
+			event:    invalidation.Event{Type: invalidation.EventTypeProjectPromoted, NamespaceCode: "ns", ProjectCode: "proj", Version: 2},
+			expected: []bool{true, true, false, true},
+		},
+		{
+			name:     "deleted",
+			event:    invalidation.Event{Type: invalidation.EventTypeProjectDeleted, NamespaceCode: "ns", ProjectCode: "proj"},
+			expected: []bool{false, false, false, true},
+		},
+		{
+			name:     "reset",
+			event:    invalidation.Event{Type: invalidation.EventTypeReset},
+			expected: []bool{false, false, false, false},
+		},
+		{
+			name:     "permissions changed",
+			event:    invalidation.Event{Type: invalidation.EventTypePermissionsChanged},
+			expected: []bool{true, true, true, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bus := invalidation.NewMemoryBus()
+			cache := NewSnapshotCache(config.SnapshotCacheConfig{MaxSize: 100}, bus)
+			fill(t, cache)
+
+			require.NoError(t, bus.Publish(context.Background(), tt.event))
+
+			assert.Equal(t, tt.expected, []bool{
+				cached(cache, snapshotKey(commonTypes.EnvironmentStaging, 1, 0)),
+				cached(cache, snapshotKey(commonTypes.EnvironmentStaging, 2, 0)),
+				cached(cache, snapshotKey(commonTypes.EnvironmentProduction, 1, 0)),
+				cached(cache, SnapshotCacheKey{NamespaceCode: "ns", ProjectCode: "other", Environment: commonTypes.EnvironmentStaging, Version: 1}),
+			})
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/model"
+	"gorm.io/gorm"
+)
+
+// SnapshotStreamBatchSize is the number of redirects or pages read at once while streaming a snapshot
+const SnapshotStreamBatchSize = 1000
+
+// ErrSnapshotChanged is returned when a project is published while its snapshot is streamed, the rows read after
+// belonging to another version
+var ErrSnapshotChanged = errors.New("project published while streaming its snapshot")
+
+// streamPublished calls fn with the batches of published rows returned by next, from the first one until a batch is
+// not full. No transaction is held during the stream: the version of the project is checked after reading each batch,
+// so the rows sent were all read before a publish changes them.
+func streamPublished[T any](ctx context.Context, db *gorm.DB, namespaceCode, projectCode string, version int, next func(last *T) ([]T, error), fn func(batch []T) error) error {
+	var last *T
+	for {
+		batch, err := next(last)
+		if err != nil {
+			return err
+		}
+		if err = checkSnapshotVersion(ctx, db, namespaceCode, projectCode, version); err != nil {
+			return err
+		}
+		if len(batch) > 0 {
+			if err = fn(batch); err != nil {
+				return err
+			}
+		}
+		if len(batch) < SnapshotStreamBatchSize {
+			return nil
+		}
+		last = &batch[len(batch)-1]
+	}
+}
+
+// checkSnapshotVersion returns ErrSnapshotChanged when the version of the project is not the streamed one anymore
+func checkSnapshotVersion(ctx context.Context, db *gorm.DB, namespaceCode, projectCode string, version int) error {
+	var current int
+	err := db.WithContext(ctx).Model(&model.Project{}).
+		Select("version").
+		Where(fmt.Sprintf("%s = ? AND %s = ?", model.ColumnNamespaceCode, model.ColumnProjectCode), namespaceCode, projectCode).
+		Scan(&current).Error
+	if err != nil {
+		return err
+	}
+	if current != version {
+		return fmt.Errorf("%w: version %d instead of %d", ErrSnapshotChanged, current, version)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupSnapshotStreamTest(t *testing.T, redirects, pages int) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&model.Namespace{}, &model.Project{}, &model.Redirect{}, &model.Page{}))
+
+	require.NoError(t, db.Create(&model.Namespace{NamespaceCode: "test-ns", Name: "Test"}).Error)
+	require.NoError(t, db.Create(&model.Project{ProjectCode: "test-proj", NamespaceCode: "test-ns", Name: "Test", Version: 3}).Error)
+	for i := 0; i < redirects; i++ {
+		require.NoError(t, db.Create(&model.Redirect{
+			NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: boolPtr(true),
+			Redirect: &commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: fmt.Sprintf("/r%d", i), Target: "/t", Status: commonTypes.RedirectStatusFound, Priority: i % 3},
+		}).Error)
+	}
+	for i := 0; i < pages; i++ {
+		require.NoError(t, db.Create(&model.Page{
+			NamespaceCode: "test-ns", ProjectCode: "test-proj", IsPublished: boolPtr(true),
+			Page: &commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: fmt.Sprintf("/p%d", i), Content: "page"},
+		}).Error)
+	}
+	return db
+}
+
+func TestRedirectService_StreamByProjectPublished(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		db := setupSnapshotStreamTest(t, SnapshotStreamBatchSize+5, 0)
+		svc := NewRedirectService(appContext.TestContext(nil), repository.NewRedirectRepository(db))
+
+		var total int64
+		var batches []int
+		seen := map[string]bool{}
+		lastPriority := 2
+		err := svc.StreamByProjectPublished(ctx, "test-ns", "test-proj", 3, func(count int64) error {
+			total = count
+			return nil
+		}, func(redirects []model.Redirect) error {
+			batches = append(batches, len(redirects))
+			for _, redirect := range redirects {
+				assert.LessOrEqual(t, redirect.Priority, lastPriority)
+				lastPriority = redirect.Priority
+				seen[redirect.Source] = true
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(SnapshotStreamBatchSize+5), total)
+		assert.Equal(t, []int{SnapshotStreamBatchSize, 5}, batches)
+		assert.Len(t, seen, SnapshotStreamBatchSize+5)
+	})
+
+	t.Run("published meanwhile", func(t *testing.T) {
+		db := setupSnapshotStreamTest(t, SnapshotStreamBatchSize+5, 0)
+		svc := NewRedirectService(appContext.TestContext(nil), repository.NewRedirectRepository(db))
+
+		batches := 0
+		err := svc.StreamByProjectPublished(ctx, "test-ns", "test-proj", 3, func(count int64) error { return nil }, func(redirects []model.Redirect) error {
+			batches++
+			return db.Model(&model.Project{}).Where("project_code = ?", "test-proj").Update("version", 4).Error
+		})
+
+		assert.ErrorIs(t, err, ErrSnapshotChanged)
+		assert.Equal(t, 1, batches)
+	})
+
+	t.Run("empty project of another version", func(t *testing.T) {
+		db := setupSnapshotStreamTest(t, 0, 0)
+		svc := NewRedirectService(appContext.TestContext(nil), repository.NewRedirectRepository(db))
+
+		err := svc.StreamByProjectPublished(ctx, "test-ns", "test-proj", 2, func(count int64) error { return nil }, func(redirects []model.Redirect) error {
+			t.Fatal("no batch expected")
+			return nil
+		})
+
+		assert.ErrorIs(t, err, ErrSnapshotChanged)
+	})
+}
+
+func TestPageService_StreamByProjectPublished(t *testing.T) {
+	db := setupSnapshotStreamTest(t, 0, 3)
+	svc := NewPageService(appContext.TestContext(nil), repository.NewPageRepository(db))
+
+	var total int64
+	var paths []string
+	err := svc.StreamByProjectPublished(context.Background(), "test-ns", "test-proj", 3, func(count int64) error {
+		total = count
+		return nil
+	}, func(pages []model.Page) error {
+		for _, page := range pages {
+			paths = append(paths, page.Path)
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []string{"/p0", "/p1", "/p2"}, paths)
+}
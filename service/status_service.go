@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/flectolab/flecto-manager/config"
+	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/flectolab/flecto-manager/version"
+)
+
+// StatusService builds the public status report of the manager, polled by the uptime monitors
+type StatusService interface {
+	// Status returns the report down to the detail level of http.status.detail, its checks being run at most
+	// once per http.status.cache_ttl
+	Status(ctx context.Context) *types.StatusReport
+}
+
+type statusService struct {
+	ctx   *appContext.Context
+	probe ProbeService
+	now   func() time.Time
+
+	mu      sync.Mutex
+	report  *types.StatusReport
+	expires time.Time
+}
+
+func NewStatusService(ctx *appContext.Context, probe ProbeService) StatusService {
+	return &statusService{
+		ctx:   ctx,
+		probe: probe,
+		now:   time.Now,
+	}
+}
+
+func (s *statusService) Status(ctx context.Context) *types.StatusReport {
+	cfg := s.ctx.Config.HTTP.Status
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if s.report == nil || !now.Before(s.expires) {
+		// the report is shared by the requests, it must not fail because the request checking it was canceled
+		s.report = s.check(context.WithoutCancel(ctx))
+		s.expires = now.Add(cfg.CacheTTL)
+	}
+	return statusWithDetail(s.report, cfg.Detail, now.Sub(s.ctx.StartedAt))
+}
+
+// check runs the readiness checks and the check of each queue, a full queue failing the report
+func (s *statusService) check(ctx context.Context) *types.StatusReport {
+	readiness := s.probe.Readiness(ctx)
+	report := &types.StatusReport{
+		Status:     readiness.Status,
+		Version:    version.GetFormattedVersion(),
+		Subsystems: readiness.Checks,
+		Queues:     s.ctx.Workers.Queues(),
+	}
+	for _, queue := range report.Queues {
+		var err error
+		if queue.Full() {
+			err = fmt.Errorf("queue full, %d items waiting", queue.Length)
+		}
+		addStatusCheck(report, queue.Name+"_queue", err)
+	}
+	return report
+}
+
+// statusWithDetail returns a copy of the report without the fields above the detail level
+func statusWithDetail(report *types.StatusReport, detail config.StatusDetail, uptime time.Duration) *types.StatusReport {
+	result := &types.StatusReport{Status: report.Status}
+	if detail != config.StatusDetailStandard && detail != config.StatusDetailFull {
+		return result
+	}
+
+	result.Uptime = int64(uptime.Seconds())
+	result.Subsystems = make(map[string]types.HealthCheck, len(report.Subsystems))
+	for name, check := range report.Subsystems {
+		if detail != config.StatusDetailFull {
+			check.Error = ""
+		}
+		result.Subsystems[name] = check
+	}
+	if detail == config.StatusDetailFull {
+		result.Version = report.Version
+		result.Queues = report.Queues
+	}
+	return result
+}
+
+// addStatusCheck sets the result of the check of a subsystem, failing the report on error
+func addStatusCheck(report *types.StatusReport, name string, err error) {
+	if err != nil {
+		report.Status = types.HealthStatusFail
+		report.Subsystems[name] = types.HealthCheck{Status: types.HealthStatusFail, Error: err.Error()}
+		return
+	}
+	report.Subsystems[name] = types.HealthCheck{Status: types.HealthStatusOK}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/migrations"
+	"github.com/flectolab/flecto-manager/types"
+	"github.com/flectolab/flecto-manager/version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusService_Status(t *testing.T) {
+	latest, err := migrations.LatestVersion()
+	require.NoError(t, err)
+
+	setup := func(t *testing.T, detail config.StatusDetail) (*statusService, chan int) {
+		db, ctx, probe := setupProbeServiceTest(t)
+		require.NoError(t, db.Exec("INSERT INTO schema_migrations VALUES (?, ?)", latest, false).Error)
+		ctx.Config.HTTP.Status.Detail = detail
+		ctx.Config.HTTP.Status.CacheTTL = time.Minute
+		ctx.StartedAt = time.Now().Add(-time.Hour)
+
+		queue := make(chan int, 2)
+		ctx.Workers.RegisterQueue("notification", func() (int, int) { return len(queue), cap(queue) })
+		return NewStatusService(ctx, probe).(*statusService), queue
+	}
+
+	t.Run("minimal", func(t *testing.T) {
+		svc, _ := setup(t, config.StatusDetailMinimal)
+
+		assert.Equal(t, &types.StatusReport{Status: types.HealthStatusOK}, svc.Status(context.Background()))
+	})
+
+	t.Run("standard", func(t *testing.T) {
+		svc, queue := setup(t, config.StatusDetailStandard)
+		queue <- 1
+		queue <- 2
+
+		report := svc.Status(context.Background())
+		assert.Equal(t, types.HealthStatusFail, report.Status)
+		assert.Empty(t, report.Version)
+		assert.Empty(t, report.Queues)
+		assert.InDelta(t, time.Hour.Seconds(), report.Uptime, 5)
+		assert.Equal(t, map[string]types.HealthCheck{
+			ProbeCheckDatabase:   {Status: types.HealthStatusOK},
+			ProbeCheckMigrations: {Status: types.HealthStatusOK},
+			ProbeCheckWorkers:    {Status: types.HealthStatusOK},
+			"notification_queue": {Status: types.HealthStatusFail},
+		}, report.Subsystems)
+	})
+
+	t.Run("full", func(t *testing.T) {
+		svc, queue := setup(t, config.StatusDetailFull)
+		queue <- 1
+		queue <- 2
+
+		report := svc.Status(context.Background())
+		assert.Equal(t, version.GetFormattedVersion(), report.Version)
+		assert.Equal(t, []types.QueueStatus{{Name: "notification", Length: 2, Capacity: 2}}, report.Queues)
+		assert.Equal(t, "queue full, 2 items waiting", report.Subsystems["notification_queue"].Error)
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		svc, queue := setup(t, config.StatusDetailMinimal)
+		now := time.Now()
+		svc.now = func() time.Time { return now }
+		assert.Equal(t, types.HealthStatusOK, svc.Status(context.Background()).Status)
+
+		queue <- 1
+		queue <- 2
+		assert.Equal(t, types.HealthStatusOK, svc.Status(context.Background()).Status)
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, types.HealthStatusFail, svc.Status(context.Background()).Status)
+	})
+}
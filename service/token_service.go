@@ -9,6 +9,8 @@ import (
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/jwt"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/repository"
@@ -16,11 +18,11 @@ import (
 )
 
 var (
-	ErrTokenNotFound      = errors.New("token not found")
-	ErrTokenAlreadyExists = errors.New("token with this name already exists")
-	ErrTokenExpired       = errors.New("token has expired")
-	ErrInvalidToken       = errors.New("invalid token")
-	ErrTokenNameTooLong   = errors.New("token name is too long")
+	ErrTokenNotFound      = flectoErrors.New(flectoErrors.CodeNotFound, "token not found")
+	ErrTokenAlreadyExists = flectoErrors.New(flectoErrors.CodeAlreadyExists, "token with this name already exists")
+	ErrTokenExpired       = flectoErrors.New(flectoErrors.CodeTokenExpired, "token has expired")
+	ErrInvalidToken       = flectoErrors.New(flectoErrors.CodeUnauthenticated, "invalid token")
+	ErrTokenNameTooLong   = flectoErrors.New(flectoErrors.CodeInvalidRequest, "token name is too long")
 )
 
 type TokenService interface {
@@ -40,17 +42,20 @@ type tokenService struct {
 	ctx      *appContext.Context
 	repo     repository.TokenRepository
 	roleRepo repository.RoleRepository
+	bus      invalidation.Bus
 }
 
 func NewTokenService(
 	ctx *appContext.Context,
 	repo repository.TokenRepository,
 	roleRepo repository.RoleRepository,
+	bus invalidation.Bus,
 ) TokenService {
 	return &tokenService{
 		ctx:      ctx,
 		repo:     repo,
 		roleRepo: roleRepo,
+		bus:      bus,
 	}
 }
 
@@ -133,6 +138,7 @@ func (s *tokenService) Create(ctx context.Context, name string, expiresAt *strin
 					Project:   perm.Project,
 					Resource:  perm.Resource,
 					Action:    perm.Action,
+					Effect:    perm.Effect,
 				}
 				if err := tx.Create(&resourcePerm).Error; err != nil {
 					return err
@@ -140,9 +146,11 @@ func (s *tokenService) Create(ctx context.Context, name string, expiresAt *strin
 			}
 			for _, perm := range permissions.Admin {
 				adminPerm := model.AdminPermission{
-					RoleID:  role.ID,
-					Section: perm.Section,
-					Action:  perm.Action,
+					RoleID:    role.ID,
+					Namespace: perm.Namespace,
+					Section:   perm.Section,
+					Action:    perm.Action,
+					Effect:    perm.Effect,
 				}
 				if err := tx.Create(&adminPerm).Error; err != nil {
 					return err
@@ -154,11 +162,12 @@ func (s *tokenService) Create(ctx context.Context, name string, expiresAt *strin
 	})
 
 	if err != nil {
-		s.ctx.Logger.Error("failed to create token", "name", name, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to create token", "name", name, "error", err)
 		return nil, "", err
 	}
 
-	s.ctx.Logger.Info("token created", "name", name, "id", token.ID)
+	s.ctx.Logger.InfoContext(ctx, "token created", "name", name, "id", token.ID)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
 	return token, plainToken, nil
 }
 
@@ -199,11 +208,12 @@ func (s *tokenService) Delete(ctx context.Context, id int64) (bool, error) {
 	})
 
 	if err != nil {
-		s.ctx.Logger.Error("failed to delete token", "name", token.Name, "id", id, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete token", "name", token.Name, "id", id, "error", err)
 		return false, err
 	}
 
-	s.ctx.Logger.Info("token deleted", "name", token.Name, "id", id)
+	s.ctx.Logger.InfoContext(ctx, "token deleted", "name", token.Name, "id", id)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
 	return true, nil
 }
 
@@ -232,7 +242,7 @@ func (s *tokenService) GetByName(ctx context.Context, name string) (*model.Token
 func (s *tokenService) ValidateToken(ctx context.Context, plainToken string) (*model.Token, *model.SubjectPermissions, error) {
 	// Check prefix
 	if len(plainToken) < len(model.TokenPrefix) || plainToken[:len(model.TokenPrefix)] != model.TokenPrefix {
-		s.ctx.Logger.Warn("token validation failed: invalid prefix")
+		s.ctx.Logger.WarnContext(ctx, "token validation failed: invalid prefix")
 		return nil, nil, ErrInvalidToken
 	}
 
@@ -241,7 +251,7 @@ func (s *tokenService) ValidateToken(ctx context.Context, plainToken string) (*m
 	token, err := s.repo.FindByHash(ctx, tokenHash)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			s.ctx.Logger.Warn("token validation failed: token not found")
+			s.ctx.Logger.WarnContext(ctx, "token validation failed: token not found")
 			return nil, nil, ErrInvalidToken
 		}
 		return nil, nil, err
@@ -249,7 +259,7 @@ func (s *tokenService) ValidateToken(ctx context.Context, plainToken string) (*m
 
 	// Check expiration
 	if token.IsExpired() {
-		s.ctx.Logger.Warn("token validation failed: token expired", "name", token.Name)
+		s.ctx.Logger.WarnContext(ctx, "token validation failed: token expired", "name", token.Name)
 		return nil, nil, ErrTokenExpired
 	}
 
@@ -277,7 +287,7 @@ func (s *tokenService) GetAll(ctx context.Context) ([]model.Token, error) {
 }
 
 func (s *tokenService) SearchPaginate(ctx context.Context, pagination *commonTypes.PaginationInput, query *gorm.DB) (*model.TokenList, error) {
-	tokens, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	tokens, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
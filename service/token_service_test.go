@@ -9,6 +9,7 @@ import (
 
 	"github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/jwt"
 	mockFlectoRepository "github.com/flectolab/flecto-manager/mocks/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/model"
@@ -32,7 +33,7 @@ func setupTokenServiceTest(t *testing.T) (*tokenServiceMocks, TokenService) {
 		tokenRepo: mockFlectoRepository.NewMockTokenRepository(ctrl),
 		roleRepo:  mockFlectoRepository.NewMockRoleRepository(ctrl),
 	}
-	svc := NewTokenService(appContext.TestContext(nil), mocks.tokenRepo, mocks.roleRepo)
+	svc := NewTokenService(appContext.TestContext(nil), mocks.tokenRepo, mocks.roleRepo, invalidation.NewMemoryBus())
 	return mocks, svc
 }
 
@@ -197,7 +198,7 @@ func TestTokenService_SearchPaginate(t *testing.T) {
 		}
 
 		mocks.tokenRepo.EXPECT().
-			SearchPaginate(ctx, nil, 10, 5).
+			SearchPaginate(ctx, nil, 10, 5, nil).
 			Return(expectedTokens, int64(20), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -219,7 +220,7 @@ func TestTokenService_SearchPaginate(t *testing.T) {
 		expectedErr := errors.New("search error")
 
 		mocks.tokenRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(nil, int64(0), expectedErr)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -590,7 +591,7 @@ func setupTokenServiceIntegrationTest(t *testing.T) (*gorm.DB, TokenService) {
 	tokenRepo := repository.NewTokenRepository(db)
 	roleRepo := repository.NewRoleRepository(db)
 
-	svc := NewTokenService(appContext.TestContext(nil), tokenRepo, roleRepo)
+	svc := NewTokenService(appContext.TestContext(nil), tokenRepo, roleRepo, invalidation.NewMemoryBus())
 	return db, svc
 }
 
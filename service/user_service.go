@@ -3,20 +3,30 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
+	"unicode"
 
 	"github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	flectoErrors "github.com/flectolab/flecto-manager/errors"
 	"github.com/flectolab/flecto-manager/hash"
+	"github.com/flectolab/flecto-manager/i18n"
+	"github.com/flectolab/flecto-manager/invalidation"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/flectolab/flecto-manager/repository"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrUserNotFound       = errors.New("user not found")
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	ErrUserInactive       = errors.New("user account is inactive")
+	ErrUserNotFound        = flectoErrors.New(flectoErrors.CodeNotFound, "user not found")
+	ErrUserAlreadyExists   = flectoErrors.New(flectoErrors.CodeAlreadyExists, "user already exists")
+	ErrInvalidCredentials  = flectoErrors.New(flectoErrors.CodeInvalidCredentials, "invalid credentials")
+	ErrUserInactive        = flectoErrors.New(flectoErrors.CodeUserInactive, "user account is inactive")
+	ErrPasswordTooShort    = flectoErrors.New(flectoErrors.CodePasswordPolicy, "password is too short")
+	ErrPasswordTooWeak     = flectoErrors.New(flectoErrors.CodePasswordPolicy, "password does not mix enough character classes")
+	ErrPasswordReused      = flectoErrors.New(flectoErrors.CodePasswordPolicy, "password has already been used")
+	ErrUnsupportedLanguage = flectoErrors.New(flectoErrors.CodeInvalidRequest, "unsupported language").WithField("language")
 )
 
 type UserService interface {
@@ -30,10 +40,11 @@ type UserService interface {
 	GetAll(ctx context.Context) ([]model.User, error)
 	Search(ctx context.Context, query *gorm.DB) ([]model.User, error)
 	SearchPaginate(ctx context.Context, pagination *types.PaginationInput, query *gorm.DB) (*model.UserList, error)
-	UpdatePassword(ctx context.Context, id int64, newPassword string) error
+	UpdatePassword(ctx context.Context, id int64, newPassword string, mustChangePassword bool) error
 	UpdateStatus(ctx context.Context, id int64, active bool) (*model.User, error)
+	// UpdateLanguage sets the language of the user, one of i18n.Languages or empty to negotiate it from the requests
+	UpdateLanguage(ctx context.Context, id int64, language string) (*model.User, error)
 	SetPassword(ctx context.Context, id int64, newPassword string) error
-	UpdateRefreshToken(ctx context.Context, id int64, refreshTokenHash string) error
 	FindOrCreate(ctx context.Context, input *model.User) (*model.User, error)
 }
 
@@ -41,17 +52,20 @@ type userService struct {
 	ctx      *appContext.Context
 	repo     repository.UserRepository
 	roleRepo repository.RoleRepository
+	bus      invalidation.Bus
 }
 
 func NewUserService(
 	ctx *appContext.Context,
 	repo repository.UserRepository,
 	roleRepo repository.RoleRepository,
+	bus invalidation.Bus,
 ) UserService {
 	return &userService{
 		ctx:      ctx,
 		repo:     repo,
 		roleRepo: roleRepo,
+		bus:      bus,
 	}
 }
 
@@ -63,6 +77,7 @@ func (s *userService) GetQuery(ctx context.Context) *gorm.DB {
 	return s.repo.GetQuery(ctx)
 }
 
+// Create creates a user, its password, if any, is given in plain text and must match the password policy
 func (s *userService) Create(ctx context.Context, input *model.User) (*model.User, error) {
 	// Check if username already exists
 	existing, err := s.repo.FindByUsername(ctx, input.Username)
@@ -78,25 +93,38 @@ func (s *userService) Create(ctx context.Context, input *model.User) (*model.Use
 		return nil, err
 	}
 
+	if input.Password != "" {
+		if err = s.checkPasswordPolicy(input.Password); err != nil {
+			return nil, err
+		}
+		hashedPassword, errHash := hash.Password(input.Password)
+		if errHash != nil {
+			return nil, errHash
+		}
+		now := time.Now()
+		input.Password = string(hashedPassword)
+		input.PasswordChangedAt = &now
+	}
+
 	if err = s.repo.Create(ctx, input); err != nil {
-		s.ctx.Logger.Error("failed to create user", "username", input.Username, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to create user", "username", input.Username, "error", err)
 		return nil, err
 	}
 
 	role := &model.Role{Code: input.Username, Type: model.RoleTypeUser}
 	err = s.roleRepo.Create(ctx, role)
 	if err != nil {
-		s.ctx.Logger.Error("failed to create user role", "username", input.Username, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to create user role", "username", input.Username, "error", err)
 		return nil, err
 	}
 
 	err = s.roleRepo.AddUserToRole(ctx, input.ID, role.ID)
 	if err != nil {
-		s.ctx.Logger.Error("failed to assign role to user", "username", input.Username, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to assign role to user", "username", input.Username, "error", err)
 		return nil, err
 	}
 
-	s.ctx.Logger.Info("user created", "username", input.Username, "id", input.ID)
+	s.ctx.Logger.InfoContext(ctx, "user created", "username", input.Username, "id", input.ID)
 	return input, nil
 }
 
@@ -133,16 +161,17 @@ func (s *userService) Delete(ctx context.Context, id int64) (bool, error) {
 	}
 
 	if err = s.roleRepo.Delete(ctx, role.ID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		s.ctx.Logger.Error("failed to delete user role", "username", user.Username, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete user role", "username", user.Username, "error", err)
 		return false, err
 	}
 
 	if err = s.repo.Delete(ctx, id); err != nil {
-		s.ctx.Logger.Error("failed to delete user", "username", user.Username, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to delete user", "username", user.Username, "error", err)
 		return false, err
 	}
 
-	s.ctx.Logger.Info("user deleted", "username", user.Username, "id", id)
+	s.ctx.Logger.InfoContext(ctx, "user deleted", "username", user.Username, "id", id)
+	publishPermissionsChanged(ctx, s.ctx, s.bus)
 	return true, nil
 }
 
@@ -177,7 +206,7 @@ func (s *userService) Search(ctx context.Context, query *gorm.DB) ([]model.User,
 }
 
 func (s *userService) SearchPaginate(ctx context.Context, pagination *types.PaginationInput, query *gorm.DB) (*model.UserList, error) {
-	users, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset())
+	users, total, err := s.repo.SearchPaginate(ctx, query, pagination.GetLimit(), pagination.GetOffset(), pagination.GetOrderBy())
 	if err != nil {
 		return nil, err
 	}
@@ -190,14 +219,45 @@ func (s *userService) SearchPaginate(ctx context.Context, pagination *types.Pagi
 	}, nil
 }
 
-func (s *userService) UpdatePassword(ctx context.Context, id int64, newPassword string) error {
+// UpdatePassword sets a new password matching the password policy,
+// mustChangePassword forces the user to choose another one at next login
+func (s *userService) UpdatePassword(ctx context.Context, id int64, newPassword string, mustChangePassword bool) error {
+	user, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err = s.checkPasswordPolicy(newPassword); err != nil {
+		return err
+	}
+	if err = s.checkPasswordHistory(ctx, user, newPassword); err != nil {
+		return err
+	}
+
 	// Hash new password
 	hashedPassword, err := hash.Password(newPassword)
 	if err != nil {
 		return err
 	}
 
-	return s.repo.UpdatePassword(ctx, id, string(hashedPassword))
+	if err = s.repo.UpdatePassword(ctx, id, string(hashedPassword), mustChangePassword); err != nil {
+		s.ctx.Logger.ErrorContext(ctx, "failed to update user password", "username", user.Username, "error", err)
+		return err
+	}
+
+	historySize := s.ctx.Config.Auth.Password.History
+	if historySize > 0 && user.HasPassword() {
+		if err = s.repo.AddPasswordHistory(ctx, id, user.Password, historySize); err != nil {
+			s.ctx.Logger.ErrorContext(ctx, "failed to store user password history", "username", user.Username, "error", err)
+			return err
+		}
+	}
+
+	s.ctx.Logger.InfoContext(ctx, "user password updated", "username", user.Username, "mustChangePassword", mustChangePassword)
+	return nil
 }
 
 func (s *userService) UpdateStatus(ctx context.Context, id int64, active bool) (*model.User, error) {
@@ -210,34 +270,42 @@ func (s *userService) UpdateStatus(ctx context.Context, id int64, active bool) (
 	}
 
 	if err = s.repo.UpdateStatus(ctx, id, active); err != nil {
-		s.ctx.Logger.Error("failed to update user status", "username", user.Username, "active", active, "error", err)
+		s.ctx.Logger.ErrorContext(ctx, "failed to update user status", "username", user.Username, "active", active, "error", err)
 		return nil, err
 	}
 
-	s.ctx.Logger.Info("user status updated", "username", user.Username, "active", active)
+	s.ctx.Logger.InfoContext(ctx, "user status updated", "username", user.Username, "active", active)
 	user.Active = &active
 	return user, nil
 }
 
-func (s *userService) SetPassword(ctx context.Context, id int64, newPassword string) error {
-	_, err := s.repo.FindByID(ctx, id)
+func (s *userService) UpdateLanguage(ctx context.Context, id int64, language string) (*model.User, error) {
+	if language != "" {
+		lang, ok := i18n.Parse(language)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, language)
+		}
+		language = string(lang)
+	}
+
+	user, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrUserNotFound
+			return nil, ErrUserNotFound
 		}
-		return err
+		return nil, err
 	}
 
-	hashedPassword, err := hash.Password(newPassword)
-	if err != nil {
-		return err
+	if err = s.repo.UpdateLanguage(ctx, id, language); err != nil {
+		return nil, err
 	}
 
-	return s.repo.UpdatePassword(ctx, id, string(hashedPassword))
+	user.Language = language
+	return user, nil
 }
 
-func (s *userService) UpdateRefreshToken(ctx context.Context, id int64, refreshTokenHash string) error {
-	return s.repo.GetQuery(ctx).Where("id = ?", id).UpdateColumn("refresh_token_hash", refreshTokenHash).Error
+func (s *userService) SetPassword(ctx context.Context, id int64, newPassword string) error {
+	return s.UpdatePassword(ctx, id, newPassword, false)
 }
 
 func (s *userService) FindOrCreate(ctx context.Context, input *model.User) (*model.User, error) {
@@ -253,3 +321,59 @@ func (s *userService) FindOrCreate(ctx context.Context, input *model.User) (*mod
 	// User not found, create it
 	return s.Create(ctx, input)
 }
+
+// checkPasswordPolicy checks the length and the number of character classes
+// (lowercase, uppercase, digit and symbol) of a password
+func (s *userService) checkPasswordPolicy(password string) error {
+	policy := s.ctx.Config.Auth.Password
+	if len([]rune(password)) < policy.MinLength {
+		return fmt.Errorf("%w: at least %d characters required", ErrPasswordTooShort, policy.MinLength)
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	classes := 0
+	for _, used := range []bool{lower, upper, digit, symbol} {
+		if used {
+			classes++
+		}
+	}
+	if classes < policy.MinClasses {
+		return fmt.Errorf("%w: at least %d of lowercase, uppercase, digit and symbol required", ErrPasswordTooWeak, policy.MinClasses)
+	}
+	return nil
+}
+
+// checkPasswordHistory checks the password is neither the current one nor one of the previous ones kept in history
+func (s *userService) checkPasswordHistory(ctx context.Context, user *model.User, password string) error {
+	historySize := s.ctx.Config.Auth.Password.History
+	if historySize <= 0 {
+		return nil
+	}
+
+	if user.HasPassword() && hash.CheckPassword(user.Password, password) == nil {
+		return ErrPasswordReused
+	}
+
+	history, err := s.repo.FindPasswordHistory(ctx, user.ID, historySize)
+	if err != nil {
+		return err
+	}
+	for _, previous := range history {
+		if hash.CheckPassword(previous.Password, password) == nil {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
@@ -7,6 +7,8 @@ import (
 
 	"github.com/flectolab/flecto-manager/common/types"
 	appContext "github.com/flectolab/flecto-manager/context"
+	"github.com/flectolab/flecto-manager/hash"
+	"github.com/flectolab/flecto-manager/invalidation"
 	mockFlectoRepository "github.com/flectolab/flecto-manager/mocks/flecto-manager/repository"
 	"github.com/flectolab/flecto-manager/model"
 	"github.com/stretchr/testify/assert"
@@ -22,7 +24,7 @@ func setupUserServiceTest(t *testing.T) (*gomock.Controller, *mockFlectoReposito
 	ctrl := gomock.NewController(t)
 	mockUserRepo := mockFlectoRepository.NewMockUserRepository(ctrl)
 	mockRoleRepo := mockFlectoRepository.NewMockRoleRepository(ctrl)
-	svc := NewUserService(appContext.TestContext(nil), mockUserRepo, mockRoleRepo)
+	svc := NewUserService(appContext.TestContext(nil), mockUserRepo, mockRoleRepo, invalidation.NewMemoryBus())
 	return ctrl, mockUserRepo, mockRoleRepo, svc
 }
 
@@ -77,6 +79,52 @@ func TestUserService_Create(t *testing.T) {
 		assert.Equal(t, input, result)
 	})
 
+	t.Run("success with password", func(t *testing.T) {
+		ctrl, mockUserRepo, mockRoleRepo, svc := setupUserServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		input := &model.User{
+			Username:  "newuser",
+			Password:  "newpassword",
+			Firstname: "New",
+			Lastname:  "User",
+			Active:    boolPtr(true),
+		}
+
+		mockUserRepo.EXPECT().FindByUsername(ctx, "newuser").Return(nil, gorm.ErrRecordNotFound)
+		mockUserRepo.EXPECT().Create(ctx, input).Return(nil)
+		mockRoleRepo.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+		mockRoleRepo.EXPECT().AddUserToRole(ctx, gomock.Any(), gomock.Any()).Return(nil)
+
+		result, err := svc.Create(ctx, input)
+
+		assert.NoError(t, err)
+		assert.NoError(t, hash.CheckPassword(result.Password, "newpassword"))
+		assert.NotNil(t, result.PasswordChangedAt)
+	})
+
+	t.Run("password not matching policy", func(t *testing.T) {
+		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		input := &model.User{
+			Username:  "newuser",
+			Password:  "short",
+			Firstname: "New",
+			Lastname:  "User",
+			Active:    boolPtr(true),
+		}
+
+		mockUserRepo.EXPECT().FindByUsername(ctx, "newuser").Return(nil, gorm.ErrRecordNotFound)
+
+		result, err := svc.Create(ctx, input)
+
+		assert.ErrorIs(t, err, ErrPasswordTooShort)
+		assert.Nil(t, result)
+	})
+
 	t.Run("user already exists", func(t *testing.T) {
 		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
 		defer ctrl.Finish()
@@ -749,7 +797,7 @@ func TestUserService_SearchPaginate(t *testing.T) {
 		}
 
 		mockUserRepo.EXPECT().
-			SearchPaginate(ctx, nil, 10, 5).
+			SearchPaginate(ctx, nil, 10, 5, nil).
 			Return(expectedUsers, int64(20), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -773,7 +821,7 @@ func TestUserService_SearchPaginate(t *testing.T) {
 		}
 
 		mockUserRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(expectedUsers, int64(1), nil)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -793,7 +841,7 @@ func TestUserService_SearchPaginate(t *testing.T) {
 		expectedErr := errors.New("search error")
 
 		mockUserRepo.EXPECT().
-			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset).
+			SearchPaginate(ctx, nil, types.DefaultLimit, types.DefaultOffset, nil).
 			Return(nil, int64(0), expectedErr)
 
 		result, err := svc.SearchPaginate(ctx, pagination, nil)
@@ -811,14 +859,104 @@ func TestUserService_UpdatePassword(t *testing.T) {
 		ctx := context.Background()
 
 		mockUserRepo.EXPECT().
-			UpdatePassword(ctx, int64(1), gomock.Any()).
+			FindByID(ctx, int64(1)).
+			Return(&model.User{ID: 1, Username: "testuser"}, nil)
+
+		mockUserRepo.EXPECT().
+			UpdatePassword(ctx, int64(1), gomock.Any(), true).
 			Return(nil)
 
-		err := svc.UpdatePassword(ctx, 1, "newpassword")
+		err := svc.UpdatePassword(ctx, 1, "newpassword", true)
 
 		assert.NoError(t, err)
 	})
 
+	t.Run("user not found", func(t *testing.T) {
+		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		mockUserRepo.EXPECT().
+			FindByID(ctx, int64(999)).
+			Return(nil, gorm.ErrRecordNotFound)
+
+		err := svc.UpdatePassword(ctx, 999, "newpassword", false)
+
+		assert.Equal(t, ErrUserNotFound, err)
+	})
+
+	t.Run("password too short", func(t *testing.T) {
+		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+
+		mockUserRepo.EXPECT().
+			FindByID(ctx, int64(1)).
+			Return(&model.User{ID: 1, Username: "testuser"}, nil)
+
+		err := svc.UpdatePassword(ctx, 1, "short", false)
+
+		assert.ErrorIs(t, err, ErrPasswordTooShort)
+	})
+
+	t.Run("password with too few character classes", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockUserRepo := mockFlectoRepository.NewMockUserRepository(ctrl)
+		appCtx := appContext.TestContext(nil)
+		appCtx.Config.Auth.Password.MinClasses = 3
+		svc := NewUserService(appCtx, mockUserRepo, mockFlectoRepository.NewMockRoleRepository(ctrl), invalidation.NewMemoryBus())
+
+		ctx := context.Background()
+
+		mockUserRepo.EXPECT().
+			FindByID(ctx, int64(1)).
+			Return(&model.User{ID: 1, Username: "testuser"}, nil).
+			Times(2)
+
+		err := svc.UpdatePassword(ctx, 1, "newpassword1", false)
+		assert.ErrorIs(t, err, ErrPasswordTooWeak)
+
+		mockUserRepo.EXPECT().
+			UpdatePassword(ctx, int64(1), gomock.Any(), false).
+			Return(nil)
+
+		err = svc.UpdatePassword(ctx, 1, "newPassword1", false)
+		assert.NoError(t, err)
+	})
+
+	t.Run("password history", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockUserRepo := mockFlectoRepository.NewMockUserRepository(ctrl)
+		appCtx := appContext.TestContext(nil)
+		appCtx.Config.Auth.Password.History = 2
+		svc := NewUserService(appCtx, mockUserRepo, mockFlectoRepository.NewMockRoleRepository(ctrl), invalidation.NewMemoryBus())
+
+		ctx := context.Background()
+		currentHash, _ := hash.Password("currentpassword")
+		previousHash, _ := hash.Password("previouspassword")
+		user := &model.User{ID: 1, Username: "testuser", Password: string(currentHash)}
+		history := []model.UserPasswordHistory{{UserID: 1, Password: string(previousHash)}}
+
+		mockUserRepo.EXPECT().FindByID(ctx, int64(1)).Return(user, nil).Times(3)
+		mockUserRepo.EXPECT().FindPasswordHistory(ctx, int64(1), 2).Return(history, nil).Times(2)
+
+		err := svc.UpdatePassword(ctx, 1, "currentpassword", false)
+		assert.Equal(t, ErrPasswordReused, err)
+
+		err = svc.UpdatePassword(ctx, 1, "previouspassword", false)
+		assert.Equal(t, ErrPasswordReused, err)
+
+		mockUserRepo.EXPECT().UpdatePassword(ctx, int64(1), gomock.Any(), false).Return(nil)
+		mockUserRepo.EXPECT().AddPasswordHistory(ctx, int64(1), string(currentHash), 2).Return(nil)
+
+		err = svc.UpdatePassword(ctx, 1, "newpassword", false)
+		assert.NoError(t, err)
+	})
+
 	t.Run("repository error", func(t *testing.T) {
 		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
 		defer ctrl.Finish()
@@ -827,17 +965,21 @@ func TestUserService_UpdatePassword(t *testing.T) {
 		expectedErr := errors.New("database error")
 
 		mockUserRepo.EXPECT().
-			UpdatePassword(ctx, int64(1), gomock.Any()).
+			FindByID(ctx, int64(1)).
+			Return(&model.User{ID: 1, Username: "testuser"}, nil)
+
+		mockUserRepo.EXPECT().
+			UpdatePassword(ctx, int64(1), gomock.Any(), false).
 			Return(expectedErr)
 
-		err := svc.UpdatePassword(ctx, 1, "newpassword")
+		err := svc.UpdatePassword(ctx, 1, "newpassword", false)
 
 		assert.Error(t, err)
 		assert.Equal(t, expectedErr, err)
 	})
 
 	t.Run("bcrypt error with too long password", func(t *testing.T) {
-		ctrl, _, _, svc := setupUserServiceTest(t)
+		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
 		defer ctrl.Finish()
 
 		ctx := context.Background()
@@ -845,7 +987,11 @@ func TestUserService_UpdatePassword(t *testing.T) {
 		// Password longer than 72 bytes triggers bcrypt error
 		longPassword := string(make([]byte, 73))
 
-		err := svc.UpdatePassword(ctx, 1, longPassword)
+		mockUserRepo.EXPECT().
+			FindByID(ctx, int64(1)).
+			Return(&model.User{ID: 1, Username: "testuser"}, nil)
+
+		err := svc.UpdatePassword(ctx, 1, longPassword, false)
 
 		assert.Error(t, err)
 	})
@@ -962,6 +1108,67 @@ func TestUserService_UpdateStatus(t *testing.T) {
 	})
 }
 
+func TestUserService_UpdateLanguage(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		mockUserRepo.EXPECT().
+			FindByID(ctx, int64(1)).
+			Return(&model.User{ID: 1, Username: "testuser"}, nil)
+		mockUserRepo.EXPECT().
+			UpdateLanguage(ctx, int64(1), "fr").
+			Return(nil)
+
+		result, err := svc.UpdateLanguage(ctx, 1, "fr-FR")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "fr", result.Language)
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		mockUserRepo.EXPECT().
+			FindByID(ctx, int64(1)).
+			Return(&model.User{ID: 1, Username: "testuser", Language: "fr"}, nil)
+		mockUserRepo.EXPECT().
+			UpdateLanguage(ctx, int64(1), "").
+			Return(nil)
+
+		result, err := svc.UpdateLanguage(ctx, 1, "")
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Language)
+	})
+
+	t.Run("unsupported language", func(t *testing.T) {
+		ctrl, _, _, svc := setupUserServiceTest(t)
+		defer ctrl.Finish()
+
+		_, err := svc.UpdateLanguage(context.Background(), 1, "it")
+
+		assert.ErrorIs(t, err, ErrUnsupportedLanguage)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		mockUserRepo.EXPECT().
+			FindByID(ctx, int64(1)).
+			Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := svc.UpdateLanguage(ctx, 1, "de")
+
+		assert.ErrorIs(t, err, ErrUserNotFound)
+	})
+}
+
 func TestUserService_SetPassword(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		ctrl, mockUserRepo, _, svc := setupUserServiceTest(t)
@@ -978,7 +1185,7 @@ func TestUserService_SetPassword(t *testing.T) {
 			Return(existingUser, nil)
 
 		mockUserRepo.EXPECT().
-			UpdatePassword(ctx, int64(1), gomock.Any()).
+			UpdatePassword(ctx, int64(1), gomock.Any(), false).
 			Return(nil)
 
 		err := svc.SetPassword(ctx, 1, "newpassword")
@@ -1015,7 +1222,7 @@ func TestUserService_SetPassword(t *testing.T) {
 			Return(existingUser, nil)
 
 		mockUserRepo.EXPECT().
-			UpdatePassword(ctx, int64(1), gomock.Any()).
+			UpdatePassword(ctx, int64(1), gomock.Any(), false).
 			Return(expectedErr)
 
 		err := svc.SetPassword(ctx, 1, "newpassword")
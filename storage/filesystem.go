@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// FilesystemStore stores the contents as files of a directory, spread in sub-directories by the first
+// characters of their key
+type FilesystemStore struct {
+	dir string
+}
+
+func NewFilesystemStore(cfg config.FilesystemStorageConfig) (*FilesystemStore, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("page storage fs requires a path")
+	}
+	if err := os.MkdirAll(cfg.Path, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create page storage directory: %w", err)
+	}
+	return &FilesystemStore{dir: cfg.Path}, nil
+}
+
+func (s *FilesystemStore) Name() model.PageContentStore {
+	return model.PageContentStoreFilesystem
+}
+
+func (s *FilesystemStore) Put(_ context.Context, content string) (string, error) {
+	key := contentKey(content)
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+	if _, err = os.Stat(path); err == nil {
+		return key, nil
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", err
+	}
+
+	// The content is written to a temporary file renamed once complete, a reader never seeing a partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), key+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err = tmp.WriteString(content); err != nil {
+		_ = tmp.Close()
+		return "", err
+	}
+	if err = tmp.Close(); err != nil {
+		return "", err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (s *FilesystemStore) Get(_ context.Context, ref string) (string, error) {
+	path, err := s.path(ref)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("%w: %s", ErrContentNotFound, ref)
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// path returns the file of a key, refusing the keys which are not a hash so that no other file can be read
+func (s *FilesystemStore) path(key string) (string, error) {
+	if !isContentKey(key) {
+		return "", fmt.Errorf("invalid page content key '%s'", key)
+	}
+	return filepath.Join(s.dir, key[:2], key), nil
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesystemStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pages")
+	store, err := NewFilesystemStore(config.FilesystemStorageConfig{Path: dir})
+	require.NoError(t, err)
+
+	key, err := store.Put(context.Background(), "content")
+	require.NoError(t, err)
+	assert.Equal(t, contentKey("content"), key)
+	stored, err := os.ReadFile(filepath.Join(dir, key[:2], key))
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(stored))
+
+	// Storing the same content again keeps its file
+	again, err := store.Put(context.Background(), "content")
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+	entries, err := os.ReadDir(filepath.Join(dir, key[:2]))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	content, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	assert.Equal(t, "content", content)
+
+	_, err = store.Get(context.Background(), contentKey("other"))
+	assert.ErrorIs(t, err, ErrContentNotFound)
+
+	_, err = store.Get(context.Background(), "../secret")
+	assert.ErrorContains(t, err, "invalid page content key")
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/flectolab/flecto-manager/sigv4"
+)
+
+const (
+	s3Service       = "s3"
+	s3DefaultRegion = "us-east-1"
+	s3Timeout       = 30 * time.Second
+)
+
+// S3Store stores the contents as objects of an S3 bucket, the requests being signed with AWS Signature Version 4
+type S3Store struct {
+	endpoint    *url.URL
+	region      string
+	bucket      string
+	prefix      string
+	pathStyle   bool
+	credentials sigv4.Credentials
+	client      *http.Client
+}
+
+func NewS3Store(cfg config.S3StorageConfig) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("page storage s3 requires a bucket")
+	}
+	region := cfg.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+	rawEndpoint := cfg.Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid page storage s3 endpoint '%s'", rawEndpoint)
+	}
+	return &S3Store{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    cfg.Bucket,
+		prefix:    cfg.Prefix,
+		pathStyle: cfg.PathStyle,
+		credentials: sigv4.Credentials{
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+			SessionToken:    cfg.SessionToken,
+		},
+		client: &http.Client{Timeout: s3Timeout},
+	}, nil
+}
+
+func (s *S3Store) Name() model.PageContentStore {
+	return model.PageContentStoreS3
+}
+
+func (s *S3Store) Put(ctx context.Context, content string) (string, error) {
+	key := contentKey(content)
+	resp, err := s.do(ctx, http.MethodPut, key, []byte(content))
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("s3 put of page content returned status %d", resp.StatusCode)
+	}
+	return key, nil
+}
+
+func (s *S3Store) Get(ctx context.Context, ref string) (string, error) {
+	if !isContentKey(ref) {
+		return "", fmt.Errorf("invalid page content key '%s'", ref)
+	}
+	resp, err := s.do(ctx, http.MethodGet, ref, nil)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return "", fmt.Errorf("%w: %s", ErrContentNotFound, ref)
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("s3 get of page content returned status %d", resp.StatusCode)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string, payload []byte) (*http.Response, error) {
+	objectURL := *s.endpoint
+	objectPath := "/" + s.prefix + key
+	if s.pathStyle {
+		objectPath = "/" + s.bucket + objectPath
+	} else {
+		objectURL.Host = s.bucket + "." + objectURL.Host
+	}
+	objectURL.Path = strings.TrimRight(objectURL.Path, "/") + objectPath
+
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, objectURL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Amz-Content-Sha256", sigv4.PayloadHash(payload))
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	sigv4.Sign(req, payload, s.credentials, s.region, s3Service, time.Now())
+	return s.client.Do(req)
+}
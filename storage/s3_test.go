@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/sigv4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is an S3 bucket in memory, checking the requests are signed
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") ||
+		!strings.Contains(r.Header.Get("Authorization"), "/eu-west-3/s3/aws4_request") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Content-Sha256") != sigv4.PayloadHash(body) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		object, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(object)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	bucket := &fakeS3{objects: map[string][]byte{}}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+
+	store, err := NewS3Store(config.S3StorageConfig{
+		Endpoint:        server.URL,
+		Region:          "eu-west-3",
+		Bucket:          "flecto",
+		Prefix:          "pages/",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	})
+	require.NoError(t, err)
+
+	key, err := store.Put(context.Background(), "content")
+	require.NoError(t, err)
+	assert.Equal(t, contentKey("content"), key)
+	assert.Equal(t, []byte("content"), bucket.objects["/flecto/pages/"+key])
+
+	content, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	assert.Equal(t, "content", content)
+
+	_, err = store.Get(context.Background(), contentKey("other"))
+	assert.ErrorIs(t, err, ErrContentNotFound)
+
+	_, err = store.Get(context.Background(), "../other-bucket/key")
+	assert.ErrorContains(t, err, "invalid page content key")
+
+	unsigned, err := NewS3Store(config.S3StorageConfig{Endpoint: server.URL, Bucket: "flecto", PathStyle: true})
+	require.NoError(t, err)
+	_, err = unsigned.Put(context.Background(), "content")
+	assert.ErrorContains(t, err, "status 403")
+}
+
+func TestNewS3Store(t *testing.T) {
+	store, err := NewS3Store(config.S3StorageConfig{Bucket: "flecto", Region: "eu-west-3"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://s3.eu-west-3.amazonaws.com", store.endpoint.String())
+
+	store, err = NewS3Store(config.S3StorageConfig{Bucket: "flecto"})
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-1", store.region)
+
+	_, err = NewS3Store(config.S3StorageConfig{Bucket: "flecto", Endpoint: "not a url"})
+	assert.ErrorContains(t, err, "invalid page storage s3 endpoint")
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/model"
+)
+
+// ErrContentNotFound is returned when a store has no content for a key
+var ErrContentNotFound = errors.New("page content not found")
+
+// ContentStore stores the page contents. The content column of a page holds the reference returned by Put,
+// which is the content itself for the database and the key of the content for the other stores.
+type ContentStore interface {
+	// Name is recorded with the contents written by the store, to read them back from it
+	Name() model.PageContentStore
+	Put(ctx context.Context, content string) (string, error)
+	Get(ctx context.Context, ref string) (string, error)
+}
+
+// New returns the store of the configured backend
+func New(cfg config.PageStorageConfig) (ContentStore, error) {
+	switch cfg.Backend {
+	case "", config.PageStorageDatabase:
+		return Database{}, nil
+	case config.PageStorageS3:
+		return NewS3Store(cfg.S3)
+	case config.PageStorageFilesystem:
+		return NewFilesystemStore(cfg.Filesystem)
+	default:
+		return nil, fmt.Errorf("unknown page storage backend '%s'", cfg.Backend)
+	}
+}
+
+// Configured returns the stores of all the configured backends, so that the contents written to a previous
+// backend are still read while its configuration is kept
+func Configured(cfg config.PageStorageConfig) ([]ContentStore, error) {
+	stores := []ContentStore{Database{}}
+	if cfg.S3.Bucket != "" {
+		store, err := NewS3Store(cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+	if cfg.Filesystem.Path != "" {
+		store, err := NewFilesystemStore(cfg.Filesystem)
+		if err != nil {
+			return nil, err
+		}
+		stores = append(stores, store)
+	}
+	return stores, nil
+}
+
+// Database keeps the contents in the content columns of the pages
+type Database struct{}
+
+func (Database) Name() model.PageContentStore {
+	return model.PageContentStoreDatabase
+}
+
+func (Database) Put(_ context.Context, content string) (string, error) {
+	return content, nil
+}
+
+func (Database) Get(_ context.Context, ref string) (string, error) {
+	return ref, nil
+}
+
+// contentKey addresses a content by its hash, so that the pages, drafts and tombstones sharing a content share
+// its object, and an object is never overwritten by another content. The objects are thus never deleted.
+func contentKey(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func isContentKey(key string) bool {
+	if len(key) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(key)
+	return err == nil
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/flectolab/flecto-manager/config"
+	"github.com/flectolab/flecto-manager/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	store, err := New(config.PageStorageConfig{})
+	require.NoError(t, err)
+	assert.Equal(t, Database{}, store)
+
+	store, err = New(config.PageStorageConfig{Backend: config.PageStorageFilesystem, Filesystem: config.FilesystemStorageConfig{Path: t.TempDir()}})
+	require.NoError(t, err)
+	assert.Equal(t, model.PageContentStoreFilesystem, store.Name())
+
+	store, err = New(config.PageStorageConfig{Backend: config.PageStorageS3, S3: config.S3StorageConfig{Bucket: "pages"}})
+	require.NoError(t, err)
+	assert.Equal(t, model.PageContentStoreS3, store.Name())
+
+	_, err = New(config.PageStorageConfig{Backend: config.PageStorageS3})
+	assert.ErrorContains(t, err, "requires a bucket")
+
+	_, err = New(config.PageStorageConfig{Backend: config.PageStorageFilesystem})
+	assert.ErrorContains(t, err, "requires a path")
+
+	_, err = New(config.PageStorageConfig{Backend: "ftp"})
+	assert.ErrorContains(t, err, "unknown page storage backend 'ftp'")
+}
+
+func TestConfigured(t *testing.T) {
+	stores, err := Configured(config.PageStorageConfig{Backend: config.PageStorageDatabase})
+	require.NoError(t, err)
+	assert.Equal(t, []ContentStore{Database{}}, stores)
+
+	stores, err = Configured(config.PageStorageConfig{
+		Backend:    config.PageStorageS3,
+		S3:         config.S3StorageConfig{Bucket: "pages"},
+		Filesystem: config.FilesystemStorageConfig{Path: t.TempDir()},
+	})
+	require.NoError(t, err)
+	require.Len(t, stores, 3)
+	assert.Equal(t, model.PageContentStoreS3, stores[1].Name())
+	assert.Equal(t, model.PageContentStoreFilesystem, stores[2].Name())
+
+	_, err = Configured(config.PageStorageConfig{S3: config.S3StorageConfig{Bucket: "pages", Endpoint: "not a url"}})
+	assert.Error(t, err)
+}
+
+func TestDatabase(t *testing.T) {
+	ref, err := Database{}.Put(context.Background(), "content")
+	require.NoError(t, err)
+	assert.Equal(t, "content", ref)
+
+	content, err := Database{}.Get(context.Background(), ref)
+	require.NoError(t, err)
+	assert.Equal(t, "content", content)
+	assert.Equal(t, model.PageContentStoreDatabase, Database{}.Name())
+}
+
+func TestContentKey(t *testing.T) {
+	key := contentKey("content")
+	assert.Equal(t, "ed7002b439e9ac845f22357d822bac1444730fbdb6016d3ec9432297b9ec9f73", key)
+	assert.True(t, isContentKey(key))
+	assert.False(t, isContentKey("../../etc/passwd"))
+	assert.False(t, isContentKey(key[:63]+"z"))
+}
@@ -10,26 +10,42 @@ import (
 	"github.com/flectolab/flecto-manager/database"
 )
 
-// uniqueIndexes defines composite unique indexes that cannot be expressed
+// uniqueIndexes defines composite indexes that cannot be expressed
 // via GORM tags due to embedded struct limitations.
+// Redirect sources are not unique, a source can be used by redirects having different conditions.
 // Format: table_name -> index definition (without the trailing comma)
 var uniqueIndexes = map[string]string{
 	"agents":          "UNIQUE INDEX `idx_agents_namespace_project_name` (`namespace_code`, `project_code`, `name`)",
 	"pages":           "UNIQUE INDEX `idx_pages_path_unique` (`namespace_code`, `project_code`, `path`)",
 	"page_drafts":     "UNIQUE INDEX `idx_page_drafts_path_unique` (`namespace_code`, `project_code`, `new_path`)",
-	"redirects":       "UNIQUE INDEX `idx_redirects_source_unique` (`namespace_code`, `project_code`, `source`)",
-	"redirect_drafts": "UNIQUE INDEX `idx_redirect_drafts_source_unique` (`namespace_code`, `project_code`, `new_source`)",
+	"redirects":       "INDEX `idx_redirects_source` (`namespace_code`, `project_code`, `source`)",
+	"redirect_drafts": "INDEX `idx_redirect_drafts_source` (`namespace_code`, `project_code`, `new_source`)",
 	"projects":        "UNIQUE INDEX `idx_projects_namespace_project` (`namespace_code`, `project_code`)",
 }
 
+// fulltextIndexes defines the FULLTEXT indexes used by the search on MySQL,
+// they are not declared via GORM tags as SQLite does not support them.
+var fulltextIndexes = map[string][]string{
+	"pages": {
+		"FULLTEXT INDEX `idx_pages_fulltext_path` (`path`)",
+		"FULLTEXT INDEX `idx_pages_fulltext` (`path`, `content`)",
+	},
+	"redirects": {
+		"FULLTEXT INDEX `idx_redirects_fulltext_source` (`source`)",
+		"FULLTEXT INDEX `idx_redirects_fulltext` (`source`, `target`)",
+	},
+}
+
 // removeConstraints lists FK constraints generated by gormschema that need to be
 // removed (either incorrect direction or missing CASCADE).
 var removeConstraints = []string{
 	"fk_agents_project",
+	"fk_import_jobs_project",
 	"fk_pages_project",
 	"fk_page_drafts_project",
 	"fk_redirects_project",
 	"fk_redirect_drafts_project",
+	"fk_tags_project",
 	"fk_projects_namespace",
 	"fk_pages_page_draft",
 	"fk_redirects_redirect_draft",
@@ -41,10 +57,12 @@ var customForeignKeys = []string{
 	// Project hierarchy: Namespace -> Project -> Resources
 	"ALTER TABLE `projects` ADD CONSTRAINT `fk_projects_namespace` FOREIGN KEY (`namespace_code`) REFERENCES `namespaces`(`namespace_code`) ON DELETE CASCADE;",
 	"ALTER TABLE `agents` ADD CONSTRAINT `fk_agents_project` FOREIGN KEY (`namespace_code`,`project_code`) REFERENCES `projects`(`namespace_code`,`project_code`) ON DELETE CASCADE;",
+	"ALTER TABLE `import_jobs` ADD CONSTRAINT `fk_import_jobs_project` FOREIGN KEY (`namespace_code`,`project_code`) REFERENCES `projects`(`namespace_code`,`project_code`) ON DELETE CASCADE;",
 	"ALTER TABLE `pages` ADD CONSTRAINT `fk_pages_project` FOREIGN KEY (`namespace_code`,`project_code`) REFERENCES `projects`(`namespace_code`,`project_code`) ON DELETE CASCADE;",
 	"ALTER TABLE `page_drafts` ADD CONSTRAINT `fk_page_drafts_project` FOREIGN KEY (`namespace_code`,`project_code`) REFERENCES `projects`(`namespace_code`,`project_code`) ON DELETE CASCADE;",
 	"ALTER TABLE `redirects` ADD CONSTRAINT `fk_redirects_project` FOREIGN KEY (`namespace_code`,`project_code`) REFERENCES `projects`(`namespace_code`,`project_code`) ON DELETE CASCADE;",
 	"ALTER TABLE `redirect_drafts` ADD CONSTRAINT `fk_redirect_drafts_project` FOREIGN KEY (`namespace_code`,`project_code`) REFERENCES `projects`(`namespace_code`,`project_code`) ON DELETE CASCADE;",
+	"ALTER TABLE `tags` ADD CONSTRAINT `fk_tags_project` FOREIGN KEY (`namespace_code`,`project_code`) REFERENCES `projects`(`namespace_code`,`project_code`) ON DELETE CASCADE;",
 	"ALTER TABLE `page_drafts` ADD CONSTRAINT `fk_pages_page_draft` FOREIGN KEY (`old_page_id`) REFERENCES `pages`(`id`) ON DELETE CASCADE;",
 	"ALTER TABLE `redirect_drafts` ADD CONSTRAINT `fk_redirects_redirect_draft` FOREIGN KEY (`old_redirect_id`) REFERENCES `redirects`(`id`) ON DELETE CASCADE;",
 }
@@ -59,6 +77,9 @@ func main() {
 	// Add unique indexes to the generated SQL
 	stmts = addUniqueIndexes(stmts)
 
+	// Add FULLTEXT indexes to the generated SQL
+	stmts = addFulltextIndexes(stmts)
+
 	// Remove incorrectly generated FK constraints
 	stmts = removeIncorrectConstraints(stmts)
 
@@ -71,28 +92,43 @@ func main() {
 // addUniqueIndexes injects unique index definitions into CREATE TABLE statements
 func addUniqueIndexes(sql string) string {
 	for table, indexDef := range uniqueIndexes {
-		// Find the CREATE TABLE statement for this table
-		tableMarker := fmt.Sprintf("CREATE TABLE `%s`", table)
-		tableStart := strings.Index(sql, tableMarker)
-		if tableStart == -1 {
-			continue
-		}
+		sql = addTableIndex(sql, table, indexDef)
+	}
 
-		// Find the closing ");" of the CREATE TABLE
-		tableEnd := strings.Index(sql[tableStart:], ");")
-		if tableEnd == -1 {
-			continue
+	return sql
+}
+
+// addFulltextIndexes injects FULLTEXT index definitions into CREATE TABLE statements
+func addFulltextIndexes(sql string) string {
+	for table, indexDefs := range fulltextIndexes {
+		for _, indexDef := range indexDefs {
+			sql = addTableIndex(sql, table, indexDef)
 		}
+	}
+
+	return sql
+}
 
-		// Insert position is just before the ");"
-		insertPos := tableStart + tableEnd
+// addTableIndex injects an index definition into the CREATE TABLE statement of a table
+func addTableIndex(sql, table, indexDef string) string {
+	// Find the CREATE TABLE statement for this table
+	tableMarker := fmt.Sprintf("CREATE TABLE `%s`", table)
+	tableStart := strings.Index(sql, tableMarker)
+	if tableStart == -1 {
+		return sql
+	}
 
-		// Insert ",UNIQUE INDEX ..." before the closing ");"
-		indexInsert := "," + indexDef
-		sql = sql[:insertPos] + indexInsert + sql[insertPos:]
+	// Find the closing ");" of the CREATE TABLE
+	tableEnd := strings.Index(sql[tableStart:], ");")
+	if tableEnd == -1 {
+		return sql
 	}
 
-	return sql
+	// Insert position is just before the ");"
+	insertPos := tableStart + tableEnd
+
+	// Insert ",INDEX ..." before the closing ");"
+	return sql[:insertPos] + "," + indexDef + sql[insertPos:]
 }
 
 // removeIncorrectConstraints removes FK constraints that gormschema generated
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// ReadOnlyMode is the read-only maintenance mode of the manager, returned and switched by the admin API
+type ReadOnlyMode struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message,omitempty" validate:"max=500"`
+}
+
+// ScheduledTask is a recurring task run by the scheduler
+type ScheduledTask struct {
+	Name string
+	// Interval is the default schedule of the task, which has none when 0
+	Interval time.Duration
+	// Enabled tells whether the feature of the task is enabled by its configuration
+	Enabled bool
+	// Run runs the task once. The runs are skipped in read-only mode, all the tasks changing data.
+	Run func(ctx context.Context, now time.Time) error
+}
+
+// ScheduledTaskStatus is the status of a recurring task of the scheduler on the replica answering, returned by the
+// admin API
+type ScheduledTaskStatus struct {
+	Name string `json:"name"`
+	// Schedule is the cron expression of the task, "@every <duration>" for the tasks running at an interval
+	Schedule string `json:"schedule"`
+	Enabled  bool   `json:"enabled"`
+	// Overridden is true when the schedule or the switch was changed by the admin API since the last reload
+	Overridden bool `json:"overridden"`
+	Running    bool `json:"running"`
+	// LastRunAt is when the last run started, nil when the task did not run since the replica started
+	LastRunAt      *time.Time `json:"lastRunAt,omitempty"`
+	LastDurationMs int64      `json:"lastDurationMs"`
+	LastError      string     `json:"lastError,omitempty"`
+	// NextRunAt is nil when the task is disabled
+	NextRunAt *time.Time `json:"nextRunAt,omitempty"`
+}
+
+// ScheduledTaskUpdate changes the schedule of a recurring task or switches it, the fields missing being kept. An empty
+// schedule takes the one of the configuration again.
+type ScheduledTaskUpdate struct {
+	Schedule *string `json:"schedule,omitempty" validate:"omitempty,schedule"`
+	Enabled  *bool   `json:"enabled,omitempty"`
+}
@@ -0,0 +1,12 @@
+package types
+
+// AgentInstanceFilter restricts the registered agents listed, its empty fields matching all agents
+type AgentInstanceFilter struct {
+	// NamespaceCode and ProjectCode keep the agents serving the project, or any project of the namespace when ProjectCode is empty
+	NamespaceCode string
+	ProjectCode   string
+	// State keeps the agents online or stale
+	State string
+	// Outdated keeps the agents serving an older version than the latest one of a project when true, the up to date agents when false
+	Outdated *bool
+}
@@ -7,9 +7,11 @@ const (
 	TokenTypeAccess  TokenType = "access"
 	TokenTypeRefresh TokenType = "refresh"
 
-	AuthTypeBasic  AuthType = "basic"
-	AuthTypeToken  AuthType = "token"
-	AuthTypeOpenID AuthType = "openid"
+	AuthTypeBasic         AuthType = "basic"
+	AuthTypeToken         AuthType = "token"
+	AuthTypeOpenID        AuthType = "openid"
+	AuthTypeImpersonation AuthType = "impersonation"
+	AuthTypeProjectAPIKey AuthType = "project_api_key"
 )
 
 type TokenPair struct {
@@ -23,6 +25,10 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+type ImpersonateRequest struct {
+	Username string `json:"username" validate:"required"`
+}
+
 type RefreshRequest struct {
 	RefreshToken string `json:"refreshToken" validate:"required"`
 }
@@ -33,8 +39,9 @@ type AuthResponse struct {
 }
 
 type UserResponse struct {
-	ID        int64  `json:"id"`
-	Username  string `json:"username"`
-	Firstname string `json:"firstname"`
-	Lastname  string `json:"lastname"`
+	ID                 int64  `json:"id"`
+	Username           string `json:"username"`
+	Firstname          string `json:"firstname"`
+	Lastname           string `json:"lastname"`
+	MustChangePassword bool   `json:"mustChangePassword"`
 }
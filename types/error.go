@@ -1,6 +0,0 @@
-package types
-
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-}
@@ -0,0 +1,53 @@
+package types
+
+import "time"
+
+type HealthStatus string
+
+const (
+	HealthStatusOK   HealthStatus = "ok"
+	HealthStatusFail HealthStatus = "fail"
+)
+
+// HealthCheck is the result of a check of a health report
+type HealthCheck struct {
+	Status HealthStatus `json:"status"`
+	Error  string       `json:"error,omitempty"`
+}
+
+// HealthReport is the result of the liveness or readiness checks, it is failed when one of its checks is
+type HealthReport struct {
+	Status  HealthStatus           `json:"status"`
+	Checks  map[string]HealthCheck `json:"checks"`
+	Workers []WorkerStatus         `json:"workers,omitempty"`
+}
+
+// WorkerStatus is the last heartbeat of a background worker
+type WorkerStatus struct {
+	Name     string    `json:"name"`
+	LastBeat time.Time `json:"lastBeat"`
+	// Stalled is true when the worker did not beat within its timeout
+	Stalled bool `json:"stalled"`
+}
+
+// QueueStatus is the number of items waiting in a queue of the background workers
+type QueueStatus struct {
+	Name     string `json:"name"`
+	Length   int    `json:"length"`
+	Capacity int    `json:"capacity"`
+}
+
+// Full is true when the queue refuses the new items
+func (q QueueStatus) Full() bool {
+	return q.Length >= q.Capacity
+}
+
+// StatusReport is the public status of the manager, the fields above the configured detail level being left out
+type StatusReport struct {
+	Status  HealthStatus `json:"status"`
+	Version string       `json:"version,omitempty"`
+	// Uptime is the number of seconds since the manager started
+	Uptime     int64                  `json:"uptime,omitempty"`
+	Subsystems map[string]HealthCheck `json:"subsystems,omitempty"`
+	Queues     []QueueStatus          `json:"queues,omitempty"`
+}
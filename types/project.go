@@ -0,0 +1,44 @@
+package types
+
+// CloneProjectOptions contains options for the project clone operation
+type CloneProjectOptions struct {
+	// Name of the new project, the name of the source project when empty
+	Name string
+	// IncludeDrafts copies the pending drafts of the source project along with its published content
+	IncludeDrafts bool
+}
+
+// ApplyProjectOptions contains options for the apply of a project manifest
+type ApplyProjectOptions struct {
+	// Publish publishes the project once its drafts match the manifest
+	Publish bool
+	// DryRun reports the changes without writing any draft
+	DryRun bool
+	// Revision is recorded as the revision of the published project, e.g. the commit SHA of the manifest
+	Revision string
+	// Force applies and publishes the manifest even when it deletes most of the project
+	Force bool
+}
+
+// PublishOptions contains options for the publish of a project
+type PublishOptions struct {
+	// Force publishes the drafts even when they delete most of the project
+	Force bool
+	// ChangesetID publishes the drafts of the changeset only, deleting the changeset once published, 0 publishing all
+	// the drafts of the project
+	ChangesetID int64
+}
+
+// PublishNamespaceOptions contains options for the publish of all the projects of a namespace
+type PublishNamespaceOptions struct {
+	// Concurrency is the number of projects published in parallel, they are published one after the other when 1 or less
+	Concurrency int
+	// StopOnFailure skips the projects not yet published once a project was locked or its publish failed
+	StopOnFailure bool
+	// Username is the user publishing, the projects where another user holds a draft lock are not published
+	Username string
+	// IgnoreDraftLocks publishes the projects regardless of the draft locks of the other users
+	IgnoreDraftLocks bool
+	// Force publishes the projects even when their drafts delete most of them
+	Force bool
+}
@@ -0,0 +1,64 @@
+package types
+
+import commonTypes "github.com/flectolab/flecto-manager/common/types"
+
+// RedirectRewriteInput describes a find/replace applied to the redirects of a project
+type RedirectRewriteInput struct {
+	// Find is the literal string, or the regular expression when Regex is set, to replace
+	Find string
+	// Replace is the replacement, it can reference regex groups with $1 or ${name}
+	Replace string
+	Regex   bool
+	// Source and Target select the fields the rewrite is applied to
+	Source bool
+	Target bool
+	// DryRun reports the matches and conflicts without creating any draft
+	DryRun bool
+}
+
+// RedirectRewriteMatch is a redirect changed by a rewrite
+type RedirectRewriteMatch struct {
+	RedirectID int64
+	Source     string
+	Target     string
+	NewSource  string
+	NewTarget  string
+}
+
+// RedirectRewriteConflict is a redirect matched by a rewrite but left unchanged
+type RedirectRewriteConflict struct {
+	RedirectRewriteMatch
+	Reason string
+}
+
+// RedirectRewriteResult reports the outcome of a rewrite
+type RedirectRewriteResult struct {
+	Matches   []RedirectRewriteMatch
+	Conflicts []RedirectRewriteConflict
+}
+
+// RedirectRegexTestInput describes sample requests run against the source of a REGEX or REGEX_HOST redirect
+type RedirectRegexTestInput struct {
+	Type   commonTypes.RedirectType
+	Source string
+	// Target is resolved with the groups matched on each sample, no target being resolved when empty
+	Target string
+	// Samples are request URIs for a REGEX source, hosts followed by request URIs for a REGEX_HOST source
+	Samples []string
+}
+
+// RedirectRegexTestMatch is the outcome of a sample of a regex test
+type RedirectRegexTestMatch struct {
+	Sample  string
+	Matched bool
+	// Groups are the groups captured by the source, $1 first
+	Groups []string
+	Target string
+}
+
+// RedirectRegexTestResult reports the outcome of a regex test
+type RedirectRegexTestResult struct {
+	// Error explains why the source would be refused, the samples being run only when it is empty
+	Error   string
+	Matches []RedirectRegexTestMatch
+}
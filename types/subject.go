@@ -0,0 +1,20 @@
+package types
+
+import "context"
+
+type subjectKey struct{}
+
+// WithSubject returns a context carrying the subject, a username or an automation name,
+// recorded as the author of the changes made with the context
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectKey{}, subject)
+}
+
+// SubjectFromContext returns the subject set by WithSubject
+func SubjectFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	subject, _ := ctx.Value(subjectKey{}).(string)
+	return subject
+}
@@ -0,0 +1,13 @@
+package types
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubjectFromContext(t *testing.T) {
+	assert.Equal(t, "", SubjectFromContext(context.Background()))
+	assert.Equal(t, "alice", SubjectFromContext(WithSubject(context.Background(), "alice")))
+}
@@ -0,0 +1,13 @@
+package validator
+
+import (
+	"github.com/flectolab/flecto-manager/gitrepo"
+	"github.com/go-playground/validator/v10"
+)
+
+// GitURLKey is the tag of the fields holding the URL of a Git repository fetched by the manager, see gitrepo.CheckURL
+const GitURLKey = "git_url"
+
+func ValidateGitURL(fl validator.FieldLevel) bool {
+	return gitrepo.CheckURL(fl.Field().String()) == nil
+}
@@ -0,0 +1,21 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGitURL(t *testing.T) {
+	type args struct {
+		RepoURL string `validate:"required,git_url"`
+	}
+	validate := validator.New()
+	_ = validate.RegisterValidation(GitURLKey, ValidateGitURL)
+
+	assert.NoError(t, validate.Struct(args{RepoURL: "https://github.com/org/repo.git"}))
+	assert.Error(t, validate.Struct(args{RepoURL: "file:///srv/repo"}))
+	assert.Error(t, validate.Struct(args{RepoURL: "http://github.com/org/repo.git"}))
+	assert.Error(t, validate.Struct(args{RepoURL: "not a url"}))
+}
@@ -40,4 +40,14 @@ func ValidatePage(sl validator.StructLevel) {
 			return
 		}
 	}
+
+	if !validateResponseHeaders(sl, page.Headers) {
+		return
+	}
+
+	if page.IsBinary() {
+		if _, err := page.Body(); err != nil {
+			sl.ReportError(page.Content, "Content", "Content", "base64", "")
+		}
+	}
 }
@@ -56,6 +56,26 @@ func TestValidatePage(t *testing.T) {
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "successWithBinary",
+			page: &commonTypes.Page{
+				Type:        commonTypes.PageTypeBasic,
+				Path:        "/favicon.ico",
+				Content:     "AAABAAEAEBA=",
+				ContentType: commonTypes.PageContentTypeBinary,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "failedBinaryInvalidBase64",
+			page: &commonTypes.Page{
+				Type:        commonTypes.PageTypeBasic,
+				Path:        "/favicon.ico",
+				Content:     "not base64!",
+				ContentType: commonTypes.PageContentTypeBinary,
+			},
+			wantErr: assert.Error,
+		},
 		{
 			name: "failedContentTypeEmpty",
 			page: &commonTypes.Page{
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
 	"net/url"
 	"regexp"
@@ -10,6 +11,10 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
+var headerNameRegex = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+var countryCodeRegex = regexp.MustCompile("^[A-Z]{2}$")
+var languageTagRegex = regexp.MustCompile("^[a-z]{1,8}(-[a-z0-9]{1,8})*$")
+
 func ValidateRedirect(sl validator.StructLevel) {
 	redirect := sl.Current().Interface().(commonTypes.Redirect)
 	if redirect.Status == "" {
@@ -27,6 +32,23 @@ func ValidateRedirect(sl validator.StructLevel) {
 		return
 	}
 
+	if redirect.ValidFrom != nil && redirect.ValidUntil != nil && !redirect.ValidUntil.After(*redirect.ValidFrom) {
+		sl.ReportError(redirect.ValidUntil, "ValidUntil", "ValidUntil", "gtfield", redirect.ValidUntil.String())
+		return
+	}
+
+	if !validateRedirectConditions(sl, redirect.Conditions) {
+		return
+	}
+
+	if !validateRedirectTargets(sl, redirect) {
+		return
+	}
+
+	if !validateResponseHeaders(sl, redirect.Headers) {
+		return
+	}
+
 	switch redirect.Type {
 	case commonTypes.RedirectTypeBasic:
 		_, err := url.Parse(redirect.Source)
@@ -45,11 +67,120 @@ func ValidateRedirect(sl validator.StructLevel) {
 			return
 		}
 	case commonTypes.RedirectTypeRegex, commonTypes.RedirectTypeRegexHost:
-		_, err := regexp.Compile(redirect.Source)
+		err := CheckRegexSource(redirect.Source)
+		if errors.Is(err, ErrRegexTooLong) || errors.Is(err, ErrRegexTooComplex) || errors.Is(err, ErrRegexNestedQuantifier) {
+			sl.ReportError(redirect.Source, "Source", "Source", err.Error(), redirect.Source)
+			return
+		}
 		if err != nil {
 			sl.ReportError(redirect.Source, "Source", "Source", "invalid regex", fmt.Sprintf("%s", redirect.Source))
 			return
 		}
+	case commonTypes.RedirectTypeCatchAll:
+		if redirect.Source != "" {
+			sl.ReportError(redirect.Source, "Source", "Source", "catch-all has no source", redirect.Source)
+			return
+		}
+		if len(redirect.Conditions) > 0 {
+			sl.ReportError(redirect.Conditions, "Conditions", "Conditions", "catch-all has no conditions", "")
+			return
+		}
 	}
 
 }
+
+func validateRedirectConditions(sl validator.StructLevel, conditions []commonTypes.RedirectCondition) bool {
+	seen := make(map[string]bool, len(conditions))
+	for _, condition := range commonTypes.NormalizeRedirectConditions(conditions) {
+		switch condition.Type {
+		case commonTypes.RedirectConditionTypeQuery:
+			if condition.Name == "" {
+				sl.ReportError(conditions, "Conditions", "Conditions", "query name required", "")
+				return false
+			}
+		case commonTypes.RedirectConditionTypeHeader:
+			if !headerNameRegex.MatchString(condition.Name) {
+				sl.ReportError(conditions, "Conditions", "Conditions", "invalid header name", condition.Name)
+				return false
+			}
+		case commonTypes.RedirectConditionTypeHost:
+			if condition.Value == "" {
+				sl.ReportError(conditions, "Conditions", "Conditions", "host required", "")
+				return false
+			}
+		case commonTypes.RedirectConditionTypeCountry:
+			if !validateConditionValues(sl, conditions, condition.Values, countryCodeRegex, "invalid country code") {
+				return false
+			}
+		case commonTypes.RedirectConditionTypeLanguage:
+			if !validateConditionValues(sl, conditions, condition.Values, languageTagRegex, "invalid language tag") {
+				return false
+			}
+		default:
+			sl.ReportError(conditions, "Conditions", "Conditions", "invalid condition type", string(condition.Type))
+			return false
+		}
+		key := commonTypes.RedirectConditionsKey([]commonTypes.RedirectCondition{condition})
+		if seen[key] {
+			sl.ReportError(conditions, "Conditions", "Conditions", "duplicate condition", condition.Name)
+			return false
+		}
+		seen[key] = true
+	}
+	return true
+}
+
+// validateConditionValues checks the normalized values of a country or language condition, at least one being required
+func validateConditionValues(sl validator.StructLevel, conditions []commonTypes.RedirectCondition, values []string, valueRegex *regexp.Regexp, tag string) bool {
+	if len(values) == 0 {
+		sl.ReportError(conditions, "Conditions", "Conditions", "values required", "")
+		return false
+	}
+	for _, value := range values {
+		if !valueRegex.MatchString(value) {
+			sl.ReportError(conditions, "Conditions", "Conditions", tag, value)
+			return false
+		}
+	}
+	return true
+}
+
+// validateRedirectTargets checks the weighted targets of a redirect: at least two distinct targets,
+// one of them being the target of the redirect, with weights summing to 100
+func validateRedirectTargets(sl validator.StructLevel, redirect commonTypes.Redirect) bool {
+	targets := redirect.Targets
+	if len(targets) == 0 {
+		return true
+	}
+	if len(targets) < 2 {
+		sl.ReportError(targets, "Targets", "Targets", "at least two targets", "")
+		return false
+	}
+	seen := make(map[string]bool, len(targets))
+	total := 0
+	for _, target := range targets {
+		if target.Target == "" {
+			sl.ReportError(targets, "Targets", "Targets", "target required", "")
+			return false
+		}
+		if target.Weight < 1 || target.Weight > commonTypes.RedirectTargetsWeight {
+			sl.ReportError(targets, "Targets", "Targets", "weight between 1 and 100", fmt.Sprintf("%d", target.Weight))
+			return false
+		}
+		if seen[target.Target] {
+			sl.ReportError(targets, "Targets", "Targets", "duplicate target", target.Target)
+			return false
+		}
+		seen[target.Target] = true
+		total += target.Weight
+	}
+	if total != commonTypes.RedirectTargetsWeight {
+		sl.ReportError(targets, "Targets", "Targets", "weights sum to 100", fmt.Sprintf("%d", total))
+		return false
+	}
+	if !seen[redirect.Target] {
+		sl.ReportError(targets, "Targets", "Targets", "target of the redirect among the targets", redirect.Target)
+		return false
+	}
+	return true
+}
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"testing"
+	"time"
 
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
 	"github.com/go-playground/validator/v10"
@@ -11,6 +12,8 @@ import (
 func TestValidateRedirect(t *testing.T) {
 	validate := validator.New()
 	validate.RegisterStructValidation(ValidateRedirect, commonTypes.Redirect{})
+	validFrom := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	validUntil := validFrom.Add(24 * time.Hour)
 	tests := []struct {
 		name     string
 		redirect *commonTypes.Redirect
@@ -133,6 +136,263 @@ func TestValidateRedirect(t *testing.T) {
 			},
 			wantErr: assert.Error,
 		},
+		{
+			name: "failedSourceNestedQuantifierWithRegexHost",
+			redirect: &commonTypes.Redirect{
+				Type:   commonTypes.RedirectTypeRegexHost,
+				Source: "example\\.com/(a+)+$",
+				Target: "/target",
+				Status: commonTypes.RedirectStatusMovedPermanent,
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "successWithValidityPeriod",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				ValidFrom:  &validFrom,
+				ValidUntil: &validUntil,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "failedValidUntilBeforeValidFrom",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				ValidFrom:  &validUntil,
+				ValidUntil: &validFrom,
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "successWithConditions",
+			redirect: &commonTypes.Redirect{
+				Type:   commonTypes.RedirectTypeBasic,
+				Source: "/source",
+				Target: "/target",
+				Status: commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{
+					{Type: commonTypes.RedirectConditionTypeQuery, Name: "lang", Value: "fr"},
+					{Type: commonTypes.RedirectConditionTypeHeader, Name: "X-Platform"},
+					{Type: commonTypes.RedirectConditionTypeHost, Value: "example.com"},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "failedConditionInvalidType",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{{Type: "COOKIE", Name: "lang"}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedConditionQueryWithoutName",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeQuery, Value: "fr"}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedConditionInvalidHeaderName",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeHeader, Name: "X Platform"}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedConditionHostWithoutValue",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeHost}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedConditionDuplicate",
+			redirect: &commonTypes.Redirect{
+				Type:   commonTypes.RedirectTypeBasic,
+				Source: "/source",
+				Target: "/target",
+				Status: commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{
+					{Type: commonTypes.RedirectConditionTypeHeader, Name: "x-platform", Value: "ios"},
+					{Type: commonTypes.RedirectConditionTypeHeader, Name: "X-Platform", Value: "ios"},
+				},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "successWithCountryAndLanguageConditions",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeCountry, Values: []string{"be", "CA"}}, {Type: commonTypes.RedirectConditionTypeLanguage, Values: []string{"fr", "en-GB"}}},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "failedConditionCountryWithoutValues",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeCountry}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedConditionInvalidCountry",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeCountry, Values: []string{"FRA"}}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedConditionInvalidLanguage",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeBasic,
+				Source:     "/source",
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusMovedPermanent,
+				Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeLanguage, Values: []string{"fr_FR"}}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "successWithCatchAll",
+			redirect: &commonTypes.Redirect{
+				Type:   commonTypes.RedirectTypeCatchAll,
+				Target: "https://example.com",
+				Status: commonTypes.RedirectStatusFound,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "failedCatchAllWithSource",
+			redirect: &commonTypes.Redirect{
+				Type:   commonTypes.RedirectTypeCatchAll,
+				Source: "/source",
+				Target: "/target",
+				Status: commonTypes.RedirectStatusFound,
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedCatchAllWithConditions",
+			redirect: &commonTypes.Redirect{
+				Type:       commonTypes.RedirectTypeCatchAll,
+				Target:     "/target",
+				Status:     commonTypes.RedirectStatusFound,
+				Conditions: []commonTypes.RedirectCondition{{Type: commonTypes.RedirectConditionTypeQuery, Name: "lang"}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "successWithTargets",
+			redirect: &commonTypes.Redirect{
+				Type:    commonTypes.RedirectTypeBasic,
+				Source:  "/source",
+				Target:  "/target",
+				Status:  commonTypes.RedirectStatusFound,
+				Targets: []commonTypes.RedirectTarget{{Target: "/target", Weight: 90}, {Target: "/beta", Weight: 10}},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "failedTargetsSingle",
+			redirect: &commonTypes.Redirect{
+				Type:    commonTypes.RedirectTypeBasic,
+				Source:  "/source",
+				Target:  "/target",
+				Status:  commonTypes.RedirectStatusFound,
+				Targets: []commonTypes.RedirectTarget{{Target: "/target", Weight: 100}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedTargetsWeightsSum",
+			redirect: &commonTypes.Redirect{
+				Type:    commonTypes.RedirectTypeBasic,
+				Source:  "/source",
+				Target:  "/target",
+				Status:  commonTypes.RedirectStatusFound,
+				Targets: []commonTypes.RedirectTarget{{Target: "/target", Weight: 90}, {Target: "/beta", Weight: 20}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedTargetsZeroWeight",
+			redirect: &commonTypes.Redirect{
+				Type:    commonTypes.RedirectTypeBasic,
+				Source:  "/source",
+				Target:  "/target",
+				Status:  commonTypes.RedirectStatusFound,
+				Targets: []commonTypes.RedirectTarget{{Target: "/target", Weight: 100}, {Target: "/beta", Weight: 0}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedTargetsEmptyTarget",
+			redirect: &commonTypes.Redirect{
+				Type:    commonTypes.RedirectTypeBasic,
+				Source:  "/source",
+				Target:  "/target",
+				Status:  commonTypes.RedirectStatusFound,
+				Targets: []commonTypes.RedirectTarget{{Target: "/target", Weight: 50}, {Target: "", Weight: 50}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedTargetsDuplicate",
+			redirect: &commonTypes.Redirect{
+				Type:    commonTypes.RedirectTypeBasic,
+				Source:  "/source",
+				Target:  "/target",
+				Status:  commonTypes.RedirectStatusFound,
+				Targets: []commonTypes.RedirectTarget{{Target: "/target", Weight: 50}, {Target: "/target", Weight: 50}},
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "failedTargetsWithoutTarget",
+			redirect: &commonTypes.Redirect{
+				Type:    commonTypes.RedirectTypeBasic,
+				Source:  "/source",
+				Target:  "/target",
+				Status:  commonTypes.RedirectStatusFound,
+				Targets: []commonTypes.RedirectTarget{{Target: "/alpha", Weight: 50}, {Target: "/beta", Weight: 50}},
+			},
+			wantErr: assert.Error,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
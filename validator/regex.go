@@ -0,0 +1,71 @@
+package validator
+
+import (
+	"errors"
+	"regexp"
+	"regexp/syntax"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// PatternKey is the tag of the fields holding a regular expression
+	PatternKey = "pattern"
+	// RegexMaxLength is the maximum length of the source of a REGEX or REGEX_HOST redirect
+	RegexMaxLength = 1000
+	// RegexMaxInstructions is the maximum size of the compiled program of a regex source, counted repetitions
+	// multiplying the size of the repeated expression
+	RegexMaxInstructions = 1000
+)
+
+var (
+	ErrRegexTooLong          = errors.New("regex too long")
+	ErrRegexTooComplex       = errors.New("regex too complex")
+	ErrRegexNestedQuantifier = errors.New("regex with nested quantifiers")
+)
+
+// CheckRegexSource parses the source of a REGEX or REGEX_HOST redirect and checks it against the safety limits.
+// The agents match the regex sources in linear time, but the sources are also exported to the configurations of
+// backtracking web servers, on which nested quantifiers like (a+)+ take an exponential time on some requests.
+func CheckRegexSource(source string) error {
+	if len(source) > RegexMaxLength {
+		return ErrRegexTooLong
+	}
+	re, err := syntax.Parse(source, syntax.Perl)
+	if err != nil {
+		return err
+	}
+	if hasNestedQuantifier(re, false) {
+		return ErrRegexNestedQuantifier
+	}
+	prog, err := syntax.Compile(re.Simplify())
+	if err != nil {
+		return err
+	}
+	if len(prog.Inst) > RegexMaxInstructions {
+		return ErrRegexTooComplex
+	}
+	return nil
+}
+
+// hasNestedQuantifier returns true if an unbounded quantifier of the regex applies to an expression repeated by another
+// quantifier
+func hasNestedQuantifier(re *syntax.Regexp, repeated bool) bool {
+	unbounded := re.Op == syntax.OpStar || re.Op == syntax.OpPlus || (re.Op == syntax.OpRepeat && re.Max == -1)
+	if unbounded && repeated {
+		return true
+	}
+	repeats := unbounded || (re.Op == syntax.OpRepeat && re.Max > 1)
+	for _, sub := range re.Sub {
+		if hasNestedQuantifier(sub, repeated || repeats) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePattern returns true if the field is a valid regular expression
+func ValidatePattern(fl validator.FieldLevel) bool {
+	_, err := regexp.Compile(fl.Field().String())
+	return err == nil
+}
@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRegexSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantErr error
+	}{
+		{name: "success", source: `^/blog/(\d+)/(.*)$`},
+		{name: "successWithOptionalGroup", source: `^/shop(/.*)?$`},
+		{name: "successWithBoundedRepeat", source: `^/(a{1,5})+$`},
+		{name: "failedTooLong", source: "/" + strings.Repeat("a", RegexMaxLength), wantErr: ErrRegexTooLong},
+		{name: "failedNestedPlus", source: `^/(a+)+$`, wantErr: ErrRegexNestedQuantifier},
+		{name: "failedNestedStar", source: `^/(?:[a-z]*/)*$`, wantErr: ErrRegexNestedQuantifier},
+		{name: "failedNestedInCountedRepeat", source: `^/(.*,){3}$`, wantErr: ErrRegexNestedQuantifier},
+		{name: "failedTooComplex", source: `^/((?:en|fr|de|es)/\d{30}){30}$`, wantErr: ErrRegexTooComplex},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRegexSource(tt.source)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	assert.Error(t, CheckRegexSource("/source["))
+}
+
+func TestValidatePattern(t *testing.T) {
+	type args struct {
+		String string `validate:"pattern"`
+	}
+	validate := validator.New()
+	_ = validate.RegisterValidation(PatternKey, ValidatePattern)
+
+	assert.NoError(t, validate.Struct(args{String: `\bAKIA[0-9A-Z]{16}\b`}))
+	assert.Error(t, validate.Struct(args{String: "key["}))
+}
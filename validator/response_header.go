@@ -0,0 +1,58 @@
+package validator
+
+import (
+	"net/http"
+	"strings"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/go-playground/validator/v10"
+)
+
+// ResponseHeadersLimit is the maximum number of headers of a page or a redirect
+const ResponseHeadersLimit = 20
+
+// ResponseHeaderValueMaxLength is the maximum length of the value of a header
+const ResponseHeaderValueMaxLength = 1024
+
+// allowedResponseHeaders are the headers a page or a redirect can set, the ones computed by the agents,
+// like Location, Content-Type or Set-Cookie, being excluded
+var allowedResponseHeaders = map[string]bool{
+	"Access-Control-Allow-Origin": true,
+	"Cache-Control":               true,
+	"Content-Disposition":         true,
+	"Content-Language":            true,
+	"Content-Security-Policy":     true,
+	"Expires":                     true,
+	"Link":                        true,
+	"Permissions-Policy":          true,
+	"Referrer-Policy":             true,
+	"Strict-Transport-Security":   true,
+	"Vary":                        true,
+	"X-Content-Type-Options":      true,
+	"X-Frame-Options":             true,
+	"X-Robots-Tag":                true,
+}
+
+// IsAllowedResponseHeader returns true when a page or a redirect can set the header, case insensitive
+func IsAllowedResponseHeader(name string) bool {
+	return allowedResponseHeaders[http.CanonicalHeaderKey(strings.TrimSpace(name))]
+}
+
+func validateResponseHeaders(sl validator.StructLevel, headers commonTypes.ResponseHeaders) bool {
+	if len(headers) > ResponseHeadersLimit {
+		sl.ReportError(headers, "Headers", "Headers", "too many headers", "")
+		return false
+	}
+	for name, value := range headers {
+		if !IsAllowedResponseHeader(name) {
+			sl.ReportError(headers, "Headers", "Headers", "header not allowed", name)
+			return false
+		}
+		value = strings.TrimSpace(value)
+		if value == "" || len(value) > ResponseHeaderValueMaxLength || strings.ContainsAny(value, "\r\n") {
+			sl.ReportError(headers, "Headers", "Headers", "invalid header value", name)
+			return false
+		}
+	}
+	return true
+}
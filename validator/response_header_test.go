@@ -0,0 +1,45 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAllowedResponseHeader(t *testing.T) {
+	assert.True(t, IsAllowedResponseHeader("Cache-Control"))
+	assert.True(t, IsAllowedResponseHeader(" x-robots-tag"))
+	assert.False(t, IsAllowedResponseHeader("Location"))
+	assert.False(t, IsAllowedResponseHeader("Set-Cookie"))
+}
+
+func TestValidateResponseHeaders(t *testing.T) {
+	validate := New()
+	tooMany := commonTypes.ResponseHeaders{}
+	for i := 0; i <= ResponseHeadersLimit; i++ {
+		tooMany[strings.Repeat("X", i+1)] = "value"
+	}
+	tests := []struct {
+		name    string
+		headers commonTypes.ResponseHeaders
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{name: "successWithoutHeaders", wantErr: assert.NoError},
+		{name: "successWithAllowedHeaders", headers: commonTypes.ResponseHeaders{"cache-control": "max-age=3600", "X-Robots-Tag": "noindex"}, wantErr: assert.NoError},
+		{name: "failedHeaderNotAllowed", headers: commonTypes.ResponseHeaders{"Location": "/other"}, wantErr: assert.Error},
+		{name: "failedEmptyValue", headers: commonTypes.ResponseHeaders{"Cache-Control": " "}, wantErr: assert.Error},
+		{name: "failedValueWithNewLine", headers: commonTypes.ResponseHeaders{"Cache-Control": "no-cache\r\nSet-Cookie: a=b"}, wantErr: assert.Error},
+		{name: "failedValueTooLong", headers: commonTypes.ResponseHeaders{"Link": strings.Repeat("a", ResponseHeaderValueMaxLength+1)}, wantErr: assert.Error},
+		{name: "failedTooManyHeaders", headers: tooMany, wantErr: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redirect := commonTypes.Redirect{Type: commonTypes.RedirectTypeBasic, Source: "/source", Target: "/target", Status: commonTypes.RedirectStatusFound, Headers: tt.headers}
+			tt.wantErr(t, validate.Struct(redirect), "redirect")
+			page := commonTypes.Page{Type: commonTypes.PageTypeBasic, Path: "/robots.txt", Content: "User-agent: *", ContentType: commonTypes.PageContentTypeTextPlain, Headers: tt.headers}
+			tt.wantErr(t, validate.Struct(page), "page")
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package validator
+
+import (
+	"github.com/flectolab/flecto-manager/cron"
+	"github.com/go-playground/validator/v10"
+)
+
+// ScheduleKey is the tag of the fields holding the schedule of a recurring task, see cron.Parse
+const ScheduleKey = "schedule"
+
+func ValidateSchedule(fl validator.FieldLevel) bool {
+	_, err := cron.Parse(fl.Field().String())
+	return err == nil
+}
@@ -0,0 +1,21 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchedule(t *testing.T) {
+	type args struct {
+		Schedule string `validate:"omitempty,schedule"`
+	}
+	validate := validator.New()
+	_ = validate.RegisterValidation(ScheduleKey, ValidateSchedule)
+
+	assert.NoError(t, validate.Struct(args{Schedule: "0 3 * * *"}))
+	assert.NoError(t, validate.Struct(args{Schedule: "@every 10m"}))
+	assert.NoError(t, validate.Struct(args{}))
+	assert.Error(t, validate.Struct(args{Schedule: "0 25 * * *"}))
+}
@@ -2,6 +2,7 @@ package validator
 
 import (
 	commonTypes "github.com/flectolab/flecto-manager/common/types"
+	"github.com/flectolab/flecto-manager/i18n"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -9,7 +10,11 @@ func New(options ...validator.Option) *validator.Validate {
 	validate := validator.New()
 	_ = validate.RegisterValidation(CodeKey, ValidateCode)
 	_ = validate.RegisterValidation(UsernameKey, ValidateUsername)
+	_ = validate.RegisterValidation(PatternKey, ValidatePattern)
+	_ = validate.RegisterValidation(ScheduleKey, ValidateSchedule)
+	_ = validate.RegisterValidation(GitURLKey, ValidateGitURL)
 	validate.RegisterStructValidation(ValidateRedirect, commonTypes.Redirect{})
 	validate.RegisterStructValidation(ValidatePage, commonTypes.Page{})
+	_ = i18n.RegisterTranslations(validate)
 	return validate
 }
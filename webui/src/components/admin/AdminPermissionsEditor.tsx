@@ -1,14 +1,22 @@
-import { ADMIN_SECTION_OPTIONS, ACTION_OPTIONS } from '../../hooks/usePermissions'
+import { ADMIN_SECTION_OPTIONS, ACTION_OPTIONS, EFFECT_OPTIONS, PermissionEffect, type PermissionEffectType } from '../../hooks/usePermissions'
 
 export interface AdminPermission {
   type?: 'user' | 'role'
+  namespace?: string
   section: string
   action: string
+  effect?: PermissionEffectType
+}
+
+interface NamespaceOption {
+  code: string
+  label: string
 }
 
 interface AdminPermissionsEditorProps {
   permissions: AdminPermission[]
-  onChange: (index: number, field: 'section' | 'action', value: string) => void
+  namespaceOptions: NamespaceOption[]
+  onChange: (index: number, field: 'namespace' | 'section' | 'action' | 'effect', value: string) => void
   onAdd: () => void
   onRemove: (index: number) => void
   readOnly?: boolean
@@ -18,6 +26,7 @@ interface AdminPermissionsEditorProps {
 
 export function AdminPermissionsEditor({
   permissions,
+  namespaceOptions,
   onChange,
   onAdd,
   onRemove,
@@ -49,8 +58,10 @@ export function AdminPermissionsEditor({
           <table className="w-full text-sm">
             <thead>
               <tr className="border-b border-slate-200 dark:border-slate-700">
+                <th className="text-left py-2 px-2 font-medium text-slate-600 dark:text-slate-400">Namespace</th>
                 <th className="text-left py-2 px-2 font-medium text-slate-600 dark:text-slate-400">Section</th>
                 <th className="text-left py-2 px-2 font-medium text-slate-600 dark:text-slate-400 w-24">Action</th>
+                <th className="text-left py-2 px-2 font-medium text-slate-600 dark:text-slate-400 w-24">Effect</th>
                 <th className="w-20"></th>
               </tr>
             </thead>
@@ -64,6 +75,21 @@ export function AdminPermissionsEditor({
 
                 return (
                   <tr key={index} className={`border-b border-slate-100 dark:border-slate-700/50 ${rowClass}`}>
+                    <td className="py-2 px-2">
+                      {isRowReadOnly ? (
+                        <span className="text-slate-600 dark:text-slate-400">{perm.namespace ?? '*'}</span>
+                      ) : (
+                        <select
+                          value={perm.namespace ?? '*'}
+                          onChange={(e) => onChange(index, 'namespace', e.target.value)}
+                          className="w-full rounded border border-slate-200 dark:border-slate-700 bg-white dark:bg-slate-900 py-1 px-2 text-slate-900 dark:text-white focus:border-brand-purple focus:outline-none"
+                        >
+                          {namespaceOptions.map(ns => (
+                            <option key={ns.code} value={ns.code}>{ns.label}</option>
+                          ))}
+                        </select>
+                      )}
+                    </td>
                     <td className="py-2 px-2">
                       {isRowReadOnly ? (
                         <span className="text-slate-600 dark:text-slate-400">{perm.section}</span>
@@ -94,6 +120,23 @@ export function AdminPermissionsEditor({
                         </select>
                       )}
                     </td>
+                    <td className="py-2 px-2">
+                      {isRowReadOnly ? (
+                        <span className={perm.effect === PermissionEffect.Deny ? 'text-red-600 dark:text-red-400' : 'text-slate-600 dark:text-slate-400'}>
+                          {perm.effect ?? PermissionEffect.Allow}
+                        </span>
+                      ) : (
+                        <select
+                          value={perm.effect ?? PermissionEffect.Allow}
+                          onChange={(e) => onChange(index, 'effect', e.target.value)}
+                          className="w-full rounded border border-slate-200 dark:border-slate-700 bg-white dark:bg-slate-900 py-1 px-2 text-slate-900 dark:text-white focus:border-brand-purple focus:outline-none"
+                        >
+                          {EFFECT_OPTIONS.map(opt => (
+                            <option key={opt.code} value={opt.code}>{opt.label}</option>
+                          ))}
+                        </select>
+                      )}
+                    </td>
                     <td className="py-2 px-2 text-right">
                       {!isRolePermission && canWrite && !readOnly && (
                         <button
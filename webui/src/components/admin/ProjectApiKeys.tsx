@@ -0,0 +1,179 @@
+import { useState } from 'react'
+import { useQuery, useMutation } from '@apollo/client/react'
+import { GetProjectApiKeysDocument, CreateProjectApiKeyDocument, DeleteProjectApiKeyDocument } from '../../generated/graphql'
+import { validateCode } from '../../hooks/usePermissions'
+import { RelativeTime } from '../RelativeTime'
+
+type Scope = 'READ' | 'WRITE'
+
+interface ProjectApiKeysProps {
+  namespaceCode: string
+  projectCode: string
+  canWrite: boolean
+}
+
+// ProjectApiKeys lists the API keys of a project, limited to its drafts, and creates or revokes them
+export function ProjectApiKeys({ namespaceCode, projectCode, canWrite }: ProjectApiKeysProps) {
+  const [name, setName] = useState('')
+  const [scopes, setScopes] = useState<Scope[]>(['READ'])
+  const [expiresAt, setExpiresAt] = useState('')
+  const [error, setError] = useState('')
+  const [createdKey, setCreatedKey] = useState<{ name: string; plainKey: string } | null>(null)
+
+  const variables = { namespaceCode, projectCode }
+  const { data, loading } = useQuery(GetProjectApiKeysDocument, { variables })
+  const refetchQueries = [{ query: GetProjectApiKeysDocument, variables }]
+  const [createKey, { loading: createLoading }] = useMutation(CreateProjectApiKeyDocument, { refetchQueries })
+  const [deleteKey, { loading: deleteLoading }] = useMutation(DeleteProjectApiKeyDocument, { refetchQueries })
+
+  const toggleScope = (scope: Scope) => {
+    setScopes((current) => (current.includes(scope) ? current.filter((s) => s !== scope) : [...current, scope]))
+  }
+
+  const handleCreate = async () => {
+    setError('')
+    const nameError = validateCode(name, 'Name')
+    if (nameError) {
+      setError(nameError)
+      return
+    }
+    try {
+      const result = await createKey({
+        variables: {
+          ...variables,
+          input: {
+            name,
+            scopes,
+            expiresAt: expiresAt ? new Date(expiresAt).toISOString() : null,
+          },
+        },
+      })
+      if (result.data?.createProjectAPIKey) {
+        setCreatedKey({ name, plainKey: result.data.createProjectAPIKey.plainKey })
+        setName('')
+        setExpiresAt('')
+      }
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to create API key')
+    }
+  }
+
+  const handleDelete = async (id: number) => {
+    setError('')
+    try {
+      await deleteKey({ variables: { ...variables, id } })
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to revoke API key')
+    }
+  }
+
+  return (
+    <div className="rounded-xl bg-white dark:bg-slate-800 border border-slate-200 dark:border-slate-700 p-6">
+      <h3 className="text-lg font-semibold text-slate-900 dark:text-white mb-1">
+        API Keys
+      </h3>
+      <p className="text-xs text-slate-500 dark:text-slate-400 mb-4">
+        Keys limited to the redirect and page drafts of this project. They cannot publish.
+      </p>
+
+      {error && (
+        <div className="mb-4 rounded-lg bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-800 p-3">
+          <p className="text-sm text-red-700 dark:text-red-400">{error}</p>
+        </div>
+      )}
+
+      {createdKey && (
+        <div className="mb-4 rounded-lg bg-green-50 dark:bg-green-900/20 border border-green-200 dark:border-green-800 p-3">
+          <p className="text-sm font-medium text-green-800 dark:text-green-300 mb-2">
+            Key {createdKey.name} created. Copy it now, it won't be shown again.
+          </p>
+          <div className="flex items-center gap-2">
+            <code className="flex-1 p-2 rounded bg-white dark:bg-slate-900 font-mono text-xs text-slate-900 dark:text-white break-all">
+              {createdKey.plainKey}
+            </code>
+            <button
+              onClick={() => navigator.clipboard.writeText(createdKey.plainKey)}
+              className="shrink-0 px-3 py-1 text-xs font-medium rounded-lg bg-slate-100 dark:bg-slate-700 text-slate-700 dark:text-slate-300 hover:bg-slate-200 dark:hover:bg-slate-600 transition-colors"
+            >
+              Copy
+            </button>
+            <button
+              onClick={() => setCreatedKey(null)}
+              className="shrink-0 px-3 py-1 text-xs font-medium rounded-lg border border-slate-200 dark:border-slate-700 text-slate-600 dark:text-slate-400 hover:bg-slate-50 dark:hover:bg-slate-700 transition-colors"
+            >
+              Done
+            </button>
+          </div>
+        </div>
+      )}
+
+      {loading ? (
+        <div className="flex justify-center py-4">
+          <div className="h-6 w-6 animate-spin rounded-full border-4 border-brand-purple border-t-transparent"></div>
+        </div>
+      ) : data?.projectAPIKeys.length ? (
+        <ul className="space-y-3 mb-4">
+          {data.projectAPIKeys.map((key) => (
+            <li key={key.id} className="flex items-start justify-between gap-2">
+              <div className="min-w-0">
+                <p className="text-sm font-medium text-slate-900 dark:text-white">{key.name}</p>
+                <p className="text-xs font-mono text-slate-500 dark:text-slate-400 truncate">{key.keyPreview}</p>
+                <p className="text-xs text-slate-500 dark:text-slate-400">
+                  {key.scopes.join(', ')} &middot; created <RelativeTime date={key.createdAt} className="cursor-help" />
+                  {key.createdBy && <> by {key.createdBy}</>}
+                  {key.expiresAt && <> &middot; expires {new Date(key.expiresAt).toLocaleDateString()}</>}
+                </p>
+              </div>
+              {canWrite && (
+                <button
+                  onClick={() => handleDelete(key.id)}
+                  disabled={deleteLoading}
+                  className="shrink-0 px-3 py-1 text-xs font-medium rounded-lg border border-red-200 dark:border-red-800 text-red-600 dark:text-red-400 hover:bg-red-50 dark:hover:bg-red-900/20 transition-colors disabled:opacity-50"
+                >
+                  Revoke
+                </button>
+              )}
+            </li>
+          ))}
+        </ul>
+      ) : (
+        <p className="text-sm text-slate-500 dark:text-slate-400 mb-4">No API keys</p>
+      )}
+
+      {canWrite && (
+        <div className="space-y-3 pt-4 border-t border-slate-200 dark:border-slate-700">
+          <input
+            aria-label="API key name"
+            type="text"
+            value={name}
+            onChange={(e) => setName(e.target.value)}
+            placeholder="Key name"
+            className="w-full rounded-lg border border-slate-200 dark:border-slate-700 bg-white dark:bg-slate-900 py-2 px-3 text-sm text-slate-900 dark:text-white placeholder-slate-400 focus:border-brand-purple focus:outline-none focus:ring-2 focus:ring-brand-purple/20"
+          />
+          <div className="flex gap-4">
+            {(['READ', 'WRITE'] as Scope[]).map((scope) => (
+              <label key={scope} className="flex items-center gap-2 text-sm text-slate-700 dark:text-slate-300">
+                <input type="checkbox" checked={scopes.includes(scope)} onChange={() => toggleScope(scope)} />
+                {scope === 'READ' ? 'Read' : 'Write drafts'}
+              </label>
+            ))}
+          </div>
+          <input
+            aria-label="API key expiration"
+            type="datetime-local"
+            value={expiresAt}
+            onChange={(e) => setExpiresAt(e.target.value)}
+            className="w-full rounded-lg border border-slate-200 dark:border-slate-700 bg-white dark:bg-slate-900 py-2 px-3 text-sm text-slate-900 dark:text-white focus:border-brand-purple focus:outline-none focus:ring-2 focus:ring-brand-purple/20"
+          />
+          <button
+            onClick={handleCreate}
+            disabled={createLoading || !name || scopes.length === 0}
+            className="w-full px-4 py-2 text-sm font-medium rounded-lg bg-gradient-to-r from-brand-purple to-brand-indigo text-white hover:opacity-90 transition-opacity disabled:opacity-50 disabled:cursor-not-allowed"
+          >
+            {createLoading ? 'Creating...' : 'Create API Key'}
+          </button>
+        </div>
+      )}
+    </div>
+  )
+}
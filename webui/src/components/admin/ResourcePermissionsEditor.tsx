@@ -1,4 +1,4 @@
-import { ACTION_OPTIONS, RESOURCE_TYPE_OPTIONS } from '../../hooks/usePermissions'
+import { ACTION_OPTIONS, EFFECT_OPTIONS, PermissionEffect, RESOURCE_TYPE_OPTIONS, type PermissionEffectType } from '../../hooks/usePermissions'
 
 export interface ResourcePermission {
   type?: 'user' | 'role'
@@ -6,6 +6,7 @@ export interface ResourcePermission {
   project: string
   resource: string
   action: string
+  effect?: PermissionEffectType
 }
 
 interface NamespaceOption {
@@ -17,7 +18,7 @@ interface ResourcePermissionsEditorProps {
   permissions: ResourcePermission[]
   namespaceOptions: NamespaceOption[]
   getProjectOptions: (namespaceCode: string) => NamespaceOption[]
-  onChange: (index: number, field: 'namespace' | 'project' | 'resource' | 'action', value: string) => void
+  onChange: (index: number, field: 'namespace' | 'project' | 'resource' | 'action' | 'effect', value: string) => void
   onAdd: () => void
   onRemove: (index: number) => void
   readOnly?: boolean
@@ -64,6 +65,7 @@ export function ResourcePermissionsEditor({
                 <th className="text-left py-2 px-2 font-medium text-slate-600 dark:text-slate-400">Project</th>
                 <th className="text-left py-2 px-2 font-medium text-slate-600 dark:text-slate-400 w-28">Resource</th>
                 <th className="text-left py-2 px-2 font-medium text-slate-600 dark:text-slate-400 w-24">Action</th>
+                <th className="text-left py-2 px-2 font-medium text-slate-600 dark:text-slate-400 w-24">Effect</th>
                 <th className="w-20"></th>
               </tr>
             </thead>
@@ -140,6 +142,23 @@ export function ResourcePermissionsEditor({
                         </select>
                       )}
                     </td>
+                    <td className="py-2 px-2">
+                      {isRowReadOnly ? (
+                        <span className={perm.effect === PermissionEffect.Deny ? 'text-red-600 dark:text-red-400' : 'text-slate-600 dark:text-slate-400'}>
+                          {perm.effect ?? PermissionEffect.Allow}
+                        </span>
+                      ) : (
+                        <select
+                          value={perm.effect ?? PermissionEffect.Allow}
+                          onChange={(e) => onChange(index, 'effect', e.target.value)}
+                          className="w-full rounded border border-slate-200 dark:border-slate-700 bg-white dark:bg-slate-900 py-1 px-2 text-slate-900 dark:text-white focus:border-brand-purple focus:outline-none"
+                        >
+                          {EFFECT_OPTIONS.map(opt => (
+                            <option key={opt.code} value={opt.code}>{opt.label}</option>
+                          ))}
+                        </select>
+                      )}
+                    </td>
                     <td className="py-2 px-2 text-right">
                       {!isRolePermission && canWrite && !readOnly && (
                         <button
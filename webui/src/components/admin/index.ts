@@ -5,3 +5,4 @@ export { ResourcePermissionsEditor } from './ResourcePermissionsEditor'
 export type { ResourcePermission } from './ResourcePermissionsEditor'
 export { AdminPermissionsEditor } from './AdminPermissionsEditor'
 export type { AdminPermission } from './AdminPermissionsEditor'
+export { ProjectApiKeys } from './ProjectApiKeys'
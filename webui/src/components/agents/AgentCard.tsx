@@ -122,6 +122,12 @@ export function AgentCard({ agent, projectVersion }: AgentCardProps) {
               <span className="font-medium">{formatAgentType(agent.type)}</span>
             </div>
 
+            {/* Environment */}
+            <div className="flex items-center gap-2 text-sm text-slate-600 dark:text-slate-400">
+              <span className="text-slate-400 dark:text-slate-500">Environment:</span>
+              <span className="font-medium capitalize">{agent.environment}</span>
+            </div>
+
             {/* Created At */}
             <div className="flex items-center gap-2 text-sm text-slate-600 dark:text-slate-400">
               <svg className="w-4 h-4 text-slate-400" fill="none" stroke="currentColor" viewBox="0 0 24 24">
@@ -7,8 +7,10 @@ import {
 } from '../../generated/graphql'
 import type { ImportRedirectDraftMutation, ImportErrorReason } from '../../generated/graphql'
 import { PathCell } from '../PathCell'
+import { config } from '../../config'
 
-const MAX_FILE_SIZE = 2 * 1024 * 1024 // 2MB
+const MAX_FILE_SIZE = config.importMaxFileSize
+const MAX_FILE_SIZE_LABEL = `${(MAX_FILE_SIZE / 1024 / 1024).toFixed(2).replace(/\.?0+$/, '')}MB`
 const ERRORS_PER_PAGE = 10
 
 interface ImportModalProps {
@@ -30,6 +32,8 @@ const errorReasonLabels: Record<ImportErrorReason, string> = {
   'DUPLICATE_SOURCE_IN_FILE': 'Duplicate in file',
   'SOURCE_ALREADY_EXISTS': 'Source exists',
   'DATABASE_ERROR': 'Database error',
+  'POLICY_VIOLATION': 'Policy violation',
+  'DRAFT_REFUSED': 'Refused by an extension',
 }
 
 function exportErrorsToCsv(errors: ImportResult['errors']) {
@@ -72,12 +76,12 @@ export function ImportModal({ namespaceCode, projectCode, onClose, onSuccess }:
   const handleFileSelect = (selectedFile: File | null) => {
     if (selectedFile) {
       const ext = selectedFile.name.toLowerCase()
-      if (!ext.endsWith('.csv') && !ext.endsWith('.tsv')) {
-        setError('Invalid file type. Only .csv and .tsv files are allowed.')
+      if (!['.csv', '.tsv', '.xlsx', '.json'].some((allowed) => ext.endsWith(allowed))) {
+        setError('Invalid file type. Only .csv, .tsv, .xlsx and .json files are allowed.')
         return
       }
       if (selectedFile.size > MAX_FILE_SIZE) {
-        setError(`File too large. Maximum size is 2MB. Your file is ${(selectedFile.size / 1024 / 1024).toFixed(2)}MB.`)
+        setError(`File too large. Maximum size is ${MAX_FILE_SIZE_LABEL}. Your file is ${(selectedFile.size / 1024 / 1024).toFixed(2)}MB.`)
         return
       }
       setFile(selectedFile)
@@ -333,7 +337,7 @@ export function ImportModal({ namespaceCode, projectCode, onClose, onSuccess }:
                         </div>
                         <div>
                           <span className="font-medium text-slate-700 dark:text-slate-300">File extension:</span>
-                          <span className="ml-2 text-slate-600 dark:text-slate-400">.csv or .tsv</span>
+                          <span className="ml-2 text-slate-600 dark:text-slate-400">.csv, .tsv, .xlsx or .json</span>
                         </div>
                         <div>
                           <span className="font-medium text-slate-700 dark:text-slate-300">Required headers:</span>
@@ -345,6 +349,16 @@ export function ImportModal({ namespaceCode, projectCode, onClose, onSuccess }:
                             ))}
                           </div>
                         </div>
+                        <div>
+                          <span className="font-medium text-slate-700 dark:text-slate-300">Optional headers:</span>
+                          <div className="mt-1 flex flex-wrap gap-2">
+                            {['tags', 'valid_from', 'valid_until', 'priority', 'comment'].map((header) => (
+                              <span key={header} className="px-2 py-0.5 rounded bg-slate-100 dark:bg-slate-700 text-slate-600 dark:text-slate-400 font-mono text-xs">
+                                {header}
+                              </span>
+                            ))}
+                          </div>
+                        </div>
                       </div>
                     </div>
 
@@ -456,7 +470,7 @@ export function ImportModal({ namespaceCode, projectCode, onClose, onSuccess }:
                 <input
                   ref={fileInputRef}
                   type="file"
-                  accept=".csv,.tsv"
+                  accept=".csv,.tsv,.xlsx,.json"
                   onChange={(e) => handleFileSelect(e.target.files?.[0] || null)}
                   className="hidden"
                 />
@@ -480,7 +494,7 @@ export function ImportModal({ namespaceCode, projectCode, onClose, onSuccess }:
                     <p className="text-slate-600 dark:text-slate-400 mb-1">
                       Drag and drop your file here, or <span className="text-brand-purple font-medium">browse</span>
                     </p>
-                    <p className="text-sm text-slate-500 dark:text-slate-500">.csv or .tsv files only (max 2MB)</p>
+                    <p className="text-sm text-slate-500 dark:text-slate-500">.csv, .tsv, .xlsx or .json files only (max {MAX_FILE_SIZE_LABEL})</p>
                   </>
                 )}
               </div>
@@ -3,6 +3,7 @@ declare global {
     APP_CONFIG?: {
       apiUrl?: string
       authHeaderName?: string
+      importMaxFileSize?: number
     }
   }
 }
@@ -10,4 +11,5 @@ declare global {
 export const config = {
   apiUrl: window.APP_CONFIG?.apiUrl ?? '',
   authHeaderName: window.APP_CONFIG?.authHeaderName ?? 'Authorization',
+  importMaxFileSize: window.APP_CONFIG?.importMaxFileSize ?? 2 * 1024 * 1024,
 }
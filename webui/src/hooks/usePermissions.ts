@@ -8,6 +8,8 @@ export const AdminSection = {
   Projects: 'projects',
   Namespaces: 'namespaces',
   Tokens: 'tokens',
+  Impersonate: 'impersonate',
+  Config: 'config',
 } as const
 
 export type AdminSectionType = (typeof AdminSection)[keyof typeof AdminSection]
@@ -20,6 +22,8 @@ export const ADMIN_SECTION_OPTIONS = [
   { code: AdminSection.Projects, label: 'Projects' },
   { code: AdminSection.Namespaces, label: 'Namespaces' },
   { code: AdminSection.Tokens, label: 'Tokens' },
+  { code: AdminSection.Impersonate, label: 'Impersonate' },
+  { code: AdminSection.Config, label: 'Config' },
 ] as const
 
 // Action constants
@@ -38,6 +42,20 @@ export const ACTION_OPTIONS = [
   { code: Action.Write, label: 'write' },
 ] as const
 
+// Permission effect constants
+export const PermissionEffect = {
+  Allow: 'ALLOW',
+  Deny: 'DENY',
+} as const
+
+export type PermissionEffectType = (typeof PermissionEffect)[keyof typeof PermissionEffect]
+
+// Permission effect options for dropdowns
+export const EFFECT_OPTIONS = [
+  { code: PermissionEffect.Allow, label: 'Allow' },
+  { code: PermissionEffect.Deny, label: 'Deny' },
+] as const
+
 // Resource type constants
 export const ResourceType = {
   Redirect: 'redirect',
@@ -70,20 +88,29 @@ export function validateCode(code: string, fieldName: string = 'Code'): string {
   return ''
 }
 
+// Deny rules override allow rules: access is granted if at least one matching
+// permission allows it and none denies it
+function isAllowed(matching: { effect?: string | null }[]): boolean {
+  return matching.length > 0 && !matching.some((p) => p.effect === PermissionEffect.Deny)
+}
+
 export function usePermissions() {
   const { data, loading, error, refetch } = useQuery(GetMeDocument)
 
   const permissions = data?.me?.permissions
 
   // Check if user can perform an action on an admin section
+  // Only global admin permissions are considered, not namespace-scoped ones
   const canAdmin = (section: AdminSectionType, action: ActionType): boolean => {
     if (!permissions?.admin) return false
 
-    return permissions.admin.some((p) => {
+    return isAllowed(permissions.admin.filter((p) => {
+      const namespaceMatch = (p.namespace ?? '*') === '*'
+      if (!namespaceMatch) return false
       const sectionMatch = p.section === '*' || p.section === section
       const actionMatch = p.action === '*' || p.action === action
       return sectionMatch && actionMatch
-    })
+    }))
   }
 
   // Check if user has any admin access
@@ -100,23 +127,30 @@ export function usePermissions() {
   ): boolean => {
     if (!permissions?.resources) return false
 
-    return permissions.resources.some((p) => {
+    return isAllowed(permissions.resources.filter((p) => {
       const nsMatch = p.namespace === '*' || p.namespace === namespace
       const projMatch = p.project === '*' || p.project === project
       const resourceMatch = p.resource === '*' || p.resource === resource
       const actionMatch = p.action === '*' || p.action === action
       return nsMatch && projMatch && resourceMatch && actionMatch
-    })
+    }))
   }
 
-  const canAdminResource = (section: string, action: ActionType): boolean => {
+  // Check if user can perform an action on an admin section, globally or within a namespace.
+  // Without namespace, namespace-scoped permissions count too so the section is reachable.
+  const canAdminResource = (section: string, action: ActionType, namespace?: string): boolean => {
     if (!permissions?.admin) return false
 
-    return permissions.admin.some((p) => {
+    return isAllowed(permissions.admin.filter((p) => {
+      const permNamespace = p.namespace ?? '*'
+      const namespaceMatch = namespace === undefined || permNamespace === '*' || permNamespace === namespace
+      if (!namespaceMatch) return false
+      // a deny scoped to one namespace does not hide the whole section
+      if (namespace === undefined && permNamespace !== '*' && p.effect === PermissionEffect.Deny) return false
       const sectionMatch = p.section === '*' || p.section === section
       const actionMatch = p.action === '*' || p.action === action
       return sectionMatch && actionMatch
-    })
+    }))
   }
 
   // Check if user can read a namespace
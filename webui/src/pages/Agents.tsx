@@ -30,6 +30,7 @@ const statusFilterLabels: Record<AgentStatus, string> = {
 export function Agents() {
   const [searchParams, setSearchParams] = useSearchParams()
   const { namespaceCode, projectCode, project } = useCurrentProject()
+  const productionVersion = project?.environments.find(e => e.environment === 'production')?.version ?? 0
   useDocumentTitle(namespaceCode && projectCode ? `Agents - ${namespaceCode}/${projectCode}` : 'Agents')
   const { canResource, loading: permissionsLoading } = usePermissions()
 
@@ -361,7 +362,11 @@ export function Agents() {
       ) : (
         <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-4">
           {agents.map((agent) => (
-            <AgentCard key={agent.name} agent={agent} projectVersion={project?.version ?? 0} />
+            <AgentCard
+              key={agent.name}
+              agent={agent}
+              projectVersion={agent.environment === 'production' ? productionVersion : project?.version ?? 0}
+            />
           ))}
         </div>
       )}
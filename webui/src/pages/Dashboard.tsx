@@ -1,9 +1,13 @@
-import { useQuery } from '@apollo/client/react'
+import { useState } from 'react'
+import { useMutation, useQuery } from '@apollo/client/react'
 import { Link } from 'react-router-dom'
 import { useDocumentTitle } from '../hooks/useDocumentTitle'
 import { useCurrentProject } from '../hooks/useCurrentProject'
-import { GetProjectDashboardDocument } from '../generated/graphql'
+import { usePermissions } from '../hooks/usePermissions'
+import { GetProjectDashboardDocument, GetProjectDocument, PromoteEnvironmentDocument } from '../generated/graphql'
 import { RelativeTime } from '../components/RelativeTime'
+import { ConfirmModal } from '../components/redirects'
+import { formatSize } from '../utils/format'
 
 export function Dashboard() {
   const { namespaceCode, projectCode, namespace, project } = useCurrentProject()
@@ -14,7 +18,30 @@ export function Dashboard() {
     skip: !namespaceCode || !projectCode,
   })
 
+  const { canWriteProject } = usePermissions()
+  const [promoteConfirm, setPromoteConfirm] = useState(false)
+  const [promoteEnvironment, { loading: promoteLoading, error: promoteError }] = useMutation(PromoteEnvironmentDocument, {
+    refetchQueries: [GetProjectDocument],
+  })
+
   const dashboard = data?.projectDashboard
+  const production = project?.environments.find(e => e.environment === 'production')
+
+  const handlePromoteConfirm = async () => {
+    if (!namespaceCode || !projectCode) return
+
+    try {
+      await promoteEnvironment({
+        variables: {
+          namespaceCode,
+          projectCode,
+        },
+      })
+    } catch (err) {
+      console.error('Failed to promote:', err)
+    }
+    setPromoteConfirm(false)
+  }
 
   if (loading) {
     return (
@@ -33,6 +60,8 @@ export function Dashboard() {
   }
 
   const isPublished = dashboard?.publishedAt && !dashboard.publishedAt.startsWith('0001-')
+  const canPromote = isPublished && namespaceCode && projectCode && canWriteProject(namespaceCode, projectCode) &&
+    production?.version !== dashboard?.version
 
   return (
     <div>
@@ -51,6 +80,27 @@ export function Dashboard() {
             <h3 className="text-lg font-semibold opacity-90">Project Version</h3>
             <p className="text-4xl font-bold mt-1">v{dashboard?.version ?? 0}</p>
           </div>
+          <div className="text-center">
+            <p className="text-sm opacity-75">Production</p>
+            <p className="text-lg font-medium mt-1">
+              {production ? (
+                <>
+                  v{production.version} <span className="text-sm opacity-75">(<RelativeTime date={production.promotedAt} className="text-white" />)</span>
+                </>
+              ) : (
+                'Not promoted yet'
+              )}
+            </p>
+            {canPromote && (
+              <button
+                onClick={() => setPromoteConfirm(true)}
+                disabled={promoteLoading}
+                className="mt-2 px-3 py-1.5 text-sm font-medium rounded-lg bg-white/20 hover:bg-white/30 disabled:opacity-50 transition-colors"
+              >
+                Promote to production
+              </button>
+            )}
+          </div>
           {isPublished && (
             <div className="text-right">
               <p className="text-sm opacity-75">Last Published</p>
@@ -68,6 +118,12 @@ export function Dashboard() {
         </div>
       </div>
 
+      {promoteError && (
+        <div className="mb-6 rounded-xl bg-red-50 dark:bg-red-900/20 border border-red-200 dark:border-red-800 p-4">
+          <p className="text-red-700 dark:text-red-400">Error promoting to production: {promoteError.message}</p>
+        </div>
+      )}
+
       {/* Stats Grid */}
       <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-5 gap-6">
         {/* Redirects Card */}
@@ -103,6 +159,18 @@ export function Dashboard() {
               <span className="text-slate-500 dark:text-slate-400">Regex Host</span>
               <span className="font-medium text-slate-700 dark:text-slate-300">{dashboard?.redirectStats.countRegexHost ?? 0}</span>
             </div>
+            <div className="flex justify-between pt-1.5 border-t border-slate-100 dark:border-slate-700">
+              <span className="text-slate-500 dark:text-slate-400">301 / 302</span>
+              <span className="font-medium text-slate-700 dark:text-slate-300">
+                {dashboard?.redirectStats.countMovedPermanent ?? 0} / {dashboard?.redirectStats.countFound ?? 0}
+              </span>
+            </div>
+            <div className="flex justify-between">
+              <span className="text-slate-500 dark:text-slate-400">307 / 308</span>
+              <span className="font-medium text-slate-700 dark:text-slate-300">
+                {dashboard?.redirectStats.countTemporaryRedirect ?? 0} / {dashboard?.redirectStats.countPermanentRedirect ?? 0}
+              </span>
+            </div>
           </div>
         </Link>
 
@@ -163,6 +231,12 @@ export function Dashboard() {
               <span className="text-slate-500 dark:text-slate-400">Host</span>
               <span className="font-medium text-slate-700 dark:text-slate-300">{dashboard?.pageStats.countBasicHost ?? 0}</span>
             </div>
+            <div className="flex justify-between pt-1.5 border-t border-slate-100 dark:border-slate-700">
+              <span className="text-slate-500 dark:text-slate-400">Size</span>
+              <span className="font-medium text-slate-700 dark:text-slate-300">
+                {formatSize(Number(dashboard?.pageStats.totalContentSize ?? 0))} / {formatSize(Number(dashboard?.pageStats.totalContentSizeLimit ?? 0))}
+              </span>
+            </div>
           </div>
         </Link>
 
@@ -228,6 +302,23 @@ export function Dashboard() {
           </div>
         </Link>
       </div>
+
+      {/* Promote Confirmation Modal */}
+      {promoteConfirm && (
+        <ConfirmModal
+          title="Promote to Production"
+          message={
+            <p>
+              Are you sure you want to promote version <strong>v{dashboard?.version}</strong> to production? Agents
+              subscribed to production will load it on their next sync.
+            </p>
+          }
+          confirmLabel="Promote"
+          variant="info"
+          onConfirm={handlePromoteConfirm}
+          onCancel={() => setPromoteConfirm(false)}
+        />
+      )}
     </div>
   )
 }
@@ -22,6 +22,9 @@ interface FormData {
   path: string
   content: string
   contentType: PageContentType
+  mimeType: string
+  // Markdown of the MARKDOWN pages, their content being rendered from it by the manager
+  source: string
 }
 
 const pageTypes: { value: PageType; label: string; description: string }[] = [
@@ -32,8 +35,16 @@ const pageTypes: { value: PageType; label: string; description: string }[] = [
 const contentTypes: { value: PageContentType; label: string; mimeType: string }[] = [
   { value: 'TEXT_PLAIN', label: 'Text', mimeType: 'text/plain' },
   { value: 'XML', label: 'XML', mimeType: 'application/xml' },
+  { value: 'MARKDOWN', label: 'Markdown', mimeType: 'text/html, rendered from markdown' },
+  { value: 'BINARY', label: 'Binary', mimeType: 'favicon.ico, images...' },
 ]
 
+// Decoded size of a base64 content
+function base64Size(content: string): number {
+  const padding = content.endsWith('==') ? 2 : content.endsWith('=') ? 1 : 0
+  return Math.floor((content.length * 3) / 4) - padding
+}
+
 const pathPlaceholders: Record<PageType, string> = {
   BASIC: '/robots.txt or /sitemap.xml',
   BASIC_HOST: 'example.com/robots.txt',
@@ -77,10 +88,14 @@ function ContentTypeBadge({ contentType }: { contentType: PageContentType }) {
   const labels: Record<PageContentType, string> = {
     TEXT_PLAIN: 'Text',
     XML: 'XML',
+    MARKDOWN: 'Markdown',
+    BINARY: 'Binary',
   }
   const colors: Record<PageContentType, string> = {
     TEXT_PLAIN: 'bg-blue-100 text-blue-700 dark:bg-blue-900/30 dark:text-blue-400',
     XML: 'bg-purple-100 text-purple-700 dark:bg-purple-900/30 dark:text-purple-400',
+    MARKDOWN: 'bg-emerald-100 text-emerald-700 dark:bg-emerald-900/30 dark:text-emerald-400',
+    BINARY: 'bg-amber-100 text-amber-700 dark:bg-amber-900/30 dark:text-amber-400',
   }
 
   return (
@@ -118,6 +133,8 @@ export function PageForm() {
     path: '',
     content: '',
     contentType: 'TEXT_PLAIN',
+    mimeType: '',
+    source: '',
   })
 
   const [errors, setErrors] = useState<Partial<Record<keyof FormData, string>>>({})
@@ -140,6 +157,8 @@ export function PageForm() {
           path: page.pageDraft.newPage.path,
           content: page.pageDraft.newPage.content,
           contentType: page.pageDraft.newPage.contentType,
+          mimeType: page.pageDraft.newPage.mimeType ?? '',
+          source: page.pageDraft.newPage.source ?? '',
         })
       } else {
         setFormData({
@@ -147,6 +166,8 @@ export function PageForm() {
           path: page.path ?? '',
           content: page.content ?? '',
           contentType: page.contentType ?? 'TEXT_PLAIN',
+          mimeType: page.mimeType ?? '',
+          source: page.source ?? '',
         })
       }
     }
@@ -172,7 +193,11 @@ export function PageForm() {
       }
     }
 
-    if (!formData.content.trim()) {
+    if (formData.contentType === 'MARKDOWN') {
+      if (!formData.source.trim()) {
+        newErrors.source = 'Markdown is required'
+      }
+    } else if (!formData.content.trim()) {
       newErrors.content = 'Content is required'
     }
 
@@ -242,6 +267,31 @@ export function PageForm() {
     }
   }
 
+  const handleContentTypeChange = (contentType: PageContentType) => {
+    if ((contentType === 'BINARY') !== (formData.contentType === 'BINARY')) {
+      // Text and base64 content are not interchangeable
+      setFormData((prev) => ({ ...prev, contentType, content: '', mimeType: '' }))
+      return
+    }
+    handleChange('contentType', contentType)
+  }
+
+  const handleFileChange = (e: React.ChangeEvent<HTMLInputElement>) => {
+    const file = e.target.files?.[0]
+    if (!file) return
+    const reader = new FileReader()
+    reader.onload = () => {
+      const dataUrl = reader.result as string
+      setFormData((prev) => ({
+        ...prev,
+        content: dataUrl.substring(dataUrl.indexOf(',') + 1),
+        mimeType: file.type,
+      }))
+      setErrors((prev) => ({ ...prev, content: undefined }))
+    }
+    reader.readAsDataURL(file)
+  }
+
   const isLoading = pageLoading || permissionsLoading
   const isSaving = createLoading || updateLoading
 
@@ -385,7 +435,7 @@ export function PageForm() {
                     <button
                       key={ct.value}
                       type="button"
-                      onClick={() => handleChange('contentType', ct.value)}
+                      onClick={() => handleContentTypeChange(ct.value)}
                       className={`px-4 py-2.5 text-sm font-medium rounded-lg border transition-colors ${
                         formData.contentType === ct.value
                           ? 'border-brand-purple bg-brand-purple/10 text-brand-purple'
@@ -412,21 +462,59 @@ export function PageForm() {
                 <label className="block text-sm font-medium text-slate-700 dark:text-slate-300 mb-2">
                   Content
                 </label>
-                <textarea
-                  value={formData.content}
-                  onChange={(e) => handleChange('content', e.target.value)}
-                  placeholder={formData.contentType === 'XML' ? '<?xml version="1.0"?>\n<urlset>...</urlset>' : 'User-agent: *\nDisallow: /admin/'}
-                  rows={15}
-                  className={`w-full rounded-lg border bg-white dark:bg-slate-900 py-2.5 px-4 text-slate-900 dark:text-white placeholder-slate-400 focus:outline-none focus:ring-2 focus:ring-brand-purple/20 font-mono text-sm resize-y ${
-                    errors.content
-                      ? 'border-red-500 focus:border-red-500'
-                      : 'border-slate-200 dark:border-slate-700 focus:border-brand-purple'
-                  }`}
-                />
-                {errors.content && <p className="mt-1 text-sm text-red-500">{errors.content}</p>}
-                <p className="mt-1 text-xs text-slate-500 dark:text-slate-400">
-                  {formData.content.length} characters
-                </p>
+                {formData.contentType === 'BINARY' ? (
+                  <>
+                    <input
+                      type="file"
+                      onChange={handleFileChange}
+                      className={`w-full rounded-lg border bg-white dark:bg-slate-900 py-2.5 px-4 text-sm text-slate-900 dark:text-white file:mr-4 file:rounded-md file:border-0 file:bg-brand-purple/10 file:px-3 file:py-1.5 file:text-sm file:font-medium file:text-brand-purple ${
+                        errors.content ? 'border-red-500' : 'border-slate-200 dark:border-slate-700'
+                      }`}
+                    />
+                    {errors.content && <p className="mt-1 text-sm text-red-500">{errors.content}</p>}
+                    <p className="mt-1 text-xs text-slate-500 dark:text-slate-400">
+                      {formData.content
+                        ? `${formatSize(base64Size(formData.content))}${formData.mimeType ? ` · ${formData.mimeType}` : ''}`
+                        : 'No file selected'}
+                    </p>
+                  </>
+                ) : formData.contentType === 'MARKDOWN' ? (
+                  <>
+                    <textarea
+                      value={formData.source}
+                      onChange={(e) => handleChange('source', e.target.value)}
+                      placeholder={'# Title\n\nSome **markdown** text'}
+                      rows={15}
+                      className={`w-full rounded-lg border bg-white dark:bg-slate-900 py-2.5 px-4 text-slate-900 dark:text-white placeholder-slate-400 focus:outline-none focus:ring-2 focus:ring-brand-purple/20 font-mono text-sm resize-y ${
+                        errors.source
+                          ? 'border-red-500 focus:border-red-500'
+                          : 'border-slate-200 dark:border-slate-700 focus:border-brand-purple'
+                      }`}
+                    />
+                    {errors.source && <p className="mt-1 text-sm text-red-500">{errors.source}</p>}
+                    <p className="mt-1 text-xs text-slate-500 dark:text-slate-400">
+                      {formData.source.length} characters · served as the sanitized HTML rendered from the markdown
+                    </p>
+                  </>
+                ) : (
+                  <>
+                    <textarea
+                      value={formData.content}
+                      onChange={(e) => handleChange('content', e.target.value)}
+                      placeholder={formData.contentType === 'XML' ? '<?xml version="1.0"?>\n<urlset>...</urlset>' : 'User-agent: *\nDisallow: /admin/'}
+                      rows={15}
+                      className={`w-full rounded-lg border bg-white dark:bg-slate-900 py-2.5 px-4 text-slate-900 dark:text-white placeholder-slate-400 focus:outline-none focus:ring-2 focus:ring-brand-purple/20 font-mono text-sm resize-y ${
+                        errors.content
+                          ? 'border-red-500 focus:border-red-500'
+                          : 'border-slate-200 dark:border-slate-700 focus:border-brand-purple'
+                      }`}
+                    />
+                    {errors.content && <p className="mt-1 text-sm text-red-500">{errors.content}</p>}
+                    <p className="mt-1 text-xs text-slate-500 dark:text-slate-400">
+                      {formData.content.length} characters
+                    </p>
+                  </>
+                )}
               </div>
 
               {/* Actions */}
@@ -484,6 +572,26 @@ export function PageForm() {
             </div>
           )}
 
+          {/* Lint Warnings of the saved draft, which do not prevent publishing it */}
+          {draft?.lintWarnings && draft.lintWarnings.length > 0 && (
+            <div className="rounded-xl bg-amber-50 dark:bg-amber-900/20 border border-amber-200 dark:border-amber-800 p-5">
+              <h3 className="text-sm font-semibold text-amber-800 dark:text-amber-300 mb-3 flex items-center gap-2">
+                <svg className="w-4 h-4" fill="none" stroke="currentColor" viewBox="0 0 24 24">
+                  <path strokeLinecap="round" strokeLinejoin="round" strokeWidth={2} d="M12 9v2m0 4h.01m-6.938 4h13.856c1.54 0 2.502-1.667 1.732-3L13.732 4c-.77-1.333-2.694-1.333-3.464 0L3.34 16c-.77 1.333.192 3 1.732 3z" />
+                </svg>
+                Content Warnings
+              </h3>
+              <ul className="space-y-2 text-sm text-amber-700 dark:text-amber-400">
+                {draft.lintWarnings.map((warning, index) => (
+                  <li key={index}>
+                    {warning.line > 0 && <span className="font-mono text-xs mr-1">L{warning.line}</span>}
+                    {warning.message}
+                  </li>
+                ))}
+              </ul>
+            </div>
+          )}
+
           {/* Old Values Card (only for UPDATE mode) */}
           {!isCreateMode && oldPage && changeType !== 'CREATE' && (
             <div className="rounded-xl bg-white dark:bg-slate-800 border border-slate-200 dark:border-slate-700 p-5">
@@ -43,6 +43,8 @@ const typeFilterLabels: Record<PageType, string> = {
 const contentTypeLabels: Record<PageContentType, string> = {
   TEXT_PLAIN: 'Text',
   XML: 'XML',
+  MARKDOWN: 'Markdown',
+  BINARY: 'Binary',
 }
 
 function PageTypeBadge({ type }: { type: PageType }) {
@@ -57,6 +59,8 @@ function ContentTypeBadge({ contentType }: { contentType: PageContentType }) {
   const colors: Record<PageContentType, string> = {
     TEXT_PLAIN: 'bg-blue-100 text-blue-700 dark:bg-blue-900/30 dark:text-blue-400',
     XML: 'bg-purple-100 text-purple-700 dark:bg-purple-900/30 dark:text-purple-400',
+    MARKDOWN: 'bg-emerald-100 text-emerald-700 dark:bg-emerald-900/30 dark:text-emerald-400',
+    BINARY: 'bg-amber-100 text-amber-700 dark:bg-amber-900/30 dark:text-amber-400',
   }
 
   return (
@@ -1,7 +1,7 @@
 import { useState, useEffect } from 'react'
 import { useParams, useNavigate, Link } from 'react-router-dom'
 import { useQuery, useMutation } from '@apollo/client/react'
-import { GetNamespaceDocument, CreateNamespaceDocument, UpdateNamespaceDocument, DeleteNamespaceDocument, SearchProjectsDocument, DeleteProjectDocument } from '../../generated/graphql'
+import { GetNamespaceDocument, CreateNamespaceDocument, UpdateNamespaceDocument, DeleteNamespaceDocument, SearchProjectsDocument, DeleteProjectDocument, GetProjectCountsDocument } from '../../generated/graphql'
 import { usePermissions, AdminSection, Action, validateCode } from '../../hooks/usePermissions'
 import { useDocumentTitle } from '../../hooks/useDocumentTitle'
 import { UnsavedChangesIndicator } from '../../components/UnsavedChangesIndicator'
@@ -17,7 +17,7 @@ export function NamespaceForm() {
 
   const isEditing = !!id && id !== 'new'
   useDocumentTitle(isEditing ? `Admin - Edit Namespace` : 'Admin - Add Namespace')
-  const canWrite = canAdminResource(AdminSection.Namespaces, Action.Write)
+  const canWrite = canAdminResource(AdminSection.Namespaces, Action.Write, isEditing ? id : undefined)
   const isReadOnly = isEditing && !canWrite
 
   const [namespaceCode, setNamespaceCode] = useState('')
@@ -33,7 +33,7 @@ export function NamespaceForm() {
   const [projectsSearchInput, setProjectsSearchInput] = useState('')
   const [projectsSearch, setProjectsSearch] = useState('')
   const [deleteProjectConfirm, setDeleteProjectConfirm] = useState<{ namespaceCode: string; projectCode: string } | null>(null)
-  const canWriteProjects = canAdminResource(AdminSection.Projects, Action.Write)
+  const canWriteProjects = canAdminResource(AdminSection.Projects, Action.Write, isEditing ? id : undefined)
 
   // Fetch namespace data if editing
   const { data: namespaceData, loading: namespaceLoading } = useQuery(GetNamespaceDocument, {
@@ -54,6 +54,13 @@ export function NamespaceForm() {
     skip: !isEditing,
   })
 
+  // Fetch the counts of all the projects of the namespace in a single query
+  const { data: countsData } = useQuery(GetProjectCountsDocument, {
+    variables: { namespaceCode: id! },
+    skip: !isEditing,
+  })
+  const projectCounts = new Map((countsData?.projectCounts ?? []).map((counts) => [counts.projectCode, counts]))
+
   const [deleteProject, { loading: deleteProjectLoading }] = useMutation(DeleteProjectDocument)
 
   const isLoading = createLoading || updateLoading || deleteLoading
@@ -549,6 +556,15 @@ export function NamespaceForm() {
                     <th className="px-6 py-3 text-left text-xs font-medium text-slate-500 dark:text-slate-400 uppercase tracking-wider">
                       Name
                     </th>
+                    <th className="px-6 py-3 text-right text-xs font-medium text-slate-500 dark:text-slate-400 uppercase tracking-wider">
+                      Redirects
+                    </th>
+                    <th className="px-6 py-3 text-right text-xs font-medium text-slate-500 dark:text-slate-400 uppercase tracking-wider">
+                      Pages
+                    </th>
+                    <th className="px-6 py-3 text-right text-xs font-medium text-slate-500 dark:text-slate-400 uppercase tracking-wider">
+                      Drafts
+                    </th>
                     <th className="px-6 py-3 text-right text-xs font-medium text-slate-500 dark:text-slate-400 uppercase tracking-wider">
                       Actions
                     </th>
@@ -568,6 +584,17 @@ export function NamespaceForm() {
                       <td className="px-6 py-4 whitespace-nowrap">
                         <span className="text-slate-600 dark:text-slate-400">{project.name}</span>
                       </td>
+                      <td className="px-6 py-4 whitespace-nowrap text-right text-sm text-slate-600 dark:text-slate-400">
+                        {projectCounts.get(project.projectCode)?.redirects ?? '-'}
+                      </td>
+                      <td className="px-6 py-4 whitespace-nowrap text-right text-sm text-slate-600 dark:text-slate-400">
+                        {projectCounts.get(project.projectCode)?.pages ?? '-'}
+                      </td>
+                      <td className="px-6 py-4 whitespace-nowrap text-right text-sm text-slate-600 dark:text-slate-400">
+                        {projectCounts.has(project.projectCode)
+                          ? projectCounts.get(project.projectCode)!.redirectDrafts + projectCounts.get(project.projectCode)!.pageDrafts
+                          : '-'}
+                      </td>
                       <td className="px-6 py-4 whitespace-nowrap text-right">
                         <div className="flex items-center justify-end gap-2">
                           <button
@@ -1,11 +1,12 @@
 import { useState, useEffect } from 'react'
 import { useParams, useNavigate, Link } from 'react-router-dom'
 import { useQuery, useMutation } from '@apollo/client/react'
-import { GetProjectDocument, CreateProjectDocument, UpdateProjectDocument, DeleteProjectDocument, GetNamespacesDocument } from '../../generated/graphql'
+import { GetProjectDocument, CreateProjectDocument, UpdateProjectDocument, DeleteProjectDocument, MoveProjectDocument, GetNamespacesDocument } from '../../generated/graphql'
 import { usePermissions, AdminSection, Action, validateCode } from '../../hooks/usePermissions'
 import { useDocumentTitle } from '../../hooks/useDocumentTitle'
 import { UnsavedChangesIndicator } from '../../components/UnsavedChangesIndicator'
 import { RelativeTime } from '../../components/RelativeTime'
+import { ProjectApiKeys } from '../../components/admin'
 import { formatSize } from '../../utils/format'
 
 export function ProjectForm() {
@@ -25,6 +26,7 @@ export function ProjectForm() {
   const [error, setError] = useState('')
   const [success, setSuccess] = useState('')
   const [deleteConfirm, setDeleteConfirm] = useState(false)
+  const [moveTarget, setMoveTarget] = useState('')
   const [isModified, setIsModified] = useState(false)
 
   // Fetch project data if editing
@@ -33,16 +35,15 @@ export function ProjectForm() {
     skip: !isEditing,
   })
 
-  // Fetch namespaces for dropdown (only in create mode)
-  const { data: namespacesData, loading: namespacesLoading } = useQuery(GetNamespacesDocument, {
-    skip: isEditing,
-  })
+  // Fetch namespaces for dropdown (namespace on creation, move target on edition)
+  const { data: namespacesData, loading: namespacesLoading } = useQuery(GetNamespacesDocument)
 
   const [createProject, { loading: createLoading }] = useMutation(CreateProjectDocument)
   const [updateProject, { loading: updateLoading }] = useMutation(UpdateProjectDocument)
   const [deleteProject, { loading: deleteLoading }] = useMutation(DeleteProjectDocument)
+  const [moveProject, { loading: moveLoading }] = useMutation(MoveProjectDocument)
 
-  const isLoading = createLoading || updateLoading || deleteLoading
+  const isLoading = createLoading || updateLoading || deleteLoading || moveLoading
 
   // Populate form when project data is loaded
   useEffect(() => {
@@ -149,6 +150,25 @@ export function ProjectForm() {
     }
   }
 
+  const handleMove = async () => {
+    if (!isEditing || !moveTarget) return
+    setError('')
+    setSuccess('')
+    try {
+      await moveProject({
+        variables: {
+          namespaceCode: paramNamespaceCode!,
+          projectCode: paramProjectCode!,
+          targetNamespaceCode: moveTarget,
+        },
+      })
+      navigate(`/admin/projects/${encodeURIComponent(moveTarget)}/${encodeURIComponent(paramProjectCode!)}`)
+      setMoveTarget('')
+    } catch (err) {
+      setError(err instanceof Error ? err.message : 'Failed to move project')
+    }
+  }
+
   const handleDeleteCancel = () => {
     setDeleteConfirm(false)
   }
@@ -274,7 +294,7 @@ export function ProjectForm() {
                 )}
                 {isEditing && !isReadOnly && (
                   <p className="mt-1 text-xs text-slate-500 dark:text-slate-400">
-                    Namespace cannot be changed
+                    Use Move Project to change the namespace
                   </p>
                 )}
               </div>
@@ -430,6 +450,12 @@ export function ProjectForm() {
                     {formatSize(Number(projectData.project.totalPageContentSize))} / {formatSize(Number(projectData.project.totalPageContentSizeLimit))}
                   </span>
                 </div>
+                <div className="flex items-center justify-between">
+                  <span className="text-sm text-slate-600 dark:text-slate-400">Page stored size</span>
+                  <span className="text-sm font-medium text-slate-900 dark:text-white">
+                    {formatSize(Number(projectData.project.totalPageStoredContentSize))}
+                  </span>
+                </div>
                 <div className="flex items-center justify-between">
                   <span className="text-sm text-slate-600 dark:text-slate-400">Created</span>
                   <RelativeTime
@@ -463,6 +489,31 @@ export function ProjectForm() {
                   Actions
                 </h3>
                 <div className="space-y-3">
+                  <div className="flex gap-2">
+                    <select
+                      aria-label="Target namespace"
+                      value={moveTarget}
+                      onChange={(e) => setMoveTarget(e.target.value)}
+                      disabled={isLoading || namespacesLoading}
+                      className="flex-1 min-w-0 rounded-lg border border-slate-200 dark:border-slate-700 bg-white dark:bg-slate-900 py-2 px-3 text-sm text-slate-900 dark:text-white focus:border-brand-purple focus:outline-none focus:ring-2 focus:ring-brand-purple/20"
+                    >
+                      <option value="">Move to namespace...</option>
+                      {namespacesData?.namespaces
+                        .filter((ns) => ns.namespaceCode !== paramNamespaceCode)
+                        .map((ns) => (
+                          <option key={ns.namespaceCode} value={ns.namespaceCode}>
+                            {ns.name} ({ns.namespaceCode})
+                          </option>
+                        ))}
+                    </select>
+                    <button
+                      onClick={handleMove}
+                      disabled={isLoading || !moveTarget}
+                      className="px-4 py-2 text-sm font-medium rounded-lg border border-slate-200 dark:border-slate-700 text-slate-600 dark:text-slate-400 hover:bg-slate-50 dark:hover:bg-slate-700 transition-colors disabled:opacity-50"
+                    >
+                      {moveLoading ? 'Moving...' : 'Move Project'}
+                    </button>
+                  </div>
                   <button
                     onClick={handleDeleteClick}
                     disabled={isLoading}
@@ -476,6 +527,9 @@ export function ProjectForm() {
                 </div>
               </div>
             )}
+
+            {/* API Keys */}
+            <ProjectApiKeys namespaceCode={paramNamespaceCode!} projectCode={paramProjectCode!} canWrite={canWrite} />
           </div>
         )}
       </div>
@@ -12,7 +12,7 @@ import {
   AddUserToRoleDocument,
   RemoveUserFromRoleDocument,
 } from '../../generated/graphql'
-import { usePermissions, AdminSection, Action, validateCode } from '../../hooks/usePermissions'
+import { usePermissions, AdminSection, Action, validateCode, type PermissionEffectType } from '../../hooks/usePermissions'
 import { useDocumentTitle } from '../../hooks/useDocumentTitle'
 import { UnsavedChangesIndicator } from '../../components/UnsavedChangesIndicator'
 import { RelativeTime } from '../../components/RelativeTime'
@@ -91,10 +91,13 @@ export function RoleForm() {
         project: r.project,
         resource: r.resource,
         action: r.action,
+        effect: r.effect,
       })))
       setAdminPermissions(roleData.role.admin.map(a => ({
+        namespace: a.namespace,
         section: a.section,
         action: a.action,
+        effect: a.effect,
       })))
       setIsModified(false)
     }
@@ -248,6 +251,8 @@ export function RoleForm() {
       perm.resource = value
     } else if (field === 'action') {
       perm.action = value
+    } else if (field === 'effect') {
+      perm.effect = value as PermissionEffectType
     }
 
     updated[index] = perm
@@ -269,7 +274,7 @@ export function RoleForm() {
     setIsModified(true)
   }
 
-  const handleAdminPermissionChange = (index: number, field: 'section' | 'action', value: string) => {
+  const handleAdminPermissionChange = (index: number, field: 'namespace' | 'section' | 'action' | 'effect', value: string) => {
     const updated = [...adminPermissions]
     updated[index] = { ...updated[index], [field]: value }
     setAdminPermissions(updated)
@@ -497,6 +502,7 @@ export function RoleForm() {
               <div className="mb-6">
                 <AdminPermissionsEditor
                   permissions={adminPermissions}
+                  namespaceOptions={namespaceOptions}
                   onChange={handleAdminPermissionChange}
                   onAdd={handleAddAdminPermission}
                   onRemove={handleRemoveAdminPermission}
@@ -2,7 +2,7 @@ import { useState, useEffect } from 'react'
 import { useParams, useNavigate } from 'react-router-dom'
 import { useQuery, useMutation } from '@apollo/client/react'
 import { GetTokenDocument, CreateTokenDocument, UpdateTokenPermissionsDocument, DeleteTokenDocument, GetNamespacesDocument } from '../../generated/graphql'
-import { usePermissions, AdminSection, Action, validateCode } from '../../hooks/usePermissions'
+import { usePermissions, AdminSection, Action, validateCode, type PermissionEffectType } from '../../hooks/usePermissions'
 import { useDocumentTitle } from '../../hooks/useDocumentTitle'
 import { UnsavedChangesIndicator } from '../../components/UnsavedChangesIndicator'
 import { RelativeTime } from '../../components/RelativeTime'
@@ -56,10 +56,13 @@ export function TokenForm() {
         project: r.project,
         resource: r.resource,
         action: r.action,
+        effect: r.effect,
       })) || [])
       setAdminPermissions(tokenData.token.role?.admin?.map(a => ({
+        namespace: a.namespace,
         section: a.section,
         action: a.action,
+        effect: a.effect,
       })) || [])
       setIsModified(false)
     }
@@ -110,6 +113,8 @@ export function TokenForm() {
       perm.resource = value
     } else if (field === 'action') {
       perm.action = value
+    } else if (field === 'effect') {
+      perm.effect = value as PermissionEffectType
     }
 
     updated[index] = perm
@@ -131,7 +136,7 @@ export function TokenForm() {
     setIsModified(true)
   }
 
-  const handleAdminPermissionChange = (index: number, field: 'section' | 'action', value: string) => {
+  const handleAdminPermissionChange = (index: number, field: 'namespace' | 'section' | 'action' | 'effect', value: string) => {
     const updated = [...adminPermissions]
     updated[index] = { ...updated[index], [field]: value }
     setAdminPermissions(updated)
@@ -476,6 +481,7 @@ export function TokenForm() {
                 <div className="mb-6">
                   <AdminPermissionsEditor
                     permissions={adminPermissions}
+                    namespaceOptions={namespaceOptions}
                     onChange={handleAdminPermissionChange}
                     onAdd={handleAddAdminPermission}
                     onRemove={handleRemoveAdminPermission}
@@ -2,7 +2,7 @@ import { useState, useEffect, useRef } from 'react'
 import { useParams, useNavigate } from 'react-router-dom'
 import { useQuery, useMutation } from '@apollo/client/react'
 import { CreateUserDocument, UpdateUserDocument, UpdateUserStatusDocument, DeleteUserDocument, GetRolesDocument, UpdateUserPermissionsDocument, GetNamespacesDocument, GetUserDocument } from '../../generated/graphql'
-import { usePermissions, AdminSection, Action } from '../../hooks/usePermissions'
+import { usePermissions, AdminSection, Action, type PermissionEffectType } from '../../hooks/usePermissions'
 import { useDocumentTitle } from '../../hooks/useDocumentTitle'
 import { RelativeTime } from '../../components/RelativeTime'
 import { UnsavedChangesIndicator } from '../../components/UnsavedChangesIndicator'
@@ -92,20 +92,20 @@ export function UserForm() {
       // Direct permissions from user's personal role
       if (userRole) {
         userRole.resources.forEach(p => {
-          resourcePerms.push({ type: 'user', namespace: p.namespace, project: p.project, resource: p.resource, action: p.action })
+          resourcePerms.push({ type: 'user', namespace: p.namespace, project: p.project, resource: p.resource, action: p.action, effect: p.effect })
         })
         userRole.admin.forEach(p => {
-          adminPerms.push({ type: 'user', section: p.section, action: p.action })
+          adminPerms.push({ type: 'user', namespace: p.namespace, section: p.section, action: p.action, effect: p.effect })
         })
       }
 
       // Inherited permissions from assigned roles
       assignedRoles.forEach(role => {
         role.resources.forEach(p => {
-          resourcePerms.push({ type: 'role', namespace: p.namespace, project: p.project, resource: p.resource, action: p.action })
+          resourcePerms.push({ type: 'role', namespace: p.namespace, project: p.project, resource: p.resource, action: p.action, effect: p.effect })
         })
         role.admin.forEach(p => {
-          adminPerms.push({ type: 'role', section: p.section, action: p.action })
+          adminPerms.push({ type: 'role', namespace: p.namespace, section: p.section, action: p.action, effect: p.effect })
         })
       })
 
@@ -291,6 +291,8 @@ export function UserForm() {
       perm.resource = value
     } else if (field === 'action') {
       perm.action = value
+    } else if (field === 'effect') {
+      perm.effect = value as PermissionEffectType
     }
 
     updated[index] = perm
@@ -312,7 +314,7 @@ export function UserForm() {
     setPermissionsModified(true)
   }
 
-  const handleAdminPermissionChange = (index: number, field: 'section' | 'action', value: string) => {
+  const handleAdminPermissionChange = (index: number, field: 'namespace' | 'section' | 'action' | 'effect', value: string) => {
     const updated = [...adminPermissions]
     updated[index] = { ...updated[index], [field]: value }
     setAdminPermissions(updated)
@@ -332,13 +334,16 @@ export function UserForm() {
           project: p.project,
           resource: p.resource,
           action: p.action,
+          effect: p.effect,
         }))
 
       const userAdminPermissions = adminPermissions
         .filter(p => p.type === 'user')
         .map(p => ({
+          namespace: p.namespace,
           section: p.section,
           action: p.action,
+          effect: p.effect,
         }))
 
       await updateUserPermissions({
@@ -666,6 +671,7 @@ export function UserForm() {
               <div className="mb-6">
                 <AdminPermissionsEditor
                   permissions={adminPermissions}
+                  namespaceOptions={namespaceOptions}
                   onChange={handleAdminPermissionChange}
                   onAdd={handleAddAdminPermission}
                   onRemove={handleRemoveAdminPermission}
@@ -0,0 +1,283 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxColumns is the number of columns of a sheet, up to XFD
+	maxColumns = 16384
+	// maxRows is the number of rows of a sheet
+	maxRows = 1048576
+)
+
+var (
+	ErrNoSheet  = errors.New("workbook does not contain any sheet")
+	ErrTooLarge = errors.New("xlsx file too large")
+)
+
+type workbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type sharedStrings struct {
+	Items []richText `xml:"si"`
+}
+
+// richText is either a plain <t> element or a list of formatted <r><t> runs
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (r richText) String() string {
+	if len(r.Runs) == 0 {
+		return r.T
+	}
+	var sb strings.Builder
+	for _, run := range r.Runs {
+		sb.WriteString(run.T)
+	}
+	return sb.String()
+}
+
+type worksheet struct {
+	Rows []struct {
+		Ref   int `xml:"r,attr"`
+		Cells []struct {
+			Ref       string   `xml:"r,attr"`
+			Type      string   `xml:"t,attr"`
+			Value     string   `xml:"v"`
+			InlineStr richText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// ReadFirstSheet reads an .xlsx document and returns the cell values of its first sheet.
+// Rows are returned in sheet order, empty rows included, each row padded so that cells keep their column position.
+// The document and each of the files it compresses are limited to maxSize bytes, ErrTooLarge being returned beyond.
+func ReadFirstSheet(reader io.Reader, maxSize int64) ([][]string, error) {
+	archive, err := openArchive(reader, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var strs sharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err = decodeFile(f, maxSize, &strs); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("invalid xlsx file: missing %s", sheetPath)
+	}
+	var sheet worksheet
+	if err = decodeFile(f, maxSize, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, r := range sheet.Rows {
+		// Rows without cells may be left out of the sheet, the row number keeps the following ones in place
+		if r.Ref != 0 {
+			if r.Ref <= len(rows) || r.Ref > maxRows {
+				return nil, fmt.Errorf("invalid xlsx file: bad row number %d", r.Ref)
+			}
+			for len(rows) < r.Ref-1 {
+				rows = append(rows, nil)
+			}
+		}
+
+		// A cell without reference follows the previous one, the referenced cells may come in any order
+		var row []string
+		next := 0
+		for _, c := range r.Cells {
+			col := next
+			if c.Ref != "" {
+				if col, err = columnIndex(c.Ref); err != nil {
+					return nil, err
+				}
+			} else if col >= maxColumns {
+				return nil, fmt.Errorf("invalid xlsx file: too many cells in row %d", len(rows)+1)
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+			next = col + 1
+
+			value := c.Value
+			switch c.Type {
+			case "s":
+				idx, errIdx := strconv.Atoi(c.Value)
+				if errIdx != nil || idx < 0 || idx >= len(strs.Items) {
+					return nil, fmt.Errorf("invalid xlsx file: bad shared string index %q in cell %s", c.Value, c.Ref)
+				}
+				value = strs.Items[idx].String()
+			case "inlineStr":
+				value = c.InlineStr.String()
+			}
+			row[col] = value
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// openArchive opens the archive from the reader, read in memory unless it can be read at an offset
+func openArchive(reader io.Reader, maxSize int64) (*zip.Reader, error) {
+	var archive *zip.Reader
+	var err error
+	if file, ok := reader.(interface {
+		io.ReaderAt
+		io.Seeker
+	}); ok {
+		var offset, size int64
+		if offset, err = file.Seek(0, io.SeekCurrent); err != nil {
+			return nil, err
+		}
+		if size, err = file.Seek(0, io.SeekEnd); err != nil {
+			return nil, err
+		}
+		if size-offset > maxSize {
+			return nil, ErrTooLarge
+		}
+		archive, err = zip.NewReader(io.NewSectionReader(file, offset, size-offset), size-offset)
+	} else {
+		var data []byte
+		if data, err = io.ReadAll(io.LimitReader(reader, maxSize+1)); err != nil {
+			return nil, err
+		}
+		if int64(len(data)) > maxSize {
+			return nil, ErrTooLarge
+		}
+		archive, err = zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx file: %w", err)
+	}
+	return archive, nil
+}
+
+// firstSheetPath resolves the archive path of the first sheet declared in the workbook
+func firstSheetPath(files map[string]*zip.File, maxSize int64) (string, error) {
+	f, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("invalid xlsx file: missing xl/workbook.xml")
+	}
+	var wb workbook
+	if err := decodeFile(f, maxSize, &wb); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", ErrNoSheet
+	}
+
+	f, ok = files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "", fmt.Errorf("invalid xlsx file: missing xl/_rels/workbook.xml.rels")
+	}
+	var rels relationships
+	if err := decodeFile(f, maxSize, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != wb.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("invalid xlsx file: no relationship found for sheet %s", wb.Sheets[0].Name)
+}
+
+// decodeFile decodes a file of the archive, refusing to uncompress more than maxSize bytes
+func decodeFile(f *zip.File, maxSize int64, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	limited := &limitedReader{reader: rc, remaining: maxSize}
+	if err = xml.NewDecoder(limited).Decode(v); err != nil {
+		if limited.remaining < 0 {
+			return ErrTooLarge
+		}
+		return fmt.Errorf("invalid xlsx file: failed to parse %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// limitedReader fails with ErrTooLarge once more than the remaining bytes are read
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.remaining < 0 {
+		return 0, ErrTooLarge
+	}
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+	if r.remaining < 0 {
+		return 0, ErrTooLarge
+	}
+	return n, err
+}
+
+// columnIndex converts a cell reference such as "C12" to a zero-based column index
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+		n++
+		if col > maxColumns {
+			return 0, fmt.Errorf("invalid xlsx file: bad cell reference %q", ref)
+		}
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid xlsx file: bad cell reference %q", ref)
+	}
+	return col - 1, nil
+}
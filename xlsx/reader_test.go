@@ -0,0 +1,165 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMaxSize = 1 << 20
+
+func buildArchive(t *testing.T, files map[string]string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	return buf
+}
+
+func workbookFiles(sheet string) map[string]string {
+	return map[string]string{
+		"xl/workbook.xml": `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Redirects" sheetId="1" r:id="rId1"/><sheet name="Other" sheetId="2" r:id="rId2"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId2" Target="worksheets/sheet2.xml"/><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/sharedStrings.xml": `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<si><t>type</t></si><si><r><t>sou</t></r><r><t>rce</t></r></si></sst>`,
+		"xl/worksheets/sheet1.xml": sheet,
+		"xl/worksheets/sheet2.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1"><v>other</v></c></row></sheetData></worksheet>`,
+	}
+}
+
+func TestReadFirstSheet(t *testing.T) {
+	t.Run("reads shared, inline and numeric cells", func(t *testing.T) {
+		sheet := `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row>
+<row r="2"><c r="A2" t="inlineStr"><is><t>BASIC</t></is></c><c r="C2"><v>301</v></c></row>
+</sheetData></worksheet>`
+		rows, err := ReadFirstSheet(buildArchive(t, workbookFiles(sheet)), testMaxSize)
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"type", "source"},
+			{"BASIC", "", "301"},
+		}, rows)
+	})
+
+	t.Run("keeps the row numbers", func(t *testing.T) {
+		sheet := `<worksheet><sheetData>
+<row r="1"><c r="A1"><v>1</v></c></row>
+<row r="4"><c r="B4"><v>4</v></c></row>
+<row><c><v>5</v></c></row>
+</sheetData></worksheet>`
+		rows, err := ReadFirstSheet(bytes.NewReader(buildArchive(t, workbookFiles(sheet)).Bytes()), testMaxSize)
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{"1"}, nil, nil, {"", "4"}, {"5"}}, rows)
+	})
+
+	t.Run("cells out of order", func(t *testing.T) {
+		sheet := `<worksheet><sheetData>
+<row r="1"><c r="C1"><v>c</v></c><c r="A1"><v>a</v></c><c><v>b</v></c></row>
+<row r="2"><c r="B2"><v>first</v></c><c r="B2"><v>second</v></c></row>
+<row r="3"><c r="B3"><v>b</v></c><c><v>c</v></c><c r="E3"><v>e</v></c><c><v>f</v></c></row>
+</sheetData></worksheet>`
+		rows, err := ReadFirstSheet(buildArchive(t, workbookFiles(sheet)), testMaxSize)
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"a", "b", "c"},
+			{"", "second"},
+			{"", "b", "c", "", "e", "f"},
+		}, rows)
+	})
+
+	t.Run("rows out of order", func(t *testing.T) {
+		sheet := `<worksheet><sheetData><row r="2"><c r="A2"><v>2</v></c></row><row r="1"><c r="A1"><v>1</v></c></row></sheetData></worksheet>`
+		rows, err := ReadFirstSheet(buildArchive(t, workbookFiles(sheet)), testMaxSize)
+
+		assert.ErrorContains(t, err, "bad row number 1")
+		assert.Nil(t, rows)
+	})
+
+	t.Run("archive too large", func(t *testing.T) {
+		archive := buildArchive(t, workbookFiles(`<worksheet><sheetData></sheetData></worksheet>`))
+
+		_, err := ReadFirstSheet(bytes.NewReader(archive.Bytes()), 100)
+		assert.ErrorIs(t, err, ErrTooLarge)
+		_, err = ReadFirstSheet(archive, 100)
+		assert.ErrorIs(t, err, ErrTooLarge)
+	})
+
+	t.Run("uncompressed sheet too large", func(t *testing.T) {
+		sheet := `<worksheet><sheetData><row r="1"><c r="A1"><v>` + strings.Repeat("0", 4*testMaxSize) + `</v></c></row></sheetData></worksheet>`
+		rows, err := ReadFirstSheet(buildArchive(t, workbookFiles(sheet)), testMaxSize)
+
+		assert.ErrorIs(t, err, ErrTooLarge)
+		assert.Nil(t, rows)
+	})
+
+	t.Run("invalid archive", func(t *testing.T) {
+		rows, err := ReadFirstSheet(strings.NewReader("not a zip"), testMaxSize)
+
+		assert.Error(t, err)
+		assert.Nil(t, rows)
+	})
+
+	t.Run("missing workbook", func(t *testing.T) {
+		rows, err := ReadFirstSheet(buildArchive(t, map[string]string{"foo.xml": "<foo/>"}), testMaxSize)
+
+		assert.ErrorContains(t, err, "missing xl/workbook.xml")
+		assert.Nil(t, rows)
+	})
+
+	t.Run("no sheet", func(t *testing.T) {
+		files := workbookFiles("")
+		files["xl/workbook.xml"] = `<workbook><sheets></sheets></workbook>`
+		rows, err := ReadFirstSheet(buildArchive(t, files), testMaxSize)
+
+		assert.ErrorIs(t, err, ErrNoSheet)
+		assert.Nil(t, rows)
+	})
+
+	t.Run("bad shared string index", func(t *testing.T) {
+		sheet := `<worksheet><sheetData><row r="1"><c r="A1" t="s"><v>9</v></c></row></sheetData></worksheet>`
+		rows, err := ReadFirstSheet(buildArchive(t, workbookFiles(sheet)), testMaxSize)
+
+		assert.ErrorContains(t, err, "bad shared string index")
+		assert.Nil(t, rows)
+	})
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    int
+		wantErr bool
+	}{
+		{ref: "A1", want: 0},
+		{ref: "D10", want: 3},
+		{ref: "AA3", want: 26},
+		{ref: "XFD1", want: 16383},
+		{ref: "XFE1", wantErr: true},
+		{ref: "AAAAAAAAAAAAAAA1", wantErr: true},
+		{ref: "12", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			got, err := columnIndex(tt.ref)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}